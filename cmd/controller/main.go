@@ -24,18 +24,30 @@ import (
 	// The following line to load the gcp plugin (only required to authenticate against GKE clusters).
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler/deployment"
+	"github.com/google/knative-gcp/pkg/reconciler/events/artifactregistry"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billing"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
+	"github.com/google/knative-gcp/pkg/reconciler/events/sourceset"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
+	"github.com/google/knative-gcp/pkg/reconciler/events/webhook"
+	"github.com/google/knative-gcp/pkg/reconciler/eventschema"
 	kedapullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
 	staticpullsubscription "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
+	"github.com/google/knative-gcp/pkg/reconciler/knativegcp"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/parallel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/sequence"
 	"github.com/google/knative-gcp/pkg/reconciler/trigger"
 	"github.com/google/knative-gcp/pkg/utils/appcredentials"
 	"knative.dev/pkg/injection"
@@ -45,6 +57,9 @@ import (
 
 func main() {
 	appcredentials.MustExistOrUnsetEnv()
+	if err := reconciler.InitFencingToken(metadataClient.NewDefaultMetadataClient()); err != nil {
+		log.Printf("Failed to get the fencing token, Pub/Sub deletions are not fenced: %v", err)
+	}
 	ctx := signals.NewContext()
 	controllers, err := InitializeControllers(ctx)
 	if err != nil {
@@ -59,25 +74,41 @@ func Controllers(
 	schedulerController scheduler.Constructor,
 	pubsubController pubsub.Constructor,
 	buildController build.Constructor,
+	monitoringController monitoring.Constructor,
+	billingController billing.Constructor,
+	secretmanagerController secretmanager.Constructor,
+	artifactregistryController artifactregistry.Constructor,
 	pullsubscriptionController staticpullsubscription.Constructor,
 	kedaPullsubscriptionController kedapullsubscription.Constructor,
 	topicController topic.Constructor,
 	channelController channel.Constructor,
+	sequenceController sequence.Constructor,
+	parallelController parallel.Constructor,
 ) []injection.ControllerConstructor {
 	return []injection.ControllerConstructor{
-		injection.ControllerConstructor(auditlogsController),
-		injection.ControllerConstructor(storageController),
-		injection.ControllerConstructor(schedulerController),
-		injection.ControllerConstructor(pubsubController),
-		injection.ControllerConstructor(buildController),
-		injection.ControllerConstructor(pullsubscriptionController),
-		injection.ControllerConstructor(kedaPullsubscriptionController),
-		injection.ControllerConstructor(topicController),
-		injection.ControllerConstructor(channelController),
-		deployment.NewController,
-		broker.NewController,
-		trigger.NewController,
-		brokercell.NewController,
+		withThreads("cloudauditlogssource", injection.ControllerConstructor(auditlogsController)),
+		withThreads("cloudstoragesource", injection.ControllerConstructor(storageController)),
+		withThreads("cloudschedulersource", injection.ControllerConstructor(schedulerController)),
+		withThreads("cloudpubsubsource", injection.ControllerConstructor(pubsubController)),
+		withThreads("cloudbuildsource", injection.ControllerConstructor(buildController)),
+		withThreads("cloudmonitoringalertsource", injection.ControllerConstructor(monitoringController)),
+		withThreads("cloudbillingbudgetsource", injection.ControllerConstructor(billingController)),
+		withThreads("secretmanagerrotationsource", injection.ControllerConstructor(secretmanagerController)),
+		withThreads("cloudartifactregistrysource", injection.ControllerConstructor(artifactregistryController)),
+		withThreads("pullsubscription", injection.ControllerConstructor(pullsubscriptionController)),
+		withThreads("keda-pullsubscription", injection.ControllerConstructor(kedaPullsubscriptionController)),
+		withThreads("topic", injection.ControllerConstructor(topicController)),
+		withThreads("channel", injection.ControllerConstructor(channelController)),
+		withThreads("sequence", injection.ControllerConstructor(sequenceController)),
+		withThreads("parallel", injection.ControllerConstructor(parallelController)),
+		withThreads("deployment", deployment.NewController),
+		withThreads("broker", broker.NewController),
+		withThreads("trigger", trigger.NewController),
+		withThreads("brokercell", brokercell.NewController),
+		withThreads("eventschema", eventschema.NewController),
+		withThreads("knativegcp", knativegcp.NewController),
+		withThreads("sourceset", sourceset.NewController),
+		withThreads("webhooksource", webhook.NewController),
 	}
 }
 
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+// controllerThreads is the number of workers of reconcilers, keyed by
+// reconciler name, overriding controller.DefaultThreadsPerController.
+type controllerThreads map[string]int
+
+var threads = controllerThreads{}
+
+func init() {
+	flag.IntVar(&controller.DefaultThreadsPerController, "threads-per-controller", controller.DefaultThreadsPerController,
+		"The number of workers of each reconciler.")
+	flag.Var(threads, "controller-threads",
+		"Comma separated name=threads pairs raising the number of workers of the named reconcilers above --threads-per-controller, e.g. pullsubscription=8,trigger=8.")
+}
+
+func (t controllerThreads) String() string {
+	pairs := make([]string, 0, len(t))
+	for name, n := range t {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", name, n))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (t controllerThreads) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid reconciler threads %q, expected name=threads", pair)
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of threads for reconciler %q: %q", parts[0], parts[1])
+		}
+		t[parts[0]] = n
+	}
+	return nil
+}
+
+// withThreads wraps the constructor of the named reconciler so that it runs
+// with the number of workers set by --controller-threads. sharedmain starts
+// every controller with controller.DefaultThreadsPerController workers, so
+// the additional ones are started here once the informers have synced.
+func withThreads(name string, ctor injection.ControllerConstructor) injection.ControllerConstructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		impl := ctor(ctx, cmw)
+		n, ok := threads[name]
+		if !ok {
+			return impl
+		}
+		extra := n - controller.DefaultThreadsPerController
+		if extra <= 0 {
+			logging.FromContext(ctx).Warnf("Ignoring %d threads for reconciler %q, it can't be lower than --threads-per-controller=%d",
+				n, name, controller.DefaultThreadsPerController)
+			return impl
+		}
+		go func() {
+			if !waitForInformersSync(ctx) {
+				return
+			}
+			impl.RunContext(ctx, extra)
+		}()
+		return impl
+	}
+}
+
+// waitForInformersSync waits until the caches of the informers started by
+// sharedmain have synced. It returns false if ctx is done first.
+func waitForInformersSync(ctx context.Context) bool {
+	var synced []cache.InformerSynced
+	for _, ii := range injection.Default.GetInformers() {
+		// The informers come from shared factories, so this returns the
+		// informers that sharedmain starts rather than new ones.
+		_, inf := ii(ctx)
+		synced = append(synced, inf.HasSynced)
+	}
+	return cache.WaitForCacheSync(ctx.Done(), synced...)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestControllerThreadsSet(t *testing.T) {
+	testCases := map[string]struct {
+		value   string
+		want    controllerThreads
+		wantErr bool
+	}{
+		"empty": {
+			value: "",
+			want:  controllerThreads{},
+		},
+		"single": {
+			value: "trigger=8",
+			want:  controllerThreads{"trigger": 8},
+		},
+		"multiple": {
+			value: "pullsubscription=8,trigger=4,",
+			want:  controllerThreads{"pullsubscription": 8, "trigger": 4},
+		},
+		"missing threads": {
+			value:   "trigger",
+			wantErr: true,
+		},
+		"not a number": {
+			value:   "trigger=eight",
+			wantErr: true,
+		},
+		"zero": {
+			value:   "trigger=0",
+			wantErr: true,
+		},
+		"negative": {
+			value:   "trigger=-1",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := controllerThreads{}
+			err := got.Set(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Set(%q) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Set(%q) unexpected threads (-want, +got) = %v", tc.value, diff)
+			}
+		})
+	}
+}
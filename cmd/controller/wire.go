@@ -19,16 +19,22 @@ import (
 	"context"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/reconciler/events/artifactregistry"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billing"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/parallel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/sequence"
 	"github.com/google/wire"
 	"knative.dev/pkg/injection"
 )
@@ -44,9 +50,15 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 		scheduler.NewConstructor,
 		pubsub.NewConstructor,
 		build.NewConstructor,
+		monitoring.NewConstructor,
+		billing.NewConstructor,
+		secretmanager.NewConstructor,
+		artifactregistry.NewConstructor,
 		static.NewConstructor,
 		keda.NewConstructor,
 		topic.NewConstructor,
 		channel.NewConstructor,
+		sequence.NewConstructor,
+		parallel.NewConstructor,
 	))
 }
@@ -9,16 +9,22 @@ import (
 	"cloud.google.com/go/iam/admin/apiv1"
 	"context"
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/reconciler/events/artifactregistry"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs"
+	"github.com/google/knative-gcp/pkg/reconciler/events/billing"
 	"github.com/google/knative-gcp/pkg/reconciler/events/build"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring"
 	"github.com/google/knative-gcp/pkg/reconciler/events/pubsub"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/secretmanager"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/static"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic"
 	"github.com/google/knative-gcp/pkg/reconciler/messaging/channel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/parallel"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/sequence"
 	"knative.dev/pkg/injection"
 )
 
@@ -44,10 +50,16 @@ func InitializeControllers(ctx context.Context) ([]injection.ControllerConstruct
 	schedulerConstructor := scheduler.NewConstructor(iamPolicyManager, storeSingleton)
 	pubsubConstructor := pubsub.NewConstructor(iamPolicyManager, storeSingleton)
 	buildConstructor := build.NewConstructor(iamPolicyManager, storeSingleton)
+	monitoringConstructor := monitoring.NewConstructor(iamPolicyManager, storeSingleton)
+	billingConstructor := billing.NewConstructor(iamPolicyManager, storeSingleton)
+	secretmanagerConstructor := secretmanager.NewConstructor(iamPolicyManager, storeSingleton)
+	artifactregistryConstructor := artifactregistry.NewConstructor(iamPolicyManager, storeSingleton)
 	staticConstructor := static.NewConstructor(iamPolicyManager, storeSingleton)
 	kedaConstructor := keda.NewConstructor(iamPolicyManager, storeSingleton)
 	topicConstructor := topic.NewConstructor(iamPolicyManager, storeSingleton)
 	channelConstructor := channel.NewConstructor(iamPolicyManager, storeSingleton)
-	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor)
+	sequenceConstructor := sequence.NewConstructor(iamPolicyManager, storeSingleton)
+	parallelConstructor := parallel.NewConstructor(iamPolicyManager, storeSingleton)
+	v2 := Controllers(constructor, storageConstructor, schedulerConstructor, pubsubConstructor, buildConstructor, monitoringConstructor, billingConstructor, secretmanagerConstructor, artifactregistryConstructor, staticConstructor, kedaConstructor, topicConstructor, channelConstructor, sequenceConstructor, parallelConstructor)
 	return v2, nil
 }
@@ -0,0 +1,214 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"time"
+
+	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/slo"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/knative-gcp/pkg/utils"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/system"
+)
+
+const (
+	fanoutComponent       = "broker-fanout"
+	fanoutMetricNamespace = "trigger"
+)
+
+type fanoutEnvConfig struct {
+	PodName                string `envconfig:"POD_NAME" required:"true"`
+	ProjectID              string `envconfig:"PROJECT_ID"`
+	TargetsConfigPath      string `envconfig:"TARGETS_CONFIG_PATH" default:"/var/run/cloud-run-events/broker/targets"`
+	HandlerConcurrency     int    `envconfig:"HANDLER_CONCURRENCY"`
+	MaxConcurrencyPerEvent int    `envconfig:"MAX_CONCURRENCY_PER_EVENT"`
+
+	// PriorityHandlerConcurrency is the number of goroutines pulling the
+	// priority queue of each broker. If not set, it is twice the
+	// HandlerConcurrency.
+	PriorityHandlerConcurrency int `envconfig:"PRIORITY_HANDLER_CONCURRENCY"`
+
+	// OutstandingBytesPerSub is the max size of the messages outstanding
+	// in the subscription of each broker. If not set, a quarter of the
+	// memory limit of the container is divided between the subscriptions,
+	// or the Pub/Sub default is used if the container has no limit.
+	OutstandingBytesPerSub int `envconfig:"OUTSTANDING_BYTES_PER_SUB"`
+
+	// MaxParallelKeys is the max number of ordering keys processed in
+	// parallel for brokers with message ordering enabled.
+	MaxParallelKeys int `envconfig:"MAX_PARALLEL_KEYS"`
+
+	// MaxStaleDuration is the max duration of the handler pool without being synced.
+	// With the internal pool resync period being 15s, it requires at least 4
+	// continuous sync failures (or no sync at all) to be stale.
+	MaxStaleDuration time.Duration `envconfig:"MAX_STALE_DURATION" default:"1m"`
+
+	// Max to 10m.
+	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// BrokerCell is the name of the BrokerCell of the fanout. Only the
+	// brokers served by the BrokerCell are handled.
+	BrokerCell string `envconfig:"BROKER_CELL"`
+
+	// PublishStatusInterval is how often the failures to publish to the
+	// retry topics are written to the publish status ConfigMap. Zero
+	// disables the reporting.
+	PublishStatusInterval time.Duration `envconfig:"PUBLISH_STATUS_INTERVAL" default:"10s"`
+
+	// SLOStatusInterval is how often the delivery counts of the triggers are
+	// written to the SLO status ConfigMap, for the delivery SLO conditions of
+	// the triggers. Zero disables the reporting.
+	SLOStatusInterval time.Duration `envconfig:"SLO_STATUS_INTERVAL" default:"30s"`
+
+	// DeliveryHeadersPath is the directory the delivery headers secret is
+	// mounted at.
+	DeliveryHeadersPath string `envconfig:"DELIVERY_HEADERS_PATH" default:"/var/run/cloud-run-events/delivery-headers"`
+
+	// CaptureResponseHeaders is the comma separated list of the response
+	// headers captured from failed deliveries, e.g. X-Request-Id, and
+	// attached to the debug logs and trace spans of the failures.
+	CaptureResponseHeaders []string `envconfig:"CAPTURE_RESPONSE_HEADERS"`
+
+	// CABundlePath is the path of a file of PEM encoded CA certificates
+	// trusted on top of the system ones when delivering events.
+	CABundlePath string `envconfig:"CA_BUNDLE_PATH"`
+
+	// LeaseEnvConfig configures the lease the region must hold to pull the
+	// queues, when brokers are run in several regions.
+	LeaseEnvConfig
+}
+
+// runFanout creates and starts the fanout sync pool.
+func runFanout() {
+	var env fanoutEnvConfig
+	ctx, res := mainhelper.Init(fanoutComponent, mainhelper.WithMetricNamespace(fanoutMetricNamespace), mainhelper.WithEnv(&env))
+	defer res.Cleanup()
+	logger := res.Logger
+
+	if env.MaxStaleDuration > 0 && env.MaxStaleDuration < poolResyncPeriod {
+		logger.Fatalf("MAX_STALE_DURATION must be greater than pool resync period %v", poolResyncPeriod)
+	}
+
+	// Give the signal channel some buffer so that reconciling handlers won't
+	// block the targets config update?
+	targetsUpdateCh := make(chan struct{})
+
+	logger.Info("Starting the broker fanout")
+
+	projectID, err := utils.ProjectID(env.ProjectID, metadataClient.NewDefaultMetadataClient())
+	if err != nil {
+		logger.Fatalf("failed to get default ProjectID: %v", err)
+	}
+
+	opts := buildFanoutHandlerOptions(env)
+	if env.PublishStatusInterval > 0 {
+		publishStatus := status.NewReporter(ctx, res.KubeClient, system.Namespace(), env.PodName)
+		opts = append(opts, handler.WithPublishStatus(publishStatus))
+		go publishStatus.Run(ctx, env.PublishStatusInterval)
+	}
+	if env.SLOStatusInterval > 0 {
+		go slo.NewEvaluator(ctx, res.KubeClient, system.Namespace(), env.PodName).Run(ctx, env.SLOStatusInterval)
+	}
+
+	if w, ok := newKeyWrapper(ctx, logger); ok {
+		opts = append(opts, handler.WithDecrypter(encryption.NewDecrypter(w)))
+	}
+	opts = append(opts, handler.WithDeliveryHeaders(headers.NewFiles(env.DeliveryHeadersPath)))
+	opts = append(opts, handler.WithCaptureResponseHeaders(env.CaptureResponseHeaders...))
+	opts = append(opts, handler.WithSchemas(newSchemaRegistry(res)))
+
+	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
+	if o, ok := startLease(ctx, logger, env.LeaseEnvConfig, fanoutComponent, env.BrokerCell, syncSignal); ok {
+		opts = append(opts, o)
+	}
+	syncPool, err := InitializeFanoutSyncPool(
+		ctx,
+		handler.ProjectID(projectID),
+		metrics.PodName(env.PodName),
+		metrics.ContainerName(fanoutComponent),
+		[]volume.Option{
+			volume.WithPath(env.TargetsConfigPath),
+			volume.WithNotifyChan(targetsUpdateCh),
+		},
+		handler.CABundlePath(env.CABundlePath),
+		opts...,
+	)
+	if err != nil {
+		logger.Fatal("Failed to create fanout sync pool", zap.Error(err))
+	}
+	if _, err := handler.StartSyncPool(ctx, syncPool, syncSignal, env.MaxStaleDuration, handler.DefaultHealthCheckPort); err != nil {
+		logger.Fatalw("Failed to start fanout sync pool", zap.Error(err))
+	}
+
+	// Context will be done if a TERM signal is issued.
+	<-ctx.Done()
+	// Wait a grace period for the handlers to shutdown.
+	time.Sleep(30 * time.Second)
+	logger.Info("Done waiting, exit.")
+}
+
+func buildFanoutHandlerOptions(env fanoutEnvConfig) []handler.Option {
+	rs := pubsub.DefaultReceiveSettings
+	var opts []handler.Option
+	if env.HandlerConcurrency > 0 {
+		// Let the pubsub subscription and handler have the same concurrency?
+		opts = append(opts, handler.WithHandlerConcurrency(env.HandlerConcurrency))
+		rs.NumGoroutines = env.HandlerConcurrency
+	} else {
+		// The handler defaults to the CPUs of the node, which overcommits
+		// containers with a lower CPU quota.
+		opts = append(opts, handler.WithHandlerConcurrency(tuning.BatchSize(1)))
+	}
+	if env.MaxConcurrencyPerEvent > 0 {
+		opts = append(opts, handler.WithMaxConcurrentPerEvent(env.MaxConcurrencyPerEvent))
+	}
+	if env.MaxParallelKeys > 0 {
+		opts = append(opts, handler.WithMaxParallelKeys(env.MaxParallelKeys))
+	}
+	if env.TimeoutPerEvent > 0 {
+		opts = append(opts, handler.WithTimeoutPerEvent(env.TimeoutPerEvent))
+	}
+	if env.BrokerCell != "" {
+		opts = append(opts, handler.WithBrokerCell(env.BrokerCell))
+	}
+	if env.OutstandingBytesPerSub > 0 {
+		rs.MaxOutstandingBytes = env.OutstandingBytesPerSub
+	} else {
+		opts = append(opts, handler.WithOutstandingBytesBudget(tuning.OutstandingBytesBudget()))
+	}
+	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
+	if env.PriorityHandlerConcurrency > 0 {
+		prs := rs
+		prs.NumGoroutines = env.PriorityHandlerConcurrency
+		// Same as the default priority receive settings.
+		prs.MaxOutstandingMessages *= 2
+		opts = append(opts, handler.WithPriorityPubsubReceiveSettings(prs))
+	}
+	// The default CeClient is good?
+	return opts
+}
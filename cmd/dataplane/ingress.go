@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"time"
+
+	"github.com/google/knative-gcp/pkg/broker/auth"
+	"github.com/google/knative-gcp/pkg/broker/compression"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/broker/status"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/knative-gcp/pkg/utils"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	knmetrics "knative.dev/pkg/metrics"
+	"knative.dev/pkg/system"
+)
+
+type ingressEnvConfig struct {
+	PodName   string `envconfig:"POD_NAME" required:"true"`
+	Port      int    `envconfig:"PORT" default:"8080"`
+	ProjectID string `envconfig:"PROJECT_ID"`
+
+	// PublisherIdleTTL is how long the publisher of a broker's decouple topic
+	// is kept after the last event sent to the broker. Zero keeps publishers
+	// until the broker is removed.
+	PublisherIdleTTL time.Duration `envconfig:"PUBLISHER_IDLE_TTL" default:"10m"`
+
+	// MaxInFlightPublishes is how many events of a batched request are
+	// published before waiting for the result of the oldest one. If not
+	// set, it is 100 per CPU of the container, weighted by architecture.
+	MaxInFlightPublishes int `envconfig:"MAX_IN_FLIGHT_PUBLISHES"`
+
+	// ReadHeaderTimeout is how long the ingress waits for the headers of a
+	// request before closing the connection.
+	ReadHeaderTimeout time.Duration `envconfig:"READ_HEADER_TIMEOUT" default:"10s"`
+
+	// ReadTimeout is how long the ingress waits for a whole request,
+	// including its body.
+	ReadTimeout time.Duration `envconfig:"READ_TIMEOUT" default:"1m"`
+
+	// IdleTimeout is how long the ingress keeps idle keep-alive connections
+	// open.
+	IdleTimeout time.Duration `envconfig:"IDLE_TIMEOUT" default:"2m"`
+
+	// MaxHeaderBytes is the maximum size of the headers of a request.
+	MaxHeaderBytes int `envconfig:"MAX_HEADER_BYTES" default:"65536"`
+
+	// MaxBodyBytes is the maximum size of the body of a request. The default
+	// is the maximum size of a Pub/Sub message.
+	MaxBodyBytes int64 `envconfig:"MAX_BODY_BYTES" default:"10485760"`
+
+	// PublishStatusInterval is how often the publish failures requiring an
+	// action from the user are written to the publish status ConfigMap.
+	// Zero disables the reporting.
+	PublishStatusInterval time.Duration `envconfig:"PUBLISH_STATUS_INTERVAL" default:"10s"`
+
+	// Compression is the content encoding the event data published to the
+	// decouple topics is compressed with, either "gzip" or "zstd". Empty
+	// disables compression.
+	Compression string `envconfig:"COMPRESSION"`
+
+	// CompressionMinBytes is the size under which event data is not
+	// compressed.
+	CompressionMinBytes int `envconfig:"COMPRESSION_MIN_BYTES" default:"1024"`
+
+	// FailoverPubsubEndpoint is the regional Pub/Sub endpoint of the
+	// secondary region the events are published through when the primary
+	// region fails, e.g. europe-west1-pubsub.googleapis.com:443. Empty
+	// disables failover.
+	FailoverPubsubEndpoint string `envconfig:"FAILOVER_PUBSUB_ENDPOINT"`
+
+	// FailoverCooldown is how long the events are published to the
+	// secondary region before the primary region is tried again.
+	FailoverCooldown time.Duration `envconfig:"FAILOVER_COOLDOWN" default:"30s"`
+}
+
+const (
+	ingressComponent       = "broker-ingress"
+	ingressMetricNamespace = "broker"
+)
+
+// runIngress creates and starts an ingress handler using default options.
+// 1. It listens on port specified by "PORT" env var, or default 8080 if env var is not set
+// 2. It reads "PROJECT_ID" env var for pubsub project. If the env var is empty, it retrieves project ID from
+//    GCE metadata.
+// 3. It expects broker configmap mounted at "/var/run/cloud-run-events/broker/targets"
+// 4. It access logs a sample of the requests according to "broker.ingress.access-log-sample-rate" in
+//    the config-observability ConfigMap.
+// 5. It stops the publishers of brokers that haven't received events for "PUBLISHER_IDLE_TTL".
+// 6. It keeps up to "MAX_IN_FLIGHT_PUBLISHES" events of a batched request in flight, by default
+//    scaled with the CPU quota and architecture of the container.
+// 7. It closes connections whose headers are not received within "READ_HEADER_TIMEOUT", or whose
+//    request is not received within "READ_TIMEOUT", and idle connections after "IDLE_TIMEOUT".
+// 8. It rejects requests whose headers exceed "MAX_HEADER_BYTES", or whose body exceeds "MAX_BODY_BYTES".
+// 9. It reports publish failures requiring an action from the user to the broker-publish-status
+//    ConfigMap every "PUBLISH_STATUS_INTERVAL".
+// 10. It encrypts the event data of brokers with an encryption key with Cloud KMS wrapped data keys.
+// 11. It compresses event data of at least "COMPRESSION_MIN_BYTES" with the "COMPRESSION" encoding.
+// 12. It validates the event data against the EventSchemas of the broker-event-schemas ConfigMap.
+// 13. It publishes through the "FAILOVER_PUBSUB_ENDPOINT" of a secondary region for "FAILOVER_COOLDOWN"
+//    when the primary region fails.
+func runIngress() {
+	var env ingressEnvConfig
+	ctx, res := mainhelper.Init(ingressComponent, mainhelper.WithMetricNamespace(ingressMetricNamespace), mainhelper.WithEnv(&env))
+	defer res.Cleanup()
+	logger := res.Logger
+
+	projectID, err := utils.ProjectID(env.ProjectID, metadataClient.NewDefaultMetadataClient())
+	if err != nil {
+		logger.Desugar().Fatal("Failed to create project id", zap.Error(err))
+	}
+	logger.Desugar().Info("Starting ingress handler", zap.Any("ingressEnvConfig", env), zap.Any("Project ID", projectID))
+
+	if env.MaxInFlightPublishes <= 0 {
+		env.MaxInFlightPublishes = tuning.BatchSize(100)
+	}
+
+	compressor, err := compression.NewCompressor(env.Compression, env.CompressionMinBytes)
+	if err != nil {
+		logger.Desugar().Fatal("Invalid compression", zap.Error(err))
+	}
+
+	ingress, err := InitializeIngressHandler(
+		ctx,
+		ingress.Port(env.Port),
+		ingress.ProjectID(projectID),
+		metrics.PodName(env.PodName),
+		metrics.ContainerName(ingressComponent),
+		ingress.PublisherIdleTTL(env.PublisherIdleTTL),
+		ingress.MaxInFlightPublishes(env.MaxInFlightPublishes),
+		ingress.ServerLimits{
+			ReadHeaderTimeout: env.ReadHeaderTimeout,
+			ReadTimeout:       env.ReadTimeout,
+			IdleTimeout:       env.IdleTimeout,
+			MaxHeaderBytes:    env.MaxHeaderBytes,
+		},
+		ingress.MaxBodyBytes(env.MaxBodyBytes),
+		compressor,
+		ingress.Failover{
+			Endpoint: env.FailoverPubsubEndpoint,
+			Cooldown: env.FailoverCooldown,
+		},
+	)
+	if err != nil {
+		logger.Desugar().Fatal("Unable to create ingress handler: ", zap.Error(err))
+	}
+
+	// The ConfigMap watcher has already started, so load the current
+	// observability config before watching for changes.
+	if cm, err := res.KubeClient.CoreV1().ConfigMaps(system.Namespace()).Get(knmetrics.ConfigMapName(), metav1.GetOptions{}); err != nil {
+		logger.Desugar().Warn("Failed to get the observability config, access logs are disabled", zap.Error(err))
+	} else {
+		ingress.UpdateFromObservabilityConfigMap(cm)
+	}
+	res.CMPWatcher.Watch(knmetrics.ConfigMapName(), ingress.UpdateFromObservabilityConfigMap)
+
+	if env.PublishStatusInterval > 0 {
+		publishStatus := status.NewReporter(ctx, res.KubeClient, system.Namespace(), env.PodName)
+		ingress.SetPublishStatusReporter(publishStatus)
+		go publishStatus.Run(ctx, env.PublishStatusInterval)
+	}
+
+	if w, ok := newKeyWrapper(ctx, logger); ok {
+		ingress.SetEncrypter(encryption.NewEncrypter(w))
+	}
+	ingress.SetSchemas(newSchemaRegistry(res))
+	ingress.SetTokens(auth.NewTokens(auth.DefaultDir))
+
+	logger.Desugar().Info("Starting ingress.", zap.Any("ingress", ingress))
+	if err := ingress.Start(ctx); err != nil {
+		logger.Desugar().Fatal("failed to start ingress: ", zap.Error(err))
+	}
+}
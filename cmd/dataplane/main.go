@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command dataplane runs one of the data plane components. All of them are
+// built into this single binary (and image), and the component to run is
+// selected with the --role flag:
+//
+//   - ingress: the broker ingress, which publishes events to the decouple topics.
+//   - fanout: the broker fanout, which delivers events to the triggers.
+//   - retry: the broker retry, which redelivers events that failed delivery.
+//   - receive-adapter: the PullSubscription receive adapter.
+//   - webhook-receiver: the WebhookSource receiver.
+//
+// Each role is configured through its own env vars.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"path"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/lease"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/utils/appcredentials"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+	"github.com/google/knative-gcp/pkg/utils/platform"
+)
+
+const (
+	ingressRole         = "ingress"
+	fanoutRole          = "fanout"
+	retryRole           = "retry"
+	receiveAdapterRole  = "receive-adapter"
+	webhookReceiverRole = "webhook-receiver"
+
+	poolResyncPeriod = 15 * time.Second
+)
+
+var role = flag.String("role", "", "The data plane component to run: ingress, fanout, retry, receive-adapter or webhook-receiver.")
+
+// tuning is the runtime tuning for the architecture and the CPU and memory
+// limits of the container. The default concurrency and flow control of the
+// components scale with it.
+var tuning = platform.Detect()
+
+func main() {
+	flag.Parse()
+	appcredentials.MustExistOrUnsetEnv()
+	log.Printf("Running on %s with %.2f CPUs and GOMAXPROCS %d", tuning.Arch, tuning.CPUs, tuning.SetMaxProcs())
+	if tuning.MemoryLimit > 0 {
+		log.Printf("Running with a %d bytes memory limit and a %d bytes soft memory limit", tuning.MemoryLimit, tuning.SetMemoryLimit())
+	}
+
+	switch *role {
+	case ingressRole:
+		runIngress()
+	case fanoutRole:
+		runFanout()
+	case retryRole:
+		runRetry()
+	case receiveAdapterRole:
+		runReceiveAdapter()
+	case webhookReceiverRole:
+		runWebhookReceiver()
+	default:
+		log.Fatalf("Unknown role %q, must be one of %q, %q, %q, %q or %q",
+			*role, ingressRole, fanoutRole, retryRole, receiveAdapterRole, webhookReceiverRole)
+	}
+}
+
+// poolSyncSignal returns a channel signaled when the targets config is
+// updated and every poolResyncPeriod, to sync the handler pool of the fanout
+// and retry.
+func poolSyncSignal(ctx context.Context, targetsUpdateCh chan struct{}) chan struct{} {
+	// Give it some buffer so that multiple signal could queue up
+	// but not blocking the signaler?
+	ch := make(chan struct{}, 10)
+	ticker := time.NewTicker(poolResyncPeriod)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-targetsUpdateCh:
+				ch <- struct{}{}
+			case <-ticker.C:
+				ch <- struct{}{}
+			}
+		}
+	}()
+	return ch
+}
+
+// newKeyWrapper creates the Cloud KMS key wrapper used to encrypt and decrypt
+// the event data of brokers with an encryption key. If it cannot be created,
+// the events of those brokers are rejected by the ingress and not delivered
+// by the fanout and retry, while other brokers are served as usual.
+func newKeyWrapper(ctx context.Context, logger *zap.SugaredLogger) (encryption.KeyWrapper, bool) {
+	w, err := encryption.NewKMSKeyWrapper(ctx)
+	if err != nil {
+		logger.Warnw("Failed to create the Cloud KMS client, event encryption is disabled", zap.Error(err))
+		return nil, false
+	}
+	return w, true
+}
+
+// LeaseEnvConfig configures the lease the fanouts and retries of a region
+// must hold to pull the broker queues, when brokers are run in several
+// regions for disaster recovery.
+type LeaseEnvConfig struct {
+	// LeaseBucket is the Cloud Storage bucket holding the lease, which all
+	// the regions must reach. Empty disables the lease, the queues are
+	// always pulled.
+	LeaseBucket string `envconfig:"LEASE_BUCKET"`
+
+	// LeaseObject is the object of the lease in LeaseBucket. It defaults to
+	// leases/<brokercell>/<component>.
+	LeaseObject string `envconfig:"LEASE_OBJECT"`
+
+	// Region identifies the region holding the lease. All the pods of a
+	// region share the lease.
+	Region string `envconfig:"REGION"`
+
+	// LeaseDuration is how long the lease is held after it is renewed, i.e.
+	// how long it takes another region to take over.
+	LeaseDuration time.Duration `envconfig:"LEASE_DURATION" default:"30s"`
+
+	// LeaseReplayLookback is how far before the last renewal of the previous
+	// holder the queues are replayed when the lease is taken over.
+	LeaseReplayLookback time.Duration `envconfig:"LEASE_REPLAY_LOOKBACK" default:"5m"`
+}
+
+// startLease starts acquiring and renewing the lease of the region, if
+// enabled, and returns the option to only pull the queues while holding it.
+// syncSignal is signaled when the lease changes hands.
+func startLease(ctx context.Context, logger *zap.SugaredLogger, env LeaseEnvConfig, component, brokerCell string, syncSignal chan<- struct{}) (handler.Option, bool) {
+	if env.LeaseBucket == "" {
+		return nil, false
+	}
+	if env.Region == "" {
+		logger.Fatal("REGION must be set along with LEASE_BUCKET")
+	}
+	client, err := storage.NewClient(ctx, endpoints.Storage()...)
+	if err != nil {
+		logger.Fatalw("Failed to create the Cloud Storage client of the lease", zap.Error(err))
+	}
+	object := env.LeaseObject
+	if object == "" {
+		object = path.Join("leases", brokerCell, component)
+	}
+	e := lease.NewElector(lease.NewGCSStore(client, env.LeaseBucket, object), env.Region, env.LeaseDuration, env.LeaseReplayLookback, syncSignal)
+	go e.Run(ctx)
+	return handler.WithLease(e), true
+}
+
+// newSchemaRegistry creates the registry of the EventSchemas the events of
+// brokers are validated against. The ConfigMap watcher has already started,
+// so the current schemas are loaded before watching for changes.
+func newSchemaRegistry(res *mainhelper.InitRes) *schema.Registry {
+	logger := res.Logger.Desugar()
+	r := schema.NewRegistry(logger)
+	if cm, err := res.KubeClient.CoreV1().ConfigMaps(system.Namespace()).Get(schema.ConfigMapName, metav1.GetOptions{}); err != nil {
+		if !apierrs.IsNotFound(err) {
+			logger.Warn("Failed to get the event schemas, events are not validated until they change", zap.Error(err))
+		}
+	} else {
+		r.UpdateFromConfigMap(cm)
+	}
+	res.CMPWatcher.Watch(schema.ConfigMapName, r.UpdateFromConfigMap)
+	return r
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package main
 
 import (
-	"flag"
 	"fmt"
 
 	"knative.dev/eventing/pkg/tracing"
@@ -33,16 +32,15 @@ import (
 )
 
 const (
-	component = "PullSubscription::ReceiveAdapter"
+	receiveAdapterComponent = "PullSubscription::ReceiveAdapter"
 )
 
-func main() {
-	flag.Parse()
-
-	startable := adapter.Adapter{}
-	if err := envconfig.Process("", &startable); err != nil {
-		panic(fmt.Sprintf("Failed to process env var: %s", err))
-	}
+// runReceiveAdapter creates and starts a PullSubscription receive adapter.
+// The Pub/Sub transport of the adapter keeps the default flow control of the
+// subscriptions it doesn't create, so its memory is only bounded by the soft
+// memory limit set from the container memory limit in main.
+func runReceiveAdapter() {
+	startable := newReceiveAdapter()
 
 	// Convert json logging.Config to logging.Config.
 	loggingConfig, err := logging.JsonToLoggingConfig(startable.LoggingConfigJson)
@@ -55,7 +53,7 @@ func main() {
 		}
 	}
 
-	sl, _ := logging.NewLoggerFromConfig(loggingConfig, component)
+	sl, _ := logging.NewLoggerFromConfig(loggingConfig, receiveAdapterComponent)
 	logger := sl.Desugar()
 	defer flush(logger)
 	ctx := logging.WithLogger(signals.NewContext(), logger.Sugar())
@@ -94,6 +92,24 @@ func main() {
 	}
 }
 
+// newReceiveAdapter returns the adapter configured by the versioned config in
+// the environment, or by the legacy environment variables of the receive
+// adapters created by older reconcilers.
+func newReceiveAdapter() *adapter.Adapter {
+	config, err := adapter.ConfigFromEnv()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to process the receive adapter config: %s", err))
+	}
+	if config != nil {
+		return config.Adapter()
+	}
+	startable := &adapter.Adapter{}
+	if err := envconfig.Process("", startable); err != nil {
+		panic(fmt.Sprintf("Failed to process env var: %s", err))
+	}
+	return startable
+}
+
 func flush(logger *zap.Logger) {
 	_ = logger.Sync()
 	metrics.FlushExporter()
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/slo"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/knative-gcp/pkg/utils"
+	"github.com/google/knative-gcp/pkg/utils/mainhelper"
+)
+
+const (
+	retryComponent       = "broker-retry"
+	retryMetricNamespace = "trigger"
+)
+
+type retryEnvConfig struct {
+	PodName            string `envconfig:"POD_NAME" required:"true"`
+	ProjectID          string `envconfig:"PROJECT_ID"`
+	TargetsConfigPath  string `envconfig:"TARGETS_CONFIG_PATH" default:"/var/run/cloud-run-events/broker/targets"`
+	HandlerConcurrency int    `envconfig:"HANDLER_CONCURRENCY"`
+
+	// Outstanding messages effectively limits how many connections we will create to each subscriber.
+	// If such connections are long, it will consume a lot of memory (aggregated) without limiting.
+	OutstandingMessagesPerSub int `envconfig:"OUTSTANDING_MESSAGES_PER_SUB" default:"100"`
+	// 3Mi. We also want to limit the memory usage from each subscription.
+	OutstandingBytesPerSub int `envconfig:"OUTSTANDING_BYTES_PER_SUB" default:"3000000"`
+
+	// MaxStaleDuration is the max duration of the handler pool without being synced.
+	// With the internal pool resync period being 15s, it requires at least 4
+	// continuous sync failures (or no sync at all) to be stale.
+	MaxStaleDuration time.Duration `envconfig:"MAX_STALE_DURATION" default:"1m"`
+
+	// Max to 10m.
+	TimeoutPerEvent time.Duration `envconfig:"TIMEOUT_PER_EVENT"`
+
+	// MinRetryBackoff and MaxRetryBackoff default to the retry policy of the
+	// retry subscriptions. The retry doesn't start with a MaxRetryBackoff
+	// above config.RetrySubscriptionMaxBackoff.
+	MinRetryBackoff time.Duration `envconfig:"MIN_RETRY_BACKOFF"`
+	MaxRetryBackoff time.Duration `envconfig:"MAX_RETRY_BACKOFF"`
+
+	// DeliveryFailureEventThreshold is the number of consecutive failed deliveries
+	// to a trigger before a Kubernetes event is emitted on the trigger.
+	// Set to 0 to disable the events.
+	DeliveryFailureEventThreshold int `envconfig:"DELIVERY_FAILURE_EVENT_THRESHOLD" default:"5"`
+	// DeliveryFailureEventInterval is the minimum interval between two events for the same trigger.
+	DeliveryFailureEventInterval time.Duration `envconfig:"DELIVERY_FAILURE_EVENT_INTERVAL" default:"10m"`
+
+	// BrokerCell is the name of the BrokerCell of the retry. Only the
+	// targets of brokers served by the BrokerCell are handled.
+	BrokerCell string `envconfig:"BROKER_CELL"`
+
+	// SLOStatusInterval is how often the delivery counts of the triggers are
+	// written to the SLO status ConfigMap, for the delivery SLO conditions of
+	// the triggers. Zero disables the reporting.
+	SLOStatusInterval time.Duration `envconfig:"SLO_STATUS_INTERVAL" default:"30s"`
+
+	// DeliveryHeadersPath is the directory the delivery headers secret is
+	// mounted at.
+	DeliveryHeadersPath string `envconfig:"DELIVERY_HEADERS_PATH" default:"/var/run/cloud-run-events/delivery-headers"`
+
+	// CaptureResponseHeaders is the comma separated list of the response
+	// headers captured from failed deliveries, e.g. X-Request-Id, and
+	// attached to the debug logs and trace spans of the failures.
+	CaptureResponseHeaders []string `envconfig:"CAPTURE_RESPONSE_HEADERS"`
+
+	// CABundlePath is the path of a file of PEM encoded CA certificates
+	// trusted on top of the system ones when delivering events.
+	CABundlePath string `envconfig:"CA_BUNDLE_PATH"`
+
+	// LeaseEnvConfig configures the lease the region must hold to pull the
+	// queues, when brokers are run in several regions.
+	LeaseEnvConfig
+}
+
+// runRetry creates and starts the retry sync pool.
+func runRetry() {
+	var env retryEnvConfig
+	ctx, res := mainhelper.Init(retryComponent, mainhelper.WithMetricNamespace(retryMetricNamespace), mainhelper.WithEnv(&env))
+	defer res.Cleanup()
+	logger := res.Logger
+
+	if env.MaxStaleDuration > 0 && env.MaxStaleDuration < poolResyncPeriod {
+		logger.Fatalf("MAX_STALE_DURATION must be greater than pool resync period %v", poolResyncPeriod)
+	}
+	// The retry subscriptions redeliver the nacked events within their retry
+	// policy, so the events can't back off longer.
+	retryPolicy := retryBackoff(env)
+	if err := config.ValidateRetryBackoff(retryPolicy.MinBackoff, retryPolicy.MaxBackoff); err != nil {
+		logger.Fatal("Invalid MIN_RETRY_BACKOFF or MAX_RETRY_BACKOFF", zap.Error(err))
+	}
+
+	// Give the signal channel some buffer so that reconciling handlers won't
+	// block the targets config update?
+	targetsUpdateCh := make(chan struct{})
+	logger.Info("Starting the broker retry")
+
+	projectID, err := utils.ProjectID(env.ProjectID, metadataClient.NewDefaultMetadataClient())
+	if err != nil {
+		logger.Fatalf("failed to get default ProjectID: %v", err)
+	}
+
+	opts := buildRetryHandlerOptions(env)
+	if env.DeliveryFailureEventThreshold > 0 {
+		opts = append(opts, handler.WithDeliveryFailureEvents(handler.DeliveryFailureEvents{
+			Recorder:  newEventRecorder(ctx, res.KubeClient),
+			Threshold: env.DeliveryFailureEventThreshold,
+			Interval:  env.DeliveryFailureEventInterval,
+		}))
+	}
+	if env.SLOStatusInterval > 0 {
+		go slo.NewEvaluator(ctx, res.KubeClient, system.Namespace(), env.PodName).Run(ctx, env.SLOStatusInterval)
+	}
+
+	if w, ok := newKeyWrapper(ctx, logger); ok {
+		opts = append(opts, handler.WithDecrypter(encryption.NewDecrypter(w)))
+	}
+	opts = append(opts, handler.WithDeliveryHeaders(headers.NewFiles(env.DeliveryHeadersPath)))
+	opts = append(opts, handler.WithCaptureResponseHeaders(env.CaptureResponseHeaders...))
+
+	syncSignal := poolSyncSignal(ctx, targetsUpdateCh)
+	if o, ok := startLease(ctx, logger, env.LeaseEnvConfig, retryComponent, env.BrokerCell, syncSignal); ok {
+		opts = append(opts, o)
+	}
+	syncPool, err := InitializeRetrySyncPool(
+		ctx,
+		handler.ProjectID(projectID),
+		metrics.PodName(env.PodName),
+		metrics.ContainerName(retryComponent),
+		[]volume.Option{
+			volume.WithPath(env.TargetsConfigPath),
+			volume.WithNotifyChan(targetsUpdateCh),
+		},
+		handler.CABundlePath(env.CABundlePath),
+		opts...,
+	)
+	if err != nil {
+		logger.Fatal("Failed to get retry sync pool", zap.Error(err))
+	}
+	if _, err := handler.StartSyncPool(ctx, syncPool, syncSignal, env.MaxStaleDuration, handler.DefaultHealthCheckPort); err != nil {
+		logger.Fatal("Failed to start retry sync pool", zap.Error(err))
+	}
+
+	// Context will be done if a TERM signal is issued.
+	<-ctx.Done()
+	logger.Info("Exiting...")
+}
+
+// newEventRecorder creates an event recorder that writes Kubernetes events
+// until ctx is done.
+func newEventRecorder(ctx context.Context, kubeClient kubernetes.Interface) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	w := eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	go func() {
+		<-ctx.Done()
+		w.Stop()
+	}()
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: retryComponent})
+}
+
+func buildRetryHandlerOptions(env retryEnvConfig) []handler.Option {
+	rs := pubsub.DefaultReceiveSettings
+	// If Synchronous is true, then no more than MaxOutstandingMessages will be in memory at one time.
+	// MaxOutstandingBytes still refers to the total bytes processed, rather than in memory.
+	// NumGoroutines is ignored.
+	// TODO Need to revisit it. For the case when synchronous is true, default value of MaxOutstandingMessages and MaxOutstandingBytes might need to override.
+	rs.Synchronous = true
+	rs.MaxOutstandingMessages = env.OutstandingMessagesPerSub
+	rs.MaxOutstandingBytes = env.OutstandingBytesPerSub
+	var opts []handler.Option
+	if env.HandlerConcurrency > 0 {
+		opts = append(opts, handler.WithHandlerConcurrency(env.HandlerConcurrency))
+		rs.NumGoroutines = env.HandlerConcurrency
+	} else {
+		// The handler defaults to the CPUs of the node, which overcommits
+		// containers with a lower CPU quota.
+		opts = append(opts, handler.WithHandlerConcurrency(tuning.BatchSize(1)))
+	}
+	if env.TimeoutPerEvent > 0 {
+		opts = append(opts, handler.WithTimeoutPerEvent(env.TimeoutPerEvent))
+	}
+	if env.BrokerCell != "" {
+		opts = append(opts, handler.WithBrokerCell(env.BrokerCell))
+	}
+	opts = append(opts, handler.WithRetryPolicy(retryBackoff(env)))
+	opts = append(opts, handler.WithPubsubReceiveSettings(rs))
+	// The default CeClient is good?
+	return opts
+}
+
+// retryBackoff returns the backoff of the requeued events, which defaults to
+// the retry policy of the retry subscriptions.
+func retryBackoff(env retryEnvConfig) handler.RetryPolicy {
+	policy := handler.RetryPolicy{
+		MinBackoff: config.RetrySubscriptionMinBackoff,
+		MaxBackoff: config.RetrySubscriptionMaxBackoff,
+	}
+	if env.MinRetryBackoff > 0 {
+		policy.MinBackoff = env.MinRetryBackoff
+	}
+	if env.MaxRetryBackoff > 0 {
+		policy.MaxBackoff = env.MaxRetryBackoff
+	}
+	return policy
+}
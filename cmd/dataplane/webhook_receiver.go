@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/signals"
+
+	"github.com/google/knative-gcp/pkg/sources/webhook"
+)
+
+const (
+	webhookReceiverComponent = "WebhookSource::Receiver"
+)
+
+// runWebhookReceiver creates and starts a WebhookSource receiver.
+func runWebhookReceiver() {
+	receiver := webhook.Receiver{}
+	if err := envconfig.Process("", &receiver); err != nil {
+		panic(fmt.Sprintf("Failed to process env var: %s", err))
+	}
+
+	sl, _ := logging.NewLogger("", "")
+	logger := sl.Desugar()
+	defer flush(logger)
+	ctx := logging.WithLogger(signals.NewContext(), sl.Named(webhookReceiverComponent))
+
+	logger.Info("Starting WebhookSource receiver.", zap.String("provider", string(receiver.Provider)), zap.String("sink", receiver.Sink))
+	if err := receiver.Start(ctx); err != nil {
+		logger.Fatal("failed to start webhook receiver: ", zap.Error(err))
+	}
+}
@@ -0,0 +1,83 @@
+// +build wireinject
+
+/*
+Copyright 2020 Google LLC.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/google/knative-gcp/pkg/broker/compression"
+	"github.com/google/knative-gcp/pkg/broker/config/volume"
+	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/ingress"
+	"github.com/google/knative-gcp/pkg/metrics"
+	"github.com/google/wire"
+)
+
+func InitializeIngressHandler(
+	ctx context.Context,
+	port ingress.Port,
+	projectID ingress.ProjectID,
+	podName metrics.PodName,
+	containerName metrics.ContainerName,
+	idleTTL ingress.PublisherIdleTTL,
+	maxInFlight ingress.MaxInFlightPublishes,
+	limits ingress.ServerLimits,
+	maxBodyBytes ingress.MaxBodyBytes,
+	compressor *compression.Compressor,
+	failover ingress.Failover,
+) (*ingress.Handler, error) {
+	panic(wire.Build(
+		ingress.HandlerSet,
+		wire.Value([]volume.Option(nil)),
+		volume.NewTargetsFromFile,
+	))
+}
+
+// InitializeFanoutSyncPool initializes the fanout sync pool. Uses the given projectID to initialize the
+// retry pool's pubsub client, uses targetsVolumeOpts to initialize the targets volume watcher and
+// trusts the CA certificates in caBundle, if any, when delivering events.
+func InitializeFanoutSyncPool(
+	ctx context.Context,
+	projectID handler.ProjectID,
+	podName metrics.PodName,
+	containerName metrics.ContainerName,
+	targetsVolumeOpts []volume.Option,
+	caBundle handler.CABundlePath,
+	opts ...handler.Option,
+) (*handler.FanoutPool, error) {
+	// Implementation generated by wire. Providers for required FanoutPool dependencies should be
+	// added here.
+	panic(wire.Build(handler.ProviderSet, volume.NewTargetsFromFile, metrics.NewDeliveryReporter))
+}
+
+// InitializeRetrySyncPool initializes the retry sync pool. Uses the given projectID to initialize the
+// retry pool's pubsub client, uses targetsVolumeOpts to initialize the targets volume watcher and
+// trusts the CA certificates in caBundle, if any, when delivering events.
+func InitializeRetrySyncPool(
+	ctx context.Context,
+	projectID handler.ProjectID,
+	podName metrics.PodName,
+	containerName metrics.ContainerName,
+	targetsVolumeOpts []volume.Option,
+	caBundle handler.CABundlePath,
+	opts ...handler.Option) (*handler.RetryPool, error) {
+	// Implementation generated by wire. Providers for required RetryPool dependencies should be
+	// added here.
+	panic(wire.Build(handler.ProviderSet, volume.NewTargetsFromFile, metrics.NewDeliveryReporter))
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command export dumps the Brokers, Triggers and sources of a cluster to a
+// declarative snapshot and imports such a snapshot into another cluster or
+// project, e.g. for disaster recovery drills:
+//
+//	export -file topology.yaml
+//	export -import -file topology.yaml -namespace-map prod=dr -project-map my-project=my-dr-project
+//	export -file dr.yaml  # against the new cluster, once the imported objects are ready
+//	export -map-ids -file topology.yaml -target dr.yaml -namespace-map prod=dr
+//
+// The last step prints which Google Cloud resources replace the original
+// ones, since the imported objects get resources with new IDs.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"knative.dev/pkg/injection/sharedmain"
+	"sigs.k8s.io/yaml"
+
+	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+	"github.com/google/knative-gcp/pkg/topology"
+)
+
+var (
+	masterURL    = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	kubeconfig   = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	namespace    = flag.String("namespace", "", "Namespace to export. All namespaces are exported if empty.")
+	file         = flag.String("file", "", "Snapshot to write, or to read with -import and -map-ids. Defaults to stdout when exporting.")
+	target       = flag.String("target", "", "Snapshot of the cluster the snapshot was imported into, for -map-ids.")
+	doImport     = flag.Bool("import", false, "Import the snapshot instead of exporting one.")
+	doMapIDs     = flag.Bool("map-ids", false, "Print the Google Cloud resources of the imported objects that replace the original ones.")
+	namespaceMap = flag.String("namespace-map", "", "Comma-separated old=new namespace pairs to apply when importing.")
+	projectMap   = flag.String("project-map", "", "Comma-separated old=new project pairs to apply when importing.")
+)
+
+func main() {
+	flag.Parse()
+
+	namespaces, err := topology.ParseMapping(*namespaceMap)
+	if err != nil {
+		log.Fatalf("Invalid -namespace-map: %v", err)
+	}
+	projects, err := topology.ParseMapping(*projectMap)
+	if err != nil {
+		log.Fatalf("Invalid -project-map: %v", err)
+	}
+	remap := topology.Remap{Namespaces: namespaces, Projects: projects}
+
+	if *doMapIDs {
+		from, to := readSnapshot(*file), readSnapshot(*target)
+		write("", topology.MapIDs(from, to, remap))
+		return
+	}
+
+	cfg, err := sharedmain.GetConfig(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatalf("Error building kubeconfig: %v", err)
+	}
+	client := clientset.NewForConfigOrDie(cfg)
+
+	if *doImport {
+		if err := topology.Import(client, readSnapshot(*file), remap); err != nil {
+			log.Fatalf("Failed to import the snapshot: %v", err)
+		}
+		return
+	}
+
+	s, err := topology.Export(client, *namespace)
+	if err != nil {
+		log.Fatalf("Failed to export the snapshot: %v", err)
+	}
+	write(*file, s)
+}
+
+func readSnapshot(path string) *topology.Snapshot {
+	if path == "" {
+		log.Fatal("Missing snapshot file")
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+	s := &topology.Snapshot{}
+	if err := yaml.Unmarshal(b, s); err != nil {
+		log.Fatalf("Failed to parse %s: %v", path, err)
+	}
+	return s
+}
+
+// write writes v as YAML to path, or to stdout if path is empty.
+func write(path string, v interface{}) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		log.Fatalf("Failed to marshal: %v", err)
+	}
+	if path == "" {
+		_, err = os.Stdout.Write(b)
+	} else {
+		err = ioutil.WriteFile(path, b, 0644)
+	}
+	if err != nil {
+		log.Fatalf("Failed to write: %v", err)
+	}
+}
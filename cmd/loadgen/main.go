@@ -0,0 +1,201 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command loadgen sends CloudEvents to a Broker or a Topic at the rate of a
+// load profile and verifies that they are received back through a Trigger or
+// a PullSubscription, e.g. to qualify the sizing of a BrokerCell with a soak
+// test. It reports the latency and the loss of the events as metrics and,
+// at the end of the run, in its termination message.
+//
+// The -manifest flag prints the resources running the load generator against
+// a Broker or a Topic instead:
+//
+//	loadgen -manifest -name soak -namespace default -image [IMAGE] -broker default \
+//	  -target http://broker-ingress.cloud-run-events.svc.cluster.local/default/default \
+//	  -profile 10-500:10m,500:1h | kubectl apply -f -
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/signals"
+	"sigs.k8s.io/yaml"
+
+	"github.com/google/knative-gcp/pkg/loadgen"
+	"github.com/google/knative-gcp/pkg/loadgen/resources"
+)
+
+const (
+	component     = "loadgen"
+	metricsDomain = "cloud.google.com/events"
+)
+
+var (
+	target         = flag.String("target", "", "The address of the Broker or Topic to send events to.")
+	profile        = flag.String("profile", "10:1m", "The load profile, as comma-separated rate:duration steps where rate is a number of events per second or a from-to ramp, e.g. 10-100:1m,100:10m.")
+	source         = flag.String("source", "", "The source of the events, identifying the run. Defaults to a unique source.")
+	eventType      = flag.String("type", loadgen.EventType, "The type of the events.")
+	payloadSize    = flag.Int("payload-size", 100, "The minimum size of the event data, in bytes.")
+	maxInFlight    = flag.Int("max-in-flight", 1000, "The maximum number of events sent concurrently.")
+	port           = flag.Int("port", 8080, "The port of the verifier sink.")
+	delay          = flag.Duration("delay", 30*time.Second, "The time to wait for the Trigger or PullSubscription to be ready before sending events.")
+	drain          = flag.Duration("drain", time.Minute, "The time to wait for the last events to be received after sending them.")
+	maxLossRate    = flag.Float64("max-loss-rate", 0, "The fraction of events that may be lost before the run fails.")
+	metricsBackend = flag.String("metrics-backend", "prometheus", "The metrics backend, prometheus or stackdriver.")
+
+	manifest       = flag.Bool("manifest", false, "Print the resources running the load generator instead of running it.")
+	name           = flag.String("name", "loadgen", "The name of the resources, with -manifest.")
+	namespace      = flag.String("namespace", "default", "The namespace of the resources, with -manifest.")
+	image          = flag.String("image", "", "The load generator image, with -manifest.")
+	serviceAccount = flag.String("service-account", "", "The Kubernetes service account of the resources, with -manifest.")
+	broker         = flag.String("broker", "", "The name of the Broker to send events to, with -manifest.")
+	topic          = flag.String("topic", "", "The Pub/Sub topic ID of the Topic to send events to, with -manifest.")
+	project        = flag.String("project", "", "The project of the PullSubscription receiving the events sent to the Topic, with -manifest.")
+)
+
+func main() {
+	flag.Parse()
+	if *target == "" {
+		log.Fatal("-target is required")
+	}
+	p, err := loadgen.ParseProfile(*profile)
+	if err != nil {
+		log.Fatalf("Invalid -profile: %v", err)
+	}
+	if *manifest {
+		if err := printManifest(); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	logger, _ := logging.NewLogger("", "info")
+	defer logger.Sync()
+	ctx := logging.WithLogger(signals.NewContext(), logger)
+
+	if err := metrics.UpdateExporter(metrics.ExporterOptions{
+		Domain:    metricsDomain,
+		Component: component,
+		ConfigMap: map[string]string{metrics.BackendDestinationKey: *metricsBackend},
+	}, logger); err != nil {
+		logger.Fatalw("Failed to set up the metrics exporter", zap.Error(err))
+	}
+	defer metrics.FlushExporter()
+	reporter, err := loadgen.NewStatsReporter()
+	if err != nil {
+		logger.Fatalw("Failed to create the stats reporter", zap.Error(err))
+	}
+
+	verifier := loadgen.NewVerifier(reporter)
+	server := &http.Server{Addr: ":" + strconv.Itoa(*port), Handler: verifier}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatalw("Failed to run the verifier sink", zap.Error(err))
+		}
+	}()
+	defer server.Shutdown(context.Background())
+
+	if *source == "" {
+		*source = fmt.Sprintf("loadgen/%d", time.Now().UnixNano())
+	}
+	sender := &loadgen.Sender{
+		Client:      http.DefaultClient,
+		Target:      *target,
+		Profile:     p,
+		Source:      *source,
+		Type:        *eventType,
+		PayloadSize: *payloadSize,
+		MaxInFlight: *maxInFlight,
+		Verifier:    verifier,
+		Reporter:    reporter,
+	}
+	logger.Infow("Starting the load run", zap.String("target", *target), zap.String("profile", *profile), zap.String("source", *source))
+	if !sleep(ctx, *delay) {
+		return
+	}
+	sender.Run(ctx)
+	sleep(ctx, *drain)
+
+	report := verifier.Report(ctx)
+	b, err := json.Marshal(report)
+	if err != nil {
+		logger.Fatalw("Failed to marshal the report", zap.Error(err))
+	}
+	logger.Infow("Finished the load run", zap.String("report", string(b)))
+	if err := ioutil.WriteFile("/dev/termination-log", b, 0644); err != nil {
+		logger.Debugw("Failed to write the termination message", zap.Error(err))
+	}
+	if report.LossRate() > *maxLossRate {
+		metrics.FlushExporter()
+		logger.Fatalw("Lost too many events", zap.Float64("lossRate", report.LossRate()), zap.Float64("maxLossRate", *maxLossRate))
+	}
+}
+
+// sleep waits for d, returning false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func printManifest() error {
+	if (*broker == "") == (*topic == "") {
+		return fmt.Errorf("exactly one of -broker and -topic must be set with -manifest")
+	}
+	args := &resources.LoadgenArgs{
+		Namespace:          *namespace,
+		Name:               *name,
+		Image:              *image,
+		ServiceAccountName: *serviceAccount,
+		Target:             *target,
+		Broker:             *broker,
+		Topic:              *topic,
+		Project:            *project,
+		Profile:            *profile,
+		PayloadSize:        *payloadSize,
+		MaxInFlight:        *maxInFlight,
+	}
+	objs := []runtime.Object{resources.MakeService(args)}
+	if *broker != "" {
+		objs = append(objs, resources.MakeTrigger(args))
+	} else {
+		objs = append(objs, resources.MakePullSubscription(args))
+	}
+	objs = append(objs, resources.MakeJob(args))
+	for _, obj := range objs {
+		b, err := yaml.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("---\n%s", b)
+	}
+	return nil
+}
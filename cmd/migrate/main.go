@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command migrate moves CloudPubSubSources to the Broker/Trigger topology.
+// For each source, it creates a Broker if needed and a Trigger delivering the
+// events of the source to its sink, then switches the sink of the source to
+// the Broker once the Trigger is ready:
+//
+//	migrate -namespace prod -dry-run   # print the Brokers and Triggers to create
+//	migrate -namespace prod -source orders -broker default
+//
+// Sources controlled by another object, e.g. a SourceSet, are left to it.
+package main
+
+import (
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/injection/sharedmain"
+	"sigs.k8s.io/yaml"
+
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+	"github.com/google/knative-gcp/pkg/migration"
+)
+
+var (
+	masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	namespace  = flag.String("namespace", "", "Namespace of the CloudPubSubSources to migrate.")
+	source     = flag.String("source", "", "Name of the CloudPubSubSource to migrate. All the sources of the namespace are migrated if empty.")
+	broker     = flag.String("broker", "default", "Name of the Broker the sources send their events to. It is created if it doesn't exist.")
+	dryRun     = flag.Bool("dry-run", false, "Print the migrations instead of performing them.")
+	timeout    = flag.Duration("timeout", 5*time.Minute, "How long each Broker, Trigger and source may take to become ready.")
+)
+
+func main() {
+	flag.Parse()
+	if *namespace == "" {
+		log.Fatal("Missing -namespace")
+	}
+
+	cfg, err := sharedmain.GetConfig(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatalf("Error building kubeconfig: %v", err)
+	}
+	client := clientset.NewForConfigOrDie(cfg)
+
+	var migrations []*migration.Migration
+	for _, src := range sources(client) {
+		m, err := migration.Plan(src, *broker)
+		if errors.Is(err, migration.ErrMigrated) {
+			log.Printf("Skipping migrated CloudPubSubSource %s/%s", src.Namespace, src.Name)
+			continue
+		}
+		if err != nil {
+			log.Fatalf("Failed to plan the migration: %v", err)
+		}
+		migrations = append(migrations, m)
+	}
+
+	if *dryRun {
+		b, err := yaml.Marshal(migrations)
+		if err != nil {
+			log.Fatalf("Failed to marshal: %v", err)
+		}
+		if _, err := os.Stdout.Write(b); err != nil {
+			log.Fatalf("Failed to write: %v", err)
+		}
+		return
+	}
+
+	migrator := &migration.Migrator{
+		Client:   client,
+		Interval: 2 * time.Second,
+		Timeout:  *timeout,
+		Logf:     log.Printf,
+	}
+	for _, m := range migrations {
+		if err := migrator.Cutover(m); err != nil {
+			log.Fatalf("Failed to migrate CloudPubSubSource %s/%s: %v", m.Source.Namespace, m.Source.Name, err)
+		}
+		log.Printf("Migrated CloudPubSubSource %s/%s", m.Source.Namespace, m.Source.Name)
+	}
+}
+
+// sources returns the CloudPubSubSources to migrate.
+func sources(client clientset.Interface) []*eventsv1beta1.CloudPubSubSource {
+	sources := client.EventsV1beta1().CloudPubSubSources(*namespace)
+	if *source != "" {
+		src, err := sources.Get(*source, metav1.GetOptions{})
+		if err != nil {
+			log.Fatalf("Failed to get CloudPubSubSource %s/%s: %v", *namespace, *source, err)
+		}
+		return []*eventsv1beta1.CloudPubSubSource{src}
+	}
+	list, err := sources.List(metav1.ListOptions{})
+	if err != nil {
+		log.Fatalf("Failed to list CloudPubSubSources: %v", err)
+	}
+	var res []*eventsv1beta1.CloudPubSubSource
+	for i := range list.Items {
+		if metav1.GetControllerOf(&list.Items[i]) == nil {
+			res = append(res, &list.Items[i])
+		}
+	}
+	return res
+}
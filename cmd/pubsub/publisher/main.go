@@ -17,7 +17,6 @@ limitations under the License.
 package main
 
 import (
-	"context"
 	"flag"
 	"log"
 
@@ -26,6 +25,9 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"knative.dev/eventing/pkg/tracing"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/signals"
 
 	"github.com/google/knative-gcp/pkg/pubsub/publisher"
 	tracingconfig "github.com/google/knative-gcp/pkg/tracing"
@@ -44,12 +46,27 @@ type envConfig struct {
 	// original config is stored in a ConfigMap inside the controller's namespace. Its value is
 	// copied here as a JSON string.
 	TracingConfigJson string `envconfig:"K_TRACING_CONFIG" required:"true"`
+
+	// MetricsConfigJson is a JSON string of metrics.ExporterOptions, copied
+	// from the observability ConfigMap like the tracing config.
+	MetricsConfigJson string `envconfig:"K_METRICS_CONFIG"`
+
+	// DeadLetterTopic is the topic events are published to when they can't
+	// be published to Topic. Optional.
+	DeadLetterTopic string `envconfig:"PUBSUB_DEAD_LETTER_TOPIC_ID"`
+
+	// BufferSize is the maximum number of events buffered locally when they
+	// can be published to neither topic. Zero disables buffering.
+	BufferSize int `envconfig:"BUFFER_SIZE" default:"0"`
+
+	// BufferDir is the directory of the buffered events.
+	BufferDir string `envconfig:"BUFFER_DIR" default:"/tmp/publisher-buffer"`
 }
 
 func main() {
 	flag.Parse()
 
-	ctx := context.Background()
+	ctx := signals.NewContext()
 	logCfg := zap.NewProductionConfig() // TODO: to replace with a dynamically updating logger.
 	logCfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	logger, err := logCfg.Build()
@@ -80,13 +97,26 @@ func main() {
 		logger.Error("Failed to setup tracing", zap.Error(err), zap.Any("tracingConfig", tracingConfig))
 	}
 
+	if env.MetricsConfigJson != "" {
+		metricsConfig, err := metrics.JsonToMetricsOptions(env.MetricsConfigJson)
+		if err != nil {
+			logger.Error("Failed to process metrics options", zap.Error(err))
+		} else if err := metrics.UpdateExporter(*metricsConfig, logger.Sugar()); err != nil {
+			logger.Error("Failed to create the metrics exporter", zap.Error(err))
+		}
+		defer metrics.FlushExporter()
+	}
+
 	startable := &publisher.Publisher{
-		ProjectID: env.Project,
-		TopicID:   env.Topic,
+		ProjectID:         env.Project,
+		TopicID:           env.Topic,
+		DeadLetterTopicID: env.DeadLetterTopic,
+		BufferDir:         env.BufferDir,
+		BufferSize:        env.BufferSize,
 	}
 
 	logger.Info("Starting Pub/Sub Publisher.", zap.Any("publisher", startable))
-	if err := startable.Start(ctx); err != nil {
+	if err := startable.Start(logging.WithLogger(ctx, logger.Sugar())); err != nil {
 		logger.Fatal("failed to start publisher: ", zap.Error(err))
 	}
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"knative.dev/eventing/pkg/tracing"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	tracingconfig "github.com/google/knative-gcp/pkg/tracing"
+	"github.com/google/knative-gcp/pkg/utils/platform"
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
+	"knative.dev/pkg/signals"
+)
+
+const (
+	component = "PullSubscription::ReceiveAdapterAgent"
+)
+
+type envConfig struct {
+	// AgentDir is the directory of the PullSubscriptions served by the
+	// agent, i.e. the mounted agent ConfigMap.
+	AgentDir string `envconfig:"AGENT_DIR" default:"/var/run/cloud-run-events/pullsubscription-agent"`
+
+	// Project is the project of the PullSubscriptions that don't set one.
+	// Defaults to the project of the node.
+	Project string `envconfig:"PROJECT_ID"`
+
+	// The logging, metrics and tracing configs are JSON strings like those
+	// of the receive adapter. They are shared by all the PullSubscriptions
+	// of the agent and optional.
+	LoggingConfigJson string `envconfig:"K_LOGGING_CONFIG"`
+	MetricsConfigJson string `envconfig:"K_METRICS_CONFIG"`
+	TracingConfigJson string `envconfig:"K_TRACING_CONFIG"`
+}
+
+func main() {
+	flag.Parse()
+
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		panic(fmt.Sprintf("Failed to process env var: %s", err))
+	}
+
+	loggingConfig, err := logging.NewConfigFromMap(map[string]string{})
+	if err != nil {
+		// If this fails, there is no recovering.
+		panic(err)
+	}
+	if env.LoggingConfigJson != "" {
+		if cfg, err := logging.JsonToLoggingConfig(env.LoggingConfigJson); err != nil {
+			fmt.Printf("[ERROR] failed to process logging config: %s", err.Error())
+		} else {
+			loggingConfig = cfg
+		}
+	}
+
+	sl, _ := logging.NewLoggerFromConfig(loggingConfig, component)
+	logger := sl.Desugar()
+	defer flush(logger)
+	ctx := logging.WithLogger(signals.NewContext(), logger.Sugar())
+
+	// The agent serves many PullSubscriptions, so size the runtime after
+	// the container rather than the node.
+	tuning := platform.Detect()
+	logger.Info("Tuned for the container",
+		zap.String("arch", tuning.Arch),
+		zap.Int("gomaxprocs", tuning.SetMaxProcs()),
+		zap.Int64("softMemoryLimit", tuning.SetMemoryLimit()))
+
+	if env.MetricsConfigJson != "" {
+		metricsConfig, err := metrics.JsonToMetricsOptions(env.MetricsConfigJson)
+		if err != nil {
+			logger.Error("Failed to process metrics options", zap.Error(err))
+		} else if err := metrics.UpdateExporter(*metricsConfig, logger.Sugar()); err != nil {
+			logger.Fatal("Failed to create the metrics exporter", zap.Error(err))
+		}
+	}
+
+	if env.TracingConfigJson != "" {
+		tracingConfig, err := tracingconfig.JSONToConfig(env.TracingConfigJson)
+		if err != nil {
+			logger.Error("Failed to process tracing options", zap.Error(err))
+		}
+		if err := tracing.SetupStaticPublishing(logger.Sugar(), "", tracingConfig); err != nil {
+			logger.Error("Failed to setup tracing", zap.Error(err), zap.Any("tracingConfig", tracingConfig))
+		}
+	}
+
+	if env.Project == "" {
+		project, err := metadata.ProjectID()
+		if err != nil {
+			logger.Fatal("failed to find project id. ", zap.Error(err))
+		}
+		env.Project = project
+	}
+
+	agent := adapter.Agent{
+		Dir:     env.AgentDir,
+		Project: env.Project,
+	}
+	logger.Info("Starting Pub/Sub Receive Adapter Agent.", zap.String("dir", env.AgentDir))
+	if err := agent.Start(ctx); err != nil {
+		logger.Fatal("failed to start agent: ", zap.Error(err))
+	}
+}
+
+func flush(logger *zap.Logger) {
+	_ = logger.Sync()
+	metrics.FlushExporter()
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	"github.com/google/knative-gcp/pkg/apis/events"
+	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	auditlogsresources "github.com/google/knative-gcp/pkg/reconciler/events/auditlogs/resources"
+	monitoringresources "github.com/google/knative-gcp/pkg/reconciler/events/monitoring/resources"
+	schedulerresources "github.com/google/knative-gcp/pkg/reconciler/events/scheduler/resources"
+	storageresources "github.com/google/knative-gcp/pkg/reconciler/events/storage/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/dryrun"
+)
+
+// dryRunSources mirror the children created by the source reconcilers in
+// pkg/reconciler/events.
+var dryRunSources = map[string]dryrun.Source{
+	"CloudStorageSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudStorageSource{} },
+		Old: func() apis.Convertible { return &eventsv1alpha1.CloudStorageSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return storageresources.GenerateTopicName(s.(*eventsv1beta1.CloudStorageSource))
+		},
+		CreatesTopic:       true,
+		ReceiveAdapterName: "cloudstoragesource.events.cloud.google.com",
+		ResourceGroup:      events.CloudStorageSourcesResource.String(),
+	},
+	"CloudSchedulerSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudSchedulerSource{} },
+		Old: func() apis.Convertible { return &eventsv1alpha1.CloudSchedulerSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return schedulerresources.GenerateTopicName(s.(*eventsv1beta1.CloudSchedulerSource))
+		},
+		CreatesTopic:       true,
+		ReceiveAdapterName: "cloudschedulersource.events.cloud.google.com",
+		ResourceGroup:      events.CloudSchedulerSourcesResource.String(),
+	},
+	"CloudAuditLogsSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudAuditLogsSource{} },
+		Old: func() apis.Convertible { return &eventsv1alpha1.CloudAuditLogsSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return auditlogsresources.GenerateTopicName(s.(*eventsv1beta1.CloudAuditLogsSource))
+		},
+		CreatesTopic:       true,
+		AdapterType:        converters.CloudAuditLogsConverter,
+		ReceiveAdapterName: "cloudauditlogssource.events.cloud.google.com",
+		ResourceGroup:      events.CloudAuditLogsSourcesResource.String(),
+	},
+	"CloudMonitoringAlertSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudMonitoringAlertSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return monitoringresources.GenerateTopicName(s.(*eventsv1beta1.CloudMonitoringAlertSource))
+		},
+		CreatesTopic:       true,
+		AdapterType:        converters.CloudMonitoringAlertConverter,
+		ReceiveAdapterName: "cloudmonitoringalertsource.events.cloud.google.com",
+		ResourceGroup:      events.CloudMonitoringAlertSourcesResource.String(),
+	},
+	"CloudPubSubSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudPubSubSource{} },
+		Old: func() apis.Convertible { return &eventsv1alpha1.CloudPubSubSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return s.(*eventsv1beta1.CloudPubSubSource).Spec.Topic
+		},
+		PushCompatible:     true,
+		ReceiveAdapterName: "cloudpubsubsource.events.cloud.google.com",
+		ResourceGroup:      events.CloudPubSubSourcesResource.String(),
+	},
+	"CloudBuildSource": {
+		New:                func() duck.PubSubable { return &eventsv1beta1.CloudBuildSource{} },
+		Old:                func() apis.Convertible { return &eventsv1alpha1.CloudBuildSource{} },
+		Topic:              func(duck.PubSubable) string { return events.CloudBuildTopic },
+		AdapterType:        converters.CloudBuildConverter,
+		ReceiveAdapterName: "cloudbuildsource.events.cloud.google.com",
+		ResourceGroup:      events.CloudBuildSourcesResource.String(),
+	},
+	"CloudBillingBudgetSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.CloudBillingBudgetSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return s.(*eventsv1beta1.CloudBillingBudgetSource).Spec.Topic
+		},
+		AdapterType:        converters.CloudBillingBudgetConverter,
+		ReceiveAdapterName: "cloudbillingbudgetsource.events.cloud.google.com",
+		ResourceGroup:      events.CloudBillingBudgetSourcesResource.String(),
+	},
+	"SecretManagerRotationSource": {
+		New: func() duck.PubSubable { return &eventsv1beta1.SecretManagerRotationSource{} },
+		Topic: func(s duck.PubSubable) string {
+			return s.(*eventsv1beta1.SecretManagerRotationSource).Spec.Topic
+		},
+		AdapterType:        converters.SecretManagerRotationConverter,
+		ReceiveAdapterName: "secretmanagerrotationsource.events.cloud.google.com",
+		ResourceGroup:      events.SecretManagerRotationSourcesResource.String(),
+	},
+	"CloudArtifactRegistrySource": {
+		New:                func() duck.PubSubable { return &eventsv1beta1.CloudArtifactRegistrySource{} },
+		Topic:              func(duck.PubSubable) string { return events.CloudArtifactRegistryTopic },
+		AdapterType:        converters.CloudArtifactRegistryConverter,
+		ReceiveAdapterName: "cloudartifactregistrysource.events.cloud.google.com",
+		ResourceGroup:      events.CloudArtifactRegistrySourcesResource.String(),
+	},
+}
+
+// dryRunCallbacks returns the validation callbacks dry-running the children
+// of the sources, for each version a source is served in.
+func dryRunCallbacks(d *dryrun.DryRunner) map[schema.GroupVersionKind]validation.Callback {
+	callbacks := make(map[schema.GroupVersionKind]validation.Callback)
+	if d == nil {
+		return callbacks
+	}
+	for kind, src := range dryRunSources {
+		callback := d.Callback(src)
+		callbacks[eventsv1beta1.SchemeGroupVersion.WithKind(kind)] = callback
+		if src.Old != nil {
+			callbacks[eventsv1alpha1.SchemeGroupVersion.WithKind(kind)] = callback
+		}
+	}
+	return callbacks
+}
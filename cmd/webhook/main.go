@@ -21,6 +21,7 @@ import (
 	"log"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/apis/configs/topiccheck"
 	configvalidation "github.com/google/knative-gcp/pkg/apis/configs/validation"
 	"github.com/google/knative-gcp/pkg/apis/events"
 	eventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
@@ -31,18 +32,23 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/messaging"
 	messagingv1alpha1 "github.com/google/knative-gcp/pkg/apis/messaging/v1alpha1"
 	messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	operatorv1alpha1 "github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
+	namespaceinformer "github.com/google/knative-gcp/pkg/client/injection/kube/informers/core/v1/namespace"
+	"github.com/google/knative-gcp/pkg/gclient/pubsub"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/dryrun"
+	"github.com/google/knative-gcp/pkg/webhook/certificates"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/eventing/pkg/logconfig"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
+	"knative.dev/pkg/injection/clients/dynamicclient"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/leaderelection"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/signals"
 	tracingconfig "knative.dev/pkg/tracing/config"
 	"knative.dev/pkg/webhook"
-	"knative.dev/pkg/webhook/certificates"
 	"knative.dev/pkg/webhook/configmaps"
 	"knative.dev/pkg/webhook/resourcesemantics"
 	"knative.dev/pkg/webhook/resourcesemantics/conversion"
@@ -53,6 +59,10 @@ import (
 var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	// For group messaging.cloud.google.com.
 	messagingv1alpha1.SchemeGroupVersion.WithKind("Channel"): &messagingv1alpha1.Channel{},
+	// Sequence and Parallel only exist in v1beta1, so they need no
+	// conversion.
+	messagingv1beta1.SchemeGroupVersion.WithKind("Sequence"): &messagingv1beta1.Sequence{},
+	messagingv1beta1.SchemeGroupVersion.WithKind("Parallel"): &messagingv1beta1.Parallel{},
 
 	// For group events.cloud.google.com.
 	eventsv1alpha1.SchemeGroupVersion.WithKind("CloudStorageSource"):   &eventsv1alpha1.CloudStorageSource{},
@@ -60,10 +70,28 @@ var types = map[schema.GroupVersionKind]resourcesemantics.GenericCRD{
 	eventsv1alpha1.SchemeGroupVersion.WithKind("CloudPubSubSource"):    &eventsv1alpha1.CloudPubSubSource{},
 	eventsv1alpha1.SchemeGroupVersion.WithKind("CloudAuditLogsSource"): &eventsv1alpha1.CloudAuditLogsSource{},
 	eventsv1alpha1.SchemeGroupVersion.WithKind("CloudBuildSource"):     &eventsv1alpha1.CloudBuildSource{},
+	eventsv1alpha1.SchemeGroupVersion.WithKind("SourceSet"):            &eventsv1alpha1.SourceSet{},
+	// CloudMonitoringAlertSource, CloudBillingBudgetSource,
+	// SecretManagerRotationSource, CloudArtifactRegistrySource and
+	// WebhookSource only exist in v1beta1, so they need no conversion.
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudMonitoringAlertSource"):  &eventsv1beta1.CloudMonitoringAlertSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudBillingBudgetSource"):    &eventsv1beta1.CloudBillingBudgetSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("SecretManagerRotationSource"): &eventsv1beta1.SecretManagerRotationSource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("CloudArtifactRegistrySource"): &eventsv1beta1.CloudArtifactRegistrySource{},
+	eventsv1beta1.SchemeGroupVersion.WithKind("WebhookSource"):               &eventsv1beta1.WebhookSource{},
 
 	// For group internal.events.cloud.google.com.
 	inteventsv1alpha1.SchemeGroupVersion.WithKind("PullSubscription"): &inteventsv1alpha1.PullSubscription{},
 	inteventsv1alpha1.SchemeGroupVersion.WithKind("Topic"):            &inteventsv1alpha1.Topic{},
+	inteventsv1alpha1.SchemeGroupVersion.WithKind("BrokerCell"):       &inteventsv1alpha1.BrokerCell{},
+	// BrokerCell is stored in v1beta1, the version external operators
+	// create it in, so both versions are defaulted and validated.
+	inteventsv1beta1.SchemeGroupVersion.WithKind("BrokerCell"): &inteventsv1beta1.BrokerCell{},
+	// EventSchema only exists in v1alpha1, so it needs no conversion.
+	inteventsv1alpha1.SchemeGroupVersion.WithKind("EventSchema"): &inteventsv1alpha1.EventSchema{},
+
+	// For group operator.events.cloud.google.com.
+	operatorv1alpha1.SchemeGroupVersion.WithKind("KnativeGCP"): &operatorv1alpha1.KnativeGCP{},
 }
 
 type defaultingAdmissionController func(context.Context, configmap.Watcher) *controller.Impl
@@ -75,9 +103,12 @@ func newDefaultingAdmissionConstructor(gcpas *gcpauth.StoreSingleton) defaulting
 }
 
 func newDefaultingAdmissionController(ctx context.Context, cmw configmap.Watcher, gcpas *gcpauth.Store) *controller.Impl {
-	// Decorate contexts with the current state of the config.
+	namespaceLister := namespaceinformer.Get(ctx).Lister()
+
+	// Decorate contexts with the current state of the config, and with the
+	// namespaces whose annotations set per namespace defaults.
 	ctxFunc := func(ctx context.Context) context.Context {
-		return gcpas.ToContext(ctx)
+		return gcpauth.WithNamespaceLister(gcpas.ToContext(ctx), namespaceLister)
 	}
 
 	return defaulting.NewAdmissionController(ctx,
@@ -108,9 +139,13 @@ func newValidationConstructor(gcpas *gcpauth.StoreSingleton) validationControlle
 }
 
 func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher, gcpas *gcpauth.Store) *controller.Impl {
+	topicCheckMode := topiccheck.ModeFromEnv()
+	topicVerifier := topiccheck.NewPubSubVerifier(pubsub.NewClient)
+	childDryRunner := dryrun.NewDryRunner(dynamicclient.Get(ctx), dryrun.ModeFromEnv())
+
 	// A function that infuses the context passed to Validate/SetDefaults with custom metadata.
 	ctxFunc := func(ctx context.Context) context.Context {
-		return gcpas.ToContext(ctx)
+		return topiccheck.WithVerifier(gcpas.ToContext(ctx), topicVerifier, topicCheckMode)
 	}
 
 	return validation.NewAdmissionController(ctx,
@@ -128,6 +163,9 @@ func newValidationAdmissionController(ctx context.Context, cmw configmap.Watcher
 
 		// Whether to disallow unknown fields.
 		true,
+
+		// Dry-run creates the children of new sources.
+		dryRunCallbacks(childDryRunner),
 	)
 }
 
@@ -230,6 +268,14 @@ func newConversionController(ctx context.Context, _ configmap.Watcher, gcpas *gc
 					inteventsv1beta1_:  &inteventsv1beta1.PullSubscription{},
 				},
 			},
+			inteventsv1alpha1.Kind("BrokerCell"): {
+				DefinitionName: intevents.BrokerCellsResource.String(),
+				HubVersion:     inteventsv1alpha1_,
+				Zygotes: map[string]conversion.ConvertibleObject{
+					inteventsv1alpha1_: &inteventsv1alpha1.BrokerCell{},
+					inteventsv1beta1_:  &inteventsv1beta1.BrokerCell{},
+				},
+			},
 			inteventsv1alpha1.Kind("Topic"): {
 				DefinitionName: intevents.TopicsResource.String(),
 				HubVersion:     inteventsv1alpha1_,
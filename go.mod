@@ -3,34 +3,41 @@ module github.com/google/knative-gcp
 go 1.14
 
 require (
-	cloud.google.com/go v0.58.0
+	cloud.google.com/go v0.75.0
 	cloud.google.com/go/logging v1.0.1-0.20200331222814-69e77e66e597
-	cloud.google.com/go/pubsub v1.3.2-0.20200506222144-2c46308f8465
-	cloud.google.com/go/storage v1.8.0
+	cloud.google.com/go/pubsub v1.9.1
+	cloud.google.com/go/pubsublite v0.7.0
+	cloud.google.com/go/storage v1.10.0
 	github.com/cloudevents/sdk-go v1.2.0
 	github.com/cloudevents/sdk-go/protocol/pubsub/v2 v2.0.1-0.20200602143929-d07dc0510d45
 	github.com/cloudevents/sdk-go/v2 v2.0.1-0.20200608152019-2ab697c8fc0b
 	github.com/fsnotify/fsnotify v1.4.9
 	github.com/gogo/protobuf v1.3.1
-	github.com/golang/protobuf v1.4.2
-	github.com/google/go-cmp v0.4.1
-	github.com/google/uuid v1.1.1
+	github.com/golang/protobuf v1.4.3
+	github.com/google/cel-go v0.5.1
+	github.com/google/go-cmp v0.5.4
+	github.com/google/uuid v1.2.0
 	github.com/google/wire v0.4.0
 	github.com/googleapis/gax-go/v2 v2.0.5
 	github.com/kelseyhightower/envconfig v1.4.0
-	go.opencensus.io v0.22.4
+	github.com/klauspost/compress v1.11.13
+	github.com/xeipuuv/gojsonschema v1.1.0
+	go.opencensus.io v0.22.5
 	go.opentelemetry.io/otel v0.3.0 // indirect
 	go.uber.org/multierr v1.5.0
 	go.uber.org/zap v1.15.0
-	golang.org/x/crypto v0.0.0-20200317142112-1b76d66859c6 // indirect
-	golang.org/x/sync v0.0.0-20200317015054-43a5402ce75a
-	google.golang.org/api v0.26.0
-	google.golang.org/genproto v0.0.0-20200608115520-7c474a2e3482
-	google.golang.org/grpc v1.29.1
-	google.golang.org/protobuf v1.24.0
+	golang.org/x/net v0.0.0-20201224014010-6772e930b67b
+	golang.org/x/oauth2 v0.0.0-20210113160501-8b1d76fa0423
+	golang.org/x/sync v0.0.0-20201207232520-09787c993a3a
+	golang.org/x/time v0.0.0-20201208040808-7e3f01d25324
+	google.golang.org/api v0.40.0
+	google.golang.org/genproto v0.0.0-20210217203555-6b1387fcb8a8
+	google.golang.org/grpc v1.35.0
+	google.golang.org/protobuf v1.25.0
 	k8s.io/api v0.18.1
 	k8s.io/apimachinery v0.18.1
 	k8s.io/client-go v11.0.1-0.20190805182717-6502b5e7b1b5+incompatible
+	k8s.io/utils v0.0.0-20200124190032-861946025e34
 	knative.dev/eventing v0.15.1-0.20200617151224-2025007875e9
 	knative.dev/pkg v0.0.0-20200617175025-6826f2137c31
 	knative.dev/serving v0.15.1-0.20200617164325-1b08e8665241
@@ -61,14 +61,14 @@ chmod +x "${CODEGEN_PKG}"/generate-groups.sh
 #                  instead of the $GOPATH directly. For normal projects this can be dropped.
 "${CODEGEN_PKG}"/generate-groups.sh "deepcopy,client,informer,lister" \
   github.com/google/knative-gcp/pkg/client github.com/google/knative-gcp/pkg/apis \
-  "messaging:v1alpha1 messaging:v1beta1 events:v1alpha1 events:v1beta1 broker:v1beta1 intevents:v1alpha1 intevents:v1beta1" \
+  "messaging:v1alpha1 messaging:v1beta1 events:v1alpha1 events:v1beta1 broker:v1beta1 intevents:v1alpha1 intevents:v1beta1 operator:v1alpha1" \
   --go-header-file "${REPO_ROOT_DIR}"/hack/boilerplate/boilerplate.go.txt
 
 # Knative Injection
 chmod +x "${KNATIVE_CODEGEN_PKG}"/hack/generate-knative.sh
 "${KNATIVE_CODEGEN_PKG}"/hack/generate-knative.sh "injection" \
   github.com/google/knative-gcp/pkg/client github.com/google/knative-gcp/pkg/apis \
-  "messaging:v1alpha1 messaging:v1beta1 events:v1alpha1 events:v1beta1 duck:v1alpha1 duck:v1beta1 broker:v1beta1 intevents:v1alpha1 intevents:v1beta1" \
+  "messaging:v1alpha1 messaging:v1beta1 events:v1alpha1 events:v1beta1 duck:v1alpha1 duck:v1beta1 broker:v1beta1 intevents:v1alpha1 intevents:v1beta1 operator:v1alpha1" \
   --go-header-file "${REPO_ROOT_DIR}"/hack/boilerplate/boilerplate.go.txt
 
 # Deep copy configs.
@@ -84,7 +84,7 @@ EXTERNAL_INFORMER_PKG="k8s.io/client-go/informers" \
 "${KNATIVE_CODEGEN_PKG}"/hack/generate-knative.sh "injection" \
   k8s.io/client-go \
   k8s.io/api \
-  "autoscaling:v2beta2" \
+  "autoscaling:v2beta2 core:v1" \
   --go-header-file "${REPO_ROOT_DIR}"/hack/boilerplate/boilerplate.go.txt
 
 go install github.com/google/wire/cmd/wire
@@ -27,6 +27,7 @@ var brokerCondSet = apis.NewLivingConditionSet(
 	BrokerConditionTopic,
 	BrokerConditionSubscription,
 	BrokerConditionConfig,
+	BrokerConditionPublish,
 )
 
 const (
@@ -41,6 +42,10 @@ const (
 	// BrokerConditionConfig reports the status of reconstructing and updating the data entry
 	// for the Broker. This condition is specific to the Google Cloud Broker.
 	BrokerConditionConfig apis.ConditionType = "ConfigReady"
+	// BrokerConditionPublish reports whether the data plane is able to publish
+	// the events of the Broker to its PubSub topics. This condition is
+	// specific to the Google Cloud Broker.
+	BrokerConditionPublish apis.ConditionType = "PublishReady"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -121,3 +126,15 @@ func (bs *BrokerStatus) MarkConfigUnknown(reason, format string, args ...interfa
 func (bs *BrokerStatus) MarkConfigReady() {
 	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionConfig)
 }
+
+func (bs *BrokerStatus) MarkPublishFailed(reason, format string, args ...interface{}) {
+	brokerCondSet.Manage(bs).MarkFalse(BrokerConditionPublish, reason, format, args...)
+}
+
+func (bs *BrokerStatus) MarkPublishUnknown(reason, format string, args ...interface{}) {
+	brokerCondSet.Manage(bs).MarkUnknown(BrokerConditionPublish, reason, format, args...)
+}
+
+func (bs *BrokerStatus) MarkPublishReady() {
+	brokerCondSet.Manage(bs).MarkTrue(BrokerConditionPublish)
+}
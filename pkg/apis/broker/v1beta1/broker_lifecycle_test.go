@@ -158,6 +158,9 @@ func TestBrokerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   BrokerConditionConfig,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   BrokerConditionPublish,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.BrokerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -195,6 +198,9 @@ func TestBrokerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   BrokerConditionConfig,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   BrokerConditionPublish,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.BrokerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -232,6 +238,9 @@ func TestBrokerInitializeConditions(t *testing.T) {
 					}, {
 						Type:   BrokerConditionConfig,
 						Status: corev1.ConditionUnknown,
+					}, {
+						Type:   BrokerConditionPublish,
+						Status: corev1.ConditionUnknown,
 					}, {
 						Type:   eventingv1beta1.BrokerConditionReady,
 						Status: corev1.ConditionUnknown,
@@ -269,6 +278,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus  corev1.ConditionStatus
 		topicStatus         corev1.ConditionStatus
 		configStatus        corev1.ConditionStatus
+		publishStatus       corev1.ConditionStatus
 		wantConditionStatus corev1.ConditionStatus
 	}{{
 		name:                "all happy",
@@ -277,6 +287,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionTrue,
 	}, {
 		name:                "subscription sad",
@@ -285,6 +296,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionFalse,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "subscription unknown",
@@ -293,6 +305,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionUnknown,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionUnknown,
 	}, {
 		name:                "topic sad",
@@ -301,6 +314,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionFalse,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "topic unknown",
@@ -309,6 +323,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionUnknown,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionUnknown,
 	}, {
 		name:                "address missing",
@@ -317,6 +332,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "ingress false",
@@ -325,6 +341,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "config false",
@@ -333,6 +350,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionFalse,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "config unknown",
@@ -341,6 +359,25 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionUnknown,
+		publishStatus:       corev1.ConditionTrue,
+		wantConditionStatus: corev1.ConditionUnknown,
+	}, {
+		name:                "publish false",
+		addressStatus:       true,
+		brokerCellStatus:    corev1.ConditionTrue,
+		subscriptionStatus:  corev1.ConditionTrue,
+		topicStatus:         corev1.ConditionTrue,
+		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionFalse,
+		wantConditionStatus: corev1.ConditionFalse,
+	}, {
+		name:                "publish unknown",
+		addressStatus:       true,
+		brokerCellStatus:    corev1.ConditionTrue,
+		subscriptionStatus:  corev1.ConditionTrue,
+		topicStatus:         corev1.ConditionTrue,
+		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionUnknown,
 		wantConditionStatus: corev1.ConditionUnknown,
 	}, {
 		name:                "brokerCell false",
@@ -349,6 +386,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionFalse,
 	}, {
 		name:                "brokerCell unknown",
@@ -357,6 +395,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionTrue,
 		topicStatus:         corev1.ConditionTrue,
 		configStatus:        corev1.ConditionTrue,
+		publishStatus:       corev1.ConditionTrue,
 		wantConditionStatus: corev1.ConditionUnknown,
 	}, {
 		name:                "all sad",
@@ -365,6 +404,7 @@ func TestBrokerConditionStatus(t *testing.T) {
 		subscriptionStatus:  corev1.ConditionFalse,
 		topicStatus:         corev1.ConditionFalse,
 		configStatus:        corev1.ConditionFalse,
+		publishStatus:       corev1.ConditionFalse,
 		wantConditionStatus: corev1.ConditionFalse,
 	}}
 	for _, test := range tests {
@@ -403,6 +443,11 @@ func TestBrokerConditionStatus(t *testing.T) {
 			} else {
 				bs.MarkConfigUnknown("Unable to reconstruct/update config", "induced unknown")
 			}
+			if test.publishStatus == corev1.ConditionTrue {
+				bs.MarkPublishReady()
+			} else if test.publishStatus == corev1.ConditionFalse {
+				bs.MarkPublishFailed("PublishQuotaExceeded", "induced failure")
+			}
 			got := bs.GetTopLevelCondition().Status
 			if test.wantConditionStatus != got {
 				t.Errorf("unexpected readiness: want %v, got %v", test.wantConditionStatus, got)
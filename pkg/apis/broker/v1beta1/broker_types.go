@@ -29,6 +29,65 @@ const (
 	// BrokerClass is the annotation value to use when creating a
 	// Google Cloud Broker object.
 	BrokerClass = "googlecloud"
+
+	// LiteLocationAnnotation is the annotation key used to put the Broker
+	// decouple queue and the retry queues of its Triggers on Pub/Sub Lite
+	// rather than Cloud Pub/Sub. Its value is the zone of the Pub/Sub Lite
+	// topics and subscriptions, e.g. us-central1-a. It cannot be changed
+	// once the Broker is created.
+	LiteLocationAnnotation = "internal.events.cloud.google.com/lite-location"
+	// MessageOrderingAnnotation is the annotation key used to enable message
+	// ordering on the Broker decouple queue. Events published to the Broker
+	// are ordered by the value of their OrderingKeyExtension.
+	MessageOrderingAnnotation = "internal.events.cloud.google.com/message-ordering"
+	// OrderingKeyExtension is the CloudEvents extension attribute whose value
+	// is used as the Pub/Sub ordering key when message ordering is enabled.
+	OrderingKeyExtension = "orderingkey"
+
+	// EncryptionKeyAnnotation is the annotation key used to encrypt the data
+	// of the events published to the Broker decouple queue. Its value is the
+	// resource name of the Cloud KMS key wrapping the data keys, e.g.
+	// projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key.
+	EncryptionKeyAnnotation = "internal.events.cloud.google.com/encryption-key"
+
+	// PriorityQueueAnnotation is the annotation key used to give the Broker
+	// a second decouple queue for high priority events, pulled by the fanout
+	// with a higher concurrency, so that they are not delayed behind bulk
+	// traffic.
+	PriorityQueueAnnotation = "internal.events.cloud.google.com/priority-queue"
+	// PriorityExtension is the CloudEvents extension attribute marking
+	// events as high priority when set to PriorityHigh. The ingress publishes
+	// them to the priority queue of Brokers that have one.
+	PriorityExtension = "priority"
+	// PriorityHigh is the value of PriorityExtension of high priority events.
+	PriorityHigh = "high"
+
+	// EventPolicyAnnotation is the annotation key used to restrict the events
+	// the ingress accepts for the Broker. Its value is a JSON object of
+	// allowed and denied event types and sources, e.g.
+	// {"allowedTypes":["com.example.*"],"deniedSources":["//test"]}. Other
+	// events are rejected with 403 Forbidden.
+	EventPolicyAnnotation = "internal.events.cloud.google.com/event-policy"
+
+	// IngressAuthSecretAnnotation is the annotation key used to require a
+	// bearer token on the requests the ingress accepts for the Broker. Its
+	// value is the name of a Secret in the Broker's namespace whose "tokens"
+	// key lists the accepted tokens, one per line. Other requests are
+	// rejected with 401 Unauthorized. Only hashes of the tokens are stored
+	// in a Secret in the system namespace mounted in the ingress pods.
+	IngressAuthSecretAnnotation = "internal.events.cloud.google.com/ingress-auth-secret"
+
+	// EventEncodingAnnotation is the annotation key used to choose how the
+	// ingress writes the events published to the Broker decouple queues, as
+	// one of EventEncodingBinary, the default, or EventEncodingStructured.
+	EventEncodingAnnotation = "internal.events.cloud.google.com/event-encoding"
+	// EventEncodingBinary writes the event attributes as Pub/Sub message
+	// attributes and the event data as the message data.
+	EventEncodingBinary = "binary"
+	// EventEncodingStructured writes the whole event as JSON in the Pub/Sub
+	// message data, so that events are not limited by the number and size
+	// of message attributes.
+	EventEncodingStructured = "structured"
 )
 
 // +genclient
@@ -103,6 +162,23 @@ type BrokerList struct {
 	Items []Broker `json:"items"`
 }
 
+// EventPolicy is the set of event types and sources the ingress accepts for a
+// Broker, the value of its EventPolicyAnnotation. Entries ending with "*"
+// match the values starting with the rest of the entry.
+type EventPolicy struct {
+	// AllowedTypes are the accepted event types. All types are accepted if
+	// empty.
+	AllowedTypes []string `json:"allowedTypes,omitempty"`
+	// DeniedTypes are the rejected event types, even if they are allowed.
+	DeniedTypes []string `json:"deniedTypes,omitempty"`
+	// AllowedSources are the accepted event sources. All sources are
+	// accepted if empty.
+	AllowedSources []string `json:"allowedSources,omitempty"`
+	// DeniedSources are the rejected event sources, even if they are
+	// allowed.
+	DeniedSources []string `json:"deniedSources,omitempty"`
+}
+
 // GetGroupVersionKind returns GroupVersionKind for Brokers
 func (b *Broker) GetGroupVersionKind() schema.GroupVersionKind {
 	return SchemeGroupVersion.WithKind("Broker")
@@ -18,13 +18,28 @@ package v1beta1
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 
 	"knative.dev/pkg/apis"
 )
 
-// Validate verifies that the Broker is valid.
+// Validate verifies that the Broker is valid. The eventing webhook runs the
+// usual validations, only the Google Cloud Broker annotations are validated
+// here.
 func (b *Broker) Validate(ctx context.Context) *apis.FieldError {
-	// The Google Cloud Broker doesn't have any custom validations. The
-	// eventing webhook will run the usual validations.
-	return nil
+	var errs *apis.FieldError
+	if v, ok := b.Annotations[EventPolicyAnnotation]; ok {
+		var p EventPolicy
+		if err := json.Unmarshal([]byte(v), &p); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", EventPolicyAnnotation)))
+		}
+	}
+	if v, ok := b.Annotations[EventEncodingAnnotation]; ok {
+		if e := strings.TrimSpace(v); !strings.EqualFold(e, EventEncodingBinary) && !strings.EqualFold(e, EventEncodingStructured) {
+			errs = errs.Also(apis.ErrInvalidValue(v, fmt.Sprintf("metadata.annotations[%s]", EventEncodingAnnotation)))
+		}
+	}
+	return errs
 }
@@ -19,11 +19,63 @@ package v1beta1
 import (
 	"context"
 	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestBroker_Validate(t *testing.T) {
-	b := Broker{}
-	if err := b.Validate(context.TODO()); err != nil {
-		t.Errorf("expected nil, got %v", err)
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name: "no annotations",
+	}, {
+		name: "valid event policy",
+		annotations: map[string]string{
+			EventPolicyAnnotation: `{"allowedTypes":["com.example.*"],"deniedSources":["//test"]}`,
+		},
+	}, {
+		name: "event policy is not JSON",
+		annotations: map[string]string{
+			EventPolicyAnnotation: "com.example.*",
+		},
+		wantErr: true,
+	}, {
+		name: "event policy with a string instead of a list",
+		annotations: map[string]string{
+			EventPolicyAnnotation: `{"allowedTypes":"com.example.order"}`,
+		},
+		wantErr: true,
+	}, {
+		name: "binary encoding",
+		annotations: map[string]string{
+			EventEncodingAnnotation: EventEncodingBinary,
+		},
+	}, {
+		name: "structured encoding",
+		annotations: map[string]string{
+			EventEncodingAnnotation: " Structured ",
+		},
+	}, {
+		name: "unknown encoding",
+		annotations: map[string]string{
+			EventEncodingAnnotation: "json",
+		},
+		wantErr: true,
+	}, {
+		name: "empty encoding",
+		annotations: map[string]string{
+			EventEncodingAnnotation: "",
+		},
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if err := b.Validate(context.TODO()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
 	}
 }
@@ -35,6 +35,7 @@ func (t testHelper) ReadyBrokerStatus() *BrokerStatus {
 	bs.MarkTopicReady()
 	bs.MarkBrokerCellReady()
 	bs.MarkConfigReady()
+	bs.MarkPublishReady()
 	return bs
 }
 
@@ -17,6 +17,8 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
@@ -31,9 +33,18 @@ var triggerCondSet = apis.NewLivingConditionSet(
 	TriggerConditionSubscription,
 )
 
+// sloCondSet manages the DeliverySLOBurning condition independently of the
+// conditions that make up the readiness of a Trigger.
+var sloCondSet = apis.NewLivingConditionSet()
+
 const (
 	TriggerConditionTopic        apis.ConditionType = "TopicReady"
 	TriggerConditionSubscription apis.ConditionType = "SubscriptionReady"
+
+	// TriggerConditionDeliverySLO reports whether the Trigger burns the error
+	// budget of its DeliverySLOAnnotation too fast. It has a warning severity
+	// and does not affect the readiness of the Trigger.
+	TriggerConditionDeliverySLO apis.ConditionType = "DeliverySLOBurning"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -159,3 +170,29 @@ func (ts *TriggerStatus) PropagateDependencyStatus(ks *duckv1.KResource) {
 		ts.MarkDependencyUnknown("DependencyUnknown", "The status of Dependency is invalid: %v", kc.Status)
 	}
 }
+
+// MarkDeliverySLOBurning sets the DeliverySLOBurning condition to True.
+func (ts *TriggerStatus) MarkDeliverySLOBurning(reason, messageFormat string, messageA ...interface{}) {
+	ts.setDeliverySLO(corev1.ConditionTrue, reason, messageFormat, messageA...)
+}
+
+// MarkDeliverySLOWithinBudget sets the DeliverySLOBurning condition to False.
+func (ts *TriggerStatus) MarkDeliverySLOWithinBudget(reason, messageFormat string, messageA ...interface{}) {
+	ts.setDeliverySLO(corev1.ConditionFalse, reason, messageFormat, messageA...)
+}
+
+// MarkDeliverySLONotConfigured removes the DeliverySLOBurning condition.
+func (ts *TriggerStatus) MarkDeliverySLONotConfigured() {
+	// ClearCondition only fails for terminal conditions.
+	_ = sloCondSet.Manage(ts).ClearCondition(TriggerConditionDeliverySLO)
+}
+
+func (ts *TriggerStatus) setDeliverySLO(status corev1.ConditionStatus, reason, messageFormat string, messageA ...interface{}) {
+	sloCondSet.Manage(ts).SetCondition(apis.Condition{
+		Type:     TriggerConditionDeliverySLO,
+		Status:   status,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, messageA...),
+	})
+}
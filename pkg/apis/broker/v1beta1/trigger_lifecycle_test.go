@@ -428,3 +428,36 @@ func TestTriggerConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTriggerDeliverySLO(t *testing.T) {
+	ts := &TriggerStatus{}
+	ts.InitializeConditions()
+	ts.MarkBrokerFailed("BrokerFailed", "induced failure")
+
+	ts.MarkDeliverySLOBurning("Burning", "burn rate %v", 20)
+	want := &apis.Condition{
+		Type:     TriggerConditionDeliverySLO,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   "Burning",
+		Message:  "burn rate 20",
+	}
+	ignoreTime := cmpopts.IgnoreFields(apis.Condition{}, "LastTransitionTime")
+	if diff := cmp.Diff(want, ts.GetCondition(TriggerConditionDeliverySLO), ignoreTime); diff != "" {
+		t.Errorf("unexpected condition (-want, +got) = %v", diff)
+	}
+	// The condition does not affect the readiness of the Trigger.
+	if got := ts.GetTopLevelCondition(); got.Status != corev1.ConditionFalse || got.Reason != "BrokerFailed" {
+		t.Errorf("unexpected readiness: %+v", got)
+	}
+
+	ts.MarkDeliverySLOWithinBudget("WithinBudget", "within budget")
+	if got := ts.GetCondition(TriggerConditionDeliverySLO); got == nil || got.Status != corev1.ConditionFalse {
+		t.Errorf("unexpected condition: %+v", got)
+	}
+
+	ts.MarkDeliverySLONotConfigured()
+	if got := ts.GetCondition(TriggerConditionDeliverySLO); got != nil {
+		t.Errorf("unexpected condition: %+v", got)
+	}
+}
@@ -32,6 +32,61 @@ const (
 	// InjectionAnnotation is the annotation key used to enable knative eventing injection for a namespace and automatically create a default broker.
 	// This will be used when the client creates a trigger paired with default broker and the default broker doesn't exist in the namespace
 	InjectionAnnotation = "knative-eventing-injection"
+	// OrderedDeliveryAnnotation is the annotation key used to opt a Trigger into ordered delivery.
+	// Events with the same ordering key are delivered to the subscriber one at a time, in order.
+	// It only takes effect if the Broker has message ordering enabled via MessageOrderingAnnotation.
+	OrderedDeliveryAnnotation = "internal.events.cloud.google.com/ordered-delivery"
+	// DeduplicationWindowAnnotation is the annotation key used to opt a Trigger into event deduplication.
+	// Its value is the number of most recently delivered event IDs remembered per fanout replica; events
+	// whose source and ID are among them are not delivered again.
+	DeduplicationWindowAnnotation = "internal.events.cloud.google.com/deduplication-window"
+	// DirectDeliveryAnnotation is the annotation key used to opt a Trigger into direct delivery.
+	// If the subscriber is a ready Knative Service, events are sent straight to the private service of
+	// its latest ready revision, bypassing the activator, and fall back to the subscriber URI on failure.
+	DirectDeliveryAnnotation = "internal.events.cloud.google.com/direct-delivery"
+	// CEOverridesAnnotation is the annotation key used to set CloudEvents extensions on the events
+	// delivered to a Trigger's subscriber. Its value is a JSON object of extension names to values,
+	// e.g. {"region":"europe"}. The fanout sets them at delivery time, overriding the producer's values.
+	CEOverridesAnnotation = "internal.events.cloud.google.com/ce-overrides"
+	// TransformAnnotation is the annotation key used to transform the data of the events delivered to a
+	// Trigger's subscriber. Its value is a JSON object of output field names to CEL expressions over
+	// the event variable, which holds the event in CloudEvents JSON format, e.g.
+	// {"user":"event.data.user.id","kind":"event.type"}. The fanout replaces the event data with the
+	// resulting JSON object at delivery time.
+	TransformAnnotation = "internal.events.cloud.google.com/transform"
+	// SubscribersAnnotation is the annotation key used to deliver the events of a Trigger to additional
+	// subscribers besides spec.subscriber. Its value is a JSON array of destinations, e.g.
+	// [{"uri":"http://audit.example.com"},{"ref":{"apiVersion":"v1","kind":"Service","name":"archive"}}].
+	// Delivery only succeeds if it succeeds for every subscriber.
+	SubscribersAnnotation = "internal.events.cloud.google.com/subscribers"
+	// SubscribersDeliveryAnnotation is the annotation key used to choose how events are delivered to the
+	// subscribers of a Trigger with additional subscribers: "parallel", the default, delivers to all of
+	// them at once, while "sequential" delivers to one after the other, in order, stopping at the first
+	// failure.
+	SubscribersDeliveryAnnotation = "internal.events.cloud.google.com/subscribers-delivery"
+	// DeliveryHeadersAnnotation is the annotation key used to set headers on the requests delivering
+	// events to a Trigger's subscribers, e.g. for sinks authenticating requests with an API key. Its
+	// value is a JSON array of headers, each with a name and either a value or a key of a Secret in the
+	// Trigger's namespace, e.g. [{"name":"X-Api-Key","valueFrom":{"secretKeyRef":{"name":"sink","key":"api-key"}}}].
+	// Header values are only stored in a Secret in the system namespace mounted in the data plane pods.
+	DeliveryHeadersAnnotation = "internal.events.cloud.google.com/delivery-headers"
+	// MaxAgeAnnotation is the annotation key used to set the latency budget of a Trigger. Its value is
+	// a duration, e.g. "15m". Events whose time is further in the past when the fanout or the retry
+	// would deliver them, e.g. after a long outage of the subscriber, are sent to the dead letter sink
+	// of the Broker instead, or dropped if it has none. Events without a time are always delivered.
+	MaxAgeAnnotation = "internal.events.cloud.google.com/max-age"
+	// DeliverySLOAnnotation is the annotation key used to set the delivery objective of a Trigger. Its
+	// value is the percentage of deliveries that should succeed, e.g. "99.9". The Trigger then reports
+	// whether its error budget is burning too fast in its DeliverySLOBurning condition.
+	DeliverySLOAnnotation = "internal.events.cloud.google.com/delivery-slo"
+)
+
+const (
+	// SubscribersDeliveryParallel delivers events to all the subscribers of a Trigger at once.
+	SubscribersDeliveryParallel = "parallel"
+	// SubscribersDeliverySequential delivers events to the subscribers of a Trigger one after the
+	// other, in order.
+	SubscribersDeliverySequential = "sequential"
 )
 
 // +genclient
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcpauth
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// ProjectAnnotation is the namespace annotation holding the default
+	// spec.project of the resources created in the namespace.
+	ProjectAnnotation = "events.cloud.google.com/default-project"
+
+	// ServiceAccountNameAnnotation is the namespace annotation holding the
+	// default spec.serviceAccountName of the resources created in the
+	// namespace.
+	ServiceAccountNameAnnotation = "events.cloud.google.com/default-service-account-name"
+
+	// SecretAnnotation is the namespace annotation holding the default
+	// spec.secret of the resources created in the namespace, as
+	// "<name>/<key>". The key defaults to "key.json" when omitted.
+	SecretAnnotation = "events.cloud.google.com/default-secret"
+
+	defaultSecretKey = "key.json"
+)
+
+type namespaceListerKey struct{}
+
+// WithNamespaceLister attaches the lister used to read namespace annotations
+// to the context.
+func WithNamespaceLister(ctx context.Context, l corev1listers.NamespaceLister) context.Context {
+	return context.WithValue(ctx, namespaceListerKey{}, l)
+}
+
+// ResourceDefaults are the defaults applied to a resource that does not set
+// them in its spec.
+// +k8s:deepcopy-gen=false
+type ResourceDefaults struct {
+	Project            string
+	ServiceAccountName string
+	Secret             *corev1.SecretKeySelector
+}
+
+// ForNamespace returns the defaults for a resource in the given namespace.
+// The project is read from the annotations of the namespace. The service
+// account name and secret are read from those annotations if either is set,
+// and from d otherwise. Namespace annotations are only read on create, as
+// the fields they default are immutable, and when a namespace lister is
+// attached to the context.
+func (d *Defaults) ForNamespace(ctx context.Context, ns string) ResourceDefaults {
+	rd := namespaceAnnotationDefaults(ctx, ns)
+	if rd.ServiceAccountName == "" && rd.Secret == nil {
+		rd.ServiceAccountName = d.KSA(ns)
+		rd.Secret = d.Secret(ns)
+	}
+	return rd
+}
+
+func namespaceAnnotationDefaults(ctx context.Context, ns string) ResourceDefaults {
+	l, ok := ctx.Value(namespaceListerKey{}).(corev1listers.NamespaceLister)
+	if !ok || l == nil || ns == "" || !apis.IsInCreate(ctx) {
+		return ResourceDefaults{}
+	}
+	n, err := l.Get(ns)
+	if err != nil {
+		return ResourceDefaults{}
+	}
+	return annotationDefaults(n.Annotations)
+}
+
+func annotationDefaults(annotations map[string]string) ResourceDefaults {
+	rd := ResourceDefaults{
+		Project:            annotations[ProjectAnnotation],
+		ServiceAccountName: annotations[ServiceAccountNameAnnotation],
+	}
+	if s := annotations[SecretAnnotation]; s != "" {
+		name, key := s, defaultSecretKey
+		if i := strings.Index(s, "/"); i >= 0 {
+			name, key = s[:i], s[i+1:]
+		}
+		rd.Secret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+	return rd
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcpauth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+)
+
+func TestForNamespace(t *testing.T) {
+	defaults := &Defaults{
+		ClusterDefaults: ScopedDefaults{
+			ServiceAccountName: "cluster-ksa",
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cluster-key"},
+				Key:                  "key.json",
+			},
+		},
+	}
+	clusterDefaults := ResourceDefaults{
+		ServiceAccountName: "cluster-ksa",
+		Secret:             defaults.ClusterDefaults.Secret,
+	}
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for name, annotations := range map[string]map[string]string{
+		"plain": nil,
+		"project": {
+			ProjectAnnotation: "team-project",
+		},
+		"all": {
+			ProjectAnnotation:            "team-project",
+			ServiceAccountNameAnnotation: "team-ksa",
+			SecretAnnotation:             "team-key/team.json",
+		},
+		"secret-name-only": {
+			SecretAnnotation: "team-key",
+		},
+	} {
+		_ = indexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}})
+	}
+	withLister := WithNamespaceLister(apis.WithinCreate(context.Background()), corev1listers.NewNamespaceLister(indexer))
+
+	testCases := map[string]struct {
+		ctx  context.Context
+		ns   string
+		want ResourceDefaults
+	}{
+		"no lister": {
+			ctx:  apis.WithinCreate(context.Background()),
+			ns:   "all",
+			want: clusterDefaults,
+		},
+		"not in create": {
+			ctx:  WithNamespaceLister(context.Background(), corev1listers.NewNamespaceLister(indexer)),
+			ns:   "all",
+			want: clusterDefaults,
+		},
+		"namespace not found": {
+			ctx:  withLister,
+			ns:   "missing",
+			want: clusterDefaults,
+		},
+		"no annotations": {
+			ctx:  withLister,
+			ns:   "plain",
+			want: clusterDefaults,
+		},
+		"project only": {
+			ctx: withLister,
+			ns:  "project",
+			want: ResourceDefaults{
+				Project:            "team-project",
+				ServiceAccountName: "cluster-ksa",
+				Secret:             defaults.ClusterDefaults.Secret,
+			},
+		},
+		"all annotations": {
+			ctx: withLister,
+			ns:  "all",
+			want: ResourceDefaults{
+				Project:            "team-project",
+				ServiceAccountName: "team-ksa",
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "team-key"},
+					Key:                  "team.json",
+				},
+			},
+		},
+		"secret without key": {
+			ctx: withLister,
+			ns:  "secret-name-only",
+			want: ResourceDefaults{
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "team-key"},
+					Key:                  "key.json",
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := defaults.ForNamespace(tc.ctx, tc.ns)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected defaults (-want +got): %v", diff)
+			}
+		})
+	}
+}
@@ -22,6 +22,9 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 // Secret is the authorization added when using WithDefaults().
@@ -52,3 +55,16 @@ func WithDefaults(ctx context.Context) context.Context {
 	}
 	return gcpauth.ToContext(ctx, cfg)
 }
+
+// WithNamespaceAnnotations attaches a namespace lister holding the namespace ns
+// with the given annotations to the passed in context.
+func WithNamespaceAnnotations(ctx context.Context, ns string, annotations map[string]string) context.Context {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	_ = indexer.Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        ns,
+			Annotations: annotations,
+		},
+	})
+	return gcpauth.WithNamespaceLister(ctx, corev1listers.NewNamespaceLister(indexer))
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topiccheck
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/gclient/pubsub"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// attachSubscriptionPermission is the permission required to create a
+// subscription to a topic.
+const attachSubscriptionPermission = "pubsub.topics.attachSubscription"
+
+type pubsubVerifier struct {
+	createClient pubsub.CreateFn
+}
+
+// NewPubSubVerifier returns a Verifier that checks the topic with the Pub/Sub
+// API, using the credentials of the webhook. The permission check therefore
+// only reflects the permissions of the data plane when both share a Google
+// service account.
+func NewPubSubVerifier(createClient pubsub.CreateFn) Verifier {
+	return &pubsubVerifier{createClient: createClient}
+}
+
+// Verify implements Verifier.
+func (v *pubsubVerifier) Verify(ctx context.Context, project, topic string) error {
+	project, err := utils.ProjectID(project, metadataClient.NewDefaultMetadataClient())
+	if err != nil {
+		return err
+	}
+	client, err := v.createClient(ctx, project)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	topicProject, topicID, err := utils.ParseTopic(topic)
+	if err != nil {
+		return err
+	}
+	if topicProject == "" {
+		topicProject = project
+	}
+	t := client.TopicInProject(topicID, topicProject)
+	exists, err := t.Exists(ctx)
+	if status.Code(err) == codes.PermissionDenied {
+		return fmt.Errorf("%w: %v", ErrPermissionDenied, err)
+	} else if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w in project %q", ErrTopicNotFound, topicProject)
+	}
+
+	granted, err := t.IAM().TestPermissions(ctx, []string{attachSubscriptionPermission})
+	if err != nil {
+		return err
+	}
+	if len(granted) == 0 {
+		return fmt.Errorf("%w: missing %s", ErrPermissionDenied, attachSubscriptionPermission)
+	}
+	return nil
+}
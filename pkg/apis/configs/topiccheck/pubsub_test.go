@@ -0,0 +1,88 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topiccheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
+	testingpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
+)
+
+func TestPubSubVerifier(t *testing.T) {
+	testCases := []struct {
+		name           string
+		data           testingpubsub.TestClientData
+		wantErr        error
+		wantUnverified bool
+	}{{
+		name: "topic exists",
+		data: testingpubsub.TestClientData{
+			TopicData: testingpubsub.TestTopicData{Exists: true},
+		},
+	}, {
+		name:    "topic does not exist",
+		wantErr: ErrTopicNotFound,
+	}, {
+		name: "exists permission denied",
+		data: testingpubsub.TestClientData{
+			TopicData: testingpubsub.TestTopicData{
+				ExistsErr: status.Error(codes.PermissionDenied, "denied"),
+			},
+		},
+		wantErr: ErrPermissionDenied,
+	}, {
+		name: "missing attach permission",
+		data: testingpubsub.TestClientData{
+			TopicData:  testingpubsub.TestTopicData{Exists: true},
+			HandleData: testiam.TestHandleData{Permissions: []string{}},
+		},
+		wantErr: ErrPermissionDenied,
+	}, {
+		name: "exists error",
+		data: testingpubsub.TestClientData{
+			TopicData: testingpubsub.TestTopicData{
+				ExistsErr: status.Error(codes.Unavailable, "unavailable"),
+			},
+		},
+		wantUnverified: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := NewPubSubVerifier(testingpubsub.TestClientCreator(tc.data))
+			err := v.Verify(context.Background(), "test-project", "test-topic")
+			switch {
+			case tc.wantErr != nil:
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Verify() got error %v, want %v", err, tc.wantErr)
+				}
+			case tc.wantUnverified:
+				if err == nil || errors.Is(err, ErrTopicNotFound) || errors.Is(err, ErrPermissionDenied) {
+					t.Errorf("Verify() got error %v, want an unverified topic error", err)
+				}
+			case err != nil:
+				t.Errorf("Verify() got unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topiccheck verifies at admission that the Pub/Sub topic referenced
+// by a PullSubscription exists and can be subscribed to, so that
+// misconfigurations surface before the resource sits NotReady.
+package topiccheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// ModeEnvKey is the environment variable of the webhook that enables the
+	// admission-time topic check.
+	ModeEnvKey = "PULLSUBSCRIPTION_TOPIC_CHECK"
+
+	// checkTimeout bounds the Pub/Sub calls so that a slow API does not
+	// time out the admission request.
+	checkTimeout = 3 * time.Second
+)
+
+// Mode is the mode of the admission-time topic check.
+type Mode string
+
+const (
+	// ModeDisabled disables the check.
+	ModeDisabled Mode = ""
+	// ModeWarn logs a warning for a misconfigured topic but admits the
+	// resource.
+	ModeWarn Mode = "warn"
+	// ModeReject rejects a resource with a misconfigured topic.
+	ModeReject Mode = "reject"
+)
+
+var (
+	// ErrTopicNotFound is returned by a Verifier when the topic does not exist.
+	ErrTopicNotFound = errors.New("topic not found")
+	// ErrPermissionDenied is returned by a Verifier when the topic cannot be
+	// subscribed to.
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// Verifier verifies that a Pub/Sub topic exists and can be subscribed to.
+type Verifier interface {
+	// Verify returns ErrTopicNotFound or ErrPermissionDenied, possibly
+	// wrapped, for a misconfigured topic, and any other error when the topic
+	// could not be verified.
+	Verify(ctx context.Context, project, topic string) error
+}
+
+// ModeFromEnv returns the mode set in the environment. Unknown modes disable
+// the check.
+func ModeFromEnv() Mode {
+	switch m := Mode(os.Getenv(ModeEnvKey)); m {
+	case ModeWarn, ModeReject:
+		return m
+	default:
+		return ModeDisabled
+	}
+}
+
+type checkerKey struct{}
+
+type checker struct {
+	verifier Verifier
+	mode     Mode
+}
+
+// WithVerifier attaches the verifier and mode of the topic check to the
+// context. The check is disabled if the mode is ModeDisabled.
+func WithVerifier(ctx context.Context, v Verifier, mode Mode) context.Context {
+	if v == nil || mode == ModeDisabled {
+		return ctx
+	}
+	return context.WithValue(ctx, checkerKey{}, &checker{verifier: v, mode: mode})
+}
+
+// Check verifies the topic when a resource is created and a verifier is
+// attached to the context. It returns an error for the topic field of a
+// misconfigured topic in ModeReject, and logs a warning in ModeWarn. Topics
+// that could not be verified are admitted.
+func Check(ctx context.Context, project, topic string) *apis.FieldError {
+	c, ok := ctx.Value(checkerKey{}).(*checker)
+	if !ok || topic == "" || !apis.IsInCreate(ctx) {
+		return nil
+	}
+
+	vctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+	err := c.verifier.Verify(vctx, project, topic)
+	if err == nil {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+	if !errors.Is(err, ErrTopicNotFound) && !errors.Is(err, ErrPermissionDenied) {
+		logger.Debugw("Unable to verify the Pub/Sub topic", zap.String("topic", topic), zap.Error(err))
+		return nil
+	}
+	if c.mode == ModeWarn {
+		logger.Warnw("Pub/Sub topic is misconfigured", zap.String("topic", topic), zap.Error(err))
+		return nil
+	}
+	return &apis.FieldError{
+		Message: fmt.Sprintf("invalid Pub/Sub topic %q: %v", topic, err),
+		Paths:   []string{"topic"},
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topiccheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"knative.dev/pkg/apis"
+)
+
+type fakeVerifier struct {
+	err   error
+	calls int
+}
+
+func (v *fakeVerifier) Verify(_ context.Context, _, _ string) error {
+	v.calls++
+	return v.err
+}
+
+func TestModeFromEnv(t *testing.T) {
+	old, ok := os.LookupEnv(ModeEnvKey)
+	defer func() {
+		if ok {
+			os.Setenv(ModeEnvKey, old)
+		} else {
+			os.Unsetenv(ModeEnvKey)
+		}
+	}()
+
+	for value, want := range map[string]Mode{
+		"":        ModeDisabled,
+		"warn":    ModeWarn,
+		"reject":  ModeReject,
+		"invalid": ModeDisabled,
+	} {
+		os.Setenv(ModeEnvKey, value)
+		if got := ModeFromEnv(); got != want {
+			t.Errorf("ModeFromEnv() with %q got=%q, want=%q", value, got, want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name      string
+		mode      Mode
+		err       error
+		update    bool
+		wantCalls int
+		wantErr   bool
+	}{{
+		name: "disabled",
+		mode: ModeDisabled,
+		err:  ErrTopicNotFound,
+	}, {
+		name:      "valid topic",
+		mode:      ModeReject,
+		wantCalls: 1,
+	}, {
+		name:      "reject missing topic",
+		mode:      ModeReject,
+		err:       fmt.Errorf("%w in project %q", ErrTopicNotFound, "p"),
+		wantCalls: 1,
+		wantErr:   true,
+	}, {
+		name:      "reject denied topic",
+		mode:      ModeReject,
+		err:       ErrPermissionDenied,
+		wantCalls: 1,
+		wantErr:   true,
+	}, {
+		name:      "warn missing topic",
+		mode:      ModeWarn,
+		err:       ErrTopicNotFound,
+		wantCalls: 1,
+	}, {
+		name:      "unverified topic",
+		mode:      ModeReject,
+		err:       errors.New("unavailable"),
+		wantCalls: 1,
+	}, {
+		name:   "update",
+		mode:   ModeReject,
+		err:    ErrTopicNotFound,
+		update: true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := &fakeVerifier{err: tc.err}
+			ctx := WithVerifier(context.Background(), v, tc.mode)
+			if tc.update {
+				ctx = apis.WithinUpdate(ctx, nil)
+			} else {
+				ctx = apis.WithinCreate(ctx)
+			}
+			err := Check(ctx, "p", "t")
+			if tc.wantErr != (err != nil) {
+				t.Errorf("Check() got error %v, want error %v", err, tc.wantErr)
+			}
+			if v.calls != tc.wantCalls {
+				t.Errorf("Verify calls got=%d, want=%d", v.calls, tc.wantCalls)
+			}
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 var (
 	validComponents = sets.NewString(
 		"controller",
+		"webhook",
 	)
 )
 
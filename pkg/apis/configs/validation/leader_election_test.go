@@ -69,7 +69,7 @@ func TestValidateLeaderElectionConfig(t *testing.T) {
 				data["enabledComponents"] = "controller,frobulator"
 				return data
 			}(),
-			err: errors.New(`invalid enabledComponent "frobulator": valid values are ["controller"]`),
+			err: errors.New(`invalid enabledComponent "frobulator": valid values are ["controller" "webhook"]`),
 		},
 	}
 
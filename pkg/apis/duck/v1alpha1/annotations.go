@@ -23,9 +23,13 @@ import (
 	"strconv"
 
 	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/eventing/pkg/logging"
 	"knative.dev/pkg/apis"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	"github.com/google/knative-gcp/pkg/utils"
 )
@@ -80,6 +84,7 @@ const (
 
 	// minimumKedaPollingInterval is the minimum allowed value for the KedaAutoscalingPollingIntervalAnnotation annotation.
 	minimumKedaPollingInterval = 5
+
 	// minimumKedaCooldownPeriod is the minimum allowed value for the KedaAutoscalingCooldownPeriodAnnotation annotation.
 	minimumKedaCooldownPeriod = 15
 	// minimumKedaSubscriptionSize is the minimum allowed value for the KedaAutoscalingSubscriptionSizeAnnotation annotation.
@@ -171,27 +176,59 @@ func validateAnnotationNotExists(annotations map[string]string, annotation strin
 	return errs
 }
 
-// SetClusterNameAnnotation sets the cluster-name annotation when running on GKE or GCE.
-func SetClusterNameAnnotation(obj *metav1.ObjectMeta, client metadataClient.Client) {
-	if _, ok := obj.Annotations[ClusterNameAnnotation]; !ok && client.OnGCE() {
-		clusterName, err := utils.ClusterName(obj.Annotations[ClusterNameAnnotation], client)
-		// If metadata access is disabled for some reason, leave the annotation to be empty.
-		if err == nil {
+// SetClusterNameAnnotation sets the cluster-name annotation if it is missing. On updates the annotation of the
+// original object is carried over, so that clients replacing the object without it don't change it. Otherwise it
+// defaults to the cluster name overridden by the operator, or to the one of the GKE metadata server when running on
+// GKE or GCE.
+func SetClusterNameAnnotation(ctx context.Context, obj *metav1.ObjectMeta, client metadataClient.Client) {
+	if _, ok := obj.Annotations[ClusterNameAnnotation]; ok {
+		return
+	}
+	if base, ok := apis.GetBaseline(ctx).(metav1.Object); ok && apis.IsInUpdate(ctx) {
+		if clusterName := base.GetAnnotations()[ClusterNameAnnotation]; clusterName != "" {
 			setDefaultAnnotationIfNotPresent(obj, ClusterNameAnnotation, clusterName)
+			return
 		}
 	}
+	if utils.ClusterNameOverride() == "" && !client.OnGCE() {
+		return
+	}
+	clusterName, err := utils.ClusterName("", client)
+	// If metadata access is disabled for some reason, leave the annotation unset, it can still be set later.
+	if err == nil && clusterName != "" {
+		setDefaultAnnotationIfNotPresent(obj, ClusterNameAnnotation, clusterName)
+	}
+}
+
+// SetDeprecatedAPIVersionAnnotation records that obj is being created or updated with the deprecated API version gv.
+// The request is not rejected, the webhook only logs a warning and the reconcilers surface the deprecation in the
+// status of the object.
+func SetDeprecatedAPIVersionAnnotation(ctx context.Context, obj *metav1.ObjectMeta, gv schema.GroupVersion) {
+	if obj.Annotations[duckv1beta1.DeprecatedAPIVersionAnnotation] == gv.String() {
+		return
+	}
+	logging.FromContext(ctx).Warn("Deprecated API version used",
+		zap.String("apiVersion", gv.String()),
+		zap.String("namespace", obj.Namespace),
+		zap.String("name", obj.Name))
+	if obj.Annotations == nil {
+		obj.Annotations = make(map[string]string)
+	}
+	obj.Annotations[duckv1beta1.DeprecatedAPIVersionAnnotation] = gv.String()
 }
 
-// CheckImmutableClusterNameAnnotation checks non-empty cluster-name annotation is immutable.
+// CheckImmutableClusterNameAnnotation checks that a non-empty cluster-name annotation is neither changed nor removed.
+// An empty or missing annotation can still be set, e.g. once the cluster name is known.
 func CheckImmutableClusterNameAnnotation(current *metav1.ObjectMeta, original *metav1.ObjectMeta, errs *apis.FieldError) *apis.FieldError {
-	if _, ok := original.Annotations[ClusterNameAnnotation]; ok {
-		if diff := cmp.Diff(original.Annotations[ClusterNameAnnotation], current.Annotations[ClusterNameAnnotation]); diff != "" {
-			return errs.Also(&apis.FieldError{
-				Message: "Immutable fields changed (-old +new)",
-				Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", ClusterNameAnnotation)},
-				Details: diff,
-			})
-		}
+	if original.Annotations[ClusterNameAnnotation] == "" {
+		return errs
+	}
+	if diff := cmp.Diff(original.Annotations[ClusterNameAnnotation], current.Annotations[ClusterNameAnnotation]); diff != "" {
+		return errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new); the cluster name can't be changed once set, leave the annotation out to keep it",
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", ClusterNameAnnotation)},
+			Details: diff,
+		})
 	}
 	return errs
 }
@@ -23,8 +23,10 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 )
 
@@ -250,7 +252,57 @@ func TestSetClusterNameAnnotation(t *testing.T) {
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
-			SetClusterNameAnnotation(tc.orig, testingMetadataClient.NewTestClient(tc.data))
+			SetClusterNameAnnotation(context.Background(), tc.orig, testingMetadataClient.NewTestClient(tc.data))
+			if diff := cmp.Diff(tc.expected, tc.orig); diff != "" {
+				t.Errorf("Unexpected differences (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestSetDeprecatedAPIVersionAnnotation(t *testing.T) {
+	gv := schema.GroupVersion{Group: "events.cloud.google.com", Version: "v1alpha1"}
+	testCases := map[string]struct {
+		orig     *v1.ObjectMeta
+		expected *v1.ObjectMeta
+	}{
+		"no annotations": {
+			orig: &v1.ObjectMeta{},
+			expected: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+				},
+			},
+		},
+		"other annotations": {
+			orig: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: "testing-cluster-name",
+				},
+			},
+			expected: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation:          "testing-cluster-name",
+					duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+				},
+			},
+		},
+		"has annotation": {
+			orig: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+				},
+			},
+			expected: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			SetDeprecatedAPIVersionAnnotation(context.Background(), tc.orig, gv)
 			if diff := cmp.Diff(tc.expected, tc.orig); diff != "" {
 				t.Errorf("Unexpected differences (-want +got): %v", diff)
 			}
@@ -273,6 +325,19 @@ func TestCheckImmutableClusterNameAnnotation(t *testing.T) {
 			},
 			error: false,
 		},
+		"update empty annotation value": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: "",
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				},
+			},
+			error: false,
+		},
 		"update non-empty annotation": {
 			original: &v1.ObjectMeta{
 				Annotations: map[string]string{
@@ -24,8 +24,10 @@ import (
 	"github.com/google/knative-gcp/pkg/utils"
 	"math"
 	"strconv"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"knative.dev/pkg/apis"
 )
 
@@ -57,6 +59,47 @@ const (
 	// Pub/Sub subscription that Keda uses in order to decide when and by how much to scale out.
 	KedaAutoscalingSubscriptionSizeAnnotation = KEDA + "/subscriptionSize"
 
+	// MetricsBackendDestinationAnnotation is the annotation to override the metrics backend of the data plane
+	// resources, e.g. "none" to exclude a high-volume receive adapter from the Cloud Monitoring export.
+	MetricsBackendDestinationAnnotation = "metrics.events.cloud.google.com/backend-destination"
+
+	// AbandonOnFinalizeFailureAnnotation is the annotation that, when set to "true", lets a resource be deleted even
+	// if the external resources it manages, e.g. Pub/Sub topics, can't be deleted in time. Those are abandoned instead,
+	// so that the resource and its namespace don't hang in Terminating during an outage of the Google Cloud APIs.
+	AbandonOnFinalizeFailureAnnotation = "events.cloud.google.com/abandon-on-finalize-failure"
+
+	// DryRunAnnotation is the annotation that, when set to "true", makes the reconcilers report the external changes
+	// they would make for a resource, e.g. creating Pub/Sub topics or IAM policy bindings, as events and conditions
+	// instead of making them.
+	DryRunAnnotation = "events.cloud.google.com/dry-run"
+
+	// ReceiveAdapterModeAnnotation is the annotation that selects how the receive adapter of a PullSubscription runs.
+	// By default every PullSubscription gets its own receive adapter Deployment. When set to
+	// ReceiveAdapterModeAgent, the PullSubscription is instead served by the shared receive adapter agent, which pulls
+	// for many low-volume PullSubscriptions in one process per node.
+	ReceiveAdapterModeAnnotation = "events.cloud.google.com/receive-adapter-mode"
+	// ReceiveAdapterModeAgent is the ReceiveAdapterModeAnnotation value for the shared receive adapter agent.
+	ReceiveAdapterModeAgent = "agent"
+
+	// DeprecatedAPIVersionAnnotation is the annotation that records the deprecated API version, e.g.
+	// "events.cloud.google.com/v1alpha1", an object was last created or updated with. It is set by the webhook and
+	// makes the reconcilers mark the object as deprecated.
+	DeprecatedAPIVersionAnnotation = "events.cloud.google.com/deprecated-api-version"
+	// ConditionDeprecated is the condition, with a Warning severity, that is True while an object was last created or
+	// updated with a deprecated API version. It doesn't affect the readiness of the object.
+	ConditionDeprecated apis.ConditionType = "Deprecated"
+
+	// CatchUpUntilAnnotation is the annotation that, set to an RFC 3339 time, temporarily boosts the data plane of a
+	// PullSubscription or a BrokerCell, e.g. to drain a backlog after an outage of the sinks. The reconcilers revert
+	// the boost once the time has passed, so that rare catch-ups don't require permanent over-provisioning. It can be
+	// set at most MaxCatchUpDuration ahead.
+	CatchUpUntilAnnotation = "events.cloud.google.com/catch-up-until"
+	// CatchUpFactor is how many times more replicas and outstanding messages the receive adapter of a PullSubscription
+	// gets while it catches up.
+	CatchUpFactor = 4
+	// MaxCatchUpDuration is how far ahead the CatchUpUntilAnnotation annotation can be set.
+	MaxCatchUpDuration = 24 * time.Hour
+
 	// defaultMinScale is the default minimum set of Pods the scaler should
 	// downscale the resource to.
 	defaultMinScale = "0"
@@ -85,6 +128,9 @@ const (
 	minimumKedaSubscriptionSize = 5
 )
 
+// metricsBackends are the allowed values for the MetricsBackendDestinationAnnotation annotation.
+var metricsBackends = sets.NewString("stackdriver", "prometheus", "opencensus", "none")
+
 func SetAutoscalingAnnotationsDefaults(ctx context.Context, obj *metav1.ObjectMeta) {
 	// If autoscaling was configured, then set defaults.
 	if _, ok := obj.Annotations[AutoscalingClassAnnotation]; ok {
@@ -134,6 +180,66 @@ func ValidateAutoscalingAnnotations(ctx context.Context, annotations map[string]
 	return errs
 }
 
+// ValidateMetricsAnnotations validates the metrics annotations.
+func ValidateMetricsAnnotations(ctx context.Context, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	if backend, ok := annotations[MetricsBackendDestinationAnnotation]; ok && !metricsBackends.Has(backend) {
+		errs = errs.Also(apis.ErrInvalidValue(backend, fmt.Sprintf("metadata.annotations[%s]", MetricsBackendDestinationAnnotation)))
+	}
+	return errs
+}
+
+// ValidateReceiveAdapterModeAnnotation validates the receive adapter mode annotation. The agent doesn't scale, so it
+// can't be combined with an autoscaling class.
+func ValidateReceiveAdapterModeAnnotation(ctx context.Context, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	mode, ok := annotations[ReceiveAdapterModeAnnotation]
+	if !ok {
+		return errs
+	}
+	path := fmt.Sprintf("metadata.annotations[%s]", ReceiveAdapterModeAnnotation)
+	if mode != ReceiveAdapterModeAgent {
+		return errs.Also(apis.ErrInvalidValue(mode, path))
+	}
+	if _, ok := annotations[AutoscalingClassAnnotation]; ok {
+		errs = errs.Also(apis.ErrMultipleOneOf(path, fmt.Sprintf("metadata.annotations[%s]", AutoscalingClassAnnotation)))
+	}
+	return errs
+}
+
+// ValidateCatchUpAnnotation validates the catch-up annotation. Times that have passed are accepted, as the annotation
+// is usually left in place once the data plane caught up.
+func ValidateCatchUpAnnotation(ctx context.Context, annotations map[string]string, errs *apis.FieldError) *apis.FieldError {
+	v, ok := annotations[CatchUpUntilAnnotation]
+	if !ok {
+		return errs
+	}
+	path := fmt.Sprintf("metadata.annotations[%s]", CatchUpUntilAnnotation)
+	until, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return errs.Also(apis.ErrInvalidValue(v, path))
+	}
+	if until.After(time.Now().Add(MaxCatchUpDuration)) {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("catch-up must end within %v", MaxCatchUpDuration),
+			Paths:   []string{path},
+		})
+	}
+	return errs
+}
+
+// CatchUpUntil returns when the catch-up set by the annotations ends, and
+// whether it is still ongoing at now.
+func CatchUpUntil(annotations map[string]string, now time.Time) (time.Time, bool) {
+	v, ok := annotations[CatchUpUntilAnnotation]
+	if !ok {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, now.Before(until)
+}
+
 func validateAnnotation(annotations map[string]string, annotation string, minimumValue int, errs *apis.FieldError) (int, *apis.FieldError) {
 	var value int
 	if val, ok := annotations[annotation]; !ok {
@@ -170,27 +276,42 @@ func validateAnnotationNotExists(annotations map[string]string, annotation strin
 	return errs
 }
 
-// SetClusterNameAnnotation sets the cluster-name annotation when running on GKE or GCE.
-func SetClusterNameAnnotation(obj *metav1.ObjectMeta, client metadataClient.Client) {
-	if _, ok := obj.Annotations[ClusterNameAnnotation]; !ok && client.OnGCE() {
-		clusterName, err := utils.ClusterName(obj.Annotations[ClusterNameAnnotation], client)
-		// If metadata access is disabled for some reason, leave the annotation to be empty.
-		if err == nil {
+// SetClusterNameAnnotation sets the cluster-name annotation if it is missing. On updates the annotation of the
+// original object is carried over, so that clients replacing the object without it don't change it. Otherwise it
+// defaults to the cluster name overridden by the operator, or to the one of the GKE metadata server when running on
+// GKE or GCE.
+func SetClusterNameAnnotation(ctx context.Context, obj *metav1.ObjectMeta, client metadataClient.Client) {
+	if _, ok := obj.Annotations[ClusterNameAnnotation]; ok {
+		return
+	}
+	if base, ok := apis.GetBaseline(ctx).(metav1.Object); ok && apis.IsInUpdate(ctx) {
+		if clusterName := base.GetAnnotations()[ClusterNameAnnotation]; clusterName != "" {
 			setDefaultAnnotationIfNotPresent(obj, ClusterNameAnnotation, clusterName)
+			return
 		}
 	}
+	if utils.ClusterNameOverride() == "" && !client.OnGCE() {
+		return
+	}
+	clusterName, err := utils.ClusterName("", client)
+	// If metadata access is disabled for some reason, leave the annotation unset, it can still be set later.
+	if err == nil && clusterName != "" {
+		setDefaultAnnotationIfNotPresent(obj, ClusterNameAnnotation, clusterName)
+	}
 }
 
-// CheckImmutableClusterNameAnnotation checks non-empty cluster-name annotation is immutable.
+// CheckImmutableClusterNameAnnotation checks that a non-empty cluster-name annotation is neither changed nor removed.
+// An empty or missing annotation can still be set, e.g. once the cluster name is known.
 func CheckImmutableClusterNameAnnotation(current *metav1.ObjectMeta, original *metav1.ObjectMeta, errs *apis.FieldError) *apis.FieldError {
-	if _, ok := original.Annotations[ClusterNameAnnotation]; ok {
-		if diff := cmp.Diff(original.Annotations[ClusterNameAnnotation], current.Annotations[ClusterNameAnnotation]); diff != "" {
-			return errs.Also(&apis.FieldError{
-				Message: "Immutable fields changed (-old +new)",
-				Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", ClusterNameAnnotation)},
-				Details: diff,
-			})
-		}
+	if original.Annotations[ClusterNameAnnotation] == "" {
+		return errs
+	}
+	if diff := cmp.Diff(original.Annotations[ClusterNameAnnotation], current.Annotations[ClusterNameAnnotation]); diff != "" {
+		return errs.Also(&apis.FieldError{
+			Message: "Immutable fields changed (-old +new); the cluster name can't be changed once set, leave the annotation out to keep it",
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", ClusterNameAnnotation)},
+			Details: diff,
+		})
 	}
 	return errs
 }
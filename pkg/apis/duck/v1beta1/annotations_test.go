@@ -18,11 +18,17 @@ package v1beta1
 
 import (
 	"context"
+	"errors"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
+
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/utils"
 )
 
 var (
@@ -208,3 +214,282 @@ func TestValidateAutoscalingAnnotations(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateMetricsAnnotations(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		error       bool
+	}{
+		"ok no annotation": {
+			annotations: nil,
+			error:       false,
+		},
+		"ok none backend": {
+			annotations: map[string]string{MetricsBackendDestinationAnnotation: "none"},
+			error:       false,
+		},
+		"ok prometheus backend": {
+			annotations: map[string]string{MetricsBackendDestinationAnnotation: "prometheus"},
+			error:       false,
+		},
+		"invalid backend": {
+			annotations: map[string]string{MetricsBackendDestinationAnnotation: "invalid"},
+			error:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidateMetricsAnnotations(context.TODO(), tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateReceiveAdapterModeAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		error       bool
+	}{
+		"ok no annotation": {
+			annotations: nil,
+			error:       false,
+		},
+		"ok agent mode": {
+			annotations: map[string]string{ReceiveAdapterModeAnnotation: ReceiveAdapterModeAgent},
+			error:       false,
+		},
+		"invalid mode": {
+			annotations: map[string]string{ReceiveAdapterModeAnnotation: "invalid"},
+			error:       true,
+		},
+		"agent mode with autoscaling": {
+			annotations: map[string]string{
+				ReceiveAdapterModeAnnotation: ReceiveAdapterModeAgent,
+				AutoscalingClassAnnotation:   KEDA,
+			},
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidateReceiveAdapterModeAnnotation(context.TODO(), tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCatchUpAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		error       bool
+	}{
+		"ok no annotation": {
+			annotations: nil,
+			error:       false,
+		},
+		"ok ahead": {
+			annotations: map[string]string{CatchUpUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)},
+			error:       false,
+		},
+		"ok passed": {
+			annotations: map[string]string{CatchUpUntilAnnotation: "2020-01-01T00:00:00Z"},
+			error:       false,
+		},
+		"too far ahead": {
+			annotations: map[string]string{CatchUpUntilAnnotation: time.Now().Add(MaxCatchUpDuration + time.Hour).Format(time.RFC3339)},
+			error:       true,
+		},
+		"not a time": {
+			annotations: map[string]string{CatchUpUntilAnnotation: "1h"},
+			error:       true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var errs *apis.FieldError
+			err := ValidateCatchUpAnnotation(context.TODO(), tc.annotations, errs)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestCatchUpUntil(t *testing.T) {
+	now := time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		annotations map[string]string
+		wantUntil   time.Time
+		wantOK      bool
+	}{
+		"no annotation": {},
+		"ongoing": {
+			annotations: map[string]string{CatchUpUntilAnnotation: "2020-07-01T13:00:00Z"},
+			wantUntil:   now.Add(time.Hour),
+			wantOK:      true,
+		},
+		"ended": {
+			annotations: map[string]string{CatchUpUntilAnnotation: "2020-07-01T11:00:00Z"},
+			wantUntil:   now.Add(-time.Hour),
+		},
+		"not a time": {
+			annotations: map[string]string{CatchUpUntilAnnotation: "1h"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			until, ok := CatchUpUntil(tc.annotations, now)
+			if !until.Equal(tc.wantUntil) || ok != tc.wantOK {
+				t.Errorf("CatchUpUntil() = %v, %t, want %v, %t", until, ok, tc.wantUntil, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestSetClusterNameAnnotation(t *testing.T) {
+	withClusterName := func(name string) *v1.ObjectMeta {
+		return &v1.ObjectMeta{
+			Annotations: map[string]string{
+				ClusterNameAnnotation: name,
+			},
+		}
+	}
+	testCases := map[string]struct {
+		orig     *v1.ObjectMeta
+		baseline *v1.ObjectMeta
+		override string
+		data     testingMetadataClient.TestClientData
+		expected *v1.ObjectMeta
+	}{
+		"no annotation, successfully get the clusterName": {
+			orig:     &v1.ObjectMeta{},
+			expected: withClusterName(testingMetadataClient.FakeClusterName),
+		},
+		"no annotation, get clusterName failed": {
+			orig: &v1.ObjectMeta{},
+			data: testingMetadataClient.TestClientData{
+				ClusterNameErr: errors.New("error when get clusterName"),
+			},
+			expected: &v1.ObjectMeta{},
+		},
+		"no annotation, overridden": {
+			orig:     &v1.ObjectMeta{},
+			override: "override-cluster-name",
+			data: testingMetadataClient.TestClientData{
+				ClusterNameErr: errors.New("error when get clusterName"),
+			},
+			expected: withClusterName("override-cluster-name"),
+		},
+		"has annotation": {
+			orig:     withClusterName("testing-cluster-name"),
+			override: "override-cluster-name",
+			expected: withClusterName("testing-cluster-name"),
+		},
+		"update without annotation keeps the original": {
+			orig:     &v1.ObjectMeta{},
+			baseline: withClusterName("original-cluster-name"),
+			expected: withClusterName("original-cluster-name"),
+		},
+		"update without annotation, original without annotation": {
+			orig:     &v1.ObjectMeta{},
+			baseline: &v1.ObjectMeta{},
+			expected: withClusterName(testingMetadataClient.FakeClusterName),
+		},
+		"update with annotation": {
+			orig:     withClusterName("new-cluster-name"),
+			baseline: withClusterName("original-cluster-name"),
+			expected: withClusterName("new-cluster-name"),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			defer os.Setenv(utils.ClusterNameEnvKey, os.Getenv(utils.ClusterNameEnvKey))
+			os.Setenv(utils.ClusterNameEnvKey, tc.override)
+			ctx := context.Background()
+			if tc.baseline != nil {
+				ctx = apis.WithinUpdate(ctx, tc.baseline)
+			}
+			SetClusterNameAnnotation(ctx, tc.orig, testingMetadataClient.NewTestClient(tc.data))
+			if diff := cmp.Diff(tc.expected, tc.orig); diff != "" {
+				t.Errorf("Unexpected differences (-want +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestCheckImmutableClusterNameAnnotation(t *testing.T) {
+	testCases := map[string]struct {
+		original *v1.ObjectMeta
+		current  *v1.ObjectMeta
+		error    bool
+	}{
+		"set missing annotation": {
+			original: &v1.ObjectMeta{},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				},
+			},
+		},
+		"set empty annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: "",
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				},
+			},
+		},
+		"update non-empty annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName + "old",
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName + "new",
+				},
+			},
+			error: true,
+		},
+		"remove non-empty annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				},
+			},
+			current: &v1.ObjectMeta{},
+			error:   true,
+		},
+		"unchanged annotation": {
+			original: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: "testing-cluster-name",
+				},
+			},
+			current: &v1.ObjectMeta{
+				Annotations: map[string]string{
+					ClusterNameAnnotation: "testing-cluster-name",
+				},
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := CheckImmutableClusterNameAnnotation(tc.current, tc.original, nil)
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	"knative.dev/pkg/apis"
+)
+
+const (
+	// IstioProxyConfigAnnotation overrides the mesh proxy config of a pod.
+	IstioProxyConfigAnnotation = "proxy.istio.io/config"
+	// IstioExcludeOutboundPortsAnnotation lists the outbound ports that bypass the sidecar.
+	IstioExcludeOutboundPortsAnnotation = "traffic.sidecar.istio.io/excludeOutboundPorts"
+	// IstioExcludeOutboundIPRangesAnnotation lists the outbound CIDRs that bypass the sidecar.
+	IstioExcludeOutboundIPRangesAnnotation = "traffic.sidecar.istio.io/excludeOutboundIPRanges"
+	// IstioExcludeInboundPortsAnnotation lists the inbound ports that bypass the sidecar.
+	IstioExcludeInboundPortsAnnotation = "traffic.sidecar.istio.io/excludeInboundPorts"
+
+	holdApplicationProxyConfig = "holdApplicationUntilProxyStarts: true"
+)
+
+// IstioSpec configures how data plane pods run alongside an injected Istio
+// sidecar.
+type IstioSpec struct {
+	// HoldApplicationUntilProxyStarts delays the data plane container until
+	// the sidecar proxy is ready, so that the first Pub/Sub and sink calls
+	// don't fail while the proxy is starting.
+	// +optional
+	HoldApplicationUntilProxyStarts bool `json:"holdApplicationUntilProxyStarts,omitempty"`
+
+	// ExcludeOutboundPorts are outbound ports whose traffic bypasses the
+	// sidecar, e.g. 443 for the Pub/Sub gRPC streams.
+	// +optional
+	ExcludeOutboundPorts []int32 `json:"excludeOutboundPorts,omitempty"`
+
+	// ExcludeOutboundIPRanges are outbound CIDRs whose traffic bypasses the
+	// sidecar.
+	// +optional
+	ExcludeOutboundIPRanges []string `json:"excludeOutboundIPRanges,omitempty"`
+
+	// ExcludeInboundPorts are inbound ports whose traffic bypasses the
+	// sidecar, e.g. the metrics port.
+	// +optional
+	ExcludeInboundPorts []int32 `json:"excludeInboundPorts,omitempty"`
+}
+
+// PodAnnotations returns the Istio annotations to set on data plane pod
+// templates. It returns nil if there is nothing to set.
+func (s *IstioSpec) PodAnnotations() map[string]string {
+	if s == nil {
+		return nil
+	}
+	annotations := make(map[string]string)
+	if s.HoldApplicationUntilProxyStarts {
+		annotations[IstioProxyConfigAnnotation] = holdApplicationProxyConfig
+	}
+	if len(s.ExcludeOutboundPorts) > 0 {
+		annotations[IstioExcludeOutboundPortsAnnotation] = joinPorts(s.ExcludeOutboundPorts)
+	}
+	if len(s.ExcludeOutboundIPRanges) > 0 {
+		annotations[IstioExcludeOutboundIPRangesAnnotation] = strings.Join(s.ExcludeOutboundIPRanges, ",")
+	}
+	if len(s.ExcludeInboundPorts) > 0 {
+		annotations[IstioExcludeInboundPortsAnnotation] = joinPorts(s.ExcludeInboundPorts)
+	}
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
+
+// Validate checks that the ports and IP ranges are well formed.
+func (s *IstioSpec) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	errs = errs.Also(validatePorts(s.ExcludeOutboundPorts, "excludeOutboundPorts"))
+	errs = errs.Also(validatePorts(s.ExcludeInboundPorts, "excludeInboundPorts"))
+	for i, r := range s.ExcludeOutboundIPRanges {
+		if _, _, err := net.ParseCIDR(r); err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(r, "excludeOutboundIPRanges", i))
+		}
+	}
+	return errs
+}
+
+func validatePorts(ports []int32, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, p := range ports {
+		if p < 1 || p > 65535 {
+			errs = errs.Also(apis.ErrInvalidArrayValue(p, field, i))
+		}
+	}
+	return errs
+}
+
+func joinPorts(ports []int32) string {
+	s := make([]string, len(ports))
+	for i, p := range ports {
+		s[i] = strconv.Itoa(int(p))
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIstioSpecPodAnnotations(t *testing.T) {
+	testCases := map[string]struct {
+		spec *IstioSpec
+		want map[string]string
+	}{
+		"nil": {
+			spec: nil,
+			want: nil,
+		},
+		"empty": {
+			spec: &IstioSpec{},
+			want: nil,
+		},
+		"all": {
+			spec: &IstioSpec{
+				HoldApplicationUntilProxyStarts: true,
+				ExcludeOutboundPorts:            []int32{443, 8443},
+				ExcludeOutboundIPRanges:         []string{"199.36.153.4/30"},
+				ExcludeInboundPorts:             []int32{9090},
+			},
+			want: map[string]string{
+				IstioProxyConfigAnnotation:             "holdApplicationUntilProxyStarts: true",
+				IstioExcludeOutboundPortsAnnotation:    "443,8443",
+				IstioExcludeOutboundIPRangesAnnotation: "199.36.153.4/30",
+				IstioExcludeInboundPortsAnnotation:     "9090",
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.spec.PodAnnotations()); diff != "" {
+				t.Errorf("Unexpected annotations (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestIstioSpecValidate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    *IstioSpec
+		wantErr bool
+	}{
+		"nil": {
+			spec: nil,
+		},
+		"valid": {
+			spec: &IstioSpec{
+				ExcludeOutboundPorts:    []int32{443},
+				ExcludeOutboundIPRanges: []string{"10.0.0.0/8"},
+				ExcludeInboundPorts:     []int32{9090},
+			},
+		},
+		"invalid outbound port": {
+			spec:    &IstioSpec{ExcludeOutboundPorts: []int32{0}},
+			wantErr: true,
+		},
+		"invalid inbound port": {
+			spec:    &IstioSpec{ExcludeInboundPorts: []int32{70000}},
+			wantErr: true,
+		},
+		"invalid ip range": {
+			spec:    &IstioSpec{ExcludeOutboundIPRanges: []string{"10.0.0.1"}},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.Background())
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
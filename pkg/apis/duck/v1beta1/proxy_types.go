@@ -0,0 +1,111 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// ProxySpec configures the egress proxy used by data plane pods for Google
+// Cloud API and sink traffic.
+type ProxySpec struct {
+	// HTTPProxy is the proxy URL for plain HTTP requests.
+	// +optional
+	HTTPProxy string `json:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy URL for HTTPS and gRPC requests, including
+	// those to Google Cloud APIs.
+	// +optional
+	HTTPSProxy string `json:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma separated list of hosts, domains and CIDRs that are
+	// reached directly, e.g. ".svc.cluster.local".
+	// +optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// Validate checks that the proxies are absolute http, https or socks5 URLs,
+// and that the CIDRs of NoProxy parse.
+func (p *ProxySpec) Validate(ctx context.Context) *apis.FieldError {
+	if p == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if p.HTTPProxy != "" && !validProxyURL(p.HTTPProxy) {
+		errs = errs.Also(apis.ErrInvalidValue(p.HTTPProxy, "httpProxy"))
+	}
+	if p.HTTPSProxy != "" && !validProxyURL(p.HTTPSProxy) {
+		errs = errs.Also(apis.ErrInvalidValue(p.HTTPSProxy, "httpsProxy"))
+	}
+	for _, host := range strings.Split(p.NoProxy, ",") {
+		host = strings.TrimSpace(host)
+		if !strings.Contains(host, "/") {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(host); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(host, "noProxy"))
+		}
+	}
+	return errs
+}
+
+func validProxyURL(proxy string) bool {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+		return true
+	}
+	return false
+}
+
+// EnvVars returns the standard proxy environment variables for the spec.
+// Fields that aren't set in the spec fall back to defaults, which usually
+// come from the operator configuration of the controller.
+func (p *ProxySpec) EnvVars(defaults ProxySpec) []corev1.EnvVar {
+	merged := defaults
+	if p != nil {
+		if p.HTTPProxy != "" {
+			merged.HTTPProxy = p.HTTPProxy
+		}
+		if p.HTTPSProxy != "" {
+			merged.HTTPSProxy = p.HTTPSProxy
+		}
+		if p.NoProxy != "" {
+			merged.NoProxy = p.NoProxy
+		}
+	}
+	var env []corev1.EnvVar
+	for _, e := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: merged.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: merged.HTTPSProxy},
+		{Name: "NO_PROXY", Value: merged.NoProxy},
+	} {
+		if e.Value != "" {
+			env = append(env, e)
+		}
+	}
+	return env
+}
@@ -33,9 +33,13 @@ func (s *PubSubSpec) SetPubSubDefaults(ctx context.Context) {
 		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
 		return
 	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
 	if s.ServiceAccountName == "" &&
 		(s.Secret == nil || equality.Semantic.DeepEqual(s.Secret, &corev1.SecretKeySelector{})) {
-		s.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
-		s.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
+		s.ServiceAccountName = rd.ServiceAccountName
+		s.Secret = rd.Secret
+	}
+	if s.Project == "" {
+		s.Project = rd.Project
 	}
 }
@@ -20,10 +20,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
 )
 
 func TestPubSubSpec_SetPubSubDefaults(t *testing.T) {
@@ -120,6 +123,49 @@ func TestPubSubSpec_SetPubSubDefaults(t *testing.T) {
 			},
 			ctx: gcpauthtesthelper.ContextWithDefaults(),
 		},
+		"namespace annotations": {
+			orig: &PubSubSpec{},
+			expected: &PubSubSpec{
+				IdentitySpec: IdentitySpec{
+					ServiceAccountName: "team-ksa",
+				},
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "team-key",
+					},
+					Key: "team.json",
+				},
+				Project: "team-project",
+			},
+			ctx: annotatedNamespaceContext(apis.WithinCreate(gcpauthtesthelper.ContextWithDefaults())),
+		},
+		"namespace annotations do not override spec": {
+			orig: &PubSubSpec{
+				IdentitySpec: IdentitySpec{
+					ServiceAccountName: "my-ksa",
+				},
+				Project: "my-project",
+			},
+			expected: &PubSubSpec{
+				IdentitySpec: IdentitySpec{
+					ServiceAccountName: "my-ksa",
+				},
+				Project: "my-project",
+			},
+			ctx: annotatedNamespaceContext(apis.WithinCreate(gcpauthtesthelper.ContextWithDefaults())),
+		},
+		"namespace annotations ignored on update": {
+			orig: &PubSubSpec{},
+			expected: &PubSubSpec{
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: "google-cloud-key",
+					},
+					Key: "key.json",
+				},
+			},
+			ctx: annotatedNamespaceContext(apis.WithinUpdate(gcpauthtesthelper.ContextWithDefaults(), nil)),
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
@@ -130,3 +176,12 @@ func TestPubSubSpec_SetPubSubDefaults(t *testing.T) {
 		})
 	}
 }
+
+func annotatedNamespaceContext(ctx context.Context) context.Context {
+	ctx = apis.WithinParent(ctx, metav1.ObjectMeta{Namespace: "team"})
+	return gcpauthtesthelper.WithNamespaceAnnotations(ctx, "team", map[string]string{
+		gcpauth.ProjectAnnotation:            "team-project",
+		gcpauth.ServiceAccountNameAnnotation: "team-ksa",
+		gcpauth.SecretAnnotation:             "team-key/team.json",
+	})
+}
@@ -90,6 +90,16 @@ type PubSubStatus struct {
 	// SubscriptionID is the created subscription ID.
 	// +optional
 	SubscriptionID string `json:"subscriptionId,omitempty"`
+
+	// TopicName is the fully qualified name of the topic, e.g.
+	// projects/my-project/topics/my-topic.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
+
+	// SubscriptionName is the fully qualified name of the created subscription,
+	// e.g. projects/my-project/subscriptions/my-subscription.
+	// +optional
+	SubscriptionName string `json:"subscriptionName,omitempty"`
 }
 
 const (
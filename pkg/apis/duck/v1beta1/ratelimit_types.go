@@ -0,0 +1,72 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"math"
+
+	"knative.dev/pkg/apis"
+)
+
+// RateLimitSpec limits the rate at which a receive adapter delivers events to
+// its sink, e.g. to protect the sink when a large backlog drains.
+type RateLimitSpec struct {
+	// EventsPerSecond is the sustained number of events delivered per
+	// second. Must be at least 1.
+	EventsPerSecond int32 `json:"eventsPerSecond"`
+
+	// Burst is the number of events that may be delivered at once after a
+	// quiet period, above EventsPerSecond. Defaults to EventsPerSecond.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// GetEventsPerSecond returns the sustained rate of deliveries, 0 if it is not
+// limited.
+func (s *RateLimitSpec) GetEventsPerSecond() int32 {
+	if s == nil {
+		return 0
+	}
+	return s.EventsPerSecond
+}
+
+// GetBurst returns the burst of deliveries, 0 if the rate is not limited.
+func (s *RateLimitSpec) GetBurst() int32 {
+	if s == nil {
+		return 0
+	}
+	if s.Burst != nil {
+		return *s.Burst
+	}
+	return s.EventsPerSecond
+}
+
+// Validate checks that the rate and the burst allow deliveries.
+func (s *RateLimitSpec) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if s.EventsPerSecond < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(s.EventsPerSecond, 1, math.MaxInt32, "eventsPerSecond"))
+	}
+	if s.Burst != nil && *s.Burst < 1 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s.Burst, 1, math.MaxInt32, "burst"))
+	}
+	return errs
+}
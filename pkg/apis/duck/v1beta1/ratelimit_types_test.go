@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestRateLimitSpecGetBurst(t *testing.T) {
+	testCases := map[string]struct {
+		spec *RateLimitSpec
+		want int32
+	}{
+		"nil": {
+			spec: nil,
+			want: 0,
+		},
+		"default": {
+			spec: &RateLimitSpec{EventsPerSecond: 10},
+			want: 10,
+		},
+		"burst": {
+			spec: &RateLimitSpec{EventsPerSecond: 10, Burst: pointer.Int32Ptr(50)},
+			want: 50,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.spec.GetBurst(); got != tc.want {
+				t.Errorf("GetBurst() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitSpecValidate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    *RateLimitSpec
+		wantErr bool
+	}{
+		"nil": {
+			spec: nil,
+		},
+		"rate": {
+			spec: &RateLimitSpec{EventsPerSecond: 10},
+		},
+		"rate and burst": {
+			spec: &RateLimitSpec{EventsPerSecond: 10, Burst: pointer.Int32Ptr(1)},
+		},
+		"no rate": {
+			spec:    &RateLimitSpec{},
+			wantErr: true,
+		},
+		"negative rate": {
+			spec:    &RateLimitSpec{EventsPerSecond: -1},
+			wantErr: true,
+		},
+		"no burst": {
+			spec:    &RateLimitSpec{EventsPerSecond: 10, Burst: pointer.Int32Ptr(0)},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if err := tc.spec.Validate(context.Background()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"math"
+
+	"knative.dev/pkg/apis"
+)
+
+// ShutdownSpec configures how receive adapter pods shut down during rollouts
+// and scale downs.
+type ShutdownSpec struct {
+	// TerminationGracePeriodSeconds is the time the receive adapter is given
+	// to deliver the messages it received once it is asked to stop, before it
+	// is killed. Defaults to the 30 seconds of Kubernetes.
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Drain stops the streaming pull in a preStop hook, before the receive
+	// adapter receives SIGTERM, and waits for the messages it already
+	// received to be delivered and acknowledged. Otherwise, SIGTERM cancels
+	// the deliveries in flight and Pub/Sub redelivers their messages.
+	// +optional
+	Drain bool `json:"drain,omitempty"`
+}
+
+// GetTerminationGracePeriodSeconds returns the termination grace period of
+// the pods, nil for the Kubernetes default.
+func (s *ShutdownSpec) GetTerminationGracePeriodSeconds() *int64 {
+	if s == nil {
+		return nil
+	}
+	return s.TerminationGracePeriodSeconds
+}
+
+// GetDrain returns whether the receive adapter drains before it is stopped.
+func (s *ShutdownSpec) GetDrain() bool {
+	return s != nil && s.Drain
+}
+
+// Validate checks that the termination grace period leaves time to drain.
+func (s *ShutdownSpec) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil || s.TerminationGracePeriodSeconds == nil {
+		return nil
+	}
+	// Draining needs a grace period, as the preStop hook counts against it.
+	var lower int64
+	if s.Drain {
+		lower = 1
+	}
+	if p := *s.TerminationGracePeriodSeconds; p < lower {
+		return apis.ErrOutOfBoundsValue(p, lower, int64(math.MaxInt64), "terminationGracePeriodSeconds")
+	}
+	return nil
+}
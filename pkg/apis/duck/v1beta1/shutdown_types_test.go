@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/utils/pointer"
+)
+
+func TestShutdownSpecValidate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    *ShutdownSpec
+		wantErr bool
+	}{
+		"nil": {
+			spec: nil,
+		},
+		"empty": {
+			spec: &ShutdownSpec{},
+		},
+		"drain with the default grace period": {
+			spec: &ShutdownSpec{Drain: true},
+		},
+		"drain": {
+			spec: &ShutdownSpec{
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(60),
+				Drain:                         true,
+			},
+		},
+		"no grace period": {
+			spec: &ShutdownSpec{TerminationGracePeriodSeconds: pointer.Int64Ptr(0)},
+		},
+		"drain without grace period": {
+			spec: &ShutdownSpec{
+				TerminationGracePeriodSeconds: pointer.Int64Ptr(0),
+				Drain:                         true,
+			},
+			wantErr: true,
+		},
+		"negative grace period": {
+			spec:    &ShutdownSpec{TerminationGracePeriodSeconds: pointer.Int64Ptr(-1)},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if err := tc.spec.Validate(context.Background()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
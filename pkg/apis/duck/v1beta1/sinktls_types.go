@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// SinkTLSSpec configures how receive adapters verify the certificates of
+// HTTPS sinks.
+type SinkTLSSpec struct {
+	// CABundle selects a key of a ConfigMap in the namespace of the
+	// PullSubscription holding PEM encoded CA certificates, which are trusted
+	// in addition to the system ones, e.g. for sinks with certificates signed
+	// by a private CA.
+	// +optional
+	CABundle *corev1.ConfigMapKeySelector `json:"caBundle,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the certificates of the
+	// sink. The connection is then open to man-in-the-middle attacks, it is
+	// only meant for testing.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// GetCABundle returns the CA bundle of the sink, nil if there is none.
+func (s *SinkTLSSpec) GetCABundle() *corev1.ConfigMapKeySelector {
+	if s == nil {
+		return nil
+	}
+	return s.CABundle
+}
+
+// GetInsecureSkipVerify returns whether the certificates of the sink are not
+// verified.
+func (s *SinkTLSSpec) GetInsecureSkipVerify() bool {
+	return s != nil && s.InsecureSkipVerify
+}
+
+// Validate checks that the CA bundle is complete, and that it isn't combined
+// with skipping the verification it is used for.
+func (s *SinkTLSSpec) Validate(ctx context.Context) *apis.FieldError {
+	if s == nil || s.CABundle == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if s.CABundle.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("caBundle.name"))
+	}
+	if s.CABundle.Key == "" {
+		errs = errs.Also(apis.ErrMissingField("caBundle.key"))
+	}
+	if s.InsecureSkipVerify {
+		errs = errs.Also(apis.ErrMultipleOneOf("caBundle", "insecureSkipVerify"))
+	}
+	return errs
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSinkTLSSpecValidate(t *testing.T) {
+	caBundle := &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+		Key:                  "ca.pem",
+	}
+	testCases := map[string]struct {
+		spec    *SinkTLSSpec
+		wantErr bool
+	}{
+		"nil": {
+			spec: nil,
+		},
+		"empty": {
+			spec: &SinkTLSSpec{},
+		},
+		"ca bundle": {
+			spec: &SinkTLSSpec{CABundle: caBundle},
+		},
+		"insecure skip verify": {
+			spec: &SinkTLSSpec{InsecureSkipVerify: true},
+		},
+		"ca bundle without name": {
+			spec:    &SinkTLSSpec{CABundle: &corev1.ConfigMapKeySelector{Key: "ca.pem"}},
+			wantErr: true,
+		},
+		"ca bundle without key": {
+			spec: &SinkTLSSpec{CABundle: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+			}},
+			wantErr: true,
+		},
+		"ca bundle and insecure skip verify": {
+			spec:    &SinkTLSSpec{CABundle: caBundle, InsecureSkipVerify: true},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if err := tc.spec.Validate(context.Background()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -20,8 +20,9 @@ package events
 import "k8s.io/apimachinery/pkg/runtime/schema"
 
 const (
-	GroupName = "events.cloud.google.com"
-	CloudBuildTopic = "cloud-builds"
+	GroupName                  = "events.cloud.google.com"
+	CloudBuildTopic            = "cloud-builds"
+	CloudArtifactRegistryTopic = "gcr"
 )
 
 var (
@@ -50,4 +51,29 @@ var (
 		Group:    GroupName,
 		Resource: "cloudbuildsources",
 	}
+	// CloudBillingBudgetSourcesResource represents a CloudBillingBudgetSource.
+	CloudBillingBudgetSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudbillingbudgetsources",
+	}
+	// CloudArtifactRegistrySourcesResource represents a CloudArtifactRegistrySource.
+	CloudArtifactRegistrySourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudartifactregistrysources",
+	}
+	// SecretManagerRotationSourcesResource represents a SecretManagerRotationSource.
+	SecretManagerRotationSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "secretmanagerrotationsources",
+	}
+	// CloudMonitoringAlertSourcesResource represents a CloudMonitoringAlertSource.
+	CloudMonitoringAlertSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "cloudmonitoringalertsources",
+	}
+	// WebhookSourcesResource represents a WebhookSource.
+	WebhookSourcesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "webhooksources",
+	}
 )
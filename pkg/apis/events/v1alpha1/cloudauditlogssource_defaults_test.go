@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -46,7 +47,8 @@ func TestCloudAuditLogsSource_SetDefaults(t *testing.T) {
 			expected: &CloudAuditLogsSource{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: CloudAuditLogsSourceSpec{
@@ -82,7 +84,8 @@ func TestCloudAuditLogsSource_SetDefaults(t *testing.T) {
 			expected: &CloudAuditLogsSource{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: CloudAuditLogsSourceSpec{
@@ -34,7 +34,8 @@ const (
 func (bs *CloudBuildSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, bs.ObjectMeta)
 	bs.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&bs.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &bs.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &bs.ObjectMeta, SchemeGroupVersion)
 	duckv1alpha1.SetAutoscalingAnnotationsDefaults(ctx, &bs.ObjectMeta)
 }
 
@@ -25,6 +25,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -60,7 +61,8 @@ func TestBuildSourceDefaults(t *testing.T) {
 		want: &CloudBuildSource{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: CloudBuildSourceSpec{
@@ -89,7 +91,8 @@ func TestBuildSourceDefaults(t *testing.T) {
 		want: &CloudBuildSource{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: CloudBuildSourceSpec{
@@ -117,7 +120,8 @@ func TestCloudBuildSourceDefaults_NoChange(t *testing.T) {
 	want := &CloudBuildSource{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
-				duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+				duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 			},
 		},
 		Spec: CloudBuildSourceSpec{
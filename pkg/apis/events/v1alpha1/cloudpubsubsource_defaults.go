@@ -36,7 +36,8 @@ const (
 func (ps *CloudPubSubSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, ps.ObjectMeta)
 	ps.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&ps.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &ps.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &ps.ObjectMeta, SchemeGroupVersion)
 	duckv1alpha1.SetAutoscalingAnnotationsDefaults(ctx, &ps.ObjectMeta)
 }
 
@@ -26,6 +26,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -65,7 +66,8 @@ func TestCloudPubSubSourceDefaults(t *testing.T) {
 		want: &CloudPubSubSource{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: CloudPubSubSourceSpec{
@@ -95,7 +97,8 @@ func TestCloudPubSubSourceDefaults(t *testing.T) {
 		want: &CloudPubSubSource{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: CloudPubSubSourceSpec{
@@ -126,7 +129,8 @@ func TestCloudPubSubSourceDefaults_NoChange(t *testing.T) {
 	want := &CloudPubSubSource{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
-				duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+				duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 			},
 		},
 		Spec: CloudPubSubSourceSpec{
@@ -23,6 +23,7 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	"github.com/google/knative-gcp/pkg/utils"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -49,6 +50,8 @@ func (current *CloudPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldE
 	// Topic [required]
 	if current.Topic == "" {
 		errs = errs.Also(apis.ErrMissingField("topic"))
+	} else if _, _, err := utils.ParseTopic(current.Topic); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(current.Topic, "topic"))
 	}
 	// Sink [required]
 	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
+	"knative.dev/pkg/ptr"
 )
 
 // These variables are used to create a 'complete' version of CloudSchedulerSource where every field is
@@ -41,6 +42,11 @@ var (
 			Location:   "location",
 			Schedule:   "schedule",
 			Data:       "data",
+			TimeZone:   "America/New_York",
+			RetryConfig: &SchedulerRetryConfig{
+				RetryCount: 3,
+				MaxBackoff: ptr.String("10m"),
+			},
 		},
 		Status: CloudSchedulerSourceStatus{
 			PubSubStatus: completePubSubStatus,
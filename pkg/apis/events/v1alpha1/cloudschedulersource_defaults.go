@@ -28,6 +28,7 @@ import (
 func (s *CloudSchedulerSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetPubSubDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &s.ObjectMeta, SchemeGroupVersion)
 	duckv1alpha1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -47,7 +48,8 @@ func TestCloudSchedulerSource_SetDefaults(t *testing.T) {
 			expected: &CloudSchedulerSource{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: CloudSchedulerSourceSpec{
@@ -83,7 +85,8 @@ func TestCloudSchedulerSource_SetDefaults(t *testing.T) {
 			expected: &CloudSchedulerSource{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: CloudSchedulerSourceSpec{
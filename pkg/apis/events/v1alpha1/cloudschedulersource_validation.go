@@ -18,6 +18,8 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -29,6 +31,15 @@ import (
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
 )
 
+const (
+	minRetryCount = 0
+	maxRetryCount = 5
+
+	// minMaxBackoff is the default minimum backoff of Scheduler Jobs, which
+	// the maximum backoff can't be lower than.
+	minMaxBackoff = 5 * time.Second
+)
+
 func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
 	return duckv1alpha1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
@@ -59,6 +70,10 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(apis.ErrMissingField("data"))
 	}
 
+	if current.RetryConfig != nil {
+		errs = errs.Also(current.RetryConfig.Validate(ctx).ViaField("retryConfig"))
+	}
+
 	if err := duckv1alpha1.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
 		errs = errs.Also(err)
 	}
@@ -66,6 +81,29 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 	return errs
 }
 
+func (current *SchedulerRetryConfig) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if current.RetryCount < minRetryCount || current.RetryCount > maxRetryCount {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(current.RetryCount, minRetryCount, maxRetryCount, "retryCount"))
+	}
+
+	if current.MaxBackoff != nil {
+		// If set, MaxBackoff needs to parse to a duration of at least the
+		// minimum backoff of the Scheduler Job.
+		mb, err := time.ParseDuration(*current.MaxBackoff)
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*current.MaxBackoff, "maxBackoff"))
+		} else if mb < minMaxBackoff {
+			fe := apis.ErrInvalidValue(*current.MaxBackoff, "maxBackoff")
+			fe.Details = fmt.Sprintf("must be at least %v", minMaxBackoff)
+			errs = errs.Also(fe)
+		}
+	}
+
+	return errs
+}
+
 func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, original *CloudSchedulerSource) *apis.FieldError {
 	if original == nil {
 		return nil
@@ -34,11 +34,25 @@ func (source *CloudStorageSource) ConvertTo(_ context.Context, to apis.Convertib
 		sink.Spec.PubSubSpec = convert.ToV1beta1PubSubSpec(source.Spec.PubSubSpec)
 		sink.Spec.ServiceAccountName = source.Spec.ServiceAccountName
 		sink.Spec.Bucket = source.Spec.Bucket
+		sink.Spec.Buckets = source.Spec.Buckets
+		if bs := source.Spec.BucketSelector; bs != nil {
+			sink.Spec.BucketSelector = &v1beta1.BucketSelector{
+				Include:     bs.Include,
+				Exclude:     bs.Exclude,
+				MatchLabels: bs.MatchLabels,
+			}
+		}
 		sink.Spec.EventTypes = source.Spec.EventTypes
 		sink.Spec.ObjectNamePrefix = source.Spec.ObjectNamePrefix
 		sink.Spec.PayloadFormat = source.Spec.PayloadFormat
 		sink.Status.PubSubStatus = convert.ToV1beta1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.NotificationID = source.Status.NotificationID
+		for _, n := range source.Status.Buckets {
+			sink.Status.Buckets = append(sink.Status.Buckets, v1beta1.BucketNotification{
+				Bucket:         n.Bucket,
+				NotificationID: n.NotificationID,
+			})
+		}
 		return nil
 	default:
 		return fmt.Errorf("unknown conversion, got: %T", sink)
@@ -55,11 +69,25 @@ func (sink *CloudStorageSource) ConvertFrom(_ context.Context, from apis.Convert
 		sink.Spec.PubSubSpec = convert.FromV1beta1PubSubSpec(source.Spec.PubSubSpec)
 		sink.Spec.ServiceAccountName = source.Spec.ServiceAccountName
 		sink.Spec.Bucket = source.Spec.Bucket
+		sink.Spec.Buckets = source.Spec.Buckets
+		if bs := source.Spec.BucketSelector; bs != nil {
+			sink.Spec.BucketSelector = &BucketSelector{
+				Include:     bs.Include,
+				Exclude:     bs.Exclude,
+				MatchLabels: bs.MatchLabels,
+			}
+		}
 		sink.Spec.EventTypes = source.Spec.EventTypes
 		sink.Spec.ObjectNamePrefix = source.Spec.ObjectNamePrefix
 		sink.Spec.PayloadFormat = source.Spec.PayloadFormat
 		sink.Status.PubSubStatus = convert.FromV1beta1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.NotificationID = source.Status.NotificationID
+		for _, n := range source.Status.Buckets {
+			sink.Status.Buckets = append(sink.Status.Buckets, BucketNotification{
+				Bucket:         n.Bucket,
+				NotificationID: n.NotificationID,
+			})
+		}
 		return nil
 	default:
 		return fmt.Errorf("unknown conversion, got: %T", source)
@@ -37,8 +37,14 @@ var (
 	completeCloudStorageSource = &CloudStorageSource{
 		ObjectMeta: completeObjectMeta,
 		Spec: CloudStorageSourceSpec{
-			PubSubSpec:       completePubSubSpec,
-			Bucket:           "bucket",
+			PubSubSpec: completePubSubSpec,
+			Bucket:     "bucket",
+			Buckets:    []string{"some", "buckets"},
+			BucketSelector: &BucketSelector{
+				Include:     []string{"include-*"},
+				Exclude:     []string{"exclude-*"},
+				MatchLabels: map[string]string{"label": "value"},
+			},
 			EventTypes:       []string{"event", "types"},
 			ObjectNamePrefix: "objectNamePrefix",
 			PayloadFormat:    "payloadFormat",
@@ -46,6 +52,10 @@ var (
 		Status: CloudStorageSourceStatus{
 			PubSubStatus:   completePubSubStatus,
 			NotificationID: "notificationId",
+			Buckets: []BucketNotification{{
+				Bucket:         "bucket",
+				NotificationID: "notificationId",
+			}},
 		},
 	}
 )
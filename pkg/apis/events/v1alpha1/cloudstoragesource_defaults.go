@@ -30,7 +30,8 @@ var allEventTypes = []string{CloudStorageSourceFinalize, CloudStorageSourceDelet
 func (s *CloudStorageSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &s.ObjectMeta, SchemeGroupVersion)
 	duckv1alpha1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
 
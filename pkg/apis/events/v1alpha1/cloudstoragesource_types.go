@@ -62,8 +62,21 @@ type CloudStorageSourceSpec struct {
 	// Sink, CloudEventOverrides, Secret, PubSubSecret, and Project
 	duckv1alpha1.PubSubSpec `json:",inline"`
 
-	// Bucket to subscribe to.
-	Bucket string `json:"bucket"`
+	// Bucket to subscribe to. Exactly one of Bucket, Buckets and
+	// BucketSelector must be set.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Buckets to subscribe to. A notification is added to each of them.
+	// +optional
+	Buckets []string `json:"buckets,omitempty"`
+
+	// BucketSelector selects the buckets of the project to subscribe to.
+	// The buckets are listed every time the source is reconciled, so that
+	// new buckets matching the selector are subscribed to and buckets that
+	// no longer match are unsubscribed from.
+	// +optional
+	BucketSelector *BucketSelector `json:"bucketSelector,omitempty"`
 
 	// EventTypes to subscribe to. If unspecified, then subscribe to all events.
 	// +optional
@@ -79,6 +92,23 @@ type CloudStorageSourceSpec struct {
 	PayloadFormat string `json:"payloadFormat,omitempty"`
 }
 
+// BucketSelector selects buckets of a project by name and labels.
+type BucketSelector struct {
+	// Include are glob patterns, e.g. 'logs-*', of the names of the buckets
+	// to subscribe to. All the buckets of the project are included if empty.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are glob patterns of the names of the buckets not to subscribe
+	// to, even if they are included.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// MatchLabels restricts the buckets to the ones having all these labels.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
 const (
 	// CloudEvent types used by CloudStorageSource.
 	CloudStorageSourceFinalize       = "com.google.cloud.storage.object.finalize"
@@ -117,6 +147,20 @@ type CloudStorageSourceStatus struct {
 	// NotificationID is the ID that GCS identifies this notification as.
 	// +optional
 	NotificationID string `json:"notificationId,omitempty"`
+
+	// Buckets are the notifications of the buckets subscribed to when the
+	// source uses Buckets or BucketSelector.
+	// +optional
+	Buckets []BucketNotification `json:"buckets,omitempty"`
+}
+
+// BucketNotification is the notification added to a bucket.
+type BucketNotification struct {
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// NotificationID is the ID that GCS identifies the notification as.
+	NotificationID string `json:"notificationId"`
 }
 
 func (storage *CloudStorageSource) GetGroupVersionKind() schema.GroupVersionKind {
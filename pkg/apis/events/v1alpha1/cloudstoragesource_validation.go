@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"path"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/pkg/apis"
@@ -44,9 +45,34 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 		errs = errs.Also(err.ViaField("sink"))
 	}
 
-	// Bucket [required]
-	if current.Bucket == "" {
-		errs = errs.Also(apis.ErrMissingField("bucket"))
+	// Exactly one of Bucket, Buckets and BucketSelector [required]
+	var set []string
+	if current.Bucket != "" {
+		set = append(set, "bucket")
+	}
+	if len(current.Buckets) > 0 {
+		set = append(set, "buckets")
+	}
+	if current.BucketSelector != nil {
+		set = append(set, "bucketSelector")
+	}
+	switch len(set) {
+	case 0:
+		errs = errs.Also(apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector"))
+	case 1:
+	default:
+		errs = errs.Also(apis.ErrMultipleOneOf(set...))
+	}
+
+	seen := make(map[string]bool, len(current.Buckets))
+	for i, bucket := range current.Buckets {
+		if bucket == "" || seen[bucket] {
+			errs = errs.Also(apis.ErrInvalidArrayValue(bucket, "buckets", i))
+		}
+		seen[bucket] = true
+	}
+	if current.BucketSelector != nil {
+		errs = errs.Also(current.BucketSelector.Validate(ctx).ViaField("bucketSelector"))
 	}
 
 	if err := duckv1alpha1.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
@@ -56,6 +82,20 @@ func (current *CloudStorageSourceSpec) Validate(ctx context.Context) *apis.Field
 	return errs
 }
 
+func (current *BucketSelector) Validate(ctx context.Context) *apis.FieldError {
+	return validatePatterns(current.Include, "include").Also(validatePatterns(current.Exclude, "exclude"))
+}
+
+func validatePatterns(patterns []string, field string) *apis.FieldError {
+	var errs *apis.FieldError
+	for i, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+			errs = errs.Also(apis.ErrInvalidArrayValue(pattern, field, i))
+		}
+	}
+	return errs
+}
+
 func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, original *CloudStorageSource) *apis.FieldError {
 	if original == nil {
 		return nil
@@ -64,7 +104,7 @@ func (current *CloudStorageSource) CheckImmutableFields(ctx context.Context, ori
 	// Modification of EventType, Secret, ServiceAccount, Project, Bucket, ObjectNamePrefix and PayloadFormat are not allowed. Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(CloudStorageSourceSpec{},
-			"Sink", "CloudEventOverrides", "ServiceAccountName")); diff != "" {
+			"Sink", "CloudEventOverrides", "ServiceAccountName", "Buckets", "BucketSelector")); diff != "" {
 		errs = errs.Also(&apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
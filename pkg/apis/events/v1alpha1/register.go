@@ -55,6 +55,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CloudPubSubSourceList{},
 		&CloudBuildSource{},
 		&CloudBuildSourceList{},
+		&SourceSet{},
+		&SourceSetList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
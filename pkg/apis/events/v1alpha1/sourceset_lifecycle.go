@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+var sourceSetCondSet = apis.NewLivingConditionSet(
+	SourceSetConditionSourcesReady,
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *SourceSetStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return sourceSetCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (s *SourceSetStatus) GetTopLevelCondition() *apis.Condition {
+	return sourceSetCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *SourceSetStatus) IsReady() bool {
+	return sourceSetCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *SourceSetStatus) InitializeConditions() {
+	sourceSetCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSourcesReady records the number of sources and marks them ready.
+func (s *SourceSetStatus) MarkSourcesReady(sources int32) {
+	s.Sources = sources
+	s.ReadySources = sources
+	sourceSetCondSet.Manage(s).MarkTrue(SourceSetConditionSourcesReady)
+}
+
+// MarkSourcesNotReady records the number of sources and how many of them are
+// ready, and marks the sources as not ready yet.
+func (s *SourceSetStatus) MarkSourcesNotReady(sources, ready int32) {
+	s.Sources = sources
+	s.ReadySources = ready
+	sourceSetCondSet.Manage(s).MarkUnknown(SourceSetConditionSourcesReady, "SourcesNotReady",
+		"%d of %d sources are ready", ready, sources)
+}
+
+// MarkSourcesFailed marks the sources as failed with the given reason and
+// message.
+func (s *SourceSetStatus) MarkSourcesFailed(reason, messageFormat string, messageA ...interface{}) {
+	sourceSetCondSet.Manage(s).MarkFalse(SourceSetConditionSourcesReady, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSourceSetStatusLifecycle(t *testing.T) {
+	s := &SourceSetStatus{}
+	s.InitializeConditions()
+	if got := s.GetTopLevelCondition().Status; got != corev1.ConditionUnknown {
+		t.Errorf("after InitializeConditions, Ready = %v, want Unknown", got)
+	}
+
+	s.MarkSourcesNotReady(3, 1)
+	if s.IsReady() {
+		t.Error("IsReady() = true with 1 of 3 sources ready")
+	}
+	if s.Sources != 3 || s.ReadySources != 1 {
+		t.Errorf("Sources, ReadySources = %d, %d, want 3, 1", s.Sources, s.ReadySources)
+	}
+
+	s.MarkSourcesReady(3)
+	if !s.IsReady() {
+		t.Error("IsReady() = false with all sources ready")
+	}
+	if s.ReadySources != 3 {
+		t.Errorf("ReadySources = %d, want 3", s.ReadySources)
+	}
+
+	s.MarkSourcesFailed("Failed", "boom")
+	if got := s.GetCondition(SourceSetConditionSourcesReady).Status; got != corev1.ConditionFalse {
+		t.Errorf("SourcesReady = %v, want False", got)
+	}
+	if s.IsReady() {
+		t.Error("IsReady() = true after MarkSourcesFailed")
+	}
+}
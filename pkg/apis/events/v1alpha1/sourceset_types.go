@@ -0,0 +1,141 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceSet stamps out one CloudPubSubSource per topic in every namespace
+// matching a label selector, and keeps the set of sources in sync as topics
+// and namespaces are added or removed.
+type SourceSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SourceSetSpec   `json:"spec,omitempty"`
+	Status SourceSetStatus `json:"status,omitempty"`
+}
+
+// Verify that SourceSet matches various duck types.
+var (
+	_ apis.Defaultable   = (*SourceSet)(nil)
+	_ apis.Validatable   = (*SourceSet)(nil)
+	_ apis.HasSpec       = (*SourceSet)(nil)
+	_ runtime.Object     = (*SourceSet)(nil)
+	_ kmeta.OwnerRefable = (*SourceSet)(nil)
+)
+
+// SourceSetSpec defines the desired state of the SourceSet.
+type SourceSetSpec struct {
+	// NamespaceSelector selects the namespaces in which sources are created.
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector"`
+
+	// Topics are the IDs of the Pub/Sub topics to create a source for, in
+	// each selected namespace.
+	Topics []string `json:"topics"`
+
+	// Template is used to create each CloudPubSubSource. Its topic is set
+	// from Topics, and a sink reference is resolved in the namespace of the
+	// source, so neither may be set in the template.
+	Template SourceTemplate `json:"template"`
+}
+
+// SourceTemplate describes the CloudPubSubSources a SourceSet creates.
+type SourceTemplate struct {
+	// Labels are added to each source, in addition to the labels
+	// identifying the SourceSet.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to each source.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Spec is the spec of each source, without its topic.
+	Spec v1beta1.CloudPubSubSourceSpec `json:"spec"`
+}
+
+const (
+	// SourceSetConditionReady has status True when all of the sources of the
+	// SourceSet are ready.
+	SourceSetConditionReady = apis.ConditionReady
+
+	// SourceSetConditionSourcesReady has status True when every source of
+	// the SourceSet has been created and is ready.
+	SourceSetConditionSourcesReady apis.ConditionType = "SourcesReady"
+
+	// SourceSetLabelKey is the label set on every source created by a
+	// SourceSet, with the name of the SourceSet as value.
+	SourceSetLabelKey = "events.cloud.google.com/sourceset"
+
+	// SourceSetTopicAnnotationKey is the annotation recording the topic a
+	// source was created for.
+	SourceSetTopicAnnotationKey = "events.cloud.google.com/sourceset-topic"
+)
+
+// SourceSetStatus defines the observed state of the SourceSet.
+type SourceSetStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+
+	// Sources is the number of sources managed by the SourceSet.
+	// +optional
+	Sources int32 `json:"sources,omitempty"`
+
+	// ReadySources is the number of managed sources that are ready.
+	// +optional
+	ReadySources int32 `json:"readySources,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SourceSetList contains a list of SourceSets.
+type SourceSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SourceSet `json:"items"`
+}
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (s *SourceSet) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("SourceSet")
+}
+
+// GetUntypedSpec returns the spec of the SourceSet.
+func (s *SourceSet) GetUntypedSpec() interface{} {
+	return s.Spec
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object.
+func (s *SourceSet) ConditionSet() *apis.ConditionSet {
+	return &sourceSetCondSet
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestSourceSet_GetGroupVersionKind(t *testing.T) {
+	want := schema.GroupVersionKind{
+		Group:   "events.cloud.google.com",
+		Version: "v1alpha1",
+		Kind:    "SourceSet",
+	}
+	s := SourceSet{}
+	if diff := cmp.Diff(want, s.GetGroupVersionKind()); diff != "" {
+		t.Errorf("GetGroupVersionKind (-want +got): %v", diff)
+	}
+}
+
+func TestSourceSet_GetUntypedSpec(t *testing.T) {
+	s := SourceSet{}
+	if _, ok := s.GetUntypedSpec().(SourceSetSpec); !ok {
+		t.Errorf("untyped spec was not a SourceSetSpec")
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+// Validate verifies that the SourceSet is valid.
+func (s *SourceSet) Validate(ctx context.Context) *apis.FieldError {
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate verifies that the SourceSetSpec is valid.
+func (ss *SourceSetSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// NamespaceSelector [required]
+	if ss.NamespaceSelector == nil {
+		errs = errs.Also(apis.ErrMissingField("namespaceSelector"))
+	} else if _, err := metav1.LabelSelectorAsSelector(ss.NamespaceSelector); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "namespaceSelector"))
+	}
+
+	// Topics [required]
+	if len(ss.Topics) == 0 {
+		errs = errs.Also(apis.ErrMissingField("topics"))
+	}
+	seen := make(map[string]bool, len(ss.Topics))
+	for i, topic := range ss.Topics {
+		if topic == "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(topic, "topics", i))
+		} else if seen[topic] {
+			errs = errs.Also(apis.ErrGeneric(fmt.Sprintf("duplicate topic %q", topic), apis.CurrentField).ViaFieldIndex("topics", i))
+		}
+		seen[topic] = true
+	}
+
+	errs = errs.Also(ss.Template.Validate(ctx).ViaField("template"))
+	return errs
+}
+
+// Validate verifies that the SourceTemplate is valid.
+func (st *SourceTemplate) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if st.Spec.Topic != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("topic").ViaField("spec"))
+	}
+	if st.Spec.Sink.Ref != nil && st.Spec.Sink.Ref.Namespace != "" {
+		errs = errs.Also(apis.ErrDisallowedFields("namespace").ViaField("spec", "sink", "ref"))
+	}
+	// Validate the rest of the spec as the sources will see it.
+	spec := st.Spec.DeepCopy()
+	spec.Topic = "placeholder"
+	errs = errs.Also(spec.Validate(ctx).ViaField("spec"))
+	return errs
+}
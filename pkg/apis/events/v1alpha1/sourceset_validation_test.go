@@ -0,0 +1,141 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+func validSourceSetSpec() SourceSetSpec {
+	return SourceSetSpec{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "payments"},
+		},
+		Topics: []string{"orders", "refunds"},
+		Template: SourceTemplate{
+			Spec: v1beta1.CloudPubSubSourceSpec{
+				PubSubSpec: duckv1beta1.PubSubSpec{
+					SourceSpec: duckv1.SourceSpec{
+						Sink: duckv1.Destination{
+							Ref: &duckv1.KReference{
+								APIVersion: "eventing.knative.dev/v1beta1",
+								Kind:       "Broker",
+								Name:       "default",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestSourceSetValidate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    func() SourceSetSpec
+		wantErr bool
+	}{
+		"valid": {
+			spec: validSourceSetSpec,
+		},
+		"missing namespace selector": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.NamespaceSelector = nil
+				return s
+			},
+			wantErr: true,
+		},
+		"invalid namespace selector": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.NamespaceSelector = &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{
+						Key:      "team",
+						Operator: "Bogus",
+					}},
+				}
+				return s
+			},
+			wantErr: true,
+		},
+		"no topics": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Topics = nil
+				return s
+			},
+			wantErr: true,
+		},
+		"empty topic": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Topics = []string{"orders", ""}
+				return s
+			},
+			wantErr: true,
+		},
+		"duplicate topic": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Topics = []string{"orders", "orders"}
+				return s
+			},
+			wantErr: true,
+		},
+		"topic in template": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Template.Spec.Topic = "orders"
+				return s
+			},
+			wantErr: true,
+		},
+		"sink namespace in template": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Template.Spec.Sink.Ref.Namespace = "other"
+				return s
+			},
+			wantErr: true,
+		},
+		"missing sink": {
+			spec: func() SourceSetSpec {
+				s := validSourceSetSpec()
+				s.Template.Spec.Sink = duckv1.Destination{}
+				return s
+			},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ss := &SourceSet{Spec: tc.spec()}
+			err := ss.Validate(context.Background())
+			if got := err != nil; got != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
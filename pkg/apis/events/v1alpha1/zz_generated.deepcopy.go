@@ -21,9 +21,59 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotification) DeepCopyInto(out *BucketNotification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotification.
+func (in *BucketNotification) DeepCopy() *BucketNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketSelector) DeepCopyInto(out *BucketSelector) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketSelector.
+func (in *BucketSelector) DeepCopy() *BucketSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudAuditLogsSource) DeepCopyInto(out *CloudAuditLogsSource) {
 	*out = *in
@@ -389,6 +439,11 @@ func (in *CloudSchedulerSourceList) DeepCopyObject() runtime.Object {
 func (in *CloudSchedulerSourceSpec) DeepCopyInto(out *CloudSchedulerSourceSpec) {
 	*out = *in
 	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.RetryConfig != nil {
+		in, out := &in.RetryConfig, &out.RetryConfig
+		*out = new(SchedulerRetryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -484,6 +539,16 @@ func (in *CloudStorageSourceList) DeepCopyObject() runtime.Object {
 func (in *CloudStorageSourceSpec) DeepCopyInto(out *CloudStorageSourceSpec) {
 	*out = *in
 	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BucketSelector != nil {
+		in, out := &in.BucketSelector, &out.BucketSelector
+		*out = new(BucketSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EventTypes != nil {
 		in, out := &in.EventTypes, &out.EventTypes
 		*out = make([]string, len(*in))
@@ -506,6 +571,11 @@ func (in *CloudStorageSourceSpec) DeepCopy() *CloudStorageSourceSpec {
 func (in *CloudStorageSourceStatus) DeepCopyInto(out *CloudStorageSourceStatus) {
 	*out = *in
 	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]BucketNotification, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -518,3 +588,160 @@ func (in *CloudStorageSourceStatus) DeepCopy() *CloudStorageSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerRetryConfig) DeepCopyInto(out *SchedulerRetryConfig) {
+	*out = *in
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerRetryConfig.
+func (in *SchedulerRetryConfig) DeepCopy() *SchedulerRetryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerRetryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSet) DeepCopyInto(out *SourceSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSet.
+func (in *SourceSet) DeepCopy() *SourceSet {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSetList) DeepCopyInto(out *SourceSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SourceSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSetList.
+func (in *SourceSetList) DeepCopy() *SourceSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SourceSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSetSpec) DeepCopyInto(out *SourceSetSpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSetSpec.
+func (in *SourceSetSpec) DeepCopy() *SourceSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSetStatus) DeepCopyInto(out *SourceSetStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSetStatus.
+func (in *SourceSetStatus) DeepCopy() *SourceSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTemplate) DeepCopyInto(out *SourceTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTemplate.
+func (in *SourceTemplate) DeepCopy() *SourceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -1,7 +1,5 @@
-// +build wireinject
-
 /*
-Copyright 2020 Google LLC.
+Copyright 2020 Google LLC
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -16,27 +14,21 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package v1beta1
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/google/knative-gcp/pkg/broker/config/volume"
-	"github.com/google/knative-gcp/pkg/broker/ingress"
-	"github.com/google/knative-gcp/pkg/metrics"
-	"github.com/google/wire"
+	"knative.dev/pkg/apis"
 )
 
-func InitializeHandler(
-	ctx context.Context,
-	port ingress.Port,
-	projectID ingress.ProjectID,
-	podName metrics.PodName,
-	containerName metrics.ContainerName,
-) (*ingress.Handler, error) {
-	panic(wire.Build(
-		ingress.HandlerSet,
-		wire.Value([]volume.Option(nil)),
-		volume.NewTargetsFromFile,
-	))
+// ConvertTo implements apis.Convertible.
+func (*CloudArtifactRegistrySource) ConvertTo(_ context.Context, to apis.Convertible) error {
+	return fmt.Errorf("v1beta1 is the highest known version, got: %T", to)
+}
+
+// ConvertFrom implements apis.Convertible.
+func (*CloudArtifactRegistrySource) ConvertFrom(_ context.Context, from apis.Convertible) error {
+	return fmt.Errorf("v1beta1 is the highest known version, got: %T", from)
 }
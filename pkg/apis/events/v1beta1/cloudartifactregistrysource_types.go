@@ -0,0 +1,142 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudArtifactRegistrySource is a specification for a
+// CloudArtifactRegistrySource resource. It converts the notifications Artifact
+// Registry and Container Registry publish to the gcr Pub/Sub topic when an
+// image is pushed, tagged or deleted into CloudEvents.
+type CloudArtifactRegistrySource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudArtifactRegistrySourceSpec   `json:"spec"`
+	Status CloudArtifactRegistrySourceStatus `json:"status"`
+}
+
+// Verify that CloudArtifactRegistrySource matches various duck types.
+var (
+	_ apis.Convertible             = (*CloudArtifactRegistrySource)(nil)
+	_ apis.Defaultable             = (*CloudArtifactRegistrySource)(nil)
+	_ apis.Validatable             = (*CloudArtifactRegistrySource)(nil)
+	_ runtime.Object               = (*CloudArtifactRegistrySource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudArtifactRegistrySource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudArtifactRegistrySource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudArtifactRegistrySource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudArtifactRegistrySource)(nil)
+)
+
+const (
+	// CloudEvent types used by CloudArtifactRegistrySource.
+	CloudArtifactRegistrySourceImagePushed  = "google.cloud.artifactregistry.image.v1.pushed"
+	CloudArtifactRegistrySourceImageDeleted = "google.cloud.artifactregistry.image.v1.deleted"
+
+	// CloudArtifactRegistrySourceRepository is the CloudEvent extension with the image repository,
+	// e.g. us-docker.pkg.dev/my-project/my-repo/my-image.
+	CloudArtifactRegistrySourceRepository = "repository"
+	// CloudArtifactRegistrySourceTag is the CloudEvent extension with the image tag, e.g. latest.
+	CloudArtifactRegistrySourceTag = "tag"
+	// CloudArtifactRegistrySourceDigest is the CloudEvent extension with the image digest, e.g. sha256:...
+	CloudArtifactRegistrySourceDigest = "digest"
+)
+
+// CloudArtifactRegistrySourceEventSource returns the Artifact Registry CloudEvent source value.
+func CloudArtifactRegistrySourceEventSource(googleCloudProject string) string {
+	return fmt.Sprintf("//artifactregistry.googleapis.com/projects/%s", googleCloudProject)
+}
+
+// CloudArtifactRegistrySourceSpec is the spec for a CloudArtifactRegistrySource resource.
+type CloudArtifactRegistrySourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret, and Project
+	duckv1beta1.PubSubSpec `json:",inline"`
+}
+
+const (
+	// CloudArtifactRegistrySourceConditionReady has status True when the
+	// CloudArtifactRegistrySource is ready to send events.
+	CloudArtifactRegistrySourceConditionReady = apis.ConditionReady
+)
+
+var artifactRegistryCondSet = apis.NewLivingConditionSet(
+	duckv1beta1.PullSubscriptionReady,
+)
+
+// CloudArtifactRegistrySourceStatus is the status for a CloudArtifactRegistrySource resource.
+type CloudArtifactRegistrySourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1beta1 Status, SinkURI, ProjectID, TopicID, and SubscriptionID
+	duckv1beta1.PubSubStatus `json:",inline"`
+}
+
+func (*CloudArtifactRegistrySource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudArtifactRegistrySource")
+}
+
+// Methods for identifiable interface
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudArtifactRegistrySource) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudArtifactRegistrySource) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *CloudArtifactRegistrySource) ConditionSet() *apis.ConditionSet {
+	return &artifactRegistryCondSet
+}
+
+// Methods for pubsubable interface
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudArtifactRegistrySource) PubSubSpec() *duckv1beta1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudArtifactRegistrySource) PubSubStatus() *duckv1beta1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudArtifactRegistrySourceList is a list of CloudArtifactRegistrySource resources
+type CloudArtifactRegistrySourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CloudArtifactRegistrySource `json:"items"`
+}
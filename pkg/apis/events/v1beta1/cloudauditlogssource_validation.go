@@ -28,7 +28,9 @@ import (
 )
 
 func (current *CloudAuditLogsSource) Validate(ctx context.Context) *apis.FieldError {
-	return current.Spec.Validate(ctx).ViaField("spec")
+	errs := current.Spec.Validate(ctx).ViaField("spec")
+	errs = duckv1beta1.ValidateMetricsAnnotations(ctx, current.Annotations, errs)
+	return duckv1beta1.ValidateReceiveAdapterModeAnnotation(ctx, current.Annotations, errs)
 }
 
 func (current *CloudAuditLogsSourceSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -72,5 +74,6 @@ func (current *CloudAuditLogsSource) CheckImmutableFields(ctx context.Context, o
 			Details: diff,
 		}
 	}
-	return nil
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
 }
@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudBillingBudgetSource is a specification for a CloudBillingBudgetSource
+// resource. It converts the notifications Cloud Billing budgets publish to a
+// Pub/Sub topic into CloudEvents.
+type CloudBillingBudgetSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudBillingBudgetSourceSpec   `json:"spec"`
+	Status CloudBillingBudgetSourceStatus `json:"status"`
+}
+
+// Verify that CloudBillingBudgetSource matches various duck types.
+var (
+	_ apis.Convertible             = (*CloudBillingBudgetSource)(nil)
+	_ apis.Defaultable             = (*CloudBillingBudgetSource)(nil)
+	_ apis.Validatable             = (*CloudBillingBudgetSource)(nil)
+	_ runtime.Object               = (*CloudBillingBudgetSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudBillingBudgetSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudBillingBudgetSource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudBillingBudgetSource)(nil)
+)
+
+const (
+	// CloudEvent types used by CloudBillingBudgetSource.
+	CloudBillingBudgetSourceThresholdExceeded = "google.cloud.billing.budget.v1.thresholdExceeded"
+	CloudBillingBudgetSourceUpdated           = "google.cloud.billing.budget.v1.updated"
+
+	// CloudBillingBudgetSourceBillingAccountID is the Pub/Sub message attribute key with the billing account of the budget.
+	CloudBillingBudgetSourceBillingAccountID = "billingAccountId"
+	// CloudBillingBudgetSourceBudgetID is the Pub/Sub message attribute key with the ID of the budget.
+	CloudBillingBudgetSourceBudgetID = "budgetId"
+)
+
+// CloudBillingBudgetSourceEventSource returns the Cloud Billing CloudEvent source value.
+func CloudBillingBudgetSourceEventSource(billingAccount, budget string) string {
+	return fmt.Sprintf("//cloudbilling.googleapis.com/billingAccounts/%s/budgets/%s", billingAccount, budget)
+}
+
+// CloudBillingBudgetSourceSpec is the spec for a CloudBillingBudgetSource resource.
+type CloudBillingBudgetSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret, and Project
+	duckv1beta1.PubSubSpec `json:",inline"`
+
+	// Topic is the ID of the Pub/Sub topic the budgets publish their
+	// notifications to, e.g. budget-alerts, or a fully qualified topic
+	// name, e.g. projects/my-project/topics/budget-alerts. Budgets are
+	// connected to the topic in their notification settings.
+	Topic string `json:"topic"`
+}
+
+const (
+	// CloudBillingBudgetSourceConditionReady has status True when the
+	// CloudBillingBudgetSource is ready to send events.
+	CloudBillingBudgetSourceConditionReady = apis.ConditionReady
+)
+
+var billingBudgetCondSet = apis.NewLivingConditionSet(
+	duckv1beta1.PullSubscriptionReady,
+)
+
+// CloudBillingBudgetSourceStatus is the status for a CloudBillingBudgetSource resource.
+type CloudBillingBudgetSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1beta1 Status, SinkURI, ProjectID, TopicID, and SubscriptionID
+	duckv1beta1.PubSubStatus `json:",inline"`
+}
+
+func (*CloudBillingBudgetSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudBillingBudgetSource")
+}
+
+// Methods for identifiable interface
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudBillingBudgetSource) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudBillingBudgetSource) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *CloudBillingBudgetSource) ConditionSet() *apis.ConditionSet {
+	return &billingBudgetCondSet
+}
+
+// Methods for pubsubable interface
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudBillingBudgetSource) PubSubSpec() *duckv1beta1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudBillingBudgetSource) PubSubStatus() *duckv1beta1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudBillingBudgetSourceList is a list of CloudBillingBudgetSource resources
+type CloudBillingBudgetSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CloudBillingBudgetSource `json:"items"`
+}
@@ -28,7 +28,7 @@ import (
 func (bs *CloudBuildSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, bs.ObjectMeta)
 	bs.Spec.SetDefaults(ctx)
-	duckv1beta1.SetClusterNameAnnotation(&bs.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1beta1.SetClusterNameAnnotation(ctx, &bs.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 	duckv1beta1.SetAutoscalingAnnotationsDefaults(ctx, &bs.ObjectMeta)
 }
 
@@ -27,7 +27,9 @@ import (
 
 func (current *CloudBuildSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
-	return duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateMetricsAnnotations(ctx, current.Annotations, errs)
+	return duckv1beta1.ValidateReceiveAdapterModeAnnotation(ctx, current.Annotations, errs)
 }
 
 func (current *CloudBuildSourceSpec) Validate(ctx context.Context) *apis.FieldError {
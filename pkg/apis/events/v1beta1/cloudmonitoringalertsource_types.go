@@ -0,0 +1,146 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudMonitoringAlertSource is a specification for a CloudMonitoringAlertSource
+// resource. It provisions a Pub/Sub notification channel in Cloud Monitoring
+// and converts the incidents of the alert policies using the channel into
+// CloudEvents.
+type CloudMonitoringAlertSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudMonitoringAlertSourceSpec   `json:"spec"`
+	Status CloudMonitoringAlertSourceStatus `json:"status"`
+}
+
+// Verify that CloudMonitoringAlertSource matches various duck types.
+var (
+	_ apis.Convertible             = (*CloudMonitoringAlertSource)(nil)
+	_ apis.Defaultable             = (*CloudMonitoringAlertSource)(nil)
+	_ apis.Validatable             = (*CloudMonitoringAlertSource)(nil)
+	_ runtime.Object               = (*CloudMonitoringAlertSource)(nil)
+	_ kmeta.OwnerRefable           = (*CloudMonitoringAlertSource)(nil)
+	_ resourcesemantics.GenericCRD = (*CloudMonitoringAlertSource)(nil)
+	_ kngcpduck.Identifiable       = (*CloudMonitoringAlertSource)(nil)
+	_ kngcpduck.PubSubable         = (*CloudMonitoringAlertSource)(nil)
+)
+
+const (
+	// CloudEvent types used by CloudMonitoringAlertSource.
+	CloudMonitoringAlertSourceRaised   = "google.cloud.monitoring.alert.v1.raised"
+	CloudMonitoringAlertSourceResolved = "google.cloud.monitoring.alert.v1.resolved"
+)
+
+// CloudMonitoringAlertSourceEventSource returns the Cloud Monitoring CloudEvent source value.
+func CloudMonitoringAlertSourceEventSource(googleCloudProject string) string {
+	return fmt.Sprintf("//monitoring.googleapis.com/projects/%s", googleCloudProject)
+}
+
+// CloudMonitoringAlertSourceSpec is the spec for a CloudMonitoringAlertSource resource.
+type CloudMonitoringAlertSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret, and Project
+	duckv1beta1.PubSubSpec `json:",inline"`
+}
+
+const (
+	// CloudMonitoringAlertSourceConditionReady has status True when the
+	// CloudMonitoringAlertSource is ready to send events.
+	CloudMonitoringAlertSourceConditionReady = apis.ConditionReady
+
+	// NotificationChannelReady has status True when the Cloud Monitoring
+	// notification channel of the CloudMonitoringAlertSource has been
+	// successfully created.
+	NotificationChannelReady apis.ConditionType = "NotificationChannelReady"
+)
+
+var monitoringAlertCondSet = apis.NewLivingConditionSet(
+	duckv1beta1.PullSubscriptionReady,
+	duckv1beta1.TopicReady,
+	NotificationChannelReady)
+
+// CloudMonitoringAlertSourceStatus is the status for a CloudMonitoringAlertSource resource.
+type CloudMonitoringAlertSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1beta1 Status, SinkURI, ProjectID, TopicID, and SubscriptionID
+	duckv1beta1.PubSubStatus `json:",inline"`
+
+	// NotificationChannel is the name of the created notification channel on
+	// success, e.g. projects/my-project/notificationChannels/1234. Alert
+	// policies using it send their incidents to the source.
+	// +optional
+	NotificationChannel string `json:"notificationChannel,omitempty"`
+}
+
+func (*CloudMonitoringAlertSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("CloudMonitoringAlertSource")
+}
+
+// Methods for identifiable interface
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *CloudMonitoringAlertSource) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *CloudMonitoringAlertSource) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *CloudMonitoringAlertSource) ConditionSet() *apis.ConditionSet {
+	return &monitoringAlertCondSet
+}
+
+// Methods for pubsubable interface
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *CloudMonitoringAlertSource) PubSubSpec() *duckv1beta1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *CloudMonitoringAlertSource) PubSubStatus() *duckv1beta1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CloudMonitoringAlertSourceList is a list of CloudMonitoringAlertSource resources
+type CloudMonitoringAlertSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []CloudMonitoringAlertSource `json:"items"`
+}
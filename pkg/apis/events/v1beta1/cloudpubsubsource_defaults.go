@@ -23,6 +23,7 @@ import (
 	"knative.dev/pkg/apis"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	"knative.dev/pkg/ptr"
 )
 
@@ -34,6 +35,7 @@ const (
 func (ps *CloudPubSubSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, ps.ObjectMeta)
 	ps.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &ps.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 	duckv1beta1.SetAutoscalingAnnotationsDefaults(ctx, &ps.ObjectMeta)
 }
 
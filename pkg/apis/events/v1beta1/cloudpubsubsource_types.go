@@ -62,10 +62,11 @@ type CloudPubSubSourceSpec struct {
 	// Sink, CloudEventOverrides, Secret, PubSubSecret, and Project
 	duckv1beta1.PubSubSpec `json:",inline"`
 
-	// Topic is the ID of the PubSub Topic to Subscribe to. It must
-	// be in the form of the unique identifier within the project, not the
-	// entire name. E.g. it must be 'laconia', not
-	// 'projects/my-proj/topics/laconia'.
+	// Topic is the ID of the PubSub Topic to Subscribe to, e.g.
+	// 'laconia'. A topic in another project than the subscription is
+	// referenced by its entire name, e.g.
+	// 'projects/other-proj/topics/laconia'. The subscription is always
+	// created in the project of the resource.
 	Topic string `json:"topic"`
 
 	// AckDeadline is the default maximum time after a subscriber receives a
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -40,7 +41,9 @@ const (
 
 func (current *CloudPubSubSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
-	return duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateMetricsAnnotations(ctx, current.Annotations, errs)
+	return duckv1beta1.ValidateReceiveAdapterModeAnnotation(ctx, current.Annotations, errs)
 }
 
 func (current *CloudPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -48,6 +51,8 @@ func (current *CloudPubSubSourceSpec) Validate(ctx context.Context) *apis.FieldE
 	// Topic [required]
 	if current.Topic == "" {
 		errs = errs.Also(apis.ErrMissingField("topic"))
+	} else if _, _, err := utils.ParseTopic(current.Topic); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(current.Topic, "topic"))
 	}
 	// Sink [required]
 	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
@@ -98,5 +103,6 @@ func (current *CloudPubSubSource) CheckImmutableFields(ctx context.Context, orig
 			Details: diff,
 		}
 	}
-	return nil
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
 }
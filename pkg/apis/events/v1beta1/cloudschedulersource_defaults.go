@@ -22,10 +22,12 @@ import (
 	"knative.dev/pkg/apis"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 )
 
 func (s *CloudSchedulerSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetPubSubDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 	duckv1beta1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
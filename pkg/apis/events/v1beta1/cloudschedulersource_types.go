@@ -82,6 +82,30 @@ type CloudSchedulerSourceSpec struct {
 
 	// What data to send
 	Data string `json:"data"`
+
+	// TimeZone is the time zone the Schedule is interpreted in, as a name
+	// from the tz database, e.g. "America/New_York". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// RetryConfig configures the retries of failed job executions. Failed
+	// executions are not retried by default.
+	// +optional
+	RetryConfig *SchedulerRetryConfig `json:"retryConfig,omitempty"`
+}
+
+// SchedulerRetryConfig configures the retries of a Scheduler Job.
+type SchedulerRetryConfig struct {
+	// RetryCount is the number of times a failed execution is retried, with
+	// exponential backoff, before waiting for the next scheduled execution.
+	// Must be between 0 and 5.
+	// +optional
+	RetryCount int32 `json:"retryCount,omitempty"`
+
+	// MaxBackoff is the maximum time to wait between two retries, e.g. '10m'.
+	// Must be at least 5 seconds. Defaults to one hour ('1h').
+	// +optional
+	MaxBackoff *string `json:"maxBackoff,omitempty"`
 }
 
 const (
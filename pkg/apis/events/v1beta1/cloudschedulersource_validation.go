@@ -18,6 +18,8 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -27,9 +29,20 @@ import (
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+const (
+	minRetryCount = 0
+	maxRetryCount = 5
+
+	// minMaxBackoff is the default minimum backoff of Scheduler Jobs, which
+	// the maximum backoff can't be lower than.
+	minMaxBackoff = 5 * time.Second
+)
+
 func (current *CloudSchedulerSource) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
-	return duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateMetricsAnnotations(ctx, current.Annotations, errs)
+	return duckv1beta1.ValidateReceiveAdapterModeAnnotation(ctx, current.Annotations, errs)
 }
 
 func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -57,6 +70,10 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 		errs = errs.Also(apis.ErrMissingField("data"))
 	}
 
+	if current.RetryConfig != nil {
+		errs = errs.Also(current.RetryConfig.Validate(ctx).ViaField("retryConfig"))
+	}
+
 	if err := duckv1beta1.ValidateCredential(current.Secret, current.ServiceAccountName); err != nil {
 		errs = errs.Also(err)
 	}
@@ -64,6 +81,29 @@ func (current *CloudSchedulerSourceSpec) Validate(ctx context.Context) *apis.Fie
 	return errs
 }
 
+func (current *SchedulerRetryConfig) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if current.RetryCount < minRetryCount || current.RetryCount > maxRetryCount {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(current.RetryCount, minRetryCount, maxRetryCount, "retryCount"))
+	}
+
+	if current.MaxBackoff != nil {
+		// If set, MaxBackoff needs to parse to a duration of at least the
+		// minimum backoff of the Scheduler Job.
+		mb, err := time.ParseDuration(*current.MaxBackoff)
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*current.MaxBackoff, "maxBackoff"))
+		} else if mb < minMaxBackoff {
+			fe := apis.ErrInvalidValue(*current.MaxBackoff, "maxBackoff")
+			fe.Details = fmt.Sprintf("must be at least %v", minMaxBackoff)
+			errs = errs.Also(fe)
+		}
+	}
+
+	return errs
+}
+
 func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, original *CloudSchedulerSource) *apis.FieldError {
 	if original == nil {
 		return nil
@@ -78,5 +118,6 @@ func (current *CloudSchedulerSource) CheckImmutableFields(ctx context.Context, o
 			Details: diff,
 		}
 	}
-	return nil
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
 }
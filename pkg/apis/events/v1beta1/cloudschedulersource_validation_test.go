@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
 )
 
 var (
@@ -347,6 +348,60 @@ func TestCloudSchedulerSourceSpecValidationFields(t *testing.T) {
 
 }
 
+func TestSchedulerRetryConfigValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		rc   *SchedulerRetryConfig
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		rc:   &SchedulerRetryConfig{},
+	}, {
+		name: "valid",
+		rc: &SchedulerRetryConfig{
+			RetryCount: 5,
+			MaxBackoff: ptr.String("10m"),
+		},
+	}, {
+		name: "retry count too high",
+		rc:   &SchedulerRetryConfig{RetryCount: 6},
+		want: apis.ErrOutOfBoundsValue(6, 0, 5, "retryCount"),
+	}, {
+		name: "negative retry count",
+		rc:   &SchedulerRetryConfig{RetryCount: -1},
+		want: apis.ErrOutOfBoundsValue(-1, 0, 5, "retryCount"),
+	}, {
+		name: "invalid max backoff",
+		rc:   &SchedulerRetryConfig{MaxBackoff: ptr.String("soon")},
+		want: apis.ErrInvalidValue("soon", "maxBackoff"),
+	}, {
+		name: "max backoff too short",
+		rc:   &SchedulerRetryConfig{MaxBackoff: ptr.String("1s")},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidValue("1s", "maxBackoff")
+			fe.Details = "must be at least 5s"
+			return fe
+		}(),
+	}}
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.rc.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("Validate SchedulerRetryConfig (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestCloudSchedulerSourceSpecValidationRetryConfig(t *testing.T) {
+	spec := minimalCloudSchedulerSourceSpec.DeepCopy()
+	spec.RetryConfig = &SchedulerRetryConfig{RetryCount: 10}
+	want := apis.ErrOutOfBoundsValue(10, 0, 5, "retryConfig.retryCount")
+	if diff := cmp.Diff(want.Error(), spec.Validate(context.TODO()).Error()); diff != "" {
+		t.Errorf("Validate CloudSchedulerSourceSpec (-want, +got) = %v", diff)
+	}
+}
+
 func TestCloudSchedulerSourceSpecCheckImmutableFields(t *testing.T) {
 	testCases := map[string]struct {
 		orig    interface{}
@@ -22,6 +22,7 @@ import (
 	"knative.dev/pkg/apis"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 )
 
 var allEventTypes = []string{CloudStorageSourceFinalize, CloudStorageSourceDelete, CloudStorageSourceArchive, CloudStorageSourceMetadataUpdate}
@@ -29,6 +30,7 @@ var allEventTypes = []string{CloudStorageSourceFinalize, CloudStorageSourceDelet
 func (s *CloudStorageSource) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 	duckv1beta1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
 
@@ -50,3 +50,10 @@ func (s *CloudStorageSourceStatus) MarkNotificationReady(notificationID string)
 	s.NotificationID = notificationID
 	storageCondSet.Manage(s).MarkTrue(NotificationReady)
 }
+
+// MarkBucketNotificationsReady sets the notifications of the buckets the
+// source subscribes to, and the condition that all of them are ready.
+func (s *CloudStorageSourceStatus) MarkBucketNotificationsReady(notifications []BucketNotification) {
+	s.Buckets = notifications
+	storageCondSet.Manage(s).MarkTrue(NotificationReady)
+}
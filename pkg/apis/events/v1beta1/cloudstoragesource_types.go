@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"fmt"
+	"path"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
@@ -60,8 +61,21 @@ type CloudStorageSourceSpec struct {
 	// Sink, CloudEventOverrides, Secret, PubSubSecret, and Project
 	duckv1beta1.PubSubSpec `json:",inline"`
 
-	// Bucket to subscribe to.
-	Bucket string `json:"bucket"`
+	// Bucket to subscribe to. Exactly one of Bucket, Buckets and
+	// BucketSelector must be set.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+
+	// Buckets to subscribe to. A notification is added to each of them.
+	// +optional
+	Buckets []string `json:"buckets,omitempty"`
+
+	// BucketSelector selects the buckets of the project to subscribe to.
+	// The buckets are listed every time the source is reconciled, so that
+	// new buckets matching the selector are subscribed to and buckets that
+	// no longer match are unsubscribed from.
+	// +optional
+	BucketSelector *BucketSelector `json:"bucketSelector,omitempty"`
 
 	// EventTypes to subscribe to. If unspecified, then subscribe to all events.
 	// +optional
@@ -77,6 +91,23 @@ type CloudStorageSourceSpec struct {
 	PayloadFormat string `json:"payloadFormat,omitempty"`
 }
 
+// BucketSelector selects buckets of a project by name and labels.
+type BucketSelector struct {
+	// Include are glob patterns, e.g. 'logs-*', of the names of the buckets
+	// to subscribe to. All the buckets of the project are included if empty.
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are glob patterns of the names of the buckets not to subscribe
+	// to, even if they are included.
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+
+	// MatchLabels restricts the buckets to the ones having all these labels.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
 const (
 	// CloudEvent types used by CloudStorageSource.
 	CloudStorageSourceFinalize       = "com.google.cloud.storage.object.finalize"
@@ -101,6 +132,30 @@ func CloudStorageSourceEventSource(bucket string) string {
 	return fmt.Sprintf("%s/%s", storageSourcePrefix, bucket)
 }
 
+// Matches returns true if the bucket with the given name and labels is
+// selected.
+func (s *BucketSelector) Matches(name string, labels map[string]string) bool {
+	for k, v := range s.MatchLabels {
+		if l, ok := labels[k]; !ok || l != v {
+			return false
+		}
+	}
+	for _, pattern := range s.Exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, pattern := range s.Include {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 var storageCondSet = apis.NewLivingConditionSet(
 	duckv1beta1.PullSubscriptionReady,
 	duckv1beta1.TopicReady,
@@ -115,6 +170,20 @@ type CloudStorageSourceStatus struct {
 	// NotificationID is the ID that GCS identifies this notification as.
 	// +optional
 	NotificationID string `json:"notificationId,omitempty"`
+
+	// Buckets are the notifications of the buckets subscribed to when the
+	// source uses Buckets or BucketSelector.
+	// +optional
+	Buckets []BucketNotification `json:"buckets,omitempty"`
+}
+
+// BucketNotification is the notification added to a bucket.
+type BucketNotification struct {
+	// Bucket is the name of the bucket.
+	Bucket string `json:"bucket"`
+
+	// NotificationID is the ID that GCS identifies the notification as.
+	NotificationID string `json:"notificationId"`
 }
 
 func (storage *CloudStorageSource) GetGroupVersionKind() schema.GroupVersionKind {
@@ -50,6 +50,37 @@ func TestCloudStorageSourceEventSource(t *testing.T) {
 	}
 }
 
+func TestBucketSelectorMatches(t *testing.T) {
+	selector := &BucketSelector{
+		Include:     []string{"logs-*", "audit"},
+		Exclude:     []string{"logs-tmp-*"},
+		MatchLabels: map[string]string{"env": "prod"},
+	}
+	prod := map[string]string{"env": "prod", "team": "a"}
+	testCases := map[string]struct {
+		selector *BucketSelector
+		name     string
+		labels   map[string]string
+		want     bool
+	}{
+		"included":             {selector: selector, name: "logs-a", labels: prod, want: true},
+		"included exactly":     {selector: selector, name: "audit", labels: prod, want: true},
+		"not included":         {selector: selector, name: "images", labels: prod, want: false},
+		"excluded":             {selector: selector, name: "logs-tmp-a", labels: prod, want: false},
+		"missing label":        {selector: selector, name: "logs-a", labels: nil, want: false},
+		"different label":      {selector: selector, name: "logs-a", labels: map[string]string{"env": "dev"}, want: false},
+		"no include":           {selector: &BucketSelector{Exclude: []string{"logs-*"}}, name: "images", want: true},
+		"no include, excluded": {selector: &BucketSelector{Exclude: []string{"logs-*"}}, name: "logs-a", want: false},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.selector.Matches(tc.name, tc.labels); got != tc.want {
+				t.Errorf("Matches(%q, %v) = %v, want %v", tc.name, tc.labels, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestCloudStorageSourceSourceConditionSet(t *testing.T) {
 	want := []apis.Condition{{
 		Type: NotificationReady,
@@ -107,8 +107,8 @@ func TestValidationFields(t *testing.T) {
 		name: "empty",
 		s:    &CloudStorageSource{Spec: CloudStorageSourceSpec{}},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("spec.bucket", "spec.sink")
-			return fe
+			fe := apis.ErrMissingOneOf("spec.bucket", "spec.buckets", "spec.bucketSelector")
+			return fe.Also(apis.ErrMissingField("spec.sink"))
 		}(),
 	}, {
 		name: "missing sink",
@@ -137,8 +137,8 @@ func TestSpecValidationFields(t *testing.T) {
 		name: "empty",
 		spec: &CloudStorageSourceSpec{},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("bucket", "sink")
-			return fe
+			fe := apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector")
+			return fe.Also(apis.ErrMissingField("sink"))
 		}(),
 	}, {
 		name: "missing sink",
@@ -181,7 +181,114 @@ func TestSpecValidationFields(t *testing.T) {
 			},
 		},
 		want: func() *apis.FieldError {
-			fe := apis.ErrMissingField("bucket")
+			fe := apis.ErrMissingOneOf("bucket", "buckets", "bucketSelector")
+			return fe
+		}(),
+	}, {
+		name: "buckets",
+		spec: &CloudStorageSourceSpec{
+			Buckets: []string{"my-test-bucket", "my-other-bucket"},
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "foo",
+							Kind:       "bar",
+							Namespace:  "baz",
+							Name:       "qux",
+						},
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid buckets",
+		spec: &CloudStorageSourceSpec{
+			Buckets: []string{"my-test-bucket", "", "my-test-bucket"},
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "foo",
+							Kind:       "bar",
+							Namespace:  "baz",
+							Name:       "qux",
+						},
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidArrayValue("", "buckets", 1)
+			return fe.Also(apis.ErrInvalidArrayValue("my-test-bucket", "buckets", 2))
+		}(),
+	}, {
+		name: "bucket selector",
+		spec: &CloudStorageSourceSpec{
+			BucketSelector: &BucketSelector{
+				Include:     []string{"logs-*"},
+				Exclude:     []string{"logs-tmp-?"},
+				MatchLabels: map[string]string{"env": "prod"},
+			},
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "foo",
+							Kind:       "bar",
+							Namespace:  "baz",
+							Name:       "qux",
+						},
+					},
+				},
+			},
+		},
+		want: nil,
+	}, {
+		name: "invalid bucket selector",
+		spec: &CloudStorageSourceSpec{
+			BucketSelector: &BucketSelector{
+				Include: []string{"logs-["},
+				Exclude: []string{""},
+			},
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "foo",
+							Kind:       "bar",
+							Namespace:  "baz",
+							Name:       "qux",
+						},
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrInvalidArrayValue("logs-[", "bucketSelector.include", 0)
+			return fe.Also(apis.ErrInvalidArrayValue("", "bucketSelector.exclude", 0))
+		}(),
+	}, {
+		name: "bucket and buckets",
+		spec: &CloudStorageSourceSpec{
+			Bucket:  "my-test-bucket",
+			Buckets: []string{"my-other-bucket"},
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						Ref: &duckv1.KReference{
+							APIVersion: "foo",
+							Kind:       "bar",
+							Namespace:  "baz",
+							Name:       "qux",
+						},
+					},
+				},
+			},
+		},
+		want: func() *apis.FieldError {
+			fe := apis.ErrMultipleOneOf("bucket", "buckets")
 			return fe
 		}(),
 	}, {
@@ -362,6 +469,23 @@ func TestCheckImmutableFields(t *testing.T) {
 			},
 			allowed: false,
 		},
+		"BucketSelector changed": {
+			orig: &CloudStorageSourceSpec{
+				BucketSelector:   &BucketSelector{Include: []string{"logs-*"}},
+				EventTypes:       storageSourceSpec.EventTypes,
+				ObjectNamePrefix: storageSourceSpec.ObjectNamePrefix,
+				PayloadFormat:    storageSourceSpec.PayloadFormat,
+				PubSubSpec:       storageSourceSpec.PubSubSpec,
+			},
+			updated: CloudStorageSourceSpec{
+				Buckets:          []string{"logs-a", "logs-b"},
+				EventTypes:       storageSourceSpec.EventTypes,
+				ObjectNamePrefix: storageSourceSpec.ObjectNamePrefix,
+				PayloadFormat:    storageSourceSpec.PayloadFormat,
+				PubSubSpec:       storageSourceSpec.PubSubSpec,
+			},
+			allowed: true,
+		},
 		"EventType changed": {
 			orig: &storageSourceSpec,
 			updated: CloudStorageSourceSpec{
@@ -35,6 +35,16 @@ func TestTypesImplements(t *testing.T) {
 		{instance: &CloudPubSubSource{}, iface: &v1beta1.Conditions{}},
 		{instance: &CloudBuildSource{}, iface: &v1beta1.Source{}},
 		{instance: &CloudBuildSource{}, iface: &v1beta1.Conditions{}},
+		{instance: &CloudMonitoringAlertSource{}, iface: &v1beta1.Source{}},
+		{instance: &CloudBillingBudgetSource{}, iface: &v1beta1.Source{}},
+		{instance: &CloudBillingBudgetSource{}, iface: &v1beta1.Conditions{}},
+		{instance: &SecretManagerRotationSource{}, iface: &v1beta1.Source{}},
+		{instance: &SecretManagerRotationSource{}, iface: &v1beta1.Conditions{}},
+		{instance: &CloudArtifactRegistrySource{}, iface: &v1beta1.Source{}},
+		{instance: &CloudArtifactRegistrySource{}, iface: &v1beta1.Conditions{}},
+		{instance: &CloudMonitoringAlertSource{}, iface: &v1beta1.Conditions{}},
+		{instance: &WebhookSource{}, iface: &v1beta1.Source{}},
+		{instance: &WebhookSource{}, iface: &v1beta1.Conditions{}},
 	}
 	for _, tc := range testCases {
 		if err := duck.VerifyType(tc.instance, tc.iface); err != nil {
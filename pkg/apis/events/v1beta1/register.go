@@ -55,6 +55,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CloudPubSubSourceList{},
 		&CloudBuildSource{},
 		&CloudBuildSourceList{},
+		&CloudMonitoringAlertSource{},
+		&CloudMonitoringAlertSourceList{},
+		&CloudBillingBudgetSource{},
+		&CloudBillingBudgetSourceList{},
+		&SecretManagerRotationSource{},
+		&SecretManagerRotationSourceList{},
+		&CloudArtifactRegistrySource{},
+		&CloudArtifactRegistrySourceList{},
+		&WebhookSource{},
+		&WebhookSourceList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -63,6 +63,11 @@ func TestKnownTypes(t *testing.T) {
 		"CloudStorageSource",
 		"CloudSchedulerSource",
 		"CloudBuildSource",
+		"CloudMonitoringAlertSource",
+		"CloudBillingBudgetSource",
+		"SecretManagerRotationSource",
+		"CloudArtifactRegistrySource",
+		"WebhookSource",
 	} {
 		if _, ok := types[name]; !ok {
 			t.Errorf("Did not find %q as registered type", name)
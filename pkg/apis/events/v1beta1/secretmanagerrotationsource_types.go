@@ -0,0 +1,156 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecretManagerRotationSource is a specification for a
+// SecretManagerRotationSource resource. It converts the event notifications
+// Secret Manager publishes to a Pub/Sub topic, e.g. when a secret is due for
+// rotation or a new secret version is added, into CloudEvents.
+type SecretManagerRotationSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   SecretManagerRotationSourceSpec   `json:"spec"`
+	Status SecretManagerRotationSourceStatus `json:"status"`
+}
+
+// Verify that SecretManagerRotationSource matches various duck types.
+var (
+	_ apis.Convertible             = (*SecretManagerRotationSource)(nil)
+	_ apis.Defaultable             = (*SecretManagerRotationSource)(nil)
+	_ apis.Validatable             = (*SecretManagerRotationSource)(nil)
+	_ runtime.Object               = (*SecretManagerRotationSource)(nil)
+	_ kmeta.OwnerRefable           = (*SecretManagerRotationSource)(nil)
+	_ resourcesemantics.GenericCRD = (*SecretManagerRotationSource)(nil)
+	_ kngcpduck.Identifiable       = (*SecretManagerRotationSource)(nil)
+	_ kngcpduck.PubSubable         = (*SecretManagerRotationSource)(nil)
+)
+
+const (
+	// CloudEvent types used by SecretManagerRotationSource.
+	SecretManagerRotationSourceSecretCreated    = "google.cloud.secretmanager.secret.v1.created"
+	SecretManagerRotationSourceSecretUpdated    = "google.cloud.secretmanager.secret.v1.updated"
+	SecretManagerRotationSourceSecretDeleted    = "google.cloud.secretmanager.secret.v1.deleted"
+	SecretManagerRotationSourceSecretRotate     = "google.cloud.secretmanager.secret.v1.rotate"
+	SecretManagerRotationSourceVersionAdded     = "google.cloud.secretmanager.secret.v1.versionAdded"
+	SecretManagerRotationSourceVersionEnabled   = "google.cloud.secretmanager.secret.v1.versionEnabled"
+	SecretManagerRotationSourceVersionDisabled  = "google.cloud.secretmanager.secret.v1.versionDisabled"
+	SecretManagerRotationSourceVersionDestroyed = "google.cloud.secretmanager.secret.v1.versionDestroyed"
+
+	// SecretManagerRotationSourceEventType is the Pub/Sub message attribute key with the Secret Manager event type,
+	// e.g. SECRET_ROTATE.
+	SecretManagerRotationSourceEventType = "eventType"
+	// SecretManagerRotationSourceSecretID is the Pub/Sub message attribute key with the name of the secret,
+	// e.g. projects/my-project/secrets/my-secret.
+	SecretManagerRotationSourceSecretID = "secretId"
+	// SecretManagerRotationSourceVersionID is the Pub/Sub message attribute key with the name of the secret version,
+	// e.g. projects/my-project/secrets/my-secret/versions/1. It is only set for version events.
+	SecretManagerRotationSourceVersionID = "versionId"
+)
+
+// SecretManagerRotationSourceEventSource returns the Secret Manager CloudEvent source value.
+func SecretManagerRotationSourceEventSource(secret string) string {
+	return fmt.Sprintf("//secretmanager.googleapis.com/%s", secret)
+}
+
+// SecretManagerRotationSourceSpec is the spec for a SecretManagerRotationSource resource.
+type SecretManagerRotationSourceSpec struct {
+	// This brings in the PubSub based Source Specs. Includes:
+	// Sink, CloudEventOverrides, Secret, and Project
+	duckv1beta1.PubSubSpec `json:",inline"`
+
+	// Topic is the ID of the Pub/Sub topic the secrets publish their event
+	// notifications to, e.g. secret-events, or a fully qualified topic
+	// name, e.g. projects/my-project/topics/secret-events. Secrets are
+	// connected to the topic in their topics setting.
+	Topic string `json:"topic"`
+}
+
+const (
+	// SecretManagerRotationSourceConditionReady has status True when the
+	// SecretManagerRotationSource is ready to send events.
+	SecretManagerRotationSourceConditionReady = apis.ConditionReady
+)
+
+var secretRotationCondSet = apis.NewLivingConditionSet(
+	duckv1beta1.PullSubscriptionReady,
+)
+
+// SecretManagerRotationSourceStatus is the status for a SecretManagerRotationSource resource.
+type SecretManagerRotationSourceStatus struct {
+	// This brings in our GCP PubSub based events importers
+	// duck/v1beta1 Status, SinkURI, ProjectID, TopicID, and SubscriptionID
+	duckv1beta1.PubSubStatus `json:",inline"`
+}
+
+func (*SecretManagerRotationSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("SecretManagerRotationSource")
+}
+
+// Methods for identifiable interface
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *SecretManagerRotationSource) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *SecretManagerRotationSource) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *SecretManagerRotationSource) ConditionSet() *apis.ConditionSet {
+	return &secretRotationCondSet
+}
+
+// Methods for pubsubable interface
+// PubSubSpec returns the PubSubSpec portion of the Spec.
+func (s *SecretManagerRotationSource) PubSubSpec() *duckv1beta1.PubSubSpec {
+	return &s.Spec.PubSubSpec
+}
+
+// PubSubStatus returns the PubSubStatus portion of the Status.
+func (s *SecretManagerRotationSource) PubSubStatus() *duckv1beta1.PubSubStatus {
+	return &s.Status.PubSubStatus
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SecretManagerRotationSourceList is a list of SecretManagerRotationSource resources
+type SecretManagerRotationSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []SecretManagerRotationSource `json:"items"`
+}
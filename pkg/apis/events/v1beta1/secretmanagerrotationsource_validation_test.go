@@ -0,0 +1,154 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+var (
+	secretRotationSourceSpec = SecretManagerRotationSourceSpec{
+		PubSubSpec: duckv1beta1.PubSubSpec{
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "secret-name",
+				},
+				Key: "secret-key",
+			},
+			SourceSpec: duckv1.SourceSpec{
+				Sink: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "foo",
+						Kind:       "bar",
+						Namespace:  "baz",
+						Name:       "qux",
+					},
+				},
+			},
+			Project: "my-eventing-project",
+		},
+		Topic: "secret-events",
+	}
+)
+
+func TestSecretManagerRotationSourceCheckValidationFields(t *testing.T) {
+	testCases := map[string]struct {
+		spec  SecretManagerRotationSourceSpec
+		error bool
+	}{
+		"ok": {
+			spec:  secretRotationSourceSpec,
+			error: false,
+		},
+		"ok, fully qualified topic": {
+			spec: func() SecretManagerRotationSourceSpec {
+				obj := secretRotationSourceSpec.DeepCopy()
+				obj.Topic = "projects/secrets-project/topics/secret-events"
+				return *obj
+			}(),
+			error: false,
+		},
+		"missing topic": {
+			spec: func() SecretManagerRotationSourceSpec {
+				obj := secretRotationSourceSpec.DeepCopy()
+				obj.Topic = ""
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid topic": {
+			spec: func() SecretManagerRotationSourceSpec {
+				obj := secretRotationSourceSpec.DeepCopy()
+				obj.Topic = "projects/secrets-project/secret-events"
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad sink, empty": {
+			spec: func() SecretManagerRotationSourceSpec {
+				obj := secretRotationSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid k8s service account": {
+			spec: func() SecretManagerRotationSourceSpec {
+				obj := secretRotationSourceSpec.DeepCopy()
+				obj.Secret = nil
+				obj.ServiceAccountName = invalidServiceAccountName
+				return *obj
+			}(),
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestSecretManagerRotationSourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		updated func(*SecretManagerRotationSourceSpec)
+		allowed bool
+	}{
+		"no change": {
+			updated: func(*SecretManagerRotationSourceSpec) {},
+			allowed: true,
+		},
+		"Sink changed": {
+			updated: func(s *SecretManagerRotationSourceSpec) {
+				s.Sink.Ref.Name = "some-other-name"
+			},
+			allowed: true,
+		},
+		"Topic changed": {
+			updated: func(s *SecretManagerRotationSourceSpec) {
+				s.Topic = "some-other-topic"
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			updated: func(s *SecretManagerRotationSourceSpec) {
+				s.Project = "some-other-project"
+			},
+			allowed: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			orig := &SecretManagerRotationSource{Spec: *secretRotationSourceSpec.DeepCopy()}
+			updated := &SecretManagerRotationSource{Spec: *secretRotationSourceSpec.DeepCopy()}
+			tc.updated(&updated.Spec)
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
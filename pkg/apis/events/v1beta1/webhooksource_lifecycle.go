@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/eventing/pkg/apis/duck"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (s *WebhookSourceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return webhookCondSet.Manage(s).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (s *WebhookSourceStatus) GetTopLevelCondition() *apis.Condition {
+	return webhookCondSet.Manage(s).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (s *WebhookSourceStatus) IsReady() bool {
+	return webhookCondSet.Manage(s).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (s *WebhookSourceStatus) InitializeConditions() {
+	webhookCondSet.Manage(s).InitializeConditions()
+}
+
+// MarkSink sets the condition that the source has a sink configured.
+func (s *WebhookSourceStatus) MarkSink(uri *apis.URL) {
+	s.SinkURI = uri
+	if !uri.IsEmpty() {
+		webhookCondSet.Manage(s).MarkTrue(WebhookSourceConditionSinkProvided)
+	} else {
+		webhookCondSet.Manage(s).MarkUnknown(WebhookSourceConditionSinkProvided, "SinkEmpty", "Sink has resolved to empty")
+	}
+}
+
+// MarkNoSink sets the condition that the source does not have a sink configured.
+func (s *WebhookSourceStatus) MarkNoSink(reason, messageFormat string, messageA ...interface{}) {
+	webhookCondSet.Manage(s).MarkFalse(WebhookSourceConditionSinkProvided, reason, messageFormat, messageA...)
+}
+
+// PropagateReceiverAvailability uses the availability of the provided
+// Endpoints to determine if WebhookSourceConditionReceiverReady should be
+// marked as true or false.
+func (s *WebhookSourceStatus) PropagateReceiverAvailability(ep *corev1.Endpoints) {
+	if duck.EndpointsAreAvailable(ep) {
+		webhookCondSet.Manage(s).MarkTrue(WebhookSourceConditionReceiverReady)
+	} else {
+		webhookCondSet.Manage(s).MarkFalse(WebhookSourceConditionReceiverReady, "EndpointsUnavailable", "Endpoints %q is unavailable.", ep.Name)
+	}
+}
+
+// MarkReceiverFailed sets the condition that the webhook receiver could not
+// be deployed.
+func (s *WebhookSourceStatus) MarkReceiverFailed(reason, messageFormat string, messageA ...interface{}) {
+	webhookCondSet.Manage(s).MarkFalse(WebhookSourceConditionReceiverReady, reason, messageFormat, messageA...)
+}
+
+// SetAddress sets the URL the provider sends webhook requests to.
+func (s *WebhookSourceStatus) SetAddress(url *apis.URL) {
+	if url == nil {
+		s.Address = nil
+		return
+	}
+	s.Address = &duckv1.Addressable{URL: url}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/webhook/resourcesemantics"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebhookSource is a specification for a WebhookSource resource. It deploys
+// an HTTP receiver that authenticates the webhook requests of a provider,
+// e.g. GitHub or Stripe, and converts them into CloudEvents sent to a sink,
+// usually a Broker.
+type WebhookSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WebhookSourceSpec   `json:"spec"`
+	Status WebhookSourceStatus `json:"status"`
+}
+
+// Verify that WebhookSource matches various duck types.
+var (
+	_ apis.Convertible             = (*WebhookSource)(nil)
+	_ apis.Defaultable             = (*WebhookSource)(nil)
+	_ apis.Validatable             = (*WebhookSource)(nil)
+	_ runtime.Object               = (*WebhookSource)(nil)
+	_ kmeta.OwnerRefable           = (*WebhookSource)(nil)
+	_ resourcesemantics.GenericCRD = (*WebhookSource)(nil)
+)
+
+// WebhookProvider is the provider sending the webhook requests.
+type WebhookProvider string
+
+const (
+	// WebhookProviderGeneric accepts webhook requests of any provider. The
+	// request body is the data of the event.
+	WebhookProviderGeneric WebhookProvider = "Generic"
+	// WebhookProviderGitHub accepts GitHub webhook requests, signed in the
+	// X-Hub-Signature-256 header.
+	WebhookProviderGitHub WebhookProvider = "GitHub"
+	// WebhookProviderStripe accepts Stripe webhook requests, signed in the
+	// Stripe-Signature header.
+	WebhookProviderStripe WebhookProvider = "Stripe"
+)
+
+// WebhookAuthStrategy is how the receiver authenticates webhook requests.
+type WebhookAuthStrategy string
+
+const (
+	// WebhookAuthHMAC verifies the HMAC-SHA256 signature of the request body,
+	// keyed with the secret.
+	WebhookAuthHMAC WebhookAuthStrategy = "HMAC"
+	// WebhookAuthAPIKey compares a request header with the secret.
+	WebhookAuthAPIKey WebhookAuthStrategy = "APIKey"
+)
+
+const (
+	// WebhookSourceGenericEventType is the CloudEvent type of the requests
+	// of Generic providers.
+	WebhookSourceGenericEventType = "com.google.cloud.events.webhook.v1.received"
+	// WebhookSourceGitHubEventTypePrefix prefixes the X-GitHub-Event header
+	// in the CloudEvent type of GitHub requests, e.g. com.github.push.
+	WebhookSourceGitHubEventTypePrefix = "com.github."
+	// WebhookSourceStripeEventTypePrefix prefixes the type of the Stripe
+	// event in the CloudEvent type of Stripe requests, e.g.
+	// com.stripe.invoice.paid.
+	WebhookSourceStripeEventTypePrefix = "com.stripe."
+
+	// Default headers holding the signature or the API key of requests.
+	WebhookSourceGitHubSignatureHeader  = "X-Hub-Signature-256"
+	WebhookSourceStripeSignatureHeader  = "Stripe-Signature"
+	WebhookSourceGenericSignatureHeader = "X-Signature"
+	WebhookSourceGenericAPIKeyHeader    = "X-API-Key"
+)
+
+// WebhookSourceEventSource returns the CloudEvent source value of the
+// events of the WebhookSource namespace/name.
+func WebhookSourceEventSource(namespace, name string) string {
+	return fmt.Sprintf("//webhook.events.cloud.google.com/namespaces/%s/webhooksources/%s", namespace, name)
+}
+
+// WebhookSourceSpec is the spec for a WebhookSource resource.
+type WebhookSourceSpec struct {
+	// This brings in CloudEventOverrides and Sink.
+	duckv1.SourceSpec `json:",inline"`
+
+	// Provider is the provider sending the webhook requests. It selects how
+	// requests are authenticated and converted into CloudEvents: Generic,
+	// GitHub or Stripe. Defaults to Generic.
+	// +optional
+	Provider WebhookProvider `json:"provider,omitempty"`
+
+	// Auth configures how webhook requests are authenticated.
+	Auth WebhookAuth `json:"auth"`
+}
+
+// WebhookAuth configures the authentication of webhook requests. Requests
+// failing it are rejected with 401 Unauthorized.
+type WebhookAuth struct {
+	// Strategy is HMAC or APIKey. GitHub and Stripe providers only support,
+	// and default to, HMAC. Defaults to HMAC for Generic providers too.
+	// +optional
+	Strategy WebhookAuthStrategy `json:"strategy,omitempty"`
+
+	// Secret is the key of the Secret holding the HMAC signing secret, or the
+	// API key.
+	Secret *corev1.SecretKeySelector `json:"secret"`
+
+	// Header is the request header holding the signature or the API key.
+	// Defaults to X-Hub-Signature-256 for GitHub, Stripe-Signature for
+	// Stripe, and X-Signature (HMAC) or X-API-Key (APIKey) for Generic
+	// providers. It can only be set for Generic providers.
+	// +optional
+	Header string `json:"header,omitempty"`
+}
+
+const (
+	// WebhookSourceConditionReady has status True when the WebhookSource is
+	// ready to receive webhook requests.
+	WebhookSourceConditionReady = apis.ConditionReady
+
+	// WebhookSourceConditionSinkProvided has status True when the
+	// WebhookSource has been configured with a sink target.
+	WebhookSourceConditionSinkProvided apis.ConditionType = "SinkProvided"
+
+	// WebhookSourceConditionReceiverReady has status True when the webhook
+	// receiver is deployed and its endpoints are available.
+	WebhookSourceConditionReceiverReady apis.ConditionType = "ReceiverReady"
+)
+
+var webhookCondSet = apis.NewLivingConditionSet(
+	WebhookSourceConditionSinkProvided,
+	WebhookSourceConditionReceiverReady,
+)
+
+// WebhookSourceStatus is the status for a WebhookSource resource.
+type WebhookSourceStatus struct {
+	// This brings in duck/v1 Status, SinkURI and CloudEventAttributes.
+	duckv1.SourceStatus `json:",inline"`
+
+	// Address is the URL the provider sends webhook requests to. It is
+	// only reachable from within the cluster; expose it through an ingress
+	// to receive requests of SaaS providers.
+	duckv1.AddressStatus `json:",inline"`
+}
+
+func (*WebhookSource) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("WebhookSource")
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *WebhookSource) ConditionSet() *apis.ConditionSet {
+	return &webhookCondSet
+}
+
+// AuthHeader returns the request header holding the signature or the API
+// key, defaulted for the provider and strategy.
+func (ws *WebhookSourceSpec) AuthHeader() string {
+	if ws.Auth.Header != "" {
+		return ws.Auth.Header
+	}
+	switch ws.Provider {
+	case WebhookProviderGitHub:
+		return WebhookSourceGitHubSignatureHeader
+	case WebhookProviderStripe:
+		return WebhookSourceStripeSignatureHeader
+	}
+	if ws.Auth.Strategy == WebhookAuthAPIKey {
+		return WebhookSourceGenericAPIKeyHeader
+	}
+	return WebhookSourceGenericSignatureHeader
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// WebhookSourceList is a list of WebhookSource resources
+type WebhookSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []WebhookSource `json:"items"`
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func (current *WebhookSource) Validate(ctx context.Context) *apis.FieldError {
+	return current.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (current *WebhookSourceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Sink [required]
+	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
+		errs = errs.Also(apis.ErrMissingField("sink"))
+	} else if err := current.Sink.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("sink"))
+	}
+
+	switch current.Provider {
+	case WebhookProviderGeneric:
+	case WebhookProviderGitHub, WebhookProviderStripe:
+		// The providers sign requests in their own headers.
+		if current.Auth.Strategy != WebhookAuthHMAC {
+			errs = errs.Also(apis.ErrInvalidValue(current.Auth.Strategy, "auth.strategy"))
+		}
+		if current.Auth.Header != "" {
+			errs = errs.Also(apis.ErrDisallowedFields("auth.header"))
+		}
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(current.Provider, "provider"))
+	}
+
+	return errs.Also(current.Auth.Validate(ctx).ViaField("auth"))
+}
+
+func (current *WebhookAuth) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	switch current.Strategy {
+	case WebhookAuthHMAC, WebhookAuthAPIKey:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(current.Strategy, "strategy"))
+	}
+
+	// Secret [required]
+	if current.Secret == nil {
+		errs = errs.Also(apis.ErrMissingField("secret"))
+	} else {
+		if current.Secret.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("secret.name"))
+		}
+		if current.Secret.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("secret.key"))
+		}
+	}
+
+	if current.Header != "" && len(validation.IsHTTPHeaderName(current.Header)) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(current.Header, "header"))
+	}
+	return errs
+}
+
+func (current *WebhookSource) CheckImmutableFields(ctx context.Context, original *WebhookSource) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+
+	// Modification of Provider is not allowed, as it changes the events the
+	// WebhookSource produces. Everything else is mutable.
+	if diff := cmp.Diff(original.Spec.Provider, current.Spec.Provider); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "provider"},
+			Details: diff,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+var (
+	webhookSourceSpec = WebhookSourceSpec{
+		SourceSpec: duckv1.SourceSpec{
+			Sink: duckv1.Destination{
+				Ref: &duckv1.KReference{
+					APIVersion: "foo",
+					Kind:       "bar",
+					Namespace:  "baz",
+					Name:       "qux",
+				},
+			},
+		},
+		Provider: WebhookProviderGitHub,
+		Auth: WebhookAuth{
+			Strategy: WebhookAuthHMAC,
+			Secret: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "github-webhook",
+				},
+				Key: "secret",
+			},
+		},
+	}
+)
+
+func TestWebhookSourceCheckValidationFields(t *testing.T) {
+	testCases := map[string]struct {
+		spec  WebhookSourceSpec
+		error bool
+	}{
+		"ok": {
+			spec:  webhookSourceSpec,
+			error: false,
+		},
+		"ok, generic api key with header": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Provider = WebhookProviderGeneric
+				obj.Auth.Strategy = WebhookAuthAPIKey
+				obj.Auth.Header = "X-Token"
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad sink, empty": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Sink = duckv1.Destination{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid provider": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Provider = "GitLab"
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid strategy": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Provider = WebhookProviderGeneric
+				obj.Auth.Strategy = "Basic"
+				return *obj
+			}(),
+			error: true,
+		},
+		"api key for github": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Auth.Strategy = WebhookAuthAPIKey
+				return *obj
+			}(),
+			error: true,
+		},
+		"header for stripe": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Provider = WebhookProviderStripe
+				obj.Auth.Header = "X-Signature"
+				return *obj
+			}(),
+			error: true,
+		},
+		"invalid header": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Provider = WebhookProviderGeneric
+				obj.Auth.Header = "X Signature"
+				return *obj
+			}(),
+			error: true,
+		},
+		"missing secret": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Auth.Secret = nil
+				return *obj
+			}(),
+			error: true,
+		},
+		"missing secret key": {
+			spec: func() WebhookSourceSpec {
+				obj := webhookSourceSpec.DeepCopy()
+				obj.Auth.Secret.Key = ""
+				return *obj
+			}(),
+			error: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := tc.spec.Validate(context.TODO())
+			if tc.error != (err != nil) {
+				t.Fatalf("Unexpected validation failure. Got %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookSourceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		updated func(*WebhookSourceSpec)
+		allowed bool
+	}{
+		"no change": {
+			updated: func(*WebhookSourceSpec) {},
+			allowed: true,
+		},
+		"Sink changed": {
+			updated: func(s *WebhookSourceSpec) {
+				s.Sink.Ref.Name = "some-other-name"
+			},
+			allowed: true,
+		},
+		"Secret changed": {
+			updated: func(s *WebhookSourceSpec) {
+				s.Auth.Secret.Name = "some-other-secret"
+			},
+			allowed: true,
+		},
+		"Provider changed": {
+			updated: func(s *WebhookSourceSpec) {
+				s.Provider = WebhookProviderStripe
+			},
+			allowed: false,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			orig := &WebhookSource{Spec: *webhookSourceSpec.DeepCopy()}
+			updated := &WebhookSource{Spec: *webhookSourceSpec.DeepCopy()}
+			tc.updated(&updated.Spec)
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
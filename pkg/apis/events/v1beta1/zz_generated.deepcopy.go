@@ -21,9 +21,154 @@ limitations under the License.
 package v1beta1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketNotification) DeepCopyInto(out *BucketNotification) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketNotification.
+func (in *BucketNotification) DeepCopy() *BucketNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BucketSelector) DeepCopyInto(out *BucketSelector) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketSelector.
+func (in *BucketSelector) DeepCopy() *BucketSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(BucketSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudArtifactRegistrySource) DeepCopyInto(out *CloudArtifactRegistrySource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudArtifactRegistrySource.
+func (in *CloudArtifactRegistrySource) DeepCopy() *CloudArtifactRegistrySource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudArtifactRegistrySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudArtifactRegistrySource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudArtifactRegistrySourceList) DeepCopyInto(out *CloudArtifactRegistrySourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudArtifactRegistrySource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudArtifactRegistrySourceList.
+func (in *CloudArtifactRegistrySourceList) DeepCopy() *CloudArtifactRegistrySourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudArtifactRegistrySourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudArtifactRegistrySourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudArtifactRegistrySourceSpec) DeepCopyInto(out *CloudArtifactRegistrySourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudArtifactRegistrySourceSpec.
+func (in *CloudArtifactRegistrySourceSpec) DeepCopy() *CloudArtifactRegistrySourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudArtifactRegistrySourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudArtifactRegistrySourceStatus) DeepCopyInto(out *CloudArtifactRegistrySourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudArtifactRegistrySourceStatus.
+func (in *CloudArtifactRegistrySourceStatus) DeepCopy() *CloudArtifactRegistrySourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudArtifactRegistrySourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudAuditLogsSource) DeepCopyInto(out *CloudAuditLogsSource) {
 	*out = *in
@@ -119,6 +264,101 @@ func (in *CloudAuditLogsSourceStatus) DeepCopy() *CloudAuditLogsSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSource) DeepCopyInto(out *CloudBillingBudgetSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSource.
+func (in *CloudBillingBudgetSource) DeepCopy() *CloudBillingBudgetSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudBillingBudgetSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceList) DeepCopyInto(out *CloudBillingBudgetSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudBillingBudgetSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceList.
+func (in *CloudBillingBudgetSourceList) DeepCopy() *CloudBillingBudgetSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudBillingBudgetSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceSpec) DeepCopyInto(out *CloudBillingBudgetSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceSpec.
+func (in *CloudBillingBudgetSourceSpec) DeepCopy() *CloudBillingBudgetSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudBillingBudgetSourceStatus) DeepCopyInto(out *CloudBillingBudgetSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudBillingBudgetSourceStatus.
+func (in *CloudBillingBudgetSourceStatus) DeepCopy() *CloudBillingBudgetSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudBillingBudgetSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudBuildSource) DeepCopyInto(out *CloudBuildSource) {
 	*out = *in
@@ -214,6 +454,101 @@ func (in *CloudBuildSourceStatus) DeepCopy() *CloudBuildSourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSource) DeepCopyInto(out *CloudMonitoringAlertSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSource.
+func (in *CloudMonitoringAlertSource) DeepCopy() *CloudMonitoringAlertSource {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceList) DeepCopyInto(out *CloudMonitoringAlertSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudMonitoringAlertSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceList.
+func (in *CloudMonitoringAlertSourceList) DeepCopy() *CloudMonitoringAlertSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CloudMonitoringAlertSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopyInto(out *CloudMonitoringAlertSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceSpec.
+func (in *CloudMonitoringAlertSourceSpec) DeepCopy() *CloudMonitoringAlertSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopyInto(out *CloudMonitoringAlertSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudMonitoringAlertSourceStatus.
+func (in *CloudMonitoringAlertSourceStatus) DeepCopy() *CloudMonitoringAlertSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudMonitoringAlertSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloudPubSubSource) DeepCopyInto(out *CloudPubSubSource) {
 	*out = *in
@@ -384,6 +719,11 @@ func (in *CloudSchedulerSourceList) DeepCopyObject() runtime.Object {
 func (in *CloudSchedulerSourceSpec) DeepCopyInto(out *CloudSchedulerSourceSpec) {
 	*out = *in
 	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.RetryConfig != nil {
+		in, out := &in.RetryConfig, &out.RetryConfig
+		*out = new(SchedulerRetryConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -479,6 +819,16 @@ func (in *CloudStorageSourceList) DeepCopyObject() runtime.Object {
 func (in *CloudStorageSourceSpec) DeepCopyInto(out *CloudStorageSourceSpec) {
 	*out = *in
 	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.BucketSelector != nil {
+		in, out := &in.BucketSelector, &out.BucketSelector
+		*out = new(BucketSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EventTypes != nil {
 		in, out := &in.EventTypes, &out.EventTypes
 		*out = make([]string, len(*in))
@@ -501,6 +851,11 @@ func (in *CloudStorageSourceSpec) DeepCopy() *CloudStorageSourceSpec {
 func (in *CloudStorageSourceStatus) DeepCopyInto(out *CloudStorageSourceStatus) {
 	*out = *in
 	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]BucketNotification, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -513,3 +868,237 @@ func (in *CloudStorageSourceStatus) DeepCopy() *CloudStorageSourceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerRetryConfig) DeepCopyInto(out *SchedulerRetryConfig) {
+	*out = *in
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerRetryConfig.
+func (in *SchedulerRetryConfig) DeepCopy() *SchedulerRetryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerRetryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretManagerRotationSource) DeepCopyInto(out *SecretManagerRotationSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretManagerRotationSource.
+func (in *SecretManagerRotationSource) DeepCopy() *SecretManagerRotationSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretManagerRotationSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretManagerRotationSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretManagerRotationSourceList) DeepCopyInto(out *SecretManagerRotationSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SecretManagerRotationSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretManagerRotationSourceList.
+func (in *SecretManagerRotationSourceList) DeepCopy() *SecretManagerRotationSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretManagerRotationSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SecretManagerRotationSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretManagerRotationSourceSpec) DeepCopyInto(out *SecretManagerRotationSourceSpec) {
+	*out = *in
+	in.PubSubSpec.DeepCopyInto(&out.PubSubSpec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretManagerRotationSourceSpec.
+func (in *SecretManagerRotationSourceSpec) DeepCopy() *SecretManagerRotationSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretManagerRotationSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretManagerRotationSourceStatus) DeepCopyInto(out *SecretManagerRotationSourceStatus) {
+	*out = *in
+	in.PubSubStatus.DeepCopyInto(&out.PubSubStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretManagerRotationSourceStatus.
+func (in *SecretManagerRotationSourceStatus) DeepCopy() *SecretManagerRotationSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretManagerRotationSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookAuth) DeepCopyInto(out *WebhookAuth) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookAuth.
+func (in *WebhookAuth) DeepCopy() *WebhookAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSource) DeepCopyInto(out *WebhookSource) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSource.
+func (in *WebhookSource) DeepCopy() *WebhookSource {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WebhookSource) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSourceList) DeepCopyInto(out *WebhookSourceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WebhookSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSourceList.
+func (in *WebhookSourceList) DeepCopy() *WebhookSourceList {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSourceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WebhookSourceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSourceSpec) DeepCopyInto(out *WebhookSourceSpec) {
+	*out = *in
+	in.SourceSpec.DeepCopyInto(&out.SourceSpec)
+	in.Auth.DeepCopyInto(&out.Auth)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSourceSpec.
+func (in *WebhookSourceSpec) DeepCopy() *WebhookSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookSourceStatus) DeepCopyInto(out *WebhookSourceStatus) {
+	*out = *in
+	in.SourceStatus.DeepCopyInto(&out.SourceStatus)
+	in.AddressStatus.DeepCopyInto(&out.AddressStatus)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookSourceStatus.
+func (in *WebhookSourceStatus) DeepCopy() *WebhookSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
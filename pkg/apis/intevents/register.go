@@ -27,9 +27,16 @@ const (
 	SourceLabelKey = "events.cloud.google.com/source-name"
 	// ChannelLabelKey is the label name used to identify the channel that owns a PS or Topic.
 	ChannelLabelKey = "events.cloud.google.com/channel-name"
+	// FlowLabelKey is the label name used to identify the Sequence or Parallel that owns a PS or Topic.
+	FlowLabelKey = "events.cloud.google.com/flow-name"
 )
 
 var (
+	// BrokerCellsResource represents a BrokerCell.
+	BrokerCellsResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "brokercells",
+	}
 	// PullSubscriptionsResource represents a PullSubscription.
 	PullSubscriptionsResource = schema.GroupResource{
 		Group:    GroupName,
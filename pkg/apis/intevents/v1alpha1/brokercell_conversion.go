@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// ConvertTo implements apis.Convertible.
+// Converts source (from v1alpha1.BrokerCell) into v1beta1.BrokerCell.
+func (source *BrokerCell) ConvertTo(_ context.Context, to apis.Convertible) error {
+	switch sink := to.(type) {
+	case *v1beta1.BrokerCell:
+		sink.ObjectMeta = source.ObjectMeta
+		sink.Spec.Istio = source.Spec.Istio
+		sink.Spec.Proxy = source.Spec.Proxy
+		sink.Spec.BrokerSelector = source.Spec.BrokerSelector
+		sink.Spec.CABundle = source.Spec.CABundle
+		sink.Spec.Components = v1beta1.ComponentsParametersSpec{
+			Ingress: (*v1beta1.ComponentParameters)(source.Spec.Components.Ingress),
+			Fanout:  (*v1beta1.ComponentParameters)(source.Spec.Components.Fanout),
+			Retry:   (*v1beta1.ComponentParameters)(source.Spec.Components.Retry),
+		}
+		sink.Status.Status = source.Status.Status
+		sink.Status.IngressTemplate = source.Status.IngressTemplate
+		sink.Status.BrokerCount = source.Status.BrokerCount
+		sink.Status.TriggerCount = source.Status.TriggerCount
+		sink.Status.Ingress = (*v1beta1.ComponentReplicas)(source.Status.Ingress)
+		sink.Status.Fanout = (*v1beta1.ComponentReplicas)(source.Status.Fanout)
+		sink.Status.Retry = (*v1beta1.ComponentReplicas)(source.Status.Retry)
+		return nil
+	default:
+		return fmt.Errorf("unknown conversion, got: %T", sink)
+	}
+}
+
+// ConvertFrom implements apis.Convertible.
+// Converts obj from v1beta1.BrokerCell into v1alpha1.BrokerCell.
+func (sink *BrokerCell) ConvertFrom(_ context.Context, from apis.Convertible) error {
+	switch source := from.(type) {
+	case *v1beta1.BrokerCell:
+		sink.ObjectMeta = source.ObjectMeta
+		sink.Spec.Istio = source.Spec.Istio
+		sink.Spec.Proxy = source.Spec.Proxy
+		sink.Spec.BrokerSelector = source.Spec.BrokerSelector
+		sink.Spec.CABundle = source.Spec.CABundle
+		sink.Spec.Components = ComponentsParametersSpec{
+			Ingress: (*ComponentParameters)(source.Spec.Components.Ingress),
+			Fanout:  (*ComponentParameters)(source.Spec.Components.Fanout),
+			Retry:   (*ComponentParameters)(source.Spec.Components.Retry),
+		}
+		sink.Status.Status = source.Status.Status
+		sink.Status.IngressTemplate = source.Status.IngressTemplate
+		sink.Status.BrokerCount = source.Status.BrokerCount
+		sink.Status.TriggerCount = source.Status.TriggerCount
+		sink.Status.Ingress = (*ComponentReplicas)(source.Status.Ingress)
+		sink.Status.Fanout = (*ComponentReplicas)(source.Status.Fanout)
+		sink.Status.Retry = (*ComponentReplicas)(source.Status.Retry)
+		return nil
+	default:
+		return fmt.Errorf("unknown conversion, got: %T", source)
+	}
+}
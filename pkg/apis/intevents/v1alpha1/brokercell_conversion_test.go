@@ -0,0 +1,135 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// completeBrokerCell is a BrokerCell with every field filled in, except
+// TypeMeta. TypeMeta is excluded because conversions do not convert it and
+// this variable was created to test conversions.
+var completeBrokerCell = &BrokerCell{
+	ObjectMeta: completeObjectMeta,
+	Spec: BrokerCellSpec{
+		Istio: &duckv1beta1.IstioSpec{
+			HoldApplicationUntilProxyStarts: true,
+			ExcludeOutboundPorts:            []int32{443},
+		},
+		Proxy: &duckv1beta1.ProxySpec{
+			HTTPSProxy: "http://proxy.example.com:3128",
+			NoProxy:    ".svc.cluster.local",
+		},
+		BrokerSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "payments"},
+		},
+		CABundle: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+			Key:                  "ca.crt",
+		},
+		Components: ComponentsParametersSpec{
+			Ingress: &ComponentParameters{
+				Image:       "gcr.io/example/dataplane:v1",
+				MinReplicas: ptr.Int32(2),
+				MaxReplicas: ptr.Int32(5),
+			},
+			Fanout: &ComponentParameters{
+				MaxReplicas: ptr.Int32(20),
+			},
+			Retry: &ComponentParameters{
+				MinReplicas: ptr.Int32(1),
+			},
+		},
+	},
+	Status: BrokerCellStatus{
+		Status: duckv1.Status{
+			ObservedGeneration: 7,
+			Conditions: duckv1.Conditions{{
+				Type:   apis.ConditionReady,
+				Status: corev1.ConditionTrue,
+			}},
+		},
+		IngressTemplate: "http://broker-ingress.cloud-run-events.svc.cluster.local/{namespace}/{name}",
+		BrokerCount:     3,
+		TriggerCount:    12,
+		Ingress:         &ComponentReplicas{Replicas: 2, ReadyReplicas: 2, MaxReplicas: 5},
+		Fanout:          &ComponentReplicas{Replicas: 1, ReadyReplicas: 1, MaxReplicas: 20},
+		Retry:           &ComponentReplicas{Replicas: 1, ReadyReplicas: 0, MaxReplicas: 10},
+	},
+}
+
+func TestBrokerCellConversionBadType(t *testing.T) {
+	good, bad := &BrokerCell{}, &Topic{}
+
+	if err := good.ConvertTo(context.Background(), bad); err == nil {
+		t.Errorf("ConvertTo() = %#v, wanted error", bad)
+	}
+
+	if err := good.ConvertFrom(context.Background(), bad); err == nil {
+		t.Errorf("ConvertFrom() = %#v, wanted error", good)
+	}
+}
+
+func TestBrokerCellConversion(t *testing.T) {
+	// Just one for now, just adding the for loop for ease of future changes.
+	versions := []apis.Convertible{&v1beta1.BrokerCell{}}
+
+	tests := []struct {
+		name string
+		in   *BrokerCell
+	}{{
+		name: "min configuration",
+		in: &BrokerCell{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "default",
+				Namespace:  "cloud-run-events",
+				Generation: 17,
+			},
+		},
+	}, {
+		name: "full configuration",
+		in:   completeBrokerCell,
+	}}
+	for _, test := range tests {
+		for _, version := range versions {
+			t.Run(test.name, func(t *testing.T) {
+				ver := version
+				if err := test.in.ConvertTo(context.Background(), ver); err != nil {
+					t.Errorf("ConvertTo() = %v", err)
+				}
+				got := &BrokerCell{}
+				if err := got.ConvertFrom(context.Background(), ver); err != nil {
+					t.Errorf("ConvertFrom() = %v", err)
+				}
+				if diff := cmp.Diff(test.in, got); diff != "" {
+					t.Errorf("roundtrip (-want, +got) = %v", diff)
+				}
+			})
+		}
+	}
+}
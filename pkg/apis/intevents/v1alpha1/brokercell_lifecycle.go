@@ -50,6 +50,12 @@ const (
 	// BrokerCellConditionTargetsConfig reports the readiness of the
 	// BrokerCell's targets configmap.
 	BrokerCellConditionTargetsConfig apis.ConditionType = "TargetsConfigReady"
+
+	// BrokerCellConditionCapacity reports whether the BrokerCell's data plane
+	// can still scale out. It is not part of the Ready condition: a
+	// BrokerCell at capacity keeps serving, but operators should consider
+	// splitting its Brokers across more BrokerCells.
+	BrokerCellConditionCapacity apis.ConditionType = "CapacityAvailable"
 )
 
 // GetCondition returns the condition currently associated with the given type, or nil.
@@ -128,3 +134,24 @@ func (bs *BrokerCellStatus) MarkTargetsConfigFailed(reason, format string, args
 func (bs *BrokerCellStatus) SetIngressTemplate(address string) {
 	bs.IngressTemplate = address
 }
+
+// MarkCapacityAvailable marks the BrokerCell's data plane as able to scale out.
+func (bs *BrokerCellStatus) MarkCapacityAvailable() {
+	brokerCellCondSet.Manage(bs).MarkTrue(BrokerCellConditionCapacity)
+}
+
+// MarkCapacityExhausted marks the BrokerCell's data plane as unable to scale
+// out any further.
+func (bs *BrokerCellStatus) MarkCapacityExhausted(reason, format string, args ...interface{}) {
+	brokerCellCondSet.Manage(bs).MarkFalse(BrokerCellConditionCapacity, reason, format, args...)
+}
+
+// NewComponentReplicas returns the replica counts of the provided Deployment,
+// capped by the maximum its autoscaler may scale it out to.
+func NewComponentReplicas(d *appsv1.Deployment, maxReplicas int32) *ComponentReplicas {
+	return &ComponentReplicas{
+		Replicas:      d.Status.Replicas,
+		ReadyReplicas: d.Status.ReadyReplicas,
+		MaxReplicas:   maxReplicas,
+	}
+}
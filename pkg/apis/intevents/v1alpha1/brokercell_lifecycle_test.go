@@ -314,3 +314,32 @@ func TestBrokerCellConditionStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestBrokerCellCapacityExhaustedStaysReady(t *testing.T) {
+	bs := TestHelper.ReadyBrokerCellStatus()
+	bs.MarkCapacityExhausted("MaxReplicasReached", "induced saturation")
+
+	if !bs.IsReady() {
+		t.Error("expected happy true, got false")
+	}
+	got := bs.GetCondition(BrokerCellConditionCapacity)
+	if got == nil {
+		t.Fatal("capacity condition is missing")
+	}
+	if got.Status != corev1.ConditionFalse || got.Severity != apis.ConditionSeverityInfo {
+		t.Errorf("unexpected capacity condition: %+v", got)
+	}
+}
+
+func TestNewComponentReplicas(t *testing.T) {
+	d := &appsv1.Deployment{
+		Status: appsv1.DeploymentStatus{
+			Replicas:      3,
+			ReadyReplicas: 2,
+		},
+	}
+	want := &ComponentReplicas{Replicas: 3, ReadyReplicas: 2, MaxReplicas: 10}
+	if diff := cmp.Diff(want, NewComponentReplicas(d, 10)); diff != "" {
+		t.Errorf("unexpected replicas (-want, +got) = %v", diff)
+	}
+}
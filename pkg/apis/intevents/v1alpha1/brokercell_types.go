@@ -17,12 +17,15 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/kmeta"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 )
 
 const (
@@ -63,10 +66,75 @@ var (
 
 	// Check that we can create OwnerReferences to a BrokerCell.
 	_ kmeta.OwnerRefable = (*BrokerCell)(nil)
+
+	// Check that BrokerCell can be converted to other versions.
+	_ apis.Convertible = (*BrokerCell)(nil)
 )
 
 // BrokerCellSpec defines the desired state of a Brokercell.
 type BrokerCellSpec struct {
+	// Istio configures the data plane pods to run alongside an injected
+	// Istio sidecar.
+	// +optional
+	Istio *duckv1beta1.IstioSpec `json:"istio,omitempty"`
+
+	// Proxy configures the egress proxy used by the data plane pods.
+	// Unset fields default to the controller's data plane proxy settings.
+	// +optional
+	Proxy *duckv1beta1.ProxySpec `json:"proxy,omitempty"`
+
+	// BrokerSelector selects the Brokers served by the BrokerCell by their
+	// labels. Brokers not selected by any BrokerCell are served by the
+	// default BrokerCell.
+	// +optional
+	BrokerSelector *metav1.LabelSelector `json:"brokerSelector,omitempty"`
+
+	// CABundle selects the key of a ConfigMap in the namespace of the
+	// BrokerCell holding PEM encoded CA certificates. The fanout and retry
+	// pods trust them on top of the system ones when delivering events, so
+	// that events can be delivered to sinks with certificates issued by a
+	// private CA.
+	// +optional
+	CABundle *corev1.ConfigMapKeySelector `json:"caBundle,omitempty"`
+
+	// Components configures the data plane components of the BrokerCell.
+	// Unset fields default to the controller's settings.
+	// +optional
+	Components ComponentsParametersSpec `json:"components,omitempty"`
+}
+
+// ComponentsParametersSpec configures each data plane component of a
+// BrokerCell.
+type ComponentsParametersSpec struct {
+	// Ingress configures the ingress deployment.
+	// +optional
+	Ingress *ComponentParameters `json:"ingress,omitempty"`
+
+	// Fanout configures the fanout deployment.
+	// +optional
+	Fanout *ComponentParameters `json:"fanout,omitempty"`
+
+	// Retry configures the retry deployment.
+	// +optional
+	Retry *ComponentParameters `json:"retry,omitempty"`
+}
+
+// ComponentParameters configures a data plane component of a BrokerCell.
+type ComponentParameters struct {
+	// Image is the image of the component's container. It defaults to the
+	// controller's data plane image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// MinReplicas is the lower limit of replicas the component can be scaled
+	// in to by its autoscaler. It defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of replicas the component can be scaled
+	// out to by its autoscaler. It defaults to 10.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
 }
 
 // BrokerCellStatus represents the current state of a BrokerCell.
@@ -81,6 +149,41 @@ type BrokerCellStatus struct {
 	// `namespace`.
 	// Example: "http://broker-ingress.cloud-run-events.svc.cluster.local/{namespace}/{name}"
 	IngressTemplate string `json:"ingressTemplate,omitempty"`
+
+	// BrokerCount is the number of Brokers served by the BrokerCell.
+	// +optional
+	BrokerCount int32 `json:"brokerCount,omitempty"`
+
+	// TriggerCount is the number of Triggers served by the BrokerCell.
+	// +optional
+	TriggerCount int32 `json:"triggerCount,omitempty"`
+
+	// Ingress reports the replicas of the ingress deployment.
+	// +optional
+	Ingress *ComponentReplicas `json:"ingress,omitempty"`
+
+	// Fanout reports the replicas of the fanout deployment.
+	// +optional
+	Fanout *ComponentReplicas `json:"fanout,omitempty"`
+
+	// Retry reports the replicas of the retry deployment.
+	// +optional
+	Retry *ComponentReplicas `json:"retry,omitempty"`
+}
+
+// ComponentReplicas reports the replica counts of a BrokerCell data plane
+// component.
+type ComponentReplicas struct {
+	// Replicas is the number of replicas of the component's deployment.
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of ready replicas of the component's
+	// deployment.
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// MaxReplicas is the upper limit of replicas the component can be scaled
+	// out to by its autoscaler.
+	MaxReplicas int32 `json:"maxReplicas"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -19,11 +19,57 @@ package v1alpha1
 import (
 	"context"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 )
 
 // Validate verifies that the BrokerCell is valid.
 func (bc *BrokerCell) Validate(ctx context.Context) *apis.FieldError {
-	// The BrokerCell doesn't have any validations.
-	return nil
+	errs := bc.Spec.Validate(ctx).ViaField("spec")
+	return duckv1beta1.ValidateCatchUpAnnotation(ctx, bc.Annotations, errs)
+}
+
+// Validate verifies that the BrokerCellSpec is valid.
+func (bcs *BrokerCellSpec) Validate(ctx context.Context) *apis.FieldError {
+	errs := bcs.Istio.Validate(ctx).ViaField("istio")
+	if bcs.BrokerSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(bcs.BrokerSelector); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(err.Error(), "brokerSelector"))
+		}
+	}
+	if bcs.CABundle != nil {
+		if bcs.CABundle.Name == "" {
+			errs = errs.Also(apis.ErrMissingField("caBundle.name"))
+		}
+		if bcs.CABundle.Key == "" {
+			errs = errs.Also(apis.ErrMissingField("caBundle.key"))
+		}
+	}
+	errs = errs.Also(bcs.Proxy.Validate(ctx).ViaField("proxy"))
+	errs = errs.Also(bcs.Components.Ingress.Validate(ctx).ViaField("components", "ingress"))
+	errs = errs.Also(bcs.Components.Fanout.Validate(ctx).ViaField("components", "fanout"))
+	return errs.Also(bcs.Components.Retry.Validate(ctx).ViaField("components", "retry"))
+}
+
+// Validate verifies that the ComponentParameters are valid.
+func (cp *ComponentParameters) Validate(ctx context.Context) *apis.FieldError {
+	if cp == nil {
+		return nil
+	}
+	var errs *apis.FieldError
+	if cp.MinReplicas != nil && *cp.MinReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*cp.MinReplicas, "minReplicas"))
+	}
+	if cp.MaxReplicas != nil && *cp.MaxReplicas < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*cp.MaxReplicas, "maxReplicas"))
+	}
+	if cp.MinReplicas != nil && cp.MaxReplicas != nil && *cp.MinReplicas > *cp.MaxReplicas {
+		errs = errs.Also(&apis.FieldError{
+			Message: "minReplicas must not be greater than maxReplicas",
+			Paths:   []string{"minReplicas", "maxReplicas"},
+		})
+	}
+	return errs
 }
@@ -19,6 +19,12 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 )
 
 func TestBrokerCell_Validate(t *testing.T) {
@@ -27,3 +33,111 @@ func TestBrokerCell_Validate(t *testing.T) {
 		t.Errorf("expected nil, got %v", err)
 	}
 }
+
+func TestBrokerCell_ValidateIstio(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Istio: &duckv1beta1.IstioSpec{
+				ExcludeOutboundPorts: []int32{443},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Istio.ExcludeOutboundPorts = []int32{0}
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid port, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateProxy(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Proxy: &duckv1beta1.ProxySpec{
+				HTTPSProxy: "http://proxy:3128",
+				NoProxy:    ".svc.cluster.local",
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Proxy.NoProxy = "10.0.0.0/33"
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid no proxy CIDR, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateBrokerSelector(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			BrokerSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "payments"},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.BrokerSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+		Key:      "tier",
+		Operator: "Near",
+	}}
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid selector operator, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateCABundle(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			CABundle: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+				Key:                  "ca.crt",
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.CABundle.Key = ""
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for CA bundle without key, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateComponents(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Components: ComponentsParametersSpec{
+				Ingress: &ComponentParameters{
+					Image:       "gcr.io/example/dataplane:v1",
+					MinReplicas: ptr.Int32(2),
+					MaxReplicas: ptr.Int32(5),
+				},
+				Retry: &ComponentParameters{
+					MaxReplicas: ptr.Int32(1),
+				},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Components.Ingress.MinReplicas = ptr.Int32(6)
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for minReplicas greater than maxReplicas, got nil")
+	}
+
+	bc.Spec.Components.Ingress.MinReplicas = nil
+	bc.Spec.Components.Retry.MaxReplicas = ptr.Int32(0)
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for zero maxReplicas, got nil")
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEventSchema_SetDefaults(t *testing.T) {
+	es := EventSchema{}
+	es.SetDefaults(context.TODO())
+	if got, want := es.Spec.Action, EventSchemaActionReport; got != want {
+		t.Errorf("Action = %q, want %q", got, want)
+	}
+
+	es.Spec.Action = EventSchemaActionReject
+	es.SetDefaults(context.TODO())
+	if got, want := es.Spec.Action, EventSchemaActionReject; got != want {
+		t.Errorf("Action = %q, want %q", got, want)
+	}
+}
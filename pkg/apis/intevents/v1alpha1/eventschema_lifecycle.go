@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+var eventSchemaCondSet = apis.NewLivingConditionSet(
+	EventSchemaConditionSchemaValid,
+	EventSchemaConditionPublished,
+)
+
+const (
+	// EventSchemaConditionReady has status true when all subconditions below
+	// have been set to True.
+	EventSchemaConditionReady apis.ConditionType = apis.ConditionReady
+
+	// EventSchemaConditionSchemaValid reports whether the schema is a valid
+	// JSON schema.
+	EventSchemaConditionSchemaValid apis.ConditionType = "SchemaValid"
+
+	// EventSchemaConditionPublished reports whether the schema is published
+	// to the data plane, which validates the events against it.
+	EventSchemaConditionPublished apis.ConditionType = "Published"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ess *EventSchemaStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return eventSchemaCondSet.Manage(ess).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (ess *EventSchemaStatus) GetTopLevelCondition() *apis.Condition {
+	return eventSchemaCondSet.Manage(ess).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ess *EventSchemaStatus) IsReady() bool {
+	return eventSchemaCondSet.Manage(ess).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ess *EventSchemaStatus) InitializeConditions() {
+	eventSchemaCondSet.Manage(ess).InitializeConditions()
+}
+
+// MarkSchemaValid marks the schema as a valid JSON schema.
+func (ess *EventSchemaStatus) MarkSchemaValid() {
+	eventSchemaCondSet.Manage(ess).MarkTrue(EventSchemaConditionSchemaValid)
+}
+
+// MarkSchemaInvalid marks the schema as an invalid JSON schema.
+func (ess *EventSchemaStatus) MarkSchemaInvalid(reason, format string, args ...interface{}) {
+	eventSchemaCondSet.Manage(ess).MarkFalse(EventSchemaConditionSchemaValid, reason, format, args...)
+}
+
+// MarkPublished marks the schema as published to the data plane.
+func (ess *EventSchemaStatus) MarkPublished() {
+	eventSchemaCondSet.Manage(ess).MarkTrue(EventSchemaConditionPublished)
+}
+
+// MarkPublishFailed marks the schema as not published to the data plane.
+func (ess *EventSchemaStatus) MarkPublishFailed(reason, format string, args ...interface{}) {
+	eventSchemaCondSet.Manage(ess).MarkFalse(EventSchemaConditionPublished, reason, format, args...)
+}
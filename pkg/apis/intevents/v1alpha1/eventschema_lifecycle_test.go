@@ -0,0 +1,51 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEventSchemaStatus_Lifecycle(t *testing.T) {
+	ess := &EventSchemaStatus{}
+	ess.InitializeConditions()
+	if got := ess.GetTopLevelCondition().Status; got != corev1.ConditionUnknown {
+		t.Errorf("initial Ready = %v, want %v", got, corev1.ConditionUnknown)
+	}
+
+	ess.MarkSchemaValid()
+	ess.MarkPublished()
+	if !ess.IsReady() {
+		t.Errorf("IsReady() = false after the schema was published, want true")
+	}
+
+	ess.MarkPublishFailed("Conflict", "conflict")
+	if ess.IsReady() {
+		t.Error("IsReady() = true after publishing failed, want false")
+	}
+	if got := ess.GetCondition(EventSchemaConditionPublished).Reason; got != "Conflict" {
+		t.Errorf("Published reason = %q, want %q", got, "Conflict")
+	}
+
+	ess.MarkPublished()
+	ess.MarkSchemaInvalid("InvalidSchema", "invalid")
+	if got := ess.GetTopLevelCondition().Status; got != corev1.ConditionFalse {
+		t.Errorf("Ready = %v with an invalid schema, want %v", got, corev1.ConditionFalse)
+	}
+}
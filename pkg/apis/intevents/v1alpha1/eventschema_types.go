@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventSchema is the JSON schema of the data of the events of a type sent to
+// the Brokers of its namespace. The ingress of the Brokers validates the
+// events of that type against it.
+type EventSchema struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the EventSchema.
+	Spec EventSchemaSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the EventSchema. This data may
+	// be out of date.
+	// +optional
+	Status EventSchemaStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that EventSchema can be validated and can be defaulted.
+	_ apis.Validatable = (*EventSchema)(nil)
+	_ apis.Defaultable = (*EventSchema)(nil)
+
+	// Check that EventSchema can return its spec untyped.
+	_ apis.HasSpec = (*EventSchema)(nil)
+
+	_ runtime.Object = (*EventSchema)(nil)
+
+	// Check that we can create OwnerReferences to an EventSchema.
+	_ kmeta.OwnerRefable = (*EventSchema)(nil)
+)
+
+// EventSchemaAction is what the ingress does with the events that don't
+// conform to their schema.
+type EventSchemaAction string
+
+const (
+	// EventSchemaActionReport accepts the events that don't conform to their
+	// schema, and only counts them in the ingress metrics.
+	EventSchemaActionReport EventSchemaAction = "Report"
+
+	// EventSchemaActionReject rejects the events that don't conform to their
+	// schema with a 400 Bad Request, on top of counting them.
+	EventSchemaActionReject EventSchemaAction = "Reject"
+)
+
+// EventSchemaSpec defines the desired state of an EventSchema.
+type EventSchemaSpec struct {
+	// Type is the CloudEvents type of the events the schema applies to.
+	Type string `json:"type"`
+
+	// Schema is the JSON schema the data of the events must conform to.
+	Schema runtime.RawExtension `json:"schema"`
+
+	// Action is what the ingress does with the events that don't conform to
+	// the schema, either Report or Reject. Defaults to Report.
+	// +optional
+	Action EventSchemaAction `json:"action,omitempty"`
+
+	// ValidateOnDelivery also validates the events in the fanout, before they
+	// are delivered to Triggers, e.g. to find out how many of the events
+	// published before the schema changed don't conform to it. Events are
+	// delivered whether they conform or not.
+	// +optional
+	ValidateOnDelivery bool `json:"validateOnDelivery,omitempty"`
+}
+
+// EventSchemaStatus represents the current state of an EventSchema.
+type EventSchemaStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// EventSchemaList is a collection of EventSchemas.
+type EventSchemaList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []EventSchema `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for EventSchemas.
+func (es *EventSchema) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("EventSchema")
+}
+
+// GetUntypedSpec returns the spec of the EventSchema.
+func (es *EventSchema) GetUntypedSpec() interface{} {
+	return es.Spec
+}
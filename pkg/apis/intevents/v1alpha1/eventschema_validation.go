@@ -0,0 +1,49 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/utils/jsonschema"
+)
+
+// Validate verifies that the EventSchema is valid.
+func (es *EventSchema) Validate(ctx context.Context) *apis.FieldError {
+	return es.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate verifies that the EventSchemaSpec is valid.
+func (ess *EventSchemaSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	if ess.Type == "" {
+		errs = errs.Also(apis.ErrMissingField("type"))
+	}
+	if len(ess.Schema.Raw) == 0 {
+		errs = errs.Also(apis.ErrMissingField("schema"))
+	} else if _, err := jsonschema.Compile(ess.Schema.Raw); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(err.Error(), "schema"))
+	}
+	switch ess.Action {
+	case EventSchemaActionReport, EventSchemaActionReject:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(ess.Action, "action"))
+	}
+	return errs
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestEventSchema_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    EventSchemaSpec
+		wantErr bool
+	}{
+		"valid": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Schema: runtime.RawExtension{Raw: []byte(`{"type":"object","required":["id"]}`)},
+				Action: EventSchemaActionReject,
+			},
+		},
+		"missing type": {
+			spec: EventSchemaSpec{
+				Schema: runtime.RawExtension{Raw: []byte(`{"type":"object"}`)},
+				Action: EventSchemaActionReport,
+			},
+			wantErr: true,
+		},
+		"missing schema": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Action: EventSchemaActionReport,
+			},
+			wantErr: true,
+		},
+		"invalid schema": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Schema: runtime.RawExtension{Raw: []byte(`{"type":"not-a-type"}`)},
+				Action: EventSchemaActionReport,
+			},
+			wantErr: true,
+		},
+		"remote reference": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Schema: runtime.RawExtension{Raw: []byte(`{"properties":{"id":{"$ref":"http://169.254.169.254/schema.json"}}}`)},
+				Action: EventSchemaActionReport,
+			},
+			wantErr: true,
+		},
+		"file reference": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Schema: runtime.RawExtension{Raw: []byte(`{"properties":{"id":{"$ref":"file:///etc/passwd"}}}`)},
+				Action: EventSchemaActionReport,
+			},
+			wantErr: true,
+		},
+		"invalid action": {
+			spec: EventSchemaSpec{
+				Type:   "com.example.order.created",
+				Schema: runtime.RawExtension{Raw: []byte(`{"type":"object"}`)},
+				Action: "Drop",
+			},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			es := EventSchema{Spec: tc.spec}
+			if err := es.Validate(context.TODO()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -34,8 +34,30 @@ func (source *PullSubscription) ConvertTo(_ context.Context, to apis.Convertible
 		sink.Spec.PubSubSpec = convert.ToV1beta1PubSubSpec(source.Spec.PubSubSpec)
 		sink.Spec.Topic = source.Spec.Topic
 		sink.Spec.AckDeadline = source.Spec.AckDeadline
+		sink.Spec.CreateTopic = source.Spec.CreateTopic
+		if tc := source.Spec.TopicConfig; tc != nil {
+			sink.Spec.TopicConfig = &v1beta1.TopicConfig{
+				Labels:                    tc.Labels,
+				AllowedPersistenceRegions: tc.AllowedPersistenceRegions,
+				KMSKeyName:                tc.KMSKeyName,
+				DeletionPolicy:            v1beta1.TopicDeletionPolicy(tc.DeletionPolicy),
+			}
+		}
 		sink.Spec.RetainAckedMessages = source.Spec.RetainAckedMessages
+		sink.Spec.EnableMessageOrdering = source.Spec.EnableMessageOrdering
 		sink.Spec.RetentionDuration = source.Spec.RetentionDuration
+		if source.Spec.RetryPolicy != nil {
+			sink.Spec.RetryPolicy = &v1beta1.RetryPolicy{
+				MinimumBackoff: source.Spec.RetryPolicy.MinimumBackoff,
+				MaximumBackoff: source.Spec.RetryPolicy.MaximumBackoff,
+			}
+		}
+		if source.Spec.DeadLetterPolicy != nil {
+			sink.Spec.DeadLetterPolicy = &v1beta1.DeadLetterPolicy{
+				DeadLetterTopic:     source.Spec.DeadLetterPolicy.DeadLetterTopic,
+				MaxDeliveryAttempts: source.Spec.DeadLetterPolicy.MaxDeliveryAttempts,
+			}
+		}
 		sink.Spec.Transformer = source.Spec.Transformer
 		if mode, err := convertToV1beta1ModeType(source.Spec.Mode); err != nil {
 			return err
@@ -43,9 +65,24 @@ func (source *PullSubscription) ConvertTo(_ context.Context, to apis.Convertible
 			sink.Spec.Mode = mode
 		}
 		sink.Spec.AdapterType = source.Spec.AdapterType
+		sink.Spec.Istio = source.Spec.Istio
+		sink.Spec.Proxy = source.Spec.Proxy
+		sink.Spec.Shutdown = source.Spec.Shutdown
+		sink.Spec.SinkTLS = source.Spec.SinkTLS
+		sink.Spec.RateLimit = source.Spec.RateLimit
+		if lc := source.Spec.LiteConfig; lc != nil {
+			sink.Spec.LiteConfig = &v1beta1.LiteConfig{
+				Location:           lc.Location,
+				Partitions:         lc.Partitions,
+				PerPartitionBytes:  lc.PerPartitionBytes,
+				PublishMiBPerSec:   lc.PublishMiBPerSec,
+				SubscribeMiBPerSec: lc.SubscribeMiBPerSec,
+			}
+		}
 		sink.Status.PubSubStatus = convert.ToV1beta1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.TransformerURI = source.Status.TransformerURI
 		sink.Status.SubscriptionID = source.Status.SubscriptionID
+		sink.Status.CreatedTopicID = source.Status.CreatedTopicID
 		return nil
 	default:
 		return fmt.Errorf("unknown conversion, got: %T", sink)
@@ -62,8 +99,30 @@ func (sink *PullSubscription) ConvertFrom(_ context.Context, from apis.Convertib
 		sink.Spec.PubSubSpec = convert.FromV1beta1PubSubSpec(source.Spec.PubSubSpec)
 		sink.Spec.Topic = source.Spec.Topic
 		sink.Spec.AckDeadline = source.Spec.AckDeadline
+		sink.Spec.CreateTopic = source.Spec.CreateTopic
+		if tc := source.Spec.TopicConfig; tc != nil {
+			sink.Spec.TopicConfig = &TopicConfig{
+				Labels:                    tc.Labels,
+				AllowedPersistenceRegions: tc.AllowedPersistenceRegions,
+				KMSKeyName:                tc.KMSKeyName,
+				DeletionPolicy:            TopicDeletionPolicy(tc.DeletionPolicy),
+			}
+		}
 		sink.Spec.RetainAckedMessages = source.Spec.RetainAckedMessages
+		sink.Spec.EnableMessageOrdering = source.Spec.EnableMessageOrdering
 		sink.Spec.RetentionDuration = source.Spec.RetentionDuration
+		if source.Spec.RetryPolicy != nil {
+			sink.Spec.RetryPolicy = &RetryPolicy{
+				MinimumBackoff: source.Spec.RetryPolicy.MinimumBackoff,
+				MaximumBackoff: source.Spec.RetryPolicy.MaximumBackoff,
+			}
+		}
+		if source.Spec.DeadLetterPolicy != nil {
+			sink.Spec.DeadLetterPolicy = &DeadLetterPolicy{
+				DeadLetterTopic:     source.Spec.DeadLetterPolicy.DeadLetterTopic,
+				MaxDeliveryAttempts: source.Spec.DeadLetterPolicy.MaxDeliveryAttempts,
+			}
+		}
 		sink.Spec.Transformer = source.Spec.Transformer
 		if mode, err := convertFromV1beta1ModeType(source.Spec.Mode); err != nil {
 			return err
@@ -71,9 +130,24 @@ func (sink *PullSubscription) ConvertFrom(_ context.Context, from apis.Convertib
 			sink.Spec.Mode = mode
 		}
 		sink.Spec.AdapterType = source.Spec.AdapterType
+		sink.Spec.Istio = source.Spec.Istio
+		sink.Spec.Proxy = source.Spec.Proxy
+		sink.Spec.Shutdown = source.Spec.Shutdown
+		sink.Spec.SinkTLS = source.Spec.SinkTLS
+		sink.Spec.RateLimit = source.Spec.RateLimit
+		if lc := source.Spec.LiteConfig; lc != nil {
+			sink.Spec.LiteConfig = &LiteConfig{
+				Location:           lc.Location,
+				Partitions:         lc.Partitions,
+				PerPartitionBytes:  lc.PerPartitionBytes,
+				PublishMiBPerSec:   lc.PublishMiBPerSec,
+				SubscribeMiBPerSec: lc.SubscribeMiBPerSec,
+			}
+		}
 		sink.Status.PubSubStatus = convert.FromV1beta1PubSubStatus(source.Status.PubSubStatus)
 		sink.Status.TransformerURI = source.Status.TransformerURI
 		sink.Status.SubscriptionID = source.Status.SubscriptionID
+		sink.Status.CreatedTopicID = source.Status.CreatedTopicID
 		return nil
 	default:
 		return fmt.Errorf("unknown conversion, got: %T", source)
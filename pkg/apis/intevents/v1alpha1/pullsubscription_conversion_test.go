@@ -26,7 +26,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -36,7 +38,12 @@ import (
 // filled in.
 var (
 	seconds  = int64(314)
+	burst    = int32(500)
 	duration = "30s"
+	attempts = int32(10)
+
+	liteCapacity      = int32(8)
+	perPartitionBytes = resource.MustParse("32Gi")
 
 	completeObjectMeta = metav1.ObjectMeta{
 		Name:            "name",
@@ -150,19 +157,61 @@ var (
 	completePullSubscription = &PullSubscription{
 		ObjectMeta: completeObjectMeta,
 		Spec: PullSubscriptionSpec{
-			PubSubSpec:          completePubSubSpec,
-			Topic:               "topic",
-			AckDeadline:         &duration,
-			RetainAckedMessages: false,
-			RetentionDuration:   &duration,
-			Transformer:         &completeDestination,
-			Mode:                ModeCloudEventsBinary,
-			AdapterType:         "adapterType",
+			PubSubSpec:  completePubSubSpec,
+			Topic:       "topic",
+			CreateTopic: true,
+			TopicConfig: &TopicConfig{
+				Labels:                    map[string]string{"team": "orders"},
+				AllowedPersistenceRegions: []string{"us-central1"},
+				KMSKeyName:                "projects/P/locations/L/keyRings/R/cryptoKeys/K",
+				DeletionPolicy:            TopicDeletionPolicyDelete,
+			},
+			AckDeadline:           &duration,
+			RetainAckedMessages:   false,
+			EnableMessageOrdering: true,
+			RetentionDuration:     &duration,
+			RetryPolicy: &RetryPolicy{
+				MinimumBackoff: &duration,
+				MaximumBackoff: &duration,
+			},
+			DeadLetterPolicy: &DeadLetterPolicy{
+				DeadLetterTopic:     "projects/other/topics/dead-letter",
+				MaxDeliveryAttempts: &attempts,
+			},
+			Transformer: &completeDestination,
+			Mode:        ModeCloudEventsBinary,
+			AdapterType: "adapterType",
+			Istio: &duckv1beta1.IstioSpec{
+				HoldApplicationUntilProxyStarts: true,
+				ExcludeOutboundPorts:            []int32{443},
+			},
+			Proxy: &duckv1beta1.ProxySpec{
+				HTTPSProxy: "http://proxy:3128",
+			},
+			Shutdown: &duckv1beta1.ShutdownSpec{
+				TerminationGracePeriodSeconds: &seconds,
+				Drain:                         true,
+			},
+			SinkTLS: &duckv1beta1.SinkTLSSpec{
+				InsecureSkipVerify: true,
+			},
+			RateLimit: &duckv1beta1.RateLimitSpec{
+				EventsPerSecond: 100,
+				Burst:           &burst,
+			},
+			LiteConfig: &LiteConfig{
+				Location:           "us-central1-a",
+				Partitions:         &liteCapacity,
+				PerPartitionBytes:  &perPartitionBytes,
+				PublishMiBPerSec:   &liteCapacity,
+				SubscribeMiBPerSec: &liteCapacity,
+			},
 		},
 		Status: PullSubscriptionStatus{
 			PubSubStatus:   completePubSubStatus,
 			TransformerURI: &completeURL,
 			SubscriptionID: "subscriptionID",
+			CreatedTopicID: "topic",
 		},
 	}
 )
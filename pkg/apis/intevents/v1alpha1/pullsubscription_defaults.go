@@ -31,17 +31,27 @@ import (
 const (
 	defaultRetentionDuration = 7 * 24 * time.Hour
 	defaultAckDeadline       = 30 * time.Second
+
+	// The backoff Pub/Sub applies when a retry policy leaves it unset.
+	defaultMinimumBackoff = 10 * time.Second
+	defaultMaximumBackoff = 600 * time.Second
+
+	// The delivery attempts Pub/Sub allows when a dead letter policy leaves
+	// them unset.
+	defaultMaxDeliveryAttempts = 5
 )
 
 func (s *PullSubscription) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &s.ObjectMeta, SchemeGroupVersion)
 	duckv1alpha1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
 
 func (ss *PullSubscriptionSpec) SetDefaults(ctx context.Context) {
-	if ss.AckDeadline == nil {
+	// Pub/Sub Lite subscriptions have no ack deadline.
+	if ss.AckDeadline == nil && ss.LiteConfig == nil {
 		ackDeadline := defaultAckDeadline
 		ss.AckDeadline = ptr.String(ackDeadline.String())
 	}
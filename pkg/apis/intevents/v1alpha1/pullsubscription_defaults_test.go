@@ -27,6 +27,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -67,7 +68,8 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 		want: &PullSubscription{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: PullSubscriptionSpec{
@@ -99,7 +101,8 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 		want: &PullSubscription{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: PullSubscriptionSpec{
@@ -126,7 +129,8 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 		want: &PullSubscription{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: PullSubscriptionSpec{
@@ -151,7 +155,8 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 		want: &PullSubscription{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: PullSubscriptionSpec{
@@ -176,7 +181,8 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 		want: &PullSubscription{
 			ObjectMeta: metav1.ObjectMeta{
 				Annotations: map[string]string{
-					duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+					duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+					duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 				},
 			},
 			Spec: PullSubscriptionSpec{
@@ -208,7 +214,8 @@ func TestPullSubscriptionDefaults_NoChange(t *testing.T) {
 	want := &PullSubscription{
 		ObjectMeta: metav1.ObjectMeta{
 			Annotations: map[string]string{
-				duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+				duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 			},
 		},
 		Spec: PullSubscriptionSpec{
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -27,6 +28,7 @@ import (
 	"knative.dev/pkg/ptr"
 
 	"github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 )
 
@@ -570,6 +572,42 @@ func TestPubSubCheckImmutableFields(t *testing.T) {
 			},
 			allowed: true,
 		},
+		"Istio changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Istio = &duckv1beta1.IstioSpec{HoldApplicationUntilProxyStarts: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Proxy changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Proxy = &duckv1beta1.ProxySpec{HTTPSProxy: "http://proxy:3128"}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Shutdown changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Shutdown = &duckv1beta1.ShutdownSpec{Drain: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"SinkTLS changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.SinkTLS = &duckv1beta1.SinkTLSSpec{InsecureSkipVerify: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
 		"no change": {
 			orig:    &pullSubscriptionSpec,
 			updated: pullSubscriptionSpec,
@@ -612,3 +650,36 @@ func TestPubSubCheckImmutableFields(t *testing.T) {
 		})
 	}
 }
+
+func TestPullSubscriptionValidateCatchUpAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name: "no annotation",
+	}, {
+		name:        "catch-up within the max duration",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)},
+	}, {
+		name:        "catch-up beyond the max duration",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(duckv1beta1.MaxCatchUpDuration + time.Hour).Format(time.RFC3339)},
+		wantErr:     true,
+	}, {
+		name:        "invalid catch-up",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: "1h"},
+		wantErr:     true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := &PullSubscription{
+				ObjectMeta: v1.ObjectMeta{Name: "ps", Namespace: "ns", Annotations: tc.annotations},
+				Spec:       *pullSubscriptionSpec.DeepCopy(),
+			}
+			if err := ps.Validate(apis.WithinCreate(context.Background())); tc.wantErr != (err != nil) {
+				t.Errorf("Validate() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
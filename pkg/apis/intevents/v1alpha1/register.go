@@ -46,6 +46,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&BrokerCell{},
 		&BrokerCellList{},
+		&EventSchema{},
+		&EventSchemaList{},
 		&PullSubscription{},
 		&PullSubscriptionList{},
 		&Topic{},
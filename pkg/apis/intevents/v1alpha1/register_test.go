@@ -87,6 +87,8 @@ func TestAddKnownTypes(t *testing.T) {
 	want := []string{
 		"BrokerCell",
 		"BrokerCellList",
+		"EventSchema",
+		"EventSchemaList",
 		"PullSubscription",
 		"PullSubscriptionList",
 		"Topic",
@@ -81,5 +81,6 @@ func (t testHelper) ReadyBrokerCellStatus() *BrokerCellStatus {
 	bs.PropagateFanoutAvailability(t.AvailableDeployment())
 	bs.PropagateRetryAvailability(t.AvailableDeployment())
 	bs.MarkTargetsConfigReady()
+	bs.MarkCapacityAvailable()
 	return bs
 }
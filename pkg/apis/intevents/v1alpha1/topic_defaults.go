@@ -39,7 +39,8 @@ var (
 func (t *Topic) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, t.ObjectMeta)
 	t.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&t.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &t.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &t.ObjectMeta, SchemeGroupVersion)
 }
 
 func (ts *TopicSpec) SetDefaults(ctx context.Context) {
@@ -53,10 +54,14 @@ func (ts *TopicSpec) SetDefaults(ctx context.Context) {
 		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
 		return
 	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
 	if ts.ServiceAccountName == "" &&
 		(ts.Secret == nil || equality.Semantic.DeepEqual(ts.Secret, &corev1.SecretKeySelector{})) {
-		ts.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
-		ts.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
+		ts.ServiceAccountName = rd.ServiceAccountName
+		ts.Secret = rd.Secret
+	}
+	if ts.Project == "" {
+		ts.Project = rd.Project
 	}
 
 	if ts.EnablePublisher == nil {
@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 )
 
@@ -40,7 +41,8 @@ func TestTopicDefaults(t *testing.T) {
 			want: &Topic{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: TopicSpec{
@@ -56,7 +58,8 @@ func TestTopicDefaults(t *testing.T) {
 			got: &Topic{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: TopicSpec{}},
@@ -66,7 +69,8 @@ func TestTopicDefaults(t *testing.T) {
 			want: &Topic{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 				Spec: TopicSpec{
@@ -75,7 +79,8 @@ func TestTopicDefaults(t *testing.T) {
 			got: &Topic{
 				ObjectMeta: metav1.ObjectMeta{
 					Annotations: map[string]string{
-						duckv1alpha1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+						duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+						duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 					},
 				},
 			},
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+const (
+	// Annotations to tell if the brokercell is created automatically by the GCP broker controller.
+	CreatorKey = "internal.events.cloud.google.com/creator"
+	Creator    = "googlecloud"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BrokerCell manages the set of data plane components servicing
+// one or more Broker objects and their associated Triggers.
+type BrokerCell struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the BrokerCell.
+	Spec BrokerCellSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the BrokerCell. This data may be out of
+	// date.
+	// +optional
+	Status BrokerCellStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that BrokerCell can be validated, can be defaulted, and has immutable fields.
+	_ apis.Validatable = (*BrokerCell)(nil)
+	_ apis.Defaultable = (*BrokerCell)(nil)
+
+	// Check that BrokerCell can return its spec untyped.
+	_ apis.HasSpec = (*BrokerCell)(nil)
+
+	_ runtime.Object = (*BrokerCell)(nil)
+
+	// Check that we can create OwnerReferences to a BrokerCell.
+	_ kmeta.OwnerRefable = (*BrokerCell)(nil)
+
+	// Check that BrokerCell can be converted to other versions.
+	_ apis.Convertible = (*BrokerCell)(nil)
+)
+
+// BrokerCellSpec defines the desired state of a Brokercell.
+type BrokerCellSpec struct {
+	// Istio configures the data plane pods to run alongside an injected
+	// Istio sidecar.
+	// +optional
+	Istio *duckv1beta1.IstioSpec `json:"istio,omitempty"`
+
+	// Proxy configures the egress proxy used by the data plane pods.
+	// Unset fields default to the controller's data plane proxy settings.
+	// +optional
+	Proxy *duckv1beta1.ProxySpec `json:"proxy,omitempty"`
+
+	// BrokerSelector selects the Brokers served by the BrokerCell by their
+	// labels. Brokers not selected by any BrokerCell are served by the
+	// default BrokerCell.
+	// +optional
+	BrokerSelector *metav1.LabelSelector `json:"brokerSelector,omitempty"`
+
+	// CABundle selects the key of a ConfigMap in the namespace of the
+	// BrokerCell holding PEM encoded CA certificates. The fanout and retry
+	// pods trust them on top of the system ones when delivering events, so
+	// that events can be delivered to sinks with certificates issued by a
+	// private CA.
+	// +optional
+	CABundle *corev1.ConfigMapKeySelector `json:"caBundle,omitempty"`
+
+	// Components configures the data plane components of the BrokerCell.
+	// Unset fields default to the controller's settings.
+	// +optional
+	Components ComponentsParametersSpec `json:"components,omitempty"`
+}
+
+// ComponentsParametersSpec configures each data plane component of a
+// BrokerCell.
+type ComponentsParametersSpec struct {
+	// Ingress configures the ingress deployment.
+	// +optional
+	Ingress *ComponentParameters `json:"ingress,omitempty"`
+
+	// Fanout configures the fanout deployment.
+	// +optional
+	Fanout *ComponentParameters `json:"fanout,omitempty"`
+
+	// Retry configures the retry deployment.
+	// +optional
+	Retry *ComponentParameters `json:"retry,omitempty"`
+}
+
+// ComponentParameters configures a data plane component of a BrokerCell.
+type ComponentParameters struct {
+	// Image is the image of the component's container. It defaults to the
+	// controller's data plane image.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// MinReplicas is the lower limit of replicas the component can be scaled
+	// in to by its autoscaler. It defaults to 1.
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit of replicas the component can be scaled
+	// out to by its autoscaler. It defaults to 10.
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// BrokerCellStatus represents the current state of a BrokerCell.
+type BrokerCellStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+
+	// IngressTemplate contains a URI template as specified by RFC6570 to
+	// generate Broker ingress URIs. It may contain variables `name` and
+	// `namespace`.
+	// Example: "http://broker-ingress.cloud-run-events.svc.cluster.local/{namespace}/{name}"
+	IngressTemplate string `json:"ingressTemplate,omitempty"`
+
+	// BrokerCount is the number of Brokers served by the BrokerCell.
+	// +optional
+	BrokerCount int32 `json:"brokerCount,omitempty"`
+
+	// TriggerCount is the number of Triggers served by the BrokerCell.
+	// +optional
+	TriggerCount int32 `json:"triggerCount,omitempty"`
+
+	// Ingress reports the replicas of the ingress deployment.
+	// +optional
+	Ingress *ComponentReplicas `json:"ingress,omitempty"`
+
+	// Fanout reports the replicas of the fanout deployment.
+	// +optional
+	Fanout *ComponentReplicas `json:"fanout,omitempty"`
+
+	// Retry reports the replicas of the retry deployment.
+	// +optional
+	Retry *ComponentReplicas `json:"retry,omitempty"`
+}
+
+// ComponentReplicas reports the replica counts of a BrokerCell data plane
+// component.
+type ComponentReplicas struct {
+	// Replicas is the number of replicas of the component's deployment.
+	Replicas int32 `json:"replicas"`
+
+	// ReadyReplicas is the number of ready replicas of the component's
+	// deployment.
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// MaxReplicas is the upper limit of replicas the component can be scaled
+	// out to by its autoscaler.
+	MaxReplicas int32 `json:"maxReplicas"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// BrokerCellList is a collection of BrokerCells.
+type BrokerCellList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []BrokerCell `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for Brokers
+func (bc *BrokerCell) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("BrokerCell")
+}
+
+// GetUntypedSpec returns the spec of the BrokerCell.
+func (bc *BrokerCell) GetUntypedSpec() interface{} {
+	return bc.Spec
+}
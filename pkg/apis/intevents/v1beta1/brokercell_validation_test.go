@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/ptr"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func TestBrokerCell_Validate(t *testing.T) {
+	bc := BrokerCell{}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
+
+func TestBrokerCell_ValidateIstio(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Istio: &duckv1beta1.IstioSpec{
+				ExcludeOutboundPorts: []int32{443},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Istio.ExcludeOutboundPorts = []int32{0}
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid port, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateProxy(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Proxy: &duckv1beta1.ProxySpec{
+				HTTPSProxy: "http://proxy:3128",
+				NoProxy:    ".svc.cluster.local",
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Proxy.NoProxy = "10.0.0.0/33"
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid no proxy CIDR, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateBrokerSelector(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			BrokerSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"team": "payments"},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.BrokerSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+		Key:      "tier",
+		Operator: "Near",
+	}}
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for invalid selector operator, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateCABundle(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			CABundle: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+				Key:                  "ca.crt",
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.CABundle.Key = ""
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for CA bundle without key, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateComponents(t *testing.T) {
+	bc := BrokerCell{
+		Spec: BrokerCellSpec{
+			Components: ComponentsParametersSpec{
+				Ingress: &ComponentParameters{
+					Image:       "gcr.io/example/dataplane:v1",
+					MinReplicas: ptr.Int32(2),
+					MaxReplicas: ptr.Int32(5),
+				},
+				Retry: &ComponentParameters{
+					MaxReplicas: ptr.Int32(1),
+				},
+			},
+		},
+	}
+	if err := bc.Validate(context.TODO()); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	bc.Spec.Components.Ingress.MinReplicas = ptr.Int32(6)
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for minReplicas greater than maxReplicas, got nil")
+	}
+
+	bc.Spec.Components.Ingress.MinReplicas = nil
+	bc.Spec.Components.Retry.MaxReplicas = ptr.Int32(0)
+	if err := bc.Validate(context.TODO()); err == nil {
+		t.Error("expected error for zero maxReplicas, got nil")
+	}
+}
+
+func TestBrokerCell_ValidateCatchUpAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantErr     bool
+	}{{
+		name: "no annotation",
+	}, {
+		name:        "catch-up within the max duration",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)},
+	}, {
+		name:        "catch-up beyond the max duration",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(duckv1beta1.MaxCatchUpDuration + time.Hour).Format(time.RFC3339)},
+		wantErr:     true,
+	}, {
+		name:        "invalid catch-up",
+		annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: "1h"},
+		wantErr:     true,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bc := BrokerCell{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if err := bc.Validate(context.TODO()); tc.wantErr != (err != nil) {
+				t.Errorf("Validate() got error %v, want error %t", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -23,22 +23,40 @@ import (
 	"knative.dev/pkg/apis"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	"knative.dev/pkg/ptr"
 )
 
 const (
 	defaultRetentionDuration = 7 * 24 * time.Hour
 	defaultAckDeadline       = 30 * time.Second
+
+	// The backoff Pub/Sub applies when a retry policy leaves it unset.
+	defaultMinimumBackoff = 10 * time.Second
+	defaultMaximumBackoff = 600 * time.Second
+
+	// The delivery attempts Pub/Sub allows when a dead letter policy leaves
+	// them unset.
+	defaultMaxDeliveryAttempts = 5
+
+	// The capacity of the Pub/Sub Lite topics created for PullSubscriptions
+	// that leave it unset, the minimum Pub/Sub Lite allows.
+	defaultLitePartitions         = 1
+	defaultLitePerPartitionBytes  = 30 * 1024 * 1024 * 1024
+	defaultLitePublishMiBPerSec   = 4
+	defaultLiteSubscribeMiBPerSec = 8
 )
 
 func (s *PullSubscription) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, s.ObjectMeta)
 	s.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &s.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 	duckv1beta1.SetAutoscalingAnnotationsDefaults(ctx, &s.ObjectMeta)
 }
 
 func (ss *PullSubscriptionSpec) SetDefaults(ctx context.Context) {
-	if ss.AckDeadline == nil {
+	// Pub/Sub Lite subscriptions have no ack deadline.
+	if ss.AckDeadline == nil && ss.LiteConfig == nil {
 		ackDeadline := defaultAckDeadline
 		ss.AckDeadline = ptr.String(ackDeadline.String())
 	}
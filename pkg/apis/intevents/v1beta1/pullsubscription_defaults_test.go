@@ -121,6 +121,23 @@ func TestPullSubscriptionDefaults(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "lite",
+		start: &PullSubscription{
+			Spec: PullSubscriptionSpec{
+				LiteConfig: &LiteConfig{Location: "us-central1-a"},
+			},
+		},
+		want: &PullSubscription{
+			Spec: PullSubscriptionSpec{
+				Mode:              ModeCloudEventsBinary,
+				RetentionDuration: ptr.String(defaultRetentionDuration.String()),
+				LiteConfig:        &LiteConfig{Location: "us-central1-a"},
+				PubSubSpec: duckv1beta1.PubSubSpec{
+					Secret: &gcpauthtesthelper.Secret,
+				},
+			},
+		},
 	}, {
 		name: "nil secret",
 		start: &PullSubscription{
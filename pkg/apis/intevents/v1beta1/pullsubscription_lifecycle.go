@@ -94,3 +94,17 @@ func (s *PullSubscriptionStatus) PropagateDeploymentAvailability(d *appsv1.Deplo
 		pullSubscriptionCondSet.Manage(s).MarkFalse(PullSubscriptionConditionDeployed, "DeploymentUnavailable", "The Deployment '%s' is unavailable.", d.Name)
 	}
 }
+
+// MarkDeployedByAgent sets the condition that the PullSubscription has been
+// handed to the shared receive adapter agent instead of a Deployment of its
+// own.
+func (s *PullSubscriptionStatus) MarkDeployedByAgent() {
+	pullSubscriptionCondSet.Manage(s).MarkTrueWithReason(PullSubscriptionConditionDeployed, deployedByAgentReason, "The PullSubscription is served by the receive adapter agent.")
+}
+
+// IsDeployedByAgent returns true if the PullSubscription was last deployed to
+// the shared receive adapter agent.
+func (s *PullSubscriptionStatus) IsDeployedByAgent() bool {
+	c := s.GetCondition(PullSubscriptionConditionDeployed)
+	return c != nil && c.IsTrue() && c.Reason == deployedByAgentReason
+}
@@ -204,6 +204,18 @@ func TestPubSubStatusIsReady(t *testing.T) {
 		}(),
 		wantConditionStatus: corev1.ConditionTrue,
 		want:                true,
+	}, {
+		name: "mark sink and deployed by agent and subscribed",
+		s: func() *PullSubscriptionStatus {
+			s := &PullSubscriptionStatus{}
+			s.InitializeConditions()
+			s.MarkSink(apis.HTTP("example"))
+			s.MarkDeployedByAgent()
+			s.MarkSubscribed("subID")
+			return s
+		}(),
+		wantConditionStatus: corev1.ConditionTrue,
+		want:                true,
 	}}
 
 	for _, test := range tests {
@@ -453,3 +465,19 @@ func TestPubSubStatusGetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestPubSubStatusIsDeployedByAgent(t *testing.T) {
+	s := &PullSubscriptionStatus{}
+	s.InitializeConditions()
+	if s.IsDeployedByAgent() {
+		t.Error("IsDeployedByAgent() = true before deploying")
+	}
+	s.MarkDeployedByAgent()
+	if !s.IsDeployedByAgent() {
+		t.Error("IsDeployedByAgent() = false after MarkDeployedByAgent")
+	}
+	s.PropagateDeploymentAvailability(availableDeployment)
+	if s.IsDeployedByAgent() {
+		t.Error("IsDeployedByAgent() = true after deploying a Deployment")
+	}
+}
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -61,10 +62,11 @@ var _ = duck.VerifyType(&PullSubscription{}, &duckv1.Conditions{})
 type PullSubscriptionSpec struct {
 	v1beta1.PubSubSpec `json:",inline"`
 
-	// Topic is the ID of the PullSubscription Topic to Subscribe to. It must
-	// be in the form of the unique identifier within the project, not the
-	// entire name. E.g. it must be 'laconia', not
-	// 'projects/my-proj/topics/laconia'.
+	// Topic is the ID of the PullSubscription Topic to Subscribe to, e.g.
+	// 'laconia'. A topic in another project than the subscription is
+	// referenced by its entire name, e.g.
+	// 'projects/other-proj/topics/laconia'. The subscription is always
+	// created in the project of the resource.
 	Topic string `json:"topic,omitempty"`
 
 	// AckDeadline is the default maximum time after a subscriber receives a
@@ -73,11 +75,30 @@ type PullSubscriptionSpec struct {
 	// +optional
 	AckDeadline *string `json:"ackDeadline,omitempty"`
 
+	// CreateTopic creates the Pub/Sub topic when it doesn't exist, instead
+	// of failing to subscribe to it. Only topics in the project of the
+	// subscription can be created, so Topic must be a topic ID.
+	// +optional
+	CreateTopic bool `json:"createTopic,omitempty"`
+
+	// TopicConfig configures the topic created when CreateTopic is true. It
+	// is only applied when the topic is created.
+	// +optional
+	TopicConfig *TopicConfig `json:"topicConfig,omitempty"`
+
 	// RetainAckedMessages defines whether to retain acknowledged messages. If
 	// true, acknowledged messages will not be expunged until they fall out of
 	// the RetentionDuration window.
 	RetainAckedMessages bool `json:"retainAckedMessages,omitempty"`
 
+	// EnableMessageOrdering creates the subscription with message ordering,
+	// so that the messages published with the same ordering key are
+	// delivered in the order they were published. The ordering key of each
+	// message is set as the orderingkey extension of its event. It cannot be
+	// changed once the subscription is created.
+	// +optional
+	EnableMessageOrdering bool `json:"enableMessageOrdering,omitempty"`
+
 	// RetentionDuration defines how long to retain messages in backlog, from
 	// the time of publish. If RetainAckedMessages is true, this duration
 	// affects the retention of acknowledged messages, otherwise only
@@ -86,6 +107,18 @@ type PullSubscriptionSpec struct {
 	// +optional
 	RetentionDuration *string `json:"retentionDuration,omitempty"`
 
+	// RetryPolicy defines how Pub/Sub retries the delivery of messages that
+	// the receive adapter nacks, e.g. because the sink failed. If unset,
+	// nacked messages are redelivered immediately.
+	// +optional
+	RetryPolicy *RetryPolicy `json:"retryPolicy,omitempty"`
+
+	// DeadLetterPolicy defines when Pub/Sub stops redelivering a message
+	// and forwards it to a dead letter topic. If unset, messages are
+	// redelivered until they expire.
+	// +optional
+	DeadLetterPolicy *DeadLetterPolicy `json:"deadLetterPolicy,omitempty"`
+
 	// Transformer is a reference to an object that will resolve to a domain
 	// name or a URI directly to use as the transformer or a URI directly.
 	// +optional
@@ -100,8 +133,139 @@ type PullSubscriptionSpec struct {
 	// PullSubscription uses.
 	// +optional
 	AdapterType string `json:"adapterType,omitempty"`
+
+	// Istio configures the receive adapter pods to run alongside an
+	// injected Istio sidecar.
+	// +optional
+	Istio *v1beta1.IstioSpec `json:"istio,omitempty"`
+
+	// Proxy configures the egress proxy used by the receive adapter pods.
+	// Unset fields default to the controller's data plane proxy settings.
+	// +optional
+	Proxy *v1beta1.ProxySpec `json:"proxy,omitempty"`
+
+	// Shutdown configures the termination grace period of the receive
+	// adapter pods and whether they drain before they are stopped.
+	// +optional
+	Shutdown *v1beta1.ShutdownSpec `json:"shutdown,omitempty"`
+
+	// SinkTLS configures how the receive adapter verifies the certificate of
+	// an HTTPS sink.
+	// +optional
+	SinkTLS *v1beta1.SinkTLSSpec `json:"sinkTLS,omitempty"`
+
+	// RateLimit limits the rate at which the receive adapter delivers events
+	// to the sink. Messages wait in the adapter, unacknowledged, until they
+	// can be delivered, so a large backlog drains at that rate instead of
+	// in a burst. Unlimited if unset.
+	// +optional
+	RateLimit *v1beta1.RateLimitSpec `json:"rateLimit,omitempty"`
+
+	// LiteConfig subscribes to a Pub/Sub Lite topic instead of a Cloud
+	// Pub/Sub topic. Topic is then the ID of the Lite topic, which must be
+	// in the project of the subscription. It cannot be changed once the
+	// subscription is created.
+	// +optional
+	LiteConfig *LiteConfig `json:"liteConfig,omitempty"`
 }
 
+// LiteConfig configures a PullSubscription of a Pub/Sub Lite topic.
+type LiteConfig struct {
+	// Location is the zone of the Lite topic and subscription, e.g.
+	// 'us-central1-a'.
+	Location string `json:"location"`
+
+	// Partitions is the number of partitions of the topic created if it
+	// does not exist. Defaults to 1.
+	// +optional
+	Partitions *int32 `json:"partitions,omitempty"`
+
+	// PerPartitionBytes is the storage provisioned for each partition of the
+	// topic created if it does not exist, at least 30Gi. Defaults to 30Gi.
+	// +optional
+	PerPartitionBytes *resource.Quantity `json:"perPartitionBytes,omitempty"`
+
+	// PublishMiBPerSec is the publish throughput of each partition of the
+	// topic created if it does not exist, between 4 and 16. Defaults to 4.
+	// +optional
+	PublishMiBPerSec *int32 `json:"publishMiBPerSec,omitempty"`
+
+	// SubscribeMiBPerSec is the subscribe throughput of each partition of
+	// the topic created if it does not exist, between 4 and 32. Defaults to
+	// 8.
+	// +optional
+	SubscribeMiBPerSec *int32 `json:"subscribeMiBPerSec,omitempty"`
+}
+
+// GetPartitions returns Partitions or the default if unset.
+func (lc LiteConfig) GetPartitions() int {
+	if lc.Partitions != nil {
+		return int(*lc.Partitions)
+	}
+	return defaultLitePartitions
+}
+
+// GetPerPartitionBytes returns PerPartitionBytes or the default if unset.
+func (lc LiteConfig) GetPerPartitionBytes() int64 {
+	if lc.PerPartitionBytes != nil {
+		return lc.PerPartitionBytes.Value()
+	}
+	return defaultLitePerPartitionBytes
+}
+
+// GetPublishMiBPerSec returns PublishMiBPerSec or the default if unset.
+func (lc LiteConfig) GetPublishMiBPerSec() int {
+	if lc.PublishMiBPerSec != nil {
+		return int(*lc.PublishMiBPerSec)
+	}
+	return defaultLitePublishMiBPerSec
+}
+
+// GetSubscribeMiBPerSec returns SubscribeMiBPerSec or the default if unset.
+func (lc LiteConfig) GetSubscribeMiBPerSec() int {
+	if lc.SubscribeMiBPerSec != nil {
+		return int(*lc.SubscribeMiBPerSec)
+	}
+	return defaultLiteSubscribeMiBPerSec
+}
+
+// TopicConfig configures the Pub/Sub topic created for a PullSubscription.
+type TopicConfig struct {
+	// Labels are the labels of the topic.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// AllowedPersistenceRegions are the regions the messages published to
+	// the topic may be stored in. Defaults to the policy of the project.
+	// +optional
+	AllowedPersistenceRegions []string `json:"allowedPersistenceRegions,omitempty"`
+
+	// KMSKeyName is the name of the Cloud KMS key protecting the messages
+	// published to the topic, e.g.
+	// 'projects/P/locations/L/keyRings/R/cryptoKeys/K'.
+	// +optional
+	KMSKeyName string `json:"kmsKeyName,omitempty"`
+
+	// DeletionPolicy defines whether the topic is deleted along with the
+	// PullSubscription. Defaults to Retain.
+	// +optional
+	DeletionPolicy TopicDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// TopicDeletionPolicy defines what happens to the topic created for a
+// PullSubscription when the PullSubscription is deleted.
+type TopicDeletionPolicy string
+
+const (
+	// TopicDeletionPolicyRetain keeps the topic when the PullSubscription is
+	// deleted.
+	TopicDeletionPolicyRetain TopicDeletionPolicy = "Retain"
+
+	// TopicDeletionPolicyDelete deletes the topic when the PullSubscription
+	// is deleted.
+	TopicDeletionPolicyDelete TopicDeletionPolicy = "Delete"
+)
+
 // GetAckDeadline parses AckDeadline and returns the default if an error occurs.
 func (ps PullSubscriptionSpec) GetAckDeadline() time.Duration {
 	if ps.AckDeadline != nil {
@@ -122,6 +286,67 @@ func (ps PullSubscriptionSpec) GetRetentionDuration() time.Duration {
 	return defaultRetentionDuration
 }
 
+// RetryPolicy defines the exponential backoff Pub/Sub applies between
+// redeliveries of a nacked message.
+type RetryPolicy struct {
+	// MinimumBackoff is the minimum delay between consecutive deliveries of
+	// a message. Must be between 0 and 600 seconds. Defaults to 10 seconds
+	// ('10s').
+	// +optional
+	MinimumBackoff *string `json:"minimumBackoff,omitempty"`
+
+	// MaximumBackoff is the maximum delay between consecutive deliveries of
+	// a message. Must be between 0 and 600 seconds. Defaults to 600 seconds
+	// ('600s').
+	// +optional
+	MaximumBackoff *string `json:"maximumBackoff,omitempty"`
+}
+
+// GetMinimumBackoff parses MinimumBackoff and returns the default if an error occurs.
+func (rp RetryPolicy) GetMinimumBackoff() time.Duration {
+	if rp.MinimumBackoff != nil {
+		if duration, err := time.ParseDuration(*rp.MinimumBackoff); err == nil {
+			return duration
+		}
+	}
+	return defaultMinimumBackoff
+}
+
+// GetMaximumBackoff parses MaximumBackoff and returns the default if an error occurs.
+func (rp RetryPolicy) GetMaximumBackoff() time.Duration {
+	if rp.MaximumBackoff != nil {
+		if duration, err := time.ParseDuration(*rp.MaximumBackoff); err == nil {
+			return duration
+		}
+	}
+	return defaultMaximumBackoff
+}
+
+// DeadLetterPolicy defines the dead letter topic Pub/Sub forwards the
+// messages it failed to deliver to. The Pub/Sub service account of the
+// project must be allowed to publish to the dead letter topic and to
+// subscribe to the subscription.
+type DeadLetterPolicy struct {
+	// DeadLetterTopic is the ID of the dead letter topic, or its name of the
+	// form projects/<project>/topics/<topic> if it is in another project.
+	DeadLetterTopic string `json:"deadLetterTopic"`
+
+	// MaxDeliveryAttempts is the number of delivery attempts of a message
+	// after which it is forwarded to the dead letter topic. Must be between 5
+	// and 100. Defaults to 5.
+	// +optional
+	MaxDeliveryAttempts *int32 `json:"maxDeliveryAttempts,omitempty"`
+}
+
+// GetMaxDeliveryAttempts returns MaxDeliveryAttempts, or the default if it
+// is unset.
+func (dlp DeadLetterPolicy) GetMaxDeliveryAttempts() int32 {
+	if dlp.MaxDeliveryAttempts != nil {
+		return *dlp.MaxDeliveryAttempts
+	}
+	return defaultMaxDeliveryAttempts
+}
+
 type ModeType string
 
 const (
@@ -138,6 +363,19 @@ const (
 	ModePushCompatible ModeType = "PushCompatible"
 )
 
+const (
+	// PushAuthServiceAccountAnnotation is the annotation of the email of the
+	// service account whose push authentication the receive adapter emulates
+	// in PushCompatible mode. The sink receives Google-signed ID tokens of the
+	// service account, like Cloud Pub/Sub would send. The receive adapter
+	// needs the Service Account Token Creator role on the service account.
+	PushAuthServiceAccountAnnotation = "events.cloud.google.com/push-auth-service-account"
+
+	// PushAuthAudienceAnnotation is the annotation of the audience of the
+	// emulated push tokens. It defaults to the sink.
+	PushAuthAudienceAnnotation = "events.cloud.google.com/push-auth-audience"
+)
+
 const (
 	// PullSubscriptionConditionReady has status True when the PullSubscription is
 	// ready to send events.
@@ -161,6 +399,10 @@ const (
 	PullSubscriptionConditionTransformerProvided apis.ConditionType = "TransformerProvided"
 )
 
+// deployedByAgentReason is the reason of PullSubscriptionConditionDeployed
+// when the PullSubscription is served by the receive adapter agent.
+const deployedByAgentReason = "DeployedByAgent"
+
 var pullSubscriptionCondSet = apis.NewLivingConditionSet(
 	PullSubscriptionConditionSinkProvided,
 	PullSubscriptionConditionDeployed,
@@ -179,6 +421,11 @@ type PullSubscriptionStatus struct {
 	// SubscriptionID is the created subscription ID used by the PullSubscription.
 	// +optional
 	SubscriptionID string `json:"subscriptionId,omitempty"`
+
+	// CreatedTopicID is the ID of the topic the PullSubscription created
+	// because of CreateTopic, if any.
+	// +optional
+	CreatedTopicID string `json:"createdTopicId,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
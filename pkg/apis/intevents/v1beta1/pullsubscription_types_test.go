@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -95,6 +96,75 @@ func TestGetRetentionDuration_default(t *testing.T) {
 	}
 }
 
+func TestRetryPolicyGetBackoff(t *testing.T) {
+	rp := RetryPolicy{MinimumBackoff: ptr.String("5s"), MaximumBackoff: ptr.String("1m")}
+	if diff := cmp.Diff(5*time.Second, rp.GetMinimumBackoff()); diff != "" {
+		t.Errorf("failed to get expected minimum backoff (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(time.Minute, rp.GetMaximumBackoff()); diff != "" {
+		t.Errorf("failed to get expected maximum backoff (-want, +got) = %v", diff)
+	}
+}
+
+func TestRetryPolicyGetBackoff_default(t *testing.T) {
+	rp := RetryPolicy{}
+	if diff := cmp.Diff(defaultMinimumBackoff, rp.GetMinimumBackoff()); diff != "" {
+		t.Errorf("failed to get expected minimum backoff (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(defaultMaximumBackoff, rp.GetMaximumBackoff()); diff != "" {
+		t.Errorf("failed to get expected maximum backoff (-want, +got) = %v", diff)
+	}
+}
+
+func TestDeadLetterPolicyGetMaxDeliveryAttempts(t *testing.T) {
+	dlp := DeadLetterPolicy{DeadLetterTopic: "dead-letter", MaxDeliveryAttempts: ptr.Int32(10)}
+	if diff := cmp.Diff(int32(10), dlp.GetMaxDeliveryAttempts()); diff != "" {
+		t.Errorf("failed to get expected max delivery attempts (-want, +got) = %v", diff)
+	}
+	dlp.MaxDeliveryAttempts = nil
+	if diff := cmp.Diff(int32(defaultMaxDeliveryAttempts), dlp.GetMaxDeliveryAttempts()); diff != "" {
+		t.Errorf("failed to get expected max delivery attempts (-want, +got) = %v", diff)
+	}
+}
+
+func TestLiteConfigGetCapacity(t *testing.T) {
+	perPartitionBytes := resource.MustParse("64Gi")
+	lc := LiteConfig{
+		Partitions:         ptr.Int32(2),
+		PerPartitionBytes:  &perPartitionBytes,
+		PublishMiBPerSec:   ptr.Int32(8),
+		SubscribeMiBPerSec: ptr.Int32(16),
+	}
+	if diff := cmp.Diff(2, lc.GetPartitions()); diff != "" {
+		t.Errorf("failed to get expected partitions (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(int64(64<<30), lc.GetPerPartitionBytes()); diff != "" {
+		t.Errorf("failed to get expected per partition bytes (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(8, lc.GetPublishMiBPerSec()); diff != "" {
+		t.Errorf("failed to get expected publish throughput (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(16, lc.GetSubscribeMiBPerSec()); diff != "" {
+		t.Errorf("failed to get expected subscribe throughput (-want, +got) = %v", diff)
+	}
+}
+
+func TestLiteConfigGetCapacity_default(t *testing.T) {
+	lc := LiteConfig{}
+	if diff := cmp.Diff(defaultLitePartitions, lc.GetPartitions()); diff != "" {
+		t.Errorf("failed to get expected partitions (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(int64(defaultLitePerPartitionBytes), lc.GetPerPartitionBytes()); diff != "" {
+		t.Errorf("failed to get expected per partition bytes (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(defaultLitePublishMiBPerSec, lc.GetPublishMiBPerSec()); diff != "" {
+		t.Errorf("failed to get expected publish throughput (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(defaultLiteSubscribeMiBPerSec, lc.GetSubscribeMiBPerSec()); diff != "" {
+		t.Errorf("failed to get expected subscribe throughput (-want, +got) = %v", diff)
+	}
+}
+
 func TestPullSubscriptionIdentitySpec(t *testing.T) {
 	s := &PullSubscription{
 		Spec: PullSubscriptionSpec{
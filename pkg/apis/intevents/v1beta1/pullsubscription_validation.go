@@ -18,10 +18,15 @@ package v1beta1
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/knative-gcp/pkg/apis/configs/topiccheck"
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/utils"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -36,11 +41,95 @@ const (
 
 	minAckDeadline = 0 * time.Second  // 0 seconds.
 	maxAckDeadline = 10 * time.Minute // 10 minutes.
+
+	minBackoff = 0 * time.Second  // 0 seconds.
+	maxBackoff = 10 * time.Minute // 10 minutes.
+
+	minDeliveryAttempts = 5
+	maxDeliveryAttempts = 100
+
+	// The capacity bounds of Pub/Sub Lite topics.
+	minLitePerPartitionBytes  = 30 * 1024 * 1024 * 1024 // 30 GiB.
+	minLitePublishMiBPerSec   = 4
+	maxLitePublishMiBPerSec   = 16
+	minLiteSubscribeMiBPerSec = 4
+	maxLiteSubscribeMiBPerSec = 32
 )
 
 func (current *PullSubscription) Validate(ctx context.Context) *apis.FieldError {
 	errs := current.Spec.Validate(ctx).ViaField("spec")
-	return duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	if errs == nil && !current.Spec.CreateTopic && current.Spec.LiteConfig == nil {
+		// Only verify the topic of otherwise valid resources, as the check
+		// calls the Pub/Sub API. Topics that are created when missing and
+		// Pub/Sub Lite topics are not verified.
+		errs = topiccheck.Check(ctx, current.Spec.Project, current.Spec.Topic).ViaField("spec")
+	}
+	errs = duckv1beta1.ValidateAutoscalingAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateMetricsAnnotations(ctx, current.Annotations, errs)
+	errs = duckv1beta1.ValidateCatchUpAnnotation(ctx, current.Annotations, errs)
+	errs = validatePushAuthAnnotations(current, errs)
+	errs = duckv1beta1.ValidateReceiveAdapterModeAnnotation(ctx, current.Annotations, errs)
+	errs = validateLiteAnnotations(current, errs)
+	return validateAgentSinkTLS(current, errs)
+}
+
+// validateLiteAnnotations rejects the agent receive adapter mode and the KEDA
+// autoscaler for Pub/Sub Lite subscriptions, as the agent only pulls from
+// Cloud Pub/Sub and KEDA scales on Cloud Pub/Sub backlog metrics.
+func validateLiteAnnotations(ps *PullSubscription, errs *apis.FieldError) *apis.FieldError {
+	if ps.Spec.LiteConfig == nil {
+		return errs
+	}
+	if ps.Annotations[duckv1beta1.ReceiveAdapterModeAnnotation] == duckv1beta1.ReceiveAdapterModeAgent {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("Pub/Sub Lite is not supported by the %s receive adapter mode", duckv1beta1.ReceiveAdapterModeAgent),
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", duckv1beta1.ReceiveAdapterModeAnnotation)},
+		})
+	}
+	if ps.Annotations[duckv1beta1.AutoscalingClassAnnotation] == duckv1beta1.KEDA {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("Pub/Sub Lite is not supported by the %s autoscaler", duckv1beta1.KEDA),
+			Paths:   []string{fmt.Sprintf("metadata.annotations[%s]", duckv1beta1.AutoscalingClassAnnotation)},
+		})
+	}
+	return errs
+}
+
+// validateAgentSinkTLS rejects CA bundles in agent mode, as the shared agent
+// can't mount the ConfigMap of every PullSubscription.
+func validateAgentSinkTLS(ps *PullSubscription, errs *apis.FieldError) *apis.FieldError {
+	if ps.Annotations[duckv1beta1.ReceiveAdapterModeAnnotation] != duckv1beta1.ReceiveAdapterModeAgent || ps.Spec.SinkTLS.GetCABundle() == nil {
+		return errs
+	}
+	return errs.Also(&apis.FieldError{
+		Message: fmt.Sprintf("A sink CA bundle is not supported by the %s receive adapter mode", duckv1beta1.ReceiveAdapterModeAgent),
+		Paths:   []string{"spec.sinkTLS.caBundle"},
+	})
+}
+
+func validatePushAuthAnnotations(ps *PullSubscription, errs *apis.FieldError) *apis.FieldError {
+	sa, ok := ps.Annotations[PushAuthServiceAccountAnnotation]
+	if !ok {
+		if _, ok := ps.Annotations[PushAuthAudienceAnnotation]; ok {
+			path := fmt.Sprintf("metadata.annotations[%s]", PushAuthAudienceAnnotation)
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("The push auth audience requires the %s annotation", PushAuthServiceAccountAnnotation),
+				Paths:   []string{path},
+			})
+		}
+		return errs
+	}
+	path := fmt.Sprintf("metadata.annotations[%s]", PushAuthServiceAccountAnnotation)
+	if !strings.Contains(sa, "@") {
+		errs = errs.Also(apis.ErrInvalidValue(sa, path))
+	}
+	if ps.PubSubMode() != ModePushCompatible {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("Push authentication is only emulated in %s mode", ModePushCompatible),
+			Paths:   []string{path},
+		})
+	}
+	return errs
 }
 
 func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -48,6 +137,24 @@ func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldEr
 	// Topic [required]
 	if current.Topic == "" {
 		errs = errs.Also(apis.ErrMissingField("topic"))
+	} else if _, _, err := utils.ParseTopic(current.Topic); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(current.Topic, "topic"))
+	}
+	if current.CreateTopic {
+		if project, _, _ := utils.ParseTopic(current.Topic); project != "" {
+			errs = errs.Also(&apis.FieldError{
+				Message: "Only topics in the project of the subscription can be created, use a topic ID",
+				Paths:   []string{"topic"},
+			})
+		}
+	} else if current.TopicConfig != nil {
+		errs = errs.Also(&apis.FieldError{
+			Message: "topicConfig is only allowed with createTopic",
+			Paths:   []string{"topicConfig"},
+		})
+	}
+	if current.TopicConfig != nil {
+		errs = errs.Also(current.TopicConfig.Validate(ctx).ViaField("topicConfig"))
 	}
 	// Sink [required]
 	if equality.Semantic.DeepEqual(current.Sink, duckv1.Destination{}) {
@@ -82,6 +189,14 @@ func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldEr
 		}
 	}
 
+	if current.RetryPolicy != nil {
+		errs = errs.Also(current.RetryPolicy.Validate(ctx).ViaField("retryPolicy"))
+	}
+
+	if current.DeadLetterPolicy != nil {
+		errs = errs.Also(current.DeadLetterPolicy.Validate(ctx).ViaField("deadLetterPolicy"))
+	}
+
 	// Mode [optional]
 	switch current.Mode {
 	case "", ModeCloudEventsBinary, ModeCloudEventsStructured, ModePushCompatible:
@@ -99,6 +214,102 @@ func (current *PullSubscriptionSpec) Validate(ctx context.Context) *apis.FieldEr
 		}
 	}
 
+	if err := current.Istio.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("istio"))
+	}
+
+	if err := current.Shutdown.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("shutdown"))
+	}
+
+	if err := current.SinkTLS.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("sinkTLS"))
+	}
+
+	if err := current.Proxy.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("proxy"))
+	}
+
+	if err := current.RateLimit.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("rateLimit"))
+	}
+
+	if current.LiteConfig != nil {
+		errs = errs.Also(current.validateLite(ctx))
+	}
+
+	return errs
+}
+
+// validateLite validates a PullSubscription of a Pub/Sub Lite topic, rejecting
+// the settings that Pub/Sub Lite doesn't support.
+func (current *PullSubscriptionSpec) validateLite(ctx context.Context) *apis.FieldError {
+	errs := current.LiteConfig.Validate(ctx).ViaField("liteConfig")
+	if project, _, _ := utils.ParseTopic(current.Topic); project != "" {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Pub/Sub Lite topics must be in the project of the subscription, use a topic ID",
+			Paths:   []string{"topic"},
+		})
+	}
+	var unsupported []string
+	if current.AckDeadline != nil {
+		unsupported = append(unsupported, "ackDeadline")
+	}
+	if current.RetainAckedMessages {
+		unsupported = append(unsupported, "retainAckedMessages")
+	}
+	if current.RetryPolicy != nil {
+		unsupported = append(unsupported, "retryPolicy")
+	}
+	if current.DeadLetterPolicy != nil {
+		unsupported = append(unsupported, "deadLetterPolicy")
+	}
+	if current.Mode == ModePushCompatible {
+		unsupported = append(unsupported, "mode")
+	}
+	if tc := current.TopicConfig; tc != nil {
+		if tc.Labels != nil {
+			unsupported = append(unsupported, "topicConfig.labels")
+		}
+		if tc.AllowedPersistenceRegions != nil {
+			unsupported = append(unsupported, "topicConfig.allowedPersistenceRegions")
+		}
+		if tc.KMSKeyName != "" {
+			unsupported = append(unsupported, "topicConfig.kmsKeyName")
+		}
+	}
+	if len(unsupported) > 0 {
+		errs = errs.Also(&apis.FieldError{
+			Message: "Not supported by Pub/Sub Lite",
+			Paths:   unsupported,
+		})
+	}
+	return errs
+}
+
+func (current *LiteConfig) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	// Location [required]
+	if current.Location == "" {
+		errs = errs.Also(apis.ErrMissingField("location"))
+	} else if err := gpubsublite.ValidateZone(current.Location); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(current.Location, "location"))
+	}
+	if current.Partitions != nil && *current.Partitions < 1 {
+		errs = errs.Also(apis.ErrInvalidValue(*current.Partitions, "partitions"))
+	}
+	if current.PerPartitionBytes != nil && current.PerPartitionBytes.Value() < minLitePerPartitionBytes {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("perPartitionBytes must be at least %d", minLitePerPartitionBytes),
+			Paths:   []string{"perPartitionBytes"},
+		})
+	}
+	if p := current.PublishMiBPerSec; p != nil && (*p < minLitePublishMiBPerSec || *p > maxLitePublishMiBPerSec) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*p, minLitePublishMiBPerSec, maxLitePublishMiBPerSec, "publishMiBPerSec"))
+	}
+	if s := current.SubscribeMiBPerSec; s != nil && (*s < minLiteSubscribeMiBPerSec || *s > maxLiteSubscribeMiBPerSec) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*s, minLiteSubscribeMiBPerSec, maxLiteSubscribeMiBPerSec, "subscribeMiBPerSec"))
+	}
 	return errs
 }
 
@@ -122,12 +333,65 @@ func (current *PullSubscription) CheckImmutableFields(ctx context.Context, origi
 	// Modification of Topic, Secret and Project are not allowed. Everything else is mutable.
 	if diff := cmp.Diff(original.Spec, current.Spec,
 		cmpopts.IgnoreFields(PullSubscriptionSpec{},
-			"Sink", "Transformer", "Mode", "AckDeadline", "RetainAckedMessages", "RetentionDuration", "CloudEventOverrides")); diff != "" {
+			"Sink", "Transformer", "Mode", "AckDeadline", "RetainAckedMessages", "RetentionDuration", "RetryPolicy", "DeadLetterPolicy", "CloudEventOverrides",
+			"CreateTopic", "TopicConfig", "RateLimit", "Istio", "Proxy", "Shutdown", "SinkTLS")); diff != "" {
 		return &apis.FieldError{
 			Message: "Immutable fields changed (-old +new)",
 			Paths:   []string{"spec"},
 			Details: diff,
 		}
 	}
-	return nil
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
+}
+
+func (current *RetryPolicy) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	// If set, the backoffs need to parse to valid durations of at most 10 minutes.
+	if current.MinimumBackoff != nil {
+		b, err := time.ParseDuration(*current.MinimumBackoff)
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*current.MinimumBackoff, "minimumBackoff"))
+		} else if b < minBackoff || b > maxBackoff {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*current.MinimumBackoff, minBackoff.String(), maxBackoff.String(), "minimumBackoff"))
+		}
+	}
+	if current.MaximumBackoff != nil {
+		b, err := time.ParseDuration(*current.MaximumBackoff)
+		if err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(*current.MaximumBackoff, "maximumBackoff"))
+		} else if b < minBackoff || b > maxBackoff {
+			errs = errs.Also(apis.ErrOutOfBoundsValue(*current.MaximumBackoff, minBackoff.String(), maxBackoff.String(), "maximumBackoff"))
+		}
+	}
+	if errs == nil && current.GetMinimumBackoff() > current.GetMaximumBackoff() {
+		errs = errs.Also(&apis.FieldError{
+			Message: "minimumBackoff must not be greater than maximumBackoff",
+			Paths:   []string{"minimumBackoff", "maximumBackoff"},
+		})
+	}
+	return errs
+}
+
+func (current *TopicConfig) Validate(ctx context.Context) *apis.FieldError {
+	switch current.DeletionPolicy {
+	case "", TopicDeletionPolicyRetain, TopicDeletionPolicyDelete:
+		return nil
+	default:
+		return apis.ErrInvalidValue(current.DeletionPolicy, "deletionPolicy")
+	}
+}
+
+func (current *DeadLetterPolicy) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	// DeadLetterTopic [required]
+	if current.DeadLetterTopic == "" {
+		errs = errs.Also(apis.ErrMissingField("deadLetterTopic"))
+	} else if _, _, err := utils.ParseTopic(current.DeadLetterTopic); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(current.DeadLetterTopic, "deadLetterTopic"))
+	}
+	if a := current.MaxDeliveryAttempts; a != nil && (*a < minDeliveryAttempts || *a > maxDeliveryAttempts) {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(*a, minDeliveryAttempts, maxDeliveryAttempts, "maxDeliveryAttempts"))
+	}
+	return errs
 }
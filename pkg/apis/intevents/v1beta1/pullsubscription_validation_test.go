@@ -18,13 +18,18 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	"knative.dev/pkg/ptr"
 
+	"github.com/google/knative-gcp/pkg/apis/configs/topiccheck"
 	"github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 )
 
@@ -71,6 +76,22 @@ func TestPubSubCheckValidationFields(t *testing.T) {
 			spec:  pullSubscriptionSpec,
 			error: false,
 		},
+		"ok cross-project topic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Topic = "projects/other-project/topics/pubsub-topic"
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad topic name": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Topic = "projects/other-project/subscriptions/pubsub-topic"
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad RetentionDuration": {
 			spec: func() PullSubscriptionSpec {
 				obj := pullSubscriptionSpec.DeepCopy()
@@ -103,6 +124,144 @@ func TestPubSubCheckValidationFields(t *testing.T) {
 			}(),
 			error: true,
 		},
+		"ok CreateTopic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.CreateTopic = true
+				obj.TopicConfig = &TopicConfig{
+					Labels:         map[string]string{"team": "orders"},
+					DeletionPolicy: TopicDeletionPolicyDelete,
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad CreateTopic, topic of another project": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Topic = "projects/other-project/topics/topic"
+				obj.CreateTopic = true
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad TopicConfig, without CreateTopic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.TopicConfig = &TopicConfig{}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad TopicConfig, DeletionPolicy": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.CreateTopic = true
+				obj.TopicConfig = &TopicConfig{DeletionPolicy: "Orphan"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"ok RetryPolicy": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{
+					MinimumBackoff: ptr.String("5s"),
+					MaximumBackoff: ptr.String("1m"),
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"ok RetryPolicy, only MaximumBackoff": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{MaximumBackoff: ptr.String("30s")}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad RetryPolicy, MinimumBackoff": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{MinimumBackoff: ptr.String("wrong")}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad RetryPolicy, MaximumBackoff range": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{MaximumBackoff: ptr.String("11m")}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad RetryPolicy, MinimumBackoff greater than MaximumBackoff": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{
+					MinimumBackoff: ptr.String("1m"),
+					MaximumBackoff: ptr.String("30s"),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"ok DeadLetterPolicy": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &DeadLetterPolicy{
+					DeadLetterTopic:     "projects/other/topics/dead-letter",
+					MaxDeliveryAttempts: ptr.Int32(10),
+				}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad DeadLetterPolicy, missing DeadLetterTopic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &DeadLetterPolicy{MaxDeliveryAttempts: ptr.Int32(10)}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad DeadLetterPolicy, DeadLetterTopic": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &DeadLetterPolicy{DeadLetterTopic: "projects/other/dead-letter"}
+				return *obj
+			}(),
+			error: true,
+		},
+		"bad DeadLetterPolicy, MaxDeliveryAttempts range": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &DeadLetterPolicy{
+					DeadLetterTopic:     "dead-letter",
+					MaxDeliveryAttempts: ptr.Int32(4),
+				}
+				return *obj
+			}(),
+			error: true,
+		},
+		"ok RateLimit": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RateLimit = &v1beta1.RateLimitSpec{EventsPerSecond: 10}
+				return *obj
+			}(),
+			error: false,
+		},
+		"bad RateLimit": {
+			spec: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RateLimit = &v1beta1.RateLimitSpec{}
+				return *obj
+			}(),
+			error: true,
+		},
 		"bad sink, name": {
 			spec: func() PullSubscriptionSpec {
 				obj := pullSubscriptionSpec.DeepCopy()
@@ -557,6 +716,88 @@ func TestPubSubCheckImmutableFields(t *testing.T) {
 			},
 			allowed: true,
 		},
+		"RetryPolicy changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RetryPolicy = &RetryPolicy{MinimumBackoff: ptr.String("30s")}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"DeadLetterPolicy changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.DeadLetterPolicy = &DeadLetterPolicy{DeadLetterTopic: "dead-letter"}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"CreateTopic changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.CreateTopic = true
+				obj.TopicConfig = &TopicConfig{DeletionPolicy: TopicDeletionPolicyDelete}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"RateLimit changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.RateLimit = &v1beta1.RateLimitSpec{EventsPerSecond: 10}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Istio changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Istio = &v1beta1.IstioSpec{HoldApplicationUntilProxyStarts: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Proxy changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Proxy = &v1beta1.ProxySpec{HTTPSProxy: "http://proxy:3128"}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"Shutdown changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.Shutdown = &v1beta1.ShutdownSpec{Drain: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"SinkTLS changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.SinkTLS = &v1beta1.SinkTLSSpec{InsecureSkipVerify: true}
+				return *obj
+			}(),
+			allowed: true,
+		},
+		"EnableMessageOrdering changed": {
+			orig: &pullSubscriptionSpec,
+			updated: func() PullSubscriptionSpec {
+				obj := pullSubscriptionSpec.DeepCopy()
+				obj.EnableMessageOrdering = true
+				return *obj
+			}(),
+			allowed: false,
+		},
 		"no change": {
 			orig:    &pullSubscriptionSpec,
 			updated: pullSubscriptionSpec,
@@ -590,3 +831,290 @@ func TestPubSubCheckImmutableFields(t *testing.T) {
 		})
 	}
 }
+
+type notFoundVerifier struct{}
+
+func (notFoundVerifier) Verify(context.Context, string, string) error {
+	return topiccheck.ErrTopicNotFound
+}
+
+func TestPullSubscriptionValidateTopicCheck(t *testing.T) {
+	ps := &PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "ns"},
+		Spec:       *pullSubscriptionSpec.DeepCopy(),
+	}
+
+	ctx := apis.WithinCreate(context.Background())
+	if err := ps.Validate(ctx); err != nil {
+		t.Errorf("Validate() without topic check got unexpected error %v", err)
+	}
+
+	ctx = topiccheck.WithVerifier(ctx, notFoundVerifier{}, topiccheck.ModeReject)
+	err := ps.Validate(ctx)
+	if err == nil {
+		t.Fatal("Validate() with a missing topic got no error")
+	}
+	if got, want := err.Paths, []string{"spec.topic"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Validate() error paths got=%v, want=%v", got, want)
+	}
+
+	// Topics that are created when missing are not verified.
+	ps.Spec.CreateTopic = true
+	if err := ps.Validate(ctx); err != nil {
+		t.Errorf("Validate() with CreateTopic got unexpected error %v", err)
+	}
+
+	// Pub/Sub Lite topics are not verified.
+	ps.Spec.LiteConfig = &LiteConfig{Location: "us-central1-a"}
+	if err := ps.Validate(ctx); err != nil {
+		t.Errorf("Validate() with LiteConfig got unexpected error %v", err)
+	}
+}
+
+func TestPullSubscriptionValidateLite(t *testing.T) {
+	liteSpec := pullSubscriptionSpec.DeepCopy()
+	liteSpec.LiteConfig = &LiteConfig{Location: "us-central1-a"}
+	perPartitionBytes := resource.MustParse("64Gi")
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		spec        func(*PullSubscriptionSpec)
+		// wantErr is the path of the expected error, if any.
+		wantErr string
+	}{{
+		name: "lite",
+	}, {
+		name: "lite topic capacity",
+		spec: func(s *PullSubscriptionSpec) {
+			s.LiteConfig.Partitions = ptr.Int32(2)
+			s.LiteConfig.PerPartitionBytes = &perPartitionBytes
+			s.LiteConfig.PublishMiBPerSec = ptr.Int32(16)
+			s.LiteConfig.SubscribeMiBPerSec = ptr.Int32(32)
+		},
+	}, {
+		name:    "missing location",
+		spec:    func(s *PullSubscriptionSpec) { s.LiteConfig.Location = "" },
+		wantErr: "spec.liteConfig.location",
+	}, {
+		name:    "region location",
+		spec:    func(s *PullSubscriptionSpec) { s.LiteConfig.Location = "us-central1" },
+		wantErr: "spec.liteConfig.location",
+	}, {
+		name:    "no partitions",
+		spec:    func(s *PullSubscriptionSpec) { s.LiteConfig.Partitions = ptr.Int32(0) },
+		wantErr: "spec.liteConfig.partitions",
+	}, {
+		name: "too little storage",
+		spec: func(s *PullSubscriptionSpec) {
+			q := resource.MustParse("1Gi")
+			s.LiteConfig.PerPartitionBytes = &q
+		},
+		wantErr: "spec.liteConfig.perPartitionBytes",
+	}, {
+		name:    "publish throughput out of bounds",
+		spec:    func(s *PullSubscriptionSpec) { s.LiteConfig.PublishMiBPerSec = ptr.Int32(32) },
+		wantErr: "spec.liteConfig.publishMiBPerSec",
+	}, {
+		name:    "subscribe throughput out of bounds",
+		spec:    func(s *PullSubscriptionSpec) { s.LiteConfig.SubscribeMiBPerSec = ptr.Int32(2) },
+		wantErr: "spec.liteConfig.subscribeMiBPerSec",
+	}, {
+		name:    "cross-project topic",
+		spec:    func(s *PullSubscriptionSpec) { s.Topic = "projects/other-project/topics/lite-topic" },
+		wantErr: "spec.topic",
+	}, {
+		name:    "ack deadline",
+		spec:    func(s *PullSubscriptionSpec) { s.AckDeadline = ptr.String("30s") },
+		wantErr: "spec.ackDeadline",
+	}, {
+		name:    "retain acked messages",
+		spec:    func(s *PullSubscriptionSpec) { s.RetainAckedMessages = true },
+		wantErr: "spec.retainAckedMessages",
+	}, {
+		name:    "retry policy",
+		spec:    func(s *PullSubscriptionSpec) { s.RetryPolicy = &RetryPolicy{} },
+		wantErr: "spec.retryPolicy",
+	}, {
+		name:    "dead letter policy",
+		spec:    func(s *PullSubscriptionSpec) { s.DeadLetterPolicy = &DeadLetterPolicy{DeadLetterTopic: "dead-letter"} },
+		wantErr: "spec.deadLetterPolicy",
+	}, {
+		name: "topic labels",
+		spec: func(s *PullSubscriptionSpec) {
+			s.CreateTopic = true
+			s.TopicConfig = &TopicConfig{Labels: map[string]string{"team": "orders"}}
+		},
+		wantErr: "spec.topicConfig.labels",
+	}, {
+		name:    "push compatible mode",
+		spec:    func(s *PullSubscriptionSpec) { s.Mode = ModePushCompatible },
+		wantErr: "spec.mode",
+	}, {
+		name:        "agent mode",
+		annotations: map[string]string{v1beta1.ReceiveAdapterModeAnnotation: v1beta1.ReceiveAdapterModeAgent},
+		wantErr:     v1beta1.ReceiveAdapterModeAnnotation,
+	}, {
+		name:        "keda",
+		annotations: map[string]string{v1beta1.AutoscalingClassAnnotation: v1beta1.KEDA},
+		wantErr:     v1beta1.AutoscalingClassAnnotation,
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := liteSpec.DeepCopy()
+			if tc.spec != nil {
+				tc.spec(spec)
+			}
+			ps := &PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "ns", Annotations: tc.annotations},
+				Spec:       *spec,
+			}
+			err := ps.Validate(apis.WithinCreate(context.Background()))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() got unexpected error %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() got error %v, want an error for %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPullSubscriptionValidatePushAuthAnnotations(t *testing.T) {
+	pushSpec := pullSubscriptionSpec.DeepCopy()
+	pushSpec.Mode = ModePushCompatible
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		spec        *PullSubscriptionSpec
+		// wantErr is the path of the expected error, if any.
+		wantErr string
+	}{{
+		name: "no annotations",
+		spec: pullSubscriptionSpec.DeepCopy(),
+	}, {
+		name: "service account and audience",
+		annotations: map[string]string{
+			PushAuthServiceAccountAnnotation: "push@my-project.iam.gserviceaccount.com",
+			PushAuthAudienceAnnotation:       "https://example.com",
+		},
+		spec: pushSpec,
+	}, {
+		name: "invalid service account",
+		annotations: map[string]string{
+			PushAuthServiceAccountAnnotation: "push",
+		},
+		spec:    pushSpec,
+		wantErr: "metadata.annotations[events.cloud.google.com/push-auth-service-account]",
+	}, {
+		name: "service account without push mode",
+		annotations: map[string]string{
+			PushAuthServiceAccountAnnotation: "push@my-project.iam.gserviceaccount.com",
+		},
+		spec:    pullSubscriptionSpec.DeepCopy(),
+		wantErr: "metadata.annotations[events.cloud.google.com/push-auth-service-account]",
+	}, {
+		name: "audience without service account",
+		annotations: map[string]string{
+			PushAuthAudienceAnnotation: "https://example.com",
+		},
+		spec:    pushSpec,
+		wantErr: "metadata.annotations[events.cloud.google.com/push-auth-audience]",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := &PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "ns", Annotations: tc.annotations},
+				Spec:       *tc.spec,
+			}
+			err := ps.Validate(apis.WithinCreate(context.Background()))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() got unexpected error %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() got error %v, want an error for %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPullSubscriptionValidateProxy(t *testing.T) {
+	spec := pullSubscriptionSpec.DeepCopy()
+	spec.Proxy = &v1beta1.ProxySpec{HTTPSProxy: "http://proxy:3128"}
+	ps := &PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "ns"},
+		Spec:       *spec,
+	}
+	if err := ps.Validate(apis.WithinCreate(context.Background())); err != nil {
+		t.Errorf("Validate() got unexpected error %v", err)
+	}
+
+	ps.Spec.Proxy.HTTPSProxy = "proxy:3128"
+	if err := ps.Validate(apis.WithinCreate(context.Background())); err == nil || !strings.Contains(err.Error(), "spec.proxy.httpsProxy") {
+		t.Errorf("Validate() got error %v, want an error for spec.proxy.httpsProxy", err)
+	}
+}
+
+func TestPullSubscriptionValidateSinkTLS(t *testing.T) {
+	caBundleSpec := pullSubscriptionSpec.DeepCopy()
+	caBundleSpec.SinkTLS = &v1beta1.SinkTLSSpec{
+		CABundle: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+			Key:                  "ca.pem",
+		},
+	}
+	skipVerifySpec := pullSubscriptionSpec.DeepCopy()
+	skipVerifySpec.SinkTLS = &v1beta1.SinkTLSSpec{InsecureSkipVerify: true}
+	bothSpec := caBundleSpec.DeepCopy()
+	bothSpec.SinkTLS.InsecureSkipVerify = true
+	agent := map[string]string{v1beta1.ReceiveAdapterModeAnnotation: v1beta1.ReceiveAdapterModeAgent}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		spec        *PullSubscriptionSpec
+		// wantErr is the path of the expected error, if any.
+		wantErr string
+	}{{
+		name: "ca bundle",
+		spec: caBundleSpec,
+	}, {
+		name: "insecure skip verify",
+		spec: skipVerifySpec,
+	}, {
+		name:        "insecure skip verify in agent mode",
+		annotations: agent,
+		spec:        skipVerifySpec,
+	}, {
+		name:    "ca bundle and insecure skip verify",
+		spec:    bothSpec,
+		wantErr: "spec.sinkTLS.caBundle",
+	}, {
+		name:        "ca bundle in agent mode",
+		annotations: agent,
+		spec:        caBundleSpec,
+		wantErr:     "spec.sinkTLS.caBundle",
+	}}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := &PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{Name: "ps", Namespace: "ns", Annotations: tc.annotations},
+				Spec:       *tc.spec,
+			}
+			err := ps.Validate(apis.WithinCreate(context.Background()))
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() got unexpected error %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() got error %v, want an error for %s", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -44,6 +44,8 @@ var (
 // Adds the list of known types to Scheme.
 func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
+		&BrokerCell{},
+		&BrokerCellList{},
 		&PullSubscription{},
 		&PullSubscriptionList{},
 		&Topic{},
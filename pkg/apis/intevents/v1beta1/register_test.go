@@ -79,6 +79,8 @@ func TestAddKnownTypes(t *testing.T) {
 	}
 
 	want := []string{
+		"BrokerCell",
+		"BrokerCellList",
 		"PullSubscription",
 		"PullSubscriptionList",
 		"Topic",
@@ -19,11 +19,14 @@ package v1beta1
 import (
 	"context"
 
-	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"knative.dev/eventing/pkg/logging"
 	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 )
 
 var (
@@ -33,6 +36,7 @@ var (
 func (t *Topic) SetDefaults(ctx context.Context) {
 	ctx = apis.WithinParent(ctx, t.ObjectMeta)
 	t.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &t.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 }
 
 func (ts *TopicSpec) SetDefaults(ctx context.Context) {
@@ -46,10 +50,14 @@ func (ts *TopicSpec) SetDefaults(ctx context.Context) {
 		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
 		return
 	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
 	if ts.ServiceAccountName == "" &&
 		(ts.Secret == nil || equality.Semantic.DeepEqual(ts.Secret, &corev1.SecretKeySelector{})) {
-		ts.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
-		ts.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
+		ts.ServiceAccountName = rd.ServiceAccountName
+		ts.Secret = rd.Secret
+	}
+	if ts.Project == "" {
+		ts.Project = rd.Project
 	}
 
 	if ts.EnablePublisher == nil {
@@ -110,6 +110,18 @@ const (
 	TopicPolicyNoCreateNoDelete PropagationPolicyType = "NoCreateNoDelete"
 )
 
+const (
+	// PublisherDeadLetterTopicAnnotation is the annotation of the Pub/Sub topic the publisher publishes events to
+	// when it can't publish them to the topic of the Topic, e.g. because of quota or permission errors. Either a topic
+	// ID in the project of the Topic or the full name of a topic in another project.
+	PublisherDeadLetterTopicAnnotation = "events.cloud.google.com/publisher-dead-letter-topic"
+
+	// PublisherBufferSizeAnnotation is the annotation of the maximum number of events the publisher buffers locally,
+	// and publishes again later, when it can publish them to neither topic. The publisher responds with 202 Accepted
+	// to buffered events. Publishers that buffer events don't scale to zero.
+	PublisherBufferSizeAnnotation = "events.cloud.google.com/publisher-buffer-size"
+)
+
 var topicCondSet = apis.NewLivingConditionSet(
 	TopicConditionTopicExists,
 )
@@ -150,6 +162,11 @@ type TopicStatus struct {
 	// TopicID is the created topic ID used by the Topic.
 	// +optional
 	TopicID string `json:"topicId,omitempty"`
+
+	// TopicName is the fully qualified name of the topic used by the Topic,
+	// e.g. projects/my-project/topics/my-topic.
+	// +optional
+	TopicName string `json:"topicName,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
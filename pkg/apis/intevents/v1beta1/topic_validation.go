@@ -19,12 +19,37 @@ package v1beta1
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"knative.dev/pkg/apis"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
 )
 
 func (t *Topic) Validate(ctx context.Context) *apis.FieldError {
-	return t.Spec.Validate(ctx).ViaField("spec")
+	errs := t.Spec.Validate(ctx).ViaField("spec")
+	return validatePublisherAnnotations(t, errs)
+}
+
+func validatePublisherAnnotations(t *Topic, errs *apis.FieldError) *apis.FieldError {
+	if dlt, ok := t.Annotations[PublisherDeadLetterTopicAnnotation]; ok {
+		path := fmt.Sprintf("metadata.annotations[%s]", PublisherDeadLetterTopicAnnotation)
+		if project, topic, err := utils.ParseTopic(dlt); err != nil || topic == "" {
+			errs = errs.Also(apis.ErrInvalidValue(dlt, path))
+		} else if topic == t.Spec.Topic && (project == "" || project == t.Spec.Project) {
+			errs = errs.Also(&apis.FieldError{
+				Message: "The dead letter topic must differ from the topic",
+				Paths:   []string{path},
+			})
+		}
+	}
+	if size, ok := t.Annotations[PublisherBufferSizeAnnotation]; ok {
+		if v, err := strconv.Atoi(size); err != nil || v < 1 {
+			errs = errs.Also(apis.ErrInvalidValue(size, fmt.Sprintf("metadata.annotations[%s]", PublisherBufferSizeAnnotation)))
+		}
+	}
+	return errs
 }
 
 func (ts *TopicSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -65,5 +90,6 @@ func (current *Topic) CheckImmutableFields(ctx context.Context, original *Topic)
 				Details: fmt.Sprintf("was %q, now %q", original.Spec.Topic, current.Spec.Topic),
 			})
 	}
-	return errs
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, errs)
 }
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/webhook/resourcesemantics"
 )
 
@@ -72,10 +73,48 @@ func TestTopicValidation(t *testing.T) {
 		want: []string{
 			"invalid value: invalid-propagation-policy: spec.propagationPolicy",
 		},
+	}, {
+		name: "invalid publisher annotations",
+		cr: &Topic{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					PublisherDeadLetterTopicAnnotation: "projects/p/topic",
+					PublisherBufferSizeAnnotation:      "0",
+				},
+			},
+			Spec: TopicSpec{
+				Topic:             "topic",
+				PropagationPolicy: TopicPolicyCreateNoDelete,
+			},
+		},
+		want: []string{
+			"invalid value: projects/p/topic: metadata.annotations[events.cloud.google.com/publisher-dead-letter-topic]",
+			"invalid value: 0: metadata.annotations[events.cloud.google.com/publisher-buffer-size]",
+		},
+	}, {
+		name: "dead letter topic is the topic",
+		cr: &Topic{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					PublisherDeadLetterTopicAnnotation: "projects/project/topics/topic",
+				},
+			},
+			Spec: TopicSpec{
+				Project:           "project",
+				Topic:             "topic",
+				PropagationPolicy: TopicPolicyCreateNoDelete,
+			},
+		},
+		want: []string{
+			"The dead letter topic must differ from the topic",
+		},
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			got := test.cr.Validate(context.TODO())
+			if test.want == nil && got != nil {
+				t.Errorf("%s: validate = %v, want nil", test.name, got)
+			}
 
 			for _, v := range test.want {
 				if !strings.Contains(got.Error(), v) {
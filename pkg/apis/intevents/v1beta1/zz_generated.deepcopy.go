@@ -21,12 +21,274 @@ limitations under the License.
 package v1beta1
 
 import (
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apis "knative.dev/pkg/apis"
-	v1 "knative.dev/pkg/apis/duck/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerCell) DeepCopyInto(out *BrokerCell) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerCell.
+func (in *BrokerCell) DeepCopy() *BrokerCell {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerCell)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerCell) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerCellList) DeepCopyInto(out *BrokerCellList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BrokerCell, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerCellList.
+func (in *BrokerCellList) DeepCopy() *BrokerCellList {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerCellList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BrokerCellList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerCellSpec) DeepCopyInto(out *BrokerCellSpec) {
+	*out = *in
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(duckv1beta1.IstioSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(duckv1beta1.ProxySpec)
+		**out = **in
+	}
+	if in.BrokerSelector != nil {
+		in, out := &in.BrokerSelector, &out.BrokerSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CABundle != nil {
+		in, out := &in.CABundle, &out.CABundle
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Components.DeepCopyInto(&out.Components)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerCellSpec.
+func (in *BrokerCellSpec) DeepCopy() *BrokerCellSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerCellSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BrokerCellStatus) DeepCopyInto(out *BrokerCellStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ComponentReplicas)
+		**out = **in
+	}
+	if in.Fanout != nil {
+		in, out := &in.Fanout, &out.Fanout
+		*out = new(ComponentReplicas)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(ComponentReplicas)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BrokerCellStatus.
+func (in *BrokerCellStatus) DeepCopy() *BrokerCellStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BrokerCellStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentParameters) DeepCopyInto(out *ComponentParameters) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxReplicas != nil {
+		in, out := &in.MaxReplicas, &out.MaxReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentParameters.
+func (in *ComponentParameters) DeepCopy() *ComponentParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentReplicas) DeepCopyInto(out *ComponentReplicas) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentReplicas.
+func (in *ComponentReplicas) DeepCopy() *ComponentReplicas {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentReplicas)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentsParametersSpec) DeepCopyInto(out *ComponentsParametersSpec) {
+	*out = *in
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(ComponentParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fanout != nil {
+		in, out := &in.Fanout, &out.Fanout
+		*out = new(ComponentParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(ComponentParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentsParametersSpec.
+func (in *ComponentsParametersSpec) DeepCopy() *ComponentsParametersSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentsParametersSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeadLetterPolicy) DeepCopyInto(out *DeadLetterPolicy) {
+	*out = *in
+	if in.MaxDeliveryAttempts != nil {
+		in, out := &in.MaxDeliveryAttempts, &out.MaxDeliveryAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeadLetterPolicy.
+func (in *DeadLetterPolicy) DeepCopy() *DeadLetterPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(DeadLetterPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LiteConfig) DeepCopyInto(out *LiteConfig) {
+	*out = *in
+	if in.Partitions != nil {
+		in, out := &in.Partitions, &out.Partitions
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PerPartitionBytes != nil {
+		in, out := &in.PerPartitionBytes, &out.PerPartitionBytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PublishMiBPerSec != nil {
+		in, out := &in.PublishMiBPerSec, &out.PublishMiBPerSec
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SubscribeMiBPerSec != nil {
+		in, out := &in.SubscribeMiBPerSec, &out.SubscribeMiBPerSec
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LiteConfig.
+func (in *LiteConfig) DeepCopy() *LiteConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LiteConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSubscription) DeepCopyInto(out *PullSubscription) {
 	*out = *in
@@ -97,14 +359,59 @@ func (in *PullSubscriptionSpec) DeepCopyInto(out *PullSubscriptionSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.TopicConfig != nil {
+		in, out := &in.TopicConfig, &out.TopicConfig
+		*out = new(TopicConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RetentionDuration != nil {
 		in, out := &in.RetentionDuration, &out.RetentionDuration
 		*out = new(string)
 		**out = **in
 	}
+	if in.RetryPolicy != nil {
+		in, out := &in.RetryPolicy, &out.RetryPolicy
+		*out = new(RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeadLetterPolicy != nil {
+		in, out := &in.DeadLetterPolicy, &out.DeadLetterPolicy
+		*out = new(DeadLetterPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Transformer != nil {
 		in, out := &in.Transformer, &out.Transformer
-		*out = new(v1.Destination)
+		*out = new(duckv1.Destination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(duckv1beta1.IstioSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(duckv1beta1.ProxySpec)
+		**out = **in
+	}
+	if in.Shutdown != nil {
+		in, out := &in.Shutdown, &out.Shutdown
+		*out = new(duckv1beta1.ShutdownSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SinkTLS != nil {
+		in, out := &in.SinkTLS, &out.SinkTLS
+		*out = new(duckv1beta1.SinkTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(duckv1beta1.RateLimitSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LiteConfig != nil {
+		in, out := &in.LiteConfig, &out.LiteConfig
+		*out = new(LiteConfig)
 		(*in).DeepCopyInto(*out)
 	}
 	return
@@ -142,6 +449,32 @@ func (in *PullSubscriptionStatus) DeepCopy() *PullSubscriptionStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	if in.MinimumBackoff != nil {
+		in, out := &in.MinimumBackoff, &out.MinimumBackoff
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaximumBackoff != nil {
+		in, out := &in.MaximumBackoff, &out.MaximumBackoff
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topic) DeepCopyInto(out *Topic) {
 	*out = *in
@@ -170,6 +503,34 @@ func (in *Topic) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicConfig) DeepCopyInto(out *TopicConfig) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AllowedPersistenceRegions != nil {
+		in, out := &in.AllowedPersistenceRegions, &out.AllowedPersistenceRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicConfig.
+func (in *TopicConfig) DeepCopy() *TopicConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopicList) DeepCopyInto(out *TopicList) {
 	*out = *in
@@ -29,4 +29,16 @@ var (
 		Group:    GroupName,
 		Resource: "channels",
 	}
+
+	// ParallelsResource represents a Parallel.
+	ParallelsResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "parallels",
+	}
+
+	// SequencesResource represents a Sequence.
+	SequencesResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "sequences",
+	}
 )
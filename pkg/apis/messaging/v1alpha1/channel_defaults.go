@@ -51,7 +51,8 @@ func (c *Channel) SetDefaults(ctx context.Context) {
 		c.Annotations[messaging.SubscribableDuckVersionAnnotation] = internal.StoredChannelVersion
 	}
 	c.Spec.SetDefaults(ctx)
-	duckv1alpha1.SetClusterNameAnnotation(&c.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetClusterNameAnnotation(ctx, &c.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+	duckv1alpha1.SetDeprecatedAPIVersionAnnotation(ctx, &c.ObjectMeta, SchemeGroupVersion)
 }
 
 func (cs *ChannelSpec) SetDefaults(ctx context.Context) {
@@ -61,8 +62,12 @@ func (cs *ChannelSpec) SetDefaults(ctx context.Context) {
 		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
 		return
 	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
 	if cs.ServiceAccountName == "" && cs.Secret == nil || equality.Semantic.DeepEqual(cs.Secret, &corev1.SecretKeySelector{}) {
-		cs.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
-		cs.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
+		cs.ServiceAccountName = rd.ServiceAccountName
+		cs.Secret = rd.Secret
+	}
+	if cs.Project == "" {
+		cs.Project = rd.Project
 	}
 }
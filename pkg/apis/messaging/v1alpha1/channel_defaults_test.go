@@ -25,6 +25,7 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 )
 
@@ -32,8 +33,9 @@ func TestChannelDefaults(t *testing.T) {
 	want := &Channel{
 		ObjectMeta: v1.ObjectMeta{
 			Annotations: map[string]string{
-				"messaging.knative.dev/subscribable": "v1alpha1",
-				duckv1alpha1.ClusterNameAnnotation:   testingMetadataClient.FakeClusterName,
+				"messaging.knative.dev/subscribable":        "v1alpha1",
+				duckv1alpha1.ClusterNameAnnotation:          testingMetadataClient.FakeClusterName,
+				duckv1beta1.DeprecatedAPIVersionAnnotation: SchemeGroupVersion.String(),
 			},
 		},
 		Spec: ChannelSpec{
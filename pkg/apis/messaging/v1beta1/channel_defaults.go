@@ -22,6 +22,8 @@ import (
 	"knative.dev/eventing/pkg/logging"
 
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 
 	"knative.dev/pkg/apis"
 
@@ -50,6 +52,7 @@ func (c *Channel) SetDefaults(ctx context.Context) {
 		c.Annotations[messaging.SubscribableDuckVersionAnnotation] = internal.StoredChannelVersion
 	}
 	c.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &c.ObjectMeta, metadataClient.NewDefaultMetadataClient())
 }
 
 func (cs *ChannelSpec) SetDefaults(ctx context.Context) {
@@ -59,8 +62,12 @@ func (cs *ChannelSpec) SetDefaults(ctx context.Context) {
 		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
 		return
 	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
 	if cs.ServiceAccountName == "" && cs.Secret == nil || equality.Semantic.DeepEqual(cs.Secret, &corev1.SecretKeySelector{}) {
-		cs.ServiceAccountName = ad.KSA(apis.ParentMeta(ctx).Namespace)
-		cs.Secret = ad.Secret(apis.ParentMeta(ctx).Namespace)
+		cs.ServiceAccountName = rd.ServiceAccountName
+		cs.Secret = rd.Secret
+	}
+	if cs.Project == "" {
+		cs.Project = rd.Project
 	}
 }
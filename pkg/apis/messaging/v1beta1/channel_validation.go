@@ -26,7 +26,8 @@ import (
 )
 
 func (c *Channel) Validate(ctx context.Context) *apis.FieldError {
-	return c.Spec.Validate(ctx).ViaField("spec")
+	errs := c.Spec.Validate(ctx).ViaField("spec")
+	return duckv1beta1.ValidateMetricsAnnotations(ctx, c.Annotations, errs)
 }
 
 func (cs *ChannelSpec) Validate(ctx context.Context) *apis.FieldError {
@@ -73,5 +74,6 @@ func (current *Channel) CheckImmutableFields(ctx context.Context, original *Chan
 		}
 	}
 
-	return nil
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
 }
@@ -24,6 +24,7 @@ import (
 	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/webhook/resourcesemantics"
@@ -171,6 +172,17 @@ func TestChannelValidation(t *testing.T) {
 			}
 			return fe
 		}(),
+	}, {
+		name: "invalid metrics backend",
+		cr: &Channel{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					duckv1beta1.MetricsBackendDestinationAnnotation: "invalid",
+				},
+			},
+			Spec: channelSpec,
+		},
+		want: apis.ErrInvalidValue("invalid", "metadata.annotations[metrics.events.cloud.google.com/backend-destination]"),
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
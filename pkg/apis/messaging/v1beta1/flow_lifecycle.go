@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// propagateTopicsStatus marks the condition t of a Sequence or Parallel true
+// if all its topics are ready, and otherwise propagates the condition of the
+// first topic that is not.
+func propagateTopicsStatus(m apis.ConditionManager, t apis.ConditionType, topics []*v1beta1.Topic) {
+	for _, topic := range topics {
+		if !propagateStatus(m, t, "Topic", topic.Name, topic.Status.GetTopLevelCondition()) {
+			return
+		}
+	}
+	m.MarkTrue(t)
+}
+
+// propagateSubscriptionsStatus marks the condition t of a Sequence or
+// Parallel true if all its PullSubscriptions are ready, and otherwise
+// propagates the condition of the first PullSubscription that is not.
+func propagateSubscriptionsStatus(m apis.ConditionManager, t apis.ConditionType, subscriptions []*v1beta1.PullSubscription) {
+	for _, ps := range subscriptions {
+		if !propagateStatus(m, t, "PullSubscription", ps.Name, ps.Status.GetTopLevelCondition()) {
+			return
+		}
+	}
+	m.MarkTrue(t)
+}
+
+// propagateStatus propagates the top level condition c of the named resource
+// to the condition t if it is not true, returning whether it is true.
+func propagateStatus(m apis.ConditionManager, t apis.ConditionType, kind, name string, c *apis.Condition) bool {
+	switch {
+	case c == nil:
+		m.MarkUnknown(t, kind+"NotConfigured", "%s %q has not yet been reconciled", kind, name)
+	case c.Status == corev1.ConditionTrue:
+		return true
+	case c.Status == corev1.ConditionFalse:
+		m.MarkFalse(t, c.Reason, "%s %q: %s", kind, name, c.Message)
+	default:
+		m.MarkUnknown(t, c.Reason, "%s %q: %s", kind, name, c.Message)
+	}
+	return false
+}
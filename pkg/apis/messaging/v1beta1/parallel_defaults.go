@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"knative.dev/eventing/pkg/logging"
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+)
+
+func (p *Parallel) SetDefaults(ctx context.Context) {
+	ctx = apis.WithinParent(ctx, p.ObjectMeta)
+	p.Spec.SetDefaults(ctx)
+	duckv1beta1.SetClusterNameAnnotation(ctx, &p.ObjectMeta, metadataClient.NewDefaultMetadataClient())
+}
+
+func (ps *ParallelSpec) SetDefaults(ctx context.Context) {
+	// Default the namespace of the branches and the reply.
+	for i := range ps.Branches {
+		ps.Branches[i].SetDefaults(ctx)
+	}
+	if ps.Reply != nil {
+		ps.Reply.SetDefaults(ctx)
+	}
+
+	ad := gcpauth.FromContextOrDefaults(ctx).GCPAuthDefaults
+	if ad == nil {
+		logging.FromContext(ctx).Error("Failed to get the GCPAuthDefaults")
+		return
+	}
+	rd := ad.ForNamespace(ctx, apis.ParentMeta(ctx).Namespace)
+	if ps.ServiceAccountName == "" && ps.Secret == nil || equality.Semantic.DeepEqual(ps.Secret, &corev1.SecretKeySelector{}) {
+		ps.ServiceAccountName = rd.ServiceAccountName
+		ps.Secret = rd.Secret
+	}
+	if ps.Project == "" {
+		ps.Project = rd.Project
+	}
+}
+
+func (pb *ParallelBranch) SetDefaults(ctx context.Context) {
+	if pb.Filter != nil {
+		pb.Filter.SetDefaults(ctx)
+	}
+	pb.Subscriber.SetDefaults(ctx)
+	if pb.Reply != nil {
+		pb.Reply.SetDefaults(ctx)
+	}
+}
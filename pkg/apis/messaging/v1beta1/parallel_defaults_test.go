@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+)
+
+func TestParallelDefaults(t *testing.T) {
+	got := Parallel{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "mynamespace",
+		},
+		Spec: ParallelSpec{
+			Branches: []ParallelBranch{{
+				Filter: &duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "filter",
+					},
+				},
+				Subscriber: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "subscriber",
+					},
+				},
+			}},
+		},
+	}
+	want := Parallel{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: "mynamespace",
+		},
+		Spec: ParallelSpec{
+			Secret: &gcpauthtesthelper.Secret,
+			Branches: []ParallelBranch{{
+				Filter: &duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "filter",
+						Namespace:  "mynamespace",
+					},
+				},
+				Subscriber: duckv1.Destination{
+					Ref: &duckv1.KReference{
+						APIVersion: "v1",
+						Kind:       "Service",
+						Name:       "subscriber",
+						Namespace:  "mynamespace",
+					},
+				},
+			}},
+		},
+	}
+
+	got.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("failed to get expected (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// GetCondition returns the condition currently associated with the given type,
+// or nil.
+func (ps *ParallelStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return parallelCondSet.Manage(ps).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (ps *ParallelStatus) GetTopLevelCondition() *apis.Condition {
+	return parallelCondSet.Manage(ps).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ps *ParallelStatus) IsReady() bool {
+	return parallelCondSet.Manage(ps).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ps *ParallelStatus) InitializeConditions() {
+	parallelCondSet.Manage(ps).InitializeConditions()
+}
+
+// SetAddress updates the Addressable status of the parallel and propagates a
+// url status to the Addressable status condition based on url.
+func (ps *ParallelStatus) SetAddress(url *apis.URL) {
+	if ps.Address == nil {
+		ps.Address = &duckv1.Addressable{}
+	}
+	if url != nil {
+		ps.Address.URL = url
+		parallelCondSet.Manage(ps).MarkTrue(ParallelConditionAddressable)
+	} else {
+		ps.Address.URL = nil
+		parallelCondSet.Manage(ps).MarkFalse(ParallelConditionAddressable, "emptyUrl", "url is empty")
+	}
+}
+
+// PropagateTopicStatuses sets the TopicsReady condition based on the status
+// of the topics of the parallel and of its filtered branches.
+func (ps *ParallelStatus) PropagateTopicStatuses(topics []*v1beta1.Topic) {
+	propagateTopicsStatus(parallelCondSet.Manage(ps), ParallelConditionTopicsReady, topics)
+}
+
+// MarkTopicsFailed sets the condition that signals the topics of the parallel
+// could not be reconciled.
+func (ps *ParallelStatus) MarkTopicsFailed(reason, messageFormat string, messageA ...interface{}) {
+	parallelCondSet.Manage(ps).MarkFalse(ParallelConditionTopicsReady, reason, messageFormat, messageA...)
+}
+
+// PropagateSubscriptionStatuses sets the SubscriptionsReady condition based
+// on the status of the PullSubscriptions of the branches.
+func (ps *ParallelStatus) PropagateSubscriptionStatuses(subscriptions []*v1beta1.PullSubscription) {
+	propagateSubscriptionsStatus(parallelCondSet.Manage(ps), ParallelConditionSubscriptionsReady, subscriptions)
+}
+
+// MarkSubscriptionsFailed sets the condition that signals the
+// PullSubscriptions of the branches could not be reconciled.
+func (ps *ParallelStatus) MarkSubscriptionsFailed(reason, messageFormat string, messageA ...interface{}) {
+	parallelCondSet.Manage(ps).MarkFalse(ParallelConditionSubscriptionsReady, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+func TestParallelInitializeConditions(t *testing.T) {
+	ps := &ParallelStatus{}
+	ps.InitializeConditions()
+
+	want := &ParallelStatus{
+		IdentityStatus: duckv1beta1.IdentityStatus{
+			Status: duckv1.Status{
+				Conditions: []apis.Condition{{
+					Type:   ParallelConditionAddressable,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ParallelConditionReady,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ParallelConditionSubscriptionsReady,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   ParallelConditionTopicsReady,
+					Status: corev1.ConditionUnknown,
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, ps, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestParallelIsReady(t *testing.T) {
+	tests := []struct {
+		name          string
+		address       *apis.URL
+		subscriptions []*v1beta1.PullSubscription
+		wantReady     bool
+	}{{
+		name:          "all ready",
+		address:       apis.HTTP("ingress"),
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0"), readyPullSubscription("ps-1")},
+		wantReady:     true,
+	}, {
+		name:          "no address",
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0")},
+		wantReady:     false,
+	}, {
+		name:          "pullsubscription not reconciled",
+		address:       apis.HTTP("ingress"),
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0"), {}},
+		wantReady:     false,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ps := &ParallelStatus{}
+			ps.InitializeConditions()
+			ps.PropagateTopicStatuses([]*v1beta1.Topic{readyTopic("ingress")})
+			ps.SetAddress(test.address)
+			ps.PropagateSubscriptionStatuses(test.subscriptions)
+			if got := ps.IsReady(); got != test.wantReady {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Parallel is a resource representing a Knative Parallel backed by Google
+// Cloud Pub/Sub. Every branch receives the events published to a Pub/Sub
+// topic of the Parallel through a subscription of its own.
+type Parallel struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Parallel.
+	Spec ParallelSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Parallel. This data may be out of
+	// date.
+	// +optional
+	Status ParallelStatus `json:"status,omitempty"`
+}
+
+// Check that Parallel can be validated, can be defaulted, and has immutable fields.
+var (
+	_ apis.Convertible             = (*Parallel)(nil)
+	_ apis.Defaultable             = (*Parallel)(nil)
+	_ apis.Validatable             = (*Parallel)(nil)
+	_ runtime.Object               = (*Parallel)(nil)
+	_ resourcesemantics.GenericCRD = (*Parallel)(nil)
+	_ kngcpduck.Identifiable       = (*Parallel)(nil)
+)
+
+// ParallelSpec defines the branches of a Parallel and where their results are
+// sent.
+type ParallelSpec struct {
+	duckv1beta1.IdentitySpec `json:",inline"`
+	// Secret is the credential to use to create, publish, and poll the Pub/Sub
+	// Topics and Subscriptions. The value of the secret entry must be a
+	// service account key in the JSON format
+	// (see https://cloud.google.com/iam/docs/creating-managing-service-account-keys).
+	// +optional
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// Project is the ID of the Google Cloud Project that the Pub/Sub
+	// Topics and Subscriptions will be created in.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Branches is the list of Filter/Subscriber pairs that events are sent
+	// to.
+	Branches []ParallelBranch `json:"branches"`
+
+	// Reply is where the replies of the branch subscribers are sent to,
+	// unless the branch has a reply of its own. If not set, the replies are
+	// dropped.
+	// +optional
+	Reply *duckv1.Destination `json:"reply,omitempty"`
+}
+
+// ParallelBranch defines a single branch of a Parallel.
+type ParallelBranch struct {
+	// Filter is where events are sent to first. Only the events it replies
+	// with are sent to the subscriber of the branch. If not set, all events
+	// are sent to the subscriber.
+	// +optional
+	Filter *duckv1.Destination `json:"filter,omitempty"`
+
+	// Subscriber is where the events that passed the filter are sent to.
+	Subscriber duckv1.Destination `json:"subscriber"`
+
+	// Reply is where the replies of the subscriber are sent to. It takes
+	// precedence over the reply of the Parallel.
+	// +optional
+	Reply *duckv1.Destination `json:"reply,omitempty"`
+}
+
+var parallelCondSet = apis.NewLivingConditionSet(
+	ParallelConditionAddressable,
+	ParallelConditionTopicsReady,
+	ParallelConditionSubscriptionsReady,
+)
+
+const (
+	// ParallelConditionReady has status True when all subconditions below have
+	// been set to True.
+	ParallelConditionReady = apis.ConditionReady
+
+	// ParallelConditionAddressable has status true when this Parallel meets the
+	// Addressable contract and has a non-empty url.
+	ParallelConditionAddressable apis.ConditionType = "Addressable"
+
+	// ParallelConditionTopicsReady has status True when the Pub/Sub topics of
+	// the Parallel and of all its filtered branches are ready.
+	ParallelConditionTopicsReady apis.ConditionType = "TopicsReady"
+
+	// ParallelConditionSubscriptionsReady has status True when the Pub/Sub
+	// subscriptions delivering events to all the branches of the Parallel
+	// are ready.
+	ParallelConditionSubscriptionsReady apis.ConditionType = "SubscriptionsReady"
+)
+
+// ParallelStatus represents the current state of a Parallel.
+type ParallelStatus struct {
+	duckv1beta1.IdentityStatus `json:",inline"`
+
+	// Parallel is Addressable. It is the address of the topic all the
+	// branches subscribe to.
+	duckv1.AddressStatus `json:",inline"`
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *Parallel) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *Parallel) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *Parallel) ConditionSet() *apis.ConditionSet {
+	return &parallelCondSet
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ParallelList is a collection of Pub/Sub backed Parallels.
+type ParallelList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Parallel `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for Pub/Sub backed Parallel.
+func (s *Parallel) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Parallel")
+}
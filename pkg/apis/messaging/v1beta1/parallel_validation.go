@@ -0,0 +1,112 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func (p *Parallel) Validate(ctx context.Context) *apis.FieldError {
+	return p.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (ps *ParallelSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Branches [required]
+	if len(ps.Branches) == 0 {
+		errs = errs.Also(apis.ErrMissingField("branches"))
+	}
+	for i, branch := range ps.Branches {
+		if err := branch.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaFieldIndex("branches", i))
+		}
+	}
+
+	if ps.Reply != nil {
+		if err := ps.Reply.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("reply"))
+		}
+	}
+
+	if err := duckv1beta1.ValidateCredential(ps.Secret, ps.ServiceAccountName); err != nil {
+		errs = errs.Also(err)
+	}
+
+	return errs
+}
+
+func (pb *ParallelBranch) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	if pb.Filter != nil {
+		if err := pb.Filter.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("filter"))
+		}
+	}
+
+	// Subscriber [required]
+	if err := pb.Subscriber.Validate(ctx); err != nil {
+		errs = errs.Also(err.ViaField("subscriber"))
+	}
+
+	if pb.Reply != nil {
+		if err := pb.Reply.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("reply"))
+		}
+	}
+
+	return errs
+}
+
+func (current *Parallel) CheckImmutableFields(ctx context.Context, original *Parallel) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+
+	// Modification of ServiceAccountName, Secret and Project are not allowed.
+	// The branches and the reply are mutable.
+	if diff := cmp.Diff(original.Spec.IdentitySpec, current.Spec.IdentitySpec); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "serviceAccountName"},
+			Details: diff,
+		}
+	}
+	if diff := cmp.Diff(original.Spec.Secret, current.Spec.Secret); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "secret"},
+			Details: diff,
+		}
+	}
+	if diff := cmp.Diff(original.Spec.Project, current.Spec.Project); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "project"},
+			Details: diff,
+		}
+	}
+
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+var (
+	parallelSpec = ParallelSpec{
+		Branches: []ParallelBranch{{
+			Filter: &duckv1.Destination{
+				URI: apis.HTTP("filterendpoint"),
+			},
+			Subscriber: duckv1.Destination{
+				URI: apis.HTTP("subscriberendpoint"),
+			},
+		}},
+		Reply: &duckv1.Destination{
+			URI: apis.HTTP("replyendpoint"),
+		},
+	}
+)
+
+func TestParallelValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   resourcesemantics.GenericCRD
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		cr: &Parallel{
+			Spec: ParallelSpec{},
+		},
+		want: apis.ErrMissingField("spec.branches"),
+	}, {
+		name: "valid branches",
+		cr: &Parallel{
+			Spec: parallelSpec,
+		},
+		want: nil,
+	}, {
+		name: "empty subscriber at index 1",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{parallelSpec.Branches[0], {}},
+			},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "spec.branches[1].subscriber.ref", "spec.branches[1].subscriber.uri"),
+	}, {
+		name: "empty filter and reply",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Filter:     &duckv1.Destination{},
+					Subscriber: parallelSpec.Branches[0].Subscriber,
+					Reply:      &duckv1.Destination{},
+				}},
+			},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "spec.branches[0].filter.ref", "spec.branches[0].filter.uri").Also(
+			apis.ErrGeneric("expected at least one, got none", "spec.branches[0].reply.ref", "spec.branches[0].reply.uri")),
+	}, {
+		name: "invalid k8s service account",
+		cr: &Parallel{
+			Spec: ParallelSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: invalidServiceAccountName,
+				},
+				Branches: parallelSpec.Branches,
+			},
+		},
+		want: &apis.FieldError{
+			Message: `invalid value: @test, serviceAccountName should have format: ^[A-Za-z0-9](?:[A-Za-z0-9\-]{0,61}[A-Za-z0-9])?$`,
+			Paths:   []string{"spec.serviceAccountName"},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cr.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestParallelCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig    *ParallelSpec
+		updated ParallelSpec
+		allowed bool
+	}{
+		"nil orig": {
+			updated: ParallelSpec{},
+			allowed: true,
+		},
+		"Branches changed": {
+			orig: &parallelSpec,
+			updated: ParallelSpec{
+				Branches: []ParallelBranch{{
+					Subscriber: duckv1.Destination{
+						URI: apis.HTTP("otherendpoint"),
+					},
+				}},
+			},
+			allowed: true,
+		},
+		"ServiceAccount changed": {
+			orig: &parallelSpec,
+			updated: ParallelSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: "new-service-account",
+				},
+				Branches: parallelSpec.Branches,
+			},
+			allowed: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *Parallel
+			if tc.orig != nil {
+				orig = &Parallel{
+					Spec: *tc.orig,
+				}
+			}
+			updated := &Parallel{
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
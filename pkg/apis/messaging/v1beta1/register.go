@@ -47,6 +47,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Channel{},
 		&ChannelList{},
+		&Parallel{},
+		&ParallelList{},
+		&Sequence{},
+		&SequenceList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
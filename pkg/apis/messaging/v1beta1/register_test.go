@@ -60,6 +60,10 @@ func TestKnownTypes(t *testing.T) {
 	for _, name := range []string{
 		"Channel",
 		"ChannelList",
+		"Parallel",
+		"ParallelList",
+		"Sequence",
+		"SequenceList",
 	} {
 		if _, ok := types[name]; !ok {
 			t.Errorf("Did not find %q as registered type", name)
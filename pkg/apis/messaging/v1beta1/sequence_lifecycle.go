@@ -0,0 +1,84 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// GetCondition returns the condition currently associated with the given type,
+// or nil.
+func (ss *SequenceStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return sequenceCondSet.Manage(ss).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level condition.
+func (ss *SequenceStatus) GetTopLevelCondition() *apis.Condition {
+	return sequenceCondSet.Manage(ss).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ss *SequenceStatus) IsReady() bool {
+	return sequenceCondSet.Manage(ss).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ss *SequenceStatus) InitializeConditions() {
+	sequenceCondSet.Manage(ss).InitializeConditions()
+}
+
+// SetAddress updates the Addressable status of the sequence and propagates a
+// url status to the Addressable status condition based on url.
+func (ss *SequenceStatus) SetAddress(url *apis.URL) {
+	if ss.Address == nil {
+		ss.Address = &duckv1.Addressable{}
+	}
+	if url != nil {
+		ss.Address.URL = url
+		sequenceCondSet.Manage(ss).MarkTrue(SequenceConditionAddressable)
+	} else {
+		ss.Address.URL = nil
+		sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionAddressable, "emptyUrl", "url is empty")
+	}
+}
+
+// PropagateTopicStatuses sets the TopicsReady condition based on the status
+// of the topics of the steps.
+func (ss *SequenceStatus) PropagateTopicStatuses(topics []*v1beta1.Topic) {
+	propagateTopicsStatus(sequenceCondSet.Manage(ss), SequenceConditionTopicsReady, topics)
+}
+
+// MarkTopicsFailed sets the condition that signals the topics of the steps
+// could not be reconciled.
+func (ss *SequenceStatus) MarkTopicsFailed(reason, messageFormat string, messageA ...interface{}) {
+	sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionTopicsReady, reason, messageFormat, messageA...)
+}
+
+// PropagateSubscriptionStatuses sets the SubscriptionsReady condition based
+// on the status of the PullSubscriptions of the steps.
+func (ss *SequenceStatus) PropagateSubscriptionStatuses(subscriptions []*v1beta1.PullSubscription) {
+	propagateSubscriptionsStatus(sequenceCondSet.Manage(ss), SequenceConditionSubscriptionsReady, subscriptions)
+}
+
+// MarkSubscriptionsFailed sets the condition that signals the
+// PullSubscriptions of the steps could not be reconciled.
+func (ss *SequenceStatus) MarkSubscriptionsFailed(reason, messageFormat string, messageA ...interface{}) {
+	sequenceCondSet.Manage(ss).MarkFalse(SequenceConditionSubscriptionsReady, reason, messageFormat, messageA...)
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+var ignoreLastTransitionTime = cmpopts.IgnoreFields(apis.Condition{}, "LastTransitionTime")
+
+func readyTopic(name string) *v1beta1.Topic {
+	t := &v1beta1.Topic{}
+	t.Name = name
+	t.Status.InitializeConditions()
+	t.Status.MarkPublisherDeployed()
+	t.Status.MarkTopicReady()
+	return t
+}
+
+func readyPullSubscription(name string) *v1beta1.PullSubscription {
+	ps := &v1beta1.PullSubscription{}
+	ps.Name = name
+	ps.Status.InitializeConditions()
+	ps.Status.MarkSink(apis.HTTP("sink"))
+	ps.Status.MarkDeployedByAgent()
+	ps.Status.MarkSubscribed("subID")
+	return ps
+}
+
+func TestSequenceInitializeConditions(t *testing.T) {
+	ss := &SequenceStatus{}
+	ss.InitializeConditions()
+
+	want := &SequenceStatus{
+		IdentityStatus: duckv1beta1.IdentityStatus{
+			Status: duckv1.Status{
+				Conditions: []apis.Condition{{
+					Type:   SequenceConditionAddressable,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   SequenceConditionReady,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   SequenceConditionSubscriptionsReady,
+					Status: corev1.ConditionUnknown,
+				}, {
+					Type:   SequenceConditionTopicsReady,
+					Status: corev1.ConditionUnknown,
+				}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, ss, ignoreAllButTypeAndStatus); diff != "" {
+		t.Errorf("unexpected conditions (-want, +got) = %v", diff)
+	}
+}
+
+func TestSequenceIsReady(t *testing.T) {
+	falseTopic := readyTopic("topic-1")
+	falseTopic.Status.MarkNoTopic("TopicFailed", "no topic")
+	falsePullSubscription := readyPullSubscription("ps-1")
+	falsePullSubscription.Status.MarkNoSink("InvalidSink", "no sink")
+
+	tests := []struct {
+		name          string
+		address       *apis.URL
+		topics        []*v1beta1.Topic
+		subscriptions []*v1beta1.PullSubscription
+		wantReady     bool
+		wantCondition *apis.Condition
+	}{{
+		name:          "all ready",
+		address:       apis.HTTP("topic-0"),
+		topics:        []*v1beta1.Topic{readyTopic("topic-0"), readyTopic("topic-1")},
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0"), readyPullSubscription("ps-1")},
+		wantReady:     true,
+	}, {
+		name:          "no address",
+		topics:        []*v1beta1.Topic{readyTopic("topic-0")},
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0")},
+		wantReady:     false,
+	}, {
+		name:          "topic not reconciled",
+		address:       apis.HTTP("topic-0"),
+		topics:        []*v1beta1.Topic{readyTopic("topic-0"), {}},
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0")},
+		wantReady:     false,
+		wantCondition: &apis.Condition{
+			Type:    SequenceConditionTopicsReady,
+			Status:  corev1.ConditionUnknown,
+			Reason:  "TopicNotConfigured",
+			Message: `Topic "" has not yet been reconciled`,
+		},
+	}, {
+		name:          "topic false",
+		address:       apis.HTTP("topic-0"),
+		topics:        []*v1beta1.Topic{readyTopic("topic-0"), falseTopic},
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0")},
+		wantReady:     false,
+		wantCondition: &apis.Condition{
+			Type:    SequenceConditionTopicsReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "TopicFailed",
+			Message: `Topic "topic-1": no topic`,
+		},
+	}, {
+		name:          "pullsubscription false",
+		address:       apis.HTTP("topic-0"),
+		topics:        []*v1beta1.Topic{readyTopic("topic-0")},
+		subscriptions: []*v1beta1.PullSubscription{readyPullSubscription("ps-0"), falsePullSubscription},
+		wantReady:     false,
+		wantCondition: &apis.Condition{
+			Type:    SequenceConditionSubscriptionsReady,
+			Status:  corev1.ConditionFalse,
+			Reason:  "InvalidSink",
+			Message: `PullSubscription "ps-1": no sink`,
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ss := &SequenceStatus{}
+			ss.InitializeConditions()
+			ss.PropagateTopicStatuses(test.topics)
+			ss.SetAddress(test.address)
+			ss.PropagateSubscriptionStatuses(test.subscriptions)
+			if got := ss.IsReady(); got != test.wantReady {
+				t.Errorf("unexpected readiness: want %v, got %v", test.wantReady, got)
+			}
+			if test.wantCondition != nil {
+				got := ss.GetCondition(test.wantCondition.Type)
+				if diff := cmp.Diff(test.wantCondition, got, ignoreLastTransitionTime); diff != "" {
+					t.Errorf("unexpected condition (-want, +got) = %v", diff)
+				}
+			}
+		})
+	}
+}
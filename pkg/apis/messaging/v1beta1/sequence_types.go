@@ -0,0 +1,151 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	kngcpduck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+)
+
+// +genclient
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Sequence is a resource representing a Knative Sequence backed by Google
+// Cloud Pub/Sub. The input of each step is a Pub/Sub topic of its own.
+type Sequence struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the Sequence.
+	Spec SequenceSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the Sequence. This data may be out of
+	// date.
+	// +optional
+	Status SequenceStatus `json:"status,omitempty"`
+}
+
+// Check that Sequence can be validated, can be defaulted, and has immutable fields.
+var (
+	_ apis.Convertible             = (*Sequence)(nil)
+	_ apis.Defaultable             = (*Sequence)(nil)
+	_ apis.Validatable             = (*Sequence)(nil)
+	_ runtime.Object               = (*Sequence)(nil)
+	_ resourcesemantics.GenericCRD = (*Sequence)(nil)
+	_ kngcpduck.Identifiable       = (*Sequence)(nil)
+)
+
+// SequenceSpec defines the steps of a Sequence and where the result of the
+// last step is sent.
+type SequenceSpec struct {
+	duckv1beta1.IdentitySpec `json:",inline"`
+	// Secret is the credential to use to create, publish, and poll the Pub/Sub
+	// Topics and Subscriptions. The value of the secret entry must be a
+	// service account key in the JSON format
+	// (see https://cloud.google.com/iam/docs/creating-managing-service-account-keys).
+	// +optional
+	Secret *corev1.SecretKeySelector `json:"secret,omitempty"`
+
+	// Project is the ID of the Google Cloud Project that the Pub/Sub
+	// Topics and Subscriptions will be created in.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// Steps is the list of Destinations that events are sent to, in order.
+	// The reply of each step is the input of the next one.
+	Steps []duckv1.Destination `json:"steps"`
+
+	// Reply is where the reply of the last step is sent to. If not set, the
+	// reply of the last step is dropped.
+	// +optional
+	Reply *duckv1.Destination `json:"reply,omitempty"`
+}
+
+var sequenceCondSet = apis.NewLivingConditionSet(
+	SequenceConditionAddressable,
+	SequenceConditionTopicsReady,
+	SequenceConditionSubscriptionsReady,
+)
+
+const (
+	// SequenceConditionReady has status True when all subconditions below have
+	// been set to True.
+	SequenceConditionReady = apis.ConditionReady
+
+	// SequenceConditionAddressable has status true when this Sequence meets the
+	// Addressable contract and has a non-empty url.
+	SequenceConditionAddressable apis.ConditionType = "Addressable"
+
+	// SequenceConditionTopicsReady has status True when the Pub/Sub topics of
+	// all the steps of the Sequence are ready.
+	SequenceConditionTopicsReady apis.ConditionType = "TopicsReady"
+
+	// SequenceConditionSubscriptionsReady has status True when the Pub/Sub
+	// subscriptions delivering events to all the steps of the Sequence are
+	// ready.
+	SequenceConditionSubscriptionsReady apis.ConditionType = "SubscriptionsReady"
+)
+
+// SequenceStatus represents the current state of a Sequence.
+type SequenceStatus struct {
+	duckv1beta1.IdentityStatus `json:",inline"`
+
+	// Sequence is Addressable. It is the address of the topic of the first
+	// step.
+	duckv1.AddressStatus `json:",inline"`
+}
+
+// Methods for identifiable interface.
+// IdentitySpec returns the IdentitySpec portion of the Spec.
+func (s *Sequence) IdentitySpec() *duckv1beta1.IdentitySpec {
+	return &s.Spec.IdentitySpec
+}
+
+// IdentityStatus returns the IdentityStatus portion of the Status.
+func (s *Sequence) IdentityStatus() *duckv1beta1.IdentityStatus {
+	return &s.Status.IdentityStatus
+}
+
+// ConditionSet returns the apis.ConditionSet of the embedding object
+func (s *Sequence) ConditionSet() *apis.ConditionSet {
+	return &sequenceCondSet
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SequenceList is a collection of Pub/Sub backed Sequences.
+type SequenceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Sequence `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for Pub/Sub backed Sequence.
+func (s *Sequence) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("Sequence")
+}
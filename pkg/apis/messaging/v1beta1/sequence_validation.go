@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func (s *Sequence) Validate(ctx context.Context) *apis.FieldError {
+	return s.Spec.Validate(ctx).ViaField("spec")
+}
+
+func (ss *SequenceSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+
+	// Steps [required]
+	if len(ss.Steps) == 0 {
+		errs = errs.Also(apis.ErrMissingField("steps"))
+	}
+	for i, step := range ss.Steps {
+		if err := step.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaFieldIndex("steps", i))
+		}
+	}
+
+	if ss.Reply != nil {
+		if err := ss.Reply.Validate(ctx); err != nil {
+			errs = errs.Also(err.ViaField("reply"))
+		}
+	}
+
+	if err := duckv1beta1.ValidateCredential(ss.Secret, ss.ServiceAccountName); err != nil {
+		errs = errs.Also(err)
+	}
+
+	return errs
+}
+
+func (current *Sequence) CheckImmutableFields(ctx context.Context, original *Sequence) *apis.FieldError {
+	if original == nil {
+		return nil
+	}
+
+	// Modification of ServiceAccountName, Secret and Project are not allowed.
+	// The steps and the reply are mutable.
+	if diff := cmp.Diff(original.Spec.IdentitySpec, current.Spec.IdentitySpec); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "serviceAccountName"},
+			Details: diff,
+		}
+	}
+	if diff := cmp.Diff(original.Spec.Secret, current.Spec.Secret); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "secret"},
+			Details: diff,
+		}
+	}
+	if diff := cmp.Diff(original.Spec.Project, current.Spec.Project); diff != "" {
+		return &apis.FieldError{
+			Message: "Immutable fields changed (-old +new)",
+			Paths:   []string{"spec", "project"},
+			Details: diff,
+		}
+	}
+
+	// Modification of non-empty cluster name annotation is not allowed.
+	return duckv1beta1.CheckImmutableClusterNameAnnotation(&current.ObjectMeta, &original.ObjectMeta, nil)
+}
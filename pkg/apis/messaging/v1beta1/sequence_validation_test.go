@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/webhook/resourcesemantics"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+var (
+	sequenceSpec = SequenceSpec{
+		Steps: []duckv1.Destination{{
+			URI: apis.HTTP("stependpoint"),
+		}},
+		Reply: &duckv1.Destination{
+			URI: apis.HTTP("replyendpoint"),
+		},
+	}
+)
+
+func TestSequenceValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cr   resourcesemantics.GenericCRD
+		want *apis.FieldError
+	}{{
+		name: "empty",
+		cr: &Sequence{
+			Spec: SequenceSpec{},
+		},
+		want: apis.ErrMissingField("spec.steps"),
+	}, {
+		name: "valid steps",
+		cr: &Sequence{
+			Spec: sequenceSpec,
+		},
+		want: nil,
+	}, {
+		name: "empty step at index 1",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []duckv1.Destination{{
+					URI: apis.HTTP("stependpoint"),
+				}, {}},
+			},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "spec.steps[1].ref", "spec.steps[1].uri"),
+	}, {
+		name: "empty reply",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				Steps: []duckv1.Destination{{
+					URI: apis.HTTP("stependpoint"),
+				}},
+				Reply: &duckv1.Destination{},
+			},
+		},
+		want: apis.ErrGeneric("expected at least one, got none", "spec.reply.ref", "spec.reply.uri"),
+	}, {
+		name: "invalid k8s service account",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: invalidServiceAccountName,
+				},
+				Steps: sequenceSpec.Steps,
+			},
+		},
+		want: &apis.FieldError{
+			Message: `invalid value: @test, serviceAccountName should have format: ^[A-Za-z0-9](?:[A-Za-z0-9\-]{0,61}[A-Za-z0-9])?$`,
+			Paths:   []string{"spec.serviceAccountName"},
+		},
+	}, {
+		name: "have k8s service account and secret at the same time",
+		cr: &Sequence{
+			Spec: SequenceSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: validServiceAccountName,
+				},
+				Secret: &gcpauthtesthelper.Secret,
+				Steps:  sequenceSpec.Steps,
+			},
+		},
+		want: &apis.FieldError{
+			Message: "Can't have spec.serviceAccountName and spec.secret at the same time",
+			Paths:   []string{"spec"},
+		},
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.cr.Validate(context.TODO())
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", test.name, diff)
+			}
+		})
+	}
+}
+
+func TestSequenceCheckImmutableFields(t *testing.T) {
+	testCases := map[string]struct {
+		orig    *SequenceSpec
+		updated SequenceSpec
+		allowed bool
+	}{
+		"nil orig": {
+			updated: SequenceSpec{},
+			allowed: true,
+		},
+		"Steps changed": {
+			orig: &sequenceSpec,
+			updated: SequenceSpec{
+				Steps: []duckv1.Destination{{
+					URI: apis.HTTP("otherendpoint"),
+				}},
+				Reply: sequenceSpec.Reply,
+			},
+			allowed: true,
+		},
+		"ServiceAccount changed": {
+			orig: &sequenceSpec,
+			updated: SequenceSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: "new-service-account",
+				},
+				Steps: sequenceSpec.Steps,
+				Reply: sequenceSpec.Reply,
+			},
+			allowed: false,
+		},
+		"Project changed": {
+			orig: &sequenceSpec,
+			updated: SequenceSpec{
+				Project: "new-project",
+				Steps:   sequenceSpec.Steps,
+				Reply:   sequenceSpec.Reply,
+			},
+			allowed: false,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var orig *Sequence
+			if tc.orig != nil {
+				orig = &Sequence{
+					Spec: *tc.orig,
+				}
+			}
+			updated := &Sequence{
+				Spec: tc.updated,
+			}
+			err := updated.CheckImmutableFields(context.TODO(), orig)
+			if tc.allowed != (err == nil) {
+				t.Fatalf("Unexpected immutable field check. Expected %v. Actual %v", tc.allowed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package operator contains the API of the resources configuring the
+// installation of Knative-GCP itself.
+package operator
+
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
+const (
+	GroupName = "operator.events.cloud.google.com"
+)
+
+var (
+	// KnativeGCPsResource represents a KnativeGCP.
+	KnativeGCPsResource = schema.GroupResource{
+		Group:    GroupName,
+		Resource: "knativegcps",
+	}
+)
@@ -0,0 +1,25 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Api versions allow the api contract for a resource to be changed while keeping
+// backward compatibility by supporting multiple concurrent versions
+// of the same resource.
+
+// Package v1alpha1 defines the types in
+// operator.events.cloud.google.com/v1alpha1 configuring the installation.
+// +k8s:deepcopy-gen=package
+// +groupName=operator.events.cloud.google.com
+package v1alpha1
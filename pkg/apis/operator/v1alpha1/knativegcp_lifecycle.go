@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"knative.dev/pkg/apis"
+)
+
+var knativeGCPCondSet = apis.NewLivingConditionSet(
+	KnativeGCPConditionConfigReady,
+	KnativeGCPConditionDeploymentsReady,
+)
+
+const (
+	// KnativeGCPConditionReady has status true when all subconditions below
+	// have been set to True.
+	KnativeGCPConditionReady apis.ConditionType = apis.ConditionReady
+
+	// KnativeGCPConditionConfigReady reports whether the ConfigMaps of the
+	// system namespace hold the configured keys.
+	KnativeGCPConditionConfigReady apis.ConditionType = "ConfigReady"
+
+	// KnativeGCPConditionDeploymentsReady reports whether the controller and
+	// the webhook run with the configured images and features.
+	KnativeGCPConditionDeploymentsReady apis.ConditionType = "DeploymentsReady"
+)
+
+// GetCondition returns the condition currently associated with the given type, or nil.
+func (ks *KnativeGCPStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return knativeGCPCondSet.Manage(ks).GetCondition(t)
+}
+
+// GetTopLevelCondition returns the top level Condition.
+func (ks *KnativeGCPStatus) GetTopLevelCondition() *apis.Condition {
+	return knativeGCPCondSet.Manage(ks).GetTopLevelCondition()
+}
+
+// IsReady returns true if the resource is ready overall.
+func (ks *KnativeGCPStatus) IsReady() bool {
+	return knativeGCPCondSet.Manage(ks).IsHappy()
+}
+
+// InitializeConditions sets relevant unset conditions to Unknown state.
+func (ks *KnativeGCPStatus) InitializeConditions() {
+	knativeGCPCondSet.Manage(ks).InitializeConditions()
+}
+
+// MarkConfigReady marks the ConfigMaps as holding the configured keys.
+func (ks *KnativeGCPStatus) MarkConfigReady() {
+	knativeGCPCondSet.Manage(ks).MarkTrue(KnativeGCPConditionConfigReady)
+}
+
+// MarkConfigFailed marks the ConfigMaps as not holding the configured keys.
+func (ks *KnativeGCPStatus) MarkConfigFailed(reason, format string, args ...interface{}) {
+	knativeGCPCondSet.Manage(ks).MarkFalse(KnativeGCPConditionConfigReady, reason, format, args...)
+}
+
+// MarkDeploymentsReady marks the deployments as rolled out with the
+// configured images and features.
+func (ks *KnativeGCPStatus) MarkDeploymentsReady() {
+	knativeGCPCondSet.Manage(ks).MarkTrue(KnativeGCPConditionDeploymentsReady)
+}
+
+// MarkDeploymentsUnknown marks the deployments as still rolling out.
+func (ks *KnativeGCPStatus) MarkDeploymentsUnknown(reason, format string, args ...interface{}) {
+	knativeGCPCondSet.Manage(ks).MarkUnknown(KnativeGCPConditionDeploymentsReady, reason, format, args...)
+}
+
+// MarkDeploymentsFailed marks the deployments as not updated.
+func (ks *KnativeGCPStatus) MarkDeploymentsFailed(reason, format string, args ...interface{}) {
+	knativeGCPCondSet.Manage(ks).MarkFalse(KnativeGCPConditionDeploymentsReady, reason, format, args...)
+}
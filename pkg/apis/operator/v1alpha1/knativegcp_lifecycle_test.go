@@ -0,0 +1,56 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestKnativeGCPStatus_Lifecycle(t *testing.T) {
+	ks := &KnativeGCPStatus{}
+	ks.InitializeConditions()
+	if got := ks.GetTopLevelCondition().Status; got != corev1.ConditionUnknown {
+		t.Errorf("initial Ready = %v, want %v", got, corev1.ConditionUnknown)
+	}
+
+	ks.MarkConfigReady()
+	ks.MarkDeploymentsUnknown("RollingOut", "rolling out")
+	if got := ks.GetTopLevelCondition().Status; got != corev1.ConditionUnknown {
+		t.Errorf("Ready = %v while rolling out, want %v", got, corev1.ConditionUnknown)
+	}
+
+	ks.MarkDeploymentsReady()
+	if !ks.IsReady() {
+		t.Errorf("IsReady() = false after the deployments rolled out, want true")
+	}
+
+	ks.MarkConfigFailed("ConfigMapUpdateFailed", "failed")
+	if ks.IsReady() {
+		t.Error("IsReady() = true after updating the config failed, want false")
+	}
+	if got := ks.GetCondition(KnativeGCPConditionConfigReady).Reason; got != "ConfigMapUpdateFailed" {
+		t.Errorf("ConfigReady reason = %q, want %q", got, "ConfigMapUpdateFailed")
+	}
+
+	ks.MarkConfigReady()
+	ks.MarkDeploymentsFailed("DeploymentUpdateFailed", "failed")
+	if got := ks.GetTopLevelCondition().Status; got != corev1.ConditionFalse {
+		t.Errorf("Ready = %v after updating the deployments failed, want %v", got, corev1.ConditionFalse)
+	}
+}
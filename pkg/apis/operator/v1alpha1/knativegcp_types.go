@@ -0,0 +1,141 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+// KnativeGCPName is the name of the only KnativeGCP applied to the
+// installation. KnativeGCPs with another name are marked as not ready.
+const KnativeGCPName = "knative-gcp"
+
+// AppliedAnnotation is the annotation of the ConfigMaps and deployments
+// changed by the KnativeGCP recording their original settings, so that they
+// are restored once the KnativeGCP no longer sets them.
+const AppliedAnnotation = "operator.events.cloud.google.com/applied"
+
+// +genclient
+// +genclient:nonNamespaced
+// +genreconciler
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KnativeGCP declares the images, configs and feature flags of the whole
+// installation. The controller applies it to the deployments and ConfigMaps
+// of the system namespace, so that upgrading or reconfiguring the installation
+// only takes changing a single resource.
+type KnativeGCP struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired state of the installation.
+	Spec KnativeGCPSpec `json:"spec,omitempty"`
+
+	// Status represents the current state of the installation. This data may
+	// be out of date.
+	// +optional
+	Status KnativeGCPStatus `json:"status,omitempty"`
+}
+
+var (
+	// Check that KnativeGCP can be validated and can be defaulted.
+	_ apis.Validatable = (*KnativeGCP)(nil)
+	_ apis.Defaultable = (*KnativeGCP)(nil)
+
+	// Check that KnativeGCP can return its spec untyped.
+	_ apis.HasSpec = (*KnativeGCP)(nil)
+
+	_ runtime.Object = (*KnativeGCP)(nil)
+
+	// Check that we can create OwnerReferences to a KnativeGCP.
+	_ kmeta.OwnerRefable = (*KnativeGCP)(nil)
+)
+
+// KnativeGCPSpec defines the desired state of the installation.
+type KnativeGCPSpec struct {
+	// Images overrides the images of the components. The components whose
+	// image is not set keep the image they were installed with.
+	// +optional
+	Images ImagesSpec `json:"images,omitempty"`
+
+	// Config holds the keys to set in the ConfigMaps of the system namespace,
+	// by ConfigMap name, e.g. config-logging or config-observability. Keys
+	// that are not listed are left as they are.
+	// +optional
+	Config map[string]map[string]string `json:"config,omitempty"`
+
+	// Features holds the values of the environment variables toggling the
+	// features of the controller and the webhook, e.g. DRY_RUN or
+	// PULLSUBSCRIPTION_TOPIC_CHECK.
+	// +optional
+	Features map[string]string `json:"features,omitempty"`
+}
+
+// ImagesSpec defines the images of the components.
+type ImagesSpec struct {
+	// Controller is the image of the controller.
+	// +optional
+	Controller string `json:"controller,omitempty"`
+
+	// Webhook is the image of the webhook.
+	// +optional
+	Webhook string `json:"webhook,omitempty"`
+
+	// DataPlane is the image of the receive adapters, the webhook receivers
+	// and the BrokerCell components.
+	// +optional
+	DataPlane string `json:"dataPlane,omitempty"`
+
+	// Publisher is the image of the Topic publishers.
+	// +optional
+	Publisher string `json:"publisher,omitempty"`
+}
+
+// KnativeGCPStatus represents the current state of the installation.
+type KnativeGCPStatus struct {
+	// inherits duck/v1 Status, which currently provides:
+	// * ObservedGeneration - the 'Generation' of the Service that was last processed by the controller.
+	// * Conditions - the latest available observations of a resource's current state.
+	duckv1.Status `json:",inline"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// KnativeGCPList is a collection of KnativeGCPs.
+type KnativeGCPList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KnativeGCP `json:"items"`
+}
+
+// GetGroupVersionKind returns GroupVersionKind for KnativeGCPs.
+func (k *KnativeGCP) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("KnativeGCP")
+}
+
+// GetUntypedSpec returns the spec of the KnativeGCP.
+func (k *KnativeGCP) GetUntypedSpec() interface{} {
+	return k.Spec
+}
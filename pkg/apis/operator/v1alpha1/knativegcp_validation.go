@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/apis"
+)
+
+var (
+	// ConfigMapNames are the ConfigMaps of the system namespace a KnativeGCP
+	// can set keys of.
+	ConfigMapNames = sets.NewString(
+		"config-br-default-channel",
+		"config-gcp-auth",
+		"config-leader-election",
+		"config-logging",
+		"config-observability",
+		"config-tracing",
+	)
+
+	// ControllerFeatures are the environment variables of the controller a
+	// KnativeGCP can set.
+	ControllerFeatures = sets.NewString(
+		"BROKER_CELL_RETRY_BACKLOG_PER_REPLICA",
+		"CLUSTER_NAME",
+		"DATA_PLANE_HTTP_PROXY",
+		"DATA_PLANE_HTTPS_PROXY",
+		"DATA_PLANE_NO_PROXY",
+		"DATA_PLANE_NODE_ARCHITECTURES",
+		"DATA_PLANE_PREFERRED_NODE_ARCHITECTURE",
+		"DRY_RUN",
+		"FINALIZE_TIMEOUT",
+		"LIFECYCLE_EVENTS_SINK",
+		"METRICS_LABEL_ANNOTATIONS",
+		"PROPAGATE_ANNOTATIONS_DENY",
+		"PROPAGATE_LABELS_DENY",
+		"PUBSUB_SUBSCRIPTION_PREFIX",
+		"PUBSUB_SUBSCRIPTION_SUFFIX",
+	)
+
+	// WebhookFeatures are the environment variables of the webhook a
+	// KnativeGCP can set.
+	WebhookFeatures = sets.NewString(
+		"CLUSTER_NAME",
+		"PULLSUBSCRIPTION_TOPIC_CHECK",
+		"SOURCE_CHILD_DRY_RUN",
+	)
+)
+
+// Validate verifies that the KnativeGCP is valid.
+func (k *KnativeGCP) Validate(ctx context.Context) *apis.FieldError {
+	return k.Spec.Validate(ctx).ViaField("spec")
+}
+
+// Validate verifies that the KnativeGCPSpec is valid.
+func (ks *KnativeGCPSpec) Validate(ctx context.Context) *apis.FieldError {
+	var errs *apis.FieldError
+	for name, data := range ks.Config {
+		if !ConfigMapNames.Has(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "config", fmt.Sprintf("supported ConfigMaps are %v", ConfigMapNames.List())))
+			continue
+		}
+		for key := range data {
+			if key == "" {
+				errs = errs.Also(apis.ErrInvalidKeyName(key, apis.CurrentField).ViaFieldKey("config", name))
+			}
+		}
+	}
+	for name := range ks.Features {
+		if !ControllerFeatures.Has(name) && !WebhookFeatures.Has(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "features", fmt.Sprintf("supported features are %v", ControllerFeatures.Union(WebhookFeatures).List())))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+)
+
+func TestKnativeGCP_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		spec    KnativeGCPSpec
+		wantErr bool
+	}{
+		"empty": {},
+		"valid": {
+			spec: KnativeGCPSpec{
+				Images: ImagesSpec{
+					Controller: "gcr.io/knative-gcp/controller:v0.18.0",
+					DataPlane:  "gcr.io/knative-gcp/dataplane:v0.18.0",
+				},
+				Config: map[string]map[string]string{
+					"config-logging": {"loglevel.controller": "debug"},
+				},
+				Features: map[string]string{
+					"DRY_RUN":                      "true",
+					"PULLSUBSCRIPTION_TOPIC_CHECK": "true",
+				},
+			},
+		},
+		"unsupported ConfigMap": {
+			spec: KnativeGCPSpec{
+				Config: map[string]map[string]string{
+					"config-unknown": {"key": "value"},
+				},
+			},
+			wantErr: true,
+		},
+		"empty key": {
+			spec: KnativeGCPSpec{
+				Config: map[string]map[string]string{
+					"config-logging": {"": "value"},
+				},
+			},
+			wantErr: true,
+		},
+		"unsupported feature": {
+			spec: KnativeGCPSpec{
+				Features: map[string]string{
+					"GOOGLE_APPLICATION_CREDENTIALS": "/tmp/key.json",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			k := KnativeGCP{Spec: tc.spec}
+			if err := k.Validate(context.TODO()); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
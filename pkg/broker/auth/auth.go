@@ -0,0 +1,188 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package auth authenticates the requests sent to brokers with bearer tokens,
+// against the token hashes in the ingress auth secret mounted in the ingress
+// pods. The targets config only records the key of the entry holding the
+// hashes of each broker, and the secret only holds hashes, so that the tokens
+// themselves never leave the secrets of the brokers' namespaces.
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDir is the directory the ingress auth secret is mounted at.
+	DefaultDir = "/var/run/cloud-run-events/ingress-auth"
+
+	// refreshPeriod is how long the hashes read from an entry are used
+	// before the entry is read again. The kubelet only refreshes mounted
+	// secrets every minute or so, so there is no point in reading them more
+	// often.
+	refreshPeriod = 30 * time.Second
+
+	bearerPrefix = "Bearer "
+)
+
+var (
+	// ErrNoToken is returned for requests without a bearer token.
+	ErrNoToken = errors.New("the request has no bearer token")
+	// ErrInvalidToken is returned for requests whose bearer token is not
+	// accepted.
+	ErrInvalidToken = errors.New("the bearer token of the request is not accepted")
+)
+
+// Hash returns the hash of a token as stored in the ingress auth secret.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseTokens returns the tokens listed in data, one per line. Surrounding
+// spaces and empty lines are ignored.
+func ParseTokens(data []byte) []string {
+	var tokens []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if t := strings.TrimSpace(scanner.Text()); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// Tokens authenticates requests with the token hashes of each broker, read
+// from the files of a mounted secret. Each file holds the hex encoded hashes
+// of the accepted tokens, one per line.
+type Tokens struct {
+	dir string
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	// mu guards entries. Each entry has its own lock, so that reading the
+	// file of a key doesn't hold up the requests for the other keys.
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the hashes read from a file, or the error reading it, along with
+// when to read it again. Errors are kept as long as hashes, so that requests
+// for a missing or malformed entry don't read the file each time.
+type entry struct {
+	mu      sync.Mutex
+	hashes  [][]byte
+	err     error
+	expires time.Time
+}
+
+// NewTokens creates a Tokens reading the token hashes from the files in dir.
+func NewTokens(dir string) *Tokens {
+	return &Tokens{
+		dir:     dir,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Authenticate returns nil if the bearer token of the request is one of the
+// tokens whose hashes are stored under key. It returns an error if there is
+// no such entry, e.g. because the secret has not been refreshed yet, so that
+// requests are not accepted without a valid token.
+func (t *Tokens) Authenticate(key string, r *http.Request) error {
+	token, ok := bearerToken(r)
+	if !ok {
+		return ErrNoToken
+	}
+	hashes, err := t.get(key)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(token))
+	var match int
+	for _, h := range hashes {
+		// Compare with every hash in constant time so that the timing
+		// tells nothing about the accepted tokens.
+		match |= subtle.ConstantTimeCompare(sum[:], h)
+	}
+	if match == 0 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// get returns the hashes stored under key.
+func (t *Tokens) get(key string) ([][]byte, error) {
+	t.mu.Lock()
+	e, ok := t.entries[key]
+	if !ok {
+		e = &entry{}
+		t.entries[key] = e
+	}
+	t.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if now := t.now(); !now.Before(e.expires) {
+		e.hashes, e.err = t.read(key)
+		e.expires = now.Add(refreshPeriod)
+	}
+	return e.hashes, e.err
+}
+
+// read reads the hashes stored under key.
+func (t *Tokens) read(key string) ([][]byte, error) {
+	// Keys are plain file names, never paths.
+	if key != filepath.Base(key) {
+		return nil, fmt.Errorf("invalid ingress auth key %q", key)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(t.dir, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ingress auth tokens %q: %w", key, err)
+	}
+	var hashes [][]byte
+	for _, line := range ParseTokens(b) {
+		h, err := hex.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ingress auth tokens %q: %w", key, err)
+		}
+		hashes = append(hashes, h)
+	}
+	return hashes, nil
+}
+
+// bearerToken returns the bearer token of the Authorization header of the
+// request.
+func bearerToken(r *http.Request) (string, bool) {
+	h := r.Header.Get("Authorization")
+	if len(h) < len(bearerPrefix) || !strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	token := strings.TrimSpace(h[len(bearerPrefix):])
+	return token, token != ""
+}
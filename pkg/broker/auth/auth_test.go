@@ -0,0 +1,189 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeEntry(t *testing.T, dir, key, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, key), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", key, err)
+	}
+}
+
+func newRequest(authorization string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/ns/broker", nil)
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	return r
+}
+
+func TestParseTokens(t *testing.T) {
+	got := ParseTokens([]byte("token-1\n\n  token-2 \r\ntoken-3"))
+	if diff := cmp.Diff([]string{"token-1", "token-2", "token-3"}, got); diff != "" {
+		t.Errorf("unexpected tokens (-want, +got) = %v", diff)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeEntry(t, dir, "broker", Hash("token-1")+"\n"+Hash("token-2")+"\n")
+	writeEntry(t, dir, "empty", "")
+	writeEntry(t, dir, "malformed", "not hex")
+
+	testCases := map[string]struct {
+		key           string
+		authorization string
+		wantErr       error
+		wantAnyErr    bool
+	}{
+		"first token": {
+			key:           "broker",
+			authorization: "Bearer token-1",
+		},
+		"second token": {
+			key:           "broker",
+			authorization: "bearer token-2",
+		},
+		"no header": {
+			key:     "broker",
+			wantErr: ErrNoToken,
+		},
+		"not bearer": {
+			key:           "broker",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantErr:       ErrNoToken,
+		},
+		"empty token": {
+			key:           "broker",
+			authorization: "Bearer ",
+			wantErr:       ErrNoToken,
+		},
+		"invalid token": {
+			key:           "broker",
+			authorization: "Bearer token-3",
+			wantErr:       ErrInvalidToken,
+		},
+		"no tokens": {
+			key:           "empty",
+			authorization: "Bearer token-1",
+			wantErr:       ErrInvalidToken,
+		},
+		"missing entry": {
+			key:           "missing",
+			authorization: "Bearer token-1",
+			wantAnyErr:    true,
+		},
+		"malformed entry": {
+			key:           "malformed",
+			authorization: "Bearer token-1",
+			wantAnyErr:    true,
+		},
+		"path": {
+			key:           "../broker",
+			authorization: "Bearer token-1",
+			wantAnyErr:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			err := NewTokens(dir).Authenticate(tc.key, newRequest(tc.authorization))
+			switch {
+			case tc.wantAnyErr:
+				if err == nil {
+					t.Error("Authenticate() = nil, want an error")
+				}
+			case !errors.Is(err, tc.wantErr):
+				t.Errorf("Authenticate() = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRefreshes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeEntry(t, dir, "broker", Hash("old"))
+
+	tokens := NewTokens(dir)
+	now := time.Now()
+	tokens.now = func() time.Time { return now }
+	authenticate := func(token string) error {
+		return tokens.Authenticate("broker", newRequest("Bearer "+token))
+	}
+
+	if err := authenticate("old"); err != nil {
+		t.Errorf("Authenticate(old) = %v", err)
+	}
+	writeEntry(t, dir, "broker", Hash("new"))
+	if err := authenticate("old"); err != nil {
+		t.Errorf("Authenticate(old) before the entry expired = %v", err)
+	}
+	now = now.Add(refreshPeriod)
+	if err := authenticate("old"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Authenticate(old) after the entry expired = %v, want %v", err, ErrInvalidToken)
+	}
+	if err := authenticate("new"); err != nil {
+		t.Errorf("Authenticate(new) after the entry expired = %v", err)
+	}
+}
+
+func TestAuthenticateCachesMissingEntries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tokens := NewTokens(dir)
+	now := time.Now()
+	tokens.now = func() time.Time { return now }
+	authenticate := func() error {
+		return tokens.Authenticate("broker", newRequest("Bearer token"))
+	}
+
+	if err := authenticate(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Authenticate() without an entry = %v, want %v", err, os.ErrNotExist)
+	}
+	writeEntry(t, dir, "broker", Hash("token"))
+	if err := authenticate(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Authenticate() before the missing entry expired = %v, want %v", err, os.ErrNotExist)
+	}
+	now = now.Add(refreshPeriod)
+	if err := authenticate(); err != nil {
+		t.Errorf("Authenticate() after the missing entry expired = %v", err)
+	}
+}
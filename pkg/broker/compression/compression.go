@@ -0,0 +1,203 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package compression compresses the data of the events published to the
+// decouple queue of a broker, to reduce the Pub/Sub throughput they use.
+package compression
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+)
+
+const (
+	// ContentEncodingExtension is the CloudEvents extension holding the
+	// encoding the data of a compressed event was compressed with. It is
+	// internal to the broker, so the ingress deletes it from the events it
+	// receives.
+	ContentEncodingExtension = "kgcpcontentencoding"
+
+	// Gzip compresses event data with gzip.
+	Gzip = "gzip"
+	// Zstd compresses event data with Zstandard.
+	Zstd = "zstd"
+
+	// maxDecompressedBytes bounds the size of decompressed event data, so
+	// that a corrupted event cannot exhaust the memory of the fanout. It is
+	// well above the maximum size of a Pub/Sub message, which compressed
+	// data must fit in.
+	maxDecompressedBytes = 100 << 20
+)
+
+// ErrCorrupted is returned by Decompress when the data of an event cannot be
+// decompressed.
+var ErrCorrupted = errors.New("compressed event is corrupted")
+
+var (
+	// zstdDecoder decompresses zstd data. It is safe for concurrent use with
+	// DecodeAll, and created on first use.
+	zstdDecoder     *zstd.Decoder
+	zstdDecoderErr  error
+	zstdDecoderOnce sync.Once
+)
+
+// Compressor compresses the data of events with a content encoding.
+type Compressor struct {
+	encoding string
+	minBytes int
+
+	// gzipWriters holds the gzip writers that aren't in use.
+	gzipWriters sync.Pool
+	zstdEncoder *zstd.Encoder
+}
+
+// NewCompressor creates a Compressor compressing event data of at least
+// minBytes with the encoding, which is either Gzip or Zstd. It returns nil if
+// the encoding is empty, which disables compression.
+func NewCompressor(encoding string, minBytes int) (*Compressor, error) {
+	c := &Compressor{encoding: encoding, minBytes: minBytes}
+	switch encoding {
+	case "":
+		return nil, nil
+	case Gzip:
+		c.gzipWriters.New = func() interface{} {
+			return gzip.NewWriter(nil)
+		}
+	case Zstd:
+		enc, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, err
+		}
+		c.zstdEncoder = enc
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q, must be %q or %q", encoding, Gzip, Zstd)
+	}
+	return c, nil
+}
+
+// Compress compresses the data of the event in place, and records the
+// encoding in the event extensions. Events with data smaller than the
+// compressor's minimum, encrypted data, which doesn't compress, or data that
+// doesn't get smaller are left as they are.
+func (c *Compressor) Compress(event *cev2.Event) error {
+	data := event.Data()
+	if len(data) == 0 || len(data) < c.minBytes {
+		return nil
+	}
+	ext := event.Extensions()
+	if _, ok := ext[ContentEncodingExtension]; ok {
+		return nil
+	}
+	if _, ok := ext[encryption.DataKeyExtension]; ok {
+		return nil
+	}
+
+	var compressed []byte
+	switch c.encoding {
+	case Gzip:
+		var buf bytes.Buffer
+		w := c.gzipWriters.Get().(*gzip.Writer)
+		defer c.gzipWriters.Put(w)
+		w.Reset(&buf)
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		compressed = buf.Bytes()
+	case Zstd:
+		compressed = c.zstdEncoder.EncodeAll(data, make([]byte, 0, len(data)))
+	}
+	if len(compressed) >= len(data) {
+		return nil
+	}
+
+	event.SetExtension(ContentEncodingExtension, c.encoding)
+	event.DataEncoded = compressed
+	event.DataBase64 = true
+	return nil
+}
+
+// Decompress decompresses the data of the event in place if it was
+// compressed, and removes the extension recorded by Compress. Events that
+// were not compressed are left as they are. It returns an error wrapping
+// ErrCorrupted if the data cannot be decompressed.
+func Decompress(event *cev2.Event) error {
+	v, ok := event.Extensions()[ContentEncodingExtension]
+	if !ok {
+		return nil
+	}
+	encoding, err := cetypes.ToString(v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	var data []byte
+	switch encoding {
+	case Gzip:
+		data, err = gunzip(event.Data())
+	case Zstd:
+		data, err = unzstd(event.Data())
+	default:
+		err = fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	event.SetExtension(ContentEncodingExtension, nil)
+	event.DataEncoded = data
+	event.DataBase64 = false
+	return nil
+}
+
+func gunzip(compressed []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxDecompressedBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDecompressedBytes {
+		return nil, fmt.Errorf("data exceeds %d bytes once decompressed", maxDecompressedBytes)
+	}
+	return data, nil
+}
+
+func unzstd(compressed []byte) ([]byte, error) {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, zstdDecoderErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(maxDecompressedBytes))
+	})
+	if zstdDecoderErr != nil {
+		return nil, zstdDecoderErr
+	}
+	return zstdDecoder.DecodeAll(compressed, nil)
+}
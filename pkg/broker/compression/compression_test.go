@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package compression
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+)
+
+func newTestEvent(data string) cev2.Event {
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	if err := e.SetData(cev2.ApplicationJSON, []byte(data)); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+var largeData = `{"items": [` + strings.Repeat(`{"hello": "world"},`, 100) + `{}]}`
+
+func TestCompressDecompress(t *testing.T) {
+	for _, encoding := range []string{Gzip, Zstd} {
+		t.Run(encoding, func(t *testing.T) {
+			c, err := NewCompressor(encoding, 0)
+			if err != nil {
+				t.Fatalf("NewCompressor() = %v", err)
+			}
+			want := newTestEvent(largeData)
+			event := newTestEvent(largeData)
+			if err := c.Compress(&event); err != nil {
+				t.Fatalf("Compress() = %v", err)
+			}
+			if len(event.Data()) >= len(want.Data()) {
+				t.Errorf("Compress() didn't shrink the data: got %d bytes, want less than %d", len(event.Data()), len(want.Data()))
+			}
+			if got := event.Extensions()[ContentEncodingExtension]; got != encoding {
+				t.Errorf("extension %s = %v, want %q", ContentEncodingExtension, got, encoding)
+			}
+
+			if err := Decompress(&event); err != nil {
+				t.Fatalf("Decompress() = %v", err)
+			}
+			event.DataBase64 = want.DataBase64
+			if diff := cmp.Diff(want, event); diff != "" {
+				t.Errorf("unexpected decompressed event (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestCompressSkipped(t *testing.T) {
+	testCases := map[string]struct {
+		minBytes int
+		event    func() cev2.Event
+	}{
+		"small data": {
+			minBytes: len(largeData) + 1,
+			event: func() cev2.Event {
+				return newTestEvent(largeData)
+			},
+		},
+		"incompressible data": {
+			event: func() cev2.Event {
+				return newTestEvent(`"a"`)
+			},
+		},
+		"encrypted data": {
+			event: func() cev2.Event {
+				e := newTestEvent(largeData)
+				e.SetExtension(encryption.DataKeyExtension, "key")
+				return e
+			},
+		},
+		"no data": {
+			event: func() cev2.Event {
+				e := cev2.NewEvent()
+				e.SetID("id")
+				e.SetSource("source")
+				e.SetType("type")
+				return e
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			c, err := NewCompressor(Gzip, tc.minBytes)
+			if err != nil {
+				t.Fatalf("NewCompressor() = %v", err)
+			}
+			want := tc.event()
+			event := tc.event()
+			if err := c.Compress(&event); err != nil {
+				t.Fatalf("Compress() = %v", err)
+			}
+			if diff := cmp.Diff(want, event); diff != "" {
+				t.Errorf("unexpected event (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewCompressor(t *testing.T) {
+	if c, err := NewCompressor("", 0); c != nil || err != nil {
+		t.Errorf(`NewCompressor("") = %v, %v, want nil, nil`, c, err)
+	}
+	if _, err := NewCompressor("br", 0); err == nil {
+		t.Error(`NewCompressor("br") = nil error, want error`)
+	}
+}
+
+func TestDecompressCorrupted(t *testing.T) {
+	for _, encoding := range []string{Gzip, Zstd, "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			event := newTestEvent(largeData)
+			event.SetExtension(ContentEncodingExtension, encoding)
+			err := Decompress(&event)
+			if !errors.Is(err, ErrCorrupted) {
+				t.Errorf("Decompress() = %v, want error wrapping ErrCorrupted", err)
+			}
+		})
+	}
+}
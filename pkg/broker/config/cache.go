@@ -30,15 +30,47 @@ type CachedTargets struct {
 
 var _ ReadonlyTargets = (*CachedTargets)(nil)
 
+// targetsSnapshot is an immutable view of a stored TargetsConfig. It indexes
+// the targets up front so that looking up a target for an event doesn't need
+// to split its key or go through its broker.
+type targetsSnapshot struct {
+	config *TargetsConfig
+	// targets maps trigger keys to targets.
+	targets map[string]*Target
+}
+
+func newTargetsSnapshot(t *TargetsConfig) *targetsSnapshot {
+	s := &targetsSnapshot{config: t}
+	if t == nil {
+		return s
+	}
+	s.targets = make(map[string]*Target)
+	for bk, b := range t.Brokers {
+		for name, target := range b.Targets {
+			s.targets[bk+"/"+name] = target
+		}
+	}
+	return s
+}
+
 // Store atomically stores a TargetsConfig.
+// The TargetsConfig must not be modified once it is stored.
 func (ct *CachedTargets) Store(t *TargetsConfig) {
-	ct.Value.Store(t)
+	ct.Value.Store(newTargetsSnapshot(t))
 }
 
 // Load atomically loads a stored TargetsConfig.
 // If there was no TargetsConfig stored, nil will be returned.
 func (ct *CachedTargets) Load() *TargetsConfig {
-	return ct.Value.Load().(*TargetsConfig)
+	if s := ct.snapshot(); s != nil {
+		return s.config
+	}
+	return nil
+}
+
+func (ct *CachedTargets) snapshot() *targetsSnapshot {
+	s, _ := ct.Value.Load().(*targetsSnapshot)
+	return s
 }
 
 // RangeAllTargets ranges over all targets.
@@ -71,8 +103,12 @@ func (ct *CachedTargets) GetTarget(namespace, brokerName, targetName string) (*T
 // GetTargetByKey returns a target by its trigger key. The format of trigger key is namespace/brokerName/targetName.
 // Do not modify the returned Target copy.
 func (ct *CachedTargets) GetTargetByKey(key string) (*Target, bool) {
-	namespace, brokerName, targetName := SplitTriggerKey(key)
-	return ct.GetTarget(namespace, brokerName, targetName)
+	s := ct.snapshot()
+	if s == nil {
+		return nil, false
+	}
+	t, ok := s.targets[key]
+	return t, ok
 }
 
 // GetBroker returns a broker and its targets if it exists.
@@ -477,4 +477,24 @@ func TestGetBrokerOrTarget(t *testing.T) {
 			t.Errorf("GetTargetByKey (-want,+got): %v", diff)
 		}
 	})
+
+	t.Run("get target by key after store", func(t *testing.T) {
+		targets := &CachedTargets{}
+		if _, ok := targets.GetTargetByKey(t1.Key()); ok {
+			t.Error("GetTargetByKey before store got ok=true, want=false")
+		}
+		targets.Store(val)
+		targets.Store(&TargetsConfig{
+			Brokers: map[string]*Broker{
+				"ns2/broker2": b2,
+			},
+		})
+		if _, ok := targets.GetTargetByKey(t1.Key()); ok {
+			t.Error("GetTargetByKey of removed target got ok=true, want=false")
+		}
+		gotTargets, _ := targets.GetTargetByKey(t3.Key())
+		if diff := cmp.Diff(t3, gotTargets, protocmp.Transform()); diff != "" {
+			t.Errorf("GetTargetByKey (-want,+got): %v", diff)
+		}
+	})
 }
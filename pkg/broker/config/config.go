@@ -17,7 +17,6 @@ limitations under the License.
 package config
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -61,8 +60,38 @@ type BrokerMutation interface {
 	SetAddress(address string) BrokerMutation
 	// SetDecoupleQueue sets the broker decouple queue.
 	SetDecoupleQueue(q *Queue) BrokerMutation
+	// SetPriorityDecoupleQueue sets the broker decouple queue of high
+	// priority events. Nil removes it.
+	SetPriorityDecoupleQueue(q *Queue) BrokerMutation
 	// SetState sets the broker state.
 	SetState(s State) BrokerMutation
+	// SetMetricLabels sets the labels attached to the broker metrics.
+	SetMetricLabels(labels map[string]string) BrokerMutation
+	// SetBrokerCell sets the name of the BrokerCell serving the broker.
+	SetBrokerCell(name string) BrokerMutation
+	// SetGeneration sets the generation of the broker.
+	SetGeneration(generation int64) BrokerMutation
+	// SetEncryptionKey sets the name of the Cloud KMS key wrapping the keys
+	// of the broker's event data.
+	SetEncryptionKey(name string) BrokerMutation
+	// SetPreviousEncryptionKeys sets the names of the Cloud KMS keys the
+	// broker's event data was encrypted with before its encryption key.
+	SetPreviousEncryptionKeys(names []string) BrokerMutation
+	// SetEventTypePolicy sets the event types the broker accepts and
+	// rejects.
+	SetEventTypePolicy(allowed, denied []string) BrokerMutation
+	// SetEventSourcePolicy sets the event sources the broker accepts and
+	// rejects.
+	SetEventSourcePolicy(allowed, denied []string) BrokerMutation
+	// SetIngressAuthKey sets the key of the entry holding the hashes of the
+	// bearer tokens the ingress accepts for the broker.
+	SetIngressAuthKey(key string) BrokerMutation
+	// SetReplyHeadersKey sets the key of the entry holding the headers set
+	// on the replies sent back to the broker.
+	SetReplyHeadersKey(key string) BrokerMutation
+	// SetStructuredEncoding sets whether the ingress publishes the events of
+	// the broker in the CloudEvents structured mode.
+	SetStructuredEncoding(structured bool) BrokerMutation
 	// UpsertTargets upserts Targets to the broker.
 	// The targets' namespace and broker will be forced to be
 	// the same as the broker's namespace and name.
@@ -88,7 +117,7 @@ func BrokerKey(namespace, name string) string {
 
 // TriggerKey returns the key of a trigger. Format is namespace/brokerName/targetName.
 func TriggerKey(namespace, broker, target string) string {
-	return fmt.Sprintf("%s/%s/%s", namespace, broker, target)
+	return namespace + "/" + broker + "/" + target
 }
 
 // SplitTriggerKey splits a trigger key into namespace, brokerName, targetName.
@@ -106,3 +135,45 @@ func (t *Target) Key() string {
 func (b *Broker) Key() string {
 	return BrokerKey(b.Namespace, b.Name)
 }
+
+// ServedBy returns true if the broker is handled by the data plane of the
+// given BrokerCell. Brokers without a BrokerCell, and data planes that don't
+// know their BrokerCell, handle every broker.
+func (b *Broker) ServedBy(brokerCell string) bool {
+	return brokerCell == "" || b.BrokerCell == "" || b.BrokerCell == brokerCell
+}
+
+// Accepts returns true if the event policy of the broker lets events of the
+// given type and source in.
+func (b *Broker) Accepts(eventType, source string) bool {
+	return policyAllows(b.AllowedEventTypes, b.DeniedEventTypes, eventType) &&
+		policyAllows(b.AllowedEventSources, b.DeniedEventSources, source)
+}
+
+// policyAllows returns true if the value matches no denied entry, and matches
+// an allowed entry or there are none.
+func policyAllows(allowed, denied []string, v string) bool {
+	for _, d := range denied {
+		if policyMatches(d, v) {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, a := range allowed {
+		if policyMatches(a, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyMatches returns true if the value is the entry, or starts with the
+// entry without its trailing "*".
+func policyMatches(entry, v string) bool {
+	if strings.HasSuffix(entry, "*") {
+		return strings.HasPrefix(v, strings.TrimSuffix(entry, "*"))
+	}
+	return entry == v
+}
@@ -35,3 +35,96 @@ func TestTriggerKeyAndSplitTriggerKey(t *testing.T) {
 		t.Errorf("unexpected readiness: want %v, got %v", want, got)
 	}
 }
+
+func TestBrokerServedBy(t *testing.T) {
+	tests := []struct {
+		name       string
+		brokerCell string
+		servedBy   string
+		want       bool
+	}{{
+		name:       "same brokercell",
+		brokerCell: "cell",
+		servedBy:   "cell",
+		want:       true,
+	}, {
+		name:       "other brokercell",
+		brokerCell: "cell",
+		servedBy:   "other-cell",
+		want:       false,
+	}, {
+		name:     "broker without brokercell",
+		servedBy: "cell",
+		want:     true,
+	}, {
+		name:       "data plane without brokercell",
+		brokerCell: "cell",
+		want:       true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Broker{BrokerCell: tc.brokerCell}
+			if got := b.ServedBy(tc.servedBy); got != tc.want {
+				t.Errorf("ServedBy(%q) = %v, want %v", tc.servedBy, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBrokerAccepts(t *testing.T) {
+	tests := []struct {
+		name      string
+		b         *Broker
+		eventType string
+		source    string
+		want      bool
+	}{{
+		name:      "no policy",
+		b:         &Broker{},
+		eventType: "com.example.order",
+		source:    "//shop",
+		want:      true,
+	}, {
+		name:      "allowed type",
+		b:         &Broker{AllowedEventTypes: []string{"com.example.order", "com.example.user"}},
+		eventType: "com.example.order",
+		source:    "//shop",
+		want:      true,
+	}, {
+		name:      "type not allowed",
+		b:         &Broker{AllowedEventTypes: []string{"com.example.user"}},
+		eventType: "com.example.order",
+		source:    "//shop",
+	}, {
+		name:      "allowed type prefix",
+		b:         &Broker{AllowedEventTypes: []string{"com.example.*"}},
+		eventType: "com.example.order",
+		source:    "//shop",
+		want:      true,
+	}, {
+		name: "denied type prefix wins over allowed type",
+		b: &Broker{
+			AllowedEventTypes: []string{"com.example.*"},
+			DeniedEventTypes:  []string{"com.example.internal.*"},
+		},
+		eventType: "com.example.internal.audit",
+		source:    "//shop",
+	}, {
+		name:      "denied source",
+		b:         &Broker{DeniedEventSources: []string{"//test"}},
+		eventType: "com.example.order",
+		source:    "//test",
+	}, {
+		name:      "source not allowed",
+		b:         &Broker{AllowedEventTypes: []string{"com.example.order"}, AllowedEventSources: []string{"//shop/*"}},
+		eventType: "com.example.order",
+		source:    "//test",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.b.Accepts(tc.eventType, tc.source); got != tc.want {
+				t.Errorf("Accepts(%q, %q) = %v, want %v", tc.eventType, tc.source, got, tc.want)
+			}
+		})
+	}
+}
@@ -46,12 +46,80 @@ func (m *brokerMutation) SetDecoupleQueue(q *config.Queue) config.BrokerMutation
 	return m
 }
 
+func (m *brokerMutation) SetPriorityDecoupleQueue(q *config.Queue) config.BrokerMutation {
+	m.delete = false
+	m.b.PriorityDecoupleQueue = q
+	return m
+}
+
 func (m *brokerMutation) SetState(s config.State) config.BrokerMutation {
 	m.delete = false
 	m.b.State = s
 	return m
 }
 
+func (m *brokerMutation) SetMetricLabels(labels map[string]string) config.BrokerMutation {
+	m.delete = false
+	m.b.MetricLabels = labels
+	return m
+}
+
+func (m *brokerMutation) SetBrokerCell(name string) config.BrokerMutation {
+	m.delete = false
+	m.b.BrokerCell = name
+	return m
+}
+
+func (m *brokerMutation) SetGeneration(generation int64) config.BrokerMutation {
+	m.delete = false
+	m.b.Generation = generation
+	return m
+}
+
+func (m *brokerMutation) SetEncryptionKey(name string) config.BrokerMutation {
+	m.delete = false
+	m.b.EncryptionKey = name
+	return m
+}
+
+func (m *brokerMutation) SetPreviousEncryptionKeys(names []string) config.BrokerMutation {
+	m.delete = false
+	m.b.PreviousEncryptionKeys = names
+	return m
+}
+
+func (m *brokerMutation) SetEventTypePolicy(allowed, denied []string) config.BrokerMutation {
+	m.delete = false
+	m.b.AllowedEventTypes = allowed
+	m.b.DeniedEventTypes = denied
+	return m
+}
+
+func (m *brokerMutation) SetEventSourcePolicy(allowed, denied []string) config.BrokerMutation {
+	m.delete = false
+	m.b.AllowedEventSources = allowed
+	m.b.DeniedEventSources = denied
+	return m
+}
+
+func (m *brokerMutation) SetIngressAuthKey(key string) config.BrokerMutation {
+	m.delete = false
+	m.b.IngressAuthKey = key
+	return m
+}
+
+func (m *brokerMutation) SetReplyHeadersKey(key string) config.BrokerMutation {
+	m.delete = false
+	m.b.ReplyHeadersKey = key
+	return m
+}
+
+func (m *brokerMutation) SetStructuredEncoding(structured bool) config.BrokerMutation {
+	m.delete = false
+	m.b.StructuredEncoding = structured
+	return m
+}
+
 func (m *brokerMutation) UpsertTargets(targets ...*config.Target) config.BrokerMutation {
 	m.delete = false
 	if m.b.Targets == nil {
@@ -93,6 +93,128 @@ func TestMutateBroker(t *testing.T) {
 		assertBroker(t, wantBroker, "ns", "broker", targets)
 	})
 
+	t.Run("update broker metric labels", func(t *testing.T) {
+		wantBroker.MetricLabels = map[string]string{"team": "eventing"}
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetMetricLabels(map[string]string{"team": "eventing"})
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.MetricLabels = nil
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetMetricLabels(nil)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker cell", func(t *testing.T) {
+		wantBroker.BrokerCell = "cell"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetBrokerCell("cell")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker generation", func(t *testing.T) {
+		wantBroker.Generation = 2
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetGeneration(2)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker encryption key", func(t *testing.T) {
+		wantBroker.EncryptionKey = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetEncryptionKey("projects/p/locations/global/keyRings/r/cryptoKeys/k")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.EncryptionKey = ""
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetEncryptionKey("")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker previous encryption keys", func(t *testing.T) {
+		wantBroker.PreviousEncryptionKeys = []string{"projects/p/locations/global/keyRings/r/cryptoKeys/k"}
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetPreviousEncryptionKeys([]string{"projects/p/locations/global/keyRings/r/cryptoKeys/k"})
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.PreviousEncryptionKeys = nil
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetPreviousEncryptionKeys(nil)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker ingress auth key", func(t *testing.T) {
+		wantBroker.IngressAuthKey = "uid"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetIngressAuthKey("uid")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.IngressAuthKey = ""
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetIngressAuthKey("")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker reply headers key", func(t *testing.T) {
+		wantBroker.ReplyHeadersKey = "reply-uid"
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetReplyHeadersKey("reply-uid")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.ReplyHeadersKey = ""
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetReplyHeadersKey("")
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker encoding", func(t *testing.T) {
+		wantBroker.StructuredEncoding = true
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetStructuredEncoding(true)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.StructuredEncoding = false
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetStructuredEncoding(false)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
+	t.Run("update broker event policy", func(t *testing.T) {
+		wantBroker.AllowedEventTypes = []string{"com.example.*"}
+		wantBroker.DeniedEventTypes = []string{"com.example.internal"}
+		wantBroker.AllowedEventSources = []string{"//shop"}
+		wantBroker.DeniedEventSources = []string{"//test"}
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetEventTypePolicy([]string{"com.example.*"}, []string{"com.example.internal"})
+			m.SetEventSourcePolicy([]string{"//shop"}, []string{"//test"})
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+
+		wantBroker.AllowedEventTypes = nil
+		wantBroker.DeniedEventTypes = nil
+		wantBroker.AllowedEventSources = nil
+		wantBroker.DeniedEventSources = nil
+		targets.MutateBroker("ns", "broker", func(m config.BrokerMutation) {
+			m.SetEventTypePolicy(nil, nil)
+			m.SetEventSourcePolicy(nil, nil)
+		})
+		assertBroker(t, wantBroker, "ns", "broker", targets)
+	})
+
 	t1 := &config.Target{
 		Id:      "uid-1",
 		Address: "consumer1.example.com",
@@ -174,6 +296,8 @@ func TestMutateBroker(t *testing.T) {
 				Topic:        "topic",
 				Subscription: "sub",
 			})
+			m.SetBrokerCell("cell")
+			m.SetGeneration(2)
 			m.UpsertTargets(t1, t2)
 		})
 		assertBroker(t, wantBroker, "ns", "broker", targets)
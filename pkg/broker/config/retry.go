@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// RetrySubscriptionMinBackoff is the minimum backoff of the retry policy
+	// of the retry subscriptions, and the default backoff of the requeued
+	// events.
+	RetrySubscriptionMinBackoff = time.Second
+	// RetrySubscriptionMaxBackoff is the maximum backoff of the retry policy
+	// of the retry subscriptions, and the default maximum backoff of the
+	// requeued events. The retry deployment nacks the requeued events that
+	// are not due yet, and Pub/Sub redelivers them within this backoff, so
+	// the requeued events can't back off longer.
+	RetrySubscriptionMaxBackoff = time.Minute
+)
+
+// ValidateRetryBackoff returns an error if the backoff of the requeued events
+// doesn't fit in the retry policy of the retry subscriptions.
+func ValidateRetryBackoff(min, max time.Duration) error {
+	if min > max {
+		return fmt.Errorf("min retry backoff %v is greater than max retry backoff %v", min, max)
+	}
+	if max > RetrySubscriptionMaxBackoff {
+		return fmt.Errorf("max retry backoff %v is greater than %v, the max backoff of the retry subscriptions", max, RetrySubscriptionMaxBackoff)
+	}
+	return nil
+}
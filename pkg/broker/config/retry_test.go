@@ -0,0 +1,55 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateRetryBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max time.Duration
+		wantErr  bool
+	}{{
+		name: "retry subscription policy",
+		min:  RetrySubscriptionMinBackoff,
+		max:  RetrySubscriptionMaxBackoff,
+	}, {
+		name: "shorter backoff",
+		min:  100 * time.Millisecond,
+		max:  10 * time.Second,
+	}, {
+		name:    "min greater than max",
+		min:     time.Minute,
+		max:     time.Second,
+		wantErr: true,
+	}, {
+		name:    "max greater than the retry subscription policy",
+		min:     time.Second,
+		max:     RetrySubscriptionMaxBackoff + time.Second,
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateRetryBackoff(tc.min, tc.max); (err != nil) != tc.wantErr {
+				t.Errorf("ValidateRetryBackoff(%v, %v) = %v, wantErr %v", tc.min, tc.max, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package encryption encrypts the data of the events published to the
+// decouple queue of a broker with envelope encryption: the data is encrypted
+// with a data key, which is itself wrapped with a Cloud KMS key and sent
+// along with the event. The data is bound to its broker, so that it can't be
+// decrypted as the data of an event of another broker.
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+)
+
+const (
+	// KeyExtension is the CloudEvents extension holding the name of the Cloud
+	// KMS key the data key of an encrypted event is wrapped with. The
+	// extensions are prefixed like the other extensions internal to the
+	// broker, and the ingress deletes them from the events it receives.
+	KeyExtension = "kgcpenckey"
+	// DataKeyExtension is the CloudEvents extension holding the wrapped data
+	// key of an encrypted event, base64 encoded.
+	DataKeyExtension = "kgcpencdatakey"
+	// ContentTypeExtension is the CloudEvents extension holding the content
+	// type of the data of an encrypted event before it was encrypted.
+	ContentTypeExtension = "kgcpenctype"
+
+	// encryptedContentType is the content type of encrypted event data.
+	encryptedContentType = "application/octet-stream"
+
+	// dataKeyLifetime is how long the data key of a broker is used before a
+	// new one is generated.
+	dataKeyLifetime = time.Hour
+	// maxCachedDataKeys is the number of unwrapped data keys a Decrypter
+	// remembers.
+	maxCachedDataKeys = 1000
+)
+
+// ErrCorrupted is returned by Decrypt when the data of an event cannot be
+// decrypted however often it is retried.
+var ErrCorrupted = errors.New("encrypted event is corrupted")
+
+// KeyWrapper wraps and unwraps data keys with a key encryption key.
+type KeyWrapper interface {
+	// Wrap encrypts the data key with the named key.
+	Wrap(ctx context.Context, keyName string, dataKey []byte) ([]byte, error)
+	// Unwrap decrypts the wrapped data key with the named key.
+	Unwrap(ctx context.Context, keyName string, wrapped []byte) ([]byte, error)
+}
+
+// Encrypter encrypts the data of the events sent to brokers with an
+// encryption key. Each broker has its own data key, which is replaced after
+// dataKeyLifetime or when the broker's encryption key changes.
+type Encrypter struct {
+	wrapper KeyWrapper
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	mu sync.Mutex
+	// dataKeys holds the data key of each broker, keyed by broker key.
+	dataKeys map[string]*dataKey
+}
+
+// dataKey is a data key along with its wrapped form.
+type dataKey struct {
+	keyName string
+	wrapped string
+	aead    cipher.AEAD
+	expires time.Time
+}
+
+// NewEncrypter creates an Encrypter wrapping data keys with wrapper.
+func NewEncrypter(wrapper KeyWrapper) *Encrypter {
+	return &Encrypter{
+		wrapper:  wrapper,
+		now:      time.Now,
+		dataKeys: make(map[string]*dataKey),
+	}
+}
+
+// Encrypt encrypts the data of the event in place if the broker has an
+// encryption key, and records what is needed to decrypt it in the event
+// extensions. The broker key is authenticated along with the data. Events
+// without data are left as they are.
+func (e *Encrypter) Encrypt(ctx context.Context, broker *config.Broker, event *cev2.Event) error {
+	if broker.EncryptionKey == "" || len(event.Data()) == 0 {
+		return nil
+	}
+	k, err := e.dataKey(ctx, broker)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	sealed := k.aead.Seal(nonce, nonce, event.Data(), []byte(broker.Key()))
+
+	if ct := event.DataContentType(); ct != "" {
+		event.SetExtension(ContentTypeExtension, ct)
+	}
+	event.SetExtension(KeyExtension, k.keyName)
+	event.SetExtension(DataKeyExtension, k.wrapped)
+	event.SetDataContentType(encryptedContentType)
+	event.DataEncoded = sealed
+	event.DataBase64 = true
+	return nil
+}
+
+// dataKey returns the current data key of the broker, generating and wrapping
+// a new one if there is none yet, or if it expired or was wrapped with another
+// key.
+func (e *Encrypter) dataKey(ctx context.Context, broker *config.Broker) (*dataKey, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.now()
+	if k, ok := e.dataKeys[broker.Key()]; ok && k.keyName == broker.EncryptionKey && now.Before(k.expires) {
+		return k, nil
+	}
+
+	plain := make([]byte, 32)
+	if _, err := rand.Read(plain); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(plain)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := e.wrapper.Wrap(ctx, broker.EncryptionKey, plain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key with %q: %w", broker.EncryptionKey, err)
+	}
+	k := &dataKey{
+		keyName: broker.EncryptionKey,
+		wrapped: base64.StdEncoding.EncodeToString(wrapped),
+		aead:    aead,
+		expires: now.Add(dataKeyLifetime),
+	}
+	e.dataKeys[broker.Key()] = k
+	return k, nil
+}
+
+// Decrypter decrypts the data of the events encrypted by an Encrypter. It
+// remembers the most recently unwrapped data keys, so that the key encryption
+// key is only used once per data key.
+type Decrypter struct {
+	wrapper KeyWrapper
+	// aeads holds the ciphers of the unwrapped data keys, keyed by wrapped
+	// data key.
+	aeads *cache.LRUExpireCache
+}
+
+// NewDecrypter creates a Decrypter unwrapping data keys with wrapper.
+func NewDecrypter(wrapper KeyWrapper) *Decrypter {
+	return &Decrypter{
+		wrapper: wrapper,
+		aeads:   cache.NewLRUExpireCache(maxCachedDataKeys),
+	}
+}
+
+// Decrypt decrypts the data of the event of the broker in place if it was
+// encrypted, and removes the extensions recorded by Encrypt. Events that were
+// not encrypted are left as they are. It returns an error wrapping
+// ErrCorrupted if the event cannot be decrypted however often it is retried,
+// which includes events encrypted for another broker or with another key than
+// the current and previous encryption keys of the broker.
+func (d *Decrypter) Decrypt(ctx context.Context, broker *config.Broker, event *cev2.Event) error {
+	ext := event.Extensions()
+	if _, ok := ext[DataKeyExtension]; !ok {
+		return nil
+	}
+	keyName, err := cetypes.ToString(ext[KeyExtension])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	// The data key is only unwrapped with the keys of the broker, so that
+	// events can't make the fanout use other keys it has access to.
+	if !brokerKey(broker, keyName) {
+		return fmt.Errorf("%w: encrypted with key %q instead of the broker key %q", ErrCorrupted, keyName, broker.EncryptionKey)
+	}
+	wrapped, err := cetypes.ToString(ext[DataKeyExtension])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	aead, err := d.aead(ctx, keyName, wrapped)
+	if err != nil {
+		return err
+	}
+
+	sealed := event.Data()
+	if len(sealed) < aead.NonceSize() {
+		return fmt.Errorf("%w: data is too short", ErrCorrupted)
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, sealed, []byte(broker.Key()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+
+	contentType, _ := cetypes.ToString(ext[ContentTypeExtension])
+	DeleteExtensions(event)
+	event.SetDataContentType(contentType)
+	event.DataEncoded = data
+	event.DataBase64 = false
+	return nil
+}
+
+// brokerKey returns true if keyName is the current or a previous encryption key
+// of the broker. Events encrypted before the key was changed are still
+// decrypted.
+func brokerKey(broker *config.Broker, keyName string) bool {
+	if keyName == "" {
+		return false
+	}
+	if keyName == broker.EncryptionKey {
+		return true
+	}
+	for _, k := range broker.PreviousEncryptionKeys {
+		if keyName == k {
+			return true
+		}
+	}
+	return false
+}
+
+// aead returns the cipher of the wrapped data key, unwrapping it with the
+// named key if it isn't cached.
+func (d *Decrypter) aead(ctx context.Context, keyName, wrapped string) (cipher.AEAD, error) {
+	if aead, ok := d.aeads.Get(wrapped); ok {
+		return aead.(cipher.AEAD), nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	plain, err := d.wrapper.Unwrap(ctx, keyName, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key with %q: %w", keyName, err)
+	}
+	aead, err := newAEAD(plain)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	// Data keys stop being used after dataKeyLifetime, but events encrypted
+	// with them may stay in the queues for days.
+	d.aeads.Add(wrapped, aead, dataKeyLifetime)
+	return aead, nil
+}
+
+// newAEAD returns an AES-GCM cipher using the data key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// DeleteExtensions deletes the extensions recorded by Encrypt from the event.
+func DeleteExtensions(event *cev2.Event) {
+	for _, name := range []string{KeyExtension, DataKeyExtension, ContentTypeExtension} {
+		event.SetExtension(name, nil)
+	}
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	enctesting "github.com/google/knative-gcp/pkg/broker/encryption/testing"
+)
+
+const testKey = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+
+func newTestEvent() cev2.Event {
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	e.SetExtension("foo", "bar")
+	if err := e.SetData(cev2.ApplicationJSON, map[string]string{"hello": "world"}); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &enctesting.FakeKeyWrapper{}
+	enc := NewEncrypter(wrapper)
+	dec := NewDecrypter(wrapper)
+	broker := &config.Broker{Namespace: "ns", Name: "broker", EncryptionKey: testKey}
+
+	want := newTestEvent()
+	for i := 0; i < 2; i++ {
+		event := newTestEvent()
+		if err := enc.Encrypt(ctx, broker, &event); err != nil {
+			t.Fatalf("Encrypt() = %v", err)
+		}
+		if string(event.Data()) == string(want.Data()) {
+			t.Errorf("Encrypt() left the data as it was: %s", event.Data())
+		}
+		if got := event.DataContentType(); got != encryptedContentType {
+			t.Errorf("DataContentType() = %q, want %q", got, encryptedContentType)
+		}
+		if got := event.Extensions()[KeyExtension]; got != testKey {
+			t.Errorf("extension %s = %v, want %q", KeyExtension, got, testKey)
+		}
+
+		if err := dec.Decrypt(ctx, broker, &event); err != nil {
+			t.Fatalf("Decrypt() = %v", err)
+		}
+		event.DataBase64 = want.DataBase64
+		if diff := cmp.Diff(want, event); diff != "" {
+			t.Errorf("unexpected decrypted event (-want, +got) = %v", diff)
+		}
+	}
+	// The data key is only wrapped and unwrapped once.
+	if got := wrapper.Wraps(); got != 1 {
+		t.Errorf("Wraps() = %d, want 1", got)
+	}
+	if got := wrapper.Unwraps(); got != 1 {
+		t.Errorf("Unwraps() = %d, want 1", got)
+	}
+}
+
+func TestEncryptWithoutKey(t *testing.T) {
+	wrapper := &enctesting.FakeKeyWrapper{}
+	enc := NewEncrypter(wrapper)
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+
+	want := newTestEvent()
+	event := newTestEvent()
+	if err := enc.Encrypt(context.Background(), broker, &event); err != nil {
+		t.Fatalf("Encrypt() = %v", err)
+	}
+	if diff := cmp.Diff(want, event); diff != "" {
+		t.Errorf("unexpected event (-want, +got) = %v", diff)
+	}
+	if got := wrapper.Wraps(); got != 0 {
+		t.Errorf("Wraps() = %d, want 0", got)
+	}
+}
+
+func TestEncryptRotatesDataKey(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &enctesting.FakeKeyWrapper{}
+	enc := NewEncrypter(wrapper)
+	now := time.Now()
+	enc.now = func() time.Time { return now }
+	broker := &config.Broker{Namespace: "ns", Name: "broker", EncryptionKey: testKey}
+
+	wrappedKey := func() interface{} {
+		t.Helper()
+		event := newTestEvent()
+		if err := enc.Encrypt(ctx, broker, &event); err != nil {
+			t.Fatalf("Encrypt() = %v", err)
+		}
+		return event.Extensions()[DataKeyExtension]
+	}
+
+	first := wrappedKey()
+	if got := wrappedKey(); got != first {
+		t.Errorf("data key changed before it expired")
+	}
+	now = now.Add(dataKeyLifetime)
+	second := wrappedKey()
+	if second == first {
+		t.Errorf("data key was not replaced once expired")
+	}
+	broker.EncryptionKey = testKey + "-2"
+	if got := wrappedKey(); got == second {
+		t.Errorf("data key was not replaced when the encryption key changed")
+	}
+}
+
+func TestDecryptUnencrypted(t *testing.T) {
+	dec := NewDecrypter(&enctesting.FakeKeyWrapper{})
+	broker := &config.Broker{Namespace: "ns", Name: "broker", EncryptionKey: testKey}
+	want := newTestEvent()
+	event := newTestEvent()
+	if err := dec.Decrypt(context.Background(), broker, &event); err != nil {
+		t.Fatalf("Decrypt() = %v", err)
+	}
+	if diff := cmp.Diff(want, event); diff != "" {
+		t.Errorf("unexpected event (-want, +got) = %v", diff)
+	}
+}
+
+func TestDecryptPreviousKey(t *testing.T) {
+	ctx := context.Background()
+	wrapper := &enctesting.FakeKeyWrapper{}
+	broker := &config.Broker{Namespace: "ns", Name: "broker", EncryptionKey: testKey}
+
+	for n, rotated := range map[string]*config.Broker{
+		"key changed":         {Namespace: "ns", Name: "broker", EncryptionKey: testKey + "-2", PreviousEncryptionKeys: []string{testKey}},
+		"encryption disabled": {Namespace: "ns", Name: "broker", PreviousEncryptionKeys: []string{testKey + "-2", testKey}},
+	} {
+		t.Run(n, func(t *testing.T) {
+			want := newTestEvent()
+			event := newTestEvent()
+			if err := NewEncrypter(wrapper).Encrypt(ctx, broker, &event); err != nil {
+				t.Fatalf("Encrypt() = %v", err)
+			}
+			if err := NewDecrypter(wrapper).Decrypt(ctx, rotated, &event); err != nil {
+				t.Fatalf("Decrypt() = %v", err)
+			}
+			event.DataBase64 = want.DataBase64
+			if diff := cmp.Diff(want, event); diff != "" {
+				t.Errorf("unexpected decrypted event (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	ctx := context.Background()
+	wrapErr := errors.New("permission denied")
+	broker := &config.Broker{Namespace: "ns", Name: "broker", EncryptionKey: testKey}
+
+	encrypted := func() cev2.Event {
+		event := newTestEvent()
+		if err := NewEncrypter(&enctesting.FakeKeyWrapper{}).Encrypt(ctx, broker, &event); err != nil {
+			t.Fatalf("Encrypt() = %v", err)
+		}
+		return event
+	}
+
+	testCases := map[string]struct {
+		wrapper *enctesting.FakeKeyWrapper
+		mutate  func(*cev2.Event)
+		// broker is the broker the event is decrypted for, if not the one
+		// it was encrypted for.
+		broker        *config.Broker
+		wantCorrupted bool
+	}{
+		"unwrap failed": {
+			wrapper: &enctesting.FakeKeyWrapper{Err: wrapErr},
+		},
+		"tampered data": {
+			wrapper: &enctesting.FakeKeyWrapper{},
+			mutate: func(e *cev2.Event) {
+				e.DataEncoded[len(e.DataEncoded)-1] ^= 1
+			},
+			wantCorrupted: true,
+		},
+		"truncated data": {
+			wrapper: &enctesting.FakeKeyWrapper{},
+			mutate: func(e *cev2.Event) {
+				e.DataEncoded = e.DataEncoded[:4]
+			},
+			wantCorrupted: true,
+		},
+		"malformed data key": {
+			wrapper: &enctesting.FakeKeyWrapper{},
+			mutate: func(e *cev2.Event) {
+				e.SetExtension(DataKeyExtension, "not base64!")
+			},
+			wantCorrupted: true,
+		},
+		"other broker": {
+			wrapper:       &enctesting.FakeKeyWrapper{},
+			broker:        &config.Broker{Namespace: "ns", Name: "other", EncryptionKey: testKey},
+			wantCorrupted: true,
+		},
+		"other key": {
+			wrapper: &enctesting.FakeKeyWrapper{},
+			mutate: func(e *cev2.Event) {
+				e.SetExtension(KeyExtension, testKey+"-2")
+			},
+			wantCorrupted: true,
+		},
+		"broker without key": {
+			wrapper:       &enctesting.FakeKeyWrapper{},
+			broker:        &config.Broker{Namespace: "ns", Name: "broker"},
+			wantCorrupted: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			event := encrypted()
+			if tc.mutate != nil {
+				tc.mutate(&event)
+			}
+			b := broker
+			if tc.broker != nil {
+				b = tc.broker
+			}
+			err := NewDecrypter(tc.wrapper).Decrypt(ctx, b, &event)
+			if err == nil {
+				t.Fatal("Decrypt() = nil, want error")
+			}
+			if got := errors.Is(err, ErrCorrupted); got != tc.wantCorrupted {
+				t.Errorf("errors.Is(%v, ErrCorrupted) = %v, want %v", err, got, tc.wantCorrupted)
+			}
+		})
+	}
+}
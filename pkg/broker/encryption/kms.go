@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package encryption
+
+import (
+	"context"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// kmsKeyWrapper wraps data keys with Cloud KMS symmetric keys.
+type kmsKeyWrapper struct {
+	client *kms.KeyManagementClient
+}
+
+// NewKMSKeyWrapper creates a KeyWrapper wrapping data keys with Cloud KMS
+// keys. Key names are Cloud KMS CryptoKey resource names, e.g.
+// projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key.
+func NewKMSKeyWrapper(ctx context.Context) (KeyWrapper, error) {
+	client, err := kms.NewKeyManagementClient(ctx, endpoints.KMS()...)
+	if err != nil {
+		return nil, err
+	}
+	return &kmsKeyWrapper{client: client}, nil
+}
+
+// Wrap implements KeyWrapper.
+func (w *kmsKeyWrapper) Wrap(ctx context.Context, keyName string, dataKey []byte) ([]byte, error) {
+	resp, err := w.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:      keyName,
+		Plaintext: dataKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+// Unwrap implements KeyWrapper.
+func (w *kmsKeyWrapper) Unwrap(ctx context.Context, keyName string, wrapped []byte) ([]byte, error) {
+	resp, err := w.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:       keyName,
+		Ciphertext: wrapped,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// FakeKeyWrapper is an encryption.KeyWrapper which "wraps" data keys by
+// prefixing them with the key name, and counts its calls.
+type FakeKeyWrapper struct {
+	// Err is returned by Wrap and Unwrap if set.
+	Err error
+
+	wraps   int32
+	unwraps int32
+}
+
+// Wrap implements encryption.KeyWrapper.
+func (w *FakeKeyWrapper) Wrap(ctx context.Context, keyName string, dataKey []byte) ([]byte, error) {
+	atomic.AddInt32(&w.wraps, 1)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return append([]byte(keyName+":"), dataKey...), nil
+}
+
+// Unwrap implements encryption.KeyWrapper.
+func (w *FakeKeyWrapper) Unwrap(ctx context.Context, keyName string, wrapped []byte) ([]byte, error) {
+	atomic.AddInt32(&w.unwraps, 1)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	prefix := []byte(keyName + ":")
+	if !bytes.HasPrefix(wrapped, prefix) {
+		return nil, errors.New("data key was wrapped with another key")
+	}
+	return wrapped[len(prefix):], nil
+}
+
+// Wraps returns the number of calls to Wrap.
+func (w *FakeKeyWrapper) Wraps() int {
+	return int(atomic.LoadInt32(&w.wraps))
+}
+
+// Unwraps returns the number of calls to Unwrap.
+func (w *FakeKeyWrapper) Unwraps() int {
+	return int(atomic.LoadInt32(&w.unwraps))
+}
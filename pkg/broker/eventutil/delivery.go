@@ -0,0 +1,186 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	cetypes "github.com/cloudevents/sdk-go/pkg/cloudevents/types"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	// Keep the attributes short for the same reason as hopsAttribute.
+	deliveryAttemptsAttribute   = "kgcpattempts"
+	deliveryLastStatusAttribute = "kgcplaststatus"
+	deliveryLastErrorAttribute  = "kgcplasterror"
+	// deliveredSubscribersAttribute records the subscriber addresses of a
+	// target that already received a retried event, separated by spaces.
+	deliveredSubscribersAttribute = "kgcpdelivered"
+	// deadLetterReasonAttribute records why an event was sent to a dead
+	// letter sink instead of being delivered.
+	deadLetterReasonAttribute = "kgcpdeadletterreason"
+
+	// DeadLetterReasonExpired is the dead letter reason of the events older
+	// than the max age of their target.
+	DeadLetterReasonExpired = "expired"
+	// DeadLetterReasonUndecryptable is the dead letter reason of the events
+	// whose data cannot be decrypted. They are sent with their data still
+	// encrypted.
+	DeadLetterReasonUndecryptable = "undecryptable"
+	// DeadLetterReasonUndecompressable is the dead letter reason of the
+	// events whose data cannot be decompressed. They are sent with their data
+	// still compressed.
+	DeadLetterReasonUndecompressable = "undecompressable"
+
+	// maxDeliveryErrorLength caps the length in bytes of the error recorded in an event
+	// so that a verbose error doesn't blow up the Pubsub message size.
+	maxDeliveryErrorLength = 256
+)
+
+// DeliveryResult describes the outcome of the last failed delivery attempt of
+// an event.
+type DeliveryResult struct {
+	// Attempts is the number of failed delivery attempts so far.
+	Attempts int32
+	// StatusCode is the HTTP status code of the last attempt. It is zero if
+	// the subscriber never responded.
+	StatusCode int
+	// Error is a (possibly truncated) description of the last failure.
+	Error string
+}
+
+// RecordDeliveryFailure records a failed delivery attempt in the event
+// extensions. It increments any existing attempt count and overwrites the last
+// status and error. statusCode is ignored if it's zero.
+func RecordDeliveryFailure(ctx context.Context, event *event.Event, statusCode int, err error) {
+	res, _ := GetDeliveryResult(ctx, event)
+	event.SetExtension(deliveryAttemptsAttribute, res.Attempts+1)
+	if statusCode != 0 {
+		event.SetExtension(deliveryLastStatusAttribute, int32(statusCode))
+	} else {
+		event.SetExtension(deliveryLastStatusAttribute, nil)
+	}
+	if err != nil {
+		event.SetExtension(deliveryLastErrorAttribute, utils.Truncate(err.Error(), maxDeliveryErrorLength))
+	} else {
+		event.SetExtension(deliveryLastErrorAttribute, nil)
+	}
+}
+
+// GetDeliveryResult returns the delivery result recorded in the event.
+// If there is no recorded attempt count or an invalid one, false is returned.
+func GetDeliveryResult(ctx context.Context, event *event.Event) (DeliveryResult, bool) {
+	var res DeliveryResult
+	exts := event.Extensions()
+	attemptsRaw, ok := exts[deliveryAttemptsAttribute]
+	if !ok {
+		return res, false
+	}
+	attempts, err := cetypes.ToInteger(attemptsRaw)
+	if err != nil {
+		logging.FromContext(ctx).Warn("Failed to convert existing delivery attempts into integer, regarding it as there is no delivery result.",
+			zap.String("event.id", event.ID()),
+			zap.Any(deliveryAttemptsAttribute, attemptsRaw),
+			zap.Error(err),
+		)
+		return res, false
+	}
+	res.Attempts = attempts
+	if statusRaw, ok := exts[deliveryLastStatusAttribute]; ok {
+		if status, err := cetypes.ToInteger(statusRaw); err == nil {
+			res.StatusCode = int(status)
+		}
+	}
+	if errRaw, ok := exts[deliveryLastErrorAttribute]; ok {
+		if msg, err := cetypes.ToString(errRaw); err == nil {
+			res.Error = msg
+		}
+	}
+	return res, true
+}
+
+// DeleteDeliveryResult deletes the delivery result from the event extensions.
+func DeleteDeliveryResult(_ context.Context, event *event.Event) {
+	event.SetExtension(deliveryAttemptsAttribute, nil)
+	event.SetExtension(deliveryLastStatusAttribute, nil)
+	event.SetExtension(deliveryLastErrorAttribute, nil)
+}
+
+// RecordDeliveredSubscribers adds addresses to the subscriber addresses
+// recorded in the event as having received it, so that retries of the event
+// skip them.
+func RecordDeliveredSubscribers(event *event.Event, addresses []string) {
+	if len(addresses) == 0 {
+		return
+	}
+	delivered := GetDeliveredSubscribers(event)
+	all := make([]string, 0, len(delivered)+len(addresses))
+	for address := range delivered {
+		all = append(all, address)
+	}
+	for _, address := range addresses {
+		if !delivered[address] {
+			all = append(all, address)
+		}
+	}
+	sort.Strings(all)
+	event.SetExtension(deliveredSubscribersAttribute, strings.Join(all, " "))
+}
+
+// GetDeliveredSubscribers returns the set of subscriber addresses recorded in
+// the event as having received it.
+func GetDeliveredSubscribers(event *event.Event) map[string]bool {
+	raw, ok := event.Extensions()[deliveredSubscribersAttribute]
+	if !ok {
+		return nil
+	}
+	s, err := cetypes.ToString(raw)
+	if err != nil {
+		return nil
+	}
+	delivered := make(map[string]bool)
+	for _, address := range strings.Fields(s) {
+		delivered[address] = true
+	}
+	return delivered
+}
+
+// DeleteDeliveredSubscribers deletes the delivered subscriber addresses from
+// the event extensions.
+func DeleteDeliveredSubscribers(event *event.Event) {
+	event.SetExtension(deliveredSubscribersAttribute, nil)
+}
+
+// SetDeadLetterReason records in the event extensions why it is sent to a
+// dead letter sink.
+func SetDeadLetterReason(event *event.Event, reason string) {
+	event.SetExtension(deadLetterReasonAttribute, reason)
+}
+
+// GetDeadLetterReason returns why the event was sent to a dead letter sink,
+// empty if it wasn't.
+func GetDeadLetterReason(event *event.Event) string {
+	reason, _ := cetypes.ToString(event.Extensions()[deadLetterReasonAttribute])
+	return reason
+}
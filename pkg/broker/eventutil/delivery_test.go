@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetDeliveryResult(t *testing.T) {
+	cases := []struct {
+		name    string
+		exts    map[string]interface{}
+		wantRes DeliveryResult
+		wantOK  bool
+	}{{
+		name: "no result",
+	}, {
+		name: "invalid attempts",
+		exts: map[string]interface{}{deliveryAttemptsAttribute: "abc"},
+	}, {
+		name: "attempts only",
+		exts: map[string]interface{}{deliveryAttemptsAttribute: 2},
+		wantRes: DeliveryResult{
+			Attempts: 2,
+		},
+		wantOK: true,
+	}, {
+		name: "full result",
+		exts: map[string]interface{}{
+			deliveryAttemptsAttribute:   "3",
+			deliveryLastStatusAttribute: "503",
+			deliveryLastErrorAttribute:  "unavailable",
+		},
+		wantRes: DeliveryResult{
+			Attempts:   3,
+			StatusCode: 503,
+			Error:      "unavailable",
+		},
+		wantOK: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := event.New()
+			for k, v := range tc.exts {
+				e.SetExtension(k, v)
+			}
+			gotRes, gotOK := GetDeliveryResult(context.Background(), &e)
+			if gotOK != tc.wantOK {
+				t.Errorf("Found delivery result OK got=%v, want=%v", gotOK, tc.wantOK)
+			}
+			if diff := cmp.Diff(tc.wantRes, gotRes); diff != "" {
+				t.Errorf("Delivery result (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestRecordDeliveryFailure(t *testing.T) {
+	ctx := context.Background()
+	e := event.New()
+
+	RecordDeliveryFailure(ctx, &e, 500, errors.New("internal error"))
+	got, ok := GetDeliveryResult(ctx, &e)
+	if !ok {
+		t.Fatal("Found delivery result OK got=false, want=true")
+	}
+	want := DeliveryResult{Attempts: 1, StatusCode: 500, Error: "internal error"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Delivery result after first failure (-want,+got): %v", diff)
+	}
+
+	// A failure without a response clears the previous status code.
+	RecordDeliveryFailure(ctx, &e, 0, errors.New(strings.Repeat("x", 2*maxDeliveryErrorLength)))
+	got, _ = GetDeliveryResult(ctx, &e)
+	if got.Attempts != 2 {
+		t.Errorf("Attempts got=%d, want=2", got.Attempts)
+	}
+	if got.StatusCode != 0 {
+		t.Errorf("StatusCode got=%d, want=0", got.StatusCode)
+	}
+	if wantErr := strings.Repeat("x", maxDeliveryErrorLength) + "..."; got.Error != wantErr {
+		t.Errorf("Error got=%q, want=%q", got.Error, wantErr)
+	}
+
+	// The error is never cut in the middle of a multi-byte character.
+	RecordDeliveryFailure(ctx, &e, 0, errors.New("x"+strings.Repeat("é", maxDeliveryErrorLength)))
+	got, _ = GetDeliveryResult(ctx, &e)
+	if wantErr := "x" + strings.Repeat("é", maxDeliveryErrorLength/2-1) + "..."; got.Error != wantErr {
+		t.Errorf("Error got=%q, want=%q", got.Error, wantErr)
+	}
+}
+
+func TestDeleteDeliveryResult(t *testing.T) {
+	ctx := context.Background()
+	e := event.New()
+	RecordDeliveryFailure(ctx, &e, 500, errors.New("internal error"))
+	DeleteDeliveryResult(ctx, &e)
+	if len(e.Extensions()) != 0 {
+		t.Errorf("After DeleteDeliveryResult extensions got=%v, want none", e.Extensions())
+	}
+}
+
+func TestRecordDeliveredSubscribers(t *testing.T) {
+	e := event.New()
+	if got := GetDeliveredSubscribers(&e); len(got) != 0 {
+		t.Errorf("Delivered subscribers of a new event got=%v, want none", got)
+	}
+	RecordDeliveredSubscribers(&e, []string{"http://b", "http://a"})
+	RecordDeliveredSubscribers(&e, []string{"http://c", "http://a"})
+	want := map[string]bool{"http://a": true, "http://b": true, "http://c": true}
+	if diff := cmp.Diff(want, GetDeliveredSubscribers(&e)); diff != "" {
+		t.Errorf("Delivered subscribers (-want,+got): %v", diff)
+	}
+	DeleteDeliveredSubscribers(&e)
+	if len(e.Extensions()) != 0 {
+		t.Errorf("After DeleteDeliveredSubscribers extensions got=%v, want none", e.Extensions())
+	}
+}
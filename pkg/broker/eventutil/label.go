@@ -23,10 +23,23 @@ import (
 )
 
 const (
-	// labelPrefix is the prefix for label keys.
+	// labelPrefix is the prefix for label keys. It is also the prefix of the
+	// other extensions internal to the broker.
 	labelPrefix = "kgcp"
 )
 
+// DeleteInternalExtensions deletes the extensions internal to the broker, i.e.
+// prefixed with "kgcp", from the event, except the remaining hops. The fanout
+// sets those on the replies it sends back to the broker ingress, which keeps
+// them so that replies can't loop forever.
+func DeleteInternalExtensions(e *cloudevents.Event) {
+	for k := range e.Extensions() {
+		if k != hopsAttribute && strings.HasPrefix(strings.ToLower(k), labelPrefix) {
+			e.SetExtension(k, nil)
+		}
+	}
+}
+
 // LabeledEvent is a wrapper of a cloudevent
 // that allows labeling the event.
 type LabeledEvent struct {
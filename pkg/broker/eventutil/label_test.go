@@ -60,3 +60,21 @@ func TestLabeledEevent(t *testing.T) {
 		t.Errorf("Labels count after removing labels got=%d want=0", gotCnt)
 	}
 }
+
+func TestDeleteInternalExtensions(t *testing.T) {
+	e := cloudevents.NewEvent()
+	e.SetSource("example/uri")
+	e.SetType("example.type")
+	e.SetExtension("custom", "foo")
+	want := e.Clone()
+	want.SetExtension(hopsAttribute, 3)
+
+	e.SetExtension(hopsAttribute, 3)
+	e.SetExtension(deliveryAttemptsAttribute, 2)
+	e.SetExtension(deliveredSubscribersAttribute, "http://subscriber")
+	e.SetExtension("kgcpenckey", "key")
+	DeleteInternalExtensions(&e)
+	if diff := cmp.Diff(want, e); diff != "" {
+		t.Errorf("DeleteInternalExtensions() (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// transformEventVar is the name of the variable holding the event in the
+// transform expressions.
+const transformEventVar = "event"
+
+var jsonValueType = reflect.TypeOf(&structpb.Value{})
+
+var (
+	// transformEnvOnce creates the CEL environment the transform expressions
+	// are compiled in once, as it is the same for all the transforms.
+	transformEnvOnce sync.Once
+	transformEnv     *cel.Env
+	transformEnvErr  error
+)
+
+// getTransformEnv returns the CEL environment the transform expressions are
+// compiled in.
+func getTransformEnv() (*cel.Env, error) {
+	transformEnvOnce.Do(func() {
+		transformEnv, transformEnvErr = cel.NewEnv(cel.Declarations(
+			decls.NewVar(transformEventVar, decls.NewMapType(decls.String, decls.Dyn)),
+		))
+	})
+	return transformEnv, transformEnvErr
+}
+
+// Transform replaces the data of events with a JSON object whose fields are
+// CEL expressions evaluated against the events in CloudEvents JSON format,
+// available as the event variable, e.g.
+// {"user":"event.data.user.id","kind":"event.type"}.
+type Transform struct {
+	fields map[string]cel.Program
+}
+
+// NewTransform compiles the CEL expressions of the transform, keyed by output
+// field name.
+func NewTransform(fields map[string]string) (*Transform, error) {
+	env, err := getTransformEnv()
+	if err != nil {
+		return nil, err
+	}
+	t := &Transform{fields: make(map[string]cel.Program, len(fields))}
+	for name, expr := range fields {
+		ast, iss := env.Compile(expr)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("invalid expression for field %q: %s", name, summarize(iss))
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expression for field %q: %w", name, err)
+		}
+		t.fields[name] = prg
+	}
+	return t, nil
+}
+
+// summarize returns the compile errors of an expression on one line, so that
+// they read well in conditions.
+func summarize(iss *cel.Issues) string {
+	msgs := make([]string, 0, len(iss.Errors()))
+	for _, e := range iss.Errors() {
+		msgs = append(msgs, fmt.Sprintf("column %d: %s", e.Location.Column()+1, e.Message))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Apply replaces the data of the event with the transformed JSON object. It
+// fails if an expression fails to evaluate, e.g. because it selects a field
+// the event doesn't have, or evaluates to a value with no JSON equivalent.
+// Expressions can check for optional fields with the has() macro.
+func (t *Transform) Apply(e *event.Event) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return err
+	}
+	vars := map[string]interface{}{transformEventVar: envelope}
+
+	data := make(map[string]json.RawMessage, len(t.fields))
+	for name, prg := range t.fields {
+		out, _, err := prg.Eval(vars)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate field %q: %w", name, err)
+		}
+		v, err := out.ConvertToNative(jsonValueType)
+		if err != nil {
+			return fmt.Errorf("field %q is not a JSON value: %w", name, err)
+		}
+		if data[name], err = protojson.Marshal(v.(*structpb.Value)); err != nil {
+			return fmt.Errorf("field %q is not a JSON value: %w", name, err)
+		}
+	}
+	return e.SetData(event.ApplicationJSON, data)
+}
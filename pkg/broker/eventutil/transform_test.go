@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventutil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTransform(t *testing.T) {
+	cases := []struct {
+		name     string
+		fields   map[string]string
+		data     interface{}
+		wantData map[string]interface{}
+		wantErr  bool
+	}{{
+		name: "envelope and data fields",
+		fields: map[string]string{
+			"kind":   "event.type",
+			"user":   "event.data.user.id",
+			"region": "event.region",
+		},
+		data: map[string]interface{}{"user": map[string]interface{}{"id": "u1", "name": "jane"}},
+		wantData: map[string]interface{}{
+			"kind":   "example.type",
+			"user":   "u1",
+			"region": "europe",
+		},
+	}, {
+		name: "computed fields",
+		fields: map[string]string{
+			"ids":   "event.data.items.map(i, i.id)",
+			"count": "size(event.data.items)",
+			"label": `event.type + "/" + event.id`,
+		},
+		data: map[string]interface{}{"items": []interface{}{map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2}}},
+		wantData: map[string]interface{}{
+			"ids":   []interface{}{float64(1), float64(2)},
+			"count": float64(2),
+			"label": "example.type/id",
+		},
+	}, {
+		name:     "optional field",
+		fields:   map[string]string{"user": `has(event.data.user) ? event.data.user.id : "anonymous"`},
+		data:     map[string]interface{}{"other": true},
+		wantData: map[string]interface{}{"user": "anonymous"},
+	}, {
+		name:    "missing field",
+		fields:  map[string]string{"user": "event.data.user.id", "kind": "event.type"},
+		data:    map[string]interface{}{"other": true},
+		wantErr: true,
+	}, {
+		name:    "non JSON data",
+		fields:  map[string]string{"user": "event.data.user.id"},
+		data:    []byte("not json"),
+		wantErr: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := event.New()
+			e.SetID("id")
+			e.SetSource("source")
+			e.SetType("example.type")
+			e.SetExtension("region", "europe")
+			if b, ok := tc.data.([]byte); ok {
+				e.SetData("text/plain", b)
+			} else if err := e.SetData(event.ApplicationJSON, tc.data); err != nil {
+				t.Fatal(err)
+			}
+
+			tr, err := NewTransform(tc.fields)
+			if err != nil {
+				t.Fatalf("unexpected error from NewTransform: %v", err)
+			}
+			if err := tr.Apply(&e); (err != nil) != tc.wantErr {
+				t.Fatalf("Apply got error=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if got := e.DataContentType(); got != event.ApplicationJSON {
+				t.Errorf("data content type got=%q, want=%q", got, event.ApplicationJSON)
+			}
+			var gotData map[string]interface{}
+			if err := json.Unmarshal(e.Data(), &gotData); err != nil {
+				t.Fatalf("transformed data is not JSON: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantData, gotData); diff != "" {
+				t.Errorf("transformed data (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewTransformInvalidExpression(t *testing.T) {
+	for name, expr := range map[string]string{
+		"syntax error":     "event.data.user.",
+		"undeclared":       "data.user.id",
+		"unknown function": "lower(event.type)",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewTransform(map[string]string{"user": expr}); err == nil {
+				t.Errorf("NewTransform(%q) got nil error, want error", expr)
+			}
+		})
+	}
+}
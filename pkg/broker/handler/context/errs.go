@@ -21,4 +21,5 @@ import "errors"
 var (
 	ErrTargetKeyNotPresent = errors.New("target key not present in the context")
 	ErrBrokerKeyNotPresent = errors.New("broker key not present in the context")
+	ErrTargetIDNotPresent  = errors.New("target id not present in the context")
 )
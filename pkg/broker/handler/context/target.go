@@ -35,3 +35,21 @@ func GetTargetKey(ctx context.Context) (string, error) {
 	}
 	return untyped.(string), nil
 }
+
+type targetIDKey struct{}
+
+// WithTargetID sets the ID of the target an event is handled for in the
+// context. It tells apart a target deleted and recreated with the same key.
+func WithTargetID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, targetIDKey{}, id)
+}
+
+// GetTargetID gets the ID of the target an event is handled for from the
+// context.
+func GetTargetID(ctx context.Context) (string, error) {
+	untyped := ctx.Value(targetIDKey{})
+	if untyped == nil {
+		return "", ErrTargetIDNotPresent
+	}
+	return untyped.(string), nil
+}
@@ -34,3 +34,20 @@ func TestTargetKey(t *testing.T) {
 		t.Errorf("GetTargetKey got=%v, want=%v", gotTarget, wantTarget)
 	}
 }
+
+func TestTargetID(t *testing.T) {
+	_, err := GetTargetID(context.Background())
+	if err != ErrTargetIDNotPresent {
+		t.Errorf("error from GetTargetID got=%v, want=%v", err, ErrTargetIDNotPresent)
+	}
+
+	wantID := "my-target-uid"
+	ctx := WithTargetID(context.Background(), wantID)
+	gotID, err := GetTargetID(ctx)
+	if err != nil {
+		t.Errorf("unexpected error from GetTargetID: %v", err)
+	}
+	if gotID != wantID {
+		t.Errorf("GetTargetID got=%v, want=%v", gotID, wantID)
+	}
+}
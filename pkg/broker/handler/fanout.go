@@ -31,9 +31,11 @@ import (
 	"github.com/google/knative-gcp/pkg/broker/config"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/dedup"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/fanout"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -45,9 +47,14 @@ type FanoutPool struct {
 	options *Options
 	targets config.ReadonlyTargets
 	pool    sync.Map
+	// priorityPool holds the handlers of the priority queues of the brokers.
+	priorityPool sync.Map
 
 	// Pubsub client used to pull events from decoupling topics.
 	pubsubClient *pubsub.Client
+	// newLiteSubscriber creates the subscribers of the decoupling topics on
+	// Pub/Sub Lite. It is replaced in tests.
+	newLiteSubscriber pubsublite.SubscriberFn
 	// For sending retry events. We only need a shared client.
 	// And we can set retry topic dynamically.
 	deliverRetryClient ceclient.Client
@@ -55,11 +62,35 @@ type FanoutPool struct {
 	// And we can set target address dynamically.
 	deliverClient *http.Client
 	statsReporter *metrics.DeliveryReporter
+	// lease gates the pulling of the decouple queues, if the region must
+	// hold the lease.
+	lease leaseGate
 }
 
 type fanoutHandlerCache struct {
 	Handler
 	b *config.Broker
+	// priority is true if the handler pulls the priority queue of the broker.
+	priority bool
+	// outstandingBytes is the flow control limit of the subscription of the
+	// handler, zero if it isn't derived from OutstandingBytesBudget.
+	outstandingBytes int
+}
+
+// overBudget returns true if the flow control limit of the handler is more
+// than twice its share of the outstanding bytes budget. Renewing the handlers
+// only then restarts each of them at most once every time the number of
+// subscriptions doubles.
+func (hc *fanoutHandlerCache) overBudget(share int) bool {
+	return share > 0 && hc.outstandingBytes > 2*share
+}
+
+// queue returns the decouple queue of the broker the handler pulls.
+func (hc *fanoutHandlerCache) queue(b *config.Broker) *config.Queue {
+	if hc.priority {
+		return b.GetPriorityDecoupleQueue()
+	}
+	return b.GetDecoupleQueue()
 }
 
 // If somehow the existing handler's setting has deviated from the current broker config,
@@ -70,11 +101,18 @@ func (hc *fanoutHandlerCache) shouldRenew(b *config.Broker) bool {
 	}
 	// If this really happens, it means a data corruption.
 	// The handler creation will fail (which is expected).
-	if b == nil || b.DecoupleQueue == nil {
+	if b == nil || hc.queue(b) == nil {
+		return true
+	}
+	// The broker was deleted and recreated with the same name.
+	if b.Id != hc.b.Id {
 		return true
 	}
-	if b.DecoupleQueue.Topic != hc.b.DecoupleQueue.Topic ||
-		b.DecoupleQueue.Subscription != hc.b.DecoupleQueue.Subscription {
+	q, old := hc.queue(b), hc.queue(hc.b)
+	if q.Topic != old.Topic ||
+		q.Subscription != old.Subscription ||
+		q.Location != old.Location ||
+		q.OrderingEnabled != old.OrderingEnabled {
 		return true
 	}
 	return false
@@ -110,9 +148,11 @@ func NewFanoutPool(
 		targets:            targets,
 		options:            options,
 		pubsubClient:       pubsubClient,
+		newLiteSubscriber:  pubsublite.NewSubscriber,
 		deliverClient:      deliverClient,
 		deliverRetryClient: retryClient,
 		statsReporter:      statsReporter,
+		lease:              leaseGate{lease: options.Lease},
 	}
 	return p, nil
 }
@@ -124,23 +164,76 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 		logging.FromContext(ctx).Error("failed to add tags to context", zap.Error(err))
 	}
 
-	p.pool.Range(func(key, value interface{}) bool {
-		if _, ok := p.targets.GetBrokerByKey(key.(string)); !ok {
+	pull, replayFrom := p.lease.check()
+	if !pull {
+		// Another region holds the lease.
+		stopAll(&p.pool)
+		stopAll(&p.priorityPool)
+		return nil
+	}
+	share := p.outstandingBytesShare()
+	p.syncPool(ctx, &p.pool, false, replayFrom, share)
+	p.syncPool(ctx, &p.priorityPool, true, replayFrom, share)
+	return nil
+}
+
+// outstandingBytesShare returns the flow control limit of each subscription
+// pulled by the pool, the outstanding bytes budget divided by the number of
+// decouple and priority queues of the ready brokers. It is zero if there is
+// no budget.
+func (p *FanoutPool) outstandingBytesShare() int {
+	if p.options.OutstandingBytesBudget <= 0 {
+		return 0
+	}
+	subs := 0
+	p.targets.RangeBrokers(func(b *config.Broker) bool {
+		if b.ServedBy(p.options.BrokerCell) && b.State == config.State_READY {
+			subs++
+			if b.PriorityDecoupleQueue != nil {
+				subs++
+			}
+		}
+		return true
+	})
+	if subs <= 1 {
+		return p.options.OutstandingBytesBudget
+	}
+	return p.options.OutstandingBytesBudget / subs
+}
+
+// syncPool syncs the handlers of the pool with the targets config. The
+// handlers pull the priority queues of the brokers if priority is true, or
+// else their decouple queues. The queues are replayed from replayFrom first
+// if it is not zero. The flow control limit of the subscriptions is share if
+// it is not zero.
+func (p *FanoutPool) syncPool(ctx context.Context, pool *sync.Map, priority bool, replayFrom time.Time, share int) {
+	pool.Range(func(key, value interface{}) bool {
+		b, ok := p.targets.GetBrokerByKey(key.(string))
+		if !ok || !b.ServedBy(p.options.BrokerCell) || (priority && b.PriorityDecoupleQueue == nil) {
 			value.(*fanoutHandlerCache).Stop()
-			p.pool.Delete(key)
+			pool.Delete(key)
 		}
 		return true
 	})
 
 	p.targets.RangeBrokers(func(b *config.Broker) bool {
-		if value, ok := p.pool.Load(b.Key()); ok {
+		// Brokers of other brokercells are handled by their own data plane.
+		if !b.ServedBy(p.options.BrokerCell) {
+			return true
+		}
+		// Most brokers have no priority queue.
+		if priority && b.PriorityDecoupleQueue == nil {
+			return true
+		}
+
+		if value, ok := pool.Load(b.Key()); ok {
 			// Skip if we don't need to renew the handler.
-			if !value.(*fanoutHandlerCache).shouldRenew(b) {
+			if hc := value.(*fanoutHandlerCache); !hc.shouldRenew(b) && !hc.overBudget(share) {
 				return true
 			}
 			// Stop and clean up the old handler before we start a new one.
 			value.(*fanoutHandlerCache).Stop()
-			p.pool.Delete(b.Key())
+			pool.Delete(b.Key())
 		}
 
 		// Don't start the handler if broker is not ready.
@@ -149,14 +242,27 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 			return true
 		}
 
-		sub := p.pubsubClient.Subscription(b.DecoupleQueue.Subscription)
-		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+		queue, settings := b.DecoupleQueue, p.options.PubsubReceiveSettings
+		if priority {
+			queue, settings = b.PriorityDecoupleQueue, *p.options.PriorityPubsubReceiveSettings
+		}
+		if queue.OrderingEnabled && p.options.MaxParallelKeys > 0 {
+			// Pubsub only hands out the next message of an ordering key
+			// after the previous one is acked, so limiting outstanding
+			// messages also limits the number of keys processed in parallel.
+			settings.MaxOutstandingMessages = p.options.MaxParallelKeys
+		}
+		if share > 0 {
+			settings.MaxOutstandingBytes = share
+		}
+		sub := subscribe(p.pubsubClient, p.newLiteSubscriber, queue, settings)
 
 		h := NewHandler(
 			sub,
 			processors.ChainProcessors(
 				&fanout.Processor{MaxConcurrency: p.options.MaxConcurrencyPerEvent, Targets: p.targets},
 				&filter.Processor{Targets: p.targets},
+				&dedup.Processor{Targets: p.targets, StatsReporter: p.statsReporter},
 				&deliver.Processor{
 					DeliverClient:      p.deliverClient,
 					Targets:            p.targets,
@@ -164,29 +270,36 @@ func (p *FanoutPool) SyncOnce(ctx context.Context) error {
 					DeliverRetryClient: p.deliverRetryClient,
 					DeliverTimeout:     p.options.DeliveryTimeout,
 					StatsReporter:      p.statsReporter,
+					PublishStatus:      p.options.PublishStatus,
+					Decrypter:          p.options.Decrypter,
+					Headers:            p.options.DeliveryHeaders,
+					Schemas:            p.options.Schemas,
+
+					CaptureResponseHeaders: p.options.CaptureResponseHeaders,
 				},
 			),
 			p.options.TimeoutPerEvent,
 			p.options.RetryPolicy,
 		)
+		h.ReplayFrom = replayFrom
 		hc := &fanoutHandlerCache{
-			Handler: *h,
-			b:       b,
+			Handler:          *h,
+			b:                b,
+			priority:         priority,
+			outstandingBytes: share,
 		}
 
 		// Start the handler with broker key in context.
 		hc.Start(handlerctx.WithBrokerKey(ctx, b.Key()), func(err error) {
 			// We will anyway get an error because of https://github.com/cloudevents/sdk-go/issues/470
 			if err != nil {
-				logging.FromContext(ctx).Error("handler for broker has stopped with error", zap.String("broker", b.Key()), zap.Error(err))
+				logging.FromContext(ctx).Error("handler for broker has stopped with error", zap.String("broker", b.Key()), zap.Bool("priority", priority), zap.Error(err))
 			} else {
-				logging.FromContext(ctx).Info("handler for broker has stopped", zap.String("broker", b.Key()))
+				logging.FromContext(ctx).Info("handler for broker has stopped", zap.String("broker", b.Key()), zap.Bool("priority", priority))
 			}
 		})
 
-		p.pool.Store(b.Key(), hc)
+		pool.Store(b.Key(), hc)
 		return true
 	})
-
-	return nil
 }
@@ -27,6 +27,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlertesting "github.com/google/knative-gcp/pkg/broker/handler/testing"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
@@ -51,7 +52,7 @@ func TestFanoutWatchAndSync(t *testing.T) {
 	defer helper.Close()
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestFanoutPool(ctx, fanoutPod, fanoutContainer, helper.Targets, helper.PubsubClient)
+	syncPool, err := InitializeTestFanoutPool(ctx, fanoutPod, fanoutContainer, helper.Targets, helper.PubsubClient, WithBrokerCell("cell"))
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -93,6 +94,27 @@ func TestFanoutWatchAndSync(t *testing.T) {
 		assertFanoutHandlers(t, syncPool, helper.Targets)
 	})
 
+	t.Run("priority queues have their own handlers", func(t *testing.T) {
+		helper.Targets.MutateBroker(bs[3].Namespace, bs[3].Name, func(bm config.BrokerMutation) {
+			bm.SetPriorityDecoupleQueue(&config.Queue{Topic: "priority-topic", Subscription: "priority-sub"})
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertFanoutHandlers(t, syncPool, helper.Targets)
+		if _, ok := syncPool.priorityPool.Load(bs[3].Key()); !ok {
+			t.Errorf("no priority handler for broker %s", bs[3].Key())
+		}
+
+		helper.Targets.MutateBroker(bs[3].Namespace, bs[3].Name, func(bm config.BrokerMutation) {
+			bm.SetPriorityDecoupleQueue(nil)
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertFanoutHandlers(t, syncPool, helper.Targets)
+	})
+
 	t.Run("adding and deleting brokers changes handlers", func(t *testing.T) {
 		// Delete old and add new.
 		for i := 0; i < 2; i++ {
@@ -105,6 +127,22 @@ func TestFanoutWatchAndSync(t *testing.T) {
 		assertFanoutHandlers(t, syncPool, helper.Targets)
 	})
 
+	t.Run("brokers of other brokercells have no handlers", func(t *testing.T) {
+		helper.Targets.MutateBroker(bs[2].Namespace, bs[2].Name, func(bm config.BrokerMutation) {
+			bm.SetBrokerCell("other-cell")
+		})
+		helper.Targets.MutateBroker(bs[3].Namespace, bs[3].Name, func(bm config.BrokerMutation) {
+			bm.SetBrokerCell("cell")
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertFanoutHandlers(t, syncPool, helper.Targets)
+		if _, ok := syncPool.pool.Load(bs[2].Key()); ok {
+			t.Errorf("handler for broker %s of another brokercell is still running", bs[2].Key())
+		}
+	})
+
 	t.Run("deleting all brokers deletes all handlers", func(t *testing.T) {
 		// clean up all brokers
 		for _, b := range bs {
@@ -140,6 +178,12 @@ func TestFanoutSyncPoolE2E(t *testing.T) {
 	expectMetrics.AddTrigger(t, t1.Name, wantTags(t1))
 	expectMetrics.AddTrigger(t, t2.Name, wantTags(t2))
 	expectMetrics.AddTrigger(t, t3.Name, wantTags(t3))
+	for _, trigger := range []string{t1.Name, t2.Name, t3.Name} {
+		expectMetrics.AddDeliveryTags(t, trigger, reportertest.Tags{
+			"attempt_class": "first",
+			"retry_count":   "0",
+		})
+	}
 
 	signal := make(chan struct{})
 	syncPool, err := InitializeTestFanoutPool(
@@ -397,15 +441,24 @@ func assertFanoutHandlers(t *testing.T, p *FanoutPool, targets config.Targets) {
 	t.Helper()
 	gotHandlers := make(map[string]bool)
 	wantHandlers := make(map[string]bool)
+	gotPriorityHandlers := make(map[string]bool)
+	wantPriorityHandlers := make(map[string]bool)
 
 	p.pool.Range(func(key, value interface{}) bool {
 		gotHandlers[key.(string)] = true
 		return true
 	})
+	p.priorityPool.Range(func(key, value interface{}) bool {
+		gotPriorityHandlers[key.(string)] = true
+		return true
+	})
 
 	targets.RangeBrokers(func(b *config.Broker) bool {
-		if b.State == config.State_READY {
+		if b.State == config.State_READY && b.ServedBy(p.options.BrokerCell) {
 			wantHandlers[b.Key()] = true
+			if b.PriorityDecoupleQueue != nil {
+				wantPriorityHandlers[b.Key()] = true
+			}
 		}
 		return true
 	})
@@ -413,6 +466,9 @@ func assertFanoutHandlers(t *testing.T, p *FanoutPool, targets config.Targets) {
 	if diff := cmp.Diff(wantHandlers, gotHandlers); diff != "" {
 		t.Errorf("handlers map (-want,+got): %v", diff)
 	}
+	if diff := cmp.Diff(wantPriorityHandlers, gotPriorityHandlers); diff != "" {
+		t.Errorf("priority handlers map (-want,+got): %v", diff)
+	}
 }
 
 func wantTags(target *config.Target) map[string]string {
@@ -425,3 +481,147 @@ func wantTags(target *config.Target) map[string]string {
 		"container_name": fanoutContainer,
 	}
 }
+
+func TestFanoutHandlerCacheShouldRenew(t *testing.T) {
+	queue := &config.Queue{Topic: "topic", Subscription: "sub"}
+	cases := []struct {
+		name string
+		b    *config.Broker
+		want bool
+	}{{
+		name: "same decouple queue",
+		b:    &config.Broker{DecoupleQueue: &config.Queue{Topic: "topic", Subscription: "sub"}},
+	}, {
+		name: "missing decouple queue",
+		b:    &config.Broker{},
+		want: true,
+	}, {
+		name: "subscription changed",
+		b:    &config.Broker{DecoupleQueue: &config.Queue{Topic: "topic", Subscription: "other"}},
+		want: true,
+	}, {
+		name: "ordering enabled",
+		b:    &config.Broker{DecoupleQueue: &config.Queue{Topic: "topic", Subscription: "sub", OrderingEnabled: true}},
+		want: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hc := &fanoutHandlerCache{b: &config.Broker{DecoupleQueue: queue}}
+			hc.alive.Store(true)
+			if got := hc.shouldRenew(tc.b); got != tc.want {
+				t.Errorf("shouldRenew got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPriorityFanoutHandlerCacheShouldRenew(t *testing.T) {
+	b := &config.Broker{
+		DecoupleQueue:         &config.Queue{Topic: "topic", Subscription: "sub"},
+		PriorityDecoupleQueue: &config.Queue{Topic: "priority-topic", Subscription: "priority-sub"},
+	}
+	cases := []struct {
+		name string
+		b    *config.Broker
+		want bool
+	}{{
+		name: "same priority queue",
+		b: &config.Broker{
+			DecoupleQueue:         &config.Queue{Topic: "topic", Subscription: "other"},
+			PriorityDecoupleQueue: &config.Queue{Topic: "priority-topic", Subscription: "priority-sub"},
+		},
+	}, {
+		name: "missing priority queue",
+		b:    &config.Broker{DecoupleQueue: &config.Queue{Topic: "topic", Subscription: "sub"}},
+		want: true,
+	}, {
+		name: "priority subscription changed",
+		b: &config.Broker{
+			DecoupleQueue:         &config.Queue{Topic: "topic", Subscription: "sub"},
+			PriorityDecoupleQueue: &config.Queue{Topic: "priority-topic", Subscription: "other"},
+		},
+		want: true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hc := &fanoutHandlerCache{b: b, priority: true}
+			hc.alive.Store(true)
+			if got := hc.shouldRenew(tc.b); got != tc.want {
+				t.Errorf("shouldRenew got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFanoutOutstandingBytesShare(t *testing.T) {
+	targets := memory.NewEmptyTargets()
+	queue := &config.Queue{Topic: "topic", Subscription: "sub"}
+	targets.MutateBroker("ns", "ready", func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(queue).SetState(config.State_READY)
+	})
+	targets.MutateBroker("ns", "priority", func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(queue).SetPriorityDecoupleQueue(queue).SetState(config.State_READY)
+	})
+	targets.MutateBroker("ns", "unknown", func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(queue)
+	})
+
+	cases := []struct {
+		name   string
+		budget int
+		want   int
+	}{{
+		name: "no budget",
+	}, {
+		name:   "budget divided between the decouple and priority queues",
+		budget: 300,
+		want:   100,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &FanoutPool{targets: targets, options: &Options{OutstandingBytesBudget: tc.budget}}
+			if got := p.outstandingBytesShare(); got != tc.want {
+				t.Errorf("outstandingBytesShare got=%d, want=%d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFanoutHandlerCacheOverBudget(t *testing.T) {
+	cases := []struct {
+		name             string
+		outstandingBytes int
+		share            int
+		want             bool
+	}{{
+		name:             "no budget",
+		outstandingBytes: 100,
+	}, {
+		name:             "share unchanged",
+		outstandingBytes: 100,
+		share:            100,
+	}, {
+		name:             "share halved",
+		outstandingBytes: 100,
+		share:            50,
+	}, {
+		name:             "share less than half",
+		outstandingBytes: 100,
+		share:            49,
+		want:             true,
+	}, {
+		name:             "handler started without a budget",
+		outstandingBytes: 0,
+		share:            50,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hc := &fanoutHandlerCache{outstandingBytes: tc.outstandingBytes}
+			if got := hc.overBudget(tc.share); got != tc.want {
+				t.Errorf("overBudget got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
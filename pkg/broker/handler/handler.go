@@ -25,6 +25,8 @@ import (
 	"cloud.google.com/go/pubsub"
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"go.uber.org/zap"
@@ -35,9 +37,9 @@ import (
 // Handler pulls Pubsub messages as events and processes them
 // with chain of processors.
 type Handler struct {
-	// PubsubEvents is the CloudEvents Pubsub protocol to pull
+	// Subscription is the Pub/Sub or Pub/Sub Lite subscription to pull
 	// messages as events.
-	Subscription *pubsub.Subscription
+	Subscription Subscription
 
 	// Processor is the processor to process events.
 	Processor processors.Interface
@@ -45,10 +47,40 @@ type Handler struct {
 	// Timeout is the timeout for processing each individual event.
 	Timeout time.Duration
 
+	// PriorDeliveries is how many times the events were delivered to their
+	// targets before they reached the subscription. It is used to tag the
+	// delivery metrics with the retry count of the events.
+	PriorDeliveries int
+
+	// Requeue, if set, sends the events that failed to be processed back to
+	// the topic of the subscription instead of nacking them. It is given a
+	// copy of the event and the processing error, and must record the
+	// failure in the event with eventutil.RecordDeliveryFailure. Requeued
+	// events are not processed before their backoff, counted from their
+	// publish time according to their recorded attempts, has elapsed, so that
+	// it doesn't reset when the handler restarts. Until then Cloud Pub/Sub
+	// messages are nacked, and redelivered according to the retry policy of
+	// the subscription. Pub/Sub Lite can't redeliver a single message, so
+	// Pub/Sub Lite messages are held until they are due. If Requeue fails,
+	// the message is nacked.
+	Requeue func(context.Context, *event.Event, error) error
+
+	// ReplayFrom, if not zero, is the time a Cloud Pub/Sub subscription is
+	// seeked back to before it is pulled, e.g. after the lease of the queues
+	// was taken over from another region. It is done once the handler has
+	// started, so that it doesn't hold up the sync of the pool.
+	ReplayFrom time.Time
+
+	// retryPolicy is the backoff of the requeued events.
+	retryPolicy RetryPolicy
 	// retryLimiter limits how fast to retry failed events.
 	retryLimiter workqueue.RateLimiter
 	// delayNack defaults to time.Sleep; could be overridden in test.
 	delayNack func(time.Duration)
+	// hold defaults to sleep; could be overridden in test.
+	hold func(context.Context, time.Duration) bool
+	// now defaults to time.Now; could be overridden in test.
+	now func() time.Time
 	// cancel is function to stop pulling messages.
 	cancel context.CancelFunc
 	alive  atomic.Value
@@ -56,7 +88,7 @@ type Handler struct {
 
 // NewHandler creates a new Handler.
 func NewHandler(
-	sub *pubsub.Subscription,
+	sub Subscription,
 	processor processors.Interface,
 	timeout time.Duration,
 	retryPolicy RetryPolicy,
@@ -65,8 +97,11 @@ func NewHandler(
 		Subscription: sub,
 		Processor:    processor,
 		Timeout:      timeout,
+		retryPolicy:  retryPolicy,
 		retryLimiter: workqueue.NewItemExponentialFailureRateLimiter(retryPolicy.MinBackoff, retryPolicy.MaxBackoff),
 		delayNack:    time.Sleep,
+		hold:         sleep,
+		now:          time.Now,
 	}
 }
 
@@ -79,6 +114,9 @@ func (h *Handler) Start(ctx context.Context, done func(error)) {
 	go func() {
 		// For any reason if inbound is closed, mark alive as false.
 		defer h.alive.Store(false)
+		if sub, ok := h.Subscription.(*pubsub.Subscription); ok {
+			replay(ctx, sub, h.ReplayFrom)
+		}
 		done(h.Subscription.Receive(ctx, h.receive))
 	}()
 }
@@ -94,7 +132,6 @@ func (h *Handler) IsAlive() bool {
 }
 
 func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
-	ctx = metrics.StartEventProcessing(ctx)
 	event, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
 	if isNonRetryable(err) {
 		logEventConversionError(ctx, msg, err, "failed to convert received message to an event, check the msg format")
@@ -109,12 +146,44 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 		return
 	}
 
+	// Events requeued after a failure carry the number of failed attempts.
+	priorFailures, requeued := h.priorFailures(ctx, event)
+	if requeued {
+		if wait := h.requeueDelay(msg, priorFailures); wait > 0 {
+			if !isLiteSubscription(h.Subscription) {
+				// Don't hold the message until the event is due, Pub/Sub
+				// redelivers it after the backoff of the subscription.
+				logging.FromContext(ctx).Debug("requeued event is not due yet; nack", zap.String("eventID", event.ID()), zap.Duration("wait", wait))
+				msg.Nack()
+				return
+			}
+			// A Pub/Sub Lite nack redelivers the whole partition after
+			// reconnecting, and has no backoff.
+			logging.FromContext(ctx).Debug("requeued event is not due yet; hold", zap.String("eventID", event.ID()), zap.Duration("wait", wait))
+			if !h.hold(ctx, wait) {
+				msg.Nack()
+				return
+			}
+		}
+	}
+
+	ctx = metrics.StartEventProcessing(ctx)
+	ctx, err = metrics.AddAttemptTags(ctx, priorFailures+h.deliveryAttempt(msg)-1)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to add attempt tags to context", zap.Error(err))
+	}
+
 	if h.Timeout != 0 {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
 		defer cancel()
 	}
 	if err := h.Processor.Process(ctx, event); err != nil {
+		if h.requeue(ctx, event, err) {
+			h.retryLimiter.Forget(msg.ID)
+			msg.Ack()
+			return
+		}
 		backoffPeriod := h.retryLimiter.When(msg.ID)
 		logging.FromContext(ctx).Error("failed to process event; backoff nack", zap.String("eventID", event.ID()), zap.Duration("backoffPeriod", backoffPeriod), zap.Error(err))
 		h.delayNack(backoffPeriod)
@@ -126,6 +195,71 @@ func (h *Handler) receive(ctx context.Context, msg *pubsub.Message) {
 	msg.Ack()
 }
 
+// deliveryAttempt returns the delivery attempt of the message, starting at 1.
+// Pubsub only reports it for subscriptions with a dead letter policy, so
+// otherwise the failed attempts of the message in this handler are counted.
+func (h *Handler) deliveryAttempt(msg *pubsub.Message) int {
+	if msg.DeliveryAttempt != nil {
+		return *msg.DeliveryAttempt
+	}
+	return h.retryLimiter.NumRequeues(msg.ID) + 1
+}
+
+// priorFailures returns how many times the event failed to be delivered
+// before this message, and whether it was requeued by this handler.
+func (h *Handler) priorFailures(ctx context.Context, event *event.Event) (int, bool) {
+	if h.Requeue == nil {
+		return h.PriorDeliveries, false
+	}
+	res, ok := eventutil.GetDeliveryResult(ctx, event)
+	if !ok || int(res.Attempts) <= h.PriorDeliveries {
+		return h.PriorDeliveries, false
+	}
+	return int(res.Attempts), true
+}
+
+// requeueDelay returns how long to wait before processing a requeued
+// message. The backoff is counted from the publish time of the message, which
+// is when its last attempt failed, so it survives handler restarts.
+func (h *Handler) requeueDelay(msg *pubsub.Message, priorFailures int) time.Duration {
+	maxBackoff := h.retryPolicy.MaxBackoff
+	backoff := h.retryPolicy.MinBackoff
+	for i := h.PriorDeliveries + 1; i < priorFailures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return msg.PublishTime.Add(backoff).Sub(h.now())
+}
+
+// sleep waits for d to elapse. It returns false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// requeue sends the event back to the topic of the subscription. It returns
+// false if the event was not requeued.
+func (h *Handler) requeue(ctx context.Context, e *event.Event, processErr error) bool {
+	if h.Requeue == nil {
+		return false
+	}
+	requeued := e.Clone()
+	if err := h.Requeue(ctx, &requeued, processErr); err != nil {
+		logging.FromContext(ctx).Error("failed to requeue event; backoff nack", zap.String("eventID", e.ID()), zap.Error(err))
+		return false
+	}
+	logging.FromContext(ctx).Debug("failed to process event; requeued", zap.String("eventID", e.ID()), zap.Error(processErr))
+	return true
+}
+
 func isNonRetryable(err error) bool {
 	// The following errors can be returned by ToEvent and are not retryable.
 	// TODO Should binding.ToEvent consolidate them and return the generic ErrCannotConvertToEvent?
@@ -27,10 +27,13 @@ import (
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/api/option"
+	pubsubpb "google.golang.org/genproto/googleapis/pubsub/v1"
 	"google.golang.org/grpc"
 
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 )
 
@@ -157,6 +160,66 @@ func TestHandler(t *testing.T) {
 	})
 }
 
+// seekReactor records the times the subscriptions are seeked to.
+type seekReactor struct {
+	seeks chan time.Time
+}
+
+func (r *seekReactor) React(req interface{}) (bool, interface{}, error) {
+	t, err := ptypes.Timestamp(req.(*pubsubpb.SeekRequest).GetTime())
+	if err != nil {
+		return true, nil, err
+	}
+	r.seeks <- t
+	return true, &pubsubpb.SeekResponse{}, nil
+}
+
+func TestHandlerReplay(t *testing.T) {
+	ctx := context.Background()
+	reactor := &seekReactor{seeks: make(chan time.Time, 1)}
+	srv := pstest.NewServer(pstest.ServerReactorOption{FuncName: "Seek", Reactor: reactor})
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial test pubsub connection: %v", err)
+	}
+	defer conn.Close()
+	c, err := pubsub.NewClient(ctx, testProjectID, option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatalf("failed to create test pubsub client: %v", err)
+	}
+	topic, err := c.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	// The subscription is seeked once the handler started, not before.
+	since := time.Unix(1e9, 0)
+	h := NewHandler(sub, &processors.FakeProcessor{}, time.Second, RetryPolicy{})
+	h.ReplayFrom = since
+	select {
+	case got := <-reactor.seeks:
+		t.Fatalf("subscription seeked to %v before the handler started", got)
+	default:
+	}
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+	select {
+	case got := <-reactor.seeks:
+		if !got.Equal(since) {
+			t.Errorf("subscription seeked to %v, want %v", got, since)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("subscription was not replayed")
+	}
+}
+
 type firstNErrProc struct {
 	processors.BaseProcessor
 	desiredErrCount, currErrCount int
@@ -248,6 +311,114 @@ func TestRetryBackoff(t *testing.T) {
 	}
 }
 
+func TestRequeueBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c, close := testPubsubClient(ctx, t, "test-project")
+	defer close()
+
+	topic, err := c.CreateTopic(ctx, "test-topic")
+	if err != nil {
+		t.Fatalf("failed to create topic: %v", err)
+	}
+	sub, err := c.CreateSubscription(ctx, "test-sub", pubsub.SubscriptionConfig{
+		Topic: topic,
+	})
+	if err != nil {
+		t.Fatalf("failed to create subscription: %v", err)
+	}
+
+	p, err := cepubsub.New(context.Background(),
+		cepubsub.WithClient(c),
+		cepubsub.WithProjectID("test-project"),
+		cepubsub.WithTopicID("test-topic"),
+	)
+	if err != nil {
+		t.Fatalf("failed to create cloudevents pubsub protocol: %v", err)
+	}
+
+	var attempts []int32
+	var nacked int
+	desiredErrCount := 5
+	successSignal := make(chan struct{})
+	processor := &firstNErrProc{
+		desiredErrCount: desiredErrCount,
+		successSignal:   successSignal,
+	}
+	h := NewHandler(sub, processor, time.Second, RetryPolicy{MinBackoff: time.Hour, MaxBackoff: 4 * time.Hour})
+	h.PriorDeliveries = 1
+	h.Requeue = func(ctx context.Context, e *event.Event, err error) error {
+		eventutil.RecordDeliveryFailure(ctx, e, 0, err)
+		res, _ := eventutil.GetDeliveryResult(ctx, e)
+		attempts = append(attempts, res.Attempts)
+		return p.Send(ctx, binding.ToMessage(e))
+	}
+	// Mock the clock so that every requeued event is nacked once before its
+	// backoff elapsed.
+	due := false
+	h.now = func() time.Time {
+		if due = !due; due {
+			nacked++
+			return time.Now()
+		}
+		return time.Now().Add(5 * time.Hour)
+	}
+	h.delayNack = func(d time.Duration) {
+		t.Errorf("unexpected nack delay %v, failed events should be requeued", d)
+	}
+	h.Start(ctx, func(err error) {})
+	defer h.Stop()
+
+	testEvent := event.New()
+	testEvent.SetID("id")
+	testEvent.SetSource("source")
+	testEvent.SetSubject("subject")
+	testEvent.SetType("type")
+	// The first delivery failed before the event reached the subscription.
+	eventutil.RecordDeliveryFailure(ctx, &testEvent, 0, errors.New("first delivery failed"))
+
+	if err := p.Send(ctx, binding.ToMessage(&testEvent)); err != nil {
+		t.Fatalf("failed to seed event to pubsub: %v", err)
+	}
+
+	select {
+	case <-successSignal:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for the event to be processed")
+	}
+	cancel()
+
+	if diff := cmp.Diff([]int32{2, 3, 4, 5, 6}, attempts); diff != "" {
+		t.Errorf("requeued attempts (-want,+got): %v", diff)
+	}
+	// The event isn't delayed before its first retry, every requeued copy
+	// is nacked until it is due.
+	if nacked != len(attempts) {
+		t.Errorf("nacked events got=%d, want=%d", nacked, len(attempts))
+	}
+}
+
+func TestRequeueDelay(t *testing.T) {
+	now := time.Now()
+	h := NewHandler(nil, nil, time.Second, RetryPolicy{MinBackoff: 10 * time.Second, MaxBackoff: 40 * time.Second})
+	h.PriorDeliveries = 1
+	h.now = func() time.Time { return now }
+	msg := &pubsub.Message{PublishTime: now.Add(-10 * time.Second)}
+
+	// The backoff doubles from the publish time up to MaxBackoff.
+	for priorFailures, want := range map[int]time.Duration{
+		2: 0,
+		3: 10 * time.Second,
+		4: 30 * time.Second,
+		5: 30 * time.Second,
+	} {
+		if got := h.requeueDelay(msg, priorFailures); got != want {
+			t.Errorf("requeueDelay(%d) got=%v, want=%v", priorFailures, got, want)
+		}
+	}
+
+}
+
 func nextEventWithTimeout(eventCh <-chan *event.Event) *event.Event {
 	select {
 	case <-time.After(time.Second):
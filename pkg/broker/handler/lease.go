@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/logging"
+)
+
+// Lease tells whether the region of a pool holds the lease of the queues,
+// when brokers are run in several regions. It is implemented by
+// lease.Elector.
+type Lease interface {
+	// Held returns true if the region holds the lease, and the time the
+	// queues should be replayed from if the lease was taken over from
+	// another region.
+	Held() (bool, time.Time)
+}
+
+// leaseGate tracks the lease of a pool across syncs.
+type leaseGate struct {
+	lease Lease
+	held  bool
+}
+
+// check returns true if the pool may pull its queues. If the lease was just
+// acquired, it also returns the time the queues should be replayed from, or
+// else the zero time.
+func (g *leaseGate) check() (bool, time.Time) {
+	if g.lease == nil {
+		return true, time.Time{}
+	}
+	held, since := g.lease.Held()
+	acquired := held && !g.held
+	g.held = held
+	if !acquired {
+		return held, time.Time{}
+	}
+	return true, since
+}
+
+// stopAll stops and removes all the handlers of the pool.
+func stopAll(pool *sync.Map) {
+	pool.Range(func(key, value interface{}) bool {
+		value.(interface{ Stop() }).Stop()
+		pool.Delete(key)
+		return true
+	})
+}
+
+// replay seeks the subscription back to the given time, if not zero, so that
+// the events the previous holder of the lease may not have delivered are
+// pulled again. Pub/Sub Lite subscriptions can't be replayed since they can't
+// seek.
+func replay(ctx context.Context, sub *pubsub.Subscription, since time.Time) {
+	if since.IsZero() {
+		return
+	}
+	if err := sub.SeekToTime(ctx, since); err != nil {
+		logging.FromContext(ctx).Error("Failed to replay the subscription after taking over the lease",
+			zap.String("subscription", sub.ID()), zap.Time("since", since), zap.Error(err))
+	}
+}
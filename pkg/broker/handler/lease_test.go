@@ -0,0 +1,121 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	handlertesting "github.com/google/knative-gcp/pkg/broker/handler/testing"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+type fakeLease struct {
+	mu    sync.Mutex
+	held  bool
+	since time.Time
+}
+
+func (l *fakeLease) Held() (bool, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held, l.since
+}
+
+func (l *fakeLease) set(held bool, since time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held, l.since = held, since
+}
+
+func TestLeaseGate(t *testing.T) {
+	since := time.Unix(1e9, 0)
+	lease := &fakeLease{}
+	g := leaseGate{lease: lease}
+	check := func(wantPull bool, wantReplayFrom time.Time) {
+		t.Helper()
+		pull, replayFrom := g.check()
+		if pull != wantPull || !replayFrom.Equal(wantReplayFrom) {
+			t.Errorf("check() = (%v, %v), want (%v, %v)", pull, replayFrom, wantPull, wantReplayFrom)
+		}
+	}
+
+	check(false, time.Time{})
+	lease.set(true, since)
+	// The queues are only replayed once the lease is acquired.
+	check(true, since)
+	check(true, time.Time{})
+	lease.set(false, time.Time{})
+	check(false, time.Time{})
+
+	if pull, _ := (&leaseGate{}).check(); !pull {
+		t.Error("check() without a lease = false, want true")
+	}
+}
+
+func TestFanoutLease(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	helper, err := handlertesting.NewHelper(ctx, "test-project")
+	if err != nil {
+		t.Fatalf("failed to create pool testing helper: %v", err)
+	}
+	defer helper.Close()
+
+	lease := &fakeLease{}
+	syncPool, err := InitializeTestFanoutPool(ctx, fanoutPod, fanoutContainer, helper.Targets, helper.PubsubClient, WithLease(lease))
+	if err != nil {
+		t.Fatalf("unexpected error from getting sync pool: %v", err)
+	}
+	helper.GenerateBroker(ctx, t, "ns")
+	handlers := func() int {
+		n := 0
+		syncPool.pool.Range(func(interface{}, interface{}) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	if err := syncPool.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() = %v", err)
+	}
+	if n := handlers(); n != 0 {
+		t.Errorf("got %d handlers without the lease, want 0", n)
+	}
+
+	// The subscription is replayed from before the previous holder's last
+	// renewal when the lease is taken over.
+	lease.set(true, time.Now().Add(-time.Minute))
+	if err := syncPool.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() = %v", err)
+	}
+	if n := handlers(); n != 1 {
+		t.Errorf("got %d handlers with the lease, want 1", n)
+	}
+
+	lease.set(false, time.Time{})
+	if err := syncPool.SyncOnce(ctx); err != nil {
+		t.Fatalf("SyncOnce() = %v", err)
+	}
+	if n := handlers(); n != 0 {
+		t.Errorf("got %d handlers after losing the lease, want 0", n)
+	}
+}
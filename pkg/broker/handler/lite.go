@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsublite/pscompat"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+// Subscription pulls the messages of a queue, see pubsub.Subscription.
+type Subscription interface {
+	// Receive calls f with the received messages until ctx is done or an
+	// error occurs.
+	Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error
+}
+
+// subscribe returns the subscription of the queue, pulled with the given
+// receive settings.
+func subscribe(client *pubsub.Client, newLiteSubscriber pubsublite.SubscriberFn, queue *config.Queue, settings pubsub.ReceiveSettings) Subscription {
+	if queue.Location != "" {
+		return newLiteSubscription(queue.Subscription, settings, newLiteSubscriber)
+	}
+	sub := client.Subscription(queue.Subscription)
+	sub.ReceiveSettings = settings
+	return sub
+}
+
+// isLiteSubscription returns whether the subscription is of a Pub/Sub Lite
+// queue.
+func isLiteSubscription(sub Subscription) bool {
+	_, ok := sub.(*liteSubscription)
+	return ok
+}
+
+// liteSubscription is a Subscription of a Pub/Sub Lite queue.
+type liteSubscription struct {
+	path          string
+	settings      pscompat.ReceiveSettings
+	newSubscriber pubsublite.SubscriberFn
+}
+
+func newLiteSubscription(path string, settings pubsub.ReceiveSettings, newSubscriber pubsublite.SubscriberFn) *liteSubscription {
+	s := pscompat.DefaultReceiveSettings
+	if settings.MaxOutstandingMessages > 0 {
+		s.MaxOutstandingMessages = settings.MaxOutstandingMessages
+	}
+	if settings.MaxOutstandingBytes > 0 {
+		s.MaxOutstandingBytes = settings.MaxOutstandingBytes
+	}
+	return &liteSubscription{
+		path:          path,
+		settings:      s,
+		newSubscriber: newSubscriber,
+	}
+}
+
+// Receive implements Subscription, see pubsublite.Receive.
+func (s *liteSubscription) Receive(ctx context.Context, f func(context.Context, *pubsub.Message)) error {
+	return pubsublite.Receive(ctx, s.path, s.settings, s.newSubscriber, f)
+}
+
+// isLiteTopic returns whether the topic of a queue is on Pub/Sub Lite. The
+// topics of Pub/Sub Lite queues are full paths while the ones of Cloud Pub/Sub
+// queues are IDs.
+func isLiteTopic(topic string) bool {
+	return strings.HasPrefix(topic, "projects/")
+}
+
+// liteSender sends the events to Pub/Sub Lite topics and passes the ones to
+// Cloud Pub/Sub topics to the wrapped sender. The topic is taken from the
+// context, see cecontext.WithTopic.
+type liteSender struct {
+	protocol.Sender
+	publishers *pubsublite.Publishers
+}
+
+func newLiteSender(sender protocol.Sender, newPublisher pubsublite.PublisherFn) *liteSender {
+	return &liteSender{
+		Sender:     sender,
+		publishers: pubsublite.NewPublishers(newPublisher),
+	}
+}
+
+// Send implements protocol.Sender.
+func (s *liteSender) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) (err error) {
+	topic := cecontext.TopicFrom(ctx)
+	if !isLiteTopic(topic) {
+		return s.Sender.Send(ctx, in, transformers...)
+	}
+	defer func() { _ = in.Finish(err) }()
+
+	msg := &pubsub.Message{}
+	if err := cepubsub.WritePubSubMessage(ctx, in, msg, transformers...); err != nil {
+		return err
+	}
+	p, err := s.publishers.Get(topic)
+	if err != nil {
+		return err
+	}
+	_, err = p.Publish(ctx, msg).Get(ctx)
+	return err
+}
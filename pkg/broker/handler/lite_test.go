@@ -0,0 +1,225 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsublite/pscompat"
+	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+const (
+	liteTopic = "projects/test-project/locations/us-central1-a/topics/test-topic"
+	liteSub   = "projects/test-project/locations/us-central1-a/subscriptions/test-sub"
+)
+
+// fakeLiteSubscriber hands out one message per Receive and returns err.
+type fakeLiteSubscriber struct {
+	msg *pubsub.Message
+	err error
+}
+
+func (s *fakeLiteSubscriber) Receive(ctx context.Context, f pscompat.MessageReceiverFunc) error {
+	f(ctx, s.msg)
+	return s.err
+}
+
+func TestLiteSubscriptionSettings(t *testing.T) {
+	var settings []pscompat.ReceiveSettings
+	sub := newLiteSubscription(liteSub, pubsub.ReceiveSettings{MaxOutstandingMessages: 10}, func(_ context.Context, path string, s pscompat.ReceiveSettings) (pubsublite.Subscriber, error) {
+		if path != liteSub {
+			t.Errorf("Subscriber path got=%q, want=%q", path, liteSub)
+		}
+		settings = append(settings, s)
+		return &fakeLiteSubscriber{msg: &pubsub.Message{ID: "1"}}, nil
+	})
+	if err := sub.Receive(context.Background(), func(context.Context, *pubsub.Message) {}); err != nil {
+		t.Fatalf("Receive got error: %v", err)
+	}
+	if len(settings) != 1 {
+		t.Fatalf("Subscribers got %d, want 1", len(settings))
+	}
+	if got := settings[0].MaxOutstandingMessages; got != 10 {
+		t.Errorf("MaxOutstandingMessages got=%d, want=10", got)
+	}
+	if got := settings[0].MaxOutstandingBytes; got != pscompat.DefaultReceiveSettings.MaxOutstandingBytes {
+		t.Errorf("MaxOutstandingBytes got=%d, want=%d", got, pscompat.DefaultReceiveSettings.MaxOutstandingBytes)
+	}
+}
+
+func TestLiteSubscriptionHoldsRequeuedEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	// The event failed twice, once before it reached the subscription and
+	// once in this handler, and is requeued for an hour.
+	eventutil.RecordDeliveryFailure(ctx, &e, 0, errors.New("first delivery failed"))
+	eventutil.RecordDeliveryFailure(ctx, &e, 0, errors.New("second delivery failed"))
+	msg := &pubsub.Message{ID: "1", PublishTime: now}
+	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(&e), msg); err != nil {
+		t.Fatal(err)
+	}
+
+	var subscribers int
+	sub := newLiteSubscription(liteSub, pubsub.ReceiveSettings{}, func(context.Context, string, pscompat.ReceiveSettings) (pubsublite.Subscriber, error) {
+		subscribers++
+		return &fakeLiteSubscriber{msg: msg}, nil
+	})
+	processed := make(chan struct{}, 1)
+	h := NewHandler(sub, &firstNErrProc{successSignal: processed}, time.Second, RetryPolicy{MinBackoff: time.Hour, MaxBackoff: 4 * time.Hour})
+	h.PriorDeliveries = 1
+	h.Requeue = func(context.Context, *event.Event, error) error {
+		t.Error("unexpected requeue")
+		return nil
+	}
+	h.now = func() time.Time { return now }
+	var held []time.Duration
+	h.hold = func(_ context.Context, d time.Duration) bool {
+		held = append(held, d)
+		return true
+	}
+
+	if err := h.Subscription.Receive(ctx, h.receive); err != nil {
+		t.Fatalf("Receive got error: %v", err)
+	}
+	// The event is held until it is due instead of nacked, which would
+	// reconnect the subscriber to redeliver the partition.
+	if diff := cmp.Diff([]time.Duration{time.Hour}, held); diff != "" {
+		t.Errorf("Held durations (-want,+got): %v", diff)
+	}
+	select {
+	case <-processed:
+	default:
+		t.Error("Requeued event was not processed once due")
+	}
+	if subscribers != 1 {
+		t.Errorf("Subscribers got %d, want 1", subscribers)
+	}
+}
+
+// fakeLitePublisher publishes to a Cloud Pub/Sub topic standing in for a
+// Pub/Sub Lite topic.
+type fakeLitePublisher struct {
+	*pubsub.Topic
+	err error
+}
+
+func (p *fakeLitePublisher) Error() error {
+	return p.err
+}
+
+// fakeSender records the topics of the events sent to Cloud Pub/Sub.
+type fakeSender struct {
+	topics []string
+}
+
+func (s *fakeSender) Send(ctx context.Context, in binding.Message, _ ...binding.Transformer) error {
+	s.topics = append(s.topics, cecontext.TopicFrom(ctx))
+	return in.Finish(nil)
+}
+
+func TestLiteSender(t *testing.T) {
+	ctx := context.Background()
+	psClient, close := testPubsubClient(ctx, t, testProjectID)
+	defer close()
+	topic, err := psClient.CreateTopic(ctx, testTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := psClient.CreateSubscription(ctx, testSub, pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cloud := &fakeSender{}
+	var publishers []*fakeLitePublisher
+	var paths []string
+	sender := newLiteSender(cloud, func(topic string) (pubsublite.Publisher, error) {
+		paths = append(paths, topic)
+		p := &fakeLitePublisher{Topic: psClient.Topic(testTopic)}
+		publishers = append(publishers, p)
+		return p, nil
+	})
+	defer func() {
+		for _, p := range publishers {
+			p.Stop()
+		}
+	}()
+	client, err := ceclient.New(sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	if res := client.Send(cecontext.WithTopic(ctx, "cloud-topic"), e); !protocol.IsACK(res) {
+		t.Fatalf("Send to Cloud Pub/Sub got %v, want ACK", res)
+	}
+	if res := client.Send(cecontext.WithTopic(ctx, liteTopic), e); !protocol.IsACK(res) {
+		t.Fatalf("Send to Pub/Sub Lite got %v, want ACK", res)
+	}
+	// A failed publisher is replaced.
+	publishers[0].err = errors.New("publisher failed")
+	if res := client.Send(cecontext.WithTopic(ctx, liteTopic), e); !protocol.IsACK(res) {
+		t.Fatalf("Send to Pub/Sub Lite got %v, want ACK", res)
+	}
+
+	if diff := cmp.Diff([]string{"cloud-topic"}, cloud.topics); diff != "" {
+		t.Errorf("Cloud Pub/Sub topics (-want,+got): %v", diff)
+	}
+	if diff := cmp.Diff([]string{liteTopic, liteTopic}, paths); diff != "" {
+		t.Errorf("Publisher topics (-want,+got): %v", diff)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	var received int
+	err = sub.Receive(rctx, func(_ context.Context, msg *pubsub.Message) {
+		msg.Ack()
+		got, err := binding.ToEvent(rctx, cepubsub.NewMessage(msg))
+		if err != nil {
+			t.Errorf("Failed to convert the message: %v", err)
+		} else if got.ID() != e.ID() {
+			t.Errorf("Event ID got=%q, want=%q", got.ID(), e.ID())
+		}
+		if received++; received == 2 {
+			cancel()
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
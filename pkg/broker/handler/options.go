@@ -21,6 +21,12 @@ import (
 	"time"
 
 	"cloud.google.com/go/pubsub"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/broker/status"
 )
 
 var (
@@ -42,6 +48,18 @@ type RetryPolicy struct {
 	MinBackoff, MaxBackoff time.Duration
 }
 
+// DeliveryFailureEvents configures the Kubernetes events emitted on Triggers
+// whose deliveries persistently fail.
+type DeliveryFailureEvents struct {
+	// Recorder is used to emit the events. If nil, no events are emitted.
+	Recorder record.EventRecorder
+	// Threshold is the number of consecutive failed deliveries to a
+	// Trigger before an event is emitted.
+	Threshold int
+	// Interval is the minimum interval between two events for the same Trigger.
+	Interval time.Duration
+}
+
 // Options holds all the options for create handler pool.
 type Options struct {
 	// HandlerConcurrency is the number of goroutines
@@ -56,8 +74,49 @@ type Options struct {
 	DeliveryTimeout time.Duration
 	// PubsubReceiveSettings is the pubsub receive settings.
 	PubsubReceiveSettings pubsub.ReceiveSettings
+	// PriorityPubsubReceiveSettings is the pubsub receive settings of the
+	// priority queues of the brokers. If not set, it is PubsubReceiveSettings
+	// with twice the goroutines and outstanding messages.
+	PriorityPubsubReceiveSettings *pubsub.ReceiveSettings
+	// OutstandingBytesBudget is the max size of the messages outstanding in
+	// all the subscriptions of the pool together. It is divided evenly
+	// between them, overriding their MaxOutstandingBytes. If zero, the
+	// receive settings are used as is.
+	OutstandingBytesBudget int
 	// RetryPolicy defines the retry policy for pubsub messages.
 	RetryPolicy RetryPolicy
+	// MaxParallelKeys is the max number of ordering keys whose events
+	// are processed at the same time for brokers with message ordering
+	// enabled. If zero, PubsubReceiveSettings is used as is.
+	MaxParallelKeys int
+	// DeliveryFailureEvents configures events about persistent delivery failures.
+	DeliveryFailureEvents DeliveryFailureEvents
+	// BrokerCell is the name of the BrokerCell the handlers belong to. Only
+	// the brokers served by the BrokerCell are handled. If empty, all
+	// brokers are handled.
+	BrokerCell string
+	// PublishStatus reports the failures to publish events to the retry
+	// topics. If nil, failures are not reported.
+	PublishStatus *status.Reporter
+	// Decrypter decrypts the data of the events encrypted by the ingress
+	// before they are delivered. If nil, encrypted events are not delivered.
+	Decrypter *encryption.Decrypter
+	// DeliveryHeaders reads the headers set on the requests delivering
+	// events to the targets with delivery headers. If nil, the events of
+	// such targets are not delivered.
+	DeliveryHeaders *headers.Files
+	// Schemas validates the events against the EventSchemas of their types
+	// that are validated on delivery. If nil, events are not validated.
+	Schemas *schema.Registry
+	// CaptureResponseHeaders are the names of the response headers captured
+	// from failed deliveries, e.g. the error IDs of the subscribers, and
+	// attached to the debug logs, trace spans and latency exemplars of the
+	// failures.
+	CaptureResponseHeaders []string
+	// Lease tells whether the region holds the lease of the queues, when
+	// brokers are run in several regions. The queues are only pulled while
+	// the region holds it. If nil, the queues are always pulled.
+	Lease Lease
 }
 
 // NewOptions creates a Options.
@@ -71,6 +130,16 @@ func NewOptions(opts ...Option) (*Options, error) {
 	for _, o := range opts {
 		o(opt)
 	}
+	if opt.PriorityPubsubReceiveSettings == nil {
+		s := opt.PubsubReceiveSettings
+		if s.NumGoroutines > 0 {
+			s.NumGoroutines *= 2
+		}
+		if s.MaxOutstandingMessages > 0 {
+			s.MaxOutstandingMessages *= 2
+		}
+		opt.PriorityPubsubReceiveSettings = &s
+	}
 	return opt, nil
 }
 
@@ -109,6 +178,20 @@ func WithPubsubReceiveSettings(s pubsub.ReceiveSettings) Option {
 	}
 }
 
+// WithPriorityPubsubReceiveSettings sets PriorityPubsubReceiveSettings.
+func WithPriorityPubsubReceiveSettings(s pubsub.ReceiveSettings) Option {
+	return func(o *Options) {
+		o.PriorityPubsubReceiveSettings = &s
+	}
+}
+
+// WithOutstandingBytesBudget sets OutstandingBytesBudget.
+func WithOutstandingBytesBudget(n int) Option {
+	return func(o *Options) {
+		o.OutstandingBytesBudget = n
+	}
+}
+
 // WithDeliveryTimeout sets the DeliveryTimeout.
 func WithDeliveryTimeout(t time.Duration) Option {
 	return func(o *Options) {
@@ -122,3 +205,66 @@ func WithRetryPolicy(r RetryPolicy) Option {
 		o.RetryPolicy = r
 	}
 }
+
+// WithMaxParallelKeys sets MaxParallelKeys.
+func WithMaxParallelKeys(n int) Option {
+	return func(o *Options) {
+		o.MaxParallelKeys = n
+	}
+}
+
+// WithDeliveryFailureEvents sets DeliveryFailureEvents.
+func WithDeliveryFailureEvents(e DeliveryFailureEvents) Option {
+	return func(o *Options) {
+		o.DeliveryFailureEvents = e
+	}
+}
+
+// WithBrokerCell sets BrokerCell.
+func WithBrokerCell(name string) Option {
+	return func(o *Options) {
+		o.BrokerCell = name
+	}
+}
+
+// WithPublishStatus sets PublishStatus.
+func WithPublishStatus(r *status.Reporter) Option {
+	return func(o *Options) {
+		o.PublishStatus = r
+	}
+}
+
+// WithDecrypter sets Decrypter.
+func WithDecrypter(d *encryption.Decrypter) Option {
+	return func(o *Options) {
+		o.Decrypter = d
+	}
+}
+
+// WithDeliveryHeaders sets DeliveryHeaders.
+func WithDeliveryHeaders(h *headers.Files) Option {
+	return func(o *Options) {
+		o.DeliveryHeaders = h
+	}
+}
+
+// WithSchemas sets Schemas.
+func WithSchemas(r *schema.Registry) Option {
+	return func(o *Options) {
+		o.Schemas = r
+	}
+}
+
+// WithCaptureResponseHeaders sets CaptureResponseHeaders.
+func WithCaptureResponseHeaders(names ...string) Option {
+	return func(o *Options) {
+		o.CaptureResponseHeaders = names
+	}
+}
+
+// WithLease sets Lease.
+func WithLease(l Lease) Option {
+	return func(o *Options) {
+		o.Lease = l
+	}
+}
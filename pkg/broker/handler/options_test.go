@@ -22,6 +22,13 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	enctesting "github.com/google/knative-gcp/pkg/broker/encryption/testing"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/schema"
 )
 
 func TestWithHandlerConcurrency(t *testing.T) {
@@ -85,6 +92,39 @@ func TestWithReceiveSettings(t *testing.T) {
 	}
 }
 
+func TestWithPriorityReceiveSettings(t *testing.T) {
+	want := pubsub.ReceiveSettings{
+		NumGoroutines: 50,
+		MaxExtension:  time.Minute,
+	}
+	opt, err := NewOptions(WithPriorityPubsubReceiveSettings(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(&want, opt.PriorityPubsubReceiveSettings); diff != "" {
+		t.Errorf("options PriorityReceiveSettings (-want,+got): %v", diff)
+	}
+}
+
+func TestDefaultPriorityReceiveSettings(t *testing.T) {
+	opt, err := NewOptions(WithPubsubReceiveSettings(pubsub.ReceiveSettings{
+		NumGoroutines:          10,
+		MaxOutstandingMessages: 100,
+		MaxExtension:           time.Minute,
+	}))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	want := &pubsub.ReceiveSettings{
+		NumGoroutines:          20,
+		MaxOutstandingMessages: 200,
+		MaxExtension:           time.Minute,
+	}
+	if diff := cmp.Diff(want, opt.PriorityPubsubReceiveSettings); diff != "" {
+		t.Errorf("options PriorityReceiveSettings (-want,+got): %v", diff)
+	}
+}
+
 func TestWithDeliveryTimeout(t *testing.T) {
 	want := 10 * time.Minute
 	// Always add project id because the default value can only be retrieved on GCE/GKE machines.
@@ -96,3 +136,84 @@ func TestWithDeliveryTimeout(t *testing.T) {
 		t.Errorf("options timeout per event got=%v, want=%v", opt.DeliveryTimeout, want)
 	}
 }
+
+func TestWithMaxParallelKeys(t *testing.T) {
+	want := 10
+	opt, err := NewOptions(WithMaxParallelKeys(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.MaxParallelKeys != want {
+		t.Errorf("options max parallel keys got=%v, want=%v", opt.MaxParallelKeys, want)
+	}
+}
+
+func TestWithDeliveryFailureEvents(t *testing.T) {
+	want := DeliveryFailureEvents{
+		Recorder:  record.NewFakeRecorder(1),
+		Threshold: 5,
+		Interval:  time.Minute,
+	}
+	opt, err := NewOptions(WithDeliveryFailureEvents(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.DeliveryFailureEvents != want {
+		t.Errorf("options delivery failure events got=%+v, want=%+v", opt.DeliveryFailureEvents, want)
+	}
+}
+
+func TestWithBrokerCell(t *testing.T) {
+	want := "cell"
+	opt, err := NewOptions(WithBrokerCell(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.BrokerCell != want {
+		t.Errorf("options broker cell got=%v, want=%v", opt.BrokerCell, want)
+	}
+}
+
+func TestWithDecrypter(t *testing.T) {
+	want := encryption.NewDecrypter(&enctesting.FakeKeyWrapper{})
+	opt, err := NewOptions(WithDecrypter(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.Decrypter != want {
+		t.Errorf("options decrypter got=%v, want=%v", opt.Decrypter, want)
+	}
+}
+
+func TestWithDeliveryHeaders(t *testing.T) {
+	want := headers.NewFiles(headers.DefaultDir)
+	opt, err := NewOptions(WithDeliveryHeaders(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.DeliveryHeaders != want {
+		t.Errorf("options delivery headers got=%v, want=%v", opt.DeliveryHeaders, want)
+	}
+}
+
+func TestWithSchemas(t *testing.T) {
+	want := schema.NewRegistry(zap.NewNop())
+	opt, err := NewOptions(WithSchemas(want))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if opt.Schemas != want {
+		t.Errorf("options schemas got=%v, want=%v", opt.Schemas, want)
+	}
+}
+
+func TestWithCaptureResponseHeaders(t *testing.T) {
+	want := []string{"X-Request-Id", "X-Error-Id"}
+	opt, err := NewOptions(WithCaptureResponseHeaders(want...))
+	if err != nil {
+		t.Errorf("NewOptions got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, opt.CaptureResponseHeaders); diff != "" {
+		t.Errorf("options capture response headers (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dedup provides a processor that drops events which were already
+// delivered to a target.
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// Processor drops events whose source and ID are among the events most
+// recently passed on for the target, and passes all other events to the next
+// processor. The number of events remembered per target is the target's
+// deduplication window; targets without one are not deduplicated.
+//
+// Deduplication is best-effort. The windows are kept in memory, so they are
+// not shared between the fanout pods and are lost when a pod restarts. An
+// event is only remembered once the next processor succeeded, so failed
+// deliveries are not suppressed when Pub/Sub redelivers them, but duplicates
+// that arrive while the first copy is still being delivered are not detected.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// StatsReporter is used to report suppressed duplicates.
+	StatsReporter *metrics.DeliveryReporter
+
+	mux     sync.RWMutex
+	windows map[string]*window
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the event to the next processor unless it's a duplicate of
+// an event recently delivered to the target in the context.
+func (p *Processor) Process(ctx context.Context, event *event.Event) error {
+	tk, err := handlerctx.GetTargetKey(ctx)
+	if err != nil {
+		return err
+	}
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok || target.DeduplicationWindow <= 0 {
+		p.forget(tk)
+		return p.Next().Process(ctx, event)
+	}
+
+	size := int(target.DeduplicationWindow)
+	id := eventID{source: event.Source(), id: event.ID()}
+	if p.seen(tk, size, id) {
+		logging.FromContext(ctx).Debug("dropping duplicate event", zap.String("target", tk), zap.String("event.id", id.id))
+		p.StatsReporter.ReportDuplicateEvent(ctx)
+		return nil
+	}
+	if err := p.Next().Process(ctx, event); err != nil {
+		return err
+	}
+	p.remember(tk, size, id)
+	return nil
+}
+
+// seen returns true if the event is in the window of the target.
+func (p *Processor) seen(tk string, size int, id eventID) bool {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	w, ok := p.windows[tk]
+	if !ok {
+		return false
+	}
+	w.resize(size)
+	return w.touch(id)
+}
+
+// remember adds the event to the window of the target.
+func (p *Processor) remember(tk string, size int, id eventID) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.windows == nil {
+		p.windows = make(map[string]*window)
+	}
+	w, ok := p.windows[tk]
+	if !ok {
+		w = newWindow(size)
+		p.windows[tk] = w
+	}
+	w.resize(size)
+	w.add(id)
+}
+
+// forget drops the window of a target that's gone or no longer deduplicated.
+// Most such targets never had a window, so it is looked up under the read
+// lock first to keep the events of different targets from contending.
+func (p *Processor) forget(tk string) {
+	p.mux.RLock()
+	_, ok := p.windows[tk]
+	p.mux.RUnlock()
+	if !ok {
+		return
+	}
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	delete(p.windows, tk)
+}
+
+// eventID identifies an event. Per the CloudEvents spec, source and id are
+// unique for each distinct event.
+type eventID struct {
+	source string
+	id     string
+}
+
+// window is a bounded set of event IDs which evicts the least recently used
+// ID when full.
+type window struct {
+	size int
+	// order holds the eventIDs, most recently used first.
+	order *list.List
+	ids   map[eventID]*list.Element
+}
+
+func newWindow(size int) *window {
+	return &window{
+		size:  size,
+		order: list.New(),
+		ids:   make(map[eventID]*list.Element),
+	}
+}
+
+// touch returns true and marks the ID as recently used if it's in the window.
+func (w *window) touch(id eventID) bool {
+	e, ok := w.ids[id]
+	if ok {
+		w.order.MoveToFront(e)
+	}
+	return ok
+}
+
+// add adds the ID to the window, evicting the least recently used ID if the
+// window is full.
+func (w *window) add(id eventID) {
+	if w.touch(id) {
+		return
+	}
+	w.ids[id] = w.order.PushFront(id)
+	w.evict()
+}
+
+// resize changes the size of the window, evicting IDs if it shrinks.
+func (w *window) resize(size int) {
+	w.size = size
+	w.evict()
+}
+
+func (w *window) evict() {
+	for w.order.Len() > w.size {
+		e := w.order.Back()
+		w.order.Remove(e)
+		delete(w.ids, e.Value.(eventID))
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dedup
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+	_ "knative.dev/pkg/metrics/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/metrics"
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+// countingProcessor counts the events it receives and returns err from Process.
+type countingProcessor struct {
+	processors.BaseProcessor
+	count int
+	err   error
+}
+
+func (p *countingProcessor) Process(_ context.Context, _ *event.Event) error {
+	p.count++
+	return p.err
+}
+
+func newEvent(source, id string) *event.Event {
+	e := event.New()
+	e.SetSource(source)
+	e.SetID(id)
+	e.SetType("type")
+	return &e
+}
+
+func setup(t *testing.T, window int32) (context.Context, *Processor, *countingProcessor, *config.Target) {
+	t.Helper()
+	reportertest.ResetDeliveryMetrics()
+	reporter, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := &config.Target{
+		Name:                "trigger",
+		Namespace:           "ns",
+		Broker:              "broker",
+		DeduplicationWindow: window,
+	}
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+
+	ctx, err := reporter.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = metrics.AddTargetTags(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	next := &countingProcessor{}
+	p := &Processor{Targets: targets, StatsReporter: reporter}
+	p.WithNext(next)
+	return ctx, p, next, target
+}
+
+func process(ctx context.Context, t *testing.T, p *Processor, e *event.Event) {
+	t.Helper()
+	if err := p.Process(ctx, e); err != nil {
+		t.Fatalf("unexpected error from Process: %v", err)
+	}
+}
+
+func TestNoTargetKey(t *testing.T) {
+	p := &Processor{Targets: memory.NewEmptyTargets()}
+	p.WithNext(&countingProcessor{})
+	if err := p.Process(context.Background(), newEvent("source", "1")); err == nil {
+		t.Error("Process got nil error, want error for missing target key")
+	}
+}
+
+func TestDeduplicationDisabled(t *testing.T) {
+	ctx, p, next, _ := setup(t, 0)
+	process(ctx, t, p, newEvent("source", "1"))
+	process(ctx, t, p, newEvent("source", "1"))
+	if next.count != 2 {
+		t.Errorf("delivered events got=%d, want=2", next.count)
+	}
+}
+
+func TestDropDuplicates(t *testing.T) {
+	ctx, p, next, _ := setup(t, 2)
+
+	process(ctx, t, p, newEvent("source", "1"))
+	process(ctx, t, p, newEvent("source", "1"))
+	// Same ID from another source is a different event.
+	process(ctx, t, p, newEvent("other", "1"))
+	if next.count != 2 {
+		t.Errorf("delivered events got=%d, want=2", next.count)
+	}
+
+	metricstest.CheckCountData(t, "event_duplicate_count", map[string]string{
+		metricskey.LabelNamespaceName: "ns",
+		metricskey.LabelBrokerName:    "broker",
+		metricskey.LabelTriggerName:   "trigger",
+		metricskey.LabelFilterType:    "any",
+		metricskey.PodName:            "pod",
+		metricskey.ContainerName:      "container",
+	}, 1)
+}
+
+func TestEvictLeastRecentlyUsed(t *testing.T) {
+	ctx, p, next, _ := setup(t, 2)
+
+	process(ctx, t, p, newEvent("source", "1"))
+	process(ctx, t, p, newEvent("source", "2"))
+	// Seeing 1 again makes 2 the least recently used.
+	process(ctx, t, p, newEvent("source", "1"))
+	process(ctx, t, p, newEvent("source", "3"))
+	if next.count != 3 {
+		t.Fatalf("delivered events got=%d, want=3", next.count)
+	}
+
+	// 2 was evicted, 1 and 3 are still in the window.
+	process(ctx, t, p, newEvent("source", "2"))
+	process(ctx, t, p, newEvent("source", "3"))
+	if next.count != 4 {
+		t.Errorf("delivered events got=%d, want=4", next.count)
+	}
+}
+
+func TestFailedDeliveryIsNotRemembered(t *testing.T) {
+	ctx, p, next, _ := setup(t, 10)
+
+	next.err = errors.New("delivery failed")
+	if err := p.Process(ctx, newEvent("source", "1")); err != next.err {
+		t.Fatalf("Process error got=%v, want=%v", err, next.err)
+	}
+	next.err = nil
+	process(ctx, t, p, newEvent("source", "1"))
+	if next.count != 2 {
+		t.Errorf("delivered events got=%d, want=2", next.count)
+	}
+}
+
+func TestWindowChanges(t *testing.T) {
+	ctx, p, next, target := setup(t, 3)
+
+	process(ctx, t, p, newEvent("source", "1"))
+	process(ctx, t, p, newEvent("source", "2"))
+	process(ctx, t, p, newEvent("source", "3"))
+
+	// Shrinking the window evicts the least recently used events.
+	p.Targets.(config.Targets).MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		target.DeduplicationWindow = 1
+		bm.UpsertTargets(target)
+	})
+	process(ctx, t, p, newEvent("source", "2"))
+	if next.count != 4 {
+		t.Fatalf("delivered events got=%d, want=4", next.count)
+	}
+
+	// Disabling deduplication forgets the window.
+	p.Targets.(config.Targets).MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		target.DeduplicationWindow = 0
+		bm.UpsertTargets(target)
+	})
+	process(ctx, t, p, newEvent("source", "2"))
+	if next.count != 5 {
+		t.Fatalf("delivered events got=%d, want=5", next.count)
+	}
+	if len(p.windows) != 0 {
+		t.Errorf("windows got=%d, want=0", len(p.windows))
+	}
+}
@@ -18,8 +18,12 @@ package deliver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
@@ -27,18 +31,36 @@ import (
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/event"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/metric/metricdata"
 	"go.opencensus.io/trace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/eventing/pkg/logging"
 
+	"github.com/google/knative-gcp/pkg/broker/compression"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
-const defaultEventHopsLimit int32 = 255
+const (
+	defaultEventHopsLimit int32 = 255
+
+	// maxCapturedHeaderLength is the length captured response header values
+	// are truncated to, so that large headers don't flood the logs.
+	maxCapturedHeaderLength = 256
+
+	// capturedHeaderPrefix prefixes the names of the captured response
+	// headers in the span attributes and exemplar attachments.
+	capturedHeaderPrefix = "response.header."
+)
 
 // Processor delivers events based on the broker/target in the context.
 type Processor struct {
@@ -64,6 +86,43 @@ type Processor struct {
 
 	// StatsReporter is used to report delivery metrics.
 	StatsReporter *metrics.DeliveryReporter
+
+	// PublishStatus reports the failures to send events to the retry
+	// topic. If nil, failures are not reported.
+	PublishStatus *status.Reporter
+
+	// Decrypter decrypts the data of the events encrypted by the ingress
+	// before they are delivered. The events sent to the retry topic stay
+	// encrypted. If nil, encrypted events are not delivered.
+	Decrypter *encryption.Decrypter
+
+	// Headers reads the headers set on the requests delivering events to the
+	// targets with delivery headers. If nil, the events of such targets are
+	// not delivered.
+	Headers *headers.Files
+
+	// Schemas validates the events against the EventSchemas of their types
+	// that are validated on delivery, and the results are counted in the
+	// delivery metrics. Events are delivered whether they conform or not.
+	// If nil, events are not validated.
+	Schemas *schema.Registry
+
+	// CaptureResponseHeaders are the names of the response headers captured
+	// from failed deliveries, e.g. the error IDs of the subscribers. They are
+	// attached to the debug logs and the trace spans of the failures, and to
+	// the exemplars of their dispatch times.
+	CaptureResponseHeaders []string
+
+	// transforms caches the compiled transform of each target, keyed by
+	// target key. The entries of the targets that were deleted or no longer
+	// have a transform are removed whenever a transform is compiled.
+	transforms sync.Map
+}
+
+// compiledTransform is a transform compiled from the fields of a target.
+type compiledTransform struct {
+	fields    map[string]string
+	transform *eventutil.Transform
 }
 
 var _ processors.Interface = (*Processor)(nil)
@@ -88,9 +147,18 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	if !ok {
 		// If the target no longer exists, then there is nothing to process.
 		logging.FromContext(ctx).Warn("target no longer exist in the config", zap.String("target", tk))
+		p.transforms.Delete(tk)
+		return nil
+	}
+	if id, err := handlerctx.GetTargetID(ctx); err == nil && id != target.Id {
+		// The target the event was handled for was deleted, and another one
+		// was created with the same name. Its events must not be delivered to
+		// the new one.
+		logging.FromContext(ctx).Warn("target was recreated since the event was handled, dropping it",
+			zap.String("target", tk), zap.String("target.id", id), zap.String("current.id", target.Id),
+			zap.Int64("current.generation", target.Generation), zap.String("event.id", event.ID()))
 		return nil
 	}
-
 	// Hops is a broker local counter so remove any hops value before forwarding.
 	// Do not modify the original event as we need to send the original
 	// event to retry queue on failure.
@@ -99,6 +167,68 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	eventutil.UpdateRemainingHops(ctx, &copy, defaultEventHopsLimit)
 	hops, _ := eventutil.GetRemainingHops(ctx, &copy)
 	eventutil.DeleteRemainingHops(ctx, &copy)
+	// A retried event records the subscribers that already received it.
+	delivered := eventutil.GetDeliveredSubscribers(event)
+	eventutil.DeleteDeliveredSubscribers(&copy)
+	if err := p.decrypt(ctx, broker, &copy); err != nil {
+		if errors.Is(err, encryption.ErrCorrupted) {
+			// The event cannot be decrypted however often it is retried.
+			if target.DeadLetterAddress == "" {
+				logging.FromContext(ctx).Error("failed to decrypt event, dropping it",
+					zap.String("target", tk), zap.String("event.id", event.ID()), zap.Error(err))
+				return nil
+			}
+			logging.FromContext(ctx).Error("failed to decrypt event, sending it to the dead letter sink",
+				zap.String("target", tk), zap.String("event.id", event.ID()), zap.Error(err))
+			return p.deadLetter(ctx, broker, target, event, &copy, eventutil.DeadLetterReasonUndecryptable)
+		}
+		return p.deliveryFailed(ctx, broker, target, event, nil, err)
+	}
+	if err := compression.Decompress(&copy); err != nil {
+		// The event cannot be decompressed however often it is retried.
+		if target.DeadLetterAddress == "" {
+			logging.FromContext(ctx).Error("failed to decompress event, dropping it",
+				zap.String("target", tk), zap.String("event.id", event.ID()), zap.Error(err))
+			return nil
+		}
+		logging.FromContext(ctx).Error("failed to decompress event, sending it to the dead letter sink",
+			zap.String("target", tk), zap.String("event.id", event.ID()), zap.Error(err))
+		return p.deadLetter(ctx, broker, target, event, &copy, eventutil.DeadLetterReasonUndecompressable)
+	}
+	if expired(target, event) {
+		// Delivering the event late is pointless for the subscriber, and
+		// retrying it would only make it older.
+		p.StatsReporter.ReportExpiredEvent(ctx)
+		if target.DeadLetterAddress == "" {
+			logging.FromContext(ctx).Warn("event exceeded the max age of the target, dropping it",
+				zap.String("target", tk), zap.String("event.id", event.ID()), zap.Time("event.time", event.Time()),
+				zap.Int64("target.maxAgeSeconds", target.MaxAgeSeconds))
+			return nil
+		}
+		logging.FromContext(ctx).Warn("event exceeded the max age of the target, sending it to the dead letter sink",
+			zap.String("target", tk), zap.String("event.id", event.ID()), zap.Time("event.time", event.Time()),
+			zap.Int64("target.maxAgeSeconds", target.MaxAgeSeconds))
+		return p.deadLetter(ctx, broker, target, event, &copy, eventutil.DeadLetterReasonExpired)
+	}
+	if v := p.Schemas.ValidateOnDelivery(broker.Namespace, &copy); v != nil && p.StatsReporter != nil {
+		p.StatsReporter.ReportSchemaValidation(ctx, copy.Type(), v.Result())
+	}
+	for name, value := range target.CeOverrides {
+		copy.SetExtension(name, value)
+	}
+	if len(target.Transform) > 0 {
+		t, err := p.transform(target)
+		if err == nil {
+			err = t.Apply(&copy)
+		}
+		if err != nil {
+			// The event is retried like any failed delivery, so that it's not
+			// lost to a mistake in the transform, e.g. after it's fixed.
+			logging.FromContext(ctx).Error("failed to transform event",
+				zap.String("target", tk), zap.String("event.id", event.ID()), zap.Error(err))
+			return p.deliveryFailed(ctx, broker, target, event, nil, fmt.Errorf("failed to transform event: %w", err))
+		}
+	}
 
 	p.StatsReporter.FinishEventProcessing(ctx)
 
@@ -110,22 +240,223 @@ func (p *Processor) Process(ctx context.Context, event *event.Event) error {
 	}
 
 	// Forward the event copy that has hops removed.
-	if err := p.deliver(dctx, target, broker, (*binding.EventMessage)(&copy), hops); err != nil {
-		if !p.RetryOnFailure {
-			return err
-		}
-
-		logging.FromContext(ctx).Warn("target delivery failed", zap.String("target", tk), zap.Error(err))
-		return p.sendToRetryTopic(ctx, target, event)
+	if newlyDelivered, err := p.deliverToSubscribers(dctx, target, broker, &copy, hops, delivered); err != nil {
+		return p.deliveryFailed(ctx, broker, target, event, newlyDelivered, err)
 	}
 	// For post-delivery processing.
 	return p.Next().Process(ctx, event)
 }
 
-// deliver delivers msg to target and sends the target's reply to the broker ingress.
-func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.Broker, msg binding.Message, hops int32) error {
+// deliveryFailed handles the failure to deliver the original event to the
+// target, either by sending it to the retry topic or by returning the error
+// so that the message is nacked. delivered are the subscriber addresses that
+// received the event in this attempt, which the retry skips.
+func (p *Processor) deliveryFailed(ctx context.Context, broker *config.Broker, target *config.Target, event *event.Event, delivered []string, err error) error {
+	if h := ResponseHeaders(err); len(h) > 0 {
+		logging.FromContext(ctx).Debug("target responded to the failed delivery with captured headers",
+			zap.String("target", target.Key()), zap.String("event.id", event.ID()),
+			zap.Int("response.code", StatusCode(err)), zap.Any("response.headers", h))
+	}
+	if !p.RetryOnFailure {
+		return err
+	}
+	if orderedDelivery(broker, target) {
+		// Sending the event to the retry topic would let later events with
+		// the same ordering key overtake it. Return the error instead so
+		// the message is nacked and redelivered in order.
+		logging.FromContext(ctx).Warn("ordered target delivery failed", zap.String("target", target.Key()), zap.Error(err))
+		return err
+	}
+
+	logging.FromContext(ctx).Warn("target delivery failed", zap.String("target", target.Key()), zap.Error(err))
+	return p.sendToRetryTopic(ctx, target, event, delivered, err)
+}
+
+// decrypt decrypts the data of the event if the ingress encrypted it.
+func (p *Processor) decrypt(ctx context.Context, broker *config.Broker, e *event.Event) error {
+	if p.Decrypter != nil {
+		return p.Decrypter.Decrypt(ctx, broker, e)
+	}
+	if _, ok := e.Extensions()[encryption.DataKeyExtension]; ok {
+		return errors.New("event is encrypted but decryption is not enabled")
+	}
+	return nil
+}
+
+// transform returns the compiled transform of the target, compiling it again
+// if the target's transform changed.
+func (p *Processor) transform(target *config.Target) (*eventutil.Transform, error) {
+	key := target.Key()
+	if v, ok := p.transforms.Load(key); ok {
+		if c := v.(*compiledTransform); reflect.DeepEqual(c.fields, target.Transform) {
+			return c.transform, nil
+		}
+	}
+	t, err := eventutil.NewTransform(target.Transform)
+	if err != nil {
+		return nil, err
+	}
+	p.pruneTransforms()
+	p.transforms.Store(key, &compiledTransform{fields: target.Transform, transform: t})
+	return t, nil
+}
+
+// pruneTransforms removes the compiled transforms of the targets that were
+// deleted or no longer have a transform.
+func (p *Processor) pruneTransforms() {
+	p.transforms.Range(func(key, _ interface{}) bool {
+		if t, ok := p.Targets.GetTargetByKey(key.(string)); !ok || len(t.Transform) == 0 {
+			p.transforms.Delete(key)
+		}
+		return true
+	})
+}
+
+// expired returns true if the target has a max age and the event's time is
+// further in the past. Events without a time never expire.
+func expired(target *config.Target, event *event.Event) bool {
+	if target.MaxAgeSeconds <= 0 || event.Time().IsZero() {
+		return false
+	}
+	return time.Since(event.Time()) > time.Duration(target.MaxAgeSeconds)*time.Second
+}
+
+// orderedDelivery returns true if events to the target must be delivered in
+// the order of their ordering keys.
+func orderedDelivery(broker *config.Broker, target *config.Target) bool {
+	return target.OrderedDelivery && broker.DecoupleQueue != nil && broker.DecoupleQueue.OrderingEnabled
+}
+
+// statusError is returned by deliver when the target responds with a non-2xx
+// status code.
+type statusError struct {
+	statusCode int
+	// headers are the captured response headers, keyed by canonical name.
+	headers map[string]string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("event delivery failed: HTTP status code %d", e.statusCode)
+}
+
+// StatusCode returns the HTTP status code of the failed delivery err is
+// returned for, zero if the target didn't respond.
+func StatusCode(err error) int {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.statusCode
+	}
+	return 0
+}
+
+// ResponseHeaders returns the response headers captured from the failed
+// delivery err is returned for, nil if none were.
+func ResponseHeaders(err error) map[string]string {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.headers
+	}
+	return nil
+}
+
+// captureResponseHeaders returns the values of the CaptureResponseHeaders set
+// in header, keyed by canonical name.
+func (p *Processor) captureResponseHeaders(header http.Header) map[string]string {
+	var captured map[string]string
+	for _, name := range p.CaptureResponseHeaders {
+		name = http.CanonicalHeaderKey(name)
+		values, ok := header[name]
+		if !ok {
+			continue
+		}
+		v := strings.Join(values, ",")
+		if len(v) > maxCapturedHeaderLength {
+			v = v[:maxCapturedHeaderLength]
+		}
+		if captured == nil {
+			captured = make(map[string]string)
+		}
+		captured[name] = v
+	}
+	return captured
+}
+
+// failureAttachments returns the exemplar attachments of a failed delivery:
+// the captured response headers and the context of the span, if sampled.
+func failureAttachments(ctx context.Context, captured map[string]string) metricdata.Attachments {
+	span := trace.FromContext(ctx)
+	sampled := span != nil && span.SpanContext().IsSampled()
+	if len(captured) == 0 && !sampled {
+		return nil
+	}
+	attachments := make(metricdata.Attachments, len(captured)+1)
+	for name, v := range captured {
+		attachments[capturedHeaderPrefix+name] = v
+	}
+	if sampled {
+		attachments[metricdata.AttachmentKeySpanContext] = span.SpanContext()
+	}
+	return attachments
+}
+
+// deliverToSubscribers delivers the event to the address of the target and to
+// the addresses of its additional subscribers, either in parallel or one after
+// the other, skipping the addresses in delivered that already received it. It
+// fails if any delivery fails, returning the errors of all the failed
+// deliveries along with the addresses that received the event.
+func (p *Processor) deliverToSubscribers(ctx context.Context, target *config.Target, broker *config.Broker, e *event.Event, hops int32, delivered map[string]bool) ([]string, error) {
+	if len(target.SubscriberAddresses) == 0 {
+		return nil, p.deliver(ctx, target, broker, target.Address, (*binding.EventMessage)(e), hops)
+	}
+	var addresses []string
+	for _, address := range append([]string{target.Address}, target.SubscriberAddresses...) {
+		if !delivered[address] {
+			addresses = append(addresses, address)
+		}
+	}
+	deliverTo := func(address string, e *event.Event) error {
+		if err := p.deliver(ctx, target, broker, address, (*binding.EventMessage)(e), hops); err != nil {
+			return fmt.Errorf("delivery to %s failed: %w", address, err)
+		}
+		return nil
+	}
+
+	if target.SequentialSubscribers {
+		for i, address := range addresses {
+			if err := deliverTo(address, e); err != nil {
+				return addresses[:i], err
+			}
+		}
+		return addresses, nil
+	}
+
+	errs := make([]error, len(addresses))
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		// Each delivery reads its own copy of the event.
+		copy := e.Clone()
+		go func(i int, address string) {
+			defer wg.Done()
+			errs[i] = deliverTo(address, &copy)
+		}(i, address)
+	}
+	wg.Wait()
+	var succeeded []string
+	for i, address := range addresses {
+		if errs[i] == nil {
+			succeeded = append(succeeded, address)
+		}
+	}
+	return succeeded, multierr.Combine(errs...)
+}
+
+// deliver delivers msg to address, which is either the address of the target
+// or of one of its additional subscribers, and sends the reply to the broker
+// ingress.
+func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *config.Broker, address string, msg binding.Message, hops int32) error {
 	startTime := time.Now()
-	resp, err := p.sendMsg(ctx, target.Address, msg)
+	resp, err := p.sendToTarget(ctx, target, address, msg)
 	if err != nil {
 		return err
 	}
@@ -135,10 +466,19 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 		}
 	}()
 
-	p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime), resp.StatusCode)
 	if resp.StatusCode/100 != 2 {
-		return fmt.Errorf("event delivery failed: HTTP status code %d", resp.StatusCode)
+		captured := p.captureResponseHeaders(resp.Header)
+		p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime), resp.StatusCode, failureAttachments(ctx, captured))
+		if span := trace.FromContext(ctx); span.IsRecordingEvents() && len(captured) > 0 {
+			attrs := make([]trace.Attribute, 0, len(captured))
+			for name, v := range captured {
+				attrs = append(attrs, trace.StringAttribute(capturedHeaderPrefix+name, v))
+			}
+			span.Annotate(attrs, "Event delivery failed")
+		}
+		return &statusError{statusCode: resp.StatusCode, headers: captured}
 	}
+	p.StatsReporter.ReportEventDispatchTime(ctx, time.Since(startTime), resp.StatusCode, nil)
 
 	respMsg := cehttp.NewMessageFromHttpResponse(resp)
 	if respMsg.ReadEncoding() == binding.EncodingUnknown {
@@ -172,18 +512,105 @@ func (p *Processor) deliver(ctx context.Context, target *config.Target, broker *
 		return nil
 	}
 
+	header, err := p.replyHeaders(broker)
+	if err != nil {
+		return fmt.Errorf("failed to send the reply to the broker: %w", err)
+	}
 	// Attach the previous hops for the reply.
-	replyResp, err := p.sendMsg(ctx, broker.Address, respMsg, eventutil.SetRemainingHopsTransformer(hops))
+	replyResp, err := p.sendMsg(ctx, broker.Address, header, respMsg, eventutil.SetRemainingHopsTransformer(hops))
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to send the reply to the broker: %w", err)
 	}
 	if err := replyResp.Body.Close(); err != nil {
 		logging.FromContext(ctx).Warn("failed to close reply response body", zap.Error(err))
 	}
+	// The reply is lost if the ingress doesn't accept it, so the delivery
+	// fails and is retried.
+	if replyResp.StatusCode/100 != 2 {
+		return fmt.Errorf("the broker rejected the reply with status code %d", replyResp.StatusCode)
+	}
 	return nil
 }
 
-func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
+// deadLetter sends copy, the copy of the event prepared for delivery, to the
+// dead letter sink of the target along with the reason it is not delivered.
+// The event is retried if the dead letter sink does not accept it.
+func (p *Processor) deadLetter(ctx context.Context, broker *config.Broker, target *config.Target, event, copy *event.Event, reason string) error {
+	eventutil.SetDeadLetterReason(copy, reason)
+	if err := p.sendToDeadLetterSink(ctx, target, copy); err != nil {
+		return p.deliveryFailed(ctx, broker, target, event, nil, err)
+	}
+	return nil
+}
+
+// sendToDeadLetterSink sends the event to the dead letter sink of the target.
+// The sink's replies are ignored.
+func (p *Processor) sendToDeadLetterSink(ctx context.Context, target *config.Target, e *event.Event) error {
+	if p.DeliverTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.DeliverTimeout)
+		defer cancel()
+	}
+	resp, err := p.sendMsg(ctx, target.DeadLetterAddress, nil, (*binding.EventMessage)(e))
+	if err != nil {
+		return fmt.Errorf("delivery to the dead letter sink failed: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		logging.FromContext(ctx).Warn("failed to close dead letter sink response body", zap.Error(err))
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("delivery to the dead letter sink failed: %w", &statusError{statusCode: resp.StatusCode})
+	}
+	return nil
+}
+
+// sendToTarget sends msg to address with the delivery headers of the target.
+// Messages to the address of the target go to its direct address if it has
+// one, falling back to its address if the direct address cannot be reached,
+// e.g. because the revision behind it has been replaced or scaled to zero.
+func (p *Processor) sendToTarget(ctx context.Context, target *config.Target, address string, msg binding.Message) (*http.Response, error) {
+	header, err := p.deliveryHeaders(target)
+	if err != nil {
+		return nil, err
+	}
+	if address == target.Address && target.DirectAddress != "" {
+		resp, err := p.sendMsg(ctx, target.DirectAddress, header, msg)
+		if err == nil || ctx.Err() != nil {
+			return resp, err
+		}
+		logging.FromContext(ctx).Debug("direct delivery failed, falling back to the target address",
+			zap.String("target", target.Name), zap.Error(err))
+	}
+	return p.sendMsg(ctx, address, header, msg)
+}
+
+// deliveryHeaders returns the headers set on the requests delivering events
+// to the target, if it has any.
+func (p *Processor) deliveryHeaders(target *config.Target) (http.Header, error) {
+	if target.DeliveryHeadersKey == "" {
+		return nil, nil
+	}
+	if p.Headers == nil {
+		return nil, errors.New("delivery headers are not available")
+	}
+	return p.Headers.Get(target.DeliveryHeadersKey)
+}
+
+// replyHeaders returns the headers set on the replies sent back to the broker,
+// if it has any, e.g. the bearer token of brokers requiring one.
+func (p *Processor) replyHeaders(broker *config.Broker) (http.Header, error) {
+	if broker.ReplyHeadersKey == "" {
+		return nil, nil
+	}
+	if p.Headers == nil {
+		return nil, errors.New("reply headers are not available")
+	}
+	return p.Headers.Get(broker.ReplyHeadersKey)
+}
+
+// sendMsg sends msg to address, setting header on the request on top of the
+// CloudEvents headers.
+func (p *Processor) sendMsg(ctx context.Context, address string, header http.Header, msg binding.Message, transformers ...binding.Transformer) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, nil)
 	if err != nil {
 		return nil, err
@@ -191,13 +618,27 @@ func (p *Processor) sendMsg(ctx context.Context, address string, msg binding.Mes
 	if err := cehttp.WriteRequest(ctx, msg, req, transformers...); err != nil {
 		return nil, err
 	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
 	return p.DeliverClient.Do(req)
 }
 
-func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event) error {
+// sendToRetryTopic sends the event to the target's retry topic, recording the
+// delivery failure in the event extensions so that the result of the last
+// attempt is visible to the retry and dead letter consumers. The subscriber
+// addresses in delivered are recorded too, so that the retry skips them.
+func (p *Processor) sendToRetryTopic(ctx context.Context, target *config.Target, event *event.Event, delivered []string, deliveryErr error) error {
+	retryEvent := event.Clone()
+	eventutil.RecordDeliveryFailure(ctx, &retryEvent, StatusCode(deliveryErr), deliveryErr)
+	eventutil.RecordDeliveredSubscribers(&retryEvent, delivered)
+
 	pctx := cecontext.WithTopic(ctx, target.RetryQueue.Topic)
-	if err := p.DeliverRetryClient.Send(pctx, *event); err != nil {
+	broker := types.NamespacedName{Namespace: target.Namespace, Name: target.Broker}
+	if err := p.DeliverRetryClient.Send(pctx, retryEvent); err != nil {
+		p.PublishStatus.Report(broker, status.RetryQueue, err)
 		return fmt.Errorf("failed to send event to retry topic: %w", err)
 	}
+	p.PublishStatus.Report(broker, status.RetryQueue, nil)
 	return nil
 }
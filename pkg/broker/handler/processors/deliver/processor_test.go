@@ -24,7 +24,11 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -38,17 +42,27 @@ import (
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/google/go-cmp/cmp"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats/view"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
 
+	"github.com/google/knative-gcp/pkg/broker/compression"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	enctesting "github.com/google/knative-gcp/pkg/broker/encryption/testing"
 	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/broker/schema"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 
@@ -205,10 +219,28 @@ func TestDeliverFailure(t *testing.T) {
 		withRetry     bool
 		withRespDelay time.Duration
 		failRetry     bool
+		// brokerOrdering enables ordering on the broker decouple queue.
+		brokerOrdering bool
+		// targetOrdered opts the target into ordered delivery.
+		targetOrdered bool
 		wantErr       bool
 	}{{
 		name:    "delivery error no retry",
 		wantErr: true,
+	}, {
+		name:           "ordered delivery error skips retry",
+		withRetry:      true,
+		brokerOrdering: true,
+		targetOrdered:  true,
+		wantErr:        true,
+	}, {
+		name:          "ordered target without broker ordering retry success",
+		withRetry:     true,
+		targetOrdered: true,
+	}, {
+		name:           "unordered target with broker ordering retry success",
+		withRetry:      true,
+		brokerOrdering: true,
 	}, {
 		name:      "delivery error retry success",
 		withRetry: true,
@@ -272,9 +304,11 @@ func TestDeliverFailure(t *testing.T) {
 				RetryQueue: &config.Queue{
 					Topic: "test-retry-topic",
 				},
+				OrderedDelivery: tc.targetOrdered,
 			}
 			testTargets := memory.NewEmptyTargets()
 			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetDecoupleQueue(&config.Queue{OrderingEnabled: tc.brokerOrdering})
 				bm.UpsertTargets(target)
 			})
 			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
@@ -330,6 +364,81 @@ func TestDeliverFailure(t *testing.T) {
 	}
 }
 
+// fakeRetryClient records the events sent to the retry topic.
+type fakeRetryClient struct {
+	ceclient.Client
+	sent []event.Event
+}
+
+func (c *fakeRetryClient) Send(_ context.Context, e event.Event) protocol.Result {
+	c.sent = append(c.sent, e)
+	return nil
+}
+
+func TestDeliverFailureRecordsResult(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.Copy(ioutil.Discard, req.Body)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retryClient := &fakeRetryClient{}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: retryClient,
+		StatsReporter:      r,
+	}
+
+	origin := newSampleEvent()
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if len(retryClient.sent) != 1 {
+		t.Fatalf("events sent to retry topic got=%d, want=1", len(retryClient.sent))
+	}
+
+	got, ok := eventutil.GetDeliveryResult(ctx, &retryClient.sent[0])
+	if !ok {
+		t.Fatal("Found delivery result OK got=false, want=true")
+	}
+	want := eventutil.DeliveryResult{
+		Attempts:   1,
+		StatusCode: http.StatusServiceUnavailable,
+		Error:      "event delivery failed: HTTP status code 503",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("delivery result (-want,+got): %v", diff)
+	}
+	if _, ok := eventutil.GetDeliveryResult(ctx, origin); ok {
+		t.Error("original event was modified with the delivery result")
+	}
+}
+
 type NoReplyHandler struct{}
 
 func (NoReplyHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -574,3 +683,1276 @@ func newSampleEvent() *event.Event {
 	sampleEvent.SetTime(time.Now())
 	return &sampleEvent
 }
+
+func TestDeliverDirectAddress(t *testing.T) {
+	cases := []struct {
+		name          string
+		directDown    bool
+		wantDirect    int
+		wantAddressed int
+	}{{
+		name:       "direct address reachable",
+		wantDirect: 1,
+	}, {
+		name:          "falls back when direct address is unreachable",
+		directDown:    true,
+		wantAddressed: 1,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+
+			var gotDirect, gotAddressed int
+			directSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotDirect++
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer directSvr.Close()
+			targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAddressed++
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer targetSvr.Close()
+			if tc.directDown {
+				directSvr.Close()
+			}
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{
+				Namespace:     "ns",
+				Name:          "target",
+				Broker:        "broker",
+				Address:       targetSvr.URL,
+				DirectAddress: directSvr.URL,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetAddress(fakeIngressAddress)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+
+			if err := p.Process(ctx, newSampleEvent()); err != nil {
+				t.Errorf("unexpected error from processing: %v", err)
+			}
+			if gotDirect != tc.wantDirect {
+				t.Errorf("direct address deliveries got=%d, want=%d", gotDirect, tc.wantDirect)
+			}
+			if gotAddressed != tc.wantAddressed {
+				t.Errorf("target address deliveries got=%d, want=%d", gotAddressed, tc.wantAddressed)
+			}
+		})
+	}
+}
+
+func TestDeliverSubscribers(t *testing.T) {
+	cases := []struct {
+		name       string
+		sequential bool
+		// failing is the index of the subscriber that fails deliveries.
+		failing int
+		want    []int32
+		wantErr bool
+	}{{
+		name:    "parallel",
+		failing: -1,
+		want:    []int32{1, 1, 1},
+	}, {
+		name:    "parallel with failing subscriber",
+		failing: 1,
+		want:    []int32{1, 1, 1},
+		wantErr: true,
+	}, {
+		name:       "sequential",
+		sequential: true,
+		failing:    -1,
+		want:       []int32{1, 1, 1},
+	}, {
+		name:       "sequential stops at failing subscriber",
+		sequential: true,
+		failing:    1,
+		want:       []int32{1, 1, 0},
+		wantErr:    true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+
+			got := make([]int32, len(tc.want))
+			var addresses []string
+			for i := range tc.want {
+				i := i
+				svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&got[i], 1)
+					if tc.failing == i {
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+					w.WriteHeader(http.StatusAccepted)
+				}))
+				defer svr.Close()
+				addresses = append(addresses, svr.URL)
+			}
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{
+				Namespace:             "ns",
+				Name:                  "target",
+				Broker:                "broker",
+				Address:               addresses[0],
+				SubscriberAddresses:   addresses[1:],
+				SequentialSubscribers: tc.sequential,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetAddress(fakeIngressAddress)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+			}
+
+			if err := p.Process(ctx, newSampleEvent()); (err != nil) != tc.wantErr {
+				t.Errorf("processing got error=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("deliveries per subscriber (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestDeliverSubscribersRetry(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	got := make([]int32, 3)
+	var failing int32 = 1
+	var addresses []string
+	for i := range got {
+		i := i
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&got[i], 1)
+			if r.Header.Get("Ce-Kgcpdelivered") != "" {
+				t.Errorf("subscriber %d got the delivered subscribers extension", i)
+			}
+			if atomic.LoadInt32(&failing) == int32(i) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer svr.Close()
+		addresses = append(addresses, svr.URL)
+	}
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:           "ns",
+		Name:                "target",
+		Broker:              "broker",
+		Address:             addresses[0],
+		SubscriberAddresses: addresses[1:],
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retryClient := &fakeRetryClient{}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: retryClient,
+		StatsReporter:      r,
+	}
+
+	if err := p.Process(ctx, newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if len(retryClient.sent) != 1 {
+		t.Fatalf("events sent to retry topic got=%d, want=1", len(retryClient.sent))
+	}
+	wantDelivered := map[string]bool{addresses[0]: true, addresses[2]: true}
+	if diff := cmp.Diff(wantDelivered, eventutil.GetDeliveredSubscribers(&retryClient.sent[0])); diff != "" {
+		t.Errorf("delivered subscribers (-want,+got): %v", diff)
+	}
+
+	// The retry only goes to the subscriber that failed.
+	atomic.StoreInt32(&failing, -1)
+	if err := p.Process(ctx, &retryClient.sent[0]); err != nil {
+		t.Fatalf("unexpected error from processing the retry: %v", err)
+	}
+	if diff := cmp.Diff([]int32{1, 2, 1}, got); diff != "" {
+		t.Errorf("deliveries per subscriber (-want,+got): %v", diff)
+	}
+	if len(retryClient.sent) != 1 {
+		t.Errorf("events sent to retry topic got=%d, want=1", len(retryClient.sent))
+	}
+}
+
+func TestDeliverRecreatedTarget(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	delivered := 0
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Id:         "new-uid",
+		Generation: 1,
+		Namespace:  "ns",
+		Name:       "target",
+		Broker:     "broker",
+		Address:    targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	// Events handled for the deleted target with the same name are dropped.
+	if err := p.Process(handlerctx.WithTargetID(ctx, "old-uid"), newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if delivered != 0 {
+		t.Errorf("event handled for the deleted target was delivered %d times", delivered)
+	}
+
+	if err := p.Process(handlerctx.WithTargetID(ctx, "new-uid"), newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if delivered != 1 {
+		t.Errorf("event handled for the target was delivered %d times, want 1", delivered)
+	}
+}
+
+func TestDeliverMaxAge(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var delivered int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:     "ns",
+		Name:          "target",
+		Broker:        "broker",
+		Address:       targetSvr.URL,
+		MaxAgeSeconds: 60,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = metrics.AddTargetTags(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	expired := newSampleEvent()
+	expired.SetTime(time.Now().Add(-time.Hour))
+	untimed := newSampleEvent()
+	untimed.SetTime(time.Time{})
+	for _, e := range []*event.Event{newSampleEvent(), expired, untimed} {
+		if err := p.Process(ctx, e); err != nil {
+			t.Fatalf("unexpected error from processing: %v", err)
+		}
+	}
+	// Only the expired event is dropped.
+	if got := atomic.LoadInt32(&delivered); got != 2 {
+		t.Errorf("delivered %d events, want 2", got)
+	}
+	metricstest.CheckCountData(t, "event_expired_count", map[string]string{
+		metricskey.LabelNamespaceName: "ns",
+		metricskey.LabelBrokerName:    "broker",
+		metricskey.LabelTriggerName:   "target",
+		metricskey.LabelFilterType:    "any",
+	}, 1)
+}
+
+func TestDeliverMaxAgeDeadLetter(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var delivered int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+	deadLetterStatus := int32(http.StatusAccepted)
+	deadLetters := make(chan *event.Event, 2)
+	deadLetterSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("dead letter sink got an invalid event: %v", err)
+		}
+		deadLetters <- e
+		w.WriteHeader(int(atomic.LoadInt32(&deadLetterStatus)))
+	}))
+	defer deadLetterSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:         "ns",
+		Name:              "target",
+		Broker:            "broker",
+		Address:           targetSvr.URL,
+		MaxAgeSeconds:     60,
+		DeadLetterAddress: deadLetterSvr.URL,
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retryClient := &fakeRetryClient{}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: retryClient,
+		StatsReporter:      r,
+	}
+
+	expired := newSampleEvent()
+	expired.SetTime(time.Now().Add(-time.Hour))
+	if err := p.Process(ctx, expired); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := atomic.LoadInt32(&delivered); got != 0 {
+		t.Errorf("delivered %d events, want 0", got)
+	}
+	got := <-deadLetters
+	if got.ID() != expired.ID() {
+		t.Errorf("dead letter event ID got=%q, want=%q", got.ID(), expired.ID())
+	}
+	if reason := eventutil.GetDeadLetterReason(got); reason != eventutil.DeadLetterReasonExpired {
+		t.Errorf("dead letter reason got=%q, want=%q", reason, eventutil.DeadLetterReasonExpired)
+	}
+	if len(retryClient.sent) != 0 {
+		t.Errorf("events sent to retry topic got=%d, want=0", len(retryClient.sent))
+	}
+
+	// A failure of the dead letter sink is retried.
+	atomic.StoreInt32(&deadLetterStatus, http.StatusServiceUnavailable)
+	if err := p.Process(ctx, expired); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	<-deadLetters
+	if len(retryClient.sent) != 1 {
+		t.Errorf("events sent to retry topic got=%d, want=1", len(retryClient.sent))
+	}
+}
+
+func TestDeliverCEOverrides(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var got *event.Event
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("failed to read the delivered event: %v", err)
+		}
+		got = e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace:   "ns",
+		Name:        "target",
+		Broker:      "broker",
+		Address:     targetSvr.URL,
+		CeOverrides: map[string]string{"region": "europe", "tier": "gold"},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	origin := newSampleEvent()
+	origin.SetExtension("region", "us")
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got == nil {
+		t.Fatal("no event was delivered")
+	}
+	for name, want := range target.CeOverrides {
+		if v := got.Extensions()[name]; v != want {
+			t.Errorf("delivered extension %s got=%v, want=%v", name, v, want)
+		}
+	}
+	if v := origin.Extensions()["region"]; v != "us" {
+		t.Errorf("original event extension region got=%v, want=us", v)
+	}
+}
+
+func TestDeliverTransform(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var got *event.Event
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("failed to read the delivered event: %v", err)
+		}
+		got = e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+		Transform: map[string]string{"user": "event.data.user.id", "kind": "event.type"},
+		RetryQueue: &config.Queue{
+			Topic: "test-retry-topic",
+		},
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	retryClient := &fakeRetryClient{}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: retryClient,
+		StatsReporter:      r,
+	}
+
+	origin := newSampleEvent()
+	if err := origin.SetData(event.ApplicationJSON, map[string]interface{}{"user": map[string]string{"id": "u1"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Deliver twice so that the second delivery uses the cached transform.
+	for i := 0; i < 2; i++ {
+		got = nil
+		if err := p.Process(ctx, origin); err != nil {
+			t.Fatalf("unexpected error from processing: %v", err)
+		}
+		if got == nil {
+			t.Fatal("no event was delivered")
+		}
+		want := fmt.Sprintf(`{"kind":%q,"user":"u1"}`, origin.Type())
+		if diff := cmp.Diff(want, string(got.Data())); diff != "" {
+			t.Errorf("delivered data (-want,+got): %v", diff)
+		}
+	}
+	if diff := cmp.Diff(`{"user":{"id":"u1"}}`, string(origin.Data())); diff != "" {
+		t.Errorf("original event data changed (-want,+got): %v", diff)
+	}
+	if len(retryClient.sent) != 0 {
+		t.Errorf("events sent to retry topic got=%d, want=0", len(retryClient.sent))
+	}
+
+	// An event the transform fails on is retried rather than delivered.
+	anonymous := newSampleEvent()
+	if err := anonymous.SetData(event.ApplicationJSON, map[string]interface{}{"other": true}); err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	if err := p.Process(ctx, anonymous); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got != nil {
+		t.Errorf("delivered event got=%v, want none", got)
+	}
+	if len(retryClient.sent) != 1 {
+		t.Errorf("events sent to retry topic got=%d, want=1", len(retryClient.sent))
+	}
+}
+
+func TestDeliverTransformPrunesDeletedTargets(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	newTarget := func(name string) *config.Target {
+		return &config.Target{
+			Namespace: "ns",
+			Name:      name,
+			Broker:    "broker",
+			Address:   targetSvr.URL,
+			Transform: map[string]string{"kind": "event.type"},
+			RetryQueue: &config.Queue{
+				Topic: "test-retry-topic",
+			},
+		}
+	}
+	deleted, kept := newTarget("deleted"), newTarget("kept")
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(deleted, kept)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: &fakeRetryClient{},
+		StatsReporter:      r,
+	}
+
+	if err := p.Process(handlerctx.WithTargetKey(ctx, deleted.Key()), newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if _, ok := p.transforms.Load(deleted.Key()); !ok {
+		t.Fatal("transform of the target was not cached")
+	}
+
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.DeleteTargets(deleted)
+	})
+	// Compiling the transform of another target removes that of the deleted one.
+	if err := p.Process(handlerctx.WithTargetKey(ctx, kept.Key()), newSampleEvent()); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if _, ok := p.transforms.Load(deleted.Key()); ok {
+		t.Error("transform of the deleted target is still cached")
+	}
+	if _, ok := p.transforms.Load(kept.Key()); !ok {
+		t.Error("transform of the kept target was not cached")
+	}
+}
+
+func TestDeliverEncrypted(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var got *event.Event
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("failed to read the delivered event: %v", err)
+		}
+		got = e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{
+		Namespace:     "ns",
+		Name:          "broker",
+		EncryptionKey: "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+	}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.SetEncryptionKey(broker.EncryptionKey)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapper := &enctesting.FakeKeyWrapper{}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+		Decrypter:     encryption.NewDecrypter(wrapper),
+	}
+
+	origin := newSampleEvent()
+	if err := origin.SetData(event.ApplicationJSON, map[string]string{"hello": "world"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := encryption.NewEncrypter(wrapper).Encrypt(ctx, broker, origin); err != nil {
+		t.Fatal(err)
+	}
+	encrypted := string(origin.Data())
+
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got == nil {
+		t.Fatal("no event was delivered")
+	}
+	if diff := cmp.Diff(`{"hello":"world"}`, string(got.Data())); diff != "" {
+		t.Errorf("delivered data (-want,+got): %v", diff)
+	}
+	if diff := cmp.Diff(event.ApplicationJSON, got.DataContentType()); diff != "" {
+		t.Errorf("delivered data content type (-want,+got): %v", diff)
+	}
+	for _, name := range []string{encryption.KeyExtension, encryption.DataKeyExtension, encryption.ContentTypeExtension} {
+		if v, ok := got.Extensions()[name]; ok {
+			t.Errorf("delivered event has extension %s=%v", name, v)
+		}
+	}
+	if string(origin.Data()) != encrypted {
+		t.Error("original event data changed")
+	}
+
+	// Without a decrypter, encrypted events are not delivered.
+	got = nil
+	p.Decrypter = nil
+	if err := p.Process(ctx, origin); err == nil {
+		t.Error("expected an error from processing an encrypted event without decrypter")
+	}
+	if got != nil {
+		t.Error("encrypted event was delivered without decrypter")
+	}
+}
+
+func TestDeliverUndecryptableDeadLetter(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var delivered int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+	deadLetters := make(chan *event.Event, 1)
+	deadLetterSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("dead letter sink got an invalid event: %v", err)
+		}
+		deadLetters <- e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSvr.Close()
+
+	// The event was encrypted with a key the broker never had.
+	broker := &config.Broker{
+		Namespace:     "ns",
+		Name:          "broker",
+		EncryptionKey: "projects/p/locations/global/keyRings/r/cryptoKeys/other",
+	}
+	target := &config.Target{
+		Namespace:         "ns",
+		Name:              "target",
+		Broker:            "broker",
+		Address:           targetSvr.URL,
+		DeadLetterAddress: deadLetterSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.SetEncryptionKey("projects/p/locations/global/keyRings/r/cryptoKeys/k")
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapper := &enctesting.FakeKeyWrapper{}
+	retryClient := &fakeRetryClient{}
+	p := &Processor{
+		DeliverClient:      http.DefaultClient,
+		Targets:            testTargets,
+		RetryOnFailure:     true,
+		DeliverRetryClient: retryClient,
+		StatsReporter:      r,
+		Decrypter:          encryption.NewDecrypter(wrapper),
+	}
+
+	origin := newSampleEvent()
+	if err := origin.SetData(event.ApplicationJSON, map[string]string{"hello": "world"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := encryption.NewEncrypter(wrapper).Encrypt(ctx, broker, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := atomic.LoadInt32(&delivered); got != 0 {
+		t.Errorf("delivered %d events, want 0", got)
+	}
+	got := <-deadLetters
+	if got.ID() != origin.ID() {
+		t.Errorf("dead letter event ID got=%q, want=%q", got.ID(), origin.ID())
+	}
+	if reason := eventutil.GetDeadLetterReason(got); reason != eventutil.DeadLetterReasonUndecryptable {
+		t.Errorf("dead letter reason got=%q, want=%q", reason, eventutil.DeadLetterReasonUndecryptable)
+	}
+	if len(retryClient.sent) != 0 {
+		t.Errorf("events sent to retry topic got=%d, want=0", len(retryClient.sent))
+	}
+}
+
+func TestDeliverCompressed(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var got *event.Event
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("failed to read the delivered event: %v", err)
+		}
+		got = e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+	}
+
+	data := `{"hello":"` + strings.Repeat("world", 100) + `"}`
+	origin := newSampleEvent()
+	if err := origin.SetData(event.ApplicationJSON, []byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	compressor, err := compression.NewCompressor(compression.Gzip, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compressor.Compress(origin); err != nil {
+		t.Fatal(err)
+	}
+	compressed := string(origin.Data())
+
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got == nil {
+		t.Fatal("no event was delivered")
+	}
+	if diff := cmp.Diff(data, string(got.Data())); diff != "" {
+		t.Errorf("delivered data (-want,+got): %v", diff)
+	}
+	if v, ok := got.Extensions()[compression.ContentEncodingExtension]; ok {
+		t.Errorf("delivered event has extension %s=%v", compression.ContentEncodingExtension, v)
+	}
+	if string(origin.Data()) != compressed {
+		t.Error("original event data changed")
+	}
+
+	// Events that cannot be decompressed are dropped if the target has no
+	// dead letter sink.
+	got = nil
+	origin.DataEncoded = []byte("garbage")
+	if err := p.Process(ctx, origin); err != nil {
+		t.Errorf("unexpected error from processing a corrupted event: %v", err)
+	}
+	if got != nil {
+		t.Error("corrupted event was delivered")
+	}
+
+	// Or sent to the dead letter sink of the target if it has one.
+	deadLetters := make(chan *event.Event, 1)
+	deadLetterSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("dead letter sink got an invalid event: %v", err)
+		}
+		deadLetters <- e
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSvr.Close()
+	target.DeadLetterAddress = deadLetterSvr.URL
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	if err := p.Process(ctx, origin); err != nil {
+		t.Errorf("unexpected error from processing a corrupted event: %v", err)
+	}
+	if got != nil {
+		t.Error("corrupted event was delivered")
+	}
+	dead := <-deadLetters
+	if dead.ID() != origin.ID() {
+		t.Errorf("dead letter event ID got=%q, want=%q", dead.ID(), origin.ID())
+	}
+	if reason := eventutil.GetDeadLetterReason(dead); reason != eventutil.DeadLetterReasonUndecompressable {
+		t.Errorf("dead letter reason got=%q, want=%q", reason, eventutil.DeadLetterReasonUndecompressable)
+	}
+}
+
+func TestDeliverSchemaValidation(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+
+	var delivered int32
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.SetAddress(fakeIngressAddress)
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = metrics.AddTargetTags(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := schema.Encode(map[string]*schema.Entry{
+		"ns.type": {
+			Namespace:          "ns",
+			Type:               "type",
+			Action:             schema.ActionReject,
+			ValidateOnDelivery: true,
+			Schema:             []byte(`{"type":"object","required":["id"]}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := schema.NewRegistry(zap.NewNop())
+	schemas.UpdateFromConfigMap(&corev1.ConfigMap{Data: data})
+	p := &Processor{
+		DeliverClient: http.DefaultClient,
+		Targets:       testTargets,
+		StatsReporter: r,
+		Schemas:       schemas,
+	}
+
+	// Events that don't conform to their schema are still delivered.
+	origin := newSampleEvent()
+	if err := origin.SetData(event.ApplicationJSON, map[string]string{"name": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Process(ctx, origin); err != nil {
+		t.Fatalf("unexpected error from processing: %v", err)
+	}
+	if got := atomic.LoadInt32(&delivered); got != 1 {
+		t.Errorf("delivered %d events, want 1", got)
+	}
+	metricstest.CheckCountData(t, "event_schema_validation_count", map[string]string{
+		metricskey.LabelNamespaceName: "ns",
+		metricskey.LabelBrokerName:    "broker",
+		metricskey.LabelTriggerName:   "target",
+		metricskey.LabelFilterType:    "any",
+		metricskey.LabelEventType:     "type",
+		"schema_validation":           schema.ResultInvalid,
+	}, 1)
+}
+
+func TestDeliverHeaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "uid"), []byte(`{"x-api-key":"secret"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		key     string
+		headers *headers.Files
+		want    string
+		wantErr bool
+	}{
+		"with headers": {
+			key:     "uid",
+			headers: headers.NewFiles(dir),
+			want:    "secret",
+		},
+		"without headers": {
+			headers: headers.NewFiles(dir),
+		},
+		"missing entry": {
+			key:     "other",
+			headers: headers.NewFiles(dir),
+			wantErr: true,
+		},
+		"headers not available": {
+			key:     "uid",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+
+			var delivered bool
+			var got string
+			targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				delivered = true
+				got = r.Header.Get("X-Api-Key")
+				w.WriteHeader(http.StatusAccepted)
+			}))
+			defer targetSvr.Close()
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{
+				Namespace:          "ns",
+				Name:               "target",
+				Broker:             "broker",
+				Address:            targetSvr.URL,
+				DeliveryHeadersKey: tc.key,
+			}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetAddress(fakeIngressAddress)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+				Headers:       tc.headers,
+			}
+
+			err = p.Process(ctx, newSampleEvent())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if delivered == tc.wantErr {
+				t.Errorf("delivered = %v, want %v", delivered, !tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("delivered header X-Api-Key got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeliverReplyHeaders(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "reply-uid"), []byte(`{"Authorization":"Bearer token"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		key          string
+		headers      *headers.Files
+		ingressCode  int
+		want         string
+		wantReceived bool
+		wantErr      bool
+	}{
+		"with reply headers": {
+			key:          "reply-uid",
+			headers:      headers.NewFiles(dir),
+			ingressCode:  http.StatusAccepted,
+			want:         "Bearer token",
+			wantReceived: true,
+		},
+		"without reply headers": {
+			headers:      headers.NewFiles(dir),
+			ingressCode:  http.StatusAccepted,
+			wantReceived: true,
+		},
+		"reply rejected": {
+			headers:      headers.NewFiles(dir),
+			ingressCode:  http.StatusUnauthorized,
+			wantReceived: true,
+			wantErr:      true,
+		},
+		"reply headers not available": {
+			key:         "reply-uid",
+			ingressCode: http.StatusAccepted,
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			reportertest.ResetDeliveryMetrics()
+			ctx := logtest.TestContextWithLogger(t)
+
+			targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("ce-specversion", "1.0")
+				w.Header().Set("ce-id", "reply")
+				w.Header().Set("ce-type", "type")
+				w.Header().Set("ce-source", "source")
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer targetSvr.Close()
+			var received bool
+			var got string
+			ingressSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = true
+				got = r.Header.Get("Authorization")
+				w.WriteHeader(tc.ingressCode)
+			}))
+			defer ingressSvr.Close()
+
+			broker := &config.Broker{Namespace: "ns", Name: "broker"}
+			target := &config.Target{Namespace: "ns", Name: "target", Broker: "broker", Address: targetSvr.URL}
+			testTargets := memory.NewEmptyTargets()
+			testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+				bm.SetAddress(ingressSvr.URL)
+				bm.SetReplyHeadersKey(tc.key)
+				bm.UpsertTargets(target)
+			})
+			ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+			r, err := metrics.NewDeliveryReporter("pod", "container")
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := &Processor{
+				DeliverClient: http.DefaultClient,
+				Targets:       testTargets,
+				StatsReporter: r,
+				Headers:       tc.headers,
+			}
+
+			err = p.Process(ctx, newSampleEvent())
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if received != tc.wantReceived {
+				t.Errorf("reply received = %v, want %v", received, tc.wantReceived)
+			}
+			if got != tc.want {
+				t.Errorf("reply header Authorization got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDeliverFailureCapturesResponseHeaders(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+	ctx := logtest.TestContextWithLogger(t)
+	long := strings.Repeat("a", maxCapturedHeaderLength+1)
+	targetSvr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.Copy(ioutil.Discard, req.Body)
+		w.Header().Set("X-Request-Id", "req-1")
+		w.Header().Set("X-Long", long)
+		w.Header().Set("X-Not-Captured", "value")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer targetSvr.Close()
+
+	broker := &config.Broker{Namespace: "ns", Name: "broker"}
+	target := &config.Target{
+		Namespace: "ns",
+		Name:      "target",
+		Broker:    "broker",
+		Address:   targetSvr.URL,
+	}
+	testTargets := memory.NewEmptyTargets()
+	testTargets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx = handlerctx.WithBrokerKey(ctx, broker.Key())
+	ctx = handlerctx.WithTargetKey(ctx, target.Key())
+
+	r, err := metrics.NewDeliveryReporter("pod", "container")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Processor{
+		DeliverClient:          http.DefaultClient,
+		Targets:                testTargets,
+		StatsReporter:          r,
+		CaptureResponseHeaders: []string{"x-request-id", "X-Long", "X-Missing"},
+	}
+
+	err = p.Process(ctx, newSampleEvent())
+	if got := StatusCode(err); got != http.StatusInternalServerError {
+		t.Fatalf("StatusCode(%v) = %d, want %d", err, got, http.StatusInternalServerError)
+	}
+	want := map[string]string{
+		"X-Request-Id": "req-1",
+		"X-Long":       long[:maxCapturedHeaderLength],
+	}
+	if diff := cmp.Diff(want, ResponseHeaders(err)); diff != "" {
+		t.Errorf("unexpected captured headers (-want, +got) = %v", diff)
+	}
+
+	rows, err := view.RetrieveData("event_dispatch_latencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exemplars []metricdata.Attachments
+	for _, row := range rows {
+		for _, e := range row.Data.(*view.DistributionData).ExemplarsPerBucket {
+			if e != nil {
+				exemplars = append(exemplars, e.Attachments)
+			}
+		}
+	}
+	wantExemplars := []metricdata.Attachments{{
+		"response.header.X-Request-Id": "req-1",
+		"response.header.X-Long":       long[:maxCapturedHeaderLength],
+	}}
+	if diff := cmp.Diff(wantExemplars, exemplars); diff != "" {
+		t.Errorf("unexpected exemplar attachments (-want, +got) = %v", diff)
+	}
+}
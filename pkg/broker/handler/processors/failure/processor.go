@@ -0,0 +1,166 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package failure provides a processor that reports persistent delivery
+// failures as Kubernetes events on the corresponding Trigger.
+package failure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	// DeliveryFailedReason is the reason of the Kubernetes events emitted
+	// for persistent delivery failures.
+	DeliveryFailedReason = "DeliveryFailed"
+
+	// maxErrorLength caps the length in bytes of the error included in an
+	// event message, to keep events small.
+	maxErrorLength = 256
+)
+
+// triggerAPIVersion and triggerKind identify the Trigger that the events are
+// emitted on. Targets don't carry their API version so it's hard-coded to the
+// version the broker reconciler works with.
+const (
+	triggerAPIVersion = "eventing.knative.dev/v1beta1"
+	triggerKind       = "Trigger"
+)
+
+// Processor passes events to the next processor and reports persistent
+// failures of the next processor as Kubernetes events on the target's
+// Trigger. Events are rate limited per target.
+type Processor struct {
+	processors.BaseProcessor
+
+	// Targets is the targets from config.
+	Targets config.ReadonlyTargets
+
+	// Recorder is used to emit the Kubernetes events.
+	Recorder record.EventRecorder
+
+	// Threshold is the number of consecutive failures for a target before
+	// an event is emitted. If zero, an event is emitted on every failure
+	// (subject to Interval).
+	Threshold int
+
+	// Interval is the minimum interval between two events emitted for
+	// the same target.
+	Interval time.Duration
+
+	// now defaults to time.Now; could be overridden in test.
+	now func() time.Time
+
+	mux      sync.Mutex
+	failures map[string]*targetFailures
+}
+
+type targetFailures struct {
+	consecutive  int
+	lastReported time.Time
+}
+
+var _ processors.Interface = (*Processor)(nil)
+
+// Process passes the event to the next processor and records the result.
+func (p *Processor) Process(ctx context.Context, event *event.Event) error {
+	err := p.Next().Process(ctx, event)
+
+	tk, kerr := handlerctx.GetTargetKey(ctx)
+	if kerr != nil {
+		return err
+	}
+	if err == nil {
+		p.reset(tk)
+		return nil
+	}
+
+	if consecutive, ok := p.recordFailure(tk); ok {
+		p.report(ctx, tk, consecutive, err)
+	}
+	return err
+}
+
+func (p *Processor) reset(tk string) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	delete(p.failures, tk)
+}
+
+// recordFailure counts a failure for the target and returns whether an event
+// should be emitted for it.
+func (p *Processor) recordFailure(tk string) (int, bool) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	if p.failures == nil {
+		p.failures = make(map[string]*targetFailures)
+	}
+	f, ok := p.failures[tk]
+	if !ok {
+		f = &targetFailures{}
+		p.failures[tk] = f
+	}
+	f.consecutive++
+	if f.consecutive < p.Threshold {
+		return f.consecutive, false
+	}
+	now := p.clock()
+	if !f.lastReported.IsZero() && now.Sub(f.lastReported) < p.Interval {
+		return f.consecutive, false
+	}
+	f.lastReported = now
+	return f.consecutive, true
+}
+
+func (p *Processor) report(ctx context.Context, tk string, consecutive int, err error) {
+	target, ok := p.Targets.GetTargetByKey(tk)
+	if !ok {
+		// The trigger is gone; there is nothing to report on.
+		return
+	}
+	ref := &corev1.ObjectReference{
+		APIVersion: triggerAPIVersion,
+		Kind:       triggerKind,
+		Namespace:  target.Namespace,
+		Name:       target.Name,
+		UID:        types.UID(target.Id),
+	}
+	msg := utils.Truncate(err.Error(), maxErrorLength)
+	logging.FromContext(ctx).Debug("reporting persistent delivery failure", zap.String("target", tk), zap.Int("consecutiveFailures", consecutive))
+	p.Recorder.Eventf(ref, corev1.EventTypeWarning, DeliveryFailedReason,
+		"%d consecutive deliveries to subscriber %q failed, last error: %s", consecutive, target.Address, msg)
+}
+
+func (p *Processor) clock() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
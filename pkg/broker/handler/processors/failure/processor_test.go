@@ -0,0 +1,152 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package failure
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors"
+)
+
+// resultProcessor returns err from Process.
+type resultProcessor struct {
+	processors.BaseProcessor
+	err error
+}
+
+func (p *resultProcessor) Process(_ context.Context, _ *event.Event) error {
+	return p.err
+}
+
+func TestNoTargetKey(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	next := &resultProcessor{err: errors.New("delivery failed")}
+	p := &Processor{Recorder: recorder}
+	p.WithNext(next)
+
+	e := event.New()
+	if err := p.Process(context.Background(), &e); err != next.err {
+		t.Errorf("Process error got=%v, want=%v", err, next.err)
+	}
+	assertEvents(t, recorder, 0)
+}
+
+func TestReportPersistentFailures(t *testing.T) {
+	target := &config.Target{
+		Id:        "trigger-uid",
+		Name:      "trigger",
+		Namespace: "ns",
+		Broker:    "broker",
+		Address:   "http://subscriber",
+	}
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx := handlerctx.WithTargetKey(context.Background(), target.Key())
+
+	now := time.Unix(1e9, 0)
+	recorder := record.NewFakeRecorder(10)
+	next := &resultProcessor{}
+	p := &Processor{
+		Targets:   targets,
+		Recorder:  recorder,
+		Threshold: 3,
+		Interval:  time.Minute,
+		now:       func() time.Time { return now },
+	}
+	p.WithNext(next)
+
+	steps := []struct {
+		name       string
+		err        error
+		advance    time.Duration
+		wantEvents int
+	}{
+		{name: "first failure", err: errors.New("boom"), wantEvents: 0},
+		{name: "second failure", err: errors.New("boom"), wantEvents: 0},
+		{name: "threshold reached", err: errors.New("boom"), wantEvents: 1},
+		{name: "rate limited", err: errors.New("boom"), advance: 30 * time.Second, wantEvents: 0},
+		{name: "interval passed", err: errors.New("boom"), advance: time.Minute, wantEvents: 1},
+		{name: "success resets", wantEvents: 0},
+		{name: "failure after reset", err: errors.New("boom"), advance: time.Hour, wantEvents: 0},
+	}
+	for _, s := range steps {
+		t.Run(s.name, func(t *testing.T) {
+			now = now.Add(s.advance)
+			next.err = s.err
+			e := event.New()
+			if err := p.Process(ctx, &e); err != s.err {
+				t.Errorf("Process error got=%v, want=%v", err, s.err)
+			}
+			assertEvents(t, recorder, s.wantEvents)
+		})
+	}
+}
+
+func TestTruncateError(t *testing.T) {
+	target := &config.Target{Name: "trigger", Namespace: "ns", Broker: "broker"}
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker("ns", "broker", func(bm config.BrokerMutation) {
+		bm.UpsertTargets(target)
+	})
+	ctx := handlerctx.WithTargetKey(context.Background(), target.Key())
+
+	recorder := record.NewFakeRecorder(10)
+	p := &Processor{Targets: targets, Recorder: recorder}
+	p.WithNext(&resultProcessor{err: errors.New(strings.Repeat("x", 2*maxErrorLength))})
+
+	e := event.New()
+	p.Process(ctx, &e)
+	select {
+	case got := <-recorder.Events:
+		if !strings.HasPrefix(got, "Warning "+DeliveryFailedReason) {
+			t.Errorf("unexpected event %q", got)
+		}
+		if strings.Contains(got, strings.Repeat("x", maxErrorLength+1)) {
+			t.Errorf("event error is not truncated: %q", got)
+		}
+	default:
+		t.Error("expected an event")
+	}
+}
+
+func assertEvents(t *testing.T, recorder *record.FakeRecorder, want int) {
+	t.Helper()
+	got := 0
+	for {
+		select {
+		case <-recorder.Events:
+			got++
+		default:
+			if got != want {
+				t.Errorf("events got=%d, want=%d", got, want)
+			}
+			return
+		}
+	}
+}
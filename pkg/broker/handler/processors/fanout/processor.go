@@ -98,9 +98,11 @@ func (p *Processor) fanoutEvent(ctx context.Context, event *event.Event, tc <-ch
 					zap.String("trigger", target.Name),
 				)
 			}
-			ctx = handlerctx.WithTargetKey(ctx, target.Key())
+			key := target.Key()
+			ctx = handlerctx.WithTargetKey(ctx, key)
+			ctx = handlerctx.WithTargetID(ctx, target.Id)
 			out <- &fanoutResult{
-				targetKey: target.Key(),
+				targetKey: key,
 				err:       p.Next().Process(ctx, event),
 			}
 		}
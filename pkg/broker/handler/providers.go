@@ -18,6 +18,10 @@ package handler
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"time"
 
@@ -27,6 +31,9 @@ import (
 	"github.com/google/wire"
 	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
 )
 
 var (
@@ -37,15 +44,7 @@ var (
 	}
 
 	DefaultHTTPClient = &http.Client{
-		Transport: &ochttp.Transport{
-			Base: &http.Transport{
-				MaxIdleConns:        1000,
-				MaxIdleConnsPerHost: 500,
-				MaxConnsPerHost:     500,
-				IdleConnTimeout:     30 * time.Second,
-			},
-			Propagation: &tracecontext.HTTPFormat{},
-		},
+		Transport: newTransport(nil),
 	}
 
 	// ProviderSet provides the fanout and retry sync pools using the default client options. In
@@ -56,7 +55,7 @@ var (
 		NewRetryPool,
 		NewPubsubClient,
 		NewRetryClient,
-		wire.Value(DefaultHTTPClient),
+		NewHTTPClient,
 		wire.Value(DefaultCEClientOpts),
 	)
 )
@@ -64,19 +63,63 @@ var (
 type (
 	ProjectID   string
 	RetryClient ceclient.Client
+	// CABundlePath is the path of a file of PEM encoded CA certificates
+	// trusted when delivering events. Empty if there is none.
+	CABundlePath string
 )
 
+// newTransport returns the transport of the clients delivering events. If
+// rootCAs is nil, the system CA certificates are trusted.
+func newTransport(rootCAs *x509.CertPool) http.RoundTripper {
+	base := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConns:        1000,
+		MaxIdleConnsPerHost: 500,
+		MaxConnsPerHost:     500,
+		IdleConnTimeout:     30 * time.Second,
+	}
+	if rootCAs != nil {
+		base.TLSClientConfig = &tls.Config{RootCAs: rootCAs}
+	}
+	return &ochttp.Transport{
+		Base:        base,
+		Propagation: &tracecontext.HTTPFormat{},
+	}
+}
+
+// NewHTTPClient provides the HTTP client delivering events. Besides the system
+// CA certificates, it trusts the ones in the CA bundle, if any, so that events
+// can be delivered to sinks with certificates issued by a private CA.
+func NewHTTPClient(caBundle CABundlePath) (*http.Client, error) {
+	if caBundle == "" {
+		return DefaultHTTPClient, nil
+	}
+	pem, err := ioutil.ReadFile(string(caBundle))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", caBundle)
+	}
+	return &http.Client{Transport: newTransport(pool)}, nil
+}
+
 // NewPubsubClient provides a pubsub client for the supplied project ID.
 func NewPubsubClient(ctx context.Context, projectID ProjectID) (*pubsub.Client, error) {
-	return pubsub.NewClient(ctx, string(projectID))
+	return pubsub.NewClient(ctx, string(projectID), endpoints.PubSub(ctx)...)
 }
 
 // NewRetryClient provides a retry CE client from a PubSub client and list of CE client options.
+// Events to retry queues on Pub/Sub Lite are published with Pub/Sub Lite clients instead.
 func NewRetryClient(ctx context.Context, client *pubsub.Client, opts ...ceclient.Option) (RetryClient, error) {
 	rps, err := cepubsub.New(ctx, cepubsub.WithClient(client))
 	if err != nil {
 		return nil, err
 	}
 
-	return ceclient.NewObserved(rps, opts...)
+	return ceclient.NewObserved(newLiteSender(rps, pubsublite.NewPublisher), opts...)
 }
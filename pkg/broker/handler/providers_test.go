@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	sink := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	dir, err := ioutil.TempDir("", "ca-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: sink.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(invalid, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		caBundle       CABundlePath
+		wantErr        bool
+		wantDeliverErr bool
+	}{
+		"no CA bundle": {
+			wantDeliverErr: true,
+		},
+		"CA bundle": {
+			caBundle: CABundlePath(bundle),
+		},
+		"missing CA bundle": {
+			caBundle: CABundlePath(filepath.Join(dir, "missing.crt")),
+			wantErr:  true,
+		},
+		"invalid CA bundle": {
+			caBundle: CABundlePath(invalid),
+			wantErr:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			client, err := NewHTTPClient(tc.caBundle)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("NewHTTPClient() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			resp, err := client.Post(sink.URL, "application/json", nil)
+			if (err != nil) != tc.wantDeliverErr {
+				t.Fatalf("Post() error = %v, wantErr %v", err, tc.wantDeliverErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
@@ -25,12 +25,18 @@ import (
 	"knative.dev/eventing/pkg/logging"
 
 	"cloud.google.com/go/pubsub"
+	ceclient "github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
 	handlerctx "github.com/google/knative-gcp/pkg/broker/handler/context"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/deliver"
+	"github.com/google/knative-gcp/pkg/broker/handler/processors/failure"
 	"github.com/google/knative-gcp/pkg/broker/handler/processors/filter"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/metrics"
 )
 
@@ -44,10 +50,18 @@ type RetryPool struct {
 	pool    sync.Map
 	// Pubsub client used to pull events from decoupling topics.
 	pubsubClient *pubsub.Client
+	// newLiteSubscriber creates the subscribers of the retry topics on
+	// Pub/Sub Lite. It is replaced in tests.
+	newLiteSubscriber pubsublite.SubscriberFn
 	// For initial events delivery. We only need a shared client.
 	// And we can set target address dynamically.
 	deliverClient *http.Client
+	// For sending events that failed again back to the retry topics.
+	retryClient   ceclient.Client
 	statsReporter *metrics.DeliveryReporter
+	// lease gates the pulling of the retry queues, if the region must hold
+	// the lease.
+	lease leaseGate
 }
 
 type retryHandlerCache struct {
@@ -66,8 +80,13 @@ func (hc *retryHandlerCache) shouldRenew(t *config.Target) bool {
 	if t == nil || t.RetryQueue == nil {
 		return true
 	}
+	// The trigger was deleted and recreated with the same name.
+	if t.Id != hc.t.Id {
+		return true
+	}
 	if t.RetryQueue.Topic != hc.t.RetryQueue.Topic ||
-		t.RetryQueue.Subscription != hc.t.RetryQueue.Subscription {
+		t.RetryQueue.Subscription != hc.t.RetryQueue.Subscription ||
+		t.RetryQueue.Location != hc.t.RetryQueue.Location {
 		return true
 	}
 	return false
@@ -78,6 +97,7 @@ func NewRetryPool(
 	targets config.ReadonlyTargets,
 	pubsubClient *pubsub.Client,
 	deliverClient *http.Client,
+	retryClient RetryClient,
 	statsReporter *metrics.DeliveryReporter,
 	opts ...Option) (*RetryPool, error) {
 	options, err := NewOptions(opts...)
@@ -86,15 +106,25 @@ func NewRetryPool(
 	}
 
 	p := &RetryPool{
-		targets:       targets,
-		options:       options,
-		pubsubClient:  pubsubClient,
-		deliverClient: deliverClient,
-		statsReporter: statsReporter,
+		targets:           targets,
+		options:           options,
+		pubsubClient:      pubsubClient,
+		newLiteSubscriber: pubsublite.NewSubscriber,
+		deliverClient:     deliverClient,
+		retryClient:       retryClient,
+		statsReporter:     statsReporter,
+		lease:             leaseGate{lease: options.Lease},
 	}
 	return p, nil
 }
 
+// servesTarget returns true if the broker of the target is served by the
+// brokercell of the pool.
+func (p *RetryPool) servesTarget(t *config.Target) bool {
+	b, ok := p.targets.GetBroker(t.Namespace, t.Broker)
+	return ok && b.ServedBy(p.options.BrokerCell)
+}
+
 // SyncOnce syncs once the handler pool based on the targets config.
 func (p *RetryPool) SyncOnce(ctx context.Context) error {
 	ctx, err := p.statsReporter.AddTags(ctx)
@@ -102,9 +132,16 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		logging.FromContext(ctx).Error("failed to add tags to context", zap.Error(err))
 	}
 
+	pull, replayFrom := p.lease.check()
+	if !pull {
+		// Another region holds the lease.
+		stopAll(&p.pool)
+		return nil
+	}
+
 	p.pool.Range(func(key, value interface{}) bool {
 		// Each target represents a trigger.
-		if _, ok := p.targets.GetTargetByKey(key.(string)); !ok {
+		if t, ok := p.targets.GetTargetByKey(key.(string)); !ok || !p.servesTarget(t) {
 			value.(*retryHandlerCache).Stop()
 			p.pool.Delete(key)
 		}
@@ -112,6 +149,11 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 	})
 
 	p.targets.RangeAllTargets(func(t *config.Target) bool {
+		// Targets of brokers of other brokercells are handled by their own data plane.
+		if !p.servesTarget(t) {
+			return true
+		}
+
 		if value, ok := p.pool.Load(t.Key()); ok {
 			// Skip if we don't need to renew the handler.
 			if !value.(*retryHandlerCache).shouldRenew(t) {
@@ -128,22 +170,45 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 			return true
 		}
 
-		sub := p.pubsubClient.Subscription(t.RetryQueue.Subscription)
-		sub.ReceiveSettings = p.options.PubsubReceiveSettings
+		sub := subscribe(p.pubsubClient, p.newLiteSubscriber, t.RetryQueue, p.options.PubsubReceiveSettings)
+
+		var chain []processors.ChainableProcessor
+		// Report persistent delivery failures on the trigger.
+		if fe := p.options.DeliveryFailureEvents; fe.Recorder != nil {
+			chain = append(chain, &failure.Processor{
+				Targets:   p.targets,
+				Recorder:  fe.Recorder,
+				Threshold: fe.Threshold,
+				Interval:  fe.Interval,
+			})
+		}
+		chain = append(chain, &deliver.Processor{
+			DeliverClient: p.deliverClient,
+			Targets:       p.targets,
+			StatsReporter: p.statsReporter,
+			Decrypter:     p.options.Decrypter,
+			Headers:       p.options.DeliveryHeaders,
+
+			CaptureResponseHeaders: p.options.CaptureResponseHeaders,
+		})
 
 		h := NewHandler(
 			sub,
-			processors.ChainProcessors(
-				&filter.Processor{Targets: p.targets},
-				&deliver.Processor{
-					DeliverClient: p.deliverClient,
-					Targets:       p.targets,
-					StatsReporter: p.statsReporter,
-				},
-			),
+			processors.ChainProcessors(&filter.Processor{Targets: p.targets}, chain...),
 			p.options.TimeoutPerEvent,
 			p.options.RetryPolicy,
 		)
+		// Events are only sent to the retry queue after their first
+		// delivery failed.
+		h.PriorDeliveries = 1
+		h.ReplayFrom = replayFrom
+		// Events that fail again are sent back to the retry topic with
+		// their attempt count, so that their backoff survives restarts.
+		retryTopic := t.RetryQueue.Topic
+		h.Requeue = func(ctx context.Context, e *event.Event, err error) error {
+			eventutil.RecordDeliveryFailure(ctx, e, deliver.StatusCode(err), err)
+			return p.retryClient.Send(cecontext.WithTopic(ctx, retryTopic), *e)
+		}
 		hc := &retryHandlerCache{
 			Handler: *h,
 			t:       t,
@@ -157,6 +222,9 @@ func (p *RetryPool) SyncOnce(ctx context.Context) error {
 		// Deliver processor needs the broker in the context for reply.
 		ctx = handlerctx.WithBrokerKey(ctx, config.BrokerKey(t.Namespace, t.Broker))
 		ctx = handlerctx.WithTargetKey(ctx, t.Key())
+		// The retry subscription belongs to this trigger, events must not be
+		// delivered to a trigger recreated with the same name.
+		ctx = handlerctx.WithTargetID(ctx, t.Id)
 		// Start the handler with target in context.
 		hc.Start(ctx, func(err error) {
 			// We will anyway get an error because of https://github.com/cloudevents/sdk-go/issues/470
@@ -50,7 +50,7 @@ func TestRetryWatchAndSync(t *testing.T) {
 	defer helper.Close()
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient, WithBrokerCell("cell"))
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -109,6 +109,24 @@ func TestRetryWatchAndSync(t *testing.T) {
 		assertRetryHandlers(t, syncPool, helper.Targets)
 	})
 
+	t.Run("targets of brokers of other brokercells have no handlers", func(t *testing.T) {
+		helper.Targets.MutateBroker(bs[2].Namespace, bs[2].Name, func(bm config.BrokerMutation) {
+			bm.SetBrokerCell("other-cell")
+		})
+		helper.Targets.MutateBroker(bs[3].Namespace, bs[3].Name, func(bm config.BrokerMutation) {
+			bm.SetBrokerCell("cell")
+		})
+		signal <- struct{}{}
+		// Wait a short period for the handlers to be updated.
+		<-time.After(time.Second)
+		assertRetryHandlers(t, syncPool, helper.Targets)
+		for _, bt := range bs[2].Targets {
+			if _, ok := syncPool.pool.Load(bt.Key()); ok {
+				t.Errorf("handler for target %s of another brokercell is still running", bt.Key())
+			}
+		}
+	})
+
 	t.Run("deleting all brokers with their targets", func(t *testing.T) {
 		// clean up all brokers
 		for _, b := range bs {
@@ -145,9 +163,16 @@ func TestRetrySyncPoolE2E(t *testing.T) {
 	expectMetrics.AddTrigger(t, t1.Name, wantRetryTags(t1))
 	expectMetrics.AddTrigger(t, t2.Name, wantRetryTags(t2))
 	expectMetrics.AddTrigger(t, t3.Name, wantRetryTags(t3))
+	// Events in the retry queue have been delivered once before.
+	for _, trigger := range []string{t1.Name, t2.Name, t3.Name} {
+		expectMetrics.AddDeliveryTags(t, trigger, reportertest.Tags{
+			"attempt_class": "retry",
+			"retry_count":   "1",
+		})
+	}
 
 	signal := make(chan struct{})
-	syncPool, err := InitializeTestRetryPool(helper.Targets, retryPod, retryContainer, helper.PubsubClient)
+	syncPool, err := InitializeTestRetryPool(ctx, helper.Targets, retryPod, retryContainer, helper.PubsubClient)
 	if err != nil {
 		t.Errorf("unexpected error from getting sync pool: %v", err)
 	}
@@ -331,7 +356,7 @@ func assertRetryHandlers(t *testing.T, p *RetryPool, targets config.Targets) {
 	})
 
 	targets.RangeAllTargets(func(t *config.Target) bool {
-		if t.State == config.State_READY {
+		if t.State == config.State_READY && p.servesTarget(t) {
 			wantHandlers[t.Key()] = true
 		}
 		return true
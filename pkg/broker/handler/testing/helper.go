@@ -478,7 +478,15 @@ func (h *Helper) VerifyNextTargetRetryEvent(ctx context.Context, t *testing.T, t
 	gotEvent, err = binding.ToEvent(ctx, msg)
 	if err != nil {
 		t.Errorf("target (key=%q) received invalid cloudevent: %v", targetKey, err)
+		return
+	}
+
+	// Retry events carry the result of the failed delivery. The status and
+	// error depend on the failure, so only verify that an attempt was recorded.
+	if res, ok := eventutil.GetDeliveryResult(ctx, gotEvent); !ok || res.Attempts < 1 {
+		t.Errorf("target (key=%q) retry event delivery attempts got=%d, want>=1", targetKey, res.Attempts)
 	}
+	eventutil.DeleteDeliveryResult(ctx, gotEvent)
 }
 
 func assertEvent(t *testing.T, want, got *event.Event, msg string) {
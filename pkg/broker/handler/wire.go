@@ -45,6 +45,7 @@ func InitializeTestFanoutPool(
 }
 
 func InitializeTestRetryPool(
+	ctx context.Context,
 	targets config.ReadonlyTargets,
 	podName metrics.PodName,
 	containerName metrics.ContainerName,
@@ -53,7 +54,9 @@ func InitializeTestRetryPool(
 ) (*RetryPool, error) {
 	panic(wire.Build(
 		NewRetryPool,
+		NewRetryClient,
 		metrics.NewDeliveryReporter,
 		wire.Value(DefaultHTTPClient),
+		wire.Value(DefaultCEClientOpts),
 	))
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package headers reads the headers set on the requests delivering events to
+// targets from the delivery headers secret mounted in the data plane pods.
+// The targets config only records the key of the entry holding the headers of
+// each target, so that header values such as API keys never leave the secret.
+package headers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultDir is the directory the delivery headers secret is mounted at.
+	DefaultDir = "/var/run/cloud-run-events/delivery-headers"
+
+	// refreshPeriod is how long the headers read from an entry are used
+	// before the entry is read again. The kubelet only refreshes mounted
+	// secrets every minute or so, so there is no point in reading them more
+	// often.
+	refreshPeriod = 30 * time.Second
+)
+
+// Files reads the headers of each target from the files of a mounted secret.
+// Each file holds a JSON object of header names to values.
+type Files struct {
+	dir string
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+// entry is the headers read from a file, along with when to read it again.
+type entry struct {
+	header  http.Header
+	expires time.Time
+}
+
+// NewFiles creates a Files reading the headers from the files in dir.
+func NewFiles(dir string) *Files {
+	return &Files{
+		dir:     dir,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Get returns the headers stored under key. It returns an error if there is no
+// such entry, e.g. because the secret has not been refreshed yet, so that the
+// event is not delivered without them.
+func (f *Files) Get(key string) (http.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := f.now()
+	if e, ok := f.entries[key]; ok && now.Before(e.expires) {
+		return e.header, nil
+	}
+	header, err := f.read(key)
+	if err != nil {
+		return nil, err
+	}
+	f.entries[key] = &entry{header: header, expires: now.Add(refreshPeriod)}
+	return header, nil
+}
+
+// read reads the headers stored under key.
+func (f *Files) read(key string) (http.Header, error) {
+	// Keys are plain file names, never paths.
+	if key != filepath.Base(key) {
+		return nil, fmt.Errorf("invalid delivery headers key %q", key)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(f.dir, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delivery headers %q: %w", key, err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse delivery headers %q: %w", key, err)
+	}
+	header := make(http.Header, len(values))
+	for name, value := range values {
+		header.Set(name, value)
+	}
+	return header, nil
+}
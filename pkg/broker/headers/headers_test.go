@@ -0,0 +1,119 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package headers
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeEntry(t *testing.T, dir, key, content string) {
+	t.Helper()
+	if err := ioutil.WriteFile(filepath.Join(dir, key), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", key, err)
+	}
+}
+
+func TestGet(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeEntry(t, dir, "valid", `{"x-api-key":"secret","Authorization":"Bearer token"}`)
+	writeEntry(t, dir, "malformed", `not json`)
+
+	testCases := map[string]struct {
+		key     string
+		want    http.Header
+		wantErr bool
+	}{
+		"valid": {
+			key: "valid",
+			want: http.Header{
+				"X-Api-Key":     {"secret"},
+				"Authorization": {"Bearer token"},
+			},
+		},
+		"missing": {
+			key:     "missing",
+			wantErr: true,
+		},
+		"malformed": {
+			key:     "malformed",
+			wantErr: true,
+		},
+		"path": {
+			key:     "../valid",
+			wantErr: true,
+		},
+		"empty": {
+			key:     "",
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewFiles(dir).Get(tc.key)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected headers (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestGetRefreshes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "headers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeEntry(t, dir, "key", `{"x-api-key":"old"}`)
+
+	f := NewFiles(dir)
+	now := time.Now()
+	f.now = func() time.Time { return now }
+	get := func() string {
+		t.Helper()
+		h, err := f.Get("key")
+		if err != nil {
+			t.Fatalf("Get() = %v", err)
+		}
+		return h.Get("X-Api-Key")
+	}
+
+	if got := get(); got != "old" {
+		t.Errorf("X-Api-Key = %q, want %q", got, "old")
+	}
+	writeEntry(t, dir, "key", `{"x-api-key":"new"}`)
+	if got := get(); got != "old" {
+		t.Errorf("X-Api-Key = %q before the entry expired, want %q", got, "old")
+	}
+	now = now.Add(refreshPeriod)
+	if got := get(); got != "new" {
+		t.Errorf("X-Api-Key = %q after the entry expired, want %q", got, "new")
+	}
+}
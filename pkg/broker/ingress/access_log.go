@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/configmap"
+)
+
+// AccessLogSampleRateKey is the config-observability key of the fraction of
+// ingress requests that are access logged. It must be between 0 and 1, and
+// defaults to 0, which disables access logs.
+const AccessLogSampleRateKey = "broker.ingress.access-log-sample-rate"
+
+// accessLogger writes structured access logs for a sample of the ingress
+// requests.
+type accessLogger struct {
+	logger *zap.Logger
+	// sampleRate holds the math.Float64bits of the sample rate so that it can
+	// be updated while requests are served.
+	sampleRate uint64
+	// random returns a number in [0.0,1.0) to decide whether a request is
+	// sampled.
+	random func() float64
+}
+
+// accessLogEntry describes a served ingress request.
+type accessLogEntry struct {
+	method     string
+	broker     types.NamespacedName
+	statusCode int
+	latency    time.Duration
+	eventID    string
+	eventType  string
+}
+
+func newAccessLogger(logger *zap.Logger) *accessLogger {
+	return &accessLogger{
+		logger: logger,
+		random: rand.Float64,
+	}
+}
+
+func (l *accessLogger) getSampleRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&l.sampleRate))
+}
+
+func (l *accessLogger) setSampleRate(rate float64) {
+	atomic.StoreUint64(&l.sampleRate, math.Float64bits(rate))
+}
+
+// log writes the access log of the request if it is sampled.
+func (l *accessLogger) log(e accessLogEntry) {
+	rate := l.getSampleRate()
+	if rate <= 0 || (rate < 1 && l.random() >= rate) {
+		return
+	}
+	l.logger.Info("Broker ingress access",
+		zap.String("method", e.method),
+		zap.String("broker", e.broker.String()),
+		zap.Int("status", e.statusCode),
+		zap.Duration("latency", e.latency),
+		zap.String("event.id", e.eventID),
+		zap.String("event.type", e.eventType),
+		// Whether only some of the requests are access logged.
+		zap.Bool("sampled", rate < 1),
+	)
+}
+
+// accessLogSampleRateFromConfigMap returns the access log sample rate set in
+// the config-observability ConfigMap.
+func accessLogSampleRateFromConfigMap(cm *corev1.ConfigMap) (float64, error) {
+	var rate float64
+	if err := configmap.Parse(cm.Data, configmap.AsFloat64(AccessLogSampleRateKey, &rate)); err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("%s must be between 0 and 1, got %v", AccessLogSampleRateKey, rate)
+	}
+	return rate, nil
+}
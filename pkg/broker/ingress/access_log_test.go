@@ -0,0 +1,202 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+)
+
+// newBufferLogger returns a logger that writes JSON entries to the returned buffer.
+func newBufferLogger() (*zap.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.InfoLevel)
+	return zap.New(core), &buf
+}
+
+// accessLogs returns the access log entries written to buf.
+func accessLogs(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		entry := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Failed to unmarshal log entry %q: %v", line, err)
+		}
+		if entry["msg"] == "Broker ingress access" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	entry := accessLogEntry{
+		method:     nethttp.MethodPost,
+		broker:     types.NamespacedName{Namespace: "ns1", Name: "broker1"},
+		statusCode: nethttp.StatusAccepted,
+		eventID:    "id1",
+		eventType:  eventType,
+	}
+	tests := []struct {
+		name       string
+		sampleRate float64
+		random     float64
+		wantLogged bool
+		wantSample bool
+	}{{
+		name:       "disabled",
+		sampleRate: 0,
+		random:     0,
+	}, {
+		name:       "not sampled",
+		sampleRate: 0.5,
+		random:     0.5,
+	}, {
+		name:       "sampled",
+		sampleRate: 0.5,
+		random:     0.2,
+		wantLogged: true,
+		wantSample: true,
+	}, {
+		name:       "all requests",
+		sampleRate: 1,
+		random:     0.99,
+		wantLogged: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			logger, buf := newBufferLogger()
+			l := newAccessLogger(logger)
+			l.random = func() float64 { return tc.random }
+			l.setSampleRate(tc.sampleRate)
+
+			l.log(entry)
+
+			entries := accessLogs(t, buf)
+			if !tc.wantLogged {
+				if len(entries) != 0 {
+					t.Errorf("Unexpected access logs: %v", entries)
+				}
+				return
+			}
+			if len(entries) != 1 {
+				t.Fatalf("Got %d access logs, want 1", len(entries))
+			}
+			got := entries[0]
+			for _, k := range []string{"level", "ts", "msg", "latency"} {
+				delete(got, k)
+			}
+			want := map[string]interface{}{
+				"method":     nethttp.MethodPost,
+				"broker":     "ns1/broker1",
+				"status":     float64(nethttp.StatusAccepted),
+				"event.id":   "id1",
+				"event.type": eventType,
+				"sampled":    tc.wantSample,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unexpected access log (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestHandlerAccessLog(t *testing.T) {
+	logger, buf := newBufferLogger()
+	ctx := logging.WithLogger(context.Background(), logger.Sugar())
+	h := NewHandler(ctx, nil, nil, nil, nil, 0)
+	h.UpdateFromObservabilityConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{AccessLogSampleRateKey: "1"},
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(nethttp.MethodGet, "/ns1/broker1", nil))
+
+	entries := accessLogs(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("Got %d access logs, want 1", len(entries))
+	}
+	if got, want := entries[0]["status"], float64(nethttp.StatusMethodNotAllowed); got != want {
+		t.Errorf("Access log status = %v, want %v", got, want)
+	}
+	if got, want := entries[0]["method"], nethttp.MethodGet; got != want {
+		t.Errorf("Access log method = %v, want %v", got, want)
+	}
+}
+
+func TestAccessLogSampleRateFromConfigMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		want    float64
+		wantErr bool
+	}{{
+		name: "unset",
+		want: 0,
+	}, {
+		name: "valid",
+		data: map[string]string{AccessLogSampleRateKey: "0.25"},
+		want: 0.25,
+	}, {
+		name:    "not a number",
+		data:    map[string]string{AccessLogSampleRateKey: "often"},
+		wantErr: true,
+	}, {
+		name:    "out of range",
+		data:    map[string]string{AccessLogSampleRateKey: "1.5"},
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := accessLogSampleRateFromConfigMap(&corev1.ConfigMap{Data: tc.data})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error: %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Sample rate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUpdateFromObservabilityConfigMapKeepsRateOnError(t *testing.T) {
+	logger, _ := newBufferLogger()
+	h := NewHandler(logging.WithLogger(context.Background(), logger.Sugar()), nil, nil, nil, nil, 0)
+	h.UpdateFromObservabilityConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{AccessLogSampleRateKey: "0.5"},
+	})
+	h.UpdateFromObservabilityConfigMap(&corev1.ConfigMap{
+		Data: map[string]string{AccessLogSampleRateKey: "-1"},
+	})
+	if got := h.accessLog.getSampleRate(); got != 0.5 {
+		t.Errorf("Sample rate = %v, want 0.5", got)
+	}
+}
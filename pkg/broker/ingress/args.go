@@ -18,24 +18,49 @@ package ingress
 
 import (
 	"context"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	cev2 "github.com/cloudevents/sdk-go/v2"
-	"knative.dev/eventing/pkg/kncloudevents"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 type Port int
 type ProjectID string
 
-// NewHTTPMessageReceiver wraps kncloudevents.NewHttpMessageReceiver with type-safe options.
-func NewHTTPMessageReceiver(port Port) *kncloudevents.HttpMessageReceiver {
-	return kncloudevents.NewHttpMessageReceiver(int(port))
+// PublisherIdleTTL is how long the handle of a decouple topic may go unused
+// before it is stopped. Zero means handles are never stopped for being idle.
+type PublisherIdleTTL time.Duration
+
+// MaxInFlightPublishes is how many events of a batch may be published to a
+// decouple topic before waiting for the result of the oldest one.
+type MaxInFlightPublishes int
+
+// ServerLimits protect the ingress HTTP server from slow or misbehaving
+// producers. Zero values mean no limit.
+type ServerLimits struct {
+	// ReadHeaderTimeout is how long the server waits for the headers of a
+	// request.
+	ReadHeaderTimeout time.Duration
+	// ReadTimeout is how long the server waits for a whole request,
+	// including its body.
+	ReadTimeout time.Duration
+	// IdleTimeout is how long the server keeps an idle keep-alive connection
+	// open.
+	IdleTimeout time.Duration
+	// MaxHeaderBytes is the maximum size of the headers of a request.
+	MaxHeaderBytes int
 }
 
+// MaxBodyBytes is the maximum size of the body of a request. Zero means no
+// limit.
+type MaxBodyBytes int64
+
 // NewPubsubClient provides a pubsub client from PubsubClientOpts.
 func NewPubsubClient(ctx context.Context, projectID ProjectID) (*pubsub.Client, error) {
-	return pubsub.NewClient(ctx, string(projectID))
+	return pubsub.NewClient(ctx, string(projectID), endpoints.PubSub(ctx)...)
 }
 
 // NewPubsubDecoupleClient creates a pubsub Cloudevents client to use to publish events to decouple queues.
@@ -0,0 +1,73 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+// errRequestTooLarge is returned when reading past the body size limit.
+var errRequestTooLarge = errors.New("request body too large")
+
+// requestBody wraps the body of a request to enforce MaxBodyBytes while it is
+// streamed, and to tell why reading it failed.
+type requestBody struct {
+	io.ReadCloser
+	// remaining is how many more bytes may be read, or negative if there is
+	// no limit.
+	remaining int64
+	// tooLarge is set when the body exceeded the limit.
+	tooLarge bool
+	// timedOut is set when reading the body timed out.
+	timedOut bool
+}
+
+func newRequestBody(body io.ReadCloser, maxBytes MaxBodyBytes) *requestBody {
+	remaining := int64(maxBytes)
+	if remaining <= 0 {
+		remaining = -1
+	}
+	return &requestBody{ReadCloser: body, remaining: remaining}
+}
+
+func (b *requestBody) Read(p []byte) (int, error) {
+	if b.tooLarge {
+		return 0, errRequestTooLarge
+	}
+	// Read one byte past the limit to tell a body of exactly the limit apart
+	// from a larger one.
+	if b.remaining >= 0 && int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if b.remaining >= 0 {
+		if int64(n) > b.remaining {
+			n = int(b.remaining)
+			b.remaining = 0
+			b.tooLarge = true
+			return n, errRequestTooLarge
+		}
+		b.remaining -= int64(n)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		b.timedOut = true
+	}
+	return n, err
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/broker/compression"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+const (
+	// DefaultFailoverCooldown is how long events are published to the
+	// secondary region after the primary region failed, by default.
+	DefaultFailoverCooldown = 30 * time.Second
+)
+
+// Failover configures the publishing of the events to a secondary Pub/Sub
+// region, for when the primary region has an incident.
+type Failover struct {
+	// Endpoint is the regional Pub/Sub endpoint of the secondary region,
+	// e.g. europe-west1-pubsub.googleapis.com:443. Empty disables failover.
+	Endpoint string
+	// Cooldown is how long events are published to the secondary region
+	// after a publish to the primary region failed. It defaults to
+	// DefaultFailoverCooldown.
+	Cooldown time.Duration
+}
+
+// NewDecoupleSink creates the decouple sink publishing to the decouple topics
+// of the brokers with client, and also to the secondary region if failover is
+// enabled.
+func NewDecoupleSink(ctx context.Context, brokerConfig config.ReadonlyTargets, projectID ProjectID, client *pubsub.Client, idleTTL PublisherIdleTTL, maxInFlight MaxInFlightPublishes, compressor *compression.Compressor, failover Failover) (DecoupleSink, error) {
+	primary := NewMultiTopicDecoupleSink(ctx, brokerConfig, client, idleTTL, maxInFlight, compressor)
+	if failover.Endpoint == "" {
+		return primary, nil
+	}
+	// Options are applied in order, so the regional endpoint overrides the
+	// endpoint of the environment, if any.
+	opts := append(endpoints.PubSub(ctx), option.WithEndpoint(failover.Endpoint))
+	secondaryClient, err := pubsub.NewClient(ctx, string(projectID), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Pub/Sub client of the secondary region: %w", err)
+	}
+	secondary := NewMultiTopicDecoupleSink(ctx, brokerConfig, secondaryClient, idleTTL, maxInFlight, compressor)
+	return newFailoverDecoupleSink(ctx, primary, secondary, failover), nil
+}
+
+// failoverDecoupleSink publishes events to the decouple topics through the
+// primary region, and through the secondary region when the primary region
+// fails. The Pub/Sub topics are global, so the events
+// published through either region are pulled by the fanouts of both.
+type failoverDecoupleSink struct {
+	primary   DecoupleSink
+	secondary DecoupleSink
+	cooldown  time.Duration
+	// unhealthyUntil is when the primary region is tried again after a
+	// failure, in unix nanoseconds. It must be accessed atomically.
+	unhealthyUntil int64
+	// now returns the current time. It is replaced in tests.
+	now    func() time.Time
+	logger *zap.Logger
+}
+
+func newFailoverDecoupleSink(ctx context.Context, primary, secondary DecoupleSink, failover Failover) *failoverDecoupleSink {
+	s := &failoverDecoupleSink{
+		primary:   primary,
+		secondary: secondary,
+		cooldown:  failover.Cooldown,
+		now:       time.Now,
+		logger:    logging.FromContext(ctx),
+	}
+	if s.cooldown <= 0 {
+		s.cooldown = DefaultFailoverCooldown
+	}
+	return s
+}
+
+// Send sends the event to the primary region, or to the secondary region if
+// the primary region is unhealthy or fails.
+func (s *failoverDecoupleSink) Send(ctx context.Context, ns, broker string, event cev2.Event) protocol.Result {
+	return s.SendBatch(ctx, ns, broker, []cev2.Event{event})[0]
+}
+
+// SendBatch sends the events to the primary region, and the events that
+// failed to the secondary region.
+func (s *failoverDecoupleSink) SendBatch(ctx context.Context, ns, broker string, events []cev2.Event) []protocol.Result {
+	if !s.primaryHealthy() {
+		return s.secondary.SendBatch(ctx, ns, broker, events)
+	}
+	results := s.primary.SendBatch(ctx, ns, broker, events)
+	var failed []int
+	for i, r := range results {
+		if regionalFailure(r) {
+			failed = append(failed, i)
+		}
+	}
+	if len(failed) == 0 {
+		return results
+	}
+	s.markPrimaryUnhealthy(results[failed[0]])
+	retried := make([]cev2.Event, len(failed))
+	for j, i := range failed {
+		retried[j] = events[i]
+	}
+	for j, r := range s.secondary.SendBatch(ctx, ns, broker, retried) {
+		results[failed[j]] = r
+	}
+	return results
+}
+
+func (s *failoverDecoupleSink) primaryHealthy() bool {
+	return s.now().UnixNano() >= atomic.LoadInt64(&s.unhealthyUntil)
+}
+
+// markPrimaryUnhealthy switches the publishes over to the secondary region
+// for the cooldown.
+func (s *failoverDecoupleSink) markPrimaryUnhealthy(err error) {
+	until := s.now().Add(s.cooldown)
+	atomic.StoreInt64(&s.unhealthyUntil, until.UnixNano())
+	s.logger.Warn("Switching over to the secondary region", zap.Time("until", until), zap.Error(err))
+}
+
+// regionalFailure returns true if the publish failed in a way that the other
+// region might not, as opposed to a success or an error in the config of the
+// broker.
+func regionalFailure(r protocol.Result) bool {
+	if protocol.IsACK(r) {
+		return false
+	}
+	return !errors.Is(r, ErrNotFound) && !errors.Is(r, ErrNotReady) && !errors.Is(r, ErrIncomplete) &&
+		!errors.Is(r, context.Canceled)
+}
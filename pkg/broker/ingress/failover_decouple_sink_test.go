@@ -0,0 +1,150 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/google/go-cmp/cmp"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// regionSink is a DecoupleSink recording the IDs of the events it is sent,
+// and failing the events whose ID is in failing.
+type regionSink struct {
+	mu      sync.Mutex
+	failing map[string]error
+	sent    []string
+}
+
+func (s *regionSink) Send(ctx context.Context, ns, broker string, event cloudevents.Event) protocol.Result {
+	return s.SendBatch(ctx, ns, broker, []cloudevents.Event{event})[0]
+}
+
+func (s *regionSink) SendBatch(_ context.Context, _, _ string, events []cloudevents.Event) []protocol.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := make([]protocol.Result, len(events))
+	for i, e := range events {
+		s.sent = append(s.sent, e.ID())
+		results[i] = s.failing[e.ID()]
+	}
+	return results
+}
+
+func failoverEvents(ids ...string) []cloudevents.Event {
+	events := make([]cloudevents.Event, len(ids))
+	for i, id := range ids {
+		events[i] = cloudevents.NewEvent()
+		events[i].SetID(id)
+	}
+	return events
+}
+
+func TestFailoverDecoupleSink(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	tests := []struct {
+		name             string
+		primaryFailing   map[string]error
+		secondaryFailing map[string]error
+		events           []string
+		wantPrimary      []string
+		wantSecondary    []string
+		wantErrs         []error
+	}{{
+		name:        "primary succeeds",
+		events:      []string{"1", "2"},
+		wantPrimary: []string{"1", "2"},
+		wantErrs:    []error{nil, nil},
+	}, {
+		name:           "failed events sent to secondary",
+		primaryFailing: map[string]error{"2": errUnavailable},
+		events:         []string{"1", "2", "3"},
+		wantPrimary:    []string{"1", "2", "3"},
+		wantSecondary:  []string{"2"},
+		wantErrs:       []error{nil, nil, nil},
+	}, {
+		name:             "both regions fail",
+		primaryFailing:   map[string]error{"1": errUnavailable},
+		secondaryFailing: map[string]error{"1": errUnavailable},
+		events:           []string{"1"},
+		wantPrimary:      []string{"1"},
+		wantSecondary:    []string{"1"},
+		wantErrs:         []error{errUnavailable},
+	}, {
+		name:           "config errors not failed over",
+		primaryFailing: map[string]error{"1": ErrNotFound},
+		events:         []string{"1"},
+		wantPrimary:    []string{"1"},
+		wantErrs:       []error{ErrNotFound},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			primary := &regionSink{failing: tc.primaryFailing}
+			secondary := &regionSink{failing: tc.secondaryFailing}
+			s := newFailoverDecoupleSink(logtesting.TestContextWithLogger(t), primary, secondary, Failover{})
+
+			results := s.SendBatch(context.Background(), "ns", "broker", failoverEvents(tc.events...))
+			var errs []error
+			for _, r := range results {
+				errs = append(errs, r)
+			}
+			if diff := cmp.Diff(tc.wantErrs, errs, cmp.Comparer(func(a, b error) bool { return a == b })); diff != "" {
+				t.Errorf("SendBatch() unexpected results (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantPrimary, primary.sent); diff != "" {
+				t.Errorf("unexpected events sent to the primary region (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantSecondary, secondary.sent); diff != "" {
+				t.Errorf("unexpected events sent to the secondary region (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestFailoverDecoupleSinkSwitchover(t *testing.T) {
+	primary := &regionSink{failing: map[string]error{"1": errors.New("unavailable")}}
+	secondary := &regionSink{}
+	s := newFailoverDecoupleSink(logtesting.TestContextWithLogger(t), primary, secondary, Failover{Cooldown: time.Minute})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+	send := func(id string) {
+		t.Helper()
+		if r := s.Send(context.Background(), "ns", "broker", failoverEvents(id)[0]); !protocol.IsACK(r) {
+			t.Errorf("Send(%s) = %v", id, r)
+		}
+	}
+
+	send("1")
+	// The primary region is skipped during the cooldown.
+	send("2")
+	now = now.Add(time.Minute)
+	send("3")
+
+	if diff := cmp.Diff([]string{"1", "3"}, primary.sent); diff != "" {
+		t.Errorf("unexpected events sent to the primary region (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff([]string{"1", "2"}, secondary.sent); diff != "" {
+		t.Errorf("unexpected events sent to the secondary region (-want, +got) = %v", diff)
+	}
+}
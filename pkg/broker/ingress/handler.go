@@ -18,8 +18,10 @@ package ingress
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	nethttp "net/http"
 	"strings"
 	"time"
@@ -29,13 +31,19 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding/transformer"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/knative-gcp/pkg/broker/auth"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/tracing"
 	"github.com/google/wire"
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
-	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/eventing/pkg/logging"
 	kntracing "knative.dev/eventing/pkg/tracing"
 )
@@ -54,13 +62,13 @@ const (
 	heathCheckPath = "/healthz"
 )
 
-// HandlerSet provides a handler with a real HTTPMessageReceiver and pubsub MultiTopicDecoupleSink.
+// HandlerSet provides a handler with a real HTTPMessageReceiver and pubsub
+// DecoupleSink, failing over to a secondary region if configured.
 var HandlerSet wire.ProviderSet = wire.NewSet(
 	NewHandler,
 	NewHTTPMessageReceiver,
-	wire.Bind(new(HttpMessageReceiver), new(*kncloudevents.HttpMessageReceiver)),
-	NewMultiTopicDecoupleSink,
-	wire.Bind(new(DecoupleSink), new(*multiTopicDecoupleSink)),
+	wire.Bind(new(HttpMessageReceiver), new(*HTTPMessageReceiver)),
+	NewDecoupleSink,
 	NewPubsubClient,
 	metrics.NewIngressReporter,
 )
@@ -69,6 +77,9 @@ var HandlerSet wire.ProviderSet = wire.NewSet(
 type DecoupleSink interface {
 	// Send sends the event from a broker to the corresponding decoupling sink.
 	Send(ctx context.Context, ns, broker string, event cev2.Event) protocol.Result
+	// SendBatch sends the events from a broker to the corresponding decoupling
+	// sink, and returns the result of each event in order.
+	SendBatch(ctx context.Context, ns, broker string, events []cev2.Event) []protocol.Result
 }
 
 // HttpMessageReceiver is an interface to listen on http requests.
@@ -82,20 +93,77 @@ type Handler struct {
 	httpReceiver HttpMessageReceiver
 	// decouple is the client to send events to a decouple sink.
 	decouple DecoupleSink
+	// targets is used to look up the metric labels of the brokers.
+	targets  config.ReadonlyTargets
 	logger   *zap.Logger
 	reporter *metrics.IngressReporter
+	// accessLog writes access logs for a sample of the requests.
+	accessLog *accessLogger
+	// maxBodyBytes is the maximum size of the body of a request.
+	maxBodyBytes MaxBodyBytes
+	// publishStatus reports the publish failures requiring an action from
+	// the user. It may be nil.
+	publishStatus *status.Reporter
+	// encrypter encrypts the data of the events sent to brokers with an
+	// encryption key. If nil, events sent to such brokers are rejected.
+	encrypter *encryption.Encrypter
+	// schemas validates the data of the events against the EventSchemas of
+	// their types. It may be nil.
+	schemas *schema.Registry
+	// tokens authenticates the requests sent to brokers requiring a bearer
+	// token. If nil, requests sent to such brokers are rejected.
+	tokens *auth.Tokens
 }
 
 // NewHandler creates a new ingress handler.
-func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, reporter *metrics.IngressReporter) *Handler {
+func NewHandler(ctx context.Context, httpReceiver HttpMessageReceiver, decouple DecoupleSink, targets config.ReadonlyTargets, reporter *metrics.IngressReporter, maxBodyBytes MaxBodyBytes) *Handler {
+	logger := logging.FromContext(ctx)
 	return &Handler{
 		httpReceiver: httpReceiver,
 		decouple:     decouple,
+		targets:      targets,
 		reporter:     reporter,
-		logger:       logging.FromContext(ctx),
+		logger:       logger,
+		accessLog:    newAccessLogger(logger),
+		maxBodyBytes: maxBodyBytes,
 	}
 }
 
+// UpdateFromObservabilityConfigMap updates the access log sample rate from the
+// config-observability ConfigMap. Invalid configs are ignored.
+func (h *Handler) UpdateFromObservabilityConfigMap(cm *corev1.ConfigMap) {
+	rate, err := accessLogSampleRateFromConfigMap(cm)
+	if err != nil {
+		h.logger.Warn("Failed to parse the access log sample rate, keeping the current one", zap.Error(err))
+		return
+	}
+	h.accessLog.setSampleRate(rate)
+}
+
+// SetPublishStatusReporter sets the reporter of the publish failures of the
+// brokers. It must be called before Start.
+func (h *Handler) SetPublishStatusReporter(r *status.Reporter) {
+	h.publishStatus = r
+}
+
+// SetEncrypter sets the encrypter of the data of the events sent to brokers
+// with an encryption key. It must be called before Start.
+func (h *Handler) SetEncrypter(e *encryption.Encrypter) {
+	h.encrypter = e
+}
+
+// SetSchemas sets the registry of the EventSchemas the events are validated
+// against. It must be called before Start.
+func (h *Handler) SetSchemas(r *schema.Registry) {
+	h.schemas = r
+}
+
+// SetTokens sets the bearer tokens of the brokers requiring one. It must be
+// called before Start.
+func (h *Handler) SetTokens(t *auth.Tokens) {
+	h.tokens = t
+}
+
 // Start blocks to receive events over HTTP.
 func (h *Handler) Start(ctx context.Context) error {
 	return h.httpReceiver.StartListen(ctx, h)
@@ -104,8 +172,13 @@ func (h *Handler) Start(ctx context.Context) error {
 // ServeHTTP implements net/http Handler interface method.
 // 1. Performs basic validation of the request.
 // 2. Parse request URL to get namespace and broker.
-// 3. Convert request to event.
-// 4. Send event to decouple sink.
+// 3. Authenticate the request if the broker requires a bearer token.
+// 4. Reject requests whose body is too large or too slow.
+// 5. Convert request to event, or to events in batched content mode.
+// 6. Check that the event policy of the broker allows the event.
+// 7. Validate the event data against the EventSchema of its type, if any.
+// 8. Encrypt the event data if the broker has an encryption key.
+// 9. Send event to decouple sink.
 func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Request) {
 	if request.URL.Path == heathCheckPath {
 		response.WriteHeader(nethttp.StatusOK)
@@ -114,8 +187,18 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 
 	ctx := request.Context()
 	h.logger.Debug("Serving http", zap.Any("headers", request.Header))
+
+	// The access log fields are filled in as the request is served.
+	entry := accessLogEntry{method: request.Method}
+	startTime := time.Now()
+	defer func() {
+		entry.latency = time.Since(startTime)
+		h.accessLog.log(entry)
+	}()
+
 	if request.Method != nethttp.MethodPost {
-		response.WriteHeader(nethttp.StatusMethodNotAllowed)
+		entry.statusCode = nethttp.StatusMethodNotAllowed
+		writeProblem(response, nethttp.StatusMethodNotAllowed, ReasonMethodNotAllowed, "Only POST is allowed.")
 		return
 	}
 
@@ -124,20 +207,47 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	if len(pieces) != 3 {
 		msg := fmt.Sprintf("Malformed request path. want: '/<ns>/<broker>'; got: %v..", request.URL.Path)
 		h.logger.Info(msg)
-		nethttp.Error(response, msg, nethttp.StatusNotFound)
+		entry.statusCode = nethttp.StatusNotFound
+		writeProblem(response, nethttp.StatusNotFound, ReasonMalformedPath, msg)
 		return
 	}
 	broker := types.NamespacedName{
 		Namespace: pieces[1],
 		Name:      pieces[2],
 	}
+	entry.broker = broker
+
+	if err := h.authenticate(broker, request); err != nil {
+		h.logger.Debug("Failed to authenticate request", zap.Any("broker", broker), zap.Error(err))
+		response.Header().Set("WWW-Authenticate", `Bearer realm="knative-gcp-broker"`)
+		msg := fmt.Sprintf("A bearer token accepted by broker %s is required.", broker)
+		h.reject(ctx, response, broker, &entry, nethttp.StatusUnauthorized, ReasonUnauthorized, msg)
+		return
+	}
+
+	// Requests that announce a body over the limit are rejected without
+	// reading it. Other bodies are cut off once they exceed the limit.
+	if h.maxBodyBytes > 0 && request.ContentLength > int64(h.maxBodyBytes) {
+		msg := fmt.Sprintf("The request body of %d bytes exceeds the limit of %d bytes.", request.ContentLength, h.maxBodyBytes)
+		h.reject(ctx, response, broker, &entry, nethttp.StatusRequestEntityTooLarge, ReasonRequestTooLarge, msg)
+		return
+	}
+	body := newRequestBody(request.Body, h.maxBodyBytes)
+	request.Body = body
+
+	if isBatch(request) {
+		h.serveBatch(ctx, response, request, broker, body, &entry)
+		return
+	}
 
 	event, err := h.toEvent(request)
 	if err != nil {
-		nethttp.Error(response, err.Error(), nethttp.StatusBadRequest)
+		h.writeReadError(ctx, response, broker, body, &entry, err)
 		return
 	}
 
+	entry.eventID = event.ID()
+	entry.eventType = event.Type()
 	event.SetExtension(EventArrivalTime, cev2.Timestamp{Time: time.Now()})
 
 	ctx, span := trace.StartSpan(ctx, kntracing.BrokerMessagingDestination(broker))
@@ -157,23 +267,285 @@ func (h *Handler) ServeHTTP(response nethttp.ResponseWriter, request *nethttp.Re
 	statusCode := nethttp.StatusAccepted
 	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
 	defer cancel()
-	defer func() { h.reportMetrics(request.Context(), broker, event, statusCode) }()
+	accepted := h.accepts(broker, event)
+	var validation *schema.Validation
+	if accepted {
+		validation = h.schemas.Validate(broker.Namespace, event)
+	}
+	defer func() {
+		entry.statusCode = statusCode
+		h.reportMetrics(request.Context(), broker, event, statusCode, validation.Result())
+	}()
+	if !accepted {
+		msg := fmt.Sprintf("The event policy of broker %s doesn't allow events of type %q from source %q.", broker, event.Type(), event.Source())
+		h.logger.Debug(msg)
+		statusCode = nethttp.StatusForbidden
+		writeProblem(response, statusCode, ReasonEventNotAllowed, msg)
+		return
+	}
+	if validation != nil && validation.Reject {
+		msg := fmt.Sprintf("The data of the event doesn't conform to the schema of type %q: %v.", event.Type(), validation.Err)
+		h.logger.Debug(msg)
+		statusCode = nethttp.StatusBadRequest
+		writeProblem(response, statusCode, ReasonSchemaValidationFailed, msg)
+		return
+	}
+	if err := h.encrypt(ctx, broker, event); err != nil {
+		msg := fmt.Sprintf("Error encrypting event for broker %s: %v.", broker, err)
+		h.logger.Error(msg)
+		statusCode = nethttp.StatusInternalServerError
+		writeProblem(response, statusCode, ReasonEncryptionFailed, msg)
+		return
+	}
 	if res := h.decouple.Send(ctx, broker.Namespace, broker.Name, *event); !cev2.IsACK(res) {
 		msg := fmt.Sprintf("Error publishing to PubSub for broker %s. event: %+v, err: %v.", broker, event, res)
 		h.logger.Error(msg)
-		statusCode = nethttp.StatusInternalServerError
-		if errors.Is(res, ErrNotFound) {
-			statusCode = nethttp.StatusNotFound
-		} else if errors.Is(res, ErrNotReady) {
-			statusCode = nethttp.StatusServiceUnavailable
+		h.publishStatus.Report(broker, status.DecoupleQueue, res)
+		var reason string
+		statusCode, reason = publishErrorStatus(res)
+		writeProblem(response, statusCode, reason, msg)
+		return
+	}
+
+	h.publishStatus.Report(broker, status.DecoupleQueue, nil)
+	response.WriteHeader(statusCode)
+}
+
+// serveBatch sends the events of a request in batched content mode to the
+// decouple sink. The request is only accepted if all of its events are
+// published. Otherwise the response describes the first failure, and the
+// producer is expected to send the batch again.
+func (h *Handler) serveBatch(ctx context.Context, response nethttp.ResponseWriter, request *nethttp.Request, broker types.NamespacedName, body *requestBody, entry *accessLogEntry) {
+	events, err := h.toEvents(request)
+	if err != nil {
+		h.writeReadError(ctx, response, broker, body, entry, err)
+		return
+	}
+	arrivalTime := cev2.Timestamp{Time: time.Now()}
+	for i := range events {
+		events[i].SetExtension(EventArrivalTime, arrivalTime)
+	}
+
+	ctx, span := trace.StartSpan(ctx, kntracing.BrokerMessagingDestination(broker))
+	defer span.End()
+	if span.IsRecordingEvents() {
+		span.AddAttributes(
+			kntracing.MessagingSystemAttribute,
+			tracing.PubSubProtocolAttribute,
+			kntracing.BrokerMessagingDestinationAttribute(broker),
+		)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, decoupleSinkTimeout)
+	defer cancel()
+	// A single event the broker doesn't allow forbids the whole batch.
+	for i := range events {
+		if h.accepts(broker, &events[i]) {
+			continue
+		}
+		msg := fmt.Sprintf("The event policy of broker %s doesn't allow event %d of the batch, of type %q from source %q.", broker, i, events[i].Type(), events[i].Source())
+		h.logger.Debug(msg)
+		entry.statusCode = nethttp.StatusForbidden
+		for j := range events {
+			h.reportMetrics(request.Context(), broker, &events[j], entry.statusCode, "")
 		}
-		nethttp.Error(response, msg, statusCode)
+		writeProblem(response, entry.statusCode, ReasonEventNotAllowed, msg)
+		return
+	}
+	eventPtrs := make([]*cev2.Event, len(events))
+	validations := make([]string, len(events))
+	rejected := -1
+	var rejectErr error
+	for i := range events {
+		eventPtrs[i] = &events[i]
+		v := h.schemas.Validate(broker.Namespace, &events[i])
+		validations[i] = v.Result()
+		if v != nil && v.Reject && rejected < 0 {
+			rejected, rejectErr = i, v.Err
+		}
+	}
+	// Batches are all or nothing, so a single event that doesn't conform to
+	// its schema rejects the whole batch.
+	if rejected >= 0 {
+		msg := fmt.Sprintf("The data of event %d of the batch doesn't conform to the schema of type %q: %v.", rejected, events[rejected].Type(), rejectErr)
+		h.logger.Debug(msg)
+		entry.statusCode = nethttp.StatusBadRequest
+		for i := range events {
+			h.reportMetrics(request.Context(), broker, &events[i], entry.statusCode, validations[i])
+		}
+		writeProblem(response, entry.statusCode, ReasonSchemaValidationFailed, msg)
+		return
+	}
+	if err := h.encrypt(ctx, broker, eventPtrs...); err != nil {
+		msg := fmt.Sprintf("Error encrypting %d events for broker %s: %v.", len(events), broker, err)
+		h.logger.Error(msg)
+		entry.statusCode = nethttp.StatusInternalServerError
+		for i := range events {
+			h.reportMetrics(request.Context(), broker, &events[i], entry.statusCode, validations[i])
+		}
+		writeProblem(response, entry.statusCode, ReasonEncryptionFailed, msg)
+		return
+	}
+	results := h.decouple.SendBatch(ctx, broker.Namespace, broker.Name, events)
+
+	statusCode := nethttp.StatusAccepted
+	var reason string
+	var failed int
+	var firstErr error
+	for i, res := range results {
+		eventStatusCode := nethttp.StatusAccepted
+		if !cev2.IsACK(res) {
+			var eventReason string
+			eventStatusCode, eventReason = publishErrorStatus(res)
+			if failed == 0 {
+				statusCode, reason, firstErr = eventStatusCode, eventReason, res
+			}
+			failed++
+			h.publishStatus.Report(broker, status.DecoupleQueue, res)
+		} else {
+			h.publishStatus.Report(broker, status.DecoupleQueue, nil)
+		}
+		h.reportMetrics(request.Context(), broker, &events[i], eventStatusCode, validations[i])
+	}
+	entry.statusCode = statusCode
+	if failed > 0 {
+		msg := fmt.Sprintf("Error publishing %d of %d events to PubSub for broker %s. first err: %v.", failed, len(events), broker, firstErr)
+		h.logger.Error(msg)
+		writeProblem(response, statusCode, reason, msg)
 		return
 	}
 
 	response.WriteHeader(statusCode)
 }
 
+// writeReadError responds to a request whose events could not be read. Bodies
+// that were too large or too slow are rejected, others are invalid events.
+func (h *Handler) writeReadError(ctx context.Context, response nethttp.ResponseWriter, broker types.NamespacedName, body *requestBody, entry *accessLogEntry, err error) {
+	switch {
+	case body.tooLarge:
+		msg := fmt.Sprintf("The request body exceeds the limit of %d bytes.", h.maxBodyBytes)
+		h.reject(ctx, response, broker, entry, nethttp.StatusRequestEntityTooLarge, ReasonRequestTooLarge, msg)
+	case body.timedOut:
+		h.reject(ctx, response, broker, entry, nethttp.StatusRequestTimeout, ReasonRequestTimeout, "Timed out reading the request body.")
+	default:
+		entry.statusCode = nethttp.StatusBadRequest
+		writeProblem(response, nethttp.StatusBadRequest, ReasonInvalidEvent, err.Error())
+	}
+}
+
+// reject responds to a request that is rejected before its events are read,
+// and counts it in the rejected request metric.
+func (h *Handler) reject(ctx context.Context, response nethttp.ResponseWriter, broker types.NamespacedName, entry *accessLogEntry, statusCode int, reason, msg string) {
+	h.logger.Info("Rejected request", zap.Any("broker", broker), zap.String("reason", reason), zap.String("detail", msg))
+	entry.statusCode = statusCode
+	writeProblem(response, statusCode, reason, msg)
+	if h.reporter == nil {
+		return
+	}
+	args := metrics.IngressRejectArgs{
+		Namespace:    broker.Namespace,
+		Broker:       broker.Name,
+		Reason:       reason,
+		ResponseCode: statusCode,
+		MetricLabels: h.metricLabels(broker),
+	}
+	if err := h.reporter.ReportRejectedRequest(ctx, args); err != nil {
+		h.logger.Warn("Failed to record metrics.", zap.Any("namespace", broker.Namespace), zap.Any("broker", broker.Name), zap.Error(err))
+	}
+}
+
+// authenticate returns an error if the broker requires a bearer token and the
+// request has none of its tokens. Brokers that are not known yet are left to
+// the decouple sink to report.
+func (h *Handler) authenticate(broker types.NamespacedName, request *nethttp.Request) error {
+	if h.targets == nil {
+		return nil
+	}
+	b, ok := h.targets.GetBrokerByKey(config.BrokerKey(broker.Namespace, broker.Name))
+	if !ok || b.IngressAuthKey == "" {
+		return nil
+	}
+	if h.tokens == nil {
+		return errors.New("request authentication is not enabled in the ingress")
+	}
+	return h.tokens.Authenticate(b.IngressAuthKey, request)
+}
+
+// accepts returns true if the event policy of the broker allows the event.
+// Brokers that are not known yet are left to the decouple sink to report.
+func (h *Handler) accepts(broker types.NamespacedName, event *cev2.Event) bool {
+	if h.targets == nil {
+		return true
+	}
+	b, ok := h.targets.GetBrokerByKey(config.BrokerKey(broker.Namespace, broker.Name))
+	return !ok || b.Accepts(event.Type(), event.Source())
+}
+
+// encrypt encrypts the data of the events if the broker has an encryption key.
+// Brokers that are not known yet are left to the decouple sink to report.
+func (h *Handler) encrypt(ctx context.Context, broker types.NamespacedName, events ...*cev2.Event) error {
+	if h.targets == nil {
+		return nil
+	}
+	b, ok := h.targets.GetBrokerByKey(config.BrokerKey(broker.Namespace, broker.Name))
+	if !ok || b.EncryptionKey == "" {
+		return nil
+	}
+	if h.encrypter == nil {
+		return errors.New("event encryption is not enabled in the ingress")
+	}
+	for _, e := range events {
+		if err := h.encrypter.Encrypt(ctx, b, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishErrorStatus returns the status code and the reason of the response to
+// an event that could not be sent to the decouple sink.
+func publishErrorStatus(res protocol.Result) (int, string) {
+	switch {
+	case errors.Is(res, ErrNotFound):
+		return nethttp.StatusNotFound, ReasonBrokerNotFound
+	case errors.Is(res, ErrNotReady):
+		return nethttp.StatusServiceUnavailable, ReasonBrokerNotReady
+	default:
+		return nethttp.StatusInternalServerError, ReasonPublishFailed
+	}
+}
+
+// isBatch returns true if the request is in batched content mode.
+func isBatch(request *nethttp.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	return err == nil && mediaType == cev2.ApplicationCloudEventsBatchJSON
+}
+
+// toEvents converts an http request in batched content mode to events.
+func (h *Handler) toEvents(request *nethttp.Request) ([]cev2.Event, error) {
+	var events []cev2.Event
+	if err := json.NewDecoder(request.Body).Decode(&events); err != nil {
+		msg := fmt.Sprintf("Failed to convert request to batch of events: %v", err)
+		h.logger.Debug(msg)
+		return nil, errors.New(msg)
+	}
+	now := time.Now()
+	for i := range events {
+		if events[i].Time().IsZero() {
+			events[i].SetTime(now)
+		}
+		if err := events[i].Validate(); err != nil {
+			msg := fmt.Sprintf("Event %d of the batch is invalid: %v", i, err)
+			h.logger.Debug(msg)
+			return nil, errors.New(msg)
+		}
+		// Clients can't make the fanout handle their events as if the
+		// broker had set its internal extensions.
+		eventutil.DeleteInternalExtensions(&events[i])
+	}
+	return events, nil
+}
+
 // toEvent converts an http request to an event.
 func (h *Handler) toEvent(request *nethttp.Request) (*cev2.Event, error) {
 	message := http.NewMessageFromHttpRequest(request)
@@ -194,17 +566,32 @@ func (h *Handler) toEvent(request *nethttp.Request) (*cev2.Event, error) {
 		h.logger.Error(msg)
 		return nil, errors.New(msg)
 	}
+	// Clients can't make the fanout handle their events as if the broker
+	// had set its internal extensions.
+	eventutil.DeleteInternalExtensions(event)
 	return event, nil
 }
 
-func (h *Handler) reportMetrics(ctx context.Context, broker types.NamespacedName, event *cev2.Event, statusCode int) {
+func (h *Handler) reportMetrics(ctx context.Context, broker types.NamespacedName, event *cev2.Event, statusCode int, schemaValidation string) {
 	args := metrics.IngressReportArgs{
-		Namespace:    broker.Namespace,
-		Broker:       broker.Name,
-		EventType:    event.Type(),
-		ResponseCode: statusCode,
+		Namespace:        broker.Namespace,
+		Broker:           broker.Name,
+		EventType:        event.Type(),
+		ResponseCode:     statusCode,
+		SchemaValidation: schemaValidation,
+		MetricLabels:     h.metricLabels(broker),
 	}
 	if err := h.reporter.ReportEventCount(ctx, args); err != nil {
 		h.logger.Warn("Failed to record metrics.", zap.Any("namespace", broker.Namespace), zap.Any("broker", broker.Name), zap.Error(err))
 	}
 }
+
+// metricLabels returns the metric labels of the broker, if it is known.
+func (h *Handler) metricLabels(broker types.NamespacedName) map[string]string {
+	if h.targets != nil {
+		if b, ok := h.targets.GetBrokerByKey(config.BrokerKey(broker.Namespace, broker.Name)); ok {
+			return b.MetricLabels
+		}
+	}
+	return nil
+}
@@ -21,8 +21,15 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	nethttp "net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -33,9 +40,16 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/extensions"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/knative-gcp/pkg/broker/auth"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/encryption"
+	enctesting "github.com/google/knative-gcp/pkg/broker/encryption/testing"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/metrics"
 	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
 	kgcptesting "github.com/google/knative-gcp/pkg/testing"
@@ -43,6 +57,13 @@ import (
 	"go.uber.org/zap/zaptest"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubefake "k8s.io/client-go/kubernetes/fake"
 	"knative.dev/eventing/pkg/kncloudevents"
 	"knative.dev/pkg/logging"
 	logtest "knative.dev/pkg/logging/testing"
@@ -110,6 +131,7 @@ type testCase struct {
 	body           map[string]string
 	header         nethttp.Header
 	wantCode       int
+	wantReason     string
 	wantMetricTags map[string]string
 	wantEventCount int64
 	// additional assertions on the output event.
@@ -162,29 +184,33 @@ func TestHandler(t *testing.T) {
 			eventAssertions: []eventAssertion{assertExtensionsExist(EventArrivalTime), assertTraceID(traceID)},
 		},
 		{
-			name:     "valid event but unsupported http method",
-			method:   "PUT",
-			path:     "/ns1/broker1",
-			event:    createTestEvent("test-event"),
-			wantCode: nethttp.StatusMethodNotAllowed,
+			name:       "valid event but unsupported http method",
+			method:     "PUT",
+			path:       "/ns1/broker1",
+			event:      createTestEvent("test-event"),
+			wantCode:   nethttp.StatusMethodNotAllowed,
+			wantReason: ReasonMethodNotAllowed,
 		},
 		{
-			name:     "malformed path",
-			path:     "/ns1/broker1/and/something/else",
-			event:    createTestEvent("test-event"),
-			wantCode: nethttp.StatusNotFound,
+			name:       "malformed path",
+			path:       "/ns1/broker1/and/something/else",
+			event:      createTestEvent("test-event"),
+			wantCode:   nethttp.StatusNotFound,
+			wantReason: ReasonMalformedPath,
 		},
 		{
-			name:     "request is not an event",
-			path:     "/ns1/broker1",
-			wantCode: nethttp.StatusBadRequest,
-			header:   nethttp.Header{},
+			name:       "request is not an event",
+			path:       "/ns1/broker1",
+			wantCode:   nethttp.StatusBadRequest,
+			wantReason: ReasonInvalidEvent,
+			header:     nethttp.Header{},
 		},
 		{
 			name:           "wrong path - broker doesn't exist in given namespace",
 			path:           "/ns1/broker-not-exist",
 			event:          createTestEvent("test-event"),
 			wantCode:       nethttp.StatusNotFound,
+			wantReason:     ReasonBrokerNotFound,
 			wantEventCount: 1,
 			wantMetricTags: map[string]string{
 				metricskey.LabelNamespaceName:     "ns1",
@@ -201,6 +227,7 @@ func TestHandler(t *testing.T) {
 			path:           "/ns-not-exist/broker1",
 			event:          createTestEvent("test-event"),
 			wantCode:       nethttp.StatusNotFound,
+			wantReason:     ReasonBrokerNotFound,
 			wantEventCount: 1,
 			wantMetricTags: map[string]string{
 				metricskey.LabelNamespaceName:     "ns-not-exist",
@@ -217,6 +244,7 @@ func TestHandler(t *testing.T) {
 			path:           "/ns4/broker-not-ready",
 			event:          createTestEvent("test-event"),
 			wantCode:       nethttp.StatusServiceUnavailable,
+			wantReason:     ReasonBrokerNotReady,
 			wantEventCount: 1,
 			wantMetricTags: map[string]string{
 				metricskey.LabelNamespaceName:     "ns4",
@@ -233,6 +261,7 @@ func TestHandler(t *testing.T) {
 			path:           "/ns2/broker2",
 			event:          createTestEvent("test-event"),
 			wantCode:       nethttp.StatusInternalServerError,
+			wantReason:     ReasonPublishFailed,
 			wantEventCount: 1,
 			wantMetricTags: map[string]string{
 				metricskey.LabelNamespaceName:     "ns2",
@@ -249,6 +278,7 @@ func TestHandler(t *testing.T) {
 			path:           "/ns3/broker3",
 			event:          createTestEvent("test-event"),
 			wantCode:       nethttp.StatusInternalServerError,
+			wantReason:     ReasonPublishFailed,
 			wantEventCount: 1,
 			wantMetricTags: map[string]string{
 				metricskey.LabelNamespaceName:     "ns3",
@@ -284,6 +314,9 @@ func TestHandler(t *testing.T) {
 			if res.StatusCode != tc.wantCode {
 				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, tc.wantCode)
 			}
+			if tc.wantReason != "" {
+				verifyProblem(t, res, tc)
+			}
 			verifyMetrics(t, tc)
 
 			// If event is accepted, check that it's stored in the decouple sink.
@@ -317,6 +350,662 @@ func TestHandler(t *testing.T) {
 	}
 }
 
+func TestHandlerBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		wantCode       int
+		wantReason     string
+		wantEventIDs   []string
+		wantMetricTags map[string]string
+		wantEventCount int64
+	}{
+		{
+			name:         "happy case",
+			path:         "/ns1/broker1",
+			body:         batchBody(t, createTestEvent("test-event-1"), createTestEvent("test-event-2"), createTestEvent("test-event-3")),
+			wantCode:     nethttp.StatusAccepted,
+			wantEventIDs: []string{"test-event-1", "test-event-2", "test-event-3"},
+			wantMetricTags: map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker1",
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "202",
+				metricskey.LabelResponseCodeClass: "2xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			wantEventCount: 3,
+		},
+		{
+			name:     "empty batch",
+			path:     "/ns1/broker1",
+			body:     "[]",
+			wantCode: nethttp.StatusAccepted,
+		},
+		{
+			name:       "malformed batch",
+			path:       "/ns1/broker1",
+			body:       `{"id":"test-event"}`,
+			wantCode:   nethttp.StatusBadRequest,
+			wantReason: ReasonInvalidEvent,
+		},
+		{
+			name:       "invalid event in batch",
+			path:       "/ns1/broker1",
+			body:       `[{"specversion":"1.0","id":"test-event","source":"test-source"}]`,
+			wantCode:   nethttp.StatusBadRequest,
+			wantReason: ReasonInvalidEvent,
+		},
+		{
+			name:       "broker doesn't exist",
+			path:       "/ns1/broker-not-exist",
+			body:       batchBody(t, createTestEvent("test-event-1"), createTestEvent("test-event-2")),
+			wantCode:   nethttp.StatusNotFound,
+			wantReason: ReasonBrokerNotFound,
+			wantMetricTags: map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker-not-exist",
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      "404",
+				metricskey.LabelResponseCodeClass: "4xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			},
+			wantEventCount: 2,
+		},
+	}
+
+	client := nethttp.Client{}
+	defer client.CloseIdleConnections()
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+			ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+			defer cancel()
+
+			psSrv := pstest.NewServer()
+			defer psSrv.Close()
+
+			url := createAndStartIngress(ctx, t, psSrv)
+			rec := setupTestReceiver(ctx, t, psSrv)
+
+			request, err := nethttp.NewRequest(nethttp.MethodPost, url+tc.path, bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			request.Header.Set("Content-Type", cloudevents.ApplicationCloudEventsBatchJSON)
+			res, err := client.Do(request)
+			if err != nil {
+				t.Fatalf("Unexpected error from http client: %v", err)
+			}
+			if res.StatusCode != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, tc.wantCode)
+			}
+			if tc.wantReason != "" {
+				verifyProblem(t, res, testCase{wantCode: tc.wantCode, wantReason: tc.wantReason})
+			}
+			verifyMetrics(t, testCase{wantMetricTags: tc.wantMetricTags, wantEventCount: tc.wantEventCount})
+
+			var gotEventIDs []string
+			for range tc.wantEventIDs {
+				m, err := rec.Receive(ctx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				savedToSink, err := binding.ToEvent(ctx, m)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := savedToSink.Extensions()[EventArrivalTime]; !ok {
+					t.Errorf("Extension %v doesn't exist.", EventArrivalTime)
+				}
+				gotEventIDs = append(gotEventIDs, savedToSink.ID())
+			}
+			sort.Strings(gotEventIDs)
+			if diff := cmp.Diff(tc.wantEventIDs, gotEventIDs); diff != "" {
+				t.Errorf("Saved event IDs (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestHandlerBodyLimit(t *testing.T) {
+	const maxBodyBytes = 1024
+	largeData := strings.Repeat("a", 2*maxBodyBytes)
+	binaryHeader := nethttp.Header{
+		"Ce-Specversion": {"1.0"},
+		"Ce-Id":          {"test-event"},
+		"Ce-Source":      {"test-source"},
+		"Ce-Type":        {eventType},
+		"Content-Type":   {"text/plain"},
+	}
+	tests := []struct {
+		name string
+		// body is read by the handler. If contentLength is -1, the size of
+		// the body is not announced.
+		body          io.Reader
+		contentLength int64
+		header        nethttp.Header
+		wantCode      int
+		wantReason    string
+	}{
+		{
+			name:          "announced body too large",
+			body:          strings.NewReader(largeData),
+			contentLength: int64(len(largeData)),
+			header:        binaryHeader,
+			wantCode:      nethttp.StatusRequestEntityTooLarge,
+			wantReason:    ReasonRequestTooLarge,
+		},
+		{
+			name:          "streamed body too large",
+			body:          strings.NewReader(largeData),
+			contentLength: -1,
+			header:        binaryHeader,
+			wantCode:      nethttp.StatusRequestEntityTooLarge,
+			wantReason:    ReasonRequestTooLarge,
+		},
+		{
+			name:          "streamed structured event too large",
+			body:          strings.NewReader(`{"specversion":"1.0","id":"test-event","source":"test-source","type":"test-event-type","data":"` + largeData + `"}`),
+			contentLength: -1,
+			header:        nethttp.Header{"Content-Type": {cloudevents.ApplicationCloudEventsJSON}},
+			wantCode:      nethttp.StatusRequestEntityTooLarge,
+			wantReason:    ReasonRequestTooLarge,
+		},
+		{
+			name:          "streamed batch too large",
+			body:          strings.NewReader(`[{"specversion":"1.0","id":"test-event","source":"test-source","type":"test-event-type","data":"` + largeData + `"}]`),
+			contentLength: -1,
+			header:        nethttp.Header{"Content-Type": {cloudevents.ApplicationCloudEventsBatchJSON}},
+			wantCode:      nethttp.StatusRequestEntityTooLarge,
+			wantReason:    ReasonRequestTooLarge,
+		},
+		{
+			name:          "body read timed out",
+			body:          io.MultiReader(strings.NewReader(`[{"specversion":"1.0"`), timeoutReader{}),
+			contentLength: -1,
+			header:        nethttp.Header{"Content-Type": {cloudevents.ApplicationCloudEventsBatchJSON}},
+			wantCode:      nethttp.StatusRequestTimeout,
+			wantReason:    ReasonRequestTimeout,
+		},
+		{
+			name:          "invalid event within the limit",
+			body:          strings.NewReader(`[{"specversion":"1.0"}]`),
+			contentLength: -1,
+			header:        nethttp.Header{"Content-Type": {cloudevents.ApplicationCloudEventsBatchJSON}},
+			wantCode:      nethttp.StatusBadRequest,
+			wantReason:    ReasonInvalidEvent,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := NewHandler(ctx, nil, nil, memory.NewTargets(brokerConfig), statsReporter, maxBodyBytes)
+
+			request := httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1", tc.body)
+			request.ContentLength = tc.contentLength
+			request.Header = tc.header
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, request)
+
+			res := w.Result()
+			if res.StatusCode != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, tc.wantCode)
+			}
+			verifyProblem(t, res, testCase{wantCode: tc.wantCode, wantReason: tc.wantReason})
+			metricstest.CheckStatsNotReported(t, "event_count")
+			if tc.wantReason == ReasonInvalidEvent {
+				metricstest.CheckStatsNotReported(t, "rejected_request_count")
+				return
+			}
+			metricstest.CheckCountData(t, "rejected_request_count", map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker1",
+				"reject_reason":                   tc.wantReason,
+				metricskey.LabelResponseCode:      strconv.Itoa(tc.wantCode),
+				metricskey.LabelResponseCodeClass: "4xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			}, 1)
+		})
+	}
+}
+
+func TestHandlerPublishStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logtest.TestLogger(t)))
+	defer cancel()
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := kubefake.NewSimpleClientset()
+	publishStatus := status.NewReporter(ctx, kubeClient, "cloud-run-events", pod)
+	go publishStatus.Run(ctx, 10*time.Millisecond)
+
+	decouple := &failingDecoupleSink{err: grpcstatus.Error(codes.ResourceExhausted, "quota exceeded")}
+	h := NewHandler(ctx, nil, decouple, memory.NewTargets(brokerConfig), statsReporter, 0)
+	h.SetPublishStatusReporter(publishStatus)
+
+	request := createRequest(testCase{event: createTestEvent("test-event")}, "/ns1/broker1")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request)
+	if got := w.Result().StatusCode; got != nethttp.StatusInternalServerError {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", got, nethttp.StatusInternalServerError)
+	}
+
+	broker := types.NamespacedName{Namespace: "ns1", Name: "broker1"}
+	var failure *status.PublishFailure
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		cm, err := kubeClient.CoreV1().ConfigMaps("cloud-run-events").Get(status.ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		failure = status.LatestFailure(cm, broker, time.Now().Add(-status.FailureExpiry))
+		return failure != nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Publish failure was not reported: %v", err)
+	}
+	if failure.Reason != status.ReasonQuotaExceeded || failure.Queue != status.DecoupleQueue {
+		t.Errorf("Unexpected publish failure %+v", failure)
+	}
+}
+
+func TestHandlerEncryption(t *testing.T) {
+	ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"ns1/broker1": {
+				Id:            "b-uid-1",
+				Name:          "broker1",
+				Namespace:     "ns1",
+				DecoupleQueue: &config.Queue{Topic: topicID},
+				State:         config.State_READY,
+				EncryptionKey: "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+			},
+			"ns1/broker2": {
+				Id:            "b-uid-2",
+				Name:          "broker2",
+				Namespace:     "ns1",
+				DecoupleQueue: &config.Queue{Topic: topicID},
+				State:         config.State_READY,
+			},
+		},
+	})
+	wrapper := &enctesting.FakeKeyWrapper{}
+
+	t.Run("encrypted", func(t *testing.T) {
+		decouple := &recordingDecoupleSink{}
+		h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
+		h.SetEncrypter(encryption.NewEncrypter(wrapper))
+
+		tc := testCase{
+			event: createTestEvent("test-event"),
+			body:  map[string]string{"hello": "world"},
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, createRequest(tc, "/ns1/broker1"))
+		if got := w.Result().StatusCode; got != nethttp.StatusAccepted {
+			t.Fatalf("StatusCode mismatch. got: %v, want: %v", got, nethttp.StatusAccepted)
+		}
+		if len(decouple.events) != 1 {
+			t.Fatalf("Got %d events sent to the decouple sink, want 1", len(decouple.events))
+		}
+		want := `{"hello":"world"}`
+		got := decouple.events[0]
+		if string(got.Data()) == want {
+			t.Errorf("Event data was sent unencrypted: %s", got.Data())
+		}
+		b, _ := targets.GetBrokerByKey("ns1/broker1")
+		if err := encryption.NewDecrypter(wrapper).Decrypt(ctx, b, &got); err != nil {
+			t.Fatalf("Failed to decrypt event: %v", err)
+		}
+		if diff := cmp.Diff(want, string(got.Data())); diff != "" {
+			t.Errorf("Decrypted data mismatch (-want, +got): %v", diff)
+		}
+	})
+
+	t.Run("client internal extensions deleted", func(t *testing.T) {
+		decouple := &recordingDecoupleSink{}
+		h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
+		h.SetEncrypter(encryption.NewEncrypter(wrapper))
+
+		event := createTestEvent("test-event")
+		event.SetExtension(encryption.KeyExtension, "projects/p/locations/global/keyRings/r/cryptoKeys/other")
+		event.SetExtension(encryption.DataKeyExtension, "a2V5")
+		event.SetExtension(encryption.ContentTypeExtension, "text/plain")
+		event.SetExtension("kgcpdelivered", "http://subscriber")
+		event.SetExtension("kgcpcontentencoding", "gzip")
+		event.SetExtension("kgcpattempts", "100")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, createRequest(testCase{event: event}, "/ns1/broker2"))
+		if got := w.Result().StatusCode; got != nethttp.StatusAccepted {
+			t.Fatalf("StatusCode mismatch. got: %v, want: %v", got, nethttp.StatusAccepted)
+		}
+		if len(decouple.events) != 1 {
+			t.Fatalf("Got %d events sent to the decouple sink, want 1", len(decouple.events))
+		}
+		for _, name := range []string{encryption.KeyExtension, encryption.DataKeyExtension, encryption.ContentTypeExtension, "kgcpdelivered", "kgcpcontentencoding", "kgcpattempts"} {
+			if v, ok := decouple.events[0].Extensions()[name]; ok {
+				t.Errorf("Extension %s = %v was not deleted", name, v)
+			}
+		}
+	})
+
+	t.Run("encryption not enabled", func(t *testing.T) {
+		decouple := &recordingDecoupleSink{}
+		h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
+
+		tc := testCase{
+			event:      createTestEvent("test-event"),
+			wantCode:   nethttp.StatusInternalServerError,
+			wantReason: ReasonEncryptionFailed,
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, createRequest(tc, "/ns1/broker1"))
+		verifyProblem(t, w.Result(), tc)
+		if len(decouple.events) != 0 {
+			t.Errorf("Got %d events sent to the decouple sink, want 0", len(decouple.events))
+		}
+	})
+}
+
+func TestHandlerSchemaValidation(t *testing.T) {
+	ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+	data, err := schema.Encode(map[string]*schema.Entry{
+		"ns1.reject": {
+			Namespace: "ns1",
+			Type:      eventType,
+			Action:    schema.ActionReject,
+			Schema:    json.RawMessage(`{"type":"object","required":["id"]}`),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schemas := schema.NewRegistry(zap.NewNop())
+	schemas.UpdateFromConfigMap(&corev1.ConfigMap{Data: data})
+
+	tests := []struct {
+		name           string
+		body           map[string]string
+		batch          bool
+		wantCode       int
+		wantReason     string
+		wantValidation string
+		wantCount      int64
+		wantSent       int
+	}{{
+		name:           "valid",
+		body:           map[string]string{"id": "1"},
+		wantCode:       nethttp.StatusAccepted,
+		wantValidation: schema.ResultValid,
+		wantCount:      1,
+		wantSent:       1,
+	}, {
+		name:           "invalid",
+		body:           map[string]string{"name": "1"},
+		wantCode:       nethttp.StatusBadRequest,
+		wantReason:     ReasonSchemaValidationFailed,
+		wantValidation: schema.ResultInvalid,
+		wantCount:      1,
+	}, {
+		name:           "invalid batch",
+		batch:          true,
+		wantCode:       nethttp.StatusBadRequest,
+		wantReason:     ReasonSchemaValidationFailed,
+		wantValidation: schema.ResultInvalid,
+		wantCount:      2,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			decouple := &recordingDecoupleSink{}
+			h := NewHandler(ctx, nil, decouple, memory.NewTargets(brokerConfig), statsReporter, 0)
+			h.SetSchemas(schemas)
+
+			var request *nethttp.Request
+			if tc.batch {
+				// Events without data are validated as null, which is not an
+				// object.
+				request = httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1",
+					strings.NewReader(batchBody(t, createTestEvent("test-event-1"), createTestEvent("test-event-2"))))
+				request.Header.Set("Content-Type", cloudevents.ApplicationCloudEventsBatchJSON)
+			} else {
+				request = createRequest(testCase{event: createTestEvent("test-event"), body: tc.body}, "/ns1/broker1")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, request)
+			if tc.wantReason != "" {
+				verifyProblem(t, w.Result(), testCase{wantCode: tc.wantCode, wantReason: tc.wantReason})
+			} else if got := w.Result().StatusCode; got != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", got, tc.wantCode)
+			}
+			if len(decouple.events) != tc.wantSent {
+				t.Errorf("Got %d events sent to the decouple sink, want %d", len(decouple.events), tc.wantSent)
+			}
+			metricstest.CheckCountData(t, "event_count", map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker1",
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      strconv.Itoa(tc.wantCode),
+				metricskey.LabelResponseCodeClass: fmt.Sprintf("%dxx", tc.wantCode/100),
+				"schema_validation":               tc.wantValidation,
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			}, tc.wantCount)
+		})
+	}
+}
+
+func TestHandlerEventPolicy(t *testing.T) {
+	ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+	tests := []struct {
+		name      string
+		broker    *config.Broker
+		batch     bool
+		wantCode  int
+		wantCount int64
+		wantSent  int
+	}{{
+		name:      "no policy",
+		broker:    &config.Broker{},
+		wantCode:  nethttp.StatusAccepted,
+		wantCount: 1,
+		wantSent:  1,
+	}, {
+		name:      "allowed type",
+		broker:    &config.Broker{AllowedEventTypes: []string{"test-event-*"}},
+		wantCode:  nethttp.StatusAccepted,
+		wantCount: 1,
+		wantSent:  1,
+	}, {
+		name:      "type not allowed",
+		broker:    &config.Broker{AllowedEventTypes: []string{"other-type"}},
+		wantCode:  nethttp.StatusForbidden,
+		wantCount: 1,
+	}, {
+		name:      "denied source",
+		broker:    &config.Broker{DeniedEventSources: []string{"test-source"}},
+		wantCode:  nethttp.StatusForbidden,
+		wantCount: 1,
+	}, {
+		name:      "denied batch",
+		broker:    &config.Broker{DeniedEventSources: []string{"test-source"}},
+		batch:     true,
+		wantCode:  nethttp.StatusForbidden,
+		wantCount: 2,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := tc.broker
+			b.Id, b.Name, b.Namespace = "b-uid-1", "broker1", "ns1"
+			b.DecoupleQueue = &config.Queue{Topic: topicID}
+			b.State = config.State_READY
+			targets := memory.NewTargets(&config.TargetsConfig{Brokers: map[string]*config.Broker{"ns1/broker1": b}})
+			decouple := &recordingDecoupleSink{}
+			h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
+
+			var request *nethttp.Request
+			if tc.batch {
+				request = httptest.NewRequest(nethttp.MethodPost, "/ns1/broker1",
+					strings.NewReader(batchBody(t, createTestEvent("test-event-1"), createTestEvent("test-event-2"))))
+				request.Header.Set("Content-Type", cloudevents.ApplicationCloudEventsBatchJSON)
+			} else {
+				request = createRequest(testCase{event: createTestEvent("test-event")}, "/ns1/broker1")
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, request)
+			if tc.wantCode == nethttp.StatusForbidden {
+				verifyProblem(t, w.Result(), testCase{wantCode: tc.wantCode, wantReason: ReasonEventNotAllowed})
+			} else if got := w.Result().StatusCode; got != tc.wantCode {
+				t.Errorf("StatusCode mismatch. got: %v, want: %v", got, tc.wantCode)
+			}
+			if len(decouple.events) != tc.wantSent {
+				t.Errorf("Got %d events sent to the decouple sink, want %d", len(decouple.events), tc.wantSent)
+			}
+			metricstest.CheckCountData(t, "event_count", map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker1",
+				metricskey.LabelEventType:         eventType,
+				metricskey.LabelResponseCode:      strconv.Itoa(tc.wantCode),
+				metricskey.LabelResponseCodeClass: fmt.Sprintf("%dxx", tc.wantCode/100),
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			}, tc.wantCount)
+		})
+	}
+}
+
+func TestHandlerAuthentication(t *testing.T) {
+	ctx := logging.WithLogger(context.Background(), logtest.TestLogger(t))
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "b-uid-1"), []byte(auth.Hash("token")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authKey       string
+		noTokens      bool
+		authorization string
+		wantCode      int
+	}{{
+		name:     "no token required",
+		wantCode: nethttp.StatusAccepted,
+	}, {
+		name:          "valid token",
+		authKey:       "b-uid-1",
+		authorization: "Bearer token",
+		wantCode:      nethttp.StatusAccepted,
+	}, {
+		name:     "missing token",
+		authKey:  "b-uid-1",
+		wantCode: nethttp.StatusUnauthorized,
+	}, {
+		name:          "invalid token",
+		authKey:       "b-uid-1",
+		authorization: "Bearer other",
+		wantCode:      nethttp.StatusUnauthorized,
+	}, {
+		name:          "tokens not written yet",
+		authKey:       "b-uid-2",
+		authorization: "Bearer token",
+		wantCode:      nethttp.StatusUnauthorized,
+	}, {
+		name:          "authentication not enabled",
+		authKey:       "b-uid-1",
+		noTokens:      true,
+		authorization: "Bearer token",
+		wantCode:      nethttp.StatusUnauthorized,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			reportertest.ResetIngressMetrics()
+			statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := &config.Broker{
+				Id:             "b-uid-1",
+				Name:           "broker1",
+				Namespace:      "ns1",
+				DecoupleQueue:  &config.Queue{Topic: topicID},
+				State:          config.State_READY,
+				IngressAuthKey: tc.authKey,
+			}
+			targets := memory.NewTargets(&config.TargetsConfig{Brokers: map[string]*config.Broker{"ns1/broker1": b}})
+			decouple := &recordingDecoupleSink{}
+			h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
+			if !tc.noTokens {
+				h.SetTokens(auth.NewTokens(dir))
+			}
+
+			request := createRequest(testCase{event: createTestEvent("test-event")}, "/ns1/broker1")
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, request)
+
+			res := w.Result()
+			if tc.wantCode != nethttp.StatusUnauthorized {
+				if res.StatusCode != tc.wantCode {
+					t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, tc.wantCode)
+				}
+				if len(decouple.events) != 1 {
+					t.Errorf("Got %d events sent to the decouple sink, want 1", len(decouple.events))
+				}
+				return
+			}
+			verifyProblem(t, res, testCase{wantCode: tc.wantCode, wantReason: ReasonUnauthorized})
+			if got := res.Header.Get("WWW-Authenticate"); !strings.HasPrefix(got, "Bearer") {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge", got)
+			}
+			if len(decouple.events) != 0 {
+				t.Errorf("Got %d events sent to the decouple sink, want 0", len(decouple.events))
+			}
+			metricstest.CheckStatsNotReported(t, "event_count")
+			metricstest.CheckCountData(t, "rejected_request_count", map[string]string{
+				metricskey.LabelNamespaceName:     "ns1",
+				metricskey.LabelBrokerName:        "broker1",
+				"reject_reason":                   ReasonUnauthorized,
+				metricskey.LabelResponseCode:      strconv.Itoa(tc.wantCode),
+				metricskey.LabelResponseCodeClass: "4xx",
+				metricskey.PodName:                pod,
+				metricskey.ContainerName:          container,
+			}, 1)
+		})
+	}
+}
+
 func BenchmarkIngressHandler(b *testing.B) {
 	for _, eventSize := range kgcptesting.BenchmarkEventSizes {
 		b.Run(fmt.Sprintf("%d bytes", eventSize), func(b *testing.B) {
@@ -338,12 +1027,13 @@ func runIngressHandlerBenchmark(b *testing.B, eventSize int) {
 	defer psSrv.Close()
 
 	psClient := createPubsubClient(ctx, b, psSrv)
-	decouple := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), psClient)
+	targets := memory.NewTargets(brokerConfig)
+	decouple := NewMultiTopicDecoupleSink(ctx, targets, psClient, 0, 0, nil)
 	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
 	if err != nil {
 		b.Fatal(err)
 	}
-	h := NewHandler(ctx, nil, decouple, statsReporter)
+	h := NewHandler(ctx, nil, decouple, targets, statsReporter, 0)
 
 	if _, err := psClient.CreateTopic(ctx, topicID); err != nil {
 		b.Fatal(err)
@@ -409,14 +1099,15 @@ func setupTestReceiver(ctx context.Context, t testing.TB, psSrv *pstest.Server)
 
 // createAndStartIngress creates an ingress and calls its Start() method in a goroutine.
 func createAndStartIngress(ctx context.Context, t testing.TB, psSrv *pstest.Server) string {
-	decouple := NewMultiTopicDecoupleSink(ctx, memory.NewTargets(brokerConfig), createPubsubClient(ctx, t, psSrv))
+	targets := memory.NewTargets(brokerConfig)
+	decouple := NewMultiTopicDecoupleSink(ctx, targets, createPubsubClient(ctx, t, psSrv), 0, 0, nil)
 
 	receiver := &testHttpMessageReceiver{urlCh: make(chan string)}
 	statsReporter, err := metrics.NewIngressReporter(metrics.PodName(pod), metrics.ContainerName(container))
 	if err != nil {
 		t.Fatal(err)
 	}
-	h := NewHandler(ctx, receiver, decouple, statsReporter)
+	h := NewHandler(ctx, receiver, decouple, targets, statsReporter, 0)
 
 	errCh := make(chan error, 1)
 	go func() {
@@ -458,6 +1149,15 @@ func createRequest(tc testCase, url string) *nethttp.Request {
 	return request
 }
 
+// batchBody returns the body of a request in batched content mode.
+func batchBody(t *testing.T, events ...*cloudevents.Event) string {
+	b, err := json.Marshal(events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
 // verifyMetrics verifies broker metrics are properly recorded (or not recorded)
 func verifyMetrics(t *testing.T, tc testCase) {
 	if tc.wantEventCount == 0 {
@@ -515,3 +1215,68 @@ func (recv *testHttpMessageReceiver) StartListen(ctx context.Context, handler ne
 	}
 	return nil
 }
+
+// timeoutReader fails like a connection whose read deadline has passed.
+// failingDecoupleSink fails to send all events with err.
+type failingDecoupleSink struct {
+	err error
+}
+
+func (s *failingDecoupleSink) Send(context.Context, string, string, cloudevents.Event) protocol.Result {
+	return s.err
+}
+
+func (s *failingDecoupleSink) SendBatch(_ context.Context, _, _ string, events []cloudevents.Event) []protocol.Result {
+	results := make([]protocol.Result, len(events))
+	for i := range results {
+		results[i] = s.err
+	}
+	return results
+}
+
+// recordingDecoupleSink records the events it is sent.
+type recordingDecoupleSink struct {
+	events []cloudevents.Event
+}
+
+func (s *recordingDecoupleSink) Send(_ context.Context, _, _ string, event cloudevents.Event) protocol.Result {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingDecoupleSink) SendBatch(_ context.Context, _, _ string, events []cloudevents.Event) []protocol.Result {
+	s.events = append(s.events, events...)
+	return make([]protocol.Result, len(events))
+}
+
+type timeoutReader struct{}
+
+func (timeoutReader) Read([]byte) (int, error) {
+	return 0, timeoutError{}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func verifyProblem(t *testing.T, res *nethttp.Response, tc testCase) {
+	t.Helper()
+	if got := res.Header.Get("Content-Type"); got != ProblemContentType {
+		t.Errorf("Content-Type mismatch. got: %q, want: %q", got, ProblemContentType)
+	}
+	var p Problem
+	if err := json.NewDecoder(res.Body).Decode(&p); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	if p.Reason != tc.wantReason {
+		t.Errorf("Reason mismatch. got: %q, want: %q", p.Reason, tc.wantReason)
+	}
+	if p.Status != tc.wantCode {
+		t.Errorf("Status mismatch. got: %v, want: %v", p.Status, tc.wantCode)
+	}
+	if p.Title != nethttp.StatusText(tc.wantCode) {
+		t.Errorf("Title mismatch. got: %q, want: %q", p.Title, nethttp.StatusText(tc.wantCode))
+	}
+}
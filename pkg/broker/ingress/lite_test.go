@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/go-cmp/cmp"
+	logtest "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+const liteTopic = "projects/test-project/locations/us-central1-a/topics/lite_topic"
+
+// fakeLitePublisher publishes to a Cloud Pub/Sub topic standing in for a
+// Pub/Sub Lite topic.
+type fakeLitePublisher struct {
+	*pubsub.Topic
+	err error
+}
+
+func (p *fakeLitePublisher) Error() error {
+	return p.err
+}
+
+func TestMultiTopicDecoupleSinkLite(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	if _, err := psClient.CreateTopic(ctx, "lite_topic"); err != nil {
+		t.Fatal(err)
+	}
+
+	brokerConfig := memory.NewEmptyTargets()
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+	var publishers []*fakeLitePublisher
+	var paths []string
+	sink.newLitePublisher = func(topic string) (pubsublite.Publisher, error) {
+		paths = append(paths, topic)
+		p := &fakeLitePublisher{Topic: psClient.Topic("lite_topic")}
+		publishers = append(publishers, p)
+		return p, nil
+	}
+	defer func() {
+		for _, p := range publishers {
+			p.Stop()
+		}
+	}()
+	brokerConfig.MutateBroker("ns", "lite", func(m config.BrokerMutation) {
+		m.SetState(config.State_READY)
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        liteTopic,
+			Subscription: "projects/test-project/locations/us-central1-a/subscriptions/lite_sub",
+			Location:     "us-central1-a",
+		})
+	})
+
+	if res := sink.Send(ctx, "ns", "lite", *createTestEvent("event-1")); !cloudevents.IsACK(res) {
+		t.Fatalf("Send got %v, want ACK", res)
+	}
+	if msgs := psSrv.Messages(); len(msgs) != 1 {
+		t.Fatalf("Published messages got %d, want 1", len(msgs))
+	}
+
+	// A failed publisher is replaced.
+	publishers[0].err = errors.New("publisher failed")
+	if res := sink.Send(ctx, "ns", "lite", *createTestEvent("event-2")); !cloudevents.IsACK(res) {
+		t.Fatalf("Send got %v, want ACK", res)
+	}
+	if diff := cmp.Diff([]string{liteTopic, liteTopic}, paths); diff != "" {
+		t.Errorf("Publisher topics (-want,+got): %v", diff)
+	}
+}
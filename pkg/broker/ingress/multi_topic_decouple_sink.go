@@ -18,8 +18,11 @@ package ingress
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"go.opencensus.io/trace"
@@ -29,23 +32,54 @@ import (
 	cepubsub "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
 	cev2 "github.com/cloudevents/sdk-go/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/extensions"
 	"github.com/cloudevents/sdk-go/v2/protocol"
+	cetypes "github.com/cloudevents/sdk-go/v2/types"
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/compression"
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"knative.dev/eventing/pkg/logging"
 )
 
-const projectEnvKey = "PROJECT_ID"
+const (
+	projectEnvKey = "PROJECT_ID"
 
-// NewMultiTopicDecoupleSink creates a new multiTopicDecoupleSink.
-func NewMultiTopicDecoupleSink(ctx context.Context, brokerConfig config.ReadonlyTargets, client *pubsub.Client) *multiTopicDecoupleSink {
-	return &multiTopicDecoupleSink{
+	// contentTypeAttribute is the Pub/Sub message attribute holding the
+	// media type of structured mode messages.
+	contentTypeAttribute = "Content-Type"
+)
+
+// NewMultiTopicDecoupleSink creates a new multiTopicDecoupleSink. It creates
+// the topic handles of all ready brokers up front, and stops the handles that
+// have not been used for the given idle TTL, if it is not zero. Batches of
+// events are published with up to maxInFlight publishes in flight, or one at a
+// time if it is not positive. The event data is compressed with the compressor
+// if it is not nil. The events of brokers whose decouple queues are on Pub/Sub
+// Lite are published with Pub/Sub Lite publishers rather than client.
+func NewMultiTopicDecoupleSink(ctx context.Context, brokerConfig config.ReadonlyTargets, client *pubsub.Client, idleTTL PublisherIdleTTL, maxInFlight MaxInFlightPublishes, compressor *compression.Compressor) *multiTopicDecoupleSink {
+	if maxInFlight < 1 {
+		maxInFlight = 1
+	}
+	m := &multiTopicDecoupleSink{
 		logger:       logging.FromContext(ctx),
 		pubsub:       client,
 		brokerConfig: brokerConfig,
-		// TODO(#1118): remove Topic when broker config is removed
-		topics: make(map[types.NamespacedName]*pubsub.Topic),
+		idleTTL:      time.Duration(idleTTL),
+		maxInFlight:  int(maxInFlight),
+		compressor:   compressor,
+		now:          time.Now,
+
+		newLitePublisher: pubsublite.NewPublisher,
 	}
+	// TODO(#1118): remove Topic when broker config is removed
+	m.topics.Store(topicMap{})
+	m.warmTopics()
+	if m.idleTTL > 0 {
+		go m.expireIdleTopicsUntil(ctx.Done())
+	}
+	return m
 }
 
 // multiTopicDecoupleSink implements DecoupleSink and routes events to pubsub topics corresponding
@@ -53,95 +87,475 @@ func NewMultiTopicDecoupleSink(ctx context.Context, brokerConfig config.Readonly
 type multiTopicDecoupleSink struct {
 	// pubsub talks to pubsub.
 	pubsub *pubsub.Client
-	// map from brokers to topics
-	topics    map[types.NamespacedName]*pubsub.Topic
-	topicsMut sync.RWMutex
+	// newLitePublisher creates the publishers of the Pub/Sub Lite topics. It
+	// is replaced in tests.
+	newLitePublisher pubsublite.PublisherFn
+	// topics holds the topicMap from brokers to topics. The map is never
+	// modified once stored: it is copied and replaced while holding
+	// topicsMut, so that sending events doesn't take any lock.
+	topics    atomic.Value
+	topicsMut sync.Mutex
+	// idleTTL is how long a topic handle may go unused before it is stopped.
+	// Zero means topic handles are never stopped for being idle.
+	idleTTL time.Duration
+	// maxInFlight is the number of events of a batch that are published
+	// before waiting for the result of the oldest one.
+	maxInFlight int
+	// compressor compresses the data of the events before they are published.
+	// Event data is not compressed if it is nil.
+	compressor *compression.Compressor
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
 	// brokerConfig holds configurations for all brokers. It's a view of a configmap populated by
 	// the broker controller.
 	brokerConfig config.ReadonlyTargets
 	logger       *zap.Logger
 }
 
+// topicMap maps the decouple queues of brokers to their topic handles.
+type topicMap map[queueKey]*cachedTopic
+
+// queueKey identifies a decouple queue of a broker: its priority queue if
+// priority is true, or else its decouple queue.
+type queueKey struct {
+	broker   types.NamespacedName
+	priority bool
+}
+
+func (m *multiTopicDecoupleSink) loadTopics() topicMap {
+	return m.topics.Load().(topicMap)
+}
+
+// copyTopics returns a copy of the topic map to be modified and stored. It must
+// be called while holding topicsMut.
+func (m *multiTopicDecoupleSink) copyTopics() topicMap {
+	old := m.loadTopics()
+	topics := make(topicMap, len(old))
+	for k, t := range old {
+		topics[k] = t
+	}
+	return topics
+}
+
+// cachedTopic is a handle of the topic of a decouple queue along with the
+// last time it was used.
+type cachedTopic struct {
+	publisher
+	// queue is the decouple queue the topic handle publishes to.
+	queue *config.Queue
+	// lastUsed is the last time the topic was used, in unix nanoseconds. It
+	// must be accessed atomically.
+	lastUsed int64
+	// stopMut is held for reading while publishing to the topic and for
+	// writing while stopping it, so that events are never published to a
+	// stopped topic handle.
+	stopMut sync.RWMutex
+	stopped bool
+}
+
+// errTopicStopped is returned when publishing to a topic handle that was
+// stopped after it was looked up.
+var errTopicStopped = errors.New("topic handle is stopped")
+
+// stop stops the topic handle once the publishes in progress are done.
+func (t *cachedTopic) stop() {
+	t.stopMut.Lock()
+	defer t.stopMut.Unlock()
+	t.stopped = true
+	t.Stop()
+}
+
+func (t *cachedTopic) touch(now time.Time) {
+	atomic.StoreInt64(&t.lastUsed, now.UnixNano())
+}
+
+func (t *cachedTopic) idleSince(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&t.lastUsed)))
+}
+
 // Send sends incoming event to its corresponding pubsub topic based on which broker it belongs to.
+// High priority events go to the priority queue of the broker if it has one.
 func (m *multiTopicDecoupleSink) Send(ctx context.Context, ns, broker string, event cev2.Event) protocol.Result {
-	topic, err := m.getTopicForBroker(types.NamespacedName{Namespace: ns, Name: broker})
+	b := types.NamespacedName{Namespace: ns, Name: broker}
+	p, err := m.publishEvent(ctx, b, event, m.structuredEncoding(b))
 	if err != nil {
 		return err
 	}
+	return p.wait(ctx)
+}
 
+// SendBatch sends incoming events to the pubsub topic of the broker they belong
+// to. It doesn't wait for each publish before starting the next one, so that a
+// slow publish doesn't hold up the rest of the batch, but waits for the oldest
+// one once maxInFlight publishes are in flight. The result of each event is
+// returned in the order of the events.
+func (m *multiTopicDecoupleSink) SendBatch(ctx context.Context, ns, broker string, events []cev2.Event) []protocol.Result {
+	results := make([]protocol.Result, len(events))
+	b := types.NamespacedName{Namespace: ns, Name: broker}
+	structured := m.structuredEncoding(b)
+
+	pending := make([]*pendingPublish, len(events))
+	// inFlight holds the indexes of the events being published, oldest first.
+	inFlight := make([]int, 0, m.maxInFlight)
+	wait := func() {
+		i := inFlight[0]
+		inFlight = inFlight[1:]
+		results[i] = pending[i].wait(ctx)
+	}
+	for i, event := range events {
+		if len(inFlight) == m.maxInFlight {
+			wait()
+		}
+		// The events of a batch may have different priorities.
+		p, err := m.publishEvent(ctx, b, event, structured)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		pending[i] = p
+		inFlight = append(inFlight, i)
+	}
+	for len(inFlight) > 0 {
+		wait()
+	}
+	return results
+}
+
+// pendingPublish is an event being published to a topic.
+type pendingPublish struct {
+	topic       publisher
+	orderingKey string
+	result      *pubsub.PublishResult
+}
+
+// publishEvent starts publishing the event to the decouple topic of the broker
+// it is sent to.
+func (m *multiTopicDecoupleSink) publishEvent(ctx context.Context, broker types.NamespacedName, event cev2.Event, structured bool) (*pendingPublish, error) {
+	key := queueKey{broker: broker, priority: highPriority(event)}
+	topic, err := m.getTopic(key)
+	if err != nil {
+		return nil, err
+	}
+	p, err := m.publish(ctx, topic, event, structured)
+	if err != errTopicStopped {
+		return p, err
+	}
+	// The topic handle expired between looking it up and publishing. Look it
+	// up again under the lock, which creates a new handle.
+	topic, err = m.updateTopic(key)
+	if err != nil {
+		return nil, err
+	}
+	return m.publish(ctx, topic, event, structured)
+}
+
+// publish starts publishing the event to the topic, compressing its data
+// first if the sink has a compressor. The event is written in the structured
+// mode if structured is true, or else in the binary mode.
+func (m *multiTopicDecoupleSink) publish(ctx context.Context, topic *cachedTopic, event cev2.Event, structured bool) (*pendingPublish, error) {
+	if m.compressor != nil {
+		// The event context is shared with the caller, which shouldn't see
+		// the compression.
+		event = event.Clone()
+		if err := m.compressor.Compress(&event); err != nil {
+			return nil, err
+		}
+	}
 	dt := extensions.FromSpanContext(trace.FromContext(ctx).SpanContext())
 	msg := new(pubsub.Message)
-	if err := cepubsub.WritePubSubMessage(ctx, binding.ToMessage(&event), msg, dt.WriteTransformer()); err != nil {
-		return err
+	writeCtx := ctx
+	if structured {
+		writeCtx = binding.WithForceStructured(ctx)
+	}
+	if err := cepubsub.WritePubSubMessage(writeCtx, binding.ToMessage(&event), msg, dt.WriteTransformer()); err != nil {
+		return nil, err
+	}
+	if structured {
+		// The structured writer leaves the attributes alone, but readers
+		// tell structured messages apart by their content type.
+		if msg.Attributes == nil {
+			msg.Attributes = make(map[string]string, 1)
+		}
+		msg.Attributes[contentTypeAttribute] = format.JSON.MediaType()
 	}
 
-	_, err = topic.Publish(ctx, msg).Get(ctx)
+	// Pub/Sub Lite publishers use the ordering key as the partition key, so
+	// that the events of a key are kept in order.
+	if topic.queue.OrderingEnabled {
+		msg.OrderingKey = orderingKey(event)
+	}
+	topic.stopMut.RLock()
+	defer topic.stopMut.RUnlock()
+	if topic.stopped {
+		return nil, errTopicStopped
+	}
+	return &pendingPublish{
+		topic:       topic.publisher,
+		orderingKey: msg.OrderingKey,
+		result:      topic.Publish(ctx, msg),
+	}, nil
+}
+
+// wait waits for the result of the publish.
+func (p *pendingPublish) wait(ctx context.Context) error {
+	_, err := p.result.Get(ctx)
+	if topic, ok := p.topic.(*pubsub.Topic); ok && err != nil && p.orderingKey != "" {
+		// A failed publish pauses publishing for the ordering key. Resume it
+		// so that the producer can retry.
+		topic.ResumePublish(p.orderingKey)
+	}
 	return err
 }
 
+// orderingKey returns the value of the ordering key extension of the event,
+// or an empty string if the event doesn't have one.
+func orderingKey(event cev2.Event) string {
+	key, err := cetypes.ToString(event.Extensions()[brokerv1beta1.OrderingKeyExtension])
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// structuredEncoding returns true if the events of the broker are published in
+// the structured mode. Brokers missing from the config fall back to the binary
+// mode; getting their topic fails anyway.
+func (m *multiTopicDecoupleSink) structuredEncoding(broker types.NamespacedName) bool {
+	b, _ := m.brokerConfig.GetBroker(broker.Namespace, broker.Name)
+	return b.GetStructuredEncoding()
+}
+
+// highPriority returns true if the event is marked as high priority.
+func highPriority(event cev2.Event) bool {
+	priority, err := cetypes.ToString(event.Extensions()[brokerv1beta1.PriorityExtension])
+	return err == nil && priority == brokerv1beta1.PriorityHigh
+}
+
 // getTopicForBroker finds the corresponding decouple topic for the broker from the mounted broker configmap volume.
-func (m *multiTopicDecoupleSink) getTopicForBroker(broker types.NamespacedName) (*pubsub.Topic, error) {
-	topicID, err := m.getTopicIDForBroker(broker)
+func (m *multiTopicDecoupleSink) getTopicForBroker(broker types.NamespacedName) (*cachedTopic, error) {
+	return m.getTopic(queueKey{broker: broker})
+}
+
+func (m *multiTopicDecoupleSink) getTopic(key queueKey) (*cachedTopic, error) {
+	key, queue, err := m.getDecoupleQueue(key)
 	if err != nil {
 		return nil, err
 	}
 
-	if topic, ok := m.getExistingTopic(broker); ok {
-		// Check that the broker's topic ID hasn't changed.
-		if topic.ID() == topicID {
+	if topic, ok := m.getExistingTopic(key); ok {
+		// Check that the broker's decouple queue hasn't changed.
+		if topicMatchesQueue(topic, queue) {
 			return topic, nil
 		}
 	}
 
 	// Topic needs to be created or updated.
-	return m.updateTopicForBroker(broker)
+	return m.updateTopic(key)
+}
+
+// updateTopic creates the topic handle of the decouple queue identified by key
+// and stores it in place of the stale one. Creating a Pub/Sub Lite publisher
+// connects to Pub/Sub Lite, so it is done without holding topicsMut.
+func (m *multiTopicDecoupleSink) updateTopic(key queueKey) (*cachedTopic, error) {
+	for {
+		_, queue, err := m.getDecoupleQueue(key)
+		if err != nil {
+			return nil, err
+		}
+		created, err := m.newTopic(queue)
+		if err != nil {
+			return nil, err
+		}
+		topic, ok, err := m.storeTopic(key, created)
+		if err != nil || ok {
+			return topic, err
+		}
+	}
 }
 
-func (m *multiTopicDecoupleSink) updateTopicForBroker(broker types.NamespacedName) (*pubsub.Topic, error) {
+// storeTopic stores the created topic handle of the decouple queue identified
+// by key, unless another one was stored for the latest decouple queue in the
+// meantime, and returns the stored one. It returns false if the decouple queue
+// changed while the topic handle was created, which then needs to be created
+// again.
+func (m *multiTopicDecoupleSink) storeTopic(key queueKey, created *cachedTopic) (*cachedTopic, bool, error) {
 	m.topicsMut.Lock()
 	defer m.topicsMut.Unlock()
-	// Fetch latest decouple topic ID under lock.
-	topicID, err := m.getTopicIDForBroker(broker)
+	// Fetch latest decouple queue under lock.
+	key, queue, err := m.getDecoupleQueue(key)
 	if err != nil {
-		return nil, err
+		created.stop()
+		return nil, false, err
 	}
 
-	if topic, ok := m.topics[broker]; ok {
-		if topic.ID() == topicID {
-			// Topic already updated.
-			return topic, nil
-		}
+	old, ok := m.loadTopics()[key]
+	if ok && topicMatchesQueue(old, queue) {
+		// Topic already updated.
+		created.stop()
+		old.touch(m.now())
+		return old, true, nil
+	}
+	if !sameQueue(created.queue, queue) {
+		created.stop()
+		return nil, false, nil
+	}
+	if ok {
 		// Stop old topic.
-		m.topics[broker].Stop()
+		old.stop()
+	}
+	topics := m.copyTopics()
+	topics[key] = created
+	m.topics.Store(topics)
+	return created, true, nil
+}
+
+func (m *multiTopicDecoupleSink) newTopic(queue *config.Queue) (*cachedTopic, error) {
+	topic := &cachedTopic{queue: queue}
+	if queue.Location != "" {
+		p, err := m.newLitePublisher(queue.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the publisher of %q: %w", queue.Topic, err)
+		}
+		topic.publisher = p
+	} else {
+		t := m.pubsub.Topic(queue.Topic)
+		t.EnableMessageOrdering = queue.OrderingEnabled
+		topic.publisher = t
 	}
-	topic := m.pubsub.Topic(topicID)
-	m.topics[broker] = topic
+	topic.touch(m.now())
 	return topic, nil
 }
 
-func (m *multiTopicDecoupleSink) getTopicIDForBroker(broker types.NamespacedName) (string, error) {
+// warmTopics creates the topic handles of all ready brokers, so that the first
+// event sent to a broker doesn't pay for it. The topic handles are created
+// before taking topicsMut and don't replace the ones created in the meantime.
+func (m *multiTopicDecoupleSink) warmTopics() {
+	created := make(topicMap)
+	m.brokerConfig.RangeBrokers(func(b *config.Broker) bool {
+		if b.State != config.State_READY {
+			return true
+		}
+		broker := types.NamespacedName{Namespace: b.Namespace, Name: b.Name}
+		warm := func(key queueKey, queue *config.Queue) {
+			if queue == nil || queue.Topic == "" {
+				return
+			}
+			// The topic is created again when an event is sent to the broker.
+			topic, err := m.newTopic(queue)
+			if err != nil {
+				m.logger.Warn("Failed to create topic handle", zap.String("broker", broker.String()), zap.Error(err))
+				return
+			}
+			created[key] = topic
+		}
+		warm(queueKey{broker: broker}, b.DecoupleQueue)
+		warm(queueKey{broker: broker, priority: true}, b.PriorityDecoupleQueue)
+		return true
+	})
+
+	m.topicsMut.Lock()
+	defer m.topicsMut.Unlock()
+	topics := m.copyTopics()
+	for key, topic := range created {
+		if _, ok := topics[key]; ok {
+			topic.stop()
+			continue
+		}
+		topics[key] = topic
+	}
+	m.topics.Store(topics)
+}
+
+// expireIdleTopicsUntil periodically stops idle topic handles until the stop
+// channel is closed.
+func (m *multiTopicDecoupleSink) expireIdleTopicsUntil(stop <-chan struct{}) {
+	ticker := time.NewTicker(m.idleTTL / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			m.expireIdleTopics()
+		}
+	}
+}
+
+// expireIdleTopics stops and forgets the topic handles that have not been used
+// for longer than the idle TTL. They are created again when an event is sent
+// to their broker.
+func (m *multiTopicDecoupleSink) expireIdleTopics() {
+	m.topicsMut.Lock()
+	defer m.topicsMut.Unlock()
+	now := m.now()
+	topics := m.copyTopics()
+	for key, topic := range topics {
+		if topic.idleSince(now) > m.idleTTL {
+			m.logger.Debug("Stopping idle topic", zap.String("broker", key.broker.String()), zap.String("topic", topic.queue.Topic))
+			topic.stop()
+			delete(topics, key)
+		}
+	}
+	m.topics.Store(topics)
+}
+
+// getDecoupleQueue returns the decouple queue identified by key. The priority
+// queue of a broker without one is its decouple queue, so the key of the
+// returned queue is returned too.
+func (m *multiTopicDecoupleSink) getDecoupleQueue(key queueKey) (queueKey, *config.Queue, error) {
+	queue, err := m.getDecoupleQueueForBroker(key.broker)
+	if err != nil {
+		return key, nil, err
+	}
+	if key.priority {
+		brokerConfig, _ := m.brokerConfig.GetBroker(key.broker.Namespace, key.broker.Name)
+		if q := brokerConfig.GetPriorityDecoupleQueue(); q != nil && q.Topic != "" {
+			return key, q, nil
+		}
+		key.priority = false
+	}
+	return key, queue, nil
+}
+
+func (m *multiTopicDecoupleSink) getDecoupleQueueForBroker(broker types.NamespacedName) (*config.Queue, error) {
 	brokerConfig, ok := m.brokerConfig.GetBroker(broker.Namespace, broker.Name)
 	if !ok {
 		// There is an propagation delay between the controller reconciles the broker config and
 		// the config being pushed to the configmap volume in the ingress pod. So sometimes we return
 		// an error even if the request is valid.
 		m.logger.Warn("config is not found for", zap.String("broker", broker.String()))
-		return "", fmt.Errorf("%q: %w", broker, ErrNotFound)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotFound)
 	}
 	if brokerConfig.State != config.State_READY {
 		m.logger.Debug("broker is not ready", zap.Any("ns", broker.Namespace), zap.Any("broker", broker))
-		return "", fmt.Errorf("%q: %w", broker, ErrNotReady)
+		return nil, fmt.Errorf("%q: %w", broker, ErrNotReady)
 	}
 	if brokerConfig.DecoupleQueue == nil || brokerConfig.DecoupleQueue.Topic == "" {
 		m.logger.Error("DecoupleQueue or topic missing for broker, this should NOT happen.", zap.Any("brokerConfig", brokerConfig))
-		return "", fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
+		return nil, fmt.Errorf("decouple queue of %q: %w", broker, ErrIncomplete)
 	}
-	return brokerConfig.DecoupleQueue.Topic, nil
+	return brokerConfig.DecoupleQueue, nil
 }
 
-func (m *multiTopicDecoupleSink) getExistingTopic(broker types.NamespacedName) (*pubsub.Topic, bool) {
-	m.topicsMut.RLock()
-	defer m.topicsMut.RUnlock()
-	topic, ok := m.topics[broker]
+// topicMatchesQueue checks whether a cached topic still publishes to the given
+// decouple queue. A Pub/Sub Lite publisher stops for good on its first failed
+// publish, so a failed one is replaced as if the queue had changed.
+func topicMatchesQueue(topic *cachedTopic, queue *config.Queue) bool {
+	if p, ok := topic.publisher.(pubsublite.Publisher); ok && p.Error() != nil {
+		return false
+	}
+	return sameQueue(topic.queue, queue)
+}
+
+// sameQueue checks whether two decouple queues are published to the same way.
+func sameQueue(a, b *config.Queue) bool {
+	return a.Topic == b.Topic && a.OrderingEnabled == b.OrderingEnabled && a.Location == b.Location
+}
+
+func (m *multiTopicDecoupleSink) getExistingTopic(key queueKey) (*cachedTopic, bool) {
+	topic, ok := m.loadTopics()[key]
+	if ok {
+		topic.touch(m.now())
+	}
 	return topic, ok
 }
@@ -19,7 +19,10 @@ package ingress
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"cloud.google.com/go/pubsub/pstest"
@@ -33,8 +36,11 @@ import (
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 	"github.com/google/go-cmp/cmp"
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/compression"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"k8s.io/apimachinery/pkg/types"
 	logtest "knative.dev/pkg/logging/testing"
 )
 
@@ -169,7 +175,7 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 					t.Fatal(err)
 				}
 
-				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient)
+				sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
 				// Send events
 				event := createTestEvent(uuid.New().String())
 				err = sink.Send(context.Background(), testCase.ns, testCase.broker, *event)
@@ -206,6 +212,510 @@ func TestMultiTopicDecoupleSink(t *testing.T) {
 	}
 }
 
+func TestMultiTopicDecoupleSinkOrdering(t *testing.T) {
+	tests := []struct {
+		name            string
+		orderingEnabled bool
+		orderingKey     string
+		wantOrderingKey string
+	}{
+		{
+			name:        "ordering disabled ignores the ordering key",
+			orderingKey: "key-1",
+		},
+		{
+			name:            "ordering enabled without ordering key",
+			orderingEnabled: true,
+		},
+		{
+			name:            "ordering enabled with ordering key",
+			orderingEnabled: true,
+			orderingKey:     "key-1",
+			wantOrderingKey: "key-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			psSrv := pstest.NewServer()
+			defer psSrv.Close()
+			psClient := createPubsubClient(ctx, t, psSrv)
+			brokerConfig := memory.NewTargets(&config.TargetsConfig{
+				Brokers: map[string]*config.Broker{
+					"test_ns_1/test_broker_1": {
+						State:         config.State_READY,
+						DecoupleQueue: &config.Queue{Topic: "test_topic_1", OrderingEnabled: tt.orderingEnabled},
+					},
+				},
+			})
+			topic, err := psClient.CreateTopic(ctx, "test_topic_1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			subscription, err := psClient.CreateSubscription(ctx, "test-sub", pubsub.SubscriptionConfig{Topic: topic})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+			event := createTestEvent(uuid.New().String())
+			if tt.orderingKey != "" {
+				event.SetExtension(brokerv1beta1.OrderingKeyExtension, tt.orderingKey)
+			}
+			if err := sink.Send(context.Background(), "test_ns_1", "test_broker_1", *event); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			rctx, cancel := context.WithCancel(ctx)
+			msgCh := make(chan *pubsub.Message, 1)
+			subscription.Receive(rctx,
+				func(ctx context.Context, m *pubsub.Message) {
+					select {
+					case msgCh <- m:
+						cancel()
+					case <-ctx.Done():
+					}
+					m.Ack()
+				},
+			)
+			msg := <-msgCh
+			if msg.OrderingKey != tt.wantOrderingKey {
+				t.Errorf("OrderingKey got=%q, want=%q", msg.OrderingKey, tt.wantOrderingKey)
+			}
+		})
+	}
+}
+
+func TestMultiTopicDecoupleSinkPriority(t *testing.T) {
+	tests := []struct {
+		name      string
+		priority  string
+		broker    string
+		wantTopic string
+	}{
+		{
+			name:      "event without priority",
+			broker:    "test_broker_1",
+			wantTopic: "test_topic_1",
+		},
+		{
+			name:      "high priority event",
+			priority:  brokerv1beta1.PriorityHigh,
+			broker:    "test_broker_1",
+			wantTopic: "test_priority_topic_1",
+		},
+		{
+			name:      "event of another priority",
+			priority:  "low",
+			broker:    "test_broker_1",
+			wantTopic: "test_topic_1",
+		},
+		{
+			name:      "high priority event to a broker without priority queue",
+			priority:  brokerv1beta1.PriorityHigh,
+			broker:    "test_broker_2",
+			wantTopic: "test_topic_2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := logtest.TestContextWithLogger(t)
+			psSrv := pstest.NewServer()
+			defer psSrv.Close()
+			psClient := createPubsubClient(ctx, t, psSrv)
+			brokerConfig := memory.NewTargets(&config.TargetsConfig{
+				Brokers: map[string]*config.Broker{
+					"test_ns_1/test_broker_1": {
+						State:                 config.State_READY,
+						DecoupleQueue:         &config.Queue{Topic: "test_topic_1"},
+						PriorityDecoupleQueue: &config.Queue{Topic: "test_priority_topic_1"},
+					},
+					"test_ns_1/test_broker_2": {
+						State:         config.State_READY,
+						DecoupleQueue: &config.Queue{Topic: "test_topic_2"},
+					},
+				},
+			})
+			subscriptions := make(map[string]*pubsub.Subscription)
+			for _, id := range []string{"test_topic_1", "test_priority_topic_1", "test_topic_2"} {
+				topic, err := psClient.CreateTopic(ctx, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				subscriptions[id], err = psClient.CreateSubscription(ctx, id+"-sub", pubsub.SubscriptionConfig{Topic: topic})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+			event := createTestEvent(uuid.New().String())
+			if tt.priority != "" {
+				event.SetExtension(brokerv1beta1.PriorityExtension, tt.priority)
+			}
+			if err := sink.Send(context.Background(), "test_ns_1", tt.broker, *event); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			rctx, cancel := context.WithCancel(ctx)
+			msgCh := make(chan *pubsub.Message, 1)
+			subscriptions[tt.wantTopic].Receive(rctx,
+				func(ctx context.Context, m *pubsub.Message) {
+					select {
+					case msgCh <- m:
+						cancel()
+					case <-ctx.Done():
+					}
+					m.Ack()
+				},
+			)
+			msg := <-msgCh
+			if got := msg.Attributes["ce-id"]; got != event.ID() {
+				t.Errorf("Event ID got=%q, want=%q", got, event.ID())
+			}
+		})
+	}
+}
+
+func TestMultiTopicDecoupleSinkSendBatch(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"test_ns_1/test_broker_1": {State: config.State_READY, DecoupleQueue: &config.Queue{Topic: "test_topic_1"}},
+		},
+	})
+	topic, err := psClient.CreateTopic(ctx, "test_topic_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription, err := psClient.CreateSubscription(ctx, "test-sub", pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 2, nil)
+
+	t.Run("broker doesn't exist", func(t *testing.T) {
+		events := []event.Event{*createTestEvent("event-1"), *createTestEvent("event-2")}
+		results := sink.SendBatch(context.Background(), "test_ns_1", "test_broker_2", events)
+		if len(results) != len(events) {
+			t.Fatalf("Results count got=%d, want=%d", len(results), len(events))
+		}
+		for i, res := range results {
+			if cloudevents.IsACK(res) {
+				t.Errorf("Result %d got ACK, want error", i)
+			}
+		}
+	})
+
+	t.Run("publishes all events", func(t *testing.T) {
+		var events []event.Event
+		var wantIDs []string
+		for i := 0; i < 5; i++ {
+			id := fmt.Sprintf("event-%d", i)
+			events = append(events, *createTestEvent(id))
+			wantIDs = append(wantIDs, id)
+		}
+		results := sink.SendBatch(context.Background(), "test_ns_1", "test_broker_1", events)
+		if len(results) != len(events) {
+			t.Fatalf("Results count got=%d, want=%d", len(results), len(events))
+		}
+		for i, res := range results {
+			if !cloudevents.IsACK(res) {
+				t.Errorf("Result %d got %v, want ACK", i, res)
+			}
+		}
+
+		rctx, cancel := context.WithCancel(ctx)
+		msgCh := make(chan *pubsub.Message, len(events))
+		subscription.Receive(rctx,
+			func(ctx context.Context, m *pubsub.Message) {
+				m.Ack()
+				msgCh <- m
+				if len(msgCh) == len(events) {
+					cancel()
+				}
+			},
+		)
+		close(msgCh)
+		var gotIDs []string
+		for msg := range msgCh {
+			got, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
+			if err != nil {
+				t.Fatal(err)
+			}
+			gotIDs = append(gotIDs, got.ID())
+		}
+		sort.Strings(gotIDs)
+		if diff := cmp.Diff(wantIDs, gotIDs); diff != "" {
+			t.Errorf("Published event IDs (-want,+got): %v", diff)
+		}
+	})
+}
+
+func TestMultiTopicDecoupleSinkCompression(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"test_ns_1/test_broker_1": {State: config.State_READY, DecoupleQueue: &config.Queue{Topic: "test_topic_1"}},
+		},
+	})
+	topic, err := psClient.CreateTopic(ctx, "test_topic_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription, err := psClient.CreateSubscription(ctx, "test-sub", pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressor, err := compression.NewCompressor(compression.Zstd, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, compressor)
+
+	e := createTestEvent("event-1")
+	data := []byte(`{"key": "` + strings.Repeat("value", 100) + `"}`)
+	if err := e.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		t.Fatal(err)
+	}
+	if res := sink.Send(context.Background(), "test_ns_1", "test_broker_1", *e); !cloudevents.IsACK(res) {
+		t.Fatalf("Send got %v, want ACK", res)
+	}
+	if _, ok := e.Extensions()[compression.ContentEncodingExtension]; ok {
+		t.Errorf("Send compressed the event of the caller")
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	var msg *pubsub.Message
+	subscription.Receive(rctx, func(ctx context.Context, m *pubsub.Message) {
+		m.Ack()
+		msg = m
+		cancel()
+	})
+	if len(msg.Data) >= len(data) {
+		t.Errorf("Published data size got=%d, want less than %d", len(msg.Data), len(data))
+	}
+	got, err := binding.ToEvent(ctx, cepubsub.NewMessage(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := compression.Decompress(got); err != nil {
+		t.Fatalf("Decompress got error: %v", err)
+	}
+	if diff := cmp.Diff(data, got.Data()); diff != "" {
+		t.Errorf("Decompressed data (-want,+got): %v", diff)
+	}
+}
+
+func TestMultiTopicDecoupleSinkStructuredEncoding(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"test_ns_1/test_broker_1": {State: config.State_READY, DecoupleQueue: &config.Queue{Topic: "test_topic_1"}, StructuredEncoding: true},
+		},
+	})
+	topic, err := psClient.CreateTopic(ctx, "test_topic_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription, err := psClient.CreateSubscription(ctx, "test-sub", pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+
+	e := createTestEvent("event-1")
+	// Too long for a Pub/Sub attribute value.
+	e.SetExtension("note", strings.Repeat("x", 2000))
+	if err := e.SetData(cloudevents.ApplicationJSON, map[string]string{"key": "value"}); err != nil {
+		t.Fatal(err)
+	}
+	if res := sink.Send(context.Background(), "test_ns_1", "test_broker_1", *e); !cloudevents.IsACK(res) {
+		t.Fatalf("Send got %v, want ACK", res)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	var msg *pubsub.Message
+	subscription.Receive(rctx, func(ctx context.Context, m *pubsub.Message) {
+		m.Ack()
+		msg = m
+		cancel()
+	})
+	if got, ok := msg.Attributes["ce-id"]; ok {
+		t.Errorf("Published message has attribute ce-id=%q, want none", got)
+	}
+	if got, want := msg.Attributes[contentTypeAttribute], cloudevents.ApplicationCloudEventsJSON; got != want {
+		t.Errorf("Published message Content-Type got=%q, want=%q", got, want)
+	}
+	m := cepubsub.NewMessage(msg)
+	if got := m.ReadEncoding(); got != binding.EncodingStructured {
+		t.Errorf("Published message encoding got=%v, want=%v", got, binding.EncodingStructured)
+	}
+	got, err := binding.ToEvent(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID() != e.ID() {
+		t.Errorf("Published event ID got=%q, want=%q", got.ID(), e.ID())
+	}
+	if diff := cmp.Diff(e.Extensions()["note"], got.Extensions()["note"]); diff != "" {
+		t.Errorf("Published event note extension (-want,+got): %v", diff)
+	}
+	if diff := cmp.Diff(e.Data(), got.Data()); diff != "" {
+		t.Errorf("Published event data (-want,+got): %v", diff)
+	}
+}
+
+func TestMultiTopicDecoupleSinkWarmAndExpireTopics(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"ns/ready": {
+				Namespace:     "ns",
+				Name:          "ready",
+				State:         config.State_READY,
+				DecoupleQueue: &config.Queue{Topic: "ready_topic"},
+			},
+			"ns/other": {
+				Namespace:     "ns",
+				Name:          "other",
+				State:         config.State_READY,
+				DecoupleQueue: &config.Queue{Topic: "other_topic"},
+			},
+			"ns/unknown": {
+				Namespace:     "ns",
+				Name:          "unknown",
+				State:         config.State_UNKNOWN,
+				DecoupleQueue: &config.Queue{Topic: "unknown_topic"},
+			},
+		},
+	})
+	ready := types.NamespacedName{Namespace: "ns", Name: "ready"}
+	other := types.NamespacedName{Namespace: "ns", Name: "other"}
+
+	// Construct without an idle TTL so that no expiry goroutine races with
+	// the fake clock below.
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+	defer func() {
+		for _, topic := range sink.loadTopics() {
+			topic.Stop()
+		}
+	}()
+
+	if got, want := cachedBrokers(sink), []types.NamespacedName{other, ready}; !cmp.Equal(got, want) {
+		t.Fatalf("Warmed topics got=%v, want=%v", got, want)
+	}
+
+	start := time.Unix(1e9, 0)
+	now := start
+	sink.now = func() time.Time { return now }
+	sink.idleTTL = time.Minute
+	for _, topic := range sink.loadTopics() {
+		topic.touch(start)
+	}
+
+	now = start.Add(30 * time.Second)
+	if _, err := sink.getTopicForBroker(ready); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	now = start.Add(90 * time.Second)
+	sink.expireIdleTopics()
+	if got, want := cachedBrokers(sink), []types.NamespacedName{ready}; !cmp.Equal(got, want) {
+		t.Errorf("Topics after first expiry got=%v, want=%v", got, want)
+	}
+
+	now = start.Add(100 * time.Second)
+	sink.expireIdleTopics()
+	if got := cachedBrokers(sink); len(got) != 0 {
+		t.Errorf("Topics after second expiry got=%v, want none", got)
+	}
+
+	// An expired topic is created again when it's needed.
+	if topic, err := sink.getTopicForBroker(ready); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	} else if topic.queue.Topic != "ready_topic" {
+		t.Errorf("Topic got=%q, want=%q", topic.queue.Topic, "ready_topic")
+	}
+}
+
+func TestMultiTopicDecoupleSinkPublishToExpiredTopic(t *testing.T) {
+	ctx := logtest.TestContextWithLogger(t)
+	psSrv := pstest.NewServer()
+	defer psSrv.Close()
+	psClient := createPubsubClient(ctx, t, psSrv)
+	brokerConfig := memory.NewTargets(&config.TargetsConfig{
+		Brokers: map[string]*config.Broker{
+			"ns/ready": {
+				Namespace:     "ns",
+				Name:          "ready",
+				State:         config.State_READY,
+				DecoupleQueue: &config.Queue{Topic: "ready_topic"},
+			},
+		},
+	})
+	if _, err := psClient.CreateTopic(ctx, "ready_topic"); err != nil {
+		t.Fatal(err)
+	}
+	ready := types.NamespacedName{Namespace: "ns", Name: "ready"}
+
+	sink := NewMultiTopicDecoupleSink(ctx, brokerConfig, psClient, 0, 0, nil)
+	defer func() {
+		for _, topic := range sink.loadTopics() {
+			topic.Stop()
+		}
+	}()
+	start := time.Unix(1e9, 0)
+	now := start
+	sink.now = func() time.Time { return now }
+	sink.idleTTL = time.Minute
+
+	// The topic handle is looked up by a sender, then expires before the
+	// sender publishes to it.
+	topic, err := sink.getTopicForBroker(ready)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now = start.Add(2 * time.Minute)
+	sink.expireIdleTopics()
+	if _, err := sink.publish(ctx, topic, *createTestEvent("event-1"), false); err != errTopicStopped {
+		t.Errorf("Publish to expired topic got error %v, want %v", err, errTopicStopped)
+	}
+
+	// Sending creates a new topic handle instead.
+	if res := sink.Send(ctx, "ns", "ready", *createTestEvent("event-2")); !cloudevents.IsACK(res) {
+		t.Errorf("Send got %v, want ACK", res)
+	}
+	if got, want := cachedBrokers(sink), []types.NamespacedName{ready}; !cmp.Equal(got, want) {
+		t.Errorf("Topics after send got=%v, want=%v", got, want)
+	}
+}
+
+// cachedBrokers returns the brokers with a cached decouple topic, sorted by name.
+func cachedBrokers(sink *multiTopicDecoupleSink) []types.NamespacedName {
+	var brokers []types.NamespacedName
+	for k := range sink.loadTopics() {
+		if !k.priority {
+			brokers = append(brokers, k.broker)
+		}
+	}
+	sort.Slice(brokers, func(i, j int) bool { return brokers[i].String() < brokers[j].String() })
+	return brokers
+}
+
 type fakePubsubClient struct {
 	t *testing.T
 	// topics is the mapping from topic name to corresponding channel which contains the event.
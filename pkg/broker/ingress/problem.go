@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+)
+
+// ProblemContentType is the content type of the error responses of the
+// ingress, as defined by RFC 7807.
+const ProblemContentType = "application/problem+json"
+
+// Reasons of the error responses of the ingress. Producers can branch on
+// them to tell failure causes apart.
+const (
+	// ReasonMethodNotAllowed is returned when the request is not a POST.
+	ReasonMethodNotAllowed = "method-not-allowed"
+	// ReasonMalformedPath is returned when the request path is not of the
+	// form /<ns>/<broker>.
+	ReasonMalformedPath = "malformed-path"
+	// ReasonInvalidEvent is returned when the request is not a valid
+	// CloudEvent.
+	ReasonInvalidEvent = "invalid-event"
+	// ReasonRequestTooLarge is returned when the request body exceeds the
+	// size limit of the ingress.
+	ReasonRequestTooLarge = "request-too-large"
+	// ReasonRequestTimeout is returned when the request body is not received
+	// within the read timeout of the ingress.
+	ReasonRequestTimeout = "request-timeout"
+	// ReasonBrokerNotFound is returned when the broker doesn't exist, or its
+	// configuration hasn't reached the ingress yet.
+	ReasonBrokerNotFound = "broker-not-found"
+	// ReasonBrokerNotReady is returned when the broker exists but is not
+	// ready.
+	ReasonBrokerNotReady = "broker-not-ready"
+	// ReasonPublishFailed is returned when the event could not be published
+	// to the decouple topic of the broker.
+	ReasonPublishFailed = "publish-failed"
+	// ReasonEncryptionFailed is returned when the event data could not be
+	// encrypted with the encryption key of the broker.
+	ReasonEncryptionFailed = "encryption-failed"
+	// ReasonSchemaValidationFailed is returned when the event data doesn't
+	// conform to the EventSchema of its type, and the schema rejects such
+	// events.
+	ReasonSchemaValidationFailed = "schema-validation-failed"
+	// ReasonEventNotAllowed is returned when the event policy of the broker
+	// doesn't allow the type or the source of the event.
+	ReasonEventNotAllowed = "event-not-allowed"
+	// ReasonUnauthorized is returned when the broker requires a bearer token
+	// and the request has none of its tokens.
+	ReasonUnauthorized = "unauthorized"
+)
+
+// Problem is the body of an error response of the ingress, as defined by
+// RFC 7807, with the reason of the error as an extension member.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// writeProblem writes an error response with a problem+json body.
+func writeProblem(response nethttp.ResponseWriter, status int, reason, detail string) {
+	b, err := json.Marshal(Problem{
+		Type:   "about:blank",
+		Title:  nethttp.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Reason: reason,
+	})
+	if err != nil {
+		// Marshalling a Problem can't fail, but fall back to a plain text
+		// error just in case.
+		nethttp.Error(response, detail, status)
+		return
+	}
+	response.Header().Set("Content-Type", ProblemContentType)
+	response.Header().Set("X-Content-Type-Options", "nosniff")
+	response.WriteHeader(status)
+	_, _ = response.Write(b)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	nethttp "net/http"
+
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// HTTPMessageReceiver is an HTTP server to receive events. Unlike
+// kncloudevents.HttpMessageReceiver, it enforces ServerLimits on the requests.
+type HTTPMessageReceiver struct {
+	port   int
+	limits ServerLimits
+}
+
+// NewHTTPMessageReceiver creates an HTTPMessageReceiver listening on port.
+func NewHTTPMessageReceiver(port Port, limits ServerLimits) *HTTPMessageReceiver {
+	return &HTTPMessageReceiver{
+		port:   int(port),
+		limits: limits,
+	}
+}
+
+// StartListen serves requests with handler until ctx is done. It blocks until
+// the server is shut down.
+func (recv *HTTPMessageReceiver) StartListen(ctx context.Context, handler nethttp.Handler) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", recv.port))
+	if err != nil {
+		return err
+	}
+	return recv.serve(ctx, listener, handler)
+}
+
+func (recv *HTTPMessageReceiver) serve(ctx context.Context, listener net.Listener, handler nethttp.Handler) error {
+	server := &nethttp.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           kncloudevents.CreateHandler(handler),
+		ReadHeaderTimeout: recv.limits.ReadHeaderTimeout,
+		ReadTimeout:       recv.limits.ReadTimeout,
+		IdleTimeout:       recv.limits.IdleTimeout,
+		MaxHeaderBytes:    recv.limits.MaxHeaderBytes,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	// Wait for the server to return or ctx.Done().
+	select {
+	case <-ctx.Done():
+		ctx, cancel := context.WithTimeout(context.Background(), kncloudevents.DefaultShutdownTimeout)
+		defer cancel()
+		err := server.Shutdown(ctx)
+		<-errChan // Wait for server goroutine to exit
+		return err
+	case err := <-errChan:
+		return err
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ingress
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	nethttp "net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startTestReceiver serves handler with an HTTPMessageReceiver enforcing limits
+// on a random port, and returns the address of the server.
+func startTestReceiver(t *testing.T, limits ServerLimits, handler nethttp.Handler) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- NewHTTPMessageReceiver(0, limits).serve(ctx, listener, handler)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-errCh; err != nil {
+			t.Errorf("Unexpected error from receiver: %v", err)
+		}
+	})
+	return listener.Addr().String()
+}
+
+func TestHTTPMessageReceiverReadHeaderTimeout(t *testing.T) {
+	addr := startTestReceiver(t, ServerLimits{ReadHeaderTimeout: 100 * time.Millisecond}, nethttp.NotFoundHandler())
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// Send the start of the headers, then stall like a slowloris client.
+	if _, err := fmt.Fprint(conn, "POST /ns1/broker1 HTTP/1.1\r\nHost: example.com\r\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := ioutil.ReadAll(conn); err != nil {
+		t.Errorf("Connection was not closed by the server: %v", err)
+	}
+}
+
+func TestHTTPMessageReceiverMaxHeaderBytes(t *testing.T) {
+	addr := startTestReceiver(t, ServerLimits{MaxHeaderBytes: 1024}, nethttp.NotFoundHandler())
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	// net/http allows some slack over MaxHeaderBytes, so send well over it.
+	if _, err := fmt.Fprintf(conn, "POST /ns1/broker1 HTTP/1.1\r\nHost: example.com\r\nCe-Large: %s\r\n\r\n", strings.Repeat("a", 1<<16)); err != nil {
+		t.Fatal(err)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	res, err := nethttp.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != nethttp.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("StatusCode mismatch. got: %v, want: %v", res.StatusCode, nethttp.StatusRequestHeaderFieldsTooLarge)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+)
+
+// gcsStore stores the lease record in a Cloud Storage object, which all the
+// regions can reach. The generation of the object is the version of the
+// record.
+type gcsStore struct {
+	object *storage.ObjectHandle
+}
+
+// NewGCSStore creates a Store keeping the lease record in the given object
+// of the bucket.
+func NewGCSStore(client *storage.Client, bucket, object string) Store {
+	return &gcsStore{object: client.Bucket(bucket).Object(object)}
+}
+
+// Get implements Store.Get.
+func (s *gcsStore) Get(ctx context.Context) (*Record, int64, error) {
+	reader, err := s.object.NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, 0, ErrNotExist
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read the lease record: %w", err)
+	}
+	defer reader.Close()
+	b, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read the lease record: %w", err)
+	}
+	var r Record
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse the lease record: %w", err)
+	}
+	return &r, reader.Attrs.Generation, nil
+}
+
+// Update implements Store.Update.
+func (s *gcsStore) Update(ctx context.Context, r *Record, version int64) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	cond := storage.Conditions{GenerationMatch: version}
+	if version == 0 {
+		cond = storage.Conditions{DoesNotExist: true}
+	}
+	writer := s.object.If(cond).NewWriter(ctx)
+	writer.ContentType = "application/json"
+	if _, err := writer.Write(b); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write the lease record: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to write the lease record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lease elects the region whose fanouts and retries pull the broker
+// queues when brokers are run in several regions, so that each event is
+// delivered by a single region. The lease is a record held by one region at
+// a time, renewed by all the pods of that region, and taken over by another
+// region once the holder stops renewing it, e.g. during a regional incident.
+package lease
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/logging"
+)
+
+var (
+	// ErrNotExist is returned by Store.Get when there is no lease record yet.
+	ErrNotExist = errors.New("the lease record does not exist")
+	// ErrConflict is returned by Store.Update when the lease record was
+	// updated since it was read.
+	ErrConflict = errors.New("the lease record was updated concurrently")
+)
+
+// Record is the state of the lease.
+type Record struct {
+	// Holder is the identity of the region holding the lease. It may be
+	// emptied to release the lease.
+	Holder string `json:"holder"`
+	// RenewTime is when the holder last renewed the lease.
+	RenewTime time.Time `json:"renewTime"`
+	// DurationSeconds is how long the lease is held after it is renewed.
+	DurationSeconds int `json:"leaseDurationSeconds"`
+}
+
+// expired returns true if the lease may be taken over at the given time.
+func (r *Record) expired(now time.Time) bool {
+	return r.Holder == "" || !now.Before(r.RenewTime.Add(time.Duration(r.DurationSeconds)*time.Second))
+}
+
+// Store reads and writes the lease record with optimistic concurrency.
+type Store interface {
+	// Get returns the lease record along with its version, or ErrNotExist.
+	Get(ctx context.Context) (*Record, int64, error)
+	// Update writes the lease record if it is still at the given version,
+	// zero meaning that it doesn't exist yet, or else returns ErrConflict.
+	Update(ctx context.Context, r *Record, version int64) error
+}
+
+// Elector acquires and renews the lease on behalf of a region.
+type Elector struct {
+	store    Store
+	holder   string
+	duration time.Duration
+	// lookback is how far before the last renewal of the previous holder
+	// the queues are replayed when the lease is taken over.
+	lookback time.Duration
+	// notify is signaled when the lease is acquired or lost.
+	notify chan<- struct{}
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+
+	mu        sync.RWMutex
+	held      bool
+	since     time.Time
+	lastRenew time.Time
+}
+
+// NewElector creates an Elector holding the lease as holder for duration
+// after each renewal. When the lease is taken over from another region, the
+// queues are replayed from lookback before the last renewal of the previous
+// holder, to make up for the events it pulled but didn't deliver. notify, if
+// not nil, is signaled without blocking when the lease is acquired or lost.
+func NewElector(store Store, holder string, duration, lookback time.Duration, notify chan<- struct{}) *Elector {
+	return &Elector{
+		store:    store,
+		holder:   holder,
+		duration: duration,
+		lookback: lookback,
+		notify:   notify,
+		now:      time.Now,
+	}
+}
+
+// Held returns true if the region holds the lease. If it took the lease over
+// from another region, it also returns the time the queues should be replayed
+// from, or else the zero time.
+func (e *Elector) Held() (bool, time.Time) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.held, e.since
+}
+
+// Run acquires and renews the lease until ctx is done. The lease is not
+// released when a pod stops, since the other pods of the region keep renewing
+// it, e.g. during a rollout.
+func (e *Elector) Run(ctx context.Context) {
+	// Renew well before the lease expires, so that a failed renewal can be
+	// retried.
+	ticker := time.NewTicker(e.duration / 3)
+	defer ticker.Stop()
+	e.TryAcquireOrRenew(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.TryAcquireOrRenew(ctx)
+		}
+	}
+}
+
+// TryAcquireOrRenew acquires the lease if it is free or expired, or renews
+// it if the region holds it.
+func (e *Elector) TryAcquireOrRenew(ctx context.Context) {
+	logger := logging.FromContext(ctx).With(zap.String("holder", e.holder))
+	now := e.now()
+	r, version, err := e.store.Get(ctx)
+	if err != nil && !errors.Is(err, ErrNotExist) {
+		logger.Warn("Failed to read the lease", zap.Error(err))
+		e.expire(ctx, now)
+		return
+	}
+	if r != nil && r.Holder != e.holder && !r.expired(now) {
+		e.set(ctx, false, time.Time{}, time.Time{})
+		return
+	}
+	var since time.Time
+	if r != nil && r.Holder != e.holder {
+		// The queues of the region have not been pulled since it last held
+		// the lease, if ever.
+		since = r.RenewTime.Add(-e.lookback)
+		logger.Info("Taking over the lease", zap.String("previousHolder", r.Holder), zap.Time("replayFrom", since))
+	}
+	next := &Record{
+		Holder:          e.holder,
+		RenewTime:       now,
+		DurationSeconds: int(e.duration / time.Second),
+	}
+	if err := e.store.Update(ctx, next, version); err != nil {
+		// The other pods of the region renew the lease too, so a conflict
+		// doesn't mean that the lease is lost. The next attempt tells.
+		if !errors.Is(err, ErrConflict) {
+			logger.Warn("Failed to update the lease", zap.Error(err))
+		}
+		e.expire(ctx, now)
+		return
+	}
+	e.mu.RLock()
+	if e.held {
+		// Keep the replay time of the takeover.
+		since = e.since
+	}
+	e.mu.RUnlock()
+	e.set(ctx, true, since, now)
+}
+
+// expire gives up the lease if it couldn't be renewed before it expired.
+func (e *Elector) expire(ctx context.Context, now time.Time) {
+	e.mu.RLock()
+	lastRenew := e.lastRenew
+	e.mu.RUnlock()
+	if now.Sub(lastRenew) >= e.duration {
+		e.set(ctx, false, time.Time{}, time.Time{})
+	}
+}
+
+func (e *Elector) set(ctx context.Context, held bool, since, lastRenew time.Time) {
+	e.mu.Lock()
+	changed := e.held != held
+	e.held, e.since = held, since
+	if held {
+		e.lastRenew = lastRenew
+	}
+	e.mu.Unlock()
+	if !changed {
+		return
+	}
+	logging.FromContext(ctx).Info("Lease changed hands", zap.String("holder", e.holder), zap.Bool("held", held))
+	if e.notify != nil {
+		select {
+		case e.notify <- struct{}{}:
+		default:
+		}
+	}
+}
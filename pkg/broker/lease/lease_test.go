@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func newTestElector(store Store, holder string, now *time.Time) (*Elector, chan struct{}) {
+	notify := make(chan struct{}, 1)
+	e := NewElector(store, holder, 30*time.Second, time.Minute, notify)
+	e.now = func() time.Time { return *now }
+	return e, notify
+}
+
+func wantHeld(t *testing.T, e *Elector, wantHeld bool, wantSince time.Time) {
+	t.Helper()
+	held, since := e.Held()
+	if held != wantHeld || !since.Equal(wantSince) {
+		t.Errorf("%s Held() = (%v, %v), want (%v, %v)", e.holder, held, since, wantHeld, wantSince)
+	}
+}
+
+func wantNotified(t *testing.T, notify chan struct{}, want bool) {
+	t.Helper()
+	select {
+	case <-notify:
+		if !want {
+			t.Error("unexpected lease change notification")
+		}
+	default:
+		if want {
+			t.Error("missing lease change notification")
+		}
+	}
+}
+
+func TestElector(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	store := NewMemoryStore()
+	now := time.Unix(1e9, 0)
+	east, eastNotify := newTestElector(store, "us-east1", &now)
+	west, westNotify := newTestElector(store, "us-west1", &now)
+
+	// The first region to try acquires the free lease, with nothing to
+	// replay.
+	east.TryAcquireOrRenew(ctx)
+	west.TryAcquireOrRenew(ctx)
+	wantHeld(t, east, true, time.Time{})
+	wantHeld(t, west, false, time.Time{})
+	wantNotified(t, eastNotify, true)
+	wantNotified(t, westNotify, false)
+
+	// The holder renews the lease.
+	now = now.Add(20 * time.Second)
+	east.TryAcquireOrRenew(ctx)
+	renewed := now
+	now = now.Add(20 * time.Second)
+	west.TryAcquireOrRenew(ctx)
+	wantHeld(t, east, true, time.Time{})
+	wantHeld(t, west, false, time.Time{})
+	wantNotified(t, eastNotify, false)
+
+	// The holder stops renewing, and the other region takes over once the
+	// lease expires, replaying from before the last renewal.
+	now = renewed.Add(30 * time.Second)
+	west.TryAcquireOrRenew(ctx)
+	wantHeld(t, west, true, renewed.Add(-time.Minute))
+	wantNotified(t, westNotify, true)
+
+	// The previous holder learns that it lost the lease.
+	east.TryAcquireOrRenew(ctx)
+	wantHeld(t, east, false, time.Time{})
+	wantNotified(t, eastNotify, true)
+
+	// The new holder keeps the replay time while renewing.
+	now = now.Add(10 * time.Second)
+	west.TryAcquireOrRenew(ctx)
+	wantHeld(t, west, true, renewed.Add(-time.Minute))
+}
+
+func TestElectorSameRegion(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	store := NewMemoryStore()
+	now := time.Unix(1e9, 0)
+	pod1, _ := newTestElector(store, "us-east1", &now)
+	pod2, _ := newTestElector(store, "us-east1", &now)
+
+	// The pods of a region share the lease.
+	pod1.TryAcquireOrRenew(ctx)
+	pod2.TryAcquireOrRenew(ctx)
+	wantHeld(t, pod1, true, time.Time{})
+	wantHeld(t, pod2, true, time.Time{})
+}
+
+// conflictStore fails every update with ErrConflict.
+type conflictStore struct {
+	Store
+}
+
+func (conflictStore) Update(context.Context, *Record, int64) error {
+	return ErrConflict
+}
+
+func TestElectorExpires(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	store := NewMemoryStore()
+	now := time.Unix(1e9, 0)
+	e, _ := newTestElector(store, "us-east1", &now)
+	e.TryAcquireOrRenew(ctx)
+	wantHeld(t, e, true, time.Time{})
+
+	// Failed renewals keep the lease until it expires.
+	e.store = conflictStore{store}
+	now = now.Add(20 * time.Second)
+	e.TryAcquireOrRenew(ctx)
+	wantHeld(t, e, true, time.Time{})
+	now = now.Add(10 * time.Second)
+	e.TryAcquireOrRenew(ctx)
+	wantHeld(t, e, false, time.Time{})
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lease
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryStore keeps the lease record in memory, e.g. for tests.
+type memoryStore struct {
+	mu      sync.Mutex
+	record  *Record
+	version int64
+}
+
+// NewMemoryStore creates a Store keeping the lease record in memory.
+func NewMemoryStore() Store {
+	return &memoryStore{}
+}
+
+// Get implements Store.Get.
+func (s *memoryStore) Get(context.Context) (*Record, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record == nil {
+		return nil, 0, ErrNotExist
+	}
+	r := *s.record
+	return &r, s.version, nil
+}
+
+// Update implements Store.Update.
+func (s *memoryStore) Update(_ context.Context, r *Record, version int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if version != s.version {
+		return ErrConflict
+	}
+	copied := *r
+	s.record = &copied
+	s.version++
+	return nil
+}
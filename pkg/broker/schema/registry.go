@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/xeipuuv/gojsonschema"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/google/knative-gcp/pkg/utils/jsonschema"
+)
+
+const (
+	// ResultValid is the metric tag value of events conforming to their
+	// schema.
+	ResultValid = "valid"
+	// ResultInvalid is the metric tag value of events not conforming to
+	// their schema.
+	ResultInvalid = "invalid"
+)
+
+// Validation is the result of validating an event against the schema of its
+// type.
+type Validation struct {
+	// Err is why the event doesn't conform to the schema, nil if it does.
+	Err error
+	// Reject is true if the event doesn't conform to the schema and the
+	// schema rejects such events.
+	Reject bool
+}
+
+// Result returns ResultValid or ResultInvalid. A nil Validation means the
+// event has no schema, and its result is empty.
+func (v *Validation) Result() string {
+	switch {
+	case v == nil:
+		return ""
+	case v.Err != nil:
+		return ResultInvalid
+	default:
+		return ResultValid
+	}
+}
+
+// compiled is a schema of the registry along with its entry.
+type compiled struct {
+	entry  *Entry
+	schema *gojsonschema.Schema
+}
+
+// typeKey identifies the schema of the events of a type in a namespace.
+type typeKey struct {
+	namespace string
+	eventType string
+}
+
+// Registry holds the compiled schemas of the ConfigMap. A nil Registry has no
+// schemas.
+type Registry struct {
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	schemas map[typeKey]*compiled
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry(logger *zap.Logger) *Registry {
+	return &Registry{
+		logger:  logger,
+		schemas: make(map[typeKey]*compiled),
+	}
+}
+
+// UpdateFromConfigMap replaces the schemas of the registry with the ones of
+// the ConfigMap. Schemas that didn't change are not compiled again. Entries
+// that cannot be decoded or compiled are logged and skipped.
+func (r *Registry) UpdateFromConfigMap(cm *corev1.ConfigMap) {
+	entries, errs := Decode(cm)
+	for k, err := range errs {
+		r.logger.Error("Failed to decode event schema", zap.String("key", k), zap.Error(err))
+	}
+
+	r.mu.RLock()
+	old := r.schemas
+	r.mu.RUnlock()
+
+	schemas := make(map[typeKey]*compiled, len(entries))
+	for k, e := range entries {
+		tk := typeKey{namespace: e.Namespace, eventType: e.Type}
+		if c, ok := old[tk]; ok && bytes.Equal(c.entry.Schema, e.Schema) {
+			schemas[tk] = &compiled{entry: e, schema: c.schema}
+			continue
+		}
+		s, err := jsonschema.Compile(e.Schema)
+		if err != nil {
+			r.logger.Error("Failed to compile event schema", zap.String("key", k), zap.Error(err))
+			continue
+		}
+		schemas[tk] = &compiled{entry: e, schema: s}
+	}
+
+	r.mu.Lock()
+	r.schemas = schemas
+	r.mu.Unlock()
+}
+
+// Validate validates the data of the event sent to a broker of the namespace
+// against the schema of its type. It returns nil if the type has no schema.
+func (r *Registry) Validate(namespace string, event *cev2.Event) *Validation {
+	c := r.lookup(namespace, event.Type())
+	if c == nil {
+		return nil
+	}
+	v := &Validation{Err: validate(c.schema, event)}
+	v.Reject = v.Err != nil && c.entry.Action == ActionReject
+	return v
+}
+
+// ValidateOnDelivery is Validate for the events delivered to the triggers of
+// a broker of the namespace. It returns nil if the type has no schema, or if
+// its schema is not validated on delivery. Events are never rejected on
+// delivery.
+func (r *Registry) ValidateOnDelivery(namespace string, event *cev2.Event) *Validation {
+	c := r.lookup(namespace, event.Type())
+	if c == nil || !c.entry.ValidateOnDelivery {
+		return nil
+	}
+	return &Validation{Err: validate(c.schema, event)}
+}
+
+func (r *Registry) lookup(namespace, eventType string) *compiled {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.schemas[typeKey{namespace: namespace, eventType: eventType}]
+}
+
+// validate validates the data of the event against the schema. Events without
+// data are validated as a JSON null.
+func validate(s *gojsonschema.Schema, event *cev2.Event) error {
+	data := event.Data()
+	if len(data) == 0 {
+		data = []byte("null")
+	}
+	res, err := s.Validate(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return fmt.Errorf("event data is not JSON: %w", err)
+	}
+	if res.Valid() {
+		return nil
+	}
+	msgs := make([]string, 0, len(res.Errors()))
+	for _, e := range res.Errors() {
+		msgs = append(msgs, e.String())
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
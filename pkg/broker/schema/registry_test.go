@@ -0,0 +1,169 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const orderSchema = `{"type":"object","required":["id"],"properties":{"id":{"type":"string"}}}`
+
+func newConfigMap(t *testing.T, entries ...*Entry) *corev1.ConfigMap {
+	t.Helper()
+	m := make(map[string]*Entry, len(entries))
+	for _, e := range entries {
+		m[Key(e.Namespace, e.Type)] = e
+	}
+	data, err := Encode(m)
+	if err != nil {
+		t.Fatalf("Encode() = %v", err)
+	}
+	return &corev1.ConfigMap{Data: data}
+}
+
+func newEvent(t *testing.T, eventType string, data interface{}) *cev2.Event {
+	t.Helper()
+	e := cev2.NewEvent()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType(eventType)
+	if data != nil {
+		if err := e.SetData(cev2.ApplicationJSON, data); err != nil {
+			t.Fatalf("SetData() = %v", err)
+		}
+	}
+	return &e
+}
+
+func TestRegistryValidate(t *testing.T) {
+	r := NewRegistry(zap.NewNop())
+	r.UpdateFromConfigMap(newConfigMap(t,
+		&Entry{Namespace: "ns", Type: "order", Action: ActionReject, Schema: json.RawMessage(orderSchema)},
+		&Entry{Namespace: "ns", Type: "report", Action: ActionReport, Schema: json.RawMessage(orderSchema)},
+	))
+
+	testCases := map[string]struct {
+		namespace  string
+		event      *cev2.Event
+		wantResult string
+		wantReject bool
+	}{
+		"valid": {
+			namespace:  "ns",
+			event:      newEvent(t, "order", map[string]string{"id": "1"}),
+			wantResult: ResultValid,
+		},
+		"invalid rejected": {
+			namespace:  "ns",
+			event:      newEvent(t, "order", map[string]int{"id": 1}),
+			wantResult: ResultInvalid,
+			wantReject: true,
+		},
+		"invalid reported": {
+			namespace:  "ns",
+			event:      newEvent(t, "report", map[string]int{"id": 1}),
+			wantResult: ResultInvalid,
+		},
+		"no data": {
+			namespace:  "ns",
+			event:      newEvent(t, "order", nil),
+			wantResult: ResultInvalid,
+			wantReject: true,
+		},
+		"type without schema": {
+			namespace: "ns",
+			event:     newEvent(t, "other", map[string]int{"id": 1}),
+		},
+		"other namespace": {
+			namespace: "other",
+			event:     newEvent(t, "order", map[string]int{"id": 1}),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			v := r.Validate(tc.namespace, tc.event)
+			if got := v.Result(); got != tc.wantResult {
+				t.Errorf("Result() = %q, want %q (error: %v)", got, tc.wantResult, v)
+			}
+			if v != nil && v.Reject != tc.wantReject {
+				t.Errorf("Reject = %v, want %v", v.Reject, tc.wantReject)
+			}
+		})
+	}
+}
+
+func TestRegistryValidateOnDelivery(t *testing.T) {
+	r := NewRegistry(zap.NewNop())
+	r.UpdateFromConfigMap(newConfigMap(t,
+		&Entry{Namespace: "ns", Type: "order", Action: ActionReject, ValidateOnDelivery: true, Schema: json.RawMessage(orderSchema)},
+		&Entry{Namespace: "ns", Type: "ingress-only", Action: ActionReject, Schema: json.RawMessage(orderSchema)},
+	))
+
+	v := r.ValidateOnDelivery("ns", newEvent(t, "order", map[string]int{"id": 1}))
+	if got := v.Result(); got != ResultInvalid {
+		t.Errorf("Result() = %q, want %q", got, ResultInvalid)
+	}
+	if v.Reject {
+		t.Error("Reject = true, events are never rejected on delivery")
+	}
+	if v := r.ValidateOnDelivery("ns", newEvent(t, "ingress-only", map[string]int{"id": 1})); v != nil {
+		t.Errorf("ValidateOnDelivery() = %v, want nil for schemas not validated on delivery", v)
+	}
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	r := NewRegistry(zap.NewNop())
+	event := newEvent(t, "order", map[string]int{"id": 1})
+	r.UpdateFromConfigMap(newConfigMap(t,
+		&Entry{Namespace: "ns", Type: "order", Action: ActionReport, Schema: json.RawMessage(orderSchema)},
+		&Entry{Namespace: "ns", Type: "broken", Action: ActionReport, Schema: json.RawMessage(`{"type":1}`)},
+	))
+	if got := r.Validate("ns", event).Result(); got != ResultInvalid {
+		t.Errorf("Result() = %q, want %q", got, ResultInvalid)
+	}
+	if v := r.Validate("ns", newEvent(t, "broken", nil)); v != nil {
+		t.Errorf("Validate() = %v, want nil for a schema that doesn't compile", v)
+	}
+
+	r.UpdateFromConfigMap(newConfigMap(t,
+		&Entry{Namespace: "ns", Type: "order", Action: ActionReport, Schema: json.RawMessage(`{"type":"object"}`)},
+	))
+	if got := r.Validate("ns", event).Result(); got != ResultValid {
+		t.Errorf("Result() after update = %q, want %q", got, ResultValid)
+	}
+
+	r.UpdateFromConfigMap(&corev1.ConfigMap{})
+	if v := r.Validate("ns", event); v != nil {
+		t.Errorf("Validate() after removal = %v, want nil", v)
+	}
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	event := newEvent(t, "order", nil)
+	if v := r.Validate("ns", event); v != nil {
+		t.Errorf("Validate() = %v, want nil", v)
+	}
+	if v := r.ValidateOnDelivery("ns", event); v != nil {
+		t.Errorf("ValidateOnDelivery() = %v, want nil", v)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schema validates the data of the events sent to brokers against the
+// JSON schemas of their types. The EventSchema controller writes the schemas
+// of all namespaces to a shared ConfigMap, which the data plane pods watch.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap the EventSchema controller
+	// writes the schemas to, in the namespace of the data plane.
+	ConfigMapName = "broker-event-schemas"
+
+	// ActionReport accepts the events that don't conform to their schema.
+	ActionReport = "Report"
+	// ActionReject rejects the events that don't conform to their schema.
+	ActionReject = "Reject"
+)
+
+// Entry is the schema of the events of a type sent to the brokers of a
+// namespace, as written to the ConfigMap.
+type Entry struct {
+	// Namespace is the namespace of the brokers the schema applies to.
+	Namespace string `json:"namespace"`
+	// Type is the type of the events the schema applies to.
+	Type string `json:"type"`
+	// Action is what the ingress does with the events that don't conform to
+	// the schema, either ActionReport or ActionReject.
+	Action string `json:"action"`
+	// ValidateOnDelivery also validates the events in the fanout.
+	ValidateOnDelivery bool `json:"validateOnDelivery,omitempty"`
+	// Schema is the JSON schema.
+	Schema json.RawMessage `json:"schema"`
+}
+
+// Key returns the key of the entry of the named EventSchema in the ConfigMap.
+func Key(namespace, name string) string {
+	return namespace + "." + name
+}
+
+// Encode returns the ConfigMap data holding the entries, keyed by Key.
+func Encode(entries map[string]*Entry) (map[string]string, error) {
+	data := make(map[string]string, len(entries))
+	for k, e := range entries {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode schema %q: %w", k, err)
+		}
+		data[k] = string(b)
+	}
+	return data, nil
+}
+
+// Decode returns the entries of the ConfigMap, keyed by Key. Malformed
+// entries are returned as errors along with the other entries.
+func Decode(cm *corev1.ConfigMap) (map[string]*Entry, map[string]error) {
+	entries := make(map[string]*Entry, len(cm.Data))
+	errs := make(map[string]error)
+	for k, v := range cm.Data {
+		var e Entry
+		if err := json.Unmarshal([]byte(v), &e); err != nil {
+			errs[k] = err
+			continue
+		}
+		entries[k] = &e
+	}
+	return entries, errs
+}
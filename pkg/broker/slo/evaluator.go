@@ -0,0 +1,269 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+// successClass is the response code class of the deliveries accepted by the
+// subscriber.
+const successClass = "2xx"
+
+// snapshot is the deliveries to each trigger counted since the pod started,
+// at a point in time.
+type snapshot struct {
+	time   time.Time
+	counts map[types.NamespacedName]Counts
+}
+
+// Evaluator counts the deliveries to each trigger over the short and long
+// windows from the delivery count view, and writes them to the entry of the
+// pod in the ConfigMap. A nil Evaluator does nothing.
+type Evaluator struct {
+	client    kubernetes.Interface
+	namespace string
+	pod       string
+	logger    *zap.Logger
+
+	// snapshots are the snapshots taken over the long window, oldest first.
+	snapshots []snapshot
+	// empty is whether the entry of the pod was last written without
+	// samples.
+	empty bool
+
+	// retrieve returns the rows of the delivery count view. It is replaced
+	// in tests.
+	retrieve func() ([]*view.Row, error)
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// NewEvaluator creates an Evaluator writing the counts of the pod to the
+// ConfigMap in the namespace.
+func NewEvaluator(ctx context.Context, client kubernetes.Interface, namespace, pod string) *Evaluator {
+	return &Evaluator{
+		client:    client,
+		namespace: namespace,
+		pod:       pod,
+		logger:    logging.FromContext(ctx).Desugar(),
+		empty:     true,
+		retrieve: func() ([]*view.Row, error) {
+			return view.RetrieveData(metrics.DeliveryCountViewName)
+		},
+		now: time.Now,
+	}
+}
+
+// Run writes the counts to the ConfigMap every interval until the context is
+// done, then removes the entry of the pod.
+func (e *Evaluator) Run(ctx context.Context, interval time.Duration) {
+	if e == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := e.write(nil); err != nil {
+				e.logger.Warn("Failed to remove the delivery counts of the pod", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			e.sync()
+		}
+	}
+}
+
+// sync takes a snapshot and writes the resulting counts.
+func (e *Evaluator) sync() {
+	rows, err := e.retrieve()
+	if err != nil {
+		e.logger.Warn("Failed to retrieve the delivery counts", zap.Error(err))
+		return
+	}
+	report := e.evaluate(cumulative(rows))
+	if len(report.Samples) == 0 && e.empty {
+		return
+	}
+	if err := e.write(report); err != nil {
+		e.logger.Warn("Failed to write the delivery counts of the pod", zap.Error(err))
+		return
+	}
+	e.empty = len(report.Samples) == 0
+}
+
+// evaluate records the snapshot of the cumulative counts, and returns the
+// counts over the windows.
+func (e *Evaluator) evaluate(counts map[types.NamespacedName]Counts) *Report {
+	now := e.now()
+	e.snapshots = append(e.snapshots, snapshot{time: now, counts: counts})
+	// Keep the latest snapshot taken before the long window started, as the
+	// base of the counts over the long window.
+	start := now.Add(-LongWindow)
+	for len(e.snapshots) > 1 && !e.snapshots[1].time.After(start) {
+		e.snapshots = e.snapshots[1:]
+	}
+
+	shortBase := e.base(now.Add(-ShortWindow))
+	longBase := e.base(start)
+	report := &Report{Time: now}
+	for trigger, c := range counts {
+		long := sub(c, longBase[trigger])
+		if long.Total == 0 {
+			continue
+		}
+		report.Samples = append(report.Samples, Sample{
+			Trigger: trigger.String(),
+			Short:   sub(c, shortBase[trigger]),
+			Long:    long,
+		})
+	}
+	sort.Slice(report.Samples, func(i, j int) bool {
+		return report.Samples[i].Trigger < report.Samples[j].Trigger
+	})
+	return report
+}
+
+// base returns the counts of the latest snapshot taken at or before t. The
+// counts start at zero when the pod starts, so they are the base of the
+// windows started before the first snapshot.
+func (e *Evaluator) base(t time.Time) map[types.NamespacedName]Counts {
+	var counts map[types.NamespacedName]Counts
+	for _, s := range e.snapshots {
+		if s.time.After(t) {
+			break
+		}
+		counts = s.counts
+	}
+	return counts
+}
+
+// write sets the entry of the pod in the ConfigMap to the report, creating
+// the ConfigMap if needed. The entry is removed if the report is nil. The
+// expired entries of other pods are removed on the way, so that the
+// ConfigMap doesn't keep the reports of pods that are gone.
+func (e *Evaluator) write(report *Report) error {
+	var data string
+	if report != nil {
+		b, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+	configMaps := e.client.CoreV1().ConfigMaps(e.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ConfigMapName, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			if data == "" {
+				return nil
+			}
+			_, err = configMaps.Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConfigMapName,
+					Namespace: e.namespace,
+				},
+				Data: map[string]string{e.pod: data},
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		now := e.now()
+		var stale []string
+		for pod, d := range cm.Data {
+			if pod != e.pod && expired(d, now) {
+				stale = append(stale, pod)
+			}
+		}
+		if cm.Data[e.pod] == data && len(stale) == 0 {
+			return nil
+		}
+		cm = cm.DeepCopy()
+		for _, pod := range stale {
+			delete(cm.Data, pod)
+		}
+		if data == "" {
+			delete(cm.Data, e.pod)
+		} else {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string, 1)
+			}
+			cm.Data[e.pod] = data
+		}
+		_, err = configMaps.Update(cm)
+		return err
+	})
+}
+
+// cumulative sums the rows of the delivery count view by trigger.
+func cumulative(rows []*view.Row) map[types.NamespacedName]Counts {
+	counts := make(map[types.NamespacedName]Counts)
+	for _, row := range rows {
+		data, ok := row.Data.(*view.CountData)
+		if !ok {
+			continue
+		}
+		trigger := types.NamespacedName{
+			Namespace: tagValue(row.Tags, metrics.NamespaceNameKey),
+			Name:      tagValue(row.Tags, metrics.TriggerNameKey),
+		}
+		if trigger.Name == "" {
+			continue
+		}
+		c := Counts{Total: data.Value}
+		if tagValue(row.Tags, metrics.ResponseCodeClassKey) != successClass {
+			c.Failed = data.Value
+		}
+		counts[trigger] = counts[trigger].Add(c)
+	}
+	return counts
+}
+
+func tagValue(tags []tag.Tag, key tag.Key) string {
+	for _, t := range tags {
+		if t.Key == key {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+// sub returns the counts between the base and c. Counts missing from the base
+// are zero.
+func sub(c, base Counts) Counts {
+	return Counts{Total: c.Total - base.Total, Failed: c.Failed - base.Failed}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/google/knative-gcp/pkg/metrics"
+)
+
+const (
+	testNS  = "cloud-run-events"
+	testPod = "fanout-1"
+)
+
+// row returns a row of the delivery count view.
+func row(namespace, trigger, class string, count int64) *view.Row {
+	return &view.Row{
+		Tags: []tag.Tag{
+			{Key: metrics.NamespaceNameKey, Value: namespace},
+			{Key: metrics.TriggerNameKey, Value: trigger},
+			{Key: metrics.ResponseCodeClassKey, Value: class},
+		},
+		Data: &view.CountData{Value: count},
+	}
+}
+
+func getReport(t *testing.T, client *fake.Clientset) *Report {
+	t.Helper()
+	cm, err := client.CoreV1().ConfigMaps(testNS).Get(ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get the ConfigMap: %v", err)
+	}
+	data, ok := cm.Data[testPod]
+	if !ok {
+		return nil
+	}
+	var r Report
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		t.Fatalf("Failed to parse the entry of the pod: %v", err)
+	}
+	return &r
+}
+
+func TestNilEvaluator(t *testing.T) {
+	var e *Evaluator
+	e.Run(context.Background(), time.Second)
+}
+
+func TestEvaluator(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	e := NewEvaluator(context.Background(), client, testNS, testPod)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	var rows []*view.Row
+	e.retrieve = func() ([]*view.Row, error) { return rows, nil }
+
+	// Nothing is written before there are deliveries.
+	e.sync()
+	if _, err := client.CoreV1().ConfigMaps(testNS).Get(ConfigMapName, metav1.GetOptions{}); err == nil {
+		t.Fatal("ConfigMap was created without deliveries")
+	}
+
+	rows = []*view.Row{
+		row("ns", "trigger", "2xx", 90),
+		row("ns", "trigger", "5xx", 10),
+		row("ns", "other", "2xx", 10),
+	}
+	e.sync()
+	want := &Report{Time: now, Samples: []Sample{
+		{Trigger: "ns/other", Short: Counts{Total: 10}, Long: Counts{Total: 10}},
+		{Trigger: "ns/trigger", Short: Counts{Total: 100, Failed: 10}, Long: Counts{Total: 100, Failed: 10}},
+	}}
+	if diff := cmp.Diff(want, getReport(t, client)); diff != "" {
+		t.Errorf("Report after the first deliveries (-want, +got) = %v", diff)
+	}
+
+	// Only the deliveries since the start of the short window are counted in
+	// it.
+	now = now.Add(ShortWindow)
+	rows = []*view.Row{
+		row("ns", "trigger", "2xx", 90),
+		row("ns", "trigger", "5xx", 30),
+		row("ns", "trigger", "4xx", 5),
+		row("ns", "other", "2xx", 10),
+	}
+	e.sync()
+	want = &Report{Time: now, Samples: []Sample{
+		{Trigger: "ns/other", Long: Counts{Total: 10}},
+		{Trigger: "ns/trigger", Short: Counts{Total: 25, Failed: 25}, Long: Counts{Total: 125, Failed: 35}},
+	}}
+	if diff := cmp.Diff(want, getReport(t, client)); diff != "" {
+		t.Errorf("Report after the short window (-want, +got) = %v", diff)
+	}
+
+	// The triggers without deliveries over the long window are dropped.
+	now = now.Add(LongWindow)
+	e.sync()
+	if got := getReport(t, client); got == nil || len(got.Samples) != 0 {
+		t.Errorf("Report after the long window = %+v, want no samples", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Run(ctx, time.Hour)
+	if got := getReport(t, client); got != nil {
+		t.Errorf("Report after the evaluator stopped = %+v, want the entry removed", got)
+	}
+}
+
+func TestEvaluatorRemovesExpiredEntries(t *testing.T) {
+	now := time.Now()
+	fresh, err := json.Marshal(Report{Time: now.Add(-ReportExpiry / 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := json.Marshal(Report{Time: now.Add(-2 * ReportExpiry)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: testNS},
+		Data: map[string]string{
+			"fresh":   string(fresh),
+			"stale":   string(stale),
+			"garbage": "{",
+		},
+	})
+	e := NewEvaluator(context.Background(), client, testNS, testPod)
+	e.now = func() time.Time { return now }
+	e.retrieve = func() ([]*view.Row, error) {
+		return []*view.Row{row("ns", "trigger", "2xx", 1)}, nil
+	}
+
+	// The expired entries of other pods are removed when the pod writes.
+	e.sync()
+	cm, err := client.CoreV1().ConfigMaps(testNS).Get(ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get the ConfigMap: %v", err)
+	}
+	var pods []string
+	for pod := range cm.Data {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+	if diff := cmp.Diff([]string{testPod, "fresh"}, pods); diff != "" {
+		t.Errorf("ConfigMap entries (-want, +got) = %v", diff)
+	}
+}
+
+func TestCumulative(t *testing.T) {
+	got := cumulative([]*view.Row{
+		row("ns", "trigger", "2xx", 3),
+		row("ns", "trigger", "5xx", 1),
+		row("ns", "", "2xx", 5),
+		{Data: &view.SumData{Value: 1}},
+	})
+	want := map[string]Counts{"ns/trigger": {Total: 4, Failed: 1}}
+	gotByName := make(map[string]Counts, len(got))
+	for t, c := range got {
+		gotByName[t.String()] = c
+	}
+	if diff := cmp.Diff(want, gotByName); diff != "" {
+		t.Errorf("cumulative() (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slo evaluates the delivery success of triggers against their
+// objectives. The fanout and retry pods count the deliveries to each trigger
+// over a short and a long window from the delivery count stats view, and write
+// the counts to a shared ConfigMap, which the trigger controller reads to
+// compute how fast the triggers burn their error budget and surface it in
+// their conditions.
+package slo
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap the data plane pods write
+	// their delivery counts to, in the namespace of the data plane.
+	ConfigMapName = "trigger-slo-status"
+
+	// ShortWindow and LongWindow are the windows the burn rates are computed
+	// over. The short window makes the alert stop soon after the deliveries
+	// recover, the long one keeps short spikes from raising it.
+	ShortWindow = 5 * time.Minute
+	LongWindow  = time.Hour
+
+	// BurnRateThreshold is the burn rate both windows must reach to raise
+	// the alert. At this rate, an hour consumes 2% of the error budget of a
+	// 30 day period.
+	BurnRateThreshold = 14.4
+
+	// ReportExpiry is how long the counts of a pod are used after they were
+	// written, so that the counts of pods that are gone are eventually
+	// ignored.
+	ReportExpiry = 2 * time.Minute
+)
+
+// Counts is the number of deliveries to a trigger over a window.
+type Counts struct {
+	// Total is the number of deliveries.
+	Total int64 `json:"total"`
+	// Failed is the number of deliveries the subscriber didn't accept.
+	Failed int64 `json:"failed"`
+}
+
+// Add returns the sum of the counts.
+func (c Counts) Add(o Counts) Counts {
+	return Counts{Total: c.Total + o.Total, Failed: c.Failed + o.Failed}
+}
+
+// BurnRate returns how many times faster than allowed by the objective the
+// deliveries consume the error budget, e.g. 1 if they fail at exactly the
+// rate the objective allows.
+func (c Counts) BurnRate(objective float64) float64 {
+	if c.Total == 0 || objective >= 1 {
+		return 0
+	}
+	return float64(c.Failed) / float64(c.Total) / (1 - objective)
+}
+
+// Sample is the deliveries to a trigger counted by a pod.
+type Sample struct {
+	// Trigger is the namespace/name of the trigger.
+	Trigger string `json:"trigger"`
+	// Short is the deliveries over the short window.
+	Short Counts `json:"short"`
+	// Long is the deliveries over the long window.
+	Long Counts `json:"long"`
+}
+
+// Report is the entry of a pod in the ConfigMap.
+type Report struct {
+	// Time is when the counts were taken.
+	Time time.Time `json:"time"`
+	// Samples are the counts of the triggers with deliveries over the long
+	// window.
+	Samples []Sample `json:"samples"`
+}
+
+// BurnRates are the burn rates of the error budget of a trigger.
+type BurnRates struct {
+	// Short is the burn rate over the short window.
+	Short float64
+	// Long is the burn rate over the long window.
+	Long float64
+}
+
+// Burning returns whether the burn rates of both windows reach the threshold.
+func (b BurnRates) Burning() bool {
+	return b.Short >= BurnRateThreshold && b.Long >= BurnRateThreshold
+}
+
+// Evaluate returns the burn rates of the error budget of the trigger with the
+// objective, e.g. 0.999, from the counts in the ConfigMap written after since.
+func Evaluate(cm *corev1.ConfigMap, trigger types.NamespacedName, objective float64, since time.Time) BurnRates {
+	var short, long Counts
+	for _, r := range parse(cm) {
+		if !r.Time.After(since) {
+			continue
+		}
+		for _, s := range r.Samples {
+			if s.Trigger == trigger.String() {
+				short = short.Add(s.Short)
+				long = long.Add(s.Long)
+			}
+		}
+	}
+	return BurnRates{Short: short.BurnRate(objective), Long: long.BurnRate(objective)}
+}
+
+// Triggers returns the triggers with counts in the ConfigMap.
+func Triggers(cm *corev1.ConfigMap) []types.NamespacedName {
+	seen := make(map[string]bool)
+	var triggers []types.NamespacedName
+	for _, r := range parse(cm) {
+		for _, s := range r.Samples {
+			if seen[s.Trigger] {
+				continue
+			}
+			seen[s.Trigger] = true
+			if t, ok := parseTrigger(s.Trigger); ok {
+				triggers = append(triggers, t)
+			}
+		}
+	}
+	return triggers
+}
+
+// parse returns the report of each pod in the ConfigMap. Entries that can't
+// be parsed are skipped.
+func parse(cm *corev1.ConfigMap) map[string]Report {
+	if cm == nil {
+		return nil
+	}
+	res := make(map[string]Report, len(cm.Data))
+	for pod, data := range cm.Data {
+		var r Report
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			continue
+		}
+		res[pod] = r
+	}
+	return res
+}
+
+// expired returns whether the report written as data is older than
+// ReportExpiry at now. Entries that can't be parsed are expired too.
+func expired(data string, now time.Time) bool {
+	var r Report
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		return true
+	}
+	return now.Sub(r.Time) > ReportExpiry
+}
+
+func parseTrigger(s string) (types.NamespacedName, bool) {
+	i := strings.IndexByte(s, types.Separator)
+	if i < 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: s[:i], Name: s[i+1:]}, true
+}
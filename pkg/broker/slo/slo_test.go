@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var testTrigger = types.NamespacedName{Namespace: "ns", Name: "trigger"}
+
+func configMap(t *testing.T, reports map[string]Report) *corev1.ConfigMap {
+	t.Helper()
+	cm := &corev1.ConfigMap{Data: make(map[string]string, len(reports))}
+	for pod, r := range reports {
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cm.Data[pod] = string(b)
+	}
+	return cm
+}
+
+func TestBurnRate(t *testing.T) {
+	testCases := map[string]struct {
+		counts    Counts
+		objective float64
+		want      float64
+	}{
+		"no deliveries": {
+			objective: 0.99,
+		},
+		"no failures": {
+			counts:    Counts{Total: 100},
+			objective: 0.99,
+		},
+		"at the objective": {
+			counts:    Counts{Total: 100, Failed: 1},
+			objective: 0.99,
+			want:      1,
+		},
+		"all failed": {
+			counts:    Counts{Total: 100, Failed: 100},
+			objective: 0.9,
+			want:      10,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.counts.BurnRate(tc.objective); got < tc.want-1e-9 || got > tc.want+1e-9 {
+				t.Errorf("BurnRate() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Now()
+	cm := configMap(t, map[string]Report{
+		"fanout-1": {
+			Time: now,
+			Samples: []Sample{
+				{Trigger: "ns/trigger", Short: Counts{Total: 50, Failed: 10}, Long: Counts{Total: 500, Failed: 40}},
+				{Trigger: "ns/other", Short: Counts{Total: 50, Failed: 50}, Long: Counts{Total: 50, Failed: 50}},
+			},
+		},
+		"retry-1": {
+			Time: now,
+			Samples: []Sample{
+				{Trigger: "ns/trigger", Short: Counts{Total: 50, Failed: 10}, Long: Counts{Total: 500, Failed: 60}},
+			},
+		},
+		// Expired.
+		"fanout-2": {
+			Time: now.Add(-time.Hour),
+			Samples: []Sample{
+				{Trigger: "ns/trigger", Short: Counts{Total: 100}, Long: Counts{Total: 1000}},
+			},
+		},
+	})
+	cm.Data["malformed"] = "not json"
+
+	got := Evaluate(cm, testTrigger, 0.99, now.Add(-ReportExpiry))
+	want := BurnRates{Short: 20, Long: 10}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("Evaluate() (-want, +got) = %v", diff)
+	}
+	if got.Burning() {
+		t.Errorf("Burning() = true, want false with a long window burn rate of %v", got.Long)
+	}
+	if !(BurnRates{Short: 20, Long: 15}).Burning() {
+		t.Error("Burning() = false, want true with both burn rates above the threshold")
+	}
+
+	if got := Evaluate(nil, testTrigger, 0.99, now.Add(-ReportExpiry)); got != (BurnRates{}) {
+		t.Errorf("Evaluate() without a ConfigMap = %+v, want zero burn rates", got)
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	cm := configMap(t, map[string]Report{
+		"fanout-1": {Samples: []Sample{{Trigger: "ns/trigger"}, {Trigger: "invalid"}}},
+		"retry-1":  {Samples: []Sample{{Trigger: "ns/trigger"}}},
+	})
+	want := []types.NamespacedName{testTrigger}
+	if diff := cmp.Diff(want, Triggers(cm)); diff != "" {
+		t.Errorf("Triggers() (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/logging"
+)
+
+// failureKey identifies the failures of a broker to a kind of topic.
+type failureKey struct {
+	broker types.NamespacedName
+	queue  Queue
+}
+
+// Reporter keeps the recent publish failures of a data plane pod, and writes
+// them to the entry of the pod in the ConfigMap. A nil Reporter ignores all
+// reports.
+type Reporter struct {
+	client    kubernetes.Interface
+	namespace string
+	pod       string
+	logger    *zap.Logger
+
+	// count is the number of failures, read without the lock so that
+	// successful publishes are cheap to report when there are no failures.
+	count    int32
+	mu       sync.Mutex
+	failures map[failureKey]PublishFailure
+	dirty    bool
+
+	// now returns the current time. It is replaced in tests.
+	now func() time.Time
+}
+
+// NewReporter creates a Reporter writing the failures of the pod to the
+// ConfigMap in the namespace.
+func NewReporter(ctx context.Context, client kubernetes.Interface, namespace, pod string) *Reporter {
+	return &Reporter{
+		client:    client,
+		namespace: namespace,
+		pod:       pod,
+		logger:    logging.FromContext(ctx).Desugar(),
+		failures:  make(map[failureKey]PublishFailure),
+		now:       time.Now,
+	}
+}
+
+// Report records the result of publishing events of the broker to a topic. A
+// nil error clears the failure of the broker to the topic. Errors that don't
+// require an action from the user are ignored.
+func (r *Reporter) Report(broker types.NamespacedName, queue Queue, err error) {
+	if r == nil {
+		return
+	}
+	if err == nil {
+		if atomic.LoadInt32(&r.count) == 0 {
+			return
+		}
+		r.clear(failureKey{broker: broker, queue: queue})
+		return
+	}
+	reason := Reason(err)
+	if reason == "" {
+		return
+	}
+
+	key := failureKey{broker: broker, queue: queue}
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prev, ok := r.failures[key]
+	// A persisting failure is only written again once it is half expired, to
+	// keep the ConfigMap updates low.
+	if ok && prev.Reason == reason && now.Sub(prev.Time) < FailureExpiry/2 {
+		return
+	}
+	r.failures[key] = PublishFailure{
+		Broker: broker.String(),
+		Queue:  queue,
+		Reason: reason,
+		Error:  err.Error(),
+		Time:   now,
+	}
+	atomic.StoreInt32(&r.count, int32(len(r.failures)))
+	r.dirty = true
+}
+
+func (r *Reporter) clear(key failureKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.failures[key]; !ok {
+		return
+	}
+	delete(r.failures, key)
+	atomic.StoreInt32(&r.count, int32(len(r.failures)))
+	r.dirty = true
+}
+
+// Run writes the failures to the ConfigMap every interval until the context
+// is done, then removes the entry of the pod.
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	if r == nil {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := r.write(nil); err != nil {
+				r.logger.Warn("Failed to remove the publish failures of the pod", zap.Error(err))
+			}
+			return
+		case <-ticker.C:
+			r.sync()
+		}
+	}
+}
+
+// sync prunes the expired failures and writes the remaining ones if they
+// changed since the last write.
+func (r *Reporter) sync() {
+	failures, changed := r.snapshot()
+	if !changed {
+		return
+	}
+	if err := r.write(failures); err != nil {
+		r.logger.Warn("Failed to write the publish failures of the pod", zap.Error(err))
+		r.mu.Lock()
+		r.dirty = true
+		r.mu.Unlock()
+	}
+}
+
+// snapshot prunes the expired failures, and returns the remaining ones and
+// whether they changed since the last snapshot.
+func (r *Reporter) snapshot() ([]PublishFailure, bool) {
+	expired := r.now().Add(-FailureExpiry)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, f := range r.failures {
+		if !f.Time.After(expired) {
+			delete(r.failures, key)
+			r.dirty = true
+		}
+	}
+	atomic.StoreInt32(&r.count, int32(len(r.failures)))
+	if !r.dirty {
+		return nil, false
+	}
+	r.dirty = false
+	failures := make([]PublishFailure, 0, len(r.failures))
+	for _, f := range r.failures {
+		failures = append(failures, f)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Broker != failures[j].Broker {
+			return failures[i].Broker < failures[j].Broker
+		}
+		return failures[i].Queue < failures[j].Queue
+	})
+	return failures, true
+}
+
+// write sets the entry of the pod in the ConfigMap to the failures, creating
+// the ConfigMap if needed. The entry is removed if there are no failures.
+func (r *Reporter) write(failures []PublishFailure) error {
+	var data string
+	if len(failures) > 0 {
+		b, err := json.Marshal(failures)
+		if err != nil {
+			return err
+		}
+		data = string(b)
+	}
+	configMaps := r.client.CoreV1().ConfigMaps(r.namespace)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(ConfigMapName, metav1.GetOptions{})
+		if apierrs.IsNotFound(err) {
+			if data == "" {
+				return nil
+			}
+			_, err = configMaps.Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConfigMapName,
+					Namespace: r.namespace,
+				},
+				Data: map[string]string{r.pod: data},
+			})
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		if cm.Data[r.pod] == data {
+			return nil
+		}
+		cm = cm.DeepCopy()
+		if data == "" {
+			delete(cm.Data, r.pod)
+		} else {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string, 1)
+			}
+			cm.Data[r.pod] = data
+		}
+		_, err = configMaps.Update(cm)
+		return err
+	})
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testNS  = "cloud-run-events"
+	testPod = "ingress-1"
+)
+
+var (
+	testBroker = types.NamespacedName{Namespace: "ns", Name: "broker"}
+	quotaErr   = grpcstatus.Error(codes.ResourceExhausted, "quota exceeded")
+)
+
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.Report(testBroker, DecoupleQueue, quotaErr)
+	r.Run(context.Background(), time.Second)
+}
+
+func TestReporter(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r := NewReporter(context.Background(), client, testNS, testPod)
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	// Errors not requiring an action are ignored.
+	r.Report(testBroker, DecoupleQueue, errors.New("induced error"))
+	r.sync()
+	if cm := getConfigMap(t, client); cm != nil {
+		t.Fatalf("ConfigMap = %+v, want it not created", cm)
+	}
+
+	r.Report(testBroker, DecoupleQueue, quotaErr)
+	r.sync()
+	cm := getConfigMap(t, client)
+	if cm == nil {
+		t.Fatal("ConfigMap was not created")
+	}
+	f := LatestFailure(cm, testBroker, now.Add(-FailureExpiry))
+	if f == nil || f.Reason != ReasonQuotaExceeded || f.Queue != DecoupleQueue {
+		t.Fatalf("LatestFailure() = %+v, want a quota failure of the decouple queue", f)
+	}
+
+	// A successful publish clears the failure.
+	r.Report(testBroker, DecoupleQueue, nil)
+	r.sync()
+	if _, ok := getConfigMap(t, client).Data[testPod]; ok {
+		t.Errorf("entry of the pod was not removed after a successful publish")
+	}
+
+	// Failures expire.
+	r.Report(testBroker, RetryQueue, quotaErr)
+	r.sync()
+	if _, ok := getConfigMap(t, client).Data[testPod]; !ok {
+		t.Fatalf("entry of the pod was not written")
+	}
+	now = now.Add(FailureExpiry)
+	r.sync()
+	if _, ok := getConfigMap(t, client).Data[testPod]; ok {
+		t.Errorf("entry of the pod was not removed after the failure expired")
+	}
+}
+
+func TestReporterRemovesEntryOnShutdown(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: testNS},
+		Data:       map[string]string{"other-pod": "[]"},
+	})
+	r := NewReporter(context.Background(), client, testNS, testPod)
+	r.Report(testBroker, DecoupleQueue, quotaErr)
+	r.sync()
+	if _, ok := getConfigMap(t, client).Data[testPod]; !ok {
+		t.Fatalf("entry of the pod was not written")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r.Run(ctx, time.Hour)
+	cm := getConfigMap(t, client)
+	if _, ok := cm.Data[testPod]; ok {
+		t.Errorf("entry of the pod was not removed on shutdown")
+	}
+	if _, ok := cm.Data["other-pod"]; !ok {
+		t.Errorf("entry of another pod was removed")
+	}
+}
+
+func getConfigMap(t *testing.T, client *fake.Clientset) *corev1.ConfigMap {
+	t.Helper()
+	cm, err := client.CoreV1().ConfigMaps(testNS).Get(ConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return cm
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package status reports the errors the data plane hits publishing the events
+// of brokers to their PubSub topics. The data plane pods write their recent
+// failures to a shared ConfigMap, which the broker controller reads to surface
+// them in the conditions of the brokers.
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap the data plane pods write
+	// their publish failures to, in the namespace of the data plane.
+	ConfigMapName = "broker-publish-status"
+
+	// ReasonQuotaExceeded is the reason of failures caused by an exhausted
+	// PubSub quota.
+	ReasonQuotaExceeded = "PublishQuotaExceeded"
+	// ReasonPermissionDenied is the reason of failures caused by the data
+	// plane missing the permission to publish to a topic.
+	ReasonPermissionDenied = "PublishPermissionDenied"
+
+	// FailureExpiry is how long a failure is reported after it last
+	// happened.
+	FailureExpiry = 2 * time.Minute
+)
+
+// Queue is the kind of PubSub topic a failed publish was sent to.
+type Queue string
+
+const (
+	// DecoupleQueue is the decouple topic the ingress publishes the events
+	// sent to a broker to.
+	DecoupleQueue Queue = "decouple"
+	// RetryQueue is the retry topic of a trigger the fanout publishes the
+	// events that failed delivery to.
+	RetryQueue Queue = "retry"
+)
+
+// PublishFailure is a failure to publish the events of a broker.
+type PublishFailure struct {
+	// Broker is the namespace/name of the broker.
+	Broker string `json:"broker"`
+	// Queue is the kind of topic the events were published to.
+	Queue Queue `json:"queue"`
+	// Reason is the reason of the failure, e.g. ReasonQuotaExceeded.
+	Reason string `json:"reason"`
+	// Error is the error returned by PubSub.
+	Error string `json:"error"`
+	// Time is when the failure last happened.
+	Time time.Time `json:"time"`
+}
+
+// Reason returns the reason of a publish error, or an empty string if the
+// error is not one that requires an action from the user.
+func Reason(err error) string {
+	for ; err != nil; err = errors.Unwrap(err) {
+		s, ok := grpcstatus.FromError(err)
+		if !ok {
+			continue
+		}
+		switch s.Code() {
+		case codes.ResourceExhausted:
+			return ReasonQuotaExceeded
+		case codes.PermissionDenied, codes.Unauthenticated:
+			return ReasonPermissionDenied
+		}
+		return ""
+	}
+	return ""
+}
+
+// LatestFailure returns the latest failure of the broker reported in the
+// ConfigMap after since, or nil if there is none.
+func LatestFailure(cm *corev1.ConfigMap, broker types.NamespacedName, since time.Time) *PublishFailure {
+	var latest *PublishFailure
+	for _, failures := range parse(cm) {
+		for i := range failures {
+			f := &failures[i]
+			if f.Broker != broker.String() || !f.Time.After(since) {
+				continue
+			}
+			if latest == nil || f.Time.After(latest.Time) {
+				latest = f
+			}
+		}
+	}
+	return latest
+}
+
+// Brokers returns the brokers with failures reported in the ConfigMap.
+func Brokers(cm *corev1.ConfigMap) []types.NamespacedName {
+	seen := make(map[string]bool)
+	var brokers []types.NamespacedName
+	for _, failures := range parse(cm) {
+		for _, f := range failures {
+			if seen[f.Broker] {
+				continue
+			}
+			seen[f.Broker] = true
+			if b, ok := parseBroker(f.Broker); ok {
+				brokers = append(brokers, b)
+			}
+		}
+	}
+	return brokers
+}
+
+// parse returns the failures reported by each pod in the ConfigMap. Entries
+// that can't be parsed are skipped.
+func parse(cm *corev1.ConfigMap) map[string][]PublishFailure {
+	if cm == nil {
+		return nil
+	}
+	res := make(map[string][]PublishFailure, len(cm.Data))
+	for pod, data := range cm.Data {
+		var failures []PublishFailure
+		if err := json.Unmarshal([]byte(data), &failures); err != nil {
+			continue
+		}
+		res[pod] = failures
+	}
+	return res
+}
+
+func parseBroker(s string) (types.NamespacedName, bool) {
+	i := strings.IndexByte(s, types.Separator)
+	if i < 0 {
+		return types.NamespacedName{}, false
+	}
+	return types.NamespacedName{Namespace: s[:i], Name: s[i+1:]}, true
+}
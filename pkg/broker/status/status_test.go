@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc/codes"
+	grpcstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{{
+		name: "nil",
+	}, {
+		name: "not a grpc error",
+		err:  errors.New("induced error"),
+	}, {
+		name: "quota exceeded",
+		err:  grpcstatus.Error(codes.ResourceExhausted, "quota exceeded"),
+		want: ReasonQuotaExceeded,
+	}, {
+		name: "wrapped permission denied",
+		err:  fmt.Errorf("failed to publish: %w", grpcstatus.Error(codes.PermissionDenied, "denied")),
+		want: ReasonPermissionDenied,
+	}, {
+		name: "unauthenticated",
+		err:  grpcstatus.Error(codes.Unauthenticated, "unauthenticated"),
+		want: ReasonPermissionDenied,
+	}, {
+		name: "unavailable",
+		err:  grpcstatus.Error(codes.Unavailable, "unavailable"),
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Reason(tt.err); got != tt.want {
+				t.Errorf("Reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatestFailure(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cm := &corev1.ConfigMap{
+		Data: map[string]string{
+			"ingress-1": `[{"broker":"ns/b1","queue":"decouple","reason":"PublishQuotaExceeded","error":"quota","time":"` + now.Add(-time.Minute).Format(time.RFC3339) + `"}]`,
+			"fanout-1":  `[{"broker":"ns/b1","queue":"retry","reason":"PublishPermissionDenied","error":"denied","time":"` + now.Format(time.RFC3339) + `"},{"broker":"ns/b2","queue":"retry","reason":"PublishQuotaExceeded","error":"quota","time":"` + now.Add(-time.Hour).Format(time.RFC3339) + `"}]`,
+			"invalid":   `not json`,
+		},
+	}
+	b1 := types.NamespacedName{Namespace: "ns", Name: "b1"}
+	b2 := types.NamespacedName{Namespace: "ns", Name: "b2"}
+
+	want := &PublishFailure{Broker: "ns/b1", Queue: RetryQueue, Reason: ReasonPermissionDenied, Error: "denied", Time: now}
+	if diff := cmp.Diff(want, LatestFailure(cm, b1, now.Add(-FailureExpiry))); diff != "" {
+		t.Errorf("LatestFailure(b1) (-want,+got): %v", diff)
+	}
+	if got := LatestFailure(cm, b2, now.Add(-FailureExpiry)); got != nil {
+		t.Errorf("LatestFailure(b2) = %+v, want nil for an expired failure", got)
+	}
+	if got := LatestFailure(nil, b1, now.Add(-FailureExpiry)); got != nil {
+		t.Errorf("LatestFailure(nil) = %+v, want nil", got)
+	}
+
+	got := Brokers(cm)
+	if len(got) != 2 {
+		t.Fatalf("Brokers() = %v, want 2 brokers", got)
+	}
+	seen := map[types.NamespacedName]bool{got[0]: true, got[1]: true}
+	if !seen[b1] || !seen[b2] {
+		t.Errorf("Brokers() = %v, want %v and %v", got, b1, b2)
+	}
+}
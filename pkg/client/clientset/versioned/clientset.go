@@ -28,6 +28,7 @@ import (
 	internalv1beta1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/intevents/v1beta1"
 	messagingv1alpha1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/messaging/v1alpha1"
 	messagingv1beta1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/messaging/v1beta1"
+	operatorv1alpha1 "github.com/google/knative-gcp/pkg/client/clientset/versioned/typed/operator/v1alpha1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -42,6 +43,7 @@ type Interface interface {
 	InternalV1beta1() internalv1beta1.InternalV1beta1Interface
 	MessagingV1alpha1() messagingv1alpha1.MessagingV1alpha1Interface
 	MessagingV1beta1() messagingv1beta1.MessagingV1beta1Interface
+	OperatorV1alpha1() operatorv1alpha1.OperatorV1alpha1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -55,6 +57,7 @@ type Clientset struct {
 	internalV1beta1   *internalv1beta1.InternalV1beta1Client
 	messagingV1alpha1 *messagingv1alpha1.MessagingV1alpha1Client
 	messagingV1beta1  *messagingv1beta1.MessagingV1beta1Client
+	operatorV1alpha1  *operatorv1alpha1.OperatorV1alpha1Client
 }
 
 // EventingV1beta1 retrieves the EventingV1beta1Client
@@ -92,6 +95,11 @@ func (c *Clientset) MessagingV1beta1() messagingv1beta1.MessagingV1beta1Interfac
 	return c.messagingV1beta1
 }
 
+// OperatorV1alpha1 retrieves the OperatorV1alpha1Client
+func (c *Clientset) OperatorV1alpha1() operatorv1alpha1.OperatorV1alpha1Interface {
+	return c.operatorV1alpha1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -141,6 +149,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.operatorV1alpha1, err = operatorv1alpha1.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(&configShallowCopy)
 	if err != nil {
@@ -160,6 +172,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 	cs.internalV1beta1 = internalv1beta1.NewForConfigOrDie(c)
 	cs.messagingV1alpha1 = messagingv1alpha1.NewForConfigOrDie(c)
 	cs.messagingV1beta1 = messagingv1beta1.NewForConfigOrDie(c)
+	cs.operatorV1alpha1 = operatorv1alpha1.NewForConfigOrDie(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClientForConfigOrDie(c)
 	return &cs
@@ -175,6 +188,7 @@ func New(c rest.Interface) *Clientset {
 	cs.internalV1beta1 = internalv1beta1.New(c)
 	cs.messagingV1alpha1 = messagingv1alpha1.New(c)
 	cs.messagingV1beta1 = messagingv1beta1.New(c)
+	cs.operatorV1alpha1 = operatorv1alpha1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -31,6 +31,7 @@ type EventsV1alpha1Interface interface {
 	CloudPubSubSourcesGetter
 	CloudSchedulerSourcesGetter
 	CloudStorageSourcesGetter
+	SourceSetsGetter
 }
 
 // EventsV1alpha1Client is used to interact with features provided by the events.cloud.google.com group.
@@ -58,6 +59,10 @@ func (c *EventsV1alpha1Client) CloudStorageSources(namespace string) CloudStorag
 	return newCloudStorageSources(c, namespace)
 }
 
+func (c *EventsV1alpha1Client) SourceSets() SourceSetInterface {
+	return newSourceSets(c)
+}
+
 // NewForConfig creates a new EventsV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*EventsV1alpha1Client, error) {
 	config := *c
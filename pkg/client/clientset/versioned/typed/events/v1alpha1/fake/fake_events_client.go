@@ -48,6 +48,10 @@ func (c *FakeEventsV1alpha1) CloudStorageSources(namespace string) v1alpha1.Clou
 	return &FakeCloudStorageSources{c, namespace}
 }
 
+func (c *FakeEventsV1alpha1) SourceSets() v1alpha1.SourceSetInterface {
+	return &FakeSourceSets{c}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeEventsV1alpha1) RESTClient() rest.Interface {
@@ -27,3 +27,5 @@ type CloudPubSubSourceExpansion interface{}
 type CloudSchedulerSourceExpansion interface{}
 
 type CloudStorageSourceExpansion interface{}
+
+type SourceSetExpansion interface{}
@@ -26,11 +26,16 @@ import (
 
 type EventsV1beta1Interface interface {
 	RESTClient() rest.Interface
+	CloudArtifactRegistrySourcesGetter
 	CloudAuditLogsSourcesGetter
+	CloudBillingBudgetSourcesGetter
 	CloudBuildSourcesGetter
+	CloudMonitoringAlertSourcesGetter
 	CloudPubSubSourcesGetter
 	CloudSchedulerSourcesGetter
 	CloudStorageSourcesGetter
+	SecretManagerRotationSourcesGetter
+	WebhookSourcesGetter
 }
 
 // EventsV1beta1Client is used to interact with features provided by the events.cloud.google.com group.
@@ -38,14 +43,26 @@ type EventsV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *EventsV1beta1Client) CloudArtifactRegistrySources(namespace string) CloudArtifactRegistrySourceInterface {
+	return newCloudArtifactRegistrySources(c, namespace)
+}
+
 func (c *EventsV1beta1Client) CloudAuditLogsSources(namespace string) CloudAuditLogsSourceInterface {
 	return newCloudAuditLogsSources(c, namespace)
 }
 
+func (c *EventsV1beta1Client) CloudBillingBudgetSources(namespace string) CloudBillingBudgetSourceInterface {
+	return newCloudBillingBudgetSources(c, namespace)
+}
+
 func (c *EventsV1beta1Client) CloudBuildSources(namespace string) CloudBuildSourceInterface {
 	return newCloudBuildSources(c, namespace)
 }
 
+func (c *EventsV1beta1Client) CloudMonitoringAlertSources(namespace string) CloudMonitoringAlertSourceInterface {
+	return newCloudMonitoringAlertSources(c, namespace)
+}
+
 func (c *EventsV1beta1Client) CloudPubSubSources(namespace string) CloudPubSubSourceInterface {
 	return newCloudPubSubSources(c, namespace)
 }
@@ -58,6 +75,14 @@ func (c *EventsV1beta1Client) CloudStorageSources(namespace string) CloudStorage
 	return newCloudStorageSources(c, namespace)
 }
 
+func (c *EventsV1beta1Client) SecretManagerRotationSources(namespace string) SecretManagerRotationSourceInterface {
+	return newSecretManagerRotationSources(c, namespace)
+}
+
+func (c *EventsV1beta1Client) WebhookSources(namespace string) WebhookSourceInterface {
+	return newWebhookSources(c, namespace)
+}
+
 // NewForConfig creates a new EventsV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*EventsV1beta1Client, error) {
 	config := *c
@@ -28,14 +28,26 @@ type FakeEventsV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeEventsV1beta1) CloudArtifactRegistrySources(namespace string) v1beta1.CloudArtifactRegistrySourceInterface {
+	return &FakeCloudArtifactRegistrySources{c, namespace}
+}
+
 func (c *FakeEventsV1beta1) CloudAuditLogsSources(namespace string) v1beta1.CloudAuditLogsSourceInterface {
 	return &FakeCloudAuditLogsSources{c, namespace}
 }
 
+func (c *FakeEventsV1beta1) CloudBillingBudgetSources(namespace string) v1beta1.CloudBillingBudgetSourceInterface {
+	return &FakeCloudBillingBudgetSources{c, namespace}
+}
+
 func (c *FakeEventsV1beta1) CloudBuildSources(namespace string) v1beta1.CloudBuildSourceInterface {
 	return &FakeCloudBuildSources{c, namespace}
 }
 
+func (c *FakeEventsV1beta1) CloudMonitoringAlertSources(namespace string) v1beta1.CloudMonitoringAlertSourceInterface {
+	return &FakeCloudMonitoringAlertSources{c, namespace}
+}
+
 func (c *FakeEventsV1beta1) CloudPubSubSources(namespace string) v1beta1.CloudPubSubSourceInterface {
 	return &FakeCloudPubSubSources{c, namespace}
 }
@@ -48,6 +60,14 @@ func (c *FakeEventsV1beta1) CloudStorageSources(namespace string) v1beta1.CloudS
 	return &FakeCloudStorageSources{c, namespace}
 }
 
+func (c *FakeEventsV1beta1) SecretManagerRotationSources(namespace string) v1beta1.SecretManagerRotationSourceInterface {
+	return &FakeSecretManagerRotationSources{c, namespace}
+}
+
+func (c *FakeEventsV1beta1) WebhookSources(namespace string) v1beta1.WebhookSourceInterface {
+	return &FakeWebhookSources{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeEventsV1beta1) RESTClient() rest.Interface {
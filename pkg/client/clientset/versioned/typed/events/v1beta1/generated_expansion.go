@@ -18,12 +18,22 @@ limitations under the License.
 
 package v1beta1
 
+type CloudArtifactRegistrySourceExpansion interface{}
+
 type CloudAuditLogsSourceExpansion interface{}
 
+type CloudBillingBudgetSourceExpansion interface{}
+
 type CloudBuildSourceExpansion interface{}
 
+type CloudMonitoringAlertSourceExpansion interface{}
+
 type CloudPubSubSourceExpansion interface{}
 
 type CloudSchedulerSourceExpansion interface{}
 
 type CloudStorageSourceExpansion interface{}
+
+type SecretManagerRotationSourceExpansion interface{}
+
+type WebhookSourceExpansion interface{}
@@ -32,6 +32,10 @@ func (c *FakeInternalV1alpha1) BrokerCells(namespace string) v1alpha1.BrokerCell
 	return &FakeBrokerCells{c, namespace}
 }
 
+func (c *FakeInternalV1alpha1) EventSchemas(namespace string) v1alpha1.EventSchemaInterface {
+	return &FakeEventSchemas{c, namespace}
+}
+
 func (c *FakeInternalV1alpha1) PullSubscriptions(namespace string) v1alpha1.PullSubscriptionInterface {
 	return &FakePullSubscriptions{c, namespace}
 }
@@ -20,6 +20,8 @@ package v1alpha1
 
 type BrokerCellExpansion interface{}
 
+type EventSchemaExpansion interface{}
+
 type PullSubscriptionExpansion interface{}
 
 type TopicExpansion interface{}
@@ -27,6 +27,7 @@ import (
 type InternalV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	BrokerCellsGetter
+	EventSchemasGetter
 	PullSubscriptionsGetter
 	TopicsGetter
 }
@@ -40,6 +41,10 @@ func (c *InternalV1alpha1Client) BrokerCells(namespace string) BrokerCellInterfa
 	return newBrokerCells(c, namespace)
 }
 
+func (c *InternalV1alpha1Client) EventSchemas(namespace string) EventSchemaInterface {
+	return newEventSchemas(c, namespace)
+}
+
 func (c *InternalV1alpha1Client) PullSubscriptions(namespace string) PullSubscriptionInterface {
 	return newPullSubscriptions(c, namespace)
 }
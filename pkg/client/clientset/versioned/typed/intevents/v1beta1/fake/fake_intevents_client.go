@@ -28,6 +28,10 @@ type FakeInternalV1beta1 struct {
 	*testing.Fake
 }
 
+func (c *FakeInternalV1beta1) BrokerCells(namespace string) v1beta1.BrokerCellInterface {
+	return &FakeBrokerCells{c, namespace}
+}
+
 func (c *FakeInternalV1beta1) PullSubscriptions(namespace string) v1beta1.PullSubscriptionInterface {
 	return &FakePullSubscriptions{c, namespace}
 }
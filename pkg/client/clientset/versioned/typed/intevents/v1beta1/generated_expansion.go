@@ -18,6 +18,8 @@ limitations under the License.
 
 package v1beta1
 
+type BrokerCellExpansion interface{}
+
 type PullSubscriptionExpansion interface{}
 
 type TopicExpansion interface{}
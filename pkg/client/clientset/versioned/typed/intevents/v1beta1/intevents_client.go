@@ -26,6 +26,7 @@ import (
 
 type InternalV1beta1Interface interface {
 	RESTClient() rest.Interface
+	BrokerCellsGetter
 	PullSubscriptionsGetter
 	TopicsGetter
 }
@@ -35,6 +36,10 @@ type InternalV1beta1Client struct {
 	restClient rest.Interface
 }
 
+func (c *InternalV1beta1Client) BrokerCells(namespace string) BrokerCellInterface {
+	return newBrokerCells(c, namespace)
+}
+
 func (c *InternalV1beta1Client) PullSubscriptions(namespace string) PullSubscriptionInterface {
 	return newPullSubscriptions(c, namespace)
 }
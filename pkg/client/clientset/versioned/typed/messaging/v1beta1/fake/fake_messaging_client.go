@@ -32,6 +32,14 @@ func (c *FakeMessagingV1beta1) Channels(namespace string) v1beta1.ChannelInterfa
 	return &FakeChannels{c, namespace}
 }
 
+func (c *FakeMessagingV1beta1) Parallels(namespace string) v1beta1.ParallelInterface {
+	return &FakeParallels{c, namespace}
+}
+
+func (c *FakeMessagingV1beta1) Sequences(namespace string) v1beta1.SequenceInterface {
+	return &FakeSequences{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeMessagingV1beta1) RESTClient() rest.Interface {
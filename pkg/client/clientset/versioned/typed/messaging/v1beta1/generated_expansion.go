@@ -19,3 +19,7 @@ limitations under the License.
 package v1beta1
 
 type ChannelExpansion interface{}
+
+type ParallelExpansion interface{}
+
+type SequenceExpansion interface{}
@@ -27,6 +27,8 @@ import (
 type MessagingV1beta1Interface interface {
 	RESTClient() rest.Interface
 	ChannelsGetter
+	ParallelsGetter
+	SequencesGetter
 }
 
 // MessagingV1beta1Client is used to interact with features provided by the messaging.cloud.google.com group.
@@ -38,6 +40,14 @@ func (c *MessagingV1beta1Client) Channels(namespace string) ChannelInterface {
 	return newChannels(c, namespace)
 }
 
+func (c *MessagingV1beta1Client) Parallels(namespace string) ParallelInterface {
+	return newParallels(c, namespace)
+}
+
+func (c *MessagingV1beta1Client) Sequences(namespace string) SequenceInterface {
+	return newSequences(c, namespace)
+}
+
 // NewForConfig creates a new MessagingV1beta1Client for the given config.
 func NewForConfig(c *rest.Config) (*MessagingV1beta1Client, error) {
 	config := *c
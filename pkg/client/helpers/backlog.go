@@ -0,0 +1,96 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/api/iterator"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+)
+
+const (
+	// backlogMetric is the metric counting the unacked messages of a
+	// subscription.
+	backlogMetric = "pubsub.googleapis.com/subscription/num_undelivered_messages"
+
+	// backlogWindow is how far back the backlog samples are looked up. Pub/Sub
+	// metrics are sampled every minute and take a few minutes to be visible.
+	backlogWindow = 10 * time.Minute
+)
+
+var (
+	// ErrNoSubscription is returned when the source has no subscription
+	// yet, e.g. because it is not ready.
+	ErrNoSubscription = errors.New("the source has no subscription yet")
+	// ErrNoBacklogSample is returned when Cloud Monitoring has no recent
+	// sample of the backlog, e.g. because the subscription was just created.
+	ErrNoBacklogSample = errors.New("no recent sample of the backlog")
+)
+
+// Backlog returns the latest sample of the number of messages not yet
+// delivered by the subscription of the source, i.e. the events waiting to be
+// sent to its sink. The subscription is the one recorded in the status of the
+// source by its controller.
+func Backlog(ctx context.Context, client monitoring.MetricClient, src duck.PubSubable) (int64, error) {
+	status := src.PubSubStatus()
+	if status.ProjectID == "" || status.SubscriptionID == "" {
+		return 0, ErrNoSubscription
+	}
+	return SubscriptionBacklog(ctx, client, status.ProjectID, status.SubscriptionID)
+}
+
+// SubscriptionBacklog returns the latest sample of the number of messages not
+// yet delivered by a subscription.
+func SubscriptionBacklog(ctx context.Context, client monitoring.MetricClient, project, subscription string) (int64, error) {
+	now := time.Now()
+	start, err := ptypes.TimestampProto(now.Add(-backlogWindow))
+	if err != nil {
+		return 0, err
+	}
+	end, err := ptypes.TimestampProto(now)
+	if err != nil {
+		return 0, err
+	}
+	it := client.ListTimeSeries(ctx, &monitoringpb.ListTimeSeriesRequest{
+		Name:     "projects/" + project,
+		Filter:   fmt.Sprintf("metric.type = %q AND resource.labels.subscription_id = %q", backlogMetric, subscription),
+		Interval: &monitoringpb.TimeInterval{StartTime: start, EndTime: end},
+		View:     monitoringpb.ListTimeSeriesRequest_FULL,
+	})
+	for {
+		ts, err := it.Next()
+		if err == iterator.Done {
+			return 0, ErrNoBacklogSample
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the backlog of subscription %q: %w", subscription, err)
+		}
+		// Points are returned in reverse time order, so the first one is
+		// the latest sample.
+		if points := ts.GetPoints(); len(points) > 0 {
+			return points[0].GetValue().GetInt64Value(), nil
+		}
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	testmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+)
+
+func timeSeries(values ...int64) *monitoringpb.TimeSeries {
+	ts := &monitoringpb.TimeSeries{}
+	for _, v := range values {
+		ts.Points = append(ts.Points, &monitoringpb.Point{
+			Value: &monitoringpb.TypedValue{Value: &monitoringpb.TypedValue_Int64Value{Int64Value: v}},
+		})
+	}
+	return ts
+}
+
+func TestBacklog(t *testing.T) {
+	listErr := errors.New("list failed")
+	testCases := map[string]struct {
+		subscription string
+		data         testmonitoring.TestMetricClientData
+		want         int64
+		wantErr      error
+	}{
+		"latest sample": {
+			subscription: "sub",
+			data: testmonitoring.TestMetricClientData{
+				TimeSeries: []*monitoringpb.TimeSeries{timeSeries(), timeSeries(42, 17)},
+			},
+			want: 42,
+		},
+		"no sample": {
+			subscription: "sub",
+			wantErr:      ErrNoBacklogSample,
+		},
+		"no subscription": {
+			wantErr: ErrNoSubscription,
+		},
+		"list error": {
+			subscription: "sub",
+			data: testmonitoring.TestMetricClientData{
+				ListTimeSeriesErr: listErr,
+			},
+			wantErr: listErr,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var requests []*monitoringpb.ListTimeSeriesRequest
+			tc.data.Requests = &requests
+			client, err := testmonitoring.TestMetricClientCreator(tc.data)(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := newPubSubSource(1)
+			src.Status.ProjectID = "project"
+			src.Status.SubscriptionID = tc.subscription
+
+			got, err := Backlog(context.Background(), client, src)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Backlog() = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Backlog() = %d, want %d", got, tc.want)
+			}
+			if tc.subscription == "" {
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("got %d ListTimeSeries requests, want 1", len(requests))
+			}
+			const wantFilter = `metric.type = "pubsub.googleapis.com/subscription/num_undelivered_messages" AND resource.labels.subscription_id = "sub"`
+			if req := requests[0]; req.Name != "projects/project" || req.Filter != wantFilter {
+				t.Errorf("unexpected request: %v", req)
+			}
+		})
+	}
+}
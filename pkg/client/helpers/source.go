@@ -0,0 +1,229 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helpers provides typed helpers over the generated clientsets for
+// the common flows of the kn plugin and other automation: creating sources
+// with their defaults, waiting for them to become ready and reading the
+// backlog of their subscriptions. They keep the defaulting, readiness and
+// naming logic of the sources in one place, so that external tooling does not
+// re-implement it.
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/clientset/versioned"
+)
+
+const (
+	// DefaultPollInterval is how often WaitForReady checks a source by
+	// default.
+	DefaultPollInterval = 2 * time.Second
+)
+
+// ErrUnsupportedSource is returned for sources of a type the helpers do not
+// know about.
+var ErrUnsupportedSource = errors.New("unsupported source type")
+
+// Source is a source of the events.cloud.google.com API group, e.g. a
+// *v1beta1.CloudPubSubSource.
+type Source interface {
+	kmeta.OwnerRefable
+	apis.Defaultable
+	apis.Validatable
+	// ConditionSet returns the apis.ConditionSet of the source.
+	ConditionSet() *apis.ConditionSet
+}
+
+// Client creates and watches sources through the generated clientset.
+type Client struct {
+	clientset versioned.Interface
+	// PollInterval is how often WaitForReady checks the source.
+	PollInterval time.Duration
+}
+
+// New creates a Client using the given clientset.
+func New(clientset versioned.Interface) *Client {
+	return &Client{
+		clientset:    clientset,
+		PollInterval: DefaultPollInterval,
+	}
+}
+
+// CreateSource applies the defaults of the source, validates it and creates
+// it. Defaulting and validating it before creating it reports invalid
+// sources without a round trip, and shows the defaults the webhook applies
+// in the returned source. The defaults of the cluster, e.g. its default
+// authentication, are only applied if ctx carries them (see gcpauth.ToContext);
+// otherwise they are left to the webhook.
+func (c *Client) CreateSource(ctx context.Context, src Source) (Source, error) {
+	src.SetDefaults(ctx)
+	if err := src.Validate(ctx); err != nil {
+		return nil, err
+	}
+	client := c.clientset.EventsV1beta1()
+	ns := src.GetObjectMeta().GetNamespace()
+	switch s := src.(type) {
+	case *v1beta1.CloudArtifactRegistrySource:
+		return result(client.CloudArtifactRegistrySources(ns).Create(s))
+	case *v1beta1.CloudAuditLogsSource:
+		return result(client.CloudAuditLogsSources(ns).Create(s))
+	case *v1beta1.CloudBillingBudgetSource:
+		return result(client.CloudBillingBudgetSources(ns).Create(s))
+	case *v1beta1.CloudBuildSource:
+		return result(client.CloudBuildSources(ns).Create(s))
+	case *v1beta1.CloudMonitoringAlertSource:
+		return result(client.CloudMonitoringAlertSources(ns).Create(s))
+	case *v1beta1.CloudPubSubSource:
+		return result(client.CloudPubSubSources(ns).Create(s))
+	case *v1beta1.CloudSchedulerSource:
+		return result(client.CloudSchedulerSources(ns).Create(s))
+	case *v1beta1.CloudStorageSource:
+		return result(client.CloudStorageSources(ns).Create(s))
+	case *v1beta1.SecretManagerRotationSource:
+		return result(client.SecretManagerRotationSources(ns).Create(s))
+	case *v1beta1.WebhookSource:
+		return result(client.WebhookSources(ns).Create(s))
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedSource, src)
+}
+
+// GetSource returns the current version of the source.
+func (c *Client) GetSource(ctx context.Context, src Source) (Source, error) {
+	client := c.clientset.EventsV1beta1()
+	ns, name := src.GetObjectMeta().GetNamespace(), src.GetObjectMeta().GetName()
+	opts := metav1.GetOptions{}
+	switch src.(type) {
+	case *v1beta1.CloudArtifactRegistrySource:
+		return result(client.CloudArtifactRegistrySources(ns).Get(name, opts))
+	case *v1beta1.CloudAuditLogsSource:
+		return result(client.CloudAuditLogsSources(ns).Get(name, opts))
+	case *v1beta1.CloudBillingBudgetSource:
+		return result(client.CloudBillingBudgetSources(ns).Get(name, opts))
+	case *v1beta1.CloudBuildSource:
+		return result(client.CloudBuildSources(ns).Get(name, opts))
+	case *v1beta1.CloudMonitoringAlertSource:
+		return result(client.CloudMonitoringAlertSources(ns).Get(name, opts))
+	case *v1beta1.CloudPubSubSource:
+		return result(client.CloudPubSubSources(ns).Get(name, opts))
+	case *v1beta1.CloudSchedulerSource:
+		return result(client.CloudSchedulerSources(ns).Get(name, opts))
+	case *v1beta1.CloudStorageSource:
+		return result(client.CloudStorageSources(ns).Get(name, opts))
+	case *v1beta1.SecretManagerRotationSource:
+		return result(client.SecretManagerRotationSources(ns).Get(name, opts))
+	case *v1beta1.WebhookSource:
+		return result(client.WebhookSources(ns).Get(name, opts))
+	}
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedSource, src)
+}
+
+// WaitForReady waits until the source is ready, and returns its ready
+// version. A source is ready once its controller has observed its latest
+// generation and its Ready condition is True. It gives up when ctx is done,
+// with an error reporting why the source is not ready.
+func (c *Client) WaitForReady(ctx context.Context, src Source) (Source, error) {
+	var current Source
+	err := wait.PollImmediateUntil(c.PollInterval, func() (bool, error) {
+		var err error
+		if current, err = c.GetSource(ctx, src); err != nil {
+			return false, err
+		}
+		return IsReady(current), nil
+	}, ctx.Done())
+	if errors.Is(err, wait.ErrWaitTimeout) {
+		return nil, notReadyError(current)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// IsReady returns true if the controller of the source has observed its
+// latest generation and its Ready condition is True.
+func IsReady(src Source) bool {
+	status := Status(src)
+	if status == nil || status.ObservedGeneration < src.GetObjectMeta().GetGeneration() {
+		return false
+	}
+	return src.ConditionSet().Manage(status).IsHappy()
+}
+
+// Status returns the duck/v1 Status of the source, or nil if the source is
+// of an unsupported type.
+func Status(src Source) *duckv1.Status {
+	switch s := src.(type) {
+	case *v1beta1.CloudArtifactRegistrySource:
+		return &s.Status.Status
+	case *v1beta1.CloudAuditLogsSource:
+		return &s.Status.Status
+	case *v1beta1.CloudBillingBudgetSource:
+		return &s.Status.Status
+	case *v1beta1.CloudBuildSource:
+		return &s.Status.Status
+	case *v1beta1.CloudMonitoringAlertSource:
+		return &s.Status.Status
+	case *v1beta1.CloudPubSubSource:
+		return &s.Status.Status
+	case *v1beta1.CloudSchedulerSource:
+		return &s.Status.Status
+	case *v1beta1.CloudStorageSource:
+		return &s.Status.Status
+	case *v1beta1.SecretManagerRotationSource:
+		return &s.Status.Status
+	case *v1beta1.WebhookSource:
+		return &s.Status.Status
+	}
+	return nil
+}
+
+// notReadyError reports why the source is not ready.
+func notReadyError(src Source) error {
+	if src == nil {
+		return errors.New("timed out waiting for the source")
+	}
+	meta := src.GetObjectMeta()
+	err := fmt.Errorf("%s %s/%s is not ready", src.GetGroupVersionKind().Kind, meta.GetNamespace(), meta.GetName())
+	if status := Status(src); status != nil {
+		if status.ObservedGeneration < meta.GetGeneration() {
+			return fmt.Errorf("%w: generation %d has not been observed yet", err, meta.GetGeneration())
+		}
+		if cond := src.ConditionSet().Manage(status).GetTopLevelCondition(); cond != nil && cond.Reason != "" {
+			return fmt.Errorf("%w: %s: %s", err, cond.Reason, cond.Message)
+		}
+	}
+	return err
+}
+
+// result converts the typed result of a clientset call, so that a failed
+// call returns a nil Source rather than a Source holding a nil pointer.
+func result(src Source, err error) (Source, error) {
+	if err != nil {
+		return nil, err
+	}
+	return src, nil
+}
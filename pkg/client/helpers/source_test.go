@@ -0,0 +1,171 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
+)
+
+const (
+	testNS   = "testnamespace"
+	testName = "source"
+)
+
+func newPubSubSource(generation int64) *v1beta1.CloudPubSubSource {
+	return &v1beta1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  testNS,
+			Name:       testName,
+			Generation: generation,
+		},
+		Spec: v1beta1.CloudPubSubSourceSpec{
+			Topic: "topic",
+		},
+	}
+}
+
+func withSink(s *v1beta1.CloudPubSubSource) *v1beta1.CloudPubSubSource {
+	s.Spec.Sink = duckv1.Destination{URI: apis.HTTP("sink.example.com")}
+	return s
+}
+
+func withReady(s *v1beta1.CloudPubSubSource, observedGeneration int64) *v1beta1.CloudPubSubSource {
+	s.Status.ObservedGeneration = observedGeneration
+	s.Status.Conditions = duckv1.Conditions{{
+		Type:   apis.ConditionReady,
+		Status: corev1.ConditionTrue,
+	}}
+	return s
+}
+
+func withNotReady(s *v1beta1.CloudPubSubSource, reason, message string) *v1beta1.CloudPubSubSource {
+	s.Status.ObservedGeneration = s.Generation
+	s.Status.Conditions = duckv1.Conditions{{
+		Type:    apis.ConditionReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}}
+	return s
+}
+
+func TestCreateSource(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	created, err := New(clientset).CreateSource(context.Background(), withSink(newPubSubSource(0)))
+	if err != nil {
+		t.Fatalf("CreateSource() = %v", err)
+	}
+	s, ok := created.(*v1beta1.CloudPubSubSource)
+	if !ok {
+		t.Fatalf("CreateSource() returned a %T, want a *v1beta1.CloudPubSubSource", created)
+	}
+	// The defaults are applied before creating the source.
+	if diff := cmp.Diff(ptr.String("30s"), s.Spec.AckDeadline); diff != "" {
+		t.Errorf("unexpected ack deadline (-want, +got) = %v", diff)
+	}
+	got, err := clientset.EventsV1beta1().CloudPubSubSources(testNS).Get(testName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get the created source: %v", err)
+	}
+	if diff := cmp.Diff(s, got); diff != "" {
+		t.Errorf("unexpected source (-want, +got) = %v", diff)
+	}
+}
+
+func TestCreateSourceInvalid(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	if _, err := New(clientset).CreateSource(context.Background(), newPubSubSource(0)); err == nil || !strings.Contains(err.Error(), "sink") {
+		t.Errorf("CreateSource() = %v, want an error about the missing sink", err)
+	}
+	if actions := clientset.Actions(); len(actions) != 0 {
+		t.Errorf("unexpected actions for an invalid source: %v", actions)
+	}
+}
+
+func TestWaitForReady(t *testing.T) {
+	testCases := map[string]struct {
+		source  *v1beta1.CloudPubSubSource
+		wantErr string
+	}{
+		"ready": {
+			source: withReady(newPubSubSource(2), 2),
+		},
+		"generation not observed": {
+			source:  withReady(newPubSubSource(2), 1),
+			wantErr: "CloudPubSubSource testnamespace/source is not ready: generation 2 has not been observed yet",
+		},
+		"not ready": {
+			source:  withNotReady(newPubSubSource(1), "TopicNotReady", "the topic does not exist"),
+			wantErr: "CloudPubSubSource testnamespace/source is not ready: TopicNotReady: the topic does not exist",
+		},
+		"not found": {
+			source:  newPubSubSource(1),
+			wantErr: `cloudpubsubsources.events.cloud.google.com "source" not found`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			clientset := fake.NewSimpleClientset()
+			if n != "not found" {
+				clientset = fake.NewSimpleClientset(tc.source)
+			}
+			c := New(clientset)
+			c.PollInterval = 10 * time.Millisecond
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			got, err := c.WaitForReady(ctx, newPubSubSource(0))
+			if tc.wantErr != "" {
+				if err == nil || err.Error() != tc.wantErr {
+					t.Errorf("WaitForReady() = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("WaitForReady() = %v", err)
+			}
+			if !IsReady(got) {
+				t.Errorf("WaitForReady() returned a source that is not ready: %+v", got)
+			}
+		})
+	}
+}
+
+func TestUnsupportedSource(t *testing.T) {
+	if _, err := New(fake.NewSimpleClientset()).GetSource(context.Background(), &unsupportedSource{}); !errors.Is(err, ErrUnsupportedSource) {
+		t.Errorf("GetSource() = %v, want %v", err, ErrUnsupportedSource)
+	}
+}
+
+// unsupportedSource is a Source the helpers do not know about.
+type unsupportedSource struct {
+	v1beta1.CloudPubSubSource
+}
@@ -34,6 +34,8 @@ type Interface interface {
 	CloudSchedulerSources() CloudSchedulerSourceInformer
 	// CloudStorageSources returns a CloudStorageSourceInformer.
 	CloudStorageSources() CloudStorageSourceInformer
+	// SourceSets returns a SourceSetInformer.
+	SourceSets() SourceSetInformer
 }
 
 type version struct {
@@ -71,3 +73,8 @@ func (v *version) CloudSchedulerSources() CloudSchedulerSourceInformer {
 func (v *version) CloudStorageSources() CloudStorageSourceInformer {
 	return &cloudStorageSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SourceSets returns a SourceSetInformer.
+func (v *version) SourceSets() SourceSetInformer {
+	return &sourceSetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
@@ -24,16 +24,26 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// CloudArtifactRegistrySources returns a CloudArtifactRegistrySourceInformer.
+	CloudArtifactRegistrySources() CloudArtifactRegistrySourceInformer
 	// CloudAuditLogsSources returns a CloudAuditLogsSourceInformer.
 	CloudAuditLogsSources() CloudAuditLogsSourceInformer
+	// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+	CloudBillingBudgetSources() CloudBillingBudgetSourceInformer
 	// CloudBuildSources returns a CloudBuildSourceInformer.
 	CloudBuildSources() CloudBuildSourceInformer
+	// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+	CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer
 	// CloudPubSubSources returns a CloudPubSubSourceInformer.
 	CloudPubSubSources() CloudPubSubSourceInformer
 	// CloudSchedulerSources returns a CloudSchedulerSourceInformer.
 	CloudSchedulerSources() CloudSchedulerSourceInformer
 	// CloudStorageSources returns a CloudStorageSourceInformer.
 	CloudStorageSources() CloudStorageSourceInformer
+	// SecretManagerRotationSources returns a SecretManagerRotationSourceInformer.
+	SecretManagerRotationSources() SecretManagerRotationSourceInformer
+	// WebhookSources returns a WebhookSourceInformer.
+	WebhookSources() WebhookSourceInformer
 }
 
 type version struct {
@@ -47,16 +57,31 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// CloudArtifactRegistrySources returns a CloudArtifactRegistrySourceInformer.
+func (v *version) CloudArtifactRegistrySources() CloudArtifactRegistrySourceInformer {
+	return &cloudArtifactRegistrySourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudAuditLogsSources returns a CloudAuditLogsSourceInformer.
 func (v *version) CloudAuditLogsSources() CloudAuditLogsSourceInformer {
 	return &cloudAuditLogsSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudBillingBudgetSources returns a CloudBillingBudgetSourceInformer.
+func (v *version) CloudBillingBudgetSources() CloudBillingBudgetSourceInformer {
+	return &cloudBillingBudgetSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudBuildSources returns a CloudBuildSourceInformer.
 func (v *version) CloudBuildSources() CloudBuildSourceInformer {
 	return &cloudBuildSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CloudMonitoringAlertSources returns a CloudMonitoringAlertSourceInformer.
+func (v *version) CloudMonitoringAlertSources() CloudMonitoringAlertSourceInformer {
+	return &cloudMonitoringAlertSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CloudPubSubSources returns a CloudPubSubSourceInformer.
 func (v *version) CloudPubSubSources() CloudPubSubSourceInformer {
 	return &cloudPubSubSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -71,3 +96,13 @@ func (v *version) CloudSchedulerSources() CloudSchedulerSourceInformer {
 func (v *version) CloudStorageSources() CloudStorageSourceInformer {
 	return &cloudStorageSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// SecretManagerRotationSources returns a SecretManagerRotationSourceInformer.
+func (v *version) SecretManagerRotationSources() SecretManagerRotationSourceInformer {
+	return &secretManagerRotationSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// WebhookSources returns a WebhookSourceInformer.
+func (v *version) WebhookSources() WebhookSourceInformer {
+	return &webhookSourceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -29,6 +29,7 @@ import (
 	internalinterfaces "github.com/google/knative-gcp/pkg/client/informers/externalversions/internalinterfaces"
 	intevents "github.com/google/knative-gcp/pkg/client/informers/externalversions/intevents"
 	messaging "github.com/google/knative-gcp/pkg/client/informers/externalversions/messaging"
+	operator "github.com/google/knative-gcp/pkg/client/informers/externalversions/operator"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -179,6 +180,7 @@ type SharedInformerFactory interface {
 	Events() events.Interface
 	Internal() intevents.Interface
 	Messaging() messaging.Interface
+	Operator() operator.Interface
 }
 
 func (f *sharedInformerFactory) Eventing() broker.Interface {
@@ -196,3 +198,7 @@ func (f *sharedInformerFactory) Internal() intevents.Interface {
 func (f *sharedInformerFactory) Messaging() messaging.Interface {
 	return messaging.New(f, f.namespace, f.tweakListOptions)
 }
+
+func (f *sharedInformerFactory) Operator() operator.Interface {
+	return operator.New(f, f.namespace, f.tweakListOptions)
+}
@@ -28,6 +28,7 @@ import (
 	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	messagingv1alpha1 "github.com/google/knative-gcp/pkg/apis/messaging/v1alpha1"
 	messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	operatorv1alpha1 "github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -75,28 +76,44 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1alpha1().CloudSchedulerSources().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("cloudstoragesources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1alpha1().CloudStorageSources().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("sourcesets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1alpha1().SourceSets().Informer()}, nil
 
 		// Group=events.cloud.google.com, Version=v1beta1
+	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudartifactregistrysources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudArtifactRegistrySources().Informer()}, nil
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudauditlogssources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudAuditLogsSources().Informer()}, nil
+	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudbillingbudgetsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudBillingBudgetSources().Informer()}, nil
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudbuildsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudBuildSources().Informer()}, nil
+	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudmonitoringalertsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudMonitoringAlertSources().Informer()}, nil
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudpubsubsources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudPubSubSources().Informer()}, nil
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudschedulersources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudSchedulerSources().Informer()}, nil
 	case eventsv1beta1.SchemeGroupVersion.WithResource("cloudstoragesources"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().CloudStorageSources().Informer()}, nil
+	case eventsv1beta1.SchemeGroupVersion.WithResource("secretmanagerrotationsources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().SecretManagerRotationSources().Informer()}, nil
+	case eventsv1beta1.SchemeGroupVersion.WithResource("webhooksources"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Events().V1beta1().WebhookSources().Informer()}, nil
 
 		// Group=internal.events.cloud.google.com, Version=v1alpha1
 	case inteventsv1alpha1.SchemeGroupVersion.WithResource("brokercells"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1alpha1().BrokerCells().Informer()}, nil
+	case inteventsv1alpha1.SchemeGroupVersion.WithResource("eventschemas"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1alpha1().EventSchemas().Informer()}, nil
 	case inteventsv1alpha1.SchemeGroupVersion.WithResource("pullsubscriptions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1alpha1().PullSubscriptions().Informer()}, nil
 	case inteventsv1alpha1.SchemeGroupVersion.WithResource("topics"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1alpha1().Topics().Informer()}, nil
 
 		// Group=internal.events.cloud.google.com, Version=v1beta1
+	case inteventsv1beta1.SchemeGroupVersion.WithResource("brokercells"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1beta1().BrokerCells().Informer()}, nil
 	case inteventsv1beta1.SchemeGroupVersion.WithResource("pullsubscriptions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Internal().V1beta1().PullSubscriptions().Informer()}, nil
 	case inteventsv1beta1.SchemeGroupVersion.WithResource("topics"):
@@ -109,6 +126,14 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=messaging.cloud.google.com, Version=v1beta1
 	case messagingv1beta1.SchemeGroupVersion.WithResource("channels"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().Channels().Informer()}, nil
+	case messagingv1beta1.SchemeGroupVersion.WithResource("parallels"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().Parallels().Informer()}, nil
+	case messagingv1beta1.SchemeGroupVersion.WithResource("sequences"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Messaging().V1beta1().Sequences().Informer()}, nil
+
+		// Group=operator.events.cloud.google.com, Version=v1alpha1
+	case operatorv1alpha1.SchemeGroupVersion.WithResource("knativegcps"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Operator().V1alpha1().KnativeGCPs().Informer()}, nil
 
 	}
 
@@ -26,6 +26,8 @@ import (
 type Interface interface {
 	// BrokerCells returns a BrokerCellInformer.
 	BrokerCells() BrokerCellInformer
+	// EventSchemas returns a EventSchemaInformer.
+	EventSchemas() EventSchemaInformer
 	// PullSubscriptions returns a PullSubscriptionInformer.
 	PullSubscriptions() PullSubscriptionInformer
 	// Topics returns a TopicInformer.
@@ -48,6 +50,11 @@ func (v *version) BrokerCells() BrokerCellInformer {
 	return &brokerCellInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// EventSchemas returns a EventSchemaInformer.
+func (v *version) EventSchemas() EventSchemaInformer {
+	return &eventSchemaInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // PullSubscriptions returns a PullSubscriptionInformer.
 func (v *version) PullSubscriptions() PullSubscriptionInformer {
 	return &pullSubscriptionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// BrokerCells returns a BrokerCellInformer.
+	BrokerCells() BrokerCellInformer
 	// PullSubscriptions returns a PullSubscriptionInformer.
 	PullSubscriptions() PullSubscriptionInformer
 	// Topics returns a TopicInformer.
@@ -41,6 +43,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// BrokerCells returns a BrokerCellInformer.
+func (v *version) BrokerCells() BrokerCellInformer {
+	return &brokerCellInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // PullSubscriptions returns a PullSubscriptionInformer.
 func (v *version) PullSubscriptions() PullSubscriptionInformer {
 	return &pullSubscriptionInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -57,3 +57,7 @@ type CloudStorageSourceListerExpansion interface{}
 // CloudStorageSourceNamespaceListerExpansion allows custom methods to be added to
 // CloudStorageSourceNamespaceLister.
 type CloudStorageSourceNamespaceListerExpansion interface{}
+
+// SourceSetListerExpansion allows custom methods to be added to
+// SourceSetLister.
+type SourceSetListerExpansion interface{}
@@ -26,6 +26,14 @@ type BrokerCellListerExpansion interface{}
 // BrokerCellNamespaceLister.
 type BrokerCellNamespaceListerExpansion interface{}
 
+// EventSchemaListerExpansion allows custom methods to be added to
+// EventSchemaLister.
+type EventSchemaListerExpansion interface{}
+
+// EventSchemaNamespaceListerExpansion allows custom methods to be added to
+// EventSchemaNamespaceLister.
+type EventSchemaNamespaceListerExpansion interface{}
+
 // PullSubscriptionListerExpansion allows custom methods to be added to
 // PullSubscriptionLister.
 type PullSubscriptionListerExpansion interface{}
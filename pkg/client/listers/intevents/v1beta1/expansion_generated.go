@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1beta1
 
+// BrokerCellListerExpansion allows custom methods to be added to
+// BrokerCellLister.
+type BrokerCellListerExpansion interface{}
+
+// BrokerCellNamespaceListerExpansion allows custom methods to be added to
+// BrokerCellNamespaceLister.
+type BrokerCellNamespaceListerExpansion interface{}
+
 // PullSubscriptionListerExpansion allows custom methods to be added to
 // PullSubscriptionLister.
 type PullSubscriptionListerExpansion interface{}
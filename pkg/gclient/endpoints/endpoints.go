@@ -0,0 +1,118 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package endpoints provides Google Cloud API endpoint overrides read from
+// the environment, e.g. for clusters inside a VPC Service Controls perimeter
+// that must reach the APIs through restricted.googleapis.com.
+package endpoints
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"google.golang.org/api/option"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/google/knative-gcp/pkg/gclient/faults"
+)
+
+const (
+	// PubSubEnvKey is the environment variable overriding the Pub/Sub API endpoint.
+	PubSubEnvKey = "PUBSUB_API_ENDPOINT"
+	// PubSubLiteEnvKey is the environment variable overriding the regional
+	// Pub/Sub Lite API endpoint.
+	PubSubLiteEnvKey = "PUBSUB_LITE_API_ENDPOINT"
+	// StorageEnvKey is the environment variable overriding the Cloud Storage API endpoint.
+	StorageEnvKey = "STORAGE_API_ENDPOINT"
+	// SchedulerEnvKey is the environment variable overriding the Cloud Scheduler API endpoint.
+	SchedulerEnvKey = "SCHEDULER_API_ENDPOINT"
+	// LoggingEnvKey is the environment variable overriding the Cloud Logging API endpoint.
+	LoggingEnvKey = "LOGGING_API_ENDPOINT"
+	// MonitoringEnvKey is the environment variable overriding the Cloud Monitoring API endpoint.
+	MonitoringEnvKey = "MONITORING_API_ENDPOINT"
+	// KMSEnvKey is the environment variable overriding the Cloud KMS API endpoint.
+	KMSEnvKey = "KMS_API_ENDPOINT"
+	// IAMCredentialsEnvKey is the environment variable overriding the IAM Credentials API endpoint.
+	IAMCredentialsEnvKey = "IAM_CREDENTIALS_API_ENDPOINT"
+)
+
+// PubSub returns the client options for the Pub/Sub endpoint override, if any,
+// and for the faults injected into Pub/Sub calls in staging clusters.
+func PubSub(ctx context.Context) []option.ClientOption {
+	return append(fromEnv(PubSubEnvKey), faults.PubSub(ctx)...)
+}
+
+// PubSubLite returns the client options for the Pub/Sub Lite endpoint override, if any.
+func PubSubLite() []option.ClientOption {
+	return fromEnv(PubSubLiteEnvKey)
+}
+
+// Storage returns the client options for the Cloud Storage endpoint override, if any.
+func Storage() []option.ClientOption {
+	return fromEnv(StorageEnvKey)
+}
+
+// Scheduler returns the client options for the Cloud Scheduler endpoint override, if any.
+func Scheduler() []option.ClientOption {
+	return fromEnv(SchedulerEnvKey)
+}
+
+// Logging returns the client options for the Cloud Logging endpoint override, if any.
+func Logging() []option.ClientOption {
+	return fromEnv(LoggingEnvKey)
+}
+
+// Monitoring returns the client options for the Cloud Monitoring endpoint override, if any.
+func Monitoring() []option.ClientOption {
+	return fromEnv(MonitoringEnvKey)
+}
+
+// KMS returns the client options for the Cloud KMS endpoint override, if any.
+func KMS() []option.ClientOption {
+	return fromEnv(KMSEnvKey)
+}
+
+// IAMCredentials returns the URL of the IAM Credentials endpoint override, if
+// any. It is set as a URL or as a host and port like the other overrides, but
+// returned as a URL since its clients call the REST API directly.
+func IAMCredentials() string {
+	v := os.Getenv(IAMCredentialsEnvKey)
+	if v != "" && !strings.Contains(v, "://") {
+		v = "https://" + v
+	}
+	return strings.TrimSuffix(v, "/")
+}
+
+// EnvVars returns the overrides and the fault injection set in the current
+// environment, so that the controller can pass them on to the data plane pods
+// it creates.
+func EnvVars() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, k := range []string{PubSubEnvKey, PubSubLiteEnvKey, StorageEnvKey, SchedulerEnvKey, LoggingEnvKey, MonitoringEnvKey, KMSEnvKey, IAMCredentialsEnvKey, faults.PubSubEnvKey} {
+		if v := os.Getenv(k); v != "" {
+			env = append(env, corev1.EnvVar{Name: k, Value: v})
+		}
+	}
+	return env
+}
+
+func fromEnv(key string) []option.ClientOption {
+	if v := os.Getenv(key); v != "" {
+		return []option.ClientOption{option.WithEndpoint(v)}
+	}
+	return nil
+}
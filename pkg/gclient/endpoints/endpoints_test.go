@@ -0,0 +1,88 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/google/knative-gcp/pkg/gclient/faults"
+)
+
+func TestFromEnv(t *testing.T) {
+	for _, k := range []string{PubSubEnvKey, PubSubLiteEnvKey, StorageEnvKey, SchedulerEnvKey, LoggingEnvKey, MonitoringEnvKey, KMSEnvKey, IAMCredentialsEnvKey, faults.PubSubEnvKey} {
+		defer os.Setenv(k, os.Getenv(k))
+		os.Unsetenv(k)
+	}
+
+	if got := PubSub(context.Background()); got != nil {
+		t.Errorf("PubSub(context.Background()) = %v, want nil", got)
+	}
+	if got := EnvVars(); got != nil {
+		t.Errorf("EnvVars() = %v, want nil", got)
+	}
+
+	os.Setenv(PubSubEnvKey, "restricted.googleapis.com:443")
+	os.Setenv(LoggingEnvKey, "restricted.googleapis.com:443")
+
+	if got := len(PubSub(context.Background())); got != 1 {
+		t.Errorf("len(PubSub(context.Background())) = %d, want 1", got)
+	}
+	if got := len(Logging()); got != 1 {
+		t.Errorf("len(Logging()) = %d, want 1", got)
+	}
+	if got := PubSubLite(); got != nil {
+		t.Errorf("PubSubLite() = %v, want nil", got)
+	}
+	if got := Storage(); got != nil {
+		t.Errorf("Storage() = %v, want nil", got)
+	}
+	if got := Scheduler(); got != nil {
+		t.Errorf("Scheduler() = %v, want nil", got)
+	}
+	if got := Monitoring(); got != nil {
+		t.Errorf("Monitoring() = %v, want nil", got)
+	}
+	if got := KMS(); got != nil {
+		t.Errorf("KMS() = %v, want nil", got)
+	}
+	if got := IAMCredentials(); got != "" {
+		t.Errorf("IAMCredentials() = %q, want empty", got)
+	}
+
+	want := []corev1.EnvVar{
+		{Name: PubSubEnvKey, Value: "restricted.googleapis.com:443"},
+		{Name: LoggingEnvKey, Value: "restricted.googleapis.com:443"},
+	}
+	if diff := cmp.Diff(want, EnvVars()); diff != "" {
+		t.Errorf("unexpected env vars (-want, +got): %v", diff)
+	}
+
+	for v, want := range map[string]string{
+		"restricted.googleapis.com:443":      "https://restricted.googleapis.com:443",
+		"https://restricted.googleapis.com/": "https://restricted.googleapis.com",
+	} {
+		os.Setenv(IAMCredentialsEnvKey, v)
+		if got := IAMCredentials(); got != want {
+			t.Errorf("IAMCredentials() with %q = %q, want %q", v, got, want)
+		}
+	}
+}
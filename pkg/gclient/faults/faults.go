@@ -0,0 +1,187 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package faults injects errors and latency into the Pub/Sub API calls made
+// through the client options it returns. It is meant for staging clusters,
+// to validate the retry, dead letter and alerting behavior of the whole
+// stack against an unreliable Pub/Sub, and is disabled unless configured in
+// the environment.
+package faults
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"knative.dev/pkg/logging"
+)
+
+// PubSubEnvKey is the environment variable configuring the faults injected
+// into Pub/Sub calls, as comma-separated class=rate[/latency] pairs, e.g.
+// "publish=0.1/500ms,pull=0.05,admin=0.2/2s". rate is the fraction of the
+// calls of the class that fail with codes.Unavailable, and latency is added
+// to every call of the class. The classes are:
+//   - publish: publishing messages.
+//   - pull: pulling, acking and nacking messages.
+//   - admin: every other call, e.g. creating or deleting topics and subscriptions.
+const PubSubEnvKey = "PUBSUB_FAULT_INJECTION"
+
+// Class is a class of Pub/Sub calls.
+type Class string
+
+const (
+	Publish Class = "publish"
+	Pull    Class = "pull"
+	Admin   Class = "admin"
+)
+
+// Fault is the fault injected into the calls of a class.
+type Fault struct {
+	// ErrorRate is the fraction of calls, between 0 and 1, that fail.
+	ErrorRate float64
+	// Latency is added to every call.
+	Latency time.Duration
+}
+
+// Config maps classes of calls to the faults injected into them.
+type Config map[Class]Fault
+
+var (
+	pubsubOnce   sync.Once
+	pubsubConfig Config
+)
+
+// PubSub returns the client options injecting the faults configured in the
+// environment into Pub/Sub calls, if any.
+func PubSub(ctx context.Context) []option.ClientOption {
+	pubsubOnce.Do(func() {
+		v := os.Getenv(PubSubEnvKey)
+		if v == "" {
+			return
+		}
+		cfg, err := Parse(v)
+		if err != nil {
+			logging.FromContext(ctx).Errorf("Ignoring invalid %s: %v", PubSubEnvKey, err)
+			return
+		}
+		logging.FromContext(ctx).Warnf("Injecting faults into Pub/Sub calls: %s", v)
+		pubsubConfig = cfg
+	})
+	if len(pubsubConfig) == 0 {
+		return nil
+	}
+	return pubsubConfig.ClientOptions()
+}
+
+// Parse parses a fault configuration in the format of PubSubEnvKey.
+func Parse(s string) (Config, error) {
+	cfg := make(Config)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid fault %q, expected class=rate[/latency]", pair)
+		}
+		class := Class(kv[0])
+		if class != Publish && class != Pull && class != Admin {
+			return nil, fmt.Errorf("unknown class %q", kv[0])
+		}
+		var f Fault
+		parts := strings.SplitN(kv[1], "/", 2)
+		rate, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid error rate %q of class %q, expected a number between 0 and 1", parts[0], class)
+		}
+		f.ErrorRate = rate
+		if len(parts) == 2 {
+			latency, err := time.ParseDuration(parts[1])
+			if err != nil || latency < 0 {
+				return nil, fmt.Errorf("invalid latency %q of class %q", parts[1], class)
+			}
+			f.Latency = latency
+		}
+		cfg[class] = f
+	}
+	return cfg, nil
+}
+
+// ClientOptions returns the client options injecting the faults of cfg.
+func (cfg Config) ClientOptions() []option.ClientOption {
+	return []option.ClientOption{
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(cfg.unaryInterceptor)),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(cfg.streamInterceptor)),
+	}
+}
+
+func (cfg Config) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if err := cfg.inject(ctx, method); err != nil {
+		return err
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (cfg Config) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	if err := cfg.inject(ctx, method); err != nil {
+		return nil, err
+	}
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+// inject waits for the latency of the class of method, then returns an error
+// at its error rate.
+func (cfg Config) inject(ctx context.Context, method string) error {
+	f, ok := cfg[classOf(method)]
+	if !ok {
+		return nil
+	}
+	if f.Latency > 0 {
+		t := time.NewTimer(f.Latency)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return status.FromContextError(ctx.Err()).Err()
+		case <-t.C:
+		}
+	}
+	if f.ErrorRate > 0 && rand.Float64() < f.ErrorRate {
+		return status.Errorf(codes.Unavailable, "injected fault in %s", method)
+	}
+	return nil
+}
+
+// classOf returns the class of the full gRPC method name, e.g.
+// "/google.pubsub.v1.Publisher/Publish".
+func classOf(method string) Class {
+	switch method[strings.LastIndex(method, "/")+1:] {
+	case "Publish":
+		return Publish
+	case "Pull", "StreamingPull", "Acknowledge", "ModifyAckDeadline":
+		return Pull
+	}
+	return Admin
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		value   string
+		want    Config
+		wantErr bool
+	}{{
+		name:  "empty",
+		value: "",
+		want:  Config{},
+	}, {
+		name:  "all classes",
+		value: "publish=0.1/500ms, pull=0.05,admin=1/2s",
+		want: Config{
+			Publish: {ErrorRate: 0.1, Latency: 500 * time.Millisecond},
+			Pull:    {ErrorRate: 0.05},
+			Admin:   {ErrorRate: 1, Latency: 2 * time.Second},
+		},
+	}, {
+		name:    "missing rate",
+		value:   "publish",
+		wantErr: true,
+	}, {
+		name:    "unknown class",
+		value:   "ack=0.1",
+		wantErr: true,
+	}, {
+		name:    "rate out of range",
+		value:   "publish=1.5",
+		wantErr: true,
+	}, {
+		name:    "invalid latency",
+		value:   "pull=0.1/soon",
+		wantErr: true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	for method, want := range map[string]Class{
+		"/google.pubsub.v1.Publisher/Publish":             Publish,
+		"/google.pubsub.v1.Subscriber/StreamingPull":      Pull,
+		"/google.pubsub.v1.Subscriber/Acknowledge":        Pull,
+		"/google.pubsub.v1.Subscriber/ModifyAckDeadline":  Pull,
+		"/google.pubsub.v1.Publisher/CreateTopic":         Admin,
+		"/google.pubsub.v1.Subscriber/DeleteSubscription": Admin,
+	} {
+		if got := classOf(method); got != want {
+			t.Errorf("classOf(%q) = %q, want %q", method, got, want)
+		}
+	}
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	cfg := Config{
+		Publish: {ErrorRate: 1},
+		Admin:   {Latency: time.Hour},
+	}
+	var invoked int
+	invoker := func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+		invoked++
+		return nil
+	}
+
+	err := cfg.unaryInterceptor(context.Background(), "/google.pubsub.v1.Publisher/Publish", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Publish error = %v, want code %v", err, codes.Unavailable)
+	}
+	if err := cfg.unaryInterceptor(context.Background(), "/google.pubsub.v1.Subscriber/Pull", nil, nil, nil, invoker); err != nil {
+		t.Errorf("Pull error = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = cfg.unaryInterceptor(ctx, "/google.pubsub.v1.Publisher/CreateTopic", nil, nil, nil, invoker)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("CreateTopic error = %v, want code %v", err, codes.DeadlineExceeded)
+	}
+
+	if invoked != 1 {
+		t.Errorf("invoked %d calls, want 1", invoked)
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	cfg := Config{Pull: {ErrorRate: 1}}
+	streamer := func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+		t.Error("streamer called, want an injected error")
+		return nil, nil
+	}
+	_, err := cfg.streamInterceptor(context.Background(), nil, nil, "/google.pubsub.v1.Subscriber/StreamingPull", streamer)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("StreamingPull error = %v, want code %v", err, codes.Unavailable)
+	}
+}
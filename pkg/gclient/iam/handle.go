@@ -35,6 +35,10 @@ func (c *iamClient) SetPolicy(ctx context.Context, policy *iam.Policy) error {
 	return c.iam.SetPolicy(ctx, policy)
 }
 
+func (c *iamClient) TestPermissions(ctx context.Context, permissions []string) ([]string, error) {
+	return c.iam.TestPermissions(ctx, permissions)
+}
+
 func NewIamHandle(iam *iam.Handle) Handle {
 	return &iamClient{iam: iam}
 }
@@ -31,4 +31,7 @@ type Handle interface {
 
 	// SetPolicy see https://godoc.org/cloud.google.com/go/iam#Handle.SetPolicy
 	SetPolicy(ctx context.Context, policy *iam.Policy) error
+
+	// TestPermissions see https://godoc.org/cloud.google.com/go/iam#Handle.TestPermissions
+	TestPermissions(ctx context.Context, permissions []string) ([]string, error)
 }
@@ -26,6 +26,10 @@ import (
 type TestHandleData struct {
 	PolicyErr    error
 	SetPolicyErr error
+	// Permissions are the permissions granted to the caller. If nil, all
+	// permissions are granted.
+	Permissions        []string
+	TestPermissionsErr error
 }
 
 type testHandle struct {
@@ -42,12 +46,31 @@ func (h *testHandle) Policy(ctx context.Context) (*iam.Policy, error) {
 }
 
 func (h *testHandle) SetPolicy(ctx context.Context, policy *iam.Policy) error {
-	if h.Config.SetPolicyErr != nil {
+	if h.Config.SetPolicyErr == nil {
 		h.policy = *policy
 	}
 	return h.Config.SetPolicyErr
 }
 
+func (h *testHandle) TestPermissions(ctx context.Context, permissions []string) ([]string, error) {
+	if h.Config.TestPermissionsErr != nil {
+		return nil, h.Config.TestPermissionsErr
+	}
+	if h.Config.Permissions == nil {
+		return permissions, nil
+	}
+	var granted []string
+	for _, p := range permissions {
+		for _, g := range h.Config.Permissions {
+			if p == g {
+				granted = append(granted, p)
+				break
+			}
+		}
+	}
+	return granted, nil
+}
+
 func NewTestHandle(config TestHandleData) giam.Handle {
 	return &testHandle{Config: config}
 }
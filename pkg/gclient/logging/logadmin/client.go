@@ -21,6 +21,8 @@ import (
 
 	"cloud.google.com/go/logging/logadmin"
 	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 // CreateFn is a factory function to create a logadmin client.
@@ -28,5 +30,7 @@ import (
 type CreateFn func(ctx context.Context, parent string, opts ...option.ClientOption) (Client, error)
 
 func NewClient(ctx context.Context, parent string, opts ...option.ClientOption) (Client, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.Logging(), opts...)
 	return logadmin.NewClient(ctx, parent, opts...)
 }
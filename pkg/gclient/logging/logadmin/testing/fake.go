@@ -73,6 +73,7 @@ type TestClientConfiguration struct {
 	CreateSinkErr   error
 	DeleteSinkErr   error
 	SinkErr         error
+	UpdateSinkErr   error
 }
 
 type sinkMap struct {
@@ -157,3 +158,29 @@ func (c *testClient) Sink(ctx context.Context, sinkID string) (*logadmin.Sink, e
 	}
 	return nil, status.Errorf(codes.NotFound, "sink %s not found", sinkID)
 }
+
+func (c *testClient) UpdateSinkOpt(ctx context.Context, sink *logadmin.Sink, opts logadmin.SinkOptions) (*logadmin.Sink, error) {
+	if c.closed {
+		return nil, errClientClosed
+	}
+	if c.data.UpdateSinkErr != nil {
+		return nil, c.data.UpdateSinkErr
+	}
+	c.sinks.lock.Lock()
+	defer c.sinks.lock.Unlock()
+	existing, ok := c.sinks.sinks[sink.ID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "sink %s not found", sink.ID)
+	}
+	if opts.UpdateDestination {
+		existing.Destination = sink.Destination
+	}
+	if opts.UpdateFilter {
+		existing.Filter = sink.Filter
+	}
+	if opts.UpdateIncludeChildren {
+		existing.IncludeChildren = sink.IncludeChildren
+	}
+	c.sinks.sinks[sink.ID] = existing
+	return &existing, nil
+}
@@ -238,6 +238,104 @@ func TestDeleteSink(t *testing.T) {
 	}
 }
 
+func TestUpdateSinkOpt(t *testing.T) {
+	testCases := []struct {
+		name         string
+		existing     *logadmin.Sink
+		sink         *logadmin.Sink
+		opts         logadmin.SinkOptions
+		want         *logadmin.Sink
+		errCode      codes.Code
+		clientConfig TestClientConfiguration
+	}{
+		{
+			name: "update succeeds",
+			existing: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "old-destination",
+				Filter:      "old-filter",
+			},
+			sink: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "new-destination",
+				Filter:      "new-filter",
+			},
+			opts: logadmin.SinkOptions{UpdateDestination: true, UpdateFilter: true},
+			want: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "new-destination",
+				Filter:      "new-filter",
+			},
+		},
+		{
+			name: "update only filter",
+			existing: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "old-destination",
+				Filter:      "old-filter",
+			},
+			sink: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "new-destination",
+				Filter:      "new-filter",
+			},
+			opts: logadmin.SinkOptions{UpdateFilter: true},
+			want: &logadmin.Sink{
+				ID:          "test-sink",
+				Destination: "old-destination",
+				Filter:      "new-filter",
+			},
+		},
+		{
+			name: "update not found",
+			sink: &logadmin.Sink{
+				ID: "test-sink",
+			},
+			opts:    logadmin.SinkOptions{UpdateFilter: true},
+			errCode: codes.NotFound,
+		},
+		{
+			name: "update injected error",
+			existing: &logadmin.Sink{
+				ID: "test-sink",
+			},
+			sink: &logadmin.Sink{
+				ID: "test-sink",
+			},
+			opts:    logadmin.SinkOptions{UpdateFilter: true},
+			errCode: codes.Internal,
+			clientConfig: TestClientConfiguration{
+				UpdateSinkErr: status.Error(codes.Internal, "injected error"),
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := createClient(t, tt.clientConfig, ctx, "test-project")
+			if tt.existing != nil {
+				if _, err := client.CreateSink(ctx, tt.existing); err != nil {
+					t.Errorf("failed to create sink during setup: %v", err)
+				}
+			}
+
+			_, err := client.UpdateSinkOpt(ctx, tt.sink, tt.opts)
+
+			if code := status.Code(err); code != tt.errCode {
+				t.Errorf("unexpected error code, wanted %v, got %v", tt.errCode, code)
+			}
+			if err == nil && tt.errCode == codes.OK {
+				got, err := client.Sink(ctx, tt.sink.ID)
+				if err != nil {
+					t.Errorf("unable to get sink after update: %v", err)
+				} else if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(*got, "WriterIdentity")); diff != "" {
+					t.Errorf("Unexpected diff between expected sink and updated sink: %v", diff)
+				}
+			}
+		})
+	}
+}
+
 func createClient(t *testing.T, config TestClientConfiguration, ctx context.Context, parent string) glogadmin.Client {
 	client, err := TestClientCreator(config)(ctx, parent)
 	if err != nil {
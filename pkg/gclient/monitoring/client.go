@@ -0,0 +1,77 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// CreateFn is a factory function to create a Cloud Monitoring client.
+type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, error)
+
+// NewClient creates a new wrapped Cloud Monitoring notification channel client.
+func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.Monitoring(), opts...)
+	client, err := monitoring.NewNotificationChannelClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &monitoringClient{
+		client: client,
+	}, nil
+}
+
+// monitoringClient wraps monitoring.NotificationChannelClient. Is the client that will be used everywhere except unit tests.
+type monitoringClient struct {
+	client *monitoring.NotificationChannelClient
+}
+
+// Verify that it satisfies the monitoring.Client interface.
+var _ Client = &monitoringClient{}
+
+// Close implements monitoring.NotificationChannelClient.Close
+func (c *monitoringClient) Close() error {
+	return c.client.Close()
+}
+
+// CreateNotificationChannel implements monitoring.NotificationChannelClient.CreateNotificationChannel
+func (c *monitoringClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.CreateNotificationChannel(ctx, req, opts...)
+}
+
+// DeleteNotificationChannel implements monitoring.NotificationChannelClient.DeleteNotificationChannel
+func (c *monitoringClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.client.DeleteNotificationChannel(ctx, req, opts...)
+}
+
+// GetNotificationChannel implements monitoring.NotificationChannelClient.GetNotificationChannel
+func (c *monitoringClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	return c.client.GetNotificationChannel(ctx, req, opts...)
+}
+
+// ListNotificationChannels implements monitoring.NotificationChannelClient.ListNotificationChannels
+func (c *monitoringClient) ListNotificationChannels(ctx context.Context, req *monitoringpb.ListNotificationChannelsRequest, opts ...gax.CallOption) NotificationChannelIterator {
+	return c.client.ListNotificationChannels(ctx, req, opts...)
+}
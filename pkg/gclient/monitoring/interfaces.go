@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"github.com/googleapis/gax-go/v2"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+)
+
+// Client matches the interface exposed by monitoring.NotificationChannelClient
+// see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient
+type Client interface {
+	// Close see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient.Close
+	Close() error
+	// CreateNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient.CreateNotificationChannel
+	CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// DeleteNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient.DeleteNotificationChannel
+	DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error
+	// GetNotificationChannel see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient.GetNotificationChannel
+	GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error)
+	// ListNotificationChannels see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelClient.ListNotificationChannels
+	ListNotificationChannels(ctx context.Context, req *monitoringpb.ListNotificationChannelsRequest, opts ...gax.CallOption) NotificationChannelIterator
+}
+
+// NotificationChannelIterator matches the interface exposed by monitoring.NotificationChannelIterator
+// see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelIterator
+type NotificationChannelIterator interface {
+	// Next see https://godoc.org/cloud.google.com/go/monitoring/apiv3#NotificationChannelIterator.Next
+	Next() (*monitoringpb.NotificationChannel, error)
+}
+
+// MetricClient matches the interface exposed by monitoring.MetricClient
+// see https://godoc.org/cloud.google.com/go/monitoring/apiv3#MetricClient
+type MetricClient interface {
+	// Close see https://godoc.org/cloud.google.com/go/monitoring/apiv3#MetricClient.Close
+	Close() error
+	// ListTimeSeries see https://godoc.org/cloud.google.com/go/monitoring/apiv3#MetricClient.ListTimeSeries
+	ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, opts ...gax.CallOption) TimeSeriesIterator
+}
+
+// TimeSeriesIterator matches the interface exposed by monitoring.TimeSeriesIterator
+// see https://godoc.org/cloud.google.com/go/monitoring/apiv3#TimeSeriesIterator
+type TimeSeriesIterator interface {
+	// Next see https://godoc.org/cloud.google.com/go/monitoring/apiv3#TimeSeriesIterator.Next
+	Next() (*monitoringpb.TimeSeries, error)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	monitoring "cloud.google.com/go/monitoring/apiv3"
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// MetricCreateFn is a factory function to create a Cloud Monitoring metric client.
+type MetricCreateFn func(ctx context.Context, opts ...option.ClientOption) (MetricClient, error)
+
+// NewMetricClient creates a new wrapped Cloud Monitoring metric client.
+func NewMetricClient(ctx context.Context, opts ...option.ClientOption) (MetricClient, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.Monitoring(), opts...)
+	client, err := monitoring.NewMetricClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &metricClient{
+		client: client,
+	}, nil
+}
+
+// metricClient wraps monitoring.MetricClient. Is the client that will be used everywhere except unit tests.
+type metricClient struct {
+	client *monitoring.MetricClient
+}
+
+// Verify that it satisfies the monitoring.MetricClient interface.
+var _ MetricClient = &metricClient{}
+
+// Close implements monitoring.MetricClient.Close
+func (c *metricClient) Close() error {
+	return c.client.Close()
+}
+
+// ListTimeSeries implements monitoring.MetricClient.ListTimeSeries
+func (c *metricClient) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, opts ...gax.CallOption) TimeSeriesIterator {
+	return c.client.ListTimeSeries(ctx, req, opts...)
+}
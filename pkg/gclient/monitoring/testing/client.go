@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+)
+
+// TestClientCreator returns a monitoring.CreateFn used to construct the test Cloud Monitoring client.
+func TestClientCreator(value interface{}) monitoring.CreateFn {
+	var data TestClientData
+	var ok bool
+	if data, ok = value.(TestClientData); !ok {
+		data = TestClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (monitoring.Client, error) {
+		return &testClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestClientData is the data used to configure the test Cloud Monitoring client.
+type TestClientData struct {
+	CreateClientErr              error
+	CreateNotificationChannelErr error
+	DeleteNotificationChannelErr error
+	GetNotificationChannelErr    error
+	CloseErr                     error
+	// NotificationChannels are the channels returned by ListNotificationChannels.
+	NotificationChannels        []*monitoringpb.NotificationChannel
+	ListNotificationChannelsErr error
+	// CreatedNotificationChannel is the name of the channel created by CreateNotificationChannel.
+	CreatedNotificationChannel string
+}
+
+// testClient is the test Cloud Monitoring client.
+type testClient struct {
+	data TestClientData
+}
+
+// Verify that it satisfies the monitoring.Client interface.
+var _ monitoring.Client = &testClient{}
+
+// Close implements client.Close
+func (c *testClient) Close() error {
+	return c.data.CloseErr
+}
+
+// CreateNotificationChannel implements client.CreateNotificationChannel
+func (c *testClient) CreateNotificationChannel(ctx context.Context, req *monitoringpb.CreateNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.CreateNotificationChannelErr != nil {
+		return nil, c.data.CreateNotificationChannelErr
+	}
+	return &monitoringpb.NotificationChannel{
+		Name:        c.data.CreatedNotificationChannel,
+		Type:        req.NotificationChannel.Type,
+		DisplayName: req.NotificationChannel.DisplayName,
+		Labels:      req.NotificationChannel.Labels,
+	}, nil
+}
+
+// DeleteNotificationChannel implements client.DeleteNotificationChannel
+func (c *testClient) DeleteNotificationChannel(ctx context.Context, req *monitoringpb.DeleteNotificationChannelRequest, opts ...gax.CallOption) error {
+	return c.data.DeleteNotificationChannelErr
+}
+
+// GetNotificationChannel implements client.GetNotificationChannel
+func (c *testClient) GetNotificationChannel(ctx context.Context, req *monitoringpb.GetNotificationChannelRequest, opts ...gax.CallOption) (*monitoringpb.NotificationChannel, error) {
+	if c.data.GetNotificationChannelErr != nil {
+		return nil, c.data.GetNotificationChannelErr
+	}
+	return &monitoringpb.NotificationChannel{
+		Name: req.Name,
+	}, nil
+}
+
+// ListNotificationChannels implements client.ListNotificationChannels
+func (c *testClient) ListNotificationChannels(ctx context.Context, req *monitoringpb.ListNotificationChannelsRequest, opts ...gax.CallOption) monitoring.NotificationChannelIterator {
+	return &testIterator{channels: c.data.NotificationChannels, err: c.data.ListNotificationChannelsErr}
+}
+
+// testIterator iterates over the channels of the test client, or fails with err.
+type testIterator struct {
+	channels []*monitoringpb.NotificationChannel
+	err      error
+}
+
+// Next implements monitoring.NotificationChannelIterator.Next
+func (it *testIterator) Next() (*monitoringpb.NotificationChannel, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if len(it.channels) == 0 {
+		return nil, iterator.Done
+	}
+	c := it.channels[0]
+	it.channels = it.channels[1:]
+	return c, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+
+	"github.com/google/knative-gcp/pkg/gclient/monitoring"
+)
+
+// TestMetricClientCreator returns a monitoring.MetricCreateFn used to construct the test Cloud Monitoring metric client.
+func TestMetricClientCreator(value interface{}) monitoring.MetricCreateFn {
+	var data TestMetricClientData
+	var ok bool
+	if data, ok = value.(TestMetricClientData); !ok {
+		data = TestMetricClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(_ context.Context, _ ...option.ClientOption) (monitoring.MetricClient, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(_ context.Context, _ ...option.ClientOption) (monitoring.MetricClient, error) {
+		return &testMetricClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestMetricClientData is the data used to configure the test Cloud Monitoring metric client.
+type TestMetricClientData struct {
+	CreateClientErr error
+	CloseErr        error
+	// TimeSeries are the time series returned by ListTimeSeries.
+	TimeSeries        []*monitoringpb.TimeSeries
+	ListTimeSeriesErr error
+	// Requests records the requests passed to ListTimeSeries.
+	Requests *[]*monitoringpb.ListTimeSeriesRequest
+}
+
+// testMetricClient is the test Cloud Monitoring metric client.
+type testMetricClient struct {
+	data TestMetricClientData
+}
+
+// Verify that it satisfies the monitoring.MetricClient interface.
+var _ monitoring.MetricClient = &testMetricClient{}
+
+// Close implements client.Close
+func (c *testMetricClient) Close() error {
+	return c.data.CloseErr
+}
+
+// ListTimeSeries implements client.ListTimeSeries
+func (c *testMetricClient) ListTimeSeries(ctx context.Context, req *monitoringpb.ListTimeSeriesRequest, opts ...gax.CallOption) monitoring.TimeSeriesIterator {
+	if c.data.Requests != nil {
+		*c.data.Requests = append(*c.data.Requests, req)
+	}
+	return &testTimeSeriesIterator{series: c.data.TimeSeries, err: c.data.ListTimeSeriesErr}
+}
+
+// testTimeSeriesIterator iterates over the time series of the test client, or fails with err.
+type testTimeSeriesIterator struct {
+	series []*monitoringpb.TimeSeries
+	err    error
+}
+
+// Next implements monitoring.TimeSeriesIterator.Next
+func (it *testTimeSeriesIterator) Next() (*monitoringpb.TimeSeries, error) {
+	if it.err != nil {
+		return nil, it.err
+	}
+	if len(it.series) == 0 {
+		return nil, iterator.Done
+	}
+	s := it.series[0]
+	it.series = it.series[1:]
+	return s, nil
+}
@@ -21,6 +21,8 @@ import (
 
 	"cloud.google.com/go/pubsub"
 	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 // CreateFn is a factory function to create a Pub/Sub client.
@@ -28,6 +30,8 @@ type CreateFn func(ctx context.Context, projectID string, opts ...option.ClientO
 
 // NewClient creates a new wrapped Pub/Sub client.
 func NewClient(ctx context.Context, projectID string, opts ...option.ClientOption) (Client, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.PubSub(ctx), opts...)
 	client, err := pubsub.NewClient(ctx, projectID, opts...)
 	if err != nil {
 		return nil, err
@@ -62,11 +66,14 @@ func (c *pubsubClient) CreateSubscription(ctx context.Context, id string, cfg Su
 		topic = t.topic
 	}
 	pscfg := pubsub.SubscriptionConfig{
-		Topic:               topic,
-		AckDeadline:         cfg.AckDeadline,
-		RetainAckedMessages: cfg.RetainAckedMessages,
-		RetentionDuration:   cfg.RetentionDuration,
-		Labels:              cfg.Labels,
+		Topic:                 topic,
+		AckDeadline:           cfg.AckDeadline,
+		RetainAckedMessages:   cfg.RetainAckedMessages,
+		RetentionDuration:     cfg.RetentionDuration,
+		Labels:                cfg.Labels,
+		EnableMessageOrdering: cfg.EnableMessageOrdering,
+		RetryPolicy:           cfg.RetryPolicy.toPubsub(),
+		DeadLetterPolicy:      cfg.DeadLetterPolicy,
 	}
 	sub, err := c.client.CreateSubscription(ctx, id, pscfg)
 	if err != nil {
@@ -80,6 +87,11 @@ func (c *pubsubClient) Topic(id string) Topic {
 	return &pubsubTopic{topic: c.client.Topic(id)}
 }
 
+// TopicInProject implements pubsub.Client.TopicInProject
+func (c *pubsubClient) TopicInProject(id, projectID string) Topic {
+	return &pubsubTopic{topic: c.client.TopicInProject(id, projectID)}
+}
+
 // CreateTopic implements pubsub.Client.CreateTopic
 func (c *pubsubClient) CreateTopic(ctx context.Context, id string) (Topic, error) {
 	topic, err := c.client.CreateTopic(ctx, id)
@@ -30,6 +30,8 @@ type Client interface {
 	Close() error
 	// Topic see https://godoc.org/cloud.google.com/go/pubsub#Client.Topic
 	Topic(id string) Topic
+	// TopicInProject see https://godoc.org/cloud.google.com/go/pubsub#Client.TopicInProject
+	TopicInProject(id, projectID string) Topic
 	// Subscription see https://godoc.org/cloud.google.com/go/pubsub#Client.Subscription
 	Subscription(id string) Subscription
 	// CreateSubscription see https://godoc.org/cloud.google.com/go/pubsub#Client.CreateSubscription
@@ -51,6 +53,8 @@ type Subscription interface {
 	Update(ctx context.Context, cfg SubscriptionConfig) (SubscriptionConfig, error)
 	// Delete see https://godoc.org/cloud.google.com/go/pubsub#Subscription.Delete
 	Delete(ctx context.Context) error
+	// IAM see https://godoc.org/cloud.google.com/go/pubsub#Subscription.IAM
+	IAM() iam.Handle
 	// ID see https://godoc.org/cloud.google.com/go/pubsub#Subscription.ID
 	ID() string
 }
@@ -60,6 +64,10 @@ type Subscription interface {
 type Topic interface {
 	// Exists see https://godoc.org/cloud.google.com/go/pubsub#Topic.Exists
 	Exists(ctx context.Context) (bool, error)
+	// Config see https://godoc.org/cloud.google.com/go/pubsub#Topic.Config
+	Config(ctx context.Context) (pubsub.TopicConfig, error)
+	// Update see https://godoc.org/cloud.google.com/go/pubsub#Topic.Update
+	Update(ctx context.Context, cfg pubsub.TopicConfigToUpdate) (pubsub.TopicConfig, error)
 	// Delete see https://godoc.org/cloud.google.com/go/pubsub#Topic.Delete
 	Delete(ctx context.Context) error
 	// IAM see https://godoc.org/cloud.google.com/go/pubsub#Topic.IAM
@@ -18,9 +18,12 @@ package pubsub
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"cloud.google.com/go/pubsub"
+
+	"github.com/google/knative-gcp/pkg/gclient/iam"
 )
 
 // SubscriptionConfig re-implements pubsub.SubscriptionConfig to allow us to
@@ -31,6 +34,58 @@ type SubscriptionConfig struct {
 	RetainAckedMessages bool
 	RetentionDuration   time.Duration
 	Labels              map[string]string
+	// EnableMessageOrdering can only be set when the subscription is
+	// created.
+	EnableMessageOrdering bool
+	// RetryPolicy is nil if Pub/Sub redelivers nacked messages immediately.
+	// In updates, a nil retry policy is left unchanged and the zero one
+	// removes it.
+	RetryPolicy *RetryPolicy
+	// DeadLetterPolicy is nil if messages are never dead lettered. In
+	// updates, a nil dead letter policy is left unchanged and the zero one
+	// removes it.
+	DeadLetterPolicy *pubsub.DeadLetterPolicy
+}
+
+// RetryPolicy is the exponential backoff Pub/Sub applies between
+// redeliveries of a nacked message. It re-implements pubsub.RetryPolicy with
+// plain durations.
+type RetryPolicy struct {
+	MinimumBackoff time.Duration
+	MaximumBackoff time.Duration
+}
+
+func (rp *RetryPolicy) String() string {
+	if rp == nil {
+		return "none"
+	}
+	return fmt.Sprintf("minimumBackoff=%v maximumBackoff=%v", rp.MinimumBackoff, rp.MaximumBackoff)
+}
+
+// toPubsub converts the retry policy to a pubsub.RetryPolicy. The zero retry
+// policy becomes the zero pubsub.RetryPolicy, which removes the retry policy
+// in updates.
+func (rp *RetryPolicy) toPubsub() *pubsub.RetryPolicy {
+	if rp == nil {
+		return nil
+	}
+	if *rp == (RetryPolicy{}) {
+		return &pubsub.RetryPolicy{}
+	}
+	return &pubsub.RetryPolicy{
+		MinimumBackoff: rp.MinimumBackoff,
+		MaximumBackoff: rp.MaximumBackoff,
+	}
+}
+
+// retryPolicyFromPubsub converts a pubsub.RetryPolicy read from Pub/Sub.
+func retryPolicyFromPubsub(rp *pubsub.RetryPolicy) *RetryPolicy {
+	if rp == nil {
+		return nil
+	}
+	minBackoff, _ := rp.MinimumBackoff.(time.Duration)
+	maxBackoff, _ := rp.MaximumBackoff.(time.Duration)
+	return &RetryPolicy{MinimumBackoff: minBackoff, MaximumBackoff: maxBackoff}
 }
 
 // pubsubSubscription wraps pubsub.Subscription. Is the subscription that will be used everywhere except unit tests.
@@ -53,11 +108,14 @@ func (s *pubsubSubscription) Config(ctx context.Context) (SubscriptionConfig, er
 		return SubscriptionConfig{}, err
 	}
 	return SubscriptionConfig{
-		Topic:               &pubsubTopic{topic: cfg.Topic},
-		AckDeadline:         cfg.AckDeadline,
-		RetainAckedMessages: cfg.RetainAckedMessages,
-		RetentionDuration:   cfg.RetentionDuration,
-		Labels:              cfg.Labels,
+		Topic:                 &pubsubTopic{topic: cfg.Topic},
+		AckDeadline:           cfg.AckDeadline,
+		RetainAckedMessages:   cfg.RetainAckedMessages,
+		RetentionDuration:     cfg.RetentionDuration,
+		Labels:                cfg.Labels,
+		RetryPolicy:           retryPolicyFromPubsub(cfg.RetryPolicy),
+		DeadLetterPolicy:      cfg.DeadLetterPolicy,
+		EnableMessageOrdering: cfg.EnableMessageOrdering,
 	}, nil
 }
 
@@ -68,17 +126,22 @@ func (s *pubsubSubscription) Update(ctx context.Context, cfg SubscriptionConfig)
 		RetainAckedMessages: cfg.RetainAckedMessages,
 		RetentionDuration:   cfg.RetentionDuration,
 		AckDeadline:         cfg.AckDeadline,
+		RetryPolicy:         cfg.RetryPolicy.toPubsub(),
+		DeadLetterPolicy:    cfg.DeadLetterPolicy,
 	}
 	updatedConfig, err := s.sub.Update(ctx, config)
 	if err != nil {
 		return SubscriptionConfig{}, err
 	}
 	return SubscriptionConfig{
-		Topic:               &pubsubTopic{topic: updatedConfig.Topic},
-		AckDeadline:         updatedConfig.AckDeadline,
-		RetainAckedMessages: updatedConfig.RetainAckedMessages,
-		RetentionDuration:   updatedConfig.RetentionDuration,
-		Labels:              updatedConfig.Labels,
+		Topic:                 &pubsubTopic{topic: updatedConfig.Topic},
+		AckDeadline:           updatedConfig.AckDeadline,
+		RetainAckedMessages:   updatedConfig.RetainAckedMessages,
+		RetentionDuration:     updatedConfig.RetentionDuration,
+		Labels:                updatedConfig.Labels,
+		RetryPolicy:           retryPolicyFromPubsub(updatedConfig.RetryPolicy),
+		DeadLetterPolicy:      updatedConfig.DeadLetterPolicy,
+		EnableMessageOrdering: updatedConfig.EnableMessageOrdering,
 	}, err
 }
 
@@ -87,6 +150,11 @@ func (s *pubsubSubscription) Delete(ctx context.Context) error {
 	return s.sub.Delete(ctx)
 }
 
+// IAM implements pubsub.Subscription.IAM
+func (s *pubsubSubscription) IAM() iam.Handle {
+	return iam.NewIamHandle(s.sub.IAM())
+}
+
 // ID implements pubsub.Subscription.ID
 func (s *pubsubSubscription) ID() string {
 	return s.sub.ID()
@@ -18,6 +18,7 @@ package testing
 
 import (
 	"context"
+	"fmt"
 
 	"cloud.google.com/go/pubsub"
 	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
@@ -74,14 +75,19 @@ func (c *testClient) Topic(id string) gpubsub.Topic {
 	return &testTopic{data: c.data.TopicData, handleData: c.data.HandleData, id: id}
 }
 
+// TopicInProject implements Client.TopicInProject.
+func (c *testClient) TopicInProject(id, projectID string) gpubsub.Topic {
+	return &testTopic{data: c.data.TopicData, handleData: c.data.HandleData, id: id, topicString: fmt.Sprintf("projects/%s/topics/%s", projectID, id)}
+}
+
 // Subscription implements Client.Subscription.
 func (c *testClient) Subscription(id string) gpubsub.Subscription {
-	return &testSubscription{data: c.data.SubscriptionData, id: id}
+	return &testSubscription{data: c.data.SubscriptionData, handleData: c.data.HandleData, id: id}
 }
 
 // CreateSubscription implements Client.CreateSubscription.
 func (c *testClient) CreateSubscription(ctx context.Context, id string, cfg gpubsub.SubscriptionConfig) (gpubsub.Subscription, error) {
-	return &testSubscription{data: c.data.SubscriptionData, id: id}, c.data.CreateSubscriptionErr
+	return &testSubscription{data: c.data.SubscriptionData, handleData: c.data.HandleData, id: id}, c.data.CreateSubscriptionErr
 }
 
 // CreateTopic implements pubsub.Client.CreateTopic
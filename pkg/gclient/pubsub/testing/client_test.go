@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/iam"
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+
+	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+)
+
+func TestSubscriptionConfig(t *testing.T) {
+	ctx := context.Background()
+	want := gpubsub.SubscriptionConfig{
+		AckDeadline:       10 * time.Second,
+		RetentionDuration: time.Hour,
+	}
+	c, _ := TestClientCreator(TestClientData{
+		SubscriptionData: TestSubscriptionData{Config: want},
+	})(ctx, "test-project")
+
+	got, err := c.Subscription("test-sub").Config(ctx)
+	if err != nil {
+		t.Fatalf("Config() = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected config (-want, +got) = %v", diff)
+	}
+}
+
+func TestSubscriptionUpdateInjectedError(t *testing.T) {
+	ctx := context.Background()
+	injectedErr := errors.New("injected error")
+	c, _ := TestClientCreator(TestClientData{
+		SubscriptionData: TestSubscriptionData{UpdateErr: injectedErr},
+	})(ctx, "test-project")
+
+	if _, err := c.Subscription("test-sub").Update(ctx, gpubsub.SubscriptionConfig{}); err != injectedErr {
+		t.Errorf("expected injected update error %v, got %v", injectedErr, err)
+	}
+}
+
+func TestTopicUpdate(t *testing.T) {
+	ctx := context.Background()
+	c, _ := TestClientCreator(TestClientData{
+		TopicData: TestTopicData{
+			Config: pubsub.TopicConfig{Labels: map[string]string{"a": "b"}},
+		},
+	})(ctx, "test-project")
+
+	labels := map[string]string{"c": "d"}
+	got, err := c.Topic("test-topic").Update(ctx, pubsub.TopicConfigToUpdate{Labels: labels})
+	if err != nil {
+		t.Fatalf("Update() = %v", err)
+	}
+	if diff := cmp.Diff(labels, got.Labels); diff != "" {
+		t.Errorf("unexpected labels (-want, +got) = %v", diff)
+	}
+}
+
+func TestTopicCreatedConfig(t *testing.T) {
+	ctx := context.Background()
+	c, _ := TestClientCreator(TestClientData{})(ctx, "test-project")
+
+	want := pubsub.TopicConfig{Labels: map[string]string{"a": "b"}}
+	topic, err := c.CreateTopicWithConfig(ctx, "test-topic", &want)
+	if err != nil {
+		t.Fatalf("CreateTopicWithConfig() = %v", err)
+	}
+	got, err := topic.Config(ctx)
+	if err != nil {
+		t.Fatalf("Config() = %v", err)
+	}
+	if diff := cmp.Diff(want.Labels, got.Labels); diff != "" {
+		t.Errorf("unexpected labels (-want, +got) = %v", diff)
+	}
+}
+
+func TestSubscriptionIAM(t *testing.T) {
+	ctx := context.Background()
+	injectedErr := errors.New("injected error")
+	c, _ := TestClientCreator(TestClientData{
+		HandleData: testiam.TestHandleData{SetPolicyErr: injectedErr},
+	})(ctx, "test-project")
+
+	h := c.Subscription("test-sub").IAM()
+	if err := h.SetPolicy(ctx, &iam.Policy{}); err != injectedErr {
+		t.Errorf("expected injected set policy error %v, got %v", injectedErr, err)
+	}
+}
+
+func TestSubscriptionIAMSetPolicy(t *testing.T) {
+	ctx := context.Background()
+	c, _ := TestClientCreator(TestClientData{})(ctx, "test-project")
+
+	h := c.Subscription("test-sub").IAM()
+	policy := &iam.Policy{}
+	policy.Add("user:test@example.com", iam.Viewer)
+	if err := h.SetPolicy(ctx, policy); err != nil {
+		t.Fatalf("SetPolicy() = %v", err)
+	}
+	got, err := h.Policy(ctx)
+	if err != nil {
+		t.Fatalf("Policy() = %v", err)
+	}
+	if !got.HasRole("user:test@example.com", iam.Viewer) {
+		t.Errorf("expected policy to grant %q, got %v", iam.Viewer, got.Roles())
+	}
+}
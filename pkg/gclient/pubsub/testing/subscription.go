@@ -19,19 +19,23 @@ package testing
 import (
 	"context"
 
+	"github.com/google/knative-gcp/pkg/gclient/iam"
+	testiam "github.com/google/knative-gcp/pkg/gclient/iam/testing"
 	"github.com/google/knative-gcp/pkg/gclient/pubsub"
 )
 
 // testSubscription is a test Pub/Sub subscription.
 type testSubscription struct {
-	data TestSubscriptionData
-	id   string
+	data       TestSubscriptionData
+	handleData testiam.TestHandleData
+	id         string
 }
 
 // TestSubscriptionData is the data used to configure the test Subscription.
 type TestSubscriptionData struct {
 	ExistsErr error
 	Exists    bool
+	Config    pubsub.SubscriptionConfig
 	ConfigErr error
 	UpdateErr error
 	DeleteErr error
@@ -47,7 +51,7 @@ func (s *testSubscription) Exists(ctx context.Context) (bool, error) {
 
 // Config implements Subscription.Config.
 func (s *testSubscription) Config(ctx context.Context) (pubsub.SubscriptionConfig, error) {
-	return pubsub.SubscriptionConfig{}, s.data.ConfigErr
+	return s.data.Config, s.data.ConfigErr
 }
 
 // Update implements Subscription.Update.
@@ -60,6 +64,11 @@ func (s *testSubscription) Delete(ctx context.Context) error {
 	return s.data.DeleteErr
 }
 
+// IAM implements Subscription.IAM.
+func (s *testSubscription) IAM() iam.Handle {
+	return testiam.NewTestHandle(s.handleData)
+}
+
 func (s *testSubscription) ID() string {
 	return s.id
 }
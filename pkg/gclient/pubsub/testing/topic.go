@@ -38,6 +38,9 @@ type testTopic struct {
 type TestTopicData struct {
 	ExistsErr error
 	Exists    bool
+	Config    pubsub.TopicConfig
+	ConfigErr error
+	UpdateErr error
 	DeleteErr error
 }
 
@@ -49,6 +52,30 @@ func (t *testTopic) Exists(ctx context.Context) (bool, error) {
 	return t.data.Exists, t.data.ExistsErr
 }
 
+// Config implements Topic.Config. It returns the config the topic was created
+// with, if any, and the configured one otherwise.
+func (t *testTopic) Config(ctx context.Context) (pubsub.TopicConfig, error) {
+	if t.config != nil {
+		return *t.config, t.data.ConfigErr
+	}
+	return t.data.Config, t.data.ConfigErr
+}
+
+// Update implements Topic.Update.
+func (t *testTopic) Update(ctx context.Context, cfg pubsub.TopicConfigToUpdate) (pubsub.TopicConfig, error) {
+	if t.data.UpdateErr != nil {
+		return pubsub.TopicConfig{}, t.data.UpdateErr
+	}
+	config, _ := t.Config(ctx)
+	if cfg.Labels != nil {
+		config.Labels = cfg.Labels
+	}
+	if cfg.MessageStoragePolicy != nil {
+		config.MessageStoragePolicy = *cfg.MessageStoragePolicy
+	}
+	return config, nil
+}
+
 func (t *testTopic) Delete(ctx context.Context) error {
 	return t.data.DeleteErr
 }
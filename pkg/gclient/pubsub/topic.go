@@ -36,6 +36,16 @@ func (t *pubsubTopic) Exists(ctx context.Context) (bool, error) {
 	return t.topic.Exists(ctx)
 }
 
+// Config implements pubsub.Topic.Config
+func (t *pubsubTopic) Config(ctx context.Context) (pubsub.TopicConfig, error) {
+	return t.topic.Config(ctx)
+}
+
+// Update implements pubsub.Topic.Update
+func (t *pubsubTopic) Update(ctx context.Context, cfg pubsub.TopicConfigToUpdate) (pubsub.TopicConfig, error) {
+	return t.topic.Update(ctx, cfg)
+}
+
 // Delete implements pubsub.Topic.Delete
 func (t *pubsubTopic) Delete(ctx context.Context) error {
 	return t.topic.Delete(ctx)
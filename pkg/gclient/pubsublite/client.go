@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloud.google.com/go/pubsublite"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// CreateFn is a factory function to create a Pub/Sub Lite admin client for
+// the resources in a zone.
+type CreateFn func(ctx context.Context, zone string, opts ...option.ClientOption) (AdminClient, error)
+
+// Verify that pubsublite.AdminClient satisfies the AdminClient interface.
+var _ AdminClient = &pubsublite.AdminClient{}
+
+// NewAdminClient creates a new Pub/Sub Lite admin client for the resources in
+// zone. Pub/Sub Lite resources are zonal, but the admin API is served per
+// region.
+func NewAdminClient(ctx context.Context, zone string, opts ...option.ClientOption) (AdminClient, error) {
+	region, err := Region(zone)
+	if err != nil {
+		return nil, err
+	}
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.PubSubLite(), opts...)
+	return pubsublite.NewAdminClient(ctx, region, opts...)
+}
+
+// ValidateZone returns an error if zone is not a zone, e.g. us-central1-a.
+func ValidateZone(zone string) error {
+	if parts := strings.Split(zone, "-"); len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("invalid zone %q", zone)
+	}
+	return nil
+}
+
+// Region returns the region of zone, e.g. us-central1 for us-central1-a.
+func Region(zone string) (string, error) {
+	if err := ValidateZone(zone); err != nil {
+		return "", err
+	}
+	return zone[:strings.LastIndex(zone, "-")], nil
+}
+
+// TopicPath returns the path of the Pub/Sub Lite topic id.
+func TopicPath(projectID, zone, id string) string {
+	return fmt.Sprintf("projects/%s/locations/%s/topics/%s", projectID, zone, id)
+}
+
+// SubscriptionPath returns the path of the Pub/Sub Lite subscription id.
+func SubscriptionPath(projectID, zone, id string) string {
+	return fmt.Sprintf("projects/%s/locations/%s/subscriptions/%s", projectID, zone, id)
+}
+
+// IsNotFound returns whether err is returned for a missing resource.
+func IsNotFound(err error) bool {
+	return gstatus.Code(err) == codes.NotFound
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+func TestRegion(t *testing.T) {
+	for _, tc := range []struct {
+		zone    string
+		want    string
+		wantErr bool
+	}{
+		{zone: "us-central1-a", want: "us-central1"},
+		{zone: "europe-west1-b", want: "europe-west1"},
+		{zone: "us-central1", wantErr: true},
+		{zone: "us-central1-", wantErr: true},
+		{zone: "", wantErr: true},
+	} {
+		t.Run(tc.zone, func(t *testing.T) {
+			got, err := Region(tc.zone)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Region(%q) error = %v, wantErr %t", tc.zone, err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Region(%q) = %q, want %q", tc.zone, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPaths(t *testing.T) {
+	if got, want := TopicPath("p", "us-central1-a", "t"), "projects/p/locations/us-central1-a/topics/t"; got != want {
+		t.Errorf("TopicPath() = %q, want %q", got, want)
+	}
+	if got, want := SubscriptionPath("p", "us-central1-a", "s"), "projects/p/locations/us-central1-a/subscriptions/s"; got != want {
+		t.Errorf("SubscriptionPath() = %q, want %q", got, want)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	if !IsNotFound(gstatus.Error(codes.NotFound, "missing")) {
+		t.Error("IsNotFound(NotFound) = false, want true")
+	}
+	if IsNotFound(errors.New("other")) {
+		t.Error("IsNotFound(other) = true, want false")
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsublite"
+)
+
+// AdminClient matches the interface exposed by pubsublite.AdminClient
+// see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient
+type AdminClient interface {
+	// Close see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.Close
+	Close() error
+	// Topic see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.Topic
+	Topic(ctx context.Context, topic string) (*pubsublite.TopicConfig, error)
+	// CreateTopic see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.CreateTopic
+	CreateTopic(ctx context.Context, config pubsublite.TopicConfig) (*pubsublite.TopicConfig, error)
+	// DeleteTopic see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.DeleteTopic
+	DeleteTopic(ctx context.Context, topic string) error
+	// Subscription see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.Subscription
+	Subscription(ctx context.Context, subscription string) (*pubsublite.SubscriptionConfig, error)
+	// CreateSubscription see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.CreateSubscription
+	CreateSubscription(ctx context.Context, config pubsublite.SubscriptionConfig) (*pubsublite.SubscriptionConfig, error)
+	// DeleteSubscription see https://godoc.org/cloud.google.com/go/pubsublite#AdminClient.DeleteSubscription
+	DeleteSubscription(ctx context.Context, subscription string) error
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsublite/pscompat"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// Publisher publishes to a Pub/Sub Lite topic, see pscompat.PublisherClient.
+type Publisher interface {
+	// Publish see https://godoc.org/cloud.google.com/go/pubsublite/pscompat#PublisherClient.Publish
+	Publish(ctx context.Context, msg *pubsub.Message) *pubsub.PublishResult
+	// Stop see https://godoc.org/cloud.google.com/go/pubsublite/pscompat#PublisherClient.Stop
+	Stop()
+	// Error returns the error that stopped the publisher, if any.
+	Error() error
+}
+
+// PublisherFn creates the Publisher of a Pub/Sub Lite topic path.
+type PublisherFn func(topic string) (Publisher, error)
+
+// NewPublisher creates the Publisher of the Pub/Sub Lite topic path.
+func NewPublisher(topic string) (Publisher, error) {
+	// The publisher doesn't keep the context, it is only used to connect.
+	return pscompat.NewPublisherClient(context.Background(), topic, endpoints.PubSubLite()...)
+}
+
+// Publishers holds a Publisher per Pub/Sub Lite topic path.
+type Publishers struct {
+	newPublisher PublisherFn
+
+	mu         sync.Mutex
+	publishers map[string]Publisher
+}
+
+// NewPublishers returns Publishers creating them with newPublisher.
+func NewPublishers(newPublisher PublisherFn) *Publishers {
+	return &Publishers{
+		newPublisher: newPublisher,
+		publishers:   make(map[string]Publisher),
+	}
+}
+
+// Get returns the Publisher of the topic path. Pub/Sub Lite publishers stop
+// permanently on their first error, so a stopped one is replaced. Creating a
+// publisher connects to Pub/Sub Lite, so it is done without holding the lock,
+// and the publisher is dropped if another one was created in the meantime.
+func (p *Publishers) Get(topic string) (Publisher, error) {
+	if pub, ok := p.load(topic); ok {
+		return pub, nil
+	}
+	created, err := p.newPublisher(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the publisher of %q: %w", topic, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if pub, ok := p.publishers[topic]; ok {
+		if pub.Error() == nil {
+			created.Stop()
+			return pub, nil
+		}
+		pub.Stop()
+	}
+	p.publishers[topic] = created
+	return created, nil
+}
+
+// load returns the publisher of the topic, if it has one that hasn't stopped.
+func (p *Publishers) load(topic string) (Publisher, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pub, ok := p.publishers[topic]
+	return pub, ok && pub.Error() == nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+)
+
+const testTopic = "projects/test-project/locations/us-central1-a/topics/test-topic"
+
+// fakePublisher records whether it was stopped.
+type fakePublisher struct {
+	err     error
+	stopped bool
+}
+
+func (p *fakePublisher) Publish(context.Context, *pubsub.Message) *pubsub.PublishResult {
+	return nil
+}
+
+func (p *fakePublisher) Stop() {
+	p.stopped = true
+}
+
+func (p *fakePublisher) Error() error {
+	return p.err
+}
+
+func TestPublishersGet(t *testing.T) {
+	var created []*fakePublisher
+	publishers := NewPublishers(func(topic string) (Publisher, error) {
+		if topic != testTopic {
+			t.Errorf("Publisher topic got=%q, want=%q", topic, testTopic)
+		}
+		p := &fakePublisher{}
+		created = append(created, p)
+		return p, nil
+	})
+
+	first, err := publishers.Get(testTopic)
+	if err != nil {
+		t.Fatalf("Get got error: %v", err)
+	}
+	if got, err := publishers.Get(testTopic); err != nil || got != first {
+		t.Errorf("Get got=%v, %v, want the first publisher", got, err)
+	}
+	// A failed publisher is replaced and stopped.
+	created[0].err = errors.New("publisher failed")
+	second, err := publishers.Get(testTopic)
+	if err != nil {
+		t.Fatalf("Get got error: %v", err)
+	}
+	if second == first {
+		t.Error("Get returned the failed publisher")
+	}
+	if !created[0].stopped {
+		t.Error("Failed publisher was not stopped")
+	}
+	if len(created) != 2 {
+		t.Errorf("Publishers created got %d, want 2", len(created))
+	}
+}
+
+func TestPublishersGetConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	var created []*fakePublisher
+	publishers := NewPublishers(func(string) (Publisher, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		p := &fakePublisher{}
+		created = append(created, p)
+		return p, nil
+	})
+
+	const n = 10
+	got := make([]Publisher, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := publishers.Get(testTopic)
+			if err != nil {
+				t.Errorf("Get got error: %v", err)
+			}
+			got[i] = p
+		}(i)
+	}
+	wg.Wait()
+
+	// Every caller gets the same publisher and the extra ones are stopped.
+	for _, p := range got {
+		if p != got[0] {
+			t.Fatal("Get returned different publishers")
+		}
+	}
+	for _, p := range created {
+		if stopped := p != got[0]; p.stopped != stopped {
+			t.Errorf("Publisher stopped got=%t, want=%t", p.stopped, stopped)
+		}
+	}
+}
+
+func TestPublishersGetError(t *testing.T) {
+	wantErr := errors.New("connect failed")
+	publishers := NewPublishers(func(string) (Publisher, error) {
+		return nil, wantErr
+	})
+	if _, err := publishers.Get(testTopic); !errors.Is(err, wantErr) {
+		t.Errorf("Get got error=%v, want=%v", err, wantErr)
+	}
+}
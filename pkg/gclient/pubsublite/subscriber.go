@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsublite/pscompat"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+// redeliveryDelay is the time waited before a subscriber reconnects after a
+// message was nacked.
+const redeliveryDelay = time.Second
+
+// ErrNack stops a subscriber when a message is nacked.
+var ErrNack = errors.New("message nacked")
+
+// Subscriber pulls messages off a Pub/Sub Lite subscription, see
+// pscompat.SubscriberClient.
+type Subscriber interface {
+	// Receive see https://godoc.org/cloud.google.com/go/pubsublite/pscompat#SubscriberClient.Receive
+	Receive(ctx context.Context, f pscompat.MessageReceiverFunc) error
+}
+
+// SubscriberFn creates a Subscriber of the Pub/Sub Lite subscription path.
+type SubscriberFn func(ctx context.Context, path string, settings pscompat.ReceiveSettings) (Subscriber, error)
+
+// NewSubscriber creates a Subscriber of the Pub/Sub Lite subscription path.
+func NewSubscriber(ctx context.Context, path string, settings pscompat.ReceiveSettings) (Subscriber, error) {
+	return pscompat.NewSubscriberClientWithSettings(ctx, path, settings, endpoints.PubSubLite()...)
+}
+
+// Receive calls f with the messages of the Pub/Sub Lite subscription path
+// until ctx is done or an error occurs. Pub/Sub Lite only acknowledges the
+// messages of a partition up to its first unacknowledged one, so it can't
+// redeliver a single message. Instead a nack stops the subscriber, which then
+// reconnects and receives again every message after the last acknowledged one
+// of each partition. The NackHandler of settings is replaced to do so.
+func Receive(ctx context.Context, path string, settings pscompat.ReceiveSettings, newSubscriber SubscriberFn, f pscompat.MessageReceiverFunc) error {
+	settings.NackHandler = func(*pubsub.Message) error {
+		return ErrNack
+	}
+	for {
+		sub, err := newSubscriber(ctx, path, settings)
+		if err != nil {
+			return fmt.Errorf("failed to create the subscriber of %q: %w", path, err)
+		}
+		if err := sub.Receive(ctx, f); !errors.Is(err, ErrNack) {
+			return err
+		}
+		logging.FromContext(ctx).Debug("Reconnecting to redeliver nacked messages", zap.String("subscription", path))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(redeliveryDelay):
+		}
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsublite
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsublite/pscompat"
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSubscription = "projects/test-project/locations/us-central1-a/subscriptions/test-sub"
+
+// fakeSubscriber hands out one message per Receive and returns err.
+type fakeSubscriber struct {
+	msg *pubsub.Message
+	err error
+}
+
+func (s *fakeSubscriber) Receive(ctx context.Context, f pscompat.MessageReceiverFunc) error {
+	f(ctx, s.msg)
+	return s.err
+}
+
+func TestReceive(t *testing.T) {
+	var settings []pscompat.ReceiveSettings
+	subscribers := []*fakeSubscriber{
+		{msg: &pubsub.Message{ID: "1"}, err: ErrNack},
+		{msg: &pubsub.Message{ID: "1"}},
+	}
+	newSubscriber := func(_ context.Context, path string, s pscompat.ReceiveSettings) (Subscriber, error) {
+		if path != testSubscription {
+			t.Errorf("Subscriber path got=%q, want=%q", path, testSubscription)
+		}
+		settings = append(settings, s)
+		sub := subscribers[0]
+		subscribers = subscribers[1:]
+		return sub, nil
+	}
+
+	var received []string
+	err := Receive(context.Background(), testSubscription, pscompat.DefaultReceiveSettings, newSubscriber, func(_ context.Context, msg *pubsub.Message) {
+		received = append(received, msg.ID)
+	})
+	if err != nil {
+		t.Fatalf("Receive got error: %v", err)
+	}
+	// The subscriber reconnects to redeliver the nacked message.
+	if diff := cmp.Diff([]string{"1", "1"}, received); diff != "" {
+		t.Errorf("Received messages (-want,+got): %v", diff)
+	}
+	if len(settings) != 2 {
+		t.Fatalf("Subscribers got %d, want 2", len(settings))
+	}
+	if err := settings[0].NackHandler(&pubsub.Message{}); !errors.Is(err, ErrNack) {
+		t.Errorf("NackHandler got=%v, want=%v", err, ErrNack)
+	}
+}
+
+func TestReceiveError(t *testing.T) {
+	wantErr := errors.New("subscriber failed")
+	newSubscriber := func(context.Context, string, pscompat.ReceiveSettings) (Subscriber, error) {
+		return &fakeSubscriber{msg: &pubsub.Message{}, err: wantErr}, nil
+	}
+	err := Receive(context.Background(), testSubscription, pscompat.DefaultReceiveSettings, newSubscriber, func(context.Context, *pubsub.Message) {})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Receive got error=%v, want=%v", err, wantErr)
+	}
+}
+
+func TestReceiveSubscriberError(t *testing.T) {
+	wantErr := errors.New("connect failed")
+	newSubscriber := func(context.Context, string, pscompat.ReceiveSettings) (Subscriber, error) {
+		return nil, wantErr
+	}
+	err := Receive(context.Background(), testSubscription, pscompat.DefaultReceiveSettings, newSubscriber, func(context.Context, *pubsub.Message) {})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("Receive got error=%v, want=%v", err, wantErr)
+	}
+}
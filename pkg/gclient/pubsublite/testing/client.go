@@ -0,0 +1,127 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing contains a fake Pub/Sub Lite admin client.
+package testing
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsublite"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+// TestAdminClientCreator returns a pubsublite.CreateFn used to construct the
+// test Pub/Sub Lite admin client.
+func TestAdminClientCreator(value interface{}) gpubsublite.CreateFn {
+	var data TestAdminClientData
+	var ok bool
+	if data, ok = value.(TestAdminClientData); !ok {
+		data = TestAdminClientData{}
+	}
+	if data.CreateClientErr != nil {
+		return func(ctx context.Context, zone string, opts ...option.ClientOption) (gpubsublite.AdminClient, error) {
+			return nil, data.CreateClientErr
+		}
+	}
+
+	return func(ctx context.Context, zone string, opts ...option.ClientOption) (gpubsublite.AdminClient, error) {
+		return &testAdminClient{
+			data: data,
+		}, nil
+	}
+}
+
+// TestAdminClientData is the data used to configure the test Pub/Sub Lite
+// admin client. Missing topics and subscriptions are reported as NotFound
+// errors, like the Pub/Sub Lite API does.
+type TestAdminClientData struct {
+	CreateClientErr       error
+	CloseErr              error
+	TopicExists           bool
+	TopicErr              error
+	CreateTopicErr        error
+	DeleteTopicErr        error
+	SubscriptionExists    bool
+	SubscriptionErr       error
+	CreateSubscriptionErr error
+	DeleteSubscriptionErr error
+}
+
+// testAdminClient is a test Pub/Sub Lite admin client.
+type testAdminClient struct {
+	data TestAdminClientData
+}
+
+// Verify that it satisfies the pubsublite.AdminClient interface.
+var _ gpubsublite.AdminClient = &testAdminClient{}
+
+// Close implements AdminClient.Close.
+func (c *testAdminClient) Close() error {
+	return c.data.CloseErr
+}
+
+// Topic implements AdminClient.Topic.
+func (c *testAdminClient) Topic(ctx context.Context, topic string) (*pubsublite.TopicConfig, error) {
+	if c.data.TopicErr != nil {
+		return nil, c.data.TopicErr
+	}
+	if !c.data.TopicExists {
+		return nil, gstatus.Errorf(codes.NotFound, "topic %s not found", topic)
+	}
+	return &pubsublite.TopicConfig{Name: topic}, nil
+}
+
+// CreateTopic implements AdminClient.CreateTopic.
+func (c *testAdminClient) CreateTopic(ctx context.Context, config pubsublite.TopicConfig) (*pubsublite.TopicConfig, error) {
+	if c.data.CreateTopicErr != nil {
+		return nil, c.data.CreateTopicErr
+	}
+	return &config, nil
+}
+
+// DeleteTopic implements AdminClient.DeleteTopic.
+func (c *testAdminClient) DeleteTopic(ctx context.Context, topic string) error {
+	return c.data.DeleteTopicErr
+}
+
+// Subscription implements AdminClient.Subscription.
+func (c *testAdminClient) Subscription(ctx context.Context, subscription string) (*pubsublite.SubscriptionConfig, error) {
+	if c.data.SubscriptionErr != nil {
+		return nil, c.data.SubscriptionErr
+	}
+	if !c.data.SubscriptionExists {
+		return nil, gstatus.Errorf(codes.NotFound, "subscription %s not found", subscription)
+	}
+	return &pubsublite.SubscriptionConfig{Name: subscription}, nil
+}
+
+// CreateSubscription implements AdminClient.CreateSubscription.
+func (c *testAdminClient) CreateSubscription(ctx context.Context, config pubsublite.SubscriptionConfig) (*pubsublite.SubscriptionConfig, error) {
+	if c.data.CreateSubscriptionErr != nil {
+		return nil, c.data.CreateSubscriptionErr
+	}
+	return &config, nil
+}
+
+// DeleteSubscription implements AdminClient.DeleteSubscription.
+func (c *testAdminClient) DeleteSubscription(ctx context.Context, subscription string) error {
+	return c.data.DeleteSubscriptionErr
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/googleapis/gax-go/v2"
 	"google.golang.org/api/option"
 	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 // CreateFn is a factory function to create a Scheduler client.
@@ -30,6 +32,8 @@ type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, er
 
 // NewClient creates a new wrapped Scheduler client.
 func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.Scheduler(), opts...)
 	client, err := scheduler.NewCloudSchedulerClient(ctx, opts...)
 	if err != nil {
 		return nil, err
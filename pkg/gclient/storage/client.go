@@ -20,7 +20,10 @@ import (
 	"context"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 // CreateFn is a factory function to create a Storage client.
@@ -28,6 +31,8 @@ type CreateFn func(ctx context.Context, opts ...option.ClientOption) (Client, er
 
 // NewClient creates a new wrapped Storage client.
 func NewClient(ctx context.Context, opts ...option.ClientOption) (Client, error) {
+	// Explicit options come last so that they take precedence over the overrides.
+	opts = append(endpoints.Storage(), opts...)
 	client, err := storage.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, err
@@ -54,3 +59,19 @@ func (c *storageClient) Close() error {
 func (c *storageClient) Bucket(name string) Bucket {
 	return &storageBucket{handle: c.client.Bucket(name)}
 }
+
+// Buckets implements client.Buckets
+func (c *storageClient) Buckets(ctx context.Context, projectID string) ([]*storage.BucketAttrs, error) {
+	var buckets []*storage.BucketAttrs
+	it := c.client.Buckets(ctx, projectID)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			return buckets, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, attrs)
+	}
+}
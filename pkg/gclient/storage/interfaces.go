@@ -29,6 +29,8 @@ type Client interface {
 	Close() error
 	// Bucket see https://godoc.org/cloud.google.com/go/storage#Client.Bucket
 	Bucket(name string) Bucket
+	// Buckets lists all the buckets of the project, see https://godoc.org/cloud.google.com/go/storage#Client.Buckets
+	Buckets(ctx context.Context, projectID string) ([]*storage.BucketAttrs, error)
 }
 
 // Bucket matches the interface exposed by storage.BucketHandle
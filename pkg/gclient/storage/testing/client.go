@@ -19,6 +19,7 @@ package testing
 import (
 	"context"
 
+	gstorage "cloud.google.com/go/storage"
 	"github.com/google/knative-gcp/pkg/gclient/storage"
 	"google.golang.org/api/option"
 )
@@ -50,6 +51,10 @@ type TestClientData struct {
 	CreateTopicErr        error
 	CloseErr              error
 	BucketData            TestBucketData
+	// BucketsData overrides BucketData for the buckets it has an entry for.
+	BucketsData map[string]TestBucketData
+	Buckets     []*gstorage.BucketAttrs
+	BucketsErr  error
 }
 
 // testClient is a test Storage client.
@@ -67,5 +72,13 @@ func (c *testClient) Close() error {
 
 // Bucket implements client.Bucket
 func (c *testClient) Bucket(name string) storage.Bucket {
+	if data, ok := c.data.BucketsData[name]; ok {
+		return &testBucket{data: data}
+	}
 	return &testBucket{data: c.data.BucketData}
 }
+
+// Buckets implements client.Buckets
+func (c *testClient) Buckets(ctx context.Context, projectID string) ([]*gstorage.BucketAttrs, error) {
+	return c.data.Buckets, c.data.BucketsErr
+}
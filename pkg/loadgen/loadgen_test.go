@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendAndVerify(t *testing.T) {
+	verifier := NewVerifier(nil)
+	sink := httptest.NewServer(verifier)
+	defer sink.Close()
+
+	// The target forwards every event to the sink but drops the fifth one,
+	// and delivers the tenth one twice.
+	var count int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&count, 1)
+		body, _ := ioutil.ReadAll(req.Body)
+		forward := func() {
+			fwd, _ := http.NewRequest(http.MethodPost, sink.URL, bytes.NewReader(body))
+			fwd.Header = req.Header.Clone()
+			if resp, err := http.DefaultClient.Do(fwd); err == nil {
+				resp.Body.Close()
+			}
+		}
+		switch n {
+		case 5:
+		case 10:
+			forward()
+			forward()
+		default:
+			forward()
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer target.Close()
+
+	s := &Sender{
+		Client:      http.DefaultClient,
+		Target:      target.URL,
+		Profile:     Profile{{From: 100, To: 100, Duration: 200 * time.Millisecond}},
+		Source:      "test",
+		Type:        EventType,
+		PayloadSize: 10,
+		MaxInFlight: 1,
+		Verifier:    verifier,
+	}
+	s.Run(context.Background())
+
+	r := verifier.Report(context.Background())
+	if r.Sent != 20 || r.Received != 19 || r.Lost != 1 || r.Duplicates != 1 || r.Unexpected != 0 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+	if r.LatencyMax <= 0 {
+		t.Errorf("LatencyMax = %v, want a positive latency", r.LatencyMax)
+	}
+	if got, want := r.LossRate(), 0.05; got != want {
+		t.Errorf("LossRate() = %v, want %v", got, want)
+	}
+}
+
+func TestSendFailed(t *testing.T) {
+	verifier := NewVerifier(nil)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer target.Close()
+
+	s := &Sender{
+		Client:      http.DefaultClient,
+		Target:      target.URL,
+		Profile:     Profile{{From: 100, To: 100, Duration: 50 * time.Millisecond}},
+		Source:      "test",
+		Type:        EventType,
+		MaxInFlight: 10,
+		Verifier:    verifier,
+	}
+	s.Run(context.Background())
+
+	if r := verifier.Report(context.Background()); r.Sent != 0 || r.Lost != 0 {
+		t.Errorf("unexpected report: %+v", r)
+	}
+}
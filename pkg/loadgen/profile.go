@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loadgen generates CloudEvent load against a Broker or a Topic and
+// verifies that the events are received back, reporting the end-to-end
+// latency and the events lost along the way. It is used to qualify the sizing
+// of BrokerCells with soak tests.
+package loadgen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Step is a step of a load Profile. The rate ramps linearly from From to To
+// events per second over Duration.
+type Step struct {
+	From     float64
+	To       float64
+	Duration time.Duration
+}
+
+// Profile is a sequence of load steps.
+type Profile []Step
+
+// ParseProfile parses a profile of comma-separated rate:duration steps, where
+// rate is either a constant number of events per second or a from-to ramp,
+// e.g. "10-100:1m,100:10m,100-0:1m".
+func ParseProfile(s string) (Profile, error) {
+	var p Profile
+	for _, step := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(step), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid step %q, expected rate:duration", step)
+		}
+		d, err := time.ParseDuration(parts[1])
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration of step %q", step)
+		}
+		rates := strings.SplitN(parts[0], "-", 2)
+		from, err := parseRate(rates[0])
+		if err != nil {
+			return nil, err
+		}
+		to := from
+		if len(rates) == 2 {
+			if to, err = parseRate(rates[1]); err != nil {
+				return nil, err
+			}
+		}
+		p = append(p, Step{From: from, To: to, Duration: d})
+	}
+	return p, nil
+}
+
+func parseRate(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil || r < 0 {
+		return 0, fmt.Errorf("invalid rate %q, expected a non-negative number of events per second", s)
+	}
+	return r, nil
+}
+
+// Duration returns the total duration of the profile.
+func (p Profile) Duration() time.Duration {
+	var d time.Duration
+	for _, s := range p {
+		d += s.Duration
+	}
+	return d
+}
+
+// Count returns the number of events the profile sends in its first elapsed
+// time.
+func (p Profile) Count(elapsed time.Duration) float64 {
+	var n float64
+	for _, s := range p {
+		if elapsed >= s.Duration {
+			n += (s.From + s.To) / 2 * s.Duration.Seconds()
+			elapsed -= s.Duration
+			continue
+		}
+		t := elapsed.Seconds()
+		n += s.From*t + (s.To-s.From)*t*t/(2*s.Duration.Seconds())
+		break
+	}
+	return n
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseProfile(t *testing.T) {
+	got, err := ParseProfile("10-100:1m, 100:10m,100-0:30s")
+	if err != nil {
+		t.Fatalf("ParseProfile() = %v", err)
+	}
+	want := Profile{
+		{From: 10, To: 100, Duration: time.Minute},
+		{From: 100, To: 100, Duration: 10 * time.Minute},
+		{From: 100, To: 0, Duration: 30 * time.Second},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected profile (-want, +got): %v", diff)
+	}
+	if got, want := got.Duration(), 11*time.Minute+30*time.Second; got != want {
+		t.Errorf("Duration() = %v, want %v", got, want)
+	}
+
+	for _, invalid := range []string{"", "100", "100:soon", "100:0s", "-1:1m", "a-b:1m"} {
+		if _, err := ParseProfile(invalid); err == nil {
+			t.Errorf("ParseProfile(%q) = nil, want an error", invalid)
+		}
+	}
+}
+
+func TestProfileCount(t *testing.T) {
+	p := Profile{
+		{From: 0, To: 10, Duration: 10 * time.Second},
+		{From: 10, To: 10, Duration: 10 * time.Second},
+	}
+	for elapsed, want := range map[time.Duration]float64{
+		0:                0,
+		5 * time.Second:  12.5,
+		10 * time.Second: 50,
+		15 * time.Second: 100,
+		time.Minute:      150,
+	} {
+		if got := p.Count(elapsed); got != want {
+			t.Errorf("Count(%v) = %v, want %v", elapsed, got, want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resources builds the Kubernetes resources of a load generator run.
+package resources
+
+import (
+	"strconv"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+const (
+	labelKey     = "events.cloud.google.com/loadgen"
+	verifierPort = 8080
+)
+
+// LoadgenArgs are the arguments needed to create a load generator run.
+// Exactly one of Broker and Topic must be set.
+type LoadgenArgs struct {
+	Namespace string
+	Name      string
+	Image     string
+	// ServiceAccountName is the Kubernetes service account of the load
+	// generator pod, and of the PullSubscription of a Topic.
+	ServiceAccountName string
+
+	// Target is the address events are sent to, i.e. the address of the
+	// Broker or of the Topic.
+	Target string
+	// Broker is the name of the Broker to send events to. The events are
+	// received back through a Trigger.
+	Broker string
+	// Topic is the Pub/Sub topic ID of the Topic to send events to. The
+	// events are received back through a PullSubscription.
+	Topic string
+	// Project is the project of the PullSubscription of a Topic.
+	Project string
+
+	// Profile is the load profile, see loadgen.ParseProfile.
+	Profile     string
+	PayloadSize int
+	MaxInFlight int
+}
+
+// Source returns the source of the events sent by the run.
+func Source(args *LoadgenArgs) string {
+	return "loadgen/" + args.Namespace + "/" + args.Name
+}
+
+func labels(args *LoadgenArgs) map[string]string {
+	return map[string]string{labelKey: args.Name}
+}
+
+// MakeJob creates the Job running the load generator and its verifier sink.
+func MakeJob(args *LoadgenArgs) *batchv1.Job {
+	backoffLimit := int32(0)
+	container := corev1.Container{
+		Name:  "loadgen",
+		Image: args.Image,
+		Args: []string{
+			"-target=" + args.Target,
+			"-profile=" + args.Profile,
+			"-source=" + Source(args),
+			"-payload-size=" + strconv.Itoa(args.PayloadSize),
+			"-max-in-flight=" + strconv.Itoa(args.MaxInFlight),
+			"-port=" + strconv.Itoa(verifierPort),
+		},
+		Ports: []corev1.ContainerPort{{
+			Name:          "http",
+			ContainerPort: verifierPort,
+		}},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: args.Namespace,
+			Name:      args.Name,
+			Labels:    labels(args),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels(args),
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: args.ServiceAccountName,
+					RestartPolicy:      corev1.RestartPolicyNever,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// MakeService creates the Service addressing the verifier sink.
+func MakeService(args *LoadgenArgs) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: args.Namespace,
+			Name:      args.Name,
+			Labels:    labels(args),
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels(args),
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(verifierPort),
+			}},
+		},
+	}
+}
+
+func sink(args *LoadgenArgs) duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Namespace:  args.Namespace,
+			Name:       args.Name,
+		},
+	}
+}
+
+// MakeTrigger creates the Trigger delivering the events sent to the Broker
+// back to the verifier sink.
+func MakeTrigger(args *LoadgenArgs) *brokerv1beta1.Trigger {
+	return &brokerv1beta1.Trigger{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "eventing.knative.dev/v1beta1",
+			Kind:       "Trigger",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: args.Namespace,
+			Name:      args.Name,
+			Labels:    labels(args),
+		},
+		Spec: eventingv1beta1.TriggerSpec{
+			Broker: args.Broker,
+			Filter: &eventingv1beta1.TriggerFilter{
+				Attributes: eventingv1beta1.TriggerFilterAttributes{
+					"source": Source(args),
+				},
+			},
+			Subscriber: sink(args),
+		},
+	}
+}
+
+// MakePullSubscription creates the PullSubscription delivering the events
+// sent to the Topic back to the verifier sink.
+func MakePullSubscription(args *LoadgenArgs) *inteventsv1beta1.PullSubscription {
+	return &inteventsv1beta1.PullSubscription{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "internal.events.cloud.google.com/v1beta1",
+			Kind:       "PullSubscription",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: args.Namespace,
+			Name:      args.Name,
+			Labels:    labels(args),
+		},
+		Spec: inteventsv1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				IdentitySpec: duckv1beta1.IdentitySpec{
+					ServiceAccountName: args.ServiceAccountName,
+				},
+				Project: args.Project,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: sink(args),
+				},
+			},
+			Topic: args.Topic,
+		},
+	}
+}
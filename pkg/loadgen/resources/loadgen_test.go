@@ -0,0 +1,87 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	rectesting "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+func args() *LoadgenArgs {
+	return &LoadgenArgs{
+		Namespace:   "soak",
+		Name:        "loadgen",
+		Image:       "loadgen-image",
+		Target:      "http://broker-ingress/soak/default",
+		Broker:      "default",
+		Profile:     "10-100:1m",
+		PayloadSize: 100,
+		MaxInFlight: 10,
+	}
+}
+
+func TestMakeJob(t *testing.T) {
+	job := MakeJob(args())
+	want := []string{
+		"-target=http://broker-ingress/soak/default",
+		"-profile=10-100:1m",
+		"-source=loadgen/soak/loadgen",
+		"-payload-size=100",
+		"-max-in-flight=10",
+		"-port=8080",
+	}
+	if diff := cmp.Diff(want, job.Spec.Template.Spec.Containers[0].Args); diff != "" {
+		t.Errorf("unexpected args (-want, +got): %v", diff)
+	}
+	if diff := cmp.Diff(MakeService(args()).Spec.Selector, job.Spec.Template.Labels); diff != "" {
+		t.Errorf("service selector doesn't match the pod labels (-selector, +labels): %v", diff)
+	}
+}
+
+func TestMakeJobGolden(t *testing.T) {
+	rectesting.AssertGolden(t, "loadgen_job", MakeJob(args()))
+}
+
+func TestMakeTrigger(t *testing.T) {
+	trigger := MakeTrigger(args())
+	if got, want := trigger.Spec.Filter.Attributes["source"], "loadgen/soak/loadgen"; got != want {
+		t.Errorf("source filter = %q, want %q", got, want)
+	}
+	wantSink := duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Namespace: "soak", Name: "loadgen"}}
+	if diff := cmp.Diff(wantSink, trigger.Spec.Subscriber); diff != "" {
+		t.Errorf("unexpected subscriber (-want, +got): %v", diff)
+	}
+}
+
+func TestMakePullSubscription(t *testing.T) {
+	a := args()
+	a.Broker = ""
+	a.Topic = "soak-topic"
+	a.Project = "my-project"
+	a.ServiceAccountName = "loadgen-ksa"
+	ps := MakePullSubscription(a)
+	if ps.Spec.Topic != "soak-topic" || ps.Spec.Project != "my-project" || ps.Spec.ServiceAccountName != "loadgen-ksa" {
+		t.Errorf("unexpected spec: %+v", ps.Spec)
+	}
+	if got := ps.Spec.Sink.Ref.Name; got != "loadgen" {
+		t.Errorf("sink name = %q, want %q", got, "loadgen")
+	}
+}
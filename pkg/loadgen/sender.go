@@ -0,0 +1,144 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// EventType is the default type of the events sent by the load generator.
+	EventType = "dev.knative.gcp.loadgen.event"
+
+	// tickInterval is the interval at which the sender catches up with its
+	// profile.
+	tickInterval = 10 * time.Millisecond
+)
+
+// Sender sends events to a target at the rate of a Profile.
+type Sender struct {
+	// Client is the HTTP client sending the events.
+	Client *http.Client
+	// Target is the address of the Broker or Topic to send events to.
+	Target string
+	// Profile is the rate at which events are sent.
+	Profile Profile
+	// Source is the source of the events. It identifies the run, and should
+	// be unique.
+	Source string
+	// Type is the type of the events.
+	Type string
+	// PayloadSize is the minimum size of the event data, in bytes.
+	PayloadSize int
+	// MaxInFlight bounds the number of events sent concurrently. Events are
+	// sent behind the profile when it is reached.
+	MaxInFlight int
+	// Verifier is notified of the events sent.
+	Verifier *Verifier
+	// Reporter reports the events sent. It may be nil.
+	Reporter *StatsReporter
+}
+
+// Run sends events until the profile ends or ctx is done.
+func (s *Sender) Run(ctx context.Context) {
+	inFlight := make(chan struct{}, s.MaxInFlight)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	padding := strings.Repeat("x", s.PayloadSize)
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	var sent int
+	for {
+		elapsed := time.Since(start)
+		done := elapsed >= s.Profile.Duration()
+		for due := int(s.Profile.Count(elapsed)); sent < due; sent++ {
+			select {
+			case <-ctx.Done():
+				return
+			case inFlight <- struct{}{}:
+			}
+			wg.Add(1)
+			go func(seq int) {
+				defer wg.Done()
+				defer func() { <-inFlight }()
+				s.send(ctx, fmt.Sprintf("%s-%d", s.Source, seq), padding)
+			}(sent)
+		}
+		if done {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sender) send(ctx context.Context, id, padding string) {
+	e := event.New()
+	e.SetID(id)
+	e.SetSource(s.Source)
+	e.SetType(s.Type)
+	now := time.Now()
+	e.SetTime(now)
+	if err := e.SetData(event.ApplicationJSON, payload{ID: id, Padding: padding}); err != nil {
+		logging.FromContext(ctx).Error("Failed to set the event data", zap.Error(err))
+		return
+	}
+
+	s.Verifier.sending(id, now)
+	if err := s.sendEvent(ctx, &e); err != nil {
+		s.Verifier.sendFailed(id)
+		s.Reporter.reportEvent(ctx, resultSendFailed)
+		logging.FromContext(ctx).Debug("Failed to send event", zap.String("id", id), zap.Error(err))
+		return
+	}
+	s.Reporter.reportEvent(ctx, resultSent)
+}
+
+func (s *Sender) sendEvent(ctx context.Context, e *event.Event) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Target, nil)
+	if err != nil {
+		return err
+	}
+	if err := cehttp.WriteRequest(ctx, binding.ToMessage(e), req); err != nil {
+		return err
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected HTTP status code %d", resp.StatusCode)
+	}
+	return nil
+}
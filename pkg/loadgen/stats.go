@@ -0,0 +1,115 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+	resultSent       = "sent"
+	resultSendFailed = "send_failed"
+	resultReceived   = "received"
+	resultDuplicate  = "duplicate"
+)
+
+var resultKey = tag.MustNewKey("result")
+
+// StatsReporter reports the metrics of a load run.
+type StatsReporter struct {
+	eventCountM *stats.Int64Measure
+	latencyM    *stats.Float64Measure
+	lostM       *stats.Int64Measure
+}
+
+// NewStatsReporter creates a new StatsReporter.
+func NewStatsReporter() (*StatsReporter, error) {
+	r := &StatsReporter{
+		eventCountM: stats.Int64(
+			"event_count",
+			"Number of events sent or received by the load generator",
+			stats.UnitDimensionless,
+		),
+		latencyM: stats.Float64(
+			"event_latencies",
+			"The time spent between sending an event and receiving it back",
+			stats.UnitMilliseconds,
+		),
+		lostM: stats.Int64(
+			"lost_event_count",
+			"Number of events sent but not received back by the end of the run",
+			stats.UnitDimensionless,
+		),
+	}
+	err := metrics.RegisterResourceView(
+		&view.View{
+			Name:        r.eventCountM.Name(),
+			Description: r.eventCountM.Description(),
+			Measure:     r.eventCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{resultKey},
+		},
+		&view.View{
+			Name:        r.latencyM.Name(),
+			Description: r.latencyM.Description(),
+			Measure:     r.latencyM,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...),
+		},
+		&view.View{
+			Name:        r.lostM.Name(),
+			Description: r.lostM.Description(),
+			Measure:     r.lostM,
+			Aggregation: view.LastValue(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register load generator stats: %w", err)
+	}
+	return r, nil
+}
+
+func (r *StatsReporter) reportEvent(ctx context.Context, result string) {
+	if r == nil {
+		return
+	}
+	ctx, err := tag.New(ctx, tag.Insert(resultKey, result))
+	if err != nil {
+		return
+	}
+	metrics.Record(ctx, r.eventCountM.M(1))
+}
+
+func (r *StatsReporter) reportLatency(ctx context.Context, latency time.Duration) {
+	if r == nil {
+		return
+	}
+	metrics.Record(ctx, r.latencyM.M(float64(latency)/float64(time.Millisecond)))
+}
+
+func (r *StatsReporter) reportLost(ctx context.Context, lost int) {
+	if r == nil {
+		return
+	}
+	metrics.Record(ctx, r.lostM.M(int64(lost)))
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadgen
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+// payload is the data of the events sent by the load generator. The event ID
+// is part of the data rather than only an attribute since events delivered
+// through a Topic and a PullSubscription get the ID of the Pub/Sub message.
+type payload struct {
+	ID      string `json:"loadgenId"`
+	Padding string `json:"padding,omitempty"`
+}
+
+type sentEvent struct {
+	sentAt   time.Time
+	received bool
+}
+
+// Verifier is the sink of the events sent by a Sender. It matches the events
+// it receives with the sent ones to compute their latency and the events that
+// are lost.
+type Verifier struct {
+	reporter *StatsReporter
+
+	mu         sync.Mutex
+	events     map[string]*sentEvent
+	received   int
+	duplicates int
+	unexpected int
+	latencies  []time.Duration
+}
+
+// NewVerifier creates a new Verifier. reporter may be nil.
+func NewVerifier(reporter *StatsReporter) *Verifier {
+	return &Verifier{
+		reporter: reporter,
+		events:   make(map[string]*sentEvent),
+	}
+}
+
+// sending records that the event id is about to be sent. It must be called
+// before sending the event, since it may be received before the send returns.
+func (v *Verifier) sending(id string, t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.events[id] = &sentEvent{sentAt: t}
+}
+
+// sendFailed records that sending the event id failed.
+func (v *Verifier) sendFailed(id string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.events, id)
+}
+
+// ServeHTTP receives an event.
+func (v *Verifier) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	event, err := binding.ToEvent(ctx, cehttp.NewMessageFromHttpRequest(req))
+	if err != nil {
+		logging.FromContext(ctx).Debug("Failed to read the received event", zap.Error(err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	var p payload
+	if err := json.Unmarshal(event.Data(), &p); err != nil || p.ID == "" {
+		p.ID = event.ID()
+	}
+	v.receive(ctx, p.ID, time.Now())
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (v *Verifier) receive(ctx context.Context, id string, t time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	e, ok := v.events[id]
+	switch {
+	case !ok:
+		v.unexpected++
+	case e.received:
+		v.duplicates++
+		v.reporter.reportEvent(ctx, resultDuplicate)
+	default:
+		e.received = true
+		v.received++
+		latency := t.Sub(e.sentAt)
+		v.latencies = append(v.latencies, latency)
+		v.reporter.reportEvent(ctx, resultReceived)
+		v.reporter.reportLatency(ctx, latency)
+	}
+}
+
+// Report is the result of a load run.
+type Report struct {
+	// Sent is the number of events accepted by the target.
+	Sent int `json:"sent"`
+	// Received is the number of sent events received back.
+	Received int `json:"received"`
+	// Lost is the number of sent events not received back.
+	Lost int `json:"lost"`
+	// Duplicates is the number of sent events received back more than once.
+	Duplicates int `json:"duplicates"`
+	// Unexpected is the number of received events that were not sent, e.g.
+	// because they were sent by an earlier run.
+	Unexpected int `json:"unexpected"`
+
+	LatencyP50 time.Duration `json:"latencyP50"`
+	LatencyP99 time.Duration `json:"latencyP99"`
+	LatencyMax time.Duration `json:"latencyMax"`
+}
+
+// LossRate returns the fraction of the sent events that were lost.
+func (r Report) LossRate() float64 {
+	if r.Sent == 0 {
+		return 0
+	}
+	return float64(r.Lost) / float64(r.Sent)
+}
+
+// Report returns the result of the run so far, and reports the events lost
+// until now.
+func (v *Verifier) Report(ctx context.Context) Report {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	r := Report{
+		Sent:       len(v.events),
+		Received:   v.received,
+		Lost:       len(v.events) - v.received,
+		Duplicates: v.duplicates,
+		Unexpected: v.unexpected,
+	}
+	if n := len(v.latencies); n > 0 {
+		sort.Slice(v.latencies, func(i, j int) bool { return v.latencies[i] < v.latencies[j] })
+		r.LatencyP50 = v.latencies[n*50/100]
+		r.LatencyP99 = v.latencies[n*99/100]
+		r.LatencyMax = v.latencies[n-1]
+	}
+	v.reporter.reportLost(ctx, r.Lost)
+	return r
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
+	"go.opencensus.io/metric/metricdata"
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
@@ -35,60 +36,126 @@ const (
 	startDeliveryProcessingTime DeliveryMetricsKey = iota
 )
 
+// DeliveryCountViewName is the name of the view counting the deliveries to
+// Trigger subscribers by response code.
+const DeliveryCountViewName = "event_count"
+
+// Values of the attempt_class tag of the delivery metrics.
+const (
+	// AttemptClassFirst is the first delivery of an event to a Trigger
+	// subscriber.
+	AttemptClassFirst = "first"
+	// AttemptClassRetry is any later delivery of an event to a Trigger
+	// subscriber.
+	AttemptClassRetry = "retry"
+)
+
 type DeliveryReporter struct {
 	podName               PodName
 	containerName         ContainerName
 	dispatchTimeInMsecM   *stats.Float64Measure
 	processingTimeInMsecM *stats.Float64Measure
+	duplicateCountM       *stats.Int64Measure
+	schemaValidationM     *stats.Int64Measure
+	expiredCountM         *stats.Int64Measure
 }
 
 func (r *DeliveryReporter) register() error {
+	labelKeys := metricLabelKeys()
 	return metrics.RegisterResourceView(
 		&view.View{
-			Name:        "event_count",
+			Name:        DeliveryCountViewName,
 			Description: "Number of events delivered to a Trigger subscriber",
 			Measure:     r.dispatchTimeInMsecM,
 			Aggregation: view.Count(),
-			TagKeys: []tag.Key{
+			TagKeys: append([]tag.Key{
 				NamespaceNameKey,
 				BrokerNameKey,
 				TriggerNameKey,
 				TriggerFilterTypeKey,
 				ResponseCodeKey,
 				ResponseCodeClassKey,
+				AttemptClassKey,
+				RetryCountKey,
 				PodNameKey,
 				ContainerNameKey,
-			},
+			}, labelKeys...),
 		},
 		&view.View{
 			Name:        r.dispatchTimeInMsecM.Name(),
 			Description: r.dispatchTimeInMsecM.Description(),
 			Measure:     r.dispatchTimeInMsecM,
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
-			TagKeys: []tag.Key{
+			TagKeys: append([]tag.Key{
 				NamespaceNameKey,
 				BrokerNameKey,
 				TriggerNameKey,
 				TriggerFilterTypeKey,
 				ResponseCodeKey,
 				ResponseCodeClassKey,
+				AttemptClassKey,
+				RetryCountKey,
 				PodNameKey,
 				ContainerNameKey,
-			},
+			}, labelKeys...),
 		},
 		&view.View{
 			Name:        r.processingTimeInMsecM.Name(),
 			Description: r.processingTimeInMsecM.Description(),
 			Measure:     r.processingTimeInMsecM,
 			Aggregation: view.Distribution(metrics.Buckets125(1, 10000)...), // 1, 2, 5, 10, 20, 50, 100, 1000, 5000, 10000
-			TagKeys: []tag.Key{
+			TagKeys: append([]tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				TriggerNameKey,
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			}, labelKeys...),
+		},
+		&view.View{
+			Name:        r.duplicateCountM.Name(),
+			Description: r.duplicateCountM.Description(),
+			Measure:     r.duplicateCountM,
+			Aggregation: view.Count(),
+			TagKeys: append([]tag.Key{
 				NamespaceNameKey,
 				BrokerNameKey,
 				TriggerNameKey,
 				TriggerFilterTypeKey,
 				PodNameKey,
 				ContainerNameKey,
-			},
+			}, labelKeys...),
+		},
+		&view.View{
+			Name:        r.schemaValidationM.Name(),
+			Description: r.schemaValidationM.Description(),
+			Measure:     r.schemaValidationM,
+			Aggregation: view.Count(),
+			TagKeys: append([]tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				TriggerNameKey,
+				TriggerFilterTypeKey,
+				EventTypeKey,
+				SchemaValidationKey,
+				PodNameKey,
+				ContainerNameKey,
+			}, labelKeys...),
+		},
+		&view.View{
+			Name:        r.expiredCountM.Name(),
+			Description: r.expiredCountM.Description(),
+			Measure:     r.expiredCountM,
+			Aggregation: view.Count(),
+			TagKeys: append([]tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				TriggerNameKey,
+				TriggerFilterTypeKey,
+				PodNameKey,
+				ContainerNameKey,
+			}, labelKeys...),
 		},
 	)
 }
@@ -112,6 +179,28 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 			"The time spent processing an event before it is dispatched to a Trigger subscriber",
 			stats.UnitMilliseconds,
 		),
+		// duplicateCountM records the events that were not delivered to a
+		// Trigger subscriber because they had been delivered before.
+		duplicateCountM: stats.Int64(
+			"event_duplicate_count",
+			"Number of duplicate events suppressed before delivery to a Trigger subscriber",
+			stats.UnitDimensionless,
+		),
+		// schemaValidationM records the events validated against the
+		// EventSchema of their type before delivery to a Trigger subscriber.
+		schemaValidationM: stats.Int64(
+			"event_schema_validation_count",
+			"Number of events validated against their EventSchema before delivery to a Trigger subscriber",
+			stats.UnitDimensionless,
+		),
+		// expiredCountM records the events that were not delivered to a
+		// Trigger subscriber because they were older than its max age. They
+		// are sent to the dead letter sink of the Trigger if it has one.
+		expiredCountM: stats.Int64(
+			"event_expired_count",
+			"Number of events not delivered to the subscriber of a Trigger for exceeding its max age",
+			stats.UnitDimensionless,
+		),
 	}
 
 	if err := r.register(); err != nil {
@@ -120,17 +209,44 @@ func NewDeliveryReporter(podName PodName, containerName ContainerName) (*Deliver
 	return r, nil
 }
 
-// ReportEventDispatchTime captures dispatch times.
-func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.Duration, responseCode int) {
+// ReportEventDispatchTime captures dispatch times. The attachments, e.g. the
+// span context and the response headers of a failed delivery, are recorded
+// as the exemplar of the dispatch time. They may be nil.
+func (r *DeliveryReporter) ReportEventDispatchTime(ctx context.Context, d time.Duration, responseCode int, attachments metricdata.Attachments) {
 	// convert time.Duration in nanoseconds to milliseconds.
 	metrics.Record(ctx, r.dispatchTimeInMsecM.M(float64(d/time.Millisecond)),
 		stats.WithTags(
 			tag.Insert(ResponseCodeKey, strconv.Itoa(responseCode)),
 			tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(responseCode)),
 		),
+		stats.WithAttachments(attachments),
 	)
 }
 
+// ReportDuplicateEvent counts an event that was dropped as a duplicate of an
+// already delivered event.
+func (r *DeliveryReporter) ReportDuplicateEvent(ctx context.Context) {
+	metrics.Record(ctx, r.duplicateCountM.M(1))
+}
+
+// ReportSchemaValidation counts an event of the type that was validated
+// against its EventSchema before delivery, with the result of the validation,
+// either "valid" or "invalid".
+func (r *DeliveryReporter) ReportSchemaValidation(ctx context.Context, eventType, result string) {
+	metrics.Record(ctx, r.schemaValidationM.M(1),
+		stats.WithTags(
+			tag.Insert(EventTypeKey, eventType),
+			tag.Insert(SchemaValidationKey, result),
+		),
+	)
+}
+
+// ReportExpiredEvent counts an event that was not delivered because it was
+// older than the max age of the Trigger.
+func (r *DeliveryReporter) ReportExpiredEvent(ctx context.Context) {
+	metrics.Record(ctx, r.expiredCountM.M(1))
+}
+
 // StartEventProcessing records the start of event processing for delivery within the given context.
 func StartEventProcessing(ctx context.Context) context.Context {
 	return context.WithValue(ctx, startDeliveryProcessingTime, time.Now())
@@ -170,14 +286,44 @@ func (r *DeliveryReporter) AddTags(ctx context.Context) (context.Context, error)
 }
 
 func AddTargetTags(ctx context.Context, target *config.Target) (context.Context, error) {
-	return tag.New(ctx,
+	mutators := append([]tag.Mutator{
 		tag.Insert(NamespaceNameKey, target.Namespace),
 		tag.Insert(BrokerNameKey, target.Broker),
 		tag.Insert(TriggerNameKey, target.Name),
 		tag.Insert(TriggerFilterTypeKey, filterTypeValue(target.FilterAttributes["type"])),
+	}, metricLabelMutators(target.MetricLabels)...)
+	return tag.New(ctx, mutators...)
+}
+
+// AddAttemptTags adds the attempt class and the retry count bucket of a
+// delivery to ctx. retries is how many times the event was delivered to the
+// Trigger subscriber before.
+func AddAttemptTags(ctx context.Context, retries int) (context.Context, error) {
+	attemptClass := AttemptClassFirst
+	if retries > 0 {
+		attemptClass = AttemptClassRetry
+	}
+	return tag.New(ctx,
+		tag.Insert(AttemptClassKey, attemptClass),
+		tag.Insert(RetryCountKey, retryCountBucket(retries)),
 	)
 }
 
+// retryCountBucket buckets retry counts to keep the cardinality of the
+// retry_count tag low.
+func retryCountBucket(retries int) string {
+	switch {
+	case retries <= 2:
+		return strconv.Itoa(retries)
+	case retries <= 4:
+		return "3-4"
+	case retries <= 9:
+		return "5-9"
+	default:
+		return "10+"
+	}
+}
+
 func getStartDeliveryProcessingTime(ctx context.Context) (time.Time, error) {
 	v := ctx.Value(startDeliveryProcessingTime)
 	if time, ok := v.(time.Time); ok {
@@ -21,6 +21,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"go.opencensus.io/metric/metricdata"
+	"go.opencensus.io/stats/view"
 	_ "knative.dev/pkg/metrics/testing"
 
 	"github.com/google/knative-gcp/pkg/broker/config"
@@ -64,17 +67,103 @@ func TestReportEventDispatchTime(t *testing.T) {
 		t.Fatal(err)
 	}
 	reportertest.ExpectMetrics(t, func() error {
-		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 202)
+		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 202, nil)
 		return nil
 	})
 	reportertest.ExpectMetrics(t, func() error {
-		r.ReportEventDispatchTime(ctx, 9100*time.Millisecond, 202)
+		r.ReportEventDispatchTime(ctx, 9100*time.Millisecond, 202, nil)
 		return nil
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportEventDispatchTimeWithAttemptTags(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelResponseCode:      "500",
+		metricskey.LabelResponseCodeClass: "5xx",
+		"attempt_class":                   "retry",
+		"retry_count":                     "3-4",
+		metricskey.PodName:                "testpod",
+		metricskey.ContainerName:          "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddAttemptTags(ctx, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 500, nil)
+		return nil
+	})
+	metricstest.CheckCountData(t, "event_count", wantTags, 1)
+	metricstest.CheckDistributionData(t, "event_dispatch_latencies", wantTags, 1, 1100.0, 1100.0)
+}
+
+func TestReportEventDispatchTimeWithAttachments(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	attachments := metricdata.Attachments{"response.header.X-Request-Id": "req-1"}
+	reportertest.ExpectMetrics(t, func() error {
+		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 503, attachments)
+		return nil
+	})
+
+	rows, err := view.RetrieveData("event_dispatch_latencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("event_dispatch_latencies has %d rows, want 1", len(rows))
+	}
+	var got []metricdata.Attachments
+	for _, e := range rows[0].Data.(*view.DistributionData).ExemplarsPerBucket {
+		if e != nil {
+			got = append(got, e.Attachments)
+		}
+	}
+	if diff := cmp.Diff([]metricdata.Attachments{attachments}, got); diff != "" {
+		t.Errorf("unexpected exemplar attachments (-want, +got) = %v", diff)
+	}
+}
+
+func TestRetryCountBucket(t *testing.T) {
+	for retries, want := range map[int]string{
+		0:  "0",
+		1:  "1",
+		2:  "2",
+		3:  "3-4",
+		4:  "3-4",
+		5:  "5-9",
+		9:  "5-9",
+		10: "10+",
+		42: "10+",
+	} {
+		if got := retryCountBucket(retries); got != want {
+			t.Errorf("retryCountBucket(%d) = %q, want %q", retries, got, want)
+		}
+	}
+}
+
 func TestReportEventProcessingTime(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -124,6 +213,115 @@ func TestReportEventProcessingTime(t *testing.T) {
 	metricstest.CheckDistributionData(t, "event_processing_latencies", wantTags, 2, 1100.0, 9100.0)
 }
 
+func TestReportDuplicateEvent(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelBrokerName:    "testbroker",
+		metricskey.LabelTriggerName:   "testtrigger",
+		metricskey.LabelFilterType:    "testeventtype",
+		metricskey.PodName:            "testpod",
+		metricskey.ContainerName:      "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace: "testns",
+		Broker:    "testbroker",
+		Name:      "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportDuplicateEvent(ctx)
+	r.ReportDuplicateEvent(ctx)
+	metricstest.CheckCountData(t, "event_duplicate_count", wantTags, 2)
+}
+
+func TestReportExpiredEvent(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelBrokerName:    "testbroker",
+		metricskey.LabelTriggerName:   "testtrigger",
+		metricskey.LabelFilterType:    "testeventtype",
+		metricskey.PodName:            "testpod",
+		metricskey.ContainerName:      "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace: "testns",
+		Broker:    "testbroker",
+		Name:      "testtrigger",
+		FilterAttributes: map[string]string{
+			"type": "testeventtype",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportExpiredEvent(ctx)
+	r.ReportExpiredEvent(ctx)
+	metricstest.CheckCountData(t, "event_expired_count", wantTags, 2)
+}
+
+func TestReportSchemaValidation(t *testing.T) {
+	reportertest.ResetDeliveryMetrics()
+
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelBrokerName:    "testbroker",
+		metricskey.LabelTriggerName:   "testtrigger",
+		metricskey.LabelFilterType:    "any",
+		metricskey.LabelEventType:     "testeventtype",
+		"schema_validation":           "invalid",
+		metricskey.PodName:            "testpod",
+		metricskey.ContainerName:      "testcontainer",
+	}
+
+	r, err := NewDeliveryReporter("testpod", "testcontainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := r.AddTags(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err = AddTargetTags(ctx, &config.Target{
+		Namespace: "testns",
+		Broker:    "testbroker",
+		Name:      "testtrigger",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.ReportSchemaValidation(ctx, "testeventtype", "invalid")
+	metricstest.CheckCountData(t, "event_schema_validation_count", wantTags, 1)
+}
+
 func TestMetricsWithEmptySourceAndTypeFilter(t *testing.T) {
 	reportertest.ResetDeliveryMetrics()
 
@@ -156,7 +354,7 @@ func TestMetricsWithEmptySourceAndTypeFilter(t *testing.T) {
 	}
 
 	reportertest.ExpectMetrics(t, func() error {
-		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 202)
+		r.ReportEventDispatchTime(ctx, 1100*time.Millisecond, 202, nil)
 		return nil
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 1)
@@ -37,6 +37,22 @@ type IngressReportArgs struct {
 	Broker       string
 	EventType    string
 	ResponseCode int
+	// SchemaValidation is whether the event conforms to the EventSchema of
+	// its type, either "valid" or "invalid". Empty if the type has no schema.
+	SchemaValidation string
+	// MetricLabels are the labels of the broker from its allowlisted annotations.
+	MetricLabels map[string]string
+}
+
+// IngressRejectArgs are the arguments to report a request rejected by the
+// ingress before its events were read.
+type IngressRejectArgs struct {
+	Namespace    string
+	Broker       string
+	Reason       string
+	ResponseCode int
+	// MetricLabels are the labels of the broker from its allowlisted annotations.
+	MetricLabels map[string]string
 }
 
 func (r *IngressReporter) register() error {
@@ -46,9 +62,11 @@ func (r *IngressReporter) register() error {
 		EventTypeKey,
 		ResponseCodeKey,
 		ResponseCodeClassKey,
+		SchemaValidationKey,
 		PodNameKey,
 		ContainerNameKey,
 	}
+	tagKeys = append(tagKeys, metricLabelKeys()...)
 
 	// Create view to see our measurements.
 	return metrics.RegisterResourceView(
@@ -59,6 +77,21 @@ func (r *IngressReporter) register() error {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Name:        r.rejectedRequestCountM.Name(),
+			Description: r.rejectedRequestCountM.Description(),
+			Measure:     r.rejectedRequestCountM,
+			Aggregation: view.Count(),
+			TagKeys: append([]tag.Key{
+				NamespaceNameKey,
+				BrokerNameKey,
+				RejectReasonKey,
+				ResponseCodeKey,
+				ResponseCodeClassKey,
+				PodNameKey,
+				ContainerNameKey,
+			}, metricLabelKeys()...),
+		},
 	)
 }
 
@@ -72,6 +105,13 @@ func NewIngressReporter(podName PodName, containerName ContainerName) (*IngressR
 			"Number of events received by a Broker",
 			stats.UnitDimensionless,
 		),
+		// rejectedRequestCountM records the requests that were rejected
+		// because their body exceeded the size limit or was sent too slowly.
+		rejectedRequestCountM: stats.Int64(
+			"rejected_request_count",
+			"Number of requests rejected by a Broker before their events were read",
+			stats.UnitDimensionless,
+		),
 	}
 	if err := r.register(); err != nil {
 		return nil, fmt.Errorf("failed to register ingress stats: %w", err)
@@ -84,11 +124,12 @@ type IngressReporter struct {
 	podName       PodName
 	containerName ContainerName
 	eventCountM   *stats.Int64Measure
+
+	rejectedRequestCountM *stats.Int64Measure
 }
 
 func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressReportArgs) error {
-	tag, err := tag.New(
-		ctx,
+	mutators := append([]tag.Mutator{
 		tag.Insert(PodNameKey, string(r.podName)),
 		tag.Insert(ContainerNameKey, string(r.containerName)),
 		tag.Insert(NamespaceNameKey, args.Namespace),
@@ -96,10 +137,34 @@ func (r *IngressReporter) ReportEventCount(ctx context.Context, args IngressRepo
 		tag.Insert(EventTypeKey, args.EventType),
 		tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
 		tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
-	)
+	}, metricLabelMutators(args.MetricLabels)...)
+	if args.SchemaValidation != "" {
+		mutators = append(mutators, tag.Insert(SchemaValidationKey, args.SchemaValidation))
+	}
+	tag, err := tag.New(ctx, mutators...)
 	if err != nil {
 		return fmt.Errorf("failed to create metrics tag: %v", err)
 	}
 	metrics.Record(tag, r.eventCountM.M(1))
 	return nil
 }
+
+// ReportRejectedRequest counts a request that was rejected before its events
+// were read.
+func (r *IngressReporter) ReportRejectedRequest(ctx context.Context, args IngressRejectArgs) error {
+	mutators := append([]tag.Mutator{
+		tag.Insert(PodNameKey, string(r.podName)),
+		tag.Insert(ContainerNameKey, string(r.containerName)),
+		tag.Insert(NamespaceNameKey, args.Namespace),
+		tag.Insert(BrokerNameKey, args.Broker),
+		tag.Insert(RejectReasonKey, args.Reason),
+		tag.Insert(ResponseCodeKey, strconv.Itoa(args.ResponseCode)),
+		tag.Insert(ResponseCodeClassKey, metrics.ResponseCodeClass(args.ResponseCode)),
+	}, metricLabelMutators(args.MetricLabels)...)
+	tag, err := tag.New(ctx, mutators...)
+	if err != nil {
+		return fmt.Errorf("failed to create metrics tag: %v", err)
+	}
+	metrics.Record(tag, r.rejectedRequestCountM.M(1))
+	return nil
+}
@@ -60,3 +60,65 @@ func TestStatsReporter(t *testing.T) {
 	})
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 }
+
+func TestReportRejectedRequest(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	args := IngressRejectArgs{
+		Namespace:    "testns",
+		Broker:       "testbroker",
+		Reason:       "request-too-large",
+		ResponseCode: 413,
+	}
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName:     "testns",
+		metricskey.LabelBrokerName:        "testbroker",
+		"reject_reason":                   "request-too-large",
+		metricskey.LabelResponseCode:      "413",
+		metricskey.LabelResponseCodeClass: "4xx",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportRejectedRequest(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "rejected_request_count", wantTags, 1)
+}
+
+func TestReportEventCountSchemaValidation(t *testing.T) {
+	reportertest.ResetIngressMetrics()
+
+	args := IngressReportArgs{
+		Namespace:        "testns",
+		Broker:           "testbroker",
+		EventType:        "testeventtype",
+		ResponseCode:     400,
+		SchemaValidation: "invalid",
+	}
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName:     "testns",
+		metricskey.LabelBrokerName:        "testbroker",
+		metricskey.LabelEventType:         "testeventtype",
+		metricskey.LabelResponseCode:      "400",
+		metricskey.LabelResponseCodeClass: "4xx",
+		"schema_validation":               "invalid",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportEventCount(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "event_count", wantTags, 1)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"go.opencensus.io/tag"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MetricLabelAnnotationsEnvKey is the environment variable with the
+// comma-separated allowlist of Broker and Trigger annotations that are attached
+// as labels to the broker metrics, e.g. "example.com/team,example.com/cost-center".
+const MetricLabelAnnotationsEnvKey = "METRICS_LABEL_ANNOTATIONS"
+
+// reservedLabels are the labels the broker metrics already have, which
+// annotations can't override.
+var reservedLabels = map[string]bool{
+	NamespaceNameKey.Name():     true,
+	BrokerNameKey.Name():        true,
+	EventTypeKey.Name():         true,
+	TriggerNameKey.Name():       true,
+	TriggerFilterTypeKey.Name(): true,
+	ResponseCodeKey.Name():      true,
+	ResponseCodeClassKey.Name(): true,
+	PodNameKey.Name():           true,
+	ContainerNameKey.Name():     true,
+}
+
+// MetricLabelName returns the name of the metric label of an annotation: the
+// name part of the annotation key with characters other than letters, digits
+// and underscores replaced by underscores. E.g. "example.com/cost-center"
+// becomes "cost_center".
+func MetricLabelName(annotation string) string {
+	name := annotation[strings.LastIndex(annotation, "/")+1:]
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// metricLabelAnnotations returns the allowlisted annotations keyed by the name
+// of their metric label. Annotations whose label is reserved are left out.
+func metricLabelAnnotations() map[string]string {
+	annotations := make(map[string]string)
+	for _, a := range strings.Split(os.Getenv(MetricLabelAnnotationsEnvKey), ",") {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		if name := MetricLabelName(a); !reservedLabels[name] {
+			annotations[name] = a
+		}
+	}
+	return annotations
+}
+
+// MetricLabels returns the metric labels of the allowlisted annotations that
+// are set in annotations, or nil if there are none. Values that aren't valid
+// metric label values are left out.
+func MetricLabels(annotations map[string]string) map[string]string {
+	var labels map[string]string
+	for name, a := range metricLabelAnnotations() {
+		if v, ok := annotations[a]; ok && validLabelValue(v) {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[name] = v
+		}
+	}
+	return labels
+}
+
+// validLabelValue returns true if v is a valid tag value, i.e. at most 255
+// printable ASCII characters.
+func validLabelValue(v string) bool {
+	if len(v) > 255 {
+		return false
+	}
+	for _, r := range v {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// metricLabelKeys returns the tag keys of the allowlisted annotations, which
+// the views of the broker metrics are broken down by.
+func metricLabelKeys() []tag.Key {
+	var names []string
+	for name := range metricLabelAnnotations() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var keys []tag.Key
+	for _, name := range names {
+		if k, err := tag.NewKey(name); err == nil {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// metricLabelMutators returns the mutators inserting the metric labels as tags.
+// Labels that aren't valid tags are left out.
+func metricLabelMutators(labels map[string]string) []tag.Mutator {
+	var mutators []tag.Mutator
+	for name, v := range labels {
+		if k, err := tag.NewKey(name); err == nil {
+			mutators = append(mutators, tag.Insert(k, v))
+		}
+	}
+	return mutators
+}
+
+// MetricLabelEnvVars returns the metric label allowlist set in the current
+// environment, so that the controller can pass it on to the data plane pods.
+func MetricLabelEnvVars() []corev1.EnvVar {
+	if v := os.Getenv(MetricLabelAnnotationsEnvKey); v != "" {
+		return []corev1.EnvVar{{Name: MetricLabelAnnotationsEnvKey, Value: v}}
+	}
+	return nil
+}
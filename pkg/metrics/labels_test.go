@@ -0,0 +1,148 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/metrics/metricskey"
+	"knative.dev/pkg/metrics/metricstest"
+
+	reportertest "github.com/google/knative-gcp/pkg/metrics/testing"
+)
+
+func setMetricLabelAnnotations(t *testing.T, value string) {
+	t.Helper()
+	old, ok := os.LookupEnv(MetricLabelAnnotationsEnvKey)
+	if err := os.Setenv(MetricLabelAnnotationsEnvKey, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(MetricLabelAnnotationsEnvKey, old)
+		} else {
+			os.Unsetenv(MetricLabelAnnotationsEnvKey)
+		}
+	})
+}
+
+func TestMetricLabelName(t *testing.T) {
+	for annotation, want := range map[string]string{
+		"team":                    "team",
+		"example.com/team":        "team",
+		"example.com/cost-center": "cost_center",
+		"a/b/cost.center_1":       "cost_center_1",
+	} {
+		if got := MetricLabelName(annotation); got != want {
+			t.Errorf("MetricLabelName(%q) got=%q, want=%q", annotation, got, want)
+		}
+	}
+}
+
+func TestMetricLabels(t *testing.T) {
+	setMetricLabelAnnotations(t, "example.com/team, example.com/cost-center,example.com/broker_name,,")
+
+	testCases := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+	}{{
+		name: "no annotations",
+	}, {
+		name: "allowlisted annotations",
+		annotations: map[string]string{
+			"example.com/team":        "payments",
+			"example.com/cost-center": "cc-1234",
+			"example.com/other":       "ignored",
+		},
+		want: map[string]string{
+			"team":        "payments",
+			"cost_center": "cc-1234",
+		},
+	}, {
+		name: "reserved label",
+		annotations: map[string]string{
+			"example.com/broker_name": "other-broker",
+		},
+	}, {
+		name: "invalid value",
+		annotations: map[string]string{
+			"example.com/team":        "payments\n",
+			"example.com/cost-center": "cc-1234",
+		},
+		want: map[string]string{
+			"cost_center": "cc-1234",
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, MetricLabels(tc.annotations)); diff != "" {
+				t.Errorf("unexpected labels (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestMetricLabelEnvVars(t *testing.T) {
+	setMetricLabelAnnotations(t, "")
+	if got := MetricLabelEnvVars(); got != nil {
+		t.Errorf("MetricLabelEnvVars() got=%v, want=nil", got)
+	}
+
+	setMetricLabelAnnotations(t, "example.com/team")
+	want := []corev1.EnvVar{{Name: MetricLabelAnnotationsEnvKey, Value: "example.com/team"}}
+	if diff := cmp.Diff(want, MetricLabelEnvVars()); diff != "" {
+		t.Errorf("unexpected env vars (-want, +got) = %v", diff)
+	}
+}
+
+func TestStatsReporterWithMetricLabels(t *testing.T) {
+	setMetricLabelAnnotations(t, "example.com/team")
+	reportertest.ResetIngressMetrics()
+	defer reportertest.ResetIngressMetrics()
+
+	args := IngressReportArgs{
+		Namespace:    "testns",
+		Broker:       "testbroker",
+		EventType:    "testeventtype",
+		ResponseCode: 202,
+		MetricLabels: map[string]string{"team": "payments"},
+	}
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName:     "testns",
+		metricskey.LabelBrokerName:        "testbroker",
+		metricskey.LabelEventType:         "testeventtype",
+		metricskey.LabelResponseCode:      "202",
+		metricskey.LabelResponseCodeClass: "2xx",
+		metricskey.ContainerName:          "testcontainer",
+		metricskey.PodName:                "testpod",
+		"team":                            "payments",
+	}
+
+	r, err := NewIngressReporter(PodName("testpod"), ContainerName("testcontainer"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportertest.ExpectMetrics(t, func() error {
+		return r.ReportEventCount(context.Background(), args)
+	})
+	metricstest.CheckCountData(t, "event_count", wantTags, 1)
+}
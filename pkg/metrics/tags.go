@@ -40,6 +40,22 @@ var (
 	ResponseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	ResponseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
 
+	// RejectReasonKey is the reason the ingress rejected a request before
+	// reading its events.
+	RejectReasonKey = tag.MustNewKey("reject_reason")
+
+	// SchemaValidationKey tells events conforming to the EventSchema of their
+	// type apart from the ones that don't. It is empty for events without a
+	// schema.
+	SchemaValidationKey = tag.MustNewKey("schema_validation")
+
+	// AttemptClassKey tells first deliveries of an event to a Trigger
+	// subscriber apart from retries.
+	AttemptClassKey = tag.MustNewKey("attempt_class")
+	// RetryCountKey is the bucket of the number of times an event was
+	// delivered to a Trigger subscriber before.
+	RetryCountKey = tag.MustNewKey("retry_count")
+
 	PodNameKey       = tag.MustNewKey(metricskey.PodName)
 	ContainerNameKey = tag.MustNewKey(metricskey.ContainerName)
 )
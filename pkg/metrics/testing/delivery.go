@@ -35,7 +35,9 @@ type deliveryKey struct {
 type Tags map[string]string
 
 type ExpectDelivery struct {
-	TriggerTags     map[string]Tags
+	TriggerTags map[string]Tags
+	// DeliveryTags are only expected on the delivery metrics of a trigger.
+	DeliveryTags    map[string]Tags
 	ProcessingCount map[string]int64
 	DeliveryCount   map[deliveryKey]int64
 }
@@ -43,6 +45,7 @@ type ExpectDelivery struct {
 func NewExpectDelivery() ExpectDelivery {
 	return ExpectDelivery{
 		TriggerTags:     make(map[string]Tags),
+		DeliveryTags:    make(map[string]Tags),
 		ProcessingCount: make(map[string]int64),
 		DeliveryCount:   make(map[deliveryKey]int64),
 	}
@@ -61,6 +64,15 @@ func (e ExpectDelivery) AddTrigger(t *testing.T, trigger string, expectTags Tags
 	e.TriggerTags[trigger] = expectTags
 }
 
+// AddDeliveryTags adds tags that are expected on the delivery metrics of the
+// trigger on top of its trigger tags, such as the attempt tags.
+func (e ExpectDelivery) AddDeliveryTags(t *testing.T, trigger string, expectTags Tags) {
+	if _, ok := e.TriggerTags[trigger]; !ok {
+		t.Fatalf("trigger %q not defined", trigger)
+	}
+	e.DeliveryTags[trigger] = expectTags
+}
+
 func (e ExpectDelivery) ExpectProcessing(t *testing.T, trigger string) {
 	if _, ok := e.TriggerTags[trigger]; !ok {
 		t.Fatalf("trigger %q not defined", trigger)
@@ -138,7 +150,7 @@ func (e ExpectDelivery) verifyDelivery(viewName string) error {
 		ignoreCodeTags := cmpopts.IgnoreMapEntries(func(k string, v string) bool {
 			return k == "response_code" || k == "response_code_class"
 		})
-		if diff := cmp.Diff(e.TriggerTags[trigger], Tags(tags), ignoreCodeTags); diff != "" {
+		if diff := cmp.Diff(e.deliveryTags(trigger), Tags(tags), ignoreCodeTags); diff != "" {
 			return fmt.Errorf("unexpected tags (-want, +got) = %v", diff)
 		}
 	}
@@ -149,6 +161,19 @@ func (e ExpectDelivery) verifyDelivery(viewName string) error {
 	return nil
 }
 
+// deliveryTags returns the tags expected on the delivery metrics of the
+// trigger.
+func (e ExpectDelivery) deliveryTags(trigger string) Tags {
+	tags := make(Tags)
+	for k, v := range e.TriggerTags[trigger] {
+		tags[k] = v
+	}
+	for k, v := range e.DeliveryTags[trigger] {
+		tags[k] = v
+	}
+	return tags
+}
+
 func (e ExpectDelivery) verifyProcessing() error {
 	rows, err := view.RetrieveData("event_processing_latencies")
 	if err != nil {
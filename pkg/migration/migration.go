@@ -0,0 +1,206 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migration moves CloudPubSubSources to the Broker/Trigger topology.
+// The events of a migrated source go through a Broker, and a Trigger filtering
+// the events of the source delivers them to its original sink, so that more
+// consumers can subscribe to them with their own Triggers.
+package migration
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+)
+
+// SourceAnnotation is set on the Triggers created by a migration to the name
+// of the CloudPubSubSource they deliver the events of.
+const SourceAnnotation = "events.cloud.google.com/migrated-from"
+
+// ErrMigrated is returned by Plan for sources that already send their events
+// to the Broker.
+var ErrMigrated = errors.New("already sends its events to the Broker")
+
+// Migration describes how a CloudPubSubSource is moved to the Broker/Trigger
+// topology.
+type Migration struct {
+	// Source is the migrated CloudPubSubSource.
+	Source *eventsv1beta1.CloudPubSubSource `json:"source"`
+	// Broker is the Broker the source sends its events to. It is created if
+	// it doesn't exist.
+	Broker *brokerv1beta1.Broker `json:"broker"`
+	// Trigger delivers the events of the source to its original sink.
+	Trigger *brokerv1beta1.Trigger `json:"trigger"`
+}
+
+// Plan returns the migration of the CloudPubSubSource to the Broker named
+// broker in its namespace. The source must be ready, so that the project of
+// its topic, which its events are filtered by, is known.
+func Plan(src *eventsv1beta1.CloudPubSubSource, broker string) (*Migration, error) {
+	if ref := src.Spec.Sink.Ref; ref != nil && ref.Kind == "Broker" && ref.Name == broker {
+		return nil, fmt.Errorf("CloudPubSubSource %s/%s: %w", src.Namespace, src.Name, ErrMigrated)
+	}
+	if src.Status.ProjectID == "" || !src.Status.IsReady() {
+		return nil, fmt.Errorf("CloudPubSubSource %s/%s is not ready", src.Namespace, src.Name)
+	}
+	subscriber := *src.Spec.Sink.DeepCopy()
+	if subscriber.Ref != nil && subscriber.Ref.Namespace == "" {
+		subscriber.Ref.Namespace = src.Namespace
+	}
+	return &Migration{
+		Source: src,
+		Broker: &brokerv1beta1.Broker{
+			TypeMeta: metav1.TypeMeta{APIVersion: brokerv1beta1.SchemeGroupVersion.String(), Kind: "Broker"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   src.Namespace,
+				Name:        broker,
+				Annotations: map[string]string{eventing.BrokerClassKey: brokerv1beta1.BrokerClass},
+			},
+		},
+		Trigger: &brokerv1beta1.Trigger{
+			TypeMeta: metav1.TypeMeta{APIVersion: brokerv1beta1.SchemeGroupVersion.String(), Kind: "Trigger"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   src.Namespace,
+				Name:        src.Name,
+				Annotations: map[string]string{SourceAnnotation: src.Name},
+			},
+			Spec: eventingv1beta1.TriggerSpec{
+				Broker: broker,
+				Filter: &eventingv1beta1.TriggerFilter{
+					Attributes: eventingv1beta1.TriggerFilterAttributes{
+						"type":   eventsv1beta1.CloudPubSubSourcePublish,
+						"source": eventsv1beta1.CloudPubSubSourceEventSource(src.Status.ProjectID, src.Spec.Topic),
+					},
+				},
+				Subscriber: subscriber,
+			},
+		},
+	}, nil
+}
+
+// Migrator performs the cutover of migrations.
+type Migrator struct {
+	Client clientset.Interface
+	// Interval is how often the readiness of the objects is checked.
+	Interval time.Duration
+	// Timeout is how long each object may take to become ready.
+	Timeout time.Duration
+	// Logf logs the progress of the cutover.
+	Logf func(format string, args ...interface{})
+}
+
+// Cutover moves the source of m to the Broker/Trigger topology:
+//
+//  1. The Broker is created if it doesn't exist, and must become ready.
+//  2. The Trigger is created, and must become ready before any event of the
+//     source is sent to the Broker.
+//  3. The sink of the source is switched to the Broker, and the source must
+//     become ready with it.
+//
+// The source keeps its subscription, so no event published to its topic is
+// lost or delivered twice by the cutover. The Broker has no other way to
+// receive the events of the topic, so the source is kept as the ingress of
+// the Broker rather than deleted. Each step is skipped if it was already
+// done, so an interrupted cutover can be resumed.
+func (m *Migrator) Cutover(mig *Migration) error {
+	ns := mig.Source.Namespace
+	brokers := m.Client.EventingV1beta1().Brokers(ns)
+	if _, err := brokers.Create(mig.Broker); err != nil && !apierrs.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create Broker %s/%s: %w", ns, mig.Broker.Name, err)
+	}
+	m.Logf("Waiting for Broker %s/%s to be ready", ns, mig.Broker.Name)
+	var broker *brokerv1beta1.Broker
+	if err := m.wait(func() (bool, error) {
+		b, err := brokers.Get(mig.Broker.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		broker = b
+		return b.Status.IsReady() && b.Status.Address.URL != nil, nil
+	}); err != nil {
+		return fmt.Errorf("Broker %s/%s is not ready: %w", ns, mig.Broker.Name, err)
+	}
+
+	triggers := m.Client.EventingV1beta1().Triggers(ns)
+	if _, err := triggers.Create(mig.Trigger); apierrs.IsAlreadyExists(err) {
+		existing, err := triggers.Get(mig.Trigger.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if existing.Annotations[SourceAnnotation] != mig.Source.Name {
+			return fmt.Errorf("Trigger %s/%s already exists and doesn't belong to the migration", ns, mig.Trigger.Name)
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to create Trigger %s/%s: %w", ns, mig.Trigger.Name, err)
+	}
+	m.Logf("Waiting for Trigger %s/%s to be ready", ns, mig.Trigger.Name)
+	if err := m.wait(func() (bool, error) {
+		t, err := triggers.Get(mig.Trigger.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return t.Status.IsReady(), nil
+	}); err != nil {
+		return fmt.Errorf("Trigger %s/%s is not ready, the source was left unchanged: %w", ns, mig.Trigger.Name, err)
+	}
+
+	sources := m.Client.EventsV1beta1().CloudPubSubSources(ns)
+	sink := duckv1.Destination{Ref: &duckv1.KReference{
+		APIVersion: "eventing.knative.dev/v1beta1",
+		Kind:       "Broker",
+		Namespace:  ns,
+		Name:       mig.Broker.Name,
+	}}
+	m.Logf("Switching the sink of CloudPubSubSource %s/%s to Broker %s", ns, mig.Source.Name, mig.Broker.Name)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		src, err := sources.Get(mig.Source.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		src.Spec.Sink = sink
+		_, err = sources.Update(src)
+		return err
+	}); err != nil {
+		return fmt.Errorf("failed to switch the sink of CloudPubSubSource %s/%s: %w", ns, mig.Source.Name, err)
+	}
+	m.Logf("Waiting for CloudPubSubSource %s/%s to be ready", ns, mig.Source.Name)
+	if err := m.wait(func() (bool, error) {
+		src, err := sources.Get(mig.Source.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return src.Status.IsReady() && src.Status.SinkURI.String() == broker.Status.Address.URL.String(), nil
+	}); err != nil {
+		return fmt.Errorf("CloudPubSubSource %s/%s is not ready with the Broker as its sink: %w", ns, mig.Source.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) wait(ready wait.ConditionFunc) error {
+	return wait.PollImmediate(m.Interval, m.Timeout, ready)
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migration
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
+)
+
+var readyConditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}
+
+func newSource(ready bool) *eventsv1beta1.CloudPubSubSource {
+	src := &eventsv1beta1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "orders"},
+		Spec: eventsv1beta1.CloudPubSubSourceSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "orders-display"}},
+				},
+			},
+			Topic: "orders",
+		},
+	}
+	if ready {
+		src.Status.ProjectID = "my-project"
+		src.Status.Conditions = readyConditions
+	}
+	return src
+}
+
+func TestPlan(t *testing.T) {
+	if _, err := Plan(newSource(false), "default"); err == nil {
+		t.Error("Plan() of a source that is not ready succeeded, want error")
+	}
+
+	toBroker := newSource(true)
+	toBroker.Spec.Sink = duckv1.Destination{Ref: &duckv1.KReference{Kind: "Broker", Name: "default"}}
+	if _, err := Plan(toBroker, "default"); !errors.Is(err, ErrMigrated) {
+		t.Errorf("Plan() of a source sending to the Broker got error %v, want %v", err, ErrMigrated)
+	}
+
+	m, err := Plan(newSource(true), "default")
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if got, want := m.Broker.Annotations["eventing.knative.dev/broker.class"], brokerv1beta1.BrokerClass; got != want {
+		t.Errorf("Broker class got=%q, want=%q", got, want)
+	}
+	wantFilter := map[string]string{
+		"type":   "com.google.cloud.pubsub.topic.publish",
+		"source": "//pubsub.googleapis.com/projects/my-project/topics/orders",
+	}
+	if diff := cmp.Diff(wantFilter, map[string]string(m.Trigger.Spec.Filter.Attributes)); diff != "" {
+		t.Errorf("Trigger filter (-want,+got): %v", diff)
+	}
+	wantSubscriber := duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Namespace: "prod", Name: "orders-display"}}
+	if diff := cmp.Diff(wantSubscriber, m.Trigger.Spec.Subscriber); diff != "" {
+		t.Errorf("Trigger subscriber (-want,+got): %v", diff)
+	}
+}
+
+// readyOn makes the objects of the resource ready when they are created or
+// updated, as their controllers would.
+func readyOn(client *fake.Clientset, verb, resource string) {
+	client.PrependReactor(verb, resource, func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		switch o := action.(interface{ GetObject() runtime.Object }).GetObject().(type) {
+		case *brokerv1beta1.Broker:
+			o.Status = *brokerv1beta1.TestHelper.ReadyBrokerStatus()
+		case *brokerv1beta1.Trigger:
+			o.Status.Conditions = readyConditions
+		case *eventsv1beta1.CloudPubSubSource:
+			o.Status.SinkURI = apis.HTTP("example.com")
+		}
+		return false, nil, nil
+	})
+}
+
+func newMigrator(client *fake.Clientset) *Migrator {
+	return &Migrator{
+		Client:   client,
+		Interval: time.Millisecond,
+		Timeout:  100 * time.Millisecond,
+		Logf:     func(string, ...interface{}) {},
+	}
+}
+
+func TestCutover(t *testing.T) {
+	src := newSource(true)
+	client := fake.NewSimpleClientset(src)
+	readyOn(client, "create", "brokers")
+	readyOn(client, "create", "triggers")
+	readyOn(client, "update", "cloudpubsubsources")
+
+	m, err := Plan(src, "default")
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := newMigrator(client).Cutover(m); err != nil {
+		t.Fatalf("Cutover() failed: %v", err)
+	}
+
+	got, err := client.EventsV1beta1().CloudPubSubSources("prod").Get("orders", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSink := duckv1.Destination{Ref: &duckv1.KReference{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Broker", Namespace: "prod", Name: "default"}}
+	if diff := cmp.Diff(wantSink, got.Spec.Sink); diff != "" {
+		t.Errorf("source sink (-want,+got): %v", diff)
+	}
+	if _, err := client.EventingV1beta1().Triggers("prod").Get("orders", metav1.GetOptions{}); err != nil {
+		t.Errorf("Trigger was not created: %v", err)
+	}
+
+	// The cutover can be resumed.
+	if err := newMigrator(client).Cutover(m); err != nil {
+		t.Errorf("resumed Cutover() failed: %v", err)
+	}
+}
+
+func TestCutoverTriggerNotReady(t *testing.T) {
+	src := newSource(true)
+	client := fake.NewSimpleClientset(src)
+	readyOn(client, "create", "brokers")
+
+	m, err := Plan(src, "default")
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := newMigrator(client).Cutover(m); err == nil {
+		t.Fatal("Cutover() with a Trigger that is not ready succeeded, want error")
+	}
+	got, err := client.EventsV1beta1().CloudPubSubSources("prod").Get("orders", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(src.Spec.Sink, got.Spec.Sink); diff != "" {
+		t.Errorf("source sink was changed (-want,+got): %v", diff)
+	}
+}
+
+func TestCutoverForeignTrigger(t *testing.T) {
+	src := newSource(true)
+	client := fake.NewSimpleClientset(src, &brokerv1beta1.Trigger{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "orders"},
+	})
+	readyOn(client, "create", "brokers")
+
+	m, err := Plan(src, "default")
+	if err != nil {
+		t.Fatalf("Plan() failed: %v", err)
+	}
+	if err := newMigrator(client).Cutover(m); err == nil {
+		t.Error("Cutover() with an existing Trigger of the same name succeeded, want error")
+	}
+}
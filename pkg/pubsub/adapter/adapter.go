@@ -24,11 +24,14 @@ import (
 
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
+	"cloud.google.com/go/pubsub"
 	cloudevents "github.com/cloudevents/sdk-go"
 	"github.com/cloudevents/sdk-go/pkg/cloudevents/transport"
-	"github.com/cloudevents/sdk-go/pkg/cloudevents/transport/http"
 	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/kncloudevents"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/utils"
@@ -65,6 +68,10 @@ type Adapter struct {
 	// E.g. 'laconia', not 'projects/my-gcp-project/topics/laconia'.
 	Topic string `envconfig:"PUBSUB_TOPIC_ID" required:"true"`
 
+	// TopicProject is the environment variable containing the project of the
+	// PubSub Topic, if it is not in the same project as the subscription.
+	TopicProject string `envconfig:"PUBSUB_TOPIC_PROJECT_ID"`
+
 	// Subscription is the environment variable containing the name of the
 	// subscription to use.
 	Subscription string `envconfig:"PUBSUB_SUBSCRIPTION_ID" required:"true"`
@@ -81,6 +88,26 @@ type Adapter struct {
 	// One of [binary, structured, push]. Default: binary
 	SendMode converters.ModeType `envconfig:"SEND_MODE" default:"binary" required:"true"`
 
+	// PushAuthServiceAccount is the email of the service account whose push
+	// authentication the adapter emulates in push mode, by sending ID tokens
+	// of it to the sink. The credentials of the adapter need the Service
+	// Account Token Creator role on it. If empty, no token is sent.
+	PushAuthServiceAccount string `envconfig:"PUSH_AUTH_SERVICE_ACCOUNT"`
+
+	// PushAuthAudience is the audience of the emulated push tokens. Defaults
+	// to the sink, like the audience of push subscriptions defaults to the
+	// push endpoint.
+	PushAuthAudience string `envconfig:"PUSH_AUTH_AUDIENCE"`
+
+	// SinkCABundlePath is the path of a file of PEM encoded CA certificates
+	// trusted to verify the certificate of the sink, in addition to the
+	// system ones.
+	SinkCABundlePath string `envconfig:"SINK_CA_BUNDLE_PATH"`
+
+	// SinkInsecureSkipVerify disables the verification of the certificate of
+	// the sink.
+	SinkInsecureSkipVerify bool `envconfig:"SINK_INSECURE_SKIP_VERIFY"`
+
 	// MetricsConfigJson is a json string of metrics.ExporterOptions.
 	// This is used to configure the metrics exporter options, the config is
 	// stored in a config map inside the controllers namespace and copied here.
@@ -105,17 +132,56 @@ type Adapter struct {
 	// Environment variable containing the resource group. E.g., storages.events.cloud.google.com.
 	ResourceGroup string `envconfig:"RESOURCE_GROUP" default:"pullsubscriptions.pubsub.cloud.google.com" required:"true"`
 
+	// DrainPort is the port of the endpoint the preStop hook calls to drain
+	// the adapter before it receives SIGTERM. The endpoint is not served if
+	// it is 0.
+	DrainPort int `envconfig:"DRAIN_PORT"`
+
+	// MaxOutstandingMessages is the max number of messages received but not
+	// yet acknowledged. The Pub/Sub client default is used if it is 0.
+	MaxOutstandingMessages int `envconfig:"MAX_OUTSTANDING_MESSAGES"`
+
+	// RateLimit is the number of events delivered per second. The rate is
+	// not limited if it is 0.
+	RateLimit int `envconfig:"RATE_LIMIT"`
+
+	// RateLimitBurst is the number of events that may be delivered at once
+	// above RateLimit. Defaults to RateLimit.
+	RateLimitBurst int `envconfig:"RATE_LIMIT_BURST"`
+
+	// stopPull stops the streaming pull started by Start.
+	stopPull context.CancelFunc
+
+	// pullStopped is closed once the streaming pull stopped and the
+	// received messages were delivered.
+	pullStopped chan struct{}
+
+	// client is the Pub/Sub client shared with the other adapters of an
+	// Agent. If nil, the adapter creates its own.
+	client *pubsub.Client
+
+	// LiteLocation is the zone of the subscription if it is a Pub/Sub Lite
+	// one, in which case Project must be set.
+	LiteLocation string `envconfig:"PUBSUB_LITE_LOCATION"`
+
+	// newLiteSubscriber creates the Pub/Sub Lite subscriber if LiteLocation
+	// is set. If nil, pscompat's is used.
+	newLiteSubscriber gpubsublite.SubscriberFn
+
 	// inbound is the cloudevents client to use to receive events.
 	inbound cloudevents.Client
 
-	// outbound is the cloudevents client to use to send events.
-	outbound cloudevents.Client
+	// outbound sends events to the sink.
+	outbound *httpSender
 
 	// transformer is the cloudevents client to transform received events before sending.
 	transformer cloudevents.Client
 
 	// reporter reports metrics to the configured backend.
 	reporter StatsReporter
+
+	// limiter paces the deliveries if RateLimit is set.
+	limiter *rate.Limiter
 }
 
 // Start starts the adapter. Note: Only call once, not thread safe.
@@ -141,8 +207,16 @@ func (a *Adapter) Start(ctx context.Context) error {
 
 	// Send events on HTTP.
 	if a.outbound == nil {
-		if a.outbound, err = a.newHTTPClient(ctx, a.Sink); err != nil {
-			return fmt.Errorf("failed to create outbound cloudevent client: %w", err)
+		client, err := newSinkHTTPClient(a.SinkCABundlePath, a.SinkInsecureSkipVerify)
+		if err != nil {
+			return fmt.Errorf("failed to create outbound HTTP client: %w", err)
+		}
+		a.outbound = newHTTPSender(a.Sink, a.SendMode, a.extensions)
+		a.outbound.client = client
+		if a.SendMode == converters.Push && a.PushAuthServiceAccount != "" {
+			if a.outbound.pushAuth, err = newPushAuth(ctx, a.PushAuthServiceAccount, a.pushAuthAudience()); err != nil {
+				return fmt.Errorf("failed to create push authentication: %w", err)
+			}
 		}
 	}
 
@@ -150,6 +224,10 @@ func (a *Adapter) Start(ctx context.Context) error {
 		a.reporter = NewStatsReporter()
 	}
 
+	if a.limiter == nil {
+		a.limiter = newRateLimiter(a.RateLimit, a.RateLimitBurst)
+	}
+
 	// Make the transformer client in case the TransformerURI has been set.
 	if a.Transformer != "" {
 		if a.transformer == nil {
@@ -159,12 +237,31 @@ func (a *Adapter) Start(ctx context.Context) error {
 		}
 	}
 
-	return a.inbound.StartReceiver(ctx, a.receive)
+	// The streaming pull is stopped either when ctx is done or when the
+	// adapter is drained, in which case the deliveries in flight continue
+	// until ctx is done.
+	pullCtx, stopPull := context.WithCancel(ctx)
+	defer stopPull()
+	a.stopPull = stopPull
+	a.pullStopped = make(chan struct{})
+	if a.DrainPort != 0 {
+		go a.serveDrain(ctx)
+	}
+
+	err = a.inbound.StartReceiver(pullCtx, func(dctx context.Context, event cloudevents.Event, resp *cloudevents.EventResponse) error {
+		return a.receive(drainContext{Context: ctx, values: dctx}, event, resp)
+	})
+	close(a.pullStopped)
+	if ctx.Err() == nil && pullCtx.Err() != nil {
+		// The adapter was drained, wait to be terminated rather than exit
+		// while the preStop hook is still running.
+		<-ctx.Done()
+		return nil
+	}
+	return err
 }
 
 func (a *Adapter) receive(ctx context.Context, event cloudevents.Event, resp *cloudevents.EventResponse) error {
-	logger := logging.FromContext(ctx).With(zap.Any("event.id", event.ID()), zap.Any("sink", a.Sink))
-
 	// TODO Name and ResourceGroup might cause problems in the near future, as we might use a single receive-adapter
 	//  for multiple source objects. Same with Namespace, when doing multi-tenancy.
 	args := &ReportArgs{
@@ -175,6 +272,21 @@ func (a *Adapter) receive(ctx context.Context, event cloudevents.Event, resp *cl
 		ResourceGroup: a.ResourceGroup,
 	}
 
+	// Pace the deliveries. The message stays unacknowledged while it waits,
+	// its lease being extended by the Pub/Sub client, and is nacked if the
+	// adapter stops first.
+	if a.limiter != nil {
+		if err := a.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Keep the ordering key of the message so that brokers and other sinks
+	// can order the events by it. Push messages carry it in their payload.
+	if key := converters.OrderingKey(ctx); key != "" && a.SendMode != converters.Push {
+		event.SetExtension(converters.OrderingKeyExtension, key)
+	}
+
 	var err error
 	// If a transformer has been configured, then transform the message.
 	// Note that this path in the code will be executed when using the receive adapter as part of the underlying Channel
@@ -184,14 +296,16 @@ func (a *Adapter) receive(ctx context.Context, event cloudevents.Event, resp *cl
 		transformedCTX, transformedEvent, err := a.transformer.Send(ctx, event)
 		rtctx := cloudevents.HTTPTransportContextFrom(transformedCTX)
 		if err != nil {
-			logger.Errorf("error transforming cloud event %q", event.ID())
+			logging.FromContext(ctx).Desugar().Error("Failed to transform event",
+				zap.String("event.id", event.ID()), zap.String("sink", a.Sink), zap.Error(err))
 			a.reporter.ReportEventCount(args, rtctx.StatusCode)
 			return err
 		}
 		if transformedEvent == nil {
 			// This doesn't mean there was an error. E.g., the Broker filter pod might not return a response.
 			// Report the returned Status Code and return.
-			logger.Debugf("cloud event %q was not transformed", event.ID())
+			logging.FromContext(ctx).Desugar().Debug("Event was not transformed",
+				zap.String("event.id", event.ID()), zap.String("sink", a.Sink))
 			a.reporter.ReportEventCount(args, rtctx.StatusCode)
 			return nil
 		}
@@ -201,15 +315,10 @@ func (a *Adapter) receive(ctx context.Context, event cloudevents.Event, resp *cl
 		ctx = trace.NewContext(ctx, trace.FromContext(transformedCTX))
 	}
 
-	// Apply CloudEvent override extensions to the outbound event.
-	for k, v := range a.extensions {
-		event.SetExtension(k, v)
-	}
-
-	// Send the event and report the count.
-	rctx, r, err := a.outbound.Send(ctx, event)
-	rtctx := cloudevents.HTTPTransportContextFrom(rctx)
-	a.reporter.ReportEventCount(args, rtctx.StatusCode)
+	// Send the event with the CloudEvent override extensions and report the
+	// count.
+	code, r, err := a.outbound.send(ctx, event)
+	a.reporter.ReportEventCount(args, code)
 	if err != nil {
 		return err
 	} else if r != nil {
@@ -223,48 +332,113 @@ func (a *Adapter) convert(ctx context.Context, m transport.Message, err error) (
 	logger.Debug("Converting event from transport.")
 
 	if msg, ok := m.(*cepubsub.Message); ok {
-		return converters.Convert(ctx, msg, a.SendMode, a.AdapterType)
+		// The transport context holds the project of the topic, push
+		// messages name the subscription in its own project.
+		ctx = converters.WithSubscriptionProject(ctx, a.Project)
+		event, err := converters.Convert(ctx, msg, a.SendMode, a.AdapterType)
+		if err != nil {
+			return nil, err
+		}
+		a.normalize(ctx, event)
+		return event, nil
 	}
 	return nil, err
 }
 
-func (a *Adapter) newPubSubClient(ctx context.Context) (cloudevents.Client, error) {
-	tOpts := []cepubsub.Option{
-		cepubsub.WithProjectID(a.Project),
-		cepubsub.WithTopicID(a.Topic),
-		cepubsub.WithSubscriptionAndTopicID(a.Subscription, a.Topic),
+// normalize fixes up attribute values of the converted event that sinks would
+// reject, and reports each change.
+func (a *Adapter) normalize(ctx context.Context, event *cloudevents.Event) {
+	changes := converters.Normalize(event)
+	if len(changes) == 0 {
+		return
+	}
+	args := &ReportArgs{
+		Name:          a.Name,
+		Namespace:     a.Namespace,
+		EventType:     event.Type(),
+		EventSource:   event.Source(),
+		ResourceGroup: a.ResourceGroup,
 	}
+	logger := logging.FromContext(ctx).With(zap.Any("event.id", event.ID()))
+	for _, c := range changes {
+		logger.Desugar().Warn("Normalized event attribute", zap.String("attribute", c.Attribute), zap.String("action", string(c.Action)))
+		if err := a.reporter.ReportAttributeNormalized(args, c.Attribute, string(c.Action)); err != nil {
+			logger.Desugar().Warn("Failed to report attribute normalization", zap.Error(err))
+		}
+	}
+}
 
-	// Make a pubsub transport for the CloudEvents client.
-	t, err := cepubsub.New(ctx, tOpts...)
-	if err != nil {
-		return nil, err
+// newRateLimiter returns a limiter of eventsPerSecond deliveries, or nil if
+// eventsPerSecond is 0. The burst defaults to eventsPerSecond.
+func newRateLimiter(eventsPerSecond, burst int) *rate.Limiter {
+	if eventsPerSecond <= 0 {
+		return nil
 	}
+	if burst <= 0 {
+		burst = eventsPerSecond
+	}
+	return rate.NewLimiter(rate.Limit(eventsPerSecond), burst)
+}
 
-	// Use the transport to make a new CloudEvents client.
-	return cloudevents.NewClient(t,
-		cloudevents.WithConverterFn(a.convert),
-	)
+// pushAuthAudience returns the audience of the emulated push tokens.
+func (a *Adapter) pushAuthAudience() string {
+	if a.PushAuthAudience != "" {
+		return a.PushAuthAudience
+	}
+	return a.Sink
 }
 
-func (a *Adapter) newHTTPClient(ctx context.Context, target string) (cloudevents.Client, error) {
-	tOpts := []http.Option{
-		cloudevents.WithTarget(target),
+// topicProject returns the project of the topic. The project ID of the
+// protocol is only used for the transport context of the received messages, so
+// that the events are attributed to the topic.
+func (a *Adapter) topicProject() string {
+	if a.TopicProject != "" {
+		return a.TopicProject
 	}
+	return a.Project
+}
 
-	switch a.SendMode {
-	case converters.Binary, converters.Push:
-		tOpts = append(tOpts, cloudevents.WithBinaryEncoding())
-	case converters.Structured:
-		tOpts = append(tOpts, cloudevents.WithStructuredEncoding())
+func (a *Adapter) newPubSubClient(ctx context.Context) (cloudevents.Client, error) {
+	// Create the Pub/Sub client here so that API endpoint overrides apply.
+	client := a.client
+	if client == nil {
+		var err error
+		if client, err = pubsub.NewClient(ctx, a.Project, endpoints.PubSub(ctx)...); err != nil {
+			return nil, err
+		}
+	}
+	tOpts := []cepubsub.Option{
+		cepubsub.WithClient(client),
+		cepubsub.WithProjectID(a.topicProject()),
+		cepubsub.WithTopicID(a.Topic),
+		cepubsub.WithSubscriptionAndTopicID(a.Subscription, a.Topic),
 	}
 
-	// Make an http transport for the CloudEvents client.
-	t, err := cloudevents.NewHTTPTransport(tOpts...)
+	// Make a pubsub transport for the CloudEvents client.
+	t, err := cepubsub.New(ctx, tOpts...)
 	if err != nil {
 		return nil, err
 	}
+	ot := &orderedTransport{
+		Transport:    t,
+		client:       client,
+		project:      a.topicProject(),
+		topic:        a.Topic,
+		subscription: a.Subscription,
+		codec:        &cepubsub.Codec{Encoding: t.Encoding},
+
+		maxOutstandingMessages: a.MaxOutstandingMessages,
+	}
+	if a.LiteLocation != "" {
+		ot.liteSubscription = gpubsublite.SubscriptionPath(a.Project, a.LiteLocation, a.Subscription)
+		ot.newLiteSubscriber = a.newLiteSubscriber
+		if ot.newLiteSubscriber == nil {
+			ot.newLiteSubscriber = gpubsublite.NewSubscriber
+		}
+	}
 
 	// Use the transport to make a new CloudEvents client.
-	return cloudevents.NewClient(t)
+	return cloudevents.NewClient(ot,
+		cloudevents.WithConverterFn(a.convert),
+	)
 }
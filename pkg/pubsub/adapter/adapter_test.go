@@ -19,16 +19,20 @@ package adapter
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	kgcptesting "github.com/google/knative-gcp/pkg/testing"
 
 	cloudevents "github.com/cloudevents/sdk-go"
 	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
@@ -38,6 +42,8 @@ import (
 type mockStatsReporter struct {
 	gotArgs *ReportArgs
 	gotCode int
+
+	gotNormalized []string
 }
 
 func (r *mockStatsReporter) ReportEventCount(args *ReportArgs, responseCode int) error {
@@ -46,6 +52,11 @@ func (r *mockStatsReporter) ReportEventCount(args *ReportArgs, responseCode int)
 	return nil
 }
 
+func (r *mockStatsReporter) ReportAttributeNormalized(args *ReportArgs, attribute, action string) error {
+	r.gotNormalized = append(r.gotNormalized, attribute+"/"+action)
+	return nil
+}
+
 func TestStartAdapter(t *testing.T) {
 	t.Skipf("need to fix the error from call to newPubSubClient: %s", `pubsub: google: could not find default credentials. See https://developers.google.com/accounts/docs/application-default-credentials for more information.`)
 	a := Adapter{
@@ -88,11 +99,12 @@ func TestStartAdapter(t *testing.T) {
 
 func TestInboundConvert(t *testing.T) {
 	cases := []struct {
-		name          string
-		ctx           context.Context
-		message       *cepubsub.Message
-		wantMessageFn func() *cloudevents.Event
-		wantErr       bool
+		name           string
+		ctx            context.Context
+		message        *cepubsub.Message
+		wantMessageFn  func() *cloudevents.Event
+		wantNormalized []string
+		wantErr        bool
 	}{{
 		name: "pubsub event",
 		ctx: pubsubcontext.WithTransportContext(
@@ -122,6 +134,34 @@ func TestInboundConvert(t *testing.T) {
 			e.SetExtension("key1", "value1")
 			return &e
 		},
+	}, {
+		name: "pubsub event with invalid attribute",
+		ctx: pubsubcontext.WithTransportContext(
+			context.Background(),
+			pubsubcontext.NewTransportContext(
+				"proj", "topic", "sub", "test",
+				&pubsub.Message{ID: "abc"},
+			),
+		),
+		message: &cepubsub.Message{
+			Data: []byte("some data"),
+			Attributes: map[string]string{
+				"key1": "multi\nline",
+			},
+		},
+		wantMessageFn: func() *cloudevents.Event {
+			e := cloudevents.NewEvent(cloudevents.VersionV1)
+			e.SetID("abc")
+			e.SetSource(v1alpha1.CloudPubSubSourceEventSource("proj", "topic"))
+			e.SetDataContentType("application/octet-stream")
+			e.SetType(v1alpha1.CloudPubSubSourcePublish)
+			e.SetExtension("knativecemode", string(converters.DefaultSendMode))
+			e.Data = []byte("some data")
+			e.DataEncoded = true
+			e.SetExtension("key1", `multi\u000aline`)
+			return &e
+		},
+		wantNormalized: []string{"key1/escaped"},
 	}, {
 		name: "storage event",
 		ctx: pubsubcontext.WithTransportContext(
@@ -177,11 +217,13 @@ func TestInboundConvert(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
+			r := &mockStatsReporter{}
 			a := Adapter{
 				Project:      "proj",
 				Topic:        "top",
 				Subscription: "sub",
 				SendMode:     converters.DefaultSendMode,
+				reporter:     r,
 			}
 			var err error
 			gotEvent, err := a.convert(tc.ctx, tc.message, err)
@@ -191,6 +233,9 @@ func TestInboundConvert(t *testing.T) {
 			if diff := cmp.Diff(tc.wantMessageFn(), gotEvent); diff != "" {
 				t.Errorf("adapter.convert got unexpeceted cloudevents.Event (-want +got) %s", diff)
 			}
+			if diff := cmp.Diff(tc.wantNormalized, r.gotNormalized); diff != "" {
+				t.Errorf("stats reporter got unexpected normalizations (-want +got): %s", diff)
+			}
 		})
 	}
 }
@@ -198,6 +243,8 @@ func TestInboundConvert(t *testing.T) {
 func TestReceive(t *testing.T) {
 	cases := []struct {
 		name           string
+		sendMode       converters.ModeType
+		extensions     map[string]string
 		eventFn        func() cloudevents.Event
 		returnStatus   int
 		returnHeader   http.Header
@@ -238,6 +285,63 @@ func TestReceive(t *testing.T) {
 			ResourceGroup: "channels.messaging.cloud.google.com",
 		},
 		wantReportCode: 200,
+	}, {
+		name:       "success with override extensions",
+		extensions: map[string]string{"foo": "bar"},
+		eventFn: func() cloudevents.Event {
+			e := cloudevents.NewEvent(cloudevents.VersionV1)
+			e.SetSource("source")
+			e.SetType("unit.testing")
+			e.SetID("abc")
+			e.SetDataContentType("application/json")
+			e.SetExtension("foo", "baz")
+			e.Data = []byte(`{"key":"value"}`)
+			return e
+		},
+		returnStatus: http.StatusOK,
+		wantHeader: map[string][]string{
+			"Ce-Id":          {"abc"},
+			"Ce-Source":      {"source"},
+			"Ce-Specversion": {"1.0"},
+			"Ce-Type":        {"unit.testing"},
+			"Ce-Foo":         {"bar"},
+			"Content-Length": {"15"},
+			"Content-Type":   {"application/json"},
+		},
+		wantBody:    []byte(`{"key":"value"}`),
+		wantEventFn: func() *cloudevents.Event { return nil },
+		wantReportArgs: &ReportArgs{
+			EventSource:   "source",
+			EventType:     "unit.testing",
+			ResourceGroup: "channels.messaging.cloud.google.com",
+		},
+		wantReportCode: 200,
+	}, {
+		name:       "success in structured mode",
+		sendMode:   converters.Structured,
+		extensions: map[string]string{"foo": "bar"},
+		eventFn: func() cloudevents.Event {
+			e := cloudevents.NewEvent(cloudevents.VersionV1)
+			e.SetSource("source")
+			e.SetType("unit.testing")
+			e.SetID("abc")
+			e.SetDataContentType("application/octet-stream")
+			e.Data = []byte("hello")
+			return e
+		},
+		returnStatus: http.StatusOK,
+		wantHeader: map[string][]string{
+			"Content-Length": {"154"},
+			"Content-Type":   {"application/cloudevents+json"},
+		},
+		wantBody:    []byte(`{"data_base64":"aGVsbG8=","datacontenttype":"application/octet-stream","foo":"bar","id":"abc","source":"source","specversion":"1.0","type":"unit.testing"}`),
+		wantEventFn: func() *cloudevents.Event { return nil },
+		wantReportArgs: &ReportArgs{
+			EventSource:   "source",
+			EventType:     "unit.testing",
+			ResourceGroup: "channels.messaging.cloud.google.com",
+		},
+		wantReportCode: 200,
 	}, {
 		name: "success without responding event and from source",
 		eventFn: func() cloudevents.Event {
@@ -374,22 +478,24 @@ func TestReceive(t *testing.T) {
 			} else {
 				resourceGroup = "channels.messaging.cloud.google.com"
 			}
+			sendMode := converters.Binary
+			if tc.sendMode != "" {
+				sendMode = tc.sendMode
+			}
 			a := Adapter{
 				Project:       "proj",
 				Topic:         "topic",
 				Subscription:  "sub",
-				SendMode:      converters.Binary,
+				SendMode:      sendMode,
 				reporter:      r,
 				ResourceGroup: resourceGroup,
+				extensions:    tc.extensions,
 			}
 
-			var err error
-			if a.outbound, err = a.newHTTPClient(context.Background(), server.URL); err != nil {
-				t.Fatalf("failed to to set adapter outbound to receive events: %v", err)
-			}
+			a.outbound = newHTTPSender(server.URL, a.SendMode, a.extensions)
 
 			var resp cloudevents.EventResponse
-			err = a.receive(context.Background(), tc.eventFn(), &resp)
+			err := a.receive(context.Background(), tc.eventFn(), &resp)
 
 			if (err != nil) != tc.wantErr {
 				t.Errorf("adapter.receiver got error %v want error %v", err, tc.wantErr)
@@ -425,3 +531,110 @@ func TestReceive(t *testing.T) {
 		})
 	}
 }
+
+func TestReceiveRateLimit(t *testing.T) {
+	var delivered int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		delivered++
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	a := Adapter{
+		Project:       "proj",
+		Topic:         "topic",
+		Subscription:  "sub",
+		SendMode:      converters.Binary,
+		reporter:      &mockStatsReporter{},
+		ResourceGroup: "pubsub.events.cloud.google.com",
+		limiter:       newRateLimiter(1, 2),
+	}
+	a.outbound = newHTTPSender(server.URL, a.SendMode, a.extensions)
+
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetSource("source")
+	event.SetType("unit.testing")
+	event.SetID("abc")
+
+	// The burst is delivered right away.
+	for i := 0; i < 2; i++ {
+		if err := a.receive(context.Background(), event, &cloudevents.EventResponse{}); err != nil {
+			t.Fatalf("receive() = %v", err)
+		}
+	}
+	// The next delivery waits for about a second, longer than the context
+	// allows, and is nacked without reaching the sink.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := a.receive(ctx, event, &cloudevents.EventResponse{}); err == nil {
+		t.Error("receive() = nil, want an error")
+	}
+	if delivered != 2 {
+		t.Errorf("delivered %d events, want 2", delivered)
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	if l := newRateLimiter(0, 10); l != nil {
+		t.Errorf("newRateLimiter(0, 10) = %v, want nil", l)
+	}
+	l := newRateLimiter(10, 0)
+	if l.Limit() != 10 || l.Burst() != 10 {
+		t.Errorf("newRateLimiter(10, 0) = %v/%d, want 10/10", l.Limit(), l.Burst())
+	}
+}
+
+func BenchmarkReceive(b *testing.B) {
+	for _, mode := range []converters.ModeType{converters.Binary, converters.Structured, converters.Push} {
+		for _, eventSize := range kgcptesting.BenchmarkEventSizes {
+			b.Run(fmt.Sprintf("%s %d bytes", mode, eventSize), func(b *testing.B) {
+				runReceiveBenchmark(b, mode, eventSize)
+			})
+		}
+	}
+}
+
+func runReceiveBenchmark(b *testing.B, mode converters.ModeType, eventSize int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		io.Copy(ioutil.Discard, req.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	a := Adapter{
+		Project:       "proj",
+		Topic:         "topic",
+		Subscription:  "sub",
+		SendMode:      mode,
+		reporter:      &mockStatsReporter{},
+		ResourceGroup: "pubsub.events.cloud.google.com",
+		extensions:    map[string]string{"foo": "bar"},
+	}
+	a.outbound = newHTTPSender(server.URL, a.SendMode, a.extensions)
+
+	ctx := pubsubcontext.WithTransportContext(context.Background(), pubsubcontext.NewTransportContext(
+		"proj",
+		"topic",
+		"sub",
+		"test",
+		&pubsub.Message{ID: "abc"},
+	))
+	data := make([]byte, eventSize)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		msg := &cepubsub.Message{
+			Data:       data,
+			Attributes: map[string]string{"key1": "value1"},
+		}
+		event, err := a.convert(ctx, msg, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		var resp cloudevents.EventResponse
+		if err := a.receive(ctx, *event, &resp); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
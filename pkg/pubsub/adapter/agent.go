@@ -0,0 +1,311 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+const defaultAgentRetryInterval = 10 * time.Second
+
+// AgentSubscription is a PullSubscription served by an Agent. It holds the
+// receive adapter settings that differ between PullSubscriptions, the logging,
+// metrics and tracing configuration are the agent's own.
+type AgentSubscription struct {
+	Project                string              `json:"project,omitempty"`
+	Topic                  string              `json:"topic"`
+	TopicProject           string              `json:"topicProject,omitempty"`
+	Subscription           string              `json:"subscription"`
+	Sink                   string              `json:"sink"`
+	Transformer            string              `json:"transformer,omitempty"`
+	AdapterType            string              `json:"adapterType,omitempty"`
+	SendMode               converters.ModeType `json:"sendMode,omitempty"`
+	PushAuthServiceAccount string              `json:"pushAuthServiceAccount,omitempty"`
+	PushAuthAudience       string              `json:"pushAuthAudience,omitempty"`
+	SinkInsecureSkipVerify bool                `json:"sinkInsecureSkipVerify,omitempty"`
+	ExtensionsBase64       string              `json:"extensions,omitempty"`
+	Namespace              string              `json:"namespace"`
+	Name                   string              `json:"name"`
+	ResourceGroup          string              `json:"resourceGroup"`
+	RateLimit              int32               `json:"rateLimit,omitempty"`
+	RateLimitBurst         int32               `json:"rateLimitBurst,omitempty"`
+	LiteLocation           string              `json:"liteLocation,omitempty"`
+}
+
+// Agent runs the receive adapters of many PullSubscriptions in one process,
+// instead of a Deployment per PullSubscription. The adapters of a project
+// share one Pub/Sub client and with it its gRPC connections.
+//
+// The PullSubscriptions are read from Dir, which holds one JSON encoded
+// AgentSubscription per file, e.g. a mounted ConfigMap. The agent watches the
+// directory and starts, restarts and stops adapters as the files change.
+type Agent struct {
+	// Dir is the directory of the AgentSubscription files.
+	Dir string
+
+	// Project is the project of the subscriptions that don't set one.
+	Project string
+
+	// RetryInterval is the time to wait before restarting an adapter that
+	// stopped with an error, e.g. because its subscription doesn't exist yet.
+	RetryInterval time.Duration
+
+	// newClient creates the Pub/Sub client of a project.
+	newClient func(ctx context.Context, project string) (*pubsub.Client, error)
+
+	// run runs the adapter of a subscription until ctx is done.
+	run func(ctx context.Context, s AgentSubscription) error
+
+	// reporter is shared by the adapters, which report their namespace and
+	// name with every event.
+	reporter StatsReporter
+
+	clientsMu sync.Mutex
+	clients   map[string]*pubsub.Client
+
+	// running is only accessed by the goroutine of Start.
+	running map[string]*agentAdapter
+}
+
+// agentAdapter is an adapter started by an Agent.
+type agentAdapter struct {
+	sub    AgentSubscription
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Start starts the adapters of the subscriptions in Dir and keeps them in
+// sync with the directory until ctx is done. Note: Only call once, not thread
+// safe.
+func (a *Agent) Start(ctx context.Context) error {
+	if a.RetryInterval <= 0 {
+		a.RetryInterval = defaultAgentRetryInterval
+	}
+	if a.newClient == nil {
+		a.newClient = func(ctx context.Context, project string) (*pubsub.Client, error) {
+			return pubsub.NewClient(ctx, project, endpoints.PubSub(ctx)...)
+		}
+	}
+	if a.run == nil {
+		a.run = a.runAdapter
+	}
+	if a.reporter == nil {
+		a.reporter = NewStatsReporter()
+	}
+	a.clients = make(map[string]*pubsub.Client)
+	a.running = make(map[string]*agentAdapter)
+	defer a.stopAll()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	// Watch before the first sync so that no change is missed in between.
+	if err := watcher.Add(a.Dir); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", a.Dir, err)
+	}
+
+	a.sync(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			// Any change may have replaced the files of a mounted ConfigMap,
+			// which are symlinks into a hidden directory.
+			a.sync(ctx)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("failed to watch %s: %w", a.Dir, err)
+		}
+	}
+}
+
+// sync starts, restarts and stops adapters to match the subscriptions in Dir.
+func (a *Agent) sync(ctx context.Context) {
+	subs, err := readAgentSubscriptions(ctx, a.Dir)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to read the agent subscriptions", zap.Error(err))
+		return
+	}
+
+	var stopped []*agentAdapter
+	for key, r := range a.running {
+		if s, ok := subs[key]; !ok || s != r.sub {
+			r.cancel()
+			stopped = append(stopped, r)
+			delete(a.running, key)
+		}
+	}
+	for _, r := range stopped {
+		<-r.done
+	}
+	for key, s := range subs {
+		if _, ok := a.running[key]; !ok {
+			a.running[key] = a.start(ctx, s)
+		}
+	}
+}
+
+// start runs the adapter of s until it is stopped, restarting it whenever it
+// stops with an error.
+func (a *Agent) start(ctx context.Context, s AgentSubscription) *agentAdapter {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &agentAdapter{sub: s, cancel: cancel, done: make(chan struct{})}
+	logger := logging.FromContext(ctx).With(zap.String("namespace", s.Namespace), zap.String("name", s.Name))
+	ctx = logging.WithLogger(ctx, logger)
+
+	go func() {
+		defer close(r.done)
+		for {
+			err := a.run(ctx, s)
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Desugar().Error("Receive adapter stopped", zap.Error(err), zap.Duration("restartAfter", a.RetryInterval))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(a.RetryInterval):
+			}
+		}
+	}()
+	return r
+}
+
+// stopAll stops the adapters and closes the Pub/Sub clients.
+func (a *Agent) stopAll() {
+	for _, r := range a.running {
+		r.cancel()
+	}
+	for key, r := range a.running {
+		<-r.done
+		delete(a.running, key)
+	}
+
+	a.clientsMu.Lock()
+	defer a.clientsMu.Unlock()
+	for project, c := range a.clients {
+		c.Close()
+		delete(a.clients, project)
+	}
+}
+
+func (a *Agent) runAdapter(ctx context.Context, s AgentSubscription) error {
+	if s.Project == "" {
+		s.Project = a.Project
+	}
+	client, err := a.client(s.Project)
+	if err != nil {
+		return fmt.Errorf("failed to create the Pub/Sub client: %w", err)
+	}
+	return s.adapter(client, a.reporter).Start(ctx)
+}
+
+// client returns the Pub/Sub client of project, creating it on first use.
+func (a *Agent) client(project string) (*pubsub.Client, error) {
+	a.clientsMu.Lock()
+	defer a.clientsMu.Unlock()
+	if c, ok := a.clients[project]; ok {
+		return c, nil
+	}
+	// The client outlives the adapter that creates it, so it must not be bound
+	// to the adapter's context.
+	c, err := a.newClient(context.Background(), project)
+	if err != nil {
+		return nil, err
+	}
+	a.clients[project] = c
+	return c, nil
+}
+
+// adapter returns the receive adapter of s that pulls with client.
+func (s *AgentSubscription) adapter(client *pubsub.Client, reporter StatsReporter) *Adapter {
+	return &Adapter{
+		Project:                s.Project,
+		Sink:                   s.Sink,
+		Transformer:            s.Transformer,
+		AdapterType:            s.AdapterType,
+		Topic:                  s.Topic,
+		TopicProject:           s.TopicProject,
+		Subscription:           s.Subscription,
+		ExtensionsBase64:       s.ExtensionsBase64,
+		SendMode:               s.SendMode,
+		PushAuthServiceAccount: s.PushAuthServiceAccount,
+		PushAuthAudience:       s.PushAuthAudience,
+		SinkInsecureSkipVerify: s.SinkInsecureSkipVerify,
+		Namespace:              s.Namespace,
+		Name:                   s.Name,
+		ResourceGroup:          s.ResourceGroup,
+		RateLimit:              int(s.RateLimit),
+		RateLimitBurst:         int(s.RateLimitBurst),
+		LiteLocation:           s.LiteLocation,
+		client:                 client,
+		reporter:               reporter,
+	}
+}
+
+// readAgentSubscriptions reads the subscriptions in dir by file name. Files
+// that can't be read or parsed are logged and skipped, so that one bad entry
+// doesn't stop the adapters of the others.
+func readAgentSubscriptions(ctx context.Context, dir string) (map[string]AgentSubscription, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	subs := make(map[string]AgentSubscription, len(files))
+	for _, f := range files {
+		// Skip the hidden files and directories that back a mounted
+		// ConfigMap, e.g. "..data".
+		if f.IsDir() || strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, f.Name()))
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to read agent subscription", zap.String("file", f.Name()), zap.Error(err))
+			continue
+		}
+		var s AgentSubscription
+		if err := json.Unmarshal(b, &s); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to parse agent subscription", zap.String("file", f.Name()), zap.Error(err))
+			continue
+		}
+		subs[f.Name()] = s
+	}
+	return subs, nil
+}
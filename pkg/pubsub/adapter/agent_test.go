@@ -0,0 +1,249 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// writeAgentSubscription replaces the file of s in dir atomically, like the
+// kubelet updates a mounted ConfigMap.
+func writeAgentSubscription(t *testing.T, dir string, s AgentSubscription) {
+	t.Helper()
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := filepath.Join(dir, ".tmp")
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, s.Name)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadAgentSubscriptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := AgentSubscription{
+		Topic:        "topic",
+		Subscription: "sub",
+		Sink:         "http://sink",
+		SendMode:     "binary",
+		Namespace:    "ns",
+		Name:         "ps",
+	}
+	writeAgentSubscription(t, dir, want)
+	if err := ioutil.WriteFile(filepath.Join(dir, "invalid"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "..data"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "..2020_06_23"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readAgentSubscriptions(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("readAgentSubscriptions() = %v", err)
+	}
+	if diff := cmp.Diff(map[string]AgentSubscription{"ps": want}, got); diff != "" {
+		t.Errorf("readAgentSubscriptions() (-want,+got): %v", diff)
+	}
+}
+
+func TestAgentSync(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	type runEvent struct {
+		name    string
+		sink    string
+		stopped bool
+	}
+	events := make(chan runEvent, 10)
+	failed := false
+	agent := &Agent{
+		Dir:           dir,
+		RetryInterval: 10 * time.Millisecond,
+		run: func(ctx context.Context, s AgentSubscription) error {
+			events <- runEvent{name: s.Name, sink: s.Sink}
+			if s.Name == "failing" && !failed {
+				failed = true
+				return errors.New("subscription not found")
+			}
+			<-ctx.Done()
+			events <- runEvent{name: s.Name, sink: s.Sink, stopped: true}
+			return nil
+		},
+	}
+	expect := func(want ...runEvent) {
+		t.Helper()
+		var got []runEvent
+		for range want {
+			select {
+			case e := <-events:
+				got = append(got, e)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("timed out waiting for %v, got %v", want, got)
+			}
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(runEvent{})); diff != "" {
+			t.Fatalf("unexpected adapter runs (-want,+got): %v", diff)
+		}
+	}
+
+	writeAgentSubscription(t, dir, AgentSubscription{Name: "a", Sink: "http://a"})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- agent.Start(ctx)
+	}()
+	expect(runEvent{name: "a", sink: "http://a"})
+
+	writeAgentSubscription(t, dir, AgentSubscription{Name: "a", Sink: "http://a2"})
+	expect(runEvent{name: "a", sink: "http://a", stopped: true}, runEvent{name: "a", sink: "http://a2"})
+
+	writeAgentSubscription(t, dir, AgentSubscription{Name: "failing", Sink: "http://f"})
+	expect(runEvent{name: "failing", sink: "http://f"}, runEvent{name: "failing", sink: "http://f"})
+
+	if err := os.Remove(filepath.Join(dir, "a")); err != nil {
+		t.Fatal(err)
+	}
+	expect(runEvent{name: "a", sink: "http://a2", stopped: true})
+
+	cancel()
+	expect(runEvent{name: "failing", sink: "http://f", stopped: true})
+	if err := <-errCh; err != nil {
+		t.Errorf("Start() = %v", err)
+	}
+}
+
+func TestAgentSharesClient(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var clients int
+	newClient := func(ctx context.Context, project string) (*pubsub.Client, error) {
+		clients++
+		return pubsub.NewClient(ctx, project, option.WithGRPCConn(conn))
+	}
+
+	// The test client only publishes, the agent closes its own clients.
+	publisher, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan string, 2)
+	dir, err := ioutil.TempDir("", "agent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var topics []*pubsub.Topic
+	for _, name := range []string{"a", "b"} {
+		name := name
+		topic, err := publisher.CreateTopic(ctx, "topic-"+name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer topic.Stop()
+		topics = append(topics, topic)
+		if _, err := publisher.CreateSubscription(ctx, "sub-"+name, pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+			t.Fatal(err)
+		}
+		sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- name
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer sink.Close()
+		writeAgentSubscription(t, dir, AgentSubscription{
+			Project:      "test-project",
+			Topic:        "topic-" + name,
+			Subscription: "sub-" + name,
+			Sink:         sink.URL,
+			SendMode:     "binary",
+			Namespace:    "ns",
+			Name:         name,
+		})
+	}
+
+	agent := &Agent{
+		Dir:       dir,
+		newClient: newClient,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- agent.Start(ctx)
+	}()
+
+	for _, topic := range topics {
+		if _, err := topic.Publish(ctx, &pubsub.Message{Data: []byte("data")}).Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := map[string]bool{}
+	for len(got) < 2 {
+		select {
+		case name := <-received:
+			got[name] = true
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("Start() = %v", err)
+	}
+	if clients != 1 {
+		t.Errorf("created %d Pub/Sub clients, want 1", clients)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+const (
+	// ConfigVersion is the version of the Config schema.
+	ConfigVersion = "v1"
+
+	// ConfigEnvKey is the environment variable holding the JSON encoded
+	// Config of the receive adapter.
+	ConfigEnvKey = "K_RECEIVE_ADAPTER_CONFIG"
+	// ConfigFileEnvKey is the environment variable holding the path of a
+	// file with the JSON encoded Config, e.g. a projected volume. It is only
+	// read if ConfigEnvKey is not set.
+	ConfigFileEnvKey = "K_RECEIVE_ADAPTER_CONFIG_FILE"
+
+	// DefaultResourceGroup is the resource group reported in the metrics of
+	// the adapters whose Config doesn't set one.
+	DefaultResourceGroup = "pullsubscriptions.internal.events.cloud.google.com"
+)
+
+// Config is the versioned configuration of a receive adapter, generated by
+// the PullSubscription reconciler and passed to the adapter as a single JSON
+// document rather than an environment variable per setting. The settings of
+// the subscription are the same as the ones of an AgentSubscription, the rest
+// configure the adapter process.
+//
+// Unknown fields are rejected, so that a setting the adapter doesn't
+// understand, e.g. because its image is older than the reconciler, fails the
+// adapter instead of being ignored.
+type Config struct {
+	// Version is the version of the schema, ConfigVersion.
+	Version string `json:"version"`
+
+	AgentSubscription
+
+	// SinkCABundlePath is the path of a file of PEM encoded CA certificates
+	// trusted to verify the certificate of the sink.
+	SinkCABundlePath string `json:"sinkCABundlePath,omitempty"`
+	// DrainPort is the port of the endpoint the preStop hook calls to drain
+	// the adapter. The endpoint is not served if it is 0.
+	DrainPort int `json:"drainPort,omitempty"`
+	// MaxOutstandingMessages is the max number of messages received but not
+	// yet acknowledged. The Pub/Sub client default is used if it is 0.
+	MaxOutstandingMessages int `json:"maxOutstandingMessages,omitempty"`
+
+	// MetricsConfig is a JSON string of metrics.ExporterOptions.
+	MetricsConfig string `json:"metricsConfig,omitempty"`
+	// LoggingConfig is a JSON string of logging.Config.
+	LoggingConfig string `json:"loggingConfig,omitempty"`
+	// TracingConfig is a JSON string of tracing.Config.
+	TracingConfig string `json:"tracingConfig,omitempty"`
+}
+
+// ParseConfig decodes a JSON encoded Config, sets its defaults and validates
+// it.
+func ParseConfig(b []byte) (*Config, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	var c Config
+	if err := d.Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to decode the receive adapter config: %w", err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("failed to decode the receive adapter config: unexpected data after the config")
+	}
+	c.SetDefaults()
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid receive adapter config: %w", err)
+	}
+	return &c, nil
+}
+
+// ConfigFromEnv returns the Config set in the environment of the adapter,
+// either in ConfigEnvKey or in the file at ConfigFileEnvKey. It returns nil if
+// neither is set, in which case the adapter reads the legacy environment
+// variables instead.
+func ConfigFromEnv() (*Config, error) {
+	return configFromEnv(os.LookupEnv)
+}
+
+func configFromEnv(lookup func(string) (string, bool)) (*Config, error) {
+	if v, ok := lookup(ConfigEnvKey); ok {
+		return ParseConfig([]byte(v))
+	}
+	path, ok := lookup(ConfigFileEnvKey)
+	if !ok {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the receive adapter config: %w", err)
+	}
+	return ParseConfig(b)
+}
+
+// SetDefaults sets the defaults of the unset optional settings.
+func (c *Config) SetDefaults() {
+	if c.SendMode == "" {
+		c.SendMode = converters.DefaultSendMode
+	}
+	if c.ResourceGroup == "" {
+		c.ResourceGroup = DefaultResourceGroup
+	}
+}
+
+// Validate returns an error describing the first invalid setting of c.
+func (c *Config) Validate() error {
+	if c.Version != ConfigVersion {
+		return fmt.Errorf("unsupported version %q, expected %q", c.Version, ConfigVersion)
+	}
+	for _, f := range []struct{ name, value string }{
+		{"topic", c.Topic},
+		{"subscription", c.Subscription},
+		{"namespace", c.Namespace},
+		{"name", c.Name},
+	} {
+		if f.value == "" {
+			return fmt.Errorf("missing field %q", f.name)
+		}
+	}
+	if err := validateURL(c.Sink); err != nil {
+		return fmt.Errorf("invalid field %q: %w", "sink", err)
+	}
+	if c.Transformer != "" {
+		if err := validateURL(c.Transformer); err != nil {
+			return fmt.Errorf("invalid field %q: %w", "transformer", err)
+		}
+	}
+	switch c.SendMode {
+	case converters.Binary, converters.Structured, converters.Push:
+	default:
+		return fmt.Errorf("invalid field %q: unknown send mode %q", "sendMode", c.SendMode)
+	}
+	if c.PushAuthServiceAccount != "" && c.SendMode != converters.Push {
+		return fmt.Errorf("invalid field %q: only supported by the %q send mode", "pushAuthServiceAccount", converters.Push)
+	}
+	if c.RateLimit < 0 {
+		return fmt.Errorf("invalid field %q: %d is negative", "rateLimit", c.RateLimit)
+	}
+	if c.RateLimitBurst < 0 {
+		return fmt.Errorf("invalid field %q: %d is negative", "rateLimitBurst", c.RateLimitBurst)
+	}
+	if c.MaxOutstandingMessages < 0 {
+		return fmt.Errorf("invalid field %q: %d is negative", "maxOutstandingMessages", c.MaxOutstandingMessages)
+	}
+	if c.LiteLocation != "" {
+		if err := gpubsublite.ValidateZone(c.LiteLocation); err != nil {
+			return fmt.Errorf("invalid field %q: %w", "liteLocation", err)
+		}
+	}
+	if c.DrainPort < 0 || c.DrainPort > 65535 {
+		return fmt.Errorf("invalid field %q: %d is not a port", "drainPort", c.DrainPort)
+	}
+	return nil
+}
+
+// validateURL returns an error if s is not an absolute URL.
+func validateURL(s string) error {
+	if s == "" {
+		return errors.New("missing URL")
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute URL", s)
+	}
+	return nil
+}
+
+// Adapter returns the receive adapter configured by c.
+func (c *Config) Adapter() *Adapter {
+	a := c.AgentSubscription.adapter(nil, nil)
+	a.SinkCABundlePath = c.SinkCABundlePath
+	a.DrainPort = c.DrainPort
+	a.MaxOutstandingMessages = c.MaxOutstandingMessages
+	a.MetricsConfigJson = c.MetricsConfig
+	a.LoggingConfigJson = c.LoggingConfig
+	a.TracingConfigJson = c.TracingConfig
+	return a
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+const minimalConfig = `{"version":"v1","topic":"t","subscription":"s","sink":"http://sink","namespace":"ns","name":"ps"}`
+
+func TestParseConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config  string
+		want    *Config
+		wantErr string
+	}{
+		"minimal": {
+			config: minimalConfig,
+			want: &Config{
+				Version: ConfigVersion,
+				AgentSubscription: AgentSubscription{
+					Topic:         "t",
+					Subscription:  "s",
+					Sink:          "http://sink",
+					SendMode:      converters.Binary,
+					Namespace:     "ns",
+					Name:          "ps",
+					ResourceGroup: DefaultResourceGroup,
+				},
+			},
+		},
+		"full": {
+			config: `{"version":"v1","project":"p","topic":"t","topicProject":"tp","subscription":"s",
+				"sink":"https://sink","transformer":"http://transformer","sendMode":"push",
+				"pushAuthServiceAccount":"push@p.iam.gserviceaccount.com","namespace":"ns","name":"ps",
+				"resourceGroup":"rg","rateLimit":100,"rateLimitBurst":500,"liteLocation":"us-central1-a","sinkCABundlePath":"/ca.crt","drainPort":8081,
+				"loggingConfig":"{}"}`,
+			want: &Config{
+				Version: ConfigVersion,
+				AgentSubscription: AgentSubscription{
+					Project:                "p",
+					Topic:                  "t",
+					TopicProject:           "tp",
+					Subscription:           "s",
+					Sink:                   "https://sink",
+					Transformer:            "http://transformer",
+					SendMode:               converters.Push,
+					PushAuthServiceAccount: "push@p.iam.gserviceaccount.com",
+					Namespace:              "ns",
+					Name:                   "ps",
+					ResourceGroup:          "rg",
+					RateLimit:              100,
+					RateLimitBurst:         500,
+					LiteLocation:           "us-central1-a",
+				},
+				SinkCABundlePath: "/ca.crt",
+				DrainPort:        8081,
+				LoggingConfig:    "{}",
+			},
+		},
+		"not JSON": {
+			config:  "SINK_URI=http://sink",
+			wantErr: "failed to decode",
+		},
+		"unknown field": {
+			config:  `{"version":"v1","topic":"t","subscription":"s","sink":"http://sink","namespace":"ns","name":"ps","retries":3}`,
+			wantErr: `unknown field "retries"`,
+		},
+		"trailing data": {
+			config:  minimalConfig + minimalConfig,
+			wantErr: "unexpected data",
+		},
+		"missing version": {
+			config:  `{"topic":"t","subscription":"s","sink":"http://sink","namespace":"ns","name":"ps"}`,
+			wantErr: `unsupported version ""`,
+		},
+		"unsupported version": {
+			config:  strings.Replace(minimalConfig, `"v1"`, `"v2"`, 1),
+			wantErr: `unsupported version "v2"`,
+		},
+		"missing subscription": {
+			config:  strings.Replace(minimalConfig, `"subscription":"s",`, "", 1),
+			wantErr: `missing field "subscription"`,
+		},
+		"relative sink": {
+			config:  strings.Replace(minimalConfig, "http://sink", "/sink", 1),
+			wantErr: `invalid field "sink"`,
+		},
+		"invalid transformer": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","transformer":"transformer",`, 1),
+			wantErr: `invalid field "transformer"`,
+		},
+		"unknown send mode": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","sendMode":"batch",`, 1),
+			wantErr: `unknown send mode "batch"`,
+		},
+		"push auth without push mode": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","pushAuthServiceAccount":"push@p.iam.gserviceaccount.com",`, 1),
+			wantErr: `invalid field "pushAuthServiceAccount"`,
+		},
+		"negative rate limit": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","rateLimit":-1,`, 1),
+			wantErr: `invalid field "rateLimit"`,
+		},
+		"negative max outstanding messages": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","maxOutstandingMessages":-1,`, 1),
+			wantErr: `invalid field "maxOutstandingMessages"`,
+		},
+		"invalid lite location": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","liteLocation":"us-central1",`, 1),
+			wantErr: `invalid field "liteLocation"`,
+		},
+		"invalid drain port": {
+			config:  strings.Replace(minimalConfig, `"v1",`, `"v1","drainPort":70000,`, 1),
+			wantErr: `invalid field "drainPort"`,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := ParseConfig([]byte(tc.config))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("ParseConfig() = %v, want an error containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseConfig() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected config (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(path, []byte(strings.Replace(minimalConfig, `"ps"`, `"from-file"`, 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		env      map[string]string
+		wantName string
+		wantErr  bool
+	}{
+		"not set": {},
+		"env": {
+			env:      map[string]string{ConfigEnvKey: minimalConfig, ConfigFileEnvKey: path},
+			wantName: "ps",
+		},
+		"file": {
+			env:      map[string]string{ConfigFileEnvKey: path},
+			wantName: "from-file",
+		},
+		"missing file": {
+			env:     map[string]string{ConfigFileEnvKey: filepath.Join(dir, "missing.json")},
+			wantErr: true,
+		},
+		"invalid env": {
+			env:     map[string]string{ConfigEnvKey: ""},
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := configFromEnv(func(key string) (string, bool) {
+				v, ok := tc.env[key]
+				return v, ok
+			})
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("configFromEnv() = %v, wantErr %v", err, tc.wantErr)
+			}
+			var name string
+			if got != nil {
+				name = got.Name
+			}
+			if name != tc.wantName {
+				t.Errorf("configFromEnv() name = %q, want %q", name, tc.wantName)
+			}
+		})
+	}
+}
+
+func TestConfigAdapter(t *testing.T) {
+	c, err := ParseConfig([]byte(`{"version":"v1","topic":"t","subscription":"s","sink":"http://sink","namespace":"ns",
+		"name":"ps","sinkCABundlePath":"/ca.crt","drainPort":8081,"maxOutstandingMessages":4000,"metricsConfig":"m",
+		"loggingConfig":"l","tracingConfig":"tr"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Adapter{
+		Topic:                  "t",
+		Subscription:           "s",
+		Sink:                   "http://sink",
+		SendMode:               converters.Binary,
+		Namespace:              "ns",
+		Name:                   "ps",
+		ResourceGroup:          DefaultResourceGroup,
+		SinkCABundlePath:       "/ca.crt",
+		DrainPort:              8081,
+		MaxOutstandingMessages: 4000,
+		MetricsConfigJson:      "m",
+		LoggingConfigJson:      "l",
+		TracingConfigJson:      "tr",
+	}
+	if diff := cmp.Diff(want, c.Adapter(), cmpopts.IgnoreUnexported(Adapter{})); diff != "" {
+		t.Errorf("unexpected adapter (-want, +got) = %v", diff)
+	}
+}
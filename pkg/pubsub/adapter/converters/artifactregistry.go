@@ -0,0 +1,114 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	CloudArtifactRegistryConverter = "com.google.cloud.artifactregistry"
+)
+
+// registryNotification is the payload Artifact Registry and Container
+// Registry publish to the gcr topic.
+type registryNotification struct {
+	// Action is INSERT or DELETE.
+	Action string `json:"action"`
+	// Digest is the image name with its digest, e.g.
+	// gcr.io/my-project/my-image@sha256:...
+	Digest string `json:"digest"`
+	// Tag is the image name with its tag, e.g. gcr.io/my-project/my-image:latest.
+	Tag string `json:"tag"`
+}
+
+func convertCloudArtifactRegistry(ctx context.Context, msg *cepubsub.Message, sendMode ModeType) (*cloudevents.Event, error) {
+	tx := pubsubcontext.TransportContextFrom(ctx)
+
+	var notification registryNotification
+	if err := json.Unmarshal(msg.Data, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode registry notification: %w", err)
+	}
+	var eventType string
+	switch notification.Action {
+	case "INSERT":
+		eventType = v1beta1.CloudArtifactRegistrySourceImagePushed
+	case "DELETE":
+		eventType = v1beta1.CloudArtifactRegistrySourceImageDeleted
+	default:
+		return nil, fmt.Errorf("received registry notification had unknown action %q", notification.Action)
+	}
+	var repository, digest, tag string
+	if notification.Digest != "" {
+		repository, digest = splitImageDigest(notification.Digest)
+	}
+	if notification.Tag != "" {
+		repository, tag = splitImageTag(notification.Tag)
+	}
+	if repository == "" {
+		return nil, fmt.Errorf("received registry notification did not have digest or tag")
+	}
+
+	// Make a new event and convert the message payload.
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(tx.ID)
+	event.SetTime(tx.PublishTime)
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetSource(v1beta1.CloudArtifactRegistrySourceEventSource(tx.Project))
+	event.SetSubject(repository)
+	event.SetType(eventType)
+	event.SetExtension(v1beta1.CloudArtifactRegistrySourceRepository, repository)
+	if digest != "" {
+		event.SetExtension(v1beta1.CloudArtifactRegistrySourceDigest, digest)
+	}
+	if tag != "" {
+		event.SetExtension(v1beta1.CloudArtifactRegistrySourceTag, tag)
+	}
+
+	// Set the mode to be an extension attribute.
+	event.SetExtension("knativecemode", string(sendMode))
+	event.Data = msg.Data
+	event.DataEncoded = true
+	return &event, nil
+}
+
+// splitImageDigest splits an image reference of the form repository@digest.
+func splitImageDigest(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// splitImageTag splits an image reference of the form repository:tag. The
+// registry host may contain a port, so only a colon after the last slash
+// starts the tag.
+func splitImageTag(ref string) (string, string) {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	imagePushed       = `{"action":"INSERT","digest":"us-docker.pkg.dev/testproject/repo/app@sha256:6ec128e26cd5","tag":"us-docker.pkg.dev/testproject/repo/app:v1"}`
+	imageDigestPushed = `{"action":"INSERT","digest":"localhost:5000/app@sha256:6ec128e26cd5"}`
+	imageTagDeleted   = `{"action":"DELETE","tag":"gcr.io/testproject/app:v1"}`
+)
+
+func TestConvertCloudArtifactRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     *cepubsub.Message
+		wantEventFn func() *cloudevents.Event
+		wantErr     bool
+	}{{
+		name: "pushed with tag",
+		message: &cepubsub.Message{
+			Data: []byte(imagePushed),
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return artifactRegistryCloudEvent(imagePushed, v1beta1.CloudArtifactRegistrySourceImagePushed, "us-docker.pkg.dev/testproject/repo/app", "sha256:6ec128e26cd5", "v1")
+		},
+	}, {
+		name: "pushed without tag, registry with port",
+		message: &cepubsub.Message{
+			Data: []byte(imageDigestPushed),
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return artifactRegistryCloudEvent(imageDigestPushed, v1beta1.CloudArtifactRegistrySourceImagePushed, "localhost:5000/app", "sha256:6ec128e26cd5", "")
+		},
+	}, {
+		name: "tag deleted",
+		message: &cepubsub.Message{
+			Data: []byte(imageTagDeleted),
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return artifactRegistryCloudEvent(imageTagDeleted, v1beta1.CloudArtifactRegistrySourceImageDeleted, "gcr.io/testproject/app", "", "v1")
+		},
+	}, {
+		name: "unknown action",
+		message: &cepubsub.Message{
+			Data: []byte(`{"action":"UPDATE","tag":"gcr.io/testproject/app:v1"}`),
+		},
+		wantErr: true,
+	}, {
+		name: "no digest or tag",
+		message: &cepubsub.Message{
+			Data: []byte(`{"action":"INSERT"}`),
+		},
+		wantErr: true,
+	}, {
+		name: "not json",
+		message: &cepubsub.Message{
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := pubsubcontext.WithTransportContext(context.TODO(), pubsubcontext.NewTransportContext(
+				"testproject",
+				"testtopic",
+				"testsubscription",
+				"testmethod",
+				&pubsub.Message{
+					ID: "id",
+				},
+			))
+
+			gotEvent, err := Convert(ctx, test.message, Binary, CloudArtifactRegistryConverter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("converters.convertCloudArtifactRegistry got error %v want error=%v", err, test.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+					t.Errorf("converters.convertCloudArtifactRegistry got unexpeceted cloudevents.Event (-want +got) %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func artifactRegistryCloudEvent(data, eventType, repository, digest, tag string) *cloudevents.Event {
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID("id")
+	e.SetSource(v1beta1.CloudArtifactRegistrySourceEventSource("testproject"))
+	e.SetSubject(repository)
+	e.SetDataContentType(cloudevents.ApplicationJSON)
+	e.SetType(eventType)
+	e.SetExtension("repository", repository)
+	if digest != "" {
+		e.SetExtension("digest", digest)
+	}
+	if tag != "" {
+		e.SetExtension("tag", tag)
+	}
+	e.SetExtension("knativecemode", string(Binary))
+	e.Data = []byte(data)
+	e.DataEncoded = true
+	return &e
+}
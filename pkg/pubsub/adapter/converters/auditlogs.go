@@ -45,9 +45,10 @@ const (
 
 	parentResourcePattern = `^(:?projects|organizations|billingAccounts|folders)/[^/]+`
 
-	serviceNameExtension  = "servicename"
-	methodNameExtension   = "methodname"
-	resourceNameExtension = "resourcename"
+	serviceNameExtension    = "servicename"
+	methodNameExtension     = "methodname"
+	resourceNameExtension   = "resourcename"
+	principalEmailExtension = "principalemail"
 )
 
 var (
@@ -143,6 +144,11 @@ func convertCloudAuditLogs(ctx context.Context, msg *cepubsub.Message, sendMode
 			event.SetExtension(serviceNameExtension, proto.ServiceName)
 			event.SetExtension(methodNameExtension, proto.MethodName)
 			event.SetExtension(resourceNameExtension, proto.ResourceName)
+			// The principal is omitted from some audit logs, e.g. when the
+			// caller is anonymous or it's redacted for privacy.
+			if email := proto.GetAuthenticationInfo().GetPrincipalEmail(); email != "" {
+				event.SetExtension(principalEmailExtension, email)
+			}
 		default:
 			return nil, fmt.Errorf("unhandled proto payload type: %T", proto)
 		}
@@ -45,6 +45,9 @@ func TestConvertAuditLog(t *testing.T) {
 		ServiceName:  "test-service-name",
 		MethodName:   "test-method-name",
 		ResourceName: "test-resource-name",
+		AuthenticationInfo: &auditpb.AuthenticationInfo{
+			PrincipalEmail: "test@example.com",
+		},
 	}
 	payload, err := ptypes.MarshalAny(&auditLog)
 	if err != nil {
@@ -109,6 +112,47 @@ func TestConvertAuditLog(t *testing.T) {
 			}
 		}
 	}
+	wantExtensions := map[string]interface{}{
+		"servicename":    "test-service-name",
+		"methodname":     "test-method-name",
+		"resourcename":   "test-resource-name",
+		"principalemail": "test@example.com",
+	}
+	if diff := cmp.Diff(wantExtensions, e.Extensions()); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestConvertAuditLogWithoutPrincipal(t *testing.T) {
+	auditLog := auditpb.AuditLog{
+		ServiceName:  "test-service-name",
+		MethodName:   "test-method-name",
+		ResourceName: "test-resource-name",
+	}
+	payload, err := ptypes.MarshalAny(&auditLog)
+	if err != nil {
+		t.Fatalf("Failed to marshal proto payload: %v", err)
+	}
+	logEntry := logpb.LogEntry{
+		InsertId:  insertID,
+		LogName:   logName,
+		Timestamp: ptypes.TimestampNow(),
+		Payload: &logpb.LogEntry_ProtoPayload{
+			ProtoPayload: payload,
+		},
+	}
+	var buf bytes.Buffer
+	if err := new(jsonpb.Marshaler).Marshal(&buf, &logEntry); err != nil {
+		t.Fatalf("Failed to marshal AuditLog pb: %v", err)
+	}
+	msg := cepubsub.Message{
+		Data: buf.Bytes(),
+	}
+
+	e, err := Convert(context.Background(), &msg, "", CloudAuditLogsConverter)
+	if err != nil {
+		t.Fatalf("conversion failed: %v", err)
+	}
 	wantExtensions := map[string]interface{}{
 		"servicename":  "test-service-name",
 		"methodname":   "test-method-name",
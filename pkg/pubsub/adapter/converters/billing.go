@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	. "github.com/cloudevents/sdk-go/pkg/cloudevents"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	CloudBillingBudgetConverter = "com.google.cloud.billing.budget"
+)
+
+// billingBudgetNotification is the part of the Cloud Billing budget
+// notification payload the converter relies on.
+type billingBudgetNotification struct {
+	// AlertThresholdExceeded is only set once the cost crossed one of the
+	// thresholds of the budget, e.g. 0.5 for 50% of the budget amount.
+	AlertThresholdExceeded *float64 `json:"alertThresholdExceeded"`
+}
+
+func convertCloudBillingBudget(ctx context.Context, msg *cepubsub.Message, sendMode ModeType) (*cloudevents.Event, error) {
+	tx := pubsubcontext.TransportContextFrom(ctx)
+
+	billingAccount, ok := msg.Attributes[v1beta1.CloudBillingBudgetSourceBillingAccountID]
+	if !ok {
+		return nil, fmt.Errorf("received budget notification did not have %s attribute", v1beta1.CloudBillingBudgetSourceBillingAccountID)
+	}
+	budget, ok := msg.Attributes[v1beta1.CloudBillingBudgetSourceBudgetID]
+	if !ok {
+		return nil, fmt.Errorf("received budget notification did not have %s attribute", v1beta1.CloudBillingBudgetSourceBudgetID)
+	}
+	var notification billingBudgetNotification
+	if err := json.Unmarshal(msg.Data, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode budget notification: %w", err)
+	}
+
+	// Make a new event and convert the message payload.
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(tx.ID)
+	event.SetTime(tx.PublishTime)
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetSource(v1beta1.CloudBillingBudgetSourceEventSource(billingAccount, budget))
+	// Budgets notify several times a day, threshold crossings are the ones
+	// most consumers are interested in.
+	if notification.AlertThresholdExceeded != nil {
+		event.SetType(v1beta1.CloudBillingBudgetSourceThresholdExceeded)
+	} else {
+		event.SetType(v1beta1.CloudBillingBudgetSourceUpdated)
+	}
+
+	// Set the mode to be an extension attribute.
+	event.SetExtension("knativecemode", string(sendMode))
+	event.Data = msg.Data
+	event.DataEncoded = true
+	// Attributes are extensions.
+	if msg.Attributes != nil && len(msg.Attributes) > 0 {
+		for k, v := range msg.Attributes {
+			// CloudEvents v1.0 attributes MUST consist of lower-case letters ('a' to 'z') or digits ('0' to '9') as per
+			// the spec. It's not even possible for a conformant transport to allow non-base36 characters.
+			// Note `SetExtension` will make it lowercase so only `IsAlphaNumeric` needs to be checked here.
+			if IsAlphaNumeric(k) {
+				event.SetExtension(k, v)
+			}
+		}
+	}
+	return &event, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	budgetThresholdExceeded = `{"budgetDisplayName":"team","alertThresholdExceeded":0.5,"costAmount":51.2,"costIntervalStart":"2020-09-01T07:00:00Z","budgetAmount":100,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"USD"}`
+	budgetUpdated           = `{"budgetDisplayName":"team","costAmount":12.5,"costIntervalStart":"2020-09-01T07:00:00Z","budgetAmount":100,"budgetAmountType":"SPECIFIED_AMOUNT","currencyCode":"USD"}`
+)
+
+func TestConvertCloudBillingBudget(t *testing.T) {
+	attributes := map[string]string{
+		"billingAccountId": "012345-6789AB-CDEF01",
+		"budgetId":         "budget1",
+		"schemaVersion":    "1.0",
+	}
+
+	tests := []struct {
+		name        string
+		message     *cepubsub.Message
+		wantEventFn func() *cloudevents.Event
+		wantErr     bool
+	}{{
+		name: "threshold exceeded",
+		message: &cepubsub.Message{
+			Data:       []byte(budgetThresholdExceeded),
+			Attributes: attributes,
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return billingBudgetCloudEvent(budgetThresholdExceeded, v1beta1.CloudBillingBudgetSourceThresholdExceeded, attributes)
+		},
+	}, {
+		name: "no threshold exceeded",
+		message: &cepubsub.Message{
+			Data:       []byte(budgetUpdated),
+			Attributes: attributes,
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return billingBudgetCloudEvent(budgetUpdated, v1beta1.CloudBillingBudgetSourceUpdated, attributes)
+		},
+	}, {
+		name: "missing billingAccountId",
+		message: &cepubsub.Message{
+			Data: []byte(budgetUpdated),
+			Attributes: map[string]string{
+				"budgetId": "budget1",
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "missing budgetId",
+		message: &cepubsub.Message{
+			Data: []byte(budgetUpdated),
+			Attributes: map[string]string{
+				"billingAccountId": "012345-6789AB-CDEF01",
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "not json",
+		message: &cepubsub.Message{
+			Data:       []byte("test data"),
+			Attributes: attributes,
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := pubsubcontext.WithTransportContext(context.TODO(), pubsubcontext.NewTransportContext(
+				"testproject",
+				"testtopic",
+				"testsubscription",
+				"testmethod",
+				&pubsub.Message{
+					ID: "id",
+				},
+			))
+
+			gotEvent, err := Convert(ctx, test.message, Binary, CloudBillingBudgetConverter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("converters.convertCloudBillingBudget got error %v want error=%v", err, test.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+					t.Errorf("converters.convertCloudBillingBudget got unexpeceted cloudevents.Event (-want +got) %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func billingBudgetCloudEvent(data, eventType string, extensions map[string]string) *cloudevents.Event {
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID("id")
+	e.SetSource(v1beta1.CloudBillingBudgetSourceEventSource("012345-6789AB-CDEF01", "budget1"))
+	e.SetDataContentType(cloudevents.ApplicationJSON)
+	e.SetType(eventType)
+	e.SetExtension("knativecemode", string(Binary))
+	e.Data = []byte(data)
+	e.DataEncoded = true
+	for k, v := range extensions {
+		e.SetExtension(k, v)
+	}
+	return &e
+}
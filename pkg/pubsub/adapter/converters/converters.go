@@ -24,6 +24,8 @@ import (
 
 	cloudevents "github.com/cloudevents/sdk-go"
 	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 )
 
 // ModeType is the type for mode enum.
@@ -40,6 +42,9 @@ const (
 	DefaultSendMode = Binary
 	// The key used in the message attributes which defines the converter type.
 	KnativeGCPConverter = "knative-gcp"
+	// OrderingKeyExtension is the CloudEvents extension the ordering key of
+	// the received messages is set as, the same one brokers order events by.
+	OrderingKeyExtension = brokerv1beta1.OrderingKeyExtension
 )
 
 type converterFn func(context.Context, *cepubsub.Message, ModeType) (*cloudevents.Event, error)
@@ -54,10 +59,14 @@ var converters map[string]converterFn
 
 func init() {
 	converters = map[string]converterFn{
-		CloudAuditLogsConverter: convertCloudAuditLogs,
-		CloudStorageConverter:   convertCloudStorage,
-		CloudSchedulerConverter: convertCloudScheduler,
-		CloudBuildConverter:     convertCloudBuild,
+		CloudAuditLogsConverter:        convertCloudAuditLogs,
+		CloudStorageConverter:          convertCloudStorage,
+		CloudSchedulerConverter:        convertCloudScheduler,
+		CloudBuildConverter:            convertCloudBuild,
+		CloudArtifactRegistryConverter: convertCloudArtifactRegistry,
+		CloudBillingBudgetConverter:    convertCloudBillingBudget,
+		CloudMonitoringAlertConverter:  convertCloudMonitoringAlert,
+		SecretManagerRotationConverter: convertSecretManagerRotation,
 	}
 }
 
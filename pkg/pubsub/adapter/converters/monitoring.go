@@ -0,0 +1,97 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	. "github.com/cloudevents/sdk-go/pkg/cloudevents"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	CloudMonitoringAlertConverter = "com.google.cloud.monitoring.alert"
+)
+
+// monitoringAlertNotification is the part of the Cloud Monitoring Pub/Sub
+// notification payload the converter relies on.
+type monitoringAlertNotification struct {
+	Incident *struct {
+		IncidentID       string `json:"incident_id"`
+		ScopingProjectID string `json:"scoping_project_id"`
+		State            string `json:"state"`
+	} `json:"incident"`
+}
+
+func convertCloudMonitoringAlert(ctx context.Context, msg *cepubsub.Message, sendMode ModeType) (*cloudevents.Event, error) {
+	tx := pubsubcontext.TransportContextFrom(ctx)
+
+	var notification monitoringAlertNotification
+	if err := json.Unmarshal(msg.Data, &notification); err != nil {
+		return nil, fmt.Errorf("failed to decode alert notification: %w", err)
+	}
+	incident := notification.Incident
+	if incident == nil {
+		return nil, errors.New("received alert notification did not have an incident")
+	}
+
+	// Make a new event and convert the message payload.
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(tx.ID)
+	event.SetTime(tx.PublishTime)
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	switch incident.State {
+	case "open":
+		event.SetType(v1beta1.CloudMonitoringAlertSourceRaised)
+	case "closed":
+		event.SetType(v1beta1.CloudMonitoringAlertSourceResolved)
+	default:
+		return nil, fmt.Errorf("received alert notification had an unknown incident state %q", incident.State)
+	}
+	// The scoping project is the project of the alert policy. Older payload
+	// versions don't have it, fall back to the project of the subscription.
+	project := incident.ScopingProjectID
+	if project == "" {
+		project = tx.Project
+	}
+	event.SetSource(v1beta1.CloudMonitoringAlertSourceEventSource(project))
+	event.SetSubject(incident.IncidentID)
+
+	// Set the mode to be an extension attribute.
+	event.SetExtension("knativecemode", string(sendMode))
+	event.Data = msg.Data
+	event.DataEncoded = true
+	// Attributes are extensions.
+	if msg.Attributes != nil && len(msg.Attributes) > 0 {
+		for k, v := range msg.Attributes {
+			// CloudEvents v1.0 attributes MUST consist of lower-case letters ('a' to 'z') or digits ('0' to '9') as per
+			// the spec. It's not even possible for a conformant transport to allow non-base36 characters.
+			// Note `SetExtension` will make it lowercase so only `IsAlphaNumeric` needs to be checked here.
+			if IsAlphaNumeric(k) {
+				event.SetExtension(k, v)
+			}
+		}
+	}
+	return &event, nil
+}
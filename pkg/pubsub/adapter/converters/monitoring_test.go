@@ -0,0 +1,133 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	openIncident   = `{"incident":{"incident_id":"0.abc","scoping_project_id":"alertproject","state":"open","policy_name":"High latency"},"version":"1.2"}`
+	closedIncident = `{"incident":{"incident_id":"0.abc","scoping_project_id":"alertproject","state":"closed","policy_name":"High latency"},"version":"1.2"}`
+	legacyIncident = `{"incident":{"incident_id":"0.abc","state":"open"},"version":"1.1"}`
+)
+
+func TestConvertCloudMonitoringAlert(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     *cepubsub.Message
+		wantEventFn func() *cloudevents.Event
+		wantErr     bool
+	}{{
+		name: "open incident",
+		message: &cepubsub.Message{
+			Data: []byte(openIncident),
+			Attributes: map[string]string{
+				"attribute1": "value1",
+			},
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return monitoringAlertCloudEvent(openIncident, v1beta1.CloudMonitoringAlertSourceRaised, "alertproject", map[string]string{
+				"attribute1": "value1",
+			})
+		},
+	}, {
+		name: "closed incident",
+		message: &cepubsub.Message{
+			Data: []byte(closedIncident),
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return monitoringAlertCloudEvent(closedIncident, v1beta1.CloudMonitoringAlertSourceResolved, "alertproject", nil)
+		},
+	}, {
+		name: "no scoping project",
+		message: &cepubsub.Message{
+			Data: []byte(legacyIncident),
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return monitoringAlertCloudEvent(legacyIncident, v1beta1.CloudMonitoringAlertSourceRaised, "testproject", nil)
+		},
+	}, {
+		name: "unknown state",
+		message: &cepubsub.Message{
+			Data: []byte(`{"incident":{"incident_id":"0.abc","state":"acknowledged"}}`),
+		},
+		wantErr: true,
+	}, {
+		name: "no incident",
+		message: &cepubsub.Message{
+			Data: []byte(`{"version":"1.2"}`),
+		},
+		wantErr: true,
+	}, {
+		name: "not json",
+		message: &cepubsub.Message{
+			Data: []byte("test data"),
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := pubsubcontext.WithTransportContext(context.TODO(), pubsubcontext.NewTransportContext(
+				"testproject",
+				"testtopic",
+				"testsubscription",
+				"testmethod",
+				&pubsub.Message{
+					ID: "id",
+				},
+			))
+
+			gotEvent, err := Convert(ctx, test.message, Binary, CloudMonitoringAlertConverter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("converters.convertCloudMonitoringAlert got error %v want error=%v", err, test.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+					t.Errorf("converters.convertCloudMonitoringAlert got unexpeceted cloudevents.Event (-want +got) %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func monitoringAlertCloudEvent(data, eventType, project string, extensions map[string]string) *cloudevents.Event {
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID("id")
+	e.SetSource(v1beta1.CloudMonitoringAlertSourceEventSource(project))
+	e.SetSubject("0.abc")
+	e.SetDataContentType(cloudevents.ApplicationJSON)
+	e.SetType(eventType)
+	e.SetExtension("knativecemode", string(Binary))
+	e.Data = []byte(data)
+	e.DataEncoded = true
+	for k, v := range extensions {
+		e.SetExtension(k, v)
+	}
+	return &e
+}
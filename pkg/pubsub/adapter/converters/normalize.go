@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+)
+
+// maxAttributeValueSize is the maximum size in bytes of a string attribute
+// value. Events are often forwarded over Pub/Sub with their attributes as
+// message attributes, e.g. through a Broker, and Pub/Sub rejects attribute
+// values over 1024 bytes.
+const maxAttributeValueSize = 1024
+
+// NormalizationAction describes how an attribute value was changed.
+type NormalizationAction string
+
+const (
+	// AttributeEscaped means that characters not allowed in CloudEvents
+	// string attributes were escaped.
+	AttributeEscaped NormalizationAction = "escaped"
+	// AttributeTruncated means that the value was truncated to
+	// maxAttributeValueSize bytes.
+	AttributeTruncated NormalizationAction = "truncated"
+)
+
+// Normalization records a change Normalize made to an event attribute.
+type Normalization struct {
+	Attribute string
+	Action    NormalizationAction
+}
+
+// Normalize makes the string attributes of a converted event valid and small
+// enough to be forwarded. Control characters and invalid UTF-8, which the
+// CloudEvents spec doesn't allow in strings, are escaped, and values over
+// maxAttributeValueSize bytes are truncated. Only the subject and extensions
+// are normalized, as the other attributes are set by the converters
+// themselves. It returns the changes made, in attribute name order.
+func Normalize(event *cloudevents.Event) []Normalization {
+	var changes []Normalization
+	if subject, c := normalizeValue("subject", event.Subject()); len(c) > 0 {
+		event.SetSubject(subject)
+		changes = append(changes, c...)
+	}
+
+	extensions := event.Extensions()
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, ok := extensions[name].(string)
+		if !ok {
+			continue
+		}
+		if value, c := normalizeValue(name, value); len(c) > 0 {
+			event.SetExtension(name, value)
+			changes = append(changes, c...)
+		}
+	}
+	return changes
+}
+
+// normalizeValue returns the normalized value of the attribute and the changes
+// made to it.
+func normalizeValue(attribute, value string) (string, []Normalization) {
+	var changes []Normalization
+	if escaped, ok := escapeValue(value); ok {
+		value = escaped
+		changes = append(changes, Normalization{Attribute: attribute, Action: AttributeEscaped})
+	}
+	if len(value) > maxAttributeValueSize {
+		value = truncateValue(value, maxAttributeValueSize)
+		changes = append(changes, Normalization{Attribute: attribute, Action: AttributeTruncated})
+	}
+	return value, changes
+}
+
+// escapeValue replaces invalid UTF-8 with the replacement character and
+// control characters with their \u escape sequence. It returns false if the
+// value needed no escaping.
+func escapeValue(value string) (string, bool) {
+	if utf8.ValidString(value) && strings.IndexFunc(value, unicode.IsControl) < 0 {
+		return value, false
+	}
+	var b strings.Builder
+	for _, r := range strings.ToValidUTF8(value, string(utf8.RuneError)) {
+		if unicode.IsControl(r) {
+			fmt.Fprintf(&b, "\\u%04x", r)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String(), true
+}
+
+// truncateValue truncates value to at most size bytes without splitting a
+// multi-byte character.
+func truncateValue(value string, size int) string {
+	for size > 0 && !utf8.RuneStart(value[size]) {
+		size--
+	}
+	return value[:size]
+}
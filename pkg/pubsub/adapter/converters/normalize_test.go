@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestNormalize(t *testing.T) {
+	long := strings.Repeat("a", maxAttributeValueSize+10)
+	// A multi-byte character straddling the size limit.
+	straddling := strings.Repeat("a", maxAttributeValueSize-1) + "é"
+
+	tests := []struct {
+		name           string
+		subject        string
+		extensions     map[string]interface{}
+		wantSubject    string
+		wantExtensions map[string]interface{}
+		wantChanges    []Normalization
+	}{{
+		name:           "valid attributes",
+		subject:        "subject",
+		extensions:     map[string]interface{}{"ext": "value", "num": int32(5)},
+		wantSubject:    "subject",
+		wantExtensions: map[string]interface{}{"ext": "value", "num": int32(5)},
+	}, {
+		name:        "long subject",
+		subject:     long,
+		wantSubject: long[:maxAttributeValueSize],
+		wantChanges: []Normalization{{Attribute: "subject", Action: AttributeTruncated}},
+	}, {
+		name:           "long extension split on character boundary",
+		extensions:     map[string]interface{}{"ext": straddling},
+		wantExtensions: map[string]interface{}{"ext": straddling[:maxAttributeValueSize-1]},
+		wantChanges:    []Normalization{{Attribute: "ext", Action: AttributeTruncated}},
+	}, {
+		name: "control characters and invalid UTF-8",
+		extensions: map[string]interface{}{
+			"b": "line1\nline2",
+			"a": "bad\xffbyte",
+		},
+		wantExtensions: map[string]interface{}{
+			"b": `line1\u000aline2`,
+			"a": "bad�byte",
+		},
+		wantChanges: []Normalization{
+			{Attribute: "a", Action: AttributeEscaped},
+			{Attribute: "b", Action: AttributeEscaped},
+		},
+	}, {
+		name:        "escaped and truncated",
+		subject:     "a\tb" + long,
+		wantSubject: (`a\u0009b` + long)[:maxAttributeValueSize],
+		wantChanges: []Normalization{
+			{Attribute: "subject", Action: AttributeEscaped},
+			{Attribute: "subject", Action: AttributeTruncated},
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := cloudevents.NewEvent(cloudevents.VersionV1)
+			if test.subject != "" {
+				e.SetSubject(test.subject)
+			}
+			for k, v := range test.extensions {
+				e.SetExtension(k, v)
+			}
+
+			gotChanges := Normalize(&e)
+			if diff := cmp.Diff(test.wantChanges, gotChanges); diff != "" {
+				t.Errorf("unexpected changes (-want, +got) = %v", diff)
+			}
+			if got := e.Subject(); got != test.wantSubject {
+				t.Errorf("subject got=%q, want=%q", got, test.wantSubject)
+			}
+			if diff := cmp.Diff(test.wantExtensions, e.Extensions(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected extensions (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package converters
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go"
@@ -50,23 +51,25 @@ func convertPubSub(ctx context.Context, msg *cepubsub.Message, sendMode ModeType
 	event.Data = msg.Data
 	event.DataEncoded = true
 
-	logger := logging.FromContext(ctx).With(zap.Any("event.id", event.ID()))
 	// If send mode is Push, convert to Pub/Sub Push payload style.
 	if sendMode == Push {
 		// Set the content type to something that can be handled by codec.go.
 		event.SetDataContentType(cloudevents.ApplicationJSON)
 		msg := &PubSubMessage{
-			ID:          event.ID(),
-			Attributes:  msg.Attributes,
-			PublishTime: event.Time(),
-			Data:        event.Data,
+			ID:                   event.ID(),
+			MessageID:            event.ID(),
+			Attributes:           msg.Attributes,
+			PublishTime:          tx.PublishTime,
+			PublishTimeSnakeCase: tx.PublishTime,
+			OrderingKey:          OrderingKey(ctx),
+			Data:                 event.Data,
 		}
 
 		if err := event.SetData(&PushMessage{
-			Subscription: tx.Subscription,
+			Subscription: subscriptionName(ctx, tx),
 			Message:      msg,
 		}); err != nil {
-			logger.Desugar().Warn("Failed to set data.", zap.Error(err))
+			logging.FromContext(ctx).Desugar().Warn("Failed to set data.", zap.Any("event.id", event.ID()), zap.Error(err))
 		}
 	} else {
 		// non-Push mode, attributes should be promoted to extensions.
@@ -81,7 +84,8 @@ func convertPubSub(ctx context.Context, msg *cepubsub.Message, sendMode ModeType
 				if IsAlphaNumeric(k) {
 					event.SetExtension(k, v)
 				} else {
-					logger.Desugar().Warn("Skipping attribute that is not a valid extension", zap.String(k, v))
+					logging.FromContext(ctx).Desugar().Warn("Skipping attribute that is not a valid extension",
+						zap.Any("event.id", event.ID()), zap.String(k, v))
 				}
 			}
 		}
@@ -89,9 +93,45 @@ func convertPubSub(ctx context.Context, msg *cepubsub.Message, sendMode ModeType
 	return &event, nil
 }
 
+type subscriptionProjectKey struct{}
+
+type orderingKeyKey struct{}
+
+// WithOrderingKey returns a copy of ctx that holds the ordering key of the
+// received message, which the transport context doesn't.
+func WithOrderingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, orderingKeyKey{}, key)
+}
+
+// OrderingKey returns the ordering key of the received message, empty if it
+// has none.
+func OrderingKey(ctx context.Context) string {
+	key, _ := ctx.Value(orderingKeyKey{}).(string)
+	return key
+}
+
+// WithSubscriptionProject returns a copy of ctx that holds the project of the
+// subscription the messages are pulled from. It is only needed if the project
+// of the subscription differs from the project of the topic in the transport
+// context.
+func WithSubscriptionProject(ctx context.Context, project string) context.Context {
+	return context.WithValue(ctx, subscriptionProjectKey{}, project)
+}
+
+// subscriptionName returns the full name of the subscription of the transport
+// context, as Pub/Sub sets it in push messages.
+func subscriptionName(ctx context.Context, tx pubsubcontext.TransportContext) string {
+	project := tx.Project
+	if p, ok := ctx.Value(subscriptionProjectKey{}).(string); ok && p != "" {
+		project = p
+	}
+	return fmt.Sprintf("projects/%s/subscriptions/%s", project, tx.Subscription)
+}
+
 // PushMessage represents the format Pub/Sub uses to push events.
 type PushMessage struct {
-	// Subscription is the subscription ID that received this Message.
+	// Subscription is the full name of the subscription that received this
+	// Message, e.g. projects/my-project/subscriptions/my-subscription.
 	Subscription string `json:"subscription"`
 	// Message holds the Pub/Sub message contents.
 	Message *PubSubMessage `json:"message,omitempty"`
@@ -103,6 +143,9 @@ type PubSubMessage struct {
 	// populated for Messages obtained from a subscription.
 	// This field is read-only.
 	ID string `json:"messageId,omitempty"`
+	// MessageID duplicates ID under the snake_case name that Pub/Sub also
+	// pushes.
+	MessageID string `json:"message_id,omitempty"`
 
 	// Data is the actual data in the message.
 	Data interface{} `json:"data,omitempty"`
@@ -115,4 +158,11 @@ type PubSubMessage struct {
 	// server for Messages obtained from a subscription.
 	// This field is read-only.
 	PublishTime time.Time `json:"publishTime,omitempty"`
+	// PublishTimeSnakeCase duplicates PublishTime under the snake_case name
+	// that Pub/Sub also pushes.
+	PublishTimeSnakeCase time.Time `json:"publish_time,omitempty"`
+
+	// OrderingKey is the ordering key of the message, if it was published
+	// with one.
+	OrderingKey string `json:"orderingKey,omitempty"`
 }
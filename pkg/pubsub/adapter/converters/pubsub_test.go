@@ -20,7 +20,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"cloud.google.com/go/pubsub"
 	cloudevents "github.com/cloudevents/sdk-go"
@@ -33,9 +35,14 @@ import (
 func TestConvertCloudPubSub(t *testing.T) {
 
 	tests := []struct {
-		name        string
-		message     *cepubsub.Message
-		sendMode    ModeType
+		name     string
+		message  *cepubsub.Message
+		sendMode ModeType
+		// subscriptionProject is the project of the subscription, if it
+		// differs from the project of the topic.
+		subscriptionProject string
+		// orderingKey is the ordering key of the received message.
+		orderingKey string
 		wantEventFn func() *cloudevents.Event
 		wantErr     bool
 	}{{
@@ -128,6 +135,30 @@ func TestConvertCloudPubSub(t *testing.T) {
 		wantEventFn: func() *cloudevents.Event {
 			return pubSubPushCloudEvent(nil, "\"InRlc3QgZGF0YSI=\"")
 		},
+	}, {
+		name: "Push mode with subscription in another project",
+		message: &cepubsub.Message{
+			Data: []byte("\"test data\""), // Data passed in quotes for it to be marshalled properly
+		},
+		sendMode:            Push,
+		subscriptionProject: "subproject",
+		wantEventFn: func() *cloudevents.Event {
+			e := pubSubPushCloudEvent(nil, "\"InRlc3QgZGF0YSI=\"")
+			e.Data = []byte(strings.Replace(string(e.Data.([]byte)), "projects/testproject/", "projects/subproject/", 1))
+			return e
+		},
+	}, {
+		name: "Push mode with ordering key",
+		message: &cepubsub.Message{
+			Data: []byte("\"test data\""), // Data passed in quotes for it to be marshalled properly
+		},
+		sendMode:    Push,
+		orderingKey: "order-1",
+		wantEventFn: func() *cloudevents.Event {
+			e := pubSubPushCloudEvent(nil, "\"InRlc3QgZGF0YSI=\"")
+			e.Data = []byte(strings.Replace(string(e.Data.([]byte)), `"}}`, `","orderingKey":"order-1"}}`, 1))
+			return e
+		},
 	}}
 
 	for _, test := range tests {
@@ -138,9 +169,16 @@ func TestConvertCloudPubSub(t *testing.T) {
 				"testsubscription",
 				"testmethod",
 				&pubsub.Message{
-					ID: "id",
+					ID:          "id",
+					PublishTime: testPublishTime,
 				},
 			))
+			if test.subscriptionProject != "" {
+				ctx = WithSubscriptionProject(ctx, test.subscriptionProject)
+			}
+			if test.orderingKey != "" {
+				ctx = WithOrderingKey(ctx, test.orderingKey)
+			}
 
 			gotEvent, err := Convert(ctx, test.message, test.sendMode, "")
 			if err != nil {
@@ -156,9 +194,12 @@ func TestConvertCloudPubSub(t *testing.T) {
 	}
 }
 
+var testPublishTime = time.Date(2020, time.September, 1, 12, 30, 0, 0, time.UTC)
+
 func pubSubPullCloudEvent(extensions map[string]string, schema string) *cloudevents.Event {
 	e := cloudevents.NewEvent(cloudevents.VersionV1)
 	e.SetID("id")
+	e.SetTime(testPublishTime)
 	e.SetSource(v1alpha1.CloudPubSubSourceEventSource("testproject", "testtopic"))
 	e.SetDataContentType("application/octet-stream")
 	e.SetType(v1alpha1.CloudPubSubSourcePublish)
@@ -177,6 +218,7 @@ func pubSubPullCloudEvent(extensions map[string]string, schema string) *cloudeve
 func pubSubPushCloudEvent(attributes map[string]string, data string) *cloudevents.Event {
 	e := cloudevents.NewEvent(cloudevents.VersionV1)
 	e.SetID("id")
+	e.SetTime(testPublishTime)
 	e.SetSource(v1alpha1.CloudPubSubSourceEventSource("testproject", "testtopic"))
 	e.SetDataContentType(cloudevents.ApplicationJSON)
 	e.SetType(v1alpha1.CloudPubSubSourcePublish)
@@ -186,7 +228,7 @@ func pubSubPushCloudEvent(attributes map[string]string, data string) *cloudevent
 		ex, _ := json.Marshal(attributes)
 		at = fmt.Sprintf(`"attributes":%s,`, ex)
 	}
-	s := fmt.Sprintf(`{"subscription":"testsubscription","message":{"messageId":"id","data":%s,%s"publishTime":"0001-01-01T00:00:00Z"}}`, data, at)
+	s := fmt.Sprintf(`{"subscription":"projects/testproject/subscriptions/testsubscription","message":{"messageId":"id","message_id":"id","data":%s,%s"publishTime":"2020-09-01T12:30:00Z","publish_time":"2020-09-01T12:30:00Z"}}`, data, at)
 	e.Data = []byte(s)
 	e.DataEncoded = true
 	return &e
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	. "github.com/cloudevents/sdk-go/pkg/cloudevents"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	SecretManagerRotationConverter = "com.google.cloud.secretmanager"
+)
+
+// secretManagerEventTypes maps the eventType attribute of Secret Manager
+// notifications to CloudEvent types.
+var secretManagerEventTypes = map[string]string{
+	"SECRET_CREATE":          v1beta1.SecretManagerRotationSourceSecretCreated,
+	"SECRET_UPDATE":          v1beta1.SecretManagerRotationSourceSecretUpdated,
+	"SECRET_DELETE":          v1beta1.SecretManagerRotationSourceSecretDeleted,
+	"SECRET_ROTATE":          v1beta1.SecretManagerRotationSourceSecretRotate,
+	"SECRET_VERSION_ADD":     v1beta1.SecretManagerRotationSourceVersionAdded,
+	"SECRET_VERSION_ENABLE":  v1beta1.SecretManagerRotationSourceVersionEnabled,
+	"SECRET_VERSION_DISABLE": v1beta1.SecretManagerRotationSourceVersionDisabled,
+	"SECRET_VERSION_DESTROY": v1beta1.SecretManagerRotationSourceVersionDestroyed,
+}
+
+func convertSecretManagerRotation(ctx context.Context, msg *cepubsub.Message, sendMode ModeType) (*cloudevents.Event, error) {
+	tx := pubsubcontext.TransportContextFrom(ctx)
+
+	eventType, ok := msg.Attributes[v1beta1.SecretManagerRotationSourceEventType]
+	if !ok {
+		return nil, fmt.Errorf("received secret notification did not have %s attribute", v1beta1.SecretManagerRotationSourceEventType)
+	}
+	ceType, ok := secretManagerEventTypes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("received secret notification had unknown %s %q", v1beta1.SecretManagerRotationSourceEventType, eventType)
+	}
+	secret, ok := msg.Attributes[v1beta1.SecretManagerRotationSourceSecretID]
+	if !ok {
+		return nil, fmt.Errorf("received secret notification did not have %s attribute", v1beta1.SecretManagerRotationSourceSecretID)
+	}
+
+	// Make a new event and convert the message payload.
+	event := cloudevents.NewEvent(cloudevents.VersionV1)
+	event.SetID(tx.ID)
+	event.SetTime(tx.PublishTime)
+	event.SetDataContentType(cloudevents.ApplicationJSON)
+	event.SetSource(v1beta1.SecretManagerRotationSourceEventSource(secret))
+	event.SetType(ceType)
+	// Version events are about a single version of the secret, e.g.
+	// versions/3.
+	if version, ok := msg.Attributes[v1beta1.SecretManagerRotationSourceVersionID]; ok {
+		event.SetSubject(strings.TrimPrefix(version, secret+"/"))
+	}
+
+	// Set the mode to be an extension attribute.
+	event.SetExtension("knativecemode", string(sendMode))
+	event.Data = msg.Data
+	event.DataEncoded = true
+	// Attributes are extensions.
+	if msg.Attributes != nil && len(msg.Attributes) > 0 {
+		for k, v := range msg.Attributes {
+			// CloudEvents v1.0 attributes MUST consist of lower-case letters ('a' to 'z') or digits ('0' to '9') as per
+			// the spec. It's not even possible for a conformant transport to allow non-base36 characters.
+			// Note `SetExtension` will make it lowercase so only `IsAlphaNumeric` needs to be checked here.
+			if IsAlphaNumeric(k) {
+				event.SetExtension(k, v)
+			}
+		}
+	}
+	return &event, nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package converters
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pubsubcontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	secretRotate       = `{"name":"projects/123/secrets/db-password","rotation":{"nextRotationTime":"2020-10-01T00:00:00Z","rotationPeriod":"2592000s"}}`
+	secretVersionAdded = `{"name":"projects/123/secrets/db-password/versions/4","state":"ENABLED"}`
+)
+
+func TestConvertSecretManagerRotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		message     *cepubsub.Message
+		wantEventFn func() *cloudevents.Event
+		wantErr     bool
+	}{{
+		name: "rotate",
+		message: &cepubsub.Message{
+			Data: []byte(secretRotate),
+			Attributes: map[string]string{
+				"eventType":  "SECRET_ROTATE",
+				"secretId":   "projects/123/secrets/db-password",
+				"dataFormat": "JSON_API_V1",
+			},
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return secretManagerCloudEvent(secretRotate, v1beta1.SecretManagerRotationSourceSecretRotate, "", map[string]string{
+				"eventType":  "SECRET_ROTATE",
+				"secretId":   "projects/123/secrets/db-password",
+				"dataFormat": "JSON_API_V1",
+			})
+		},
+	}, {
+		name: "version added",
+		message: &cepubsub.Message{
+			Data: []byte(secretVersionAdded),
+			Attributes: map[string]string{
+				"eventType":  "SECRET_VERSION_ADD",
+				"secretId":   "projects/123/secrets/db-password",
+				"versionId":  "projects/123/secrets/db-password/versions/4",
+				"dataFormat": "JSON_API_V1",
+			},
+		},
+		wantEventFn: func() *cloudevents.Event {
+			return secretManagerCloudEvent(secretVersionAdded, v1beta1.SecretManagerRotationSourceVersionAdded, "versions/4", map[string]string{
+				"eventType":  "SECRET_VERSION_ADD",
+				"secretId":   "projects/123/secrets/db-password",
+				"versionId":  "projects/123/secrets/db-password/versions/4",
+				"dataFormat": "JSON_API_V1",
+			})
+		},
+	}, {
+		name: "missing eventType",
+		message: &cepubsub.Message{
+			Data: []byte(secretRotate),
+			Attributes: map[string]string{
+				"secretId": "projects/123/secrets/db-password",
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "unknown eventType",
+		message: &cepubsub.Message{
+			Data: []byte(secretRotate),
+			Attributes: map[string]string{
+				"eventType": "SECRET_SHRED",
+				"secretId":  "projects/123/secrets/db-password",
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "missing secretId",
+		message: &cepubsub.Message{
+			Data: []byte(secretRotate),
+			Attributes: map[string]string{
+				"eventType": "SECRET_ROTATE",
+			},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := pubsubcontext.WithTransportContext(context.TODO(), pubsubcontext.NewTransportContext(
+				"testproject",
+				"testtopic",
+				"testsubscription",
+				"testmethod",
+				&pubsub.Message{
+					ID: "id",
+				},
+			))
+
+			gotEvent, err := Convert(ctx, test.message, Binary, SecretManagerRotationConverter)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("converters.convertSecretManagerRotation got error %v want error=%v", err, test.wantErr)
+			}
+			if err == nil {
+				if diff := cmp.Diff(test.wantEventFn(), gotEvent); diff != "" {
+					t.Errorf("converters.convertSecretManagerRotation got unexpeceted cloudevents.Event (-want +got) %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func secretManagerCloudEvent(data, eventType, subject string, extensions map[string]string) *cloudevents.Event {
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID("id")
+	e.SetSource(v1beta1.SecretManagerRotationSourceEventSource("projects/123/secrets/db-password"))
+	e.SetDataContentType(cloudevents.ApplicationJSON)
+	e.SetType(eventType)
+	if subject != "" {
+		e.SetSubject(subject)
+	}
+	e.SetExtension("knativecemode", string(Binary))
+	e.Data = []byte(data)
+	e.DataEncoded = true
+	for k, v := range extensions {
+		e.SetExtension(k, v)
+	}
+	return &e
+}
@@ -18,6 +18,7 @@ package converters
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"go.uber.org/zap"
@@ -47,8 +48,24 @@ const (
 	//  The link above is tied to the go-client, and it seems not to be a valid json schema.
 	storageSchemaUrl      = "https://raw.githubusercontent.com/google/knative-gcp/master/schemas/storage/schema.json"
 	CloudStorageConverter = "com.google.cloud.storage"
+
+	// Extensions set from the object metadata in the notification payload.
+	objectGenerationExtension     = "objectgeneration"
+	objectMetagenerationExtension = "objectmetageneration"
+	objectSizeExtension           = "objectsize"
+	objectContentTypeExtension    = "objectcontenttype"
 )
 
+// storageObject holds the object metadata fields of a notification payload
+// that are promoted to extensions. The int64 fields are strings in the JSON
+// API and are kept as strings, as CloudEvents integers are only 32 bits.
+type storageObject struct {
+	Generation     string `json:"generation"`
+	Metageneration string `json:"metageneration"`
+	Size           string `json:"size"`
+	ContentType    string `json:"contentType"`
+}
+
 func convertCloudStorage(ctx context.Context, msg *cepubsub.Message, sendMode ModeType) (*cloudevents.Event, error) {
 	if msg == nil {
 		return nil, errors.New("nil pubsub message")
@@ -103,5 +120,27 @@ func convertCloudStorage(ctx context.Context, msg *cepubsub.Message, sendMode Mo
 			}
 		}
 	}
+	setStorageObjectExtensions(ctx, &event, msg.Data)
 	return &event, nil
 }
+
+// setStorageObjectExtensions promotes the object metadata in the notification
+// payload to extensions, so that they can be used without fetching the object.
+// Notifications without a JSON payload are left as they are.
+func setStorageObjectExtensions(ctx context.Context, event *cloudevents.Event, data []byte) {
+	var obj storageObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		logging.FromContext(ctx).Desugar().Debug("received event without object metadata payload", zap.Error(err))
+		return
+	}
+	for k, v := range map[string]string{
+		objectGenerationExtension:     obj.Generation,
+		objectMetagenerationExtension: obj.Metageneration,
+		objectSizeExtension:           obj.Size,
+		objectContentTypeExtension:    obj.ContentType,
+	} {
+		if v != "" {
+			event.SetExtension(k, v)
+		}
+	}
+}
@@ -170,6 +170,51 @@ func TestConvertCloudStorageSource(t *testing.T) {
 	}
 }
 
+func TestConvertCloudStorageObjectExtensions(t *testing.T) {
+	ctx := pubsubcontext.WithTransportContext(context.TODO(), pubsubcontext.NewTransportContext(
+		"testproject",
+		"testtopic",
+		"testsubscription",
+		"testmethod",
+		&pubsub.Message{
+			ID: "id",
+		},
+	))
+	data := []byte(`{
+		"kind": "storage#object",
+		"name": "myfile.jpg",
+		"bucket": "my-bucket",
+		"generation": "1588778055917163",
+		"metageneration": "1",
+		"contentType": "image/jpeg",
+		"size": "4294967296"
+	}`)
+	msg := &cepubsub.Message{
+		Data: data,
+		Attributes: map[string]string{
+			"knative-gcp":      "com.google.cloud.storage",
+			"bucketId":         "my-bucket",
+			"eventType":        "OBJECT_FINALIZE",
+			"objectId":         "myfile.jpg",
+			"objectGeneration": "1588778055917163",
+		},
+	}
+
+	gotEvent, err := Convert(ctx, msg, Binary, "")
+	if err != nil {
+		t.Fatalf("converters.convertCloudStorage got error %v", err)
+	}
+	wantExtensions := map[string]interface{}{
+		"objectgeneration":     "1588778055917163",
+		"objectmetageneration": "1",
+		"objectsize":           "4294967296",
+		"objectcontenttype":    "image/jpeg",
+	}
+	if diff := cmp.Diff(wantExtensions, gotEvent.Extensions()); diff != "" {
+		t.Errorf("unexpected extensions (-want +got) %s", diff)
+	}
+}
+
 func storageCloudEvent(extensions map[string]string, subject ...string) *cloudevents.Event {
 	e := cloudevents.NewEvent(cloudevents.VersionV1)
 	e.SetID("id")
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+// drainPath is the path of the endpoint the preStop hook of the receive
+// adapter calls to drain it.
+const drainPath = "/drain"
+
+// drainContext carries the values of the context of a delivery, which is
+// canceled as soon as the streaming pull stops, but is only canceled along
+// with the lifetime context of the adapter. Draining thus lets the deliveries
+// in flight complete.
+type drainContext struct {
+	context.Context
+	values context.Context
+}
+
+// Value implements context.Context.
+func (c drainContext) Value(key interface{}) interface{} {
+	return c.values.Value(key)
+}
+
+// Drain stops the streaming pull and waits for the messages already received
+// to be delivered, or for ctx to be done. It must be called after Start.
+func (a *Adapter) Drain(ctx context.Context) error {
+	a.stopPull()
+	select {
+	case <-a.pullStopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// serveDrain serves the drain endpoint on DrainPort until ctx is done.
+func (a *Adapter) serveDrain(ctx context.Context) {
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc(drainPath, func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		logger := logging.FromContext(ctx)
+		logger.Info("Draining the receive adapter")
+		if err := a.Drain(r.Context()); err != nil {
+			logger.Warnw("Failed to drain the receive adapter", zap.Error(err))
+			nethttp.Error(w, err.Error(), nethttp.StatusServiceUnavailable)
+			return
+		}
+		logger.Info("Drained the receive adapter")
+		w.WriteHeader(nethttp.StatusOK)
+	})
+	srv := &nethttp.Server{
+		Addr:    fmt.Sprintf(":%d", a.DrainPort),
+		Handler: mux,
+	}
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(sctx)
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != nethttp.ErrServerClosed {
+		logging.FromContext(ctx).Errorw("Failed to serve the drain endpoint", zap.Error(err))
+	}
+}
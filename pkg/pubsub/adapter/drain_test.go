@@ -0,0 +1,119 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+)
+
+var errReceiverStopped = errors.New("receiver stopped")
+
+// blockingClient is an inbound client whose receiver runs until its context
+// is done.
+type blockingClient struct {
+	started chan struct{}
+}
+
+func (c *blockingClient) Send(ctx context.Context, event cloudevents.Event) (context.Context, *cloudevents.Event, error) {
+	return ctx, nil, nil
+}
+
+func (c *blockingClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	close(c.started)
+	<-ctx.Done()
+	return errReceiverStopped
+}
+
+func startBlockingAdapter(ctx context.Context, t *testing.T) (*Adapter, chan error) {
+	t.Helper()
+	inbound := &blockingClient{started: make(chan struct{})}
+	a := &Adapter{
+		Sink:     "http://localhost:8080",
+		inbound:  inbound,
+		reporter: &mockStatsReporter{},
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- a.Start(ctx)
+	}()
+	select {
+	case <-inbound.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the receiver to start")
+	}
+	return a, errCh
+}
+
+func TestDrain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	a, errCh := startBlockingAdapter(ctx, t)
+
+	if err := a.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain() = %v", err)
+	}
+	select {
+	case err := <-errCh:
+		t.Fatalf("Start() returned %v before the adapter was terminated", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Errorf("Start() = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Start to return")
+	}
+}
+
+func TestStopWithoutDrain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, errCh := startBlockingAdapter(ctx, t)
+
+	cancel()
+	select {
+	case err := <-errCh:
+		if err != errReceiverStopped {
+			t.Errorf("Start() = %v, want %v", err, errReceiverStopped)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for Start to return")
+	}
+}
+
+func TestDrainContext(t *testing.T) {
+	type key struct{}
+	values, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	cancel()
+
+	ctx := drainContext{Context: context.Background(), values: values}
+	if err := ctx.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+	if got := ctx.Value(key{}); got != "value" {
+		t.Errorf("Value() = %v, want %q", got, "value")
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsublite/pscompat"
+
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+// startLiteReceiver pulls messages off the Pub/Sub Lite subscription until
+// ctx is done, see gpubsublite.Receive.
+func (t *orderedTransport) startLiteReceiver(ctx context.Context) error {
+	return gpubsublite.Receive(ctx, t.liteSubscription, pscompat.DefaultReceiveSettings, t.newLiteSubscriber, t.receive)
+}
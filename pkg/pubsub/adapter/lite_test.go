@@ -0,0 +1,125 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"cloud.google.com/go/pubsublite/pscompat"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+// fakeLiteSubscriber delivers msg on each Receive. The first Receive then
+// stops as if msg was nacked, the others block until ctx is done.
+type fakeLiteSubscriber struct {
+	msg    *pubsub.Message
+	nacked bool
+}
+
+func (s *fakeLiteSubscriber) Receive(ctx context.Context, f pscompat.MessageReceiverFunc) error {
+	f(ctx, s.msg)
+	if !s.nacked {
+		s.nacked = true
+		return gpubsublite.ErrNack
+	}
+	<-ctx.Done()
+	return nil
+}
+
+func TestReceiveLite(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	got := make(chan string, 2)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got <- r.Header.Get("Ce-Orderingkey")
+	}))
+	defer sink.Close()
+
+	sub := &fakeLiteSubscriber{
+		msg: &pubsub.Message{ID: "1", Data: []byte("hello"), OrderingKey: "order-1"},
+	}
+	var paths []string
+	a := &Adapter{
+		Project:      "test-project",
+		Topic:        "topic",
+		Subscription: "sub",
+		SendMode:     converters.Binary,
+		LiteLocation: "us-central1-a",
+		client:       client,
+		reporter:     &mockStatsReporter{},
+		newLiteSubscriber: func(_ context.Context, path string, settings pscompat.ReceiveSettings) (gpubsublite.Subscriber, error) {
+			if err := settings.NackHandler(sub.msg); err != gpubsublite.ErrNack {
+				t.Errorf("NackHandler got %v want %v", err, gpubsublite.ErrNack)
+			}
+			paths = append(paths, path)
+			return sub, nil
+		},
+	}
+	a.outbound = newHTTPSender(sink.URL, a.SendMode, nil)
+	inbound, err := a.newPubSubClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rctx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- inbound.StartReceiver(rctx, a.receive)
+	}()
+
+	// The message is received again once the subscriber reconnects.
+	for i := 0; i < 2; i++ {
+		select {
+		case key := <-got:
+			if key != "order-1" {
+				t.Errorf("ordering key extension got %q want %q", key, "order-1")
+			}
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the event")
+		}
+	}
+	stop()
+	if err := <-done; err != nil {
+		t.Errorf("StartReceiver got error %v", err)
+	}
+	want := "projects/test-project/locations/us-central1-a/subscriptions/sub"
+	if len(paths) != 2 || paths[0] != want || paths[1] != want {
+		t.Errorf("subscriber paths got %v want two of %q", paths, want)
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	cetypes "github.com/cloudevents/sdk-go/pkg/cloudevents/types"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/extensions"
+	"go.opencensus.io/trace"
+)
+
+var specV1 = spec.VS.Version(cloudevents.VersionV1)
+
+// eventMessage is a binding.Message reading a converted event, so that the
+// event is written straight into the outbound request instead of being
+// converted to another event type or encoded in between.
+type eventMessage struct {
+	event *cloudevents.Event
+	ec    *cloudevents.EventContextV1
+	// extensions override those of the event. They are nil when they are
+	// added to the outbound request as precomputed headers instead.
+	extensions map[string]string
+	// structured is true if the event is read in structured encoding.
+	structured bool
+}
+
+var _ binding.Message = (*eventMessage)(nil)
+
+func newEventMessage(event *cloudevents.Event, extensions map[string]string, structured bool) *eventMessage {
+	return &eventMessage{
+		event:      event,
+		ec:         event.Context.AsV1(),
+		extensions: extensions,
+		structured: structured,
+	}
+}
+
+// ReadEncoding implements binding.MessageReader.
+func (m *eventMessage) ReadEncoding() binding.Encoding {
+	if m.structured {
+		return binding.EncodingStructured
+	}
+	return binding.EncodingBinary
+}
+
+// ReadStructured implements binding.MessageReader.
+func (m *eventMessage) ReadStructured(ctx context.Context, w binding.StructuredWriter) error {
+	if !m.structured {
+		return binding.ErrNotStructured
+	}
+	// Encode the event with the codec of its own type, which keeps data of
+	// non-JSON content types in data_base64 rather than as a string.
+	ec := *m.ec
+	ec.Extensions = make(map[string]interface{}, len(m.ec.Extensions)+len(m.extensions)+1)
+	for k, v := range m.ec.Extensions {
+		ec.Extensions[k] = v
+	}
+	for k, v := range m.extensions {
+		ec.Extensions[k] = v
+	}
+	if tp := traceParent(ctx); tp != "" {
+		ec.Extensions[extensions.TraceParentExtension] = tp
+	}
+	e := *m.event
+	e.Context = &ec
+	if data, ok := e.Data.([]byte); ok && len(data) == 0 {
+		// The codec cannot encode empty data.
+		e.Data = nil
+	}
+	body, err := e.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return w.SetStructuredEvent(ctx, format.JSON, bytes.NewReader(body))
+}
+
+// ReadBinary implements binding.MessageReader.
+func (m *eventMessage) ReadBinary(ctx context.Context, w binding.BinaryWriter) error {
+	for _, attr := range specV1.Attributes() {
+		if _, v := m.GetAttribute(attr.Kind()); v != nil {
+			if err := w.SetAttribute(attr, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	for k := range m.ec.Extensions {
+		if _, ok := m.extensions[k]; ok {
+			continue
+		}
+		if err := w.SetExtension(k, m.GetExtension(k)); err != nil {
+			return err
+		}
+	}
+	for k, v := range m.extensions {
+		if err := w.SetExtension(k, v); err != nil {
+			return err
+		}
+	}
+	if tp := traceParent(ctx); tp != "" {
+		if err := w.SetExtension(extensions.TraceParentExtension, tp); err != nil {
+			return err
+		}
+	}
+
+	data, err := m.event.DataBytes()
+	if err != nil {
+		return err
+	}
+	if len(data) > 0 {
+		return w.SetData(bytes.NewReader(data))
+	}
+	return nil
+}
+
+// GetAttribute implements binding.MessageMetadataReader.
+func (m *eventMessage) GetAttribute(kind spec.Kind) (spec.Attribute, interface{}) {
+	attr := specV1.AttributeFromKind(kind)
+	if attr == nil {
+		return nil, nil
+	}
+	ec := m.ec
+	switch kind {
+	case spec.SpecVersion:
+		return attr, cloudevents.VersionV1
+	case spec.ID:
+		return attr, ec.ID
+	case spec.Source:
+		return attr, ec.Source.String()
+	case spec.Type:
+		return attr, ec.Type
+	case spec.DataContentType:
+		if ec.DataContentType != nil {
+			return attr, *ec.DataContentType
+		}
+	case spec.DataSchema:
+		if ec.DataSchema != nil {
+			return attr, ec.DataSchema.String()
+		}
+	case spec.Subject:
+		if ec.Subject != nil {
+			return attr, *ec.Subject
+		}
+	case spec.Time:
+		if ec.Time != nil && !ec.Time.IsZero() {
+			return attr, ec.Time.Time
+		}
+	}
+	return attr, nil
+}
+
+// GetExtension implements binding.MessageMetadataReader.
+func (m *eventMessage) GetExtension(name string) interface{} {
+	if v, ok := m.extensions[name]; ok {
+		return v
+	}
+	v, ok := m.ec.Extensions[name]
+	if !ok {
+		return nil
+	}
+	switch v.(type) {
+	case string, bool, int32:
+		return v
+	}
+	// Extensions of the sdk-go v1 types are read as strings.
+	s, err := cetypes.Format(v)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// Finish implements binding.Message.
+func (m *eventMessage) Finish(error) error {
+	return nil
+}
+
+// traceParent returns the traceparent extension of the span in ctx, if any.
+func traceParent(ctx context.Context) string {
+	span := trace.FromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return extensions.FromSpanContext(span.SpanContext()).TraceParent
+}
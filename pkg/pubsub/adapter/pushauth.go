@@ -0,0 +1,202 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2/google"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+)
+
+const (
+	// iamCredentialsEndpoint is the endpoint of the IAM Credentials API.
+	iamCredentialsEndpoint = "https://iamcredentials.googleapis.com"
+	// iamCredentialsScope is the OAuth scope of the IAM Credentials API.
+	iamCredentialsScope = "https://www.googleapis.com/auth/cloud-platform"
+	// pushAuthTokenRefresh is how long before their expiry the tokens are
+	// replaced, so that they don't expire in flight.
+	pushAuthTokenRefresh = 5 * time.Minute
+)
+
+// pushAuth emulates the authentication of push subscriptions, which send an
+// OIDC token of a service account in the Authorization header of every push
+// request. The tokens are Google-signed ID tokens of the service account,
+// generated with the IAM Credentials API, so the credentials of the adapter
+// need the Service Account Token Creator role on the service account.
+type pushAuth struct {
+	// serviceAccount is the email of the service account of the tokens.
+	serviceAccount string
+	// audience is the audience of the tokens.
+	audience string
+	// client is authenticated with the credentials of the adapter.
+	client *http.Client
+	// endpoint is the IAM Credentials endpoint override, if any. It defaults
+	// to iamCredentialsEndpoint.
+	endpoint string
+	// now defaults to time.Now; could be overridden in test.
+	now func() time.Time
+
+	mu sync.Mutex
+	// token is the last generated token, reused until pushAuthTokenRefresh
+	// before its expiry.
+	token  string
+	expiry time.Time
+	// refreshed is closed once the token being generated is cached, nil if
+	// no token is being generated.
+	refreshed chan struct{}
+}
+
+// newPushAuth returns the push authentication of the service account for the
+// audience, generating the tokens with the default credentials.
+func newPushAuth(ctx context.Context, serviceAccount, audience string) (*pushAuth, error) {
+	client, err := google.DefaultClient(ctx, iamCredentialsScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the IAM Credentials client: %w", err)
+	}
+	return &pushAuth{
+		serviceAccount: serviceAccount,
+		audience:       audience,
+		client:         client,
+		endpoint:       endpoints.IAMCredentials(),
+	}, nil
+}
+
+// authorization returns the value of the Authorization header of a push
+// request. Only one token is generated at a time, without holding the lock,
+// and the requests keep using the previous token until it expires.
+func (a *pushAuth) authorization(ctx context.Context) (string, error) {
+	now := time.Now()
+	if a.now != nil {
+		now = a.now()
+	}
+	for {
+		a.mu.Lock()
+		token, expiry, refreshed := a.token, a.expiry, a.refreshed
+		if token != "" && now.Before(expiry.Add(-pushAuthTokenRefresh)) {
+			a.mu.Unlock()
+			return "Bearer " + token, nil
+		}
+		if refreshed == nil {
+			a.refreshed = make(chan struct{})
+			a.mu.Unlock()
+			return a.refresh(ctx)
+		}
+		a.mu.Unlock()
+		if token != "" && now.Before(expiry) {
+			return "Bearer " + token, nil
+		}
+		// Wait for the token being generated, and generate one if that fails.
+		select {
+		case <-refreshed:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// refresh generates a token and caches it. It is only called by the
+// authorization that set refreshed.
+func (a *pushAuth) refresh(ctx context.Context) (string, error) {
+	token, expiry, err := a.generateIDToken(ctx)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if err == nil {
+		a.token, a.expiry = token, expiry
+	}
+	close(a.refreshed)
+	a.refreshed = nil
+	if err != nil {
+		return "", err
+	}
+	return "Bearer " + token, nil
+}
+
+// generateIDToken generates an ID token of the service account with the IAM
+// Credentials API, and returns it with its expiry.
+func (a *pushAuth) generateIDToken(ctx context.Context) (string, time.Time, error) {
+	endpoint := a.endpoint
+	if endpoint == "" {
+		endpoint = iamCredentialsEndpoint
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"audience":     a.audience,
+		"includeEmail": true,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	u := fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:generateIdToken", endpoint, url.PathEscape(a.serviceAccount))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate an ID token of %s: %w", a.serviceAccount, err)
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, fmt.Errorf("failed to generate an ID token of %s: %s: %s", a.serviceAccount, resp.Status, b)
+	}
+	var r struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode the ID token of %s: %w", a.serviceAccount, err)
+	}
+	expiry, err := tokenExpiry(r.Token)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode the ID token of %s: %w", a.serviceAccount, err)
+	}
+	return r.Token, expiry, nil
+}
+
+// tokenExpiry returns the expiry of a JWT, without verifying it.
+func tokenExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("token is not a JWT")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, err
+	}
+	var claims struct {
+		Expiry int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(b, &claims); err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(claims.Expiry, 0), nil
+}
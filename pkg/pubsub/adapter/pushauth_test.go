@@ -0,0 +1,205 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+// fakeIDToken returns an unsigned JWT expiring at expiry, standing in for the
+// ID tokens generated by the IAM Credentials API.
+func fakeIDToken(expiry time.Time) string {
+	claims := fmt.Sprintf(`{"exp":%d}`, expiry.Unix())
+	return "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+}
+
+func TestPushAuthSendsToken(t *testing.T) {
+	now := time.Date(2020, time.September, 1, 12, 0, 0, 0, time.UTC)
+	var generated int
+	var gotPath string
+	var gotRequest map[string]interface{}
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		generated++
+		gotPath = req.URL.EscapedPath()
+		if err := json.NewDecoder(req.Body).Decode(&gotRequest); err != nil {
+			t.Errorf("failed to decode generateIdToken request: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": fakeIDToken(now.Add(time.Hour))})
+	}))
+	defer iam.Close()
+
+	var gotAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotAuth = append(gotAuth, req.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	s := newHTTPSender(server.URL, converters.Push, nil)
+	s.pushAuth = &pushAuth{
+		serviceAccount: "push@my-project.iam.gserviceaccount.com",
+		audience:       "https://example.com",
+		client:         iam.Client(),
+		endpoint:       iam.URL,
+		now:            func() time.Time { return now },
+	}
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	for i := 0; i < 2; i++ {
+		if _, _, err := s.send(context.Background(), e); err != nil {
+			t.Fatalf("send() got error %v", err)
+		}
+	}
+
+	if generated != 1 {
+		t.Errorf("generated %d tokens, want the token reused", generated)
+	}
+	if want := "/v1/projects/-/serviceAccounts/push@my-project.iam.gserviceaccount.com:generateIdToken"; gotPath != want {
+		t.Errorf("generateIdToken path = %q, want %q", gotPath, want)
+	}
+	wantRequest := map[string]interface{}{"audience": "https://example.com", "includeEmail": true}
+	if diff := cmp.Diff(wantRequest, gotRequest); diff != "" {
+		t.Errorf("unexpected generateIdToken request (-want, +got): %s", diff)
+	}
+	wantAuth := "Bearer " + fakeIDToken(now.Add(time.Hour))
+	if diff := cmp.Diff([]string{wantAuth, wantAuth}, gotAuth); diff != "" {
+		t.Errorf("unexpected Authorization headers (-want, +got): %s", diff)
+	}
+
+	// The token is replaced before it expires.
+	now = now.Add(56 * time.Minute)
+	if _, err := s.pushAuth.authorization(context.Background()); err != nil {
+		t.Fatalf("authorization() got error %v", err)
+	}
+	if generated != 2 {
+		t.Errorf("generated %d tokens, want the token replaced before its expiry", generated)
+	}
+}
+
+func TestPushAuthConcurrentRefresh(t *testing.T) {
+	now := time.Date(2020, time.September, 1, 12, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	var generated int
+	release := make(chan struct{})
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		generated++
+		mu.Unlock()
+		<-release
+		json.NewEncoder(w).Encode(map[string]string{"token": fakeIDToken(now.Add(time.Hour))})
+	}))
+	defer iam.Close()
+
+	old := fakeIDToken(now.Add(2 * time.Minute))
+	a := &pushAuth{
+		serviceAccount: "push@my-project.iam.gserviceaccount.com",
+		audience:       "https://example.com",
+		client:         iam.Client(),
+		endpoint:       iam.URL,
+		now:            func() time.Time { return now },
+		token:          old,
+		expiry:         now.Add(2 * time.Minute),
+	}
+
+	// The first request refreshes the token, which is blocked on the IAM
+	// Credentials API.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := a.authorization(context.Background()); err != nil {
+			t.Errorf("authorization() got error %v", err)
+		}
+	}()
+	for {
+		mu.Lock()
+		g := generated
+		mu.Unlock()
+		if g > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The other requests keep using the token that has not expired yet.
+	for i := 0; i < 3; i++ {
+		got, err := a.authorization(context.Background())
+		if err != nil {
+			t.Fatalf("authorization() got error %v", err)
+		}
+		if want := "Bearer " + old; got != want {
+			t.Errorf("authorization() = %q, want %q", got, want)
+		}
+	}
+	close(release)
+	wg.Wait()
+
+	got, err := a.authorization(context.Background())
+	if err != nil {
+		t.Fatalf("authorization() got error %v", err)
+	}
+	if want := "Bearer " + fakeIDToken(now.Add(time.Hour)); got != want {
+		t.Errorf("authorization() = %q, want %q", got, want)
+	}
+	if generated != 1 {
+		t.Errorf("generated %d tokens, want 1", generated)
+	}
+}
+
+func TestPushAuthError(t *testing.T) {
+	iam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+	}))
+	defer iam.Close()
+
+	a := &pushAuth{
+		serviceAccount: "push@my-project.iam.gserviceaccount.com",
+		audience:       "https://example.com",
+		client:         iam.Client(),
+		endpoint:       iam.URL,
+	}
+	if _, err := a.authorization(context.Background()); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("authorization() got error %v, want the status of the IAM Credentials API", err)
+	}
+}
+
+func TestPushAuthAudience(t *testing.T) {
+	a := &Adapter{Sink: "http://sink"}
+	if got, want := a.pushAuthAudience(), "http://sink"; got != want {
+		t.Errorf("pushAuthAudience() = %q, want %q", got, want)
+	}
+	a.PushAuthAudience = "https://example.com"
+	if got, want := a.pushAuthAudience(), "https://example.com"; got != want {
+		t.Errorf("pushAuthAudience() = %q, want %q", got, want)
+	}
+}
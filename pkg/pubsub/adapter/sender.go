@@ -0,0 +1,192 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	nethttp "net/http"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+// defaultHTTPClient keeps enough idle connections to the sink to avoid
+// reconnecting under load.
+var defaultHTTPClient = &nethttp.Client{Transport: newTransport(nil)}
+
+// newTransport returns the transport of the clients sending events to the
+// sink, with tlsConfig if set.
+func newTransport(tlsConfig *tls.Config) nethttp.RoundTripper {
+	return &ochttp.Transport{
+		Base: &nethttp.Transport{
+			Proxy:               nethttp.ProxyFromEnvironment,
+			TLSClientConfig:     tlsConfig,
+			MaxIdleConns:        1000,
+			MaxIdleConnsPerHost: 500,
+			IdleConnTimeout:     30 * time.Second,
+		},
+		Propagation: &tracecontext.HTTPFormat{},
+	}
+}
+
+// newSinkHTTPClient returns the client sending events to the sink. It trusts
+// the PEM encoded CA certificates of the caBundle file in addition to the
+// system ones if set, and doesn't verify the certificate of the sink at all if
+// insecureSkipVerify.
+func newSinkHTTPClient(caBundle string, insecureSkipVerify bool) (*nethttp.Client, error) {
+	if caBundle == "" && !insecureSkipVerify {
+		return defaultHTTPClient, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+	if caBundle != "" {
+		pem, err := ioutil.ReadFile(caBundle)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no CA certificate found in %s", caBundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &nethttp.Client{Transport: newTransport(tlsConfig)}, nil
+}
+
+// httpSender sends the converted events to the sink.
+type httpSender struct {
+	client *nethttp.Client
+	target string
+	mode   converters.ModeType
+
+	// extensions override the extensions of the events in structured mode.
+	extensions map[string]string
+	// header holds the headers of the override extensions in binary mode,
+	// computed once rather than for every event.
+	header nethttp.Header
+	// pushAuth emulates the authentication of push subscriptions, if set.
+	pushAuth *pushAuth
+}
+
+func newHTTPSender(target string, mode converters.ModeType, extensions map[string]string) *httpSender {
+	s := &httpSender{
+		client: defaultHTTPClient,
+		target: target,
+		mode:   mode,
+	}
+	if mode == converters.Structured {
+		s.extensions = extensions
+		return s
+	}
+	s.header = make(nethttp.Header, len(extensions))
+	for k, v := range extensions {
+		s.header[nethttp.CanonicalHeaderKey("ce-"+k)] = []string{v}
+	}
+	return s
+}
+
+// send sends event to the target. It returns the status code of the response
+// and the event it holds, if any.
+func (s *httpSender) send(ctx context.Context, e cloudevents.Event) (int, *cloudevents.Event, error) {
+	if err := e.Validate(); err != nil {
+		return 0, nil, err
+	}
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, s.target, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	msg := newEventMessage(&e, s.extensions, s.mode == converters.Structured)
+	if err := cehttp.WriteRequest(ctx, msg, req); err != nil {
+		return 0, nil, err
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	if s.pushAuth != nil {
+		auth, err := s.pushAuth.authorization(ctx)
+		if err != nil {
+			return 0, nil, err
+		}
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		// Drain the body so that the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, nil, fmt.Errorf("error sending cloudevent: %s", nethttp.StatusText(resp.StatusCode))
+	}
+
+	respMsg := cehttp.NewMessageFromHttpResponse(resp)
+	if respMsg.ReadEncoding() == binding.EncodingUnknown {
+		// No reply.
+		return resp.StatusCode, nil, nil
+	}
+	reply, err := binding.ToEvent(ctx, respMsg)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+	return resp.StatusCode, toV1Event(reply), nil
+}
+
+// toV1Event converts a reply event to the event type of the receiver.
+func toV1Event(e *event.Event) *cloudevents.Event {
+	r := cloudevents.NewEvent(cloudevents.VersionV1)
+	r.SetID(e.ID())
+	r.SetSource(e.Source())
+	r.SetType(e.Type())
+	if v := e.DataContentType(); v != "" {
+		r.SetDataContentType(v)
+	}
+	if v := e.DataSchema(); v != "" {
+		r.SetDataSchema(v)
+	}
+	if v := e.Subject(); v != "" {
+		r.SetSubject(v)
+	}
+	if t := e.Time(); !t.IsZero() {
+		r.SetTime(t)
+	}
+	for k, v := range e.Extensions() {
+		r.SetExtension(k, v)
+	}
+	if data := e.Data(); len(data) > 0 {
+		r.Data = data
+		r.DataEncoded = true
+	}
+	return &r
+}
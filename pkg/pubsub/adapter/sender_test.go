@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSinkHTTPClient(t *testing.T) {
+	sink := httptest.NewTLSServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer sink.Close()
+
+	dir, err := ioutil.TempDir("", "sink-ca-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: sink.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	invalid := filepath.Join(dir, "invalid.crt")
+	if err := ioutil.WriteFile(invalid, []byte("not a certificate"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		caBundle           string
+		insecureSkipVerify bool
+		wantErr            bool
+		wantSendErr        bool
+	}{
+		"default": {
+			wantSendErr: true,
+		},
+		"CA bundle": {
+			caBundle: bundle,
+		},
+		"insecure skip verify": {
+			insecureSkipVerify: true,
+		},
+		"missing CA bundle": {
+			caBundle: filepath.Join(dir, "missing.crt"),
+			wantErr:  true,
+		},
+		"invalid CA bundle": {
+			caBundle: invalid,
+			wantErr:  true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			client, err := newSinkHTTPClient(tc.caBundle, tc.insecureSkipVerify)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("newSinkHTTPClient() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			resp, err := client.Post(sink.URL, "application/json", nil)
+			if (err != nil) != tc.wantSendErr {
+				t.Fatalf("Post() error = %v, wantErr %v", err, tc.wantSendErr)
+			}
+			if err == nil {
+				resp.Body.Close()
+			}
+		})
+	}
+}
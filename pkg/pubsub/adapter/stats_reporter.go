@@ -36,6 +36,14 @@ var (
 		stats.UnitDimensionless,
 	)
 
+	// attributeNormalizedCountM is a counter which records the number of
+	// event attributes changed to make the event valid.
+	attributeNormalizedCountM = stats.Int64(
+		"event_attribute_normalized_count",
+		"Number of event attributes escaped or truncated before the event was sent",
+		stats.UnitDimensionless,
+	)
+
 	// Create the tag keys that will be used to add tags to our measurements.
 	// Tag keys must conform to the restrictions described in
 	// go.opencensus.io/tag/validate.go. Currently those restrictions are:
@@ -48,6 +56,8 @@ var (
 	resourceGroupKey     = tag.MustNewKey(metricskey.LabelResourceGroup)
 	responseCodeKey      = tag.MustNewKey(metricskey.LabelResponseCode)
 	responseCodeClassKey = tag.MustNewKey(metricskey.LabelResponseCodeClass)
+	attributeKey         = tag.MustNewKey("attribute")
+	actionKey            = tag.MustNewKey("action")
 )
 
 type ReportArgs struct {
@@ -66,6 +76,9 @@ func init() {
 type StatsReporter interface {
 	// ReportEventCount captures the event count. It records one per call.
 	ReportEventCount(args *ReportArgs, responseCode int) error
+	// ReportAttributeNormalized captures a change to an event attribute. It
+	// records one per call.
+	ReportAttributeNormalized(args *ReportArgs, attribute, action string) error
 }
 
 var _ StatsReporter = (*reporter)(nil)
@@ -88,6 +101,23 @@ func (r *reporter) ReportEventCount(args *ReportArgs, responseCode int) error {
 	return nil
 }
 
+func (r *reporter) ReportAttributeNormalized(args *ReportArgs, attribute, action string) error {
+	ctx, err := tag.New(
+		emptyContext,
+		tag.Insert(namespaceKey, args.Namespace),
+		tag.Insert(eventSourceKey, args.EventSource),
+		tag.Insert(eventTypeKey, args.EventType),
+		tag.Insert(nameKey, args.Name),
+		tag.Insert(resourceGroupKey, args.ResourceGroup),
+		tag.Insert(attributeKey, attribute),
+		tag.Insert(actionKey, action))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, attributeNormalizedCountM.M(1))
+	return nil
+}
+
 func (r *reporter) generateTag(args *ReportArgs, responseCode int) (context.Context, error) {
 	return tag.New(
 		emptyContext,
@@ -118,6 +148,20 @@ func register() {
 			Aggregation: view.Count(),
 			TagKeys:     tagKeys,
 		},
+		&view.View{
+			Description: attributeNormalizedCountM.Description(),
+			Measure:     attributeNormalizedCountM,
+			Aggregation: view.Count(),
+			TagKeys: []tag.Key{
+				namespaceKey,
+				eventSourceKey,
+				eventTypeKey,
+				nameKey,
+				resourceGroupKey,
+				attributeKey,
+				actionKey,
+			},
+		},
 	); err != nil {
 		panic(err)
 	}
@@ -59,6 +59,35 @@ func TestStatsReporter(t *testing.T) {
 	metricstest.CheckCountData(t, "event_count", wantTags, 2)
 }
 
+func TestReportAttributeNormalized(t *testing.T) {
+	setup()
+
+	args := &ReportArgs{
+		Namespace:     "testns",
+		EventType:     "dev.knative.event",
+		EventSource:   "unit-test",
+		Name:          "testobject",
+		ResourceGroup: "testresourcegroup",
+	}
+
+	r := NewStatsReporter()
+
+	wantTags := map[string]string{
+		metricskey.LabelNamespaceName: "testns",
+		metricskey.LabelEventType:     "dev.knative.event",
+		metricskey.LabelEventSource:   "unit-test",
+		metricskey.LabelName:          "testobject",
+		metricskey.LabelResourceGroup: "testresourcegroup",
+		"attribute":                   "subject",
+		"action":                      "truncated",
+	}
+
+	expectSuccess(t, func() error {
+		return r.ReportAttributeNormalized(args, "subject", "truncated")
+	})
+	metricstest.CheckCountData(t, "event_attribute_normalized_count", wantTags, 1)
+}
+
 func expectSuccess(t *testing.T, f func() error) {
 	t.Helper()
 	if err := f(); err != nil {
@@ -72,6 +101,6 @@ func setup() {
 
 func resetMetrics() {
 	// OpenCensus metrics carry global state that need to be reset between unit tests.
-	metricstest.Unregister("event_count")
+	metricstest.Unregister("event_count", "event_attribute_normalized_count")
 	register()
 }
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsub"
+	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
+	pscontext "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub/context"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+// orderedTransport is the Pub/Sub transport of the inbound client. It pulls
+// messages like the CloudEvents transport does, but also passes the ordering
+// key of each message on to the converter and the receiver, which the
+// CloudEvents transport drops. The Pub/Sub client already hands the messages
+// of an ordering key over one at a time, in order, when the subscription has
+// message ordering enabled.
+type orderedTransport struct {
+	*cepubsub.Transport
+
+	client       *pubsub.Client
+	project      string
+	topic        string
+	subscription string
+	codec        *cepubsub.Codec
+
+	// maxOutstandingMessages overrides the flow control of the Pub/Sub
+	// client if it is not 0.
+	maxOutstandingMessages int
+
+	// liteSubscription is the path of the subscription if it is a Pub/Sub
+	// Lite one, which is pulled by a subscriber of newLiteSubscriber instead
+	// of client.
+	liteSubscription  string
+	newLiteSubscriber gpubsublite.SubscriberFn
+}
+
+// StartReceiver pulls messages off the subscription until ctx is done.
+func (t *orderedTransport) StartReceiver(ctx context.Context) error {
+	if t.liteSubscription != "" {
+		return t.startLiteReceiver(ctx)
+	}
+	sub := t.client.Subscription(t.subscription)
+	ok, err := sub.Exists(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check whether subscription %q exists: %w", t.subscription, err)
+	}
+	if !ok {
+		return fmt.Errorf("subscription %q does not exist", t.subscription)
+	}
+	if t.maxOutstandingMessages > 0 {
+		sub.ReceiveSettings.MaxOutstandingMessages = t.maxOutstandingMessages
+	}
+	return sub.Receive(ctx, t.receive)
+}
+
+func (t *orderedTransport) receive(ctx context.Context, m *pubsub.Message) {
+	logger := logging.FromContext(ctx).With(zap.String("message.id", m.ID))
+	ctx = pscontext.WithTransportContext(ctx, pscontext.NewTransportContext(t.project, t.topic, t.subscription, "pull", m))
+	ctx = converters.WithOrderingKey(ctx, m.OrderingKey)
+	msg := &cepubsub.Message{
+		Attributes: m.Attributes,
+		Data:       m.Data,
+	}
+	event, err := t.codec.Decode(ctx, msg)
+	// If the codec fails, try with the converter.
+	if err != nil && t.HasConverter() {
+		event, err = t.Converter.Convert(ctx, msg, err)
+	}
+	if err != nil {
+		logger.Desugar().Error("Failed to decode message", zap.Error(err))
+		m.Nack()
+		return
+	}
+	if err := t.Receiver.Receive(ctx, *event, nil); err != nil {
+		logger.Desugar().Warn("Failed to receive event", zap.Error(err))
+		m.Nack()
+		return
+	}
+	m.Ack()
+}
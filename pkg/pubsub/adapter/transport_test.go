@@ -0,0 +1,209 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+)
+
+func TestReceiveOrderingKey(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	topic, err := client.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	topic.EnableMessageOrdering = true
+	defer topic.Stop()
+	for _, tc := range []struct {
+		name     string
+		sub      string
+		sendMode converters.ModeType
+		key      string
+		want     string
+	}{{
+		name:     "binary",
+		sub:      "sub-binary",
+		sendMode: converters.Binary,
+		key:      "order-1",
+		want:     "order-1",
+	}, {
+		name:     "no ordering key",
+		sub:      "sub-no-key",
+		sendMode: converters.Binary,
+	}, {
+		name:     "push",
+		sub:      "sub-push",
+		sendMode: converters.Push,
+		key:      "order-1",
+	}} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Each case has its own subscription, so that it doesn't receive
+			// the messages of the others.
+			sub, err := client.CreateSubscription(ctx, tc.sub, pubsub.SubscriptionConfig{
+				Topic:                 topic,
+				EnableMessageOrdering: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sub.Delete(ctx)
+			got := make(chan string, 1)
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got <- r.Header.Get("Ce-Orderingkey")
+			}))
+			defer sink.Close()
+
+			a := &Adapter{
+				Project:      "test-project",
+				Topic:        "topic",
+				Subscription: tc.sub,
+				SendMode:     tc.sendMode,
+				client:       client,
+				reporter:     &mockStatsReporter{},
+			}
+			a.outbound = newHTTPSender(sink.URL, a.SendMode, nil)
+			inbound, err := a.newPubSubClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rctx, stop := context.WithCancel(ctx)
+			done := make(chan error, 1)
+			go func() {
+				done <- inbound.StartReceiver(rctx, a.receive)
+			}()
+			defer func() {
+				stop()
+				<-done
+			}()
+
+			if _, err := topic.Publish(ctx, &pubsub.Message{Data: []byte("hello"), OrderingKey: tc.key}).Get(ctx); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case key := <-got:
+				if key != tc.want {
+					t.Errorf("ordering key extension got %q want %q", key, tc.want)
+				}
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for the event")
+			}
+		})
+	}
+}
+
+func TestReceiveMaxOutstandingMessages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	srv := pstest.NewServer()
+	defer srv.Close()
+	conn, err := grpc.Dial(srv.Addr, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client, err := pubsub.NewClient(ctx, "test-project", option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	topic, err := client.CreateTopic(ctx, "topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer topic.Stop()
+	if _, err := client.CreateSubscription(ctx, "sub", pubsub.SubscriptionConfig{Topic: topic}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The sink holds the deliveries until the test is done, so that only
+	// the outstanding messages reach it.
+	received := make(chan struct{}, 3)
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer sink.Close()
+
+	a := &Adapter{
+		Project:                "test-project",
+		Topic:                  "topic",
+		Subscription:           "sub",
+		SendMode:               converters.Binary,
+		MaxOutstandingMessages: 2,
+		client:                 client,
+		reporter:               &mockStatsReporter{},
+	}
+	a.outbound = newHTTPSender(sink.URL, a.SendMode, nil)
+	inbound, err := a.newPubSubClient(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rctx, stop := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- inbound.StartReceiver(rctx, a.receive)
+	}()
+	defer func() {
+		stop()
+		<-done
+	}()
+	// Release the held deliveries before the receiver is stopped.
+	defer close(release)
+
+	for i := 0; i < 3; i++ {
+		if _, err := topic.Publish(ctx, &pubsub.Message{Data: []byte("hello")}).Get(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-received:
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for the events")
+		}
+	}
+	select {
+	case <-received:
+		t.Error("received more events than the max outstanding messages")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+)
+
+// errBufferFull is returned when an event doesn't fit in the buffer.
+var errBufferFull = errors.New("publish buffer is full")
+
+// buffer holds events that couldn't be published, one file per event, until
+// they can be. The files are named after a counter seeded with the time, so
+// that they sort in the order the events were buffered, across restarts too.
+type buffer struct {
+	dir  string
+	size int
+
+	mu  sync.Mutex
+	n   int
+	seq int64
+}
+
+// newBuffer returns a buffer of up to size events in dir. Events buffered in
+// dir by a previous publisher are kept.
+func newBuffer(dir string, size int) (*buffer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// Remove the partial events of a publisher that died while writing them.
+	partial, _ := filepath.Glob(filepath.Join(dir, ".*.json"))
+	for _, f := range partial {
+		os.Remove(f)
+	}
+	names, err := bufferedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &buffer{
+		dir:  dir,
+		size: size,
+		n:    len(names),
+		seq:  time.Now().UnixNano(),
+	}, nil
+}
+
+// len returns the number of buffered events.
+func (b *buffer) len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.n
+}
+
+// add buffers event. It returns errBufferFull if the buffer is full.
+func (b *buffer) add(event cloudevents.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.n >= b.size {
+		return errBufferFull
+	}
+	b.seq++
+	name := fmt.Sprintf("%016x.json", b.seq)
+	// Write to a hidden file first so that drain never reads a partial event.
+	tmp := filepath.Join(b.dir, "."+name)
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(b.dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	b.n++
+	return nil
+}
+
+// drain publishes the buffered events in order and removes the published
+// ones. It stops at the first event that fails to publish, as the following
+// ones would most likely fail too, and returns the number of published events.
+// Events that can't be parsed are logged and dropped.
+func (b *buffer) drain(ctx context.Context, publish func(context.Context, cloudevents.Event) error) (int, error) {
+	names, err := bufferedFiles(b.dir)
+	if err != nil {
+		return 0, err
+	}
+	published := 0
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return published, ctx.Err()
+		}
+		path := filepath.Join(b.dir, name)
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return published, err
+		}
+		var event cloudevents.Event
+		if err := json.Unmarshal(data, &event); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Dropping unparsable buffered event", zap.String("file", name), zap.Error(err))
+		} else if err := publish(ctx, event); err != nil {
+			return published, err
+		} else {
+			published++
+		}
+		if err := os.Remove(path); err != nil {
+			return published, err
+		}
+		b.mu.Lock()
+		b.n--
+		b.mu.Unlock()
+	}
+	return published, nil
+}
+
+// bufferedFiles returns the names of the buffered event files in dir, oldest
+// first.
+func bufferedFiles(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && !strings.HasPrefix(f.Name(), ".") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
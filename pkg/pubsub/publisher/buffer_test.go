@@ -0,0 +1,70 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBufferKeepsEventsAcrossRestarts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	b, err := newBuffer(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"1", "2"} {
+		if err := b.add(newEvent(id)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A partial event of a publisher that died while writing it.
+	if err := ioutil.WriteFile(dir+"/.0000000000000003.json", []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := newBuffer(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := restarted.len(); got != 2 {
+		t.Errorf("buffered events after restart = %d, want 2", got)
+	}
+	if err := restarted.add(newEvent("3")); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	n, err := restarted.drain(context.Background(), func(_ context.Context, e cloudevents.Event) error {
+		got = append(got, e.ID())
+		return nil
+	})
+	if err != nil || n != 3 {
+		t.Errorf("drain() = (%d, %v), want (3, nil)", n, err)
+	}
+	if diff := cmp.Diff([]string{"1", "2", "3"}, got); diff != "" {
+		t.Errorf("unexpected drained events (-want,+got): %v", diff)
+	}
+}
@@ -18,15 +18,28 @@ package publisher
 
 import (
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 	"knative.dev/pkg/logging"
 
 	"context"
 
+	"cloud.google.com/go/pubsub"
 	cloudevents "github.com/cloudevents/sdk-go"
 	cepubsub "github.com/cloudevents/sdk-go/pkg/cloudevents/transport/pubsub"
 	"knative.dev/eventing/pkg/kncloudevents"
+
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	defaultReplayInterval = 30 * time.Second
+	// shutdownDrainTimeout bounds the last attempt to publish the buffered
+	// events when the publisher stops.
+	shutdownDrainTimeout = 10 * time.Second
 )
 
 // Publisher implements the Pub/Sub adapter to deliver Pub/Sub messages from a
@@ -37,10 +50,32 @@ type Publisher struct {
 	// TopicID is the pre-existing eventing pub/sub topic id to use.
 	TopicID string
 
+	// DeadLetterTopicID is the topic events are published to when they
+	// can't be published to TopicID, e.g. because of quota or permission
+	// errors. Either a topic ID in ProjectID or the full name of a topic in
+	// another project. Optional.
+	DeadLetterTopicID string
+	// BufferDir is the directory events are buffered in when they can be
+	// published to neither topic.
+	BufferDir string
+	// BufferSize is the maximum number of buffered events. Events are not
+	// buffered if zero.
+	BufferSize int
+	// ReplayInterval is the interval at which buffered events are published
+	// again.
+	ReplayInterval time.Duration
+
 	// inbound is the cloudevents client to use to receive events.
 	inbound cloudevents.Client
 	// outbound is the cloudevents client to use to send events.
 	outbound cloudevents.Client
+	// deadLetter is the cloudevents client to send events to the dead letter
+	// topic. Nil if there is none.
+	deadLetter cloudevents.Client
+	// buffer holds the events that couldn't be published. Nil if disabled.
+	buffer *buffer
+	// reporter reports metrics to the configured backend.
+	reporter StatsReporter
 }
 
 func (a *Publisher) Start(ctx context.Context) error {
@@ -55,30 +90,143 @@ func (a *Publisher) Start(ctx context.Context) error {
 
 	// Send Events on Pub/Sub.
 	if a.outbound == nil {
-		if a.outbound, err = a.newPubSubClient(ctx); err != nil {
+		if a.outbound, err = a.newPubSubClient(ctx, a.ProjectID, a.TopicID); err != nil {
 			return fmt.Errorf("failed to create outbound cloudevent client: %w", err)
 		}
 	}
 
+	if a.DeadLetterTopicID != "" && a.deadLetter == nil {
+		project, topic, err := utils.ParseTopic(a.DeadLetterTopicID)
+		if err != nil {
+			return fmt.Errorf("invalid dead letter topic: %w", err)
+		}
+		if project == "" {
+			project = a.ProjectID
+		}
+		if a.deadLetter, err = a.newPubSubClient(ctx, project, topic); err != nil {
+			return fmt.Errorf("failed to create dead letter cloudevent client: %w", err)
+		}
+	}
+
+	if a.reporter == nil {
+		a.reporter = NewStatsReporter()
+	}
+
+	if a.BufferSize > 0 && a.buffer == nil {
+		if a.buffer, err = newBuffer(a.BufferDir, a.BufferSize); err != nil {
+			return fmt.Errorf("failed to create publish buffer: %w", err)
+		}
+	}
+	if a.buffer != nil {
+		if a.ReplayInterval <= 0 {
+			a.ReplayInterval = defaultReplayInterval
+		}
+		done := make(chan struct{})
+		defer func() {
+			<-done
+			// Try once more, events left in the buffer are only published
+			// if the next publisher gets the same directory.
+			dctx, cancel := context.WithTimeout(logging.WithLogger(context.Background(), logging.FromContext(ctx)), shutdownDrainTimeout)
+			defer cancel()
+			a.replay(dctx)
+		}()
+		go func() {
+			defer close(done)
+			a.replayLoop(ctx)
+		}()
+	}
+
 	return a.inbound.StartReceiver(ctx, a.receive)
 }
 
 func (a *Publisher) receive(ctx context.Context, event cloudevents.Event, resp *cloudevents.EventResponse) error {
-	if _, r, err := a.outbound.Send(ctx, event); err != nil {
-		logging.FromContext(ctx).Desugar().Error("Error publishing to PubSub", zap.String("event", event.String()), zap.Error(err))
-		return err
-	} else if r != nil {
-		resp.RespondWith(200, r)
+	r, err := a.publish(ctx, event)
+	if err == nil {
+		if r != nil {
+			resp.RespondWith(200, r)
+		}
+		return nil
 	}
 
+	if a.buffer == nil {
+		a.reporter.ReportFallback(a.TopicID, resultDropped)
+		return err
+	}
+	if berr := a.buffer.add(event); berr != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to buffer event", zap.String("event.id", event.ID()), zap.Error(berr))
+		a.reporter.ReportFallback(a.TopicID, resultDropped)
+		return err
+	}
+	a.reporter.ReportFallback(a.TopicID, resultBuffered)
+	a.reporter.ReportBufferedEvents(a.TopicID, a.buffer.len())
+	// The event isn't lost, but it isn't published yet either.
+	resp.RespondWith(http.StatusAccepted, nil)
 	return nil
 }
 
-func (a *Publisher) newPubSubClient(ctx context.Context) (cloudevents.Client, error) {
+// publish publishes event to the topic, or to the dead letter topic if that
+// fails. The error of the topic is returned if both fail.
+func (a *Publisher) publish(ctx context.Context, event cloudevents.Event) (*cloudevents.Event, error) {
+	_, r, err := a.outbound.Send(ctx, event)
+	if err == nil {
+		return r, nil
+	}
+	logging.FromContext(ctx).Desugar().Error("Error publishing to PubSub", zap.String("event", event.String()), zap.Error(err))
+	if a.deadLetter == nil {
+		return nil, err
+	}
+	if _, _, dlErr := a.deadLetter.Send(ctx, event); dlErr != nil {
+		logging.FromContext(ctx).Desugar().Error("Error publishing to the dead letter topic", zap.String("event.id", event.ID()), zap.Error(dlErr))
+		return nil, err
+	}
+	a.reporter.ReportFallback(a.TopicID, resultDeadLetter)
+	return nil, nil
+}
+
+// replayLoop publishes the buffered events every ReplayInterval until ctx is
+// done.
+func (a *Publisher) replayLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.ReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.replay(ctx)
+		}
+	}
+}
+
+// replay publishes the buffered events.
+func (a *Publisher) replay(ctx context.Context) {
+	if a.buffer.len() == 0 {
+		return
+	}
+	n, err := a.buffer.drain(ctx, func(ctx context.Context, event cloudevents.Event) error {
+		_, err := a.publish(ctx, event)
+		return err
+	})
+	for i := 0; i < n; i++ {
+		a.reporter.ReportFallback(a.TopicID, resultReplayed)
+	}
+	a.reporter.ReportBufferedEvents(a.TopicID, a.buffer.len())
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Warn("Failed to publish buffered events", zap.Int("published", n), zap.Int("buffered", a.buffer.len()), zap.Error(err))
+	}
+}
+
+func (a *Publisher) newPubSubClient(ctx context.Context, projectID, topicID string) (cloudevents.Client, error) {
+	// Create the Pub/Sub client here so that API endpoint overrides apply.
+	client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
+	if err != nil {
+		return nil, err
+	}
 	tOpts := []cepubsub.Option{
+		cepubsub.WithClient(client),
 		cepubsub.WithBinaryEncoding(),
-		cepubsub.WithProjectID(a.ProjectID),
-		cepubsub.WithTopicID(a.TopicID),
+		cepubsub.WithProjectID(projectID),
+		cepubsub.WithTopicID(topicID),
 	}
 
 	// Make a pubsub transport for the CloudEvents client.
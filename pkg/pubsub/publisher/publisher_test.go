@@ -0,0 +1,206 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeClient struct {
+	err  error
+	sent []string
+}
+
+func (c *fakeClient) Send(ctx context.Context, event cloudevents.Event) (context.Context, *cloudevents.Event, error) {
+	if c.err != nil {
+		return ctx, nil, c.err
+	}
+	c.sent = append(c.sent, event.ID())
+	return ctx, nil, nil
+}
+
+func (c *fakeClient) StartReceiver(ctx context.Context, fn interface{}) error {
+	return nil
+}
+
+type fakeStatsReporter struct {
+	results  []string
+	buffered int
+}
+
+func (r *fakeStatsReporter) ReportFallback(topic, result string) error {
+	r.results = append(r.results, result)
+	return nil
+}
+
+func (r *fakeStatsReporter) ReportBufferedEvents(topic string, n int) error {
+	r.buffered = n
+	return nil
+}
+
+func newEvent(id string) cloudevents.Event {
+	e := cloudevents.NewEvent(cloudevents.VersionV1)
+	e.SetID(id)
+	e.SetType("type")
+	e.SetSource("source")
+	return e
+}
+
+func TestReceive(t *testing.T) {
+	errPublish := errors.New("quota exceeded")
+	cases := []struct {
+		name           string
+		outboundErr    error
+		deadLetter     *fakeClient
+		bufferSize     int
+		wantErr        bool
+		wantStatus     int
+		wantOutbound   []string
+		wantDeadLetter []string
+		wantResults    []string
+		wantBuffered   int
+	}{{
+		name:         "published",
+		wantOutbound: []string{"1"},
+	}, {
+		name:        "no fallback",
+		outboundErr: errPublish,
+		wantErr:     true,
+		wantResults: []string{resultDropped},
+	}, {
+		name:           "dead letter topic",
+		outboundErr:    errPublish,
+		deadLetter:     &fakeClient{},
+		wantDeadLetter: []string{"1"},
+		wantResults:    []string{resultDeadLetter},
+	}, {
+		name:         "dead letter topic fails, buffered",
+		outboundErr:  errPublish,
+		deadLetter:   &fakeClient{err: errPublish},
+		bufferSize:   10,
+		wantStatus:   http.StatusAccepted,
+		wantResults:  []string{resultBuffered},
+		wantBuffered: 1,
+	}, {
+		name:        "buffer full",
+		outboundErr: errPublish,
+		bufferSize:  -1,
+		wantErr:     true,
+		wantResults: []string{resultDropped},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			outbound := &fakeClient{err: tc.outboundErr}
+			reporter := &fakeStatsReporter{}
+			p := &Publisher{
+				TopicID:  "topic",
+				outbound: outbound,
+				reporter: reporter,
+			}
+			if tc.deadLetter != nil {
+				p.deadLetter = tc.deadLetter
+			}
+			if tc.bufferSize != 0 {
+				dir, err := ioutil.TempDir("", "publisher")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer os.RemoveAll(dir)
+				// A negative size makes a buffer that is always full.
+				if p.buffer, err = newBuffer(dir, tc.bufferSize); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resp := &cloudevents.EventResponse{}
+			err := p.receive(context.Background(), newEvent("1"), resp)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("receive() = %v, want error %v", err, tc.wantErr)
+			}
+			if resp.Status != tc.wantStatus {
+				t.Errorf("response status = %d, want %d", resp.Status, tc.wantStatus)
+			}
+			if diff := cmp.Diff(tc.wantOutbound, outbound.sent); diff != "" {
+				t.Errorf("unexpected published events (-want,+got): %v", diff)
+			}
+			if tc.deadLetter != nil {
+				if diff := cmp.Diff(tc.wantDeadLetter, tc.deadLetter.sent); diff != "" {
+					t.Errorf("unexpected dead letter events (-want,+got): %v", diff)
+				}
+			}
+			if diff := cmp.Diff(tc.wantResults, reporter.results); diff != "" {
+				t.Errorf("unexpected fallback results (-want,+got): %v", diff)
+			}
+			if reporter.buffered != tc.wantBuffered {
+				t.Errorf("buffered events = %d, want %d", reporter.buffered, tc.wantBuffered)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "publisher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	b, err := newBuffer(dir, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outbound := &fakeClient{err: errors.New("permission denied")}
+	reporter := &fakeStatsReporter{}
+	p := &Publisher{
+		TopicID:  "topic",
+		outbound: outbound,
+		buffer:   b,
+		reporter: reporter,
+	}
+	for _, id := range []string{"1", "2", "3"} {
+		if err := p.receive(context.Background(), newEvent(id), &cloudevents.EventResponse{}); err != nil {
+			t.Fatalf("receive() = %v", err)
+		}
+	}
+
+	// The topic is still failing, nothing is replayed.
+	p.replay(context.Background())
+	if got := b.len(); got != 3 {
+		t.Errorf("buffered events = %d, want 3", got)
+	}
+
+	outbound.err = nil
+	p.replay(context.Background())
+	if diff := cmp.Diff([]string{"1", "2", "3"}, outbound.sent); diff != "" {
+		t.Errorf("unexpected replayed events (-want,+got): %v", diff)
+	}
+	if got := b.len(); got != 0 {
+		t.Errorf("buffered events = %d, want 0", got)
+	}
+	wantResults := []string{resultBuffered, resultBuffered, resultBuffered, resultReplayed, resultReplayed, resultReplayed}
+	if diff := cmp.Diff(wantResults, reporter.results); diff != "" {
+		t.Errorf("unexpected fallback results (-want,+got): %v", diff)
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publisher
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+// The results of events the publisher couldn't publish to its topic.
+const (
+	// resultDeadLetter is an event published to the dead letter topic.
+	resultDeadLetter = "dead_letter"
+	// resultBuffered is an event buffered locally.
+	resultBuffered = "buffered"
+	// resultReplayed is a buffered event published after all.
+	resultReplayed = "replayed"
+	// resultDropped is an event the producer got an error for.
+	resultDropped = "dropped"
+)
+
+var (
+	// publishFallbackCountM is a counter which records the number of events
+	// that couldn't be published to the topic, by what happened to them.
+	publishFallbackCountM = stats.Int64(
+		"publish_fallback_count",
+		"Number of events that could not be published to the topic",
+		stats.UnitDimensionless,
+	)
+
+	// bufferedEventsM records the number of events buffered locally.
+	bufferedEventsM = stats.Int64(
+		"publish_buffered_events",
+		"Number of events buffered locally until they can be published",
+		stats.UnitDimensionless,
+	)
+
+	topicKey  = tag.MustNewKey("topic_id")
+	resultKey = tag.MustNewKey("result")
+)
+
+func init() {
+	register()
+}
+
+// StatsReporter defines the interface for sending metrics.
+type StatsReporter interface {
+	// ReportFallback captures an event that couldn't be published to topic.
+	// It records one per call.
+	ReportFallback(topic, result string) error
+	// ReportBufferedEvents captures the number of events buffered for topic.
+	ReportBufferedEvents(topic string, n int) error
+}
+
+var _ StatsReporter = (*reporter)(nil)
+
+// reporter holds cached metric objects to report metrics.
+type reporter struct{}
+
+// NewStatsReporter creates a reporter that collects and reports metrics.
+func NewStatsReporter() StatsReporter {
+	return &reporter{}
+}
+
+func (r *reporter) ReportFallback(topic, result string) error {
+	ctx, err := tag.New(context.Background(),
+		tag.Insert(topicKey, topic),
+		tag.Insert(resultKey, result))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, publishFallbackCountM.M(1))
+	return nil
+}
+
+func (r *reporter) ReportBufferedEvents(topic string, n int) error {
+	ctx, err := tag.New(context.Background(), tag.Insert(topicKey, topic))
+	if err != nil {
+		return err
+	}
+	metrics.Record(ctx, bufferedEventsM.M(int64(n)))
+	return nil
+}
+
+func register() {
+	if err := metrics.RegisterResourceView(
+		&view.View{
+			Description: publishFallbackCountM.Description(),
+			Measure:     publishFallbackCountM,
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{topicKey, resultKey},
+		},
+		&view.View{
+			Description: bufferedEventsM.Description(),
+			Measure:     bufferedEventsM,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{topicKey},
+		},
+	); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apply server-side applies the resources generated by the
+// reconcilers, so that the controller only owns the fields it sets and
+// leaves fields managed by users or other controllers alone.
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// FieldManager is the field manager that owns the fields set by the
+// controller.
+const FieldManager = "cloud-run-events-controller"
+
+var force = true
+
+// Apply server-side applies obj as FieldManager and stores the resulting
+// object into out. Conflicting fields are forced to the applied values, since
+// obj only contains the fields the controller owns.
+func Apply(client dynamic.Interface, obj runtime.Object, out runtime.Object) error {
+	gvk, err := kindOf(obj)
+	if err != nil {
+		return err
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	data, err := Patch(obj)
+	if err != nil {
+		return err
+	}
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+	u, err := client.Resource(gvr).Namespace(accessor.GetNamespace()).Patch(accessor.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: FieldManager,
+		Force:        &force,
+	})
+	if err != nil {
+		return err
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), out)
+}
+
+// Changed returns true if any field the controller sets on desired differs
+// from current, i.e. if desired needs to be applied. Fields that are only set
+// on current, by users or other controllers, are ignored.
+func Changed(desired, current metav1.Object, desiredSpec, currentSpec interface{}) bool {
+	return !equality.Semantic.DeepDerivative(desiredSpec, currentSpec) ||
+		!equality.Semantic.DeepDerivative(desired.GetLabels(), current.GetLabels()) ||
+		!equality.Semantic.DeepDerivative(desired.GetAnnotations(), current.GetAnnotations())
+}
+
+// Patch returns the apply patch of obj. The patch contains the type, the
+// metadata identifying obj and the fields the controller owns, but no status.
+func Patch(obj runtime.Object) ([]byte, error) {
+	gvk, err := kindOf(obj)
+	if err != nil {
+		return nil, err
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+	// The status is owned by the controllers of the generated resources.
+	unstructured.RemoveNestedField(u.Object, "status")
+	pruneNulls(u.Object)
+	return json.Marshal(u.Object)
+}
+
+// pruneNulls removes null values, e.g. zero creation timestamps, from m so
+// that the patch only contains the fields the controller sets.
+func pruneNulls(m map[string]interface{}) {
+	for k, v := range m {
+		switch v := v.(type) {
+		case nil:
+			delete(m, k)
+		case map[string]interface{}:
+			pruneNulls(v)
+		case []interface{}:
+			for _, e := range v {
+				if em, ok := e.(map[string]interface{}); ok {
+					pruneNulls(em)
+				}
+			}
+		}
+	}
+}
+
+func kindOf(obj runtime.Object) (schema.GroupVersionKind, error) {
+	gvks, _, err := scheme.Scheme.ObjectKinds(obj)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if len(gvks) == 0 {
+		return schema.GroupVersionKind{}, fmt.Errorf("unknown kind of %T", obj)
+	}
+	return gvks[0], nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apply
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+)
+
+func newDeployment(labels map[string]string, minReadySeconds int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testns",
+			Name:      "test",
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{MinReadySeconds: minReadySeconds},
+	}
+}
+
+func TestPatch(t *testing.T) {
+	d := newDeployment(map[string]string{"app": "test"}, 10)
+	d.Status.Replicas = 3
+
+	got, err := Patch(d)
+	if err != nil {
+		t.Fatalf("Patch() = %v", err)
+	}
+	want := `{"apiVersion":"apps/v1","kind":"Deployment",` +
+		`"metadata":{"labels":{"app":"test"},"name":"test","namespace":"testns"},` +
+		`"spec":{"minReadySeconds":10,"strategy":{},"template":{"metadata":{},"spec":{}}}}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("Patch() (-want,+got): %s", diff)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	cases := []struct {
+		name    string
+		desired *appsv1.Deployment
+		current *appsv1.Deployment
+		want    bool
+	}{{
+		name:    "same",
+		desired: newDeployment(map[string]string{"app": "test"}, 10),
+		current: newDeployment(map[string]string{"app": "test"}, 10),
+	}, {
+		name:    "fields set by others",
+		desired: newDeployment(map[string]string{"app": "test"}, 10),
+		current: newDeployment(map[string]string{"app": "test", "team": "other"}, 10),
+	}, {
+		name:    "spec changed",
+		desired: newDeployment(nil, 20),
+		current: newDeployment(nil, 10),
+		want:    true,
+	}, {
+		name:    "label changed",
+		desired: newDeployment(map[string]string{"app": "new"}, 10),
+		current: newDeployment(map[string]string{"app": "test"}, 10),
+		want:    true,
+	}}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Changed(tc.desired, tc.current, tc.desired.Spec, tc.current.Spec); got != tc.want {
+				t.Errorf("Changed() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	client := dynamicfake.NewSimpleDynamicClient(scheme.Scheme)
+	var gotPatch clientgotesting.PatchAction
+	client.PrependReactor("patch", "deployments", func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		gotPatch = action.(clientgotesting.PatchAction)
+		applied := newDeployment(map[string]string{"app": "test"}, 10)
+		applied.TypeMeta = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+		applied.Status.Replicas = 1
+		return true, applied, nil
+	})
+
+	d := newDeployment(map[string]string{"app": "test"}, 10)
+	got := &appsv1.Deployment{}
+	if err := Apply(client, d, got); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if gotPatch == nil {
+		t.Fatal("Apply() didn't patch the deployment")
+	}
+	if gotPatch.GetNamespace() != "testns" || gotPatch.GetName() != "test" {
+		t.Errorf("Apply() patched %s/%s, want testns/test", gotPatch.GetNamespace(), gotPatch.GetName())
+	}
+	wantPatch, _ := Patch(d)
+	if diff := cmp.Diff(string(wantPatch), string(gotPatch.GetPatch())); diff != "" {
+		t.Errorf("Apply() patch (-want,+got): %s", diff)
+	}
+	if got.Status.Replicas != 1 {
+		t.Errorf("Apply() result replicas = %d, want 1", got.Status.Replicas)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/pubsub"
@@ -33,24 +34,35 @@ import (
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	"knative.dev/eventing/pkg/apis/eventing"
 	"knative.dev/eventing/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
 	"knative.dev/pkg/system"
+	"knative.dev/serving/pkg/apis/serving"
+	servinglisters "knative.dev/serving/pkg/client/listers/serving/v1"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
 	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 	"github.com/google/knative-gcp/pkg/utils"
 )
@@ -68,10 +80,10 @@ const (
 
 // Hard-coded for now. TODO(https://github.com/google/knative-gcp/issues/863)
 // BrokerCell will handle this.
-var dataPlaneDeployments = []string{
-	brokercellresources.Name(resources.DefaultBroekrCellName, brokercellresources.IngressName),
-	brokercellresources.Name(resources.DefaultBroekrCellName, brokercellresources.FanoutName),
-	brokercellresources.Name(resources.DefaultBroekrCellName, brokercellresources.RetryName),
+var dataPlaneComponents = []string{
+	brokercellresources.IngressName,
+	brokercellresources.FanoutName,
+	brokercellresources.RetryName,
 }
 
 // TODO
@@ -92,6 +104,8 @@ type Reconciler struct {
 	deploymentLister appsv1listers.DeploymentLister
 	podLister        corev1listers.PodLister
 	brokerCellLister inteventslisters.BrokerCellLister
+	serviceLister    servinglisters.ServiceLister
+	secretLister     corev1listers.SecretLister
 
 	// TODO allow configuring multiples of these
 	targetsConfig config.Targets
@@ -105,6 +119,33 @@ type Reconciler struct {
 
 	// pubsubClient is used as the Pubsub client when present.
 	pubsubClient *pubsub.Client
+
+	// uriResolver resolves the additional subscribers of triggers.
+	uriResolver *resolver.URIResolver
+
+	// deliveryHeaders holds the resolved delivery headers of the triggers of
+	// each broker until they are written to the delivery headers secret,
+	// keyed by broker key and then by delivery headers key.
+	deliveryHeadersMu sync.Mutex
+	deliveryHeaders   map[string]map[string][]byte
+
+	// ingressAuth holds the hashes of the bearer tokens of each broker
+	// until they are written to the ingress auth secret, keyed by broker
+	// key.
+	ingressAuthMu sync.Mutex
+	ingressAuth   map[string][]byte
+	// replyTokens holds the bearer token the fanout and retry pods send the
+	// replies to each broker requiring one with, keyed by broker key. It is
+	// guarded by ingressAuthMu.
+	replyTokens map[string]string
+
+	// createLiteClientFn creates the Pub/Sub Lite clients of the brokers
+	// whose queues are on Pub/Sub Lite.
+	createLiteClientFn gpubsublite.CreateFn
+
+	// subscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// decoupling and retry subscriptions.
+	subscriptionNaming psresources.SubscriptionNaming
 }
 
 // Check that Reconciler implements Interface
@@ -130,11 +171,14 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, b *brokerv1beta1.Broker)
 
 	// Delete broker from targets-config, this will cause the data plane to stop working for this Broker and all
 	// undelivered events will be lost.
+	existing, _ := r.targetsConfig.GetBroker(b.Namespace, b.Name)
 	r.targetsConfig.MutateBroker(b.Namespace, b.Name, func(m config.BrokerMutation) {
 		m.Delete()
 	})
+	r.forgetDeliveryHeaders(b)
+	r.forgetIngressAuth(b)
 
-	if err := r.deleteDecouplingTopicAndSubscription(ctx, b); err != nil {
+	if err := r.deleteDecouplingTopicAndSubscription(ctx, b, existing); err != nil {
 		return fmt.Errorf("failed to delete Pub/Sub topic: %v", err)
 	}
 
@@ -147,16 +191,31 @@ func (r *Reconciler) reconcileBroker(ctx context.Context, b *brokerv1beta1.Broke
 	b.Status.InitializeConditions()
 	b.Status.ObservedGeneration = b.Generation
 
-	if err := r.ensureBrokerCellExists(ctx, b); err != nil {
+	bc, err := r.ensureBrokerCellExists(ctx, b)
+	if err != nil {
 		return fmt.Errorf("brokercell reconcile failed: %v", err)
 	}
 
+	// get ProjectID from metadata if projectID isn't set
+	projectID, err := utils.ProjectID(r.projectID, metadataClient.NewDefaultMetadataClient())
+	if err != nil {
+		logger.Error("Failed to find project id", zap.Error(err))
+		b.Status.MarkTopicUnknown("ProjectIdNotFound", "Failed to find project id: %w", err)
+		b.Status.MarkSubscriptionUnknown("ProjectIdNotFound", "Failed to find project id: %w", err)
+		return fmt.Errorf("decoupling topic reconcile failed: %v", err)
+	}
+	// Set the projectID in the status.
+	//TODO uncomment when eventing webhook allows this
+	//b.Status.ProjectID = projectID
+
 	// Create decoupling topic and pullsub for this broker. Ingress will push
 	// to this topic and fanout will pull from the pull sub.
-	if err := r.reconcileDecouplingTopicAndSubscription(ctx, b); err != nil {
+	if err := r.reconcileDecouplingTopicAndSubscription(ctx, b, projectID); err != nil {
 		return fmt.Errorf("decoupling topic reconcile failed: %v", err)
 	}
 
+	r.reconcilePublishStatus(ctx, b)
+
 	// Filter by `eventing.knative.dev/broker: <name>` here
 	// to get only the triggers for this broker. The trigger webhook will
 	// ensure that triggers are always labeled with their broker name.
@@ -167,48 +226,109 @@ func (r *Reconciler) reconcileBroker(ctx context.Context, b *brokerv1beta1.Broke
 		return err
 	}
 
-	r.reconcileConfig(ctx, b, triggers)
+	r.reconcileConfig(ctx, b, bc.Name, projectID, triggers)
 	// Update config map
 	r.flagTargetsForUpdate()
 	b.Status.MarkConfigReady()
 	return nil
 }
 
-// reconcileConfig reconstructs the data entry for the given broker in targets-config.
-func (r *Reconciler) reconcileConfig(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger) {
+// reconcileConfig reconstructs the data entry for the given broker served by
+// brokerCell in targets-config. projectID is the project of its queues.
+func (r *Reconciler) reconcileConfig(ctx context.Context, b *brokerv1beta1.Broker, brokerCell, projectID string, triggers []*brokerv1beta1.Trigger) {
 	// TODO Maybe get rid of BrokerMutation and add Delete() and Upsert(broker) methods to TargetsConfig. Now we always
 	//  delete or update the entire broker entry and we don't need partial updates per trigger.
 	// The code can be simplified to r.targetsConfig.Upsert(brokerConfigEntry)
+	// The delivery headers are recorded first, so that they are there by the
+	// time the targets referring to them are written out.
+	deliveryHeadersKeys := r.reconcileDeliveryHeaders(ctx, b, triggers)
+	ingressAuthKey, replyHeadersKey := r.reconcileIngressAuth(ctx, b)
+	policy, err := resources.BrokerEventPolicy(b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to parse the event policy, rejecting all events", zap.Error(err))
+		r.Recorder.Eventf(b, corev1.EventTypeWarning, "InvalidEventPolicy", "Rejecting all events: %v", err)
+	}
+	deadLetterAddress := r.deadLetterAddress(ctx, b)
+	// The decoupling topic reconcile rejects malformed locations.
+	liteLocation, _ := resources.LiteLocation(b)
+	encryptionKey := resources.EncryptionKey(b)
+	previousEncryptionKeys := r.previousEncryptionKeys(b, encryptionKey)
+	existing, _ := r.targetsConfig.GetBroker(b.Namespace, b.Name)
+	subID, prioritySubID := resources.GenerateDecouplingSubscriptionName(b), resources.GeneratePriorityDecouplingSubscriptionName(b)
+	if liteLocation == "" {
+		// The decoupling topic reconcile rejects invalid subscription IDs.
+		subID, prioritySubID, _ = r.decouplingSubscriptionIDs(b, existing)
+	}
 	r.targetsConfig.MutateBroker(b.Namespace, b.Name, func(m config.BrokerMutation) {
 		// First delete the broker entry.
 		m.Delete()
 
 		// Then reconstruct the broker entry and insert it
 		m.SetID(string(b.UID))
+		m.SetGeneration(b.Generation)
 		m.SetAddress(b.Status.Address.URL.String())
-		m.SetDecoupleQueue(&config.Queue{
-			Topic:        resources.GenerateDecouplingTopicName(b),
-			Subscription: resources.GenerateDecouplingSubscriptionName(b),
-		})
+		decoupleQueue := queue(projectID, liteLocation, resources.GenerateDecouplingTopicName(b), subID)
+		decoupleQueue.OrderingEnabled = resources.MessageOrderingEnabled(b)
+		m.SetDecoupleQueue(decoupleQueue)
+		if resources.PriorityQueueEnabled(b) {
+			priorityQueue := queue(projectID, liteLocation, resources.GeneratePriorityDecouplingTopicName(b), prioritySubID)
+			priorityQueue.OrderingEnabled = resources.MessageOrderingEnabled(b)
+			m.SetPriorityDecoupleQueue(priorityQueue)
+		}
 		if b.Status.IsReady() {
 			m.SetState(config.State_READY)
 		} else {
 			m.SetState(config.State_UNKNOWN)
 		}
+		brokerLabels := metrics.MetricLabels(b.Annotations)
+		m.SetEncryptionKey(encryptionKey)
+		m.SetPreviousEncryptionKeys(previousEncryptionKeys)
+		m.SetEventTypePolicy(policy.AllowedTypes, policy.DeniedTypes)
+		m.SetEventSourcePolicy(policy.AllowedSources, policy.DeniedSources)
+		m.SetIngressAuthKey(ingressAuthKey)
+		m.SetReplyHeadersKey(replyHeadersKey)
+		m.SetStructuredEncoding(resources.StructuredEncoding(b))
+		m.SetMetricLabels(brokerLabels)
+		m.SetBrokerCell(brokerCell)
 
 		// Insert each Trigger to the config.
 		for _, t := range triggers {
 			if t.Spec.Broker == b.Name {
+				// The trigger reconciler reports malformed max ages,
+				// overrides and transforms, which keep the trigger from
+				// becoming ready.
+				maxAge, _ := resources.MaxAgeSeconds(t)
+				overrides, _ := resources.CEOverrides(t)
+				transform, _ := resources.Transform(t)
+				retrySubID := resources.GenerateRetrySubscriptionName(t)
+				if liteLocation == "" {
+					// The trigger reconciler reports invalid
+					// subscription IDs.
+					retrySubID, _ = r.retrySubscriptionID(t, existing)
+				}
 				target := &config.Target{
-					Id:        string(t.UID),
-					Name:      t.Name,
-					Namespace: t.Namespace,
-					Broker:    b.Name,
-					Address:   t.Status.SubscriberURI.String(),
-					RetryQueue: &config.Queue{
-						Topic:        resources.GenerateRetryTopicName(t),
-						Subscription: resources.GenerateRetrySubscriptionName(t),
-					},
+					Id:                  string(t.UID),
+					Generation:          t.Generation,
+					Name:                t.Name,
+					Namespace:           t.Namespace,
+					Broker:              b.Name,
+					Address:             t.Status.SubscriberURI.String(),
+					RetryQueue:          queue(projectID, liteLocation, resources.GenerateRetryTopicName(t), retrySubID),
+					OrderedDelivery:     resources.OrderedDeliveryEnabled(t),
+					MetricLabels:        targetMetricLabels(brokerLabels, t),
+					DeduplicationWindow: resources.DeduplicationWindow(t),
+					CeOverrides:         overrides,
+					Transform:           transform,
+					DeliveryHeadersKey:  deliveryHeadersKeys[t.UID],
+					MaxAgeSeconds:       maxAge,
+					DeadLetterAddress:   deadLetterAddress,
+				}
+				if resources.DirectDeliveryEnabled(t) {
+					target.DirectAddress = r.directAddress(ctx, t)
+				}
+				if addresses := r.subscriberAddresses(ctx, b, t); len(addresses) > 0 {
+					target.SubscriberAddresses = addresses
+					target.SequentialSubscribers = resources.SequentialSubscribersDelivery(t)
 				}
 				if t.Spec.Filter != nil && t.Spec.Filter.Attributes != nil {
 					target.FilterAttributes = t.Spec.Filter.Attributes
@@ -224,25 +344,211 @@ func (r *Reconciler) reconcileConfig(ctx context.Context, b *brokerv1beta1.Broke
 	})
 }
 
-func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context, b *brokerv1beta1.Broker) error {
+// queue returns the config of the queue of the given topic and subscription
+// IDs. Pub/Sub Lite queues, whose location is not empty, are written with the
+// paths of their topic and subscription since they are not in a client wide
+// project.
+func queue(projectID, location, topicID, subID string) *config.Queue {
+	if location == "" {
+		return &config.Queue{
+			Topic:        topicID,
+			Subscription: subID,
+		}
+	}
+	return &config.Queue{
+		Topic:        gpubsublite.TopicPath(projectID, location, topicID),
+		Subscription: gpubsublite.SubscriptionPath(projectID, location, subID),
+		Location:     location,
+	}
+}
+
+// directAddress returns the address to deliver the trigger's events to
+// without going through the activator, if its subscriber is a ready Knative
+// Service.
+func (r *Reconciler) directAddress(ctx context.Context, t *brokerv1beta1.Trigger) string {
+	namespace, name, ok := subscriberService(t)
+	if !ok {
+		return ""
+	}
+	s, err := r.serviceLister.Services(namespace).Get(name)
+	if err != nil {
+		if !apierrs.IsNotFound(err) {
+			logging.FromContext(ctx).Error("Failed to get subscriber Service", zap.String("trigger", t.Name), zap.Error(err))
+		}
+		return ""
+	}
+	return resources.DirectAddress(s)
+}
+
+// subscriberAddresses returns the resolved URIs of the trigger's additional
+// subscribers. Subscribers that cannot be resolved are left out; the trigger
+// reconciler reports them in the trigger status.
+func (r *Reconciler) subscriberAddresses(ctx context.Context, b *brokerv1beta1.Broker, t *brokerv1beta1.Trigger) []string {
+	subscribers, err := resources.Subscribers(t)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to parse additional subscribers", zap.String("trigger", t.Name), zap.Error(err))
+		return nil
+	}
+	var addresses []string
+	for _, s := range subscribers {
+		uri, err := r.uriResolver.URIFromDestinationV1(s, b)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to resolve additional subscriber", zap.String("trigger", t.Name), zap.Error(err))
+			continue
+		}
+		addresses = append(addresses, uri.String())
+	}
+	return addresses
+}
+
+// previousEncryptionKeys returns the encryption keys the broker used before
+// key, most recent first, as recorded in its targets config entry. Events
+// encrypted with them may still be in its queues, so they are kept for as long
+// as the broker exists.
+func (r *Reconciler) previousEncryptionKeys(b *brokerv1beta1.Broker, key string) []string {
+	old, ok := r.targetsConfig.GetBroker(b.Namespace, b.Name)
+	// A recreated broker has new queues.
+	if !ok || old.Id != string(b.UID) {
+		return nil
+	}
+	var keys []string
+	seen := sets.NewString(key, "")
+	for _, k := range append([]string{old.EncryptionKey}, old.PreviousEncryptionKeys...) {
+		if !seen.Has(k) {
+			seen.Insert(k)
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// deadLetterAddress returns the resolved URI of the dead letter sink in the
+// delivery spec of the broker, which the data plane sends the events expired
+// for a trigger to. It's empty if the broker has none or it cannot be resolved.
+func (r *Reconciler) deadLetterAddress(ctx context.Context, b *brokerv1beta1.Broker) string {
+	if b.Spec.Delivery == nil || b.Spec.Delivery.DeadLetterSink == nil {
+		return ""
+	}
+	sink := *b.Spec.Delivery.DeadLetterSink
+	if sink.Ref != nil && sink.Ref.Namespace == "" {
+		ref := *sink.Ref
+		ref.Namespace = b.Namespace
+		sink.Ref = &ref
+	}
+	uri, err := r.uriResolver.URIFromDestinationV1(sink, b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to resolve the dead letter sink", zap.Error(err))
+		return ""
+	}
+	return uri.String()
+}
+
+// subscriberService returns the namespace and name of the Knative Service
+// the trigger's subscriber refers to, if any.
+func subscriberService(t *brokerv1beta1.Trigger) (namespace, name string, ok bool) {
+	ref := t.Spec.Subscriber.Ref
+	if ref == nil || ref.Kind != "Service" {
+		return "", "", false
+	}
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != serving.GroupName {
+		return "", "", false
+	}
+	namespace = ref.Namespace
+	if namespace == "" {
+		namespace = t.Namespace
+	}
+	return namespace, ref.Name, true
+}
+
+// targetMetricLabels returns the metric labels of the trigger's target. The
+// labels of the trigger take precedence over the labels of its broker.
+func targetMetricLabels(brokerLabels map[string]string, t *brokerv1beta1.Trigger) map[string]string {
+	triggerLabels := metrics.MetricLabels(t.Annotations)
+	if len(brokerLabels) == 0 {
+		return triggerLabels
+	}
+	labels := make(map[string]string, len(brokerLabels)+len(triggerLabels))
+	for k, v := range brokerLabels {
+		labels[k] = v
+	}
+	for k, v := range triggerLabels {
+		labels[k] = v
+	}
+	return labels
+}
+
+// reconcilePublishStatus sets the publish condition of the broker from the
+// failures reported by the data plane in the publish status ConfigMap.
+func (r *Reconciler) reconcilePublishStatus(ctx context.Context, b *brokerv1beta1.Broker) {
+	cm, err := r.configMapLister.ConfigMaps(system.Namespace()).Get(status.ConfigMapName)
+	if apierrs.IsNotFound(err) {
+		b.Status.MarkPublishReady()
+		return
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Problem getting the publish status", zap.Error(err))
+		b.Status.MarkPublishUnknown("PublishStatusUnknown", "Failed to get the publish status: %v", err)
+		return
+	}
+	f := status.LatestFailure(cm, types.NamespacedName{Namespace: b.Namespace, Name: b.Name}, time.Now().Add(-status.FailureExpiry))
+	if f == nil {
+		b.Status.MarkPublishReady()
+		return
+	}
+
+	topic := "a retry topic"
+	if f.Queue == status.DecoupleQueue {
+		topic = fmt.Sprintf("the decouple topic %q", resources.GenerateDecouplingTopicName(b))
+	}
+	var advice string
+	switch f.Reason {
+	case status.ReasonQuotaExceeded:
+		advice = "Raise the Pub/Sub quota of the project or reduce the rate of events."
+	case status.ReasonPermissionDenied:
+		role := "roles/pubsub.publisher"
+		if location, _ := r.hadLiteLocation(b); location != "" {
+			role = "roles/pubsublite.publisher"
+		}
+		advice = fmt.Sprintf("Grant %s to the Google service account of the broker data plane.", role)
+	}
+	b.Status.MarkPublishFailed(f.Reason, "Events are dropped because publishing to %s failed at %s: %s. %s",
+		topic, f.Time.Format(time.RFC3339), f.Error, advice)
+}
+
+func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context, b *brokerv1beta1.Broker, projectID string) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Reconciling decoupling topic", zap.Any("broker", b))
-	// get ProjectID from metadata if projectID isn't set
-	projectID, err := utils.ProjectID(r.projectID, metadataClient.NewDefaultMetadataClient())
+
+	location, err := resources.LiteLocation(b)
 	if err != nil {
-		logger.Error("Failed to find project id", zap.Error(err))
-		b.Status.MarkTopicUnknown("ProjectIdNotFound", "Failed to find project id: %w", err)
-		b.Status.MarkSubscriptionUnknown("ProjectIdNotFound", "Failed to find project id: %w", err)
+		b.Status.MarkTopicFailed("InvalidLiteLocation", "%v", err)
+		b.Status.MarkSubscriptionFailed("InvalidLiteLocation", "%v", err)
+		return err
+	}
+	// The data plane would lose the events of the old queue, so the broker
+	// keeps it until it is recreated.
+	if had, known := r.hadLiteLocation(b); known && had != location {
+		err := fmt.Errorf("the Pub/Sub Lite location of the queues can't be changed from %q to %q", had, location)
+		r.Recorder.Eventf(b, corev1.EventTypeWarning, "LiteLocationChanged", "Ignoring the changed Pub/Sub Lite location: %v", err)
+		b.Status.MarkTopicFailed("LiteLocationChanged", "%v", err)
+		b.Status.MarkSubscriptionFailed("LiteLocationChanged", "%v", err)
+		return err
+	}
+	if location != "" {
+		return r.reconcileLiteDecouplingTopicsAndSubscriptions(ctx, b, projectID, location)
+	}
+	existing, _ := r.targetsConfig.GetBroker(b.Namespace, b.Name)
+	subID, prioritySubID, err := r.decouplingSubscriptionIDs(b, existing)
+	if err != nil {
+		logger.Error("Failed to generate the Pub/Sub subscription IDs", zap.Error(err))
+		b.Status.MarkSubscriptionFailed("InvalidSubscriptionID", "%v", err)
 		return err
 	}
-	// Set the projectID in the status.
-	//TODO uncomment when eventing webhook allows this
-	//b.Status.ProjectID = projectID
 
 	client := r.pubsubClient
 	if client == nil {
 		var err error
-		client, err = pubsub.NewClient(ctx, projectID)
+		client, err = pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 		if err != nil {
 			logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 			b.Status.MarkTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: %w", err)
@@ -273,14 +579,17 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	//b.Status.TopicID = topic.ID()
 
 	// Check if PullSub exists, and if not, create it.
-	subID := resources.GenerateDecouplingSubscriptionName(b)
 	subConfig := pubsub.SubscriptionConfig{
 		Topic:  topic,
 		Labels: labels,
+		// Message ordering cannot be changed once the subscription is created.
+		EnableMessageOrdering: resources.MessageOrderingEnabled(b),
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
 	}
+	// Pub/Sub cannot update message ordering in place, so the pullsubs are
+	// recreated when the annotation changes.
 	if _, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, b, &b.Status); err != nil {
 		return err
 	}
@@ -289,10 +598,124 @@ func (r *Reconciler) reconcileDecouplingTopicAndSubscription(ctx context.Context
 	//TODO uncomment when eventing webhook allows this
 	//b.Status.SubscriptionID = sub.ID()
 
+	// Brokers with a priority queue have a second topic and pullsub for
+	// their high priority events.
+	priorityTopicID := resources.GeneratePriorityDecouplingTopicName(b)
+	if !resources.PriorityQueueEnabled(b) {
+		if r.hadPriorityQueue(b) {
+			// The broker no longer has a priority queue, its topic and
+			// pullsub are not used anymore.
+			return pubsubReconciler.DeleteTopicAndSubscription(ctx, priorityTopicID, prioritySubID, b, &b.Status)
+		}
+		return nil
+	}
+	priorityTopic, err := pubsubReconciler.ReconcileTopic(ctx, priorityTopicID, topicConfig, b, &b.Status)
+	if err != nil {
+		return err
+	}
+	subConfig.Topic = priorityTopic
+	if _, err := pubsubReconciler.ReconcileSubscription(ctx, prioritySubID, subConfig, b, &b.Status); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reconcileLiteDecouplingTopicsAndSubscriptions is
+// reconcileDecouplingTopicAndSubscription for a broker whose queues are on
+// Pub/Sub Lite in location. Pub/Sub Lite subscriptions are always ordered by
+// partition, so they don't depend on message ordering.
+func (r *Reconciler) reconcileLiteDecouplingTopicsAndSubscriptions(ctx context.Context, b *brokerv1beta1.Broker, projectID, location string) error {
+	client, err := r.createLiteClientFn(ctx, location)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+		b.Status.MarkTopicUnknown("PubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+		b.Status.MarkSubscriptionUnknown("PubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+		return err
+	}
+	defer client.Close()
+	liteReconciler := reconcilerutilspubsub.NewLiteReconciler(client, r.Recorder)
+
+	q := queue(projectID, location, resources.GenerateDecouplingTopicName(b), resources.GenerateDecouplingSubscriptionName(b))
+	if err := liteReconciler.ReconcileTopicAndSubscription(ctx, q.Topic, q.Subscription, b, &b.Status, &b.Status.Status); err != nil {
+		return err
+	}
+	q = queue(projectID, location, resources.GeneratePriorityDecouplingTopicName(b), resources.GeneratePriorityDecouplingSubscriptionName(b))
+	if resources.PriorityQueueEnabled(b) {
+		return liteReconciler.ReconcileTopicAndSubscription(ctx, q.Topic, q.Subscription, b, &b.Status, &b.Status.Status)
+	}
+	if r.hadPriorityQueue(b) {
+		return liteReconciler.DeleteTopicAndSubscription(ctx, q.Topic, q.Subscription, b, &b.Status, &b.Status.Status)
+	}
 	return nil
 }
 
-func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, b *brokerv1beta1.Broker) error {
+// decouplingSubscriptionIDs returns the IDs of the Pub/Sub decoupling
+// subscription and priority decoupling subscription of the broker. existing
+// is the broker as it was last written to the targets config, if it was. The
+// broker keeps the subscriptions it was written with, so that changing the
+// subscription naming doesn't lose their events.
+func (r *Reconciler) decouplingSubscriptionIDs(b *brokerv1beta1.Broker, existing *config.Broker) (subID, prioritySubID string, err error) {
+	if existing != nil && existing.Id != "" && existing.Id != string(b.UID) {
+		// The broker was recreated with the same name.
+		existing = nil
+	}
+	if subID = pubsubSubscription(existing.GetDecoupleQueue()); subID == "" {
+		if subID, err = resources.DecouplingSubscriptionID(r.subscriptionNaming, b); err != nil {
+			return "", "", err
+		}
+	}
+	if prioritySubID = pubsubSubscription(existing.GetPriorityDecoupleQueue()); prioritySubID == "" {
+		if prioritySubID, err = resources.PriorityDecouplingSubscriptionID(r.subscriptionNaming, b); err != nil {
+			return "", "", err
+		}
+	}
+	return subID, prioritySubID, nil
+}
+
+// retrySubscriptionID returns the ID of the Pub/Sub retry subscription of the
+// trigger. Like the broker, the trigger keeps the subscription it was last
+// written to the targets config with in existing.
+func (r *Reconciler) retrySubscriptionID(t *brokerv1beta1.Trigger, existing *config.Broker) (string, error) {
+	if target, ok := existing.GetTargets()[t.Name]; ok && (target.Id == "" || target.Id == string(t.UID)) {
+		if id := pubsubSubscription(target.RetryQueue); id != "" {
+			return id, nil
+		}
+	}
+	return resources.RetrySubscriptionID(r.subscriptionNaming, t)
+}
+
+// pubsubSubscription returns the ID of the Pub/Sub subscription of q, or an
+// empty string if there is no queue or it is on Pub/Sub Lite.
+func pubsubSubscription(q *config.Queue) string {
+	if q == nil || q.Location != "" {
+		return ""
+	}
+	return q.Subscription
+}
+
+// hadPriorityQueue returns true if the broker had a priority queue the last
+// time it was written to the targets config.
+func (r *Reconciler) hadPriorityQueue(b *brokerv1beta1.Broker) bool {
+	existing, ok := r.targetsConfig.GetBroker(b.Namespace, b.Name)
+	return ok && existing.PriorityDecoupleQueue != nil
+}
+
+// hadLiteLocation returns the Pub/Sub Lite location of the queues of the
+// broker the last time it was written to the targets config. known is false
+// if the broker has not been written yet.
+func (r *Reconciler) hadLiteLocation(b *brokerv1beta1.Broker) (location string, known bool) {
+	existing, ok := r.targetsConfig.GetBroker(b.Namespace, b.Name)
+	if !ok || existing.DecoupleQueue == nil {
+		return "", false
+	}
+	return existing.DecoupleQueue.Location, true
+}
+
+// deleteDecouplingTopicAndSubscription deletes the decoupling topic and pullsub
+// of the broker, along with those of its priority queue if it had one.
+// existing is the broker as it was last written to the targets config, if it
+// was.
+func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, b *brokerv1beta1.Broker, existing *config.Broker) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Deleting decoupling topic")
 
@@ -305,9 +728,19 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, b
 		return err
 	}
 
+	hadPriorityQueue := existing != nil && existing.PriorityDecoupleQueue != nil
+	// Brokers that were never written keep the location of their annotation.
+	location, _ := resources.LiteLocation(b)
+	if existing != nil && existing.DecoupleQueue != nil {
+		location = existing.DecoupleQueue.Location
+	}
+	if location != "" {
+		return r.deleteLiteDecouplingTopicsAndSubscriptions(ctx, b, projectID, location, hadPriorityQueue, existing)
+	}
+
 	client := r.pubsubClient
 	if client == nil {
-		client, err := pubsub.NewClient(ctx, projectID)
+		client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 		if err != nil {
 			logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 			b.Status.MarkTopicUnknown("FinalizeTopicPubSubClientCreationFailed", "Failed to create Pub/Sub client: %w", err)
@@ -318,14 +751,56 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, b
 	}
 	pubsubReconciler := reconcilerutilspubsub.NewReconciler(client, r.Recorder)
 
-	// Delete topic if it exists. Pull subscriptions continue pulling from the
-	// topic until deleted themselves.
+	// Delete topic and subscription if they exist. Pull subscriptions continue
+	// pulling from the topic until deleted themselves.
 	topicID := resources.GenerateDecouplingTopicName(b)
-	err = multierr.Append(nil, pubsubReconciler.DeleteTopic(ctx, topicID, b, &b.Status))
-	subID := resources.GenerateDecouplingSubscriptionName(b)
-	err = multierr.Append(err, pubsubReconciler.DeleteSubscription(ctx, subID, b, &b.Status))
+	subID, prioritySubID, err := r.decouplingSubscriptionIDs(b, existing)
+	if err != nil {
+		// No subscription could have been created with invalid IDs.
+		subID, prioritySubID = resources.GenerateDecouplingSubscriptionName(b), resources.GeneratePriorityDecouplingSubscriptionName(b)
+	}
+	if err := pubsubReconciler.DeleteTopicAndSubscription(ctx, topicID, subID, b, &b.Status); err != nil {
+		return err
+	}
+	if !hadPriorityQueue && !resources.PriorityQueueEnabled(b) {
+		return nil
+	}
+	priorityTopicID := resources.GeneratePriorityDecouplingTopicName(b)
+	return pubsubReconciler.DeleteTopicAndSubscription(ctx, priorityTopicID, prioritySubID, b, &b.Status)
+}
 
-	return err
+// deleteLiteDecouplingTopicsAndSubscriptions is
+// deleteDecouplingTopicAndSubscription for a broker whose queues are on
+// Pub/Sub Lite in location. The retry queues of its triggers are deleted as
+// well, since the trigger reconciler can't find their location once the
+// broker is gone.
+func (r *Reconciler) deleteLiteDecouplingTopicsAndSubscriptions(ctx context.Context, b *brokerv1beta1.Broker, projectID, location string, hadPriorityQueue bool, existing *config.Broker) error {
+	client, err := r.createLiteClientFn(ctx, location)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+		b.Status.MarkTopicUnknown("FinalizeTopicPubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+		b.Status.MarkSubscriptionUnknown("FinalizeSubscriptionPubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+		return err
+	}
+	defer client.Close()
+	liteReconciler := reconcilerutilspubsub.NewLiteReconciler(client, r.Recorder)
+
+	queues := []*config.Queue{queue(projectID, location, resources.GenerateDecouplingTopicName(b), resources.GenerateDecouplingSubscriptionName(b))}
+	if hadPriorityQueue || resources.PriorityQueueEnabled(b) {
+		queues = append(queues, queue(projectID, location, resources.GeneratePriorityDecouplingTopicName(b), resources.GeneratePriorityDecouplingSubscriptionName(b)))
+	}
+	if existing != nil {
+		for _, t := range existing.Targets {
+			if t.RetryQueue != nil && t.RetryQueue.Location == location {
+				queues = append(queues, t.RetryQueue)
+			}
+		}
+	}
+	var errs error
+	for _, q := range queues {
+		errs = multierr.Append(errs, liteReconciler.DeleteTopicAndSubscription(ctx, q.Topic, q.Subscription, b, &b.Status, &b.Status.Status))
+	}
+	return errs
 }
 
 //TODO all this stuff should be in a configmap variant of the config object
@@ -333,6 +808,16 @@ func (r *Reconciler) deleteDecouplingTopicAndSubscription(ctx context.Context, b
 // This function is not thread-safe and should only be executed by
 // TargetsConfigUpdater
 func (r *Reconciler) updateTargetsConfig(ctx context.Context) error {
+	// Failing to update the delivery headers only affects the targets with
+	// delivery headers, whose events fail to be delivered until it succeeds.
+	if err := r.updateDeliveryHeadersSecret(ctx); err != nil {
+		r.Logger.Error("Error updating delivery headers secret", zap.Error(err))
+	}
+	// Likewise for the brokers requiring a bearer token, whose requests are
+	// rejected until it succeeds.
+	if err := r.updateIngressAuthSecret(ctx); err != nil {
+		r.Logger.Error("Error updating ingress auth secret", zap.Error(err))
+	}
 	//TODO resources package?
 	data, err := r.targetsConfig.Bytes()
 	if err != nil {
@@ -387,9 +872,22 @@ func (r *Reconciler) updateTargetsConfig(ctx context.Context) error {
 // TODO(https://github.com/google/knative-gcp/issues/863) With BrokerCell, we
 // will reconcile data plane deployments dynamically.
 func (r *Reconciler) updateConfigmapVolumeAnnotation() error {
-	var err error
-	for _, name := range dataPlaneDeployments {
-		err = multierr.Append(err, resources.UpdateVolumeGenerationForDeployment(r.KubeClientSet, r.deploymentLister, r.podLister, system.Namespace(), name))
+	// All brokercells share the targets configmap.
+	brokerCells := []string{resources.DefaultBroekrCellName}
+	bcs, err := r.brokerCellLister.BrokerCells(system.Namespace()).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, bc := range bcs {
+		if bc.Name != resources.DefaultBroekrCellName {
+			brokerCells = append(brokerCells, bc.Name)
+		}
+	}
+	for _, bc := range brokerCells {
+		for _, component := range dataPlaneComponents {
+			name := brokercellresources.Name(bc, component)
+			err = multierr.Append(err, resources.UpdateVolumeGenerationForDeployment(r.KubeClientSet, r.deploymentLister, r.podLister, system.Namespace(), name))
+		}
 	}
 	return err
 }
@@ -18,22 +18,39 @@ package broker
 
 import (
 	"context"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/auth"
 	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/metrics"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"google.golang.org/protobuf/testing/protocmp"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	"knative.dev/pkg/logging"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/ptr"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
-	//. "knative.dev/pkg/reconciler/testing"
 	fakerunclient "github.com/google/knative-gcp/pkg/client/injection/client/fake"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
@@ -87,6 +104,9 @@ func TestUpdateTargetsConfig(t *testing.T) {
 				configMapLister:    listers.GetConfigMapLister(),
 				endpointsLister:    listers.GetEndpointsLister(),
 				deploymentLister:   listers.GetDeploymentLister(),
+				brokerCellLister:   listers.GetBrokerCellLister(),
+				serviceLister:      listers.GetV1ServiceLister(),
+				secretLister:       listers.GetSecretLister(),
 				targetsConfig:      tc.targetsConfig,
 				targetsNeedsUpdate: make(chan struct{}),
 				projectID:          testProject,
@@ -113,3 +133,820 @@ func TestUpdateTargetsConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileConfigLite(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress), WithBrokerLiteLocation("us-central1-a"))
+	trigger := NewTrigger("test-trigger", testNS, brokerName, WithTriggerUID("trigger-uid"))
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, []*brokerv1beta1.Trigger{trigger})
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	want := &config.Queue{
+		Topic:        "projects/test-project-id/locations/us-central1-a/topics/cre-bkr_testnamespace_test-broker_abc123",
+		Subscription: "projects/test-project-id/locations/us-central1-a/subscriptions/cre-bkr_testnamespace_test-broker_abc123",
+		Location:     "us-central1-a",
+	}
+	if diff := cmp.Diff(want, got.DecoupleQueue, protocmp.Transform()); diff != "" {
+		t.Errorf("decouple queue (-want,+got): %v", diff)
+	}
+	want = &config.Queue{
+		Topic:        "projects/test-project-id/locations/us-central1-a/topics/" + resources.GenerateRetryTopicName(trigger),
+		Subscription: "projects/test-project-id/locations/us-central1-a/subscriptions/" + resources.GenerateRetrySubscriptionName(trigger),
+		Location:     "us-central1-a",
+	}
+	if diff := cmp.Diff(want, got.Targets[trigger.Name].GetRetryQueue(), protocmp.Transform()); diff != "" {
+		t.Errorf("retry queue (-want,+got): %v", diff)
+	}
+	if location, known := r.hadLiteLocation(b); !known || location != "us-central1-a" {
+		t.Errorf("hadLiteLocation got=%q, %v, want=us-central1-a, true", location, known)
+	}
+}
+
+func TestReconcileConfigOrdering(t *testing.T) {
+	testCases := []struct {
+		name         string
+		broker       *brokerv1beta1.Broker
+		trigger      *brokerv1beta1.Trigger
+		wantOrdering bool
+		wantOrdered  bool
+	}{{
+		name:    "ordering disabled",
+		broker:  NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress)),
+		trigger: NewTrigger("test-trigger", testNS, brokerName),
+	}, {
+		name:         "broker ordering without ordered trigger",
+		broker:       NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress), WithBrokerMessageOrdering),
+		trigger:      NewTrigger("test-trigger", testNS, brokerName),
+		wantOrdering: true,
+	}, {
+		name:         "broker ordering with ordered trigger",
+		broker:       NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress), WithBrokerMessageOrdering),
+		trigger:      NewTrigger("test-trigger", testNS, brokerName, WithTriggerOrderedDelivery),
+		wantOrdering: true,
+		wantOrdered:  true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+			r.reconcileConfig(context.Background(), tc.broker, resources.DefaultBroekrCellName, testProject, []*brokerv1beta1.Trigger{tc.trigger})
+
+			b, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+			if !ok {
+				t.Fatal("broker is missing from the targets config")
+			}
+			if got := b.BrokerCell; got != resources.DefaultBroekrCellName {
+				t.Errorf("broker BrokerCell got=%v, want=%v", got, resources.DefaultBroekrCellName)
+			}
+			if got := b.DecoupleQueue.OrderingEnabled; got != tc.wantOrdering {
+				t.Errorf("decouple queue OrderingEnabled got=%v, want=%v", got, tc.wantOrdering)
+			}
+			target, ok := b.Targets[tc.trigger.Name]
+			if !ok {
+				t.Fatal("trigger is missing from the targets config")
+			}
+			if got := target.OrderedDelivery; got != tc.wantOrdered {
+				t.Errorf("target OrderedDelivery got=%v, want=%v", got, tc.wantOrdered)
+			}
+		})
+	}
+}
+
+func TestReconcileConfigIdentity(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerGeneration(3), WithBrokerReadyURI(brokerAddress))
+	trigger := NewTrigger("test-trigger", testNS, brokerName, WithTriggerUID("trigger-uid"), WithTriggerGeneration(2))
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, []*brokerv1beta1.Trigger{trigger})
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	if got.Id != testUID || got.Generation != 3 {
+		t.Errorf("broker Id, Generation got=%v, %v, want=%v, 3", got.Id, got.Generation, testUID)
+	}
+	target := got.Targets[trigger.Name]
+	if target.GetId() != "trigger-uid" || target.GetGeneration() != 2 {
+		t.Errorf("target Id, Generation got=%v, %v, want=trigger-uid, 2", target.GetId(), target.GetGeneration())
+	}
+}
+
+func TestReconcileConfigPreviousEncryptionKeys(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	reconcileKey := func(key string) []string {
+		t.Helper()
+		b.Annotations[brokerv1beta1.EncryptionKeyAnnotation] = key
+		r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+		got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+		if !ok {
+			t.Fatal("broker is missing from the targets config")
+		}
+		if got.EncryptionKey != key {
+			t.Errorf("EncryptionKey got=%q, want=%q", got.EncryptionKey, key)
+		}
+		return got.PreviousEncryptionKeys
+	}
+
+	if got := reconcileKey("k1"); len(got) != 0 {
+		t.Errorf("PreviousEncryptionKeys got=%v, want none", got)
+	}
+	if diff := cmp.Diff([]string{"k1"}, reconcileKey("k2")); diff != "" {
+		t.Errorf("PreviousEncryptionKeys (-want,+got): %v", diff)
+	}
+	if diff := cmp.Diff([]string{"k2", "k1"}, reconcileKey("")); diff != "" {
+		t.Errorf("PreviousEncryptionKeys after disabling encryption (-want,+got): %v", diff)
+	}
+	// Switching back to a previous key keeps the others.
+	if diff := cmp.Diff([]string{"k2"}, reconcileKey("k1")); diff != "" {
+		t.Errorf("PreviousEncryptionKeys after switching back (-want,+got): %v", diff)
+	}
+
+	// A recreated broker starts over.
+	b.UID = "new-uid"
+	if got := reconcileKey("k3"); len(got) != 0 {
+		t.Errorf("PreviousEncryptionKeys of recreated broker got=%v, want none", got)
+	}
+}
+
+func TestReconcileConfigPriorityQueue(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress), WithBrokerPriorityQueue)
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	want := &config.Queue{
+		Topic:        "cre-bkrp_testnamespace_test-broker_abc123",
+		Subscription: "cre-bkrp_testnamespace_test-broker_abc123",
+	}
+	if diff := cmp.Diff(want, got.PriorityDecoupleQueue, protocmp.Transform()); diff != "" {
+		t.Errorf("priority decouple queue (-want,+got): %v", diff)
+	}
+	if !r.hadPriorityQueue(b) {
+		t.Error("hadPriorityQueue got=false, want=true")
+	}
+
+	// Opting out removes the priority queue.
+	b.Annotations[brokerv1beta1.PriorityQueueAnnotation] = "false"
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+	got, _ = r.targetsConfig.GetBroker(testNS, brokerName)
+	if got.PriorityDecoupleQueue != nil {
+		t.Errorf("priority decouple queue got=%v, want=nil", got.PriorityDecoupleQueue)
+	}
+}
+
+func TestReconcileConfigEventPolicy(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	r := &Reconciler{Base: &reconciler.Base{Recorder: recorder}, targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress),
+		WithBrokerEventPolicy(`{"allowedTypes":["com.example.*"],"deniedSources":["//test"]}`))
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	if diff := cmp.Diff([]string{"com.example.*"}, got.AllowedEventTypes); diff != "" {
+		t.Errorf("allowed event types (-want,+got): %v", diff)
+	}
+	if diff := cmp.Diff([]string{"//test"}, got.DeniedEventSources); diff != "" {
+		t.Errorf("denied event sources (-want,+got): %v", diff)
+	}
+
+	// A malformed policy rejects all events.
+	b.Annotations[brokerv1beta1.EventPolicyAnnotation] = "com.example.*"
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+	got, _ = r.targetsConfig.GetBroker(testNS, brokerName)
+	if got.Accepts("com.example.order", "//shop") {
+		t.Error("broker with a malformed event policy accepts events")
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.HasPrefix(event, "Warning InvalidEventPolicy ") {
+			t.Errorf("event got=%q, want an InvalidEventPolicy warning", event)
+		}
+	default:
+		t.Error("no event recorded for the malformed event policy")
+	}
+}
+
+func TestReconcileConfigEventEncoding(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress),
+		WithBrokerEventEncoding(brokerv1beta1.EventEncodingStructured))
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	if !got.StructuredEncoding {
+		t.Error("StructuredEncoding got=false, want=true")
+	}
+
+	// Switching back to the binary mode takes effect too.
+	b.Annotations[brokerv1beta1.EventEncodingAnnotation] = brokerv1beta1.EventEncodingBinary
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+	got, _ = r.targetsConfig.GetBroker(testNS, brokerName)
+	if got.StructuredEncoding {
+		t.Error("StructuredEncoding got=true, want=false")
+	}
+}
+
+func TestReconcileConfigDeduplicationWindow(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName),
+		NewTrigger("dedup-trigger", testNS, brokerName, WithTriggerDeduplicationWindow("500")),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	for name, want := range map[string]int32{"plain-trigger": 0, "dedup-trigger": 500} {
+		if w := got.Targets[name].GetDeduplicationWindow(); w != want {
+			t.Errorf("target %s DeduplicationWindow got=%v, want=%v", name, w, want)
+		}
+	}
+}
+
+func TestReconcileConfigMaxAge(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName),
+		NewTrigger("budget-trigger", testNS, brokerName, WithTriggerMaxAge("10m")),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	for name, want := range map[string]int64{"plain-trigger": 0, "budget-trigger": 600} {
+		if s := got.Targets[name].GetMaxAgeSeconds(); s != want {
+			t.Errorf("target %s MaxAgeSeconds got=%v, want=%v", name, s, want)
+		}
+	}
+}
+
+func TestReconcileConfigDeadLetterAddress(t *testing.T) {
+	ctx, _ := SetupFakeContext(t)
+	ctx = addressable.WithDuck(ctx)
+	for name, tc := range map[string]struct {
+		opts []BrokerOption
+		want string
+	}{
+		"no dead letter sink": {},
+		"dead letter sink": {
+			opts: []BrokerOption{WithBrokerDeadLetterSinkURI(apis.HTTP("dls.example.com"))},
+			want: "http://dls.example.com",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				targetsConfig: memory.NewEmptyTargets(),
+				uriResolver:   resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+			}
+			b := NewBroker(brokerName, testNS, append([]BrokerOption{WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress)}, tc.opts...)...)
+			triggers := []*brokerv1beta1.Trigger{NewTrigger("trigger", testNS, brokerName)}
+			r.reconcileConfig(ctx, b, resources.DefaultBroekrCellName, testProject, triggers)
+
+			got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+			if !ok {
+				t.Fatal("broker is missing from the targets config")
+			}
+			if a := got.Targets["trigger"].GetDeadLetterAddress(); a != tc.want {
+				t.Errorf("DeadLetterAddress got=%q, want=%q", a, tc.want)
+			}
+		})
+	}
+}
+
+func TestReconcileConfigCEOverrides(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName),
+		NewTrigger("overrides-trigger", testNS, brokerName, WithTriggerCEOverrides(`{"region":"europe"}`)),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	for name, want := range map[string]map[string]string{"plain-trigger": nil, "overrides-trigger": {"region": "europe"}} {
+		if diff := cmp.Diff(want, got.Targets[name].GetCeOverrides()); diff != "" {
+			t.Errorf("target %s CeOverrides (-want,+got): %v", name, diff)
+		}
+	}
+}
+
+func TestReconcileConfigTransform(t *testing.T) {
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName),
+		NewTrigger("transform-trigger", testNS, brokerName, WithTriggerTransform(`{"user":"event.data.user.id"}`)),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	for name, want := range map[string]map[string]string{"plain-trigger": nil, "transform-trigger": {"user": "event.data.user.id"}} {
+		if diff := cmp.Diff(want, got.Targets[name].GetTransform()); diff != "" {
+			t.Errorf("target %s Transform (-want,+got): %v", name, diff)
+		}
+	}
+}
+
+func TestReconcileConfigDeliveryHeaders(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "sink", Namespace: testNS},
+		Data:       map[string][]byte{"api-key": []byte("secret")},
+	}
+	listers := NewListers([]runtime.Object{secret})
+	r := &Reconciler{
+		secretLister:  listers.GetSecretLister(),
+		targetsConfig: memory.NewEmptyTargets(),
+	}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName, WithTriggerUID("plain-uid")),
+		NewTrigger("headers-trigger", testNS, brokerName, WithTriggerUID("headers-uid"),
+			WithTriggerDeliveryHeaders(`[{"name":"X-Api-Key","valueFrom":{"secretKeyRef":{"name":"sink","key":"api-key"}}},{"name":"X-Tenant","value":"acme"}]`)),
+		NewTrigger("missing-secret-trigger", testNS, brokerName, WithTriggerUID("missing-uid"),
+			WithTriggerDeliveryHeaders(`[{"name":"X-Api-Key","valueFrom":{"secretKeyRef":{"name":"missing","key":"api-key"}}}]`)),
+		NewTrigger("malformed-trigger", testNS, brokerName, WithTriggerUID("malformed-uid"), WithTriggerDeliveryHeaders(`X-Api-Key: secret`)),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	// Triggers whose headers cannot be resolved keep their key, so that
+	// their events are not delivered without them.
+	for name, want := range map[string]string{
+		"plain-trigger":          "",
+		"headers-trigger":        "headers-uid",
+		"missing-secret-trigger": "missing-uid",
+		"malformed-trigger":      "malformed-uid",
+	} {
+		if got := got.Targets[name].GetDeliveryHeadersKey(); got != want {
+			t.Errorf("target %s DeliveryHeadersKey got=%q, want=%q", name, got, want)
+		}
+	}
+	wantHeaders := map[string][]byte{"headers-uid": []byte(`{"X-Api-Key":"secret","X-Tenant":"acme"}`)}
+	if diff := cmp.Diff(wantHeaders, r.deliveryHeaders[b.Namespace+"/"+b.Name]); diff != "" {
+		t.Errorf("delivery headers (-want,+got): %v", diff)
+	}
+}
+
+func TestUpdateDeliveryHeadersSecret(t *testing.T) {
+	headersTarget := &config.Target{Name: "headers", Namespace: testNS, Broker: brokerName, DeliveryHeadersKey: "headers-uid"}
+	unresolvedTarget := &config.Target{Name: "unresolved", Namespace: testNS, Broker: brokerName, DeliveryHeadersKey: "unresolved-uid"}
+	plainTarget := &config.Target{Name: "plain", Namespace: testNS, Broker: brokerName}
+	resolved := map[string]map[string][]byte{
+		testNS + "/" + brokerName: {"headers-uid": []byte(`{"X-Api-Key":"new"}`)},
+	}
+	newSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: brokercellresources.DeliveryHeadersSecretName, Namespace: systemNS},
+			Data:       data,
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		targets     []*config.Target
+		replyKey    string
+		replyTokens map[string]string
+		existing    *corev1.Secret
+		want        map[string][]byte
+		wantNone    bool
+	}{{
+		name:     "no delivery headers",
+		targets:  []*config.Target{plainTarget},
+		wantNone: true,
+	}, {
+		name:    "create",
+		targets: []*config.Target{plainTarget, headersTarget},
+		want:    map[string][]byte{"headers-uid": []byte(`{"X-Api-Key":"new"}`)},
+	}, {
+		name:    "update and keep unresolved entries",
+		targets: []*config.Target{headersTarget, unresolvedTarget},
+		existing: newSecret(map[string][]byte{
+			"headers-uid":    []byte(`{"X-Api-Key":"old"}`),
+			"unresolved-uid": []byte(`{"X-Api-Key":"unresolved"}`),
+		}),
+		want: map[string][]byte{
+			"headers-uid":    []byte(`{"X-Api-Key":"new"}`),
+			"unresolved-uid": []byte(`{"X-Api-Key":"unresolved"}`),
+		},
+	}, {
+		name:     "remove entries of deleted targets",
+		targets:  []*config.Target{plainTarget},
+		existing: newSecret(map[string][]byte{"deleted-uid": []byte(`{"X-Api-Key":"deleted"}`)}),
+		want:     map[string][]byte{},
+	}, {
+		name:        "reply headers",
+		targets:     []*config.Target{plainTarget},
+		replyKey:    "reply-uid",
+		replyTokens: map[string]string{testNS + "/" + brokerName: "token"},
+		want:        map[string][]byte{"reply-uid": []byte(`{"Authorization":"Bearer token"}`)},
+	}, {
+		name:     "keep unresolved reply headers",
+		targets:  []*config.Target{plainTarget},
+		replyKey: "reply-uid",
+		existing: newSecret(map[string][]byte{"reply-uid": []byte(`{"Authorization":"Bearer old"}`)}),
+		want:     map[string][]byte{"reply-uid": []byte(`{"Authorization":"Bearer old"}`)},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objs []runtime.Object
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			listers := NewListers(objs)
+			ctx := logging.WithLogger(context.Background(), logtesting.TestLogger(t))
+			ctx, kubeClient := fakekubeclient.With(ctx, listers.GetKubeObjects()...)
+			ctx, _ = fakerunclient.With(ctx)
+			ctx, _ = fakeservingclient.With(ctx)
+			ctx, _ = fakedynamicclient.With(ctx, scheme.Scheme)
+
+			targets := memory.NewEmptyTargets()
+			targets.MutateBroker(testNS, brokerName, func(m config.BrokerMutation) {
+				m.SetReplyHeadersKey(tc.replyKey)
+				m.UpsertTargets(tc.targets...)
+			})
+			r := &Reconciler{
+				Base:            reconciler.NewBase(ctx, controllerAgentName, nil),
+				secretLister:    listers.GetSecretLister(),
+				targetsConfig:   targets,
+				deliveryHeaders: resolved,
+				replyTokens:     tc.replyTokens,
+			}
+			if err := r.updateDeliveryHeadersSecret(ctx); err != nil {
+				t.Fatalf("updateDeliveryHeadersSecret() = %v", err)
+			}
+
+			got, err := kubeClient.CoreV1().Secrets(systemNS).Get(brokercellresources.DeliveryHeadersSecretName, metav1.GetOptions{})
+			if tc.wantNone {
+				if !apierrs.IsNotFound(err) {
+					t.Errorf("delivery headers secret got=%v, want none", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error getting delivery headers secret: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Data, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected Data (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileConfigSubscribers(t *testing.T) {
+	ctx, _ := SetupFakeContext(t)
+	ctx = addressable.WithDuck(ctx)
+	r := &Reconciler{
+		targetsConfig: memory.NewEmptyTargets(),
+		uriResolver:   resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+	}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("plain-trigger", testNS, brokerName),
+		NewTrigger("parallel-trigger", testNS, brokerName,
+			WithTriggerSubscribers(`[{"uri":"http://audit.example.com"},{"uri":"http://archive.example.com"}]`)),
+		NewTrigger("sequential-trigger", testNS, brokerName, WithTriggerSequentialSubscribers,
+			WithTriggerSubscribers(`[{"uri":"http://audit.example.com"},{"ref":{"apiVersion":"serving.knative.dev/v1","kind":"Service","name":"missing"}}]`)),
+		NewTrigger("malformed-trigger", testNS, brokerName, WithTriggerSubscribers(`http://audit.example.com`)),
+	}
+	r.reconcileConfig(ctx, b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	for name, want := range map[string]struct {
+		addresses  []string
+		sequential bool
+	}{
+		"plain-trigger":      {},
+		"parallel-trigger":   {addresses: []string{"http://audit.example.com", "http://archive.example.com"}},
+		"sequential-trigger": {addresses: []string{"http://audit.example.com"}, sequential: true},
+		"malformed-trigger":  {},
+	} {
+		target := got.Targets[name]
+		if diff := cmp.Diff(want.addresses, target.GetSubscriberAddresses()); diff != "" {
+			t.Errorf("target %s SubscriberAddresses (-want,+got): %v", name, diff)
+		}
+		if target.GetSequentialSubscribers() != want.sequential {
+			t.Errorf("target %s SequentialSubscribers got=%v, want=%v", name, target.GetSequentialSubscribers(), want.sequential)
+		}
+	}
+}
+
+func TestReconcileConfigDirectAddress(t *testing.T) {
+	serviceGVK := metav1.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Service"}
+	ready := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: testNS},
+		Status: servingv1.ServiceStatus{
+			Status: duckv1.Status{
+				Conditions: duckv1.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}},
+			},
+			ConfigurationStatusFields: servingv1.ConfigurationStatusFields{
+				LatestReadyRevisionName: "ready-00002",
+			},
+		},
+	}
+	notReady := ready.DeepCopy()
+	notReady.Name = "not-ready"
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	split := ready.DeepCopy()
+	split.Name = "split"
+	split.Status.Traffic = []servingv1.TrafficTarget{
+		{RevisionName: "ready-00001", Percent: ptr.Int64(50)},
+		{RevisionName: "ready-00002", Percent: ptr.Int64(50)},
+	}
+	listers := NewListers([]runtime.Object{ready, notReady, split})
+
+	r := &Reconciler{
+		serviceLister: listers.GetV1ServiceLister(),
+		targetsConfig: memory.NewEmptyTargets(),
+	}
+	b := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	triggers := []*brokerv1beta1.Trigger{
+		NewTrigger("not-enabled", testNS, brokerName, WithTriggerSubscriberRef(serviceGVK, "ready", testNS)),
+		NewTrigger("ready", testNS, brokerName, WithTriggerDirectDelivery, WithTriggerSubscriberRef(serviceGVK, "ready", "")),
+		NewTrigger("not-ready", testNS, brokerName, WithTriggerDirectDelivery, WithTriggerSubscriberRef(serviceGVK, "not-ready", testNS)),
+		NewTrigger("split", testNS, brokerName, WithTriggerDirectDelivery, WithTriggerSubscriberRef(serviceGVK, "split", testNS)),
+		NewTrigger("missing", testNS, brokerName, WithTriggerDirectDelivery, WithTriggerSubscriberRef(serviceGVK, "missing", testNS)),
+		NewTrigger("uri", testNS, brokerName, WithTriggerDirectDelivery, WithTriggerSubscriberURI("http://example.com/")),
+	}
+	r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, triggers)
+
+	got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	want := map[string]string{
+		"not-enabled": "",
+		"ready":       "http://ready-00002-private." + testNS + ".svc.cluster.local",
+		"not-ready":   "",
+		"split":       "",
+		"missing":     "",
+		"uri":         "",
+	}
+	for name, want := range want {
+		if a := got.Targets[name].GetDirectAddress(); a != want {
+			t.Errorf("target %s DirectAddress got=%q, want=%q", name, a, want)
+		}
+	}
+}
+
+func TestReconcileConfigMetricLabels(t *testing.T) {
+	old, ok := os.LookupEnv(metrics.MetricLabelAnnotationsEnvKey)
+	os.Setenv(metrics.MetricLabelAnnotationsEnvKey, "example.com/team,example.com/cost-center")
+	defer func() {
+		if ok {
+			os.Setenv(metrics.MetricLabelAnnotationsEnvKey, old)
+		} else {
+			os.Unsetenv(metrics.MetricLabelAnnotationsEnvKey)
+		}
+	}()
+
+	broker := NewBroker(brokerName, testNS, WithBrokerUID(testUID), WithBrokerReadyURI(brokerAddress))
+	broker.Annotations = map[string]string{
+		"example.com/team":        "payments",
+		"example.com/cost-center": "cc-1234",
+	}
+	trigger := NewTrigger("test-trigger", testNS, brokerName)
+	trigger.Annotations = map[string]string{
+		"example.com/team":  "checkout",
+		"example.com/other": "ignored",
+	}
+
+	r := &Reconciler{targetsConfig: memory.NewEmptyTargets()}
+	r.reconcileConfig(context.Background(), broker, resources.DefaultBroekrCellName, testProject, []*brokerv1beta1.Trigger{trigger})
+
+	b, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+	if !ok {
+		t.Fatal("broker is missing from the targets config")
+	}
+	wantBroker := map[string]string{"team": "payments", "cost_center": "cc-1234"}
+	if diff := cmp.Diff(wantBroker, b.MetricLabels); diff != "" {
+		t.Errorf("unexpected broker MetricLabels (-want, +got) = %v", diff)
+	}
+	target, ok := b.Targets[trigger.Name]
+	if !ok {
+		t.Fatal("trigger is missing from the targets config")
+	}
+	wantTarget := map[string]string{"team": "checkout", "cost_center": "cc-1234"}
+	if diff := cmp.Diff(wantTarget, target.MetricLabels); diff != "" {
+		t.Errorf("unexpected target MetricLabels (-want, +got) = %v", diff)
+	}
+}
+
+func TestReconcileConfigIngressAuth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "producers", Namespace: testNS},
+		Data:       map[string][]byte{resources.IngressAuthTokensKey: []byte("token-1\ntoken-2\n")},
+	}
+	empty := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: testNS},
+		Data:       map[string][]byte{"other": []byte("token")},
+	}
+	// The reply token of the broker with testUID was written out before.
+	deliveryHeaders := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: brokercellresources.DeliveryHeadersSecretName, Namespace: systemNS},
+		Data:       map[string][]byte{"reply-" + testUID: []byte(`{"Authorization":"Bearer reply-token"}`)},
+	}
+	listers := NewListers([]runtime.Object{secret, empty, deliveryHeaders})
+
+	testCases := []struct {
+		name           string
+		uid            string
+		secret         string
+		wantKey        string
+		wantReplyKey   string
+		wantReplyToken string
+		wantHashes     []byte
+	}{{
+		name: "no token required",
+		uid:  testUID,
+	}, {
+		name:           "tokens",
+		uid:            testUID,
+		secret:         "producers",
+		wantKey:        testUID,
+		wantReplyKey:   "reply-" + testUID,
+		wantReplyToken: "reply-token",
+		wantHashes:     []byte(auth.Hash("token-1") + "\n" + auth.Hash("token-2") + "\n" + auth.Hash("reply-token")),
+	}, {
+		name:         "new reply token",
+		uid:          "new-uid",
+		secret:       "producers",
+		wantKey:      "new-uid",
+		wantReplyKey: "reply-new-uid",
+	}, {
+		name:           "missing secret",
+		uid:            testUID,
+		secret:         "missing",
+		wantKey:        testUID,
+		wantReplyKey:   "reply-" + testUID,
+		wantReplyToken: "reply-token",
+	}, {
+		name:           "no tokens",
+		uid:            testUID,
+		secret:         "empty",
+		wantKey:        testUID,
+		wantReplyKey:   "reply-" + testUID,
+		wantReplyToken: "reply-token",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Reconciler{
+				secretLister:  listers.GetSecretLister(),
+				targetsConfig: memory.NewEmptyTargets(),
+			}
+			opts := []BrokerOption{WithBrokerUID(tc.uid), WithBrokerReadyURI(brokerAddress)}
+			if tc.secret != "" {
+				opts = append(opts, WithBrokerIngressAuthSecret(tc.secret))
+			}
+			b := NewBroker(brokerName, testNS, opts...)
+			r.reconcileConfig(context.Background(), b, resources.DefaultBroekrCellName, testProject, nil)
+
+			got, ok := r.targetsConfig.GetBroker(testNS, brokerName)
+			if !ok {
+				t.Fatal("broker is missing from the targets config")
+			}
+			// Brokers whose tokens cannot be resolved keep their key, so
+			// that requests are not let in without a token.
+			if got.GetIngressAuthKey() != tc.wantKey {
+				t.Errorf("IngressAuthKey got=%q, want=%q", got.GetIngressAuthKey(), tc.wantKey)
+			}
+			if got.GetReplyHeadersKey() != tc.wantReplyKey {
+				t.Errorf("ReplyHeadersKey got=%q, want=%q", got.GetReplyHeadersKey(), tc.wantReplyKey)
+			}
+			replyToken := r.replyTokens[b.Namespace+"/"+b.Name]
+			wantHashes := tc.wantHashes
+			if tc.wantReplyKey != "" && tc.wantReplyToken == "" {
+				// A new reply token is generated, whose hash is accepted
+				// along with the broker's tokens.
+				if len(replyToken) != 64 {
+					t.Errorf("generated reply token got=%q, want 64 hex digits", replyToken)
+				}
+				wantHashes = []byte(auth.Hash("token-1") + "\n" + auth.Hash("token-2") + "\n" + auth.Hash(replyToken))
+			} else if replyToken != tc.wantReplyToken {
+				t.Errorf("reply token got=%q, want=%q", replyToken, tc.wantReplyToken)
+			}
+			if diff := cmp.Diff(wantHashes, r.ingressAuth[b.Namespace+"/"+b.Name]); diff != "" {
+				t.Errorf("ingress auth hashes (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateIngressAuthSecret(t *testing.T) {
+	resolved := map[string][]byte{testNS + "/auth-broker": []byte("new")}
+	newSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: brokercellresources.IngressAuthSecretName, Namespace: systemNS},
+			Data:       data,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		brokers  map[string]string
+		existing *corev1.Secret
+		want     map[string][]byte
+		wantNone bool
+	}{{
+		name:     "no token required",
+		brokers:  map[string]string{"plain-broker": ""},
+		wantNone: true,
+	}, {
+		name:    "create",
+		brokers: map[string]string{"plain-broker": "", "auth-broker": "auth-uid"},
+		want:    map[string][]byte{"auth-uid": []byte("new")},
+	}, {
+		name:    "update and keep unresolved entries",
+		brokers: map[string]string{"auth-broker": "auth-uid", "unresolved-broker": "unresolved-uid"},
+		existing: newSecret(map[string][]byte{
+			"auth-uid":       []byte("old"),
+			"unresolved-uid": []byte("unresolved"),
+		}),
+		want: map[string][]byte{
+			"auth-uid":       []byte("new"),
+			"unresolved-uid": []byte("unresolved"),
+		},
+	}, {
+		name:     "remove entries of deleted brokers",
+		brokers:  map[string]string{"plain-broker": ""},
+		existing: newSecret(map[string][]byte{"deleted-uid": []byte("deleted")}),
+		want:     map[string][]byte{},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var objs []runtime.Object
+			if tc.existing != nil {
+				objs = append(objs, tc.existing)
+			}
+			listers := NewListers(objs)
+			ctx := logging.WithLogger(context.Background(), logtesting.TestLogger(t))
+			ctx, kubeClient := fakekubeclient.With(ctx, listers.GetKubeObjects()...)
+			ctx, _ = fakerunclient.With(ctx)
+			ctx, _ = fakeservingclient.With(ctx)
+			ctx, _ = fakedynamicclient.With(ctx, scheme.Scheme)
+
+			targets := memory.NewEmptyTargets()
+			for name, key := range tc.brokers {
+				key := key
+				targets.MutateBroker(testNS, name, func(m config.BrokerMutation) {
+					m.SetIngressAuthKey(key)
+				})
+			}
+			r := &Reconciler{
+				Base:          reconciler.NewBase(ctx, controllerAgentName, nil),
+				secretLister:  listers.GetSecretLister(),
+				targetsConfig: targets,
+				ingressAuth:   resolved,
+			}
+			if err := r.updateIngressAuthSecret(ctx); err != nil {
+				t.Fatalf("updateIngressAuthSecret() = %v", err)
+			}
+
+			got, err := kubeClient.CoreV1().Secrets(systemNS).Get(brokercellresources.IngressAuthSecretName, metav1.GetOptions{})
+			if tc.wantNone {
+				if !apierrs.IsNotFound(err) {
+					t.Errorf("ingress auth secret got=%v, want none", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error getting ingress auth secret: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got.Data, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected Data (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 
@@ -33,14 +36,19 @@ import (
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
 	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
+	gpubsublitetesting "github.com/google/knative-gcp/pkg/gclient/pubsublite/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 )
 
@@ -68,6 +76,18 @@ var (
 		Host:   fmt.Sprintf("%s.%s.svc.%s", ingressServiceName, systemNS, utils.GetClusterDomainName()),
 		Path:   fmt.Sprintf("/%s/%s", testNS, brokerName),
 	}
+
+	// publishFailureTime is the time of the publish failures reported in
+	// tests, recent enough not to be expired.
+	publishFailureTime = time.Now().UTC().Truncate(time.Second)
+
+	teamLabels        = map[string]string{"team": "payments"}
+	teamBrokerCell    = "payments"
+	teamBrokerAddress = &apis.URL{
+		Scheme: "http",
+		Host:   fmt.Sprintf("%s.%s.svc.%s", brokercellresources.Name(teamBrokerCell, brokercellresources.IngressName), systemNS, utils.GetClusterDomainName()),
+		Path:   fmt.Sprintf("/%s/%s", testNS, brokerName),
+	}
 )
 
 func init() {
@@ -113,8 +133,8 @@ func TestAllCases(t *testing.T) {
 				WithBrokerDeletionTimestamp),
 		},
 		WantEvents: []string{
-			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
-			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123"`),
 			brokerFinalizedEvent,
 		},
 		OtherTestData: map[string]interface{}{
@@ -141,12 +161,268 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Create broker with a subscription naming, the subscription is named",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID)),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/prod-testnamespace-cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/prod-testnamespace-cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre":    []PubsubAction{},
+			"naming": psresources.SubscriptionNaming{Prefix: "prod-{{.Namespace}}-"},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("prod-testnamespace-cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Subscription naming set after the decoupling subscription was created, the subscription is kept",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID)),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub("cre-bkr_testnamespace_test-broker_abc123", "cre-bkr_testnamespace_test-broker_abc123"),
+			},
+			"targets": cloudTargets(),
+			"naming":  psresources.SubscriptionNaming{Prefix: "prod-{{.Namespace}}-"},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			OnlySubscriptions("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Create broker with a priority queue, both queues are created",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerPriorityQueue),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerPriorityQueue,
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkrp_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkrp_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkrp_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkrp_testnamespace_test-broker_abc123?project=test-project-id`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+			TopicExists("cre-bkrp_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkrp_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Message ordering enabled on an existing broker, decoupling pullsub is recreated",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerMessageOrdering),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerMessageOrdering,
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeWarning, "MessageOrderingChanged", `Message ordering changed to true, recreating subscription: "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub("cre-bkr_testnamespace_test-broker_abc123", "cre-bkr_testnamespace_test-broker_abc123"),
+			},
+			"targets": unorderedTargets(),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker with a priority queue is being deleted, both queues are deleted",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerPriorityQueue,
+				WithInitBrokerConditions,
+				WithBrokerDeletionTimestamp),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-bkrp_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-bkrp_testnamespace_test-broker_abc123"`),
+			brokerFinalizedEvent,
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub("cre-bkr_testnamespace_test-broker_abc123", "cre-bkr_testnamespace_test-broker_abc123"),
+				TopicAndSub("cre-bkrp_testnamespace_test-broker_abc123", "cre-bkrp_testnamespace_test-broker_abc123"),
+			},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			NoTopicsExist(),
+			NoSubscriptionsExist(),
+		},
+	}, {
+		Name: "Data plane reports publish failures, broker is not ready",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID)),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: status.ConfigMapName, Namespace: systemNS},
+				Data: map[string]string{
+					"ingress-1": fmt.Sprintf(`[{"broker":"testnamespace/test-broker","queue":"decouple","reason":"PublishQuotaExceeded","error":"quota exceeded","time":%q}]`, publishFailureTime.Format(time.RFC3339)),
+				},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishFailed(status.ReasonQuotaExceeded, fmt.Sprintf(`Events are dropped because publishing to the decouple topic "cre-bkr_testnamespace_test-broker_abc123" failed at %s: quota exceeded. Raise the Pub/Sub quota of the project or reduce the rate of events.`, publishFailureTime.Format(time.RFC3339))),
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{},
+		},
+	}, {
+		Name: "Create broker selected by a brokercell, broker is served by the selecting brokercell",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLabels(teamLabels)),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+			NewBrokerCell(teamBrokerCell, systemNS,
+				WithBrokerCellBrokerSelector(&metav1.LabelSelector{MatchLabels: teamLabels}),
+				WithBrokerCellIngressFailed("", "")),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLabels(teamLabels),
+				WithBrokerReadyURI(teamBrokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+				WithBrokerBrokerCellUnknown("BrokerCellNotReady", "Brokercell knative-testing/payments is not ready"),
 			),
 		}},
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
-			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
-			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
 			brokerReconciledEvent,
 		},
 		WantPatches: []clientgotesting.PatchActionImpl{
@@ -174,13 +450,14 @@ func TestAllCases(t *testing.T) {
 				WithBrokerUID(testUID),
 				WithBrokerReadyURI(brokerAddress),
 				WithBrokerConfigReady,
+				WithBrokerPublishReady,
 				WithBrokerBrokerCellUnknown("BrokerCellNotReady", "Brokercell knative-testing/default is not ready"),
 			),
 		}},
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
-			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
-			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
 			brokerReconciledEvent,
 		},
 		WantPatches: []clientgotesting.PatchActionImpl{
@@ -239,6 +516,7 @@ func TestAllCases(t *testing.T) {
 					WithBrokerUID(testUID),
 					WithBrokerReadyURI(brokerAddress),
 					WithBrokerConfigReady,
+					WithBrokerPublishReady,
 					WithBrokerBrokerCellUnknown("BrokerCellNotReady", "Brokercell knative-testing/default is not ready"),
 				),
 			},
@@ -248,8 +526,8 @@ func TestAllCases(t *testing.T) {
 		WantEvents: []string{
 			brokerFinalizerUpdatedEvent,
 			Eventf(corev1.EventTypeNormal, "BrokerCellCreated", `Created brokercell knative-testing/default`),
-			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-bkr_testnamespace_test-broker_abc123"`),
-			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-bkr_testnamespace_test-broker_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-bkr_testnamespace_test-broker_abc123?project=test-project-id`),
 			brokerReconciledEvent,
 		},
 		WantPatches: []clientgotesting.PatchActionImpl{
@@ -262,6 +540,107 @@ func TestAllCases(t *testing.T) {
 			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
 			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
 		},
+	}, {
+		Name: "Create broker on Pub/Sub Lite, Pub/Sub Lite queue is created",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLiteLocation("us-central1-a")),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLiteLocation("us-central1-a"),
+				WithBrokerReadyURI(brokerAddress),
+				WithBrokerConfigReady,
+				WithBrokerPublishReady,
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeNormal, "TopicCreated", `Created Pub/Sub Lite topic "projects/test-project-id/locations/us-central1-a/topics/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created Pub/Sub Lite subscription "projects/test-project-id/locations/us-central1-a/subscriptions/cre-bkr_testnamespace_test-broker_abc123"`),
+			brokerReconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre":  []PubsubAction{},
+			"lite": gpubsublitetesting.TestAdminClientData{},
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			NoTopicsExist(),
+			NoSubscriptionsExist(),
+		},
+	}, {
+		Name: "Pub/Sub Lite location set on an existing broker, change is rejected",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLiteLocation("us-central1-a")),
+			NewBrokerCell(resources.DefaultBroekrCellName, systemNS, WithBrokerCellReady),
+		},
+		WantErr: true,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLiteLocation("us-central1-a"),
+				WithInitBrokerConditions,
+				WithBrokerBrokerCellReady,
+				WithBrokerAddressURI(brokerAddress),
+				WithBrokerTopicFailed("LiteLocationChanged", `the Pub/Sub Lite location of the queues can't be changed from "" to "us-central1-a"`),
+				WithBrokerSubscriptionFailed("LiteLocationChanged", `the Pub/Sub Lite location of the queues can't be changed from "" to "us-central1-a"`),
+			),
+		}},
+		WantEvents: []string{
+			brokerFinalizerUpdatedEvent,
+			Eventf(corev1.EventTypeWarning, "LiteLocationChanged", `Ignoring the changed Pub/Sub Lite location: the Pub/Sub Lite location of the queues can't be changed from "" to "us-central1-a"`),
+			Eventf(corev1.EventTypeWarning, "InternalError", `failed to reconcile broker: decoupling topic reconcile failed: the Pub/Sub Lite location of the queues can't be changed from "" to "us-central1-a"`),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, brokerName, brokerFinalizerName),
+		},
+		OtherTestData: map[string]interface{}{
+			"pre": []PubsubAction{
+				TopicAndSub("cre-bkr_testnamespace_test-broker_abc123", "cre-bkr_testnamespace_test-broker_abc123"),
+			},
+			"targets": cloudTargets(),
+		},
+		PostConditions: []func(*testing.T, *TableRow){
+			TopicExists("cre-bkr_testnamespace_test-broker_abc123"),
+			SubscriptionExists("cre-bkr_testnamespace_test-broker_abc123"),
+		},
+	}, {
+		Name: "Broker on Pub/Sub Lite is being deleted, its queue and the retry queues of its triggers are deleted",
+		Key:  testKey,
+		Objects: []runtime.Object{
+			NewBroker(brokerName, testNS,
+				WithBrokerClass(brokerv1beta1.BrokerClass),
+				WithBrokerUID(testUID),
+				WithBrokerLiteLocation("us-central1-a"),
+				WithInitBrokerConditions,
+				WithBrokerDeletionTimestamp),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted Pub/Sub Lite subscription "projects/test-project-id/locations/us-central1-a/subscriptions/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted Pub/Sub Lite topic "projects/test-project-id/locations/us-central1-a/topics/cre-bkr_testnamespace_test-broker_abc123"`),
+			Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted Pub/Sub Lite subscription "projects/test-project-id/locations/us-central1-a/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted Pub/Sub Lite topic "projects/test-project-id/locations/us-central1-a/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+			brokerFinalizedEvent,
+		},
+		OtherTestData: map[string]interface{}{
+			"pre":     []PubsubAction{},
+			"lite":    gpubsublitetesting.TestAdminClientData{TopicExists: true, SubscriptionExists: true},
+			"targets": liteTargets(),
+		},
 	}}
 
 	defer logtesting.ClearAll()
@@ -279,6 +658,16 @@ func TestAllCases(t *testing.T) {
 			}
 		}
 
+		targets := memory.NewEmptyTargets()
+		if testData["targets"] != nil {
+			targets = testData["targets"].(config.Targets)
+		}
+
+		var naming psresources.SubscriptionNaming
+		if testData["naming"] != nil {
+			naming = testData["naming"].(psresources.SubscriptionNaming)
+		}
+
 		ctx = addressable.WithDuck(ctx)
 		ctx = resource.WithDuck(ctx)
 		r := &Reconciler{
@@ -289,15 +678,70 @@ func TestAllCases(t *testing.T) {
 			deploymentLister:   listers.GetDeploymentLister(),
 			podLister:          listers.GetPodLister(),
 			brokerCellLister:   listers.GetBrokerCellLister(),
-			targetsConfig:      memory.NewEmptyTargets(),
+			serviceLister:      listers.GetV1ServiceLister(),
+			secretLister:       listers.GetSecretLister(),
+			targetsConfig:      targets,
 			targetsNeedsUpdate: make(chan struct{}),
 			projectID:          testProject,
 			pubsubClient:       psclient,
+			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+			createLiteClientFn: gpubsublitetesting.TestAdminClientCreator(testData["lite"]),
+			subscriptionNaming: naming,
 		}
 		return brokerreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetBrokerLister(), r.Recorder, r, brokerv1beta1.BrokerClass)
 	}))
 }
 
+// unorderedTargets returns targets in which the test broker was last written
+// without message ordering.
+func unorderedTargets() config.Targets {
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker(testNS, brokerName, func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        "cre-bkr_testnamespace_test-broker_abc123",
+			Subscription: "cre-bkr_testnamespace_test-broker_abc123",
+		})
+	})
+	return targets
+}
+
+// cloudTargets returns targets in which the test broker was last written with
+// its queue on Cloud Pub/Sub.
+func cloudTargets() config.Targets {
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker(testNS, brokerName, func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        "cre-bkr_testnamespace_test-broker_abc123",
+			Subscription: "cre-bkr_testnamespace_test-broker_abc123",
+		})
+	})
+	return targets
+}
+
+// liteTargets returns targets in which the test broker was last written
+// with its queue on Pub/Sub Lite, along with a trigger.
+func liteTargets() config.Targets {
+	targets := memory.NewEmptyTargets()
+	targets.MutateBroker(testNS, brokerName, func(m config.BrokerMutation) {
+		m.SetDecoupleQueue(&config.Queue{
+			Topic:        "projects/test-project-id/locations/us-central1-a/topics/cre-bkr_testnamespace_test-broker_abc123",
+			Subscription: "projects/test-project-id/locations/us-central1-a/subscriptions/cre-bkr_testnamespace_test-broker_abc123",
+			Location:     "us-central1-a",
+		})
+		m.UpsertTargets(&config.Target{
+			Name:      "test-trigger",
+			Namespace: testNS,
+			Broker:    brokerName,
+			RetryQueue: &config.Queue{
+				Topic:        "projects/test-project-id/locations/us-central1-a/topics/cre-tgr_testnamespace_test-trigger_abc123",
+				Subscription: "projects/test-project-id/locations/us-central1-a/subscriptions/cre-tgr_testnamespace_test-trigger_abc123",
+				Location:     "us-central1-a",
+			},
+		})
+	})
+	return targets
+}
+
 func patchFinalizers(namespace, name, finalizer string) clientgotesting.PatchActionImpl {
 	action := clientgotesting.PatchActionImpl{}
 	action.Name = name
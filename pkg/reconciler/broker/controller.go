@@ -21,7 +21,9 @@ import (
 	"os"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -32,19 +34,29 @@ import (
 	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
 	podinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/pod"
+	secretinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/secret"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+	serviceinformer "knative.dev/serving/pkg/client/injection/informers/serving/v1/service"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/broker/config/memory"
+	"github.com/google/knative-gcp/pkg/broker/status"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	brokerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/broker"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
@@ -54,6 +66,13 @@ const (
 	controllerAgentName = "broker-controller"
 )
 
+type envConfig struct {
+	// SubscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// decoupling subscriptions of the brokers and retry subscriptions of
+	// their triggers.
+	psresources.SubscriptionNaming
+}
+
 func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 	brokerInformer := brokerinformer.Get(ctx)
 	triggerInformer := triggerinformer.Get(ctx)
@@ -62,6 +81,16 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	deploymentInformer := deploymentinformer.Get(ctx)
 	podInformer := podinformer.Get(ctx)
 	bcInformer := brokercellinformer.Get(ctx)
+	serviceInformer := serviceinformer.Get(ctx)
+	secretInformer := secretinformer.Get(ctx)
+
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		logging.FromContext(ctx).Fatal("Failed to process env var", zap.Error(err))
+	}
+	if err := env.SubscriptionNaming.Validate(); err != nil {
+		logging.FromContext(ctx).Fatal("Invalid Pub/Sub subscription naming", zap.Error(err))
+	}
 
 	// If there is an error, the projectID will be empty. The reconciler will retry
 	// to get the projectID during reconciliation.
@@ -92,8 +121,12 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		deploymentLister:   deploymentInformer.Lister(),
 		podLister:          podInformer.Lister(),
 		brokerCellLister:   bcInformer.Lister(),
+		serviceLister:      serviceInformer.Lister(),
+		secretLister:       secretInformer.Lister(),
 		projectID:          projectID,
 		pubsubClient:       client,
+		createLiteClientFn: gpubsublite.NewAdminClient,
+		subscriptionNaming: env.SubscriptionNaming,
 		targetsNeedsUpdate: make(chan struct{}),
 	}
 
@@ -110,6 +143,7 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	go r.TargetsConfigUpdater(ctx)
 
 	impl := brokerreconciler.NewImpl(ctx, r, brokerv1beta1.BrokerClass)
+	r.uriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
 
 	r.Logger.Info("Setting up event handlers")
 
@@ -132,6 +166,28 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	//	Handler:    controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("" /*any namespace*/, eventing.BrokerLabelKey)),
 	//})
 
+	// Reconcile the brokers whose publish failures are reported, or no
+	// longer reported, by the data plane. Expired failures of data plane
+	// pods that are gone are cleared by the global resync.
+	enqueuePublishFailures := func(obj interface{}) {
+		if cm, ok := obj.(*corev1.ConfigMap); ok {
+			for _, b := range status.Brokers(cm) {
+				impl.EnqueueKey(b)
+			}
+		}
+	}
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), status.ConfigMapName),
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: enqueuePublishFailures,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				enqueuePublishFailures(oldObj)
+				enqueuePublishFailures(newObj)
+			},
+			DeleteFunc: enqueuePublishFailures,
+		},
+	})
+
 	triggerInformer.Informer().AddEventHandler(controller.HandleAll(
 		func(obj interface{}) {
 			if trigger, ok := obj.(*brokerv1beta1.Trigger); ok {
@@ -158,6 +214,58 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		},
 	))
 
+	// The direct address of a trigger changes with the revisions of its
+	// subscriber Service, so reconcile the brokers of the triggers delivering
+	// directly to a Service when it changes.
+	serviceInformer.Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			if s, ok := obj.(*servingv1.Service); ok {
+				triggers, err := triggerInformer.Lister().List(labels.Everything())
+				if err != nil {
+					r.Logger.Error("Failed to list triggers", zap.Error(err))
+					return
+				}
+				for _, t := range triggers {
+					if !resources.DirectDeliveryEnabled(t) {
+						continue
+					}
+					if namespace, name, ok := subscriberService(t); ok && namespace == s.Namespace && name == s.Name {
+						impl.EnqueueKey(types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.Broker})
+					}
+				}
+			}
+		},
+	))
+
+	// Reconcile the brokers of the triggers whose delivery headers come from
+	// a secret, and the brokers whose ingress auth tokens do, when it changes.
+	secretInformer.Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			if s, ok := obj.(*corev1.Secret); ok {
+				triggers, err := triggerInformer.Lister().Triggers(s.Namespace).List(labels.Everything())
+				if err != nil {
+					r.Logger.Error("Failed to list triggers", zap.Error(err))
+					return
+				}
+				for _, t := range triggers {
+					if deliveryHeadersSecret(t, s.Name) {
+						impl.EnqueueKey(types.NamespacedName{Namespace: t.Namespace, Name: t.Spec.Broker})
+					}
+				}
+				brokers, err := brokerInformer.Lister().Brokers(s.Namespace).List(labels.Everything())
+				if err != nil {
+					r.Logger.Error("Failed to list brokers", zap.Error(err))
+					return
+				}
+				for _, b := range brokers {
+					if ingressAuthSecret(b, s.Name) {
+						impl.Enqueue(b)
+					}
+				}
+			}
+		},
+	))
+
 	return impl
 }
 
@@ -167,7 +275,7 @@ func newPubsubClient(ctx context.Context, projectID string) (*pubsub.Client, err
 		return nil, err
 	}
 
-	client, err := pubsub.NewClient(ctx, projectID)
+	client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 	if err != nil {
 		return nil, err
 	}
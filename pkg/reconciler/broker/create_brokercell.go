@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/eventing/pkg/logging"
 	"knative.dev/eventing/pkg/reconciler/names"
 	"knative.dev/pkg/apis"
@@ -35,17 +36,15 @@ import (
 	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
 )
 
-// ensureBrokerCellExists creates a BrokerCell if it doesn't exist, and update broker status based on brokercell status.
-func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta1.Broker) error {
-	var bc *inteventsv1alpha1.BrokerCell
-	var err error
-	// TODO(#866) Get brokercell based on the label (or annotation) on the broker.
-	bc, err = r.brokerCellLister.BrokerCells(system.Namespace()).Get(resources.DefaultBroekrCellName)
+// ensureBrokerCellExists selects the BrokerCell serving the broker, creates the default BrokerCell if it doesn't
+// exist, and update broker status based on brokercell status.
+func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta1.Broker) (*inteventsv1alpha1.BrokerCell, error) {
+	bc, err := r.getBrokerCell(b)
 
 	if err != nil && !apierrs.IsNotFound(err) {
 		logging.FromContext(ctx).Error("Error reconciling brokercell", zap.String("namespace", b.Namespace), zap.String("broker", b.Name), zap.Error(err))
-		b.Status.MarkBrokerCelllUnknown("BrokerCellUnknown", "Failed to get brokercell %s/%s", bc.Namespace, bc.Name)
-		return err
+		b.Status.MarkBrokerCelllUnknown("BrokerCellUnknown", "Failed to get brokercell for broker %s/%s", b.Namespace, b.Name)
+		return nil, err
 	}
 
 	if apierrs.IsNotFound(err) {
@@ -54,7 +53,7 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta
 		if err != nil && !apierrs.IsAlreadyExists(err) {
 			logging.FromContext(ctx).Error("Error creating brokercell", zap.String("namespace", b.Namespace), zap.String("broker", b.Name), zap.Error(err))
 			b.Status.MarkBrokerCelllFailed("BrokerCellCreationFailed", "Failed to create %s/%s", want.Namespace, want.Name)
-			return err
+			return nil, err
 		}
 		if apierrs.IsAlreadyExists(err) {
 			logging.FromContext(ctx).Info("Brokercell already exists", zap.String("namespace", b.Namespace), zap.String("broker", b.Name))
@@ -64,7 +63,7 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta
 			if err != nil {
 				logging.FromContext(ctx).Error("Failed to get the brokercell from the API server", zap.String("namespace", b.Namespace), zap.String("broker", b.Name), zap.Error(err))
 				b.Status.MarkBrokerCelllUnknown("BrokerCellUnknown", "Failed to get the brokercell from the API server %s/%s", want.Namespace, want.Name)
-				return err
+				return nil, err
 			}
 		}
 		if err == nil {
@@ -86,5 +85,18 @@ func (r *Reconciler) ensureBrokerCellExists(ctx context.Context, b *brokerv1beta
 		Path:   fmt.Sprintf("/%s/%s", b.Namespace, b.Name),
 	})
 
-	return nil
+	return bc, nil
+}
+
+// getBrokerCell returns the BrokerCell whose broker selector selects the broker. Brokers not selected by any
+// BrokerCell are served by the default BrokerCell.
+func (r *Reconciler) getBrokerCell(b *brokerv1beta1.Broker) (*inteventsv1alpha1.BrokerCell, error) {
+	bcs, err := r.brokerCellLister.BrokerCells(system.Namespace()).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	if bc := resources.SelectBrokerCell(bcs, b); bc != nil {
+		return bc, nil
+	}
+	return r.brokerCellLister.BrokerCells(system.Namespace()).Get(resources.DefaultBroekrCellName)
 }
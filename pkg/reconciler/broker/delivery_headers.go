@@ -0,0 +1,215 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing/pkg/logging"
+	"knative.dev/pkg/system"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+)
+
+// reconcileDeliveryHeaders resolves the delivery headers of the broker's
+// triggers and records them to be written to the delivery headers secret. It
+// returns the key of the entry holding the headers of each trigger that has
+// some, keyed by trigger UID.
+//
+// Triggers whose headers cannot be resolved keep their key, so that their
+// events are delivered with the headers last written to the secret, if any,
+// rather than without them.
+func (r *Reconciler) reconcileDeliveryHeaders(ctx context.Context, b *brokerv1beta1.Broker, triggers []*brokerv1beta1.Trigger) map[types.UID]string {
+	keys := make(map[types.UID]string)
+	entries := make(map[string][]byte)
+	for _, t := range triggers {
+		if t.Spec.Broker != b.Name {
+			continue
+		}
+		if _, ok := t.Annotations[brokerv1beta1.DeliveryHeadersAnnotation]; !ok {
+			continue
+		}
+		key := string(t.UID)
+		keys[t.UID] = key
+		values, err := r.resolveDeliveryHeaders(t)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to resolve delivery headers", zap.String("trigger", t.Name), zap.Error(err))
+			continue
+		}
+		data, err := json.Marshal(values)
+		if err != nil {
+			logging.FromContext(ctx).Error("Failed to serialize delivery headers", zap.String("trigger", t.Name), zap.Error(err))
+			continue
+		}
+		entries[key] = data
+	}
+
+	r.deliveryHeadersMu.Lock()
+	defer r.deliveryHeadersMu.Unlock()
+	if r.deliveryHeaders == nil {
+		r.deliveryHeaders = make(map[string]map[string][]byte)
+	}
+	r.deliveryHeaders[config.BrokerKey(b.Namespace, b.Name)] = entries
+	return keys
+}
+
+// forgetDeliveryHeaders drops the delivery headers recorded for the broker's
+// triggers.
+func (r *Reconciler) forgetDeliveryHeaders(b *brokerv1beta1.Broker) {
+	r.deliveryHeadersMu.Lock()
+	defer r.deliveryHeadersMu.Unlock()
+	delete(r.deliveryHeaders, config.BrokerKey(b.Namespace, b.Name))
+}
+
+// resolveDeliveryHeaders returns the values of the delivery headers of the
+// trigger, keyed by header name, reading them from the trigger's secrets.
+func (r *Reconciler) resolveDeliveryHeaders(t *brokerv1beta1.Trigger) (map[string]string, error) {
+	headers, err := resources.DeliveryHeaders(t)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(headers))
+	for _, h := range headers {
+		ref := h.SecretKeyRef()
+		if ref == nil {
+			values[h.Name] = h.Value
+			continue
+		}
+		s, err := r.secretLister.Secrets(t.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q of header %q: %w", ref.Name, h.Name, err)
+		}
+		v, ok := s.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("secret %q of header %q has no key %q", ref.Name, h.Name, ref.Key)
+		}
+		values[h.Name] = string(v)
+	}
+	return values, nil
+}
+
+// deliveryHeadersSecret returns true if some delivery header of the trigger
+// comes from the named secret in its namespace.
+func deliveryHeadersSecret(t *brokerv1beta1.Trigger, name string) bool {
+	headers, err := resources.DeliveryHeaders(t)
+	if err != nil {
+		return false
+	}
+	for _, h := range headers {
+		if ref := h.SecretKeyRef(); ref != nil && ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// updateDeliveryHeadersSecret writes the delivery headers of the targets, and
+// the reply headers of the brokers, in the targets config to the delivery
+// headers secret mounted in the fanout and retry pods. Entries of targets and
+// brokers that are gone are removed, and entries that were not resolved since
+// the controller started are kept as they are.
+func (r *Reconciler) updateDeliveryHeadersSecret(ctx context.Context) error {
+	existing, err := r.secretLister.Secrets(system.Namespace()).Get(brokercellresources.DeliveryHeadersSecretName)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("error getting delivery headers secret: %w", err)
+	}
+
+	data := make(map[string][]byte)
+	r.deliveryHeadersMu.Lock()
+	r.targetsConfig.RangeAllTargets(func(t *config.Target) bool {
+		key := t.DeliveryHeadersKey
+		if key == "" {
+			return true
+		}
+		if v, ok := r.deliveryHeaders[config.BrokerKey(t.Namespace, t.Broker)][key]; ok {
+			data[key] = v
+		} else if existing != nil {
+			if v, ok := existing.Data[key]; ok {
+				data[key] = v
+			}
+		}
+		return true
+	})
+	r.deliveryHeadersMu.Unlock()
+
+	r.ingressAuthMu.Lock()
+	r.targetsConfig.RangeBrokers(func(b *config.Broker) bool {
+		key := b.ReplyHeadersKey
+		if key == "" {
+			return true
+		}
+		if token, ok := r.replyTokens[config.BrokerKey(b.Namespace, b.Name)]; ok {
+			if v, err := replyHeaders(token); err == nil {
+				data[key] = v
+				return true
+			}
+		}
+		if existing != nil {
+			if v, ok := existing.Data[key]; ok {
+				data[key] = v
+			}
+		}
+		return true
+	})
+	r.ingressAuthMu.Unlock()
+
+	return r.writeSystemSecret(brokercellresources.DeliveryHeadersSecretName, existing, data)
+}
+
+// writeSystemSecret creates or updates the named secret in the system
+// namespace so that it holds data. existing is the current secret, nil if
+// there is none, in which case it is only created if there is some data.
+func (r *Reconciler) writeSystemSecret(name string, existing *corev1.Secret, data map[string][]byte) error {
+	if existing == nil {
+		if len(data) == 0 {
+			return nil
+		}
+		desired := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: system.Namespace(),
+			},
+			Data: data,
+		}
+		r.Logger.Debug("Creating secret", zap.String("namespace", desired.Namespace), zap.String("name", desired.Name))
+		if _, err := r.KubeClientSet.CoreV1().Secrets(desired.Namespace).Create(desired); err != nil {
+			return fmt.Errorf("error creating secret %q: %w", name, err)
+		}
+		return nil
+	}
+	if equality.Semantic.DeepEqual(data, existing.Data) {
+		return nil
+	}
+	desired := existing.DeepCopy()
+	desired.Data = data
+	r.Logger.Debug("Updating secret", zap.String("namespace", desired.Namespace), zap.String("name", desired.Name))
+	if _, err := r.KubeClientSet.CoreV1().Secrets(desired.Namespace).Update(desired); err != nil {
+		return fmt.Errorf("error updating secret %q: %w", name, err)
+	}
+	return nil
+}
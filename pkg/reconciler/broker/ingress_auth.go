@@ -0,0 +1,207 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/eventing/pkg/logging"
+	"knative.dev/pkg/system"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/auth"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	brokercellresources "github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+)
+
+// replyHeadersKeyPrefix prefixes the keys of the entries of the delivery
+// headers secret holding the headers of the replies sent back to brokers, so
+// that they never collide with the entries of targets, keyed by trigger UID.
+const replyHeadersKeyPrefix = "reply-"
+
+// reconcileIngressAuth resolves the bearer tokens the ingress accepts for the
+// broker and records their hashes to be written to the ingress auth secret.
+// The broker also gets a reply token, recorded to be written to the delivery
+// headers secret, so that the replies the fanout and retry pods send back to
+// the broker are accepted too. It returns the key of the entry holding the
+// hashes and the key of the entry holding the reply headers, both empty if
+// the broker doesn't require a token.
+//
+// Brokers whose tokens cannot be resolved keep their keys, so that the tokens
+// last written to the secrets, if any, keep being required rather than
+// letting requests in without a token.
+func (r *Reconciler) reconcileIngressAuth(ctx context.Context, b *brokerv1beta1.Broker) (string, string) {
+	if resources.IngressAuthSecret(b) == "" {
+		r.forgetIngressAuth(b)
+		return "", ""
+	}
+	key := string(b.UID)
+	replyKey := replyHeadersKeyPrefix + key
+	replyToken, err := r.replyToken(b, replyKey)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to resolve the reply token", zap.Error(err))
+		return key, replyKey
+	}
+	hashes, err := r.resolveIngressAuth(b)
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to resolve ingress auth tokens", zap.Error(err))
+		return key, replyKey
+	}
+	hashes = append(hashes, '\n')
+	hashes = append(hashes, auth.Hash(replyToken)...)
+
+	r.ingressAuthMu.Lock()
+	defer r.ingressAuthMu.Unlock()
+	if r.ingressAuth == nil {
+		r.ingressAuth = make(map[string][]byte)
+	}
+	r.ingressAuth[config.BrokerKey(b.Namespace, b.Name)] = hashes
+	return key, replyKey
+}
+
+// forgetIngressAuth drops the token hashes and the reply token recorded for
+// the broker.
+func (r *Reconciler) forgetIngressAuth(b *brokerv1beta1.Broker) {
+	r.ingressAuthMu.Lock()
+	defer r.ingressAuthMu.Unlock()
+	delete(r.ingressAuth, config.BrokerKey(b.Namespace, b.Name))
+	delete(r.replyTokens, config.BrokerKey(b.Namespace, b.Name))
+}
+
+// replyToken returns the token the replies sent back to the broker carry. The
+// token last written to the delivery headers secret under replyKey is reused,
+// so that replies keep being accepted across controller restarts, and a new
+// one is generated if there is none.
+func (r *Reconciler) replyToken(b *brokerv1beta1.Broker, replyKey string) (string, error) {
+	brokerKey := config.BrokerKey(b.Namespace, b.Name)
+	r.ingressAuthMu.Lock()
+	token, ok := r.replyTokens[brokerKey]
+	r.ingressAuthMu.Unlock()
+	if ok {
+		return token, nil
+	}
+
+	existing, err := r.secretLister.Secrets(system.Namespace()).Get(brokercellresources.DeliveryHeadersSecretName)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return "", fmt.Errorf("error getting delivery headers secret: %w", err)
+	}
+	if existing != nil {
+		token = parseReplyToken(existing.Data[replyKey])
+	}
+	if token == "" {
+		if token, err = newReplyToken(); err != nil {
+			return "", err
+		}
+	}
+
+	r.ingressAuthMu.Lock()
+	defer r.ingressAuthMu.Unlock()
+	if r.replyTokens == nil {
+		r.replyTokens = make(map[string]string)
+	}
+	r.replyTokens[brokerKey] = token
+	return token, nil
+}
+
+// newReplyToken generates a random reply token.
+func newReplyToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate a reply token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// replyHeaders returns the delivery headers entry setting the reply token on
+// the replies.
+func replyHeaders(token string) ([]byte, error) {
+	return json.Marshal(map[string]string{"Authorization": "Bearer " + token})
+}
+
+// parseReplyToken returns the reply token of a delivery headers entry, empty
+// if it has none.
+func parseReplyToken(data []byte) string {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(values["Authorization"], "Bearer ")
+}
+
+// resolveIngressAuth returns the hashes of the tokens of the broker, one per
+// line, reading them from the broker's secret.
+func (r *Reconciler) resolveIngressAuth(b *brokerv1beta1.Broker) ([]byte, error) {
+	name := resources.IngressAuthSecret(b)
+	s, err := r.secretLister.Secrets(b.Namespace).Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress auth secret %q: %w", name, err)
+	}
+	tokens := auth.ParseTokens(s.Data[resources.IngressAuthTokensKey])
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("ingress auth secret %q has no tokens in key %q", name, resources.IngressAuthTokensKey)
+	}
+	hashes := make([]string, len(tokens))
+	for i, t := range tokens {
+		hashes[i] = auth.Hash(t)
+	}
+	return []byte(strings.Join(hashes, "\n")), nil
+}
+
+// ingressAuthSecret returns true if the tokens of the broker come from the
+// named secret in its namespace.
+func ingressAuthSecret(b *brokerv1beta1.Broker, name string) bool {
+	return resources.IngressAuthSecret(b) == name
+}
+
+// updateIngressAuthSecret writes the token hashes of the brokers in the
+// targets config to the ingress auth secret mounted in the ingress pods.
+// Entries of brokers that are gone are removed, and entries that were not
+// resolved since the controller started are kept as they are.
+func (r *Reconciler) updateIngressAuthSecret(ctx context.Context) error {
+	existing, err := r.secretLister.Secrets(system.Namespace()).Get(brokercellresources.IngressAuthSecretName)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("error getting ingress auth secret: %w", err)
+	}
+
+	data := make(map[string][]byte)
+	r.ingressAuthMu.Lock()
+	r.targetsConfig.RangeBrokers(func(b *config.Broker) bool {
+		key := b.IngressAuthKey
+		if key == "" {
+			return true
+		}
+		if v, ok := r.ingressAuth[config.BrokerKey(b.Namespace, b.Name)]; ok {
+			data[key] = v
+		} else if existing != nil {
+			if v, ok := existing.Data[key]; ok {
+				data[key] = v
+			}
+		}
+		return true
+	})
+	r.ingressAuthMu.Unlock()
+
+	return r.writeSystemSecret(brokercellresources.IngressAuthSecretName, existing, data)
+}
@@ -17,19 +17,22 @@ limitations under the License.
 package resources
 
 import (
+	"sort"
+
 	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"knative.dev/pkg/system"
 
 	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 )
 
-// Right now we only support one brokercell in the system namespace in the cluster.
-// TODO(#866) Delete hard-coded brokercell once we can dynamically assign brokercell to brokers.
+// DefaultBroekrCellName is the name of the brokercell in the system namespace
+// serving the brokers not selected by any other brokercell.
 const DefaultBroekrCellName = "default"
 
+// CreateBrokerCell returns the default brokercell, which is created on demand.
 func CreateBrokerCell(b *v1beta1.Broker) *inteventsv1alpha1.BrokerCell {
-	// TODO(#866) Get brokercell from the label (or annotation) from the broker.
 	return &inteventsv1alpha1.BrokerCell{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:   system.Namespace(),
@@ -38,3 +41,39 @@ func CreateBrokerCell(b *v1beta1.Broker) *inteventsv1alpha1.BrokerCell {
 		},
 	}
 }
+
+// SelectBrokerCell returns the brokercell whose broker selector selects the
+// broker, or nil if there is none. If several brokercells select the broker,
+// the first one by name is returned so that the choice is stable.
+func SelectBrokerCell(bcs []*inteventsv1alpha1.BrokerCell, b *v1beta1.Broker) *inteventsv1alpha1.BrokerCell {
+	var selected []*inteventsv1alpha1.BrokerCell
+	for _, bc := range bcs {
+		if bc.Spec.BrokerSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(bc.Spec.BrokerSelector)
+		if err != nil {
+			// Invalid selectors are rejected by the webhook.
+			continue
+		}
+		if selector.Matches(labels.Set(b.Labels)) {
+			selected = append(selected, bc)
+		}
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].Name < selected[j].Name
+	})
+	return selected[0]
+}
+
+// BrokerCellName returns the name of the brokercell serving the broker: the one
+// selected by SelectBrokerCell, or else the default brokercell.
+func BrokerCellName(bcs []*inteventsv1alpha1.BrokerCell, b *v1beta1.Broker) string {
+	if bc := SelectBrokerCell(bcs, b); bc != nil {
+		return bc.Name
+	}
+	return DefaultBroekrCellName
+}
@@ -16,9 +16,75 @@ limitations under the License.
 
 package resources
 
-import "testing"
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	inteventsv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
 
 // This is already tested in broker_test.go, this test is just to make coverage tool happy.
 func TestBrokerCellCreation(t *testing.T) {
 	CreateBrokerCell(nil)
 }
+
+func TestSelectBrokerCell(t *testing.T) {
+	cell := func(name string, selector *metav1.LabelSelector) *inteventsv1alpha1.BrokerCell {
+		return &inteventsv1alpha1.BrokerCell{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       inteventsv1alpha1.BrokerCellSpec{BrokerSelector: selector},
+		}
+	}
+	payments := &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}}
+	bcs := []*inteventsv1alpha1.BrokerCell{
+		cell(DefaultBroekrCellName, nil),
+		cell("payments-b", payments),
+		cell("payments-a", payments),
+		cell("invalid", &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}}}),
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   string
+	}{{
+		name:   "selected by several brokercells",
+		labels: map[string]string{"team": "payments"},
+		want:   "payments-a",
+	}, {
+		name:   "not selected",
+		labels: map[string]string{"team": "search"},
+	}, {
+		name: "no labels",
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels}}
+			var got string
+			if bc := SelectBrokerCell(bcs, b); bc != nil {
+				got = bc.Name
+			}
+			if got != tc.want {
+				t.Errorf("SelectBrokerCell() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBrokerCellName(t *testing.T) {
+	bcs := []*inteventsv1alpha1.BrokerCell{{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments"},
+		Spec: inteventsv1alpha1.BrokerCellSpec{
+			BrokerSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+		},
+	}}
+	selected := &v1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "payments"}}}
+	if got := BrokerCellName(bcs, selected); got != "payments" {
+		t.Errorf("BrokerCellName() = %q, want %q", got, "payments")
+	}
+	if got := BrokerCellName(bcs, &v1beta1.Broker{}); got != DefaultBroekrCellName {
+		t.Errorf("BrokerCellName() = %q, want %q", got, DefaultBroekrCellName)
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strconv"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// maxDeduplicationWindow caps the deduplication window of a Trigger, to bound
+// the memory the fanout spends on it.
+const maxDeduplicationWindow = 100000
+
+// DeduplicationWindow returns the number of recently delivered event IDs the
+// fanout should remember for the Trigger. Missing, malformed or non-positive
+// values disable deduplication.
+func DeduplicationWindow(t *brokerv1beta1.Trigger) int32 {
+	window, err := strconv.ParseInt(t.Annotations[brokerv1beta1.DeduplicationWindowAnnotation], 10, 32)
+	if err != nil || window <= 0 {
+		return 0
+	}
+	if window > maxDeduplicationWindow {
+		return maxDeduplicationWindow
+	}
+	return int32(window)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDeduplicationWindow(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        int32
+	}{
+		"no annotations": {},
+		"enabled": {
+			annotations: map[string]string{brokerv1beta1.DeduplicationWindowAnnotation: "1000"},
+			want:        1000,
+		},
+		"zero": {
+			annotations: map[string]string{brokerv1beta1.DeduplicationWindowAnnotation: "0"},
+		},
+		"negative": {
+			annotations: map[string]string{brokerv1beta1.DeduplicationWindowAnnotation: "-5"},
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.DeduplicationWindowAnnotation: "lots"},
+		},
+		"capped": {
+			annotations: map[string]string{brokerv1beta1.DeduplicationWindowAnnotation: "10000000"},
+			want:        maxDeduplicationWindow,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := DeduplicationWindow(trig); got != tc.want {
+				t.Errorf("DeduplicationWindow got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/network"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// DirectDeliveryEnabled returns true if the Trigger has opted into direct
+// delivery.
+func DirectDeliveryEnabled(t *brokerv1beta1.Trigger) bool {
+	return annotationEnabled(t.Annotations, brokerv1beta1.DirectDeliveryAnnotation)
+}
+
+// DirectAddress returns the address of the private service of the latest
+// ready revision of the Knative Service. It returns the empty string if the
+// Service is not ready or routes traffic to other revisions, in which case
+// events must go through the Service's URL.
+func DirectAddress(s *servingv1.Service) string {
+	revision := s.Status.LatestReadyRevisionName
+	if !s.IsReady() || revision == "" {
+		return ""
+	}
+	for _, tt := range s.Status.Traffic {
+		if tt.Percent != nil && *tt.Percent > 0 && tt.RevisionName != revision {
+			return ""
+		}
+	}
+	// Knative Serving exposes the pods of each revision, without the
+	// activator in the path, through a service named after the revision
+	// with a "-private" suffix.
+	return fmt.Sprintf("http://%s", network.GetServiceHostname(kmeta.ChildName(revision, "-private"), s.Namespace))
+}
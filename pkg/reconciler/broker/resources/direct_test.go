@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/ptr"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestDirectDeliveryEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"no annotations": {},
+		"enabled": {
+			annotations: map[string]string{brokerv1beta1.DirectDeliveryAnnotation: "true"},
+			want:        true,
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.DirectDeliveryAnnotation: "sure"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := DirectDeliveryEnabled(trig); got != tc.want {
+				t.Errorf("DirectDeliveryEnabled got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDirectAddress(t *testing.T) {
+	testCases := map[string]struct {
+		generation int64
+		ready      corev1.ConditionStatus
+		revision   string
+		traffic    []servingv1.TrafficTarget
+		want       string
+	}{
+		"ready": {
+			ready:    corev1.ConditionTrue,
+			revision: "svc-00001",
+			want:     "http://svc-00001-private.ns.svc.cluster.local",
+		},
+		"all traffic to the latest ready revision": {
+			ready:    corev1.ConditionTrue,
+			revision: "svc-00002",
+			traffic: []servingv1.TrafficTarget{
+				{RevisionName: "svc-00002", Percent: ptr.Int64(100)},
+				{RevisionName: "svc-00001", Percent: ptr.Int64(0), Tag: "old"},
+			},
+			want: "http://svc-00002-private.ns.svc.cluster.local",
+		},
+		"traffic split between revisions": {
+			ready:    corev1.ConditionTrue,
+			revision: "svc-00002",
+			traffic: []servingv1.TrafficTarget{
+				{RevisionName: "svc-00002", Percent: ptr.Int64(90)},
+				{RevisionName: "svc-00001", Percent: ptr.Int64(10)},
+			},
+		},
+		"not ready": {
+			ready:    corev1.ConditionFalse,
+			revision: "svc-00001",
+		},
+		"spec not observed": {
+			generation: 2,
+			ready:      corev1.ConditionTrue,
+			revision:   "svc-00001",
+		},
+		"no ready revision": {
+			ready: corev1.ConditionTrue,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s := &servingv1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "ns", Generation: tc.generation},
+				Status: servingv1.ServiceStatus{
+					Status: duckv1.Status{
+						Conditions: duckv1.Conditions{{Type: apis.ConditionReady, Status: tc.ready}},
+					},
+					ConfigurationStatusFields: servingv1.ConfigurationStatusFields{
+						LatestReadyRevisionName: tc.revision,
+					},
+					RouteStatusFields: servingv1.RouteStatusFields{
+						Traffic: tc.traffic,
+					},
+				},
+			}
+			if got := DirectAddress(s); got != tc.want {
+				t.Errorf("DirectAddress got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// StructuredEncoding returns true if the ingress should publish the events of
+// the Broker in the CloudEvents structured mode. Any value other than
+// structured falls back to the binary mode.
+func StructuredEncoding(b *brokerv1beta1.Broker) bool {
+	encoding := strings.TrimSpace(b.Annotations[brokerv1beta1.EventEncodingAnnotation])
+	return strings.EqualFold(encoding, brokerv1beta1.EventEncodingStructured)
+}
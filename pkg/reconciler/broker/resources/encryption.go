@@ -0,0 +1,29 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// EncryptionKey returns the name of the Cloud KMS key the ingress should wrap
+// the data keys of the Broker with. An empty name disables encryption.
+func EncryptionKey(b *brokerv1beta1.Broker) string {
+	return strings.TrimSpace(b.Annotations[brokerv1beta1.EncryptionKeyAnnotation])
+}
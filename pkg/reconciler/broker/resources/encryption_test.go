@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEncryptionKey(t *testing.T) {
+	const key = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+	}{
+		"no annotations": {},
+		"key": {
+			annotations: map[string]string{brokerv1beta1.EncryptionKeyAnnotation: key},
+			want:        key,
+		},
+		"surrounding spaces": {
+			annotations: map[string]string{brokerv1beta1.EncryptionKeyAnnotation: " " + key + "\n"},
+			want:        key,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := EncryptionKey(b); got != tc.want {
+				t.Errorf("EncryptionKey got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
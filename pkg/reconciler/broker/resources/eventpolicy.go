@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// denyAll is the policy of Brokers with an invalid policy annotation.
+var denyAll = brokerv1beta1.EventPolicy{DeniedTypes: []string{"*"}}
+
+// BrokerEventPolicy returns the event policy of the Broker. Unlike the other
+// Broker annotations, an invalid policy rejects all events, so that a typo
+// never lets in the events the Broker should keep out.
+func BrokerEventPolicy(b *brokerv1beta1.Broker) (brokerv1beta1.EventPolicy, error) {
+	v, ok := b.Annotations[brokerv1beta1.EventPolicyAnnotation]
+	if !ok {
+		return brokerv1beta1.EventPolicy{}, nil
+	}
+	var p brokerv1beta1.EventPolicy
+	if err := json.Unmarshal([]byte(v), &p); err != nil {
+		return denyAll, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.EventPolicyAnnotation, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestBrokerEventPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        brokerv1beta1.EventPolicy
+		wantErr     bool
+	}{{
+		name: "no annotation",
+	}, {
+		name: "allow and deny lists",
+		annotations: map[string]string{
+			brokerv1beta1.EventPolicyAnnotation: `{"allowedTypes":["com.example.*"],"deniedTypes":["com.example.internal"],"deniedSources":["//test/*"]}`,
+		},
+		want: brokerv1beta1.EventPolicy{
+			AllowedTypes:  []string{"com.example.*"},
+			DeniedTypes:   []string{"com.example.internal"},
+			DeniedSources: []string{"//test/*"},
+		},
+	}, {
+		name: "malformed annotation denies all events",
+		annotations: map[string]string{
+			brokerv1beta1.EventPolicyAnnotation: `{"allowedTypes":"com.example.order"}`,
+		},
+		want:    brokerv1beta1.EventPolicy{DeniedTypes: []string{"*"}},
+		wantErr: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := BrokerEventPolicy(b)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("BrokerEventPolicy() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("BrokerEventPolicy() (-want,+got): %v", diff)
+			}
+		})
+	}
+}
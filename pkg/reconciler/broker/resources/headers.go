@@ -0,0 +1,106 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+	corev1 "k8s.io/api/core/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// DeliveryHeader is a header set on the requests delivering events to the
+// subscribers of a Trigger.
+type DeliveryHeader struct {
+	// Name is the name of the header.
+	Name string `json:"name"`
+	// Value is the value of the header, if it doesn't come from a Secret.
+	Value string `json:"value,omitempty"`
+	// ValueFrom is where the value of the header comes from.
+	ValueFrom *DeliveryHeaderSource `json:"valueFrom,omitempty"`
+}
+
+// DeliveryHeaderSource is where the value of a DeliveryHeader comes from.
+type DeliveryHeaderSource struct {
+	// SecretKeyRef selects a key of a Secret in the namespace of the Trigger.
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeyRef returns the Secret key the value of the header comes from, if
+// any.
+func (h DeliveryHeader) SecretKeyRef() *corev1.SecretKeySelector {
+	if h.ValueFrom == nil {
+		return nil
+	}
+	return h.ValueFrom.SecretKeyRef
+}
+
+// DeliveryHeaders returns the headers the fanout should set on the requests
+// delivering events to the subscribers of the Trigger. Unlike the other
+// delivery annotations, an invalid header fails the whole annotation, so that
+// events are never delivered without the headers the subscriber expects.
+func DeliveryHeaders(t *brokerv1beta1.Trigger) ([]DeliveryHeader, error) {
+	v, ok := t.Annotations[brokerv1beta1.DeliveryHeadersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var headers []DeliveryHeader
+	if err := json.Unmarshal([]byte(v), &headers); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.DeliveryHeadersAnnotation, err)
+	}
+	seen := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		if err := validateDeliveryHeader(h); err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.DeliveryHeadersAnnotation, err)
+		}
+		name := http.CanonicalHeaderKey(h.Name)
+		if seen[name] {
+			return nil, fmt.Errorf("invalid %s annotation: duplicate header %q", brokerv1beta1.DeliveryHeadersAnnotation, h.Name)
+		}
+		seen[name] = true
+	}
+	return headers, nil
+}
+
+// validateDeliveryHeader checks that the header has a valid name that doesn't
+// clash with the headers of the CloudEvents HTTP binding, and a single source
+// for its value.
+func validateDeliveryHeader(h DeliveryHeader) error {
+	if !httpguts.ValidHeaderFieldName(h.Name) {
+		return fmt.Errorf("invalid header name %q", h.Name)
+	}
+	if name := strings.ToLower(h.Name); name == "content-type" || strings.HasPrefix(name, "ce-") {
+		return fmt.Errorf("header %q is reserved for CloudEvents", h.Name)
+	}
+	ref := h.SecretKeyRef()
+	switch {
+	case h.ValueFrom != nil && ref == nil:
+		return fmt.Errorf("header %q has no secretKeyRef", h.Name)
+	case ref != nil && h.Value != "":
+		return fmt.Errorf("header %q has both a value and a secretKeyRef", h.Name)
+	case ref != nil && (ref.Name == "" || ref.Key == ""):
+		return fmt.Errorf("the secretKeyRef of header %q needs a name and a key", h.Name)
+	case ref == nil && !httpguts.ValidHeaderFieldValue(h.Value):
+		return fmt.Errorf("invalid value for header %q", h.Name)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestDeliveryHeaders(t *testing.T) {
+	testCases := map[string]struct {
+		annotation string
+		want       []DeliveryHeader
+		wantErr    bool
+	}{
+		"no annotation": {},
+		"headers": {
+			annotation: `[{"name":"X-Api-Key","valueFrom":{"secretKeyRef":{"name":"sink","key":"api-key"}}},{"name":"X-Tenant","value":"acme"}]`,
+			want: []DeliveryHeader{
+				{
+					Name: "X-Api-Key",
+					ValueFrom: &DeliveryHeaderSource{SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "sink"},
+						Key:                  "api-key",
+					}},
+				},
+				{Name: "X-Tenant", Value: "acme"},
+			},
+		},
+		"malformed": {
+			annotation: `X-Api-Key: secret`,
+			wantErr:    true,
+		},
+		"invalid name": {
+			annotation: `[{"name":"X Api Key","value":"secret"}]`,
+			wantErr:    true,
+		},
+		"invalid value": {
+			annotation: `[{"name":"X-Api-Key","value":"secret\n"}]`,
+			wantErr:    true,
+		},
+		"cloudevents header": {
+			annotation: `[{"name":"Ce-Source","value":"somewhere"}]`,
+			wantErr:    true,
+		},
+		"content type": {
+			annotation: `[{"name":"content-type","value":"text/plain"}]`,
+			wantErr:    true,
+		},
+		"duplicate header": {
+			annotation: `[{"name":"X-Tenant","value":"acme"},{"name":"x-tenant","value":"other"}]`,
+			wantErr:    true,
+		},
+		"value and secret": {
+			annotation: `[{"name":"X-Api-Key","value":"secret","valueFrom":{"secretKeyRef":{"name":"sink","key":"api-key"}}}]`,
+			wantErr:    true,
+		},
+		"empty source": {
+			annotation: `[{"name":"X-Api-Key","valueFrom":{}}]`,
+			wantErr:    true,
+		},
+		"secret without key": {
+			annotation: `[{"name":"X-Api-Key","valueFrom":{"secretKeyRef":{"name":"sink"}}}]`,
+			wantErr:    true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace"}}
+			if tc.annotation != "" {
+				trig.Annotations = map[string]string{brokerv1beta1.DeliveryHeadersAnnotation: tc.annotation}
+			}
+			got, err := DeliveryHeaders(trig)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DeliveryHeaders error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DeliveryHeaders (-want,+got): %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// IngressAuthTokensKey is the key of the Secret named by the ingress auth
+// secret annotation listing the accepted tokens, one per line.
+const IngressAuthTokensKey = "tokens"
+
+// IngressAuthSecret returns the name of the Secret in the Broker's namespace
+// holding the bearer tokens the ingress accepts for the Broker. An empty name
+// lets requests in without a token.
+func IngressAuthSecret(b *brokerv1beta1.Broker) string {
+	return strings.TrimSpace(b.Annotations[brokerv1beta1.IngressAuthSecretAnnotation])
+}
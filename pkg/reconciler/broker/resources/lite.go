@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+)
+
+// LiteLocation returns the zone of the Pub/Sub Lite queues of the Broker, or
+// an empty string if its queues are on Cloud Pub/Sub.
+func LiteLocation(b *brokerv1beta1.Broker) (string, error) {
+	location, ok := b.Annotations[brokerv1beta1.LiteLocationAnnotation]
+	if !ok {
+		return "", nil
+	}
+	if err := gpubsublite.ValidateZone(location); err != nil {
+		return "", fmt.Errorf("invalid annotation %q: %w", brokerv1beta1.LiteLocationAnnotation, err)
+	}
+	return location, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLiteLocation(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        string
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"zone": {
+			annotations: map[string]string{brokerv1beta1.LiteLocationAnnotation: "us-central1-a"},
+			want:        "us-central1-a",
+		},
+		"region": {
+			annotations: map[string]string{brokerv1beta1.LiteLocationAnnotation: "us-central1"},
+			wantErr:     true,
+		},
+		"empty": {
+			annotations: map[string]string{brokerv1beta1.LiteLocationAnnotation: ""},
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := LiteLocation(b)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("LiteLocation error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("LiteLocation got=%q, want=%q", got, tc.want)
+			}
+		})
+	}
+}
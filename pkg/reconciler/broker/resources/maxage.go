@@ -0,0 +1,43 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"time"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// MaxAgeSeconds returns the maximum age, in seconds, of the events the data
+// plane should deliver to the Trigger. Durations are rounded up to the second.
+// It's zero if the annotation is missing, and an error is returned if the
+// value is malformed or not positive.
+func MaxAgeSeconds(t *brokerv1beta1.Trigger) (int64, error) {
+	v, ok := t.Annotations[brokerv1beta1.MaxAgeAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	maxAge, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.MaxAgeAnnotation, err)
+	}
+	if maxAge <= 0 {
+		return 0, fmt.Errorf("invalid %s annotation: %q is not positive", brokerv1beta1.MaxAgeAnnotation, v)
+	}
+	return int64((maxAge + time.Second - 1) / time.Second), nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMaxAgeSeconds(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        int64
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"minutes": {
+			annotations: map[string]string{brokerv1beta1.MaxAgeAnnotation: "15m"},
+			want:        900,
+		},
+		"rounded up": {
+			annotations: map[string]string{brokerv1beta1.MaxAgeAnnotation: "1500ms"},
+			want:        2,
+		},
+		"zero": {
+			annotations: map[string]string{brokerv1beta1.MaxAgeAnnotation: "0s"},
+			wantErr:     true,
+		},
+		"negative": {
+			annotations: map[string]string{brokerv1beta1.MaxAgeAnnotation: "-1h"},
+			wantErr:     true,
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.MaxAgeAnnotation: "forever"},
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := MaxAgeSeconds(trig)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("MaxAgeSeconds got error=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("MaxAgeSeconds got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
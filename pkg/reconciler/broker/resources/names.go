@@ -18,6 +18,7 @@ package resources
 
 import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	"github.com/google/knative-gcp/pkg/utils/naming"
 )
 
@@ -47,6 +48,36 @@ func GenerateDecouplingSubscriptionName(b *brokerv1beta1.Broker) string {
 	return naming.TruncatedPubsubResourceName("cre-bkr", b.Namespace, b.Name, b.UID)
 }
 
+// DecouplingSubscriptionID returns the ID of the Pub/Sub decoupling
+// subscription of a Broker, the name generated by
+// GenerateDecouplingSubscriptionName between the prefix and the suffix of n.
+func DecouplingSubscriptionID(n psresources.SubscriptionNaming, b *brokerv1beta1.Broker) (string, error) {
+	return n.Name(b, "cre-bkr", b.Namespace, b.Name, b.UID)
+}
+
+// GeneratePriorityDecouplingTopicName generates a deterministic name for the
+// topic of a Broker's high priority events. If the topic name would be longer
+// than allowed by PubSub, the Broker name is truncated to fit.
+func GeneratePriorityDecouplingTopicName(b *brokerv1beta1.Broker) string {
+	return naming.TruncatedPubsubResourceName("cre-bkrp", b.Namespace, b.Name, b.UID)
+}
+
+// GeneratePriorityDecouplingSubscriptionName generates a deterministic name
+// for the subscription of a Broker's high priority events. If the
+// subscription name would be longer than allowed by PubSub, the Broker name
+// is truncated to fit.
+func GeneratePriorityDecouplingSubscriptionName(b *brokerv1beta1.Broker) string {
+	return naming.TruncatedPubsubResourceName("cre-bkrp", b.Namespace, b.Name, b.UID)
+}
+
+// PriorityDecouplingSubscriptionID returns the ID of the Pub/Sub subscription
+// of a Broker's high priority events, the name generated by
+// GeneratePriorityDecouplingSubscriptionName between the prefix and the suffix
+// of n.
+func PriorityDecouplingSubscriptionID(n psresources.SubscriptionNaming, b *brokerv1beta1.Broker) (string, error) {
+	return n.Name(b, "cre-bkrp", b.Namespace, b.Name, b.UID)
+}
+
 // GenerateRetryTopicName generates a deterministic topic name for a Trigger.
 // If the topic name would be longer than allowed by PubSub, the Trigger name is
 // truncated to fit.
@@ -60,3 +91,10 @@ func GenerateRetryTopicName(t *brokerv1beta1.Trigger) string {
 func GenerateRetrySubscriptionName(t *brokerv1beta1.Trigger) string {
 	return naming.TruncatedPubsubResourceName("cre-tgr", t.Namespace, t.Name, t.UID)
 }
+
+// RetrySubscriptionID returns the ID of the Pub/Sub retry subscription of a
+// Trigger, the name generated by GenerateRetrySubscriptionName between the
+// prefix and the suffix of n.
+func RetrySubscriptionID(n psresources.SubscriptionNaming, t *brokerv1beta1.Trigger) (string, error) {
+	return n.Name(t, "cre-tgr", t.Namespace, t.Name, t.UID)
+}
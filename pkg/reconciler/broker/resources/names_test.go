@@ -115,6 +115,20 @@ func TestGenerateDecouplingSubscriptionName(t *testing.T) {
 	}
 }
 
+func TestGeneratePriorityDecouplingNames(t *testing.T) {
+	b := broker("default", "default", testUID)
+	want := fmt.Sprintf("cre-bkrp_default_default_%s", testUID)
+	if got := GeneratePriorityDecouplingTopicName(b); got != want {
+		t.Errorf("GeneratePriorityDecouplingTopicName got=%v, want=%v", got, want)
+	}
+	if got := GeneratePriorityDecouplingSubscriptionName(b); got != want {
+		t.Errorf("GeneratePriorityDecouplingSubscriptionName got=%v, want=%v", got, want)
+	}
+	if got := GeneratePriorityDecouplingTopicName(b); got == GenerateDecouplingTopicName(b) {
+		t.Errorf("priority topic %v is the decoupling topic", got)
+	}
+}
+
 func TestGenerateRetryTopicName(t *testing.T) {
 	testCases := []struct {
 		ns   string
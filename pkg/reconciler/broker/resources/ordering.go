@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strconv"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// MessageOrderingEnabled returns true if the Broker has opted into message
+// ordering on its decouple queue.
+func MessageOrderingEnabled(b *brokerv1beta1.Broker) bool {
+	return annotationEnabled(b.Annotations, brokerv1beta1.MessageOrderingAnnotation)
+}
+
+// OrderedDeliveryEnabled returns true if the Trigger has opted into ordered
+// delivery.
+func OrderedDeliveryEnabled(t *brokerv1beta1.Trigger) bool {
+	return annotationEnabled(t.Annotations, brokerv1beta1.OrderedDeliveryAnnotation)
+}
+
+// annotationEnabled parses a boolean annotation. Missing or malformed values
+// are treated as false.
+func annotationEnabled(annotations map[string]string, key string) bool {
+	enabled, err := strconv.ParseBool(annotations[key])
+	return err == nil && enabled
+}
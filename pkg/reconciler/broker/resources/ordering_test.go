@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMessageOrderingEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"no annotations": {},
+		"enabled": {
+			annotations: map[string]string{brokerv1beta1.MessageOrderingAnnotation: "true"},
+			want:        true,
+		},
+		"disabled": {
+			annotations: map[string]string{brokerv1beta1.MessageOrderingAnnotation: "false"},
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.MessageOrderingAnnotation: "yes please"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			b := &brokerv1beta1.Broker{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := MessageOrderingEnabled(b); got != tc.want {
+				t.Errorf("MessageOrderingEnabled got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOrderedDeliveryEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"no annotations": {},
+		"enabled": {
+			annotations: map[string]string{brokerv1beta1.OrderedDeliveryAnnotation: "true"},
+			want:        true,
+		},
+		"broker annotation is ignored": {
+			annotations: map[string]string{brokerv1beta1.MessageOrderingAnnotation: "true"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := OrderedDeliveryEnabled(trig); got != tc.want {
+				t.Errorf("OrderedDeliveryEnabled got=%v, want=%v", got, tc.want)
+			}
+		})
+	}
+}
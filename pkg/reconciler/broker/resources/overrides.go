@@ -0,0 +1,66 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// contextAttributes are the CloudEvents context attributes, which cannot be
+// overridden as extensions.
+var contextAttributes = map[string]bool{
+	"specversion":     true,
+	"id":              true,
+	"source":          true,
+	"type":            true,
+	"subject":         true,
+	"time":            true,
+	"datacontenttype": true,
+	"dataschema":      true,
+}
+
+// CEOverrides returns the CloudEvents extensions the fanout should set on the
+// events delivered to the Trigger. An error is returned if the annotation is
+// malformed or names an invalid extension or a context attribute.
+func CEOverrides(t *brokerv1beta1.Trigger) (map[string]string, error) {
+	v, ok := t.Annotations[brokerv1beta1.CEOverridesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var overrides map[string]string
+	if err := json.Unmarshal([]byte(v), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.CEOverridesAnnotation, err)
+	}
+	for name := range overrides {
+		if !event.IsAlphaNumeric(name) || name != strings.ToLower(name) {
+			return nil, fmt.Errorf("invalid %s annotation: %q is not a valid extension name", brokerv1beta1.CEOverridesAnnotation, name)
+		}
+		if contextAttributes[name] {
+			return nil, fmt.Errorf("invalid %s annotation: %q is a context attribute", brokerv1beta1.CEOverridesAnnotation, name)
+		}
+	}
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+	return overrides, nil
+}
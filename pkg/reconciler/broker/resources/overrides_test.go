@@ -0,0 +1,71 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestCEOverrides(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"overrides": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `{"region":"europe","tier":"gold"}`},
+			want:        map[string]string{"region": "europe", "tier": "gold"},
+		},
+		"empty": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `{}`},
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `region=europe`},
+			wantErr:     true,
+		},
+		"upper case name": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `{"region":"europe","Tier":"gold"}`},
+			wantErr:     true,
+		},
+		"non alphanumeric name": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `{"region":"europe","my-tier":"gold"}`},
+			wantErr:     true,
+		},
+		"context attribute": {
+			annotations: map[string]string{brokerv1beta1.CEOverridesAnnotation: `{"source":"somewhere"}`},
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := CEOverrides(trig)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("CEOverrides error got=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("CEOverrides (-want,+got): %v", diff)
+			}
+		})
+	}
+}
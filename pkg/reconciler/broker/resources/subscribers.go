@@ -0,0 +1,52 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+// Subscribers returns the destinations the Trigger delivers events to besides
+// its spec.subscriber. The namespace of destination references is the
+// namespace of the Trigger.
+func Subscribers(t *brokerv1beta1.Trigger) ([]duckv1.Destination, error) {
+	v, ok := t.Annotations[brokerv1beta1.SubscribersAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var subscribers []duckv1.Destination
+	if err := json.Unmarshal([]byte(v), &subscribers); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.SubscribersAnnotation, err)
+	}
+	for i := range subscribers {
+		if subscribers[i].Ref != nil {
+			subscribers[i].Ref.Namespace = t.Namespace
+		}
+	}
+	return subscribers, nil
+}
+
+// SequentialSubscribersDelivery returns true if events are delivered to the
+// subscribers of the Trigger one after the other rather than in parallel.
+func SequentialSubscribersDelivery(t *brokerv1beta1.Trigger) bool {
+	return t.Annotations[brokerv1beta1.SubscribersDeliveryAnnotation] == brokerv1beta1.SubscribersDeliverySequential
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestSubscribers(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        []duckv1.Destination
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"subscribers": {
+			annotations: map[string]string{brokerv1beta1.SubscribersAnnotation: `[{"uri":"http://audit.example.com"},{"ref":{"apiVersion":"v1","kind":"Service","name":"archive"}}]`},
+			want: []duckv1.Destination{
+				{URI: apis.HTTP("audit.example.com")},
+				{Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "archive", Namespace: "testnamespace"}},
+			},
+		},
+		"ref namespace overridden": {
+			annotations: map[string]string{brokerv1beta1.SubscribersAnnotation: `[{"ref":{"apiVersion":"v1","kind":"Service","name":"archive","namespace":"other"}}]`},
+			want: []duckv1.Destination{
+				{Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "archive", Namespace: "testnamespace"}},
+			},
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.SubscribersAnnotation: `http://audit.example.com`},
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Namespace: "testnamespace", Annotations: tc.annotations}}
+			got, err := Subscribers(trig)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Subscribers error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Subscribers (-want,+got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestSequentialSubscribersDelivery(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+	}{
+		"no annotations": {},
+		"parallel": {
+			annotations: map[string]string{brokerv1beta1.SubscribersDeliveryAnnotation: brokerv1beta1.SubscribersDeliveryParallel},
+		},
+		"sequential": {
+			annotations: map[string]string{brokerv1beta1.SubscribersDeliveryAnnotation: brokerv1beta1.SubscribersDeliverySequential},
+			want:        true,
+		},
+		"unknown": {
+			annotations: map[string]string{brokerv1beta1.SubscribersDeliveryAnnotation: "random"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := SequentialSubscribersDelivery(trig); got != tc.want {
+				t.Errorf("SequentialSubscribersDelivery = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"encoding/json"
+	"fmt"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/eventutil"
+)
+
+// Transform returns the CEL expressions, keyed by output field name, the
+// fanout should transform the data of the events delivered to the Trigger
+// with. An error is returned if the annotation is malformed, empty or has an
+// invalid expression.
+func Transform(t *brokerv1beta1.Trigger) (map[string]string, error) {
+	v, ok := t.Annotations[brokerv1beta1.TransformAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var fields map[string]string
+	if err := json.Unmarshal([]byte(v), &fields); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.TransformAnnotation, err)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid %s annotation: no fields", brokerv1beta1.TransformAnnotation)
+	}
+	if _, err := eventutil.NewTransform(fields); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", brokerv1beta1.TransformAnnotation, err)
+	}
+	return fields, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+)
+
+func TestTransform(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"transform": {
+			annotations: map[string]string{brokerv1beta1.TransformAnnotation: `{"user":"event.data.user.id","kind":"event.type"}`},
+			want:        map[string]string{"user": "event.data.user.id", "kind": "event.type"},
+		},
+		"malformed": {
+			annotations: map[string]string{brokerv1beta1.TransformAnnotation: `user=event.data.user.id`},
+			wantErr:     true,
+		},
+		"empty": {
+			annotations: map[string]string{brokerv1beta1.TransformAnnotation: `{}`},
+			wantErr:     true,
+		},
+		"invalid expression": {
+			annotations: map[string]string{brokerv1beta1.TransformAnnotation: `{"user":"event.data.user.id","kind":"event.type +"}`},
+			wantErr:     true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			trig := &brokerv1beta1.Trigger{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := Transform(trig)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Transform got error=%v, wantErr=%v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Transform (-want,+got): %v", diff)
+			}
+		})
+	}
+}
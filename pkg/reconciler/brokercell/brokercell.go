@@ -19,36 +19,68 @@ package brokercell
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
 	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	hpav2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/eventing/pkg/logging"
 	"knative.dev/eventing/pkg/reconciler/names"
 	pkgreconciler "knative.dev/pkg/reconciler"
 
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/apply"
+	brokerresources "github.com/google/knative-gcp/pkg/reconciler/broker/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/brokercell/resources"
+	"github.com/google/knative-gcp/pkg/utils/platform"
+)
+
+const (
+	// defaultMinReplicas and defaultMaxReplicas bound the replicas of the
+	// data plane components whose BrokerCell doesn't set them.
+	defaultMinReplicas = 1
+	defaultMaxReplicas = 10
 )
 
 type envConfig struct {
-	IngressImage       string `envconfig:"INGRESS_IMAGE" required:"true"`
-	FanoutImage        string `envconfig:"FANOUT_IMAGE" required:"true"`
-	RetryImage         string `envconfig:"RETRY_IMAGE" required:"true"`
+	// DataPlaneImage is the image of the data plane binary. Each
+	// component runs it with its own role.
+	DataPlaneImage     string `envconfig:"DATA_PLANE_IMAGE" required:"true"`
 	ServiceAccountName string `envconfig:"SERVICE_ACCOUNT" default:"broker"`
 	IngressPort        int    `envconfig:"INGRESS_PORT" default:"8080"`
 	MetricsPort        int    `envconfig:"METRICS_PORT" default:"9090"`
+
+	// Proxy settings for data plane pods of BrokerCells that don't set
+	// their own.
+	HTTPProxy  string `envconfig:"DATA_PLANE_HTTP_PROXY"`
+	HTTPSProxy string `envconfig:"DATA_PLANE_HTTPS_PROXY"`
+	NoProxy    string `envconfig:"DATA_PLANE_NO_PROXY"`
+
+	// NodeArchitectures are the CPU architectures of the nodes the data
+	// plane pods run on.
+	platform.NodeArchitectures
+
+	// RetryBacklogPerReplica is the target number of undelivered messages
+	// in the retry subscriptions per retry replica. It requires the Custom
+	// Metrics Stackdriver Adapter. If zero, the retry deployment is only
+	// scaled on its resource usage.
+	RetryBacklogPerReplica int64 `envconfig:"RETRY_BACKLOG_PER_REPLICA" default:"0"`
 }
 
 // NewReconciler creates a new BrokerCell reconciler.
@@ -58,15 +90,15 @@ func NewReconciler(base *reconciler.Base, brokerLister brokerlisters.BrokerListe
 		return nil, err
 	}
 	svcRec := &reconciler.ServiceReconciler{
-		KubeClient:      base.KubeClientSet,
+		DynamicClient:   base.DynamicClientSet,
 		ServiceLister:   serviceLister,
 		EndpointsLister: endpointsLister,
 		Recorder:        base.Recorder,
 	}
 	deploymentRec := &reconciler.DeploymentReconciler{
-		KubeClient: base.KubeClientSet,
-		Lister:     deploymentLister,
-		Recorder:   base.Recorder,
+		DynamicClient: base.DynamicClientSet,
+		Lister:        deploymentLister,
+		Recorder:      base.Recorder,
 	}
 	r := &Reconciler{
 		Base:          base,
@@ -82,8 +114,10 @@ func NewReconciler(base *reconciler.Base, brokerLister brokerlisters.BrokerListe
 type Reconciler struct {
 	*reconciler.Base
 
-	brokerLister brokerlisters.BrokerLister
-	hpaLister    hpav2beta2listers.HorizontalPodAutoscalerLister
+	brokerLister     brokerlisters.BrokerLister
+	triggerLister    brokerlisters.TriggerLister
+	brokerCellLister inteventslisters.BrokerCellLister
+	hpaLister        hpav2beta2listers.HorizontalPodAutoscalerLister
 
 	svcRec        *reconciler.ServiceReconciler
 	deploymentRec *reconciler.DeploymentReconciler
@@ -172,6 +206,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, bc *intv1alpha1.BrokerCe
 	// - Configmap
 	bc.Status.MarkTargetsConfigReady()
 
+	bc.Status.Ingress = intv1alpha1.NewComponentReplicas(ind, ingressHPA.Spec.MaxReplicas)
+	bc.Status.Fanout = intv1alpha1.NewComponentReplicas(fd, fanoutHPA.Spec.MaxReplicas)
+	bc.Status.Retry = intv1alpha1.NewComponentReplicas(rd, retryHPA.Spec.MaxReplicas)
+	r.reconcileCapacity(ctx, bc, map[string]*hpav2beta2.HorizontalPodAutoscaler{
+		resources.IngressName: ingressHPA,
+		resources.FanoutName:  fanoutHPA,
+		resources.RetryName:   retryHPA,
+	})
+	r.countBrokersAndTriggers(ctx, bc)
+
 	bc.Status.ObservedGeneration = bc.Generation
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "BrokerCellReconciled", "BrokerCell reconciled: \"%s/%s\"", bc.Namespace, bc.Name)
 }
@@ -199,6 +243,72 @@ func (r *Reconciler) shouldGC(ctx context.Context, bc *intv1alpha1.BrokerCell) b
 	return len(brokers) == 0
 }
 
+// reconcileCapacity marks the capacity of the brokercell as exhausted if any
+// of its data plane components has been scaled out to its maximum replicas.
+func (r *Reconciler) reconcileCapacity(ctx context.Context, bc *intv1alpha1.BrokerCell, hpas map[string]*hpav2beta2.HorizontalPodAutoscaler) {
+	var saturated []string
+	for component, desired := range hpas {
+		existing, err := r.hpaLister.HorizontalPodAutoscalers(desired.Namespace).Get(desired.Name)
+		if err != nil {
+			// The HPA was just created and hasn't been observed yet.
+			continue
+		}
+		if existing.Status.CurrentReplicas >= desired.Spec.MaxReplicas {
+			saturated = append(saturated, component)
+		}
+	}
+	if len(saturated) == 0 {
+		bc.Status.MarkCapacityAvailable()
+		return
+	}
+	sort.Strings(saturated)
+	bc.Status.MarkCapacityExhausted("MaxReplicasReached", "Data plane components at their maximum replicas: %s. Consider spreading the Brokers across more BrokerCells.", strings.Join(saturated, ", "))
+}
+
+// countBrokersAndTriggers records the number of brokers and triggers served by
+// the brokercell in its status.
+func (r *Reconciler) countBrokersAndTriggers(ctx context.Context, bc *intv1alpha1.BrokerCell) {
+	brokers, err := r.brokerLister.List(labels.Everything())
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list brokers", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace), zap.Error(err))
+		return
+	}
+	triggers, err := r.triggerLister.List(labels.Everything())
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list triggers", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace), zap.Error(err))
+		return
+	}
+	bcs, err := r.brokerCellLister.BrokerCells(bc.Namespace).List(labels.Everything())
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to list brokercells", zap.String("brokercell", bc.Name), zap.String("Namespace", bc.Namespace), zap.Error(err))
+		return
+	}
+
+	served := make(map[string]bool, len(brokers))
+	for _, b := range brokers {
+		if b.GetAnnotations()[eventingv1beta1.BrokerClassAnnotationKey] != brokerv1beta1.BrokerClass {
+			continue
+		}
+		// Brokers not selected by any brokercell are served by the default brokercell.
+		if selected := brokerresources.SelectBrokerCell(bcs, b); selected != nil {
+			if selected.Name != bc.Name {
+				continue
+			}
+		} else if bc.Name != brokerresources.DefaultBroekrCellName {
+			continue
+		}
+		served[b.Namespace+"/"+b.Name] = true
+	}
+	var triggerCount int32
+	for _, t := range triggers {
+		if served[t.Namespace+"/"+t.Spec.Broker] {
+			triggerCount++
+		}
+	}
+	bc.Status.BrokerCount = int32(len(served))
+	bc.Status.TriggerCount = triggerCount
+}
+
 func (r *Reconciler) delete(ctx context.Context, bc *intv1alpha1.BrokerCell) pkgreconciler.Event {
 	if err := r.RunClientSet.InternalV1alpha1().BrokerCells(bc.Namespace).Delete(bc.Name, nil); err != nil {
 		return fmt.Errorf("failed to garbage collect brokercell: %w", err)
@@ -206,26 +316,38 @@ func (r *Reconciler) delete(ctx context.Context, bc *intv1alpha1.BrokerCell) pkg
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, "BrokerCellGarbageCollected", "BrokerCell garbage collected: \"%s/%s\"", bc.Namespace, bc.Name)
 }
 
+func (r *Reconciler) defaultProxy() duckv1beta1.ProxySpec {
+	return duckv1beta1.ProxySpec{
+		HTTPProxy:  r.env.HTTPProxy,
+		HTTPSProxy: r.env.HTTPSProxy,
+		NoProxy:    r.env.NoProxy,
+	}
+}
+
 func (r *Reconciler) makeIngressArgs(bc *intv1alpha1.BrokerCell) resources.IngressArgs {
 	return resources.IngressArgs{
 		Args: resources.Args{
 			ComponentName:      resources.IngressName,
 			BrokerCell:         bc,
-			Image:              r.env.IngressImage,
+			Image:              r.image(bc.Spec.Components.Ingress),
 			ServiceAccountName: r.env.ServiceAccountName,
 			MetricsPort:        r.env.MetricsPort,
+			DefaultProxy:       r.defaultProxy(),
+			NodeArchitectures:  r.env.NodeArchitectures,
 		},
 		Port: r.env.IngressPort,
 	}
 }
 
 func (r *Reconciler) makeIngressHPAArgs(bc *intv1alpha1.BrokerCell) resources.AutoscalingArgs {
+	minReplicas, maxReplicas := replicas(bc.Spec.Components.Ingress)
 	return resources.AutoscalingArgs{
 		ComponentName:     resources.IngressName,
 		BrokerCell:        bc,
 		AvgCPUUtilization: 95,
 		AvgMemoryUsage:    "700Mi",
-		MaxReplicas:       10,
+		MinReplicas:       minReplicas,
+		MaxReplicas:       maxReplicas,
 	}
 }
 
@@ -234,14 +356,17 @@ func (r *Reconciler) makeFanoutArgs(bc *intv1alpha1.BrokerCell) resources.Fanout
 		Args: resources.Args{
 			ComponentName:      resources.FanoutName,
 			BrokerCell:         bc,
-			Image:              r.env.FanoutImage,
+			Image:              r.image(bc.Spec.Components.Fanout),
 			ServiceAccountName: r.env.ServiceAccountName,
 			MetricsPort:        r.env.MetricsPort,
+			DefaultProxy:       r.defaultProxy(),
+			NodeArchitectures:  r.env.NodeArchitectures,
 		},
 	}
 }
 
 func (r *Reconciler) makeFanoutHPAArgs(bc *intv1alpha1.BrokerCell) resources.AutoscalingArgs {
+	minReplicas, maxReplicas := catchUpReplicas(bc, bc.Spec.Components.Fanout)
 	return resources.AutoscalingArgs{
 		ComponentName:     resources.FanoutName,
 		BrokerCell:        bc,
@@ -252,7 +377,8 @@ func (r *Reconciler) makeFanoutHPAArgs(bc *intv1alpha1.BrokerCell) resources.Aut
 		// usage, HPA could have enough time to kick in.
 		// See: https://github.com/google/knative-gcp/issues/1265
 		AvgMemoryUsage: "1500Mi",
-		MaxReplicas:    10,
+		MinReplicas:    minReplicas,
+		MaxReplicas:    maxReplicas,
 	}
 }
 
@@ -261,14 +387,17 @@ func (r *Reconciler) makeRetryArgs(bc *intv1alpha1.BrokerCell) resources.RetryAr
 		Args: resources.Args{
 			ComponentName:      resources.RetryName,
 			BrokerCell:         bc,
-			Image:              r.env.RetryImage,
+			Image:              r.image(bc.Spec.Components.Retry),
 			ServiceAccountName: r.env.ServiceAccountName,
 			MetricsPort:        r.env.MetricsPort,
+			DefaultProxy:       r.defaultProxy(),
+			NodeArchitectures:  r.env.NodeArchitectures,
 		},
 	}
 }
 
 func (r *Reconciler) makeRetryHPAArgs(bc *intv1alpha1.BrokerCell) resources.AutoscalingArgs {
+	minReplicas, maxReplicas := catchUpReplicas(bc, bc.Spec.Components.Retry)
 	return resources.AutoscalingArgs{
 		ComponentName:     resources.RetryName,
 		BrokerCell:        bc,
@@ -278,36 +407,68 @@ func (r *Reconciler) makeRetryHPAArgs(bc *intv1alpha1.BrokerCell) resources.Auto
 		// Here we only set half of the limit so that in case of surging memory
 		// usage, HPA could have enough time to kick in.
 		// See: https://github.com/google/knative-gcp/issues/1265
-		AvgMemoryUsage: "1500Mi",
-		MaxReplicas:    10,
+		AvgMemoryUsage:  "1500Mi",
+		MinReplicas:     minReplicas,
+		MaxReplicas:     maxReplicas,
+		AvgRetryBacklog: r.env.RetryBacklogPerReplica,
 	}
 }
 
+// image returns the image of a data plane component, defaulting to the data
+// plane image.
+func (r *Reconciler) image(params *intv1alpha1.ComponentParameters) string {
+	if params != nil && params.Image != "" {
+		return params.Image
+	}
+	return r.env.DataPlaneImage
+}
+
+// replicas returns the range of replicas of a data plane component,
+// defaulting to [defaultMinReplicas, defaultMaxReplicas]. A minimum above the
+// default maximum raises the maximum.
+func replicas(params *intv1alpha1.ComponentParameters) (minReplicas, maxReplicas int32) {
+	minReplicas, maxReplicas = defaultMinReplicas, defaultMaxReplicas
+	if params != nil && params.MinReplicas != nil {
+		minReplicas = *params.MinReplicas
+	}
+	if params != nil && params.MaxReplicas != nil {
+		maxReplicas = *params.MaxReplicas
+	}
+	if minReplicas > maxReplicas {
+		maxReplicas = minReplicas
+	}
+	return minReplicas, maxReplicas
+}
+
+// catchUpReplicas returns the bounds of the replicas of the fanout or retry
+// component. While the BrokerCell catches up, the component runs at its
+// maximum replicas to drain the backlogs of its brokers. The BrokerCells are
+// resynced often enough to revert it shortly after the catch-up ends.
+func catchUpReplicas(bc *intv1alpha1.BrokerCell, params *intv1alpha1.ComponentParameters) (minReplicas, maxReplicas int32) {
+	minReplicas, maxReplicas = replicas(params)
+	if _, ok := duckv1beta1.CatchUpUntil(bc.Annotations, time.Now()); ok {
+		minReplicas = maxReplicas
+	}
+	return minReplicas, maxReplicas
+}
+
 func (r *Reconciler) reconcileAutoscaling(ctx context.Context, bc *intv1alpha1.BrokerCell, desired *hpav2beta2.HorizontalPodAutoscaler) error {
+	reason, action := "HorizontalPodAutoscalerUpdated", "Updated"
 	existing, err := r.hpaLister.HorizontalPodAutoscalers(desired.Namespace).Get(desired.Name)
-	if apierrs.IsNotFound(err) {
-		existing, err = r.KubeClientSet.AutoscalingV2beta2().HorizontalPodAutoscalers(desired.Namespace).Create(desired)
-		if apierrs.IsAlreadyExists(err) {
-			return nil
-		}
-		if err == nil {
-			r.Recorder.Eventf(bc, corev1.EventTypeNormal, "HorizontalPodAutoscalerCreated", "Created HPA %s/%s", desired.Namespace, desired.Name)
-		}
-		return err
-	}
-	if err != nil {
+	switch {
+	case apierrs.IsNotFound(err):
+		reason, action = "HorizontalPodAutoscalerCreated", "Created"
+	case err != nil:
 		return err
+	case reconciler.ShouldAdopt(existing, desired):
+		reason, action = "HorizontalPodAutoscalerAdopted", "Adopted"
+	case !apply.Changed(desired, existing, desired.Spec, existing.Spec):
+		return nil
 	}
 
-	if !equality.Semantic.DeepDerivative(desired.Spec, existing.Spec) {
-		// Don't modify the informers copy.
-		copy := existing.DeepCopy()
-		copy.Spec = desired.Spec
-		_, err := r.KubeClientSet.AutoscalingV2beta2().HorizontalPodAutoscalers(copy.Namespace).Update(copy)
-		if err == nil {
-			r.Recorder.Eventf(bc, corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA %s/%s", desired.Namespace, desired.Name)
-		}
+	if err := apply.Apply(r.DynamicClientSet, desired, &hpav2beta2.HorizontalPodAutoscaler{}); err != nil {
 		return err
 	}
+	r.Recorder.Eventf(bc, corev1.EventTypeNormal, reason, "%s HPA %s/%s", action, desired.Namespace, desired.Name)
 	return nil
 }
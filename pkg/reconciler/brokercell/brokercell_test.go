@@ -20,10 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
 
@@ -32,8 +34,10 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	bcreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
 	"github.com/google/knative-gcp/pkg/reconciler"
@@ -49,6 +53,12 @@ const (
 var (
 	testKey = fmt.Sprintf("%s/%s", testNS, brokerCellName)
 
+	// The deployments in the testing data don't report any replicas.
+	defaultReplicas = intv1alpha1.ComponentReplicas{MaxReplicas: 10}
+
+	teamLabels   = map[string]string{"team": "payments"}
+	teamSelector = &metav1.LabelSelector{MatchLabels: teamLabels}
+
 	creatorAnnotation = map[string]string{"internal.events.cloud.google.com/creator": "googlecloud"}
 
 	brokerCellReconciledEvent     = Eventf(corev1.EventTypeNormal, "BrokerCellReconciled", `BrokerCell reconciled: "testnamespace/test-brokercell"`)
@@ -69,12 +79,16 @@ var (
 	retryHPAUpdatedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerUpdated", "Updated HPA testnamespace/test-brokercell-brokercell-retry-hpa")
 	ingressServiceCreatedEvent    = Eventf(corev1.EventTypeNormal, "ServiceCreated", "Created service testnamespace/test-brokercell-brokercell-ingress")
 	ingressServiceUpdatedEvent    = Eventf(corev1.EventTypeNormal, "ServiceUpdated", "Updated service testnamespace/test-brokercell-brokercell-ingress")
-	deploymentCreationFailedEvent = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create deployments")
-	deploymentUpdateFailedEvent   = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update deployments")
-	serviceCreationFailedEvent    = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create services")
-	serviceUpdateFailedEvent      = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update services")
-	hpaCreationFailedEvent        = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for create horizontalpodautoscalers")
-	hpaUpdateFailedEvent          = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for update horizontalpodautoscalers")
+	ingressDeploymentAdoptedEvent = Eventf(corev1.EventTypeNormal, "DeploymentAdopted", "Adopted deployment testnamespace/test-brokercell-brokercell-ingress")
+	ingressHPAAdoptedEvent        = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerAdopted", "Adopted HPA testnamespace/test-brokercell-brokercell-ingress-hpa")
+	ingressServiceAdoptedEvent    = Eventf(corev1.EventTypeNormal, "ServiceAdopted", "Adopted service testnamespace/test-brokercell-brokercell-ingress")
+	fanoutDeploymentAdoptedEvent  = Eventf(corev1.EventTypeNormal, "DeploymentAdopted", "Adopted deployment testnamespace/test-brokercell-brokercell-fanout")
+	fanoutHPAAdoptedEvent         = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerAdopted", "Adopted HPA testnamespace/test-brokercell-brokercell-fanout-hpa")
+	retryDeploymentAdoptedEvent   = Eventf(corev1.EventTypeNormal, "DeploymentAdopted", "Adopted deployment testnamespace/test-brokercell-brokercell-retry")
+	retryHPAAdoptedEvent          = Eventf(corev1.EventTypeNormal, "HorizontalPodAutoscalerAdopted", "Adopted HPA testnamespace/test-brokercell-brokercell-retry-hpa")
+	deploymentApplyFailedEvent    = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for patch deployments")
+	serviceApplyFailedEvent       = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for patch services")
+	hpaApplyFailedEvent           = Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for patch horizontalpodautoscalers")
 )
 
 func init() {
@@ -108,19 +122,19 @@ func TestAllCases(t *testing.T) {
 				NewBrokerCell(brokerCellName, testNS),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("IngressDeploymentFailed", `Failed to reconcile ingress deployment: inducing failure for create deployments`),
+					WithBrokerCellIngressFailed("IngressDeploymentFailed", `Failed to reconcile ingress deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
-				deploymentCreationFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.IngressDeployment(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -138,19 +152,19 @@ func TestAllCases(t *testing.T) {
 				),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("IngressDeploymentFailed", `Failed to reconcile ingress deployment: inducing failure for update deployments`),
+					WithBrokerCellIngressFailed("IngressDeploymentFailed", `Failed to reconcile ingress deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
-				deploymentUpdateFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.IngressDeployment(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -162,19 +176,19 @@ func TestAllCases(t *testing.T) {
 				testingdata.IngressDeploymentWithStatus(t),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile ingress HorizontalPodAutoscaler: inducing failure for create horizontalpodautoscalers`),
+					WithBrokerCellIngressFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile ingress HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaCreationFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.IngressHPA(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -187,19 +201,19 @@ func TestAllCases(t *testing.T) {
 				emptyHPASpec(testingdata.IngressHPA(t)),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile ingress HorizontalPodAutoscaler: inducing failure for update horizontalpodautoscalers`),
+					WithBrokerCellIngressFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile ingress HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaUpdateFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.IngressHPA(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -214,19 +228,19 @@ func TestAllCases(t *testing.T) {
 					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "services"),
+				InduceFailure("patch", "services"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("IngressServiceFailed", `Failed to reconcile ingress service: inducing failure for create services`),
+					WithBrokerCellIngressFailed("IngressServiceFailed", `Failed to reconcile ingress service: inducing failure for patch services`),
 				),
 			}},
 			WantEvents: []string{
-				serviceCreationFailedEvent,
+				serviceApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.IngressService(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressService(t)),
 			},
 			WantErr: true,
 		},
@@ -246,19 +260,19 @@ func TestAllCases(t *testing.T) {
 					}),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "services"),
+				InduceFailure("patch", "services"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
-					WithBrokerCellIngressFailed("IngressServiceFailed", `Failed to reconcile ingress service: inducing failure for update services`),
+					WithBrokerCellIngressFailed("IngressServiceFailed", `Failed to reconcile ingress service: inducing failure for patch services`),
 				),
 			}},
 			WantEvents: []string{
-				serviceUpdateFailedEvent,
+				serviceApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.IngressService(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressService(t)),
 			},
 			WantErr: true,
 		},
@@ -274,21 +288,21 @@ func TestAllCases(t *testing.T) {
 				testingdata.IngressServiceWithStatus(t),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
-					WithBrokerCellFanoutFailed("FanoutDeploymentFailed", `Failed to reconcile fanout deployment: inducing failure for create deployments`),
+					WithBrokerCellFanoutFailed("FanoutDeploymentFailed", `Failed to reconcile fanout deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
-				deploymentCreationFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.FanoutDeployment(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.FanoutDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -311,21 +325,21 @@ func TestAllCases(t *testing.T) {
 				),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
-					WithBrokerCellFanoutFailed("FanoutDeploymentFailed", `Failed to reconcile fanout deployment: inducing failure for update deployments`),
+					WithBrokerCellFanoutFailed("FanoutDeploymentFailed", `Failed to reconcile fanout deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
-				deploymentUpdateFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.FanoutDeployment(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.FanoutDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -342,21 +356,21 @@ func TestAllCases(t *testing.T) {
 				testingdata.FanoutDeployment(t),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
-					WithBrokerCellFanoutFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile fanout HorizontalPodAutoscaler: inducing failure for create horizontalpodautoscalers`),
+					WithBrokerCellFanoutFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile fanout HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaCreationFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.FanoutHPA(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.FanoutHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -374,21 +388,21 @@ func TestAllCases(t *testing.T) {
 				emptyHPASpec(testingdata.FanoutHPA(t)),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
 					WithInitBrokerCellConditions,
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
-					WithBrokerCellFanoutFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile fanout HorizontalPodAutoscaler: inducing failure for update horizontalpodautoscalers`),
+					WithBrokerCellFanoutFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile fanout HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaUpdateFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.FanoutHPA(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.FanoutHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -406,7 +420,7 @@ func TestAllCases(t *testing.T) {
 				testingdata.FanoutHPA(t),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
@@ -414,15 +428,15 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
 					WithBrokerCellFanoutAvailable(),
-					WithBrokerCellRetryFailed("RetryDeploymentFailed", `Failed to reconcile retry deployment: inducing failure for create deployments`),
+					WithBrokerCellRetryFailed("RetryDeploymentFailed", `Failed to reconcile retry deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
 
-				deploymentCreationFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.RetryDeployment(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.RetryDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -447,7 +461,7 @@ func TestAllCases(t *testing.T) {
 				),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "deployments"),
+				InduceFailure("patch", "deployments"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
@@ -455,14 +469,14 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
 					WithBrokerCellFanoutAvailable(),
-					WithBrokerCellRetryFailed("RetryDeploymentFailed", `Failed to reconcile retry deployment: inducing failure for update deployments`),
+					WithBrokerCellRetryFailed("RetryDeploymentFailed", `Failed to reconcile retry deployment: inducing failure for patch deployments`),
 				),
 			}},
 			WantEvents: []string{
-				deploymentUpdateFailedEvent,
+				deploymentApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.RetryDeployment(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.RetryDeployment(t)),
 			},
 			WantErr: true,
 		},
@@ -481,7 +495,7 @@ func TestAllCases(t *testing.T) {
 				testingdata.FanoutHPA(t),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("create", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
@@ -489,14 +503,14 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
 					WithBrokerCellFanoutAvailable(),
-					WithBrokerCellRetryFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile retry HorizontalPodAutoscaler: inducing failure for create horizontalpodautoscalers`),
+					WithBrokerCellRetryFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile retry HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaCreationFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantCreates: []runtime.Object{
-				testingdata.RetryHPA(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.RetryHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -516,7 +530,7 @@ func TestAllCases(t *testing.T) {
 				emptyHPASpec(testingdata.RetryHPA(t)),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
-				InduceFailure("update", "horizontalpodautoscalers"),
+				InduceFailure("patch", "horizontalpodautoscalers"),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 				Object: NewBrokerCell(brokerCellName, testNS,
@@ -524,14 +538,14 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellIngressAvailable(),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
 					WithBrokerCellFanoutAvailable(),
-					WithBrokerCellRetryFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile retry HorizontalPodAutoscaler: inducing failure for update horizontalpodautoscalers`),
+					WithBrokerCellRetryFailed("HorizontalPodAutoscalerFailed", `Failed to reconcile retry HorizontalPodAutoscaler: inducing failure for patch horizontalpodautoscalers`),
 				),
 			}},
 			WantEvents: []string{
-				hpaUpdateFailedEvent,
+				hpaApplyFailedEvent,
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.RetryHPA(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.RetryHPA(t)),
 			},
 			WantErr: true,
 		},
@@ -542,14 +556,14 @@ func TestAllCases(t *testing.T) {
 				NewBrokerCell(brokerCellName, testNS),
 				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS),
 			},
-			WantCreates: []runtime.Object{
-				testingdata.IngressDeployment(t),
-				testingdata.IngressHPA(t),
-				testingdata.IngressService(t),
-				testingdata.FanoutDeployment(t),
-				testingdata.FanoutHPA(t),
-				testingdata.RetryDeployment(t),
-				testingdata.RetryHPA(t),
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressDeployment(t)),
+				NewApplyPatch(t, testingdata.IngressHPA(t)),
+				NewApplyPatch(t, testingdata.IngressService(t)),
+				NewApplyPatch(t, testingdata.FanoutDeployment(t)),
+				NewApplyPatch(t, testingdata.FanoutHPA(t)),
+				NewApplyPatch(t, testingdata.RetryDeployment(t)),
+				NewApplyPatch(t, testingdata.RetryHPA(t)),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
 				{Object: NewBrokerCell(brokerCellName, testNS,
@@ -557,11 +571,12 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellReady,
 					// For newly created deployments and services, there statues are not ready because
 					// we don't have a controller in the tests to mark their statues ready.
-					// We only verify that they are created in the WantCreates.
+					// We only verify that they are applied in the WantPatches.
 					WithBrokerCellIngressFailed("EndpointsUnavailable", `Endpoints "test-brokercell-brokercell-ingress" is unavailable.`),
 					WithBrokerCellFanoutFailed("DeploymentUnavailable", `Deployment "test-brokercell-brokercell-fanout" is unavailable.`),
 					WithBrokerCellRetryFailed("DeploymentUnavailable", `Deployment "test-brokercell-brokercell-retry" is unavailable.`),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
 				)},
 			},
 			WantEvents: []string{
@@ -608,14 +623,14 @@ func TestAllCases(t *testing.T) {
 				emptyHPASpec(testingdata.FanoutHPA(t)),
 				emptyHPASpec(testingdata.RetryHPA(t)),
 			},
-			WantUpdates: []clientgotesting.UpdateActionImpl{
-				{Object: testingdata.IngressDeployment(t)},
-				{Object: testingdata.IngressHPA(t)},
-				{Object: testingdata.IngressService(t)},
-				{Object: testingdata.FanoutDeployment(t)},
-				{Object: testingdata.FanoutHPA(t)},
-				{Object: testingdata.RetryDeployment(t)},
-				{Object: testingdata.RetryHPA(t)},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressDeployment(t)),
+				NewApplyPatch(t, testingdata.IngressHPA(t)),
+				NewApplyPatch(t, testingdata.IngressService(t)),
+				NewApplyPatch(t, testingdata.FanoutDeployment(t)),
+				NewApplyPatch(t, testingdata.FanoutHPA(t)),
+				NewApplyPatch(t, testingdata.RetryDeployment(t)),
+				NewApplyPatch(t, testingdata.RetryHPA(t)),
 			},
 			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
 				{Object: NewBrokerCell(brokerCellName, testNS,
@@ -623,11 +638,12 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellReady,
 					// For newly created deployments and services, there statues are not ready because
 					// we don't have a controller in the tests to mark their statues ready.
-					// We only verify that they are created in the WantCreates.
+					// We only verify that they are applied in the WantPatches.
 					WithBrokerCellIngressFailed("EndpointsUnavailable", `Endpoints "test-brokercell-brokercell-ingress" is unavailable.`),
 					WithBrokerCellFanoutFailed("DeploymentUnavailable", `Deployment "test-brokercell-brokercell-fanout" is unavailable.`),
 					WithBrokerCellRetryFailed("DeploymentUnavailable", `Deployment "test-brokercell-brokercell-retry" is unavailable.`),
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
 				)},
 			},
 			WantEvents: []string{
@@ -663,6 +679,7 @@ func TestAllCases(t *testing.T) {
 				{Object: NewBrokerCell(brokerCellName, testNS,
 					WithBrokerCellReady,
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
 				)},
 			},
 			WantEvents: []string{
@@ -689,12 +706,55 @@ func TestAllCases(t *testing.T) {
 				{Object: NewBrokerCell(brokerCellName, testNS,
 					WithBrokerCellReady,
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
 				)},
 			},
 			WantEvents: []string{
 				brokerCellReconciledEvent,
 			},
 		},
+		{
+			Name: "BrokerCell resources restored without owner references are adopted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				orphaned(testingdata.IngressDeploymentWithStatus(t)),
+				orphaned(testingdata.IngressServiceWithStatus(t)),
+				orphaned(testingdata.FanoutDeploymentWithStatus(t)),
+				orphaned(testingdata.RetryDeploymentWithStatus(t)),
+				orphaned(testingdata.IngressHPA(t)),
+				orphaned(testingdata.FanoutHPA(t)),
+				orphaned(testingdata.RetryHPA(t)),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				NewApplyPatch(t, testingdata.IngressDeploymentWithStatus(t)),
+				NewApplyPatch(t, testingdata.IngressHPA(t)),
+				NewApplyPatch(t, testingdata.IngressServiceWithStatus(t)),
+				NewApplyPatch(t, testingdata.FanoutDeploymentWithStatus(t)),
+				NewApplyPatch(t, testingdata.FanoutHPA(t)),
+				NewApplyPatch(t, testingdata.RetryDeploymentWithStatus(t)),
+				NewApplyPatch(t, testingdata.RetryHPA(t)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
+				)},
+			},
+			WantEvents: []string{
+				ingressDeploymentAdoptedEvent,
+				ingressHPAAdoptedEvent,
+				ingressServiceAdoptedEvent,
+				fanoutDeploymentAdoptedEvent,
+				fanoutHPAAdoptedEvent,
+				retryDeploymentAdoptedEvent,
+				retryHPAAdoptedEvent,
+				brokerCellReconciledEvent,
+			},
+		},
 		{
 			Name: "googlecloud created BrokerCell shouldn't be gc'ed because there are brokers",
 			Key:  testKey,
@@ -716,6 +776,49 @@ func TestAllCases(t *testing.T) {
 					WithBrokerCellAnnotations(creatorAnnotation),
 					WithBrokerCellReady,
 					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(defaultReplicas, defaultReplicas, defaultReplicas),
+				)},
+			},
+			WantEvents: []string{
+				brokerCellReconciledEvent,
+			},
+		},
+		{
+			Name: "BrokerCell reports served brokers and triggers and exhausted capacity",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBrokerCell(brokerCellName, testNS, WithBrokerCellBrokerSelector(teamSelector)),
+				NewBroker("broker", testNS, WithBrokerLabels(teamLabels)),
+				NewBroker("other-broker", "other-namespace", WithBrokerLabels(teamLabels)),
+				NewBroker("mt-broker", testNS, WithBrokerClass("MTChannelBasedBroker"), WithBrokerLabels(teamLabels)),
+				NewBroker("unselected-broker", testNS),
+				NewTrigger("trigger", testNS, "broker"),
+				NewTrigger("other-trigger", "other-namespace", "other-broker"),
+				NewTrigger("mt-trigger", testNS, "mt-broker"),
+				NewTrigger("unselected-trigger", testNS, "unselected-broker"),
+				NewTrigger("missing-broker-trigger", testNS, "missing"),
+				NewEndpoints(brokerCellName+"-brokercell-ingress", testNS,
+					WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+				withReplicas(testingdata.IngressDeploymentWithStatus(t), 10, 9),
+				testingdata.IngressServiceWithStatus(t),
+				withReplicas(testingdata.FanoutDeploymentWithStatus(t), 2, 2),
+				testingdata.RetryDeploymentWithStatus(t),
+				withCurrentReplicas(testingdata.IngressHPA(t), 10),
+				withCurrentReplicas(testingdata.FanoutHPA(t), 2),
+				testingdata.RetryHPA(t),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{
+				{Object: NewBrokerCell(brokerCellName, testNS,
+					WithBrokerCellBrokerSelector(teamSelector),
+					WithBrokerCellReady,
+					WithIngressTemplate("http://test-brokercell-brokercell-ingress.testnamespace.svc.cluster.local/{namespace}/{name}"),
+					WithBrokerCellReplicas(
+						intv1alpha1.ComponentReplicas{Replicas: 10, ReadyReplicas: 9, MaxReplicas: 10},
+						intv1alpha1.ComponentReplicas{Replicas: 2, ReadyReplicas: 2, MaxReplicas: 10},
+						defaultReplicas,
+					),
+					WithBrokerCellCapacityExhausted("MaxReplicasReached", "Data plane components at their maximum replicas: ingress. Consider spreading the Brokers across more BrokerCells."),
+					WithBrokerCellCounts(2, 2),
 				)},
 			},
 			WantEvents: []string{
@@ -767,11 +870,118 @@ func TestAllCases(t *testing.T) {
 			t.Fatalf("Failed to created BrokerCell reconciler: %v", err)
 		}
 		r.hpaLister = listers.GetHPALister()
+		r.triggerLister = listers.GetTriggerLister()
+		r.brokerCellLister = listers.GetBrokerCellLister()
 		return bcreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetBrokerCellLister(), r.Recorder, r)
 	}))
 }
 
+// orphaned removes the owner references of obj, as happens when it's restored
+// from a backup.
+func orphaned(obj interface {
+	runtime.Object
+	metav1.Object
+}) runtime.Object {
+	obj.SetOwnerReferences(nil)
+	return obj
+}
+
+func withReplicas(d *appsv1.Deployment, replicas, readyReplicas int32) *appsv1.Deployment {
+	d.Status.Replicas = replicas
+	d.Status.ReadyReplicas = readyReplicas
+	return d
+}
+
+func withCurrentReplicas(hpa *hpav2beta2.HorizontalPodAutoscaler, replicas int32) *hpav2beta2.HorizontalPodAutoscaler {
+	hpa.Status.CurrentReplicas = replicas
+	return hpa
+}
+
 func emptyHPASpec(template *hpav2beta2.HorizontalPodAutoscaler) *hpav2beta2.HorizontalPodAutoscaler {
 	template.Spec = hpav2beta2.HorizontalPodAutoscalerSpec{}
 	return template
 }
+
+func TestComponentParameters(t *testing.T) {
+	r := &Reconciler{env: envConfig{DataPlaneImage: "dataplane"}}
+	testCases := map[string]struct {
+		params  *intv1alpha1.ComponentParameters
+		image   string
+		minReps int32
+		maxReps int32
+	}{
+		"unset": {
+			image:   "dataplane",
+			minReps: 1,
+			maxReps: 10,
+		},
+		"empty": {
+			params:  &intv1alpha1.ComponentParameters{},
+			image:   "dataplane",
+			minReps: 1,
+			maxReps: 10,
+		},
+		"set": {
+			params: &intv1alpha1.ComponentParameters{
+				Image:       "custom",
+				MinReplicas: ptr.Int32(2),
+				MaxReplicas: ptr.Int32(4),
+			},
+			image:   "custom",
+			minReps: 2,
+			maxReps: 4,
+		},
+		"minimum above the default maximum": {
+			params: &intv1alpha1.ComponentParameters{
+				MinReplicas: ptr.Int32(15),
+			},
+			image:   "dataplane",
+			minReps: 15,
+			maxReps: 15,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := r.image(tc.params); got != tc.image {
+				t.Errorf("image() = %q, want %q", got, tc.image)
+			}
+			minReps, maxReps := replicas(tc.params)
+			if minReps != tc.minReps || maxReps != tc.maxReps {
+				t.Errorf("replicas() = [%d, %d], want [%d, %d]", minReps, maxReps, tc.minReps, tc.maxReps)
+			}
+		})
+	}
+}
+
+func TestCatchUpReplicas(t *testing.T) {
+	params := &intv1alpha1.ComponentParameters{
+		MinReplicas: ptr.Int32(2),
+		MaxReplicas: ptr.Int32(8),
+	}
+	testCases := map[string]struct {
+		annotations map[string]string
+		minReps     int32
+	}{
+		"no catch-up": {
+			minReps: 2,
+		},
+		"catching up": {
+			annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(time.Hour).Format(time.RFC3339)},
+			minReps:     8,
+		},
+		"caught up": {
+			annotations: map[string]string{duckv1beta1.CatchUpUntilAnnotation: time.Now().Add(-time.Hour).Format(time.RFC3339)},
+			minReps:     2,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			bc := &intv1alpha1.BrokerCell{}
+			bc.Annotations = tc.annotations
+			minReps, maxReps := catchUpReplicas(bc, params)
+			if minReps != tc.minReps || maxReps != 8 {
+				t.Errorf("catchUpReplicas() = [%d, %d], want [%d, 8]", minReps, maxReps, tc.minReps)
+			}
+		})
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"knative.dev/pkg/controller"
 
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
+	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
 	"github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	hpainformer "github.com/google/knative-gcp/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler"
 	v1alpha1brokercell "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/brokercell"
@@ -59,6 +60,7 @@ func NewController(
 	svcLister := serviceinformer.Get(ctx).Lister()
 	epLister := endpointsinformer.Get(ctx).Lister()
 	hpaLister := hpainformer.Get(ctx).Lister()
+	triggerLister := triggerinformer.Get(ctx).Lister()
 
 	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
 	r, err := NewReconciler(base, brokerLister, svcLister, epLister, deploymentLister)
@@ -66,6 +68,8 @@ func NewController(
 		logger.Fatal("Failed to create BrokerCell reconciler", zap.Error(err))
 	}
 	r.hpaLister = hpaLister
+	r.triggerLister = triggerLister
+	r.brokerCellLister = brokercellInformer.Lister()
 	impl := v1alpha1brokercell.NewImpl(ctx, r)
 
 	logger.Info("Setting up event handlers.")
@@ -32,6 +32,7 @@ import (
 
 	// Fake injection informers
 	_ "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker/fake"
+	_ "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger/fake"
 	_ "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell/fake"
 	_ "github.com/google/knative-gcp/pkg/client/injection/kube/informers/autoscaling/v2beta2/horizontalpodautoscaler/fake"
 	_ "knative.dev/pkg/client/injection/ducks/duck/v1/conditions/fake"
@@ -76,7 +77,5 @@ func TestNew(t *testing.T) {
 }
 
 func setReconcilerEnv() {
-	_ = os.Setenv("BROKER_CELL_INGRESS_IMAGE", "ingress")
-	_ = os.Setenv("BROKER_CELL_FANOUT_IMAGE", "fanout")
-	_ = os.Setenv("BROKER_CELL_RETRY_IMAGE", "retry")
+	_ = os.Setenv("BROKER_CELL_DATA_PLANE_IMAGE", "dataplane")
 }
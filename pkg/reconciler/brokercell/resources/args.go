@@ -21,7 +21,9 @@ import (
 
 	"knative.dev/pkg/kmeta"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/utils/platform"
 )
 
 const (
@@ -32,6 +34,20 @@ const (
 	// RetryName is the name used for the retry container.
 	RetryName          = "retry"
 	BrokerCellLabelKey = "brokerCell"
+	// DeliveryHeadersSecretName is the name of the secret holding the
+	// headers set on the requests delivering events to triggers, mounted in
+	// the fanout and retry pods.
+	DeliveryHeadersSecretName = "broker-delivery-headers"
+	// IngressAuthSecretName is the name of the secret holding the hashes of
+	// the bearer tokens accepted by the brokers requiring one, mounted in
+	// the ingress pods.
+	IngressAuthSecretName = "broker-ingress-auth"
+
+	// caBundleVolumeName is the name of the volume of the CA bundle of the
+	// BrokerCell, mounted at caBundleDir with the bundle in caBundleFile.
+	caBundleVolumeName = "ca-bundle"
+	caBundleDir        = "/var/run/cloud-run-events/ca-bundle"
+	caBundleFile       = "ca.crt"
 )
 
 var (
@@ -45,6 +61,12 @@ type Args struct {
 	Image              string
 	ServiceAccountName string
 	MetricsPort        int
+	// DefaultProxy is used for the proxy settings the BrokerCell doesn't
+	// set.
+	DefaultProxy duckv1beta1.ProxySpec
+	// NodeArchitectures are the CPU architectures of the nodes the pods run
+	// on.
+	NodeArchitectures platform.NodeArchitectures
 }
 
 // IngressArgs are the arguments to create a Broker's ingress Deployment.
@@ -69,7 +91,14 @@ type AutoscalingArgs struct {
 	BrokerCell        *intv1alpha1.BrokerCell
 	AvgCPUUtilization int32
 	AvgMemoryUsage    string
-	MaxReplicas       int32
+	// MinReplicas is the lower limit of replicas. If zero, it defaults to
+	// 1.
+	MinReplicas int32
+	MaxReplicas int32
+	// AvgRetryBacklog is the target number of undelivered messages in the
+	// retry subscriptions per replica. If zero, the backlog isn't used
+	// for autoscaling.
+	AvgRetryBacklog int64
 }
 
 // Labels generates the labels present on all resources representing the
@@ -19,7 +19,11 @@ package resources
 import (
 	"strconv"
 
+	"github.com/google/knative-gcp/pkg/broker/auth"
 	"github.com/google/knative-gcp/pkg/broker/handler"
+	"github.com/google/knative-gcp/pkg/broker/headers"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/metrics"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -59,7 +63,7 @@ func MakeIngressDeployment(args IngressArgs) *appsv1.Deployment {
 			corev1.ResourceCPU:    resource.MustParse("1000m"),
 		},
 	}
-	return deploymentTemplate(args.Args, []corev1.Container{container})
+	return withIngressAuth(deploymentTemplate(args.Args, []corev1.Container{container}))
 }
 
 // MakeFanoutDeployment creates the fanout Deployment object.
@@ -98,7 +102,7 @@ func MakeFanoutDeployment(args FanoutArgs) *appsv1.Deployment {
 		SuccessThreshold:    1,
 		TimeoutSeconds:      5,
 	}
-	return deploymentTemplate(args.Args, []corev1.Container{container})
+	return withCABundle(withDeliveryHeaders(deploymentTemplate(args.Args, []corev1.Container{container})), args.BrokerCell.Spec.CABundle)
 }
 
 // MakeRetryDeployment creates the retry Deployment object.
@@ -133,12 +137,12 @@ func MakeRetryDeployment(args RetryArgs) *appsv1.Deployment {
 		SuccessThreshold:    1,
 		TimeoutSeconds:      5,
 	}
-	return deploymentTemplate(args.Args, []corev1.Container{container})
+	return withCABundle(withDeliveryHeaders(deploymentTemplate(args.Args, []corev1.Container{container})), args.BrokerCell.Spec.CABundle)
 }
 
 // deploymentTemplate creates a template for data plane deployments.
 func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deployment {
-	return &appsv1.Deployment{
+	d := &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       args.BrokerCell.Namespace,
 			Name:            Name(args.BrokerCell.Name, args.ComponentName),
@@ -148,7 +152,10 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{MatchLabels: Labels(args.BrokerCell.Name, args.ComponentName)},
 			Template: corev1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{Labels: Labels(args.BrokerCell.Name, args.ComponentName)},
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      Labels(args.BrokerCell.Name, args.ComponentName),
+					Annotations: args.BrokerCell.Spec.Istio.PodAnnotations(),
+				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: args.ServiceAccountName,
 					Volumes: []corev1.Volume{
@@ -166,13 +173,84 @@ func deploymentTemplate(args Args, containers []corev1.Container) *appsv1.Deploy
 			},
 		},
 	}
+	args.NodeArchitectures.Apply(&d.Spec.Template.Spec)
+	return d
+}
+
+// withDeliveryHeaders mounts the delivery headers secret in the containers of
+// a deployment delivering events to triggers. The secret only exists once a
+// trigger has delivery headers, so it is optional.
+func withDeliveryHeaders(d *appsv1.Deployment) *appsv1.Deployment {
+	spec := &d.Spec.Template.Spec
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         DeliveryHeadersSecretName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: DeliveryHeadersSecretName, Optional: &optionalSecretVolume}},
+	})
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      DeliveryHeadersSecretName,
+			MountPath: headers.DefaultDir,
+			ReadOnly:  true,
+		})
+	}
+	return d
+}
+
+// withIngressAuth mounts the ingress auth secret in the containers of the
+// ingress deployment. The secret only exists once a broker requires a bearer
+// token, so it is optional.
+func withIngressAuth(d *appsv1.Deployment) *appsv1.Deployment {
+	spec := &d.Spec.Template.Spec
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         IngressAuthSecretName,
+		VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: IngressAuthSecretName, Optional: &optionalSecretVolume}},
+	})
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      IngressAuthSecretName,
+			MountPath: auth.DefaultDir,
+			ReadOnly:  true,
+		})
+	}
+	return d
+}
+
+// withCABundle mounts the CA bundle of the BrokerCell, if any, in the
+// containers of a deployment delivering events to triggers, and has them trust
+// its certificates. The bundle is read when the containers start.
+func withCABundle(d *appsv1.Deployment, caBundle *corev1.ConfigMapKeySelector) *appsv1.Deployment {
+	if caBundle == nil {
+		return d
+	}
+	spec := &d.Spec.Template.Spec
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name: caBundleVolumeName,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: caBundle.LocalObjectReference,
+			Items:                []corev1.KeyToPath{{Key: caBundle.Key, Path: caBundleFile}},
+		}},
+	})
+	for i := range spec.Containers {
+		c := &spec.Containers[i]
+		c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      caBundleVolumeName,
+			MountPath: caBundleDir,
+			ReadOnly:  true,
+		})
+		c.Env = append(c.Env, corev1.EnvVar{
+			Name:  "CA_BUNDLE_PATH",
+			Value: caBundleDir + "/" + caBundleFile,
+		})
+	}
+	return d
 }
 
 // containerTemplate returns a common template for broker data plane containers.
 func containerTemplate(args Args) corev1.Container {
-	return corev1.Container{
+	c := corev1.Container{
 		Image: args.Image,
 		Name:  args.ComponentName,
+		Args:  []string{"--role=" + args.ComponentName},
 		Env: []corev1.EnvVar{
 			{
 				Name:  "GOOGLE_APPLICATION_CREDENTIALS",
@@ -190,6 +268,10 @@ func containerTemplate(args Args) corev1.Container {
 					},
 				},
 			},
+			{
+				Name:  "BROKER_CELL",
+				Value: args.BrokerCell.Name,
+			},
 			{
 				Name:  "CONFIG_LOGGING_NAME",
 				Value: "config-logging",
@@ -221,4 +303,8 @@ func containerTemplate(args Args) corev1.Container {
 			},
 		},
 	}
+	c.Env = append(c.Env, args.BrokerCell.Spec.Proxy.EnvVars(args.DefaultProxy)...)
+	c.Env = append(c.Env, endpoints.EnvVars()...)
+	c.Env = append(c.Env, metrics.MetricLabelEnvVars()...)
+	return c
 }
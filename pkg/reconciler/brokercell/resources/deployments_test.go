@@ -0,0 +1,154 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	_ "knative.dev/pkg/system/testing"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	rectesting "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"github.com/google/knative-gcp/pkg/utils/platform"
+)
+
+func TestMakeDeploymentsGolden(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns", UID: "uid"},
+		Spec: intv1alpha1.BrokerCellSpec{
+			CABundle: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+				Key:                  "bundle.pem",
+			},
+		},
+	}
+	args := Args{
+		BrokerCell:         bc,
+		Image:              "image",
+		ServiceAccountName: "broker",
+		MetricsPort:        9090,
+	}
+	rectesting.AssertGolden(t, "ingress_deployment", MakeIngressDeployment(IngressArgs{Args: args, Port: 8080}))
+	rectesting.AssertGolden(t, "fanout_deployment", MakeFanoutDeployment(FanoutArgs{Args: args}))
+	rectesting.AssertGolden(t, "retry_deployment", MakeRetryDeployment(RetryArgs{Args: args}))
+}
+
+func TestMakeDeploymentsWithProxy(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns"},
+		Spec: intv1alpha1.BrokerCellSpec{
+			Proxy: &duckv1beta1.ProxySpec{NoProxy: ".svc.cluster.local"},
+		},
+	}
+	args := Args{
+		BrokerCell:   bc,
+		Image:        "image",
+		MetricsPort:  9090,
+		DefaultProxy: duckv1beta1.ProxySpec{HTTPSProxy: "http://proxy:3128"},
+	}
+	want := []corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+		{Name: "NO_PROXY", Value: ".svc.cluster.local"},
+	}
+
+	for _, c := range []corev1.Container{
+		MakeIngressDeployment(IngressArgs{Args: args, Port: 8080}).Spec.Template.Spec.Containers[0],
+		MakeFanoutDeployment(FanoutArgs{Args: args}).Spec.Template.Spec.Containers[0],
+		MakeRetryDeployment(RetryArgs{Args: args}).Spec.Template.Spec.Containers[0],
+	} {
+		var got []corev1.EnvVar
+		for _, e := range c.Env {
+			if e.Name == "HTTP_PROXY" || e.Name == "HTTPS_PROXY" || e.Name == "NO_PROXY" {
+				got = append(got, e)
+			}
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("unexpected proxy env (-want, +got) = %v", diff)
+		}
+	}
+}
+
+func TestMakeDeploymentsWithCABundle(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns"},
+		Spec: intv1alpha1.BrokerCellSpec{
+			CABundle: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+				Key:                  "bundle.pem",
+			},
+		},
+	}
+	args := Args{BrokerCell: bc, Image: "image", MetricsPort: 9090}
+	wantVolume := corev1.Volume{
+		Name: "ca-bundle",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+			Items:                []corev1.KeyToPath{{Key: "bundle.pem", Path: "ca.crt"}},
+		}},
+	}
+	wantMount := corev1.VolumeMount{Name: "ca-bundle", MountPath: "/var/run/cloud-run-events/ca-bundle", ReadOnly: true}
+	wantEnv := corev1.EnvVar{Name: "CA_BUNDLE_PATH", Value: "/var/run/cloud-run-events/ca-bundle/ca.crt"}
+
+	for name, spec := range map[string]corev1.PodSpec{
+		"fanout": MakeFanoutDeployment(FanoutArgs{Args: args}).Spec.Template.Spec,
+		"retry":  MakeRetryDeployment(RetryArgs{Args: args}).Spec.Template.Spec,
+	} {
+		c := spec.Containers[0]
+		if diff := cmp.Diff(wantVolume, spec.Volumes[len(spec.Volumes)-1]); diff != "" {
+			t.Errorf("%s: unexpected CA bundle volume (-want, +got) = %v", name, diff)
+		}
+		if diff := cmp.Diff(wantMount, c.VolumeMounts[len(c.VolumeMounts)-1]); diff != "" {
+			t.Errorf("%s: unexpected CA bundle volume mount (-want, +got) = %v", name, diff)
+		}
+		if diff := cmp.Diff(wantEnv, c.Env[len(c.Env)-1]); diff != "" {
+			t.Errorf("%s: unexpected CA bundle env (-want, +got) = %v", name, diff)
+		}
+	}
+
+	// The ingress doesn't deliver events.
+	for _, v := range MakeIngressDeployment(IngressArgs{Args: args, Port: 8080}).Spec.Template.Spec.Volumes {
+		if v.Name == "ca-bundle" {
+			t.Error("ingress: unexpected CA bundle volume")
+		}
+	}
+}
+
+func TestMakeDeploymentsWithNodeArchitectures(t *testing.T) {
+	archs := platform.NodeArchitectures{Allowed: []string{"amd64", "arm64"}, Preferred: "arm64"}
+	args := Args{
+		BrokerCell:        &intv1alpha1.BrokerCell{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns"}},
+		Image:             "image",
+		MetricsPort:       9090,
+		NodeArchitectures: archs,
+	}
+	for name, spec := range map[string]corev1.PodSpec{
+		"ingress": MakeIngressDeployment(IngressArgs{Args: args, Port: 8080}).Spec.Template.Spec,
+		"fanout":  MakeFanoutDeployment(FanoutArgs{Args: args}).Spec.Template.Spec,
+		"retry":   MakeRetryDeployment(RetryArgs{Args: args}).Spec.Template.Spec,
+	} {
+		if diff := cmp.Diff(archs.Affinity(), spec.Affinity); diff != "" {
+			t.Errorf("%s: unexpected affinity (-want, +got) = %v", name, diff)
+		}
+		if diff := cmp.Diff(archs.Tolerations(), spec.Tolerations); diff != "" {
+			t.Errorf("%s: unexpected tolerations (-want, +got) = %v", name, diff)
+		}
+	}
+}
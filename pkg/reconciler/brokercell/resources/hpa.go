@@ -25,11 +25,28 @@ import (
 	"knative.dev/pkg/kmeta"
 )
 
+const (
+	// undeliveredMessagesMetric is the Stackdriver metric of the number of
+	// undelivered messages in a Pub/Sub subscription, as exposed by the
+	// Custom Metrics Stackdriver Adapter.
+	undeliveredMessagesMetric = "pubsub.googleapis.com|subscription|num_undelivered_messages"
+
+	// retrySubscriptionLabel selects the retry subscriptions, which are
+	// labeled by the trigger reconciler.
+	retrySubscriptionLabel = "metadata.user_labels.resource"
+	// retryBrokerCellLabel selects the retry subscriptions of the triggers
+	// served by a brokercell, which are labeled by the trigger reconciler.
+	retryBrokerCellLabel = "metadata.user_labels.brokercell"
+)
+
 // MakeHorizontalPodAutoscaler makes an HPA for the given arguments.
 func MakeHorizontalPodAutoscaler(deployment *appsv1.Deployment, args AutoscalingArgs) *hpav2beta2.HorizontalPodAutoscaler {
-	var one int32 = 1
+	minReplicas := args.MinReplicas
+	if minReplicas == 0 {
+		minReplicas = 1
+	}
 	memQuantity := resource.MustParse(args.AvgMemoryUsage)
-	return &hpav2beta2.HorizontalPodAutoscaler{
+	hpa := &hpav2beta2.HorizontalPodAutoscaler{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            deployment.Name + "-hpa",
 			Namespace:       deployment.Namespace,
@@ -43,7 +60,7 @@ func MakeHorizontalPodAutoscaler(deployment *appsv1.Deployment, args Autoscaling
 				Name:       deployment.Name,
 			},
 			MaxReplicas: args.MaxReplicas,
-			MinReplicas: &one,
+			MinReplicas: &minReplicas,
 			Metrics: []hpav2beta2.MetricSpec{
 				{
 					Type: hpav2beta2.ResourceMetricSourceType,
@@ -68,4 +85,34 @@ func MakeHorizontalPodAutoscaler(deployment *appsv1.Deployment, args Autoscaling
 			},
 		},
 	}
+	if args.AvgRetryBacklog > 0 {
+		hpa.Spec.Metrics = append(hpa.Spec.Metrics, retryBacklogMetric(args.BrokerCell.Name, args.AvgRetryBacklog))
+	}
+	return hpa
+}
+
+// retryBacklogMetric returns an external metric scaling on the total number
+// of undelivered messages in the retry subscriptions of the brokercell.
+// Scaling on CPU alone leaves retries lagging when a sink recovers from an
+// outage, since the retry pods mostly wait on the sinks while the backlog
+// builds up.
+func retryBacklogMetric(brokerCell string, avgBacklog int64) hpav2beta2.MetricSpec {
+	return hpav2beta2.MetricSpec{
+		Type: hpav2beta2.ExternalMetricSourceType,
+		External: &hpav2beta2.ExternalMetricSource{
+			Metric: hpav2beta2.MetricIdentifier{
+				Name: undeliveredMessagesMetric,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						retrySubscriptionLabel: "triggers",
+						retryBrokerCellLabel:   brokerCell,
+					},
+				},
+			},
+			Target: hpav2beta2.MetricTarget{
+				Type:         hpav2beta2.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(avgBacklog, resource.DecimalSI),
+			},
+		},
+	}
 }
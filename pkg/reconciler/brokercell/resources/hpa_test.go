@@ -0,0 +1,100 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	hpav2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
+
+func TestMakeHorizontalPodAutoscalerRetryBacklog(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns"},
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-brokercell-retry", Namespace: "ns"},
+	}
+	args := AutoscalingArgs{
+		ComponentName:     RetryName,
+		BrokerCell:        bc,
+		AvgCPUUtilization: 95,
+		AvgMemoryUsage:    "1500Mi",
+		MaxReplicas:       10,
+	}
+
+	if got := len(MakeHorizontalPodAutoscaler(d, args).Spec.Metrics); got != 2 {
+		t.Errorf("got %d metrics without retry backlog, want 2", got)
+	}
+
+	args.AvgRetryBacklog = 1000
+	metrics := MakeHorizontalPodAutoscaler(d, args).Spec.Metrics
+	if len(metrics) != 3 {
+		t.Fatalf("got %d metrics with retry backlog, want 3", len(metrics))
+	}
+	want := hpav2beta2.MetricSpec{
+		Type: hpav2beta2.ExternalMetricSourceType,
+		External: &hpav2beta2.ExternalMetricSource{
+			Metric: hpav2beta2.MetricIdentifier{
+				Name: "pubsub.googleapis.com|subscription|num_undelivered_messages",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"metadata.user_labels.resource":   "triggers",
+						"metadata.user_labels.brokercell": "default",
+					},
+				},
+			},
+			Target: hpav2beta2.MetricTarget{
+				Type:         hpav2beta2.AverageValueMetricType,
+				AverageValue: resource.NewQuantity(1000, resource.DecimalSI),
+			},
+		},
+	}
+	if diff := cmp.Diff(want, metrics[2]); diff != "" {
+		t.Errorf("unexpected retry backlog metric (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeHorizontalPodAutoscalerMinReplicas(t *testing.T) {
+	bc := &intv1alpha1.BrokerCell{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "ns"},
+	}
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "default-brokercell-ingress", Namespace: "ns"},
+	}
+	args := AutoscalingArgs{
+		ComponentName:     IngressName,
+		BrokerCell:        bc,
+		AvgCPUUtilization: 95,
+		AvgMemoryUsage:    "700Mi",
+		MaxReplicas:       10,
+	}
+
+	if got := *MakeHorizontalPodAutoscaler(d, args).Spec.MinReplicas; got != 1 {
+		t.Errorf("got %d min replicas by default, want 1", got)
+	}
+	args.MinReplicas = 3
+	if got := *MakeHorizontalPodAutoscaler(d, args).Spec.MinReplicas; got != 3 {
+		t.Errorf("got %d min replicas, want 3", got)
+	}
+}
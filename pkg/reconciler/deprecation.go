@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+// DeprecatedAPIVersionReason is the reason of the events and conditions
+// reporting that an object was written with a deprecated API version.
+const DeprecatedAPIVersionReason = "DeprecatedAPIVersion"
+
+// deprecationCondSet manages the Deprecated condition independently of the
+// conditions that make up the readiness of an object.
+var deprecationCondSet = apis.NewLivingConditionSet()
+
+// MarkDeprecated sets the Deprecated condition in status if obj was last
+// created or updated with a deprecated API version, as recorded by the
+// webhook, recommending the replacement version instead. A warning event is
+// emitted when the condition is set. The condition is cleared once obj no
+// longer has the DeprecatedAPIVersionAnnotation.
+func MarkDeprecated(ctx context.Context, obj kmeta.Accessor, status apis.ConditionsAccessor, replacement schema.GroupVersion) {
+	cm := deprecationCondSet.Manage(status)
+	version, ok := obj.GetAnnotations()[duckv1beta1.DeprecatedAPIVersionAnnotation]
+	if !ok {
+		// ClearCondition only fails for terminal conditions.
+		_ = cm.ClearCondition(duckv1beta1.ConditionDeprecated)
+		return
+	}
+
+	message := fmt.Sprintf("%s is deprecated and will be removed in a future release, use %s instead", version, replacement)
+	if c := cm.GetCondition(duckv1beta1.ConditionDeprecated); c != nil && c.IsTrue() && c.Message == message {
+		return
+	}
+	cm.SetCondition(apis.Condition{
+		Type:     duckv1beta1.ConditionDeprecated,
+		Status:   corev1.ConditionTrue,
+		Severity: apis.ConditionSeverityWarning,
+		Reason:   DeprecatedAPIVersionReason,
+		Message:  message,
+	})
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+		recorder.Event(obj, corev1.EventTypeWarning, DeprecatedAPIVersionReason, message)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/controller"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+func TestMarkDeprecated(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.Background(), recorder)
+	topic := &v1beta1.Topic{ObjectMeta: metav1.ObjectMeta{
+		Name:      "my-topic",
+		Namespace: "my-namespace",
+		Annotations: map[string]string{
+			duckv1beta1.DeprecatedAPIVersionAnnotation: "internal.events.cloud.google.com/v1alpha1",
+		},
+	}}
+	topic.Status.InitializeConditions()
+
+	MarkDeprecated(ctx, topic, &topic.Status, v1beta1.SchemeGroupVersion)
+	cond := topic.Status.GetCondition(duckv1beta1.ConditionDeprecated)
+	if cond == nil {
+		t.Fatal("Deprecated condition not set")
+	}
+	wantMessage := "internal.events.cloud.google.com/v1alpha1 is deprecated and will be removed in a future release, use internal.events.cloud.google.com/v1beta1 instead"
+	if !cond.IsTrue() || cond.Severity != apis.ConditionSeverityWarning || cond.Reason != DeprecatedAPIVersionReason || cond.Message != wantMessage {
+		t.Errorf("Unexpected Deprecated condition: %+v", cond)
+	}
+	if got, want := <-recorder.Events, corev1.EventTypeWarning+" "+DeprecatedAPIVersionReason+" "+wantMessage; got != want {
+		t.Errorf("Unexpected event, got: %q, want: %q", got, want)
+	}
+	if topic.Status.IsReady() || topic.Status.GetCondition(apis.ConditionReady).IsFalse() {
+		t.Errorf("Deprecated condition changed the readiness: %+v", topic.Status.GetCondition(apis.ConditionReady))
+	}
+
+	// Reconciling again doesn't repeat the event.
+	MarkDeprecated(ctx, topic, &topic.Status, v1beta1.SchemeGroupVersion)
+	if len(recorder.Events) != 0 {
+		t.Errorf("Unexpected event: %q", <-recorder.Events)
+	}
+
+	// The condition is cleared once the object is written with a supported version.
+	delete(topic.Annotations, duckv1beta1.DeprecatedAPIVersionAnnotation)
+	MarkDeprecated(ctx, topic, &topic.Status, v1beta1.SchemeGroupVersion)
+	if cond := topic.Status.GetCondition(duckv1beta1.ConditionDeprecated); cond != nil {
+		t.Errorf("Deprecated condition not cleared: %+v", cond)
+	}
+	if len(recorder.Events) != 0 {
+		t.Errorf("Unexpected event: %q", <-recorder.Events)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+const (
+	// DryRunEnvKey is the environment variable that, when set to "true",
+	// puts all the resources reconciled by the controller in dry-run mode.
+	DryRunEnvKey = "DRY_RUN"
+
+	// DryRunReason is the reason of the events and conditions reporting an
+	// external change planned in dry-run mode.
+	DryRunReason = "DryRun"
+)
+
+// DryRun returns true if the external changes for obj, e.g. creating Pub/Sub
+// topics or IAM policy bindings, must be planned and reported instead of made.
+// This is the case if obj has the DryRunAnnotation set to "true" or if the
+// whole controller runs in dry-run mode.
+func DryRun(obj interface{}) bool {
+	if os.Getenv(DryRunEnvKey) == "true" {
+		return true
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return accessor.GetAnnotations()[duckv1beta1.DryRunAnnotation] == "true"
+}
+
+// PlannedChange logs an external change that was planned but not made in
+// dry-run mode, and returns a normal event reporting it.
+func PlannedChange(ctx context.Context, format string, args ...interface{}) pkgreconciler.Event {
+	logging.FromContext(ctx).Desugar().Info("Dry run, skipping external change", zap.String("change", fmt.Sprintf(format, args...)))
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, DryRunReason, format, args...)
+}
+
+// IsPlannedChange returns true if err is an event returned by PlannedChange.
+func IsPlannedChange(err error) bool {
+	var event *pkgreconciler.ReconcilerEvent
+	return pkgreconciler.EventAs(err, &event) && event.Reason == DryRunReason
+}
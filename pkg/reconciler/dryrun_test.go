@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func TestDryRun(t *testing.T) {
+	defer os.Setenv(DryRunEnvKey, os.Getenv(DryRunEnvKey))
+	annotated := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{duckv1beta1.DryRunAnnotation: "true"},
+	}}
+	tests := []struct {
+		name string
+		env  string
+		obj  *corev1.Namespace
+		want bool
+	}{
+		{name: "not annotated", obj: obj, want: false},
+		{name: "annotated", obj: annotated, want: true},
+		{name: "controller in dry run", env: "true", obj: obj, want: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(DryRunEnvKey, tc.env)
+			if got := DryRun(tc.obj); got != tc.want {
+				t.Errorf("DryRun() = %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsPlannedChange(t *testing.T) {
+	planned := PlannedChange(context.Background(), "Would create Pub/Sub topic %q", "test-topic")
+	if got, want := planned.Error(), `Would create Pub/Sub topic "test-topic"`; got != want {
+		t.Errorf("Unexpected message, got: %q, want: %q", got, want)
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "planned change", err: planned, want: true},
+		{name: "wrapped planned change", err: fmt.Errorf("wrapped: %w", planned), want: true},
+		{name: "other event", err: pkgreconciler.NewEvent(corev1.EventTypeNormal, "TopicReconciled", "reconciled"), want: false},
+		{name: "error", err: errors.New("failed"), want: false},
+		{name: "nil", want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsPlannedChange(tc.err); got != tc.want {
+				t.Errorf("IsPlannedChange() = %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
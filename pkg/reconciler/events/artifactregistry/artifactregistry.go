@@ -0,0 +1,111 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifactregistry
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	cloudartifactregistrysourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudartifactregistrysource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+)
+
+const (
+	resourceGroup = "cloudartifactregistrysources.events.cloud.google.com"
+
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "CloudArtifactRegistrySourceReconciled"
+)
+
+// Reconciler is the controller implementation for the CloudArtifactRegistrySource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
+	// registryLister for reading cloudartifactregistrysources.
+	registryLister listers.CloudArtifactRegistrySourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudartifactregistrysourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, registry *v1beta1.CloudArtifactRegistrySource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("registry", registry)))
+
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, registry, &registry.Status)()
+
+	registry.Status.InitializeConditions()
+	registry.Status.ObservedGeneration = registry.Generation
+	kgcpreconciler.MarkDeprecated(ctx, registry, &registry.Status, v1beta1.SchemeGroupVersion)
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if registry.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, registry.Spec.Project, registry); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudArtifactRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	// The registries publish to the gcr topic of the project, which the user
+	// creates, so only the subscription is managed.
+	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, registry, events.CloudArtifactRegistryTopic, resourceGroup, false)
+	if event != nil {
+		return event
+	}
+
+	if err := r.ReconcileEventTypes(ctx, registry, []eventtype.EventType{{
+		Type:        v1beta1.CloudArtifactRegistrySourceImagePushed,
+		Description: "This event is sent when an image is pushed or tagged in Artifact Registry or Container Registry.",
+	}, {
+		Type:        v1beta1.CloudArtifactRegistrySourceImageDeleted,
+		Description: "This event is sent when an image or a tag is deleted from Artifact Registry or Container Registry.",
+	}}); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile CloudArtifactRegistrySource EventTypes: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudArtifactRegistrySource reconciled: "%s/%s"`, registry.Namespace, registry.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, registry *v1beta1.CloudArtifactRegistrySource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if registry.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, registry.Spec.Project, registry); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudArtifactRegistrySource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
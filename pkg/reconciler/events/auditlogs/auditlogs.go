@@ -34,7 +34,9 @@ import (
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
 	glogadmin "github.com/google/knative-gcp/pkg/gclient/logging/logadmin"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/auditlogs/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 )
@@ -43,9 +45,13 @@ const (
 	resourceGroup = "cloudauditlogssources.events.cloud.google.com"
 	publisherRole = "roles/pubsub.publisher"
 
+	// auditLogsSchema is the schema of the data of the audit log CloudEvents.
+	auditLogsSchema = "type.googleapis.com/google.logging.v2.LogEntry"
+
 	deletePubSubFailed           = "PubSubDeleteFailed"
 	deleteSinkFailed             = "SinkDeleteFailed"
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
 	reconciledFailedReason       = "SinkReconcileFailed"
 	reconciledPubSubFailedReason = "PubSubReconcileFailed"
 	reconciledSuccessReason      = "CloudAuditLogsSourceReconciled"
@@ -56,6 +62,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
 	auditLogsSourceLister  listers.CloudAuditLogsSourceLister
 	logadminClientProvider glogadmin.CreateFn
 	pubsubClientProvider   gpubsub.CreateFn
@@ -69,8 +77,12 @@ var _ cloudauditlogssourcereconciler.Interface = (*Reconciler)(nil)
 func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1beta1.CloudAuditLogsSource) reconciler.Event {
 	ctx = logging.WithLogger(ctx, c.Logger.With(zap.Any("auditlogsource", s)))
 
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer c.TrackReadiness(ctx, s, &s.Status)()
+
 	s.Status.InitializeConditions()
 	s.Status.ObservedGeneration = s.Generation
+	kgcpreconciler.MarkDeprecated(ctx, s, &s.Status, v1beta1.SchemeGroupVersion)
 
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if s.Spec.ServiceAccountName != "" {
@@ -94,6 +106,14 @@ func (c *Reconciler) ReconcileKind(ctx context.Context, s *v1beta1.CloudAuditLog
 	s.Status.MarkSinkReady()
 	c.Logger.Debugf("Reconciled Stackdriver sink: %+v", sink)
 
+	if err := c.ReconcileEventTypes(ctx, s, []eventtype.EventType{{
+		Type:        v1beta1.CloudAuditLogsSourceEvent,
+		Schema:      auditLogsSchema,
+		Description: "Common audit log event type for all Google Cloud Platform API operations.",
+	}}); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Reconcile EventTypes failed with: %s", err.Error())
+	}
+
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudAuditLogsSource reconciled: "%s/%s"`, s.Namespace, s.Name)
 }
 
@@ -121,23 +141,33 @@ func (c *Reconciler) ensureSinkCreated(ctx context.Context, s *v1beta1.CloudAudi
 		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
 		return nil, err
 	}
+	defer logadminClient.Close()
+
+	filterBuilder := resources.FilterBuilder{}
+	filterBuilder.WithServiceName(s.Spec.ServiceName).WithMethodName(s.Spec.MethodName)
+	if s.Spec.ResourceName != "" {
+		filterBuilder.WithResourceName(s.Spec.ResourceName)
+	}
+	desired := &logadmin.Sink{
+		ID:          sinkID,
+		Destination: resources.GenerateTopicResourceName(s),
+		Filter:      filterBuilder.GetFilterQuery(),
+	}
+
 	sink, err := logadminClient.Sink(ctx, sinkID)
-	if status.Code(err) == codes.NotFound {
-		filterBuilder := resources.FilterBuilder{}
-		filterBuilder.WithServiceName(s.Spec.ServiceName).WithMethodName(s.Spec.MethodName)
-		if s.Spec.ResourceName != "" {
-			filterBuilder.WithResourceName(s.Spec.ResourceName)
-		}
-		sink = &logadmin.Sink{
-			ID:          sinkID,
-			Destination: resources.GenerateTopicResourceName(s),
-			Filter:      filterBuilder.GetFilterQuery(),
-		}
-		sink, err = logadminClient.CreateSinkOpt(ctx, sink, logadmin.SinkOptions{UniqueWriterIdentity: true})
+	switch {
+	case status.Code(err) == codes.NotFound:
+		sink, err = logadminClient.CreateSinkOpt(ctx, desired, logadmin.SinkOptions{UniqueWriterIdentity: true})
 		// Handle AlreadyExists in-case of a race between another create call.
 		if status.Code(err) == codes.AlreadyExists {
 			sink, err = logadminClient.Sink(ctx, sinkID)
 		}
+	case err == nil && (sink.Destination != desired.Destination || sink.Filter != desired.Filter):
+		// The sink was changed outside of the cluster, e.g. through gcloud. Restore it, keeping its writer identity,
+		// which has already been granted the publisher role on the topic.
+		logging.FromContext(ctx).Desugar().Info("Restoring modified Stackdriver sink",
+			zap.String("sinkID", sinkID), zap.String("destination", sink.Destination), zap.String("filter", sink.Filter))
+		sink, err = logadminClient.UpdateSinkOpt(ctx, desired, logadmin.SinkOptions{UpdateDestination: true, UpdateFilter: true})
 	}
 	return sink, err
 }
@@ -149,6 +179,7 @@ func (c *Reconciler) ensureSinkIsPublisher(ctx context.Context, s *v1beta1.Cloud
 		logging.FromContext(ctx).Desugar().Error("Failed to create PubSub client", zap.Error(err))
 		return err
 	}
+	defer pubsubClient.Close()
 	topicIam := pubsubClient.Topic(s.Status.TopicID).IAM()
 	topicPolicy, err := topicIam.Policy(ctx)
 	if err != nil {
@@ -167,21 +198,32 @@ func (c *Reconciler) ensureSinkIsPublisher(ctx context.Context, s *v1beta1.Cloud
 	return nil
 }
 
-// deleteSink looks at status.SinkID and if non-empty will delete the
-// previously created stackdriver sink.
+// deleteSink deletes the stackdriver sink of the source, if any. Transient
+// failures are retried, and the sink is abandoned if it still can't be deleted
+// and the source has the AbandonOnFinalizeFailureAnnotation set.
 func (c *Reconciler) deleteSink(ctx context.Context, s *v1beta1.CloudAuditLogsSource) error {
-	if s.Status.StackdriverSink == "" {
+	if s.Status.ProjectID == "" {
+		// The sink is only created once the project is known.
 		return nil
 	}
+	sinkID := s.Status.StackdriverSink
+	if sinkID == "" {
+		// The sink may have been created without its ID making it to the
+		// status, e.g. if the status update failed.
+		sinkID = resources.GenerateSinkName(s)
+	}
 	logadminClient, err := c.logadminClientProvider(ctx, s.Status.ProjectID)
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to create LogAdmin client", zap.Error(err))
 		return err
 	}
-	if err = logadminClient.DeleteSink(ctx, s.Status.StackdriverSink); status.Code(err) != codes.NotFound {
-		return err
-	}
-	return nil
+	defer logadminClient.Close()
+	return kgcpreconciler.FinalizeExternal(ctx, c.Recorder, s, "Stackdriver sink", sinkID, func(ctx context.Context) error {
+		if err := logadminClient.DeleteSink(ctx, sinkID); status.Code(err) != codes.NotFound {
+			return err
+		}
+		return nil
+	})
 }
 
 func (c *Reconciler) FinalizeKind(ctx context.Context, s *v1beta1.CloudAuditLogsSource) reconciler.Event {
@@ -50,6 +50,7 @@ import (
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
@@ -1271,6 +1272,209 @@ func TestAllCases(t *testing.T) {
 				Name: sourceName,
 			},
 		},
+	}, {
+		Name: "sink exists with a modified filter, restored",
+		Objects: []runtime.Object{
+			NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+			),
+			NewTopic(sourceName, testNS,
+				WithTopicSpec(inteventsv1beta1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				WithTopicReady(testTopicID),
+				WithTopicAddress(testTopicURI),
+				WithTopicProjectID(testProject),
+			),
+			NewPullSubscriptionWithNoDefaults(sourceName, testNS,
+				WithPullSubscriptionReady(sinkURI),
+				WithPullSubscriptionSpecWithNoDefaults(inteventsv1beta1.PullSubscriptionSpec{
+					Topic: testTopicID,
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret: &secret,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: newSinkDestination(),
+						},
+					},
+					AdapterType: converters.CloudAuditLogsConverter,
+				})),
+		},
+		Key: testNS + "/" + sourceName,
+		OtherTestData: map[string]interface{}{
+			"existingSinks": []logadmin.Sink{{
+				ID:          testSinkID,
+				Filter:      "modified-filter",
+				Destination: testTopicResource,
+			}},
+			"expectedSinks": map[string]*logadmin.Sink{
+				testSinkID: {
+					ID:          testSinkID,
+					Filter:      testFilter,
+					Destination: testTopicResource,
+				}},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudAuditLogsSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				WithCloudAuditLogsSourceProjectID(testProject),
+				WithCloudAuditLogsSourceSubscriptionID(SubscriptionID),
+				WithInitCloudAuditLogsSourceConditions,
+				WithCloudAuditLogsSourceTopicReady(testTopicID),
+				WithCloudAuditLogsSourcePullSubscriptionReady(),
+				WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				WithCloudAuditLogsSourceSinkReady(),
+				WithCloudAuditLogsSourceSinkID(testSinkID),
+			),
+		}},
+	}, {
+		Name: "sink delete fails, abandoned",
+		Objects: []runtime.Object{
+			NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				WithCloudAuditLogsSourceProjectID(testProject),
+				WithInitCloudAuditLogsSourceConditions,
+				WithCloudAuditLogsSourceTopicReady(testTopicID),
+				WithCloudAuditLogsSourcePullSubscriptionReady(),
+				WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				WithCloudAuditLogsSourceSinkReady(),
+				WithCloudAuditLogsSourceSinkID(testSinkID),
+				WithCloudAuditLogsSourceDeletionTimestamp,
+				WithCloudAuditLogsSourceAnnotations(map[string]string{
+					duckv1beta1.AbandonOnFinalizeFailureAnnotation: "true",
+				}),
+			),
+			NewTopic(sourceName, testNS,
+				WithTopicReady(testTopicID),
+				WithTopicAddress(testTopicURI),
+				WithTopicProjectID(testProject),
+			),
+			NewPullSubscriptionWithNoDefaults(sourceName, testNS,
+				WithPullSubscriptionReady(sinkURI),
+			),
+		},
+		Key: testNS + "/" + sourceName,
+		OtherTestData: map[string]interface{}{
+			"existingSinks": []logadmin.Sink{{
+				ID:          testSinkID,
+				Filter:      testFilter,
+				Destination: testTopicResource,
+			}},
+			"expectedSinks": map[string]*logadmin.Sink{
+				testSinkID: {
+					ID:          testSinkID,
+					Filter:      testFilter,
+					Destination: testTopicResource,
+				}},
+			"logadmin": glogadmintesting.TestClientConfiguration{
+				DeleteSinkErr: errors.New("delete-sink-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "ExternalResourceAbandoned", `Abandoned Stackdriver sink %q after failing to delete it: delete-sink-induced-error`, testSinkID),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				WithInitCloudAuditLogsSourceConditions,
+				WithCloudAuditLogsSourceSinkReady(),
+				WithCloudAuditLogsSourceTopicFailed("TopicDeleted", fmt.Sprintf("Successfully deleted Topic: %s", sourceName)),
+				WithCloudAuditLogsSourcePullSubscriptionFailed("PullSubscriptionDeleted", fmt.Sprintf("Successfully deleted PullSubscription: %s", sourceName)),
+				WithCloudAuditLogsSourceDeletionTimestamp,
+				WithCloudAuditLogsSourceAnnotations(map[string]string{
+					duckv1beta1.AbandonOnFinalizeFailureAnnotation: "true",
+				}),
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "topics"}},
+				Name: sourceName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "pullsubscriptions"}},
+				Name: sourceName,
+			},
+		},
+	}, {
+		Name: "sink delete succeeds, sink missing from the status",
+		Objects: []runtime.Object{
+			NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				WithCloudAuditLogsSourceProjectID(testProject),
+				WithInitCloudAuditLogsSourceConditions,
+				WithCloudAuditLogsSourceTopicReady(testTopicID),
+				WithCloudAuditLogsSourcePullSubscriptionReady(),
+				WithCloudAuditLogsSourceSinkURI(calSinkURL),
+				WithCloudAuditLogsSourceSinkReady(),
+				WithCloudAuditLogsSourceDeletionTimestamp,
+			),
+			NewTopic(sourceName, testNS,
+				WithTopicReady(testTopicID),
+				WithTopicAddress(testTopicURI),
+				WithTopicProjectID(testProject),
+			),
+			NewPullSubscriptionWithNoDefaults(sourceName, testNS,
+				WithPullSubscriptionReady(sinkURI),
+			),
+		},
+		Key: testNS + "/" + sourceName,
+		OtherTestData: map[string]interface{}{
+			"existingSinks": []logadmin.Sink{{
+				ID:          testSinkID,
+				Filter:      testFilter,
+				Destination: testTopicResource,
+			}},
+			"expectedSinks": map[string]*logadmin.Sink{
+				testSinkID: nil,
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudAuditLogsSource(sourceName, testNS,
+				WithCloudAuditLogsSourceUID(sourceUID),
+				WithCloudAuditLogsSourceMethodName(testMethodName),
+				WithCloudAuditLogsSourceServiceName(testServiceName),
+				WithCloudAuditLogsSourceSink(sinkGVK, sinkName),
+				WithInitCloudAuditLogsSourceConditions,
+				WithCloudAuditLogsSourceSinkReady(),
+				WithCloudAuditLogsSourceTopicFailed("TopicDeleted", fmt.Sprintf("Successfully deleted Topic: %s", sourceName)),
+				WithCloudAuditLogsSourcePullSubscriptionFailed("PullSubscriptionDeleted", fmt.Sprintf("Successfully deleted PullSubscription: %s", sourceName)),
+				WithCloudAuditLogsSourceDeletionTimestamp,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "topics"}},
+				Name: sourceName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "pullsubscriptions"}},
+				Name: sourceName,
+			},
+		},
 	}, {
 		Name: "delete succeeds, sink does not exist",
 		Objects: []runtime.Object{
@@ -1340,6 +1544,7 @@ func TestAllCases(t *testing.T) {
 					r := &Reconciler{
 						PubSubBase:             intevents.NewPubSubBaseWithAdapter(ctx, controllerAgentName, receiveAdapterName, converters.CloudAuditLogsConverter, cmw),
 						Identity:               identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+						EventTypes:             eventtype.NewEventTypes(ctx),
 						auditLogsSourceLister:  listers.GetCloudAuditLogsSourceLister(),
 						logadminClientProvider: logadminClientProvider,
 						pubsubClientProvider:   gpubsub.TestClientCreator(testData["pubsub"]),
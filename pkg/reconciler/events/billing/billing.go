@@ -0,0 +1,110 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package billing
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	cloudbillingbudgetsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudbillingbudgetsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+)
+
+const (
+	resourceGroup = "cloudbillingbudgetsources.events.cloud.google.com"
+
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "CloudBillingBudgetSourceReconciled"
+)
+
+// Reconciler is the controller implementation for the CloudBillingBudgetSource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
+	// budgetLister for reading cloudbillingbudgetsources.
+	budgetLister listers.CloudBillingBudgetSourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudbillingbudgetsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, budget *v1beta1.CloudBillingBudgetSource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("budget", budget)))
+
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, budget, &budget.Status)()
+
+	budget.Status.InitializeConditions()
+	budget.Status.ObservedGeneration = budget.Generation
+	kgcpreconciler.MarkDeprecated(ctx, budget, &budget.Status, v1beta1.SchemeGroupVersion)
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if budget.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, budget.Spec.Project, budget); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+	// The topic belongs to the user, who connects their budgets to it, so
+	// only the subscription is managed.
+	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, budget, budget.Spec.Topic, resourceGroup, false)
+	if event != nil {
+		return event
+	}
+
+	if err := r.ReconcileEventTypes(ctx, budget, []eventtype.EventType{{
+		Type:        v1beta1.CloudBillingBudgetSourceThresholdExceeded,
+		Description: "This event is sent when the cost of a budget exceeds one of its alert thresholds.",
+	}, {
+		Type:        v1beta1.CloudBillingBudgetSourceUpdated,
+		Description: "This event is sent when a budget reports its current cost without exceeding a threshold.",
+	}}); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile CloudBillingBudgetSource EventTypes: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, budget.Namespace, budget.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, budget *v1beta1.CloudBillingBudgetSource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if budget.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, budget.Spec.Project, budget); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudBillingBudgetSource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package billing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	. "knative.dev/pkg/reconciler/testing"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudbillingbudgetsource"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	budgetName = "my-test-budget"
+	budgetUID  = "test-billing-budget-uid"
+	sinkName   = "sink"
+
+	testNS                                     = "testnamespace"
+	testTopicID                                = "budget-alerts"
+	generation                                 = 1
+	failedToPropagatePullSubscriptionStatusMsg = `Failed to propagate PullSubscription status`
+)
+
+var (
+	trueVal = true
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = v1beta1.AddToScheme(scheme.Scheme)
+}
+
+// Returns an ownerref for the test CloudBillingBudgetSource object
+func ownerRef() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "events.cloud.google.com/v1beta1",
+		Kind:               "CloudBillingBudgetSource",
+		Name:               budgetName,
+		UID:                budgetUID,
+		Controller:         &trueVal,
+		BlockOwnerDeletion: &trueVal,
+	}
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1beta1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1beta1",
+			Kind:       "Sink",
+			Namespace:  testNS,
+			Name:       sinkName,
+		},
+	}
+}
+
+func newPullSubscriptionSpec() inteventsv1beta1.PullSubscriptionSpec {
+	return inteventsv1beta1.PullSubscriptionSpec{
+		Topic:       testTopicID,
+		AdapterType: converters.CloudBillingBudgetConverter,
+		PubSubSpec: duckv1beta1.PubSubSpec{
+			Secret: &secret,
+			SourceSpec: duckv1.SourceSpec{
+				Sink: newSinkDestination(),
+			},
+		},
+	}
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "pullsubscription created on the budget topic",
+		Objects: []runtime.Object{
+			NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				WithCloudBillingBudgetSourceAnnotations(map[string]string{
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				WithCloudBillingBudgetSourceDefaultGCPAuth(),
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + budgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceStatusObservedGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				WithInitCloudBillingBudgetSourceConditions,
+				WithCloudBillingBudgetSourceAnnotations(map[string]string{
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				WithCloudBillingBudgetSourceDefaultGCPAuth(),
+				WithCloudBillingBudgetSourcePullSubscriptionUnknown("PullSubscriptionNotConfigured", "PullSubscription has not yet been reconciled"),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			NewPullSubscriptionWithNoDefaults(budgetName, testNS,
+				WithPullSubscriptionSpecWithNoDefaults(newPullSubscriptionSpec()),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionLabels(map[string]string{
+					"receive-adapter":                     receiveAdapterName,
+					"events.cloud.google.com/source-name": budgetName,
+				}),
+				WithPullSubscriptionAnnotations(map[string]string{
+					"metrics-resource-group":          resourceGroup,
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				WithPullSubscriptionOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+				WithPullSubscriptionDefaultGCPAuth(),
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, budgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", budgetName),
+			Eventf(corev1.EventTypeWarning, intevents.PullSubscriptionStatusPropagateFailedReason, "%s: PullSubscription %q has not yet been reconciled", failedToPropagatePullSubscriptionStatusMsg, budgetName),
+		},
+	}, {
+		Name: "pullsubscription exists and the status is false",
+		Objects: []runtime.Object{
+			NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+			),
+			NewPullSubscriptionWithNoDefaults(budgetName, testNS,
+				WithPullSubscriptionSpecWithNoDefaults(newPullSubscriptionSpec()),
+				WithPullSubscriptionReadyStatus(corev1.ConditionFalse, "PullSubscriptionFalse", "status false test message")),
+			newSink(),
+		},
+		Key: testNS + "/" + budgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceStatusObservedGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				WithInitCloudBillingBudgetSourceConditions,
+				WithCloudBillingBudgetSourcePullSubscriptionFailed("PullSubscriptionFalse", "status false test message"),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, budgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", budgetName),
+			Eventf(corev1.EventTypeWarning, intevents.PullSubscriptionStatusPropagateFailedReason, "%s: the status of PullSubscription %q is False", failedToPropagatePullSubscriptionStatusMsg, budgetName),
+		},
+	}, {
+		Name: "pullsubscription exists and ready",
+		Objects: []runtime.Object{
+			NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+			),
+			NewPullSubscriptionWithNoDefaults(budgetName, testNS,
+				WithPullSubscriptionSpecWithNoDefaults(newPullSubscriptionSpec()),
+				WithPullSubscriptionReady(sinkURI),
+				WithPullSubscriptionReadyStatus(corev1.ConditionTrue, "PullSubscriptionNoReady", ""),
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + budgetName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudBillingBudgetSource(budgetName, testNS,
+				WithCloudBillingBudgetSourceObjectMetaGeneration(generation),
+				WithCloudBillingBudgetSourceStatusObservedGeneration(generation),
+				WithCloudBillingBudgetSourceTopic(testTopicID),
+				WithCloudBillingBudgetSourceSink(sinkGVK, sinkName),
+				WithInitCloudBillingBudgetSourceConditions,
+				WithCloudBillingBudgetSourcePullSubscriptionReady(),
+				WithCloudBillingBudgetSourceSinkURI(sinkURI),
+				WithCloudBillingBudgetSourceSubscriptionID(SubscriptionID),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, budgetName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", budgetName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBillingBudgetSource reconciled: "%s/%s"`, testNS, budgetName),
+		},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase:           intevents.NewPubSubBaseWithAdapter(ctx, controllerAgentName, receiveAdapterName, converters.CloudBillingBudgetConverter, cmw),
+			Identity:             identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			EventTypes:           eventtype.NewEventTypes(ctx),
+			budgetLister:         listers.GetCloudBillingBudgetSourceLister(),
+			serviceAccountLister: listers.GetServiceAccountLister(),
+		}
+		return cloudbillingbudgetsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudBillingBudgetSourceLister(), r.Recorder, r)
+	}))
+}
@@ -31,6 +31,8 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	cloudbuildsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudbuildsource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 )
@@ -42,6 +44,7 @@ const (
 
 	createFailedReason           = "PullSubscriptionCreateFailed"
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
 	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
 	reconciledSuccessReason      = "CloudBuildSourceReconciled"
 )
@@ -52,6 +55,8 @@ type Reconciler struct {
 
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
 	// buildLister for reading cloudbuildsources.
 	buildLister listers.CloudBuildSourceLister
 	// serviceAccountLister for reading serviceAccounts.
@@ -64,8 +69,12 @@ var _ cloudbuildsourcereconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, build *v1beta1.CloudBuildSource) pkgreconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("build", build)))
 
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, build, &build.Status)()
+
 	build.Status.InitializeConditions()
 	build.Status.ObservedGeneration = build.Generation
+	kgcpreconciler.MarkDeprecated(ctx, build, &build.Status, v1beta1.SchemeGroupVersion)
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if build.Spec.ServiceAccountName != "" {
 		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, build.Spec.Project, build); err != nil {
@@ -77,6 +86,13 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, build *v1beta1.CloudBuil
 		return event
 	}
 
+	if err := r.ReconcileEventTypes(ctx, build, []eventtype.EventType{{
+		Type:        v1beta1.CloudBuildSourceEvent,
+		Description: "This event is sent when your build's state changes.",
+	}}); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile CloudBuildSource EventTypes: %s", err.Error())
+	}
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudBuildSource reconciled: "%s/%s"`, build.Namespace, build.Name)
 }
 
@@ -30,6 +30,7 @@ import (
 	cloudbuildsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudbuildsource"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
@@ -69,6 +70,7 @@ func newController(
 	r := &Reconciler{
 		PubSubBase:           intevents.NewPubSubBaseWithAdapter(ctx, controllerAgentName, receiveAdapterName, converters.CloudBuildConverter, cmw),
 		Identity:             identity.NewIdentity(ctx, ipm, gcpas),
+		EventTypes:           eventtype.NewEventTypes(ctx),
 		buildLister:          cloudbuildsourceInformer.Lister(),
 		serviceAccountLister: serviceAccountInformer.Lister(),
 	}
@@ -0,0 +1,233 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	cloudmonitoringalertsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudmonitoringalertsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/monitoring/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	resourceGroup = "cloudmonitoringalertsources.events.cloud.google.com"
+
+	deleteNotificationChannelFailed = "NotificationChannelDeleteFailed"
+	deletePubSubFailed              = "PubSubDeleteFailed"
+	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed                = "EventTypesReconcileFailed"
+	reconciledPubSubFailedReason    = "PubSubReconcileFailed"
+	reconciledFailedReason          = "NotificationChannelReconcileFailed"
+	reconciledSuccessReason         = "CloudMonitoringAlertSourceReconciled"
+	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler is the controller implementation for Google Cloud Monitoring alerts.
+type Reconciler struct {
+	*intevents.PubSubBase
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
+	// monitoringAlertLister for reading alert sources.
+	monitoringAlertLister listers.CloudMonitoringAlertSourceLister
+
+	createClientFn gmonitoring.CreateFn
+}
+
+// Check that our Reconciler implements Interface.
+var _ cloudmonitoringalertsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, src *v1beta1.CloudMonitoringAlertSource) reconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("monitoringAlert", src)))
+
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, src, &src.Status)()
+
+	src.Status.InitializeConditions()
+	src.Status.ObservedGeneration = src.Generation
+	kgcpreconciler.MarkDeprecated(ctx, src, &src.Status, v1beta1.SchemeGroupVersion)
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if src.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, src.Spec.Project, src); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	topic := resources.GenerateTopicName(src)
+	_, _, err := r.PubSubBase.ReconcilePubSub(ctx, src, topic, resourceGroup)
+	if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: %s", err.Error())
+	}
+
+	channel, err := r.reconcileNotificationChannel(ctx, src, topic)
+	if err != nil {
+		src.Status.MarkNotificationChannelNotReady(reconciledFailedReason, "Failed to reconcile CloudMonitoringAlertSource notification channel: %s", err.Error())
+		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: %s", err.Error())
+	}
+	src.Status.MarkNotificationChannelReady(channel)
+
+	eventSource := v1beta1.CloudMonitoringAlertSourceEventSource(src.Status.ProjectID)
+	if err := r.ReconcileEventTypes(ctx, src, []eventtype.EventType{{
+		Type:        v1beta1.CloudMonitoringAlertSourceRaised,
+		Source:      eventSource,
+		Description: "This event is sent when an alert policy incident is opened in Cloud Monitoring.",
+	}, {
+		Type:        v1beta1.CloudMonitoringAlertSourceResolved,
+		Source:      eventSource,
+		Description: "This event is sent when an alert policy incident is closed in Cloud Monitoring.",
+	}}); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Reconcile EventTypes failed with: %s", err.Error())
+	}
+	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, src.Namespace, src.Name)
+}
+
+// reconcileNotificationChannel makes sure a Pub/Sub notification channel publishing to topic exists and returns its
+// name. Channel names are assigned by Cloud Monitoring, so an existing channel for the topic is adopted rather than
+// creating a duplicate.
+func (r *Reconciler) reconcileNotificationChannel(ctx context.Context, src *v1beta1.CloudMonitoringAlertSource, topic string) (string, error) {
+	if src.Status.ProjectID == "" {
+		projectID, err := utils.ProjectID(src.Spec.Project, metadataClient.NewDefaultMetadataClient())
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return "", err
+		}
+		// Set the projectID in the status.
+		src.Status.ProjectID = projectID
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		return "", err
+	}
+	defer client.Close()
+
+	// Check if the channel we created before still exists.
+	if src.Status.NotificationChannel != "" {
+		channel, err := client.GetNotificationChannel(ctx, &monitoringpb.GetNotificationChannelRequest{Name: src.Status.NotificationChannel})
+		if err == nil {
+			return channel.Name, nil
+		}
+		if st, ok := gstatus.FromError(err); !ok || st.Code() != codes.NotFound {
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while retrieving notification channel", zap.String("channel", src.Status.NotificationChannel), zap.Error(err))
+			return "", err
+		}
+	}
+
+	pubsubTopic := resources.GenerateNotificationChannelTopic(src, topic)
+	it := client.ListNotificationChannels(ctx, &monitoringpb.ListNotificationChannelsRequest{
+		Name:   resources.GenerateNotificationChannelParent(src),
+		Filter: resources.GenerateNotificationChannelFilter(pubsubTopic),
+	})
+	channel, err := it.Next()
+	if err == nil {
+		return channel.Name, nil
+	}
+	if err != iterator.Done {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while listing notification channels", zap.String("topic", pubsubTopic), zap.Error(err))
+		return "", err
+	}
+
+	channel, err = client.CreateNotificationChannel(ctx, &monitoringpb.CreateNotificationChannelRequest{
+		Name: resources.GenerateNotificationChannelParent(src),
+		NotificationChannel: &monitoringpb.NotificationChannel{
+			Type:        resources.NotificationChannelType,
+			DisplayName: resources.GenerateNotificationChannelDisplayName(src),
+			Labels: map[string]string{
+				resources.NotificationChannelTopicLabel: pubsubTopic,
+			},
+		},
+	})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource notification channel", zap.String("topic", pubsubTopic), zap.Error(err))
+		return "", err
+	}
+	return channel.Name, nil
+}
+
+// deleteNotificationChannel looks at the status.NotificationChannel and if non-empty,
+// hence indicating that we have created a notification channel successfully
+// in Cloud Monitoring, remove it.
+func (r *Reconciler) deleteNotificationChannel(ctx context.Context, src *v1beta1.CloudMonitoringAlertSource) error {
+	if src.Status.NotificationChannel == "" {
+		return nil
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudMonitoringAlertSource client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	// Force removes the channel from any alert policies still referring to it.
+	err = client.DeleteNotificationChannel(ctx, &monitoringpb.DeleteNotificationChannelRequest{Name: src.Status.NotificationChannel, Force: true})
+	if err == nil {
+		logging.FromContext(ctx).Desugar().Debug("Deleted CloudMonitoringAlertSource notification channel", zap.String("channel", src.Status.NotificationChannel))
+		return nil
+	}
+	if st, ok := gstatus.FromError(err); !ok {
+		logging.FromContext(ctx).Desugar().Error("Failed from CloudMonitoringAlertSource client while deleting notification channel", zap.String("channel", src.Status.NotificationChannel), zap.Error(err))
+		return err
+	} else if st.Code() != codes.NotFound {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete CloudMonitoringAlertSource notification channel", zap.String("channel", src.Status.NotificationChannel), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, src *v1beta1.CloudMonitoringAlertSource) reconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if src.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, src.Spec.Project, src); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete CloudMonitoringAlertSource workload identity: %s", err.Error())
+		}
+	}
+
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudMonitoringAlertSource notification channel")
+	if err := r.deleteNotificationChannel(ctx, src); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteNotificationChannelFailed, "Failed to delete CloudMonitoringAlertSource notification channel: %s", err.Error())
+	}
+
+	if err := r.PubSubBase.DeletePubSub(ctx, src); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailed, "Failed to delete CloudMonitoringAlertSource PubSub: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,608 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package monitoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	monitoringpb "google.golang.org/genproto/googleapis/monitoring/v3"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	monitoringv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	. "github.com/google/knative-gcp/pkg/apis/intevents"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudmonitoringalertsource"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	gmonitoring "github.com/google/knative-gcp/pkg/gclient/monitoring/testing"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	sourceName = "my-test-alerts"
+	sourceUID  = "test-monitoring-alert-uid"
+	sinkName   = "sink"
+
+	testNS       = "testnamespace"
+	testProject  = "test-project-id"
+	testTopicURI = "http://" + sourceName + "-topic." + testNS + ".svc.cluster.local"
+	channelName  = "projects/" + testProject + "/notificationChannels/1234"
+
+	failedToReconcileTopicMsg            = `Topic has not yet been reconciled`
+	failedToReconcileNotificationChannel = `Failed to reconcile CloudMonitoringAlertSource notification channel`
+	failedToDeleteNotificationChannelMsg = `Failed to delete CloudMonitoringAlertSource notification channel`
+)
+
+var (
+	trueVal  = true
+	falseVal = false
+
+	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	testTopicID = fmt.Sprintf("cre-src_%s_%s_%s", testNS, sourceName, sourceUID)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	secret = corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "google-cloud-key",
+		},
+		Key: "key.json",
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = monitoringv1beta1.AddToScheme(scheme.Scheme)
+}
+
+// Returns an ownerref for the test CloudMonitoringAlertSource object
+func ownerRef() metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion:         "events.cloud.google.com/v1beta1",
+		Kind:               "CloudMonitoringAlertSource",
+		Name:               sourceName,
+		UID:                sourceUID,
+		Controller:         &trueVal,
+		BlockOwnerDeletion: &trueVal,
+	}
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1beta1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"hostname": sinkDNS,
+				},
+			},
+		},
+	}
+}
+
+func newSinkDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "testing.cloud.google.com/v1beta1",
+			Kind:       "Sink",
+			Name:       sinkName,
+		},
+	}
+}
+
+func newReadyTopic() *inteventsv1beta1.Topic {
+	return NewTopic(sourceName, testNS,
+		WithTopicSpec(inteventsv1beta1.TopicSpec{
+			Topic:             testTopicID,
+			PropagationPolicy: "CreateDelete",
+			Project:           testProject,
+			EnablePublisher:   &falseVal,
+		}),
+		WithTopicReady(testTopicID),
+		WithTopicAddress(testTopicURI),
+		WithTopicProjectID(testProject),
+	)
+}
+
+func newReadyPullSubscription() *inteventsv1beta1.PullSubscription {
+	return NewPullSubscriptionWithNoDefaults(sourceName, testNS,
+		WithPullSubscriptionReady(sinkURI),
+		WithPullSubscriptionSpecWithNoDefaults(inteventsv1beta1.PullSubscriptionSpec{
+			Topic: testTopicID,
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Secret: &secret,
+				SourceSpec: duckv1.SourceSpec{
+					Sink: newSinkDestination(),
+				},
+				Project: testProject,
+			},
+		}),
+	)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "topic created, not ready",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithCloudMonitoringAlertSourceAnnotations(map[string]string{
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+			),
+			newSink(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceAnnotations(map[string]string{
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				WithCloudMonitoringAlertSourceTopicUnknown("TopicNotConfigured", failedToReconcileTopicMsg),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			NewTopic(sourceName, testNS,
+				WithTopicSpec(inteventsv1beta1.TopicSpec{
+					Topic:             testTopicID,
+					PropagationPolicy: "CreateDelete",
+					EnablePublisher:   &falseVal,
+				}),
+				WithTopicLabels(map[string]string{
+					"receive-adapter": receiveAdapterName,
+					SourceLabelKey:    sourceName,
+				}),
+				WithTopicAnnotations(map[string]string{
+					duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+				}),
+				WithTopicOwnerReferences([]metav1.OwnerReference{ownerRef()}),
+			),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Reconcile PubSub failed with: Topic %q has not yet been reconciled", sourceName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create client fails",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				CreateClientErr: errors.New("create-client-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: %s", failedToReconcileNotificationChannel, "create-client-induced-error")),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: create-client-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, list notification channels fails",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				ListNotificationChannelsErr: gstatus.Error(codes.Unknown, "list-channels-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileNotificationChannel, codes.Unknown, "list-channels-induced-error")),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.Unknown, "list-channels-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create notification channel fails",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				CreateNotificationChannelErr: gstatus.Error(codes.PermissionDenied, "create-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileNotificationChannel, codes.PermissionDenied, "create-channel-induced-error")),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.PermissionDenied, "create-channel-induced-error"),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, create notification channel succeeds",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				CreatedNotificationChannel: channelName,
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "topic and pullsubscription exist and ready, notification channel for the topic already exists",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				NotificationChannels: []*monitoringpb.NotificationChannel{{Name: channelName}},
+				// Adopting the existing channel must not create another one.
+				CreateNotificationChannelErr: errors.New("unexpected create"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "notification channel in status exists",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				ListNotificationChannelsErr: errors.New("unexpected list"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "notification channel in status was deleted, recreated",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				GetNotificationChannelErr:  gstatus.Error(codes.NotFound, "get-channel-induced-error"),
+				CreatedNotificationChannel: channelName + "5",
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName+"5"),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudMonitoringAlertSource reconciled: "%s/%s"`, testNS, sourceName),
+		},
+	}, {
+		Name: "notification channel in status fails to get with grpc unknown error",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				GetNotificationChannelErr: gstatus.Error(codes.Unknown, "get-channel-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithCloudMonitoringAlertSourceNotificationChannel(channelName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceSubscriptionID(SubscriptionID),
+				WithCloudMonitoringAlertSourceNotificationChannelNotReady(reconciledFailedReason, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToReconcileNotificationChannel, codes.Unknown, "get-channel-induced-error")),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI)),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, true),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile NotificationChannel failed with: rpc error: code = %s desc = %s", codes.Unknown, "get-channel-induced-error"),
+		},
+	}, {
+		Name: "notification channel fails to delete with Unknown grpc error",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key:               testNS + "/" + sourceName,
+		WantStatusUpdates: nil,
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.Unknown, "delete-channel-induced-error"),
+			},
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, deleteNotificationChannelFailed, fmt.Sprintf("%s: rpc error: code = %s desc = %s", failedToDeleteNotificationChannelMsg, codes.Unknown, "delete-channel-induced-error")),
+		},
+	}, {
+		Name: "source successfully deleted with NotFound grpc error",
+		Objects: []runtime.Object{
+			NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceTopicReady(testTopicID, testProject),
+				WithCloudMonitoringAlertSourcePullSubscriptionReady(),
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceSinkURI(sinkURI),
+				WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+			newReadyTopic(),
+			newReadyPullSubscription(),
+			newSink(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewCloudMonitoringAlertSource(sourceName, testNS,
+				WithCloudMonitoringAlertSourceProject(testProject),
+				WithCloudMonitoringAlertSourceSink(sinkGVK, sinkName),
+				WithInitCloudMonitoringAlertSourceConditions,
+				WithCloudMonitoringAlertSourceNotificationChannelReady(channelName),
+				WithCloudMonitoringAlertSourceTopicFailed("TopicDeleted", fmt.Sprintf("Successfully deleted Topic: %s", sourceName)),
+				WithCloudMonitoringAlertSourcePullSubscriptionFailed("PullSubscriptionDeleted", fmt.Sprintf("Successfully deleted PullSubscription: %s", sourceName)),
+				WithCloudMonitoringAlertSourceDeletionTimestamp,
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "topics"}},
+				Name: sourceName,
+			},
+			{ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "pullsubscriptions"}},
+				Name: sourceName,
+			},
+		},
+		OtherTestData: map[string]interface{}{
+			"monitoring": gmonitoring.TestClientData{
+				DeleteNotificationChannelErr: gstatus.Error(codes.NotFound, "delete-channel-induced-error"),
+			},
+		},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, testData map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			PubSubBase:            intevents.NewPubSubBase(ctx, controllerAgentName, receiveAdapterName, cmw),
+			Identity:              identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			EventTypes:            eventtype.NewEventTypes(ctx),
+			monitoringAlertLister: listers.GetCloudMonitoringAlertSourceLister(),
+			createClientFn:        gmonitoring.TestClientCreator(testData["monitoring"]),
+		}
+		return cloudmonitoringalertsource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetCloudMonitoringAlertSourceLister(), r.Recorder, r)
+	}))
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils/naming"
+)
+
+const (
+	// NotificationChannelType is the Cloud Monitoring notification channel type that publishes to Pub/Sub.
+	NotificationChannelType = "pubsub"
+	// NotificationChannelTopicLabel is the notification channel label holding the Pub/Sub topic.
+	NotificationChannelTopicLabel = "topic"
+)
+
+// GenerateTopicName generates a topic name for the alert source. This refers to the underlying Pub/Sub topic, and not
+// our Topic resource.
+func GenerateTopicName(src *v1beta1.CloudMonitoringAlertSource) string {
+	return naming.TruncatedPubsubResourceName("cre-src", src.Namespace, src.Name, src.UID)
+}
+
+// GenerateNotificationChannelTopic generates the fully qualified Pub/Sub topic the notification channel publishes to.
+func GenerateNotificationChannelTopic(src *v1beta1.CloudMonitoringAlertSource, topic string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", src.Status.ProjectID, topic)
+}
+
+// GenerateNotificationChannelParent generates the parent under which the notification channel is created,
+// like this: projects/PROJECT_ID.
+func GenerateNotificationChannelParent(src *v1beta1.CloudMonitoringAlertSource) string {
+	return fmt.Sprintf("projects/%s", src.Status.ProjectID)
+}
+
+// GenerateNotificationChannelFilter generates the filter used to look up a notification channel already
+// publishing to the given fully qualified topic.
+func GenerateNotificationChannelFilter(topic string) string {
+	return fmt.Sprintf(`type = %q AND labels.%s = %q`, NotificationChannelType, NotificationChannelTopicLabel, topic)
+}
+
+// GenerateNotificationChannelDisplayName generates the display name of the notification channel.
+func GenerateNotificationChannelDisplayName(src *v1beta1.CloudMonitoringAlertSource) string {
+	return fmt.Sprintf("knative-gcp %s/%s", src.Namespace, src.Name)
+}
@@ -32,6 +32,7 @@ import (
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/pullsubscription"
 	cloudpubsubsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudpubsubsource"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
@@ -71,6 +72,7 @@ func newController(
 	r := &Reconciler{
 		PubSubBase:   intevents.NewPubSubBase(ctx, controllerAgentName, receiveAdapterName, cmw),
 		Identity:     identity.NewIdentity(ctx, ipm, gcpas),
+		EventTypes:   eventtype.NewEventTypes(ctx),
 		pubsubLister: cloudpubsubsourceInformer.Lister(),
 	}
 	impl := cloudpubsubsourcereconciler.NewImpl(ctx, r)
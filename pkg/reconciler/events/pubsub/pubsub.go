@@ -27,6 +27,8 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	cloudpubsubsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudpubsubsource"
 	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 )
@@ -35,6 +37,7 @@ const (
 	resourceGroup = "cloudpubsubsources.events.cloud.google.com"
 
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
 	reconciledSuccessReason      = "CloudPubSubSourceReconciled"
 	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
 )
@@ -44,6 +47,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
 	// pubsubLister for reading cloudpubsubsources.
 	pubsubLister listers.CloudPubSubSourceLister
 }
@@ -54,8 +59,12 @@ var _ cloudpubsubsourcereconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, pubsub *v1beta1.CloudPubSubSource) pkgreconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("pubsub", pubsub)))
 
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, pubsub, &pubsub.Status)()
+
 	pubsub.Status.InitializeConditions()
 	pubsub.Status.ObservedGeneration = pubsub.Generation
+	kgcpreconciler.MarkDeprecated(ctx, pubsub, &pubsub.Status, v1beta1.SchemeGroupVersion)
 
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if pubsub.Spec.ServiceAccountName != "" {
@@ -68,6 +77,13 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, pubsub *v1beta1.CloudPub
 	if event != nil {
 		return event
 	}
+
+	if err := r.ReconcileEventTypes(ctx, pubsub, []eventtype.EventType{{
+		Type:        v1beta1.CloudPubSubSourcePublish,
+		Description: "This event is sent when a message is published to a Cloud Pub/Sub topic.",
+	}}); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile CloudPubSubSource EventTypes: %s", err.Error())
+	}
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudPubSubSource reconciled: "%s/%s"`, pubsub.Namespace, pubsub.Name)
 }
 
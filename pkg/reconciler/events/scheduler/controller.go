@@ -24,6 +24,7 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
@@ -74,6 +75,7 @@ func newController(
 	c := &Reconciler{
 		PubSubBase:      intevents.NewPubSubBase(ctx, controllerAgentName, receiveAdapterName, cmw),
 		Identity:        identity.NewIdentity(ctx, ipm, gcpas),
+		EventTypes:      eventtype.NewEventTypes(ctx),
 		schedulerLister: cloudschedulersourceInformer.Lister(),
 		createClientFn:  gscheduler.NewClient,
 	}
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// MakeRetryConfig generates the RetryConfig of the CloudSchedulerSource job.
+// It returns nil if the CloudSchedulerSource doesn't configure retries, which
+// leaves the Cloud Scheduler defaults in place.
+func MakeRetryConfig(scheduler *v1beta1.CloudSchedulerSource) *schedulerpb.RetryConfig {
+	rc := scheduler.Spec.RetryConfig
+	if rc == nil {
+		return nil
+	}
+	config := &schedulerpb.RetryConfig{
+		RetryCount: rc.RetryCount,
+	}
+	if rc.MaxBackoff != nil {
+		// MaxBackoff was validated by the webhook.
+		if d, err := time.ParseDuration(*rc.MaxBackoff); err == nil {
+			config.MaxBackoffDuration = ptypes.DurationProto(d)
+		}
+	}
+	return config
+}
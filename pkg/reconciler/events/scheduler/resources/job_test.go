@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-cmp/cmp"
+	schedulerpb "google.golang.org/genproto/googleapis/cloud/scheduler/v1"
+	"google.golang.org/protobuf/testing/protocmp"
+	"knative.dev/pkg/ptr"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+func TestMakeRetryConfig(t *testing.T) {
+	testCases := map[string]struct {
+		rc   *v1beta1.SchedulerRetryConfig
+		want *schedulerpb.RetryConfig
+	}{
+		"no retry config": {},
+		"retry count only": {
+			rc:   &v1beta1.SchedulerRetryConfig{RetryCount: 3},
+			want: &schedulerpb.RetryConfig{RetryCount: 3},
+		},
+		"retry count and max backoff": {
+			rc: &v1beta1.SchedulerRetryConfig{
+				RetryCount: 5,
+				MaxBackoff: ptr.String("10m"),
+			},
+			want: &schedulerpb.RetryConfig{
+				RetryCount:         5,
+				MaxBackoffDuration: ptypes.DurationProto(10 * time.Minute),
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := MakeRetryConfig(&v1beta1.CloudSchedulerSource{
+				Spec: v1beta1.CloudSchedulerSourceSpec{RetryConfig: tc.rc},
+			})
+			if diff := cmp.Diff(tc.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("unexpected RetryConfig (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
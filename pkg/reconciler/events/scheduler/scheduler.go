@@ -34,7 +34,9 @@ import (
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	gscheduler "github.com/google/knative-gcp/pkg/gclient/scheduler"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/scheduler/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	"github.com/google/knative-gcp/pkg/utils"
@@ -46,6 +48,7 @@ const (
 	deleteJobFailed              = "JobDeleteFailed"
 	deletePubSubFailed           = "PubSubDeleteFailed"
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
 	reconciledPubSubFailedReason = "PubSubReconcileFailed"
 	reconciledFailedReason       = "JobReconcileFailed"
 	reconciledSuccessReason      = "CloudSchedulerSourceReconciled"
@@ -57,6 +60,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
 	// schedulerLister for reading schedulers.
 	schedulerLister listers.CloudSchedulerSourceLister
 
@@ -69,8 +74,12 @@ var _ cloudschedulersourcereconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, scheduler *v1beta1.CloudSchedulerSource) reconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("scheduler", scheduler)))
 
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, scheduler, &scheduler.Status)()
+
 	scheduler.Status.InitializeConditions()
 	scheduler.Status.ObservedGeneration = scheduler.Generation
+	kgcpreconciler.MarkDeprecated(ctx, scheduler, &scheduler.Status, v1beta1.SchemeGroupVersion)
 
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if scheduler.Spec.ServiceAccountName != "" {
@@ -92,6 +101,14 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, scheduler *v1beta1.Cloud
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledFailedReason, "Reconcile Job failed with: %s", err.Error())
 	}
 	scheduler.Status.MarkJobReady(jobName)
+
+	if err := r.ReconcileEventTypes(ctx, scheduler, []eventtype.EventType{{
+		Type:        v1beta1.CloudSchedulerSourceExecute,
+		Source:      v1beta1.CloudSchedulerSourceEventSource(resources.ExtractParentName(jobName), scheduler.Name),
+		Description: "This event is sent when a job is executed in Cloud Scheduler.",
+	}}); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Reconcile EventTypes failed with: %s", err.Error())
+	}
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudSchedulerSource reconciled: "%s/%s"`, scheduler.Namespace, scheduler.Name)
 }
 
@@ -139,7 +156,9 @@ func (r *Reconciler) reconcileJob(ctx context.Context, scheduler *v1beta1.CloudS
 							Attributes: customAttributes,
 						},
 					},
-					Schedule: scheduler.Spec.Schedule,
+					Schedule:    scheduler.Spec.Schedule,
+					TimeZone:    scheduler.Spec.TimeZone,
+					RetryConfig: resources.MakeRetryConfig(scheduler),
 				},
 			})
 			if err != nil {
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretmanager
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	secretmanagerrotationsourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/secretmanagerrotationsource"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents"
+)
+
+const (
+	resourceGroup = "secretmanagerrotationsources.events.cloud.google.com"
+
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
+	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+	reconciledSuccessReason      = "SecretManagerRotationSourceReconciled"
+)
+
+// Reconciler is the controller implementation for the SecretManagerRotationSource source.
+type Reconciler struct {
+	*intevents.PubSubBase
+
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
+	// rotationLister for reading secretmanagerrotationsources.
+	rotationLister listers.SecretManagerRotationSourceLister
+	// serviceAccountLister for reading serviceAccounts.
+	serviceAccountLister corev1listers.ServiceAccountLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ secretmanagerrotationsourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, rotation *v1beta1.SecretManagerRotationSource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("rotation", rotation)))
+
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, rotation, &rotation.Status)()
+
+	rotation.Status.InitializeConditions()
+	rotation.Status.ObservedGeneration = rotation.Generation
+	kgcpreconciler.MarkDeprecated(ctx, rotation, &rotation.Status, v1beta1.SchemeGroupVersion)
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if rotation.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, rotation.Spec.Project, rotation); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile SecretManagerRotationSource workload identity: %s", err.Error())
+		}
+	}
+	// The topic belongs to the user, who connects their secrets to it, so
+	// only the subscription is managed.
+	_, event := r.PubSubBase.ReconcilePullSubscription(ctx, rotation, rotation.Spec.Topic, resourceGroup, false)
+	if event != nil {
+		return event
+	}
+
+	if err := r.ReconcileEventTypes(ctx, rotation, []eventtype.EventType{{
+		Type:        v1beta1.SecretManagerRotationSourceSecretCreated,
+		Description: "This event is sent when a secret is created.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceSecretUpdated,
+		Description: "This event is sent when the metadata of a secret is updated.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceSecretDeleted,
+		Description: "This event is sent when a secret is deleted.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceSecretRotate,
+		Description: "This event is sent when a secret is due for rotation.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceVersionAdded,
+		Description: "This event is sent when a new version is added to a secret.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceVersionEnabled,
+		Description: "This event is sent when a secret version is enabled.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceVersionDisabled,
+		Description: "This event is sent when a secret version is disabled.",
+	}, {
+		Type:        v1beta1.SecretManagerRotationSourceVersionDestroyed,
+		Description: "This event is sent when a secret version is destroyed.",
+	}}); err != nil {
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile SecretManagerRotationSource EventTypes: %s", err.Error())
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `SecretManagerRotationSource reconciled: "%s/%s"`, rotation.Namespace, rotation.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, rotation *v1beta1.SecretManagerRotationSource) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if rotation.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, rotation.Spec.Project, rotation); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete SecretManagerRotationSource workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
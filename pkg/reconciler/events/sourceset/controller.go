@@ -0,0 +1,74 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceset
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	sourcesetinformers "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1alpha1/sourceset"
+	cloudpubsubsourceinformers "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1beta1/cloudpubsubsource"
+	namespaceinformers "github.com/google/knative-gcp/pkg/client/injection/kube/informers/core/v1/namespace"
+	sourcesetreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/sourceset"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "cloud-run-events-sourceset-controller"
+)
+
+// NewController initializes the controller and is called by the generated code.
+// Registers event handlers to enqueue events.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	sourceSetInformer := sourcesetinformers.Get(ctx)
+	cloudpubsubsourceInformer := cloudpubsubsourceinformers.Get(ctx)
+	namespaceInformer := namespaceinformers.Get(ctx)
+
+	r := &Reconciler{
+		Base:            reconciler.NewBase(ctx, controllerAgentName, cmw),
+		namespaceLister: namespaceInformer.Lister(),
+		sourceLister:    cloudpubsubsourceInformer.Lister(),
+	}
+	impl := sourcesetreconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+	sourceSetInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
+
+	// Sources created by a SourceSet carry its name in a label, as the
+	// SourceSet is cluster scoped and the sources are not.
+	cloudpubsubsourceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGK(v1alpha1.Kind("SourceSet")),
+		Handler:    controller.HandleAll(impl.EnqueueLabelOfClusterScopedResource(v1alpha1.SourceSetLabelKey)),
+	})
+
+	// Any namespace change may change which namespaces a SourceSet selects.
+	namespaceInformer.Informer().AddEventHandler(controller.HandleAll(func(interface{}) {
+		impl.GlobalResync(sourceSetInformer.Informer())
+	}))
+
+	return impl
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// SourceName returns the name of the CloudPubSubSource the SourceSet creates
+// for the given topic. Topic IDs may contain characters that are not allowed
+// in a Kubernetes name, so those are replaced, and a hash of the original
+// topic is appended to keep names of distinct topics distinct.
+func SourceName(sourceSetName, topic string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, topic)
+	if sanitized != topic {
+		h := md5.Sum([]byte(topic))
+		sanitized = fmt.Sprintf("%s-%x", sanitized, h[:4])
+	}
+	return kmeta.ChildName(sourceSetName, "-"+sanitized)
+}
+
+// MakeSource creates the spec for, but does not create, the CloudPubSubSource
+// the SourceSet manages for the given topic in the given namespace.
+func MakeSource(s *v1alpha1.SourceSet, namespace, topic string) *v1beta1.CloudPubSubSource {
+	labels := make(map[string]string, len(s.Spec.Template.Labels)+1)
+	for k, v := range s.Spec.Template.Labels {
+		labels[k] = v
+	}
+	labels[v1alpha1.SourceSetLabelKey] = s.Name
+
+	annotations := make(map[string]string, len(s.Spec.Template.Annotations)+1)
+	for k, v := range s.Spec.Template.Annotations {
+		annotations[k] = v
+	}
+	annotations[v1alpha1.SourceSetTopicAnnotationKey] = topic
+
+	spec := s.Spec.Template.Spec.DeepCopy()
+	spec.Topic = topic
+
+	return &v1beta1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            SourceName(s.Name, topic),
+			Namespace:       namespace,
+			Labels:          labels,
+			Annotations:     annotations,
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(s)},
+		},
+		Spec: *spec,
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+func TestSourceName(t *testing.T) {
+	testCases := map[string]struct {
+		topic string
+		want  string
+	}{
+		"valid name": {
+			topic: "my-topic",
+			want:  "set-my-topic",
+		},
+		"upper case and dots": {
+			topic: "My.Topic",
+			want:  "set-my-topic-fe6fee6d",
+		},
+		"differs only in case": {
+			topic: "my-Topic",
+			want:  "set-my-topic-2c5e6974",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := SourceName("set", tc.topic); got != tc.want {
+				t.Errorf("SourceName(%q) = %q, want %q", tc.topic, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMakeSource(t *testing.T) {
+	trueVal := true
+	s := &v1alpha1.SourceSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "set",
+			UID:  "set-uid",
+		},
+		Spec: v1alpha1.SourceSetSpec{
+			Topics: []string{"my-topic"},
+			Template: v1alpha1.SourceTemplate{
+				Labels:      map[string]string{"team": "a"},
+				Annotations: map[string]string{"note": "b"},
+			},
+		},
+	}
+	s.Spec.Template.Spec.Sink = duckv1.Destination{
+		Ref: &duckv1.KReference{APIVersion: "v1", Kind: "Service", Name: "sink"},
+	}
+
+	got := MakeSource(s, "ns", "my-topic")
+
+	want := &v1beta1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "set-my-topic",
+			Namespace: "ns",
+			Labels: map[string]string{
+				"team":                     "a",
+				v1alpha1.SourceSetLabelKey: "set",
+			},
+			Annotations: map[string]string{
+				"note":                               "b",
+				v1alpha1.SourceSetTopicAnnotationKey: "my-topic",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "events.cloud.google.com/v1alpha1",
+				Kind:               "SourceSet",
+				Name:               "set",
+				UID:                "set-uid",
+				Controller:         &trueVal,
+				BlockOwnerDeletion: &trueVal,
+			}},
+		},
+		Spec: v1beta1.CloudPubSubSourceSpec{
+			Topic: "my-topic",
+		},
+	}
+	want.Spec.Sink = s.Spec.Template.Spec.Sink
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected source (-want, +got) = %v", diff)
+	}
+	if s.Spec.Template.Spec.Topic != "" {
+		t.Errorf("MakeSource modified the SourceSet template")
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	sourcesetreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/sourceset"
+	listers "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/sourceset/resources"
+)
+
+const (
+	reconciledSuccessReason = "SourceSetReconciled"
+	sourceCreateFailed      = "SourceCreateFailed"
+	sourceUpdateFailed      = "SourceUpdateFailed"
+	sourceDeleteFailed      = "SourceDeleteFailed"
+	namespaceSelectorFailed = "NamespaceSelectorInvalid"
+)
+
+// Reconciler implements controller.Reconciler for SourceSet resources.
+type Reconciler struct {
+	*reconciler.Base
+
+	// namespaceLister for finding the namespaces selected by a SourceSet.
+	namespaceLister corev1listers.NamespaceLister
+	// sourceLister for reading the sources created by a SourceSet.
+	sourceLister listers.CloudPubSubSourceLister
+}
+
+// Check that our Reconciler implements Interface.
+var _ sourcesetreconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, s *v1alpha1.SourceSet) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("sourceset", s)))
+
+	s.Status.InitializeConditions()
+	s.Status.ObservedGeneration = s.Generation
+
+	selector, err := metav1.LabelSelectorAsSelector(s.Spec.NamespaceSelector)
+	if err != nil {
+		s.Status.MarkSourcesFailed(namespaceSelectorFailed, "Invalid namespace selector: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, namespaceSelectorFailed, "Invalid namespace selector: %s", err.Error())
+	}
+	namespaces, err := r.namespaceLister.List(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	// Sort the namespaces so that sources are created in a stable order.
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+
+	var keys []string
+	desired := make(map[string]*v1beta1.CloudPubSubSource)
+	for _, ns := range namespaces {
+		if ns.DeletionTimestamp != nil {
+			continue
+		}
+		for _, topic := range s.Spec.Topics {
+			src := resources.MakeSource(s, ns.Name, topic)
+			key := src.Namespace + "/" + src.Name
+			keys = append(keys, key)
+			desired[key] = src
+		}
+	}
+
+	existing, err := r.sourceLister.List(labels.SelectorFromSet(map[string]string{v1alpha1.SourceSetLabelKey: s.Name}))
+	if err != nil {
+		return fmt.Errorf("failed to list sources: %w", err)
+	}
+
+	var managed, ready int32
+	for _, src := range existing {
+		if !metav1.IsControlledBy(src, s) {
+			continue
+		}
+		key := src.Namespace + "/" + src.Name
+		want, ok := desired[key]
+		if !ok {
+			if err := r.deleteSource(ctx, src); err != nil {
+				s.Status.MarkSourcesFailed(sourceDeleteFailed, "Failed to delete source %q: %s", key, err.Error())
+				return pkgreconciler.NewEvent(corev1.EventTypeWarning, sourceDeleteFailed, "Failed to delete source %q: %s", key, err.Error())
+			}
+			continue
+		}
+		delete(desired, key)
+		managed++
+		if !equality.Semantic.DeepDerivative(want.Spec, src.Spec) {
+			if src, err = r.updateSource(ctx, src, want); err != nil {
+				s.Status.MarkSourcesFailed(sourceUpdateFailed, "Failed to update source %q: %s", key, err.Error())
+				return pkgreconciler.NewEvent(corev1.EventTypeWarning, sourceUpdateFailed, "Failed to update source %q: %s", key, err.Error())
+			}
+		}
+		if src.Status.IsReady() {
+			ready++
+		}
+	}
+
+	for _, key := range keys {
+		src, ok := desired[key]
+		if !ok {
+			continue
+		}
+		if _, err := r.RunClientSet.EventsV1beta1().CloudPubSubSources(src.Namespace).Create(src); err != nil && !apierrs.IsAlreadyExists(err) {
+			s.Status.MarkSourcesFailed(sourceCreateFailed, "Failed to create source %q: %s", key, err.Error())
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, sourceCreateFailed, "Failed to create source %q: %s", key, err.Error())
+		}
+		managed++
+	}
+
+	if ready == managed {
+		s.Status.MarkSourcesReady(managed)
+	} else {
+		s.Status.MarkSourcesNotReady(managed, ready)
+	}
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, s.Name)
+}
+
+func (r *Reconciler) updateSource(ctx context.Context, existing, desired *v1beta1.CloudPubSubSource) (*v1beta1.CloudPubSubSource, error) {
+	// Don't modify the informers copy.
+	src := existing.DeepCopy()
+	src.Spec = desired.Spec
+	logging.FromContext(ctx).Desugar().Debug("Updating CloudPubSubSource", zap.Any("source", src))
+	return r.RunClientSet.EventsV1beta1().CloudPubSubSources(src.Namespace).Update(src)
+}
+
+func (r *Reconciler) deleteSource(ctx context.Context, src *v1beta1.CloudPubSubSource) error {
+	logging.FromContext(ctx).Desugar().Debug("Deleting CloudPubSubSource", zap.String("namespace", src.Namespace), zap.String("name", src.Name))
+	err := r.RunClientSet.EventsV1beta1().CloudPubSubSources(src.Namespace).Delete(src.Name, &metav1.DeleteOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sourceset
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1alpha1/sourceset"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/sourceset/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	sourceSetName = "my-sourceset"
+	sinkName      = "sink"
+	testTopic1    = "topic-1"
+	testTopic2    = "topic-2"
+)
+
+var (
+	selected = map[string]string{"team": "a"}
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	sourcesGVR = schema.GroupVersionResource{
+		Group:    "events.cloud.google.com",
+		Version:  "v1beta1",
+		Resource: "cloudpubsubsources",
+	}
+)
+
+func newSourceSet(so ...SourceSetOption) *v1alpha1.SourceSet {
+	return NewSourceSet(sourceSetName, append([]SourceSetOption{
+		WithSourceSetNamespaceSelector(selected),
+		WithSourceSetTopics(testTopic1),
+		WithSourceSetSink(sinkGVK, sinkName),
+	}, so...)...)
+}
+
+func newSource(s *v1alpha1.SourceSet, namespace, topic string, ready bool) *v1beta1.CloudPubSubSource {
+	src := resources.MakeSource(s, namespace, topic)
+	src.Status.InitializeConditions()
+	if ready {
+		src.Status.MarkPullSubscriptionReady(src.ConditionSet())
+	}
+	return src
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "sources created in selected namespaces",
+		Objects: []runtime.Object{
+			newSourceSet(),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+			NewNamespace("ns-2", WithNamespaceLabeled(selected)),
+			NewNamespace("ns-3", WithNamespaceLabeled(map[string]string{"team": "b"})),
+			NewNamespace("ns-4", WithNamespaceLabeled(selected), WithNamespaceDeleted),
+		},
+		Key: sourceSetName,
+		// Sources are created in the selected namespaces, not in the
+		// namespace of the cluster scoped SourceSet.
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeSource(newSourceSet(), "ns-1", testTopic1),
+			resources.MakeSource(newSourceSet(), "ns-2", testTopic1),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesNotReady(2, 0),
+			),
+		}},
+	}, {
+		Name: "all sources ready",
+		Objects: []runtime.Object{
+			newSourceSet(WithSourceSetTopics(testTopic1, testTopic2)),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+			newSource(newSourceSet(), "ns-1", testTopic1, true),
+			newSource(newSourceSet(), "ns-1", testTopic2, true),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithSourceSetTopics(testTopic1, testTopic2),
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesReady(2),
+			),
+		}},
+	}, {
+		Name: "source updated when the template changes",
+		Objects: []runtime.Object{
+			newSourceSet(),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+			newSource(newSourceSet(WithSourceSetSink(sinkGVK, "old-sink")), "ns-1", testTopic1, true),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSource(newSourceSet(), "ns-1", testTopic1, true),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesReady(1),
+			),
+		}},
+	}, {
+		Name: "source deleted when its topic is removed",
+		Objects: []runtime.Object{
+			newSourceSet(),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+			newSource(newSourceSet(), "ns-1", testTopic1, true),
+			newSource(newSourceSet(), "ns-1", testTopic2, true),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{Namespace: "ns-1", Verb: "delete", Resource: sourcesGVR},
+			Name:       resources.SourceName(sourceSetName, testTopic2),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesReady(1),
+			),
+		}},
+	}, {
+		Name: "source deleted when its namespace is no longer selected",
+		Objects: []runtime.Object{
+			newSourceSet(),
+			NewNamespace("ns-1", WithNamespaceLabeled(map[string]string{"team": "b"})),
+			newSource(newSourceSet(), "ns-1", testTopic1, true),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{Namespace: "ns-1", Verb: "delete", Resource: sourcesGVR},
+			Name:       resources.SourceName(sourceSetName, testTopic1),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesReady(0),
+			),
+		}},
+	}, {
+		Name: "source not controlled by the sourceset is left alone",
+		Objects: []runtime.Object{
+			newSourceSet(WithSourceSetTopics(testTopic2)),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+			func() runtime.Object {
+				src := newSource(newSourceSet(), "ns-1", testTopic1, true)
+				src.OwnerReferences = nil
+				return src
+			}(),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `SourceSet reconciled: "%s"`, sourceSetName),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeSource(newSourceSet(), "ns-1", testTopic2),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithSourceSetTopics(testTopic2),
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesNotReady(1, 0),
+			),
+		}},
+	}, {
+		Name: "source create fails",
+		Objects: []runtime.Object{
+			newSourceSet(),
+			NewNamespace("ns-1", WithNamespaceLabeled(selected)),
+		},
+		Key:                     sourceSetName,
+		SkipNamespaceValidation: true,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("create", "cloudpubsubsources"),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, sourceCreateFailed, `Failed to create source "ns-1/%s": inducing failure for create cloudpubsubsources`,
+				resources.SourceName(sourceSetName, testTopic1)),
+		},
+		WantCreates: []runtime.Object{
+			resources.MakeSource(newSourceSet(), "ns-1", testTopic1),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSourceSet(
+				WithInitSourceSetConditions,
+				WithSourceSetSourcesFailed(sourceCreateFailed,
+					`Failed to create source "ns-1/`+resources.SourceName(sourceSetName, testTopic1)+`": inducing failure for create cloudpubsubsources`),
+			),
+		}},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			Base:            reconciler.NewBase(ctx, controllerAgentName, cmw),
+			namespaceLister: listers.GetNamespaceLister(),
+			sourceLister:    listers.GetCloudPubSubSourceLister(),
+		}
+		return sourceset.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetSourceSetLister(), r.Recorder, r)
+	}))
+}
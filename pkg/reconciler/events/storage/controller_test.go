@@ -25,6 +25,7 @@ import (
 
 	iamtesting "github.com/google/knative-gcp/pkg/reconciler/testing"
 
+	_ "knative.dev/eventing/pkg/client/injection/client/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/batch/v1/job/fake"
 	_ "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount/fake"
 
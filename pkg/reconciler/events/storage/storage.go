@@ -18,6 +18,10 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"go.uber.org/zap"
 
@@ -35,7 +39,9 @@ import (
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/events/storage/resources"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	"github.com/google/knative-gcp/pkg/utils"
@@ -46,11 +52,15 @@ const (
 
 	deleteNotificationFailed     = "NotificationDeleteFailed"
 	deletePubSubFailed           = "PubSubDeleteFailed"
+	eventTypesFailed             = "EventTypesReconcileFailed"
 	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
 	reconciledNotificationFailed = "NotificationReconcileFailed"
 	reconciledPubSubFailed       = "PubSubReconcileFailed"
 	reconciledSuccessReason      = "CloudStorageSourceReconciled"
 	workloadIdentityFailed       = "WorkloadIdentityReconcileFailed"
+
+	// storageSchema is the schema of the data of the storage source CloudEvents.
+	storageSchema = "https://raw.githubusercontent.com/google/knative-gcp/master/schemas/storage/schema.json"
 )
 
 var (
@@ -61,6 +71,14 @@ var (
 		v1beta1.CloudStorageSourceDelete:         "OBJECT_DELETE",
 		v1beta1.CloudStorageSourceMetadataUpdate: "OBJECT_METADATA_UPDATE",
 	}
+
+	// Descriptions of the storage source CloudEvent types registered as EventTypes.
+	storageEventTypeDescriptions = map[string]string{
+		v1beta1.CloudStorageSourceFinalize:       "Sent when a new object (or a new generation of an existing object) is successfully created in the bucket.",
+		v1beta1.CloudStorageSourceArchive:        "Sent when the live version of an object has become an archived version.",
+		v1beta1.CloudStorageSourceDelete:         "Sent when an object has been permanently deleted.",
+		v1beta1.CloudStorageSourceMetadataUpdate: "Sent when the metadata of an existing object changes.",
+	}
 )
 
 // Reconciler is the controller implementation for Google Cloud Storage (GCS) event
@@ -69,6 +87,8 @@ type Reconciler struct {
 	*intevents.PubSubBase
 	// identity reconciler for reconciling workload identity.
 	*identity.Identity
+	// EventTypes for registering the event types of the source.
+	*eventtype.EventTypes
 	// storageLister for reading storages.
 	storageLister listers.CloudStorageSourceLister
 
@@ -83,8 +103,12 @@ var _ cloudstoragesourcereconciler.Interface = (*Reconciler)(nil)
 func (r *Reconciler) ReconcileKind(ctx context.Context, storage *v1beta1.CloudStorageSource) reconciler.Event {
 	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("storage", storage)))
 
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, storage, &storage.Status)()
+
 	storage.Status.InitializeConditions()
 	storage.Status.ObservedGeneration = storage.Generation
+	kgcpreconciler.MarkDeprecated(ctx, storage, &storage.Status, v1beta1.SchemeGroupVersion)
 
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if storage.Spec.ServiceAccountName != "" {
@@ -99,25 +123,33 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, storage *v1beta1.CloudSt
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailed, "Failed to reconcile CloudStorageSource PubSub: %s", err.Error())
 	}
 
-	notification, err := r.reconcileNotification(ctx, storage)
-	if err != nil {
-		storage.Status.MarkNotificationNotReady(reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
-		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
+	if storage.Spec.Bucket != "" {
+		notification, err := r.reconcileNotification(ctx, storage)
+		if err != nil {
+			storage.Status.MarkNotificationNotReady(reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
+			return reconciler.NewEvent(corev1.EventTypeWarning, reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notification: %s", err.Error())
+		}
+		storage.Status.MarkNotificationReady(notification)
+	} else {
+		notifications, err := r.reconcileBucketNotifications(ctx, storage)
+		if err != nil {
+			storage.Status.Buckets = notifications
+			storage.Status.MarkNotificationNotReady(reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notifications: %s", err.Error())
+			return reconciler.NewEvent(corev1.EventTypeWarning, reconciledNotificationFailed, "Failed to reconcile CloudStorageSource notifications: %s", err.Error())
+		}
+		storage.Status.MarkBucketNotificationsReady(notifications)
+	}
+
+	if err := r.ReconcileEventTypes(ctx, storage, r.eventTypes(storage)); err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, eventTypesFailed, "Failed to reconcile CloudStorageSource EventTypes: %s", err.Error())
 	}
-	storage.Status.MarkNotificationReady(notification)
 
 	return reconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, storage.Namespace, storage.Name)
 }
 
 func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1beta1.CloudStorageSource) (string, error) {
-	if storage.Status.ProjectID == "" {
-		projectID, err := utils.ProjectID(storage.Spec.Project, metadataClient.NewDefaultMetadataClient())
-		if err != nil {
-			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
-			return "", err
-		}
-		// Set the projectID in the status.
-		storage.Status.ProjectID = projectID
+	if err := r.reconcileProjectID(ctx, storage); err != nil {
+		return "", err
 	}
 
 	client, err := r.createClientFn(ctx)
@@ -127,15 +159,115 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1beta1
 	}
 	defer client.Close()
 
+	return r.reconcileBucketNotification(ctx, client, storage, storage.Spec.Bucket, storage.Status.NotificationID)
+}
+
+// reconcileBucketNotifications adds a notification to each bucket the source
+// subscribes to, and deletes the notifications of the buckets it no longer
+// subscribes to. It returns the notifications of the buckets, including the
+// ones that could not be deleted, so that they are deleted later.
+func (r *Reconciler) reconcileBucketNotifications(ctx context.Context, storage *v1beta1.CloudStorageSource) ([]v1beta1.BucketNotification, error) {
+	if err := r.reconcileProjectID(ctx, storage); err != nil {
+		return storage.Status.Buckets, err
+	}
+
+	client, err := r.createClientFn(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource client", zap.Error(err))
+		return storage.Status.Buckets, err
+	}
+	defer client.Close()
+
+	buckets, err := r.buckets(ctx, client, storage)
+	if err != nil {
+		return storage.Status.Buckets, err
+	}
+
+	existing := make(map[string]string, len(storage.Status.Buckets))
+	for _, n := range storage.Status.Buckets {
+		existing[n.Bucket] = n.NotificationID
+	}
+
+	var notifications []v1beta1.BucketNotification
+	var failed []string
+	for _, bucket := range buckets {
+		id, err := r.reconcileBucketNotification(ctx, client, storage, bucket, existing[bucket])
+		if err != nil {
+			failed = append(failed, bucket)
+			// Keep track of the notification the bucket might still have.
+			id = existing[bucket]
+		}
+		delete(existing, bucket)
+		if id != "" {
+			notifications = append(notifications, v1beta1.BucketNotification{Bucket: bucket, NotificationID: id})
+		}
+	}
+	for _, n := range storage.Status.Buckets {
+		if _, ok := existing[n.Bucket]; !ok {
+			continue
+		}
+		if err := r.deleteBucketNotification(ctx, client, n.Bucket, n.NotificationID); err != nil {
+			failed = append(failed, n.Bucket)
+			notifications = append(notifications, n)
+		}
+	}
+
+	if len(failed) > 0 {
+		return notifications, fmt.Errorf("failed to reconcile the notifications of buckets %s", strings.Join(failed, ", "))
+	}
+	if len(notifications) == 0 {
+		return nil, errors.New("no bucket matches the bucket selector")
+	}
+	return notifications, nil
+}
+
+// buckets returns the sorted names of the buckets the source subscribes to.
+func (r *Reconciler) buckets(ctx context.Context, client gstorage.Client, storage *v1beta1.CloudStorageSource) ([]string, error) {
+	if storage.Spec.BucketSelector == nil {
+		buckets := append([]string(nil), storage.Spec.Buckets...)
+		sort.Strings(buckets)
+		return buckets, nil
+	}
+	attrs, err := client.Buckets(ctx, storage.Status.ProjectID)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to list buckets", zap.String("projectId", storage.Status.ProjectID), zap.Error(err))
+		return nil, err
+	}
+	var buckets []string
+	for _, a := range attrs {
+		if storage.Spec.BucketSelector.Matches(a.Name, a.Labels) {
+			buckets = append(buckets, a.Name)
+		}
+	}
+	sort.Strings(buckets)
+	return buckets, nil
+}
+
+func (r *Reconciler) reconcileProjectID(ctx context.Context, storage *v1beta1.CloudStorageSource) error {
+	if storage.Status.ProjectID == "" {
+		projectID, err := utils.ProjectID(storage.Spec.Project, metadataClient.NewDefaultMetadataClient())
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to find project id", zap.Error(err))
+			return err
+		}
+		// Set the projectID in the status.
+		storage.Status.ProjectID = projectID
+	}
+	return nil
+}
+
+// reconcileBucketNotification makes sure the bucket has the notification with
+// the given ID, creating it if needed, and returns its ID.
+func (r *Reconciler) reconcileBucketNotification(ctx context.Context, client gstorage.Client, storage *v1beta1.CloudStorageSource, bucketName, notificationID string) (string, error) {
 	// Load the Bucket.
-	bucket := client.Bucket(storage.Spec.Bucket)
+	bucket := client.Bucket(bucketName)
 	//Check whether Bucket exists or not
 	if _, err := bucket.Attrs(ctx); err != nil {
 		if err == ErrBucketNotExist {
-			logging.FromContext(ctx).Desugar().Error("Bucket doesn't exist", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Bucket doesn't exist", zap.String("bucketName", bucketName), zap.Error(err))
 			return "", err
 		}
-		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", bucketName), zap.Error(err))
 		return "", err
 	}
 
@@ -146,7 +278,7 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1beta1
 	}
 
 	// If the notification does exist, then return its ID.
-	if existing, ok := notifications[storage.Status.NotificationID]; ok {
+	if existing, ok := notifications[notificationID]; ok {
 		return existing.ID, nil
 	}
 
@@ -168,7 +300,7 @@ func (r *Reconciler) reconcileNotification(ctx context.Context, storage *v1beta1
 
 	notification, err := bucket.AddNotification(ctx, nc)
 	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource notification", zap.Error(err))
+		logging.FromContext(ctx).Desugar().Error("Failed to create CloudStorageSource notification", zap.String("bucketName", bucketName), zap.Error(err))
 		return "", err
 	}
 	return notification.ID, nil
@@ -182,11 +314,34 @@ func (r *Reconciler) toCloudStorageSourceEventTypes(eventTypes []string) []strin
 	return storageTypes
 }
 
+// eventTypes returns the event types emitted by the storage source.
+func (r *Reconciler) eventTypes(storage *v1beta1.CloudStorageSource) []eventtype.EventType {
+	buckets := []string{storage.Spec.Bucket}
+	if storage.Spec.Bucket == "" {
+		buckets = buckets[:0]
+		for _, n := range storage.Status.Buckets {
+			buckets = append(buckets, n.Bucket)
+		}
+	}
+	eventTypes := make([]eventtype.EventType, 0, len(buckets)*len(storage.Spec.EventTypes))
+	for _, bucket := range buckets {
+		for _, eventType := range storage.Spec.EventTypes {
+			eventTypes = append(eventTypes, eventtype.EventType{
+				Type:        eventType,
+				Source:      v1beta1.CloudStorageSourceEventSource(bucket),
+				Schema:      storageSchema,
+				Description: storageEventTypeDescriptions[eventType],
+			})
+		}
+	}
+	return eventTypes
+}
+
 // deleteNotification looks at the status.NotificationID and if non-empty,
 // hence indicating that we have created a notification successfully
 // in the CloudStorageSource, remove it.
 func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1beta1.CloudStorageSource) error {
-	if storage.Status.NotificationID == "" {
+	if storage.Status.NotificationID == "" && len(storage.Status.Buckets) == 0 {
 		return nil
 	}
 
@@ -199,17 +354,33 @@ func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1beta1.Cl
 	}
 	defer client.Close()
 
+	if storage.Status.NotificationID != "" {
+		if err := r.deleteBucketNotification(ctx, client, storage.Spec.Bucket, storage.Status.NotificationID); err != nil {
+			return err
+		}
+	}
+	for _, n := range storage.Status.Buckets {
+		if err := r.deleteBucketNotification(ctx, client, n.Bucket, n.NotificationID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBucketNotification deletes the notification with the given ID from
+// the bucket, if they both exist.
+func (r *Reconciler) deleteBucketNotification(ctx context.Context, client gstorage.Client, bucketName, notificationID string) error {
 	// Load the Bucket.
-	bucket := client.Bucket(storage.Spec.Bucket)
+	bucket := client.Bucket(bucketName)
 
 	// Check whether bucket exists or not
 	if _, err := bucket.Attrs(ctx); err != nil {
 		// If the bucket was already deleted, then we should proceed
 		if err == ErrBucketNotExist {
-			logging.FromContext(ctx).Desugar().Info("Bucket doesn't exist", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Info("Bucket doesn't exist", zap.String("bucketName", bucketName), zap.Error(err))
 			return nil
 		}
-		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", storage.Spec.Bucket), zap.Error(err))
+		logging.FromContext(ctx).Desugar().Error("Failed to fetch attrs of bucket", zap.String("bucketName", bucketName), zap.Error(err))
 		return err
 	}
 
@@ -222,18 +393,18 @@ func (r *Reconciler) deleteNotification(ctx context.Context, storage *v1beta1.Cl
 	// This is bit wonky because, we could always just try to delete, but figuring out
 	// if an error returned is NotFound seems to not really work, so, we'll try
 	// checking first the list and only then deleting.
-	if existing, ok := notifications[storage.Status.NotificationID]; ok {
+	if existing, ok := notifications[notificationID]; ok {
 		logging.FromContext(ctx).Desugar().Debug("Found existing notification", zap.Any("notification", existing))
-		err = bucket.DeleteNotification(ctx, storage.Status.NotificationID)
+		err = bucket.DeleteNotification(ctx, notificationID)
 		if err == nil {
-			logging.FromContext(ctx).Desugar().Debug("Deleted Notification", zap.String("notificationId", storage.Status.NotificationID))
+			logging.FromContext(ctx).Desugar().Debug("Deleted Notification", zap.String("notificationId", notificationID))
 			return nil
 		}
 		if st, ok := gstatus.FromError(err); !ok {
-			logging.FromContext(ctx).Desugar().Error("Failed from CloudStorageSource client while deleting CloudStorageSource notification", zap.String("notificationId", storage.Status.NotificationID), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Failed from CloudStorageSource client while deleting CloudStorageSource notification", zap.String("notificationId", notificationID), zap.Error(err))
 			return err
 		} else if st.Code() != codes.NotFound {
-			logging.FromContext(ctx).Desugar().Error("Failed to delete CloudStorageSource notification", zap.String("notificationId", storage.Status.NotificationID), zap.Error(err))
+			logging.FromContext(ctx).Desugar().Error("Failed to delete CloudStorageSource notification", zap.String("notificationId", notificationID), zap.Error(err))
 			return err
 		}
 	}
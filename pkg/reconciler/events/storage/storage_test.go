@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 
 	"knative.dev/pkg/apis"
@@ -48,6 +49,8 @@ import (
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/cloudstoragesource"
 	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
 	gstorage "github.com/google/knative-gcp/pkg/gclient/storage/testing"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype"
+	eventtyperesources "github.com/google/knative-gcp/pkg/reconciler/eventtype/resources"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
@@ -58,6 +61,7 @@ const (
 	storageUID     = "test-storage-uid"
 	bucket         = "my-test-bucket"
 	sinkName       = "sink"
+	brokerName     = "default"
 	notificationId = "135"
 	testNS         = "testnamespace"
 	testImage      = "notification-ops-image"
@@ -69,6 +73,7 @@ const (
 	failedToReconcileTopicMsg                  = `Topic has not yet been reconciled`
 	failedToReconcilepullSubscriptionMsg       = `PullSubscription has not yet been reconciled`
 	failedToReconcileNotificationMsg           = `Failed to reconcile CloudStorageSource notification`
+	failedToReconcileNotificationsMsg          = `Failed to reconcile CloudStorageSource notifications`
 	failedToReconcilePubSubMsg                 = `Failed to reconcile CloudStorageSource PubSub`
 	failedToPropagatePullSubscriptionStatusMsg = `Failed to propagate PullSubscription status`
 	failedToDeleteNotificationMsg              = `Failed to delete CloudStorageSource notification`
@@ -89,6 +94,12 @@ var (
 		Kind:    "Sink",
 	}
 
+	brokerGVK = metav1.GroupVersionKind{
+		Group:   "eventing.knative.dev",
+		Version: "v1beta1",
+		Kind:    "Broker",
+	}
+
 	secret = corev1.SecretKeySelector{
 		LocalObjectReference: corev1.LocalObjectReference{
 			Name: "google-cloud-key",
@@ -158,6 +169,31 @@ func newSinkDestination() duckv1.Destination {
 	}
 }
 
+func newBrokerDestination() duckv1.Destination {
+	return duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "eventing.knative.dev/v1beta1",
+			Kind:       "Broker",
+			Name:       brokerName,
+			Namespace:  testNS,
+		},
+	}
+}
+
+func newEventType(eventType string) *eventingv1beta1.EventType {
+	source, _ := apis.ParseURL(storagev1beta1.CloudStorageSourceEventSource(bucket))
+	schema, _ := apis.ParseURL(storageSchema)
+	return eventtyperesources.MakeEventType(&eventtyperesources.EventTypeArgs{
+		Owner: NewCloudStorageSource(storageName, testNS,
+			WithCloudStorageSourceProject(testProject)),
+		Broker:      brokerName,
+		Type:        eventType,
+		Source:      source,
+		Schema:      schema,
+		Description: storageEventTypeDescriptions[eventType],
+	})
+}
+
 // TODO add a unit test for successfully creating a k8s service account, after issue https://github.com/google/knative-gcp/issues/657 gets solved.
 func TestAllCases(t *testing.T) {
 	storageSinkURL := sinkURI
@@ -1004,6 +1040,247 @@ func TestAllCases(t *testing.T) {
 					WithCloudStorageSourceNotificationReady(notificationId)),
 			}},
 		},
+		{
+			Name: "successfully created notifications for a bucket selector",
+			Objects: []runtime.Object{
+				NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceBucketSelector(storagev1beta1.BucketSelector{
+						Include: []string{"logs-*"},
+						Exclude: []string{"logs-tmp"},
+					}),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithCloudStorageSourceBucketNotifications(
+						storagev1beta1.BucketNotification{Bucket: "logs-b", NotificationID: "5"},
+						storagev1beta1.BucketNotification{Bucket: "logs-old", NotificationID: "7"},
+					),
+				),
+				NewTopic(storageName, testNS,
+					WithTopicSpec(inteventsv1beta1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					WithTopicReady(testTopicID),
+					WithTopicAddress(testTopicURI),
+					WithTopicProjectID(testProject),
+				),
+				NewPullSubscriptionWithNoDefaults(storageName, testNS,
+					WithPullSubscriptionSpecWithNoDefaults(inteventsv1beta1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: duckv1beta1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+					}),
+					WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					Buckets: []*storage.BucketAttrs{{Name: "logs-b"}, {Name: "logs-a"}, {Name: "logs-tmp"}, {Name: "other"}},
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+					},
+					BucketsData: map[string]gstorage.TestBucketData{
+						"logs-b": {
+							Notifications: map[string]*storage.Notification{
+								"5": {ID: "5"},
+							},
+						},
+						"logs-old": {
+							Notifications: map[string]*storage.Notification{
+								"7": {ID: "7"},
+							},
+						},
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, testNS, storageName),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceStatusObservedGeneration(generation),
+					WithCloudStorageSourceBucketSelector(storagev1beta1.BucketSelector{
+						Include: []string{"logs-*"},
+						Exclude: []string{"logs-tmp"},
+					}),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithInitCloudStorageSourceConditions,
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceTopicReady(testTopicID),
+					WithCloudStorageSourceProjectID(testProject),
+					WithCloudStorageSourcePullSubscriptionReady(),
+					WithCloudStorageSourceSubscriptionID(SubscriptionID),
+					WithCloudStorageSourceSinkURI(storageSinkURL),
+					WithCloudStorageSourceBucketNotificationsReady(
+						storagev1beta1.BucketNotification{Bucket: "logs-a", NotificationID: notificationId},
+						storagev1beta1.BucketNotification{Bucket: "logs-b", NotificationID: "5"},
+					)),
+			}},
+		},
+		{
+			Name: "bucket notifications partially fail",
+			Objects: []runtime.Object{
+				NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceBuckets(bucket, "my-missing-bucket"),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+				),
+				NewTopic(storageName, testNS,
+					WithTopicSpec(inteventsv1beta1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					WithTopicReady(testTopicID),
+					WithTopicAddress(testTopicURI),
+					WithTopicProjectID(testProject),
+				),
+				NewPullSubscriptionWithNoDefaults(storageName, testNS,
+					WithPullSubscriptionSpecWithNoDefaults(inteventsv1beta1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: duckv1beta1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newSinkDestination(),
+							},
+						},
+					}),
+					WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+					},
+					BucketsData: map[string]gstorage.TestBucketData{
+						"my-missing-bucket": {
+							AttrsError: storage.ErrBucketNotExist,
+						},
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeWarning, reconciledNotificationFailed, "%s: %s", failedToReconcileNotificationsMsg, "failed to reconcile the notifications of buckets my-missing-bucket"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceStatusObservedGeneration(generation),
+					WithCloudStorageSourceBuckets(bucket, "my-missing-bucket"),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithInitCloudStorageSourceConditions,
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceTopicReady(testTopicID),
+					WithCloudStorageSourceProjectID(testProject),
+					WithCloudStorageSourcePullSubscriptionReady(),
+					WithCloudStorageSourceSubscriptionID(SubscriptionID),
+					WithCloudStorageSourceSinkURI(storageSinkURL),
+					WithCloudStorageSourceBucketNotifications(storagev1beta1.BucketNotification{Bucket: bucket, NotificationID: notificationId}),
+					WithCloudStorageSourceNotificationNotReady(reconciledNotificationFailed, fmt.Sprintf("%s: %s", failedToReconcileNotificationsMsg, "failed to reconcile the notifications of buckets my-missing-bucket"))),
+			}},
+		},
+		{
+			Name: "successfully created notification and EventTypes for a broker sink",
+			Objects: []runtime.Object{
+				NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceBucket(bucket),
+					WithCloudStorageSourceSink(brokerGVK, brokerName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+				),
+				NewTopic(storageName, testNS,
+					WithTopicSpec(inteventsv1beta1.TopicSpec{
+						Topic:             testTopicID,
+						PropagationPolicy: "CreateDelete",
+						Project:           testProject,
+						EnablePublisher:   &falseVal,
+					}),
+					WithTopicReady(testTopicID),
+					WithTopicAddress(testTopicURI),
+					WithTopicProjectID(testProject),
+				),
+				NewPullSubscriptionWithNoDefaults(storageName, testNS,
+					WithPullSubscriptionSpecWithNoDefaults(inteventsv1beta1.PullSubscriptionSpec{
+						Topic: testTopicID,
+						PubSubSpec: duckv1beta1.PubSubSpec{
+							Project: testProject,
+							Secret:  &secret,
+							SourceSpec: duckv1.SourceSpec{
+								Sink: newBrokerDestination(),
+							},
+						},
+					}),
+					WithPullSubscriptionReady(sinkURI),
+				),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						AddNotificationID: notificationId,
+					},
+				},
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", storageName),
+				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `CloudStorageSource reconciled: "%s/%s"`, testNS, storageName),
+			},
+			WantCreates: []runtime.Object{
+				newEventType(storagev1beta1.CloudStorageSourceFinalize),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, storageName, true),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceStatusObservedGeneration(generation),
+					WithCloudStorageSourceBucket(bucket),
+					WithCloudStorageSourceSink(brokerGVK, brokerName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithInitCloudStorageSourceConditions,
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceTopicReady(testTopicID),
+					WithCloudStorageSourceProjectID(testProject),
+					WithCloudStorageSourcePullSubscriptionReady(),
+					WithCloudStorageSourceSubscriptionID(SubscriptionID),
+					WithCloudStorageSourceSinkURI(storageSinkURL),
+					WithCloudStorageSourceNotificationReady(notificationId)),
+			}},
+		},
 		{
 			Name: "delete fails with non grpc error",
 			Objects: []runtime.Object{
@@ -1198,6 +1475,77 @@ func TestAllCases(t *testing.T) {
 					WithDeletionTimestamp()),
 			}},
 		},
+		{
+			Name: "successfully deleted storage with several buckets",
+			Objects: []runtime.Object{
+				NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceBuckets(bucket, "my-other-bucket"),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithCloudStorageSourceSinkURI(storageSinkURL),
+					WithCloudStorageSourceTopicReady(testTopicID),
+					WithCloudStorageSourceBucketNotifications(
+						storagev1beta1.BucketNotification{Bucket: bucket, NotificationID: notificationId},
+						storagev1beta1.BucketNotification{Bucket: "my-other-bucket", NotificationID: "7"},
+					),
+					WithDeletionTimestamp(),
+				),
+				NewTopic(storageName, testNS,
+					WithTopicReady(testTopicID),
+					WithTopicAddress(testTopicURI),
+					WithTopicProjectID(testProject),
+				),
+				NewPullSubscriptionWithNoDefaults(storageName, testNS,
+					WithPullSubscriptionReady(sinkURI),
+				),
+				newSink(),
+			},
+			Key: testNS + "/" + storageName,
+			OtherTestData: map[string]interface{}{
+				"storage": gstorage.TestClientData{
+					BucketData: gstorage.TestBucketData{
+						Notifications: map[string]*storage.Notification{
+							notificationId: {
+								ID: notificationId,
+							},
+						},
+					},
+					BucketsData: map[string]gstorage.TestBucketData{
+						"my-other-bucket": {
+							AttrsError: storage.ErrBucketNotExist,
+						},
+					},
+				},
+			},
+			WantDeletes: []clientgotesting.DeleteActionImpl{
+				{ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "topics"}},
+					Name: storageName,
+				},
+				{ActionImpl: clientgotesting.ActionImpl{
+					Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "pullsubscriptions"}},
+					Name: storageName,
+				},
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewCloudStorageSource(storageName, testNS,
+					WithCloudStorageSourceProject(testProject),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceBuckets(bucket, "my-other-bucket"),
+					WithCloudStorageSourceSink(sinkGVK, sinkName),
+					WithCloudStorageSourceEventTypes([]string{storagev1beta1.CloudStorageSourceFinalize}),
+					WithCloudStorageSourceBucketNotifications(
+						storagev1beta1.BucketNotification{Bucket: bucket, NotificationID: notificationId},
+						storagev1beta1.BucketNotification{Bucket: "my-other-bucket", NotificationID: "7"},
+					),
+					WithCloudStorageSourceObjectMetaGeneration(generation),
+					WithCloudStorageSourceTopicFailed("TopicDeleted", fmt.Sprintf("Successfully deleted Topic: %s", storageName)),
+					WithCloudStorageSourcePullSubscriptionFailed("PullSubscriptionDeleted", fmt.Sprintf("Successfully deleted PullSubscription: %s", storageName)),
+					WithDeletionTimestamp()),
+			}},
+		},
 	}
 
 	defer logtesting.ClearAll()
@@ -1205,6 +1553,7 @@ func TestAllCases(t *testing.T) {
 		r := &Reconciler{
 			PubSubBase:     intevents.NewPubSubBase(ctx, controllerAgentName, receiveAdapterName, cmw),
 			Identity:       identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+			EventTypes:     eventtype.NewEventTypes(ctx),
 			storageLister:  listers.GetCloudStorageSourceLister(),
 			createClientFn: gstorage.TestClientCreator(testData["storage"]),
 		}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	endpointsinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/endpoints"
+	serviceinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/service"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/resolver"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	webhooksourceinformers "github.com/google/knative-gcp/pkg/client/injection/informers/events/v1beta1/webhooksource"
+	webhooksourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/webhooksource"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/webhook/resources"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "cloud-run-events-webhook-source-controller"
+)
+
+type envConfig struct {
+	// ReceiverImage is the image of the webhook receivers. Required.
+	ReceiverImage string `envconfig:"WEBHOOK_RECEIVER_IMAGE" required:"true"`
+}
+
+// NewController initializes the controller and is called by the generated
+// code. Registers event handlers to enqueue events.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	deploymentInformer := deploymentinformer.Get(ctx)
+	serviceInformer := serviceinformer.Get(ctx)
+	endpointsInformer := endpointsinformer.Get(ctx)
+	webhooksourceInformer := webhooksourceinformers.Get(ctx)
+
+	base := reconciler.NewBase(ctx, controllerAgentName, cmw)
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		base.Logger.Fatal("Failed to process env var", zap.Error(err))
+	}
+
+	r := &Reconciler{
+		Base:          base,
+		receiverImage: env.ReceiverImage,
+		deploymentRec: &reconciler.DeploymentReconciler{
+			DynamicClient: base.DynamicClientSet,
+			Lister:        deploymentInformer.Lister(),
+			Recorder:      base.Recorder,
+		},
+		serviceRec: &reconciler.ServiceReconciler{
+			DynamicClient:   base.DynamicClientSet,
+			ServiceLister:   serviceInformer.Lister(),
+			EndpointsLister: endpointsInformer.Lister(),
+			Recorder:        base.Recorder,
+		},
+	}
+	impl := webhooksourcereconciler.NewImpl(ctx, r)
+	r.uriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
+
+	r.Logger.Info("Setting up event handlers")
+	webhooksourceInformer.Informer().AddEventHandlerWithResyncPeriod(
+		controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
+
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterGroupVersionKind(v1beta1.SchemeGroupVersion.WithKind("WebhookSource")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+	// The endpoints of the receivers aren't owned by the WebhookSources, but
+	// have the labels of their services.
+	endpointsInformer.Informer().AddEventHandler(
+		controller.HandleAll(impl.EnqueueLabelOfNamespaceScopedResource("", resources.WebhookSourceLabelKey)))
+
+	return impl
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"strconv"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	// WebhookSourceLabelKey is the label key of the name of the
+	// WebhookSource owning a receiver.
+	WebhookSourceLabelKey = "events.cloud.google.com/webhooksource"
+
+	receiverName = "webhook-receiver"
+	receiverPort = 8080
+)
+
+// ReceiverArgs are the arguments to create the receiver of a WebhookSource.
+type ReceiverArgs struct {
+	Image   string
+	Source  *v1beta1.WebhookSource
+	SinkURI *apis.URL
+}
+
+// Name returns the name of the receiver Deployment and Service of source.
+func Name(source *v1beta1.WebhookSource) string {
+	return kmeta.ChildName(source.Name, "-webhook")
+}
+
+// Labels returns the labels of the receiver of the WebhookSource name.
+func Labels(name string) map[string]string {
+	return map[string]string{
+		WebhookSourceLabelKey: name,
+	}
+}
+
+// ceExtensions returns the CloudEvent overrides of source as pod embeddable
+// properties.
+func ceExtensions(ctx context.Context, source *v1beta1.WebhookSource) string {
+	if source.Spec.CloudEventOverrides == nil || source.Spec.CloudEventOverrides.Extensions == nil {
+		return ""
+	}
+	ceExtensions, err := utils.MapToBase64(source.Spec.CloudEventOverrides.Extensions)
+	if err != nil {
+		logging.FromContext(ctx).Warnw("failed to make cloudevents overrides extensions",
+			zap.Error(err),
+			zap.Any("extensions", source.Spec.CloudEventOverrides.Extensions))
+	}
+	return ceExtensions
+}
+
+// MakeReceiverDeployment creates the receiver Deployment of a WebhookSource.
+// The webhook secret is passed to the receiver through a Secret reference, so
+// it never shows up in the Deployment.
+func MakeReceiverDeployment(ctx context.Context, args ReceiverArgs) *appsv1.Deployment {
+	source := args.Source
+	container := corev1.Container{
+		Name:  receiverName,
+		Image: args.Image,
+		Args:  []string{"--role=" + receiverName},
+		Env: []corev1.EnvVar{{
+			Name:  "PORT",
+			Value: strconv.Itoa(receiverPort),
+		}, {
+			Name:  "SINK_URI",
+			Value: args.SinkURI.String(),
+		}, {
+			Name:  "WEBHOOK_PROVIDER",
+			Value: string(source.Spec.Provider),
+		}, {
+			Name:  "WEBHOOK_AUTH_STRATEGY",
+			Value: string(source.Spec.Auth.Strategy),
+		}, {
+			Name:  "WEBHOOK_AUTH_HEADER",
+			Value: source.Spec.AuthHeader(),
+		}, {
+			Name: "WEBHOOK_AUTH_SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: source.Spec.Auth.Secret,
+			},
+		}, {
+			Name:  "WEBHOOK_EVENT_SOURCE",
+			Value: v1beta1.WebhookSourceEventSource(source.Namespace, source.Name),
+		}, {
+			Name:  "K_CE_EXTENSIONS",
+			Value: ceExtensions(ctx, source),
+		}},
+		Ports: []corev1.ContainerPort{{
+			Name:          "http",
+			ContainerPort: receiverPort,
+		}},
+		ReadinessProbe: &corev1.Probe{
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   "/healthz",
+					Port:   intstr.FromInt(receiverPort),
+					Scheme: corev1.URISchemeHTTP,
+				},
+			},
+		},
+	}
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       source.Namespace,
+			Name:            Name(source),
+			Labels:          Labels(source.Name),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: Labels(source.Name)},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: Labels(source.Name),
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{container},
+				},
+			},
+		},
+	}
+}
+
+// MakeReceiverService creates the Service of the receiver of a
+// WebhookSource.
+func MakeReceiverService(args ReceiverArgs) *corev1.Service {
+	source := args.Source
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       source.Namespace,
+			Name:            Name(source),
+			Labels:          Labels(source.Name),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(source)},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: Labels(source.Name),
+			Ports: []corev1.ServicePort{{
+				Name:       "http",
+				Port:       80,
+				TargetPort: intstr.FromInt(receiverPort),
+			}},
+		},
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"knative.dev/eventing/pkg/reconciler/names"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/resolver"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	webhooksourcereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/webhooksource"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/webhook/resources"
+)
+
+const (
+	reconciledSuccessReason = "WebhookSourceReconciled"
+)
+
+// Reconciler is the controller implementation for the WebhookSource source.
+type Reconciler struct {
+	*reconciler.Base
+
+	// receiverImage is the image of the webhook receivers.
+	receiverImage string
+
+	deploymentRec *reconciler.DeploymentReconciler
+	serviceRec    *reconciler.ServiceReconciler
+	uriResolver   *resolver.URIResolver
+}
+
+// Check that our Reconciler implements Interface.
+var _ webhooksourcereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, source *v1beta1.WebhookSource) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("webhook", source)))
+
+	// Notify of changes to the readiness of the source once it is reconciled.
+	defer r.TrackReadiness(ctx, source, &source.Status)()
+
+	source.Status.InitializeConditions()
+	source.Status.ObservedGeneration = source.Generation
+
+	// Sink is required.
+	sinkURI, err := r.resolveDestination(source.Spec.Sink, source)
+	if err != nil {
+		source.Status.MarkNoSink("InvalidSink", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, "InvalidSink", "InvalidSink: %s", err.Error())
+	}
+	source.Status.MarkSink(sinkURI)
+
+	args := resources.ReceiverArgs{
+		Image:   r.receiverImage,
+		Source:  source,
+		SinkURI: sinkURI,
+	}
+	if _, err := r.deploymentRec.ReconcileDeployment(source, resources.MakeReceiverDeployment(ctx, args)); err != nil {
+		logging.FromContext(ctx).Error("Failed to reconcile receiver deployment", zap.Error(err))
+		source.Status.MarkReceiverFailed("ReceiverDeploymentFailed", "Failed to reconcile receiver deployment: %v", err)
+		return err
+	}
+
+	svc := resources.MakeReceiverService(args)
+	endpoints, err := r.serviceRec.ReconcileService(source, svc)
+	switch {
+	case apierrs.IsNotFound(err):
+		// The endpoints of a new service are created asynchronously. The
+		// source is reconciled again once they are.
+		source.Status.MarkReceiverFailed("EndpointsUnavailable", "Endpoints %q is unavailable.", svc.Name)
+	case err != nil:
+		logging.FromContext(ctx).Error("Failed to reconcile receiver service", zap.Error(err))
+		source.Status.MarkReceiverFailed("ReceiverServiceFailed", "Failed to reconcile receiver service: %v", err)
+		return err
+	default:
+		source.Status.PropagateReceiverAvailability(endpoints)
+	}
+	source.Status.SetAddress(&apis.URL{
+		Scheme: "http",
+		Host:   names.ServiceHostName(svc.Name, svc.Namespace),
+	})
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `WebhookSource reconciled: "%s/%s"`, source.Namespace, source.Name)
+}
+
+func (r *Reconciler) resolveDestination(destination duckv1.Destination, source *v1beta1.WebhookSource) (*apis.URL, error) {
+	// To call URIFromDestinationV1(), dest.Ref must have a Namespace. If there is
+	// no Namespace defined in dest.Ref, we will use the Namespace of the source
+	// as the Namespace of dest.Ref.
+	if destination.Ref != nil && destination.Ref.Namespace == "" {
+		destination.Ref.Namespace = source.Namespace
+	}
+	url, err := r.uriResolver.URIFromDestinationV1(destination, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve sink: %w", err)
+	}
+	return url, nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/resolver"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/events/v1beta1/webhooksource"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/events/webhook/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	webhookName   = "github"
+	testNS        = "testnamespace"
+	sinkName      = "sink"
+	receiverImage = "dataplane"
+)
+
+var (
+	sinkDNS = sinkName + ".testnamespace.svc.cluster.local"
+	sinkURI = apis.HTTP(sinkDNS)
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	receiverAddress = apis.HTTP("github-webhook.testnamespace.svc.cluster.local")
+)
+
+func newSink() *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "testing.cloud.google.com/v1beta1",
+			"kind":       "Sink",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      sinkName,
+			},
+			"status": map[string]interface{}{
+				"address": map[string]interface{}{
+					"url": sinkURI.String(),
+				},
+			},
+		},
+	}
+}
+
+func newWebhookSource(opts ...WebhookSourceOption) *v1beta1.WebhookSource {
+	return NewWebhookSource(webhookName, testNS, append([]WebhookSourceOption{
+		WithWebhookSourceProvider(v1beta1.WebhookProviderGitHub),
+		WithWebhookSourceSecret("github-webhook", "secret"),
+		WithWebhookSourceSink(sinkGVK, sinkName),
+	}, opts...)...)
+}
+
+func receiverArgs() resources.ReceiverArgs {
+	return resources.ReceiverArgs{
+		Image:   receiverImage,
+		Source:  newWebhookSource(),
+		SinkURI: sinkURI,
+	}
+}
+
+func receiverDeployment() *appsv1.Deployment {
+	return resources.MakeReceiverDeployment(context.Background(), receiverArgs())
+}
+
+func receiverService() *corev1.Service {
+	return resources.MakeReceiverService(receiverArgs())
+}
+
+func receiverEndpoints(opts ...EndpointsOption) *corev1.Endpoints {
+	return NewEndpoints("github-webhook", testNS, append([]EndpointsOption{
+		WithEndpointsLabels(resources.Labels(webhookName)),
+	}, opts...)...)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "sink not found",
+		Key:  testNS + "/" + webhookName,
+		Objects: []runtime.Object{
+			newWebhookSource(),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newWebhookSource(
+				WithInitWebhookSourceConditions,
+				WithWebhookSourceNoSink("InvalidSink", `failed to resolve sink: failed to get ref &ObjectReference{Kind:Sink,Namespace:testnamespace,Name:sink,UID:,APIVersion:testing.cloud.google.com/v1beta1,ResourceVersion:,FieldPath:,}: sinks.testing.cloud.google.com "sink" not found`),
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InvalidSink", `InvalidSink: failed to resolve sink: failed to get ref &ObjectReference{Kind:Sink,Namespace:testnamespace,Name:sink,UID:,APIVersion:testing.cloud.google.com/v1beta1,ResourceVersion:,FieldPath:,}: sinks.testing.cloud.google.com "sink" not found`),
+		},
+	}, {
+		Name: "receiver created",
+		Key:  testNS + "/" + webhookName,
+		Objects: []runtime.Object{
+			newWebhookSource(),
+			newSink(),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newWebhookSource(
+				WithInitWebhookSourceConditions,
+				WithWebhookSourceSinkURI(sinkURI),
+				WithWebhookSourceReceiverFailed("EndpointsUnavailable", `Endpoints "github-webhook" is unavailable.`),
+				WithWebhookSourceAddress(receiverAddress),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, receiverDeployment()),
+			NewApplyPatch(t, receiverService()),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "DeploymentCreated", "Created deployment testnamespace/github-webhook"),
+			Eventf(corev1.EventTypeNormal, "ServiceCreated", "Created service testnamespace/github-webhook"),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `WebhookSource reconciled: "testnamespace/github"`),
+		},
+	}, {
+		Name: "receiver deployment apply error",
+		Key:  testNS + "/" + webhookName,
+		Objects: []runtime.Object{
+			newWebhookSource(),
+			newSink(),
+		},
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("patch", "deployments"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newWebhookSource(
+				WithInitWebhookSourceConditions,
+				WithWebhookSourceSinkURI(sinkURI),
+				WithWebhookSourceReceiverFailed("ReceiverDeploymentFailed", "Failed to reconcile receiver deployment: inducing failure for patch deployments"),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, receiverDeployment()),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", "inducing failure for patch deployments"),
+		},
+		WantErr: true,
+	}, {
+		Name: "receiver endpoints unavailable",
+		Key:  testNS + "/" + webhookName,
+		Objects: []runtime.Object{
+			newWebhookSource(),
+			newSink(),
+			receiverDeployment(),
+			receiverService(),
+			receiverEndpoints(WithEndpointsNotReadyAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newWebhookSource(
+				WithInitWebhookSourceConditions,
+				WithWebhookSourceSinkURI(sinkURI),
+				WithWebhookSourceReceiverFailed("EndpointsUnavailable", `Endpoints "github-webhook" is unavailable.`),
+				WithWebhookSourceAddress(receiverAddress),
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `WebhookSource reconciled: "testnamespace/github"`),
+		},
+	}, {
+		Name: "receiver ready",
+		Key:  testNS + "/" + webhookName,
+		Objects: []runtime.Object{
+			newWebhookSource(),
+			newSink(),
+			receiverDeployment(),
+			receiverService(),
+			receiverEndpoints(WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"})),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newWebhookSource(
+				WithInitWebhookSourceConditions,
+				WithWebhookSourceSinkURI(sinkURI),
+				WithWebhookSourceReceiverAvailability(receiverEndpoints(WithEndpointsAddresses(corev1.EndpointAddress{IP: "127.0.0.1"}))),
+				WithWebhookSourceAddress(receiverAddress),
+			),
+		}},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `WebhookSource reconciled: "testnamespace/github"`),
+		},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		ctx = addressable.WithDuck(ctx)
+		base := reconciler.NewBase(ctx, controllerAgentName, cmw)
+		r := &Reconciler{
+			Base:          base,
+			receiverImage: receiverImage,
+			deploymentRec: &reconciler.DeploymentReconciler{
+				DynamicClient: base.DynamicClientSet,
+				Lister:        listers.GetDeploymentLister(),
+				Recorder:      base.Recorder,
+			},
+			serviceRec: &reconciler.ServiceReconciler{
+				DynamicClient:   base.DynamicClientSet,
+				ServiceLister:   listers.GetK8sServiceLister(),
+				EndpointsLister: listers.GetEndpointsLister(),
+				Recorder:        base.Recorder,
+			},
+			uriResolver: resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
+		}
+		return webhooksource.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetWebhookSourceLister(), r.Recorder, r)
+	}))
+}
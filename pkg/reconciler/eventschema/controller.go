@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	eventschemainformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/eventschema"
+	eventschemareconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/eventschema"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "eventschema-controller"
+)
+
+// NewController initializes the controller and is called by the generated code.
+// Registers event handlers to enqueue events.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	eventSchemaInformer := eventschemainformer.Get(ctx)
+	configMapInformer := configmapinformer.Get(ctx)
+
+	r := &Reconciler{
+		Base:            reconciler.NewBase(ctx, controllerAgentName, cmw),
+		schemaLister:    eventSchemaInformer.Lister(),
+		configMapLister: configMapInformer.Lister(),
+	}
+	impl := eventschemareconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+
+	// Whether an EventSchema is published depends on the other EventSchemas
+	// of its namespace, so all of them are reconciled on any change.
+	eventSchemaInformer.Informer().AddEventHandlerWithResyncPeriod(controller.HandleAll(func(obj interface{}) {
+		accessor, err := kmeta.DeletionHandlingAccessor(obj)
+		if err != nil {
+			return
+		}
+		siblings, err := r.schemaLister.EventSchemas(accessor.GetNamespace()).List(labels.Everything())
+		if err != nil {
+			return
+		}
+		for _, es := range siblings {
+			impl.Enqueue(es)
+		}
+	}), reconciler.DefaultResyncPeriod)
+
+	// Restore the schemas ConfigMap if it is changed or deleted.
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), schema.ConfigMapName),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(eventSchemaInformer.Informer())
+		}),
+	})
+
+	return impl
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	eventschemareconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/eventschema"
+	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/utils/jsonschema"
+)
+
+const (
+	reconciledSuccessReason = "EventSchemaReconciled"
+	schemaInvalidReason     = "SchemaInvalid"
+	conflictReason          = "Conflict"
+	configMapFailedReason   = "ConfigMapUpdateFailed"
+)
+
+// Reconciler implements controller.Reconciler for EventSchema resources.
+type Reconciler struct {
+	*reconciler.Base
+
+	// schemaLister for reading all the EventSchemas published to the data
+	// plane.
+	schemaLister listers.EventSchemaLister
+	// configMapLister for reading the schemas ConfigMap.
+	configMapLister corev1listers.ConfigMapLister
+}
+
+// Check that our Reconciler implements Interface and Finalizer.
+var _ eventschemareconciler.Interface = (*Reconciler)(nil)
+var _ eventschemareconciler.Finalizer = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, es *v1alpha1.EventSchema) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("eventschema", es)))
+
+	es.Status.InitializeConditions()
+	es.Status.ObservedGeneration = es.Generation
+
+	if err := compile(es); err != nil {
+		es.Status.MarkSchemaInvalid(schemaInvalidReason, "Invalid JSON schema: %s", err.Error())
+		es.Status.MarkPublishFailed(schemaInvalidReason, "Invalid schemas are not published")
+		// Unpublish the schema if it was valid before.
+		if err := r.reconcileConfigMap(ctx); err != nil {
+			return err
+		}
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, schemaInvalidReason, "Invalid JSON schema: %s", err.Error())
+	}
+	es.Status.MarkSchemaValid()
+
+	published, err := r.published()
+	if err != nil {
+		return err
+	}
+	if owner := published[typeKey{namespace: es.Namespace, eventType: es.Spec.Type}]; owner != nil && owner.Name != es.Name {
+		es.Status.MarkPublishFailed(conflictReason, "EventSchema %q already defines the schema of type %q", owner.Name, es.Spec.Type)
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, conflictReason, "EventSchema %q already defines the schema of type %q", owner.Name, es.Spec.Type)
+	}
+
+	if err := r.reconcileConfigMap(ctx); err != nil {
+		es.Status.MarkPublishFailed(configMapFailedReason, "Failed to publish the schema: %s", err.Error())
+		return err
+	}
+	es.Status.MarkPublished()
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `EventSchema reconciled: "%s/%s"`, es.Namespace, es.Name)
+}
+
+// FinalizeKind removes the schema from the ConfigMap. The schemas of the same
+// type left in the namespace, if any, are published instead once reconciled.
+func (r *Reconciler) FinalizeKind(ctx context.Context, es *v1alpha1.EventSchema) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("eventschema", es)))
+	return r.reconcileConfigMap(ctx)
+}
+
+// typeKey identifies the events of a type sent to the brokers of a namespace.
+type typeKey struct {
+	namespace string
+	eventType string
+}
+
+// published returns the EventSchemas to publish, keyed by the events they
+// apply to. When several valid EventSchemas of a namespace define the schema
+// of the same type, the first one by name is published.
+func (r *Reconciler) published() (map[typeKey]*v1alpha1.EventSchema, error) {
+	all, err := r.schemaLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list EventSchemas: %w", err)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Namespace != all[j].Namespace {
+			return all[i].Namespace < all[j].Namespace
+		}
+		return all[i].Name < all[j].Name
+	})
+	published := make(map[typeKey]*v1alpha1.EventSchema)
+	for _, es := range all {
+		if es.DeletionTimestamp != nil || compile(es) != nil {
+			continue
+		}
+		k := typeKey{namespace: es.Namespace, eventType: es.Spec.Type}
+		if _, ok := published[k]; !ok {
+			published[k] = es
+		}
+	}
+	return published, nil
+}
+
+// reconcileConfigMap writes the published EventSchemas to the ConfigMap the
+// data plane reads them from.
+func (r *Reconciler) reconcileConfigMap(ctx context.Context) error {
+	published, err := r.published()
+	if err != nil {
+		return err
+	}
+	entries := make(map[string]*schema.Entry, len(published))
+	for _, es := range published {
+		entries[schema.Key(es.Namespace, es.Name)] = &schema.Entry{
+			Namespace:          es.Namespace,
+			Type:               es.Spec.Type,
+			Action:             string(es.Spec.Action),
+			ValidateOnDelivery: es.Spec.ValidateOnDelivery,
+			Schema:             es.Spec.Schema.Raw,
+		}
+	}
+	data, err := schema.Encode(entries)
+	if err != nil {
+		return err
+	}
+
+	existing, err := r.configMapLister.ConfigMaps(system.Namespace()).Get(schema.ConfigMapName)
+	if apierrs.IsNotFound(err) {
+		desired := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      schema.ConfigMapName,
+				Namespace: system.Namespace(),
+			},
+			Data: data,
+		}
+		if _, err := r.KubeClientSet.CoreV1().ConfigMaps(desired.Namespace).Create(desired); err != nil {
+			return fmt.Errorf("failed to create the schemas ConfigMap: %w", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get the schemas ConfigMap: %w", err)
+	}
+	if equality.Semantic.DeepEqual(data, existing.Data) || (len(data) == 0 && len(existing.Data) == 0) {
+		return nil
+	}
+	logging.FromContext(ctx).Debug("Updating the schemas ConfigMap")
+	desired := existing.DeepCopy()
+	desired.Data = data
+	if _, err := r.KubeClientSet.CoreV1().ConfigMaps(desired.Namespace).Update(desired); err != nil {
+		return fmt.Errorf("failed to update the schemas ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// compile returns why the schema of the EventSchema is not a valid JSON
+// schema, nil if it is.
+func compile(es *v1alpha1.EventSchema) error {
+	_, err := jsonschema.Compile(es.Spec.Schema.Raw)
+	return err
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventschema
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/broker/schema"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1alpha1/eventschema"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	testNS        = "testnamespace"
+	schemaName    = "orders"
+	otherName     = "orders-v2"
+	eventType     = "com.example.order"
+	finalizerName = "eventschemas.internal.events.cloud.google.com"
+
+	orderSchema = `{"properties":{"id":{"type":"string"}},"required":["id"],"type":"object"}`
+	otherSchema = `{"type":"object"}`
+)
+
+var (
+	finalizerUpdatedEvent = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "orders" finalizers`)
+	reconciledEvent       = Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `EventSchema reconciled: "%s/%s"`, testNS, schemaName)
+)
+
+func newEventSchema(o ...EventSchemaOption) *v1alpha1.EventSchema {
+	return NewEventSchema(schemaName, testNS, append([]EventSchemaOption{
+		WithEventSchemaType(eventType),
+		WithEventSchemaSchema(orderSchema),
+	}, o...)...)
+}
+
+// schemasConfigMap returns the schemas ConfigMap holding the EventSchemas.
+func schemasConfigMap(schemas ...*v1alpha1.EventSchema) *corev1.ConfigMap {
+	entries := make(map[string]*schema.Entry, len(schemas))
+	for _, es := range schemas {
+		entries[schema.Key(es.Namespace, es.Name)] = &schema.Entry{
+			Namespace:          es.Namespace,
+			Type:               es.Spec.Type,
+			Action:             string(es.Spec.Action),
+			ValidateOnDelivery: es.Spec.ValidateOnDelivery,
+			Schema:             es.Spec.Schema.Raw,
+		}
+	}
+	data, err := schema.Encode(entries)
+	if err != nil {
+		panic(err)
+	}
+	return NewConfigMap(schema.ConfigMapName, system.Namespace(), WithConfigMapData(data))
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", finalizerName)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "schema published, ConfigMap created",
+		Objects: []runtime.Object{
+			newEventSchema(WithEventSchemaAction(v1alpha1.EventSchemaActionReject)),
+		},
+		Key:                     testNS + "/" + schemaName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			finalizerUpdatedEvent,
+			reconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, schemaName, true),
+		},
+		WantCreates: []runtime.Object{
+			schemasConfigMap(newEventSchema(WithEventSchemaAction(v1alpha1.EventSchemaActionReject))),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newEventSchema(
+				WithEventSchemaAction(v1alpha1.EventSchemaActionReject),
+				WithInitEventSchemaConditions,
+				WithEventSchemaSchemaValid,
+				WithEventSchemaPublished,
+			),
+		}},
+	}, {
+		Name: "schema changed, ConfigMap updated",
+		Objects: []runtime.Object{
+			newEventSchema(WithEventSchemaValidateOnDelivery, WithEventSchemaFinalizers(finalizerName)),
+			schemasConfigMap(newEventSchema()),
+		},
+		Key:                     testNS + "/" + schemaName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: schemasConfigMap(newEventSchema(WithEventSchemaValidateOnDelivery)),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newEventSchema(
+				WithEventSchemaValidateOnDelivery,
+				WithEventSchemaFinalizers(finalizerName),
+				WithInitEventSchemaConditions,
+				WithEventSchemaSchemaValid,
+				WithEventSchemaPublished,
+			),
+		}},
+	}, {
+		Name: "schema already published",
+		Objects: []runtime.Object{
+			newEventSchema(WithEventSchemaFinalizers(finalizerName)),
+			schemasConfigMap(newEventSchema()),
+		},
+		Key: testNS + "/" + schemaName,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newEventSchema(
+				WithEventSchemaFinalizers(finalizerName),
+				WithInitEventSchemaConditions,
+				WithEventSchemaSchemaValid,
+				WithEventSchemaPublished,
+			),
+		}},
+	}, {
+		Name: "another schema of the type is published",
+		Objects: []runtime.Object{
+			NewEventSchema(otherName, testNS,
+				WithEventSchemaType(eventType),
+				WithEventSchemaSchema(otherSchema),
+				WithEventSchemaFinalizers(finalizerName)),
+			newEventSchema(WithEventSchemaFinalizers(finalizerName)),
+			schemasConfigMap(newEventSchema()),
+		},
+		Key: testNS + "/" + otherName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, conflictReason, `EventSchema %q already defines the schema of type %q`, schemaName, eventType),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewEventSchema(otherName, testNS,
+				WithEventSchemaType(eventType),
+				WithEventSchemaSchema(otherSchema),
+				WithEventSchemaFinalizers(finalizerName),
+				WithInitEventSchemaConditions,
+				WithEventSchemaSchemaValid,
+				WithEventSchemaPublishFailed(conflictReason, fmt.Sprintf(`EventSchema %q already defines the schema of type %q`, schemaName, eventType)),
+			),
+		}},
+	}, {
+		Name: "deleted schema unpublished",
+		Objects: []runtime.Object{
+			newEventSchema(WithEventSchemaFinalizers(finalizerName), WithEventSchemaDeletionTimestamp),
+			NewEventSchema(otherName, testNS,
+				WithEventSchemaType(eventType),
+				WithEventSchemaSchema(otherSchema),
+				WithEventSchemaFinalizers(finalizerName)),
+			schemasConfigMap(newEventSchema()),
+		},
+		Key:                     testNS + "/" + schemaName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			finalizerUpdatedEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, schemaName, false),
+		},
+		// The schema of the same type left in the namespace takes its place.
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: schemasConfigMap(NewEventSchema(otherName, testNS,
+				WithEventSchemaType(eventType),
+				WithEventSchemaSchema(otherSchema))),
+		}},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			Base:            reconciler.NewBase(ctx, controllerAgentName, cmw),
+			schemaLister:    listers.GetEventSchemaLister(),
+			configMapLister: listers.GetConfigMapLister(),
+		}
+		return eventschema.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetEventSchemaLister(), r.Recorder, r)
+	}))
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventtype contains the reconciler registering the event types
+// emitted by sources as Knative EventTypes.
+package eventtype
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	eventingclientset "knative.dev/eventing/pkg/client/clientset/versioned"
+	eventingclient "knative.dev/eventing/pkg/client/injection/client"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype/resources"
+)
+
+const brokerKind = "Broker"
+
+// EventType describes an event type emitted by a source.
+type EventType struct {
+	// Type is the CloudEvent type.
+	Type string
+	// Source is the CloudEvent source. It is left empty when the source
+	// varies from event to event.
+	Source string
+	// Schema is the URL of the schema of the event data, if any.
+	Schema string
+	// Description is a human readable description of the event type.
+	Description string
+}
+
+func NewEventTypes(ctx context.Context) *EventTypes {
+	return &EventTypes{
+		eventingClient: eventingclient.Get(ctx),
+	}
+}
+
+type EventTypes struct {
+	eventingClient eventingclientset.Interface
+}
+
+// ReconcileEventTypes registers the given event types as EventTypes of the
+// broker the pubsubable sends its events to, so that they can be discovered
+// through the Knative event registry. EventTypes previously registered by the
+// pubsubable that are no longer wanted, e.g. because its sink is no longer a
+// broker, are deleted.
+func (e *EventTypes) ReconcileEventTypes(ctx context.Context, pubsubable duck.PubSubable, eventTypes []EventType) error {
+	desired := make(map[string]*eventingv1beta1.EventType, len(eventTypes))
+	if broker := brokerName(pubsubable); broker != "" {
+		for _, et := range eventTypes {
+			args := &resources.EventTypeArgs{
+				Owner:       pubsubable,
+				Broker:      broker,
+				Type:        et.Type,
+				Description: et.Description,
+			}
+			var err error
+			if args.Source, err = apis.ParseURL(et.Source); err != nil {
+				return fmt.Errorf("invalid source of event type %q: %w", et.Type, err)
+			}
+			if args.Schema, err = apis.ParseURL(et.Schema); err != nil {
+				return fmt.Errorf("invalid schema of event type %q: %w", et.Type, err)
+			}
+			newEventType := resources.MakeEventType(args)
+			desired[newEventType.Name] = newEventType
+		}
+	}
+
+	namespace := pubsubable.GetObjectMeta().GetNamespace()
+	eventTypeClient := e.eventingClient.EventingV1beta1().EventTypes(namespace)
+	existing, err := eventTypeClient.List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(resources.GetLabels(pubsubable.GetObjectMeta().GetName())).String(),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to list EventTypes", zap.Error(err))
+		return fmt.Errorf("failed to list EventTypes: %w", err)
+	}
+
+	for i := range existing.Items {
+		et := &existing.Items[i]
+		if !metav1.IsControlledBy(et, pubsubable.GetObjectMeta()) {
+			continue
+		}
+		newEventType, ok := desired[et.Name]
+		if !ok {
+			logging.FromContext(ctx).Desugar().Debug("Deleting EventType", zap.String("eventType", et.Name))
+			if err := eventTypeClient.Delete(et.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				logging.FromContext(ctx).Desugar().Error("Failed to delete EventType", zap.String("eventType", et.Name), zap.Error(err))
+				return fmt.Errorf("failed to delete EventType: %w", err)
+			}
+			continue
+		}
+		delete(desired, et.Name)
+		if equality.Semantic.DeepDerivative(newEventType.Spec, et.Spec) {
+			continue
+		}
+		// Don't modify the original copy.
+		update := et.DeepCopy()
+		update.Spec = newEventType.Spec
+		logging.FromContext(ctx).Desugar().Debug("Updating EventType", zap.Any("eventType", update))
+		if _, err := eventTypeClient.Update(update); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to update EventType", zap.Any("eventType", update), zap.Error(err))
+			return fmt.Errorf("failed to update EventType: %w", err)
+		}
+	}
+
+	for _, et := range eventTypes {
+		name := resources.GenerateEventTypeName(pubsubable.GetObjectMeta().GetName(), et.Type)
+		newEventType, ok := desired[name]
+		if !ok {
+			continue
+		}
+		delete(desired, name)
+		logging.FromContext(ctx).Desugar().Debug("Creating EventType", zap.Any("eventType", newEventType))
+		if _, err := eventTypeClient.Create(newEventType); err != nil && !apierrs.IsAlreadyExists(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to create EventType", zap.Any("eventType", newEventType), zap.Error(err))
+			return fmt.Errorf("failed to create EventType: %w", err)
+		}
+	}
+	return nil
+}
+
+// brokerName returns the name of the broker the pubsubable sends its events
+// to, or the empty string if its sink is not a broker in its namespace.
+func brokerName(pubsubable duck.PubSubable) string {
+	ref := pubsubable.PubSubSpec().Sink.Ref
+	if ref == nil || ref.Kind != brokerKind {
+		return ""
+	}
+	if gv, err := schema.ParseGroupVersion(ref.APIVersion); err != nil || gv.Group != eventing.GroupName {
+		return ""
+	}
+	if ref.Namespace != "" && ref.Namespace != pubsubable.GetObjectMeta().GetNamespace() {
+		return ""
+	}
+	return ref.Name
+}
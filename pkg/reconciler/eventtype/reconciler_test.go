@@ -0,0 +1,198 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventtype
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	fakeeventingclientset "knative.dev/eventing/pkg/client/clientset/versioned/fake"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler/eventtype/resources"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	testNS     = "testnamespace"
+	sourceName = "source"
+	testBroker = "default"
+)
+
+var (
+	brokerGVK = metav1.GroupVersionKind{
+		Group:   "eventing.knative.dev",
+		Version: "v1beta1",
+		Kind:    "Broker",
+	}
+
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	publishEventType = EventType{
+		Type:        v1beta1.CloudPubSubSourcePublish,
+		Source:      "//pubsub.googleapis.com/projects/project/topics/topic",
+		Description: "publish",
+	}
+
+	otherEventType = EventType{
+		Type:   "com.example.other",
+		Schema: "https://example.com/schema.json",
+	}
+)
+
+func TestReconcileEventTypes(t *testing.T) {
+	brokerSource := NewCloudPubSubSource(sourceName, testNS,
+		WithCloudPubSubSourceSink(brokerGVK, testBroker))
+	sinkSource := NewCloudPubSubSource(sourceName, testNS,
+		WithCloudPubSubSourceSink(sinkGVK, testBroker))
+	otherNamespaceSource := NewCloudPubSubSource(sourceName, testNS,
+		WithCloudPubSubSourceSink(brokerGVK, testBroker))
+	otherNamespaceSource.Spec.Sink.Ref.Namespace = "other"
+
+	testCases := []struct {
+		name       string
+		source     *v1beta1.CloudPubSubSource
+		eventTypes []EventType
+		objects    []runtime.Object
+		want       []*eventingv1beta1.EventType
+	}{{
+		name:       "sink is a broker",
+		source:     brokerSource,
+		eventTypes: []EventType{publishEventType, otherEventType},
+		want: []*eventingv1beta1.EventType{
+			makeEventType(brokerSource, publishEventType),
+			makeEventType(brokerSource, otherEventType),
+		},
+	}, {
+		name:       "sink is not a broker",
+		source:     sinkSource,
+		eventTypes: []EventType{publishEventType},
+	}, {
+		name:       "sink is a broker in another namespace",
+		source:     otherNamespaceSource,
+		eventTypes: []EventType{publishEventType},
+	}, {
+		name:       "event types are up to date",
+		source:     brokerSource,
+		eventTypes: []EventType{publishEventType},
+		objects: []runtime.Object{
+			makeEventType(brokerSource, publishEventType),
+		},
+		want: []*eventingv1beta1.EventType{
+			makeEventType(brokerSource, publishEventType),
+		},
+	}, {
+		name:       "event type is updated",
+		source:     brokerSource,
+		eventTypes: []EventType{publishEventType},
+		objects: []runtime.Object{
+			makeEventType(brokerSource, EventType{Type: publishEventType.Type, Description: "stale"}),
+		},
+		want: []*eventingv1beta1.EventType{
+			makeEventType(brokerSource, publishEventType),
+		},
+	}, {
+		name:       "event type no longer emitted is deleted",
+		source:     brokerSource,
+		eventTypes: []EventType{publishEventType},
+		objects: []runtime.Object{
+			makeEventType(brokerSource, publishEventType),
+			makeEventType(brokerSource, otherEventType),
+		},
+		want: []*eventingv1beta1.EventType{
+			makeEventType(brokerSource, publishEventType),
+		},
+	}, {
+		name:       "event types are deleted when the sink is no longer a broker",
+		source:     sinkSource,
+		eventTypes: []EventType{publishEventType},
+		objects: []runtime.Object{
+			makeEventType(brokerSource, publishEventType),
+		},
+	}, {
+		name:       "event types of other sources are left alone",
+		source:     sinkSource,
+		eventTypes: []EventType{publishEventType},
+		objects: []runtime.Object{
+			makeEventType(NewCloudStorageSource(sourceName, testNS), publishEventType),
+		},
+		want: []*eventingv1beta1.EventType{
+			makeEventType(NewCloudStorageSource(sourceName, testNS), publishEventType),
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := fakeeventingclientset.NewSimpleClientset(tc.objects...)
+			e := &EventTypes{eventingClient: cs}
+
+			if err := e.ReconcileEventTypes(context.Background(), tc.source, tc.eventTypes); err != nil {
+				t.Fatalf("ReconcileEventTypes() = %v", err)
+			}
+
+			list, err := cs.EventingV1beta1().EventTypes(testNS).List(metav1.ListOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []*eventingv1beta1.EventType
+			for i := range list.Items {
+				got = append(got, &list.Items[i])
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].Spec.Type > got[j].Spec.Type })
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected EventTypes (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileEventTypesInvalidURL(t *testing.T) {
+	source := NewCloudPubSubSource(sourceName, testNS,
+		WithCloudPubSubSourceSink(brokerGVK, testBroker))
+	e := &EventTypes{eventingClient: fakeeventingclientset.NewSimpleClientset()}
+
+	err := e.ReconcileEventTypes(context.Background(), source, []EventType{{
+		Type:   v1beta1.CloudPubSubSourcePublish,
+		Schema: "://invalid",
+	}})
+	if err == nil {
+		t.Error("ReconcileEventTypes() = nil, want error")
+	}
+}
+
+func makeEventType(owner kmeta.OwnerRefable, et EventType) *eventingv1beta1.EventType {
+	args := &resources.EventTypeArgs{
+		Owner:       owner,
+		Broker:      testBroker,
+		Type:        et.Type,
+		Description: et.Description,
+	}
+	args.Source, _ = apis.ParseURL(et.Source)
+	args.Schema, _ = apis.ParseURL(et.Schema)
+	return resources.MakeEventType(args)
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"crypto/md5"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+)
+
+// EventTypeArgs are the arguments needed to create an EventType registered
+// for an event type emitted by a source.
+type EventTypeArgs struct {
+	Owner       kmeta.OwnerRefable
+	Broker      string
+	Type        string
+	Source      *apis.URL
+	Schema      *apis.URL
+	Description string
+}
+
+// MakeEventType generates (but does not insert into K8s) the EventType
+// registering an event type emitted by the owner source into the broker.
+func MakeEventType(args *EventTypeArgs) *eventingv1beta1.EventType {
+	owner := args.Owner.GetObjectMeta()
+	return &eventingv1beta1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       owner.GetNamespace(),
+			Name:            GenerateEventTypeName(owner.GetName(), args.Type),
+			Labels:          GetLabels(owner.GetName()),
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(args.Owner)},
+		},
+		Spec: eventingv1beta1.EventTypeSpec{
+			Type:        args.Type,
+			Source:      args.Source,
+			Schema:      args.Schema,
+			Broker:      args.Broker,
+			Description: args.Description,
+		},
+	}
+}
+
+// GenerateEventTypeName generates the name of the EventType registered by
+// the source with the given name for eventType. CloudEvent types are not
+// valid resource names, so the type is hashed.
+func GenerateEventTypeName(source, eventType string) string {
+	return kmeta.ChildName(source+"-", fmt.Sprintf("%x", md5.Sum([]byte(eventType))))
+}
+
+// GetLabels returns the labels of the EventTypes registered by the source
+// with the given name.
+func GetLabels(source string) map[string]string {
+	return map[string]string{
+		intevents.SourceLabelKey: source,
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	"knative.dev/pkg/apis"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+func TestMakeEventType(t *testing.T) {
+	source := &v1beta1.CloudStorageSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "storage-name",
+			Namespace: "storage-namespace",
+			UID:       "storage-uid",
+		},
+	}
+	eventSource, _ := apis.ParseURL(v1beta1.CloudStorageSourceEventSource("bucket"))
+	schema, _ := apis.ParseURL("https://example.com/schema.json")
+
+	got := MakeEventType(&EventTypeArgs{
+		Owner:       source,
+		Broker:      "default",
+		Type:        v1beta1.CloudStorageSourceFinalize,
+		Source:      eventSource,
+		Schema:      schema,
+		Description: "finalize",
+	})
+
+	yes := true
+	want := &eventingv1beta1.EventType{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "storage-namespace",
+			Name:      GenerateEventTypeName("storage-name", v1beta1.CloudStorageSourceFinalize),
+			Labels: map[string]string{
+				"events.cloud.google.com/source-name": "storage-name",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "events.cloud.google.com/v1beta1",
+				Kind:               "CloudStorageSource",
+				Name:               "storage-name",
+				UID:                "storage-uid",
+				Controller:         &yes,
+				BlockOwnerDeletion: &yes,
+			}},
+		},
+		Spec: eventingv1beta1.EventTypeSpec{
+			Type:        v1beta1.CloudStorageSourceFinalize,
+			Source:      eventSource,
+			Schema:      schema,
+			Broker:      "default",
+			Description: "finalize",
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected EventType (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateEventTypeName(t *testing.T) {
+	finalize := GenerateEventTypeName("storage-name", v1beta1.CloudStorageSourceFinalize)
+	if want := "storage-name-"; finalize[:len(want)] != want {
+		t.Errorf("GenerateEventTypeName() = %q, want prefix %q", finalize, want)
+	}
+	if got := GenerateEventTypeName("storage-name", v1beta1.CloudStorageSourceFinalize); got != finalize {
+		t.Errorf("GenerateEventTypeName() = %q, want stable name %q", got, finalize)
+	}
+	if archive := GenerateEventTypeName("storage-name", v1beta1.CloudStorageSourceArchive); archive == finalize {
+		t.Errorf("GenerateEventTypeName() = %q for different event types", archive)
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"os"
+	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const (
+	// FencingTokenEnvKey is the environment variable with the fencing token
+	// of the controller. If it is unset, the name of the GKE cluster the
+	// controller runs in is used.
+	FencingTokenEnvKey = "FENCING_TOKEN"
+
+	// FencingTokenLabel is the label of the Pub/Sub topics and subscriptions
+	// holding the fencing token of the controller that created them. Pub/Sub
+	// Lite topics and subscriptions have no labels, so it is a status
+	// annotation of the object they were created for instead.
+	FencingTokenLabel = "events-fencing-token"
+
+	// FencedReason is the reason of the events reporting that an external
+	// resource was left alone because another controller created it.
+	FencedReason = "ExternalResourceFenced"
+
+	maxLabelValueLength = 63
+)
+
+// fencingToken holds the fencing token of the controller. Fencing is disabled
+// while it is empty.
+var fencingToken atomic.Value
+
+// InitFencingToken sets the fencing token of the controller from the
+// environment, or from the cluster name in the metadata server when running
+// on GCE. A controller started from a backup of the cluster, or from a cloned
+// cluster, runs in another GKE cluster and so has another token, which keeps
+// it from deleting the Pub/Sub resources of the live cluster. Fencing is
+// disabled if the token cannot be found.
+func InitFencingToken(client metadataClient.Client) error {
+	token := os.Getenv(FencingTokenEnvKey)
+	if token == "" && (utils.ClusterNameOverride() != "" || client.OnGCE()) {
+		clusterName, err := utils.ClusterName("", client)
+		if err != nil {
+			return err
+		}
+		token = clusterName
+	}
+	SetFencingToken(token)
+	return nil
+}
+
+// SetFencingToken sets the fencing token of the controller. An empty token
+// disables fencing.
+func SetFencingToken(token string) {
+	fencingToken.Store(sanitizeLabelValue(token))
+}
+
+// FencingToken returns the fencing token of the controller, or an empty
+// string if fencing is disabled.
+func FencingToken() string {
+	token, _ := fencingToken.Load().(string)
+	return token
+}
+
+// WithFencingLabel returns a copy of the labels of a new external resource
+// with the fencing token of the controller.
+func WithFencingLabel(labels map[string]string) map[string]string {
+	token := FencingToken()
+	if token == "" {
+		return labels
+	}
+	withToken := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		withToken[k] = v
+	}
+	withToken[FencingTokenLabel] = token
+	return withToken
+}
+
+// Fenced returns true if the labels of an external resource hold the fencing
+// token of another controller, in which case the resource must not be deleted.
+// Resources created before fencing was enabled, or by a controller without a
+// fencing token, are not fenced.
+func Fenced(labels map[string]string) bool {
+	token := FencingToken()
+	owner, ok := labels[FencingTokenLabel]
+	return ok && token != "" && owner != token
+}
+
+// RecordFencingToken records the fencing token of the controller in the
+// status annotations of an object after it created an external resource of
+// the object that has no labels, such as a Pub/Sub Lite topic or
+// subscription. Fenced then tells whether the resource may be deleted given
+// the status annotations.
+func RecordFencingToken(status *duckv1.Status) {
+	token := FencingToken()
+	if token == "" {
+		return
+	}
+	if status.Annotations == nil {
+		status.Annotations = make(map[string]string, 1)
+	}
+	status.Annotations[FencingTokenLabel] = token
+}
+
+// RecordFenced records a warning event on obj reporting that the external
+// resource id of the given kind was left alone because it is fenced.
+func RecordFenced(recorder record.EventRecorder, obj runtime.Object, kind, id string) {
+	recorder.Eventf(obj, corev1.EventTypeWarning, FencedReason,
+		"Not deleting %s %q, it was created by the controller of another cluster", kind, id)
+}
+
+// sanitizeLabelValue turns s into a valid label value of Google Cloud
+// resources: at most 63 lowercase letters, digits, underscores and dashes.
+func sanitizeLabelValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '_'
+		}
+	}, s)
+	if len(s) > maxLabelValueLength {
+		s = s[:maxLabelValueLength]
+	}
+	return s
+}
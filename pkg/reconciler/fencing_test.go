@@ -0,0 +1,121 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	metadatatesting "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+)
+
+func TestInitFencingToken(t *testing.T) {
+	defer os.Setenv(FencingTokenEnvKey, os.Getenv(FencingTokenEnvKey))
+	defer SetFencingToken("")
+	tests := []struct {
+		name    string
+		env     string
+		data    metadatatesting.TestClientData
+		want    string
+		wantErr bool
+	}{
+		{name: "cluster name", want: metadatatesting.FakeClusterName},
+		{name: "from environment", env: "My.Cluster", want: "my_cluster"},
+		{name: "metadata error", data: metadatatesting.TestClientData{ClusterNameErr: errors.New("unavailable")}, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetFencingToken("")
+			os.Setenv(FencingTokenEnvKey, tc.env)
+			err := InitFencingToken(metadatatesting.NewTestClient(tc.data))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("InitFencingToken() = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := FencingToken(); got != tc.want {
+				t.Errorf("FencingToken() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFenced(t *testing.T) {
+	defer SetFencingToken("")
+	tests := []struct {
+		name   string
+		token  string
+		labels map[string]string
+		want   bool
+	}{
+		{name: "fencing disabled", labels: map[string]string{FencingTokenLabel: "other"}, want: false},
+		{name: "same token", token: "live", labels: map[string]string{FencingTokenLabel: "live"}, want: false},
+		{name: "other token", token: "live", labels: map[string]string{FencingTokenLabel: "other"}, want: true},
+		{name: "unlabeled", token: "live", labels: map[string]string{"name": "broker"}, want: false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			SetFencingToken(tc.token)
+			if got := Fenced(tc.labels); got != tc.want {
+				t.Errorf("Fenced() = %t, want: %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWithFencingLabel(t *testing.T) {
+	defer SetFencingToken("")
+	labels := map[string]string{"name": "broker"}
+
+	SetFencingToken("")
+	if diff := cmp.Diff(labels, WithFencingLabel(labels)); diff != "" {
+		t.Errorf("WithFencingLabel() without token (-want,+got): %v", diff)
+	}
+
+	SetFencingToken(strings.Repeat("a", 100))
+	want := map[string]string{"name": "broker", FencingTokenLabel: strings.Repeat("a", maxLabelValueLength)}
+	if diff := cmp.Diff(want, WithFencingLabel(labels)); diff != "" {
+		t.Errorf("WithFencingLabel() (-want,+got): %v", diff)
+	}
+	if _, ok := labels[FencingTokenLabel]; ok {
+		t.Error("WithFencingLabel() modified its argument")
+	}
+}
+
+func TestRecordFencingToken(t *testing.T) {
+	defer SetFencingToken("")
+	var status duckv1.Status
+
+	SetFencingToken("")
+	RecordFencingToken(&status)
+	if status.Annotations != nil {
+		t.Errorf("RecordFencingToken() without token set annotations %v", status.Annotations)
+	}
+
+	SetFencingToken("live")
+	RecordFencingToken(&status)
+	if diff := cmp.Diff(map[string]string{FencingTokenLabel: "live"}, status.Annotations); diff != "" {
+		t.Errorf("RecordFencingToken() (-want,+got): %v", diff)
+	}
+	SetFencingToken("clone")
+	if !Fenced(status.Annotations) {
+		t.Error("Fenced() = false for the status recorded by another controller")
+	}
+}
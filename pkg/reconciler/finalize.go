@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/logging"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+const (
+	// FinalizeTimeoutEnvKey is the environment variable with the maximum time,
+	// as a Go duration, spent deleting an external resource while finalizing
+	// an object, retries included.
+	FinalizeTimeoutEnvKey = "FINALIZE_TIMEOUT"
+
+	defaultFinalizeTimeout = 30 * time.Second
+
+	externalResourceAbandoned = "ExternalResourceAbandoned"
+)
+
+// finalizeBackoff bounds the retries of a failed deletion, on top of the
+// finalize timeout.
+var finalizeBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    5,
+	Cap:      5 * time.Second,
+}
+
+// FinalizeTimeout returns the finalize timeout set in the environment, or the
+// default if it is unset or invalid.
+func FinalizeTimeout(ctx context.Context) time.Duration {
+	v := os.Getenv(FinalizeTimeoutEnvKey)
+	if v == "" {
+		return defaultFinalizeTimeout
+	}
+	timeout, err := time.ParseDuration(v)
+	if err != nil || timeout <= 0 {
+		logging.FromContext(ctx).Desugar().Warn("Invalid finalize timeout, using the default",
+			zap.String("value", v), zap.Duration("default", defaultFinalizeTimeout))
+		return defaultFinalizeTimeout
+	}
+	return timeout
+}
+
+// FinalizeExternal deletes the external resource id of the given kind (e.g.
+// "Pub/Sub topic") while finalizing obj. del is retried on transient errors
+// until it succeeds, the retries are exhausted or the finalize timeout
+// elapses, so that an outage of the external API doesn't block the
+// reconciler indefinitely.
+//
+// If del still fails and obj has the AbandonOnFinalizeFailureAnnotation set
+// to "true", the resource is abandoned: the failure is logged and recorded as
+// a warning event on obj, and nil is returned so that the finalizer can be
+// removed. Abandoned resources must be cleaned up outside the cluster.
+func FinalizeExternal(ctx context.Context, recorder record.EventRecorder, obj runtime.Object, kind, id string, del func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, FinalizeTimeout(ctx))
+	defer cancel()
+
+	err := deleteWithRetries(ctx, del)
+	if err == nil || IsPlannedChange(err) || !abandonOnFailure(obj) {
+		return err
+	}
+
+	logging.FromContext(ctx).Desugar().Warn("Abandoning external resource after failing to delete it",
+		zap.String("kind", kind), zap.String("id", id), zap.Bool("abandoned", true), zap.Error(err))
+	recorder.Eventf(obj, corev1.EventTypeWarning, externalResourceAbandoned, "Abandoned %s %q after failing to delete it: %v", kind, id, err)
+	return nil
+}
+
+func deleteWithRetries(ctx context.Context, del func(context.Context) error) error {
+	backoff := finalizeBackoff
+	for {
+		err := del(ctx)
+		if err == nil || !retryable(err) || backoff.Steps == 0 {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// retryable returns true if err is a transient error of a Google Cloud API.
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal:
+		return true
+	}
+	return false
+}
+
+func abandonOnFailure(obj runtime.Object) bool {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return accessor.GetAnnotations()[duckv1beta1.AbandonOnFinalizeFailureAnnotation] == "true"
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func TestFinalizeExternal(t *testing.T) {
+	// Retry without waiting.
+	defer func(b wait.Backoff) { finalizeBackoff = b }(finalizeBackoff)
+	finalizeBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 3}
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	permissionDenied := status.Error(codes.PermissionDenied, "permission denied")
+	abandonable := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{duckv1beta1.AbandonOnFinalizeFailureAnnotation: "true"},
+	}}
+
+	tests := []struct {
+		name         string
+		obj          *corev1.Namespace
+		errs         []error
+		wantErr      error
+		wantAttempts int
+		wantEvents   []string
+	}{{
+		name:         "deleted",
+		obj:          obj,
+		wantAttempts: 1,
+	}, {
+		name:         "retried until deleted",
+		obj:          obj,
+		errs:         []error{unavailable, unavailable},
+		wantAttempts: 3,
+	}, {
+		name:         "retries exhausted",
+		obj:          obj,
+		errs:         []error{unavailable, unavailable, unavailable, unavailable, unavailable},
+		wantErr:      unavailable,
+		wantAttempts: 4,
+	}, {
+		name:         "not retryable",
+		obj:          obj,
+		errs:         []error{permissionDenied},
+		wantErr:      permissionDenied,
+		wantAttempts: 1,
+	}, {
+		name:         "abandoned",
+		obj:          abandonable,
+		errs:         []error{permissionDenied},
+		wantAttempts: 1,
+		wantEvents:   []string{`Warning ExternalResourceAbandoned Abandoned Pub/Sub topic "test-topic" after failing to delete it: rpc error: code = PermissionDenied desc = permission denied`},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(len(tc.wantEvents))
+			attempts := 0
+			err := FinalizeExternal(context.Background(), recorder, tc.obj, "Pub/Sub topic", "test-topic", func(context.Context) error {
+				attempts++
+				if attempts <= len(tc.errs) {
+					return tc.errs[attempts-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("Unexpected error, got: %v, want: %v", err, tc.wantErr)
+			}
+			if attempts != tc.wantAttempts {
+				t.Errorf("Unexpected attempts, got: %d, want: %d", attempts, tc.wantAttempts)
+			}
+			for _, want := range tc.wantEvents {
+				if got := <-recorder.Events; got != want {
+					t.Errorf("Unexpected event recorded, got: %v, want: %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFinalizeExternalTimeout(t *testing.T) {
+	defer os.Setenv(FinalizeTimeoutEnvKey, os.Getenv(FinalizeTimeoutEnvKey))
+	os.Setenv(FinalizeTimeoutEnvKey, "10ms")
+
+	err := FinalizeExternal(context.Background(), record.NewFakeRecorder(0), obj, "Pub/Sub topic", "test-topic", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Unexpected error, got: %v, want: %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestFinalizeTimeout(t *testing.T) {
+	defer os.Setenv(FinalizeTimeoutEnvKey, os.Getenv(FinalizeTimeoutEnvKey))
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: defaultFinalizeTimeout},
+		{value: "2m", want: 2 * time.Minute},
+		{value: "invalid", want: defaultFinalizeTimeout},
+		{value: "-1s", want: defaultFinalizeTimeout},
+	}
+	for _, tc := range tests {
+		os.Setenv(FinalizeTimeoutEnvKey, tc.value)
+		if got := FinalizeTimeout(context.Background()); got != tc.want {
+			t.Errorf("FinalizeTimeout() with %q = %v, want: %v", tc.value, got, tc.want)
+		}
+	}
+}
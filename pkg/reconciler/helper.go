@@ -19,96 +19,91 @@ package reconciler
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/dynamic"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/record"
+
+	"github.com/google/knative-gcp/pkg/reconciler/apply"
 )
 
 const (
 	deploymentCreated = "DeploymentCreated"
 	deploymentUpdated = "DeploymentUpdated"
+	deploymentAdopted = "DeploymentAdopted"
 	serviceCreated    = "ServiceCreated"
 	serviceUpdated    = "ServiceUpdated"
+	serviceAdopted    = "ServiceAdopted"
 )
 
+// ShouldAdopt returns true if the existing object has no controller but the
+// desired one does. This happens when an object was restored from a backup
+// (e.g. by velero) or recreated without its owner references, in which case
+// the owner of the desired object should adopt it instead of failing or
+// leaving it orphaned.
+func ShouldAdopt(existing, desired metav1.Object) bool {
+	return metav1.GetControllerOf(existing) == nil && metav1.GetControllerOf(desired) != nil
+}
+
 type ServiceReconciler struct {
-	KubeClient      kubernetes.Interface
+	DynamicClient   dynamic.Interface
 	ServiceLister   corev1listers.ServiceLister
 	EndpointsLister corev1listers.EndpointsLister
 	Recorder        record.EventRecorder
 }
 
 type DeploymentReconciler struct {
-	KubeClient kubernetes.Interface
-	Lister     appsv1listers.DeploymentLister
-	Recorder   record.EventRecorder
+	DynamicClient dynamic.Interface
+	Lister        appsv1listers.DeploymentLister
+	Recorder      record.EventRecorder
 }
 
-// ReconcileDeployment reconciles the K8s Deployment 'd'.
+// ReconcileDeployment reconciles the K8s Deployment 'd'. The deployment is
+// server-side applied, so fields set by others are preserved.
 func (r *DeploymentReconciler) ReconcileDeployment(obj runtime.Object, d *appsv1.Deployment) (*appsv1.Deployment, error) {
+	reason, action := deploymentUpdated, "Updated"
 	current, err := r.Lister.Deployments(d.Namespace).Get(d.Name)
-	if apierrs.IsNotFound(err) {
-		current, err = r.KubeClient.AppsV1().Deployments(d.Namespace).Create(d)
-		if apierrs.IsAlreadyExists(err) {
-			return current, nil
-		}
-		if err == nil {
-			r.Recorder.Eventf(obj, corev1.EventTypeNormal, deploymentCreated, "Created deployment %s/%s", d.Namespace, d.Name)
-		}
-		return current, err
-	}
-	if err != nil {
+	switch {
+	case apierrs.IsNotFound(err):
+		reason, action = deploymentCreated, "Created"
+	case err != nil:
 		return nil, err
+	case ShouldAdopt(current, d):
+		reason, action = deploymentAdopted, "Adopted"
+	case !apply.Changed(d, current, d.Spec, current.Spec):
+		return current, nil
 	}
-	if !equality.Semantic.DeepDerivative(d.Spec, current.Spec) {
-		// Don't modify the informers copy.
-		desired := current.DeepCopy()
-		desired.Spec = d.Spec
-		d, err := r.KubeClient.AppsV1().Deployments(desired.Namespace).Update(desired)
-		if err == nil {
-			r.Recorder.Eventf(obj, corev1.EventTypeNormal, deploymentUpdated, "Updated deployment %s/%s", d.Namespace, d.Name)
-		}
-		return d, err
+
+	applied := &appsv1.Deployment{}
+	if err := apply.Apply(r.DynamicClient, d, applied); err != nil {
+		return nil, err
 	}
-	return current, err
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, reason, "%s deployment %s/%s", action, d.Namespace, d.Name)
+	return applied, nil
 }
 
-// ReconcileService reconciles the K8s Service 'svc'.
+// ReconcileService reconciles the K8s Service 'svc'. The service is
+// server-side applied, so fields set by others are preserved.
 func (r *ServiceReconciler) ReconcileService(obj runtime.Object, svc *corev1.Service) (*corev1.Endpoints, error) {
+	reason, action := serviceUpdated, "Updated"
 	current, err := r.ServiceLister.Services(svc.Namespace).Get(svc.Name)
-
-	if apierrs.IsNotFound(err) {
-		current, err = r.KubeClient.CoreV1().Services(svc.Namespace).Create(svc)
-		if err == nil {
-			r.Recorder.Eventf(obj, corev1.EventTypeNormal, serviceCreated, "Created service %s/%s", svc.Namespace, svc.Name)
-		}
-		if err == nil || apierrs.IsAlreadyExists(err) {
-			return r.EndpointsLister.Endpoints(svc.Namespace).Get(svc.Name)
-		}
+	switch {
+	case apierrs.IsNotFound(err):
+		reason, action = serviceCreated, "Created"
+	case err != nil:
 		return nil, err
+	case ShouldAdopt(current, svc):
+		reason, action = serviceAdopted, "Adopted"
+	case !apply.Changed(svc, current, svc.Spec, current.Spec):
+		return r.EndpointsLister.Endpoints(svc.Namespace).Get(svc.Name)
 	}
-	if err != nil {
+
+	if err := apply.Apply(r.DynamicClient, svc, &corev1.Service{}); err != nil {
 		return nil, err
 	}
-	// spec.clusterIP is immutable and is set on existing services. If we don't set this to the same value, we will
-	// encounter an error while updating.
-	svc.Spec.ClusterIP = current.Spec.ClusterIP
-	if !equality.Semantic.DeepDerivative(svc.Spec, current.Spec) {
-		// Don't modify the informers copy.
-		desired := current.DeepCopy()
-		desired.Spec = svc.Spec
-		current, err = r.KubeClient.CoreV1().Services(current.Namespace).Update(desired)
-		if err == nil {
-			r.Recorder.Eventf(obj, corev1.EventTypeNormal, serviceUpdated, "Updated service %s/%s", svc.Namespace, svc.Name)
-		}
-		if err != nil {
-			return nil, err
-		}
-	}
-
+	r.Recorder.Eventf(obj, corev1.EventTypeNormal, reason, "%s service %s/%s", action, svc.Namespace, svc.Name)
 	return r.EndpointsLister.Endpoints(svc.Namespace).Get(svc.Name)
 }
@@ -27,7 +27,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 	pkgreconcilertesting "knative.dev/pkg/reconciler/testing"
 )
@@ -37,37 +38,104 @@ const (
 	deploymentUpdatedEvent = "Normal DeploymentUpdated Updated deployment testns/test"
 	serviceCreatedEvent    = "Normal ServiceCreated Created service testns/test"
 	serviceUpdatedEvent    = "Normal ServiceUpdated Updated service testns/test"
+	deploymentAdoptedEvent = "Normal DeploymentAdopted Adopted deployment testns/test"
+	serviceAdoptedEvent    = "Normal ServiceAdopted Adopted service testns/test"
 )
 
 var (
 	// obj can be anything that implements runtime.Object. In real reconcilers this should be the object being reconciled.
 	obj = &corev1.Namespace{}
 
+	deploymentType = metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+	serviceType    = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+
 	deployment = &appsv1.Deployment{
+		TypeMeta:   deploymentType,
 		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 		Spec:       appsv1.DeploymentSpec{MinReadySeconds: 10},
 	}
 	deploymentDifferentSpec = &appsv1.Deployment{
+		TypeMeta:   deploymentType,
 		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 		Spec:       appsv1.DeploymentSpec{MinReadySeconds: 20},
 	}
 
 	service = &corev1.Service{
+		TypeMeta:   serviceType,
 		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
 	}
 	serviceDifferentSpec = &corev1.Service{
+		TypeMeta:   serviceType,
 		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeNodePort},
 	}
+	// owner is the controller of the owned deployment and service, otherOwner
+	// controls an unrelated deployment with the same name.
+	owner = &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "owner", UID: "owner-uid"},
+	}
+	otherOwner = &appsv1.Deployment{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "other", UID: "other-uid"},
+	}
+	ownedDeployment = &appsv1.Deployment{
+		TypeMeta: deploymentType,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "testns",
+			Name:            "test",
+			Labels:          map[string]string{"app": "test"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, owner.GroupVersionKind())},
+		},
+		Spec: appsv1.DeploymentSpec{MinReadySeconds: 10},
+	}
+	orphanDeployment = &appsv1.Deployment{
+		TypeMeta: deploymentType,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "testns",
+			Name:      "test",
+			Labels:    map[string]string{"restored": "true"},
+		},
+		Spec: appsv1.DeploymentSpec{MinReadySeconds: 10},
+	}
+	otherOwnedDeployment = &appsv1.Deployment{
+		TypeMeta: deploymentType,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "testns",
+			Name:            "test",
+			Labels:          map[string]string{"app": "test"},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(otherOwner, otherOwner.GroupVersionKind())},
+		},
+		Spec: appsv1.DeploymentSpec{MinReadySeconds: 10},
+	}
+	adoptedDeployment = &appsv1.Deployment{
+		TypeMeta: deploymentType,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "testns",
+			Name:            "test",
+			Labels:          map[string]string{"app": "test", "restored": "true"},
+			OwnerReferences: ownedDeployment.OwnerReferences,
+		},
+		Spec: appsv1.DeploymentSpec{MinReadySeconds: 10},
+	}
+
+	ownedService = &corev1.Service{
+		TypeMeta: serviceType,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "testns",
+			Name:            "test",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(owner, owner.GroupVersionKind())},
+		},
+		Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeClusterIP},
+	}
+
 	endPoints = &corev1.Endpoints{
 		ObjectMeta: metav1.ObjectMeta{Namespace: "testns", Name: "test"},
 	}
 
-	deploymentCreateFailure = pkgreconcilertesting.InduceFailure("create", "deployments")
-	deploymentUpdateFailure = pkgreconcilertesting.InduceFailure("update", "deployments")
-	serviceCreateFailure    = pkgreconcilertesting.InduceFailure("create", "services")
-	serviceUpdateFailure    = pkgreconcilertesting.InduceFailure("update", "services")
+	deploymentApplyFailure = pkgreconcilertesting.InduceFailure("patch", "deployments")
+	serviceApplyFailure    = pkgreconcilertesting.InduceFailure("patch", "services")
 
 	tr = &testRunner{}
 )
@@ -106,7 +174,7 @@ func TestDeploymentReconciler(t *testing.T) {
 		{
 			commonCase: commonCase{
 				name:      "deployment creation error",
-				reactions: []clientgotesting.ReactionFunc{deploymentCreateFailure},
+				reactions: []clientgotesting.ReactionFunc{deploymentApplyFailure},
 				wantErr:   true,
 			},
 			in: deployment,
@@ -120,10 +188,27 @@ func TestDeploymentReconciler(t *testing.T) {
 			in:   deployment,
 			want: deployment,
 		},
+		{
+			commonCase: commonCase{
+				name:       "orphan deployment adopted",
+				existing:   []runtime.Object{orphanDeployment},
+				wantEvents: []string{deploymentAdoptedEvent},
+			},
+			in:   ownedDeployment,
+			want: adoptedDeployment,
+		},
+		{
+			commonCase: commonCase{
+				name:     "deployment controlled by another owner is not adopted",
+				existing: []runtime.Object{otherOwnedDeployment},
+			},
+			in:   ownedDeployment,
+			want: otherOwnedDeployment,
+		},
 		{
 			commonCase: commonCase{
 				name:      "deployment update error",
-				reactions: []clientgotesting.ReactionFunc{deploymentUpdateFailure},
+				reactions: []clientgotesting.ReactionFunc{deploymentApplyFailure},
 				existing:  []runtime.Object{deploymentDifferentSpec},
 				wantErr:   true,
 			},
@@ -136,9 +221,9 @@ func TestDeploymentReconciler(t *testing.T) {
 			tr.setup(test.commonCase)
 
 			rec := DeploymentReconciler{
-				KubeClient: tr.client,
-				Lister:     tr.listers.GetDeploymentLister(),
-				Recorder:   tr.recorder,
+				DynamicClient: tr.client,
+				Lister:        tr.listers.GetDeploymentLister(),
+				Recorder:      tr.recorder,
 			}
 			out, err := rec.ReconcileDeployment(obj, test.in)
 
@@ -178,7 +263,7 @@ func TestServiceReconciler(t *testing.T) {
 		{
 			commonCase: commonCase{
 				name:      "service creation error",
-				reactions: []clientgotesting.ReactionFunc{serviceCreateFailure},
+				reactions: []clientgotesting.ReactionFunc{serviceApplyFailure},
 				wantErr:   true,
 			},
 			in: service,
@@ -192,10 +277,28 @@ func TestServiceReconciler(t *testing.T) {
 			in:   service,
 			want: endPoints,
 		},
+		{
+			commonCase: commonCase{
+				name:       "orphan service adopted",
+				existing:   []runtime.Object{service, endPoints},
+				wantEvents: []string{serviceAdoptedEvent},
+			},
+			in:   ownedService,
+			want: endPoints,
+		},
+		{
+			commonCase: commonCase{
+				name:      "service adoption error",
+				reactions: []clientgotesting.ReactionFunc{serviceApplyFailure},
+				existing:  []runtime.Object{service, endPoints},
+				wantErr:   true,
+			},
+			in: ownedService,
+		},
 		{
 			commonCase: commonCase{
 				name:      "service update error",
-				reactions: []clientgotesting.ReactionFunc{serviceUpdateFailure},
+				reactions: []clientgotesting.ReactionFunc{serviceApplyFailure},
 				existing:  []runtime.Object{serviceDifferentSpec, endPoints},
 				wantErr:   true,
 			},
@@ -208,7 +311,7 @@ func TestServiceReconciler(t *testing.T) {
 			tr.setup(test.commonCase)
 
 			rec := ServiceReconciler{
-				KubeClient:      tr.client,
+				DynamicClient:   tr.client,
 				ServiceLister:   tr.listers.GetK8sServiceLister(),
 				EndpointsLister: tr.listers.GetEndpointsLister(),
 				Recorder:        tr.recorder,
@@ -225,16 +328,19 @@ func TestServiceReconciler(t *testing.T) {
 	}
 }
 
-// testRunner helps to setup resources such as fake KubeClientSet and informers, as well as verify the common test case.
+// testRunner helps to setup resources such as fake DynamicClientSet and informers, as well as verify the common test case.
 type testRunner struct {
-	client   *fake.Clientset
+	client   *dynamicfake.FakeDynamicClient
 	listers  reconcilertesting.Listers
 	recorder *record.FakeRecorder
 }
 
 func (r *testRunner) setup(cc commonCase) {
 	objs := append(cc.existing)
-	r.client = fake.NewSimpleClientset(objs...)
+	s := runtime.NewScheme()
+	_ = kubescheme.AddToScheme(s)
+	r.client = dynamicfake.NewSimpleDynamicClient(s, objs...)
+	r.client.PrependReactor("patch", "*", reconcilertesting.ApplyPatchReactor(s, objs...))
 	for _, reaction := range cc.reactions {
 		r.client.PrependReactor("*", "*", reaction)
 	}
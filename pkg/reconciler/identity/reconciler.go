@@ -25,9 +25,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/ptr"
@@ -35,6 +37,7 @@ import (
 	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
 	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/resources"
 	"github.com/google/knative-gcp/pkg/utils"
@@ -102,6 +105,12 @@ func (i *Identity) ReconcileWorkloadIdentity(ctx context.Context, projectID stri
 	}
 
 	// Add iam policy binding to GCP ServiceAccount.
+	if reconciler.DryRun(identifiable) {
+		message := planChange(ctx, identifiable, "Would bind Kubernetes service account %s/%s to Google service account %s",
+			identityNames.Namespace, identityNames.KServiceAccountName, identityNames.GoogleServiceAccountName)
+		status.MarkWorkloadIdentityNotConfigured(identifiable.ConditionSet(), reconciler.DryRunReason, "%s", message)
+		return kServiceAccount, nil
+	}
 	if err := i.addIamPolicyBinding(ctx, projectID, identityNames); err != nil {
 		status.MarkWorkloadIdentityFailed(identifiable.ConditionSet(), workloadIdentityFailed, err.Error())
 		return kServiceAccount, fmt.Errorf("adding iam policy binding failed with: %w", err)
@@ -140,6 +149,11 @@ func (i *Identity) DeleteWorkloadIdentity(ctx context.Context, projectID string,
 		return fmt.Errorf("getting k8s service account failed with: %w", err)
 	}
 	if kServiceAccount != nil && len(kServiceAccount.OwnerReferences) == 1 {
+		if reconciler.DryRun(identifiable) {
+			planChange(ctx, identifiable, "Would unbind Kubernetes service account %s/%s from Google service account %s",
+				identityNames.Namespace, identityNames.KServiceAccountName, identityNames.GoogleServiceAccountName)
+			return nil
+		}
 		logging.FromContext(ctx).Desugar().Debug("Removing iam policy binding.")
 		if err := i.removeIamPolicyBinding(ctx, projectID, identityNames); err != nil {
 			status.MarkWorkloadIdentityFailed(identifiable.ConditionSet(), deleteWorkloadIdentityFailed, err.Error())
@@ -210,6 +224,18 @@ func (i *Identity) removeIamPolicyBinding(ctx context.Context, projectID string,
 	return i.policyManager.RemoveIAMPolicyBinding(ctx, iam.GServiceAccount(identityNames.GoogleServiceAccountName), currentMember, Role)
 }
 
+// planChange reports an IAM change planned in dry-run mode as an event on
+// identifiable and returns its description.
+func planChange(ctx context.Context, identifiable duck.Identifiable, format string, args ...interface{}) string {
+	planned := reconciler.PlannedChange(ctx, format, args...)
+	if obj, ok := identifiable.(runtime.Object); ok {
+		if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+			recorder.Event(obj, corev1.EventTypeNormal, reconciler.DryRunReason, planned.Error())
+		}
+	}
+	return planned.Error()
+}
+
 // ownerReferenceExists checks if a K8s ServiceAccount contains specific ownerReference
 func ownerReferenceExists(kServiceAccount *corev1.ServiceAccount, expect metav1.OwnerReference) bool {
 	references := kServiceAccount.OwnerReferences
@@ -0,0 +1,202 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dryrun dry-run creates, at admission, the Topic and PullSubscription
+// the reconciler of a source would create, so that child-level validation
+// errors surface when the source is applied rather than in its status once it
+// is reconciled.
+package dryrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/webhook"
+	"knative.dev/pkg/webhook/resourcesemantics/validation"
+
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/resources"
+)
+
+// ModeEnvKey is the environment variable of the webhook that enables the
+// admission-time dry run of the children of sources.
+const ModeEnvKey = "SOURCE_CHILD_DRY_RUN"
+
+// Mode is the mode of the admission-time dry run.
+type Mode string
+
+const (
+	// ModeDisabled disables the dry run.
+	ModeDisabled Mode = ""
+	// ModeWarn logs a warning for a source whose children are rejected but
+	// admits the source.
+	ModeWarn Mode = "warn"
+	// ModeReject rejects a source whose children are rejected.
+	ModeReject Mode = "reject"
+)
+
+// ModeFromEnv returns the mode set in the environment. Unknown modes disable
+// the dry run.
+func ModeFromEnv() Mode {
+	switch m := Mode(os.Getenv(ModeEnvKey)); m {
+	case ModeWarn, ModeReject:
+		return m
+	default:
+		return ModeDisabled
+	}
+}
+
+// Source describes the children the reconciler of a source creates.
+type Source struct {
+	// New returns an empty v1beta1 object of the source.
+	New func() duck.PubSubable
+	// Old returns an empty v1alpha1 object of the source, converted to
+	// v1beta1 before its children are made. It is nil for sources that only
+	// exist in v1beta1.
+	Old func() apis.Convertible
+	// Topic returns the Pub/Sub topic the source subscribes to.
+	Topic func(duck.PubSubable) string
+	// CreatesTopic is whether a Topic is created along with the
+	// PullSubscription, i.e. whether the reconciler calls ReconcilePubSub.
+	CreatesTopic bool
+	// PushCompatible is whether the PullSubscription is in push-compatible
+	// mode.
+	PushCompatible bool
+	// AdapterType is the adapter type of the PullSubscription.
+	AdapterType string
+	// ReceiveAdapterName is the name of the receive adapter of the source,
+	// labeling its children.
+	ReceiveAdapterName string
+	// ResourceGroup is the resource group of the source, recorded on its
+	// PullSubscription for the metrics.
+	ResourceGroup string
+}
+
+// DryRunner dry-run creates the children of sources.
+type DryRunner struct {
+	client dynamic.Interface
+	mode   Mode
+}
+
+// NewDryRunner returns a DryRunner creating the children with client. It
+// returns nil if mode is ModeDisabled.
+func NewDryRunner(client dynamic.Interface, mode Mode) *DryRunner {
+	if mode == ModeDisabled {
+		return nil
+	}
+	return &DryRunner{client: client, mode: mode}
+}
+
+// Callback returns the validation callback dry-running the children of a
+// newly created src. In ModeReject, it returns an error when the API server
+// rejects a child as invalid, and it logs a warning in ModeWarn. Children
+// that could not be dry-run, e.g. because one with the same name already
+// exists, are admitted.
+func (d *DryRunner) Callback(src Source) validation.Callback {
+	return validation.NewCallback(func(ctx context.Context, u *unstructured.Unstructured) error {
+		return d.check(ctx, src, u)
+	}, webhook.Create)
+}
+
+func (d *DryRunner) check(ctx context.Context, src Source, u *unstructured.Unstructured) error {
+	logger := logging.FromContext(ctx)
+	s, err := decode(ctx, src, u)
+	if err != nil {
+		logger.Debugw("Unable to decode the source", zap.Error(err))
+		return nil
+	}
+	err = d.dryRun(s, src)
+	if err == nil {
+		return nil
+	}
+	var statusErr *apierrs.StatusError
+	if !errors.As(err, &statusErr) || !apierrs.IsInvalid(statusErr) && !apierrs.IsBadRequest(statusErr) {
+		logger.Debugw("Unable to dry run the children of the source", zap.Error(err))
+		return nil
+	}
+	if d.mode == ModeWarn {
+		logger.Warnw("Children of the source are invalid", zap.Error(err))
+		return nil
+	}
+	return err
+}
+
+func decode(ctx context.Context, src Source, u *unstructured.Unstructured) (duck.PubSubable, error) {
+	s := src.New()
+	if u.GroupVersionKind().Version == s.GetGroupVersionKind().Version || src.Old == nil {
+		return s, runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, s)
+	}
+	old := src.Old()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, old); err != nil {
+		return nil, err
+	}
+	to, ok := s.(apis.Convertible)
+	if !ok {
+		return nil, fmt.Errorf("%T is not convertible", s)
+	}
+	return s, old.ConvertTo(ctx, to)
+}
+
+func (d *DryRunner) dryRun(s duck.PubSubable, src Source) error {
+	args := &resources.SourceArgs{
+		Topic:              src.Topic(s),
+		ReceiveAdapterName: src.ReceiveAdapterName,
+		ResourceGroup:      src.ResourceGroup,
+		AdapterType:        src.AdapterType,
+		PushCompatible:     src.PushCompatible,
+	}
+	if src.CreatesTopic {
+		if err := d.create(resources.MakeSourceTopic(s, args), "topics", "Topic"); err != nil {
+			return fmt.Errorf("invalid Topic: %w", err)
+		}
+	}
+	if err := d.create(resources.MakeSourcePullSubscription(s, args), "pullsubscriptions", "PullSubscription"); err != nil {
+		return fmt.Errorf("invalid PullSubscription: %w", err)
+	}
+	return nil
+}
+
+// create dry-run creates obj, ignoring an existing object of the same name.
+func (d *DryRunner) create(obj metav1.Object, resource, kind string) error {
+	// The source is not persisted yet, and the webhook may not block its
+	// deletion.
+	obj.SetOwnerReferences(nil)
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	u := &unstructured.Unstructured{Object: m}
+	u.SetGroupVersionKind(inteventsv1beta1.SchemeGroupVersion.WithKind(kind))
+
+	_, err = d.client.Resource(inteventsv1beta1.SchemeGroupVersion.WithResource(resource)).
+		Namespace(u.GetNamespace()).
+		Create(u, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if apierrs.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
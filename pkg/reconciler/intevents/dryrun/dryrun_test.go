@@ -0,0 +1,211 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dryrun
+
+import (
+	"os"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+)
+
+const (
+	testNS    = "testnamespace"
+	testName  = "test"
+	testTopic = "test-topic"
+)
+
+var testSource = Source{
+	New: func() duck.PubSubable { return &v1beta1.CloudPubSubSource{} },
+	Old: func() apis.Convertible { return &v1alpha1.CloudPubSubSource{} },
+	Topic: func(s duck.PubSubable) string {
+		return s.(*v1beta1.CloudPubSubSource).Spec.Topic
+	},
+	PushCompatible: true,
+}
+
+func TestModeFromEnv(t *testing.T) {
+	old, ok := os.LookupEnv(ModeEnvKey)
+	defer func() {
+		if ok {
+			os.Setenv(ModeEnvKey, old)
+		} else {
+			os.Unsetenv(ModeEnvKey)
+		}
+	}()
+
+	for value, want := range map[string]Mode{
+		"":        ModeDisabled,
+		"warn":    ModeWarn,
+		"reject":  ModeReject,
+		"invalid": ModeDisabled,
+	} {
+		os.Setenv(ModeEnvKey, value)
+		if got := ModeFromEnv(); got != want {
+			t.Errorf("ModeFromEnv() with %q got=%q, want=%q", value, got, want)
+		}
+	}
+}
+
+func TestNewDryRunnerDisabled(t *testing.T) {
+	if d := NewDryRunner(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), ModeDisabled); d != nil {
+		t.Errorf("NewDryRunner() got=%v, want=nil", d)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mode          Mode
+		src           Source
+		apiVersion    string
+		objects       []runtime.Object
+		createErr     error
+		wantErr       bool
+		wantResources []string
+	}{{
+		name:          "pullsubscription only",
+		mode:          ModeReject,
+		src:           testSource,
+		apiVersion:    v1beta1.SchemeGroupVersion.String(),
+		wantResources: []string{"pullsubscriptions"},
+	}, {
+		name: "topic and pullsubscription",
+		mode: ModeReject,
+		src: func() Source {
+			s := testSource
+			s.CreatesTopic = true
+			return s
+		}(),
+		apiVersion:    v1beta1.SchemeGroupVersion.String(),
+		wantResources: []string{"topics", "pullsubscriptions"},
+	}, {
+		name:          "converted from v1alpha1",
+		mode:          ModeReject,
+		src:           testSource,
+		apiVersion:    v1alpha1.SchemeGroupVersion.String(),
+		wantResources: []string{"pullsubscriptions"},
+	}, {
+		name:          "invalid child rejected",
+		mode:          ModeReject,
+		src:           testSource,
+		apiVersion:    v1beta1.SchemeGroupVersion.String(),
+		createErr:     apierrs.NewBadRequest("admission webhook denied the request"),
+		wantErr:       true,
+		wantResources: []string{"pullsubscriptions"},
+	}, {
+		name:          "invalid child warned",
+		mode:          ModeWarn,
+		src:           testSource,
+		apiVersion:    v1beta1.SchemeGroupVersion.String(),
+		createErr:     apierrs.NewBadRequest("admission webhook denied the request"),
+		wantResources: []string{"pullsubscriptions"},
+	}, {
+		name:          "child not dry run",
+		mode:          ModeReject,
+		src:           testSource,
+		apiVersion:    v1beta1.SchemeGroupVersion.String(),
+		createErr:     apierrs.NewServiceUnavailable("unavailable"),
+		wantResources: []string{"pullsubscriptions"},
+	}, {
+		name:       "child already exists",
+		mode:       ModeReject,
+		src:        testSource,
+		apiVersion: v1beta1.SchemeGroupVersion.String(),
+		objects: []runtime.Object{&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": inteventsv1beta1.SchemeGroupVersion.String(),
+			"kind":       "PullSubscription",
+			"metadata": map[string]interface{}{
+				"namespace": testNS,
+				"name":      testName,
+			},
+		}}},
+		wantResources: []string{"pullsubscriptions"},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tc.objects...)
+			if tc.createErr != nil {
+				client.PrependReactor("create", "*", func(clientgotesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tc.createErr
+				})
+			}
+			d := NewDryRunner(client, tc.mode)
+
+			u := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": tc.apiVersion,
+				"kind":       "CloudPubSubSource",
+				"metadata": map[string]interface{}{
+					"namespace": testNS,
+					"name":      testName,
+				},
+				"spec": map[string]interface{}{
+					"topic": testTopic,
+				},
+			}}
+			ctx := logtesting.TestContextWithLogger(t)
+			err := d.check(ctx, tc.src, u)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("check() got error %v, want error=%v", err, tc.wantErr)
+			}
+
+			var gotResources []string
+			for _, action := range client.Actions() {
+				create, ok := action.(clientgotesting.CreateAction)
+				if !ok {
+					t.Fatalf("unexpected action %v", action)
+				}
+				gotResources = append(gotResources, create.GetResource().Resource)
+				obj := create.GetObject().(*unstructured.Unstructured)
+				if obj.GetNamespace() != testNS || obj.GetName() != testName {
+					t.Errorf("created %s/%s, want %s/%s", obj.GetNamespace(), obj.GetName(), testNS, testName)
+				}
+				if len(obj.GetOwnerReferences()) != 0 {
+					t.Errorf("created %s with owner references %v", obj.GetKind(), obj.GetOwnerReferences())
+				}
+				if topic, _, _ := unstructured.NestedString(obj.Object, "spec", "topic"); topic != testTopic {
+					t.Errorf("created %s with topic %q, want %q", obj.GetKind(), topic, testTopic)
+				}
+				if create.GetResource().Resource == "pullsubscriptions" {
+					if mode, _, _ := unstructured.NestedString(obj.Object, "spec", "mode"); mode != string(inteventsv1beta1.ModePushCompatible) {
+						t.Errorf("created PullSubscription with mode %q, want %q", mode, inteventsv1beta1.ModePushCompatible)
+					}
+				}
+			}
+			if len(gotResources) != len(tc.wantResources) {
+				t.Fatalf("created %v, want %v", gotResources, tc.wantResources)
+			}
+			for i := range gotResources {
+				if gotResources[i] != tc.wantResources[i] {
+					t.Errorf("created %v, want %v", gotResources, tc.wantResources)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"context"
+	"encoding/json"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
+)
+
+// reconcileAgentSubscription hands ps to the receive adapter agent and deletes
+// the receive adapter Deployment ps had before switching to the agent. The
+// messages the Deployment didn't pull wait in the subscription for the agent.
+func (r *Base) reconcileAgentSubscription(ctx context.Context, ps *v1beta1.PullSubscription, args *resources.ReceiveAdapterArgs) error {
+	b, err := json.Marshal(resources.MakeAgentSubscription(ctx, args))
+	if err != nil {
+		return err
+	}
+	if err := r.updateAgentConfigMap(ctx, resources.AgentSubscriptionKey(ps), string(b)); err != nil {
+		return err
+	}
+
+	existing, err := r.getReceiveAdapter(ctx, ps)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil {
+		logging.FromContext(ctx).Desugar().Info("Deleting the receive adapter replaced by the agent", zap.String("deployment", existing.Name))
+		err := r.KubeClientSet.AppsV1().Deployments(existing.Namespace).Delete(existing.Name, &metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	ps.Status.MarkDeployedByAgent()
+	return nil
+}
+
+// deleteAgentSubscription takes ps away from the receive adapter agent.
+func (r *Base) deleteAgentSubscription(ctx context.Context, ps *v1beta1.PullSubscription) error {
+	return r.updateAgentConfigMap(ctx, resources.AgentSubscriptionKey(ps), "")
+}
+
+// updateAgentConfigMap sets the value of key in the agent ConfigMap, creating
+// the ConfigMap if needed. An empty value deletes the key.
+func (r *Base) updateAgentConfigMap(ctx context.Context, key, value string) error {
+	configMaps := r.KubeClientSet.CoreV1().ConfigMaps(system.Namespace())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := configMaps.Get(resources.AgentConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if value == "" {
+				return nil
+			}
+			_, err = configMaps.Create(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: system.Namespace(),
+					Name:      resources.AgentConfigMapName,
+				},
+				Data: map[string]string{key: value},
+			})
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		if current, ok := cm.Data[key]; current == value && (ok || value == "") {
+			return nil
+		}
+		cm = cm.DeepCopy()
+		if value == "" {
+			delete(cm.Data, key)
+		} else {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string, 1)
+			}
+			cm.Data[key] = value
+		}
+		_, err = configMaps.Update(cm)
+		return err
+	})
+}
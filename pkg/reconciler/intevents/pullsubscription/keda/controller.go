@@ -32,11 +32,14 @@ import (
 	pullsubscriptioninformers "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/pullsubscription"
 	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1beta1/pullsubscription"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
+	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
+	"github.com/google/knative-gcp/pkg/utils/platform"
 	"github.com/kelseyhightower/envconfig"
 
 	eventingduck "knative.dev/eventing/pkg/duck"
@@ -65,6 +68,22 @@ const (
 type envConfig struct {
 	// ReceiveAdapter is the receive adapters image. Required.
 	ReceiveAdapter string `envconfig:"PUBSUB_RA_IMAGE" required:"true"`
+
+	// MetadataPropagation selects the annotations and labels copied from
+	// PullSubscriptions onto their receive adapters.
+	resources.MetadataPropagation
+
+	// ProxyDefaults are the proxy settings for receive adapters whose
+	// PullSubscriptions don't set their own.
+	resources.ProxyDefaults
+
+	// NodeArchitectures are the CPU architectures of the nodes receive
+	// adapters run on.
+	platform.NodeArchitectures
+
+	// SubscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// subscriptions.
+	resources.SubscriptionNaming
 }
 
 type Constructor injection.ControllerConstructor
@@ -92,6 +111,9 @@ func newController(
 	if err := envconfig.Process("", &env); err != nil {
 		logger.Fatal("Failed to process env var", zap.Error(err))
 	}
+	if err := env.SubscriptionNaming.Validate(); err != nil {
+		logger.Fatal("Invalid Pub/Sub subscription naming", zap.Error(err))
+	}
 
 	pubsubBase := &intevents.PubSubBase{
 		Base: reconciler.NewBase(ctx, controllerAgentName, cmw),
@@ -104,7 +126,12 @@ func newController(
 			DeploymentLister:       deploymentInformer.Lister(),
 			PullSubscriptionLister: pullSubscriptionInformer.Lister(),
 			ReceiveAdapterImage:    env.ReceiveAdapter,
+			MetadataPropagation:    env.MetadataPropagation,
+			DefaultProxy:           env.ProxyDefaults.Spec(),
+			NodeArchitectures:      env.NodeArchitectures,
+			SubscriptionNaming:     env.SubscriptionNaming,
 			CreateClientFn:         gpubsub.NewClient,
+			CreateLiteClientFn:     gpubsublite.NewAdminClient,
 			ControllerAgentName:    controllerAgentName,
 			ResourceGroup:          resourceGroup,
 		},
@@ -132,6 +159,7 @@ func newController(
 	})
 
 	r.UriResolver = resolver.NewURIResolver(ctx, impl.EnqueueKey)
+	r.EnqueueAfter = impl.EnqueueAfter
 	r.ReconcileDataPlaneFn = r.ReconcileScaledObject
 	r.scaledObjectTracker = eventingduck.NewListableTracker(ctx, resource.Get, impl.EnqueueKey, controller.GetTrackerLease(ctx))
 	r.discoveryFn = discovery.ServerSupportsVersion
@@ -27,7 +27,6 @@ import (
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/keda/resources"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -70,20 +69,16 @@ func (r *Reconciler) ReconcileScaledObject(ctx context.Context, ra *appsv1.Deplo
 		}
 	}
 
+	// Given than the Deployment replicas will be controlled by Keda, leave
+	// them out of the applied fields so we don't take ownership of them.
+	ra.Spec.Replicas = nil
 	existing, err := r.Base.GetOrCreateReceiveAdapter(ctx, ra, src)
 	if err != nil {
 		return err
 	}
-	// Given than the Deployment replicas will be controlled by Keda, we assume
-	// the replica count from the existing one is the correct one.
-	ra.Spec.Replicas = existing.Spec.Replicas
-	if !equality.Semantic.DeepEqual(ra.Spec, existing.Spec) {
-		existing.Spec = ra.Spec
-		existing, err = r.KubeClientSet.AppsV1().Deployments(src.Namespace).Update(existing)
-		if err != nil {
-			logging.FromContext(ctx).Desugar().Error("Error updating Receive Adapter", zap.Error(err))
-			return err
-		}
+	existing, err = r.Base.UpdateReceiveAdapter(ctx, ra, existing)
+	if err != nil {
+		return err
 	}
 
 	src.Status.PropagateDeploymentAvailability(existing)
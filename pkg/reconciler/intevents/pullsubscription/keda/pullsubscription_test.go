@@ -87,7 +87,11 @@ var (
 	transformerDNS = transformerName + ".mynamespace.svc.cluster.local"
 	transformerURI = apis.HTTP(transformerDNS)
 
-	testSubscriptionID = fmt.Sprintf("cre-ps_%s_%s_%s", testNS, sourceName, sourceUID)
+	testSubscriptionID   = fmt.Sprintf("cre-ps_%s_%s_%s", testNS, sourceName, sourceUID)
+	testSubscriptionName = fmt.Sprintf("projects/%s/subscriptions/%s", testProject, testSubscriptionID)
+
+	subscriptionCreatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", "Created Pub/Sub subscription %q: https://console.cloud.google.com/cloudpubsub/subscription/detail/%s?project=%s",
+		testSubscriptionName, testSubscriptionID, testProject)
 
 	sinkGVK = metav1.GroupVersionKind{
 		Group:   "testing.cloud.google.com",
@@ -539,6 +543,7 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
 		OtherTestData: map[string]interface{}{
@@ -550,7 +555,6 @@ func TestAllCases(t *testing.T) {
 		},
 		WantCreates: []runtime.Object{
 			newScaledObject(newPullSubscription(testSubscriptionID)),
-			newReceiveAdapter(context.Background(), testImage, nil),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
@@ -573,10 +577,12 @@ func TestAllCases(t *testing.T) {
 				// Updates
 				WithPullSubscriptionStatusObservedGeneration(generation),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkNoDeployed(deploymentName(testSubscriptionID), testNS),
 			),
 		}},
 		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newAppliedReceiveAdapter(context.Background(), testImage, nil)),
 			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 	}, {
@@ -612,6 +618,7 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
@@ -630,6 +637,7 @@ func TestAllCases(t *testing.T) {
 				WithPullSubscriptionProjectID(testProject),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkDeployed(deploymentName(testSubscriptionID), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
@@ -675,16 +683,9 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			ActionImpl: clientgotesting.ActionImpl{
-				Namespace: testNS,
-				Verb:      "update",
-				Resource:  receiveAdapterGVR(),
-			},
-			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
-		}},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -702,6 +703,7 @@ func TestAllCases(t *testing.T) {
 				WithPullSubscriptionSink(sinkGVK, sinkName),
 				WithPullSubscriptionTransformer(transformerGVK, transformerName),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkNoDeployed(deploymentName(testSubscriptionID), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionMarkTransformer(transformerURI),
@@ -709,6 +711,7 @@ func TestAllCases(t *testing.T) {
 			),
 		}},
 		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newAppliedReceiveAdapter(context.Background(), testImage, transformerURI)),
 			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 	}, {
@@ -727,6 +730,7 @@ func TestAllCases(t *testing.T) {
 				}),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkDeployed(deploymentName(testSubscriptionID), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionDeleted,
@@ -765,6 +769,7 @@ func TestAllCases(t *testing.T) {
 				}),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkDeployed(deploymentName(testSubscriptionID), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionSubscriptionID(""),
@@ -858,6 +863,14 @@ func newAvailableReceiveAdapter(ctx context.Context, image string, transformer *
 	return obj
 }
 
+// newAppliedReceiveAdapter returns the receive adapter as it is applied by the
+// reconciler, with the replicas left to Keda.
+func newAppliedReceiveAdapter(ctx context.Context, image string, transformer *apis.URL) runtime.Object {
+	obj := newReceiveAdapter(ctx, image, transformer)
+	obj.(*v1.Deployment).Spec.Replicas = nil
+	return obj
+}
+
 func newScaledObject(ps *pubsubv1beta1.PullSubscription) runtime.Object {
 	ctx := context.Background()
 	ra := newReceiveAdapter(ctx, testImage, nil)
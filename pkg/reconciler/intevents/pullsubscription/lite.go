@@ -0,0 +1,203 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/pubsublite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// reconcileLiteSubscription is reconcileSubscription for a PullSubscription of
+// a Pub/Sub Lite topic. Pub/Sub Lite resources have no labels, so unlike
+// Cloud Pub/Sub resources the fencing token of the controller that created
+// them is recorded in the status of the PullSubscription. The subscription
+// has no settings to update once it is created.
+func (r *Base) reconcileLiteSubscription(ctx context.Context, ps *v1beta1.PullSubscription) (string, error) {
+	lc := ps.Spec.LiteConfig
+	client, err := r.CreateLiteClientFn(ctx, lc.Location)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+		return "", err
+	}
+	defer client.Close()
+
+	subID := ps.Status.SubscriptionID
+	if subID == "" {
+		subID, err = r.SubscriptionNaming.SubscriptionName(ps)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// Validation guarantees that the topic is in the project of the subscription.
+	_, topicID, err := utils.ParseTopic(ps.Spec.Topic)
+	if err != nil {
+		return "", err
+	}
+	topicPath := gpubsublite.TopicPath(ps.Status.ProjectID, lc.Location, topicID)
+	if _, err := client.Topic(ctx, topicPath); gpubsublite.IsNotFound(err) {
+		if !ps.Spec.CreateTopic {
+			return "", fmt.Errorf("Topic %q does not exist", topicPath)
+		}
+		if err := r.createLiteTopic(ctx, ps, client, topicPath, topicID); err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub Lite topic exists", zap.Error(err))
+		return "", err
+	}
+
+	subPath := gpubsublite.SubscriptionPath(ps.Status.ProjectID, lc.Location, subID)
+	if _, err := client.Subscription(ctx, subPath); err == nil {
+		return subID, nil
+	} else if !gpubsublite.IsNotFound(err) {
+		logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub Lite subscription exists", zap.Error(err))
+		return "", err
+	}
+	if kgcpreconciler.DryRun(ps) {
+		return "", kgcpreconciler.PlannedChange(ctx, "Would create Pub/Sub Lite subscription %q", subPath)
+	}
+	cfg := pubsublite.SubscriptionConfig{
+		Name:  subPath,
+		Topic: topicPath,
+		// The receive adapter acknowledges messages once they are delivered,
+		// so it doesn't need to wait for them to be stored.
+		DeliveryRequirement: pubsublite.DeliverImmediately,
+	}
+	if _, err := client.CreateSubscription(ctx, cfg); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub Lite subscription", zap.Error(err))
+		return "", err
+	}
+	kgcpreconciler.RecordFencingToken(&ps.Status.Status)
+	r.Recorder.Eventf(ps, corev1.EventTypeNormal, subscriptionCreatedReason, "Created Pub/Sub Lite subscription %q", subPath)
+	return subID, nil
+}
+
+// createLiteTopic creates the missing Pub/Sub Lite topic of a PullSubscription
+// with CreateTopic, with the capacity of its LiteConfig, and records it in the
+// status so that its deletion policy applies on finalize.
+func (r *Base) createLiteTopic(ctx context.Context, ps *v1beta1.PullSubscription, client gpubsublite.AdminClient, topicPath, topicID string) error {
+	if kgcpreconciler.DryRun(ps) {
+		return kgcpreconciler.PlannedChange(ctx, "Would create Pub/Sub Lite topic %q", topicPath)
+	}
+	lc := ps.Spec.LiteConfig
+	cfg := pubsublite.TopicConfig{
+		Name:                       topicPath,
+		PartitionCount:             lc.GetPartitions(),
+		PublishCapacityMiBPerSec:   lc.GetPublishMiBPerSec(),
+		SubscribeCapacityMiBPerSec: lc.GetSubscribeMiBPerSec(),
+		PerPartitionBytes:          lc.GetPerPartitionBytes(),
+		RetentionDuration:          ps.Spec.GetRetentionDuration(),
+	}
+	if _, err := client.CreateTopic(ctx, cfg); err != nil {
+		// The topic may have been created since it was checked, in which
+		// case it is used but not claimed.
+		if gstatus.Code(err) == codes.AlreadyExists {
+			return nil
+		}
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub Lite topic", zap.Error(err))
+		return err
+	}
+	ps.Status.CreatedTopicID = topicID
+	kgcpreconciler.RecordFencingToken(&ps.Status.Status)
+	r.Recorder.Eventf(ps, corev1.EventTypeNormal, topicCreatedReason, "Created Pub/Sub Lite topic %q", topicPath)
+	return nil
+}
+
+// deleteLiteSubscription is deleteSubscription for a PullSubscription of a
+// Pub/Sub Lite topic.
+func (r *Base) deleteLiteSubscription(ctx context.Context, ps *v1beta1.PullSubscription) error {
+	lc := ps.Spec.LiteConfig
+	client, err := r.CreateLiteClientFn(ctx, lc.Location)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	subPath := gpubsublite.SubscriptionPath(ps.Status.ProjectID, lc.Location, ps.Status.SubscriptionID)
+	return kgcpreconciler.FinalizeExternal(ctx, r.Recorder, ps, "Pub/Sub Lite subscription", ps.Status.SubscriptionID, func(ctx context.Context) error {
+		if _, err := client.Subscription(ctx, subPath); gpubsublite.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub Lite subscription exists", zap.Error(err))
+			return err
+		}
+		if kgcpreconciler.Fenced(ps.Status.Annotations) {
+			logging.FromContext(ctx).Desugar().Warn("Not deleting Pub/Sub Lite subscription created by another cluster",
+				zap.String("owner", ps.Status.Annotations[kgcpreconciler.FencingTokenLabel]))
+			kgcpreconciler.RecordFenced(r.Recorder, ps, "Pub/Sub Lite subscription", ps.Status.SubscriptionID)
+			return nil
+		}
+		if kgcpreconciler.DryRun(ps) {
+			return kgcpreconciler.PlannedChange(ctx, "Would delete Pub/Sub Lite subscription %q", subPath)
+		}
+		if err := client.DeleteSubscription(ctx, subPath); err != nil && !gpubsublite.IsNotFound(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub Lite subscription", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// deleteLiteTopic is deleteTopic for a PullSubscription of a Pub/Sub Lite
+// topic.
+func (r *Base) deleteLiteTopic(ctx context.Context, ps *v1beta1.PullSubscription) error {
+	lc := ps.Spec.LiteConfig
+	client, err := r.CreateLiteClientFn(ctx, lc.Location)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	topicPath := gpubsublite.TopicPath(ps.Status.ProjectID, lc.Location, ps.Status.CreatedTopicID)
+	return kgcpreconciler.FinalizeExternal(ctx, r.Recorder, ps, "Pub/Sub Lite topic", ps.Status.CreatedTopicID, func(ctx context.Context) error {
+		if _, err := client.Topic(ctx, topicPath); gpubsublite.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub Lite topic exists", zap.Error(err))
+			return err
+		}
+		if kgcpreconciler.Fenced(ps.Status.Annotations) {
+			logging.FromContext(ctx).Desugar().Warn("Not deleting Pub/Sub Lite topic created by another cluster",
+				zap.String("owner", ps.Status.Annotations[kgcpreconciler.FencingTokenLabel]))
+			kgcpreconciler.RecordFenced(r.Recorder, ps, "Pub/Sub Lite topic", ps.Status.CreatedTopicID)
+			return nil
+		}
+		if kgcpreconciler.DryRun(ps) {
+			return kgcpreconciler.PlannedChange(ctx, "Would delete Pub/Sub Lite topic %q", topicPath)
+		}
+		if err := client.DeleteTopic(ctx, topicPath); err != nil && !gpubsublite.IsNotFound(err) {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub Lite topic", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
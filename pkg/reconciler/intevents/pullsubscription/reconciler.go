@@ -19,12 +19,17 @@ package pullsubscription
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -42,13 +47,18 @@ import (
 	"knative.dev/pkg/resolver"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/apply"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	"github.com/google/knative-gcp/pkg/tracing"
+	"github.com/google/knative-gcp/pkg/utils/platform"
 )
 
 const (
@@ -57,10 +67,14 @@ const (
 	channelComponent = "channel"
 
 	deletePubSubFailedReason        = "SubscriptionDeleteFailed"
+	deleteTopicFailedReason         = "TopicDeleteFailed"
 	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
 	reconciledPubSubFailedReason    = "SubscriptionReconcileFailed"
 	reconciledDataPlaneFailedReason = "DataPlaneReconcileFailed"
 	reconciledSuccessReason         = "PullSubscriptionReconciled"
+	subscriptionCreatedReason       = "SubscriptionCreated"
+	subscriptionUpdatedReason       = "SubscriptionUpdated"
+	topicCreatedReason              = "TopicCreated"
 	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
 
 	// If the topic of the subscription has been deleted, the value of its topic becomes "_deleted-topic_".
@@ -83,6 +97,18 @@ type Base struct {
 	UriResolver *resolver.URIResolver
 
 	ReceiveAdapterImage string
+	// MetadataPropagation selects the annotations and labels copied onto
+	// the receive adapter.
+	MetadataPropagation resources.MetadataPropagation
+	// DefaultProxy is the proxy configuration for receive adapters whose
+	// PullSubscriptions don't set one.
+	DefaultProxy duckv1beta1.ProxySpec
+	// NodeArchitectures are the CPU architectures of the nodes receive
+	// adapters run on.
+	NodeArchitectures platform.NodeArchitectures
+	// SubscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// subscriptions.
+	SubscriptionNaming  resources.SubscriptionNaming
 	ControllerAgentName string
 	ResourceGroup       string
 
@@ -94,8 +120,16 @@ type Base struct {
 	// This is needed so that we can inject a mock client for UTs purposes.
 	CreateClientFn gpubsub.CreateFn
 
+	// CreateLiteClientFn is the function used to create the Pub/Sub Lite
+	// admin client for the PullSubscriptions of Pub/Sub Lite topics.
+	CreateLiteClientFn gpubsublite.CreateFn
+
 	// ReconcileDataPlaneFn is the function used to reconcile the data plane resources.
 	ReconcileDataPlaneFn ReconcileDataPlaneFunc
+
+	// EnqueueAfter enqueues a PullSubscription to be reconciled again after
+	// a delay, e.g. once its catch-up ends.
+	EnqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // ReconcileDataPlaneFunc is used to reconcile the data plane component(s).
@@ -106,6 +140,7 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1beta1.PullSubscription)
 
 	ps.Status.InitializeConditions()
 	ps.Status.ObservedGeneration = ps.Generation
+	kgcpreconciler.MarkDeprecated(ctx, ps, &ps.Status, v1beta1.SchemeGroupVersion)
 
 	// If pullsubscription doesn't have ownerReference and ServiceAccountName is provided, reconcile workload identity.
 	// Otherwise, its owner will reconcile workload identity.
@@ -139,11 +174,18 @@ func (r *Base) ReconcileKind(ctx context.Context, ps *v1beta1.PullSubscription)
 	}
 
 	subscriptionID, err := r.reconcileSubscription(ctx, ps)
-	if err != nil {
+	if kgcpreconciler.IsPlannedChange(err) {
+		ps.Status.MarkNoSubscription(kgcpreconciler.DryRunReason, "%s", err.Error())
+		return err
+	} else if err != nil {
 		ps.Status.MarkNoSubscription(reconciledPubSubFailedReason, "Failed to reconcile Pub/Sub subscription: %s", err.Error())
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledPubSubFailedReason, "Failed to reconcile Pub/Sub subscription: %s", err.Error())
 	}
 	ps.Status.MarkSubscribed(subscriptionID)
+	ps.Status.SubscriptionName = utils.SubscriptionName(ps.Status.ProjectID, subscriptionID)
+	if lc := ps.Spec.LiteConfig; lc != nil {
+		ps.Status.SubscriptionName = gpubsublite.SubscriptionPath(ps.Status.ProjectID, lc.Location, subscriptionID)
+	}
 
 	err = r.reconcileDataPlaneResources(ctx, ps, r.ReconcileDataPlaneFn)
 	if err != nil {
@@ -163,6 +205,9 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 		// Set the projectID in the status.
 		ps.Status.ProjectID = projectID
 	}
+	if ps.Spec.LiteConfig != nil {
+		return r.reconcileLiteSubscription(ctx, ps)
+	}
 
 	// Auth to GCP is handled by having the GOOGLE_APPLICATION_CREDENTIALS environment variable
 	// pointing at a credential file.
@@ -173,8 +218,16 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 	}
 	defer client.Close()
 
-	// Generate the subscription name
-	subID := resources.GenerateSubscriptionName(ps)
+	// Generate the subscription name. The subscription of an existing
+	// PullSubscription is kept when the naming changes, so that its
+	// messages are not lost.
+	subID := ps.Status.SubscriptionID
+	if subID == "" {
+		subID, err = r.SubscriptionNaming.SubscriptionName(ps)
+		if err != nil {
+			return "", err
+		}
+	}
 
 	// Load the subscription.
 	sub := client.Subscription(subID)
@@ -184,7 +237,15 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 		return "", err
 	}
 
-	t := client.Topic(ps.Spec.Topic)
+	// The topic may be in another project than the subscription.
+	topicProject, topicID, err := utils.ParseTopic(ps.Spec.Topic)
+	if err != nil {
+		return "", err
+	}
+	if topicProject == "" {
+		topicProject = ps.Status.ProjectID
+	}
+	t := client.TopicInProject(topicID, topicProject)
 	topicExists, err := t.Exists(ctx)
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
@@ -192,13 +253,20 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 	}
 
 	if !topicExists {
-		return "", fmt.Errorf("Topic %q does not exist", ps.Spec.Topic)
+		if !ps.Spec.CreateTopic || topicProject != ps.Status.ProjectID {
+			return "", fmt.Errorf("Topic %q does not exist", ps.Spec.Topic)
+		}
+		if err := r.createTopic(ctx, ps, client, topicID); err != nil {
+			return "", err
+		}
 	}
 
 	// subConfig is the wanted config based on settings.
 	subConfig := gpubsub.SubscriptionConfig{
 		Topic:               t,
 		RetainAckedMessages: ps.Spec.RetainAckedMessages,
+		// Message ordering cannot be changed once the subscription is created.
+		EnableMessageOrdering: ps.Spec.EnableMessageOrdering,
 	}
 
 	if ps.Spec.AckDeadline != nil {
@@ -219,6 +287,28 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 		subConfig.RetentionDuration = retentionDuration
 	}
 
+	if rp := ps.Spec.RetryPolicy; rp != nil {
+		subConfig.RetryPolicy = &gpubsub.RetryPolicy{
+			MinimumBackoff: rp.GetMinimumBackoff(),
+			MaximumBackoff: rp.GetMaximumBackoff(),
+		}
+	}
+
+	if dlp := ps.Spec.DeadLetterPolicy; dlp != nil {
+		// The dead letter topic defaults to the project of the subscription.
+		dltProject, dltID, err := utils.ParseTopic(dlp.DeadLetterTopic)
+		if err != nil {
+			return "", err
+		}
+		if dltProject == "" {
+			dltProject = ps.Status.ProjectID
+		}
+		subConfig.DeadLetterPolicy = &pubsub.DeadLetterPolicy{
+			DeadLetterTopic:     utils.TopicName(dltProject, dltID),
+			MaxDeliveryAttempts: int(dlp.GetMaxDeliveryAttempts()),
+		}
+	}
+
 	// Check if the topic of the subscription is "_deleted-topic_"
 	if subExists {
 		config, err := sub.Config(ctx)
@@ -228,29 +318,191 @@ func (r *Base) reconcileSubscription(ctx context.Context, ps *v1beta1.PullSubscr
 		}
 		if config.Topic != nil && config.Topic.String() == deletedTopic {
 			logging.FromContext(ctx).Desugar().Error("Detected deleted topic. Going to recreate the pull subscription. Unacked messages will be lost.")
+			if kgcpreconciler.Fenced(config.Labels) {
+				kgcpreconciler.RecordFenced(r.Recorder, ps, "Pub/Sub subscription", subID)
+				return "", fmt.Errorf("topic of the subscription has been deleted, but the subscription %q was created by the controller of another cluster", subID)
+			}
+			if kgcpreconciler.DryRun(ps) {
+				return "", kgcpreconciler.PlannedChange(ctx, "Would recreate Pub/Sub subscription %q", subID)
+			}
 			// Subscription with "_deleted-topic_" cannot pull from the new topic. In order to recover, we first delete
 			// the sub and then create it. Unacked messages will be lost.
 			if err := sub.Delete(ctx); err != nil {
 				logging.FromContext(ctx).Desugar().Error("Failed to delete the _deleted-topic_ susbscription", zap.Error(err))
 				return "", fmt.Errorf("failed to delete the _deleted-topic_ susbscription: %v", err)
 			}
-			sub, err = client.CreateSubscription(ctx, subID, subConfig)
+			sub, err = client.CreateSubscription(ctx, subID, withFencingLabel(subConfig))
 			if err != nil {
 				logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
 				return "", err
 			}
+		} else if err := r.updateSubscription(ctx, ps, sub, config, subConfig); err != nil {
+			return "", err
 		}
 	} else {
-		sub, err = client.CreateSubscription(ctx, subID, subConfig)
+		if kgcpreconciler.DryRun(ps) {
+			return "", kgcpreconciler.PlannedChange(ctx, "Would create Pub/Sub subscription %q", subID)
+		}
+		sub, err = client.CreateSubscription(ctx, subID, withFencingLabel(subConfig))
 		if err != nil {
 			logging.FromContext(ctx).Desugar().Error("Failed to create subscription", zap.Error(err))
 			return "", err
 		}
+		r.Recorder.Eventf(ps, corev1.EventTypeNormal, subscriptionCreatedReason, "Created Pub/Sub subscription %q: %s",
+			utils.SubscriptionName(ps.Status.ProjectID, subID), utils.SubscriptionConsoleURL(ps.Status.ProjectID, subID))
 	}
-	// TODO update the subscription's config if needed.
 	return subID, nil
 }
 
+// createTopic creates the missing topic of a PullSubscription with
+// CreateTopic, labeled with the fencing token of the controller, and records
+// it in the status so that its deletion policy applies on finalize.
+func (r *Base) createTopic(ctx context.Context, ps *v1beta1.PullSubscription, client gpubsub.Client, topicID string) error {
+	if kgcpreconciler.DryRun(ps) {
+		return kgcpreconciler.PlannedChange(ctx, "Would create Pub/Sub topic %q", topicID)
+	}
+	cfg := &pubsub.TopicConfig{}
+	if tc := ps.Spec.TopicConfig; tc != nil {
+		cfg.Labels = tc.Labels
+		cfg.MessageStoragePolicy.AllowedPersistenceRegions = tc.AllowedPersistenceRegions
+		cfg.KMSKeyName = tc.KMSKeyName
+	}
+	cfg.Labels = kgcpreconciler.WithFencingLabel(cfg.Labels)
+	if _, err := client.CreateTopicWithConfig(ctx, topicID, cfg); err != nil {
+		// The topic may have been created since it was checked, in which
+		// case it is used but not claimed.
+		if gstatus.Code(err) == codes.AlreadyExists {
+			return nil
+		}
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub topic", zap.Error(err))
+		return err
+	}
+	ps.Status.CreatedTopicID = topicID
+	r.Recorder.Eventf(ps, corev1.EventTypeNormal, topicCreatedReason, "Created Pub/Sub topic %q: %s",
+		utils.TopicName(ps.Status.ProjectID, topicID), utils.TopicConsoleURL(ps.Status.ProjectID, topicID))
+	return nil
+}
+
+// deleteTopic deletes the topic created for the PullSubscription, if any, when
+// its deletion policy says so.
+func (r *Base) deleteTopic(ctx context.Context, ps *v1beta1.PullSubscription) error {
+	if ps.Status.CreatedTopicID == "" || ps.Spec.TopicConfig == nil || ps.Spec.TopicConfig.DeletionPolicy != v1beta1.TopicDeletionPolicyDelete {
+		return nil
+	}
+	if ps.Spec.LiteConfig != nil {
+		return r.deleteLiteTopic(ctx, ps)
+	}
+
+	client, err := r.CreateClientFn(ctx, ps.Status.ProjectID)
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to create Pub/Sub client", zap.Error(err))
+		return err
+	}
+	defer client.Close()
+
+	t := client.Topic(ps.Status.CreatedTopicID)
+	return kgcpreconciler.FinalizeExternal(ctx, r.Recorder, ps, "Pub/Sub topic", ps.Status.CreatedTopicID, func(ctx context.Context) error {
+		exists, err := t.Exists(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
+			return err
+		}
+		if !exists {
+			return nil
+		}
+		if kgcpreconciler.FencingToken() != "" {
+			config, err := t.Config(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to get Pub/Sub topic config", zap.Error(err))
+				return err
+			}
+			if kgcpreconciler.Fenced(config.Labels) {
+				logging.FromContext(ctx).Desugar().Warn("Not deleting Pub/Sub topic created by another cluster",
+					zap.String("owner", config.Labels[kgcpreconciler.FencingTokenLabel]))
+				kgcpreconciler.RecordFenced(r.Recorder, ps, "Pub/Sub topic", ps.Status.CreatedTopicID)
+				return nil
+			}
+		}
+		if kgcpreconciler.DryRun(ps) {
+			return kgcpreconciler.PlannedChange(ctx, "Would delete Pub/Sub topic %q", ps.Status.CreatedTopicID)
+		}
+		if err := t.Delete(ctx); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub topic", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+}
+
+// updateSubscription updates the mutable settings of the Pub/Sub subscription
+// that drifted from the desired config, all in a single update.
+func (r *Base) updateSubscription(ctx context.Context, ps *v1beta1.PullSubscription, sub gpubsub.Subscription, current, desired gpubsub.SubscriptionConfig) error {
+	toUpdate, changes := subscriptionConfigToUpdate(current, desired)
+	if len(changes) == 0 {
+		return nil
+	}
+	if kgcpreconciler.DryRun(ps) {
+		return kgcpreconciler.PlannedChange(ctx, "Would update Pub/Sub subscription %s: %s", sub.ID(), strings.Join(changes, ", "))
+	}
+	if _, err := sub.Update(ctx, toUpdate); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to update subscription", zap.Strings("changes", changes), zap.Error(err))
+		return err
+	}
+	r.Recorder.Eventf(ps, corev1.EventTypeNormal, subscriptionUpdatedReason, "Updated Pub/Sub subscription %s: %s", sub.ID(), strings.Join(changes, ", "))
+	return nil
+}
+
+// subscriptionConfigToUpdate returns the config to update the subscription
+// with, along with a description of each setting that drifted from the desired
+// config. Zero durations in the desired config are left unchanged, while a nil
+// retry or dead letter policy removes the one of the subscription.
+func subscriptionConfigToUpdate(current, desired gpubsub.SubscriptionConfig) (gpubsub.SubscriptionConfig, []string) {
+	var changes []string
+	toUpdate := gpubsub.SubscriptionConfig{
+		// RetainAckedMessages is always sent, so send the desired value.
+		RetainAckedMessages: desired.RetainAckedMessages,
+	}
+	if desired.AckDeadline != 0 && desired.AckDeadline != current.AckDeadline {
+		toUpdate.AckDeadline = desired.AckDeadline
+		changes = append(changes, fmt.Sprintf("ackDeadline %v -> %v", current.AckDeadline, desired.AckDeadline))
+	}
+	if desired.RetainAckedMessages != current.RetainAckedMessages {
+		changes = append(changes, fmt.Sprintf("retainAckedMessages %t -> %t", current.RetainAckedMessages, desired.RetainAckedMessages))
+	}
+	if desired.RetentionDuration != 0 && desired.RetentionDuration != current.RetentionDuration {
+		toUpdate.RetentionDuration = desired.RetentionDuration
+		changes = append(changes, fmt.Sprintf("retentionDuration %v -> %v", current.RetentionDuration, desired.RetentionDuration))
+	}
+	if desired.Labels != nil && !equality.Semantic.DeepEqual(desired.Labels, current.Labels) {
+		toUpdate.Labels = desired.Labels
+		changes = append(changes, fmt.Sprintf("labels %v -> %v", current.Labels, desired.Labels))
+	}
+	if !equality.Semantic.DeepEqual(desired.RetryPolicy, current.RetryPolicy) {
+		toUpdate.RetryPolicy = desired.RetryPolicy
+		if toUpdate.RetryPolicy == nil {
+			toUpdate.RetryPolicy = &gpubsub.RetryPolicy{}
+		}
+		changes = append(changes, fmt.Sprintf("retryPolicy %v -> %v", current.RetryPolicy, desired.RetryPolicy))
+	}
+	if !equality.Semantic.DeepEqual(desired.DeadLetterPolicy, current.DeadLetterPolicy) {
+		toUpdate.DeadLetterPolicy = desired.DeadLetterPolicy
+		if toUpdate.DeadLetterPolicy == nil {
+			toUpdate.DeadLetterPolicy = &pubsub.DeadLetterPolicy{}
+		}
+		changes = append(changes, fmt.Sprintf("deadLetterPolicy %s -> %s", deadLetterPolicyString(current.DeadLetterPolicy), deadLetterPolicyString(desired.DeadLetterPolicy)))
+	}
+	return toUpdate, changes
+}
+
+// deadLetterPolicyString describes a dead letter policy in the events listing
+// the changes to a subscription.
+func deadLetterPolicyString(dlp *pubsub.DeadLetterPolicy) string {
+	if dlp == nil {
+		return "none"
+	}
+	return fmt.Sprintf("deadLetterTopic=%s maxDeliveryAttempts=%d", dlp.DeadLetterTopic, dlp.MaxDeliveryAttempts)
+}
+
 // deleteSubscription looks at the status.SubscriptionID and if non-empty,
 // hence indicating that we have created a subscription successfully
 // in the PullSubscription, remove it.
@@ -258,6 +510,9 @@ func (r *Base) deleteSubscription(ctx context.Context, ps *v1beta1.PullSubscript
 	if ps.Status.SubscriptionID == "" {
 		return nil
 	}
+	if ps.Spec.LiteConfig != nil {
+		return r.deleteLiteSubscription(ctx, ps)
+	}
 
 	// At this point the project ID should have been populated in the status.
 	// Querying Pub/Sub as the subscription could have been deleted outside the cluster (e.g, through gcloud).
@@ -270,18 +525,44 @@ func (r *Base) deleteSubscription(ctx context.Context, ps *v1beta1.PullSubscript
 
 	// Load the subscription.
 	sub := client.Subscription(ps.Status.SubscriptionID)
-	exists, err := sub.Exists(ctx)
-	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub subscription exists", zap.Error(err))
-		return err
-	}
-	if exists {
-		if err := sub.Delete(ctx); err != nil {
-			logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub subscription", zap.Error(err))
+	return kgcpreconciler.FinalizeExternal(ctx, r.Recorder, ps, "Pub/Sub subscription", ps.Status.SubscriptionID, func(ctx context.Context) error {
+		exists, err := sub.Exists(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub subscription exists", zap.Error(err))
 			return err
 		}
-	}
-	return nil
+		if exists {
+			if kgcpreconciler.FencingToken() != "" {
+				config, err := sub.Config(ctx)
+				if err != nil {
+					logging.FromContext(ctx).Desugar().Error("Failed to get Pub/Sub subscription Config", zap.Error(err))
+					return err
+				}
+				if kgcpreconciler.Fenced(config.Labels) {
+					logging.FromContext(ctx).Desugar().Warn("Not deleting Pub/Sub subscription created by another cluster",
+						zap.String("owner", config.Labels[kgcpreconciler.FencingTokenLabel]))
+					kgcpreconciler.RecordFenced(r.Recorder, ps, "Pub/Sub subscription", ps.Status.SubscriptionID)
+					return nil
+				}
+			}
+			if kgcpreconciler.DryRun(ps) {
+				return kgcpreconciler.PlannedChange(ctx, "Would delete Pub/Sub subscription %q", ps.Status.SubscriptionID)
+			}
+			if err := sub.Delete(ctx); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub subscription", zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withFencingLabel returns the config of a new subscription, labeled with the
+// fencing token of the controller. Existing subscriptions are not relabeled, so
+// that the controller of another cluster cannot claim them.
+func withFencingLabel(cfg gpubsub.SubscriptionConfig) gpubsub.SubscriptionConfig {
+	cfg.Labels = kgcpreconciler.WithFencingLabel(cfg.Labels)
+	return cfg
 }
 
 func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1beta1.PullSubscription, f ReconcileDataPlaneFunc) error {
@@ -299,7 +580,7 @@ func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1beta1.Pull
 		r.MetricsConfig.Component = component
 	}
 
-	metricsConfig, err := metrics.MetricsOptionsToJson(r.MetricsConfig)
+	metricsConfig, err := metrics.MetricsOptionsToJson(metricsOptions(r.MetricsConfig, ps))
 	if err != nil {
 		logging.FromContext(ctx).Desugar().Error("Error serializing metrics config", zap.Error(err))
 	}
@@ -309,7 +590,7 @@ func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1beta1.Pull
 		logging.FromContext(ctx).Desugar().Error("Error serializing tracing config", zap.Error(err))
 	}
 
-	desired := resources.MakeReceiveAdapter(ctx, &resources.ReceiveAdapterArgs{
+	args := &resources.ReceiveAdapterArgs{
 		Image:            r.ReceiveAdapterImage,
 		PullSubscription: ps,
 		Labels:           resources.GetLabels(r.ControllerAgentName, ps.Name),
@@ -319,9 +600,48 @@ func (r *Base) reconcileDataPlaneResources(ctx context.Context, ps *v1beta1.Pull
 		LoggingConfig:    loggingConfig,
 		MetricsConfig:    metricsConfig,
 		TracingConfig:    tracingConfig,
-	})
 
-	return f(ctx, desired, ps)
+		MetadataPropagation: r.MetadataPropagation,
+		DefaultProxy:        r.DefaultProxy,
+		NodeArchitectures:   r.NodeArchitectures,
+	}
+	if until, ok := duckv1beta1.CatchUpUntil(ps.Annotations, time.Now()); ok {
+		args.CatchUp = true
+		// Revert the boost once the catch-up ends.
+		if r.EnqueueAfter != nil {
+			r.EnqueueAfter(ps, time.Until(until))
+		}
+	}
+	if resources.IsAgentMode(ps) {
+		return r.reconcileAgentSubscription(ctx, ps, args)
+	}
+	if ps.Status.IsDeployedByAgent() {
+		// The PullSubscription switched back from the agent. Its receive
+		// adapter is created below and the agent stops pulling shortly after.
+		if err := r.deleteAgentSubscription(ctx, ps); err != nil {
+			return err
+		}
+	}
+
+	return f(ctx, resources.MakeReceiveAdapter(ctx, args), ps)
+}
+
+// metricsOptions returns the metrics options of the receive adapter of ps. The
+// metrics backend is overridden by the MetricsBackendDestinationAnnotation of
+// ps, if set.
+func metricsOptions(opts *metrics.ExporterOptions, ps *v1beta1.PullSubscription) *metrics.ExporterOptions {
+	backend, ok := ps.Annotations[duckv1beta1.MetricsBackendDestinationAnnotation]
+	if opts == nil || !ok {
+		return opts
+	}
+	cm := make(map[string]string, len(opts.ConfigMap)+1)
+	for k, v := range opts.ConfigMap {
+		cm[k] = v
+	}
+	cm[metrics.BackendDestinationKey] = backend
+	o := *opts
+	o.ConfigMap = cm
+	return &o
 }
 
 func (r *Base) GetOrCreateReceiveAdapter(ctx context.Context, desired *appsv1.Deployment, ps *v1beta1.PullSubscription) (*appsv1.Deployment, error) {
@@ -331,8 +651,11 @@ func (r *Base) GetOrCreateReceiveAdapter(ctx context.Context, desired *appsv1.De
 		return nil, err
 	}
 	if existing == nil {
-		existing, err = r.KubeClientSet.AppsV1().Deployments(ps.Namespace).Create(desired)
-		if err != nil {
+		if adopted, err := r.adoptReceiveAdapter(ctx, desired, ps); err != nil || adopted != nil {
+			return adopted, err
+		}
+		existing = &appsv1.Deployment{}
+		if err := apply.Apply(r.DynamicClientSet, desired, existing); err != nil {
 			logging.FromContext(ctx).Desugar().Error("Error creating Receive Adapter", zap.Error(err))
 			return nil, err
 		}
@@ -340,6 +663,46 @@ func (r *Base) GetOrCreateReceiveAdapter(ctx context.Context, desired *appsv1.De
 	return existing, nil
 }
 
+// UpdateReceiveAdapter server-side applies the desired receive adapter if the
+// fields it sets differ from the existing one. Fields set by others, e.g. the
+// replicas of an autoscaled receive adapter, are left alone.
+func (r *Base) UpdateReceiveAdapter(ctx context.Context, desired, existing *appsv1.Deployment) (*appsv1.Deployment, error) {
+	if !apply.Changed(desired, existing, desired.Spec, existing.Spec) {
+		return existing, nil
+	}
+	updated := &appsv1.Deployment{}
+	if err := apply.Apply(r.DynamicClientSet, desired, updated); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Error updating Receive Adapter", zap.Error(err))
+		return nil, err
+	}
+	return updated, nil
+}
+
+// adoptReceiveAdapter adopts an existing receive adapter with the desired name
+// that has no controller, e.g. one restored from a backup without its owner
+// references. Creating the desired receive adapter would otherwise fail with
+// AlreadyExists. It returns nil if there is nothing to adopt.
+func (r *Base) adoptReceiveAdapter(ctx context.Context, desired *appsv1.Deployment, ps *v1beta1.PullSubscription) (*appsv1.Deployment, error) {
+	orphan, err := r.DeploymentLister.Deployments(desired.Namespace).Get(desired.Name)
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Unable to get an existing Receive Adapter", zap.Error(err))
+		return nil, err
+	}
+	if !kgcpreconciler.ShouldAdopt(orphan, desired) {
+		return nil, nil
+	}
+	adopted := &appsv1.Deployment{}
+	if err := apply.Apply(r.DynamicClientSet, desired, adopted); err != nil {
+		logging.FromContext(ctx).Desugar().Error("Error adopting Receive Adapter", zap.Error(err))
+		return nil, err
+	}
+	r.Recorder.Eventf(ps, corev1.EventTypeNormal, "ReceiveAdapterAdopted", "Adopted receive adapter %s/%s", adopted.Namespace, adopted.Name)
+	return adopted, nil
+}
+
 func (r *Base) getReceiveAdapter(ctx context.Context, ps *v1beta1.PullSubscription) (*appsv1.Deployment, error) {
 	dl, err := r.KubeClientSet.AppsV1().Deployments(ps.Namespace).List(metav1.ListOptions{
 		LabelSelector: resources.GetLabelSelector(r.ControllerAgentName, ps.Name).String(),
@@ -433,9 +796,25 @@ func (r *Base) FinalizeKind(ctx context.Context, ps *v1beta1.PullSubscription) r
 		}
 	}
 
+	if resources.IsAgentMode(ps) || ps.Status.IsDeployedByAgent() {
+		if err := r.deleteAgentSubscription(ctx, ps); err != nil {
+			return reconciler.NewEvent(corev1.EventTypeWarning, reconciledDataPlaneFailedReason, "Failed to remove the PullSubscription from the receive adapter agent: %s", err.Error())
+		}
+	}
+
 	logging.FromContext(ctx).Desugar().Debug("Deleting Pub/Sub subscription")
-	if err := r.deleteSubscription(ctx, ps); err != nil {
+	if err := r.deleteSubscription(ctx, ps); kgcpreconciler.IsPlannedChange(err) {
+		return err
+	} else if err != nil {
 		return reconciler.NewEvent(corev1.EventTypeWarning, deletePubSubFailedReason, "Failed to delete Pub/Sub subscription: %s", err.Error())
 	}
+
+	// The topic is deleted after the subscription, which would otherwise be
+	// left pointing at a deleted topic.
+	if err := r.deleteTopic(ctx, ps); kgcpreconciler.IsPlannedChange(err) {
+		return err
+	} else if err != nil {
+		return reconciler.NewEvent(corev1.EventTypeWarning, deleteTopicFailedReason, "Failed to delete Pub/Sub topic: %s", err.Error())
+	}
 	return nil
 }
@@ -0,0 +1,180 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pullsubscription
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/metrics"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+)
+
+func TestMetricsOptions(t *testing.T) {
+	opts := &metrics.ExporterOptions{
+		Domain:    "cloud.google.com/events",
+		Component: sourceComponent,
+		ConfigMap: map[string]string{
+			metrics.BackendDestinationKey: "stackdriver",
+			"metrics.reporting-period":    "60",
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		opts        *metrics.ExporterOptions
+		annotations map[string]string
+		want        *metrics.ExporterOptions
+	}{{
+		name: "no metrics config",
+		annotations: map[string]string{
+			duckv1beta1.MetricsBackendDestinationAnnotation: "none",
+		},
+	}, {
+		name: "no override",
+		opts: opts,
+		want: opts,
+	}, {
+		name: "backend override",
+		opts: opts,
+		annotations: map[string]string{
+			duckv1beta1.MetricsBackendDestinationAnnotation: "none",
+		},
+		want: &metrics.ExporterOptions{
+			Domain:    "cloud.google.com/events",
+			Component: sourceComponent,
+			ConfigMap: map[string]string{
+				metrics.BackendDestinationKey: "none",
+				"metrics.reporting-period":    "60",
+			},
+		},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ps := &v1beta1.PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+			}
+			if diff := cmp.Diff(tc.want, metricsOptions(tc.opts, ps)); diff != "" {
+				t.Errorf("unexpected metrics options (-want, +got) = %v", diff)
+			}
+		})
+	}
+
+	// The shared options must not be modified by the override.
+	if got := opts.ConfigMap[metrics.BackendDestinationKey]; got != "stackdriver" {
+		t.Errorf("shared metrics backend got=%q, want=%q", got, "stackdriver")
+	}
+}
+
+func TestSubscriptionConfigToUpdateDeadLetterPolicy(t *testing.T) {
+	dlp := &pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/p/topics/dead-letter", MaxDeliveryAttempts: 5}
+	testCases := []struct {
+		name        string
+		current     *pubsub.DeadLetterPolicy
+		desired     *pubsub.DeadLetterPolicy
+		wantUpdate  *pubsub.DeadLetterPolicy
+		wantChanges []string
+	}{{
+		name:    "unchanged",
+		current: dlp,
+		desired: dlp,
+	}, {
+		name:        "added",
+		desired:     dlp,
+		wantUpdate:  dlp,
+		wantChanges: []string{"deadLetterPolicy none -> deadLetterTopic=projects/p/topics/dead-letter maxDeliveryAttempts=5"},
+	}, {
+		name:       "max delivery attempts changed",
+		current:    dlp,
+		desired:    &pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/p/topics/dead-letter", MaxDeliveryAttempts: 10},
+		wantUpdate: &pubsub.DeadLetterPolicy{DeadLetterTopic: "projects/p/topics/dead-letter", MaxDeliveryAttempts: 10},
+		wantChanges: []string{
+			"deadLetterPolicy deadLetterTopic=projects/p/topics/dead-letter maxDeliveryAttempts=5 -> deadLetterTopic=projects/p/topics/dead-letter maxDeliveryAttempts=10",
+		},
+	}, {
+		name:        "removed",
+		current:     dlp,
+		wantUpdate:  &pubsub.DeadLetterPolicy{},
+		wantChanges: []string{"deadLetterPolicy deadLetterTopic=projects/p/topics/dead-letter maxDeliveryAttempts=5 -> none"},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			toUpdate, changes := subscriptionConfigToUpdate(
+				gpubsub.SubscriptionConfig{DeadLetterPolicy: tc.current},
+				gpubsub.SubscriptionConfig{DeadLetterPolicy: tc.desired})
+			if diff := cmp.Diff(tc.wantUpdate, toUpdate.DeadLetterPolicy); diff != "" {
+				t.Errorf("unexpected dead letter policy update (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantChanges, changes); diff != "" {
+				t.Errorf("unexpected changes (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriptionConfigToUpdateRetryPolicy(t *testing.T) {
+	rp := &gpubsub.RetryPolicy{MinimumBackoff: 5 * time.Second, MaximumBackoff: 10 * time.Minute}
+	testCases := []struct {
+		name        string
+		current     gpubsub.SubscriptionConfig
+		desired     gpubsub.SubscriptionConfig
+		wantUpdate  gpubsub.SubscriptionConfig
+		wantChanges []string
+	}{{
+		name:    "unchanged",
+		current: gpubsub.SubscriptionConfig{RetryPolicy: rp},
+		desired: gpubsub.SubscriptionConfig{RetryPolicy: rp},
+	}, {
+		name:        "added",
+		desired:     gpubsub.SubscriptionConfig{RetryPolicy: rp},
+		wantUpdate:  gpubsub.SubscriptionConfig{RetryPolicy: rp},
+		wantChanges: []string{"retryPolicy none -> minimumBackoff=5s maximumBackoff=10m0s"},
+	}, {
+		name:        "removed",
+		current:     gpubsub.SubscriptionConfig{RetryPolicy: rp},
+		wantUpdate:  gpubsub.SubscriptionConfig{RetryPolicy: &gpubsub.RetryPolicy{}},
+		wantChanges: []string{"retryPolicy minimumBackoff=5s maximumBackoff=10m0s -> none"},
+	}, {
+		name:       "changed along with the ack deadline",
+		current:    gpubsub.SubscriptionConfig{AckDeadline: 10 * time.Second},
+		desired:    gpubsub.SubscriptionConfig{AckDeadline: 30 * time.Second, RetryPolicy: rp},
+		wantUpdate: gpubsub.SubscriptionConfig{AckDeadline: 30 * time.Second, RetryPolicy: rp},
+		wantChanges: []string{
+			"ackDeadline 10s -> 30s",
+			"retryPolicy none -> minimumBackoff=5s maximumBackoff=10m0s",
+		},
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			toUpdate, changes := subscriptionConfigToUpdate(tc.current, tc.desired)
+			if diff := cmp.Diff(tc.wantUpdate, toUpdate); diff != "" {
+				t.Errorf("unexpected update (-want, +got) = %v", diff)
+			}
+			if diff := cmp.Diff(tc.wantChanges, changes); diff != "" {
+				t.Errorf("unexpected changes (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// AgentConfigMapName is the name of the ConfigMap in the system namespace that
+// holds the PullSubscriptions served by the receive adapter agent.
+const AgentConfigMapName = "pullsubscription-agent"
+
+// IsAgentMode returns true if ps is served by the receive adapter agent
+// instead of a receive adapter Deployment of its own.
+func IsAgentMode(ps *v1beta1.PullSubscription) bool {
+	return ps.Annotations[duckv1beta1.ReceiveAdapterModeAnnotation] == duckv1beta1.ReceiveAdapterModeAgent
+}
+
+// AgentSubscriptionKey returns the key of ps in the agent ConfigMap. Namespace
+// names can't contain dots, so the key is unique.
+func AgentSubscriptionKey(ps *v1beta1.PullSubscription) string {
+	return ps.Namespace + "." + ps.Name
+}
+
+// MakeAgentSubscription generates the configuration of the PullSubscription
+// for the receive adapter agent. Only the fields of args that vary by
+// PullSubscription are used, the agent has its own image, logging, metrics
+// and tracing configuration.
+func MakeAgentSubscription(ctx context.Context, args *ReceiveAdapterArgs) *adapter.AgentSubscription {
+	ps := args.PullSubscription
+	resourceGroup, resourceName := metricsResource(ps)
+	topicProject, topicID, _ := utils.ParseTopic(ps.Spec.Topic)
+
+	pushAuthServiceAccount, pushAuthAudience := pushAuth(ps)
+
+	var transformerURI string
+	if args.TransformerURI != nil {
+		transformerURI = args.TransformerURI.String()
+	}
+
+	var liteLocation string
+	if ps.Spec.LiteConfig != nil {
+		liteLocation = ps.Spec.LiteConfig.Location
+	}
+
+	return &adapter.AgentSubscription{
+		Project:                ps.Spec.Project,
+		Topic:                  topicID,
+		TopicProject:           topicProject,
+		Subscription:           args.SubscriptionID,
+		Sink:                   args.SinkURI.String(),
+		Transformer:            transformerURI,
+		AdapterType:            ps.Spec.AdapterType,
+		SendMode:               sendMode(ps),
+		PushAuthServiceAccount: pushAuthServiceAccount,
+		PushAuthAudience:       pushAuthAudience,
+		SinkInsecureSkipVerify: ps.Spec.SinkTLS.GetInsecureSkipVerify(),
+		ExtensionsBase64:       ceExtensions(ctx, ps),
+		Namespace:              ps.Namespace,
+		Name:                   resourceName,
+		ResourceGroup:          resourceGroup,
+		RateLimit:              ps.Spec.RateLimit.GetEventsPerSecond(),
+		RateLimitBurst:         ps.Spec.RateLimit.GetBurst(),
+		LiteLocation:           liteLocation,
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func TestMakeAgentSubscription(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+			Annotations: map[string]string{
+				duckv1beta1.ReceiveAdapterModeAnnotation: duckv1beta1.ReceiveAdapterModeAgent,
+				"metrics-resource-group":                 "test-resource-group",
+			},
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+				SourceSpec: duckv1.SourceSpec{
+					CloudEventOverrides: &duckv1.CloudEventOverrides{
+						Extensions: map[string]string{"foo": "bar"},
+					},
+				},
+			},
+			Topic:     "projects/topic-project/topics/topic",
+			SinkTLS:   &duckv1beta1.SinkTLSSpec{InsecureSkipVerify: true},
+			RateLimit: &duckv1beta1.RateLimitSpec{EventsPerSecond: 10},
+		},
+	}
+	if !IsAgentMode(ps) {
+		t.Error("IsAgentMode() = false, want true")
+	}
+	if got, want := AgentSubscriptionKey(ps), "testnamespace.testname"; got != want {
+		t.Errorf("AgentSubscriptionKey() = %q, want %q", got, want)
+	}
+
+	got := MakeAgentSubscription(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		TransformerURI:   apis.HTTP("transformer-uri"),
+	})
+	want := &adapter.AgentSubscription{
+		Project:                "eventing-name",
+		Topic:                  "topic",
+		TopicProject:           "topic-project",
+		Subscription:           "sub-id",
+		Sink:                   "http://sink-uri",
+		Transformer:            "http://transformer-uri",
+		SendMode:               converters.Binary,
+		SinkInsecureSkipVerify: true,
+		ExtensionsBase64:       "eyJmb28iOiJiYXIifQ==",
+		Namespace:              "testnamespace",
+		Name:                   "testname",
+		ResourceGroup:          "test-resource-group",
+		RateLimit:              10,
+		RateLimitBurst:         10,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected agent subscription (-want, +got) = %v", diff)
+	}
+}
@@ -18,26 +18,107 @@ package resources
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
 	"github.com/google/knative-gcp/pkg/apis/intevents"
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	"github.com/google/knative-gcp/pkg/utils/naming"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/kmeta"
 )
 
 // GenerateSubscriptionName generates the name for the Pub/Sub subscription to be used for this PullSubscription.
-//  It uses the object labels to see whether it's from a source, channel, or ps to construct the name.
+//  It uses the object labels to see whether it's from a source, channel, flow, or ps to construct the name.
 func GenerateSubscriptionName(ps *v1beta1.PullSubscription) string {
 	prefix := getPrefix(ps)
 	return naming.TruncatedPubsubResourceName(prefix, ps.Namespace, ps.Name, ps.UID)
 }
 
+// pubsubIDRegexp matches the valid Pub/Sub subscription IDs, see
+// https://cloud.google.com/pubsub/docs/admin#resource_names.
+var pubsubIDRegexp = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-._~+%]{2,254}$`)
+
+// SubscriptionNaming is the operator level prefix and suffix of the IDs of
+// the Pub/Sub subscriptions, e.g. for organization policies requiring them to
+// carry the environment. They are templates evaluated against the resource
+// the subscription is for, a PullSubscription, Broker or Trigger, e.g.
+// "prod-{{.Namespace}}-".
+type SubscriptionNaming struct {
+	Prefix string `envconfig:"PUBSUB_SUBSCRIPTION_PREFIX"`
+	Suffix string `envconfig:"PUBSUB_SUBSCRIPTION_SUFFIX"`
+}
+
+// Validate returns an error if the prefix or the suffix is not a valid
+// template.
+func (n SubscriptionNaming) Validate() error {
+	if _, err := template.New("prefix").Option("missingkey=error").Parse(n.Prefix); err != nil {
+		return fmt.Errorf("invalid subscription prefix: %w", err)
+	}
+	if _, err := template.New("suffix").Option("missingkey=error").Parse(n.Suffix); err != nil {
+		return fmt.Errorf("invalid subscription suffix: %w", err)
+	}
+	return nil
+}
+
+// SubscriptionName generates the ID of the Pub/Sub subscription to be used for
+// this PullSubscription. It is the name generated by GenerateSubscriptionName
+// between the prefix and the suffix, truncated so that the ID fits in the
+// Pub/Sub limit.
+func (n SubscriptionNaming) SubscriptionName(ps *v1beta1.PullSubscription) (string, error) {
+	return n.Name(ps, getPrefix(ps), ps.Namespace, ps.Name, ps.UID)
+}
+
+// Name generates the ID of a Pub/Sub subscription of obj. It is the name
+// generated from prefix, ns, name and uid between the prefix and the suffix
+// evaluated against obj, truncated so that the ID fits in the Pub/Sub limit.
+func (n SubscriptionNaming) Name(obj interface{}, prefix, ns, name string, uid types.UID) (string, error) {
+	if n.Prefix == "" && n.Suffix == "" {
+		return naming.TruncatedPubsubResourceName(prefix, ns, name, uid), nil
+	}
+	before, err := expand("prefix", n.Prefix, obj)
+	if err != nil {
+		return "", err
+	}
+	after, err := expand("suffix", n.Suffix, obj)
+	if err != nil {
+		return "", err
+	}
+	// The generated name must keep at least its separator and the UID.
+	maximum := naming.PubsubMax - len(before) - len(after)
+	if maximum <= len(uid)+1 {
+		return "", fmt.Errorf("subscription prefix %q and suffix %q leave no room for the subscription name in %d characters", before, after, naming.PubsubMax)
+	}
+	id := before + naming.TruncatedResourceName(prefix, ns, name, uid, maximum) + after
+	if !pubsubIDRegexp.MatchString(id) || strings.HasPrefix(id, "goog") {
+		return "", fmt.Errorf("invalid subscription ID %q", id)
+	}
+	return id, nil
+}
+
+func expand(name, text string, obj interface{}) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid subscription %s: %w", name, err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, obj); err != nil {
+		return "", fmt.Errorf("failed to expand the subscription %s: %w", name, err)
+	}
+	return b.String(), nil
+}
+
 // GenerateReceiveAdapterName generates the name of the receive adapter to be used for this PullSubscription.
 func GenerateReceiveAdapterName(ps *v1beta1.PullSubscription) string {
 	return GenerateK8sName(ps)
 }
 
 // GenerateK8sName generates a k8s name based on PullSubscription information.
-//  It uses the object labels to see whether it's from a source, channel, or ps to constructs a k8s compliant name.
+//  It uses the object labels to see whether it's from a source, channel, flow, or ps to constructs a k8s compliant name.
 func GenerateK8sName(ps *v1beta1.PullSubscription) string {
 	prefix := getPrefix(ps)
 	return kmeta.ChildName(fmt.Sprintf("%s-%s", prefix, ps.Name), "-"+string(ps.UID))
@@ -49,6 +130,8 @@ func getPrefix(ps *v1beta1.PullSubscription) string {
 		prefix = "cre-src"
 	} else if _, ok := ps.Labels[intevents.ChannelLabelKey]; ok {
 		prefix = "cre-chan"
+	} else if _, ok := ps.Labels[intevents.FlowLabelKey]; ok {
+		prefix = "cre-flow"
 	}
 	return prefix
 }
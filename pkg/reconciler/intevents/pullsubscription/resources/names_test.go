@@ -17,6 +17,7 @@ limitations under the License.
 package resources
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -66,6 +67,19 @@ func TestGenerateSubscriptionName(t *testing.T) {
 			},
 		},
 		want: "cre-chan_mynamespace_myname_uid",
+	}, {
+		name: "flow-based name",
+		ps: &v1beta1.PullSubscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myname",
+				Namespace: "mynamespace",
+				UID:       "uid",
+				Labels: map[string]string{
+					intevents.FlowLabelKey: "myname",
+				},
+			},
+		},
+		want: "cre-flow_mynamespace_myname_uid",
 	}}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -77,6 +91,90 @@ func TestGenerateSubscriptionName(t *testing.T) {
 	}
 }
 
+func TestSubscriptionNaming(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myname",
+			Namespace: "mynamespace",
+			UID:       "uid",
+			Labels: map[string]string{
+				intevents.SourceLabelKey: "myname",
+				"env":                    "prod",
+			},
+		},
+	}
+	long := ps.DeepCopy()
+	long.Name = strings.Repeat("n", 300)
+
+	tests := []struct {
+		name    string
+		naming  SubscriptionNaming
+		ps      *v1beta1.PullSubscription
+		want    string
+		wantErr bool
+	}{{
+		name: "no naming",
+		ps:   ps,
+		want: "cre-src_mynamespace_myname_uid",
+	}, {
+		name:   "prefix and suffix",
+		naming: SubscriptionNaming{Prefix: "{{.Labels.env}}-", Suffix: "-{{.Namespace}}"},
+		ps:     ps,
+		want:   "prod-cre-src_mynamespace_myname_uid-mynamespace",
+	}, {
+		name:   "truncated",
+		naming: SubscriptionNaming{Prefix: "prod-"},
+		ps:     long,
+		want:   "prod-cre-src_mynamespace_" + strings.Repeat("n", 226) + "_uid",
+	}, {
+		name:    "missing key",
+		naming:  SubscriptionNaming{Prefix: "{{.Labels.team}}-"},
+		ps:      ps,
+		wantErr: true,
+	}, {
+		name:    "no room left",
+		naming:  SubscriptionNaming{Prefix: strings.Repeat("p", 252)},
+		ps:      ps,
+		wantErr: true,
+	}, {
+		name:    "invalid ID",
+		naming:  SubscriptionNaming{Prefix: "1-"},
+		ps:      ps,
+		wantErr: true,
+	}, {
+		name:    "reserved ID",
+		naming:  SubscriptionNaming{Prefix: "goog-"},
+		ps:      ps,
+		wantErr: true,
+	}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.naming.SubscriptionName(test.ps)
+			if test.wantErr != (err != nil) {
+				t.Fatalf("SubscriptionName() got error %v, want error %v", err, test.wantErr)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("unexpected (-want, +got) = %v", diff)
+			}
+			if len(got) > 255 {
+				t.Errorf("SubscriptionName() has %d characters, want at most 255", len(got))
+			}
+		})
+	}
+}
+
+func TestSubscriptionNamingValidate(t *testing.T) {
+	if err := (SubscriptionNaming{Prefix: "{{.Namespace}}-", Suffix: "-x"}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	if err := (SubscriptionNaming{Prefix: "{{.Namespace"}).Validate(); err == nil {
+		t.Error("Validate() of an invalid prefix succeeded, want error")
+	}
+	if err := (SubscriptionNaming{Suffix: "{{end}}"}).Validate(); err == nil {
+		t.Error("Validate() of an invalid suffix succeeded, want error")
+	}
+}
+
 func TestGenerateReceiveAdapterName(t *testing.T) {
 	tests := []struct {
 		name string
@@ -118,6 +216,19 @@ func TestGenerateReceiveAdapterName(t *testing.T) {
 			},
 		},
 		want: "cre-chan-myname-uid",
+	}, {
+		name: "flow-based name",
+		ps: &v1beta1.PullSubscription{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myname",
+				Namespace: "mynamespace",
+				UID:       "uid",
+				Labels: map[string]string{
+					intevents.FlowLabelKey: "myname",
+				},
+			},
+		},
+		want: "cre-flow-myname-uid",
 	}, {
 		name: "name too long, hashed and shortened",
 		ps: &v1beta1.PullSubscription{
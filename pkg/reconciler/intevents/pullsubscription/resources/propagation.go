@@ -0,0 +1,97 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"strings"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+// MetadataPropagation selects the PullSubscription annotations and labels
+// that are copied onto its receive adapter Deployment. Keys can be exact or
+// end with "*" to match any key with that prefix. An empty allow list allows
+// every key that is not denied.
+type MetadataPropagation struct {
+	AllowedAnnotations []string `envconfig:"PROPAGATE_ANNOTATIONS_ALLOW"`
+	DeniedAnnotations  []string `envconfig:"PROPAGATE_ANNOTATIONS_DENY" default:"kubectl.kubernetes.io/*,argocd.argoproj.io/*"`
+	AllowedLabels      []string `envconfig:"PROPAGATE_LABELS_ALLOW"`
+	DeniedLabels       []string `envconfig:"PROPAGATE_LABELS_DENY" default:"app.kubernetes.io/instance,argocd.argoproj.io/*"`
+}
+
+// requiredAnnotations are always propagated because the controllers filter
+// Deployments on them.
+var requiredAnnotations = []string{
+	duckv1beta1.AutoscalingClassAnnotation,
+}
+
+// Annotations returns the annotations to propagate from the given ones.
+func (p *MetadataPropagation) Annotations(from map[string]string) map[string]string {
+	to := propagate(from, p.AllowedAnnotations, p.DeniedAnnotations)
+	for _, k := range requiredAnnotations {
+		if v, ok := from[k]; ok {
+			if to == nil {
+				to = make(map[string]string, len(requiredAnnotations))
+			}
+			to[k] = v
+		}
+	}
+	return to
+}
+
+// Labels returns the labels to propagate from the given ones, merged with
+// the given selector labels. The selector labels take precedence.
+func (p *MetadataPropagation) Labels(from, selector map[string]string) map[string]string {
+	to := propagate(from, p.AllowedLabels, p.DeniedLabels)
+	if to == nil {
+		return selector
+	}
+	for k, v := range selector {
+		to[k] = v
+	}
+	return to
+}
+
+func propagate(from map[string]string, allowed, denied []string) map[string]string {
+	var to map[string]string
+	for k, v := range from {
+		if len(allowed) > 0 && !matchesAny(k, allowed) {
+			continue
+		}
+		if matchesAny(k, denied) {
+			continue
+		}
+		if to == nil {
+			to = make(map[string]string, len(from))
+		}
+		to[k] = v
+	}
+	return to
+}
+
+func matchesAny(key string, patterns []string) bool {
+	for _, p := range patterns {
+		if strings.HasSuffix(p, "*") {
+			if strings.HasPrefix(key, strings.TrimSuffix(p, "*")) {
+				return true
+			}
+		} else if key == p {
+			return true
+		}
+	}
+	return false
+}
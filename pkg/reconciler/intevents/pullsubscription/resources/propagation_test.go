@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func TestPropagateAnnotations(t *testing.T) {
+	from := map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"argocd.argoproj.io/sync-wave":                     "1",
+		"metrics-resource-group":                           "group",
+		"team":                                             "eventing",
+		duckv1beta1.AutoscalingClassAnnotation:             duckv1beta1.KEDA,
+	}
+	testCases := map[string]struct {
+		p    MetadataPropagation
+		want map[string]string
+	}{
+		"no lists propagates everything": {
+			want: from,
+		},
+		"deny list": {
+			p: MetadataPropagation{
+				DeniedAnnotations: []string{"kubectl.kubernetes.io/*", "argocd.argoproj.io/*"},
+			},
+			want: map[string]string{
+				"metrics-resource-group":               "group",
+				"team":                                 "eventing",
+				duckv1beta1.AutoscalingClassAnnotation: duckv1beta1.KEDA,
+			},
+		},
+		"allow list keeps required annotations": {
+			p: MetadataPropagation{
+				AllowedAnnotations: []string{"team"},
+			},
+			want: map[string]string{
+				"team":                                 "eventing",
+				duckv1beta1.AutoscalingClassAnnotation: duckv1beta1.KEDA,
+			},
+		},
+		"deny takes precedence over allow": {
+			p: MetadataPropagation{
+				AllowedAnnotations: []string{"team", "metrics-*"},
+				DeniedAnnotations:  []string{"team"},
+			},
+			want: map[string]string{
+				"metrics-resource-group":               "group",
+				duckv1beta1.AutoscalingClassAnnotation: duckv1beta1.KEDA,
+			},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.p.Annotations(from)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected annotations (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestPropagateLabels(t *testing.T) {
+	selector := map[string]string{
+		"internal.events.cloud.google.com/controller":       "controller",
+		"internal.events.cloud.google.com/pullsubscription": "ps",
+	}
+	testCases := map[string]struct {
+		p    MetadataPropagation
+		from map[string]string
+		want map[string]string
+	}{
+		"no labels": {
+			want: selector,
+		},
+		"labels are merged with selector": {
+			p: MetadataPropagation{
+				DeniedLabels: []string{"app.kubernetes.io/instance"},
+			},
+			from: map[string]string{
+				"app.kubernetes.io/instance": "argo-app",
+				"team":                       "eventing",
+			},
+			want: map[string]string{
+				"internal.events.cloud.google.com/controller":       "controller",
+				"internal.events.cloud.google.com/pullsubscription": "ps",
+				"team": "eventing",
+			},
+		},
+		"selector takes precedence": {
+			from: map[string]string{
+				"internal.events.cloud.google.com/pullsubscription": "other",
+			},
+			want: selector,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := tc.p.Labels(tc.from, selector)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected labels (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
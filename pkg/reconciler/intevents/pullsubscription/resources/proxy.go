@@ -0,0 +1,38 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+// ProxyDefaults are the operator level proxy settings for receive adapters.
+// They apply to any field a PullSubscription doesn't set in spec.proxy.
+type ProxyDefaults struct {
+	HTTPProxy  string `envconfig:"DATA_PLANE_HTTP_PROXY"`
+	HTTPSProxy string `envconfig:"DATA_PLANE_HTTPS_PROXY"`
+	NoProxy    string `envconfig:"DATA_PLANE_NO_PROXY"`
+}
+
+// Spec returns the defaults as a ProxySpec.
+func (d ProxyDefaults) Spec() duckv1beta1.ProxySpec {
+	return duckv1beta1.ProxySpec{
+		HTTPProxy:  d.HTTPProxy,
+		HTTPSProxy: d.HTTPSProxy,
+		NoProxy:    d.NoProxy,
+	}
+}
@@ -18,21 +18,28 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
 
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
 	"github.com/google/knative-gcp/pkg/pubsub/adapter/converters"
 	"github.com/google/knative-gcp/pkg/utils"
+	"github.com/google/knative-gcp/pkg/utils/platform"
 
 	"k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // ReceiveAdapterArgs are the arguments needed to create a PullSubscription Receive
@@ -47,6 +54,18 @@ type ReceiveAdapterArgs struct {
 	MetricsConfig    string
 	LoggingConfig    string
 	TracingConfig    string
+	// MetadataPropagation selects the PullSubscription annotations and
+	// labels copied onto the Deployment.
+	MetadataPropagation MetadataPropagation
+	// DefaultProxy is used for the proxy settings the PullSubscription
+	// doesn't set.
+	DefaultProxy duckv1beta1.ProxySpec
+	// NodeArchitectures are the CPU architectures of the nodes the receive
+	// adapter runs on.
+	NodeArchitectures platform.NodeArchitectures
+	// CatchUp boosts the replicas and the flow control of the receive
+	// adapter, see duckv1beta1.CatchUpUntilAnnotation.
+	CatchUp bool
 }
 
 const (
@@ -54,23 +73,37 @@ const (
 	credsMountPath       = "/var/secrets/google"
 	metricsDomain        = "cloud.google.com/events"
 	defaultResourceGroup = "pullsubscriptions.internal.events.cloud.google.com"
+
+	// drainPort is the port of the endpoint the preStop hook of draining
+	// receive adapters calls.
+	drainPort = 8081
+	drainPath = "/drain"
+
+	// sinkCAVolume is the volume of the CA bundle of the sink, mounted at
+	// sinkCAMountPath with the bundle in sinkCAFile.
+	sinkCAVolume    = "sink-ca-bundle"
+	sinkCAMountPath = "/var/run/cloud-run-events/sink-ca-bundle"
+	sinkCAFile      = "ca.crt"
 )
 
-func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *corev1.PodSpec {
-	// Convert CloudEvent Overrides to pod embeddable properties.
-	ceExtensions := ""
-	if args.PullSubscription.Spec.CloudEventOverrides != nil && args.PullSubscription.Spec.CloudEventOverrides.Extensions != nil {
-		var err error
-		ceExtensions, err = utils.MapToBase64(args.PullSubscription.Spec.CloudEventOverrides.Extensions)
-		if err != nil {
-			logging.FromContext(ctx).Warnw("failed to make cloudevents overrides extensions",
-				zap.Error(err),
-				zap.Any("extensions", args.PullSubscription.Spec.CloudEventOverrides.Extensions))
-		}
+// ceExtensions returns the CloudEvent overrides of ps as pod embeddable
+// properties.
+func ceExtensions(ctx context.Context, ps *v1beta1.PullSubscription) string {
+	if ps.Spec.CloudEventOverrides == nil || ps.Spec.CloudEventOverrides.Extensions == nil {
+		return ""
+	}
+	ceExtensions, err := utils.MapToBase64(ps.Spec.CloudEventOverrides.Extensions)
+	if err != nil {
+		logging.FromContext(ctx).Warnw("failed to make cloudevents overrides extensions",
+			zap.Error(err),
+			zap.Any("extensions", ps.Spec.CloudEventOverrides.Extensions))
 	}
+	return ceExtensions
+}
 
+func sendMode(ps *v1beta1.PullSubscription) converters.ModeType {
 	var mode converters.ModeType
-	switch args.PullSubscription.PubSubMode() {
+	switch ps.PubSubMode() {
 	case "", v1beta1.ModeCloudEventsBinary:
 		mode = converters.Binary
 	case v1beta1.ModeCloudEventsStructured:
@@ -78,67 +111,72 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 	case v1beta1.ModePushCompatible:
 		mode = converters.Push
 	}
+	return mode
+}
 
+// pushAuth returns the service account and the audience of the emulated push
+// authentication of ps. The service account is empty if it is not emulated.
+func pushAuth(ps *v1beta1.PullSubscription) (string, string) {
+	if sendMode(ps) != converters.Push {
+		return "", ""
+	}
+	return ps.Annotations[v1beta1.PushAuthServiceAccountAnnotation], ps.Annotations[v1beta1.PushAuthAudienceAnnotation]
+}
+
+// metricsResource returns the resource group and name the receive adapter of
+// ps reports metrics for.
+func metricsResource(ps *v1beta1.PullSubscription) (string, string) {
 	var resourceGroup = defaultResourceGroup
-	if rg, ok := args.PullSubscription.Annotations["metrics-resource-group"]; ok {
+	if rg, ok := ps.Annotations["metrics-resource-group"]; ok {
 		resourceGroup = rg
 	}
 	// Needed for Channels, as we use a generate name for the PullSubscription.
-	var resourceName = args.PullSubscription.Name
-	if rn, ok := args.PullSubscription.Annotations["metrics-resource-name"]; ok {
+	var resourceName = ps.Name
+	if rn, ok := ps.Annotations["metrics-resource-name"]; ok {
 		resourceName = rn
 	}
+	return resourceGroup, resourceName
+}
 
-	var transformerURI string
-	if args.TransformerURI != nil {
-		transformerURI = args.TransformerURI.String()
+// MakeReceiveAdapterConfig generates the versioned config of the receive
+// adapter of the PullSubscription, passed to it in a single environment
+// variable. The settings of the subscription are the ones of its
+// AgentSubscription, so that both kinds of adapters are configured alike.
+func MakeReceiveAdapterConfig(ctx context.Context, args *ReceiveAdapterArgs) *adapter.Config {
+	ps := args.PullSubscription
+	config := &adapter.Config{
+		Version:           adapter.ConfigVersion,
+		AgentSubscription: *MakeAgentSubscription(ctx, args),
+		MetricsConfig:     args.MetricsConfig,
+		LoggingConfig:     args.LoggingConfig,
+		TracingConfig:     args.TracingConfig,
+	}
+	if ps.Spec.Shutdown.GetDrain() {
+		config.DrainPort = drainPort
+	}
+	if ps.Spec.SinkTLS.GetCABundle() != nil {
+		config.SinkCABundlePath = sinkCAMountPath + "/" + sinkCAFile
+	}
+	if args.CatchUp {
+		config.MaxOutstandingMessages = duckv1beta1.CatchUpFactor * pubsub.DefaultReceiveSettings.MaxOutstandingMessages
+	}
+	return config
+}
+
+func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *corev1.PodSpec {
+	config, err := json.Marshal(MakeReceiveAdapterConfig(ctx, args))
+	if err != nil {
+		// The config only holds strings and numbers.
+		panic(fmt.Sprintf("failed to encode the receive adapter config: %v", err))
 	}
 
 	receiveAdapterContainer := corev1.Container{
 		Name:  "receive-adapter",
 		Image: args.Image,
+		Args:  []string{"--role=receive-adapter"},
 		Env: []corev1.EnvVar{{
-			Name:  "PROJECT_ID",
-			Value: args.PullSubscription.Spec.Project,
-		}, {
-			Name:  "PUBSUB_TOPIC_ID",
-			Value: args.PullSubscription.Spec.Topic,
-		}, {
-			Name:  "PUBSUB_SUBSCRIPTION_ID",
-			Value: args.SubscriptionID,
-		}, {
-			Name:  "SINK_URI",
-			Value: args.SinkURI.String(),
-		}, {
-			Name:  "TRANSFORMER_URI",
-			Value: transformerURI,
-		}, {
-			Name:  "ADAPTER_TYPE",
-			Value: args.PullSubscription.Spec.AdapterType,
-		}, {
-			Name:  "SEND_MODE",
-			Value: string(mode),
-		}, {
-			Name:  "K_CE_EXTENSIONS",
-			Value: ceExtensions,
-		}, {
-			Name:  "K_METRICS_CONFIG",
-			Value: args.MetricsConfig,
-		}, {
-			Name:  "K_LOGGING_CONFIG",
-			Value: args.LoggingConfig,
-		}, {
-			Name:  "K_TRACING_CONFIG",
-			Value: args.TracingConfig,
-		}, {
-			Name:  "NAME",
-			Value: resourceName,
-		}, {
-			Name:  "NAMESPACE",
-			Value: args.PullSubscription.Namespace,
-		}, {
-			Name:  "RESOURCE_GROUP",
-			Value: resourceGroup,
+			Name:  adapter.ConfigEnvKey,
+			Value: string(config),
 		}, {
 			Name:  "METRICS_DOMAIN",
 			Value: metricsDomain,
@@ -149,10 +187,29 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 		}},
 	}
 
+	if args.PullSubscription.Spec.Shutdown.GetDrain() {
+		// The hook stops the streaming pull and returns once the received
+		// messages are delivered, before the adapter receives SIGTERM.
+		receiveAdapterContainer.Lifecycle = &corev1.Lifecycle{
+			PreStop: &corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: drainPath,
+					Port: intstr.FromInt(drainPort),
+				},
+			},
+		}
+	}
+	receiveAdapterContainer.Env = append(receiveAdapterContainer.Env,
+		args.PullSubscription.Spec.Proxy.EnvVars(args.DefaultProxy)...)
+	receiveAdapterContainer.Env = append(receiveAdapterContainer.Env, endpoints.EnvVars()...)
+
+	gracePeriod := args.PullSubscription.Spec.Shutdown.GetTerminationGracePeriodSeconds()
+
 	// If there is no secret to embed, return what we have.
 	if args.PullSubscription.Spec.Secret == nil {
 		return &corev1.PodSpec{
-			ServiceAccountName: args.PullSubscription.Spec.ServiceAccountName,
+			ServiceAccountName:            args.PullSubscription.Spec.ServiceAccountName,
+			TerminationGracePeriodSeconds: gracePeriod,
 			Containers: []corev1.Container{
 				receiveAdapterContainer,
 			},
@@ -182,7 +239,8 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 	}}
 
 	return &corev1.PodSpec{
-		ServiceAccountName: args.PullSubscription.Spec.ServiceAccountName,
+		ServiceAccountName:            args.PullSubscription.Spec.ServiceAccountName,
+		TerminationGracePeriodSeconds: gracePeriod,
 		Containers: []corev1.Container{
 			receiveAdapterContainer,
 		},
@@ -197,20 +255,47 @@ func makeReceiveAdapterPodSpec(ctx context.Context, args *ReceiveAdapterArgs) *c
 	}
 }
 
+// withSinkCABundle mounts the CA bundle of the sink, if any, in the receive
+// adapter container at the path set in its config.
+func withSinkCABundle(podSpec *corev1.PodSpec, caBundle *corev1.ConfigMapKeySelector) *corev1.PodSpec {
+	if caBundle == nil {
+		return podSpec
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: sinkCAVolume,
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: caBundle.LocalObjectReference,
+			Items:                []corev1.KeyToPath{{Key: caBundle.Key, Path: sinkCAFile}},
+		}},
+	})
+	c := &podSpec.Containers[0]
+	c.VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+		Name:      sinkCAVolume,
+		MountPath: sinkCAMountPath,
+		ReadOnly:  true,
+	})
+	return podSpec
+}
+
 // MakeReceiveAdapter generates (but does not insert into K8s) the Receive Adapter Deployment for
 // PullSubscriptions.
 func MakeReceiveAdapter(ctx context.Context, args *ReceiveAdapterArgs) *v1.Deployment {
-	podSpec := makeReceiveAdapterPodSpec(ctx, args)
+	podSpec := withSinkCABundle(makeReceiveAdapterPodSpec(ctx, args), args.PullSubscription.Spec.SinkTLS.GetCABundle())
+	args.NodeArchitectures.Apply(podSpec)
 	replicas := int32(1)
+	if args.CatchUp {
+		replicas = duckv1beta1.CatchUpFactor
+	}
+	labels := args.MetadataPropagation.Labels(args.PullSubscription.Labels, args.Labels)
 
 	return &v1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace:       args.PullSubscription.Namespace,
 			Name:            GenerateReceiveAdapterName(args.PullSubscription),
-			Labels:          args.Labels,
+			Labels:          labels,
 			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(args.PullSubscription)},
 			// Copy the source annotations so that the appropriate reconciler is called.
-			Annotations: args.PullSubscription.Annotations,
+			Annotations: args.MetadataPropagation.Annotations(args.PullSubscription.Annotations),
 		},
 		Spec: v1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
@@ -219,7 +304,8 @@ func MakeReceiveAdapter(ctx context.Context, args *ReceiveAdapterArgs) *v1.Deplo
 			Replicas: &replicas,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: args.Labels,
+					Labels:      labels,
+					Annotations: args.PullSubscription.Spec.Istio.PodAnnotations(),
 				},
 				Spec: *podSpec,
 			},
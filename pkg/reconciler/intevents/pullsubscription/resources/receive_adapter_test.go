@@ -18,6 +18,7 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,14 +26,35 @@ import (
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	testingmetadata "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/pubsub/adapter"
+	rectesting "github.com/google/knative-gcp/pkg/reconciler/testing"
+	"github.com/google/knative-gcp/pkg/utils/platform"
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
+// receiveAdapterConfig returns the config of the receive adapter of d, parsed
+// like the adapter does.
+func receiveAdapterConfig(t *testing.T, d *v1.Deployment) *adapter.Config {
+	t.Helper()
+	for _, e := range d.Spec.Template.Spec.Containers[0].Env {
+		if e.Name == adapter.ConfigEnvKey {
+			c, err := adapter.ParseConfig([]byte(e.Value))
+			if err != nil {
+				t.Fatalf("Failed to parse the receive adapter config: %v", err)
+			}
+			return c
+		}
+	}
+	t.Fatalf("No env %s", adapter.ConfigEnvKey)
+	return nil
+}
+
 func TestMakeMinimumReceiveAdapter(t *testing.T) {
 	ps := &v1beta1.PullSubscription{
 		ObjectMeta: metav1.ObjectMeta{
@@ -105,45 +127,10 @@ func TestMakeMinimumReceiveAdapter(t *testing.T) {
 					Containers: []corev1.Container{{
 						Name:  "receive-adapter",
 						Image: "test-image",
+						Args:  []string{"--role=receive-adapter"},
 						Env: []corev1.EnvVar{{
-							Name:  "PROJECT_ID",
-							Value: "eventing-name",
-						}, {
-							Name:  "PUBSUB_TOPIC_ID",
-							Value: "topic",
-						}, {
-							Name:  "PUBSUB_SUBSCRIPTION_ID",
-							Value: "sub-id",
-						}, {
-							Name:  "SINK_URI",
-							Value: "http://sink-uri",
-						}, {
-							Name: "TRANSFORMER_URI",
-						}, {
-							Name: "ADAPTER_TYPE",
-						}, {
-							Name:  "SEND_MODE",
-							Value: "binary",
-						}, {
-							Name: "K_CE_EXTENSIONS",
-						}, {
-							Name:  "K_METRICS_CONFIG",
-							Value: "MetricsConfig-ABC123",
-						}, {
-							Name:  "K_LOGGING_CONFIG",
-							Value: "LoggingConfig-ABC123",
-						}, {
-							Name:  "K_TRACING_CONFIG",
-							Value: "TracingConfig-ABC123",
-						}, {
-							Name:  "NAME",
-							Value: "testname",
-						}, {
-							Name:  "NAMESPACE",
-							Value: "testnamespace",
-						}, {
-							Name:  "RESOURCE_GROUP",
-							Value: defaultResourceGroup,
+							Name:  adapter.ConfigEnvKey,
+							Value: `{"version":"v1","project":"eventing-name","topic":"topic","subscription":"sub-id","sink":"http://sink-uri","sendMode":"binary","namespace":"testnamespace","name":"testname","resourceGroup":"pullsubscriptions.internal.events.cloud.google.com","metricsConfig":"MetricsConfig-ABC123","loggingConfig":"LoggingConfig-ABC123","tracingConfig":"TracingConfig-ABC123"}`,
 						}, {
 							Name:  "METRICS_DOMAIN",
 							Value: metricsDomain,
@@ -262,48 +249,10 @@ func TestMakeFullReceiveAdapter(t *testing.T) {
 					Containers: []corev1.Container{{
 						Name:  "receive-adapter",
 						Image: "test-image",
+						Args:  []string{"--role=receive-adapter"},
 						Env: []corev1.EnvVar{{
-							Name:  "PROJECT_ID",
-							Value: "eventing-name",
-						}, {
-							Name:  "PUBSUB_TOPIC_ID",
-							Value: "topic",
-						}, {
-							Name:  "PUBSUB_SUBSCRIPTION_ID",
-							Value: "sub-id",
-						}, {
-							Name:  "SINK_URI",
-							Value: "http://sink-uri",
-						}, {
-							Name:  "TRANSFORMER_URI",
-							Value: "http://transformer-uri",
-						}, {
-							Name:  "ADAPTER_TYPE",
-							Value: "adapter-type",
-						}, {
-							Name:  "SEND_MODE",
-							Value: "binary",
-						}, {
-							Name:  "K_CE_EXTENSIONS",
-							Value: "eyJmb28iOiJiYXIifQ==",
-						}, {
-							Name:  "K_METRICS_CONFIG",
-							Value: "MetricsConfig-ABC123",
-						}, {
-							Name:  "K_LOGGING_CONFIG",
-							Value: "LoggingConfig-ABC123",
-						}, {
-							Name:  "K_TRACING_CONFIG",
-							Value: "TracingConfig-ABC123",
-						}, {
-							Name:  "NAME",
-							Value: "testname",
-						}, {
-							Name:  "NAMESPACE",
-							Value: "testnamespace",
-						}, {
-							Name:  "RESOURCE_GROUP",
-							Value: "test-resource-group",
+							Name:  adapter.ConfigEnvKey,
+							Value: `{"version":"v1","project":"eventing-name","topic":"topic","subscription":"sub-id","sink":"http://sink-uri","transformer":"http://transformer-uri","adapterType":"adapter-type","sendMode":"binary","extensions":"eyJmb28iOiJiYXIifQ==","namespace":"testnamespace","name":"testname","resourceGroup":"test-resource-group","metricsConfig":"MetricsConfig-ABC123","loggingConfig":"LoggingConfig-ABC123","tracingConfig":"TracingConfig-ABC123"}`,
 						}, {
 							Name:  "METRICS_DOMAIN",
 							Value: metricsDomain,
@@ -339,6 +288,7 @@ func TestMakeFullReceiveAdapter(t *testing.T) {
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
+	rectesting.AssertGolden(t, "full_receive_adapter", got)
 }
 
 func TestMakeReceiveAdapterWithServiceAccount(t *testing.T) {
@@ -425,48 +375,10 @@ func TestMakeReceiveAdapterWithServiceAccount(t *testing.T) {
 					Containers: []corev1.Container{{
 						Name:  "receive-adapter",
 						Image: "test-image",
+						Args:  []string{"--role=receive-adapter"},
 						Env: []corev1.EnvVar{{
-							Name:  "PROJECT_ID",
-							Value: "eventing-name",
-						}, {
-							Name:  "PUBSUB_TOPIC_ID",
-							Value: "topic",
-						}, {
-							Name:  "PUBSUB_SUBSCRIPTION_ID",
-							Value: "sub-id",
-						}, {
-							Name:  "SINK_URI",
-							Value: "http://sink-uri",
-						}, {
-							Name:  "TRANSFORMER_URI",
-							Value: "http://transformer-uri",
-						}, {
-							Name:  "ADAPTER_TYPE",
-							Value: "adapter-type",
-						}, {
-							Name:  "SEND_MODE",
-							Value: "binary",
-						}, {
-							Name:  "K_CE_EXTENSIONS",
-							Value: "eyJmb28iOiJiYXIifQ==",
-						}, {
-							Name:  "K_METRICS_CONFIG",
-							Value: "MetricsConfig-ABC123",
-						}, {
-							Name:  "K_LOGGING_CONFIG",
-							Value: "LoggingConfig-ABC123",
-						}, {
-							Name:  "K_TRACING_CONFIG",
-							Value: "TracingConfig-ABC123",
-						}, {
-							Name:  "NAME",
-							Value: "testname",
-						}, {
-							Name:  "NAMESPACE",
-							Value: "testnamespace",
-						}, {
-							Name:  "RESOURCE_GROUP",
-							Value: "test-resource-group",
+							Name:  adapter.ConfigEnvKey,
+							Value: `{"version":"v1","project":"eventing-name","topic":"topic","subscription":"sub-id","sink":"http://sink-uri","transformer":"http://transformer-uri","adapterType":"adapter-type","sendMode":"binary","extensions":"eyJmb28iOiJiYXIifQ==","namespace":"testnamespace","name":"testname","resourceGroup":"test-resource-group","metricsConfig":"MetricsConfig-ABC123","loggingConfig":"LoggingConfig-ABC123","tracingConfig":"TracingConfig-ABC123"}`,
 						}, {
 							Name:  "METRICS_DOMAIN",
 							Value: metricsDomain,
@@ -485,3 +397,356 @@ func TestMakeReceiveAdapterWithServiceAccount(t *testing.T) {
 		t.Errorf("unexpected deploy (-want, +got) = %v", diff)
 	}
 }
+
+func TestMakeReceiveAdapterWithIstio(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "topic",
+			Istio: &duckv1beta1.IstioSpec{
+				HoldApplicationUntilProxyStarts: true,
+				ExcludeOutboundPorts:            []int32{443},
+				ExcludeInboundPorts:             []int32{9090},
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		Labels: map[string]string{
+			"test-key1": "test-value1",
+		},
+		SubscriptionID: "sub-id",
+		SinkURI:        apis.HTTP("sink-uri"),
+	})
+
+	want := map[string]string{
+		duckv1beta1.IstioProxyConfigAnnotation:          "holdApplicationUntilProxyStarts: true",
+		duckv1beta1.IstioExcludeOutboundPortsAnnotation: "443",
+		duckv1beta1.IstioExcludeInboundPortsAnnotation:  "9090",
+	}
+	if diff := cmp.Diff(want, got.Spec.Template.Annotations); diff != "" {
+		t.Errorf("unexpected pod template annotations (-want, +got) = %v", diff)
+	}
+	if got.Annotations != nil {
+		t.Errorf("unexpected deployment annotations: %v", got.Annotations)
+	}
+}
+
+func TestMakeReceiveAdapterWithProxy(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "topic",
+			Proxy: &duckv1beta1.ProxySpec{
+				HTTPSProxy: "http://ps-proxy:3128",
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+		DefaultProxy: duckv1beta1.ProxySpec{
+			HTTPSProxy: "http://default-proxy:3128",
+			NoProxy:    ".svc.cluster.local",
+		},
+	})
+
+	env := make(map[string]string)
+	for _, e := range got.Spec.Template.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	want := map[string]string{
+		"HTTPS_PROXY": "http://ps-proxy:3128",
+		"NO_PROXY":    ".svc.cluster.local",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env %s = %q, want %q", k, env[k], v)
+		}
+	}
+	if _, ok := env["HTTP_PROXY"]; ok {
+		t.Errorf("unexpected HTTP_PROXY env: %q", env["HTTP_PROXY"])
+	}
+}
+
+func TestMakeReceiveAdapterWithNodeArchitectures(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "topic",
+		},
+	}
+	archs := platform.NodeArchitectures{Allowed: []string{"arm64"}}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:             "test-image",
+		PullSubscription:  ps,
+		SubscriptionID:    "sub-id",
+		SinkURI:           apis.HTTP("sink-uri"),
+		NodeArchitectures: archs,
+	})
+
+	if diff := cmp.Diff(archs.Affinity(), got.Spec.Template.Spec.Affinity); diff != "" {
+		t.Errorf("unexpected affinity (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(archs.Tolerations(), got.Spec.Template.Spec.Tolerations); diff != "" {
+		t.Errorf("unexpected tolerations (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithShutdown(t *testing.T) {
+	gracePeriod := int64(120)
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "topic",
+			Shutdown: &duckv1beta1.ShutdownSpec{
+				TerminationGracePeriodSeconds: &gracePeriod,
+				Drain:                         true,
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+	})
+
+	podSpec := got.Spec.Template.Spec
+	if diff := cmp.Diff(&gracePeriod, podSpec.TerminationGracePeriodSeconds); diff != "" {
+		t.Errorf("unexpected termination grace period (-want, +got) = %v", diff)
+	}
+	wantLifecycle := &corev1.Lifecycle{
+		PreStop: &corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/drain",
+				Port: intstr.FromInt(8081),
+			},
+		},
+	}
+	if diff := cmp.Diff(wantLifecycle, podSpec.Containers[0].Lifecycle); diff != "" {
+		t.Errorf("unexpected lifecycle (-want, +got) = %v", diff)
+	}
+	if port := receiveAdapterConfig(t, got).DrainPort; port != 8081 {
+		t.Errorf("config drainPort = %d, want %d", port, 8081)
+	}
+}
+
+func TestMakeReceiveAdapterWithCatchUp(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "topic",
+		},
+	}
+
+	for _, catchUp := range []bool{false, true} {
+		t.Run(fmt.Sprint(catchUp), func(t *testing.T) {
+			got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+				Image:            "test-image",
+				PullSubscription: ps,
+				SubscriptionID:   "sub-id",
+				SinkURI:          apis.HTTP("sink-uri"),
+				CatchUp:          catchUp,
+			})
+
+			wantReplicas, wantOutstanding := int32(1), 0
+			if catchUp {
+				wantReplicas, wantOutstanding = 4, 4000
+			}
+			if got := *got.Spec.Replicas; got != wantReplicas {
+				t.Errorf("replicas = %d, want %d", got, wantReplicas)
+			}
+			if got := receiveAdapterConfig(t, got).MaxOutstandingMessages; got != wantOutstanding {
+				t.Errorf("config maxOutstandingMessages = %d, want %d", got, wantOutstanding)
+			}
+		})
+	}
+}
+
+func TestMakeReceiveAdapterWithSinkTLS(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "eventing-secret-name"},
+					Key:                  "eventing-secret-key",
+				},
+			},
+			Topic: "topic",
+			SinkTLS: &duckv1beta1.SinkTLSSpec{
+				CABundle: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+					Key:                  "ca.pem",
+				},
+			},
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+	})
+
+	podSpec := got.Spec.Template.Spec
+	wantVolumes := []corev1.Volume{{
+		Name: "google-cloud-key",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: "eventing-secret-name"},
+		},
+	}, {
+		Name: "sink-ca-bundle",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "private-ca"},
+			Items:                []corev1.KeyToPath{{Key: "ca.pem", Path: "ca.crt"}},
+		}},
+	}}
+	if diff := cmp.Diff(wantVolumes, podSpec.Volumes); diff != "" {
+		t.Errorf("unexpected volumes (-want, +got) = %v", diff)
+	}
+	wantMounts := []corev1.VolumeMount{{
+		Name:      "google-cloud-key",
+		MountPath: "/var/secrets/google",
+	}, {
+		Name:      "sink-ca-bundle",
+		MountPath: "/var/run/cloud-run-events/sink-ca-bundle",
+		ReadOnly:  true,
+	}}
+	if diff := cmp.Diff(wantMounts, podSpec.Containers[0].VolumeMounts); diff != "" {
+		t.Errorf("unexpected volume mounts (-want, +got) = %v", diff)
+	}
+	config := receiveAdapterConfig(t, got)
+	if got, want := config.SinkCABundlePath, "/var/run/cloud-run-events/sink-ca-bundle/ca.crt"; got != want {
+		t.Errorf("config sinkCABundlePath = %q, want %q", got, want)
+	}
+	if config.SinkInsecureSkipVerify {
+		t.Error("unexpected config sinkInsecureSkipVerify")
+	}
+
+	ps.Spec.SinkTLS = &duckv1beta1.SinkTLSSpec{InsecureSkipVerify: true}
+	got = MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+	})
+	config = receiveAdapterConfig(t, got)
+	if !config.SinkInsecureSkipVerify {
+		t.Error("config sinkInsecureSkipVerify = false, want true")
+	}
+	if config.SinkCABundlePath != "" {
+		t.Errorf("unexpected config sinkCABundlePath %q", config.SinkCABundlePath)
+	}
+}
+
+func TestMakeReceiveAdapterWithCrossProjectTopic(t *testing.T) {
+	ps := &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "testname",
+			Namespace: "testnamespace",
+		},
+		Spec: v1beta1.PullSubscriptionSpec{
+			PubSubSpec: duckv1beta1.PubSubSpec{
+				Project: "eventing-name",
+			},
+			Topic: "projects/other-project/topics/topic",
+		},
+	}
+
+	got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+		Image:            "test-image",
+		PullSubscription: ps,
+		SubscriptionID:   "sub-id",
+		SinkURI:          apis.HTTP("sink-uri"),
+	})
+
+	config := receiveAdapterConfig(t, got)
+	want := [3]string{"eventing-name", "topic", "other-project"}
+	if diff := cmp.Diff(want, [3]string{config.Project, config.Topic, config.TopicProject}); diff != "" {
+		t.Errorf("unexpected config project, topic and topicProject (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakeReceiveAdapterWithPushAuth(t *testing.T) {
+	for _, mode := range []v1beta1.ModeType{v1beta1.ModePushCompatible, v1beta1.ModeCloudEventsBinary} {
+		t.Run(string(mode), func(t *testing.T) {
+			ps := &v1beta1.PullSubscription{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testname",
+					Namespace: "testnamespace",
+					Annotations: map[string]string{
+						v1beta1.PushAuthServiceAccountAnnotation: "push@eventing-name.iam.gserviceaccount.com",
+						v1beta1.PushAuthAudienceAnnotation:       "https://example.com",
+					},
+				},
+				Spec: v1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Project: "eventing-name",
+					},
+					Topic: "topic",
+					Mode:  mode,
+				},
+			}
+
+			got := MakeReceiveAdapter(context.Background(), &ReceiveAdapterArgs{
+				Image:            "test-image",
+				PullSubscription: ps,
+				SubscriptionID:   "sub-id",
+				SinkURI:          apis.HTTP("sink-uri"),
+			})
+
+			config := receiveAdapterConfig(t, got)
+			var want [2]string
+			if mode == v1beta1.ModePushCompatible {
+				want = [2]string{"push@eventing-name.iam.gserviceaccount.com", "https://example.com"}
+			}
+			if diff := cmp.Diff(want, [2]string{config.PushAuthServiceAccount, config.PushAuthAudience}); diff != "" {
+				t.Errorf("unexpected config pushAuthServiceAccount and pushAuthAudience (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
@@ -18,14 +18,11 @@ package static
 
 import (
 	"context"
-	"go.uber.org/zap"
 
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	pullsubscriptionreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1beta1/pullsubscription"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
 	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/apimachinery/pkg/api/equality"
-	"knative.dev/pkg/logging"
 	"knative.dev/pkg/reconciler"
 )
 
@@ -46,13 +43,9 @@ func (r *Reconciler) ReconcileDeployment(ctx context.Context, ra *appsv1.Deploym
 	if err != nil {
 		return err
 	}
-	if !equality.Semantic.DeepEqual(ra.Spec, existing.Spec) {
-		existing.Spec = ra.Spec
-		existing, err = r.KubeClientSet.AppsV1().Deployments(src.Namespace).Update(existing)
-		if err != nil {
-			logging.FromContext(ctx).Desugar().Error("Error updating Receive Adapter", zap.Error(err))
-			return err
-		}
+	existing, err = r.Base.UpdateReceiveAdapter(ctx, ra, existing)
+	if err != nil {
+		return err
 	}
 
 	src.Status.PropagateDeploymentAvailability(existing)
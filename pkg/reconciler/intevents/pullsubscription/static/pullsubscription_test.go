@@ -18,12 +18,15 @@ package static
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"k8s.io/api/apps/v1"
 	"strings"
 	"testing"
+	"time"
 
+	cloudpubsub "cloud.google.com/go/pubsub"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -41,13 +44,17 @@ import (
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	pubsubv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1beta1/pullsubscription"
+	"github.com/google/knative-gcp/pkg/gclient/pubsub"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	psreconciler "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription"
@@ -70,6 +77,12 @@ const (
 	testTopicID = sourceUID + "-TOPIC"
 	generation  = 1
 
+	// crossProjectTopic is a topic in another project than the subscription.
+	crossProjectTopic = "projects/other-project/topics/" + testTopicID
+
+	// testLiteLocation is the zone of the Pub/Sub Lite topics.
+	testLiteLocation = "us-central1-a"
+
 	secretName = "testing-secret"
 
 	failedToReconcileSubscriptionMsg = `Failed to reconcile Pub/Sub subscription`
@@ -89,7 +102,16 @@ var (
 		Kind:    "Sink",
 	}
 
-	testSubscriptionID = fmt.Sprintf("cre-ps_%s_%s_%s", testNS, sourceName, sourceUID)
+	testSubscriptionID   = fmt.Sprintf("cre-ps_%s_%s_%s", testNS, sourceName, sourceUID)
+	testSubscriptionName = fmt.Sprintf("projects/%s/subscriptions/%s", testProject, testSubscriptionID)
+
+	subscriptionCreatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", "Created Pub/Sub subscription %q: https://console.cloud.google.com/cloudpubsub/subscription/detail/%s?project=%s",
+		testSubscriptionName, testSubscriptionID, testProject)
+
+	testLiteTopicPath        = fmt.Sprintf("projects/%s/locations/%s/topics/%s", testProject, testLiteLocation, testTopicID)
+	testLiteSubscriptionName = fmt.Sprintf("projects/%s/locations/%s/subscriptions/%s", testProject, testLiteLocation, testSubscriptionID)
+
+	liteSubscriptionCreatedEvent = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", "Created Pub/Sub Lite subscription %q", testLiteSubscriptionName)
 
 	transformerGVK = metav1.GroupVersionKind{
 		Group:   "testing.cloud.google.com",
@@ -103,6 +125,41 @@ var (
 		},
 		Key: "testing-key",
 	}
+
+	createTopicSpec = pubsubv1beta1.PullSubscriptionSpec{
+		PubSubSpec: duckv1beta1.PubSubSpec{
+			Secret:  &secret,
+			Project: testProject,
+		},
+		Topic:       testTopicID,
+		CreateTopic: true,
+		TopicConfig: &pubsubv1beta1.TopicConfig{
+			Labels:         map[string]string{"team": "orders"},
+			DeletionPolicy: pubsubv1beta1.TopicDeletionPolicyDelete,
+		},
+	}
+
+	liteSpec = pubsubv1beta1.PullSubscriptionSpec{
+		PubSubSpec: duckv1beta1.PubSubSpec{
+			Secret:  &secret,
+			Project: testProject,
+		},
+		Topic:      testTopicID,
+		LiteConfig: &pubsubv1beta1.LiteConfig{Location: testLiteLocation},
+	}
+
+	createLiteTopicSpec = pubsubv1beta1.PullSubscriptionSpec{
+		PubSubSpec: duckv1beta1.PubSubSpec{
+			Secret:  &secret,
+			Project: testProject,
+		},
+		Topic:       testTopicID,
+		CreateTopic: true,
+		TopicConfig: &pubsubv1beta1.TopicConfig{
+			DeletionPolicy: pubsubv1beta1.TopicDeletionPolicyDelete,
+		},
+		LiteConfig: &pubsubv1beta1.LiteConfig{Location: testLiteLocation},
+	}
 )
 
 func init() {
@@ -383,6 +440,113 @@ func TestAllCases(t *testing.T) {
 				WithPullSubscriptionTransformerURI(nil),
 				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: Topic %q does not exist", failedToReconcileSubscriptionMsg, testTopicID))),
 		}},
+	}, {
+		Name: "cross-project topic does not exist",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: crossProjectTopic,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "SubscriptionReconcileFailed", "Failed to reconcile Pub/Sub subscription: Topic %q does not exist", crossProjectTopic),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: false,
+				},
+			},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: crossProjectTopic,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: Topic %q does not exist", failedToReconcileSubscriptionMsg, crossProjectTopic))),
+		}},
+	}, {
+		Name: "topic does not exist, created",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Pub/Sub topic %q: https://console.cloud.google.com/cloudpubsub/topic/detail/%s?project=%s",
+				"projects/"+testProject+"/topics/"+testTopicID, testTopicID, testProject),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: false,
+				},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionCreatedTopicID(testTopicID),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 	}, {
 		Name: "subscription exists fails",
 		Objects: []runtime.Object{
@@ -495,7 +659,7 @@ func TestAllCases(t *testing.T) {
 				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: %s", failedToReconcileSubscriptionMsg, "subscription-create-induced-error"))),
 		}},
 	}, {
-		Name: "successfully created subscription",
+		Name: "invalid subscription naming",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -517,7 +681,7 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+			Eventf(corev1.EventTypeWarning, "SubscriptionReconcileFailed", "Failed to reconcile Pub/Sub subscription: invalid subscription ID %q", "1-"+testSubscriptionID),
 		},
 		OtherTestData: map[string]interface{}{
 			"ps": gpubsub.TestClientData{
@@ -525,14 +689,16 @@ func TestAllCases(t *testing.T) {
 					Exists: true,
 				},
 			},
+			"naming": resources.SubscriptionNaming{Prefix: "1-"},
 		},
-		WantCreates: []runtime.Object{
-			newReceiveAdapter(context.Background(), testImage, nil),
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
 				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
 				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
 					PubSubSpec: duckv1beta1.PubSubSpec{
 						Secret:  &secret,
@@ -546,17 +712,10 @@ func TestAllCases(t *testing.T) {
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
 				WithPullSubscriptionTransformerURI(nil),
-				// Updates
-				WithPullSubscriptionStatusObservedGeneration(generation),
-				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
-			),
+				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: invalid subscription ID %q", failedToReconcileSubscriptionMsg, "1-"+testSubscriptionID))),
 		}},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
-		},
 	}, {
-		Name: "sink namespace empty, default to the source one",
+		Name: "successfully created subscription",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -565,9 +724,6 @@ func TestAllCases(t *testing.T) {
 					PubSubSpec: duckv1beta1.PubSubSpec{
 						Secret:  &secret,
 						Project: testProject,
-						SourceSpec: duckv1.SourceSpec{
-							Sink: newSinkDestination(""),
-						},
 					},
 					Topic: testTopicID,
 				}),
@@ -581,6 +737,7 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
 		OtherTestData: map[string]interface{}{
@@ -590,9 +747,6 @@ func TestAllCases(t *testing.T) {
 				},
 			},
 		},
-		WantCreates: []runtime.Object{
-			newReceiveAdapter(context.Background(), testImage, nil),
-		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -613,14 +767,16 @@ func TestAllCases(t *testing.T) {
 				// Updates
 				WithPullSubscriptionStatusObservedGeneration(generation),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 			),
 		}},
 		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
 			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 	}, {
-		Name: "sink URI set instead of ref",
+		Name: "successfully updated drifted subscription",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -629,13 +785,9 @@ func TestAllCases(t *testing.T) {
 					PubSubSpec: duckv1beta1.PubSubSpec{
 						Secret:  &secret,
 						Project: testProject,
-						SourceSpec: duckv1.SourceSpec{
-							Sink: duckv1.Destination{
-								URI: sinkURI,
-							},
-						},
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
 				}),
 				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionSink(sinkGVK, sinkName),
@@ -647,6 +799,7 @@ func TestAllCases(t *testing.T) {
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "SubscriptionUpdated", "Updated Pub/Sub subscription %s: ackDeadline 10s -> 30s", testSubscriptionID),
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
 		OtherTestData: map[string]interface{}{
@@ -654,11 +807,15 @@ func TestAllCases(t *testing.T) {
 				TopicData: gpubsub.TestTopicData{
 					Exists: true,
 				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       10 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+					},
+				},
 			},
 		},
-		WantCreates: []runtime.Object{
-			newReceiveAdapter(context.Background(), testImage, nil),
-		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -668,7 +825,8 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
 				}),
 				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionProjectID(testProject),
@@ -679,14 +837,16 @@ func TestAllCases(t *testing.T) {
 				// Updates
 				WithPullSubscriptionStatusObservedGeneration(generation),
 				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
 				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 			),
 		}},
 		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
 			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 	}, {
-		Name: "successful create - reuse existing receive adapter - match",
+		Name: "subscription exists with a different retry policy, updated",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -696,29 +856,39 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+					RetryPolicy: &pubsubv1beta1.RetryPolicy{
+						MinimumBackoff: ptr.String("5s"),
+					},
 				}),
+				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
 			),
 			newSink(),
 			newSecret(),
-			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "SubscriptionUpdated", "Updated Pub/Sub subscription %s: retryPolicy none -> minimumBackoff=5s maximumBackoff=10m0s", testSubscriptionID),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
 		OtherTestData: map[string]interface{}{
 			"ps": gpubsub.TestClientData{
 				TopicData: gpubsub.TestTopicData{
 					Exists: true,
 				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       30 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+					},
+				},
 			},
 		},
-		Key: testNS + "/" + sourceName,
-		WantEvents: []string{
-			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
-			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
-		},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
-		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -728,21 +898,31 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+					RetryPolicy: &pubsubv1beta1.RetryPolicy{
+						MinimumBackoff: ptr.String("5s"),
+					},
 				}),
 				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionProjectID(testProject),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
-				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
 				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
 				WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 	}, {
-		Name: "successful create - reuse existing receive adapter - mismatch",
+		Name: "subscription exists with a retry policy removed from the spec, updated",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -752,64 +932,71 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
 				}),
+				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionSink(sinkGVK, sinkName),
-				WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				WithPullSubscriptionMarkSink(sinkURI),
 			),
 			newSink(),
-			newTransformer(),
 			newSecret(),
-			newReceiveAdapter(context.Background(), "old"+testImage, nil),
-		},
-		OtherTestData: map[string]interface{}{
-			"ps": gpubsub.TestClientData{
-				TopicData: gpubsub.TestTopicData{
-					Exists: true,
-				},
-			},
 		},
 		Key: testNS + "/" + sourceName,
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "SubscriptionUpdated", "Updated Pub/Sub subscription %s: retryPolicy minimumBackoff=5s maximumBackoff=10m0s -> none", testSubscriptionID),
 			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
 		},
-		WantUpdates: []clientgotesting.UpdateActionImpl{{
-			ActionImpl: clientgotesting.ActionImpl{
-				Namespace: testNS,
-				Verb:      "update",
-				Resource:  receiveAdapterGVR(),
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       30 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+						RetryPolicy: &pubsub.RetryPolicy{
+							MinimumBackoff: 5 * time.Second,
+							MaximumBackoff: 10 * time.Minute,
+						},
+					},
+				},
 			},
-			Object: newReceiveAdapter(context.Background(), testImage, transformerURI),
-		}},
-		WantPatches: []clientgotesting.PatchActionImpl{
-			patchFinalizers(testNS, sourceName, resourceGroup),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
-				//WithPullSubscriptionFinalizers(resourceGroup),
 				WithPullSubscriptionObjectMetaGeneration(generation),
 				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
 					PubSubSpec: duckv1beta1.PubSubSpec{
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
 				}),
 				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionProjectID(testProject),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
-				WithPullSubscriptionTransformer(transformerGVK, transformerName),
-				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
-				WithPullSubscriptionMarkTransformer(transformerURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
 				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
 			),
 		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
 	}, {
-		Name: "deleting - failed to delete subscription",
+		Name: "subscription exists without the dead letter policy of the spec, updated",
 		Objects: []runtime.Object{
 			NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
@@ -819,51 +1006,730 @@ func TestAllCases(t *testing.T) {
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+					DeadLetterPolicy: &pubsubv1beta1.DeadLetterPolicy{
+						DeadLetterTopic: "dead-letter",
+					},
 				}),
+				WithInitPullSubscriptionConditions,
 				WithPullSubscriptionSink(sinkGVK, sinkName),
-				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
-				WithPullSubscriptionDeleted,
 			),
+			newSink(),
 			newSecret(),
 		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "SubscriptionUpdated", "Updated Pub/Sub subscription %s: deadLetterPolicy none -> deadLetterTopic=projects/%s/topics/dead-letter maxDeliveryAttempts=5", testSubscriptionID, testProject),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
 		OtherTestData: map[string]interface{}{
 			"ps": gpubsub.TestClientData{
 				TopicData: gpubsub.TestTopicData{
 					Exists: true,
 				},
 				SubscriptionData: gpubsub.TestSubscriptionData{
-					Exists:    true,
-					DeleteErr: errors.New("subscription-delete-induced-error"),
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       30 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+					},
 				},
 			},
 		},
-		Key: testNS + "/" + sourceName,
-		WantEvents: []string{
-			Eventf(corev1.EventTypeWarning, "SubscriptionDeleteFailed", "Failed to delete Pub/Sub subscription: subscription-delete-induced-error"),
-		},
-		WantStatusUpdates: nil,
-	}, {
-		Name: "successfully deleted subscription",
-		Objects: []runtime.Object{
-			NewPullSubscription(sourceName, testNS,
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
 				WithPullSubscriptionUID(sourceUID),
 				WithPullSubscriptionObjectMetaGeneration(generation),
-				WithPullSubscriptionStatusObservedGeneration(generation),
 				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
 					PubSubSpec: duckv1beta1.PubSubSpec{
 						Secret:  &secret,
 						Project: testProject,
 					},
-					Topic: testTopicID,
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+					DeadLetterPolicy: &pubsubv1beta1.DeadLetterPolicy{
+						DeadLetterTopic: "dead-letter",
+					},
 				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
 				WithPullSubscriptionSink(sinkGVK, sinkName),
-				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
-				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
 				WithPullSubscriptionMarkSink(sinkURI),
-				WithPullSubscriptionDeleted,
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "subscription exists with a dead letter policy removed from the spec, updated",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "SubscriptionUpdated", "Updated Pub/Sub subscription %s: deadLetterPolicy deadLetterTopic=projects/other/topics/dead-letter maxDeliveryAttempts=10 -> none", testSubscriptionID),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       30 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+						DeadLetterPolicy: &cloudpubsub.DeadLetterPolicy{
+							DeadLetterTopic:     "projects/other/topics/dead-letter",
+							MaxDeliveryAttempts: 10,
+						},
+					},
+				},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "update subscription fails",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "SubscriptionReconcileFailed", "Failed to reconcile Pub/Sub subscription: subscription-update-induced-error"),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+					Config: pubsub.SubscriptionConfig{
+						AckDeadline:       10 * time.Second,
+						RetentionDuration: 7 * 24 * time.Hour,
+					},
+					UpdateErr: errors.New("subscription-update-induced-error"),
+				},
+			},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic:       testTopicID,
+					AckDeadline: ptr.String("30s"),
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: %s", failedToReconcileSubscriptionMsg, "subscription-update-induced-error"))),
+		}},
+	}, {
+		Name: "sink namespace empty, default to the source one",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: newSinkDestination(""),
+						},
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "sink URI set instead of ref",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+						SourceSpec: duckv1.SourceSpec{
+							Sink: duckv1.Destination{
+								URI: sinkURI,
+							},
+						},
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "successful create - reuse existing receive adapter - match",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+			),
+			newSink(),
+			newSecret(),
+			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+			),
+		}},
+	}, {
+		Name: "successful create - reuse existing receive adapter - mismatch",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionTransformer(transformerGVK, transformerName),
+			),
+			newSink(),
+			newTransformer(),
+			newSecret(),
+			newReceiveAdapter(context.Background(), "old"+testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, transformerURI)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				//WithPullSubscriptionFinalizers(resourceGroup),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionTransformer(transformerGVK, transformerName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkTransformer(transformerURI),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+			),
+		}},
+	}, {
+		Name: "successful create - adopt orphaned receive adapter",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+			),
+			newSink(),
+			newSecret(),
+			newOrphanedReceiveAdapter(context.Background(), testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "ReceiveAdapterAdopted", "Adopted receive adapter %s/%s", testNS, deploymentName()),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapter(context.Background(), testImage, nil)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+			),
+		}},
+	}, {
+		Name: "agent mode - replaces receive adapter",
+		// The agent ConfigMap is in the system namespace.
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionAnnotations(agentAnnotations()),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+			),
+			newSink(),
+			newSecret(),
+			newAvailableReceiveAdapter(context.Background(), testImage, nil),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			subscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantCreates: []runtime.Object{
+			newAgentConfigMap(map[string]string{
+				testNS + "." + sourceName: newAgentSubscription(),
+			}),
+		},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+			Name: deploymentName(),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionAnnotations(agentAnnotations()),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployedByAgent,
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+			),
+		}},
+	}, {
+		Name: "deleting - removes agent subscription",
+		// The agent ConfigMap is in the system namespace.
+		SkipNamespaceValidation: true,
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionAnnotations(agentAnnotations()),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployedByAgent,
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+			newAgentConfigMap(map[string]string{
+				testNS + "." + sourceName: newAgentSubscription(),
+				"othernamespace.other":    "{}",
+			}),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newAgentConfigMap(map[string]string{
+				"othernamespace.other": "{}",
+			}),
+		}},
+		WantEvents: nil,
+	}, {
+		Name: "deleting - failed to delete subscription",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists: true,
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists:    true,
+					DeleteErr: errors.New("subscription-delete-induced-error"),
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "SubscriptionDeleteFailed", "Failed to delete Pub/Sub subscription: subscription-delete-induced-error"),
+		},
+		WantStatusUpdates: nil,
+	}, {
+		Name: "deleting - failed to delete created topic",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createTopicSpec),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionCreatedTopicID(testTopicID),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"ps": gpubsub.TestClientData{
+				TopicData: gpubsub.TestTopicData{
+					Exists:    true,
+					DeleteErr: errors.New("topic-delete-induced-error"),
+				},
+				SubscriptionData: gpubsub.TestSubscriptionData{
+					Exists: true,
+				},
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "TopicDeleteFailed", "Failed to delete Pub/Sub topic: topic-delete-induced-error"),
+		},
+		WantStatusUpdates: nil,
+	}, {
+		Name: "successfully deleted subscription",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(pubsubv1beta1.PullSubscriptionSpec{
+					PubSubSpec: duckv1beta1.PubSubSpec{
+						Secret:  &secret,
+						Project: testProject,
+					},
+					Topic: testTopicID,
+				}),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
 			),
 			newSecret(),
 		},
@@ -879,6 +1745,271 @@ func TestAllCases(t *testing.T) {
 		},
 		Key:        testNS + "/" + sourceName,
 		WantEvents: nil,
+	}, {
+		Name: "lite - topic does not exist",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(liteSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "SubscriptionReconcileFailed", "Failed to reconcile Pub/Sub subscription: Topic %q does not exist", testLiteTopicPath),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists: false,
+			},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(liteSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: Topic %q does not exist", failedToReconcileSubscriptionMsg, testLiteTopicPath))),
+		}},
+	}, {
+		Name: "lite - create topic fails",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeWarning, "SubscriptionReconcileFailed", "Failed to reconcile Pub/Sub subscription: topic-create-induced-error"),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists:    false,
+				CreateTopicErr: errors.New("topic-create-induced-error"),
+			},
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				WithPullSubscriptionMarkNoSubscription("SubscriptionReconcileFailed", fmt.Sprintf("%s: %s", failedToReconcileSubscriptionMsg, "topic-create-induced-error"))),
+		}},
+	}, {
+		Name: "lite - topic does not exist, created",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Pub/Sub Lite topic %q", testLiteTopicPath),
+			liteSubscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists: false,
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionCreatedTopicID(testTopicID),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testLiteSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapterWithSpec(context.Background(), testImage, createLiteTopicSpec)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "lite - successfully created subscription",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(liteSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sourceName),
+			liteSubscriptionCreatedEvent,
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionReconciled", `PullSubscription reconciled: "%s/%s"`, testNS, sourceName),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists: true,
+			},
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(liteSpec),
+				WithInitPullSubscriptionConditions,
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionMarkNoTransformer("TransformerNil", "Transformer is nil"),
+				WithPullSubscriptionTransformerURI(nil),
+				// Updates
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testLiteSubscriptionName),
+				WithPullSubscriptionMarkNoDeployed(deploymentName(), testNS),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			NewApplyPatch(t, newReceiveAdapterWithSpec(context.Background(), testImage, liteSpec)),
+			patchFinalizers(testNS, sourceName, resourceGroup),
+		},
+	}, {
+		Name: "lite - deleting - failed to delete subscription",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(liteSpec),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testLiteSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists:           true,
+				SubscriptionExists:    true,
+				DeleteSubscriptionErr: errors.New("subscription-delete-induced-error"),
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "SubscriptionDeleteFailed", "Failed to delete Pub/Sub subscription: subscription-delete-induced-error"),
+		},
+		WantStatusUpdates: nil,
+	}, {
+		Name: "lite - deleting - failed to delete created topic",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionCreatedTopicID(testTopicID),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testLiteSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists:        true,
+				DeleteTopicErr:     errors.New("topic-delete-induced-error"),
+				SubscriptionExists: true,
+			},
+		},
+		Key: testNS + "/" + sourceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "TopicDeleteFailed", "Failed to delete Pub/Sub topic: topic-delete-induced-error"),
+		},
+		WantStatusUpdates: nil,
+	}, {
+		Name: "lite - successfully deleted subscription and created topic",
+		Objects: []runtime.Object{
+			NewPullSubscription(sourceName, testNS,
+				WithPullSubscriptionUID(sourceUID),
+				WithPullSubscriptionObjectMetaGeneration(generation),
+				WithPullSubscriptionStatusObservedGeneration(generation),
+				WithPullSubscriptionSpec(createLiteTopicSpec),
+				WithPullSubscriptionSink(sinkGVK, sinkName),
+				WithPullSubscriptionProjectID(testProject),
+				WithPullSubscriptionCreatedTopicID(testTopicID),
+				WithPullSubscriptionMarkSubscribed(testSubscriptionID),
+				WithPullSubscriptionSubscriptionName(testLiteSubscriptionName),
+				WithPullSubscriptionMarkDeployed(deploymentName(), testNS),
+				WithPullSubscriptionMarkSink(sinkURI),
+				WithPullSubscriptionDeleted,
+			),
+			newSecret(),
+		},
+		OtherTestData: map[string]interface{}{
+			"lite": gpubsublite.TestAdminClientData{
+				TopicExists:        true,
+				SubscriptionExists: true,
+			},
+		},
+		Key:        testNS + "/" + sourceName,
+		WantEvents: nil,
 	}}
 
 	defer logtesting.ClearAll()
@@ -887,6 +2018,7 @@ func TestAllCases(t *testing.T) {
 		pubsubBase := &intevents.PubSubBase{
 			Base: reconciler.NewBase(ctx, controllerAgentName, cmw),
 		}
+		naming, _ := testData["naming"].(resources.SubscriptionNaming)
 		r := &Reconciler{
 			Base: &psreconciler.Base{
 				PubSubBase:             pubsubBase,
@@ -895,6 +2027,8 @@ func TestAllCases(t *testing.T) {
 				UriResolver:            resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 				ReceiveAdapterImage:    testImage,
 				CreateClientFn:         gpubsub.TestClientCreator(testData["ps"]),
+				SubscriptionNaming:     naming,
+				CreateLiteClientFn:     gpubsublite.TestAdminClientCreator(testData["lite"]),
 				ControllerAgentName:    controllerAgentName,
 				ResourceGroup:          resourceGroup,
 			},
@@ -923,6 +2057,22 @@ func newReceiveAdapter(ctx context.Context, image string, transformer *apis.URL)
 	return ra
 }
 
+// newReceiveAdapterWithSpec returns the receive adapter of a PullSubscription
+// with spec, e.g. one of a Pub/Sub Lite topic.
+func newReceiveAdapterWithSpec(ctx context.Context, image string, spec pubsubv1beta1.PullSubscriptionSpec) runtime.Object {
+	ps := NewPullSubscription(sourceName, testNS,
+		WithPullSubscriptionUID(sourceUID),
+		WithPullSubscriptionSpec(spec))
+	args := &resources.ReceiveAdapterArgs{
+		Image:            image,
+		PullSubscription: ps,
+		Labels:           resources.GetLabels(controllerAgentName, sourceName),
+		SubscriptionID:   testSubscriptionID,
+		SinkURI:          sinkURI,
+	}
+	return resources.MakeReceiveAdapter(ctx, args)
+}
+
 func newAvailableReceiveAdapter(ctx context.Context, image string, transformer *apis.URL) runtime.Object {
 	obj := newReceiveAdapter(ctx, image, transformer)
 	ra := obj.(*v1.Deployment)
@@ -930,6 +2080,45 @@ func newAvailableReceiveAdapter(ctx context.Context, image string, transformer *
 	return obj
 }
 
+// newOrphanedReceiveAdapter returns an available receive adapter without its
+// owner references and labels, as if it was restored from a backup.
+func newOrphanedReceiveAdapter(ctx context.Context, image string, transformer *apis.URL) runtime.Object {
+	obj := newAvailableReceiveAdapter(ctx, image, transformer)
+	ra := obj.(*v1.Deployment)
+	ra.OwnerReferences = nil
+	ra.Labels = nil
+	return obj
+}
+
+func agentAnnotations() map[string]string {
+	return map[string]string{
+		duckv1beta1.ReceiveAdapterModeAnnotation: duckv1beta1.ReceiveAdapterModeAgent,
+	}
+}
+
+func newAgentConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace(),
+			Name:      resources.AgentConfigMapName,
+		},
+		Data: data,
+	}
+}
+
+func newAgentSubscription() string {
+	sub := resources.MakeAgentSubscription(context.Background(), &resources.ReceiveAdapterArgs{
+		PullSubscription: newPullSubscription(),
+		SubscriptionID:   testSubscriptionID,
+		SinkURI:          sinkURI,
+	})
+	b, err := json.Marshal(sub)
+	if err != nil {
+		panic(err)
+	}
+	return string(b)
+}
+
 func newPullSubscription() *pubsubv1beta1.PullSubscription {
 	return NewPullSubscription(sourceName, testNS,
 		WithPullSubscriptionUID(sourceUID),
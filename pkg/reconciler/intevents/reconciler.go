@@ -43,8 +43,6 @@ const (
 	PullSubscriptionStatusPropagateFailedReason = "PullSubscriptionStatusPropagateFailed"
 )
 
-var falseVal = false
-
 type PubSubBase struct {
 	*reconciler.Base
 
@@ -81,18 +79,10 @@ func (psb *PubSubBase) reconcileTopic(ctx context.Context, pubsubable duck.PubSu
 		return nil, fmt.Errorf("nil pubsubable passed in")
 	}
 
-	name := pubsubable.GetObjectMeta().GetName()
-	args := &resources.TopicArgs{
-		Namespace:       pubsubable.GetObjectMeta().GetNamespace(),
-		Name:            name,
-		Spec:            pubsubable.PubSubSpec(),
-		EnablePublisher: &falseVal,
-		Owner:           pubsubable,
-		Topic:           topic,
-		Labels:          resources.GetLabels(psb.receiveAdapterName, name),
-		Annotations:     pubsubable.GetObjectMeta().GetAnnotations(),
-	}
-	newTopic := resources.MakeTopic(args)
+	newTopic := resources.MakeSourceTopic(pubsubable, &resources.SourceArgs{
+		Topic:              topic,
+		ReceiveAdapterName: psb.receiveAdapterName,
+	})
 
 	topics := psb.pubsubClient.InternalV1beta1().Topics(newTopic.Namespace)
 	t, err := topics.Get(newTopic.Name, v1.GetOptions{})
@@ -133,28 +123,17 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 		logging.FromContext(ctx).Desugar().Error("Nil pubsubable passed in")
 		return nil, pkgreconciler.NewEvent(corev1.EventTypeWarning, nilPubsubableReason, "nil pubsubable passed in")
 	}
-	namespace := pubsubable.GetObjectMeta().GetNamespace()
-	name := pubsubable.GetObjectMeta().GetName()
-	annotations := pubsubable.GetObjectMeta().GetAnnotations()
-	spec := pubsubable.PubSubSpec()
 	status := pubsubable.PubSubStatus()
-
 	cs := pubsubable.ConditionSet()
 
-	args := &resources.PullSubscriptionArgs{
-		Namespace:   namespace,
-		Name:        name,
-		Spec:        spec,
-		Owner:       pubsubable,
-		Topic:       topic,
-		AdapterType: psb.adapterType,
-		Labels:      resources.GetLabels(psb.receiveAdapterName, name),
-		Annotations: resources.GetAnnotations(annotations, resourceGroup),
-	}
-	if isPushCompatible {
-		args.Mode = inteventsv1beta1.ModePushCompatible
-	}
-	newPS := resources.MakePullSubscription(args)
+	newPS := resources.MakeSourcePullSubscription(pubsubable, &resources.SourceArgs{
+		Topic:              topic,
+		ReceiveAdapterName: psb.receiveAdapterName,
+		ResourceGroup:      resourceGroup,
+		AdapterType:        psb.adapterType,
+		PushCompatible:     isPushCompatible,
+	})
+	namespace, name := newPS.Namespace, newPS.Name
 
 	pullSubscriptions := psb.pubsubClient.InternalV1beta1().PullSubscriptions(namespace)
 	ps, err := pullSubscriptions.Get(name, v1.GetOptions{})
@@ -188,6 +167,7 @@ func (psb *PubSubBase) ReconcilePullSubscription(ctx context.Context, pubsubable
 	}
 
 	status.SubscriptionID = ps.Status.SubscriptionID
+	status.SubscriptionName = ps.Status.SubscriptionName
 	status.SinkURI = ps.Status.SinkURI
 	return ps, nil
 }
@@ -248,6 +228,7 @@ func propagateTopicStatus(t *inteventsv1beta1.Topic, status *duckv1beta1.PubSubS
 		return fmt.Errorf("Topic %q mismatch: expected %q got %q", t.Name, topic, t.Status.TopicID)
 	}
 	status.TopicID = t.Status.TopicID
+	status.TopicName = t.Status.TopicName
 	status.ProjectID = t.Status.ProjectID
 	status.MarkTopicReady(cs)
 	return nil
@@ -271,6 +252,7 @@ func (psb *PubSubBase) DeletePubSub(ctx context.Context, pubsubable duck.PubSuba
 	}
 	status.MarkTopicFailed(cs, "TopicDeleted", "Successfully deleted Topic: %s", name)
 	status.TopicID = ""
+	status.TopicName = ""
 	status.ProjectID = ""
 
 	// Delete the pullsubscription
@@ -59,7 +59,8 @@ const (
 )
 
 var (
-	trueVal = true
+	trueVal  = true
+	falseVal = false
 
 	testTopicURI = "http://" + name + "-topic." + testNS + ".svc.cluster.local"
 
@@ -16,11 +16,28 @@ limitations under the License.
 
 package resources
 
+import (
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+// GetAnnotations returns the annotations of the PullSubscription of a source
+// with the original annotations, recording the resource group of the source
+// for the metrics.
 func GetAnnotations(original map[string]string, resourceGroup string) map[string]string {
-	annotations := original
-	if annotations == nil {
-		annotations = make(map[string]string)
-	}
+	annotations := PropagatedAnnotations(original)
 	annotations["metrics-resource-group"] = resourceGroup
 	return annotations
 }
+
+// PropagatedAnnotations returns a copy of the original annotations of a
+// source for its Topic and PullSubscription, without the annotations that
+// only apply to the source itself. The children are created with the current
+// API version, so they are not deprecated even if the source is.
+func PropagatedAnnotations(original map[string]string) map[string]string {
+	annotations := make(map[string]string, len(original)+1)
+	for k, v := range original {
+		annotations[k] = v
+	}
+	delete(annotations, duckv1beta1.DeprecatedAPIVersionAnnotation)
+	return annotations
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func TestGetAnnotations(t *testing.T) {
+	original := map[string]string{
+		"custom": "value",
+		duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+	}
+	want := map[string]string{
+		"custom":                 "value",
+		"metrics-resource-group": "storages.events.cloud.google.com",
+	}
+	got := GetAnnotations(original, "storages.events.cloud.google.com")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+	// The annotations of the source are left as they are.
+	if _, ok := original["metrics-resource-group"]; ok {
+		t.Error("GetAnnotations() modified the original annotations")
+	}
+	if _, ok := original[duckv1beta1.DeprecatedAPIVersionAnnotation]; !ok {
+		t.Error("GetAnnotations() removed the deprecated API version from the original annotations")
+	}
+}
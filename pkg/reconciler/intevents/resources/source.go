@@ -0,0 +1,75 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	duck "github.com/google/knative-gcp/pkg/duck/v1beta1"
+)
+
+// SourceArgs are the arguments of the Topic and PullSubscription of a source.
+type SourceArgs struct {
+	// Topic is the Pub/Sub topic the source subscribes to.
+	Topic string
+	// ReceiveAdapterName is the name of the receive adapter of the source,
+	// labeling its children.
+	ReceiveAdapterName string
+	// ResourceGroup is the resource group of the source, recorded on its
+	// PullSubscription for the metrics.
+	ResourceGroup string
+	// AdapterType is the adapter type of the PullSubscription.
+	AdapterType string
+	// PushCompatible is whether the PullSubscription is in push-compatible
+	// mode.
+	PushCompatible bool
+}
+
+// MakeSourceTopic makes the Topic of the source, which creates and deletes
+// the Pub/Sub topic but doesn't publish to it.
+func MakeSourceTopic(s duck.PubSubable, args *SourceArgs) *inteventsv1beta1.Topic {
+	enablePublisher := false
+	name := s.GetObjectMeta().GetName()
+	return MakeTopic(&TopicArgs{
+		Namespace:       s.GetObjectMeta().GetNamespace(),
+		Name:            name,
+		Spec:            s.PubSubSpec(),
+		EnablePublisher: &enablePublisher,
+		Owner:           s,
+		Topic:           args.Topic,
+		Labels:          GetLabels(args.ReceiveAdapterName, name),
+		Annotations:     PropagatedAnnotations(s.GetObjectMeta().GetAnnotations()),
+	})
+}
+
+// MakeSourcePullSubscription makes the PullSubscription of the source.
+func MakeSourcePullSubscription(s duck.PubSubable, args *SourceArgs) *inteventsv1beta1.PullSubscription {
+	name := s.GetObjectMeta().GetName()
+	psArgs := &PullSubscriptionArgs{
+		Namespace:   s.GetObjectMeta().GetNamespace(),
+		Name:        name,
+		Spec:        s.PubSubSpec(),
+		Owner:       s,
+		Topic:       args.Topic,
+		AdapterType: args.AdapterType,
+		Labels:      GetLabels(args.ReceiveAdapterName, name),
+		Annotations: GetAnnotations(s.GetObjectMeta().GetAnnotations(), args.ResourceGroup),
+	}
+	if args.PushCompatible {
+		psArgs.Mode = inteventsv1beta1.ModePushCompatible
+	}
+	return MakePullSubscription(psArgs)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+func TestMakeSourceChildren(t *testing.T) {
+	s := &v1beta1.CloudPubSubSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "source",
+			Namespace: "ns",
+			Annotations: map[string]string{
+				"custom": "value",
+				duckv1beta1.DeprecatedAPIVersionAnnotation: "events.cloud.google.com/v1alpha1",
+			},
+		},
+	}
+	args := &SourceArgs{
+		Topic:              "topic",
+		ReceiveAdapterName: "cloudpubsubsource.events.cloud.google.com",
+		ResourceGroup:      "cloudpubsubsources.events.cloud.google.com",
+		PushCompatible:     true,
+	}
+	wantLabels := map[string]string{
+		"receive-adapter":        "cloudpubsubsource.events.cloud.google.com",
+		intevents.SourceLabelKey: "source",
+	}
+
+	topic := MakeSourceTopic(s, args)
+	if diff := cmp.Diff(wantLabels, topic.Labels); diff != "" {
+		t.Errorf("Topic labels (-want, +got) = %v", diff)
+	}
+	if diff := cmp.Diff(map[string]string{"custom": "value"}, topic.Annotations); diff != "" {
+		t.Errorf("Topic annotations (-want, +got) = %v", diff)
+	}
+	if topic.Spec.EnablePublisher == nil || *topic.Spec.EnablePublisher {
+		t.Errorf("Topic EnablePublisher = %v, want false", topic.Spec.EnablePublisher)
+	}
+
+	ps := MakeSourcePullSubscription(s, args)
+	if diff := cmp.Diff(wantLabels, ps.Labels); diff != "" {
+		t.Errorf("PullSubscription labels (-want, +got) = %v", diff)
+	}
+	wantAnnotations := map[string]string{
+		"custom":                 "value",
+		"metrics-resource-group": "cloudpubsubsources.events.cloud.google.com",
+	}
+	if diff := cmp.Diff(wantAnnotations, ps.Annotations); diff != "" {
+		t.Errorf("PullSubscription annotations (-want, +got) = %v", diff)
+	}
+	if ps.Spec.Mode != inteventsv1beta1.ModePushCompatible {
+		t.Errorf("PullSubscription mode = %q, want %q", ps.Spec.Mode, inteventsv1beta1.ModePushCompatible)
+	}
+}
@@ -28,6 +28,8 @@ import (
 
 	"knative.dev/pkg/configmap"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/metrics"
+	_ "knative.dev/pkg/metrics/testing"
 	. "knative.dev/pkg/reconciler/testing"
 
 	// Fake injection informers
@@ -51,6 +53,13 @@ func TestNew(t *testing.T) {
 				Namespace: system.Namespace(),
 			},
 			Data: map[string]string{},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      metrics.ConfigMapName(),
+				Namespace: system.Namespace(),
+			},
+			Data: map[string]string{},
 		})
 	c := newController(ctx, cmw, iamtesting.NoopIAMPolicyManager, iamtesting.NewGCPAuthTestStore(t, nil))
 
@@ -22,9 +22,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/kmeta"
+	"knative.dev/serving/pkg/apis/autoscaling"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
 	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 )
 
 // PublisherArgs are the arguments needed to create a Topic publisher.
@@ -35,6 +37,7 @@ type PublisherArgs struct {
 	Labels map[string]string
 
 	TracingConfig string
+	MetricsConfig string
 }
 
 const (
@@ -65,8 +68,24 @@ func makePublisherPodSpec(args *PublisherArgs) *corev1.PodSpec {
 		}, {
 			Name:  "K_TRACING_CONFIG",
 			Value: args.TracingConfig,
+		}, {
+			Name:  "K_METRICS_CONFIG",
+			Value: args.MetricsConfig,
 		}},
 	}
+	if dlt, ok := args.Topic.Annotations[v1beta1.PublisherDeadLetterTopicAnnotation]; ok {
+		publisherContainer.Env = append(publisherContainer.Env, corev1.EnvVar{
+			Name:  "PUBSUB_DEAD_LETTER_TOPIC_ID",
+			Value: dlt,
+		})
+	}
+	if size, ok := args.Topic.Annotations[v1beta1.PublisherBufferSizeAnnotation]; ok {
+		publisherContainer.Env = append(publisherContainer.Env, corev1.EnvVar{
+			Name:  "BUFFER_SIZE",
+			Value: size,
+		})
+	}
+	publisherContainer.Env = append(publisherContainer.Env, endpoints.EnvVars()...)
 
 	// If k8s service account is specified, use that service account as credential.
 	if args.Topic.Spec.ServiceAccountName != "" {
@@ -125,7 +144,8 @@ func MakePublisher(args *PublisherArgs) *servingv1.Service {
 			ConfigurationSpec: servingv1.ConfigurationSpec{
 				Template: servingv1.RevisionTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
-						Labels: args.Labels,
+						Labels:      args.Labels,
+						Annotations: publisherAnnotations(args.Topic),
 					},
 					Spec: servingv1.RevisionSpec{
 						PodSpec: *podSpec,
@@ -135,3 +155,15 @@ func MakePublisher(args *PublisherArgs) *servingv1.Service {
 		},
 	}
 }
+
+// publisherAnnotations returns the annotations of the publisher revisions.
+// Publishers that buffer events don't scale to zero, as that would strand the
+// buffered events.
+func publisherAnnotations(topic *v1beta1.Topic) map[string]string {
+	if _, ok := topic.Annotations[v1beta1.PublisherBufferSizeAnnotation]; !ok {
+		return nil
+	}
+	return map[string]string{
+		autoscaling.MinScaleAnnotationKey: "1",
+	}
+}
@@ -53,6 +53,7 @@ func TestMakePublisher(t *testing.T) {
 		Topic:         topic,
 		Labels:        GetLabels("controller-name", "topic-name"),
 		TracingConfig: "TracingConfig-ABC123",
+		MetricsConfig: "MetricsConfig-ABC123",
 	})
 
 	gotb, _ := json.MarshalIndent(pub, "", "  ")
@@ -113,6 +114,10 @@ func TestMakePublisher(t *testing.T) {
                 "name": "K_TRACING_CONFIG",
                 "value": "TracingConfig-ABC123"
               },
+              {
+                "name": "K_METRICS_CONFIG",
+                "value": "MetricsConfig-ABC123"
+              },
               {
                 "name": "GOOGLE_APPLICATION_CREDENTIALS",
                 "value": "/var/secrets/google/eventing-secret-key"
@@ -162,6 +167,7 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 		Topic:         topic,
 		Labels:        GetLabels("controller-name", "topic-name"),
 		TracingConfig: "TracingConfig-ABC123",
+		MetricsConfig: "MetricsConfig-ABC123",
 	})
 
 	yes := true
@@ -206,6 +212,9 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 								}, {
 									Name:  "K_TRACING_CONFIG",
 									Value: "TracingConfig-ABC123",
+								}, {
+									Name:  "K_METRICS_CONFIG",
+									Value: "MetricsConfig-ABC123",
 								}},
 							}},
 							ServiceAccountName: "test",
@@ -220,6 +229,60 @@ func TestMakePublisherWithServiceAccount(t *testing.T) {
 	}
 }
 
+func TestMakePublisherWithFallback(t *testing.T) {
+	topic := &v1beta1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "topic-name",
+			Namespace: "topic-namespace",
+			Annotations: map[string]string{
+				v1beta1.PublisherDeadLetterTopicAnnotation: "dead-letter-topic",
+				v1beta1.PublisherBufferSizeAnnotation:      "100",
+			},
+		},
+		Spec: v1beta1.TopicSpec{
+			Project: "eventing-name",
+			Topic:   "topic-name",
+		},
+	}
+
+	got := MakePublisher(&PublisherArgs{
+		Image:  "test-image",
+		Topic:  topic,
+		Labels: GetLabels("controller-name", "topic-name"),
+	})
+
+	wantAnnotations := map[string]string{
+		"autoscaling.knative.dev/minScale": "1",
+	}
+	if diff := cmp.Diff(wantAnnotations, got.Spec.Template.Annotations); diff != "" {
+		t.Errorf("unexpected revision annotations (-want, +got) = %v", diff)
+	}
+
+	wantEnv := []corev1.EnvVar{{
+		Name:  "PROJECT_ID",
+		Value: "eventing-name",
+	}, {
+		Name:  "PUBSUB_TOPIC_ID",
+		Value: "topic-name",
+	}, {
+		Name: "K_TRACING_CONFIG",
+	}, {
+		Name: "K_METRICS_CONFIG",
+	}, {
+		Name:  "PUBSUB_DEAD_LETTER_TOPIC_ID",
+		Value: "dead-letter-topic",
+	}, {
+		Name:  "BUFFER_SIZE",
+		Value: "100",
+	}, {
+		Name:  "GOOGLE_APPLICATION_CREDENTIALS",
+		Value: "/var/secrets/google/key.json",
+	}}
+	if diff := cmp.Diff(wantEnv, got.Spec.Template.Spec.Containers[0].Env); diff != "" {
+		t.Errorf("unexpected env (-want, +got) = %v", diff)
+	}
+}
+
 func TestMakePublisherSelector(t *testing.T) {
 	selector := GetLabelSelector("controller-name", "topic-name")
 
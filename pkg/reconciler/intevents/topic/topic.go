@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"cloud.google.com/go/pubsub"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	corev1 "k8s.io/api/core/v1"
@@ -34,6 +35,7 @@ import (
 	"github.com/google/knative-gcp/pkg/utils"
 
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/metrics"
 	"knative.dev/pkg/reconciler"
 	tracingconfig "knative.dev/pkg/tracing/config"
 
@@ -46,6 +48,7 @@ import (
 	topicreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1beta1/topic"
 	listers "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub"
+	kgcpreconciler "github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/identity"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents"
 	"github.com/google/knative-gcp/pkg/reconciler/intevents/topic/resources"
@@ -54,11 +57,16 @@ import (
 const (
 	resourceGroup = "topics.internal.events.cloud.google.com"
 
-	deleteTopicFailed               = "TopicDeleteFailed"
-	deleteWorkloadIdentityFailed    = "WorkloadIdentityDeleteFailed"
+	deleteTopicFailed            = "TopicDeleteFailed"
+	deleteWorkloadIdentityFailed = "WorkloadIdentityDeleteFailed"
+
+	// publisherComponent is the component name of the publisher metrics.
+	publisherComponent = "publisher"
+
 	reconciledPublisherFailedReason = "PublisherReconcileFailed"
 	reconciledSuccessReason         = "TopicReconciled"
 	reconciledTopicFailedReason     = "TopicReconcileFailed"
+	topicCreatedReason              = "TopicCreated"
 	workloadIdentityFailed          = "WorkloadIdentityReconcileFailed"
 )
 
@@ -76,6 +84,7 @@ type Reconciler struct {
 
 	publisherImage string
 	tracingConfig  *tracingconfig.Config
+	metricsConfig  *metrics.ExporterOptions
 
 	// createClientFn is the function used to create the Pub/Sub client that interacts with Pub/Sub.
 	// This is needed so that we can inject a mock client for UTs purposes.
@@ -90,6 +99,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, topic *v1beta1.Topic) re
 
 	topic.Status.InitializeConditions()
 	topic.Status.ObservedGeneration = topic.Generation
+	kgcpreconciler.MarkDeprecated(ctx, topic, &topic.Status, v1beta1.SchemeGroupVersion)
 
 	// If topic doesn't have ownerReference and ServiceAccountName is provided, reconcile workload identity.
 	// Otherwise, its owner will reconcile workload identity.
@@ -99,13 +109,17 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, topic *v1beta1.Topic) re
 		}
 	}
 
-	if err := r.reconcileTopic(ctx, topic); err != nil {
+	if err := r.reconcileTopic(ctx, topic); kgcpreconciler.IsPlannedChange(err) {
+		topic.Status.MarkNoTopic(kgcpreconciler.DryRunReason, "%s", err.Error())
+		return err
+	} else if err != nil {
 		topic.Status.MarkNoTopic(reconciledTopicFailedReason, "Failed to reconcile Pub/Sub topic: %s", err.Error())
 		return reconciler.NewEvent(corev1.EventTypeWarning, reconciledTopicFailedReason, "Failed to reconcile Pub/Sub topic: %s", err.Error())
 	}
 	topic.Status.MarkTopicReady()
 	// Set the topic being used.
 	topic.Status.TopicID = topic.Spec.Topic
+	topic.Status.TopicName = utils.TopicName(topic.Status.ProjectID, topic.Spec.Topic)
 
 	// If enablePublisher is false, then skip creating the publisher.
 	if enablePublisher := topic.Spec.EnablePublisher; enablePublisher != nil && !*enablePublisher {
@@ -157,8 +171,14 @@ func (r *Reconciler) reconcileTopic(ctx context.Context, topic *v1beta1.Topic) e
 			logging.FromContext(ctx).Desugar().Error("Topic does not exist and the topic policy doesn't allow creation")
 			return fmt.Errorf("Topic %q does not exist and the topic policy doesn't allow creation", topic.Spec.Topic)
 		} else {
-			// Create a new topic with the given name.
-			t, err = client.CreateTopic(ctx, topic.Spec.Topic)
+			if kgcpreconciler.DryRun(topic) {
+				return kgcpreconciler.PlannedChange(ctx, "Would create Pub/Sub topic %q", topic.Spec.Topic)
+			}
+			// Create a new topic with the given name, labeled with the
+			// fencing token of the controller.
+			t, err = client.CreateTopicWithConfig(ctx, topic.Spec.Topic, &pubsub.TopicConfig{
+				Labels: kgcpreconciler.WithFencingLabel(nil),
+			})
 			if err != nil {
 				// For some reason (maybe some cache invalidation thing), sometimes t.Exists returns that the topic
 				// doesn't exist but it actually does. When we try to create it again, it fails with an AlreadyExists
@@ -172,6 +192,8 @@ func (r *Reconciler) reconcileTopic(ctx context.Context, topic *v1beta1.Topic) e
 				}
 				return nil
 			}
+			r.Recorder.Eventf(topic, corev1.EventTypeNormal, topicCreatedReason, "Created Pub/Sub topic %q: %s",
+				utils.TopicName(topic.Status.ProjectID, topic.Spec.Topic), utils.TopicConsoleURL(topic.Status.ProjectID, topic.Spec.Topic))
 		}
 	}
 	return nil
@@ -195,19 +217,37 @@ func (r *Reconciler) deleteTopic(ctx context.Context, topic *v1beta1.Topic) erro
 	defer client.Close()
 
 	t := client.Topic(topic.Status.TopicID)
-	exists, err := t.Exists(ctx)
-	if err != nil {
-		logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
-		return err
-	}
-	if exists {
-		// Delete the topic.
-		if err := t.Delete(ctx); err != nil {
-			logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub topic", zap.Error(err))
+	return kgcpreconciler.FinalizeExternal(ctx, r.Recorder, topic, "Pub/Sub topic", topic.Status.TopicID, func(ctx context.Context) error {
+		exists, err := t.Exists(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Desugar().Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
 			return err
 		}
-	}
-	return nil
+		if exists {
+			if kgcpreconciler.FencingToken() != "" {
+				config, err := t.Config(ctx)
+				if err != nil {
+					logging.FromContext(ctx).Desugar().Error("Failed to get Pub/Sub topic config", zap.Error(err))
+					return err
+				}
+				if kgcpreconciler.Fenced(config.Labels) {
+					logging.FromContext(ctx).Desugar().Warn("Not deleting Pub/Sub topic created by another cluster",
+						zap.String("owner", config.Labels[kgcpreconciler.FencingTokenLabel]))
+					kgcpreconciler.RecordFenced(r.Recorder, topic, "Pub/Sub topic", topic.Status.TopicID)
+					return nil
+				}
+			}
+			if kgcpreconciler.DryRun(topic) {
+				return kgcpreconciler.PlannedChange(ctx, "Would delete Pub/Sub topic %q", topic.Status.TopicID)
+			}
+			// Delete the topic.
+			if err := t.Delete(ctx); err != nil {
+				logging.FromContext(ctx).Desugar().Error("Failed to delete Pub/Sub topic", zap.Error(err))
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *Reconciler) reconcilePublisher(ctx context.Context, topic *v1beta1.Topic) (error, *servingv1.Service) {
@@ -230,11 +270,19 @@ func (r *Reconciler) reconcilePublisher(ctx context.Context, topic *v1beta1.Topi
 		logging.FromContext(ctx).Desugar().Error("Error serializing tracing config", zap.Error(err))
 	}
 
+	var metricsCfg string
+	if r.metricsConfig != nil {
+		if metricsCfg, err = metrics.MetricsOptionsToJson(r.metricsConfig); err != nil {
+			logging.FromContext(ctx).Desugar().Error("Error serializing metrics config", zap.Error(err))
+		}
+	}
+
 	desired := resources.MakePublisher(&resources.PublisherArgs{
 		Image:         r.publisherImage,
 		Topic:         topic,
 		Labels:        resources.GetLabels(controllerAgentName, topic.Name),
 		TracingConfig: tracingCfg,
+		MetricsConfig: metricsCfg,
 	})
 
 	svc := existing
@@ -272,6 +320,22 @@ func (r *Reconciler) UpdateFromTracingConfigMap(cfg *corev1.ConfigMap) {
 	// TODO: requeue all Topics. See https://github.com/google/knative-gcp/issues/457.
 }
 
+func (r *Reconciler) UpdateFromMetricsConfigMap(cfg *corev1.ConfigMap) {
+	if cfg == nil {
+		r.Logger.Error("Metrics ConfigMap is nil")
+		return
+	}
+	delete(cfg.Data, "_example")
+
+	r.metricsConfig = &metrics.ExporterOptions{
+		Domain:    metrics.Domain(),
+		Component: publisherComponent,
+		ConfigMap: cfg.Data,
+	}
+	r.Logger.Debugw("Updated metrics config", zap.Any("metricsCfg", r.metricsConfig))
+	// TODO: requeue all Topics. See https://github.com/google/knative-gcp/issues/457.
+}
+
 func (r *Reconciler) FinalizeKind(ctx context.Context, topic *v1beta1.Topic) reconciler.Event {
 	// If topic doesn't have ownerReference, and
 	// k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
@@ -284,7 +348,9 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, topic *v1beta1.Topic) rec
 	}
 	if topic.Spec.PropagationPolicy == v1beta1.TopicPolicyCreateDelete {
 		logging.FromContext(ctx).Desugar().Debug("Deleting Pub/Sub topic")
-		if err := r.deleteTopic(ctx, topic); err != nil {
+		if err := r.deleteTopic(ctx, topic); kgcpreconciler.IsPlannedChange(err) {
+			return err
+		} else if err != nil {
 			return reconciler.NewEvent(corev1.EventTypeWarning, deleteTopicFailed, "Failed to delete Pub/Sub topic: %s", err.Error())
 		}
 	}
@@ -39,6 +39,7 @@ import (
 	. "knative.dev/pkg/reconciler/testing"
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	pubsubv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	"github.com/google/knative-gcp/pkg/client/injection/reconciler/intevents/v1beta1/topic"
 	gpubsub "github.com/google/knative-gcp/pkg/gclient/pubsub/testing"
@@ -52,12 +53,13 @@ const (
 	topicName = "hubbub"
 	sinkName  = "sink"
 
-	testNS       = "testnamespace"
-	testImage    = "test_image"
-	topicUID     = topicName + "-abc-123"
-	testProject  = "test-project-id"
-	testTopicID  = "cloud-run-topic-" + testNS + "-" + topicName + "-" + topicUID
-	testTopicURI = "http://" + topicName + "-topic." + testNS + ".svc.cluster.local"
+	testNS        = "testnamespace"
+	testImage     = "test_image"
+	topicUID      = topicName + "-abc-123"
+	testProject   = "test-project-id"
+	testTopicID   = "cloud-run-topic-" + testNS + "-" + topicName + "-" + topicUID
+	testTopicName = "projects/" + testProject + "/topics/" + testTopicID
+	testTopicURI  = "http://" + topicName + "-topic." + testNS + ".svc.cluster.local"
 
 	secretName = "testing-secret"
 
@@ -69,6 +71,9 @@ var (
 	trueVal  = true
 	falseVal = false
 
+	topicCreatedEvent = Eventf(corev1.EventTypeNormal, topicCreatedReason, "Created Pub/Sub topic %q: https://console.cloud.google.com/cloudpubsub/topic/detail/%s?project=%s",
+		testTopicName, testTopicID, testProject)
+
 	sinkDNS = sinkName + ".mynamespace.svc.cluster.local"
 	sinkURI = "http://" + sinkDNS + "/"
 
@@ -295,6 +300,45 @@ func TestAllCases(t *testing.T) {
 				WithInitTopicConditions,
 				WithTopicNoTopic("TopicReconcileFailed", fmt.Sprintf("%s: %s", failedToReconcileTopicMsg, "create-topic-induced-error"))),
 		}},
+	}, {
+		Name: "topic not created in dry run",
+		Objects: []runtime.Object{
+			NewTopic(topicName, testNS,
+				WithTopicUID(topicUID),
+				WithTopicSpec(pubsubv1beta1.TopicSpec{
+					Project: testProject,
+					Topic:   testTopicID,
+					Secret:  &secret,
+				}),
+				WithTopicPropagationPolicy("CreateNoDelete"),
+				WithTopicAnnotations(map[string]string{duckv1beta1.DryRunAnnotation: "true"}),
+			),
+			newSink(),
+			newSecret(),
+		},
+		Key: testNS + "/" + topicName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+			Eventf(corev1.EventTypeNormal, "DryRun", "Would create Pub/Sub topic %q", testTopicID),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, topicName, resourceGroup),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewTopic(topicName, testNS,
+				WithTopicUID(topicUID),
+				WithTopicProjectID(testProject),
+				WithTopicSpec(pubsubv1beta1.TopicSpec{
+					Project: testProject,
+					Topic:   testTopicID,
+					Secret:  &secret,
+				}),
+				WithTopicPropagationPolicy("CreateNoDelete"),
+				WithTopicAnnotations(map[string]string{duckv1beta1.DryRunAnnotation: "true"}),
+				// Updates
+				WithInitTopicConditions,
+				WithTopicNoTopic("DryRun", fmt.Sprintf("Would create Pub/Sub topic %q", testTopicID))),
+		}},
 	}, {
 		Name: "topic created with EnablePublisher = false",
 		Objects: []runtime.Object{
@@ -317,12 +361,14 @@ func TestAllCases(t *testing.T) {
 		},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+			topicCreatedEvent,
 			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 		},
 		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
 			Object: NewTopic(topicName, testNS,
 				WithTopicUID(topicUID),
 				WithTopicProjectID(testProject),
+				WithTopicTopicName(testTopicName),
 				WithTopicSpec(pubsubv1beta1.TopicSpec{
 					Project:         testProject,
 					Topic:           testTopicID,
@@ -355,6 +401,7 @@ func TestAllCases(t *testing.T) {
 		},
 		WantEvents: []string{
 			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+			topicCreatedEvent,
 			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 		},
 		WantCreates: []runtime.Object{
@@ -364,6 +411,7 @@ func TestAllCases(t *testing.T) {
 			Object: NewTopic(topicName, testNS,
 				WithTopicUID(topicUID),
 				WithTopicProjectID(testProject),
+				WithTopicTopicName(testTopicName),
 				WithTopicSpec(pubsubv1beta1.TopicSpec{
 					Project: testProject,
 					Topic:   testTopicID,
@@ -397,6 +445,7 @@ func TestAllCases(t *testing.T) {
 			},
 			WantEvents: []string{
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+				topicCreatedEvent,
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
@@ -409,6 +458,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewTopic(topicName, testNS,
 					WithTopicUID(topicUID),
 					WithTopicProjectID(testProject),
+					WithTopicTopicName(testTopicName),
 					WithTopicSpec(pubsubv1beta1.TopicSpec{
 						Project: testProject,
 						Topic:   testTopicID,
@@ -441,6 +491,7 @@ func TestAllCases(t *testing.T) {
 			},
 			WantEvents: []string{
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+				topicCreatedEvent,
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
@@ -453,6 +504,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewTopic(topicName, testNS,
 					WithTopicUID(topicUID),
 					WithTopicProjectID(testProject),
+					WithTopicTopicName(testTopicName),
 					WithTopicSpec(pubsubv1beta1.TopicSpec{
 						Project: testProject,
 						Topic:   testTopicID,
@@ -485,6 +537,7 @@ func TestAllCases(t *testing.T) {
 			},
 			WantEvents: []string{
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+				topicCreatedEvent,
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{
@@ -497,6 +550,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewTopic(topicName, testNS,
 					WithTopicUID(topicUID),
 					WithTopicProjectID(testProject),
+					WithTopicTopicName(testTopicName),
 					WithTopicSpec(pubsubv1beta1.TopicSpec{
 						Project: testProject,
 						Topic:   testTopicID,
@@ -535,6 +589,7 @@ func TestAllCases(t *testing.T) {
 			},
 			WantEvents: []string{
 				Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", topicName),
+				topicCreatedEvent,
 				Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Topic reconciled: "%s/%s"`, testNS, topicName),
 			},
 			WithReactors: []clientgotesting.ReactionFunc{},
@@ -542,6 +597,7 @@ func TestAllCases(t *testing.T) {
 				Object: NewTopic(topicName, testNS,
 					WithTopicUID(topicUID),
 					WithTopicProjectID(testProject),
+					WithTopicTopicName(testTopicName),
 					WithTopicSpec(pubsubv1beta1.TopicSpec{
 						Project: testProject,
 						Topic:   testTopicID,
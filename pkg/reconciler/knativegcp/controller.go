@@ -0,0 +1,98 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativegcp
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+	deploymentinformer "knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
+	knativegcpinformer "github.com/google/knative-gcp/pkg/client/injection/informers/operator/v1alpha1/knativegcp"
+	knativegcpreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/operator/v1alpha1/knativegcp"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+const (
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "knativegcp-controller"
+)
+
+// NewController initializes the controller and is called by the generated code.
+// Registers event handlers to enqueue events.
+func NewController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+) *controller.Impl {
+	knativeGCPInformer := knativegcpinformer.Get(ctx)
+	deploymentInformer := deploymentinformer.Get(ctx)
+	configMapInformer := configmapinformer.Get(ctx)
+
+	r := &Reconciler{
+		Base:             reconciler.NewBase(ctx, controllerAgentName, cmw),
+		deploymentLister: deploymentInformer.Lister(),
+		configMapLister:  configMapInformer.Lister(),
+	}
+	impl := knativegcpreconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+
+	knativeGCPInformer.Informer().AddEventHandlerWithResyncPeriod(controller.HandleAll(impl.Enqueue), reconciler.DefaultResyncPeriod)
+
+	// Follow the rollout of the deployments, and restore them if they are
+	// changed.
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: inSystemNamespace(controllerName, webhookName),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(knativeGCPInformer.Informer())
+		}),
+	})
+
+	// Restore the configured keys if they are changed.
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: inSystemNamespace(v1alpha1.ConfigMapNames.List()...),
+		Handler: controller.HandleAll(func(interface{}) {
+			impl.GlobalResync(knativeGCPInformer.Informer())
+		}),
+	})
+
+	return impl
+}
+
+// inSystemNamespace returns a filter accepting the objects of the system
+// namespace with one of the names.
+func inSystemNamespace(names ...string) func(obj interface{}) bool {
+	return func(obj interface{}) bool {
+		accessor, err := kmeta.DeletionHandlingAccessor(obj)
+		if err != nil || accessor.GetNamespace() != system.Namespace() {
+			return false
+		}
+		for _, name := range names {
+			if accessor.GetName() == name {
+				return true
+			}
+		}
+		return false
+	}
+}
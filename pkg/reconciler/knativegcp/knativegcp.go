@@ -0,0 +1,422 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativegcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"knative.dev/eventing/pkg/apis/duck"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
+	knativegcpreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/operator/v1alpha1/knativegcp"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+const (
+	reconciledSuccessReason     = "KnativeGCPReconciled"
+	unsupportedNameReason       = "UnsupportedName"
+	configMapFailedReason       = "ConfigMapUpdateFailed"
+	deploymentFailedReason      = "DeploymentUpdateFailed"
+	rollingOutReason            = "RollingOut"
+	deploymentUnavailableReason = "DeploymentUnavailable"
+
+	// controllerName and webhookName are the names of the deployments and of
+	// their containers.
+	controllerName = "controller"
+	webhookName    = "webhook"
+)
+
+// dataPlaneImageEnvVars are the environment variables of the controller
+// holding the image of the data plane.
+var dataPlaneImageEnvVars = []string{
+	"BROKER_CELL_DATA_PLANE_IMAGE",
+	"PUBSUB_RA_IMAGE",
+	"WEBHOOK_RECEIVER_IMAGE",
+}
+
+// publisherImageEnvVar is the environment variable of the controller holding
+// the image of the Topic publishers.
+const publisherImageEnvVar = "PUBSUB_PUBLISHER_IMAGE"
+
+// Reconciler implements controller.Reconciler for KnativeGCP resources.
+type Reconciler struct {
+	*reconciler.Base
+
+	deploymentLister appsv1listers.DeploymentLister
+	configMapLister  corev1listers.ConfigMapLister
+}
+
+// Check that our Reconciler implements Interface and Finalizer.
+var _ knativegcpreconciler.Interface = (*Reconciler)(nil)
+var _ knativegcpreconciler.Finalizer = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, k *v1alpha1.KnativeGCP) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("knativegcp", k)))
+
+	k.Status.InitializeConditions()
+	k.Status.ObservedGeneration = k.Generation
+
+	// Applying several KnativeGCPs would make them fight over the
+	// deployments.
+	if k.Name != v1alpha1.KnativeGCPName {
+		k.Status.MarkConfigFailed(unsupportedNameReason, "Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName)
+		k.Status.MarkDeploymentsFailed(unsupportedNameReason, "Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName)
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, unsupportedNameReason, "Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName)
+	}
+
+	if err := r.reconcileConfig(ctx, k.Spec.Config); err != nil {
+		k.Status.MarkConfigFailed(configMapFailedReason, "Failed to update the ConfigMaps: %s", err.Error())
+		return err
+	}
+	k.Status.MarkConfigReady()
+
+	// The webhook is updated first since updating the controller restarts it.
+	webhook, webhookUpdated, err := r.reconcileDeployment(ctx, webhookName, webhookEnv(k), k.Spec.Images.Webhook)
+	if err != nil {
+		k.Status.MarkDeploymentsFailed(deploymentFailedReason, "Failed to update the webhook: %s", err.Error())
+		return err
+	}
+	controller, controllerUpdated, err := r.reconcileDeployment(ctx, controllerName, controllerEnv(k), k.Spec.Images.Controller)
+	if err != nil {
+		k.Status.MarkDeploymentsFailed(deploymentFailedReason, "Failed to update the controller: %s", err.Error())
+		return err
+	}
+	k.Status.MarkDeploymentsReady()
+	for _, d := range []struct {
+		*appsv1.Deployment
+		updated bool
+	}{{webhook, webhookUpdated}, {controller, controllerUpdated}} {
+		if d.updated || !rolledOut(d.Deployment) {
+			k.Status.MarkDeploymentsUnknown(rollingOutReason, "Deployment %q is rolling out", d.Name)
+			break
+		}
+		if !duck.DeploymentIsAvailable(&d.Status, false) {
+			k.Status.MarkDeploymentsUnknown(deploymentUnavailableReason, "Deployment %q is unavailable", d.Name)
+			break
+		}
+	}
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `KnativeGCP reconciled: "%s"`, k.Name)
+}
+
+// FinalizeKind restores what the KnativeGCP set in the ConfigMaps and the
+// deployments when it is deleted.
+func (r *Reconciler) FinalizeKind(ctx context.Context, k *v1alpha1.KnativeGCP) pkgreconciler.Event {
+	if k.Name != v1alpha1.KnativeGCPName {
+		return nil
+	}
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("knativegcp", k)))
+	if err := r.reconcileConfig(ctx, nil); err != nil {
+		return err
+	}
+	if _, _, err := r.reconcileDeployment(ctx, webhookName, nil, ""); err != nil {
+		return err
+	}
+	_, _, err := r.reconcileDeployment(ctx, controllerName, nil, "")
+	return err
+}
+
+// reconcileConfig sets the configured keys of the ConfigMaps of the system
+// namespace, and restores the keys set before that are no longer configured.
+// The other keys are left as they are.
+func (r *Reconciler) reconcileConfig(ctx context.Context, config map[string]map[string]string) error {
+	for _, name := range v1alpha1.ConfigMapNames.List() {
+		if err := r.reconcileConfigMap(ctx, name, config[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) reconcileConfigMap(ctx context.Context, name string, data map[string]string) error {
+	existing, err := r.configMapLister.ConfigMaps(system.Namespace()).Get(name)
+	create := apierrs.IsNotFound(err)
+	if create {
+		if len(data) == 0 {
+			return nil
+		}
+		existing = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: system.Namespace(),
+			},
+		}
+	} else if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %q: %w", name, err)
+	}
+	desired := existing.DeepCopy()
+	// The original values of the keys set by the KnativeGCP, nil for the
+	// keys that were absent.
+	original := make(map[string]*string)
+	if err := getApplied(desired, &original); err != nil {
+		return fmt.Errorf("failed to read the keys applied to ConfigMap %q: %w", name, err)
+	}
+	if desired.Data == nil {
+		desired.Data = make(map[string]string, len(data))
+	}
+	for key, value := range data {
+		if _, ok := original[key]; !ok {
+			original[key] = nil
+			if v, ok := desired.Data[key]; ok {
+				original[key] = &v
+			}
+		}
+		desired.Data[key] = value
+	}
+	for key, v := range original {
+		if _, ok := data[key]; ok {
+			continue
+		}
+		if v == nil {
+			delete(desired.Data, key)
+		} else {
+			desired.Data[key] = *v
+		}
+		delete(original, key)
+	}
+	if err := setApplied(desired, original, len(original) == 0); err != nil {
+		return err
+	}
+
+	if create {
+		logging.FromContext(ctx).Debugw("Creating ConfigMap", zap.String("name", name))
+		if _, err := r.KubeClientSet.CoreV1().ConfigMaps(desired.Namespace).Create(desired); err != nil {
+			return fmt.Errorf("failed to create ConfigMap %q: %w", name, err)
+		}
+		return nil
+	}
+	if equality.Semantic.DeepEqual(existing.Data, desired.Data) && equality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) {
+		return nil
+	}
+	logging.FromContext(ctx).Debugw("Updating ConfigMap", zap.String("name", name))
+	if _, err := r.KubeClientSet.CoreV1().ConfigMaps(desired.Namespace).Update(desired); err != nil {
+		return fmt.Errorf("failed to update ConfigMap %q: %w", name, err)
+	}
+	return nil
+}
+
+// appliedContainer records the original settings of a container changed by
+// the KnativeGCP.
+type appliedContainer struct {
+	// Image is the original image of the container, empty if the KnativeGCP
+	// doesn't set it.
+	Image string `json:"image,omitempty"`
+	// Env are the original environment variables set by the KnativeGCP, nil
+	// for those that were absent.
+	Env map[string]*corev1.EnvVar `json:"env,omitempty"`
+}
+
+// reconcileDeployment sets the image and the environment variables of the
+// container of the deployment named after it, and restores those set before
+// that are no longer configured. It returns the deployment, and whether it
+// was updated.
+func (r *Reconciler) reconcileDeployment(ctx context.Context, name string, env map[string]string, image string) (*appsv1.Deployment, bool, error) {
+	existing, err := r.deploymentLister.Deployments(system.Namespace()).Get(name)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get deployment %q: %w", name, err)
+	}
+	desired := existing.DeepCopy()
+	var container *corev1.Container
+	for i := range desired.Spec.Template.Spec.Containers {
+		if desired.Spec.Template.Spec.Containers[i].Name == name {
+			container = &desired.Spec.Template.Spec.Containers[i]
+		}
+	}
+	if container == nil {
+		return nil, false, fmt.Errorf("deployment %q has no container %q", name, name)
+	}
+	applied := appliedContainer{Env: make(map[string]*corev1.EnvVar)}
+	if err := getApplied(desired, &applied); err != nil {
+		return nil, false, fmt.Errorf("failed to read the settings applied to deployment %q: %w", name, err)
+	}
+	if image != "" {
+		if applied.Image == "" {
+			applied.Image = container.Image
+		}
+		container.Image = image
+	} else if applied.Image != "" {
+		container.Image = applied.Image
+		applied.Image = ""
+	}
+	setEnv(container, env, applied.Env)
+	if err := setApplied(desired, applied, applied.Image == "" && len(applied.Env) == 0); err != nil {
+		return nil, false, err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, desired.Spec) && equality.Semantic.DeepEqual(existing.Annotations, desired.Annotations) {
+		return existing, false, nil
+	}
+	logging.FromContext(ctx).Debugw("Updating deployment", zap.String("name", name))
+	updated, err := r.KubeClientSet.AppsV1().Deployments(desired.Namespace).Update(desired)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update deployment %q: %w", name, err)
+	}
+	return updated, true, nil
+}
+
+// setEnv sets the environment variables of the container, recording their
+// original value in original, and restores the original value of those in
+// original that are no longer set.
+func setEnv(container *corev1.Container, env map[string]string, original map[string]*corev1.EnvVar) {
+	for _, name := range sortedKeys(env) {
+		i := envIndex(container, name)
+		if _, ok := original[name]; !ok {
+			original[name] = nil
+			if i >= 0 {
+				e := container.Env[i]
+				original[name] = &e
+			}
+		}
+		e := corev1.EnvVar{Name: name, Value: env[name]}
+		if i >= 0 {
+			container.Env[i] = e
+		} else {
+			container.Env = append(container.Env, e)
+		}
+	}
+	names := make([]string, 0, len(original))
+	for name := range original {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := env[name]; ok {
+			continue
+		}
+		e, i := original[name], envIndex(container, name)
+		switch {
+		case e == nil && i >= 0:
+			container.Env = append(container.Env[:i], container.Env[i+1:]...)
+		case e != nil && i >= 0:
+			container.Env[i] = *e
+		case e != nil:
+			container.Env = append(container.Env, *e)
+		}
+		delete(original, name)
+	}
+}
+
+// envIndex returns the index of the environment variable of the container,
+// -1 if it has none.
+func envIndex(container *corev1.Container, name string) int {
+	for i := range container.Env {
+		if container.Env[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// getApplied reads what the KnativeGCP applied to the object into applied.
+func getApplied(obj metav1.Object, applied interface{}) error {
+	v, ok := obj.GetAnnotations()[v1alpha1.AppliedAnnotation]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal([]byte(v), applied)
+}
+
+// setApplied records what the KnativeGCP applied to the object, or removes
+// the record if empty.
+func setApplied(obj metav1.Object, applied interface{}, empty bool) error {
+	annotations := obj.GetAnnotations()
+	if empty {
+		delete(annotations, v1alpha1.AppliedAnnotation)
+		if len(annotations) == 0 {
+			annotations = nil
+		}
+		obj.SetAnnotations(annotations)
+		return nil
+	}
+	b, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[v1alpha1.AppliedAnnotation] = string(b)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// controllerEnv returns the environment variables of the controller set by
+// the KnativeGCP.
+func controllerEnv(k *v1alpha1.KnativeGCP) map[string]string {
+	env := features(k, v1alpha1.ControllerFeatures)
+	if image := k.Spec.Images.DataPlane; image != "" {
+		for _, name := range dataPlaneImageEnvVars {
+			env[name] = image
+		}
+	}
+	if image := k.Spec.Images.Publisher; image != "" {
+		env[publisherImageEnvVar] = image
+	}
+	return env
+}
+
+// webhookEnv returns the environment variables of the webhook set by the
+// KnativeGCP.
+func webhookEnv(k *v1alpha1.KnativeGCP) map[string]string {
+	return features(k, v1alpha1.WebhookFeatures)
+}
+
+// features returns the features of the KnativeGCP in the supported set.
+func features(k *v1alpha1.KnativeGCP, supported sets.String) map[string]string {
+	env := make(map[string]string)
+	for name, value := range k.Spec.Features {
+		if supported.Has(name) {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// rolledOut returns whether all the replicas of the deployment run its
+// current spec.
+func rolledOut(d *appsv1.Deployment) bool {
+	if d.Status.ObservedGeneration < d.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Status.UpdatedReplicas >= replicas
+}
@@ -0,0 +1,422 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package knativegcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+	. "knative.dev/pkg/reconciler/testing"
+	"knative.dev/pkg/system"
+
+	"github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/operator/v1alpha1/knativegcp"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	controllerImage = "gcr.io/knative-gcp/controller:v1"
+	webhookImage    = "gcr.io/knative-gcp/webhook:v1"
+	dataPlaneImage  = "gcr.io/knative-gcp/dataplane:v1"
+
+	finalizerName = "knativegcps.operator.events.cloud.google.com"
+)
+
+var (
+	reconciledEvent = Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `KnativeGCP reconciled: "%s"`, v1alpha1.KnativeGCPName)
+
+	// controllerApplied records the original settings of the controller
+	// changed by the images and features of the KnativeGCP.
+	controllerApplied = `{"image":"ko://controller","env":{` +
+		`"BROKER_CELL_DATA_PLANE_IMAGE":null,` +
+		`"DRY_RUN":{"name":"DRY_RUN","value":"false"},` +
+		`"PUBSUB_RA_IMAGE":{"name":"PUBSUB_RA_IMAGE","value":"ko://dataplane"},` +
+		`"WEBHOOK_RECEIVER_IMAGE":null}}`
+
+	controllerEnvVars = []corev1.EnvVar{
+		{Name: "PUBSUB_RA_IMAGE", Value: "ko://dataplane"},
+		{Name: "DRY_RUN", Value: "false"},
+		{Name: "SYSTEM_NAMESPACE", ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+		}},
+	}
+)
+
+// deployment returns a deployment whose only replica runs its spec.
+func deployment(name, image string, env []corev1.EnvVar, o ...DeploymentOption) *appsv1.Deployment {
+	return NewDeployment(name, system.Namespace(), append([]DeploymentOption{
+		WithDeploymentContainer(name, image, env, nil),
+		WithDeploymentAvailable(),
+		func(d *appsv1.Deployment) {
+			d.Status.UpdatedReplicas = 1
+		},
+	}, o...)...)
+}
+
+// applied records the original settings of a deployment or ConfigMap changed
+// by the KnativeGCP.
+func applied(record string) func(metav1.Object) {
+	return func(obj metav1.Object) {
+		obj.SetAnnotations(map[string]string{v1alpha1.AppliedAnnotation: record})
+	}
+}
+
+func deploymentApplied(record string) DeploymentOption {
+	return func(d *appsv1.Deployment) {
+		applied(record)(d)
+	}
+}
+
+func configMapApplied(record string) ConfigMapOption {
+	return func(cm *corev1.ConfigMap) {
+		applied(record)(cm)
+	}
+}
+
+func patchFinalizers(add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = v1alpha1.KnativeGCPName
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", finalizerName)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func controllerDeployment(o ...DeploymentOption) *appsv1.Deployment {
+	return deployment(controllerName, "ko://controller", controllerEnvVars, o...)
+}
+
+func webhookDeployment(o ...DeploymentOption) *appsv1.Deployment {
+	return deployment(webhookName, "ko://webhook", nil, o...)
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "not-found",
+	}, {
+		Name: "nothing to change",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName, WithKnativeGCPFinalizers(finalizerName)),
+			controllerDeployment(),
+			webhookDeployment(),
+		},
+		Key: v1alpha1.KnativeGCPName,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsReady,
+			),
+		}},
+	}, {
+		Name: "images and features updated",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPImages(v1alpha1.ImagesSpec{
+					Controller: controllerImage,
+					Webhook:    webhookImage,
+					DataPlane:  dataPlaneImage,
+				}),
+				WithKnativeGCPFeatures(map[string]string{
+					"DRY_RUN":                      "true",
+					"PULLSUBSCRIPTION_TOPIC_CHECK": "true",
+				}),
+			),
+			controllerDeployment(),
+			webhookDeployment(),
+		},
+		Key:                     v1alpha1.KnativeGCPName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: deployment(webhookName, webhookImage, []corev1.EnvVar{
+				{Name: "PULLSUBSCRIPTION_TOPIC_CHECK", Value: "true"},
+			}, deploymentApplied(`{"image":"ko://webhook","env":{"PULLSUBSCRIPTION_TOPIC_CHECK":null}}`)),
+		}, {
+			Object: deployment(controllerName, controllerImage, []corev1.EnvVar{
+				{Name: "PUBSUB_RA_IMAGE", Value: dataPlaneImage},
+				{Name: "DRY_RUN", Value: "true"},
+				controllerEnvVars[2],
+				{Name: "BROKER_CELL_DATA_PLANE_IMAGE", Value: dataPlaneImage},
+				{Name: "WEBHOOK_RECEIVER_IMAGE", Value: dataPlaneImage},
+			}, deploymentApplied(controllerApplied)),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPImages(v1alpha1.ImagesSpec{
+					Controller: controllerImage,
+					Webhook:    webhookImage,
+					DataPlane:  dataPlaneImage,
+				}),
+				WithKnativeGCPFeatures(map[string]string{
+					"DRY_RUN":                      "true",
+					"PULLSUBSCRIPTION_TOPIC_CHECK": "true",
+				}),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsUnknown(rollingOutReason, `Deployment "webhook" is rolling out`),
+			),
+		}},
+	}, {
+		Name: "ConfigMaps updated and created",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPConfig("config-logging", map[string]string{"loglevel.controller": "debug"}),
+				WithKnativeGCPConfig("config-tracing", map[string]string{"backend": "zipkin"}),
+			),
+			NewConfigMap("config-logging", system.Namespace(), WithConfigMapData(map[string]string{
+				"loglevel.controller": "info",
+				"loglevel.webhook":    "info",
+			})),
+			controllerDeployment(),
+			webhookDeployment(),
+		},
+		Key:                     v1alpha1.KnativeGCPName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewConfigMap("config-logging", system.Namespace(), WithConfigMapData(map[string]string{
+				"loglevel.controller": "debug",
+				"loglevel.webhook":    "info",
+			}), configMapApplied(`{"loglevel.controller":"info"}`)),
+		}},
+		WantCreates: []runtime.Object{
+			NewConfigMap("config-tracing", system.Namespace(), WithConfigMapData(map[string]string{
+				"backend": "zipkin",
+			}), configMapApplied(`{"backend":null}`)),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPConfig("config-logging", map[string]string{"loglevel.controller": "debug"}),
+				WithKnativeGCPConfig("config-tracing", map[string]string{"backend": "zipkin"}),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsReady,
+			),
+		}},
+	}, {
+		Name: "finalizer added",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName),
+			controllerDeployment(),
+			webhookDeployment(),
+		},
+		Key: v1alpha1.KnativeGCPName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "%s" finalizers`, v1alpha1.KnativeGCPName),
+			reconciledEvent,
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(true),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsReady,
+			),
+		}},
+	}, {
+		Name: "images, features and config no longer set are restored",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPFeatures(map[string]string{
+					"DRY_RUN": "true",
+				}),
+			),
+			NewConfigMap("config-logging", system.Namespace(), WithConfigMapData(map[string]string{
+				"loglevel.controller": "debug",
+				"loglevel.webhook":    "info",
+			}), configMapApplied(`{"loglevel.controller":"info"}`)),
+			deployment(controllerName, controllerImage, []corev1.EnvVar{
+				{Name: "PUBSUB_RA_IMAGE", Value: dataPlaneImage},
+				{Name: "DRY_RUN", Value: "true"},
+				controllerEnvVars[2],
+				{Name: "BROKER_CELL_DATA_PLANE_IMAGE", Value: dataPlaneImage},
+				{Name: "WEBHOOK_RECEIVER_IMAGE", Value: dataPlaneImage},
+			}, deploymentApplied(controllerApplied)),
+			webhookDeployment(),
+		},
+		Key:                     v1alpha1.KnativeGCPName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewConfigMap("config-logging", system.Namespace(), WithConfigMapData(map[string]string{
+				"loglevel.controller": "info",
+				"loglevel.webhook":    "info",
+			})),
+		}, {
+			Object: deployment(controllerName, "ko://controller", []corev1.EnvVar{
+				controllerEnvVars[0],
+				{Name: "DRY_RUN", Value: "true"},
+				controllerEnvVars[2],
+			}, deploymentApplied(`{"env":{"DRY_RUN":{"name":"DRY_RUN","value":"false"}}}`)),
+		}},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPFeatures(map[string]string{
+					"DRY_RUN": "true",
+				}),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsUnknown(rollingOutReason, `Deployment "controller" is rolling out`),
+			),
+		}},
+	}, {
+		Name: "deleted, everything applied is restored",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithKnativeGCPDeletionTimestamp,
+			),
+			NewConfigMap("config-tracing", system.Namespace(), WithConfigMapData(map[string]string{
+				"backend": "zipkin",
+			}), configMapApplied(`{"backend":null}`)),
+			deployment(controllerName, controllerImage, []corev1.EnvVar{
+				{Name: "PUBSUB_RA_IMAGE", Value: dataPlaneImage},
+				{Name: "DRY_RUN", Value: "true"},
+				controllerEnvVars[2],
+				{Name: "BROKER_CELL_DATA_PLANE_IMAGE", Value: dataPlaneImage},
+				{Name: "WEBHOOK_RECEIVER_IMAGE", Value: dataPlaneImage},
+			}, deploymentApplied(controllerApplied)),
+			deployment(webhookName, webhookImage, []corev1.EnvVar{
+				{Name: "PULLSUBSCRIPTION_TOPIC_CHECK", Value: "true"},
+			}, deploymentApplied(`{"image":"ko://webhook","env":{"PULLSUBSCRIPTION_TOPIC_CHECK":null}}`)),
+		},
+		Key:                     v1alpha1.KnativeGCPName,
+		SkipNamespaceValidation: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "%s" finalizers`, v1alpha1.KnativeGCPName),
+		},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewConfigMap("config-tracing", system.Namespace(), WithConfigMapData(map[string]string{})),
+		}, {
+			Object: webhookDeployment(func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{}
+			}),
+		}, {
+			Object: controllerDeployment(),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(false),
+		},
+	}, {
+		Name: "deployment unavailable",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName, WithKnativeGCPFinalizers(finalizerName)),
+			controllerDeployment(func(d *appsv1.Deployment) {
+				d.Status.Conditions = nil
+			}),
+			webhookDeployment(),
+		},
+		Key: v1alpha1.KnativeGCPName,
+		WantEvents: []string{
+			reconciledEvent,
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsUnknown(deploymentUnavailableReason, `Deployment "controller" is unavailable`),
+			),
+		}},
+	}, {
+		Name: "deployment missing",
+		Objects: []runtime.Object{
+			NewKnativeGCP(v1alpha1.KnativeGCPName, WithKnativeGCPFinalizers(finalizerName)),
+			webhookDeployment(),
+		},
+		Key:     v1alpha1.KnativeGCPName,
+		WantErr: true,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, "InternalError", `failed to get deployment "controller": deployment.apps "controller" not found`),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP(v1alpha1.KnativeGCPName,
+				WithKnativeGCPFinalizers(finalizerName),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigReady,
+				WithKnativeGCPDeploymentsFailed(deploymentFailedReason, `Failed to update the controller: failed to get deployment "controller": deployment.apps "controller" not found`),
+			),
+		}},
+	}, {
+		Name: "unsupported name",
+		Objects: []runtime.Object{
+			NewKnativeGCP("other", WithKnativeGCPFinalizers(finalizerName)),
+			controllerDeployment(),
+			webhookDeployment(),
+		},
+		Key: "other",
+		WantEvents: []string{
+			Eventf(corev1.EventTypeWarning, unsupportedNameReason, "Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: NewKnativeGCP("other",
+				WithKnativeGCPFinalizers(finalizerName),
+				WithInitKnativeGCPConditions,
+				WithKnativeGCPConfigFailed(unsupportedNameReason, fmt.Sprintf("Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName)),
+				WithKnativeGCPDeploymentsFailed(unsupportedNameReason, fmt.Sprintf("Only the KnativeGCP named %q is applied", v1alpha1.KnativeGCPName)),
+			),
+		}},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			Base:             reconciler.NewBase(ctx, controllerAgentName, cmw),
+			deploymentLister: listers.GetDeploymentLister(),
+			configMapLister:  listers.GetConfigMapLister(),
+		}
+		return knativegcp.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetKnativeGCPLister(), r.Recorder, r)
+	}))
+}
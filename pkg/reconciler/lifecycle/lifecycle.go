@@ -0,0 +1,212 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package lifecycle publishes CloudEvents about the readiness of the resources
+// managed by the reconcilers to a system Broker, so that operators can automate
+// around the eventing infrastructure itself.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// SinkEnvKey is the environment variable with the URI of the Broker that
+	// lifecycle events are sent to. Lifecycle events are disabled if it is unset.
+	SinkEnvKey = "LIFECYCLE_EVENTS_SINK"
+
+	// ReadyEventType is the type of the event sent when a resource becomes ready.
+	ReadyEventType = "com.google.cloud.events.lifecycle.ready"
+	// FailedEventType is the type of the event sent when a resource fails to
+	// become ready.
+	FailedEventType = "com.google.cloud.events.lifecycle.failed"
+
+	// EventSource is the source of the lifecycle events.
+	EventSource = "//events.cloud.google.com/controller"
+
+	sendTimeout = 5 * time.Second
+	// queueSize is the number of lifecycle events waiting to be sent above
+	// which new events are dropped, so that a slow sink can't hold up the
+	// reconcilers.
+	queueSize = 100
+)
+
+// Notification is the data of a lifecycle event.
+type Notification struct {
+	// Kind is the kind of the resource, e.g. CloudStorageSource.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the resource.
+	Namespace string `json:"namespace"`
+	// Name is the name of the resource.
+	Name string `json:"name"`
+	// Condition is the new Ready condition of the resource.
+	Condition apis.Condition `json:"condition"`
+}
+
+// Object is a resource whose readiness is notified.
+type Object interface {
+	kmeta.Accessor
+	kmeta.OwnerRefable
+}
+
+// ConditionGetter is the status of a resource whose readiness is notified.
+type ConditionGetter interface {
+	GetCondition(t apis.ConditionType) *apis.Condition
+}
+
+// Notifier sends lifecycle events.
+type Notifier interface {
+	// NotifyReadyChange queues a lifecycle event if the Ready condition of obj
+	// became True or False since it was before. It doesn't wait for the event
+	// to be sent; failures to send are logged and do not affect the
+	// reconciliation.
+	NotifyReadyChange(ctx context.Context, obj kmeta.Accessor, kind string, before, after *apis.Condition)
+}
+
+// NewNotifierFromEnv returns a Notifier sending lifecycle events to the sink
+// set in the environment until ctx is done, or a Notifier that does nothing
+// if it is unset.
+func NewNotifierFromEnv(ctx context.Context) (Notifier, error) {
+	sink := os.Getenv(SinkEnvKey)
+	if sink == "" {
+		return noopNotifier{}, nil
+	}
+	client, err := cev2.NewDefaultClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lifecycle events client: %w", err)
+	}
+	return NewNotifier(ctx, client, sink), nil
+}
+
+// NewNotifier returns a Notifier sending lifecycle events to sink with client
+// until ctx is done.
+func NewNotifier(ctx context.Context, client cev2.Client, sink string) Notifier {
+	return newCENotifier(ctx, client, sink, queueSize)
+}
+
+func newCENotifier(ctx context.Context, client cev2.Client, sink string, size int) *ceNotifier {
+	n := &ceNotifier{
+		client: client,
+		sink:   sink,
+		queue:  make(chan cev2.Event, size),
+	}
+	go n.run(ctx)
+	return n
+}
+
+type ceNotifier struct {
+	client cev2.Client
+	sink   string
+	// queue holds the events waiting to be sent.
+	queue chan cev2.Event
+}
+
+// run sends the queued events one at a time until ctx is done.
+func (n *ceNotifier) run(ctx context.Context) {
+	logger := logging.FromContext(ctx).Desugar().With(zap.String("sink", n.sink))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.queue:
+			sendCtx, cancel := context.WithTimeout(cecontext.WithTarget(ctx, n.sink), sendTimeout)
+			if res := n.client.Send(sendCtx, event); !cev2.IsACK(res) {
+				logger.Warn("Failed to send lifecycle event", zap.String("type", event.Type()), zap.String("subject", event.Subject()), zap.Error(res))
+			}
+			cancel()
+		}
+	}
+}
+
+func (n *ceNotifier) NotifyReadyChange(ctx context.Context, obj kmeta.Accessor, kind string, before, after *apis.Condition) {
+	eventType, ok := eventTypeFor(before, after)
+	if !ok {
+		return
+	}
+	logger := logging.FromContext(ctx).Desugar().With(zap.String("type", eventType))
+
+	event := cev2.NewEvent()
+	event.SetID(fmt.Sprintf("%s-%s-%d", obj.GetUID(), after.Status, after.LastTransitionTime.Inner.UnixNano()))
+	event.SetType(eventType)
+	event.SetSource(EventSource)
+	event.SetSubject(fmt.Sprintf("%s/%s/%s", kind, obj.GetNamespace(), obj.GetName()))
+	event.SetTime(time.Now())
+	if err := event.SetData(cev2.ApplicationJSON, Notification{
+		Kind:      kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		Condition: *after,
+	}); err != nil {
+		logger.Error("Failed to set lifecycle event data", zap.Error(err))
+		return
+	}
+
+	select {
+	case n.queue <- event:
+	default:
+		logger.Warn("Dropped lifecycle event, too many events are waiting to be sent", zap.String("subject", event.Subject()))
+	}
+}
+
+// eventTypeFor returns the type of the lifecycle event to send when the Ready
+// condition changes from before to after, if any.
+func eventTypeFor(before, after *apis.Condition) (string, bool) {
+	if after == nil || (before != nil && before.Status == after.Status) {
+		return "", false
+	}
+	switch after.Status {
+	case corev1.ConditionTrue:
+		return ReadyEventType, true
+	case corev1.ConditionFalse:
+		return FailedEventType, true
+	default:
+		return "", false
+	}
+}
+
+type noopNotifier struct{}
+
+func (noopNotifier) NotifyReadyChange(context.Context, kmeta.Accessor, string, *apis.Condition, *apis.Condition) {
+}
+
+type notifierKey struct{}
+
+// WithNotifier attaches the given Notifier to the provided context in the
+// returned context.
+func WithNotifier(ctx context.Context, n Notifier) context.Context {
+	return context.WithValue(ctx, notifierKey{}, n)
+}
+
+// GetNotifier attempts to look up the Notifier on a given context.
+// It may return nil if none is found.
+func GetNotifier(ctx context.Context) Notifier {
+	untyped := ctx.Value(notifierKey{})
+	if untyped == nil {
+		return nil
+	}
+	return untyped.(Notifier)
+}
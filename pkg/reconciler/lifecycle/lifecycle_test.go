@@ -0,0 +1,225 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	cev2 "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+)
+
+func condition(status corev1.ConditionStatus) *apis.Condition {
+	return &apis.Condition{
+		Type:               apis.ConditionReady,
+		Status:             status,
+		LastTransitionTime: apis.VolatileTime{Inner: metav1.NewTime(time.Unix(1600000000, 0))},
+	}
+}
+
+func TestNotifyReadyChange(t *testing.T) {
+	obj := &duckv1.KResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "source",
+			UID:       "uid",
+		},
+	}
+	tests := []struct {
+		name     string
+		before   *apis.Condition
+		after    *apis.Condition
+		wantType string
+	}{{
+		name:     "became ready",
+		before:   condition(corev1.ConditionUnknown),
+		after:    condition(corev1.ConditionTrue),
+		wantType: ReadyEventType,
+	}, {
+		name:     "ready on first reconcile",
+		after:    condition(corev1.ConditionTrue),
+		wantType: ReadyEventType,
+	}, {
+		name:     "failed",
+		before:   condition(corev1.ConditionTrue),
+		after:    condition(corev1.ConditionFalse),
+		wantType: FailedEventType,
+	}, {
+		name:   "still ready",
+		before: condition(corev1.ConditionTrue),
+		after:  condition(corev1.ConditionTrue),
+	}, {
+		name:   "unknown",
+		before: condition(corev1.ConditionFalse),
+		after:  condition(corev1.ConditionUnknown),
+	}, {
+		name:   "no condition",
+		before: condition(corev1.ConditionTrue),
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Don't start the sender, so that the queued events can be checked.
+			n := &ceNotifier{queue: make(chan cev2.Event, 1)}
+
+			n.NotifyReadyChange(context.Background(), obj, "CloudStorageSource", tc.before, tc.after)
+
+			if tc.wantType == "" {
+				select {
+				case e := <-n.queue:
+					t.Errorf("Unexpected lifecycle event: %v", e)
+				default:
+				}
+				return
+			}
+			var e cev2.Event
+			select {
+			case e = <-n.queue:
+			default:
+				t.Fatal("Lifecycle event was not queued")
+			}
+			if e.Type() != tc.wantType {
+				t.Errorf("Event type = %q, want %q", e.Type(), tc.wantType)
+			}
+			if e.Source() != EventSource {
+				t.Errorf("Event source = %q, want %q", e.Source(), EventSource)
+			}
+			if got, want := e.Subject(), "CloudStorageSource/ns/source"; got != want {
+				t.Errorf("Event subject = %q, want %q", got, want)
+			}
+			var got Notification
+			if err := json.Unmarshal(e.Data(), &got); err != nil {
+				t.Fatalf("Failed to unmarshal event data: %v", err)
+			}
+			want := Notification{
+				Kind:      "CloudStorageSource",
+				Namespace: "ns",
+				Name:      "source",
+				Condition: *tc.after,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Unexpected notification (-want, +got): %v", diff)
+			}
+		})
+	}
+}
+
+func TestNotifierSendsQueuedEvents(t *testing.T) {
+	obj := &duckv1.KResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "source",
+			UID:       "uid",
+		},
+	}
+	events := make(chan *cev2.Event, 1)
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		e, err := binding.ToEvent(r.Context(), cehttp.NewMessageFromHttpRequest(r))
+		if err != nil {
+			t.Errorf("Failed to convert request to event: %v", err)
+		}
+		events <- e
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer srv.Close()
+	client, err := cev2.NewDefaultClient()
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	NewNotifier(ctx, client, srv.URL).NotifyReadyChange(ctx, obj, "CloudStorageSource", nil, condition(corev1.ConditionTrue))
+
+	select {
+	case e := <-events:
+		if e.Type() != ReadyEventType {
+			t.Errorf("Event type = %q, want %q", e.Type(), ReadyEventType)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the lifecycle event")
+	}
+}
+
+func TestNotifyReadyChangeDropsEventsWhenQueueIsFull(t *testing.T) {
+	obj := &duckv1.KResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "source",
+			UID:       "uid",
+		},
+	}
+	n := &ceNotifier{queue: make(chan cev2.Event, 1)}
+
+	// Neither call blocks, even though nothing is sending the queued events.
+	n.NotifyReadyChange(context.Background(), obj, "CloudStorageSource", nil, condition(corev1.ConditionTrue))
+	n.NotifyReadyChange(context.Background(), obj, "CloudStorageSource", nil, condition(corev1.ConditionFalse))
+
+	if got := len(n.queue); got != 1 {
+		t.Fatalf("Queued events = %d, want 1", got)
+	}
+	if e := <-n.queue; e.Type() != ReadyEventType {
+		t.Errorf("Event type = %q, want %q", e.Type(), ReadyEventType)
+	}
+}
+
+func TestNewNotifierFromEnv(t *testing.T) {
+	defer os.Setenv(SinkEnvKey, os.Getenv(SinkEnvKey))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	os.Unsetenv(SinkEnvKey)
+	n, err := NewNotifierFromEnv(ctx)
+	if err != nil {
+		t.Fatalf("NewNotifierFromEnv() = %v", err)
+	}
+	if _, ok := n.(noopNotifier); !ok {
+		t.Errorf("NewNotifierFromEnv() = %T, want noopNotifier", n)
+	}
+
+	os.Setenv(SinkEnvKey, "http://broker-ingress.cloud-run-events.svc.cluster.local/ns/system")
+	n, err = NewNotifierFromEnv(ctx)
+	if err != nil {
+		t.Fatalf("NewNotifierFromEnv() = %v", err)
+	}
+	if _, ok := n.(*ceNotifier); !ok {
+		t.Errorf("NewNotifierFromEnv() = %T, want *ceNotifier", n)
+	}
+}
+
+func TestGetNotifier(t *testing.T) {
+	ctx := context.Background()
+	if n := GetNotifier(ctx); n != nil {
+		t.Errorf("GetNotifier() = %v, want nil", n)
+	}
+	want := noopNotifier{}
+	if n := GetNotifier(WithNotifier(ctx, want)); n != want {
+		t.Errorf("GetNotifier() = %v, want %v", n, want)
+	}
+}
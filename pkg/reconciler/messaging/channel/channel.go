@@ -71,6 +71,7 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, channel *v1beta1.Channel
 
 	channel.Status.InitializeConditions()
 	channel.Status.ObservedGeneration = channel.Generation
+	reconciler.MarkDeprecated(ctx, channel, &channel.Status, v1beta1.SchemeGroupVersion)
 
 	// If ServiceAccountName is provided, reconcile workload identity.
 	if channel.Spec.ServiceAccountName != "" {
@@ -150,6 +151,7 @@ func (r *Reconciler) syncSubscribers(ctx context.Context, channel *v1beta1.Chann
 	}
 
 	clusterName := channel.GetAnnotations()[duckv1beta1.ClusterNameAnnotation]
+	metricsBackend := channel.GetAnnotations()[duckv1beta1.MetricsBackendDestinationAnnotation]
 	for _, s := range subCreates {
 		genName := resources.GeneratePullSubscriptionName(s.UID)
 
@@ -161,7 +163,7 @@ func (r *Reconciler) syncSubscribers(ctx context.Context, channel *v1beta1.Chann
 			ServiceAccountName: channel.Spec.ServiceAccountName,
 			Secret:             channel.Spec.Secret,
 			Labels:             resources.GetPullSubscriptionLabels(controllerAgentName, channel.Name, genName, string(channel.UID)),
-			Annotations:        resources.GetPullSubscriptionAnnotations(channel.Name, clusterName),
+			Annotations:        resources.GetPullSubscriptionAnnotations(channel.Name, clusterName, metricsBackend),
 			Subscriber:         s,
 		})
 		ps, err := r.RunClientSet.InternalV1beta1().PullSubscriptions(channel.Namespace).Create(ps)
@@ -196,7 +198,7 @@ func (r *Reconciler) syncSubscribers(ctx context.Context, channel *v1beta1.Chann
 			ServiceAccountName: channel.Spec.ServiceAccountName,
 			Secret:             channel.Spec.Secret,
 			Labels:             resources.GetPullSubscriptionLabels(controllerAgentName, channel.Name, genName, string(channel.UID)),
-			Annotations:        resources.GetPullSubscriptionAnnotations(channel.Name, clusterName),
+			Annotations:        resources.GetPullSubscriptionAnnotations(channel.Name, clusterName, metricsBackend),
 			Subscriber:         s,
 		})
 
@@ -564,7 +564,7 @@ func newPullSubscriptionWithOwner(subscriber eventingduckv1beta1.SubscriberSpec,
 		Topic:       channel.Status.TopicID,
 		Secret:      channel.Spec.Secret,
 		Labels:      resources.GetPullSubscriptionLabels(controllerAgentName, channel.Name, resources.GeneratePullSubscriptionName(subscriber.UID), string(channel.UID)),
-		Annotations: resources.GetPullSubscriptionAnnotations(channel.Name, channel.GetAnnotations()[duckv1beta1.ClusterNameAnnotation]),
+		Annotations: resources.GetPullSubscriptionAnnotations(channel.Name, channel.GetAnnotations()[duckv1beta1.ClusterNameAnnotation], channel.GetAnnotations()[duckv1beta1.MetricsBackendDestinationAnnotation]),
 		Subscriber:  subscriber,
 	})
 }
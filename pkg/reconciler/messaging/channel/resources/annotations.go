@@ -18,9 +18,13 @@ package resources
 
 import (
 	duckv1alpha1 "github.com/google/knative-gcp/pkg/apis/duck/v1alpha1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 )
 
-func GetPullSubscriptionAnnotations(channel, clusterName string) map[string]string {
+// GetPullSubscriptionAnnotations returns the annotations of the
+// PullSubscriptions of a channel. The metrics backend of their receive adapters
+// is overridden with metricsBackend, if set, like the channel's.
+func GetPullSubscriptionAnnotations(channel, clusterName, metricsBackend string) map[string]string {
 	annotation := map[string]string{
 		"metrics-resource-group": "channels.messaging.cloud.google.com",
 		"metrics-resource-name":  channel,
@@ -28,6 +32,9 @@ func GetPullSubscriptionAnnotations(channel, clusterName string) map[string]stri
 	if clusterName != "" {
 		annotation[duckv1alpha1.ClusterNameAnnotation] = clusterName
 	}
+	if metricsBackend != "" {
+		annotation[duckv1beta1.MetricsBackendDestinationAnnotation] = metricsBackend
+	}
 	return annotation
 }
 
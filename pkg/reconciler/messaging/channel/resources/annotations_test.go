@@ -32,7 +32,7 @@ func TestGetPullSubscriptionAnnotations(t *testing.T) {
 				"metrics-resource-name":  "my-channel",
 				"metrics-resource-group": "channels.messaging.cloud.google.com",
 			},
-			got: GetPullSubscriptionAnnotations("my-channel", ""),
+			got: GetPullSubscriptionAnnotations("my-channel", "", ""),
 		},
 		"has cluster name": {
 			want: map[string]string{
@@ -40,7 +40,15 @@ func TestGetPullSubscriptionAnnotations(t *testing.T) {
 				"metrics-resource-group": "channels.messaging.cloud.google.com",
 				"cluster-name":           "fake-cluster-name",
 			},
-			got: GetPullSubscriptionAnnotations("my-channel", "fake-cluster-name"),
+			got: GetPullSubscriptionAnnotations("my-channel", "fake-cluster-name", ""),
+		},
+		"has metrics backend": {
+			want: map[string]string{
+				"metrics-resource-name":                               "my-channel",
+				"metrics-resource-group":                              "channels.messaging.cloud.google.com",
+				"metrics.events.cloud.google.com/backend-destination": "none",
+			},
+			got: GetPullSubscriptionAnnotations("my-channel", "", "none"),
 		},
 	}
 	for n, tc := range testCases {
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flows
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/logging"
+
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+// Flow is a Sequence or Parallel owning Topics and PullSubscriptions.
+type Flow interface {
+	kmeta.OwnerRefable
+	runtime.Object
+}
+
+// Reconciler reconciles the Topics and PullSubscriptions of flows.
+type Reconciler struct {
+	*reconciler.Base
+
+	TopicLister            inteventslisters.TopicLister
+	PullSubscriptionLister inteventslisters.PullSubscriptionLister
+}
+
+// ReconcileTopics creates the desired Topics of the flow that do not exist
+// yet and deletes the Topics controlled by the flow, among those matching
+// selector, that are not desired anymore. It returns the Topics in the order
+// of desired.
+func (r *Reconciler) ReconcileTopics(ctx context.Context, flow Flow, selector labels.Selector, desired []*inteventsv1beta1.Topic) ([]*inteventsv1beta1.Topic, error) {
+	namespace := flow.GetObjectMeta().GetNamespace()
+	existing, err := r.TopicLister.Topics(namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Topics: %w", err)
+	}
+	owned := make(map[string]*inteventsv1beta1.Topic)
+	for _, t := range existing {
+		if metav1.IsControlledBy(t, flow.GetObjectMeta()) {
+			owned[t.Name] = t
+		}
+	}
+
+	topics := make([]*inteventsv1beta1.Topic, 0, len(desired))
+	for _, want := range desired {
+		got, ok := owned[want.Name]
+		delete(owned, want.Name)
+		// The spec of the Topics only depends on the flow fields that cannot
+		// change, so existing Topics are left as they are.
+		if !ok {
+			got, err = r.RunClientSet.InternalV1beta1().Topics(namespace).Create(want)
+			if err != nil {
+				r.Recorder.Eventf(flow, corev1.EventTypeWarning, "TopicCreateFailed", "Failed to create Topic %q: %s", want.Name, err.Error())
+				return nil, err
+			}
+			r.Recorder.Eventf(flow, corev1.EventTypeNormal, "TopicCreated", "Created Topic %q", got.Name)
+		}
+		topics = append(topics, got)
+	}
+
+	for name := range owned {
+		if err := r.deleteTopic(ctx, flow, name); err != nil {
+			return nil, err
+		}
+	}
+	return topics, nil
+}
+
+func (r *Reconciler) deleteTopic(ctx context.Context, flow Flow, name string) error {
+	namespace := flow.GetObjectMeta().GetNamespace()
+	err := r.RunClientSet.InternalV1beta1().Topics(namespace).Delete(name, &metav1.DeleteOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete Topic", zap.String("topic", name), zap.Error(err))
+		r.Recorder.Eventf(flow, corev1.EventTypeWarning, "TopicDeleteFailed", "Failed to delete Topic %q: %s", name, err.Error())
+		return err
+	}
+	r.Recorder.Eventf(flow, corev1.EventTypeNormal, "TopicDeleted", "Deleted Topic %q", name)
+	return nil
+}
+
+// ReconcilePullSubscriptions creates or updates the desired PullSubscriptions
+// of the flow and deletes the PullSubscriptions controlled by the flow, among
+// those matching selector, that are not desired anymore. It returns the
+// PullSubscriptions in the order of desired.
+func (r *Reconciler) ReconcilePullSubscriptions(ctx context.Context, flow Flow, selector labels.Selector, desired []*inteventsv1beta1.PullSubscription) ([]*inteventsv1beta1.PullSubscription, error) {
+	namespace := flow.GetObjectMeta().GetNamespace()
+	existing, err := r.PullSubscriptionLister.PullSubscriptions(namespace).List(selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PullSubscriptions: %w", err)
+	}
+	owned := make(map[string]*inteventsv1beta1.PullSubscription)
+	for _, ps := range existing {
+		if metav1.IsControlledBy(ps, flow.GetObjectMeta()) {
+			owned[ps.Name] = ps
+		}
+	}
+
+	subscriptions := make([]*inteventsv1beta1.PullSubscription, 0, len(desired))
+	for _, want := range desired {
+		got, ok := owned[want.Name]
+		if ok && got.Spec.Topic != want.Spec.Topic {
+			// The topic of a PullSubscription cannot change. Delete it, it is
+			// created again once the deletion is observed.
+			if err := r.deletePullSubscription(ctx, flow, got.Name); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("PullSubscription %q is being replaced as its topic changed", got.Name)
+		}
+		delete(owned, want.Name)
+		switch {
+		case !ok:
+			got, err = r.RunClientSet.InternalV1beta1().PullSubscriptions(namespace).Create(want)
+			if err != nil {
+				r.Recorder.Eventf(flow, corev1.EventTypeWarning, "PullSubscriptionCreateFailed", "Failed to create PullSubscription %q: %s", want.Name, err.Error())
+				return nil, err
+			}
+			r.Recorder.Eventf(flow, corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", got.Name)
+		case !equality.Semantic.DeepEqual(want.Spec.Sink, got.Spec.Sink) || !equality.Semantic.DeepEqual(want.Spec.Transformer, got.Spec.Transformer):
+			// Don't modify the informers copy.
+			update := got.DeepCopy()
+			update.Spec.Sink = want.Spec.Sink
+			update.Spec.Transformer = want.Spec.Transformer
+			got, err = r.RunClientSet.InternalV1beta1().PullSubscriptions(namespace).Update(update)
+			if err != nil {
+				r.Recorder.Eventf(flow, corev1.EventTypeWarning, "PullSubscriptionUpdateFailed", "Failed to update PullSubscription %q: %s", want.Name, err.Error())
+				return nil, err
+			}
+			r.Recorder.Eventf(flow, corev1.EventTypeNormal, "PullSubscriptionUpdated", "Updated PullSubscription %q", got.Name)
+		}
+		subscriptions = append(subscriptions, got)
+	}
+
+	for name := range owned {
+		if err := r.deletePullSubscription(ctx, flow, name); err != nil {
+			return nil, err
+		}
+	}
+	return subscriptions, nil
+}
+
+func (r *Reconciler) deletePullSubscription(ctx context.Context, flow Flow, name string) error {
+	namespace := flow.GetObjectMeta().GetNamespace()
+	err := r.RunClientSet.InternalV1beta1().PullSubscriptions(namespace).Delete(name, &metav1.DeleteOptions{})
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Desugar().Error("Failed to delete PullSubscription", zap.String("ps", name), zap.Error(err))
+		r.Recorder.Eventf(flow, corev1.EventTypeWarning, "PullSubscriptionDeleteFailed", "Failed to delete PullSubscription %q: %s", name, err.Error())
+		return err
+	}
+	r.Recorder.Eventf(flow, corev1.EventTypeNormal, "PullSubscriptionDeleted", "Deleted PullSubscription %q", name)
+	return nil
+}
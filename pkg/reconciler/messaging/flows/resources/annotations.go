@@ -0,0 +1,40 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+func GetPullSubscriptionAnnotations(resourceGroup, flow, clusterName string) map[string]string {
+	annotation := map[string]string{
+		"metrics-resource-group": resourceGroup,
+		"metrics-resource-name":  flow,
+	}
+	if clusterName != "" {
+		annotation[duckv1beta1.ClusterNameAnnotation] = clusterName
+	}
+	return annotation
+}
+
+func GetTopicAnnotations(clusterName string) map[string]string {
+	annotation := map[string]string{}
+	if clusterName != "" {
+		annotation[duckv1beta1.ClusterNameAnnotation] = clusterName
+	}
+	return annotation
+}
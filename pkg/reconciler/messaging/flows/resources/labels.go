@@ -0,0 +1,35 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents"
+)
+
+func GetLabelSelector(controller, flow, uid string) labels.Selector {
+	return labels.SelectorFromSet(GetLabels(controller, flow, uid))
+}
+
+func GetLabels(controller, flow, uid string) map[string]string {
+	return map[string]string{
+		"events.cloud.google.com/flow":           controller,
+		intevents.FlowLabelKey:                   flow,
+		"events.cloud.google.com/controller-uid": uid,
+	}
+}
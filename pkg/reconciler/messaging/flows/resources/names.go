@@ -0,0 +1,42 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+
+	"github.com/google/knative-gcp/pkg/utils/naming"
+)
+
+// GenerateName generates the name of a Topic or PullSubscription of the flow,
+// with suffix identifying it among the others.
+func GenerateName(flow metav1.Object, suffix string) string {
+	if strings.HasPrefix(flow.GetName(), "cre-") {
+		return kmeta.ChildName(flow.GetName(), suffix)
+	}
+	return kmeta.ChildName(fmt.Sprintf("cre-%s", flow.GetName()), suffix)
+}
+
+// GenerateTopicID generates the name of the Pub/Sub topic, not our Topic
+// resource, with suffix identifying it among the others of the flow.
+func GenerateTopicID(flow metav1.Object, suffix string) string {
+	return naming.TruncatedPubsubResourceName("cre-flow", flow.GetNamespace(), flow.GetName()+suffix, flow.GetUID())
+}
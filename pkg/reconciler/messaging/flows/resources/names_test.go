@@ -0,0 +1,68 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+)
+
+func TestGenerateTopicID(t *testing.T) {
+	want := "cre-flow_default_foo-step-0_a-uid"
+	got := GenerateTopicID(&v1beta1.Sequence{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "foo",
+			Namespace: "default",
+			UID:       "a-uid",
+		},
+	}, "-step-0")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateName(t *testing.T) {
+	want := "cre-foo-step-0"
+	got := GenerateName(&v1beta1.Sequence{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "foo",
+		},
+	}, "-step-0")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestGenerateNameFromFlow(t *testing.T) {
+	want := "cre-foo-branch-1"
+	got := GenerateName(&v1beta1.Parallel{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "cre-foo",
+		},
+	}, "-branch-1")
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
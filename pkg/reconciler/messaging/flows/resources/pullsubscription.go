@@ -0,0 +1,82 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	gcpduckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// PullSubscriptionArgs are the arguments needed to create a PullSubscription
+// of a Sequence or Parallel. Reply is optional, every other field is
+// required.
+type PullSubscriptionArgs struct {
+	Owner              kmeta.OwnerRefable
+	Name               string
+	Project            string
+	Topic              string
+	ServiceAccountName string
+	Secret             *corev1.SecretKeySelector
+	Labels             map[string]string
+	Annotations        map[string]string
+	Subscriber         duckv1.Destination
+	Reply              *duckv1.Destination
+}
+
+// MakePullSubscription generates (but does not insert into K8s) a
+// PullSubscription of a Sequence or Parallel, delivering the events of Topic
+// to Subscriber and the replies of Subscriber to Reply.
+func MakePullSubscription(args *PullSubscriptionArgs) *v1beta1.PullSubscription {
+	spec := v1beta1.PullSubscriptionSpec{
+		PubSubSpec: gcpduckv1beta1.PubSubSpec{
+			IdentitySpec: gcpduckv1beta1.IdentitySpec{
+				ServiceAccountName: args.ServiceAccountName,
+			},
+			Secret:  args.Secret,
+			Project: args.Project,
+		},
+		Topic: args.Topic,
+	}
+
+	// If there is a reply, map:
+	//   pull.transformer to subscriber
+	//   pull.sink to reply
+	// Otherwise, pull.sink has to be used, so set it to the subscriber.
+	if args.Reply != nil {
+		subscriber := args.Subscriber
+		spec.Transformer = &subscriber
+		spec.Sink = *args.Reply
+	} else {
+		spec.Sink = args.Subscriber
+	}
+
+	return &v1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       args.Owner.GetObjectMeta().GetNamespace(),
+			Name:            args.Name,
+			Labels:          args.Labels,
+			Annotations:     args.Annotations,
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(args.Owner)},
+		},
+		Spec: spec,
+	}
+}
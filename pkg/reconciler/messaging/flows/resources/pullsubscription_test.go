@@ -0,0 +1,154 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gcpduckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+)
+
+var (
+	sequence = &v1beta1.Sequence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sequence-name",
+			Namespace: "sequence-namespace",
+			UID:       "sequence-uid",
+		},
+	}
+
+	secret = &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{
+			Name: "eventing-secret-name",
+		},
+		Key: "eventing-secret-key",
+	}
+)
+
+func TestMakePullSubscription(t *testing.T) {
+	got := MakePullSubscription(&PullSubscriptionArgs{
+		Owner:              sequence,
+		Name:               "cre-sequence-name-step-0",
+		Project:            "project-123",
+		Topic:              "topic-abc",
+		ServiceAccountName: "ksa",
+		Secret:             secret,
+		Labels: map[string]string{
+			"test-key1": "test-value1",
+		},
+		Subscriber: duckv1.Destination{
+			URI: &apis.URL{Scheme: "http", Host: "subscriber", Path: "/"},
+		},
+		Reply: &duckv1.Destination{
+			URI: &apis.URL{Scheme: "http", Host: "reply", Path: "/"},
+		},
+	})
+
+	yes := true
+	want := &inteventsv1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "sequence-namespace",
+			Name:      "cre-sequence-name-step-0",
+			Labels: map[string]string{
+				"test-key1": "test-value1",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "messaging.cloud.google.com/v1beta1",
+				Kind:               "Sequence",
+				Name:               "sequence-name",
+				UID:                "sequence-uid",
+				Controller:         &yes,
+				BlockOwnerDeletion: &yes,
+			}},
+		},
+		Spec: inteventsv1beta1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1beta1.PubSubSpec{
+				IdentitySpec: gcpduckv1beta1.IdentitySpec{
+					ServiceAccountName: "ksa",
+				},
+				Secret:  secret,
+				Project: "project-123",
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						URI: &apis.URL{Scheme: "http", Host: "reply", Path: "/"},
+					},
+				},
+			},
+			Topic: "topic-abc",
+			Transformer: &duckv1.Destination{
+				URI: &apis.URL{Scheme: "http", Host: "subscriber", Path: "/"},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestMakePullSubscription_JustSubscriber(t *testing.T) {
+	got := MakePullSubscription(&PullSubscriptionArgs{
+		Owner:   sequence,
+		Name:    "cre-sequence-name-step-0",
+		Project: "project-123",
+		Topic:   "topic-abc",
+		Secret:  secret,
+		Subscriber: duckv1.Destination{
+			URI: &apis.URL{Scheme: "http", Host: "subscriber", Path: "/"},
+		},
+	})
+
+	yes := true
+	want := &inteventsv1beta1.PullSubscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "sequence-namespace",
+			Name:      "cre-sequence-name-step-0",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "messaging.cloud.google.com/v1beta1",
+				Kind:               "Sequence",
+				Name:               "sequence-name",
+				UID:                "sequence-uid",
+				Controller:         &yes,
+				BlockOwnerDeletion: &yes,
+			}},
+		},
+		Spec: inteventsv1beta1.PullSubscriptionSpec{
+			PubSubSpec: gcpduckv1beta1.PubSubSpec{
+				Secret:  secret,
+				Project: "project-123",
+				SourceSpec: duckv1.SourceSpec{
+					Sink: duckv1.Destination{
+						URI: &apis.URL{Scheme: "http", Host: "subscriber", Path: "/"},
+					},
+				},
+			},
+			Topic: "topic-abc",
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
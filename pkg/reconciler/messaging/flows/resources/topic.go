@@ -0,0 +1,76 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/kmeta"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+// TopicArgs are the arguments needed to create a Topic of a Sequence or
+// Parallel. Every field is required.
+type TopicArgs struct {
+	Owner              kmeta.OwnerRefable
+	Name               string
+	Project            string
+	Topic              string
+	ServiceAccountName string
+	Secret             *corev1.SecretKeySelector
+	Labels             map[string]string
+	Annotations        map[string]string
+}
+
+// MakeTopic generates (but does not insert into K8s) a Topic of a Sequence or
+// Parallel.
+func MakeTopic(args *TopicArgs) *v1beta1.Topic {
+	return &v1beta1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       args.Owner.GetObjectMeta().GetNamespace(),
+			Name:            args.Name,
+			Labels:          args.Labels,
+			Annotations:     args.Annotations,
+			OwnerReferences: []metav1.OwnerReference{*kmeta.NewControllerRef(args.Owner)},
+		},
+		Spec: v1beta1.TopicSpec{
+			IdentitySpec: duckv1beta1.IdentitySpec{
+				ServiceAccountName: args.ServiceAccountName,
+			},
+			Secret:            args.Secret,
+			Project:           args.Project,
+			Topic:             args.Topic,
+			PropagationPolicy: v1beta1.TopicPolicyCreateDelete,
+		},
+	}
+}
+
+// TopicDestination returns a Destination that resolves to the address of the
+// Topic, to publish events to it.
+func TopicDestination(topic *v1beta1.Topic) *duckv1.Destination {
+	return &duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: v1beta1.SchemeGroupVersion.String(),
+			Kind:       "Topic",
+			Namespace:  topic.Namespace,
+			Name:       topic.Name,
+		},
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+)
+
+func TestMakeTopic(t *testing.T) {
+	got := MakeTopic(&TopicArgs{
+		Owner:   sequence,
+		Name:    "cre-sequence-name-step-0",
+		Project: "project-123",
+		Topic:   "topic-abc",
+		Secret:  secret,
+		Labels: map[string]string{
+			"test-key1": "test-value1",
+		},
+	})
+
+	yes := true
+	want := &inteventsv1beta1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "sequence-namespace",
+			Name:      "cre-sequence-name-step-0",
+			Labels: map[string]string{
+				"test-key1": "test-value1",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         "messaging.cloud.google.com/v1beta1",
+				Kind:               "Sequence",
+				Name:               "sequence-name",
+				UID:                "sequence-uid",
+				Controller:         &yes,
+				BlockOwnerDeletion: &yes,
+			}},
+		},
+		Spec: inteventsv1beta1.TopicSpec{
+			Secret:            secret,
+			Project:           "project-123",
+			Topic:             "topic-abc",
+			PropagationPolicy: inteventsv1beta1.TopicPolicyCreateDelete,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
+
+func TestTopicDestination(t *testing.T) {
+	got := TopicDestination(&inteventsv1beta1.Topic{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "sequence-namespace",
+			Name:      "cre-sequence-name-step-0",
+		},
+	})
+
+	want := &duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: "internal.events.cloud.google.com/v1beta1",
+			Kind:       "Topic",
+			Namespace:  "sequence-namespace",
+			Name:       "cre-sequence-name-step-0",
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("unexpected (-want, +got) = %v", diff)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parallel
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/cache"
+	serviceaccountinformers "knative.dev/pkg/client/injection/kube/informers/core/v1/serviceaccount"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+
+	"github.com/google/knative-gcp/pkg/apis/configs/gcpauth"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	pullsubscriptioninformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/pullsubscription"
+	topicinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1beta1/topic"
+	parallelinformer "github.com/google/knative-gcp/pkg/client/injection/informers/messaging/v1beta1/parallel"
+	parallelreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/parallel"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/identity/iam"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows"
+)
+
+const (
+	// reconcilerName is the name of the reconciler
+	reconcilerName = "Parallels"
+
+	// controllerAgentName is the string used by this controller to identify
+	// itself when creating events.
+	controllerAgentName = "cloud-run-events-parallel-controller"
+)
+
+type Constructor injection.ControllerConstructor
+
+// NewConstructor creates a constructor to make a Parallel controller.
+func NewConstructor(ipm iam.IAMPolicyManager, gcpas *gcpauth.StoreSingleton) Constructor {
+	return func(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+		return newController(ctx, cmw, ipm, gcpas.Store(ctx, cmw))
+	}
+}
+
+func newController(
+	ctx context.Context,
+	cmw configmap.Watcher,
+	ipm iam.IAMPolicyManager,
+	gcpas *gcpauth.Store,
+) *controller.Impl {
+	parallelInformer := parallelinformer.Get(ctx)
+
+	topicInformer := topicinformer.Get(ctx)
+	pullSubscriptionInformer := pullsubscriptioninformer.Get(ctx)
+	serviceAccountInformer := serviceaccountinformers.Get(ctx)
+
+	r := &Reconciler{
+		Reconciler: &flows.Reconciler{
+			Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
+			TopicLister:            topicInformer.Lister(),
+			PullSubscriptionLister: pullSubscriptionInformer.Lister(),
+		},
+		Identity: identity.NewIdentity(ctx, ipm, gcpas),
+	}
+	impl := parallelreconciler.NewImpl(ctx, r)
+
+	r.Logger.Info("Setting up event handlers")
+	parallelInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	topicInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1beta1.SchemeGroupVersion.WithKind("Parallel")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	pullSubscriptionInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1beta1.SchemeGroupVersion.WithKind("Parallel")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	serviceAccountInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterGroupVersionKind(v1beta1.SchemeGroupVersion.WithKind("Parallel")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	return impl
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parallel
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	parallelreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/parallel"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows/resources"
+)
+
+const (
+	resourceGroup = "parallels.messaging.cloud.google.com"
+
+	reconciledSuccessReason             = "ParallelReconciled"
+	reconciledTopicsFailedReason        = "TopicsReconcileFailed"
+	reconciledSubscriptionsFailedReason = "SubscriptionsReconcileFailed"
+	deleteWorkloadIdentityFailed        = "WorkloadIdentityDeleteFailed"
+	workloadIdentityFailed              = "WorkloadIdentityReconcileFailed"
+
+	// ingressSuffix is the suffix of the name of the Topic events sent to
+	// the Parallel are published to.
+	ingressSuffix = "-parallel"
+)
+
+// Reconciler implements controller.Reconciler for Parallel resources.
+type Reconciler struct {
+	*flows.Reconciler
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+}
+
+// Check that our Reconciler implements Interface.
+var _ parallelreconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, parallel *v1beta1.Parallel) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("parallel", parallel)))
+
+	parallel.Status.InitializeConditions()
+	parallel.Status.ObservedGeneration = parallel.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if parallel.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, parallel.Spec.Project, parallel); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile Parallel workload identity: %s", err.Error())
+		}
+	}
+
+	selector := resources.GetLabelSelector(controllerAgentName, parallel.Name, string(parallel.UID))
+	clusterName := parallel.GetAnnotations()[duckv1beta1.ClusterNameAnnotation]
+
+	// 1. Create the ingress Topic and the Topic of each filtered branch.
+	topics, err := r.ReconcileTopics(ctx, parallel, selector, makeTopics(parallel, clusterName))
+	if err != nil {
+		parallel.Status.MarkTopicsFailed(reconciledTopicsFailedReason, "Failed to reconcile Topics: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledTopicsFailedReason, "Reconcile Topics failed with: %s", err.Error())
+	}
+	parallel.Status.PropagateTopicStatuses(topics)
+	// Events sent to the Parallel go to its ingress Topic.
+	parallel.Status.SetAddress(topicAddress(topics[0]))
+
+	// 2. Subscribe the filters and subscribers of the branches.
+	subscriptions, err := r.ReconcilePullSubscriptions(ctx, parallel, selector, makePullSubscriptions(parallel, topics, clusterName))
+	if err != nil {
+		parallel.Status.MarkSubscriptionsFailed(reconciledSubscriptionsFailedReason, "Failed to reconcile PullSubscriptions: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledSubscriptionsFailedReason, "Reconcile PullSubscriptions failed with: %s", err.Error())
+	}
+	parallel.Status.PropagateSubscriptionStatuses(subscriptions)
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `Parallel reconciled: "%s/%s"`, parallel.Namespace, parallel.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, parallel *v1beta1.Parallel) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if parallel.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, parallel.Spec.Project, parallel); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete Parallel workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// branchSuffix returns the suffix of the names of the Topic and
+// PullSubscription of the subscriber of the i-th branch.
+func branchSuffix(i int) string {
+	return fmt.Sprintf("-branch-%d", i)
+}
+
+// filterSuffix returns the suffix of the name of the PullSubscription of the
+// filter of the i-th branch.
+func filterSuffix(i int) string {
+	return fmt.Sprintf("-branch-%d-filter", i)
+}
+
+// makeTopics returns the ingress Topic followed by the Topics the filtered
+// branches send the events that passed their filter to.
+func makeTopics(parallel *v1beta1.Parallel, clusterName string) []*inteventsv1beta1.Topic {
+	topics := []*inteventsv1beta1.Topic{makeTopic(parallel, ingressSuffix, clusterName)}
+	for i, branch := range parallel.Spec.Branches {
+		if branch.Filter != nil {
+			topics = append(topics, makeTopic(parallel, branchSuffix(i), clusterName))
+		}
+	}
+	return topics
+}
+
+func makeTopic(parallel *v1beta1.Parallel, suffix, clusterName string) *inteventsv1beta1.Topic {
+	return resources.MakeTopic(&resources.TopicArgs{
+		Owner:              parallel,
+		Name:               resources.GenerateName(parallel, suffix),
+		Project:            parallel.Spec.Project,
+		Topic:              resources.GenerateTopicID(parallel, suffix),
+		ServiceAccountName: parallel.Spec.ServiceAccountName,
+		Secret:             parallel.Spec.Secret,
+		Labels:             resources.GetLabels(controllerAgentName, parallel.Name, string(parallel.UID)),
+		Annotations:        resources.GetTopicAnnotations(clusterName),
+	})
+}
+
+// makePullSubscriptions returns the PullSubscriptions of the branches. The
+// filter of a filtered branch subscribes to the ingress Topic and replies to
+// the Topic of the branch, which its subscriber subscribes to. The subscriber
+// of an unfiltered branch subscribes to the ingress Topic directly.
+func makePullSubscriptions(parallel *v1beta1.Parallel, topics []*inteventsv1beta1.Topic, clusterName string) []*inteventsv1beta1.PullSubscription {
+	byName := make(map[string]*inteventsv1beta1.Topic, len(topics))
+	for _, t := range topics {
+		byName[t.Name] = t
+	}
+	ingress := byName[resources.GenerateName(parallel, ingressSuffix)]
+
+	subscriptions := make([]*inteventsv1beta1.PullSubscription, 0, len(parallel.Spec.Branches))
+	for i, branch := range parallel.Spec.Branches {
+		reply := branch.Reply
+		if reply == nil {
+			reply = parallel.Spec.Reply
+		}
+		if branch.Filter == nil {
+			subscriptions = append(subscriptions, makePullSubscription(parallel, branchSuffix(i), ingress, branch.Subscriber, reply, clusterName))
+			continue
+		}
+		topic := byName[resources.GenerateName(parallel, branchSuffix(i))]
+		subscriptions = append(subscriptions,
+			makePullSubscription(parallel, filterSuffix(i), ingress, *branch.Filter, resources.TopicDestination(topic), clusterName),
+			makePullSubscription(parallel, branchSuffix(i), topic, branch.Subscriber, reply, clusterName),
+		)
+	}
+	return subscriptions
+}
+
+func makePullSubscription(parallel *v1beta1.Parallel, suffix string, topic *inteventsv1beta1.Topic, subscriber duckv1.Destination, reply *duckv1.Destination, clusterName string) *inteventsv1beta1.PullSubscription {
+	return resources.MakePullSubscription(&resources.PullSubscriptionArgs{
+		Owner:              parallel,
+		Name:               resources.GenerateName(parallel, suffix),
+		Project:            parallel.Spec.Project,
+		Topic:              topic.Spec.Topic,
+		ServiceAccountName: parallel.Spec.ServiceAccountName,
+		Secret:             parallel.Spec.Secret,
+		Labels:             resources.GetLabels(controllerAgentName, parallel.Name, string(parallel.UID)),
+		Annotations:        resources.GetPullSubscriptionAnnotations(resourceGroup, parallel.Name, clusterName),
+		Subscriber:         subscriber,
+		Reply:              reply,
+	})
+}
+
+func topicAddress(topic *inteventsv1beta1.Topic) *apis.URL {
+	if topic.Status.Address == nil {
+		return nil
+	}
+	return topic.Status.Address.URL
+}
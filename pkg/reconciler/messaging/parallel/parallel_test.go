@@ -0,0 +1,313 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	. "knative.dev/pkg/reconciler/testing"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/parallel"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows/resources"
+
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	parallelName = "par"
+
+	testNS = "testnamespace"
+
+	parallelUID = parallelName + "-abc-123"
+
+	testProject = "test-project-id"
+
+	ingressName = "cre-par-parallel"
+	ingressURI  = "http://cre-par-parallel.testnamespace.svc.cluster.local/"
+)
+
+var (
+	sinkGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	replyURI       = apis.HTTP("reply.testnamespace.svc.cluster.local")
+	branchReplyURI = apis.HTTP("branch-reply.testnamespace.svc.cluster.local")
+
+	parallelSpec = v1beta1.ParallelSpec{
+		Project: testProject,
+		Branches: []v1beta1.ParallelBranch{{
+			Filter:     sinkDestination("filter"),
+			Subscriber: *sinkDestination("first"),
+		}, {
+			Subscriber: *sinkDestination("second"),
+			Reply: &duckv1.Destination{
+				URI: branchReplyURI,
+			},
+		}},
+		Reply: &duckv1.Destination{
+			URI: replyURI,
+		},
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = inteventsv1beta1.AddToScheme(scheme.Scheme)
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "create topics and pullsubscriptions",
+		Objects: []runtime.Object{
+			newParallel(),
+		},
+		Key: testNS + "/" + parallelName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", parallelName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Topic %q", ingressName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Topic %q", "cre-par-branch-0"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", "cre-par-branch-0-filter"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", "cre-par-branch-0"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", "cre-par-branch-1"),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Parallel reconciled: "%s/%s"`, testNS, parallelName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newParallel(
+				// Updates
+				WithInitParallelConditions,
+				WithParallelTopicStatuses([]*inteventsv1beta1.Topic{newIngressTopic()}),
+				WithParallelNoAddress,
+				WithParallelSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newFilterPullSubscription()}),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			newIngressTopic(),
+			newBranchTopic(),
+			newFilterPullSubscription(),
+			newFilteredPullSubscription(),
+			newUnfilteredPullSubscription(),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, parallelName, true),
+		},
+	}, {
+		Name: "topics and pullsubscriptions ready",
+		Objects: []runtime.Object{
+			newParallel(
+				WithInitParallelConditions,
+			),
+			ready(newIngressTopic()),
+			ready(newBranchTopic()),
+			readyPullSubscription(newFilterPullSubscription()),
+			readyPullSubscription(newFilteredPullSubscription()),
+			readyPullSubscription(newUnfilteredPullSubscription()),
+		},
+		Key: testNS + "/" + parallelName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", parallelName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Parallel reconciled: "%s/%s"`, testNS, parallelName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newParallel(
+				WithInitParallelConditions,
+				// Updates
+				WithParallelTopicStatuses([]*inteventsv1beta1.Topic{ready(newIngressTopic())}),
+				WithParallelAddress(ingressURI),
+				WithParallelSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{readyPullSubscription(newFilterPullSubscription())}),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, parallelName, true),
+		},
+	}, {
+		Name: "create pullsubscription fails",
+		Objects: []runtime.Object{
+			newParallel(
+				WithInitParallelConditions,
+			),
+			ready(newIngressTopic()),
+			ready(newBranchTopic()),
+		},
+		Key: testNS + "/" + parallelName,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("create", "pullsubscriptions"),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", parallelName),
+			Eventf(corev1.EventTypeWarning, "PullSubscriptionCreateFailed", "Failed to create PullSubscription %q: %s", "cre-par-branch-0-filter", "inducing failure for create pullsubscriptions"),
+			Eventf(corev1.EventTypeWarning, reconciledSubscriptionsFailedReason, "Reconcile PullSubscriptions failed with: %s", "inducing failure for create pullsubscriptions"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newParallel(
+				WithInitParallelConditions,
+				// Updates
+				WithParallelTopicStatuses([]*inteventsv1beta1.Topic{ready(newIngressTopic())}),
+				WithParallelAddress(ingressURI),
+				WithParallelSubscriptionsFailed(reconciledSubscriptionsFailedReason, "Failed to reconcile PullSubscriptions: inducing failure for create pullsubscriptions"),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			newFilterPullSubscription(),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, parallelName, true),
+		},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			Reconciler: &flows.Reconciler{
+				Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
+				TopicLister:            listers.GetTopicLister(),
+				PullSubscriptionLister: listers.GetPullSubscriptionLister(),
+			},
+			Identity: identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+		}
+		return parallel.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetParallelLister(), r.Recorder, r)
+	}))
+}
+
+func sinkDestination(name string) *duckv1.Destination {
+	return &duckv1.Destination{
+		Ref: &duckv1.KReference{
+			APIVersion: sinkGVK.Group + "/" + sinkGVK.Version,
+			Kind:       sinkGVK.Kind,
+			Name:       name,
+			Namespace:  testNS,
+		},
+	}
+}
+
+func newParallel(opts ...ParallelOption) *v1beta1.Parallel {
+	opts = append([]ParallelOption{
+		WithParallelUID(parallelUID),
+		WithParallelSpec(parallelSpec),
+		WithParallelAnnotations(map[string]string{
+			duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+		}),
+	}, opts...)
+	return NewParallel(parallelName, testNS, opts...)
+}
+
+func newTopic(suffix string) *inteventsv1beta1.Topic {
+	p := newParallel()
+	return resources.MakeTopic(&resources.TopicArgs{
+		Owner:       p,
+		Name:        resources.GenerateName(p, suffix),
+		Project:     testProject,
+		Topic:       resources.GenerateTopicID(p, suffix),
+		Secret:      p.Spec.Secret,
+		Labels:      resources.GetLabels(controllerAgentName, parallelName, parallelUID),
+		Annotations: resources.GetTopicAnnotations(testingMetadataClient.FakeClusterName),
+	})
+}
+
+func newIngressTopic() *inteventsv1beta1.Topic {
+	return newTopic(ingressSuffix)
+}
+
+func newBranchTopic() *inteventsv1beta1.Topic {
+	return newTopic(branchSuffix(0))
+}
+
+func ready(topic *inteventsv1beta1.Topic) *inteventsv1beta1.Topic {
+	url, _ := apis.ParseURL(fmt.Sprintf("http://%s.%s.svc.cluster.local/", topic.Name, testNS))
+	topic.Status.SetAddress(url)
+	topic.Status.MarkPublisherDeployed()
+	topic.Status.MarkTopicReady()
+	return topic
+}
+
+func newPullSubscription(suffix, topicSuffix string, subscriber duckv1.Destination, reply *duckv1.Destination) *inteventsv1beta1.PullSubscription {
+	p := newParallel()
+	return resources.MakePullSubscription(&resources.PullSubscriptionArgs{
+		Owner:       p,
+		Name:        resources.GenerateName(p, suffix),
+		Project:     testProject,
+		Topic:       resources.GenerateTopicID(p, topicSuffix),
+		Secret:      p.Spec.Secret,
+		Labels:      resources.GetLabels(controllerAgentName, parallelName, parallelUID),
+		Annotations: resources.GetPullSubscriptionAnnotations(resourceGroup, parallelName, testingMetadataClient.FakeClusterName),
+		Subscriber:  subscriber,
+		Reply:       reply,
+	})
+}
+
+func newFilterPullSubscription() *inteventsv1beta1.PullSubscription {
+	return newPullSubscription(filterSuffix(0), ingressSuffix, *sinkDestination("filter"), resources.TopicDestination(newBranchTopic()))
+}
+
+func newFilteredPullSubscription() *inteventsv1beta1.PullSubscription {
+	return newPullSubscription(branchSuffix(0), branchSuffix(0), *sinkDestination("first"), &duckv1.Destination{URI: replyURI})
+}
+
+func newUnfilteredPullSubscription() *inteventsv1beta1.PullSubscription {
+	return newPullSubscription(branchSuffix(1), ingressSuffix, *sinkDestination("second"), &duckv1.Destination{URI: branchReplyURI})
+}
+
+func readyPullSubscription(ps *inteventsv1beta1.PullSubscription) *inteventsv1beta1.PullSubscription {
+	ps.Status.InitializeConditions()
+	ps.Status.MarkSink(replyURI)
+	ps.Status.MarkDeployedByAgent()
+	ps.Status.MarkSubscribed("subID")
+	return ps
+}
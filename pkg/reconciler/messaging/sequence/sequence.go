@@ -0,0 +1,159 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sequence
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	sequencereconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/sequence"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows/resources"
+)
+
+const (
+	resourceGroup = "sequences.messaging.cloud.google.com"
+
+	reconciledSuccessReason             = "SequenceReconciled"
+	reconciledTopicsFailedReason        = "TopicsReconcileFailed"
+	reconciledSubscriptionsFailedReason = "SubscriptionsReconcileFailed"
+	deleteWorkloadIdentityFailed        = "WorkloadIdentityDeleteFailed"
+	workloadIdentityFailed              = "WorkloadIdentityReconcileFailed"
+)
+
+// Reconciler implements controller.Reconciler for Sequence resources.
+type Reconciler struct {
+	*flows.Reconciler
+	// identity reconciler for reconciling workload identity.
+	*identity.Identity
+}
+
+// Check that our Reconciler implements Interface.
+var _ sequencereconciler.Interface = (*Reconciler)(nil)
+
+func (r *Reconciler) ReconcileKind(ctx context.Context, sequence *v1beta1.Sequence) pkgreconciler.Event {
+	ctx = logging.WithLogger(ctx, r.Logger.With(zap.Any("sequence", sequence)))
+
+	sequence.Status.InitializeConditions()
+	sequence.Status.ObservedGeneration = sequence.Generation
+
+	// If ServiceAccountName is provided, reconcile workload identity.
+	if sequence.Spec.ServiceAccountName != "" {
+		if _, err := r.Identity.ReconcileWorkloadIdentity(ctx, sequence.Spec.Project, sequence); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, workloadIdentityFailed, "Failed to reconcile Sequence workload identity: %s", err.Error())
+		}
+	}
+
+	selector := resources.GetLabelSelector(controllerAgentName, sequence.Name, string(sequence.UID))
+	clusterName := sequence.GetAnnotations()[duckv1beta1.ClusterNameAnnotation]
+
+	// 1. Create the Topic of each step.
+	topics, err := r.ReconcileTopics(ctx, sequence, selector, makeTopics(sequence, clusterName))
+	if err != nil {
+		sequence.Status.MarkTopicsFailed(reconciledTopicsFailedReason, "Failed to reconcile Topics: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledTopicsFailedReason, "Reconcile Topics failed with: %s", err.Error())
+	}
+	sequence.Status.PropagateTopicStatuses(topics)
+	// Events sent to the Sequence go to the Topic of its first step.
+	sequence.Status.SetAddress(topicAddress(topics))
+
+	// 2. Subscribe each step to its Topic, sending its reply to the Topic
+	// of the next step.
+	subscriptions, err := r.ReconcilePullSubscriptions(ctx, sequence, selector, makePullSubscriptions(sequence, topics, clusterName))
+	if err != nil {
+		sequence.Status.MarkSubscriptionsFailed(reconciledSubscriptionsFailedReason, "Failed to reconcile PullSubscriptions: %s", err.Error())
+		return pkgreconciler.NewEvent(corev1.EventTypeWarning, reconciledSubscriptionsFailedReason, "Reconcile PullSubscriptions failed with: %s", err.Error())
+	}
+	sequence.Status.PropagateSubscriptionStatuses(subscriptions)
+
+	return pkgreconciler.NewEvent(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, sequence.Namespace, sequence.Name)
+}
+
+func (r *Reconciler) FinalizeKind(ctx context.Context, sequence *v1beta1.Sequence) pkgreconciler.Event {
+	// If k8s ServiceAccount exists, binds to the default GCP ServiceAccount, and it only has one ownerReference,
+	// remove the corresponding GCP ServiceAccount iam policy binding.
+	// No need to delete k8s ServiceAccount, it will be automatically handled by k8s Garbage Collection.
+	if sequence.Spec.ServiceAccountName != "" {
+		if err := r.Identity.DeleteWorkloadIdentity(ctx, sequence.Spec.Project, sequence); err != nil {
+			return pkgreconciler.NewEvent(corev1.EventTypeWarning, deleteWorkloadIdentityFailed, "Failed to delete Sequence workload identity: %s", err.Error())
+		}
+	}
+	return nil
+}
+
+// stepSuffix returns the suffix of the names of the Topic and PullSubscription
+// of the i-th step.
+func stepSuffix(i int) string {
+	return fmt.Sprintf("-step-%d", i)
+}
+
+func makeTopics(sequence *v1beta1.Sequence, clusterName string) []*inteventsv1beta1.Topic {
+	topics := make([]*inteventsv1beta1.Topic, 0, len(sequence.Spec.Steps))
+	for i := range sequence.Spec.Steps {
+		topics = append(topics, resources.MakeTopic(&resources.TopicArgs{
+			Owner:              sequence,
+			Name:               resources.GenerateName(sequence, stepSuffix(i)),
+			Project:            sequence.Spec.Project,
+			Topic:              resources.GenerateTopicID(sequence, stepSuffix(i)),
+			ServiceAccountName: sequence.Spec.ServiceAccountName,
+			Secret:             sequence.Spec.Secret,
+			Labels:             resources.GetLabels(controllerAgentName, sequence.Name, string(sequence.UID)),
+			Annotations:        resources.GetTopicAnnotations(clusterName),
+		}))
+	}
+	return topics
+}
+
+func makePullSubscriptions(sequence *v1beta1.Sequence, topics []*inteventsv1beta1.Topic, clusterName string) []*inteventsv1beta1.PullSubscription {
+	subscriptions := make([]*inteventsv1beta1.PullSubscription, 0, len(sequence.Spec.Steps))
+	for i, step := range sequence.Spec.Steps {
+		reply := sequence.Spec.Reply
+		if i < len(sequence.Spec.Steps)-1 {
+			reply = resources.TopicDestination(topics[i+1])
+		}
+		subscriptions = append(subscriptions, resources.MakePullSubscription(&resources.PullSubscriptionArgs{
+			Owner:              sequence,
+			Name:               resources.GenerateName(sequence, stepSuffix(i)),
+			Project:            sequence.Spec.Project,
+			Topic:              topics[i].Spec.Topic,
+			ServiceAccountName: sequence.Spec.ServiceAccountName,
+			Secret:             sequence.Spec.Secret,
+			Labels:             resources.GetLabels(controllerAgentName, sequence.Name, string(sequence.UID)),
+			Annotations:        resources.GetPullSubscriptionAnnotations(resourceGroup, sequence.Name, clusterName),
+			Subscriber:         step,
+			Reply:              reply,
+		}))
+	}
+	return subscriptions
+}
+
+func topicAddress(topics []*inteventsv1beta1.Topic) *apis.URL {
+	if len(topics) == 0 || topics[0].Status.Address == nil {
+		return nil
+	}
+	return topics[0].Status.Address.URL
+}
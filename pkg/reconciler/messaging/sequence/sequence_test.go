@@ -0,0 +1,404 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sequence
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	clientgotesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	. "knative.dev/pkg/reconciler/testing"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/injection/reconciler/messaging/v1beta1/sequence"
+	testingMetadataClient "github.com/google/knative-gcp/pkg/gclient/metadata/testing"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/reconciler/identity"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows"
+	"github.com/google/knative-gcp/pkg/reconciler/messaging/flows/resources"
+
+	. "github.com/google/knative-gcp/pkg/reconciler/testing"
+)
+
+const (
+	sequenceName = "seq"
+
+	testNS = "testnamespace"
+
+	sequenceUID = sequenceName + "-abc-123"
+
+	testProject = "test-project-id"
+
+	topicURI = "http://cre-seq-step-0.testnamespace.svc.cluster.local/"
+)
+
+var (
+	stepGVK = metav1.GroupVersionKind{
+		Group:   "testing.cloud.google.com",
+		Version: "v1beta1",
+		Kind:    "Sink",
+	}
+
+	replyURI = apis.HTTP("reply.testnamespace.svc.cluster.local")
+
+	steps = []duckv1.Destination{{
+		Ref: &duckv1.KReference{
+			APIVersion: apiVersion(stepGVK),
+			Kind:       stepGVK.Kind,
+			Name:       "first",
+			Namespace:  testNS,
+		},
+	}, {
+		Ref: &duckv1.KReference{
+			APIVersion: apiVersion(stepGVK),
+			Kind:       stepGVK.Kind,
+			Name:       "second",
+			Namespace:  testNS,
+		},
+	}}
+
+	sequenceSpec = v1beta1.SequenceSpec{
+		Project: testProject,
+		Steps:   steps,
+		Reply: &duckv1.Destination{
+			URI: replyURI,
+		},
+	}
+)
+
+func init() {
+	// Add types to scheme
+	_ = inteventsv1beta1.AddToScheme(scheme.Scheme)
+}
+
+func patchFinalizers(namespace, name string, add bool) clientgotesting.PatchActionImpl {
+	action := clientgotesting.PatchActionImpl{}
+	action.Name = name
+	action.Namespace = namespace
+	var fname string
+	if add {
+		fname = fmt.Sprintf("%q", resourceGroup)
+	}
+	patch := `{"metadata":{"finalizers":[` + fname + `],"resourceVersion":""}}`
+	action.Patch = []byte(patch)
+	return action
+}
+
+func TestAllCases(t *testing.T) {
+	table := TableTest{{
+		Name: "bad workqueue key",
+		// Make sure Reconcile handles bad keys.
+		Key: "too/many/parts",
+	}, {
+		Name: "key not found",
+		// Make sure Reconcile handles good keys that don't exist.
+		Key: "foo/not-found",
+	}, {
+		Name: "create topics and pullsubscriptions",
+		Objects: []runtime.Object{
+			newSequence(),
+		},
+		Key: testNS + "/" + sequenceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Topic %q", "cre-seq-step-0"),
+			Eventf(corev1.EventTypeNormal, "TopicCreated", "Created Topic %q", "cre-seq-step-1"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", "cre-seq-step-0"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionCreated", "Created PullSubscription %q", "cre-seq-step-1"),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, testNS, sequenceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				// Updates
+				WithInitSequenceConditions,
+				WithSequenceTopicStatuses([]*inteventsv1beta1.Topic{newTopic(0)}),
+				WithSequenceNoAddress,
+				WithSequenceSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newPullSubscription(0)}),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			newTopic(0),
+			newTopic(1),
+			newPullSubscription(0),
+			newPullSubscription(1),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}, {
+		Name: "topics and pullsubscriptions ready",
+		Objects: []runtime.Object{
+			newSequence(
+				WithInitSequenceConditions,
+			),
+			newReadyTopic(0),
+			newReadyTopic(1),
+			newReadyPullSubscription(0),
+			newReadyPullSubscription(1),
+		},
+		Key: testNS + "/" + sequenceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, testNS, sequenceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				WithInitSequenceConditions,
+				// Updates
+				WithSequenceTopicStatuses([]*inteventsv1beta1.Topic{newReadyTopic(0)}),
+				WithSequenceAddress(topicURI),
+				WithSequenceSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newReadyPullSubscription(0)}),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}, {
+		Name: "the status of a pullsubscription is false",
+		Objects: []runtime.Object{
+			newSequence(
+				WithInitSequenceConditions,
+			),
+			newReadyTopic(0),
+			newReadyTopic(1),
+			newReadyPullSubscription(0),
+			newFalsePullSubscription(1),
+		},
+		Key: testNS + "/" + sequenceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, testNS, sequenceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				WithInitSequenceConditions,
+				// Updates
+				WithSequenceTopicStatuses([]*inteventsv1beta1.Topic{newReadyTopic(0)}),
+				WithSequenceAddress(topicURI),
+				WithSequenceSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newFalsePullSubscription(1)}),
+			),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}, {
+		Name: "update step",
+		Objects: []runtime.Object{
+			newSequence(
+				WithInitSequenceConditions,
+			),
+			newReadyTopic(0),
+			newReadyTopic(1),
+			withSink(newReadyPullSubscription(0), duckv1.Destination{URI: replyURI}),
+			newReadyPullSubscription(1),
+		},
+		Key: testNS + "/" + sequenceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionUpdated", "Updated PullSubscription %q", "cre-seq-step-0"),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, testNS, sequenceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				WithInitSequenceConditions,
+				// Updates
+				WithSequenceTopicStatuses([]*inteventsv1beta1.Topic{newReadyTopic(0)}),
+				WithSequenceAddress(topicURI),
+				WithSequenceSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newReadyPullSubscription(0)}),
+			),
+		}},
+		WantUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newReadyPullSubscription(0),
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}, {
+		Name: "delete removed step",
+		Objects: []runtime.Object{
+			newSequence(
+				WithInitSequenceConditions,
+			),
+			newReadyTopic(0),
+			newReadyTopic(1),
+			newReadyTopic(2),
+			newReadyPullSubscription(0),
+			newReadyPullSubscription(1),
+			newReadyPullSubscription(2),
+		},
+		Key: testNS + "/" + sequenceName,
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeNormal, "TopicDeleted", "Deleted Topic %q", "cre-seq-step-2"),
+			Eventf(corev1.EventTypeNormal, "PullSubscriptionDeleted", "Deleted PullSubscription %q", "cre-seq-step-2"),
+			Eventf(corev1.EventTypeNormal, reconciledSuccessReason, `Sequence reconciled: "%s/%s"`, testNS, sequenceName),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				WithInitSequenceConditions,
+				// Updates
+				WithSequenceTopicStatuses([]*inteventsv1beta1.Topic{newReadyTopic(0)}),
+				WithSequenceAddress(topicURI),
+				WithSequenceSubscriptionStatuses([]*inteventsv1beta1.PullSubscription{newReadyPullSubscription(0)}),
+			),
+		}},
+		WantDeletes: []clientgotesting.DeleteActionImpl{{
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "topics"}},
+			Name: "cre-seq-step-2",
+		}, {
+			ActionImpl: clientgotesting.ActionImpl{
+				Namespace: testNS, Verb: "delete", Resource: schema.GroupVersionResource{Group: "internal.events.cloud.google.com", Version: "v1beta1", Resource: "pullsubscriptions"}},
+			Name: "cre-seq-step-2",
+		}},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}, {
+		Name: "create topic fails",
+		Objects: []runtime.Object{
+			newSequence(),
+		},
+		Key: testNS + "/" + sequenceName,
+		WithReactors: []clientgotesting.ReactionFunc{
+			InduceFailure("create", "topics"),
+		},
+		WantEvents: []string{
+			Eventf(corev1.EventTypeNormal, "FinalizerUpdate", "Updated %q finalizers", sequenceName),
+			Eventf(corev1.EventTypeWarning, "TopicCreateFailed", "Failed to create Topic %q: %s", "cre-seq-step-0", "inducing failure for create topics"),
+			Eventf(corev1.EventTypeWarning, reconciledTopicsFailedReason, "Reconcile Topics failed with: %s", "inducing failure for create topics"),
+		},
+		WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+			Object: newSequence(
+				// Updates
+				WithInitSequenceConditions,
+				WithSequenceTopicsFailed(reconciledTopicsFailedReason, "Failed to reconcile Topics: inducing failure for create topics"),
+			),
+		}},
+		WantCreates: []runtime.Object{
+			newTopic(0),
+		},
+		WantPatches: []clientgotesting.PatchActionImpl{
+			patchFinalizers(testNS, sequenceName, true),
+		},
+	}}
+
+	defer logtesting.ClearAll()
+	table.Test(t, MakeFactory(func(ctx context.Context, listers *Listers, cmw configmap.Watcher, _ map[string]interface{}) controller.Reconciler {
+		r := &Reconciler{
+			Reconciler: &flows.Reconciler{
+				Base:                   reconciler.NewBase(ctx, controllerAgentName, cmw),
+				TopicLister:            listers.GetTopicLister(),
+				PullSubscriptionLister: listers.GetPullSubscriptionLister(),
+			},
+			Identity: identity.NewIdentity(ctx, NoopIAMPolicyManager, NewGCPAuthTestStore(t, nil)),
+		}
+		return sequence.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetSequenceLister(), r.Recorder, r)
+	}))
+}
+
+func apiVersion(gvk metav1.GroupVersionKind) string {
+	return gvk.Group + "/" + gvk.Version
+}
+
+func newSequence(opts ...SequenceOption) *v1beta1.Sequence {
+	opts = append([]SequenceOption{
+		WithSequenceUID(sequenceUID),
+		WithSequenceSpec(sequenceSpec),
+		WithSequenceAnnotations(map[string]string{
+			duckv1beta1.ClusterNameAnnotation: testingMetadataClient.FakeClusterName,
+		}),
+	}, opts...)
+	return NewSequence(sequenceName, testNS, opts...)
+}
+
+func newTopic(step int) *inteventsv1beta1.Topic {
+	s := newSequence()
+	return resources.MakeTopic(&resources.TopicArgs{
+		Owner:       s,
+		Name:        resources.GenerateName(s, stepSuffix(step)),
+		Project:     testProject,
+		Topic:       resources.GenerateTopicID(s, stepSuffix(step)),
+		Secret:      s.Spec.Secret,
+		Labels:      resources.GetLabels(controllerAgentName, sequenceName, sequenceUID),
+		Annotations: resources.GetTopicAnnotations(testingMetadataClient.FakeClusterName),
+	})
+}
+
+func newReadyTopic(step int) *inteventsv1beta1.Topic {
+	topic := newTopic(step)
+	url, _ := apis.ParseURL(fmt.Sprintf("http://%s.%s.svc.cluster.local/", topic.Name, testNS))
+	topic.Status.SetAddress(url)
+	topic.Status.MarkPublisherDeployed()
+	topic.Status.MarkTopicReady()
+	return topic
+}
+
+func newPullSubscription(step int) *inteventsv1beta1.PullSubscription {
+	s := newSequence()
+	reply := sequenceSpec.Reply
+	if step < len(steps)-1 {
+		reply = resources.TopicDestination(newTopic(step + 1))
+	}
+	return resources.MakePullSubscription(&resources.PullSubscriptionArgs{
+		Owner:       s,
+		Name:        resources.GenerateName(s, stepSuffix(step)),
+		Project:     testProject,
+		Topic:       resources.GenerateTopicID(s, stepSuffix(step)),
+		Secret:      s.Spec.Secret,
+		Labels:      resources.GetLabels(controllerAgentName, sequenceName, sequenceUID),
+		Annotations: resources.GetPullSubscriptionAnnotations(resourceGroup, sequenceName, testingMetadataClient.FakeClusterName),
+		Subscriber:  steps[step%len(steps)],
+		Reply:       reply,
+	})
+}
+
+func newReadyPullSubscription(step int) *inteventsv1beta1.PullSubscription {
+	ps := newPullSubscription(step)
+	ps.Status.InitializeConditions()
+	ps.Status.MarkSink(replyURI)
+	ps.Status.MarkDeployedByAgent()
+	ps.Status.MarkSubscribed("subID")
+	return ps
+}
+
+func newFalsePullSubscription(step int) *inteventsv1beta1.PullSubscription {
+	ps := newPullSubscription(step)
+	ps.Status.InitializeConditions()
+	ps.Status.MarkNoSink("InvalidSink", "Sink is not ready")
+	return ps
+}
+
+func withSink(ps *inteventsv1beta1.PullSubscription, sink duckv1.Destination) *inteventsv1beta1.PullSubscription {
+	ps.Spec.Sink = sink
+	ps.Spec.Transformer = nil
+	return ps
+}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	"go.uber.org/zap"
+	"knative.dev/pkg/apis"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
@@ -42,6 +43,7 @@ import (
 	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
 	runScheme "github.com/google/knative-gcp/pkg/client/clientset/versioned/scheme"
 	runclient "github.com/google/knative-gcp/pkg/client/injection/client"
+	"github.com/google/knative-gcp/pkg/reconciler/lifecycle"
 )
 
 const (
@@ -124,6 +126,9 @@ type Base struct {
 	// StatsReporter reports reconciler's metrics.
 	StatsReporter StatsReporter
 
+	// Lifecycle sends lifecycle events of the reconciled resources.
+	Lifecycle lifecycle.Notifier
+
 	// Sugared logger is easier to use but is not as performant as the
 	// raw logger. In performance critical paths, call logger.Desugar()
 	// and use the returned raw logger instead. In addition to the
@@ -176,6 +181,16 @@ func NewBase(ctx context.Context, controllerAgentName string, cmw configmap.Watc
 		}
 	}
 
+	notifier := lifecycle.GetNotifier(ctx)
+	if notifier == nil {
+		logger.Debug("Creating lifecycle notifier")
+		var err error
+		notifier, err = lifecycle.NewNotifierFromEnv(ctx)
+		if err != nil {
+			logger.Fatal(err)
+		}
+	}
+
 	base := &Base{
 		KubeClientSet:    kubeClient,
 		RunClientSet:     runclient.Get(ctx),
@@ -184,12 +199,25 @@ func NewBase(ctx context.Context, controllerAgentName string, cmw configmap.Watc
 		ConfigMapWatcher: cmw,
 		Recorder:         recorder,
 		StatsReporter:    statsReporter,
+		Lifecycle:        notifier,
 		Logger:           logger,
 	}
 
 	return base
 }
 
+// TrackReadiness records the Ready condition of obj, whose status is status,
+// and returns a function that notifies of a change of its readiness once obj
+// is reconciled. It is meant to be deferred at the start of ReconcileKind:
+//
+//	defer r.TrackReadiness(ctx, source, &source.Status)()
+func (b *Base) TrackReadiness(ctx context.Context, obj lifecycle.Object, status lifecycle.ConditionGetter) func() {
+	before := status.GetCondition(apis.ConditionReady).DeepCopy()
+	return func() {
+		b.Lifecycle.NotifyReadyChange(ctx, obj, obj.GetGroupVersionKind().Kind, before, status.GetCondition(apis.ConditionReady))
+	}
+}
+
 func init() {
 	// Add run types to the default Kubernetes Scheme so Events can be
 	// logged for run types.
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"encoding/json"
+	"testing"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/types"
+	clientgotesting "k8s.io/client-go/testing"
+
+	"github.com/google/knative-gcp/pkg/reconciler/apply"
+)
+
+// NewApplyPatch returns the patch action that server-side applies obj.
+func NewApplyPatch(t *testing.T, obj runtime.Object) clientgotesting.PatchActionImpl {
+	t.Helper()
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		t.Fatalf("Failed to access the metadata of %T: %v", obj, err)
+	}
+	patch, err := apply.Patch(obj)
+	if err != nil {
+		t.Fatalf("Failed to create the apply patch of %T: %v", obj, err)
+	}
+	return clientgotesting.PatchActionImpl{
+		ActionImpl: clientgotesting.ActionImpl{
+			Namespace: accessor.GetNamespace(),
+			Verb:      "patch",
+		},
+		Name:      accessor.GetName(),
+		PatchType: types.ApplyPatchType,
+		Patch:     patch,
+	}
+}
+
+// ApplyPatchReactor emulates server-side apply, which the fake clients don't
+// support, by merging apply patches into objs. The result is returned but not
+// stored, like the fake dynamic client does for other patches.
+func ApplyPatchReactor(scheme *runtime.Scheme, objs ...runtime.Object) clientgotesting.ReactionFunc {
+	tracker := clientgotesting.NewObjectTracker(scheme, serializer.NewCodecFactory(scheme).UniversalDecoder())
+	for _, obj := range objs {
+		if err := tracker.Add(obj); err != nil {
+			panic(err)
+		}
+	}
+	return func(action clientgotesting.Action) (bool, runtime.Object, error) {
+		pa, ok := action.(clientgotesting.PatchAction)
+		if !ok || pa.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		applied := map[string]interface{}{}
+		if err := json.Unmarshal(pa.GetPatch(), &applied); err != nil {
+			return true, nil, err
+		}
+		existing, err := tracker.Get(pa.GetResource(), pa.GetNamespace(), pa.GetName())
+		if apierrs.IsNotFound(err) {
+			return true, &unstructured.Unstructured{Object: applied}, nil
+		}
+		if err != nil {
+			return true, nil, err
+		}
+		current, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+		if err != nil {
+			return true, nil, err
+		}
+		mergeApplied(current, applied)
+		return true, &unstructured.Unstructured{Object: current}, nil
+	}
+}
+
+// mergeApplied merges the applied fields into current. Unlike real
+// server-side apply, lists are replaced rather than merged by key.
+func mergeApplied(current, applied map[string]interface{}) {
+	for k, v := range applied {
+		am, aok := v.(map[string]interface{})
+		cm, cok := current[k].(map[string]interface{})
+		if aok && cok {
+			mergeApplied(cm, am)
+			continue
+		}
+		current[k] = v
+	}
+}
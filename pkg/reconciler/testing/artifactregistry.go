@@ -0,0 +1,157 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Veroute.on 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// CloudArtifactRegistrySourceOption enables further configuration of a CloudArtifactRegistrySource.
+type CloudArtifactRegistrySourceOption func(*v1beta1.CloudArtifactRegistrySource)
+
+// NewCloudArtifactRegistrySource creates a CloudArtifactRegistrySource with CloudArtifactRegistrySourceOptions
+func NewCloudArtifactRegistrySource(name, namespace string, so ...CloudArtifactRegistrySourceOption) *v1beta1.CloudArtifactRegistrySource {
+	bs := &v1beta1.CloudArtifactRegistrySource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-artifact-registry-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(bs)
+	}
+	bs.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return bs
+}
+
+func WithCloudArtifactRegistrySourceSink(gvk metav1.GroupVersionKind, name string) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudArtifactRegistrySourceDeletionTimestamp(s *v1beta1.CloudArtifactRegistrySource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudArtifactRegistrySourceProject(project string) CloudArtifactRegistrySourceOption {
+	return func(s *v1beta1.CloudArtifactRegistrySource) {
+		s.Spec.Project = project
+	}
+}
+
+// WithInitCloudArtifactRegistrySourceConditions initializes the CloudArtifactRegistrySource's conditions.
+func WithInitCloudArtifactRegistrySourceConditions(bs *v1beta1.CloudArtifactRegistrySource) {
+	bs.Status.InitializeConditions()
+}
+
+// WithCloudArtifactRegistrySourceServiceAccountName will give status.ServiceAccountName a k8s service account name, which is related on Workload Identity's Google service account.
+func WithCloudArtifactRegistrySourceServiceAccountName(name string) CloudArtifactRegistrySourceOption {
+	return func(s *v1beta1.CloudArtifactRegistrySource) {
+		s.Status.ServiceAccountName = name
+	}
+}
+
+func WithCloudArtifactRegistrySourceWorkloadIdentityFailed(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(s *v1beta1.CloudArtifactRegistrySource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithCloudArtifactRegistrySourcePullSubscriptionFailed(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.MarkPullSubscriptionFailed(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithCloudArtifactRegistrySourcePullSubscriptionUnknown(reason, message string) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.MarkPullSubscriptionUnknown(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudArtifactRegistrySourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithCloudArtifactRegistrySourcePullSubscriptionReady() CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.MarkPullSubscriptionReady(bs.ConditionSet())
+	}
+}
+
+// WithCloudArtifactRegistrySourceSinkURI sets the status for sink URI
+func WithCloudArtifactRegistrySourceSinkURI(url *apis.URL) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.SinkURI = url
+	}
+}
+
+func WithCloudArtifactRegistrySourceSubscriptionID(subscriptionID string) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudArtifactRegistrySourceFinalizers(finalizers ...string) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Finalizers = finalizers
+	}
+}
+
+func WithCloudArtifactRegistrySourceStatusObservedGeneration(generation int64) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithCloudArtifactRegistrySourceObjectMetaGeneration(generation int64) CloudArtifactRegistrySourceOption {
+	return func(bs *v1beta1.CloudArtifactRegistrySource) {
+		bs.ObjectMeta.Generation = generation
+	}
+}
+
+func WithCloudArtifactRegistrySourceAnnotations(Annotations map[string]string) CloudArtifactRegistrySourceOption {
+	return func(s *v1beta1.CloudArtifactRegistrySource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudArtifactRegistrySourceDefaultGCPAuth() CloudArtifactRegistrySourceOption {
+	return func(s *v1beta1.CloudArtifactRegistrySource) {
+		s.Spec.PubSubSpec.SetPubSubDefaults(gcpauthtesthelper.ContextWithDefaults())
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Veroute.on 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// CloudBillingBudgetSourceOption enables further configuration of a CloudBillingBudgetSource.
+type CloudBillingBudgetSourceOption func(*v1beta1.CloudBillingBudgetSource)
+
+// NewCloudBillingBudgetSource creates a CloudBillingBudgetSource with CloudBillingBudgetSourceOptions
+func NewCloudBillingBudgetSource(name, namespace string, so ...CloudBillingBudgetSourceOption) *v1beta1.CloudBillingBudgetSource {
+	bs := &v1beta1.CloudBillingBudgetSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-billing-budget-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(bs)
+	}
+	bs.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return bs
+}
+
+func WithCloudBillingBudgetSourceSink(gvk metav1.GroupVersionKind, name string) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudBillingBudgetSourceDeletionTimestamp(s *v1beta1.CloudBillingBudgetSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithCloudBillingBudgetSourceProject(project string) CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudBillingBudgetSourceTopic(topic string) CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.Spec.Topic = topic
+	}
+}
+
+// WithInitCloudBillingBudgetSourceConditions initializes the CloudBillingBudgetSource's conditions.
+func WithInitCloudBillingBudgetSourceConditions(bs *v1beta1.CloudBillingBudgetSource) {
+	bs.Status.InitializeConditions()
+}
+
+// WithCloudBillingBudgetSourceServiceAccountName will give status.ServiceAccountName a k8s service account name, which is related on Workload Identity's Google service account.
+func WithCloudBillingBudgetSourceServiceAccountName(name string) CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.Status.ServiceAccountName = name
+	}
+}
+
+func WithCloudBillingBudgetSourceWorkloadIdentityFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithCloudBillingBudgetSourcePullSubscriptionFailed(reason, message string) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.MarkPullSubscriptionFailed(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithCloudBillingBudgetSourcePullSubscriptionUnknown(reason, message string) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.MarkPullSubscriptionUnknown(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudBillingBudgetSourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithCloudBillingBudgetSourcePullSubscriptionReady() CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.MarkPullSubscriptionReady(bs.ConditionSet())
+	}
+}
+
+// WithCloudBillingBudgetSourceSinkURI sets the status for sink URI
+func WithCloudBillingBudgetSourceSinkURI(url *apis.URL) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.SinkURI = url
+	}
+}
+
+func WithCloudBillingBudgetSourceSubscriptionID(subscriptionID string) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithCloudBillingBudgetSourceFinalizers(finalizers ...string) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Finalizers = finalizers
+	}
+}
+
+func WithCloudBillingBudgetSourceStatusObservedGeneration(generation int64) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithCloudBillingBudgetSourceObjectMetaGeneration(generation int64) CloudBillingBudgetSourceOption {
+	return func(bs *v1beta1.CloudBillingBudgetSource) {
+		bs.ObjectMeta.Generation = generation
+	}
+}
+
+func WithCloudBillingBudgetSourceAnnotations(Annotations map[string]string) CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudBillingBudgetSourceDefaultGCPAuth() CloudBillingBudgetSourceOption {
+	return func(s *v1beta1.CloudBillingBudgetSource) {
+		s.Spec.PubSubSpec.SetPubSubDefaults(gcpauthtesthelper.ContextWithDefaults())
+	}
+}
@@ -23,8 +23,10 @@ import (
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	eventingduckv1beta1 "knative.dev/eventing/pkg/apis/duck/v1beta1"
 	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
 	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
 
 // BrokerOption enables further configuration of a Broker.
@@ -146,10 +148,43 @@ func WithBrokerTopicReady(b *brokerv1beta1.Broker) {
 	b.Status.MarkTopicReady()
 }
 
+func WithBrokerTopicFailed(reason, msg string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.MarkTopicFailed(reason, msg)
+	}
+}
+
+func WithBrokerSubscriptionFailed(reason, msg string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.MarkSubscriptionFailed(reason, msg)
+	}
+}
+
 func WithBrokerConfigReady(b *brokerv1beta1.Broker) {
 	b.Status.MarkConfigReady()
 }
 
+func WithBrokerPublishReady(b *brokerv1beta1.Broker) {
+	b.Status.MarkPublishReady()
+}
+
+func WithBrokerPublishFailed(reason, msg string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Status.MarkPublishFailed(reason, msg)
+	}
+}
+
+// WithBrokerDeadLetterSinkURI sets the dead letter sink in the delivery spec
+// of the Broker.
+func WithBrokerDeadLetterSinkURI(uri *apis.URL) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		if b.Spec.Delivery == nil {
+			b.Spec.Delivery = &eventingduckv1beta1.DeliverySpec{}
+		}
+		b.Spec.Delivery.DeadLetterSink = &duckv1.Destination{URI: uri}
+	}
+}
+
 func WithBrokerClass(bc string) BrokerOption {
 	return func(b *brokerv1beta1.Broker) {
 		annotations := b.GetAnnotations()
@@ -160,3 +195,82 @@ func WithBrokerClass(bc string) BrokerOption {
 		b.SetAnnotations(annotations)
 	}
 }
+
+// WithBrokerLiteLocation puts the queues of the broker on Pub/Sub Lite in
+// the given zone.
+func WithBrokerLiteLocation(location string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.LiteLocationAnnotation] = location
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerMessageOrdering enables message ordering on the Broker decouple queue.
+func WithBrokerLabels(labels map[string]string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		b.Labels = labels
+	}
+}
+
+func WithBrokerMessageOrdering(b *brokerv1beta1.Broker) {
+	annotations := b.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[brokerv1beta1.MessageOrderingAnnotation] = "true"
+	b.SetAnnotations(annotations)
+}
+
+// WithBrokerPriorityQueue gives the Broker a priority queue for its high
+// priority events.
+func WithBrokerPriorityQueue(b *brokerv1beta1.Broker) {
+	annotations := b.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[brokerv1beta1.PriorityQueueAnnotation] = "true"
+	b.SetAnnotations(annotations)
+}
+
+// WithBrokerEventPolicy sets the event policy of the Broker, a JSON object
+// of allowed and denied event types and sources.
+func WithBrokerEventPolicy(policy string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.EventPolicyAnnotation] = policy
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerIngressAuthSecret sets the name of the Secret holding the bearer
+// tokens the ingress accepts for the Broker.
+func WithBrokerIngressAuthSecret(name string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.IngressAuthSecretAnnotation] = name
+		b.SetAnnotations(annotations)
+	}
+}
+
+// WithBrokerEventEncoding sets how the ingress writes the events published to
+// the Broker decouple queues.
+func WithBrokerEventEncoding(encoding string) BrokerOption {
+	return func(b *brokerv1beta1.Broker) {
+		annotations := b.GetAnnotations()
+		if annotations == nil {
+			annotations = make(map[string]string, 1)
+		}
+		annotations[brokerv1beta1.EventEncodingAnnotation] = encoding
+		b.SetAnnotations(annotations)
+	}
+}
@@ -50,6 +50,12 @@ func WithBrokerCellAnnotations(annotations map[string]string) BrokerCellOption {
 }
 
 // WithInitBrokerCellConditions initializes the BrokerCell's conditions.
+func WithBrokerCellBrokerSelector(selector *metav1.LabelSelector) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Spec.BrokerSelector = selector
+	}
+}
+
 func WithInitBrokerCellConditions(bc *intv1alpha1.BrokerCell) {
 	bc.Status.InitializeConditions()
 }
@@ -134,3 +140,24 @@ func WithTargetsCofigFailed(reason, msg string) BrokerCellOption {
 		bc.Status.MarkTargetsConfigFailed(reason, msg)
 	}
 }
+
+func WithBrokerCellCapacityExhausted(reason, msg string) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Status.MarkCapacityExhausted(reason, msg)
+	}
+}
+
+func WithBrokerCellReplicas(ingress, fanout, retry intv1alpha1.ComponentReplicas) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Status.Ingress = &ingress
+		bc.Status.Fanout = &fanout
+		bc.Status.Retry = &retry
+	}
+}
+
+func WithBrokerCellCounts(brokers, triggers int32) BrokerCellOption {
+	return func(bc *intv1alpha1.BrokerCell) {
+		bc.Status.BrokerCount = brokers
+		bc.Status.TriggerCount = triggers
+	}
+}
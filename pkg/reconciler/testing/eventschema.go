@@ -0,0 +1,103 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
+)
+
+// EventSchemaOption enables further configuration of an EventSchema.
+type EventSchemaOption func(*v1alpha1.EventSchema)
+
+// NewEventSchema creates an EventSchema with EventSchemaOptions.
+func NewEventSchema(name, namespace string, o ...EventSchemaOption) *v1alpha1.EventSchema {
+	es := &v1alpha1.EventSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+		},
+	}
+	for _, opt := range o {
+		opt(es)
+	}
+	es.SetDefaults(context.Background())
+	return es
+}
+
+func WithEventSchemaType(eventType string) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Spec.Type = eventType
+	}
+}
+
+func WithEventSchemaSchema(schema string) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Spec.Schema = runtime.RawExtension{Raw: []byte(schema)}
+	}
+}
+
+func WithEventSchemaAction(action v1alpha1.EventSchemaAction) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Spec.Action = action
+	}
+}
+
+func WithEventSchemaValidateOnDelivery(es *v1alpha1.EventSchema) {
+	es.Spec.ValidateOnDelivery = true
+}
+
+func WithEventSchemaDeletionTimestamp(es *v1alpha1.EventSchema) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	es.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithEventSchemaFinalizers(finalizers ...string) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Finalizers = finalizers
+	}
+}
+
+// WithInitEventSchemaConditions initializes the EventSchema's conditions.
+func WithInitEventSchemaConditions(es *v1alpha1.EventSchema) {
+	es.Status.InitializeConditions()
+}
+
+func WithEventSchemaSchemaValid(es *v1alpha1.EventSchema) {
+	es.Status.MarkSchemaValid()
+}
+
+func WithEventSchemaSchemaInvalid(reason, message string) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Status.MarkSchemaInvalid(reason, message)
+	}
+}
+
+func WithEventSchemaPublished(es *v1alpha1.EventSchema) {
+	es.Status.MarkPublished()
+}
+
+func WithEventSchemaPublishFailed(reason, message string) EventSchemaOption {
+	return func(es *v1alpha1.EventSchema) {
+		es.Status.MarkPublishFailed(reason, message)
+	}
+}
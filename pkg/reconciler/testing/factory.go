@@ -30,6 +30,7 @@ import (
 	logtesting "knative.dev/pkg/logging/testing"
 
 	fakerunclient "github.com/google/knative-gcp/pkg/client/injection/client/fake"
+	fakeeventingclient "knative.dev/eventing/pkg/client/injection/client/fake"
 	fakekubeclient "knative.dev/pkg/client/injection/kube/client/fake"
 	fakedynamicclient "knative.dev/pkg/injection/clients/dynamicclient/fake"
 	fakeservingclient "knative.dev/serving/pkg/client/injection/client/fake"
@@ -58,6 +59,9 @@ func MakeFactory(ctor Ctor) Factory {
 		ctx, kubeClient := fakekubeclient.With(ctx, ls.GetKubeObjects()...)
 		ctx, client := fakerunclient.With(ctx, ls.GetEventsObjects()...)
 		ctx, servingclient := fakeservingclient.With(ctx, ls.GetServingObjects()...)
+		// The Knative eventing types share their API group with the broker
+		// types, so they cannot be added to the listers' scheme.
+		ctx, eventingclient := fakeeventingclient.With(ctx)
 
 		dynamicScheme := runtime.NewScheme()
 		for _, addTo := range clientSetSchemes {
@@ -72,6 +76,7 @@ func MakeFactory(ctor Ctor) Factory {
 			func(action ktesting.Action) (bool, runtime.Object, error) {
 				return true, nil, nil
 			})
+		dynamicClient.PrependReactor("patch", "*", ApplyPatchReactor(dynamicScheme, ls.GetAllObjects()...))
 
 		eventRecorder := record.NewFakeRecorder(maxEventBufferSize)
 		ctx = controller.WithEventRecorder(ctx, eventRecorder)
@@ -87,6 +92,7 @@ func MakeFactory(ctor Ctor) Factory {
 			client.PrependReactor("*", "*", reactor)
 			dynamicClient.PrependReactor("*", "*", reactor)
 			servingclient.PrependReactor("*", "*", reactor)
+			eventingclient.PrependReactor("*", "*", reactor)
 		}
 
 		// Validate all Create operations through the serving client.
@@ -99,7 +105,7 @@ func MakeFactory(ctor Ctor) Factory {
 			return ValidateUpdates(context.Background(), action)
 		})
 
-		actionRecorderList := ActionRecorderList{dynamicClient, client, kubeClient, servingclient}
+		actionRecorderList := ActionRecorderList{dynamicClient, client, kubeClient, servingclient, eventingclient}
 		eventList := EventList{Recorder: eventRecorder}
 
 		return c, actionRecorderList, eventList
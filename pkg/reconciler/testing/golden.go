@@ -0,0 +1,69 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/yaml"
+)
+
+// updateGolden makes AssertGolden write the golden files instead of comparing
+// them, e.g. go test ./pkg/reconciler/brokercell/resources -update-golden.
+var updateGolden = flag.Bool("update-golden", false, "write the golden files of the snapshot tests with the generated resources")
+
+// GoldenPath returns the path of the golden file of the named snapshot,
+// relative to the directory of the package under test.
+func GoldenPath(name string) string {
+	return filepath.Join("testdata", name+".golden.yaml")
+}
+
+// AssertGolden compares the YAML serialization of a generated resource to the
+// golden file of the named snapshot. Run the test with -update-golden to write
+// the golden file after an intended change, and review the diff of the file.
+func AssertGolden(t *testing.T, name string, obj interface{}) {
+	t.Helper()
+	got, err := yaml.Marshal(obj)
+	if err != nil {
+		t.Fatalf("Failed to serialize %s to YAML: %v", name, err)
+	}
+	path := GoldenPath(name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create the golden file directory: %v", err)
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("Failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Fatalf("Golden file %s doesn't exist, run the test with -update-golden to create it", path)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden file %s: %v", path, err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("%s doesn't match the golden file %s, run the test with -update-golden if the change is intended (-want, +got): %v", name, path, diff)
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
+)
+
+// KnativeGCPOption enables further configuration of a KnativeGCP.
+type KnativeGCPOption func(*v1alpha1.KnativeGCP)
+
+// NewKnativeGCP creates a KnativeGCP with KnativeGCPOptions.
+func NewKnativeGCP(name string, o ...KnativeGCPOption) *v1alpha1.KnativeGCP {
+	k := &v1alpha1.KnativeGCP{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+	for _, opt := range o {
+		opt(k)
+	}
+	k.SetDefaults(context.Background())
+	return k
+}
+
+func WithKnativeGCPImages(images v1alpha1.ImagesSpec) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Spec.Images = images
+	}
+}
+
+func WithKnativeGCPConfig(name string, data map[string]string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		if k.Spec.Config == nil {
+			k.Spec.Config = make(map[string]map[string]string)
+		}
+		k.Spec.Config[name] = data
+	}
+}
+
+func WithKnativeGCPFeatures(features map[string]string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Spec.Features = features
+	}
+}
+
+func WithKnativeGCPFinalizers(finalizers ...string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Finalizers = finalizers
+	}
+}
+
+func WithKnativeGCPDeletionTimestamp(k *v1alpha1.KnativeGCP) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	k.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitKnativeGCPConditions initializes the KnativeGCP's conditions.
+func WithInitKnativeGCPConditions(k *v1alpha1.KnativeGCP) {
+	k.Status.InitializeConditions()
+}
+
+func WithKnativeGCPConfigReady(k *v1alpha1.KnativeGCP) {
+	k.Status.MarkConfigReady()
+}
+
+func WithKnativeGCPConfigFailed(reason, message string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Status.MarkConfigFailed(reason, message)
+	}
+}
+
+func WithKnativeGCPDeploymentsReady(k *v1alpha1.KnativeGCP) {
+	k.Status.MarkDeploymentsReady()
+}
+
+func WithKnativeGCPDeploymentsUnknown(reason, message string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Status.MarkDeploymentsUnknown(reason, message)
+	}
+}
+
+func WithKnativeGCPDeploymentsFailed(reason, message string) KnativeGCPOption {
+	return func(k *v1alpha1.KnativeGCP) {
+		k.Status.MarkDeploymentsFailed(reason, message)
+	}
+}
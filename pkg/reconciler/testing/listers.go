@@ -44,16 +44,20 @@ import (
 	fakeservingclientset "knative.dev/serving/pkg/client/clientset/versioned/fake"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	EventsV1alpha1 "github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
 	EventsV1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
 	intv1alpha1 "github.com/google/knative-gcp/pkg/apis/intevents/v1alpha1"
 	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
 	Messagingv1beta1 "github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+	operatorv1alpha1 "github.com/google/knative-gcp/pkg/apis/operator/v1alpha1"
 	fakeeventsclientset "github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	eventsv1alpha1listers "github.com/google/knative-gcp/pkg/client/listers/events/v1alpha1"
 	eventslisters "github.com/google/knative-gcp/pkg/client/listers/events/v1beta1"
 	intlisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
 	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1beta1"
 	messaginglisters "github.com/google/knative-gcp/pkg/client/listers/messaging/v1beta1"
+	operatorlisters "github.com/google/knative-gcp/pkg/client/listers/operator/v1alpha1"
 )
 
 var sinkAddToScheme = func(scheme *runtime.Scheme) error {
@@ -128,6 +132,14 @@ func (l *Listers) GetChannelLister() messaginglisters.ChannelLister {
 	return messaginglisters.NewChannelLister(l.indexerFor(&Messagingv1beta1.Channel{}))
 }
 
+func (l *Listers) GetSequenceLister() messaginglisters.SequenceLister {
+	return messaginglisters.NewSequenceLister(l.indexerFor(&Messagingv1beta1.Sequence{}))
+}
+
+func (l *Listers) GetParallelLister() messaginglisters.ParallelLister {
+	return messaginglisters.NewParallelLister(l.indexerFor(&Messagingv1beta1.Parallel{}))
+}
+
 func (l *Listers) GetJobLister() batchv1listers.JobLister {
 	return batchv1listers.NewJobLister(l.indexerFor(&batchv1.Job{}))
 }
@@ -152,6 +164,30 @@ func (l *Listers) GetCloudBuildSourceLister() eventslisters.CloudBuildSourceList
 	return eventslisters.NewCloudBuildSourceLister(l.indexerFor(&EventsV1beta1.CloudBuildSource{}))
 }
 
+func (l *Listers) GetCloudMonitoringAlertSourceLister() eventslisters.CloudMonitoringAlertSourceLister {
+	return eventslisters.NewCloudMonitoringAlertSourceLister(l.indexerFor(&EventsV1beta1.CloudMonitoringAlertSource{}))
+}
+
+func (l *Listers) GetCloudBillingBudgetSourceLister() eventslisters.CloudBillingBudgetSourceLister {
+	return eventslisters.NewCloudBillingBudgetSourceLister(l.indexerFor(&EventsV1beta1.CloudBillingBudgetSource{}))
+}
+
+func (l *Listers) GetSecretManagerRotationSourceLister() eventslisters.SecretManagerRotationSourceLister {
+	return eventslisters.NewSecretManagerRotationSourceLister(l.indexerFor(&EventsV1beta1.SecretManagerRotationSource{}))
+}
+
+func (l *Listers) GetCloudArtifactRegistrySourceLister() eventslisters.CloudArtifactRegistrySourceLister {
+	return eventslisters.NewCloudArtifactRegistrySourceLister(l.indexerFor(&EventsV1beta1.CloudArtifactRegistrySource{}))
+}
+
+func (l *Listers) GetWebhookSourceLister() eventslisters.WebhookSourceLister {
+	return eventslisters.NewWebhookSourceLister(l.indexerFor(&EventsV1beta1.WebhookSource{}))
+}
+
+func (l *Listers) GetSourceSetLister() eventsv1alpha1listers.SourceSetLister {
+	return eventsv1alpha1listers.NewSourceSetLister(l.indexerFor(&EventsV1alpha1.SourceSet{}))
+}
+
 func (l *Listers) GetDeploymentLister() appsv1listers.DeploymentLister {
 	return appsv1listers.NewDeploymentLister(l.indexerFor(&appsv1.Deployment{}))
 }
@@ -196,6 +232,10 @@ func (l *Listers) GetConfigMapLister() corev1listers.ConfigMapLister {
 	return corev1listers.NewConfigMapLister(l.indexerFor(&corev1.ConfigMap{}))
 }
 
+func (l *Listers) GetSecretLister() corev1listers.SecretLister {
+	return corev1listers.NewSecretLister(l.indexerFor(&corev1.Secret{}))
+}
+
 func (l *Listers) GetBrokerLister() brokerlisters.BrokerLister {
 	return brokerlisters.NewBrokerLister(l.indexerFor(&brokerv1beta1.Broker{}))
 }
@@ -208,6 +248,14 @@ func (l *Listers) GetBrokerCellLister() intlisters.BrokerCellLister {
 	return intlisters.NewBrokerCellLister(l.indexerFor(&intv1alpha1.BrokerCell{}))
 }
 
+func (l *Listers) GetEventSchemaLister() intlisters.EventSchemaLister {
+	return intlisters.NewEventSchemaLister(l.indexerFor(&intv1alpha1.EventSchema{}))
+}
+
+func (l *Listers) GetKnativeGCPLister() operatorlisters.KnativeGCPLister {
+	return operatorlisters.NewKnativeGCPLister(l.indexerFor(&operatorv1alpha1.KnativeGCP{}))
+}
+
 func (l *Listers) GetHPALister() hpav2beta2listers.HorizontalPodAutoscalerLister {
 	return hpav2beta2listers.NewHorizontalPodAutoscalerLister(l.indexerFor(&hpav2beta2.HorizontalPodAutoscaler{}))
 }
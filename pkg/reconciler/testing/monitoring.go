@@ -0,0 +1,200 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// CloudMonitoringAlertSourceOption enables further configuration of a CloudMonitoringAlertSource.
+type CloudMonitoringAlertSourceOption func(*v1beta1.CloudMonitoringAlertSource)
+
+// NewCloudMonitoringAlertSource creates a CloudMonitoringAlertSource with CloudMonitoringAlertSourceOptions
+func NewCloudMonitoringAlertSource(name, namespace string, so ...CloudMonitoringAlertSourceOption) *v1beta1.CloudMonitoringAlertSource {
+	s := &v1beta1.CloudMonitoringAlertSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-monitoring-alert-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return s
+}
+
+func WithCloudMonitoringAlertSourceSink(gvk metav1.GroupVersionKind, name string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithCloudMonitoringAlertSourceProject(project string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithCloudMonitoringAlertSourceServiceAccount(kServiceAccount string) CloudMonitoringAlertSourceOption {
+	return func(ps *v1beta1.CloudMonitoringAlertSource) {
+		ps.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithCloudMonitoringAlertSourceDeletionTimestamp(s *v1beta1.CloudMonitoringAlertSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitCloudMonitoringAlertSourceConditions initializes the CloudMonitoringAlertSources's conditions.
+func WithInitCloudMonitoringAlertSourceConditions(s *v1beta1.CloudMonitoringAlertSource) {
+	s.Status.InitializeConditions()
+}
+
+// WithCloudMonitoringAlertSourceServiceAccountName will give status.ServiceAccountName a k8s service account name, which is related on Workload Identity's Google service account.
+func WithCloudMonitoringAlertSourceServiceAccountName(name string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.ServiceAccountName = name
+	}
+}
+
+func WithCloudMonitoringAlertSourceWorkloadIdentityFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicFailed marks the condition that the
+// status of topic is False.
+func WithCloudMonitoringAlertSourceTopicFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicUnknown marks the condition that the
+// status of topic is Unknown.
+func WithCloudMonitoringAlertSourceTopicUnknown(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceTopicNotReady marks the condition that the
+// topic is not ready.
+func WithCloudMonitoringAlertSourceTopicReady(topicID, projectID string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkTopicReady(s.ConditionSet())
+		s.Status.TopicID = topicID
+		s.Status.ProjectID = projectID
+	}
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionFailed marks the condition that the
+// topic is False.
+func WithCloudMonitoringAlertSourcePullSubscriptionFailed(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkPullSubscriptionFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown.
+func WithCloudMonitoringAlertSourcePullSubscriptionUnknown(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkPullSubscriptionUnknown(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourcePullSubscriptionReady marks the condition that the
+// topic is ready.
+func WithCloudMonitoringAlertSourcePullSubscriptionReady() CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkPullSubscriptionReady(s.ConditionSet())
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelNotReady marks the condition that the
+// CloudMonitoringAlertSource notification channel is not ready.
+func WithCloudMonitoringAlertSourceNotificationChannelNotReady(reason, message string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelNotReady(reason, message)
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannelReady marks the condition that the
+// CloudMonitoringAlertSource notification channel is ready and sets Status.NotificationChannel to channel.
+func WithCloudMonitoringAlertSourceNotificationChannelReady(channel string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.MarkNotificationChannelReady(channel)
+	}
+}
+
+// WithCloudMonitoringAlertSourceSinkURI sets the status for sink URI
+func WithCloudMonitoringAlertSourceSinkURI(url *apis.URL) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.SinkURI = url
+	}
+}
+
+func WithCloudMonitoringAlertSourceSubscriptionID(subscriptionID string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.SubscriptionID = subscriptionID
+	}
+}
+
+// WithCloudMonitoringAlertSourceNotificationChannel sets the status for the notification channel
+func WithCloudMonitoringAlertSourceNotificationChannel(channel string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Status.NotificationChannel = channel
+	}
+}
+
+func WithCloudMonitoringAlertSourceFinalizers(finalizers ...string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Finalizers = finalizers
+	}
+}
+
+func WithCloudMonitoringAlertSourceAnnotations(Annotations map[string]string) CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithCloudMonitoringAlertSourceDefaultGCPAuth() CloudMonitoringAlertSourceOption {
+	return func(s *v1beta1.CloudMonitoringAlertSource) {
+		s.Spec.PubSubSpec.SetPubSubDefaults(gcpauthtesthelper.ContextWithDefaults())
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+)
+
+// ParallelOption enables further configuration of a Parallel.
+type ParallelOption func(*v1beta1.Parallel)
+
+// NewParallel creates a Parallel with ParallelOptions
+func NewParallel(name, namespace string, so ...ParallelOption) *v1beta1.Parallel {
+	p := &v1beta1.Parallel{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	for _, opt := range so {
+		opt(p)
+	}
+	p.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return p
+}
+
+func WithParallelUID(uid types.UID) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.UID = uid
+	}
+}
+
+func WithParallelSpec(spec v1beta1.ParallelSpec) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Spec = spec
+	}
+}
+
+func WithParallelAnnotations(annotations map[string]string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.ObjectMeta.Annotations = annotations
+	}
+}
+
+func WithParallelServiceAccount(kServiceAccount string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithParallelDeletionTimestamp(p *v1beta1.Parallel) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	p.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitParallelConditions initializes the Parallel's conditions.
+func WithInitParallelConditions(p *v1beta1.Parallel) {
+	p.Status.InitializeConditions()
+}
+
+func WithParallelAddress(url string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		u, _ := apis.ParseURL(url)
+		p.Status.SetAddress(u)
+	}
+}
+
+func WithParallelNoAddress(p *v1beta1.Parallel) {
+	p.Status.SetAddress(nil)
+}
+
+func WithParallelTopicStatuses(topics []*inteventsv1beta1.Topic) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Status.PropagateTopicStatuses(topics)
+	}
+}
+
+func WithParallelTopicsFailed(reason, message string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Status.MarkTopicsFailed(reason, message)
+	}
+}
+
+func WithParallelSubscriptionStatuses(subscriptions []*inteventsv1beta1.PullSubscription) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Status.PropagateSubscriptionStatuses(subscriptions)
+	}
+}
+
+func WithParallelSubscriptionsFailed(reason, message string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Status.MarkSubscriptionsFailed(reason, message)
+	}
+}
+
+func WithParallelWorkloadIdentityFailed(reason, message string) ParallelOption {
+	return func(p *v1beta1.Parallel) {
+		p.Status.MarkWorkloadIdentityFailed(p.ConditionSet(), reason, message)
+	}
+}
@@ -113,6 +113,30 @@ func SubscriptionExists(id string) func(*testing.T, *rtesting.TableRow) {
 	}
 }
 
+func SubscriptionHasRetryPolicy(id string, want *pubsub.RetryPolicy) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		config, err := c.Subscription(id).Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting subscription config: %v", err)
+		} else if got := config.RetryPolicy; (got == nil) != (want == nil) || (got != nil && *got != *want) {
+			t.Errorf("Expected subscription %q to have retry policy %v, got %v", id, want, got)
+		}
+	}
+}
+
+func SubscriptionHasLabel(id, key, want string) func(*testing.T, *rtesting.TableRow) {
+	return func(t *testing.T, r *rtesting.TableRow) {
+		c := getPubsubClient(r)
+		config, err := c.Subscription(id).Config(context.Background())
+		if err != nil {
+			t.Errorf("Error getting subscription config: %v", err)
+		} else if got := config.Labels[key]; got != want {
+			t.Errorf("Expected subscription %q to have label %s=%q, got %q", id, key, want, got)
+		}
+	}
+}
+
 func OnlySubscriptions(ids ...string) func(*testing.T, *rtesting.TableRow) {
 	return func(t *testing.T, r *rtesting.TableRow) {
 		c := getPubsubClient(r)
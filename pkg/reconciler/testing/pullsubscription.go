@@ -153,12 +153,24 @@ func WithPullSubscriptionSubscriptionID(subscriptionID string) PullSubscriptionO
 	}
 }
 
+func WithPullSubscriptionSubscriptionName(subscriptionName string) PullSubscriptionOption {
+	return func(s *v1beta1.PullSubscription) {
+		s.Status.SubscriptionName = subscriptionName
+	}
+}
+
 func WithPullSubscriptionProjectID(projectID string) PullSubscriptionOption {
 	return func(s *v1beta1.PullSubscription) {
 		s.Status.ProjectID = projectID
 	}
 }
 
+func WithPullSubscriptionCreatedTopicID(topicID string) PullSubscriptionOption {
+	return func(s *v1beta1.PullSubscription) {
+		s.Status.CreatedTopicID = topicID
+	}
+}
+
 func WithPullSubscriptionTransformerURI(uri *apis.URL) PullSubscriptionOption {
 	return func(s *v1beta1.PullSubscription) {
 		s.Status.TransformerURI = uri
@@ -177,6 +189,10 @@ func WithPullSubscriptionMarkDeployed(name, namespace string) PullSubscriptionOp
 	}
 }
 
+func WithPullSubscriptionMarkDeployedByAgent(s *v1beta1.PullSubscription) {
+	s.Status.MarkDeployedByAgent()
+}
+
 func WithPullSubscriptionMarkNoDeployed(name, namespace string) PullSubscriptionOption {
 	return func(s *v1beta1.PullSubscription) {
 		s.Status.PropagateDeploymentAvailability(NewDeployment(name, namespace))
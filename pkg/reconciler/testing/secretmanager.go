@@ -0,0 +1,163 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Veroute.on 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// SecretManagerRotationSourceOption enables further configuration of a SecretManagerRotationSource.
+type SecretManagerRotationSourceOption func(*v1beta1.SecretManagerRotationSource)
+
+// NewSecretManagerRotationSource creates a SecretManagerRotationSource with SecretManagerRotationSourceOptions
+func NewSecretManagerRotationSource(name, namespace string, so ...SecretManagerRotationSourceOption) *v1beta1.SecretManagerRotationSource {
+	bs := &v1beta1.SecretManagerRotationSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-secret-rotation-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(bs)
+	}
+	bs.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return bs
+}
+
+func WithSecretManagerRotationSourceSink(gvk metav1.GroupVersionKind, name string) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithSecretManagerRotationSourceDeletionTimestamp(s *v1beta1.SecretManagerRotationSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+func WithSecretManagerRotationSourceProject(project string) SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.Spec.Project = project
+	}
+}
+
+func WithSecretManagerRotationSourceTopic(topic string) SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.Spec.Topic = topic
+	}
+}
+
+// WithInitSecretManagerRotationSourceConditions initializes the SecretManagerRotationSource's conditions.
+func WithInitSecretManagerRotationSourceConditions(bs *v1beta1.SecretManagerRotationSource) {
+	bs.Status.InitializeConditions()
+}
+
+// WithSecretManagerRotationSourceServiceAccountName will give status.ServiceAccountName a k8s service account name, which is related on Workload Identity's Google service account.
+func WithSecretManagerRotationSourceServiceAccountName(name string) SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.Status.ServiceAccountName = name
+	}
+}
+
+func WithSecretManagerRotationSourceWorkloadIdentityFailed(reason, message string) SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
+
+// WithSecretManagerRotationSourcePullSubscriptionFailed marks the condition that the
+// status of PullSubscription is False
+func WithSecretManagerRotationSourcePullSubscriptionFailed(reason, message string) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.MarkPullSubscriptionFailed(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithSecretManagerRotationSourcePullSubscriptionUnknown marks the condition that the
+// topic is Unknown
+func WithSecretManagerRotationSourcePullSubscriptionUnknown(reason, message string) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.MarkPullSubscriptionUnknown(bs.ConditionSet(), reason, message)
+	}
+}
+
+// WithSecretManagerRotationSourcePullSubscriptionReady marks the condition that the
+// topic is not ready
+func WithSecretManagerRotationSourcePullSubscriptionReady() SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.MarkPullSubscriptionReady(bs.ConditionSet())
+	}
+}
+
+// WithSecretManagerRotationSourceSinkURI sets the status for sink URI
+func WithSecretManagerRotationSourceSinkURI(url *apis.URL) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.SinkURI = url
+	}
+}
+
+func WithSecretManagerRotationSourceSubscriptionID(subscriptionID string) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.SubscriptionID = subscriptionID
+	}
+}
+
+func WithSecretManagerRotationSourceFinalizers(finalizers ...string) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Finalizers = finalizers
+	}
+}
+
+func WithSecretManagerRotationSourceStatusObservedGeneration(generation int64) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.Status.Status.ObservedGeneration = generation
+	}
+}
+
+func WithSecretManagerRotationSourceObjectMetaGeneration(generation int64) SecretManagerRotationSourceOption {
+	return func(bs *v1beta1.SecretManagerRotationSource) {
+		bs.ObjectMeta.Generation = generation
+	}
+}
+
+func WithSecretManagerRotationSourceAnnotations(Annotations map[string]string) SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.ObjectMeta.Annotations = Annotations
+	}
+}
+
+func WithSecretManagerRotationSourceDefaultGCPAuth() SecretManagerRotationSourceOption {
+	return func(s *v1beta1.SecretManagerRotationSource) {
+		s.Spec.PubSubSpec.SetPubSubDefaults(gcpauthtesthelper.ContextWithDefaults())
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+
+	gcpauthtesthelper "github.com/google/knative-gcp/pkg/apis/configs/gcpauth/testhelper"
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/messaging/v1beta1"
+)
+
+// SequenceOption enables further configuration of a Sequence.
+type SequenceOption func(*v1beta1.Sequence)
+
+// NewSequence creates a Sequence with SequenceOptions
+func NewSequence(name, namespace string, so ...SequenceOption) *v1beta1.Sequence {
+	s := &v1beta1.Sequence{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	s.SetDefaults(gcpauthtesthelper.ContextWithDefaults())
+	return s
+}
+
+func WithSequenceUID(uid types.UID) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.UID = uid
+	}
+}
+
+func WithSequenceSpec(spec v1beta1.SequenceSpec) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Spec = spec
+	}
+}
+
+func WithSequenceAnnotations(annotations map[string]string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.ObjectMeta.Annotations = annotations
+	}
+}
+
+func WithSequenceServiceAccount(kServiceAccount string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Spec.ServiceAccountName = kServiceAccount
+	}
+}
+
+func WithSequenceDeletionTimestamp(s *v1beta1.Sequence) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
+
+// WithInitSequenceConditions initializes the Sequence's conditions.
+func WithInitSequenceConditions(s *v1beta1.Sequence) {
+	s.Status.InitializeConditions()
+}
+
+func WithSequenceAddress(url string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		u, _ := apis.ParseURL(url)
+		s.Status.SetAddress(u)
+	}
+}
+
+func WithSequenceNoAddress(s *v1beta1.Sequence) {
+	s.Status.SetAddress(nil)
+}
+
+func WithSequenceTopicStatuses(topics []*inteventsv1beta1.Topic) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Status.PropagateTopicStatuses(topics)
+	}
+}
+
+func WithSequenceTopicsFailed(reason, message string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Status.MarkTopicsFailed(reason, message)
+	}
+}
+
+func WithSequenceSubscriptionStatuses(subscriptions []*inteventsv1beta1.PullSubscription) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Status.PropagateSubscriptionStatuses(subscriptions)
+	}
+}
+
+func WithSequenceSubscriptionsFailed(reason, message string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Status.MarkSubscriptionsFailed(reason, message)
+	}
+}
+
+func WithSequenceWorkloadIdentityFailed(reason, message string) SequenceOption {
+	return func(s *v1beta1.Sequence) {
+		s.Status.MarkWorkloadIdentityFailed(s.ConditionSet(), reason, message)
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1alpha1"
+)
+
+// SourceSetOption enables further configuration of a SourceSet.
+type SourceSetOption func(*v1alpha1.SourceSet)
+
+// NewSourceSet creates a SourceSet with SourceSetOptions
+func NewSourceSet(name string, so ...SourceSetOption) *v1alpha1.SourceSet {
+	s := &v1alpha1.SourceSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  "test-sourceset-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	return s
+}
+
+func WithSourceSetNamespaceSelector(labels map[string]string) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: labels}
+	}
+}
+
+func WithSourceSetTopics(topics ...string) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Spec.Topics = topics
+	}
+}
+
+func WithSourceSetSink(gvk metav1.GroupVersionKind, name string) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Spec.Template.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithSourceSetTemplateLabels(labels map[string]string) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Spec.Template.Labels = labels
+	}
+}
+
+// WithInitSourceSetConditions initializes the SourceSet's conditions.
+func WithInitSourceSetConditions(s *v1alpha1.SourceSet) {
+	s.Status.InitializeConditions()
+}
+
+func WithSourceSetSourcesReady(sources int32) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Status.MarkSourcesReady(sources)
+	}
+}
+
+func WithSourceSetSourcesNotReady(sources, ready int32) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Status.MarkSourcesNotReady(sources, ready)
+	}
+}
+
+func WithSourceSetSourcesFailed(reason, message string) SourceSetOption {
+	return func(s *v1alpha1.SourceSet) {
+		s.Status.MarkSourcesFailed(reason, message)
+	}
+}
@@ -59,6 +59,18 @@ func WithCloudStorageSourceBucket(bucket string) CloudStorageSourceOption {
 	}
 }
 
+func WithCloudStorageSourceBuckets(buckets ...string) CloudStorageSourceOption {
+	return func(s *v1beta1.CloudStorageSource) {
+		s.Spec.Buckets = buckets
+	}
+}
+
+func WithCloudStorageSourceBucketSelector(selector v1beta1.BucketSelector) CloudStorageSourceOption {
+	return func(s *v1beta1.CloudStorageSource) {
+		s.Spec.BucketSelector = &selector
+	}
+}
+
 func WithCloudStorageSourceProject(project string) CloudStorageSourceOption {
 	return func(s *v1beta1.CloudStorageSource) {
 		s.Spec.Project = project
@@ -184,6 +196,22 @@ func WithCloudStorageSourceNotificationReady(notificationID string) CloudStorage
 	}
 }
 
+// WithCloudStorageSourceBucketNotificationsReady marks the condition that the
+// GCS Notifications of all the buckets are ready.
+func WithCloudStorageSourceBucketNotificationsReady(notifications ...v1beta1.BucketNotification) CloudStorageSourceOption {
+	return func(s *v1beta1.CloudStorageSource) {
+		s.Status.MarkBucketNotificationsReady(notifications)
+	}
+}
+
+// WithCloudStorageSourceBucketNotifications sets the status for the
+// notifications of the buckets.
+func WithCloudStorageSourceBucketNotifications(notifications ...v1beta1.BucketNotification) CloudStorageSourceOption {
+	return func(s *v1beta1.CloudStorageSource) {
+		s.Status.Buckets = notifications
+	}
+}
+
 // WithCloudStorageSourceSinkURI sets the status for sink URI
 func WithCloudStorageSourceSinkURI(url *apis.URL) CloudStorageSourceOption {
 	return func(s *v1beta1.CloudStorageSource) {
@@ -65,6 +65,12 @@ func WithTopicTopicID(topicID string) TopicOption {
 	}
 }
 
+func WithTopicTopicName(topicName string) TopicOption {
+	return func(s *v1beta1.Topic) {
+		s.Status.TopicName = topicName
+	}
+}
+
 func WithTopicPropagationPolicy(policy string) TopicOption {
 	return func(s *v1beta1.Topic) {
 		s.Spec.PropagationPolicy = v1beta1.PropagationPolicyType(policy)
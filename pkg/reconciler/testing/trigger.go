@@ -21,6 +21,7 @@ import (
 	"time"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/eventing/pkg/apis/eventing"
@@ -152,6 +153,115 @@ func WithDependencyAnnotation(dependencyAnnotation string) TriggerOption {
 	}
 }
 
+// WithTriggerOrderedDelivery opts the Trigger into ordered delivery.
+func WithTriggerOrderedDelivery(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.OrderedDeliveryAnnotation] = "true"
+}
+
+func WithTriggerDeduplicationWindow(window string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.DeduplicationWindowAnnotation] = window
+	}
+}
+
+// WithTriggerMaxAge sets the maximum age of the events delivered to the
+// Trigger's subscriber.
+func WithTriggerMaxAge(maxAge string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.MaxAgeAnnotation] = maxAge
+	}
+}
+
+// WithTriggerDryRun marks the Trigger so that its external changes are only
+// planned.
+func WithTriggerDryRun(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[duckv1beta1.DryRunAnnotation] = "true"
+}
+
+// WithTriggerDeliverySLO sets the percentage of the deliveries to the
+// Trigger's subscriber that should succeed.
+func WithTriggerDeliverySLO(percent string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.DeliverySLOAnnotation] = percent
+	}
+}
+
+// WithTriggerCEOverrides sets the CloudEvents extensions to set on the
+// events delivered to the Trigger's subscriber.
+func WithTriggerCEOverrides(overrides string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.CEOverridesAnnotation] = overrides
+	}
+}
+
+// WithTriggerTransform sets the JSONPath templates to transform the data of
+// the events delivered to the Trigger's subscriber with.
+func WithTriggerTransform(transform string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.TransformAnnotation] = transform
+	}
+}
+
+// WithTriggerSubscribers sets the additional subscribers, a JSON array of
+// destinations, the Trigger delivers events to.
+func WithTriggerSubscribers(subscribers string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.SubscribersAnnotation] = subscribers
+	}
+}
+
+// WithTriggerDeliveryHeaders sets the headers, a JSON array, set on the
+// requests delivering the Trigger's events.
+func WithTriggerDeliveryHeaders(headers string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		if t.Annotations == nil {
+			t.Annotations = make(map[string]string)
+		}
+		t.Annotations[brokerv1beta1.DeliveryHeadersAnnotation] = headers
+	}
+}
+
+// WithTriggerSequentialSubscribers has the Trigger deliver events to its
+// subscribers one after the other.
+func WithTriggerSequentialSubscribers(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.SubscribersDeliveryAnnotation] = brokerv1beta1.SubscribersDeliverySequential
+}
+
+// WithTriggerDirectDelivery opts the Trigger into direct delivery.
+func WithTriggerDirectDelivery(t *brokerv1beta1.Trigger) {
+	if t.Annotations == nil {
+		t.Annotations = make(map[string]string)
+	}
+	t.Annotations[brokerv1beta1.DirectDeliveryAnnotation] = "true"
+}
+
 func WithTriggerDependencyReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkDependencySucceeded()
 }
@@ -192,6 +302,18 @@ func WithTriggerTopicReady(t *brokerv1beta1.Trigger) {
 	t.Status.MarkTopicReady()
 }
 
+func WithTriggerDeliverySLOBurning(reason, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkDeliverySLOBurning(reason, "%s", message)
+	}
+}
+
+func WithTriggerDeliverySLOWithinBudget(reason, message string) TriggerOption {
+	return func(t *brokerv1beta1.Trigger) {
+		t.Status.MarkDeliverySLOWithinBudget(reason, "%s", message)
+	}
+}
+
 func WithTriggerDeletionTimestamp(t *brokerv1beta1.Trigger) {
 	deleteTime := metav1.NewTime(time.Unix(1e9, 0))
 	t.ObjectMeta.SetDeletionTimestamp(&deleteTime)
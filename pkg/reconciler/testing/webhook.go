@@ -0,0 +1,118 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// WebhookSourceOption enables further configuration of a WebhookSource.
+type WebhookSourceOption func(*v1beta1.WebhookSource)
+
+// NewWebhookSource creates a WebhookSource with WebhookSourceOptions
+func NewWebhookSource(name, namespace string, so ...WebhookSourceOption) *v1beta1.WebhookSource {
+	s := &v1beta1.WebhookSource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			UID:       "test-webhook-uid",
+		},
+	}
+	for _, opt := range so {
+		opt(s)
+	}
+	s.SetDefaults(context.Background())
+	return s
+}
+
+func WithWebhookSourceSink(gvk metav1.GroupVersionKind, name string) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Spec.Sink = duckv1.Destination{
+			Ref: &duckv1.KReference{
+				APIVersion: apiVersion(gvk),
+				Kind:       gvk.Kind,
+				Name:       name,
+			},
+		}
+	}
+}
+
+func WithWebhookSourceProvider(provider v1beta1.WebhookProvider) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Spec.Provider = provider
+	}
+}
+
+func WithWebhookSourceSecret(name, key string) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Spec.Auth.Secret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Key:                  key,
+		}
+	}
+}
+
+// WithInitWebhookSourceConditions initializes the WebhookSource's conditions.
+func WithInitWebhookSourceConditions(s *v1beta1.WebhookSource) {
+	s.Status.InitializeConditions()
+}
+
+// WithWebhookSourceSinkURI sets the status for sink URI
+func WithWebhookSourceSinkURI(url *apis.URL) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Status.MarkSink(url)
+	}
+}
+
+func WithWebhookSourceNoSink(reason, message string) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Status.MarkNoSink(reason, message)
+	}
+}
+
+func WithWebhookSourceReceiverFailed(reason, message string) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Status.MarkReceiverFailed(reason, message)
+	}
+}
+
+// WithWebhookSourceReceiverAvailability propagates the availability of the
+// endpoints of the receiver.
+func WithWebhookSourceReceiverAvailability(ep *corev1.Endpoints) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Status.PropagateReceiverAvailability(ep)
+	}
+}
+
+func WithWebhookSourceAddress(url *apis.URL) WebhookSourceOption {
+	return func(s *v1beta1.WebhookSource) {
+		s.Status.SetAddress(url)
+	}
+}
+
+func WithWebhookSourceDeletionTimestamp(s *v1beta1.WebhookSource) {
+	t := metav1.NewTime(time.Unix(1e9, 0))
+	s.ObjectMeta.SetDeletionTimestamp(&t)
+}
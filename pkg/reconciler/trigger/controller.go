@@ -21,8 +21,11 @@ import (
 	"os"
 
 	"cloud.google.com/go/pubsub"
+	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 
 	"knative.dev/eventing/pkg/apis/eventing"
@@ -31,18 +34,25 @@ import (
 	"knative.dev/eventing/pkg/logging"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/addressable"
 	"knative.dev/pkg/client/injection/ducks/duck/v1/conditions"
+	configmapinformer "knative.dev/pkg/client/injection/kube/informers/core/v1/configmap"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	pkgcontroller "knative.dev/pkg/controller"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/slo"
 	brokerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/broker"
 	triggerinformer "github.com/google/knative-gcp/pkg/client/injection/informers/broker/v1beta1/trigger"
+	brokercellinformer "github.com/google/knative-gcp/pkg/client/injection/informers/intevents/v1alpha1/brokercell"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	"github.com/google/knative-gcp/pkg/utils"
 )
 
@@ -54,11 +64,26 @@ const (
 	finalizerName = "googlecloud"
 )
 
+type envConfig struct {
+	// SubscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// retry subscriptions of the triggers.
+	psresources.SubscriptionNaming
+}
+
 // filterBroker is the function to filter brokers with proper brokerclass.
 var filterBroker = pkgreconciler.AnnotationFilterFunc(eventingv1beta1.BrokerClassAnnotationKey, brokerv1beta1.BrokerClass, false /*allowUnset*/)
 
 func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
 	triggerInformer := triggerinformer.Get(ctx)
+	configMapInformer := configmapinformer.Get(ctx)
+
+	var env envConfig
+	if err := envconfig.Process("", &env); err != nil {
+		logging.FromContext(ctx).Fatal("Failed to process env var", zap.Error(err))
+	}
+	if err := env.SubscriptionNaming.Validate(); err != nil {
+		logging.FromContext(ctx).Fatal("Invalid Pub/Sub subscription naming", zap.Error(err))
+	}
 
 	// If there is an error, the projectID will be empty. The reconciler will retry
 	// to get the projectID during reconciliation.
@@ -82,10 +107,14 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	}
 
 	r := &Reconciler{
-		Base:         reconciler.NewBase(ctx, controllerAgentName, cmw),
-		brokerLister: brokerinformer.Get(ctx).Lister(),
-		pubsubClient: client,
-		projectID:    projectID,
+		Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
+		brokerLister:       brokerinformer.Get(ctx).Lister(),
+		brokerCellLister:   brokercellinformer.Get(ctx).Lister(),
+		configMapLister:    configMapInformer.Lister(),
+		pubsubClient:       client,
+		createLiteClientFn: gpubsublite.NewAdminClient,
+		subscriptionNaming: env.SubscriptionNaming,
+		projectID:          projectID,
 	}
 
 	impl := triggerreconciler.NewImpl(ctx, r, withAgentAndFinalizer)
@@ -117,6 +146,58 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		},
 	)
 
+	// Reconcile the triggers whose deliveries are, or were, reported by the
+	// data plane when their burn state flips, so that their delivery SLO
+	// condition follows the counts without reconciling every reported trigger
+	// on each write.
+	enqueueSLOTriggers := func(cm *corev1.ConfigMap, triggers []types.NamespacedName) {
+		seen := make(map[types.NamespacedName]bool, len(triggers))
+		for _, key := range triggers {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			t, err := triggerInformer.Lister().Triggers(key.Namespace).Get(key.Name)
+			if err != nil {
+				continue
+			}
+			if deliverySLOChanged(cm, t) {
+				impl.EnqueueKey(key)
+			}
+		}
+	}
+	configMapInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(system.Namespace(), slo.ConfigMapName),
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if cm, ok := obj.(*corev1.ConfigMap); ok {
+					enqueueSLOTriggers(cm, slo.Triggers(cm))
+				}
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				oldCM, ok := oldObj.(*corev1.ConfigMap)
+				if !ok {
+					return
+				}
+				newCM, ok := newObj.(*corev1.ConfigMap)
+				if !ok {
+					return
+				}
+				enqueueSLOTriggers(newCM, append(slo.Triggers(oldCM), slo.Triggers(newCM)...))
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if cm, ok := obj.(*corev1.ConfigMap); ok {
+					// Without the ConfigMap there are no counts to burn the
+					// error budget.
+					enqueueSLOTriggers(nil, slo.Triggers(cm))
+				}
+			},
+		},
+	})
+
 	return impl
 }
 
@@ -126,7 +207,7 @@ func newPubsubClient(ctx context.Context, projectID string) (*pubsub.Client, err
 		return nil, err
 	}
 
-	client, err := pubsub.NewClient(ctx, projectID)
+	client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 	if err != nil {
 		return nil, err
 	}
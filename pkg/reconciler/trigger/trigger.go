@@ -19,25 +19,39 @@ package trigger
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
-	"go.uber.org/multierr"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	corev1listers "k8s.io/client-go/listers/core/v1"
 
 	"knative.dev/eventing/pkg/duck"
 	"knative.dev/eventing/pkg/logging"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	pkgreconciler "knative.dev/pkg/reconciler"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	"cloud.google.com/go/pubsub"
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/config"
+	"github.com/google/knative-gcp/pkg/broker/slo"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
 	brokerlisters "github.com/google/knative-gcp/pkg/client/listers/broker/v1beta1"
+	inteventslisters "github.com/google/knative-gcp/pkg/client/listers/intevents/v1alpha1"
+	"github.com/google/knative-gcp/pkg/gclient/endpoints"
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
 	"github.com/google/knative-gcp/pkg/reconciler"
 	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	reconcilerutilspubsub "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub"
 	"github.com/google/knative-gcp/pkg/utils"
 	"knative.dev/eventing/pkg/apis/eventing/v1beta1"
@@ -47,13 +61,28 @@ const (
 	// Name of the corev1.Events emitted from the Trigger reconciliation process.
 	triggerReconciled = "TriggerReconciled"
 	triggerFinalized  = "TriggerFinalized"
+
+	// brokerCellLabel is the label of the retry subscriptions holding the
+	// name of the BrokerCell serving their trigger, so that the retry pods of
+	// each BrokerCell autoscale on the backlog of their own subscriptions.
+	brokerCellLabel = "brokercell"
 )
 
+// retrySubscriptionRetryPolicy is the retry policy of the retry
+// subscriptions. The retry deployment nacks the requeued events that are not
+// due yet, and Pub/Sub redelivers them after this backoff.
+var retrySubscriptionRetryPolicy = &pubsub.RetryPolicy{
+	MinimumBackoff: config.RetrySubscriptionMinBackoff,
+	MaximumBackoff: config.RetrySubscriptionMaxBackoff,
+}
+
 // Reconciler implements controller.Reconciler for Trigger resources.
 type Reconciler struct {
 	*reconciler.Base
 
-	brokerLister brokerlisters.BrokerLister
+	brokerLister     brokerlisters.BrokerLister
+	brokerCellLister inteventslisters.BrokerCellLister
+	configMapLister  corev1listers.ConfigMapLister
 
 	// Dynamic tracker to track KResources. It tracks the dependency between Triggers and Sources.
 	kresourceTracker duck.ListableTracker
@@ -66,6 +95,14 @@ type Reconciler struct {
 
 	// pubsubClient is used as the Pubsub client when present.
 	pubsubClient *pubsub.Client
+
+	// createLiteClientFn creates the Pub/Sub Lite clients of the triggers
+	// of brokers whose queues are on Pub/Sub Lite.
+	createLiteClientFn gpubsublite.CreateFn
+
+	// subscriptionNaming is the prefix and suffix of the IDs of the Pub/Sub
+	// retry subscriptions.
+	subscriptionNaming psresources.SubscriptionNaming
 }
 
 // Check that TriggerReconciler implements Interface
@@ -106,7 +143,19 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		return err
 	}
 
-	if err := r.reconcileRetryTopicAndSubscription(ctx, t); err != nil {
+	// The retry queue is on Pub/Sub Lite along with the decouple queue of
+	// the broker.
+	location, err := resources.LiteLocation(b)
+	if err != nil {
+		t.Status.MarkTopicFailed("InvalidLiteLocation", "%v", err)
+		t.Status.MarkSubscriptionFailed("InvalidLiteLocation", "%v", err)
+		return err
+	}
+	bcs, err := r.brokerCellLister.BrokerCells(system.Namespace()).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	if err := r.reconcileRetryTopicAndSubscription(ctx, t, location, resources.BrokerCellName(bcs, b)); err != nil {
 		return err
 	}
 
@@ -114,9 +163,82 @@ func (r *Reconciler) reconcile(ctx context.Context, t *brokerv1beta1.Trigger, b
 		return err
 	}
 
+	r.reconcileDeliverySLO(ctx, t)
+
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerReconciled, "Trigger reconciled: \"%s/%s\"", t.Namespace, t.Name)
 }
 
+// reconcileDeliverySLO sets the delivery SLO condition of the trigger from the
+// delivery counts reported by the data plane in the SLO status ConfigMap.
+func (r *Reconciler) reconcileDeliverySLO(ctx context.Context, t *brokerv1beta1.Trigger) {
+	v, ok := t.Annotations[brokerv1beta1.DeliverySLOAnnotation]
+	if !ok {
+		t.Status.MarkDeliverySLONotConfigured()
+		return
+	}
+	percent, ok := deliverySLOObjective(t)
+	if !ok {
+		t.Status.MarkDeliverySLONotConfigured()
+		logging.FromContext(ctx).Warn("Ignoring invalid delivery SLO", zap.String("slo", v))
+		return
+	}
+
+	cm, err := r.configMapLister.ConfigMaps(system.Namespace()).Get(slo.ConfigMapName)
+	if err != nil && !apierrs.IsNotFound(err) {
+		// Keep the current condition rather than flapping on a transient error.
+		logging.FromContext(ctx).Error("Problem getting the SLO status", zap.Error(err))
+		return
+	}
+	// A missing ConfigMap means there were no deliveries to report.
+	rates := evaluateDeliverySLO(cm, t, percent)
+	if rates.Burning() {
+		t.Status.MarkDeliverySLOBurning("ErrorBudgetBurning",
+			"Deliveries burn the error budget of the %v%% objective %.1fx too fast over %v and %.1fx over %v",
+			percent, rates.Short, slo.ShortWindow, rates.Long, slo.LongWindow)
+		return
+	}
+	t.Status.MarkDeliverySLOWithinBudget("WithinBudget",
+		"Deliveries don't burn the error budget of the %v%% objective %vx too fast over both %v and %v",
+		percent, slo.BurnRateThreshold, slo.ShortWindow, slo.LongWindow)
+}
+
+// deliverySLOObjective returns the delivery SLO of the trigger in percent, and
+// whether the trigger has a valid one.
+func deliverySLOObjective(t *brokerv1beta1.Trigger) (float64, bool) {
+	v, ok := t.Annotations[brokerv1beta1.DeliverySLOAnnotation]
+	if !ok {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(v, 64)
+	if err != nil || percent <= 0 || percent >= 100 {
+		return 0, false
+	}
+	return percent, true
+}
+
+// evaluateDeliverySLO returns the burn rates of the trigger's objective from
+// the unexpired counts in the ConfigMap.
+func evaluateDeliverySLO(cm *corev1.ConfigMap, t *brokerv1beta1.Trigger, percent float64) slo.BurnRates {
+	return slo.Evaluate(cm, types.NamespacedName{Namespace: t.Namespace, Name: t.Name}, percent/100, time.Now().Add(-slo.ReportExpiry))
+}
+
+// deliverySLOChanged returns whether the DeliverySLOBurning condition of the
+// trigger doesn't match the counts in the ConfigMap, so that only the triggers
+// whose burn state flips are reconciled when the data plane writes its counts.
+func deliverySLOChanged(cm *corev1.ConfigMap, t *brokerv1beta1.Trigger) bool {
+	percent, ok := deliverySLOObjective(t)
+	if !ok {
+		// The condition of triggers without an objective doesn't depend on
+		// the counts.
+		return false
+	}
+	cond := t.Status.GetCondition(brokerv1beta1.TriggerConditionDeliverySLO)
+	if cond == nil || cond.IsUnknown() {
+		return true
+	}
+	return cond.IsTrue() != evaluateDeliverySLO(cm, t, percent).Burning()
+}
+
 // FinalizeKind frees GCP Broker related resources for this Trigger if applicable. It's called when:
 // 1) the Trigger is being deleted;
 // 2) the Broker of this Trigger is deleted;
@@ -129,7 +251,7 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, t *brokerv1beta1.Trigger)
 	if !hasGCPBrokerFinalizer(t) {
 		return nil
 	}
-	if err := r.deleteRetryTopicAndSubscription(ctx, t); err != nil {
+	if err := r.deleteRetryTopicAndSubscription(ctx, t, r.liteLocation(t)); err != nil {
 		return err
 	}
 	return pkgreconciler.NewEvent(corev1.EventTypeNormal, triggerFinalized, "Trigger finalized: \"%s/%s\"", t.Namespace, t.Name)
@@ -150,6 +272,33 @@ func (r *Reconciler) resolveSubscriber(ctx context.Context, t *brokerv1beta1.Tri
 		return err
 	}
 	t.Status.SubscriberURI = subscriberURI
+
+	// The broker reconciler resolves the additional subscribers for the
+	// fanout; here they are only checked so that failures show in the status.
+	subscribers, err := resources.Subscribers(t)
+	if err != nil {
+		t.Status.MarkSubscriberResolvedFailed("Unable to parse the additional Subscribers", "%v", err)
+		return err
+	}
+	for _, s := range subscribers {
+		if _, err := r.uriResolver.URIFromDestinationV1(s, b); err != nil {
+			logging.FromContext(ctx).Error("Unable to get an additional Subscriber's URI", zap.Error(err))
+			t.Status.MarkSubscriberResolvedFailed("Unable to get an additional Subscriber's URI", "%v", err)
+			return err
+		}
+	}
+	if _, err := resources.MaxAgeSeconds(t); err != nil {
+		t.Status.MarkSubscriberResolvedFailed("Unable to parse the max age", "%v", err)
+		return err
+	}
+	if _, err := resources.CEOverrides(t); err != nil {
+		t.Status.MarkSubscriberResolvedFailed("Unable to parse the CloudEvents overrides", "%v", err)
+		return err
+	}
+	if _, err := resources.Transform(t); err != nil {
+		t.Status.MarkSubscriberResolvedFailed("Unable to parse the transform", "%v", err)
+		return err
+	}
 	t.Status.MarkSubscriberResolvedSucceeded()
 
 	return nil
@@ -165,7 +314,23 @@ func hasGCPBrokerFinalizer(t *brokerv1beta1.Trigger) bool {
 	return false
 }
 
-func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger) error {
+// liteLocation returns the Pub/Sub Lite location of the retry queue of the
+// trigger, which is that of the queues of its broker. Once the broker is gone,
+// the broker reconciler has deleted the Pub/Sub Lite retry queues of its
+// triggers.
+func (r *Reconciler) liteLocation(t *brokerv1beta1.Trigger) string {
+	b, err := r.brokerLister.Brokers(t.Namespace).Get(t.Spec.Broker)
+	if err != nil || !filterBroker(b) {
+		return ""
+	}
+	location, _ := resources.LiteLocation(b)
+	return location
+}
+
+// reconcileRetryTopicAndSubscription creates the retry topic and pullsub of
+// the trigger, on Pub/Sub Lite in location if it is not empty. The pullsub is
+// labeled with brokerCell, the BrokerCell whose retry pods pull it.
+func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, location, brokerCell string) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Reconciling retry topic")
 	// get ProjectID from metadata
@@ -181,9 +346,23 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 	//TODO uncomment when eventing webhook allows this
 	//trig.Status.ProjectID = projectID
 
+	if location != "" {
+		client, err := r.createLiteClientFn(ctx, location)
+		if err != nil {
+			logger.Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+			trig.Status.MarkTopicUnknown("PubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+			trig.Status.MarkSubscriptionUnknown("PubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+			return err
+		}
+		defer client.Close()
+		topicPath := gpubsublite.TopicPath(projectID, location, resources.GenerateRetryTopicName(trig))
+		subPath := gpubsublite.SubscriptionPath(projectID, location, resources.GenerateRetrySubscriptionName(trig))
+		return reconcilerutilspubsub.NewLiteReconciler(client, r.Recorder).ReconcileTopicAndSubscription(ctx, topicPath, subPath, trig, &trig.Status, &trig.Status.Status)
+	}
+
 	client := r.pubsubClient
 	if client == nil {
-		client, err := pubsub.NewClient(ctx, projectID)
+		client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 		if err != nil {
 			logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 			trig.Status.MarkTopicUnknown("PubSubClientCreationFailed", "Failed to create Pub/Sub client: %w", err)
@@ -214,15 +393,33 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 	//trig.Status.TopicID = topic.ID()
 
 	// Check if PullSub exists, and if not, create it.
-	subID := resources.GenerateRetrySubscriptionName(trig)
+	subID, err := r.retrySubscriptionID(ctx, client, trig)
+	if err != nil {
+		logger.Error("Failed to generate the Pub/Sub subscription ID", zap.Error(err))
+		trig.Status.MarkSubscriptionFailed("InvalidSubscriptionID", "%v", err)
+		return err
+	}
+	subLabels := make(map[string]string, len(labels)+1)
+	for k, v := range labels {
+		subLabels[k] = v
+	}
+	subLabels[brokerCellLabel] = brokerCell
 	subConfig := pubsub.SubscriptionConfig{
-		Topic:  topic,
-		Labels: labels,
+		Topic:       topic,
+		Labels:      subLabels,
+		RetryPolicy: retrySubscriptionRetryPolicy,
 		//TODO(grantr): configure these settings?
 		// AckDeadline
 		// RetentionDuration
 	}
-	if _, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, trig, &trig.Status); err != nil {
+	sub, err := pubsubReconciler.ReconcileSubscription(ctx, subID, subConfig, trig, &trig.Status)
+	if err != nil {
+		return err
+	}
+	// Retry subscriptions created without a retry policy redeliver the
+	// nacked events right away, and those created without the BrokerCell
+	// label are left out of the backlog the retry pods autoscale on.
+	if err := r.reconcileRetrySubscription(ctx, sub, trig, brokerCell); err != nil {
 		return err
 	}
 	// TODO(grantr): this isn't actually persisted due to webhook issues.
@@ -232,7 +429,55 @@ func (r *Reconciler) reconcileRetryTopicAndSubscription(ctx context.Context, tri
 	return nil
 }
 
-func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger) error {
+// reconcileRetrySubscription sets the retry policy and the BrokerCell label of
+// the retry subscription if they differ. In a dry run the update is only
+// planned.
+func (r *Reconciler) reconcileRetrySubscription(ctx context.Context, sub *pubsub.Subscription, trig *brokerv1beta1.Trigger, brokerCell string) error {
+	config, err := sub.Config(ctx)
+	if status.Code(err) == codes.NotFound && reconciler.DryRun(trig) {
+		// The creation of the subscription with the policy is already planned.
+		return nil
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("Failed to get Pub/Sub subscription Config", zap.Error(err))
+		trig.Status.MarkSubscriptionUnknown("SubscriptionConfigUnknown", "Failed to get Pub/Sub subscription Config: %w", err)
+		return err
+	}
+	var update pubsub.SubscriptionConfigToUpdate
+	var changed []string
+	if rp := config.RetryPolicy; rp == nil || *rp != *retrySubscriptionRetryPolicy {
+		update.RetryPolicy = retrySubscriptionRetryPolicy
+		changed = append(changed, "retry policy")
+	}
+	if config.Labels[brokerCellLabel] != brokerCell {
+		// The other labels, e.g. the fencing token, are kept.
+		update.Labels = make(map[string]string, len(config.Labels)+1)
+		for k, v := range config.Labels {
+			update.Labels[k] = v
+		}
+		update.Labels[brokerCellLabel] = brokerCell
+		changed = append(changed, "labels")
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	what := strings.Join(changed, " and ")
+	if reconciler.DryRun(trig) {
+		planned := reconciler.PlannedChange(ctx, "Would update the %s of Pub/Sub subscription %q", what, sub.ID())
+		r.Recorder.Event(trig, corev1.EventTypeNormal, reconciler.DryRunReason, planned.Error())
+		return nil
+	}
+	if _, err := sub.Update(ctx, update); err != nil {
+		logging.FromContext(ctx).Error("Failed to update the Pub/Sub subscription", zap.String("fields", what), zap.Error(err))
+		trig.Status.MarkSubscriptionFailed("SubscriptionUpdateFailed", "Failed to update the %s of the Pub/Sub subscription: %w", what, err)
+		return err
+	}
+	return nil
+}
+
+// deleteRetryTopicAndSubscription deletes the retry topic and pullsub of the
+// trigger, on Pub/Sub Lite in location if it is not empty.
+func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *brokerv1beta1.Trigger, location string) error {
 	logger := logging.FromContext(ctx)
 	logger.Debug("Deleting retry topic")
 
@@ -246,9 +491,23 @@ func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *
 		return err
 	}
 
+	if location != "" {
+		client, err := r.createLiteClientFn(ctx, location)
+		if err != nil {
+			logger.Error("Failed to create Pub/Sub Lite client", zap.Error(err))
+			trig.Status.MarkTopicUnknown("FinalizeTopicPubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+			trig.Status.MarkSubscriptionUnknown("FinalizeSubscriptionPubSubLiteClientCreationFailed", "Failed to create Pub/Sub Lite client: %w", err)
+			return err
+		}
+		defer client.Close()
+		topicPath := gpubsublite.TopicPath(projectID, location, resources.GenerateRetryTopicName(trig))
+		subPath := gpubsublite.SubscriptionPath(projectID, location, resources.GenerateRetrySubscriptionName(trig))
+		return reconcilerutilspubsub.NewLiteReconciler(client, r.Recorder).DeleteTopicAndSubscription(ctx, topicPath, subPath, trig, &trig.Status, &trig.Status.Status)
+	}
+
 	client := r.pubsubClient
 	if client == nil {
-		client, err := pubsub.NewClient(ctx, projectID)
+		client, err := pubsub.NewClient(ctx, projectID, endpoints.PubSub(ctx)...)
 		if err != nil {
 			logger.Error("Failed to create Pub/Sub client", zap.Error(err))
 			trig.Status.MarkTopicUnknown("FinalizeTopicPubSubClientCreationFailed", "Failed to create Pub/Sub client: %w", err)
@@ -259,14 +518,40 @@ func (r *Reconciler) deleteRetryTopicAndSubscription(ctx context.Context, trig *
 	}
 	pubsubReconciler := reconcilerutilspubsub.NewReconciler(client, r.Recorder)
 
-	// Delete topic if it exists. Pull subscriptions continue pulling from the
-	// topic until deleted themselves.
+	// Delete topic and pull subscription if they exist. Pull subscriptions
+	// continue pulling from the topic until deleted themselves.
 	topicID := resources.GenerateRetryTopicName(trig)
-	err = multierr.Append(nil, pubsubReconciler.DeleteTopic(ctx, topicID, trig, &trig.Status))
-	// Delete pull subscription if it exists.
-	subID := resources.GenerateRetrySubscriptionName(trig)
-	err = multierr.Append(nil, pubsubReconciler.DeleteSubscription(ctx, subID, trig, &trig.Status))
-	return err
+	subID, err := r.retrySubscriptionID(ctx, client, trig)
+	if err != nil {
+		// No subscription could have been created with an invalid ID.
+		subID = resources.GenerateRetrySubscriptionName(trig)
+	}
+	return pubsubReconciler.DeleteTopicAndSubscription(ctx, topicID, subID, trig, &trig.Status)
+}
+
+// retrySubscriptionID returns the ID of the Pub/Sub retry subscription of the
+// trigger. A trigger whose subscription was created before the subscription
+// naming was set keeps it, so that its events are not lost.
+func (r *Reconciler) retrySubscriptionID(ctx context.Context, client *pubsub.Client, trig *brokerv1beta1.Trigger) (string, error) {
+	id, err := resources.RetrySubscriptionID(r.subscriptionNaming, trig)
+	if err != nil {
+		return "", err
+	}
+	generated := resources.GenerateRetrySubscriptionName(trig)
+	if id == generated {
+		return id, nil
+	}
+	if exists, err := client.Subscription(id).Exists(ctx); err != nil || exists {
+		return id, err
+	}
+	exists, err := client.Subscription(generated).Exists(ctx)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return generated, nil
+	}
+	return id, nil
 }
 
 func (r *Reconciler) checkDependencyAnnotation(ctx context.Context, t *brokerv1beta1.Trigger, b *brokerv1beta1.Broker) error {
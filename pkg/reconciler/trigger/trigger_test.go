@@ -18,8 +18,10 @@ package trigger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,11 +38,15 @@ import (
 	logtesting "knative.dev/pkg/logging/testing"
 	. "knative.dev/pkg/reconciler/testing"
 	"knative.dev/pkg/resolver"
+	"knative.dev/pkg/system"
 
 	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/pkg/broker/slo"
 	"github.com/google/knative-gcp/pkg/client/injection/ducks/duck/v1alpha1/resource"
 	triggerreconciler "github.com/google/knative-gcp/pkg/client/injection/reconciler/broker/v1beta1/trigger"
+	gpubsublitetesting "github.com/google/knative-gcp/pkg/gclient/pubsublite/testing"
 	"github.com/google/knative-gcp/pkg/reconciler"
+	psresources "github.com/google/knative-gcp/pkg/reconciler/intevents/pullsubscription/resources"
 	. "github.com/google/knative-gcp/pkg/reconciler/testing"
 )
 
@@ -56,6 +62,8 @@ const (
 	subscriberName    = "subscriber-name"
 	subscriberGroup   = "serving.knative.dev"
 	subscriberVersion = "v1"
+
+	withinBudgetMessage = "Deliveries don't burn the error budget of the 99.9% objective 14.4x too fast over both 5m0s and 1h0m0s"
 )
 
 var (
@@ -64,8 +72,8 @@ var (
 	triggerFinalizerUpdatedEvent = Eventf(corev1.EventTypeNormal, "FinalizerUpdate", `Updated "test-trigger" finalizers`)
 	triggerReconciledEvent       = Eventf(corev1.EventTypeNormal, "TriggerReconciled", `Trigger reconciled: "testnamespace/test-trigger"`)
 	triggerFinalizedEvent        = Eventf(corev1.EventTypeNormal, "TriggerFinalized", `Trigger finalized: "testnamespace/test-trigger"`)
-	topicCreatedEvent            = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`)
-	subscriptionCreatedEvent     = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`)
+	topicCreatedEvent            = Eventf(corev1.EventTypeNormal, "TopicCreated", `Created PubSub topic "projects/test-project-id/topics/cre-tgr_testnamespace_test-trigger_abc123": https://console.cloud.google.com/cloudpubsub/topic/detail/cre-tgr_testnamespace_test-trigger_abc123?project=test-project-id`)
+	subscriptionCreatedEvent     = Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/cre-tgr_testnamespace_test-trigger_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-tgr_testnamespace_test-trigger_abc123?project=test-project-id`)
 	subscriberAPIVersion         = fmt.Sprintf("%s/%s", subscriberGroup, subscriberVersion)
 	subscriberGVK                = metav1.GroupVersionKind{
 		Group:   subscriberGroup,
@@ -123,8 +131,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerFinalizers(finalizerName)),
 			},
 			WantEvents: []string{
-				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`),
-				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
 				triggerFinalizerUpdatedEvent,
 				triggerFinalizedEvent,
 			},
@@ -146,8 +154,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerFinalizers(finalizerName)),
 			},
 			WantEvents: []string{
-				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`),
-				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
 				triggerFinalizedEvent,
 			},
 			OtherTestData: map[string]interface{}{
@@ -167,8 +175,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerFinalizers(finalizerName)),
 			},
 			WantEvents: []string{
-				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`),
-				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
 				triggerFinalizedEvent,
 			},
 			OtherTestData: map[string]interface{}{
@@ -187,8 +195,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithTriggerFinalizers(finalizerName)),
 			},
 			WantEvents: []string{
-				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "cre-tgr_testnamespace_test-trigger_abc123"`),
-				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted PubSub topic "projects/test-project-id/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted PubSub subscription "projects/test-project-id/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
 				triggerFinalizedEvent,
 			},
 			OtherTestData: map[string]interface{}{
@@ -244,7 +252,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
-					WithBrokerConfigReady),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
 					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
@@ -267,6 +276,150 @@ func TestAllCasesTrigger(t *testing.T) {
 			},
 			WantErr: true,
 		},
+		{
+			Name: "Additional subscriber doesn't exist",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSubscribers(`[{"ref":{"apiVersion":"serving.knative.dev/v1","kind":"Service","name":"missing"}}]`)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerSubscribers(`[{"ref":{"apiVersion":"serving.knative.dev/v1","kind":"Service","name":"missing"}}]`),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedFailed("Unable to get an additional Subscriber's URI", `failed to get ref &ObjectReference{Kind:Service,Namespace:testnamespace,Name:missing,UID:,APIVersion:serving.knative.dev/v1,ResourceVersion:,FieldPath:,}: services.serving.knative.dev "missing" not found`),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", `failed to get ref &ObjectReference{Kind:Service,Namespace:testnamespace,Name:missing,UID:,APIVersion:serving.knative.dev/v1,ResourceVersion:,FieldPath:,}: services.serving.knative.dev "missing" not found`),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Malformed max age",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerMaxAge("forever")),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerMaxAge("forever"),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedFailed("Unable to parse the max age", `invalid internal.events.cloud.google.com/max-age annotation: time: invalid duration "forever"`),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", `invalid internal.events.cloud.google.com/max-age annotation: time: invalid duration "forever"`),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Invalid CloudEvents overrides",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCEOverrides(`{"source":"somewhere"}`)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerCEOverrides(`{"source":"somewhere"}`),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedFailed("Unable to parse the CloudEvents overrides", "invalid internal.events.cloud.google.com/ce-overrides annotation: \"source\" is a context attribute"),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", "invalid internal.events.cloud.google.com/ce-overrides annotation: \"source\" is a context attribute"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			WantErr: true,
+		},
+		{
+			Name: "Invalid transform",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerTransform(`{"user":"event.data.user."}`)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerTransform(`{"user":"event.data.user."}`),
+					WithInitTriggerConditions,
+					WithTriggerBrokerReady,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerSubscriberResolvedFailed("Unable to parse the transform", "invalid internal.events.cloud.google.com/transform annotation: invalid expression for field \"user\": column 17: Syntax error: missing IDENTIFIER at '<EOF>'"),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeWarning, "InternalError", "invalid internal.events.cloud.google.com/transform annotation: invalid expression for field \"user\": column 17: Syntax error: missing IDENTIFIER at '<EOF>'"),
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			WantErr: true,
+		},
 		{
 			Name: "Trigger created, broker ready, subscriber is addressable",
 			Key:  testKey,
@@ -275,7 +428,8 @@ func TestAllCasesTrigger(t *testing.T) {
 					WithBrokerClass(brokerv1beta1.BrokerClass),
 					WithInitBrokerConditions,
 					WithBrokerReady("url"),
-					WithBrokerConfigReady),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
 				makeSubscriberAddressableAsUnstructured(),
 				NewTrigger(triggerName, testNS, brokerName,
 					WithTriggerUID(testUID),
@@ -306,6 +460,344 @@ func TestAllCasesTrigger(t *testing.T) {
 			PostConditions: []func(*testing.T, *TableRow){
 				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123"),
 				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123", retrySubscriptionRetryPolicy),
+				SubscriptionHasLabel("cre-tgr_testnamespace_test-trigger_abc123", brokerCellLabel, "default"),
+			},
+		},
+		{
+			Name: "Retry subscription without a retry policy",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123", retrySubscriptionRetryPolicy),
+			},
+		},
+		{
+			Name: "Trigger created with a subscription naming",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created PubSub subscription "projects/test-project-id/subscriptions/prod-testnamespace-cre-tgr_testnamespace_test-trigger_abc123": https://console.cloud.google.com/cloudpubsub/subscription/detail/prod-testnamespace-cre-tgr_testnamespace_test-trigger_abc123?project=test-project-id`),
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"naming": psresources.SubscriptionNaming{Prefix: "prod-{{.Namespace}}-"},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics("cre-tgr_testnamespace_test-trigger_abc123"),
+				OnlySubscriptions("prod-testnamespace-cre-tgr_testnamespace_test-trigger_abc123"),
+			},
+		},
+		{
+			Name: "Subscription naming set after the retry subscription was created",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"naming": psresources.SubscriptionNaming{Prefix: "prod-{{.Namespace}}-"},
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlySubscriptions("cre-tgr_testnamespace_test-trigger_abc123"),
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123", retrySubscriptionRetryPolicy),
+			},
+		},
+		{
+			Name: "Retry subscription without a retry policy, dry run",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerDryRun,
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerDryRun,
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeNormal, "DryRun", `Would update the retry policy and labels of Pub/Sub subscription "cre-tgr_testnamespace_test-trigger_abc123"`),
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"pre": []PubsubAction{
+					TopicAndSub("cre-tgr_testnamespace_test-trigger_abc123", "cre-tgr_testnamespace_test-trigger_abc123"),
+				},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				SubscriptionHasRetryPolicy("cre-tgr_testnamespace_test-trigger_abc123", nil),
+			},
+		},
+		{
+			Name: "Trigger with a delivery SLO, no deliveries reported",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySLO("99.9")),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySLO("99.9"),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerDeliverySLOWithinBudget("WithinBudget", withinBudgetMessage),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{},
+		},
+		{
+			Name: "Trigger with a delivery SLO, error budget burning",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySLO("99.9")),
+				sloConfigMap(slo.Sample{
+					Trigger: testKey,
+					Short:   slo.Counts{Total: 100, Failed: 50},
+					Long:    slo.Counts{Total: 1000, Failed: 20},
+				}),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerDeliverySLO("99.9"),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+					WithTriggerDeliverySLOBurning("ErrorBudgetBurning",
+						"Deliveries burn the error budget of the 99.9% objective 500.0x too fast over 5m0s and 20.0x over 1h0m0s"),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				topicCreatedEvent,
+				subscriptionCreatedEvent,
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{},
+		},
+		{
+			Name: "Trigger created, broker on Pub/Sub Lite, Pub/Sub Lite retry queue is created",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerLiteLocation("us-central1-a"),
+					WithInitBrokerConditions,
+					WithBrokerReady("url"),
+					WithBrokerConfigReady,
+					WithBrokerPublishReady),
+				makeSubscriberAddressableAsUnstructured(),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS)),
+			},
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerUID(testUID),
+					WithTriggerSubscriberRef(subscriberGVK, subscriberName, testNS),
+					WithTriggerBrokerReady,
+					WithTriggerSubscriptionReady,
+					WithTriggerTopicReady,
+					WithTriggerDependencyReady,
+					WithTriggerSubscriberResolvedSucceeded,
+					WithTriggerStatusSubscriberURI(subscriberURI),
+				),
+			}},
+			WantEvents: []string{
+				triggerFinalizerUpdatedEvent,
+				Eventf(corev1.EventTypeNormal, "TopicCreated", `Created Pub/Sub Lite topic "projects/test-project-id/locations/us-central1-a/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "SubscriptionCreated", `Created Pub/Sub Lite subscription "projects/test-project-id/locations/us-central1-a/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
+				triggerReconciledEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchFinalizers(testNS, triggerName, finalizerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"lite": gpubsublitetesting.TestAdminClientData{},
+			},
+			PostConditions: []func(*testing.T, *TableRow){
+				OnlyTopics(),
+				OnlySubscriptions(),
+			},
+		},
+		{
+			Name: "Trigger of a broker on Pub/Sub Lite is being deleted, Pub/Sub Lite retry queue is deleted",
+			Key:  testKey,
+			Objects: []runtime.Object{
+				NewBroker(brokerName, testNS,
+					WithBrokerClass(brokerv1beta1.BrokerClass),
+					WithBrokerLiteLocation("us-central1-a")),
+				NewTrigger(triggerName, testNS, brokerName,
+					WithTriggerDeletionTimestamp,
+					WithTriggerUID(testUID),
+					WithTriggerFinalizers(finalizerName)),
+			},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeNormal, "SubscriptionDeleted", `Deleted Pub/Sub Lite subscription "projects/test-project-id/locations/us-central1-a/subscriptions/cre-tgr_testnamespace_test-trigger_abc123"`),
+				Eventf(corev1.EventTypeNormal, "TopicDeleted", `Deleted Pub/Sub Lite topic "projects/test-project-id/locations/us-central1-a/topics/cre-tgr_testnamespace_test-trigger_abc123"`),
+				triggerFinalizerUpdatedEvent,
+				triggerFinalizedEvent,
+			},
+			WantPatches: []clientgotesting.PatchActionImpl{
+				patchRemoveFinalizers(testNS, triggerName),
+			},
+			OtherTestData: map[string]interface{}{
+				"lite": gpubsublitetesting.TestAdminClientData{TopicExists: true, SubscriptionExists: true},
 			},
 		},
 	}
@@ -332,17 +824,89 @@ func TestAllCasesTrigger(t *testing.T) {
 		r := &Reconciler{
 			Base:               reconciler.NewBase(ctx, controllerAgentName, cmw),
 			brokerLister:       listers.GetBrokerLister(),
+			brokerCellLister:   listers.GetBrokerCellLister(),
+			configMapLister:    listers.GetConfigMapLister(),
 			kresourceTracker:   duck.NewListableTracker(ctx, conditions.Get, func(types.NamespacedName) {}, 0),
 			addressableTracker: duck.NewListableTracker(ctx, addressable.Get, func(types.NamespacedName) {}, 0),
 			uriResolver:        resolver.NewURIResolver(ctx, func(types.NamespacedName) {}),
 			projectID:          testProject,
 			pubsubClient:       psclient,
+			createLiteClientFn: gpubsublitetesting.TestAdminClientCreator(testData["lite"]),
+		}
+		if naming, ok := testData["naming"]; ok {
+			r.subscriptionNaming = naming.(psresources.SubscriptionNaming)
 		}
 
 		return triggerreconciler.NewReconciler(ctx, r.Logger, r.RunClientSet, listers.GetTriggerLister(), r.Recorder, r, withAgentAndFinalizer(nil))
 	}))
 }
 
+func TestDeliverySLOChanged(t *testing.T) {
+	burning := sloConfigMap(slo.Sample{
+		Trigger: testKey,
+		Short:   slo.Counts{Total: 100, Failed: 50},
+		Long:    slo.Counts{Total: 1000, Failed: 20},
+	})
+	withinBudget := sloConfigMap(slo.Sample{
+		Trigger: testKey,
+		Short:   slo.Counts{Total: 100},
+		Long:    slo.Counts{Total: 1000},
+	})
+	tests := []struct {
+		name    string
+		trigger *brokerv1beta1.Trigger
+		cm      *corev1.ConfigMap
+		want    bool
+	}{{
+		name:    "no objective",
+		trigger: NewTrigger(triggerName, testNS, brokerName),
+		cm:      burning,
+	}, {
+		name:    "not reconciled yet",
+		trigger: NewTrigger(triggerName, testNS, brokerName, WithTriggerDeliverySLO("99.9")),
+		cm:      withinBudget,
+		want:    true,
+	}, {
+		name: "starts burning",
+		trigger: NewTrigger(triggerName, testNS, brokerName, WithTriggerDeliverySLO("99.9"),
+			WithTriggerDeliverySLOWithinBudget("WithinBudget", "")),
+		cm:   burning,
+		want: true,
+	}, {
+		name: "keeps burning",
+		trigger: NewTrigger(triggerName, testNS, brokerName, WithTriggerDeliverySLO("99.9"),
+			WithTriggerDeliverySLOBurning("ErrorBudgetBurning", "")),
+		cm: burning,
+	}, {
+		name: "stays within budget",
+		trigger: NewTrigger(triggerName, testNS, brokerName, WithTriggerDeliverySLO("99.9"),
+			WithTriggerDeliverySLOWithinBudget("WithinBudget", "")),
+		cm: withinBudget,
+	}, {
+		name: "ConfigMap deleted while burning",
+		trigger: NewTrigger(triggerName, testNS, brokerName, WithTriggerDeliverySLO("99.9"),
+			WithTriggerDeliverySLOBurning("ErrorBudgetBurning", "")),
+		want: true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := deliverySLOChanged(tc.cm, tc.trigger); got != tc.want {
+				t.Errorf("deliverySLOChanged() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// sloConfigMap returns the SLO status ConfigMap with a report of the samples
+// by a single data plane pod.
+func sloConfigMap(samples ...slo.Sample) *corev1.ConfigMap {
+	b, err := json.Marshal(slo.Report{Time: time.Now(), Samples: samples})
+	if err != nil {
+		panic(err)
+	}
+	return NewConfigMap(slo.ConfigMapName, system.Namespace(), WithConfigMapDataEntry("fanout-1", string(b)))
+}
+
 func makeSubscriberAddressableAsUnstructured() *unstructured.Unstructured {
 	return &unstructured.Unstructured{
 		Object: map[string]interface{}{
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+
+	"cloud.google.com/go/pubsublite"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/eventing/pkg/logging"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	inteventsv1beta1 "github.com/google/knative-gcp/pkg/apis/intevents/v1beta1"
+	gpubsublite "github.com/google/knative-gcp/pkg/gclient/pubsublite"
+	"github.com/google/knative-gcp/pkg/reconciler"
+)
+
+// LiteReconciler reconciles Pub/Sub Lite topics and subscriptions. Unlike
+// Cloud Pub/Sub resources, they have no labels, so the fencing token of the
+// controller that created them is recorded in the status of their object.
+type LiteReconciler struct {
+	client   gpubsublite.AdminClient
+	recorder record.EventRecorder
+}
+
+func NewLiteReconciler(client gpubsublite.AdminClient, recorder record.EventRecorder) *LiteReconciler {
+	return &LiteReconciler{
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// ReconcileTopicAndSubscription creates the Pub/Sub Lite topic and
+// subscription of the given paths if they don't exist. The topic gets the
+// default capacity of the topics created for PullSubscriptions. The fencing
+// token of the controller is recorded in status if it creates either.
+func (r *LiteReconciler) ReconcileTopicAndSubscription(ctx context.Context, topicPath, subPath string, obj runtime.Object, updater StatusUpdater, status *duckv1.Status) error {
+	logger := logging.FromContext(ctx)
+
+	if _, err := r.client.Topic(ctx, topicPath); gpubsublite.IsNotFound(err) {
+		if reconciler.DryRun(obj) {
+			updater.MarkTopicUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would create Pub/Sub Lite topic %q", topicPath))
+			updater.MarkSubscriptionUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would create Pub/Sub Lite subscription %q", subPath))
+			return nil
+		}
+		var capacity inteventsv1beta1.LiteConfig
+		cfg := pubsublite.TopicConfig{
+			Name:                       topicPath,
+			PartitionCount:             capacity.GetPartitions(),
+			PublishCapacityMiBPerSec:   capacity.GetPublishMiBPerSec(),
+			SubscribeCapacityMiBPerSec: capacity.GetSubscribeMiBPerSec(),
+			PerPartitionBytes:          capacity.GetPerPartitionBytes(),
+		}
+		// The topic may have been created since it was checked, in which
+		// case it is not claimed.
+		if _, err := r.client.CreateTopic(ctx, cfg); err == nil {
+			reconciler.RecordFencingToken(status)
+		} else if gstatus.Code(err) != codes.AlreadyExists {
+			logger.Error("Failed to create Pub/Sub Lite topic", zap.Error(err))
+			updater.MarkTopicFailed("TopicCreationFailed", "Topic creation failed: %w", err)
+			return err
+		}
+		logger.Info("Created Pub/Sub Lite topic", zap.String("name", topicPath))
+		r.recorder.Eventf(obj, corev1.EventTypeNormal, topicCreated, "Created Pub/Sub Lite topic %q", topicPath)
+	} else if err != nil {
+		logger.Error("Failed to verify Pub/Sub Lite topic exists", zap.Error(err))
+		updater.MarkTopicUnknown("TopicVerificationFailed", "Failed to verify Pub/Sub Lite topic exists: %w", err)
+		return err
+	}
+	updater.MarkTopicReady()
+
+	if _, err := r.client.Subscription(ctx, subPath); gpubsublite.IsNotFound(err) {
+		if reconciler.DryRun(obj) {
+			updater.MarkSubscriptionUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would create Pub/Sub Lite subscription %q", subPath))
+			return nil
+		}
+		cfg := pubsublite.SubscriptionConfig{
+			Name:  subPath,
+			Topic: topicPath,
+			// The data plane acknowledges messages once they are processed,
+			// so it doesn't need to wait for them to be stored.
+			DeliveryRequirement: pubsublite.DeliverImmediately,
+		}
+		// The subscription may have been created since it was checked, in
+		// which case it is not claimed.
+		if _, err := r.client.CreateSubscription(ctx, cfg); err == nil {
+			reconciler.RecordFencingToken(status)
+		} else if gstatus.Code(err) != codes.AlreadyExists {
+			logger.Error("Failed to create Pub/Sub Lite subscription", zap.Error(err))
+			updater.MarkSubscriptionFailed("SubscriptionCreationFailed", "Subscription creation failed: %w", err)
+			return err
+		}
+		logger.Info("Created Pub/Sub Lite subscription", zap.String("name", subPath))
+		r.recorder.Eventf(obj, corev1.EventTypeNormal, subCreated, "Created Pub/Sub Lite subscription %q", subPath)
+	} else if err != nil {
+		logger.Error("Failed to verify Pub/Sub Lite subscription exists", zap.Error(err))
+		updater.MarkSubscriptionUnknown("SubscriptionVerificationFailed", "Failed to verify Pub/Sub Lite subscription exists: %w", err)
+		return err
+	}
+	updater.MarkSubscriptionReady()
+	return nil
+}
+
+// DeleteTopicAndSubscription deletes the Pub/Sub Lite subscription and topic
+// of the given paths if they exist, unless status records that the controller
+// of another cluster created them.
+func (r *LiteReconciler) DeleteTopicAndSubscription(ctx context.Context, topicPath, subPath string, obj runtime.Object, updater StatusUpdater, status *duckv1.Status) error {
+	logger := logging.FromContext(ctx)
+
+	err := reconciler.FinalizeExternal(ctx, r.recorder, obj, "Pub/Sub Lite subscription", subPath, func(ctx context.Context) error {
+		if _, err := r.client.Subscription(ctx, subPath); gpubsublite.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			logger.Error("Failed to verify Pub/Sub Lite subscription exists", zap.Error(err))
+			updater.MarkSubscriptionUnknown("FinalizeSubscriptionVerificationFailed", "failed to verify Pub/Sub Lite subscription exists: %w", err)
+			return err
+		}
+		if reconciler.Fenced(status.Annotations) {
+			logger.Warn("Not deleting Pub/Sub Lite subscription created by another cluster", zap.String("name", subPath),
+				zap.String("owner", status.Annotations[reconciler.FencingTokenLabel]))
+			reconciler.RecordFenced(r.recorder, obj, "Pub/Sub Lite subscription", subPath)
+			return nil
+		}
+		if reconciler.DryRun(obj) {
+			r.planChange(ctx, obj, "Would delete Pub/Sub Lite subscription %q", subPath)
+			return nil
+		}
+		if err := r.client.DeleteSubscription(ctx, subPath); err != nil && !gpubsublite.IsNotFound(err) {
+			logger.Error("Failed to delete Pub/Sub Lite subscription", zap.Error(err))
+			updater.MarkSubscriptionUnknown("FinalizeSubscriptionDeletionFailed", "failed to delete Pub/Sub Lite subscription: %w", err)
+			return err
+		}
+		r.recorder.Eventf(obj, corev1.EventTypeNormal, subDeleted, "Deleted Pub/Sub Lite subscription %q", subPath)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return reconciler.FinalizeExternal(ctx, r.recorder, obj, "Pub/Sub Lite topic", topicPath, func(ctx context.Context) error {
+		if _, err := r.client.Topic(ctx, topicPath); gpubsublite.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			logger.Error("Failed to verify Pub/Sub Lite topic exists", zap.Error(err))
+			updater.MarkTopicUnknown("FinalizeTopicVerificationFailed", "failed to verify Pub/Sub Lite topic exists: %w", err)
+			return err
+		}
+		if reconciler.Fenced(status.Annotations) {
+			logger.Warn("Not deleting Pub/Sub Lite topic created by another cluster", zap.String("name", topicPath),
+				zap.String("owner", status.Annotations[reconciler.FencingTokenLabel]))
+			reconciler.RecordFenced(r.recorder, obj, "Pub/Sub Lite topic", topicPath)
+			return nil
+		}
+		if reconciler.DryRun(obj) {
+			r.planChange(ctx, obj, "Would delete Pub/Sub Lite topic %q", topicPath)
+			return nil
+		}
+		if err := r.client.DeleteTopic(ctx, topicPath); err != nil && !gpubsublite.IsNotFound(err) {
+			logger.Error("Failed to delete Pub/Sub Lite topic", zap.Error(err))
+			updater.MarkTopicUnknown("FinalizeTopicDeletionFailed", "failed to delete Pub/Sub Lite topic: %w", err)
+			return err
+		}
+		r.recorder.Eventf(obj, corev1.EventTypeNormal, topicDeleted, "Deleted Pub/Sub Lite topic %q", topicPath)
+		return nil
+	})
+}
+
+// planChange records an external change of obj planned in dry-run mode and
+// returns its description.
+func (r *LiteReconciler) planChange(ctx context.Context, obj runtime.Object, format string, args ...interface{}) string {
+	planned := reconciler.PlannedChange(ctx, format, args...)
+	r.recorder.Event(obj, corev1.EventTypeNormal, reconciler.DryRunReason, planned.Error())
+	return planned.Error()
+}
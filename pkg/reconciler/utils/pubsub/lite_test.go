@@ -0,0 +1,153 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	gpubsublitetesting "github.com/google/knative-gcp/pkg/gclient/pubsublite/testing"
+	"github.com/google/knative-gcp/pkg/reconciler"
+	utilspubsubtesting "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub/testing"
+)
+
+const (
+	liteTopic = "projects/test-project/locations/us-central1-a/topics/test-topic"
+	liteSub   = "projects/test-project/locations/us-central1-a/subscriptions/test-sub"
+)
+
+func TestReconcileLiteTopicAndSub(t *testing.T) {
+	reconciler.SetFencingToken("live")
+	defer reconciler.SetFencingToken("")
+	tests := []struct {
+		name       string
+		data       gpubsublitetesting.TestAdminClientData
+		wantEvents []string
+		wantTopic  corev1.ConditionStatus
+		wantSub    corev1.ConditionStatus
+		wantToken  string
+		wantErr    bool
+	}{{
+		name: "created",
+		wantEvents: []string{
+			`Normal TopicCreated Created Pub/Sub Lite topic "` + liteTopic + `"`,
+			`Normal SubscriptionCreated Created Pub/Sub Lite subscription "` + liteSub + `"`,
+		},
+		wantTopic: corev1.ConditionTrue,
+		wantSub:   corev1.ConditionTrue,
+		wantToken: "live",
+	}, {
+		name:      "exist",
+		data:      gpubsublitetesting.TestAdminClientData{TopicExists: true, SubscriptionExists: true},
+		wantTopic: corev1.ConditionTrue,
+		wantSub:   corev1.ConditionTrue,
+	}, {
+		name:      "topic creation failed",
+		data:      gpubsublitetesting.TestAdminClientData{CreateTopicErr: errors.New("create failed")},
+		wantTopic: corev1.ConditionFalse,
+		wantErr:   true,
+	}, {
+		name:      "subscription creation failed",
+		data:      gpubsublitetesting.TestAdminClientData{TopicExists: true, CreateSubscriptionErr: errors.New("create failed")},
+		wantTopic: corev1.ConditionTrue,
+		wantSub:   corev1.ConditionFalse,
+		wantErr:   true,
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := gpubsublitetesting.TestAdminClientCreator(tc.data)(context.Background(), "us-central1-a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := record.NewFakeRecorder(len(tc.wantEvents))
+			su := &utilspubsubtesting.StatusUpdater{}
+			status := &duckv1.Status{}
+			err = NewLiteReconciler(client, recorder).ReconcileTopicAndSubscription(context.Background(), liteTopic, liteSub, obj, su, status)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			for _, want := range tc.wantEvents {
+				if got := <-recorder.Events; got != want {
+					t.Errorf("Unexpected event recorded, got: %v, want: %v", got, want)
+				}
+			}
+			if su.TopicCondition.Status != tc.wantTopic || su.SubCondition.Status != tc.wantSub {
+				t.Errorf("Unexpected conditions, got topic: %+v, sub: %+v", su.TopicCondition, su.SubCondition)
+			}
+			// Only the resources created by the controller are claimed.
+			if got := status.Annotations[reconciler.FencingTokenLabel]; got != tc.wantToken {
+				t.Errorf("Unexpected fencing token recorded, got: %q, want: %q", got, tc.wantToken)
+			}
+		})
+	}
+}
+
+func TestDeleteLiteTopicAndSub(t *testing.T) {
+	client, err := gpubsublitetesting.TestAdminClientCreator(gpubsublitetesting.TestAdminClientData{
+		TopicExists:        true,
+		SubscriptionExists: true,
+	})(context.Background(), "us-central1-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := record.NewFakeRecorder(2)
+	su := &utilspubsubtesting.StatusUpdater{}
+	if err := NewLiteReconciler(client, recorder).DeleteTopicAndSubscription(context.Background(), liteTopic, liteSub, obj, su, &duckv1.Status{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`Normal SubscriptionDeleted Deleted Pub/Sub Lite subscription "` + liteSub + `"`,
+		`Normal TopicDeleted Deleted Pub/Sub Lite topic "` + liteTopic + `"`,
+	} {
+		if got := <-recorder.Events; got != want {
+			t.Errorf("Unexpected event recorded, got: %v, want: %v", got, want)
+		}
+	}
+}
+
+func TestDeleteLiteTopicAndSubFenced(t *testing.T) {
+	reconciler.SetFencingToken("clone")
+	defer reconciler.SetFencingToken("")
+	client, err := gpubsublitetesting.TestAdminClientCreator(gpubsublitetesting.TestAdminClientData{
+		TopicExists:           true,
+		SubscriptionExists:    true,
+		DeleteTopicErr:        errors.New("topic deleted"),
+		DeleteSubscriptionErr: errors.New("subscription deleted"),
+	})(context.Background(), "us-central1-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := record.NewFakeRecorder(2)
+	su := &utilspubsubtesting.StatusUpdater{}
+	status := &duckv1.Status{Annotations: map[string]string{reconciler.FencingTokenLabel: "live"}}
+	if err := NewLiteReconciler(client, recorder).DeleteTopicAndSubscription(context.Background(), liteTopic, liteSub, obj, su, status); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`Warning ExternalResourceFenced Not deleting Pub/Sub Lite subscription "` + liteSub + `", it was created by the controller of another cluster`,
+		`Warning ExternalResourceFenced Not deleting Pub/Sub Lite topic "` + liteTopic + `", it was created by the controller of another cluster`,
+	} {
+		if got := <-recorder.Events; got != want {
+			t.Errorf("Unexpected event recorded, got: %v, want: %v", got, want)
+		}
+	}
+}
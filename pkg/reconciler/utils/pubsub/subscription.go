@@ -25,6 +25,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/utils"
 )
 
 const (
@@ -33,6 +36,8 @@ const (
 	deletedTopic = "_deleted-topic_"
 	subCreated   = "SubscriptionCreated"
 	subDeleted   = "SubscriptionDeleted"
+
+	messageOrderingChanged = "MessageOrderingChanged"
 )
 
 func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subConfig pubsub.SubscriptionConfig, obj runtime.Object, updater StatusUpdater) (*pubsub.Subscription, error) {
@@ -58,11 +63,16 @@ func (r *Reconciler) ReconcileSubscription(ctx context.Context, id string, subCo
 			r.recorder.Eventf(obj, corev1.EventTypeWarning, topicDeleted, "Unexpected topic deletion detected for subscription: %q", sub.ID())
 			// Subscription with "_deleted-topic_" cannot pull from the new topic. In order to recover, we first delete
 			// the sub and then create it. Unacked messages will be lost.
-			if err := r.deleteSubscription(ctx, sub, obj); err != nil {
-				updater.MarkSubscriptionFailed("SubscriptionDeletionFailed", "topic of the subscription has been deleted, need to recreate the subscription: %v", err)
-				return nil, fmt.Errorf("topic of the subscription has been deleted, need to recreate the subscription: %v", err)
-			}
-			return r.createSubscription(ctx, id, subConfig, obj, updater)
+			return r.recreateSubscription(ctx, sub, id, subConfig, config, obj, updater, "the topic of the subscription has been deleted")
+		}
+		// Pub/Sub cannot update message ordering in place. The existing
+		// subscription is compared rather than any recorded state, so that
+		// a reconcile retried after a later failure doesn't recreate it again.
+		if config.EnableMessageOrdering != subConfig.EnableMessageOrdering {
+			logger.Warn("Detected changed message ordering. Going to recreate the pull subscription. Unacked messages will be lost.",
+				zap.String("name", id), zap.Bool("messageOrdering", subConfig.EnableMessageOrdering))
+			r.recorder.Eventf(obj, corev1.EventTypeWarning, messageOrderingChanged, "Message ordering changed to %t, recreating subscription: %q", subConfig.EnableMessageOrdering, sub.ID())
+			return r.recreateSubscription(ctx, sub, id, subConfig, config, obj, updater, "the message ordering of the subscription has changed")
 		}
 		updater.MarkSubscriptionReady()
 		return sub, nil
@@ -76,19 +86,59 @@ func (r *Reconciler) DeleteSubscription(ctx context.Context, id string, obj runt
 	logger.Debug("Deleting decoupling sub")
 
 	sub := r.client.Subscription(id)
-	exists, err := sub.Exists(ctx)
-	if err != nil {
-		logger.Error("Failed to verify Pub/Sub subscription exists", zap.Error(err))
-		updater.MarkSubscriptionUnknown("FinalizeSubscriptionVerificationFailed", "failed to verify Pub/Sub subscription exists: %w", err)
-		return err
-	}
-	if exists {
-		if err = r.deleteSubscription(ctx, sub, obj); err != nil {
-			updater.MarkSubscriptionUnknown("FinalizeSubscriptionDeletionFailed", "failed to delete Pub/Sub subscription: %w", err)
+	return reconciler.FinalizeExternal(ctx, r.recorder, obj, "Pub/Sub subscription", id, func(ctx context.Context) error {
+		exists, err := sub.Exists(ctx)
+		if err != nil {
+			logger.Error("Failed to verify Pub/Sub subscription exists", zap.Error(err))
+			updater.MarkSubscriptionUnknown("FinalizeSubscriptionVerificationFailed", "failed to verify Pub/Sub subscription exists: %w", err)
 			return err
 		}
+		if exists {
+			if reconciler.FencingToken() != "" {
+				config, err := sub.Config(ctx)
+				if err != nil {
+					logger.Error("Failed to get Pub/Sub subscription config", zap.Error(err))
+					updater.MarkSubscriptionUnknown("FinalizeSubscriptionConfigUnknown", "failed to get Pub/Sub subscription config: %w", err)
+					return err
+				}
+				if reconciler.Fenced(config.Labels) {
+					logger.Warn("Not deleting Pub/Sub subscription created by another cluster", zap.String("name", id),
+						zap.String("owner", config.Labels[reconciler.FencingTokenLabel]))
+					reconciler.RecordFenced(r.recorder, obj, "Pub/Sub subscription", id)
+					return nil
+				}
+			}
+			if reconciler.DryRun(obj) {
+				r.planChange(ctx, obj, "Would delete Pub/Sub subscription %q", id)
+				return nil
+			}
+			if err = r.deleteSubscription(ctx, sub, obj); err != nil {
+				updater.MarkSubscriptionUnknown("FinalizeSubscriptionDeletionFailed", "failed to delete Pub/Sub subscription: %w", err)
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// recreateSubscription deletes the subscription and creates it again with
+// subConfig, because it cannot be recovered or updated in place. why says what
+// requires it, and config is the current config of the subscription.
+func (r *Reconciler) recreateSubscription(ctx context.Context, sub *pubsub.Subscription, id string, subConfig pubsub.SubscriptionConfig, config pubsub.SubscriptionConfig, obj runtime.Object, updater StatusUpdater, why string) (*pubsub.Subscription, error) {
+	if reconciler.Fenced(config.Labels) {
+		reconciler.RecordFenced(r.recorder, obj, "Pub/Sub subscription", id)
+		updater.MarkSubscriptionFailed("SubscriptionFenced", "%s, but the subscription was created by the controller of another cluster", why)
+		return nil, fmt.Errorf("%s, but the subscription %q was created by the controller of another cluster", why, id)
 	}
-	return nil
+	if reconciler.DryRun(obj) {
+		updater.MarkSubscriptionUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would recreate Pub/Sub subscription %q", id))
+		return sub, nil
+	}
+	if err := r.deleteSubscription(ctx, sub, obj); err != nil {
+		updater.MarkSubscriptionFailed("SubscriptionDeletionFailed", "%s, need to recreate the subscription: %v", why, err)
+		return nil, fmt.Errorf("%s, need to recreate the subscription: %v", why, err)
+	}
+	return r.createSubscription(ctx, id, subConfig, obj, updater)
 }
 
 func (r *Reconciler) deleteSubscription(ctx context.Context, sub *pubsub.Subscription, obj runtime.Object) error {
@@ -98,12 +148,18 @@ func (r *Reconciler) deleteSubscription(ctx context.Context, sub *pubsub.Subscri
 		return err
 	}
 	logger.Info("Deleted PubSub subscription", zap.String("name", sub.ID()))
-	r.recorder.Eventf(obj, corev1.EventTypeNormal, subDeleted, "Deleted PubSub subscription %q", sub.ID())
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, subDeleted, "Deleted PubSub subscription %q", sub.String())
 	return nil
 }
 
 func (r *Reconciler) createSubscription(ctx context.Context, id string, subConfig pubsub.SubscriptionConfig, obj runtime.Object, updater StatusUpdater) (*pubsub.Subscription, error) {
+	if reconciler.DryRun(obj) {
+		updater.MarkSubscriptionUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would create Pub/Sub subscription %q", id))
+		return r.client.Subscription(id), nil
+	}
 	logger := logging.FromContext(ctx)
+	// Label the subscription with the fencing token of the controller.
+	subConfig.Labels = reconciler.WithFencingLabel(subConfig.Labels)
 	logger.Debug("Creating sub with cfg", zap.String("id", id), zap.Any("cfg", subConfig))
 	sub, err := r.client.CreateSubscription(ctx, id, subConfig)
 	if err != nil {
@@ -112,7 +168,7 @@ func (r *Reconciler) createSubscription(ctx context.Context, id string, subConfi
 		return nil, err
 	}
 	logger.Info("Created PubSub subscription", zap.String("name", sub.ID()))
-	r.recorder.Eventf(obj, corev1.EventTypeNormal, subCreated, "Created PubSub subscription %q", sub.ID())
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, subCreated, "Created PubSub subscription %q: %s", sub.String(), utils.SubscriptionConsoleURL(projectOf(sub.String()), sub.ID()))
 	updater.MarkSubscriptionReady()
 	return sub, nil
 }
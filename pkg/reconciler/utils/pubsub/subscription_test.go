@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/apis"
 
+	"github.com/google/knative-gcp/pkg/reconciler"
 	reconcilertesting "github.com/google/knative-gcp/pkg/reconciler/testing"
 	utilspubsubtesting "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub/testing"
 )
@@ -39,7 +40,7 @@ func TestReconcileSub(t *testing.T) {
 		{
 			name:             "new sub created",
 			pre:              []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic)},
-			wantEvents:       []string{`Normal SubscriptionCreated Created PubSub subscription "test-sub"`},
+			wantEvents:       []string{`Normal SubscriptionCreated Created PubSub subscription "projects/test-project/subscriptions/test-sub": https://console.cloud.google.com/cloudpubsub/subscription/detail/test-sub?project=test-project`},
 			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
 		{
@@ -54,8 +55,8 @@ func TestReconcileSub(t *testing.T) {
 			pre: []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub), deleteTopic, reconcilertesting.Topic(topic)},
 			wantEvents: []string{
 				`Warning TopicDeleted Unexpected topic deletion detected for subscription: "test-sub"`,
-				`Normal SubscriptionDeleted Deleted PubSub subscription "test-sub"`,
-				`Normal SubscriptionCreated Created PubSub subscription "test-sub"`,
+				`Normal SubscriptionDeleted Deleted PubSub subscription "projects/test-project/subscriptions/test-sub"`,
+				`Normal SubscriptionCreated Created PubSub subscription "projects/test-project/subscriptions/test-sub": https://console.cloud.google.com/cloudpubsub/subscription/detail/test-sub?project=test-project`,
 			},
 			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
@@ -78,12 +79,48 @@ func TestReconcileSub(t *testing.T) {
 
 }
 
+func TestReconcileSubMessageOrderingChanged(t *testing.T) {
+	tests := []testCase{
+		{
+			name:             "new sub created",
+			pre:              []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic)},
+			wantEvents:       []string{`Normal SubscriptionCreated Created PubSub subscription "projects/test-project/subscriptions/test-sub": https://console.cloud.google.com/cloudpubsub/subscription/detail/test-sub?project=test-project`},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+		{
+			name: "sub recreated",
+			pre:  []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+			wantEvents: []string{
+				`Warning MessageOrderingChanged Message ordering changed to true, recreating subscription: "test-sub"`,
+				`Normal SubscriptionDeleted Deleted PubSub subscription "projects/test-project/subscriptions/test-sub"`,
+				`Normal SubscriptionCreated Created PubSub subscription "projects/test-project/subscriptions/test-sub": https://console.cloud.google.com/cloudpubsub/subscription/detail/test-sub?project=test-project`,
+			},
+			wantSubCondition: apis.Condition{Status: corev1.ConditionTrue},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, cleanup := newTestRunner(t, tc)
+			defer cleanup()
+			r := NewReconciler(tr.client, tr.recorder)
+			su := &utilspubsubtesting.StatusUpdater{}
+			subConfig := pubsub.SubscriptionConfig{Topic: tr.client.Topic(topic), EnableMessageOrdering: true}
+			res, err := r.ReconcileSubscription(context.Background(), sub, subConfig, obj, su)
+
+			tr.verify(t, tc, su, err)
+			if res != nil {
+				verifySub(t, res, subConfig)
+			}
+		})
+	}
+}
+
 func TestDeleteSub(t *testing.T) {
 	tests := []testCase{
 		{
 			name:       "sub deleted",
 			pre:        []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
-			wantEvents: []string{`Normal SubscriptionDeleted Deleted PubSub subscription "test-sub"`},
+			wantEvents: []string{`Normal SubscriptionDeleted Deleted PubSub subscription "projects/test-project/subscriptions/test-sub"`},
 		},
 		{
 			name: "nothing to delete",
@@ -109,6 +146,69 @@ func TestDeleteSub(t *testing.T) {
 
 }
 
+func TestFencedSub(t *testing.T) {
+	reconciler.SetFencingToken("live")
+	defer reconciler.SetFencingToken("")
+
+	t.Run("sub of another cluster kept", func(t *testing.T) {
+		tc := testCase{
+			pre:        []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic), labeledSub("other")},
+			wantEvents: []string{`Warning ExternalResourceFenced Not deleting Pub/Sub subscription "test-sub", it was created by the controller of another cluster`},
+		}
+		tr, cleanup := newTestRunner(t, tc)
+		defer cleanup()
+		r := NewReconciler(tr.client, tr.recorder)
+		su := &utilspubsubtesting.StatusUpdater{}
+		err := r.DeleteSubscription(context.Background(), sub, obj, su)
+		tr.verify(t, tc, su, err)
+		exists, err := tr.client.Subscription(sub).Exists(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to verify sub exists: %v", err)
+		}
+		if !exists {
+			t.Errorf("Sub of another cluster was deleted")
+		}
+	})
+
+	t.Run("sub of another cluster not recreated", func(t *testing.T) {
+		tc := testCase{
+			pre: []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic), labeledSub("other"), deleteTopic, reconcilertesting.Topic(topic)},
+			wantEvents: []string{
+				`Warning TopicDeleted Unexpected topic deletion detected for subscription: "test-sub"`,
+				`Warning ExternalResourceFenced Not deleting Pub/Sub subscription "test-sub", it was created by the controller of another cluster`,
+			},
+			wantSubCondition: apis.Condition{
+				Status:  corev1.ConditionFalse,
+				Reason:  "SubscriptionFenced",
+				Message: "the topic of the subscription has been deleted, but the subscription was created by the controller of another cluster",
+			},
+		}
+		tr, cleanup := newTestRunner(t, tc)
+		defer cleanup()
+		r := NewReconciler(tr.client, tr.recorder)
+		su := &utilspubsubtesting.StatusUpdater{}
+		_, err := r.ReconcileSubscription(context.Background(), sub, pubsub.SubscriptionConfig{Topic: tr.client.Topic(topic)}, obj, su)
+		if err == nil {
+			t.Error("ReconcileSubscription got nil error, want error")
+		}
+		tr.verify(t, tc, su, nil)
+	})
+}
+
+// labeledSub creates the subscription with the fencing token of a
+// controller.
+func labeledSub(token string) reconcilertesting.PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		_, err := c.CreateSubscription(ctx, sub, pubsub.SubscriptionConfig{
+			Topic:  c.Topic(topic),
+			Labels: map[string]string{reconciler.FencingTokenLabel: token},
+		})
+		if err != nil {
+			t.Fatalf("Error creating subscription %q: %v", sub, err)
+		}
+	}
+}
+
 func deleteTopic(ctx context.Context, t *testing.T, c *pubsub.Client) {
 	if err := c.Topic(topic).Delete(ctx); err != nil {
 		t.Fatalf("Failed to delete topic: %v", err)
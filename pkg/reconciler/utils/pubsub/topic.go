@@ -24,6 +24,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"knative.dev/eventing/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/reconciler"
+	"github.com/google/knative-gcp/pkg/utils"
 )
 
 const (
@@ -46,17 +49,26 @@ func (r *Reconciler) ReconcileTopic(ctx context.Context, id string, topicConfig
 		updater.MarkTopicReady()
 		return topic, nil
 	}
+	if reconciler.DryRun(obj) {
+		updater.MarkTopicUnknown(reconciler.DryRunReason, "%s", r.planChange(ctx, obj, "Would create Pub/Sub topic %q", id))
+		return topic, nil
+	}
 
-	// Create a new topic.
-	logger.Debug("Creating topic with cfg", zap.String("id", id), zap.Any("cfg", topicConfig))
-	topic, err = r.client.CreateTopicWithConfig(ctx, id, topicConfig)
+	// Create a new topic, labeled with the fencing token of the controller.
+	cfg := pubsub.TopicConfig{}
+	if topicConfig != nil {
+		cfg = *topicConfig
+	}
+	cfg.Labels = reconciler.WithFencingLabel(cfg.Labels)
+	logger.Debug("Creating topic with cfg", zap.String("id", id), zap.Any("cfg", cfg))
+	topic, err = r.client.CreateTopicWithConfig(ctx, id, &cfg)
 	if err != nil {
 		logger.Error("Failed to create Pub/Sub topic", zap.Error(err))
 		updater.MarkTopicFailed("TopicCreationFailed", "Topic creation failed: %w", err)
 		return nil, err
 	}
 	logger.Info("Created PubSub topic", zap.String("name", topic.ID()))
-	r.recorder.Eventf(obj, corev1.EventTypeNormal, topicCreated, "Created PubSub topic %q", topic.ID())
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, topicCreated, "Created PubSub topic %q: %s", topic.String(), utils.TopicConsoleURL(projectOf(topic.String()), topic.ID()))
 	updater.MarkTopicReady()
 	return topic, nil
 }
@@ -68,20 +80,40 @@ func (r *Reconciler) DeleteTopic(ctx context.Context, id string, obj runtime.Obj
 	// Delete topic if it exists. Pull subscriptions continue pulling from the
 	// topic until deleted themselves.
 	topic := r.client.Topic(id)
-	exists, err := topic.Exists(ctx)
-	if err != nil {
-		logger.Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
-		updater.MarkTopicUnknown("FinalizeTopicVerificationFailed", "failed to verify Pub/Sub topic exists: %w", err)
-		return err
-	}
-	if exists {
-		if err := topic.Delete(ctx); err != nil {
-			logger.Error("Failed to delete Pub/Sub topic", zap.Error(err))
-			updater.MarkTopicUnknown("FinalizeTopicDeletionFailed", "failed to delete Pub/Sub topic: %w", err)
+	return reconciler.FinalizeExternal(ctx, r.recorder, obj, "Pub/Sub topic", id, func(ctx context.Context) error {
+		exists, err := topic.Exists(ctx)
+		if err != nil {
+			logger.Error("Failed to verify Pub/Sub topic exists", zap.Error(err))
+			updater.MarkTopicUnknown("FinalizeTopicVerificationFailed", "failed to verify Pub/Sub topic exists: %w", err)
 			return err
 		}
-		logger.Info("Deleted PubSub topic", zap.String("name", topic.ID()))
-		r.recorder.Eventf(obj, corev1.EventTypeNormal, topicDeleted, "Deleted PubSub topic %q", topic.ID())
-	}
-	return nil
+		if exists {
+			if reconciler.FencingToken() != "" {
+				config, err := topic.Config(ctx)
+				if err != nil {
+					logger.Error("Failed to get Pub/Sub topic config", zap.Error(err))
+					updater.MarkTopicUnknown("FinalizeTopicConfigUnknown", "failed to get Pub/Sub topic config: %w", err)
+					return err
+				}
+				if reconciler.Fenced(config.Labels) {
+					logger.Warn("Not deleting Pub/Sub topic created by another cluster", zap.String("name", id),
+						zap.String("owner", config.Labels[reconciler.FencingTokenLabel]))
+					reconciler.RecordFenced(r.recorder, obj, "Pub/Sub topic", id)
+					return nil
+				}
+			}
+			if reconciler.DryRun(obj) {
+				r.planChange(ctx, obj, "Would delete Pub/Sub topic %q", id)
+				return nil
+			}
+			if err := topic.Delete(ctx); err != nil {
+				logger.Error("Failed to delete Pub/Sub topic", zap.Error(err))
+				updater.MarkTopicUnknown("FinalizeTopicDeletionFailed", "failed to delete Pub/Sub topic: %w", err)
+				return err
+			}
+			logger.Info("Deleted PubSub topic", zap.String("name", topic.ID()))
+			r.recorder.Eventf(obj, corev1.EventTypeNormal, topicDeleted, "Deleted PubSub topic %q", topic.String())
+		}
+		return nil
+	})
 }
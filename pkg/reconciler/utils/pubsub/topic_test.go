@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"knative.dev/pkg/apis"
 
+	"github.com/google/knative-gcp/pkg/reconciler"
 	reconcilertesting "github.com/google/knative-gcp/pkg/reconciler/testing"
 	utilspubsubtesting "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub/testing"
 )
@@ -47,7 +48,7 @@ func TestReconcileTopic(t *testing.T) {
 	tests := []testCase{
 		{
 			name:               "new topic created",
-			wantEvents:         []string{`Normal TopicCreated Created PubSub topic "test-topic"`},
+			wantEvents:         []string{`Normal TopicCreated Created PubSub topic "projects/test-project/topics/test-topic": https://console.cloud.google.com/cloudpubsub/topic/detail/test-topic?project=test-project`},
 			wantTopicCondition: apis.Condition{Status: corev1.ConditionTrue},
 		},
 		{
@@ -76,7 +77,7 @@ func TestDeleteTopic(t *testing.T) {
 		{
 			name:       "topic deleted",
 			pre:        []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic)},
-			wantEvents: []string{`Normal TopicDeleted Deleted PubSub topic "test-topic"`},
+			wantEvents: []string{`Normal TopicDeleted Deleted PubSub topic "projects/test-project/topics/test-topic"`},
 		},
 		{
 			name: "nothing to delete",
@@ -102,6 +103,81 @@ func TestDeleteTopic(t *testing.T) {
 
 }
 
+func TestFencedTopic(t *testing.T) {
+	reconciler.SetFencingToken("live")
+	defer reconciler.SetFencingToken("")
+
+	tests := []struct {
+		testCase
+		wantExists bool
+	}{{
+		testCase: testCase{
+			name:       "topic of this cluster deleted",
+			pre:        []reconcilertesting.PubsubAction{labeledTopic("live")},
+			wantEvents: []string{`Normal TopicDeleted Deleted PubSub topic "projects/test-project/topics/test-topic"`},
+		},
+	}, {
+		testCase: testCase{
+			name:       "topic of another cluster kept",
+			pre:        []reconcilertesting.PubsubAction{labeledTopic("other")},
+			wantEvents: []string{`Warning ExternalResourceFenced Not deleting Pub/Sub topic "test-topic", it was created by the controller of another cluster`},
+		},
+		wantExists: true,
+	}, {
+		testCase: testCase{
+			name:       "unlabeled topic deleted",
+			pre:        []reconcilertesting.PubsubAction{reconcilertesting.Topic(topic)},
+			wantEvents: []string{`Normal TopicDeleted Deleted PubSub topic "projects/test-project/topics/test-topic"`},
+		},
+	}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tr, cleanup := newTestRunner(t, tc.testCase)
+			defer cleanup()
+			r := NewReconciler(tr.client, tr.recorder)
+			su := &utilspubsubtesting.StatusUpdater{}
+			err := r.DeleteTopic(context.Background(), topic, obj, su)
+			tr.verify(t, tc.testCase, su, err)
+			exists, err := tr.client.Topic(topic).Exists(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to verify topic exists: %v", err)
+			}
+			if exists != tc.wantExists {
+				t.Errorf("Topic exists got=%v, want=%v", exists, tc.wantExists)
+			}
+		})
+	}
+
+	t.Run("new topic labeled", func(t *testing.T) {
+		tr, cleanup := newTestRunner(t, testCase{})
+		defer cleanup()
+		r := NewReconciler(tr.client, record.NewFakeRecorder(1))
+		res, err := r.ReconcileTopic(context.Background(), topic, &topicConfig, obj, &utilspubsubtesting.StatusUpdater{})
+		if err != nil {
+			t.Fatalf("ReconcileTopic got error: %v", err)
+		}
+		gotConfig, err := res.Config(context.Background())
+		if err != nil {
+			t.Fatalf("Failed to get config: %v", err)
+		}
+		if got := gotConfig.Labels[reconciler.FencingTokenLabel]; got != "live" {
+			t.Errorf("Fencing label got=%q, want=%q", got, "live")
+		}
+	})
+}
+
+// labeledTopic creates the topic with the fencing token of a controller.
+func labeledTopic(token string) reconcilertesting.PubsubAction {
+	return func(ctx context.Context, t *testing.T, c *pubsub.Client) {
+		_, err := c.CreateTopicWithConfig(ctx, topic, &pubsub.TopicConfig{
+			Labels: map[string]string{reconciler.FencingTokenLabel: token},
+		})
+		if err != nil {
+			t.Fatalf("Error creating topic %q: %v", topic, err)
+		}
+	}
+}
+
 func verifyTopic(t *testing.T, got *pubsub.Topic) {
 	want := fmt.Sprintf("projects/%s/topics/%s", project, topic)
 	if got.String() != want {
@@ -17,8 +17,17 @@ limitations under the License.
 package pubsub
 
 import (
+	"context"
+	"strings"
+	"sync"
+
 	"cloud.google.com/go/pubsub"
+	"go.uber.org/multierr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
+
+	"github.com/google/knative-gcp/pkg/reconciler"
 )
 
 type Reconciler struct {
@@ -33,6 +42,24 @@ func NewReconciler(client *pubsub.Client, recorder record.EventRecorder) *Reconc
 	}
 }
 
+// planChange records an external change of obj planned in dry-run mode and
+// returns its description.
+func (r *Reconciler) planChange(ctx context.Context, obj runtime.Object, format string, args ...interface{}) string {
+	planned := reconciler.PlannedChange(ctx, format, args...)
+	r.recorder.Event(obj, corev1.EventTypeNormal, reconciler.DryRunReason, planned.Error())
+	return planned.Error()
+}
+
+// projectOf returns the project of a fully qualified
+// "projects/<project>/<collection>/<id>" Pub/Sub resource name.
+func projectOf(name string) string {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
 // StatusUpdater is an interface which updates resource status based on pubsub reconciliation results.
 type StatusUpdater interface {
 	MarkTopicFailed(reason, format string, args ...interface{})
@@ -42,3 +69,100 @@ type StatusUpdater interface {
 	MarkSubscriptionUnknown(reason, format string, args ...interface{})
 	MarkSubscriptionReady()
 }
+
+// DeleteTopicAndSubscription deletes the topic and the subscription in
+// parallel, so that finalizing obj takes at most one finalize timeout. The
+// events of the topic are recorded before those of the subscription.
+func (r *Reconciler) DeleteTopicAndSubscription(ctx context.Context, topicID, subID string, obj runtime.Object, updater StatusUpdater) error {
+	updater = &lockedStatusUpdater{updater: updater}
+	topicRecorder := &bufferedRecorder{EventRecorder: r.recorder}
+	subRecorder := &bufferedRecorder{EventRecorder: r.recorder}
+	var topicErr, subErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		topicErr = NewReconciler(r.client, topicRecorder).DeleteTopic(ctx, topicID, obj, updater)
+	}()
+	go func() {
+		defer wg.Done()
+		subErr = NewReconciler(r.client, subRecorder).DeleteSubscription(ctx, subID, obj, updater)
+	}()
+	wg.Wait()
+	topicRecorder.flush()
+	subRecorder.flush()
+	return multierr.Append(topicErr, subErr)
+}
+
+// bufferedRecorder holds the events of a deletion until they can be recorded
+// in a deterministic order.
+type bufferedRecorder struct {
+	record.EventRecorder
+	events []func(record.EventRecorder)
+}
+
+func (r *bufferedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.events = append(r.events, func(recorder record.EventRecorder) {
+		recorder.Event(object, eventtype, reason, message)
+	})
+}
+
+func (r *bufferedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.events = append(r.events, func(recorder record.EventRecorder) {
+		recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	})
+}
+
+func (r *bufferedRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.events = append(r.events, func(recorder record.EventRecorder) {
+		recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	})
+}
+
+func (r *bufferedRecorder) flush() {
+	for _, event := range r.events {
+		event(r.EventRecorder)
+	}
+}
+
+// lockedStatusUpdater serializes the status updates of concurrent deletions.
+type lockedStatusUpdater struct {
+	mu      sync.Mutex
+	updater StatusUpdater
+}
+
+func (u *lockedStatusUpdater) MarkTopicFailed(reason, format string, args ...interface{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkTopicFailed(reason, format, args...)
+}
+
+func (u *lockedStatusUpdater) MarkTopicUnknown(reason, format string, args ...interface{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkTopicUnknown(reason, format, args...)
+}
+
+func (u *lockedStatusUpdater) MarkTopicReady() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkTopicReady()
+}
+
+func (u *lockedStatusUpdater) MarkSubscriptionFailed(reason, format string, args ...interface{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkSubscriptionFailed(reason, format, args...)
+}
+
+func (u *lockedStatusUpdater) MarkSubscriptionUnknown(reason, format string, args ...interface{}) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkSubscriptionUnknown(reason, format, args...)
+}
+
+func (u *lockedStatusUpdater) MarkSubscriptionReady() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.updater.MarkSubscriptionReady()
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubsub
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/pubsub"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	reconcilertesting "github.com/google/knative-gcp/pkg/reconciler/testing"
+	utilspubsubtesting "github.com/google/knative-gcp/pkg/reconciler/utils/pubsub/testing"
+)
+
+func TestDeleteTopicAndSub(t *testing.T) {
+	tc := testCase{
+		pre: []reconcilertesting.PubsubAction{reconcilertesting.TopicAndSub(topic, sub)},
+		wantEvents: []string{
+			`Normal TopicDeleted Deleted PubSub topic "projects/test-project/topics/test-topic"`,
+			`Normal SubscriptionDeleted Deleted PubSub subscription "projects/test-project/subscriptions/test-sub"`,
+		},
+	}
+	tr, cleanup := newTestRunner(t, tc)
+	defer cleanup()
+	r := NewReconciler(tr.client, tr.recorder)
+	su := &utilspubsubtesting.StatusUpdater{}
+	err := r.DeleteTopicAndSubscription(context.Background(), topic, sub, obj, su)
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	tr.verify(t, tc, su, err)
+	if exists, err := tr.client.Topic(topic).Exists(context.Background()); err != nil || exists {
+		t.Errorf("Topic still exists: %v", err)
+	}
+	if exists, err := tr.client.Subscription(sub).Exists(context.Background()); err != nil || exists {
+		t.Errorf("Sub still exists: %v", err)
+	}
+}
+
+func TestDeleteTopicAndSubAbandoned(t *testing.T) {
+	tr, cleanup := newTestRunner(t, testCase{wantEvents: []string{"", ""}})
+	// Simulate an outage of Pub/Sub.
+	cleanup()
+	r := NewReconciler(tr.client, tr.recorder)
+	su := &utilspubsubtesting.StatusUpdater{}
+	abandonable := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{duckv1beta1.AbandonOnFinalizeFailureAnnotation: "true"},
+	}}
+	if err := r.DeleteTopicAndSubscription(context.Background(), topic, sub, abandonable, su); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		`Warning ExternalResourceAbandoned Abandoned Pub/Sub topic "test-topic" after failing to delete it`,
+		`Warning ExternalResourceAbandoned Abandoned Pub/Sub subscription "test-sub" after failing to delete it`,
+	} {
+		if got := <-tr.recorder.Events; !strings.HasPrefix(got, want) {
+			t.Errorf("Unexpected event recorded, got: %v, want prefix: %v", got, want)
+		}
+	}
+	if su.TopicCondition.Status != corev1.ConditionUnknown || su.SubCondition.Status != corev1.ConditionUnknown {
+		t.Errorf("Unexpected conditions, got topic: %+v, sub: %+v", su.TopicCondition, su.SubCondition)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	tc := testCase{
+		pre: []reconcilertesting.PubsubAction{reconcilertesting.Topic("existing-topic"), reconcilertesting.Topic(topic)},
+		wantEvents: []string{
+			`Normal DryRun Would create Pub/Sub topic "new-topic"`,
+			`Normal DryRun Would create Pub/Sub subscription "test-sub"`,
+			`Normal DryRun Would delete Pub/Sub topic "test-topic"`,
+		},
+	}
+	tr, cleanup := newTestRunner(t, tc)
+	defer cleanup()
+	r := NewReconciler(tr.client, tr.recorder)
+	dryRun := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{duckv1beta1.DryRunAnnotation: "true"},
+	}}
+
+	su := &utilspubsubtesting.StatusUpdater{}
+	if _, err := r.ReconcileTopic(context.Background(), "new-topic", &topicConfig, dryRun, su); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if su.TopicCondition.Status != corev1.ConditionUnknown || su.TopicCondition.Reason != "DryRun" {
+		t.Errorf("Unexpected topic condition: %+v", su.TopicCondition)
+	}
+	subConfig := pubsub.SubscriptionConfig{Topic: tr.client.Topic("existing-topic")}
+	if _, err := r.ReconcileSubscription(context.Background(), sub, subConfig, dryRun, su); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if su.SubCondition.Status != corev1.ConditionUnknown || su.SubCondition.Reason != "DryRun" {
+		t.Errorf("Unexpected subscription condition: %+v", su.SubCondition)
+	}
+	if err := r.DeleteTopicAndSubscription(context.Background(), topic, sub, dryRun, su); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	for _, want := range tc.wantEvents {
+		if got := <-tr.recorder.Events; got != want {
+			t.Errorf("Unexpected event recorded, got: %v, want: %v", got, want)
+		}
+	}
+	for id, want := range map[string]bool{"new-topic": false, topic: true} {
+		if exists, err := tr.client.Topic(id).Exists(context.Background()); err != nil || exists != want {
+			t.Errorf("Topic %q exists = %t, want: %t (%v)", id, exists, want, err)
+		}
+	}
+	if exists, err := tr.client.Subscription(sub).Exists(context.Background()); err != nil || exists {
+		t.Errorf("Sub exists = %t, want: false (%v)", exists, err)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sources provides a generic view of the knative-gcp sources, so that
+// tooling can discover them without knowing each source kind.
+package sources
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	"knative.dev/pkg/apis/duck"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// GVRs are the resources of the knative-gcp sources. All of them implement
+// the duckv1beta1.PubSub duck type.
+var GVRs = []schema.GroupVersionResource{
+	v1beta1.SchemeGroupVersion.WithResource("cloudartifactregistrysources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudauditlogssources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudbillingbudgetsources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudbuildsources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudmonitoringalertsources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudpubsubsources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudschedulersources"),
+	v1beta1.SchemeGroupVersion.WithResource("cloudstoragesources"),
+	v1beta1.SchemeGroupVersion.WithResource("secretmanagerrotationsources"),
+}
+
+// GCPSource is the kind-agnostic view of a knative-gcp source.
+type GCPSource struct {
+	// Resource is the resource of the source, e.g. cloudstoragesources.
+	Resource schema.GroupVersionResource
+
+	Namespace string
+	Name      string
+
+	// Sink is the resolved URI of the sink, if any.
+	Sink *apis.URL
+
+	// ProjectID and TopicID identify the Pub/Sub topic the source's events
+	// go through.
+	ProjectID string
+	TopicID   string
+
+	// Ready is true if the source is ready to send events.
+	Ready bool
+
+	// CloudEventAttributes are the attributes of the events the source sends.
+	CloudEventAttributes []duckv1.CloudEventAttributes
+}
+
+// FromPubSub returns the GCPSource of a source of the given resource.
+func FromPubSub(gvr schema.GroupVersionResource, ps *duckv1beta1.PubSub) *GCPSource {
+	return &GCPSource{
+		Resource:             gvr,
+		Namespace:            ps.Namespace,
+		Name:                 ps.Name,
+		Sink:                 ps.Status.SinkURI,
+		ProjectID:            ps.Status.ProjectID,
+		TopicID:              ps.Status.TopicID,
+		Ready:                ps.Status.IsReady(),
+		CloudEventAttributes: ps.Status.CloudEventAttributes,
+	}
+}
+
+// Lister lists the knative-gcp sources of all kinds.
+type Lister struct {
+	gvrs    []schema.GroupVersionResource
+	listers map[schema.GroupVersionResource]cache.GenericLister
+}
+
+// NewLister returns a Lister of the given resources, GVRs if none are given.
+// The informers are obtained from factory, which should produce
+// duckv1beta1.PubSub objects, e.g. the one of the PubSub duck injection
+// package.
+func NewLister(factory duck.InformerFactory, gvrs ...schema.GroupVersionResource) (*Lister, error) {
+	if len(gvrs) == 0 {
+		gvrs = GVRs
+	}
+	l := &Lister{
+		gvrs:    gvrs,
+		listers: make(map[schema.GroupVersionResource]cache.GenericLister, len(gvrs)),
+	}
+	for _, gvr := range gvrs {
+		_, lister, err := factory.Get(gvr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lister for %v: %w", gvr, err)
+		}
+		l.listers[gvr] = lister
+	}
+	return l, nil
+}
+
+// List returns the sources in the namespace, or in all namespaces if it's
+// empty, whose labels match the selector. The sources are grouped by resource,
+// in the order of the Lister's resources, and sorted by namespace and name.
+func (l *Lister) List(namespace string, selector labels.Selector) ([]*GCPSource, error) {
+	var sources []*GCPSource
+	for _, gvr := range l.gvrs {
+		var objs []runtime.Object
+		var err error
+		if namespace == "" {
+			objs, err = l.listers[gvr].List(selector)
+		} else {
+			objs, err = l.listers[gvr].ByNamespace(namespace).List(selector)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %v: %w", gvr, err)
+		}
+		start := len(sources)
+		for _, obj := range objs {
+			ps, ok := obj.(*duckv1beta1.PubSub)
+			if !ok {
+				return nil, fmt.Errorf("unexpected type %T listing %v", obj, gvr)
+			}
+			sources = append(sources, FromPubSub(gvr, ps))
+		}
+		sortByName(sources[start:])
+	}
+	return sources, nil
+}
+
+func sortByName(sources []*GCPSource) {
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Namespace != sources[j].Namespace {
+			return sources[i].Namespace < sources[j].Namespace
+		}
+		return sources[i].Name < sources[j].Name
+	})
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+)
+
+var (
+	storageGVR = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1beta1", Resource: "cloudstoragesources"}
+	pubsubGVR  = schema.GroupVersionResource{Group: "events.cloud.google.com", Version: "v1beta1", Resource: "cloudpubsubsources"}
+
+	sinkURI = apis.HTTP("sink.example.com")
+)
+
+// fakeInformerFactory serves listers of fixed objects per resource.
+type fakeInformerFactory struct {
+	objs map[schema.GroupVersionResource][]*duckv1beta1.PubSub
+	err  error
+}
+
+func (f *fakeInformerFactory) Get(gvr schema.GroupVersionResource) (cache.SharedIndexInformer, cache.GenericLister, error) {
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, obj := range f.objs[gvr] {
+		indexer.Add(obj)
+	}
+	return nil, cache.NewGenericLister(indexer, gvr.GroupResource()), nil
+}
+
+func newPubSub(namespace, name string, ready bool, lbls map[string]string) *duckv1beta1.PubSub {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	ps := &duckv1beta1.PubSub{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: lbls},
+	}
+	ps.Status.Conditions = duckv1.Conditions{{Type: apis.ConditionReady, Status: status}}
+	ps.Status.SinkURI = sinkURI
+	ps.Status.ProjectID = "project"
+	ps.Status.TopicID = "topic-" + name
+	return ps
+}
+
+func TestLister(t *testing.T) {
+	factory := &fakeInformerFactory{
+		objs: map[schema.GroupVersionResource][]*duckv1beta1.PubSub{
+			storageGVR: {
+				newPubSub("ns2", "storage", true, nil),
+				newPubSub("ns1", "storage", false, map[string]string{"team": "a"}),
+			},
+			pubsubGVR: {
+				newPubSub("ns1", "pubsub", true, map[string]string{"team": "a"}),
+			},
+		},
+	}
+	l, err := NewLister(factory, storageGVR, pubsubGVR)
+	if err != nil {
+		t.Fatalf("NewLister() = %v", err)
+	}
+
+	source := func(gvr schema.GroupVersionResource, namespace, name string, ready bool) *GCPSource {
+		return &GCPSource{
+			Resource:  gvr,
+			Namespace: namespace,
+			Name:      name,
+			Sink:      sinkURI,
+			ProjectID: "project",
+			TopicID:   "topic-" + name,
+			Ready:     ready,
+		}
+	}
+
+	testCases := map[string]struct {
+		namespace string
+		selector  labels.Selector
+		want      []*GCPSource
+	}{
+		"all namespaces": {
+			selector: labels.Everything(),
+			want: []*GCPSource{
+				source(storageGVR, "ns1", "storage", false),
+				source(storageGVR, "ns2", "storage", true),
+				source(pubsubGVR, "ns1", "pubsub", true),
+			},
+		},
+		"one namespace": {
+			namespace: "ns2",
+			selector:  labels.Everything(),
+			want: []*GCPSource{
+				source(storageGVR, "ns2", "storage", true),
+			},
+		},
+		"label selector": {
+			selector: labels.SelectorFromSet(labels.Set{"team": "a"}),
+			want: []*GCPSource{
+				source(storageGVR, "ns1", "storage", false),
+				source(pubsubGVR, "ns1", "pubsub", true),
+			},
+		},
+		"no match": {
+			namespace: "other",
+			selector:  labels.Everything(),
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := l.List(tc.namespace, tc.selector)
+			if err != nil {
+				t.Fatalf("List() = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected sources (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestNewListerDefaultsToAllSources(t *testing.T) {
+	l, err := NewLister(&fakeInformerFactory{})
+	if err != nil {
+		t.Fatalf("NewLister() = %v", err)
+	}
+	if diff := cmp.Diff(GVRs, l.gvrs); diff != "" {
+		t.Errorf("unexpected resources (-want, +got) = %v", diff)
+	}
+}
+
+func TestNewListerError(t *testing.T) {
+	if _, err := NewLister(&fakeInformerFactory{err: errors.New("no such resource")}); err == nil {
+		t.Error("NewLister() = nil, wanted error")
+	}
+}
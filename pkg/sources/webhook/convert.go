@@ -0,0 +1,139 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// converter converts an authenticated webhook request with header and body
+// into a CloudEvent. source is the default source of the event.
+type converter func(source string, header http.Header, body []byte) (*event.Event, error)
+
+// newConverter returns the converter of the webhook requests of provider.
+func newConverter(provider v1beta1.WebhookProvider) (converter, error) {
+	switch provider {
+	case v1beta1.WebhookProviderGeneric:
+		return convertGeneric, nil
+	case v1beta1.WebhookProviderGitHub:
+		return convertGitHub, nil
+	case v1beta1.WebhookProviderStripe:
+		return convertStripe, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+// newEvent returns an event with the request body as its data.
+func newEvent(id, source, eventType string, header http.Header, body []byte) *event.Event {
+	e := event.New(event.CloudEventsVersionV1)
+	e.SetID(id)
+	e.SetSource(source)
+	e.SetType(eventType)
+	if ct := header.Get("Content-Type"); ct != "" {
+		e.SetDataContentType(ct)
+	}
+	e.DataEncoded = body
+	return &e
+}
+
+// convertGeneric converts requests of any provider. The ID of the event is
+// random, as there's no common header for it.
+func convertGeneric(source string, header http.Header, body []byte) (*event.Event, error) {
+	e := newEvent(uuid.New().String(), source, v1beta1.WebhookSourceGenericEventType, header, body)
+	e.SetTime(time.Now())
+	return e, nil
+}
+
+// gitHubPayload holds the fields of GitHub webhook payloads used in the
+// attributes of the event.
+type gitHubPayload struct {
+	Action     string `json:"action"`
+	Repository struct {
+		HTMLURL string `json:"html_url"`
+	} `json:"repository"`
+}
+
+// convertGitHub converts GitHub requests, see
+// https://docs.github.com/en/developers/webhooks-and-events/webhook-events-and-payloads.
+// The type of the event is the X-GitHub-Event header, e.g. com.github.push,
+// and its source the repository, if any.
+func convertGitHub(source string, header http.Header, body []byte) (*event.Event, error) {
+	eventType := header.Get("X-GitHub-Event")
+	if eventType == "" {
+		return nil, errors.New("missing X-GitHub-Event header")
+	}
+	id := header.Get("X-GitHub-Delivery")
+	if id == "" {
+		return nil, errors.New("missing X-GitHub-Delivery header")
+	}
+
+	// Payloads of form encoded requests aren't inspected.
+	var payload gitHubPayload
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == "application/json" {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return nil, fmt.Errorf("invalid payload: %w", err)
+		}
+	}
+	if payload.Repository.HTMLURL != "" {
+		source = payload.Repository.HTMLURL
+	}
+	e := newEvent(id, source, v1beta1.WebhookSourceGitHubEventTypePrefix+eventType, header, body)
+	e.SetTime(time.Now())
+	if payload.Action != "" {
+		e.SetSubject(payload.Action)
+	}
+	return e, nil
+}
+
+// stripePayload holds the fields of Stripe events used in the attributes of
+// the event.
+type stripePayload struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+}
+
+// convertStripe converts Stripe requests, which hold a Stripe event, see
+// https://stripe.com/docs/api/events. The ID, type and time of the event are
+// the ones of the Stripe event, e.g. com.stripe.invoice.paid.
+func convertStripe(source string, header http.Header, body []byte) (*event.Event, error) {
+	var payload stripePayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if payload.ID == "" || payload.Type == "" {
+		return nil, errors.New("payload is not a Stripe event")
+	}
+	e := newEvent(payload.ID, source, v1beta1.WebhookSourceStripeEventTypePrefix+payload.Type, header, body)
+	if payload.Created != 0 {
+		e.SetTime(time.Unix(payload.Created, 0))
+	} else {
+		e.SetTime(time.Now())
+	}
+	return e, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const testSource = "//webhook.events.cloud.google.com/namespaces/ns/webhooksources/hooks"
+
+func TestConvertGitHub(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("X-GitHub-Event", "pull_request")
+	header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	body := []byte(`{"action":"opened","repository":{"html_url":"https://github.com/google/knative-gcp"}}`)
+
+	e, err := convertGitHub(testSource, header, body)
+	if err != nil {
+		t.Fatalf("convertGitHub() failed: %v", err)
+	}
+	if got, want := e.ID(), "72d3162e-cc78-11e3-81ab-4c9367dc0958"; got != want {
+		t.Errorf("ID = %q, want %q", got, want)
+	}
+	if got, want := e.Type(), "com.github.pull_request"; got != want {
+		t.Errorf("Type = %q, want %q", got, want)
+	}
+	if got, want := e.Source(), "https://github.com/google/knative-gcp"; got != want {
+		t.Errorf("Source = %q, want %q", got, want)
+	}
+	if got, want := e.Subject(), "opened"; got != want {
+		t.Errorf("Subject = %q, want %q", got, want)
+	}
+	if got, want := string(e.Data()), string(body); got != want {
+		t.Errorf("Data = %q, want %q", got, want)
+	}
+
+	header.Del("X-GitHub-Event")
+	if _, err := convertGitHub(testSource, header, body); err == nil {
+		t.Error("convertGitHub() without X-GitHub-Event succeeded, want error")
+	}
+}
+
+func TestConvertGitHubFormEncoded(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("X-GitHub-Event", "ping")
+	header.Set("X-GitHub-Delivery", "1")
+
+	e, err := convertGitHub(testSource, header, []byte("payload=%7B%7D"))
+	if err != nil {
+		t.Fatalf("convertGitHub() failed: %v", err)
+	}
+	if got := e.Source(); got != testSource {
+		t.Errorf("Source = %q, want %q", got, testSource)
+	}
+}
+
+func TestConvertStripe(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	body := []byte(`{"id":"evt_1","object":"event","type":"invoice.paid","created":1600000000}`)
+
+	e, err := convertStripe(testSource, header, body)
+	if err != nil {
+		t.Fatalf("convertStripe() failed: %v", err)
+	}
+	if got, want := e.ID(), "evt_1"; got != want {
+		t.Errorf("ID = %q, want %q", got, want)
+	}
+	if got, want := e.Type(), "com.stripe.invoice.paid"; got != want {
+		t.Errorf("Type = %q, want %q", got, want)
+	}
+	if got, want := e.Time(), time.Unix(1600000000, 0); !got.Equal(want) {
+		t.Errorf("Time = %v, want %v", got, want)
+	}
+	if got := e.Source(); got != testSource {
+		t.Errorf("Source = %q, want %q", got, testSource)
+	}
+
+	if _, err := convertStripe(testSource, header, []byte(`{"zen":"Keep it logically awesome."}`)); err == nil {
+		t.Error("convertStripe() of a non-event succeeded, want error")
+	}
+}
+
+func TestConvertGeneric(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain")
+
+	e, err := convertGeneric(testSource, header, []byte("hello"))
+	if err != nil {
+		t.Fatalf("convertGeneric() failed: %v", err)
+	}
+	if e.ID() == "" {
+		t.Error("ID is empty")
+	}
+	if got, want := e.Type(), v1beta1.WebhookSourceGenericEventType; got != want {
+		t.Errorf("Type = %q, want %q", got, want)
+	}
+	if got, want := e.DataContentType(), "text/plain"; got != want {
+		t.Errorf("DataContentType = %q, want %q", got, want)
+	}
+	if err := e.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
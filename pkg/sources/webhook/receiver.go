@@ -0,0 +1,213 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook implements the receiver of WebhookSources, which converts
+// authenticated webhook requests into CloudEvents sent to a sink.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"go.opencensus.io/plugin/ochttp"
+	"go.opencensus.io/plugin/ochttp/propagation/tracecontext"
+	"go.uber.org/zap"
+	"knative.dev/pkg/logging"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+const shutdownTimeout = 30 * time.Second
+
+// Receiver receives the webhook requests of a WebhookSource.
+type Receiver struct {
+	// Port is the port to receive webhook requests on.
+	Port int `envconfig:"PORT" default:"8080"`
+
+	// Sink is the URI events are sent to.
+	Sink string `envconfig:"SINK_URI" required:"true"`
+
+	// Provider is the provider sending the webhook requests.
+	Provider v1beta1.WebhookProvider `envconfig:"WEBHOOK_PROVIDER" default:"Generic"`
+
+	// AuthStrategy is how webhook requests are authenticated.
+	AuthStrategy v1beta1.WebhookAuthStrategy `envconfig:"WEBHOOK_AUTH_STRATEGY" default:"HMAC"`
+
+	// AuthHeader is the request header holding the signature or the API key.
+	AuthHeader string `envconfig:"WEBHOOK_AUTH_HEADER" required:"true"`
+
+	// AuthSecret is the HMAC signing secret or the API key.
+	AuthSecret string `envconfig:"WEBHOOK_AUTH_SECRET" required:"true"`
+
+	// EventSource is the default source of the events.
+	EventSource string `envconfig:"WEBHOOK_EVENT_SOURCE" required:"true"`
+
+	// ExtensionsBase64 is a based64 encoded json string of a map of
+	// CloudEvents extensions (key-value pairs) override onto the outbound
+	// event.
+	ExtensionsBase64 string `envconfig:"K_CE_EXTENSIONS"`
+
+	// MaxBodyBytes is the largest request body accepted. Larger requests are
+	// rejected with 413 Request Entity Too Large.
+	MaxBodyBytes int64 `envconfig:"MAX_BODY_BYTES" default:"10485760"`
+
+	verifier   verifier
+	convert    converter
+	extensions map[string]string
+	client     *http.Client
+}
+
+// Start receives webhook requests until ctx is done.
+func (r *Receiver) Start(ctx context.Context) error {
+	if err := r.init(); err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(r.Port))
+	if err != nil {
+		return err
+	}
+	return r.serve(ctx, listener)
+}
+
+func (r *Receiver) init() error {
+	var err error
+	if r.verifier, err = newVerifier(r.Provider, r.AuthStrategy, r.AuthHeader, []byte(r.AuthSecret)); err != nil {
+		return err
+	}
+	if r.convert, err = newConverter(r.Provider); err != nil {
+		return err
+	}
+	if r.ExtensionsBase64 != "" {
+		if r.extensions, err = utils.Base64ToMap(r.ExtensionsBase64); err != nil {
+			return fmt.Errorf("failed to decode the CloudEvent overrides: %w", err)
+		}
+	}
+	r.client = &http.Client{
+		Transport: &ochttp.Transport{
+			Base:        http.DefaultTransport,
+			Propagation: &tracecontext.HTTPFormat{},
+		},
+	}
+	return nil
+}
+
+func (r *Receiver) serve(ctx context.Context, listener net.Listener) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("/", r)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// ServeHTTP authenticates and converts a webhook request, and sends the
+// event to the sink. Failures to send are reported with 502 Bad Gateway, so
+// that providers retrying failed requests, like Stripe, send them again.
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are accepted", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.ContentLength > r.MaxBodyBytes {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, r.MaxBodyBytes+1))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(body)) > r.MaxBodyBytes {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	ctx := req.Context()
+	logger := logging.FromContext(ctx)
+	if err := r.verifier.verify(req.Header, body); err != nil {
+		logger.Debugw("Rejected unauthenticated webhook request", zap.String("remoteAddr", req.RemoteAddr))
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	event, err := r.convert(r.EventSource, req.Header, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for k, v := range r.extensions {
+		event.SetExtension(k, v)
+	}
+
+	if err := r.send(ctx, binding.ToMessage(event)); err != nil {
+		logger.Errorw("Failed to send webhook event", zap.String("id", event.ID()), zap.String("type", event.Type()), zap.Error(err))
+		http.Error(w, "failed to send event", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// send sends msg to the sink in binary mode.
+func (r *Receiver) send(ctx context.Context, msg binding.Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Sink, nil)
+	if err != nil {
+		return err
+	}
+	if err := cehttp.WriteRequest(ctx, msg, req); err != nil {
+		return err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Drain the body so that the connection can be reused.
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		return errors.New(http.StatusText(resp.StatusCode))
+	}
+	return nil
+}
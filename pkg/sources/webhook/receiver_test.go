@@ -0,0 +1,129 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+func TestReceiver(t *testing.T) {
+	var sinkStatus int
+	var got *http.Request
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		w.WriteHeader(sinkStatus)
+	}))
+	defer sink.Close()
+
+	extensions, err := utils.MapToBase64(map[string]string{"team": "payments"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Receiver{
+		Sink:             sink.URL,
+		Provider:         v1beta1.WebhookProviderGitHub,
+		AuthStrategy:     v1beta1.WebhookAuthHMAC,
+		AuthHeader:       v1beta1.WebhookSourceGitHubSignatureHeader,
+		AuthSecret:       testSecret,
+		EventSource:      testSource,
+		ExtensionsBase64: extensions,
+		MaxBodyBytes:     64,
+	}
+	if err := r.init(); err != nil {
+		t.Fatalf("init() failed: %v", err)
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		body       string
+		signature  string
+		sinkStatus int
+		wantStatus int
+		wantSent   bool
+	}{{
+		name:       "sent",
+		method:     http.MethodPost,
+		body:       testBody,
+		signature:  "sha256=" + sign(testBody),
+		sinkStatus: http.StatusAccepted,
+		wantStatus: http.StatusAccepted,
+		wantSent:   true,
+	}, {
+		name:       "sink failed",
+		method:     http.MethodPost,
+		body:       testBody,
+		signature:  "sha256=" + sign(testBody),
+		sinkStatus: http.StatusInternalServerError,
+		wantStatus: http.StatusBadGateway,
+		wantSent:   true,
+	}, {
+		name:       "unauthenticated",
+		method:     http.MethodPost,
+		body:       testBody,
+		signature:  "sha256=" + sign("{}"),
+		wantStatus: http.StatusUnauthorized,
+	}, {
+		name:       "too large",
+		method:     http.MethodPost,
+		body:       testBody + strings.Repeat(" ", 64),
+		wantStatus: http.StatusRequestEntityTooLarge,
+	}, {
+		name:       "not a post",
+		method:     http.MethodGet,
+		wantStatus: http.StatusMethodNotAllowed,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, sinkStatus = nil, tc.sinkStatus
+			req := httptest.NewRequest(tc.method, "/", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-GitHub-Event", "push")
+			req.Header.Set("X-GitHub-Delivery", "delivery-1")
+			req.Header.Set(v1beta1.WebhookSourceGitHubSignatureHeader, tc.signature)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req.WithContext(context.Background()))
+
+			if w.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tc.wantStatus)
+			}
+			if sent := got != nil; sent != tc.wantSent {
+				t.Fatalf("sent = %v, want %v", sent, tc.wantSent)
+			}
+			if !tc.wantSent {
+				return
+			}
+			for header, want := range map[string]string{
+				"Ce-Id":     "delivery-1",
+				"Ce-Type":   "com.github.push",
+				"Ce-Source": testSource,
+				"Ce-Team":   "payments",
+			} {
+				if v := got.Header.Get(header); v != want {
+					t.Errorf("%s = %q, want %q", header, v, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// stripeTolerance is how old the timestamp of a Stripe signature can be, to
+// protect against replayed requests. It's the default of the Stripe
+// libraries.
+const stripeTolerance = 5 * time.Minute
+
+// errUnauthenticated is returned when a request fails authentication. It
+// doesn't tell why, so as not to help forging requests.
+var errUnauthenticated = errors.New("request failed authentication")
+
+// verifier authenticates webhook requests.
+type verifier interface {
+	// verify returns errUnauthenticated if the request with header and body
+	// isn't authentic.
+	verify(header http.Header, body []byte) error
+}
+
+// newVerifier returns the verifier of the webhook requests of provider, which
+// hold the signature or the API key in header.
+func newVerifier(provider v1beta1.WebhookProvider, strategy v1beta1.WebhookAuthStrategy, header string, secret []byte) (verifier, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("the webhook secret is empty")
+	}
+	switch strategy {
+	case v1beta1.WebhookAuthAPIKey:
+		if provider != v1beta1.WebhookProviderGeneric {
+			return nil, fmt.Errorf("%s webhooks don't support API keys", provider)
+		}
+		return &apiKeyVerifier{header: header, key: secret}, nil
+	case v1beta1.WebhookAuthHMAC:
+	default:
+		return nil, fmt.Errorf("unknown auth strategy %q", strategy)
+	}
+	switch provider {
+	case v1beta1.WebhookProviderGeneric, v1beta1.WebhookProviderGitHub:
+		return &hmacVerifier{header: header, secret: secret}, nil
+	case v1beta1.WebhookProviderStripe:
+		return &stripeVerifier{header: header, secret: secret, tolerance: stripeTolerance, now: time.Now}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", provider)
+	}
+}
+
+// apiKeyVerifier authenticates requests holding the API key in a header.
+type apiKeyVerifier struct {
+	header string
+	key    []byte
+}
+
+func (v *apiKeyVerifier) verify(header http.Header, _ []byte) error {
+	if subtle.ConstantTimeCompare([]byte(header.Get(v.header)), v.key) != 1 {
+		return errUnauthenticated
+	}
+	return nil
+}
+
+// hmacVerifier authenticates requests holding the hex encoded HMAC-SHA256 of
+// their body in a header, optionally prefixed with sha256= like GitHub does.
+type hmacVerifier struct {
+	header string
+	secret []byte
+}
+
+func (v *hmacVerifier) verify(header http.Header, body []byte) error {
+	signature := strings.TrimPrefix(header.Get(v.header), "sha256=")
+	if !validSignature(v.secret, body, signature) {
+		return errUnauthenticated
+	}
+	return nil
+}
+
+// stripeVerifier authenticates Stripe requests. Their signature header holds
+// the timestamp of the signature and one or more signatures, e.g.
+// t=1492774577,v1=5257a869...,v1=6ffbb59b..., where each signature is the
+// HMAC-SHA256 of the timestamp, a dot and the body.
+type stripeVerifier struct {
+	header    string
+	secret    []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+func (v *stripeVerifier) verify(header http.Header, body []byte) error {
+	var timestamp string
+	var signatures []string
+	for _, item := range strings.Split(header.Get(v.header), ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	t, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || v.now().Sub(time.Unix(t, 0)) > v.tolerance {
+		return errUnauthenticated
+	}
+
+	payload := make([]byte, 0, len(timestamp)+1+len(body))
+	payload = append(append(append(payload, timestamp...), '.'), body...)
+	for _, signature := range signatures {
+		if validSignature(v.secret, payload, signature) {
+			return nil
+		}
+	}
+	return errUnauthenticated
+}
+
+// validSignature returns true if signature is the hex encoded HMAC-SHA256 of
+// payload keyed with secret.
+func validSignature(secret, payload []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
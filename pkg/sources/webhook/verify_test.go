@@ -0,0 +1,174 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+const (
+	testSecret = "It's a Secret to Everybody"
+	testBody   = `{"zen":"Keep it logically awesome."}`
+)
+
+func sign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifier(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	stripeSignature := func(timestamp time.Time, signatures ...string) string {
+		header := fmt.Sprintf("t=%d", timestamp.Unix())
+		for _, s := range signatures {
+			header += ",v1=" + s
+		}
+		return header
+	}
+	stripeSigned := func(timestamp time.Time) string {
+		return sign(fmt.Sprintf("%d.%s", timestamp.Unix(), testBody))
+	}
+
+	testCases := []struct {
+		name     string
+		provider v1beta1.WebhookProvider
+		strategy v1beta1.WebhookAuthStrategy
+		header   string
+		value    string
+		wantErr  bool
+	}{{
+		name:     "generic hmac",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   "X-Signature",
+		value:    sign(testBody),
+	}, {
+		name:     "generic hmac with prefix",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   "X-Signature",
+		value:    "sha256=" + sign(testBody),
+	}, {
+		name:     "generic hmac of other body",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   "X-Signature",
+		value:    sign("{}"),
+		wantErr:  true,
+	}, {
+		name:     "generic hmac not hex",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   "X-Signature",
+		value:    "not-a-signature",
+		wantErr:  true,
+	}, {
+		name:     "github",
+		provider: v1beta1.WebhookProviderGitHub,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceGitHubSignatureHeader,
+		value:    "sha256=" + sign(testBody),
+	}, {
+		name:     "github missing signature",
+		provider: v1beta1.WebhookProviderGitHub,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceGitHubSignatureHeader,
+		wantErr:  true,
+	}, {
+		name:     "stripe",
+		provider: v1beta1.WebhookProviderStripe,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceStripeSignatureHeader,
+		value:    stripeSignature(now, stripeSigned(now)),
+	}, {
+		name:     "stripe with rolled secret",
+		provider: v1beta1.WebhookProviderStripe,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceStripeSignatureHeader,
+		value:    stripeSignature(now, "0123", stripeSigned(now)),
+	}, {
+		name:     "stripe signature of other timestamp",
+		provider: v1beta1.WebhookProviderStripe,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceStripeSignatureHeader,
+		value:    stripeSignature(now, stripeSigned(now.Add(-time.Second))),
+		wantErr:  true,
+	}, {
+		name:     "stripe replayed",
+		provider: v1beta1.WebhookProviderStripe,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceStripeSignatureHeader,
+		value:    stripeSignature(now.Add(-time.Hour), stripeSigned(now.Add(-time.Hour))),
+		wantErr:  true,
+	}, {
+		name:     "stripe missing timestamp",
+		provider: v1beta1.WebhookProviderStripe,
+		strategy: v1beta1.WebhookAuthHMAC,
+		header:   v1beta1.WebhookSourceStripeSignatureHeader,
+		value:    "v1=" + sign(testBody),
+		wantErr:  true,
+	}, {
+		name:     "api key",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthAPIKey,
+		header:   "X-API-Key",
+		value:    testSecret,
+	}, {
+		name:     "wrong api key",
+		provider: v1beta1.WebhookProviderGeneric,
+		strategy: v1beta1.WebhookAuthAPIKey,
+		header:   "X-API-Key",
+		value:    "It's a Secret to Nobody",
+		wantErr:  true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v, err := newVerifier(tc.provider, tc.strategy, tc.header, []byte(testSecret))
+			if err != nil {
+				t.Fatalf("newVerifier() failed: %v", err)
+			}
+			if sv, ok := v.(*stripeVerifier); ok {
+				sv.now = func() time.Time { return now }
+			}
+			header := http.Header{}
+			if tc.value != "" {
+				header.Set(tc.header, tc.value)
+			}
+			if err := v.verify(header, []byte(testBody)); (err != nil) != tc.wantErr {
+				t.Errorf("verify() = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifierErrors(t *testing.T) {
+	if _, err := newVerifier(v1beta1.WebhookProviderGitHub, v1beta1.WebhookAuthHMAC, "X-Hub-Signature-256", nil); err == nil {
+		t.Error("newVerifier() with empty secret succeeded, want error")
+	}
+	if _, err := newVerifier(v1beta1.WebhookProviderStripe, v1beta1.WebhookAuthAPIKey, "X-API-Key", []byte(testSecret)); err == nil {
+		t.Error("newVerifier() of Stripe API keys succeeded, want error")
+	}
+}
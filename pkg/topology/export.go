@@ -0,0 +1,280 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/eventing/pkg/apis/eventing"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+	"github.com/google/knative-gcp/pkg/reconciler/broker/resources"
+)
+
+// lastAppliedAnnotation is set by kubectl apply and not worth carrying over.
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// Export returns a snapshot of the Brokers of the googlecloud class, their
+// Triggers and the sources in namespace, or in all namespaces if it is empty.
+// Sources controlled by another object, e.g. a SourceSet, are left to it.
+func Export(client clientset.Interface, namespace string) (*Snapshot, error) {
+	s := &Snapshot{Version: Version}
+	opts := metav1.ListOptions{}
+
+	brokers, err := client.EventingV1beta1().Brokers(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	exported := make(map[string]bool)
+	for _, b := range brokers.Items {
+		if b.Annotations[eventing.BrokerClassKey] != brokerv1beta1.BrokerClass {
+			continue
+		}
+		exported[b.Namespace+"/"+b.Name] = true
+		s.Resources = append(s.Resources,
+			resource("Broker", &b.ObjectMeta, TopicResource, "", resources.GenerateDecouplingTopicName(&b)),
+			resource("Broker", &b.ObjectMeta, SubscriptionResource, "", resources.GenerateDecouplingSubscriptionName(&b)))
+		s.Brokers = append(s.Brokers, brokerv1beta1.Broker{
+			TypeMeta:   metav1.TypeMeta{APIVersion: brokerv1beta1.SchemeGroupVersion.String(), Kind: "Broker"},
+			ObjectMeta: exportMeta(b.ObjectMeta),
+			Spec:       b.Spec,
+		})
+	}
+
+	triggers, err := client.EventingV1beta1().Triggers(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range triggers.Items {
+		if !exported[t.Namespace+"/"+t.Spec.Broker] {
+			continue
+		}
+		s.Resources = append(s.Resources,
+			resource("Trigger", &t.ObjectMeta, TopicResource, "", resources.GenerateRetryTopicName(&t)),
+			resource("Trigger", &t.ObjectMeta, SubscriptionResource, "", resources.GenerateRetrySubscriptionName(&t)))
+		s.Triggers = append(s.Triggers, brokerv1beta1.Trigger{
+			TypeMeta:   metav1.TypeMeta{APIVersion: brokerv1beta1.SchemeGroupVersion.String(), Kind: "Trigger"},
+			ObjectMeta: exportMeta(t.ObjectMeta),
+			Spec:       t.Spec,
+		})
+	}
+
+	events := client.EventsV1beta1()
+	sourceType := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: eventsv1beta1.SchemeGroupVersion.String(), Kind: kind}
+	}
+
+	registries, err := events.CloudArtifactRegistrySources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range registries.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudArtifactRegistrySource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		s.CloudArtifactRegistrySources = append(s.CloudArtifactRegistrySources, eventsv1beta1.CloudArtifactRegistrySource{
+			TypeMeta:   sourceType("CloudArtifactRegistrySource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	auditLogs, err := events.CloudAuditLogsSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range auditLogs.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudAuditLogsSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		if src.Status.StackdriverSink != "" {
+			s.Resources = append(s.Resources, resource("CloudAuditLogsSource", &src.ObjectMeta, SinkResource, src.Status.ProjectID, src.Status.StackdriverSink))
+		}
+		s.CloudAuditLogsSources = append(s.CloudAuditLogsSources, eventsv1beta1.CloudAuditLogsSource{
+			TypeMeta:   sourceType("CloudAuditLogsSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	budgets, err := events.CloudBillingBudgetSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range budgets.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudBillingBudgetSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		s.CloudBillingBudgetSources = append(s.CloudBillingBudgetSources, eventsv1beta1.CloudBillingBudgetSource{
+			TypeMeta:   sourceType("CloudBillingBudgetSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	builds, err := events.CloudBuildSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range builds.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudBuildSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		s.CloudBuildSources = append(s.CloudBuildSources, eventsv1beta1.CloudBuildSource{
+			TypeMeta:   sourceType("CloudBuildSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	alerts, err := events.CloudMonitoringAlertSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range alerts.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudMonitoringAlertSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		if src.Status.NotificationChannel != "" {
+			s.Resources = append(s.Resources, resource("CloudMonitoringAlertSource", &src.ObjectMeta, ChannelResource, src.Status.ProjectID, src.Status.NotificationChannel))
+		}
+		s.CloudMonitoringAlertSources = append(s.CloudMonitoringAlertSources, eventsv1beta1.CloudMonitoringAlertSource{
+			TypeMeta:   sourceType("CloudMonitoringAlertSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	pubsubs, err := events.CloudPubSubSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range pubsubs.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudPubSubSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		s.CloudPubSubSources = append(s.CloudPubSubSources, eventsv1beta1.CloudPubSubSource{
+			TypeMeta:   sourceType("CloudPubSubSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	schedulers, err := events.CloudSchedulerSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range schedulers.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudSchedulerSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		if src.Status.JobName != "" {
+			s.Resources = append(s.Resources, resource("CloudSchedulerSource", &src.ObjectMeta, JobResource, src.Status.ProjectID, src.Status.JobName))
+		}
+		s.CloudSchedulerSources = append(s.CloudSchedulerSources, eventsv1beta1.CloudSchedulerSource{
+			TypeMeta:   sourceType("CloudSchedulerSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	storages, err := events.CloudStorageSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range storages.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("CloudStorageSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		if src.Status.NotificationID != "" {
+			s.Resources = append(s.Resources, resource("CloudStorageSource", &src.ObjectMeta, NotificationResource, src.Status.ProjectID, src.Status.NotificationID))
+		}
+		s.CloudStorageSources = append(s.CloudStorageSources, eventsv1beta1.CloudStorageSource{
+			TypeMeta:   sourceType("CloudStorageSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+
+	rotations, err := events.SecretManagerRotationSources(namespace).List(opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range rotations.Items {
+		if metav1.GetControllerOf(&src) != nil {
+			continue
+		}
+		s.addPubSubResources("SecretManagerRotationSource", &src.ObjectMeta, &src.Status.PubSubStatus)
+		s.SecretManagerRotationSources = append(s.SecretManagerRotationSources, eventsv1beta1.SecretManagerRotationSource{
+			TypeMeta:   sourceType("SecretManagerRotationSource"),
+			ObjectMeta: exportMeta(src.ObjectMeta),
+			Spec:       src.Spec,
+		})
+	}
+	return s, nil
+}
+
+// addPubSubResources adds the Pub/Sub topic and subscription of a source.
+func (s *Snapshot) addPubSubResources(kind string, meta *metav1.ObjectMeta, status *duckv1beta1.PubSubStatus) {
+	if status.TopicID != "" {
+		s.Resources = append(s.Resources, resource(kind, meta, TopicResource, status.ProjectID, status.TopicID))
+	}
+	if status.SubscriptionID != "" {
+		s.Resources = append(s.Resources, resource(kind, meta, SubscriptionResource, status.ProjectID, status.SubscriptionID))
+	}
+}
+
+func resource(kind string, meta *metav1.ObjectMeta, resourceType, project, id string) Resource {
+	return Resource{
+		Kind:      kind,
+		Namespace: meta.Namespace,
+		Name:      meta.Name,
+		Type:      resourceType,
+		Project:   project,
+		ID:        id,
+	}
+}
+
+// exportMeta keeps the parts of meta that are needed to recreate the object.
+func exportMeta(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	var annotations map[string]string
+	for k, v := range meta.Annotations {
+		if k == lastAppliedAnnotation {
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[k] = v
+	}
+	return metav1.ObjectMeta{
+		Namespace:   meta.Namespace,
+		Name:        meta.Name,
+		Labels:      meta.Labels,
+		Annotations: annotations,
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/multierr"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	clientset "github.com/google/knative-gcp/pkg/client/clientset/versioned"
+	"github.com/google/knative-gcp/pkg/utils"
+)
+
+// Import creates the objects of s, with their namespaces and projects mapped
+// by remap. Objects that already exist are left unchanged, so that an
+// interrupted import can be resumed. The controller then creates new Google
+// Cloud resources for the objects, see MapIDs.
+func Import(client clientset.Interface, s *Snapshot, remap Remap) error {
+	if s.Version != Version {
+		return fmt.Errorf("unsupported snapshot version %q, expected %q", s.Version, Version)
+	}
+	var errs error
+	create := func(kind, name string, err error) {
+		if err != nil && !apierrs.IsAlreadyExists(err) {
+			errs = multierr.Append(errs, fmt.Errorf("failed to create %s %s: %w", kind, name, err))
+		}
+	}
+
+	for i := range s.Brokers {
+		b := s.Brokers[i].DeepCopy()
+		b.Namespace = remap.namespace(b.Namespace)
+		if b.Spec.Config != nil && b.Spec.Config.Namespace != "" {
+			b.Spec.Config.Namespace = remap.namespace(b.Spec.Config.Namespace)
+		}
+		if b.Spec.Delivery != nil {
+			remap.destination(b.Spec.Delivery.DeadLetterSink)
+		}
+		_, err := client.EventingV1beta1().Brokers(b.Namespace).Create(b)
+		create("Broker", b.Namespace+"/"+b.Name, err)
+	}
+	for i := range s.Triggers {
+		t := s.Triggers[i].DeepCopy()
+		t.Namespace = remap.namespace(t.Namespace)
+		remap.destination(&t.Spec.Subscriber)
+		_, err := client.EventingV1beta1().Triggers(t.Namespace).Create(t)
+		create("Trigger", t.Namespace+"/"+t.Name, err)
+	}
+
+	events := client.EventsV1beta1()
+	for i := range s.CloudArtifactRegistrySources {
+		src := s.CloudArtifactRegistrySources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		_, err := events.CloudArtifactRegistrySources(src.Namespace).Create(src)
+		create("CloudArtifactRegistrySource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudAuditLogsSources {
+		src := s.CloudAuditLogsSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		src.Spec.ResourceName = remap.resourceName(src.Spec.ResourceName)
+		_, err := events.CloudAuditLogsSources(src.Namespace).Create(src)
+		create("CloudAuditLogsSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudBillingBudgetSources {
+		src := s.CloudBillingBudgetSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		if project, id, err := utils.ParseTopic(src.Spec.Topic); err == nil && project != "" {
+			src.Spec.Topic = fmt.Sprintf("projects/%s/topics/%s", remap.project(project), id)
+		}
+		_, err := events.CloudBillingBudgetSources(src.Namespace).Create(src)
+		create("CloudBillingBudgetSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudBuildSources {
+		src := s.CloudBuildSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		_, err := events.CloudBuildSources(src.Namespace).Create(src)
+		create("CloudBuildSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudMonitoringAlertSources {
+		src := s.CloudMonitoringAlertSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		_, err := events.CloudMonitoringAlertSources(src.Namespace).Create(src)
+		create("CloudMonitoringAlertSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudPubSubSources {
+		src := s.CloudPubSubSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		if project, id, err := utils.ParseTopic(src.Spec.Topic); err == nil && project != "" {
+			src.Spec.Topic = fmt.Sprintf("projects/%s/topics/%s", remap.project(project), id)
+		}
+		_, err := events.CloudPubSubSources(src.Namespace).Create(src)
+		create("CloudPubSubSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudSchedulerSources {
+		src := s.CloudSchedulerSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		_, err := events.CloudSchedulerSources(src.Namespace).Create(src)
+		create("CloudSchedulerSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.CloudStorageSources {
+		src := s.CloudStorageSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		_, err := events.CloudStorageSources(src.Namespace).Create(src)
+		create("CloudStorageSource", src.Namespace+"/"+src.Name, err)
+	}
+	for i := range s.SecretManagerRotationSources {
+		src := s.SecretManagerRotationSources[i].DeepCopy()
+		src.Namespace = remap.namespace(src.Namespace)
+		remap.pubSubSpec(&src.Spec.PubSubSpec)
+		if project, id, err := utils.ParseTopic(src.Spec.Topic); err == nil && project != "" {
+			src.Spec.Topic = fmt.Sprintf("projects/%s/topics/%s", remap.project(project), id)
+		}
+		_, err := events.SecretManagerRotationSources(src.Namespace).Create(src)
+		create("SecretManagerRotationSource", src.Namespace+"/"+src.Name, err)
+	}
+	return errs
+}
+
+func (r Remap) destination(d *duckv1.Destination) {
+	if d != nil && d.Ref != nil && d.Ref.Namespace != "" {
+		d.Ref.Namespace = r.namespace(d.Ref.Namespace)
+	}
+}
+
+func (r Remap) pubSubSpec(spec *duckv1beta1.PubSubSpec) {
+	if spec.Project != "" {
+		spec.Project = r.project(spec.Project)
+	}
+	r.destination(&spec.Sink)
+}
+
+// resourceName maps the project of a resource name of the form
+// projects/<project>/...
+func (r Remap) resourceName(name string) string {
+	parts := strings.SplitN(name, "/", 3)
+	if len(parts) < 2 || parts[0] != "projects" {
+		return name
+	}
+	parts[1] = r.project(parts[1])
+	return strings.Join(parts, "/")
+}
+
+// IDMapping maps a Google Cloud resource of an exported object to the
+// resource of the same type of the object imported from it.
+type IDMapping struct {
+	From Resource `json:"from"`
+	To   Resource `json:"to"`
+}
+
+// MapIDs maps the resources of from, a snapshot of the original cluster, to
+// the resources of to, a snapshot of the cluster from was imported into with
+// remap, e.g. to point the applications publishing to or pulling from them at
+// the new ones. Resources without a counterpart, e.g. because the imported
+// object isn't ready yet, are left out.
+func MapIDs(from, to *Snapshot, remap Remap) []IDMapping {
+	type key struct {
+		kind, namespace, name, resourceType string
+	}
+	imported := make(map[key]Resource, len(to.Resources))
+	for _, r := range to.Resources {
+		imported[key{r.Kind, r.Namespace, r.Name, r.Type}] = r
+	}
+	var mappings []IDMapping
+	for _, r := range from.Resources {
+		if t, ok := imported[key{r.Kind, remap.namespace(r.Namespace), r.Name, r.Type}]; ok {
+			mappings = append(mappings, IDMapping{From: r, To: t})
+		}
+	}
+	return mappings
+}
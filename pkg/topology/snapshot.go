@@ -0,0 +1,120 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package topology exports the Brokers, Triggers and sources of a cluster to
+// a declarative snapshot, and imports such a snapshot into another cluster or
+// project, e.g. for disaster recovery drills.
+package topology
+
+import (
+	"fmt"
+	"strings"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+)
+
+// Version is the version of the snapshot format.
+const Version = "v1"
+
+// Snapshot is a portable manifest of the Brokers, Triggers and sources of a
+// cluster. Objects only keep their name, namespace, labels, annotations and
+// spec, while the Google Cloud resources that were created for them are
+// listed separately.
+type Snapshot struct {
+	// Version is the version of the snapshot format.
+	Version string `json:"version"`
+
+	Brokers                      []brokerv1beta1.Broker                      `json:"brokers,omitempty"`
+	Triggers                     []brokerv1beta1.Trigger                     `json:"triggers,omitempty"`
+	CloudArtifactRegistrySources []eventsv1beta1.CloudArtifactRegistrySource `json:"cloudArtifactRegistrySources,omitempty"`
+	CloudAuditLogsSources        []eventsv1beta1.CloudAuditLogsSource        `json:"cloudAuditLogsSources,omitempty"`
+	CloudBillingBudgetSources    []eventsv1beta1.CloudBillingBudgetSource    `json:"cloudBillingBudgetSources,omitempty"`
+	CloudBuildSources            []eventsv1beta1.CloudBuildSource            `json:"cloudBuildSources,omitempty"`
+	CloudMonitoringAlertSources  []eventsv1beta1.CloudMonitoringAlertSource  `json:"cloudMonitoringAlertSources,omitempty"`
+	CloudPubSubSources           []eventsv1beta1.CloudPubSubSource           `json:"cloudPubSubSources,omitempty"`
+	CloudSchedulerSources        []eventsv1beta1.CloudSchedulerSource        `json:"cloudSchedulerSources,omitempty"`
+	CloudStorageSources          []eventsv1beta1.CloudStorageSource          `json:"cloudStorageSources,omitempty"`
+	SecretManagerRotationSources []eventsv1beta1.SecretManagerRotationSource `json:"secretManagerRotationSources,omitempty"`
+
+	// Resources are the Google Cloud resources of the objects at the time of
+	// the export.
+	Resources []Resource `json:"resources,omitempty"`
+}
+
+// Resource is a Google Cloud resource created for an object of a snapshot.
+type Resource struct {
+	// Kind, Namespace and Name identify the object the resource belongs to.
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+
+	// Type is the type of the resource, e.g. "topic".
+	Type string `json:"type"`
+	// Project is the project of the resource. It is empty for the resources
+	// of Brokers and Triggers, which live in the project of the controller.
+	Project string `json:"project,omitempty"`
+	// ID is the ID of the resource.
+	ID string `json:"id"`
+}
+
+// Types of resources.
+const (
+	TopicResource        = "topic"
+	SubscriptionResource = "subscription"
+	NotificationResource = "notification"
+	JobResource          = "job"
+	ChannelResource      = "notificationchannel"
+	SinkResource         = "sink"
+)
+
+// Remap maps namespaces and projects of a snapshot to the ones they are
+// imported into. Namespaces and projects that aren't mapped are kept.
+type Remap struct {
+	Namespaces map[string]string
+	Projects   map[string]string
+}
+
+// ParseMapping parses a comma-separated list of old=new pairs, e.g.
+// "prod=dr,staging=dr-staging".
+func ParseMapping(s string) (map[string]string, error) {
+	m := make(map[string]string)
+	if s == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expected old=new", pair)
+		}
+		m[parts[0]] = parts[1]
+	}
+	return m, nil
+}
+
+func (r Remap) namespace(namespace string) string {
+	if n, ok := r.Namespaces[namespace]; ok {
+		return n
+	}
+	return namespace
+}
+
+func (r Remap) project(project string) string {
+	if p, ok := r.Projects[project]; ok {
+		return p
+	}
+	return project
+}
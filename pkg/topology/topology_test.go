@@ -0,0 +1,206 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topology
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/eventing/pkg/apis/eventing"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+
+	brokerv1beta1 "github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	duckv1beta1 "github.com/google/knative-gcp/pkg/apis/duck/v1beta1"
+	eventsv1beta1 "github.com/google/knative-gcp/pkg/apis/events/v1beta1"
+	"github.com/google/knative-gcp/pkg/client/clientset/versioned/fake"
+)
+
+func objects() []runtime.Object {
+	sink := duckv1.Destination{Ref: &duckv1.KReference{
+		APIVersion: "eventing.knative.dev/v1beta1", Kind: "Broker", Namespace: "prod", Name: "default",
+	}}
+	return []runtime.Object{
+		&brokerv1beta1.Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "prod", Name: "default", UID: "broker-uid", ResourceVersion: "1",
+				Annotations: map[string]string{
+					eventing.BrokerClassKey: brokerv1beta1.BrokerClass,
+					lastAppliedAnnotation:   "{}",
+				},
+			},
+		},
+		&brokerv1beta1.Broker{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "prod", Name: "other-class",
+				Annotations: map[string]string{eventing.BrokerClassKey: "MTChannelBasedBroker"},
+			},
+		},
+		&brokerv1beta1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "trigger", UID: "trigger-uid"},
+			Spec:       eventingv1beta1.TriggerSpec{Broker: "default", Subscriber: sink},
+		},
+		&brokerv1beta1.Trigger{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "other-trigger"},
+			Spec:       eventingv1beta1.TriggerSpec{Broker: "other-class"},
+		},
+		&eventsv1beta1.CloudPubSubSource{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "source", UID: "source-uid"},
+			Spec: eventsv1beta1.CloudPubSubSourceSpec{
+				PubSubSpec: duckv1beta1.PubSubSpec{SourceSpec: duckv1.SourceSpec{Sink: sink}, Project: "my-project"},
+				Topic:      "projects/my-project/topics/orders",
+			},
+			Status: eventsv1beta1.CloudPubSubSourceStatus{PubSubStatus: duckv1beta1.PubSubStatus{
+				ProjectID: "my-project", SubscriptionID: "cre-src_prod_source_source-uid",
+			}},
+		},
+		&eventsv1beta1.CloudStorageSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "prod", Name: "owned",
+				OwnerReferences: []metav1.OwnerReference{{Name: "sourceset", UID: types.UID("owner"), Controller: &[]bool{true}[0]}},
+			},
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	got, err := Export(fake.NewSimpleClientset(objects()...), "")
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+	sink := duckv1.Destination{Ref: &duckv1.KReference{
+		APIVersion: "eventing.knative.dev/v1beta1", Kind: "Broker", Namespace: "prod", Name: "default",
+	}}
+	want := &Snapshot{
+		Version: Version,
+		Brokers: []brokerv1beta1.Broker{{
+			TypeMeta: metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Broker"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "prod", Name: "default",
+				Annotations: map[string]string{eventing.BrokerClassKey: brokerv1beta1.BrokerClass},
+			},
+		}},
+		Triggers: []brokerv1beta1.Trigger{{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "eventing.knative.dev/v1beta1", Kind: "Trigger"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "trigger"},
+			Spec:       eventingv1beta1.TriggerSpec{Broker: "default", Subscriber: sink},
+		}},
+		CloudPubSubSources: []eventsv1beta1.CloudPubSubSource{{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "events.cloud.google.com/v1beta1", Kind: "CloudPubSubSource"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "source"},
+			Spec: eventsv1beta1.CloudPubSubSourceSpec{
+				PubSubSpec: duckv1beta1.PubSubSpec{SourceSpec: duckv1.SourceSpec{Sink: sink}, Project: "my-project"},
+				Topic:      "projects/my-project/topics/orders",
+			},
+		}},
+		Resources: []Resource{
+			{Kind: "Broker", Namespace: "prod", Name: "default", Type: TopicResource, ID: "cre-bkr_prod_default_broker-uid"},
+			{Kind: "Broker", Namespace: "prod", Name: "default", Type: SubscriptionResource, ID: "cre-bkr_prod_default_broker-uid"},
+			{Kind: "Trigger", Namespace: "prod", Name: "trigger", Type: TopicResource, ID: "cre-tgr_prod_trigger_trigger-uid"},
+			{Kind: "Trigger", Namespace: "prod", Name: "trigger", Type: SubscriptionResource, ID: "cre-tgr_prod_trigger_trigger-uid"},
+			{Kind: "CloudPubSubSource", Namespace: "prod", Name: "source", Type: SubscriptionResource, Project: "my-project", ID: "cre-src_prod_source_source-uid"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected snapshot (-want, +got): %s", diff)
+	}
+}
+
+func TestImport(t *testing.T) {
+	s, err := Export(fake.NewSimpleClientset(objects()...), "")
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+	client := fake.NewSimpleClientset()
+	remap := Remap{
+		Namespaces: map[string]string{"prod": "dr"},
+		Projects:   map[string]string{"my-project": "my-dr-project"},
+	}
+	if err := Import(client, s, remap); err != nil {
+		t.Fatalf("Import() = %v", err)
+	}
+	// Importing again leaves the existing objects alone.
+	if err := Import(client, s, remap); err != nil {
+		t.Fatalf("Import() again = %v", err)
+	}
+
+	if _, err := client.EventingV1beta1().Brokers("dr").Get("default", metav1.GetOptions{}); err != nil {
+		t.Errorf("Failed to get the imported Broker: %v", err)
+	}
+	trigger, err := client.EventingV1beta1().Triggers("dr").Get("trigger", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get the imported Trigger: %v", err)
+	}
+	if got := trigger.Spec.Subscriber.Ref.Namespace; got != "dr" {
+		t.Errorf("Unexpected subscriber namespace, got: %q, want: %q", got, "dr")
+	}
+	source, err := client.EventsV1beta1().CloudPubSubSources("dr").Get("source", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get the imported CloudPubSubSource: %v", err)
+	}
+	if got := source.Spec.Project; got != "my-dr-project" {
+		t.Errorf("Unexpected project, got: %q, want: %q", got, "my-dr-project")
+	}
+	if got, want := source.Spec.Topic, "projects/my-dr-project/topics/orders"; got != want {
+		t.Errorf("Unexpected topic, got: %q, want: %q", got, want)
+	}
+	if got := source.Spec.Sink.Ref.Namespace; got != "dr" {
+		t.Errorf("Unexpected sink namespace, got: %q, want: %q", got, "dr")
+	}
+}
+
+func TestImportUnsupportedVersion(t *testing.T) {
+	if err := Import(fake.NewSimpleClientset(), &Snapshot{Version: "v0"}, Remap{}); err == nil {
+		t.Error("Import() = nil, want an error")
+	}
+}
+
+func TestMapIDs(t *testing.T) {
+	from := &Snapshot{Resources: []Resource{
+		{Kind: "Broker", Namespace: "prod", Name: "default", Type: TopicResource, ID: "old-topic"},
+		{Kind: "Broker", Namespace: "prod", Name: "default", Type: SubscriptionResource, ID: "old-sub"},
+		{Kind: "Trigger", Namespace: "prod", Name: "not-ready", Type: TopicResource, ID: "old-retry"},
+	}}
+	to := &Snapshot{Resources: []Resource{
+		{Kind: "Broker", Namespace: "dr", Name: "default", Type: TopicResource, ID: "new-topic"},
+		{Kind: "Broker", Namespace: "dr", Name: "default", Type: SubscriptionResource, ID: "new-sub"},
+	}}
+	want := []IDMapping{
+		{From: from.Resources[0], To: to.Resources[0]},
+		{From: from.Resources[1], To: to.Resources[1]},
+	}
+	got := MapIDs(from, to, Remap{Namespaces: map[string]string{"prod": "dr"}})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected mappings (-want, +got): %s", diff)
+	}
+}
+
+func TestParseMapping(t *testing.T) {
+	got, err := ParseMapping("prod=dr,staging=dr-staging")
+	if err != nil {
+		t.Fatalf("ParseMapping() = %v", err)
+	}
+	if diff := cmp.Diff(map[string]string{"prod": "dr", "staging": "dr-staging"}, got); diff != "" {
+		t.Errorf("Unexpected mapping (-want, +got): %s", diff)
+	}
+	if _, err := ParseMapping("prod"); err == nil {
+		t.Error("ParseMapping() with an invalid pair = nil, want an error")
+	}
+}
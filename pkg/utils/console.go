@@ -0,0 +1,37 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// consoleURL is the base URL of the Google Cloud Console.
+const consoleURL = "https://console.cloud.google.com"
+
+// TopicConsoleURL returns the URL of the page of a Pub/Sub topic in the
+// Google Cloud Console.
+func TopicConsoleURL(project, id string) string {
+	return fmt.Sprintf("%s/cloudpubsub/topic/detail/%s?project=%s", consoleURL, url.PathEscape(id), url.QueryEscape(project))
+}
+
+// SubscriptionConsoleURL returns the URL of the page of a Pub/Sub
+// subscription in the Google Cloud Console.
+func SubscriptionConsoleURL(project, id string) string {
+	return fmt.Sprintf("%s/cloudpubsub/subscription/detail/%s?project=%s", consoleURL, url.PathEscape(id), url.QueryEscape(project))
+}
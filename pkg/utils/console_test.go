@@ -0,0 +1,35 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestTopicConsoleURL(t *testing.T) {
+	want := "https://console.cloud.google.com/cloudpubsub/topic/detail/cre-src_ns_name?project=my-project"
+	if got := TopicConsoleURL("my-project", "cre-src_ns_name"); got != want {
+		t.Errorf("TopicConsoleURL() got=%q, want=%q", got, want)
+	}
+}
+
+func TestSubscriptionConsoleURL(t *testing.T) {
+	want := "https://console.cloud.google.com/cloudpubsub/subscription/detail/cre-src_ns_name?project=my-project"
+	if got := SubscriptionConsoleURL("my-project", "cre-src_ns_name"); got != want {
+		t.Errorf("SubscriptionConsoleURL() got=%q, want=%q", got, want)
+	}
+}
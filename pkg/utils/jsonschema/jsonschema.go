@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jsonschema compiles the JSON schemas written by users. Unlike the
+// default gojsonschema loaders, it never follows references to URLs or files,
+// which would let anyone able to write a schema make the pods compiling it
+// fetch arbitrary URLs or read local files.
+package jsonschema
+
+import (
+	"fmt"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Compile compiles the JSON schema. References must point within the schema.
+func Compile(raw []byte) (*gojsonschema.Schema, error) {
+	return gojsonschema.NewSchemaLoader().Compile(localLoader{gojsonschema.NewBytesLoader(raw)})
+}
+
+// localLoader loads a schema whose references are loaded by refusedLoaders.
+type localLoader struct {
+	gojsonschema.JSONLoader
+}
+
+func (localLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return refusingFactory{}
+}
+
+// refusingFactory creates refusedLoaders. It is only asked for the
+// references that point outside of the schema.
+type refusingFactory struct{}
+
+func (refusingFactory) New(source string) gojsonschema.JSONLoader {
+	return refusedLoader{JSONLoader: gojsonschema.NewReferenceLoader(source), source: source}
+}
+
+// refusedLoader fails to load the referenced document.
+type refusedLoader struct {
+	gojsonschema.JSONLoader
+	source string
+}
+
+func (l refusedLoader) LoadJSON() (interface{}, error) {
+	return nil, fmt.Errorf("reference %q is not allowed, references must point within the schema", l.source)
+}
+
+func (refusedLoader) LoaderFactory() gojsonschema.JSONLoaderFactory {
+	return refusingFactory{}
+}
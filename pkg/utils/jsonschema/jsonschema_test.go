@@ -0,0 +1,90 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonschema
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+func TestCompile(t *testing.T) {
+	var fetched bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = true
+		w.Write([]byte(`{"type": "string"}`))
+	}))
+	defer srv.Close()
+	dir, err := ioutil.TempDir("", "jsonschema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "schema.json")
+	if err := ioutil.WriteFile(file, []byte(`{"type": "string"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		schema  string
+		wantErr bool
+	}{
+		"no reference": {
+			schema: `{"type": "object", "required": ["id"]}`,
+		},
+		"local reference": {
+			schema: `{"definitions": {"id": {"type": "string"}}, "properties": {"id": {"$ref": "#/definitions/id"}}}`,
+		},
+		"meta schema": {
+			schema: `{"$schema": "http://json-schema.org/draft-07/schema#", "type": "object"}`,
+		},
+		"URL reference": {
+			schema:  `{"properties": {"id": {"$ref": "` + srv.URL + `/schema.json"}}}`,
+			wantErr: true,
+		},
+		"file reference": {
+			schema:  `{"properties": {"id": {"$ref": "file://` + file + `"}}}`,
+			wantErr: true,
+		},
+		"invalid schema": {
+			schema:  `{"type": 1}`,
+			wantErr: true,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			s, err := Compile([]byte(tc.schema))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Compile() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if _, err := s.Validate(gojsonschema.NewStringLoader(`{"id": "a"}`)); err != nil {
+				t.Errorf("Validate() = %v", err)
+			}
+		})
+	}
+	if fetched {
+		t.Error("Compile() fetched the referenced URL")
+	}
+}
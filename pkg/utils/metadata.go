@@ -17,12 +17,17 @@ limitations under the License.
 package utils
 
 import (
+	"os"
+
 	metadataClient "github.com/google/knative-gcp/pkg/gclient/metadata"
 )
 
 const (
 	clusterNameAttr = "cluster-name"
 	ProjectIDEnvKey = "PROJECT_ID"
+	// ClusterNameEnvKey is the environment variable that, when set, overrides the cluster name read from the GKE
+	// metadata server, e.g. for clusters whose metadata server is unreachable or doesn't know the cluster name.
+	ClusterNameEnvKey = "CLUSTER_NAME"
 )
 
 // ProjectID returns the project ID for a particular resource.
@@ -45,9 +50,19 @@ func ClusterName(clusterName string, client metadataClient.Client) (string, erro
 	if clusterName != "" {
 		return clusterName, nil
 	}
+	// Then the override of the operator.
+	if override := ClusterNameOverride(); override != "" {
+		return override, nil
+	}
+	// Otherwise, ask GKE metadata server.
 	clusterName, err := client.InstanceAttributeValue(clusterNameAttr)
 	if err != nil {
 		return "", err
 	}
 	return clusterName, nil
 }
+
+// ClusterNameOverride returns the cluster name set by the operator through ClusterNameEnvKey, if any.
+func ClusterNameOverride() string {
+	return os.Getenv(ClusterNameEnvKey)
+}
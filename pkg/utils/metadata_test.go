@@ -18,6 +18,7 @@ package utils
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -69,10 +70,11 @@ func TestProjectID(t *testing.T) {
 
 func TestClusterName(t *testing.T) {
 	testCases := map[string]struct {
-		want  string
-		data  testingMetadataClient.TestClientData
-		input string
-		error bool
+		want     string
+		data     testingMetadataClient.TestClientData
+		input    string
+		override string
+		error    bool
 	}{
 		"cluster name exists": {
 			want:  "testing-cluster-name",
@@ -94,9 +96,27 @@ func TestClusterName(t *testing.T) {
 			input: "",
 			error: true,
 		},
+		"cluster name doesn't exist, overridden": {
+			want: "override-cluster-name",
+			data: testingMetadataClient.TestClientData{
+				ClusterNameErr: fmt.Errorf("get cluster name failed"),
+			},
+			input:    "",
+			override: "override-cluster-name",
+			error:    false,
+		},
+		"cluster name exists, overridden": {
+			want:     "testing-cluster-name",
+			data:     testingMetadataClient.TestClientData{},
+			input:    "testing-cluster-name",
+			override: "override-cluster-name",
+			error:    false,
+		},
 	}
 	for n, tc := range testCases {
 		t.Run(n, func(t *testing.T) {
+			defer os.Setenv(ClusterNameEnvKey, os.Getenv(ClusterNameEnvKey))
+			os.Setenv(ClusterNameEnvKey, tc.override)
 			client := testingMetadataClient.NewTestClient(tc.data)
 			got, err := ClusterName(tc.input, client)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
@@ -41,6 +41,14 @@ func TruncatedLoggingSinkResourceName(prefix, ns, n string, uid types.UID) strin
 	return truncateResourceName(prefix, ns, n, uid, LoggingSinkMax)
 }
 
+// TruncatedResourceName generates a deterministic name for a resource whose
+// name must fit in the given number of characters, e.g. because it is
+// embedded in a longer name. The maximum must leave room for the uid and its
+// separator.
+func TruncatedResourceName(prefix, ns, n string, uid types.UID, maximum int) string {
+	return truncateResourceName(prefix, ns, n, uid, maximum)
+}
+
 func truncateResourceName(prefix, ns, n string, uid types.UID, maximum int) string {
 	s := fmt.Sprintf("%s_%s_%s_%s", prefix, ns, n, string(uid))
 	if len(s) <= maximum {
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// archLabel is the node label and taint key of the CPU architecture. GKE
+// taints arm64 nodes with it, so that only pods tolerating it run there.
+const archLabel = "kubernetes.io/arch"
+
+// NodeArchitectures are the operator level settings of the CPU architectures
+// of the nodes data plane pods run on. The data plane image must be built for
+// all of them.
+type NodeArchitectures struct {
+	// Allowed are the architectures the pods may run on. Empty allows any.
+	Allowed []string `envconfig:"DATA_PLANE_NODE_ARCHITECTURES"`
+	// Preferred is the architecture of the nodes the pods are scheduled on
+	// if possible. Empty has no preference.
+	Preferred string `envconfig:"DATA_PLANE_PREFERRED_NODE_ARCHITECTURE"`
+}
+
+// Affinity returns the node affinity of the pods, nil if the settings are
+// empty.
+func (a NodeArchitectures) Affinity() *corev1.Affinity {
+	if len(a.Allowed) == 0 && a.Preferred == "" {
+		return nil
+	}
+	na := &corev1.NodeAffinity{}
+	if len(a.Allowed) > 0 {
+		na.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      archLabel,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   a.Allowed,
+				}},
+			}},
+		}
+	}
+	if a.Preferred != "" {
+		na.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.PreferredSchedulingTerm{{
+			Weight: 100,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      archLabel,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{a.Preferred},
+				}},
+			},
+		}}
+	}
+	return &corev1.Affinity{NodeAffinity: na}
+}
+
+// Tolerations returns the tolerations of the architecture taints of the
+// nodes the pods may run on.
+func (a NodeArchitectures) Tolerations() []corev1.Toleration {
+	var tolerations []corev1.Toleration
+	seen := make(map[string]bool)
+	for _, arch := range append(append([]string{}, a.Allowed...), a.Preferred) {
+		if arch == "" || seen[arch] {
+			continue
+		}
+		seen[arch] = true
+		tolerations = append(tolerations, corev1.Toleration{
+			Key:      archLabel,
+			Operator: corev1.TolerationOpEqual,
+			Value:    arch,
+			Effect:   corev1.TaintEffectNoSchedule,
+		})
+	}
+	return tolerations
+}
+
+// Apply sets the node affinity and tolerations of a pod spec.
+func (a NodeArchitectures) Apply(spec *corev1.PodSpec) {
+	if affinity := a.Affinity(); affinity != nil {
+		spec.Affinity = affinity
+	}
+	spec.Tolerations = append(spec.Tolerations, a.Tolerations()...)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNodeArchitecturesApply(t *testing.T) {
+	testCases := map[string]struct {
+		archs           NodeArchitectures
+		wantAffinity    *corev1.Affinity
+		wantTolerations []corev1.Toleration
+	}{
+		"empty": {},
+		"allowed and preferred": {
+			archs: NodeArchitectures{Allowed: []string{"amd64", "arm64"}, Preferred: "arm64"},
+			wantAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "kubernetes.io/arch",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"amd64", "arm64"},
+						}},
+					}},
+				},
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+					Weight: 100,
+					Preference: corev1.NodeSelectorTerm{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "kubernetes.io/arch",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"arm64"},
+						}},
+					},
+				}},
+			}},
+			wantTolerations: []corev1.Toleration{{
+				Key:      "kubernetes.io/arch",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "amd64",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}, {
+				Key:      "kubernetes.io/arch",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "arm64",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		},
+		"preferred only": {
+			archs: NodeArchitectures{Preferred: "arm64"},
+			wantAffinity: &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{{
+					Weight: 100,
+					Preference: corev1.NodeSelectorTerm{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "kubernetes.io/arch",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"arm64"},
+						}},
+					},
+				}},
+			}},
+			wantTolerations: []corev1.Toleration{{
+				Key:      "kubernetes.io/arch",
+				Operator: corev1.TolerationOpEqual,
+				Value:    "arm64",
+				Effect:   corev1.TaintEffectNoSchedule,
+			}},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			var spec corev1.PodSpec
+			tc.archs.Apply(&spec)
+			if diff := cmp.Diff(tc.wantAffinity, spec.Affinity); diff != "" {
+				t.Errorf("Affinity (-want,+got): %s", diff)
+			}
+			if diff := cmp.Diff(tc.wantTolerations, spec.Tolerations); diff != "" {
+				t.Errorf("Tolerations (-want,+got): %s", diff)
+			}
+		})
+	}
+}
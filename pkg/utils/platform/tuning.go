@@ -0,0 +1,191 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package platform adapts the data plane to the CPU architecture of the nodes
+// it runs on and to the CPU and memory limits of its containers, so that mixed
+// amd64 and arm64 node pools run it efficiently and default settings don't
+// get it OOMKilled.
+package platform
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// cgroupRoot is where the cgroup filesystem is mounted in containers.
+	cgroupRoot = "/sys/fs/cgroup"
+
+	// unlimitedMemory is the smallest memory limit considered unlimited.
+	// cgroup v1 reports no limit as the largest page aligned int64.
+	unlimitedMemory = 1 << 62
+
+	// softMemoryRatio is the share of the memory limit the Go runtime
+	// collects garbage at, leaving room for memory it doesn't manage.
+	softMemoryRatio = 0.9
+	// outstandingMemoryRatio is the share of the memory limit taken by the
+	// Pub/Sub messages outstanding in all the subscriptions.
+	outstandingMemoryRatio = 0.25
+)
+
+// cpuWeights is the throughput of a CPU of an architecture relative to an
+// amd64 CPU. An arm64 vCPU is a physical core, while an amd64 vCPU is usually
+// one of the two hyperthreads of a core.
+var cpuWeights = map[string]float64{
+	"arm64": 1.5,
+}
+
+// Tuning holds the runtime settings derived from the architecture and the
+// CPU and memory limits of the container.
+type Tuning struct {
+	// Arch is the CPU architecture, as in runtime.GOARCH.
+	Arch string
+	// CPUs is the CPU quota of the container, or the number of CPUs of the
+	// node if the container has no quota.
+	CPUs float64
+	// MemoryLimit is the memory limit of the container in bytes, zero if it
+	// has none.
+	MemoryLimit int64
+}
+
+// Detect returns the tuning of the running container.
+func Detect() Tuning {
+	return detect(runtime.GOARCH, cgroupRoot, runtime.NumCPU())
+}
+
+func detect(arch, root string, numCPU int) Tuning {
+	cpus := float64(numCPU)
+	if quota, ok := cpuQuota(root); ok && quota < cpus {
+		cpus = quota
+	}
+	return Tuning{Arch: arch, CPUs: cpus, MemoryLimit: memoryLimit(root)}
+}
+
+// cpuQuota returns the CPU quota of the cgroup mounted at root, with cgroup
+// v2 or v1, and false if there is none.
+func cpuQuota(root string) (float64, bool) {
+	// cgroup v2: "<quota> <period>", with a "max" quota if unlimited.
+	if b, err := ioutil.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+		return ratio(fields[0], fields[1])
+	}
+	// cgroup v1: the quota is -1 if unlimited.
+	quota, err := ioutil.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := ioutil.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+	return ratio(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+// memoryLimit returns the memory limit of the cgroup mounted at root, with
+// cgroup v2 or v1, and zero if there is none.
+func memoryLimit(root string) int64 {
+	b, err := ioutil.ReadFile(filepath.Join(root, "memory.max"))
+	if err != nil {
+		if b, err = ioutil.ReadFile(filepath.Join(root, "memory", "memory.limit_in_bytes")); err != nil {
+			return 0
+		}
+	}
+	// cgroup v2 reports no limit as "max", which doesn't parse.
+	limit, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil || limit <= 0 || limit >= unlimitedMemory {
+		return 0
+	}
+	return limit
+}
+
+func ratio(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+	return q / p, true
+}
+
+// MaxProcs returns the GOMAXPROCS matching the CPU quota, rounded up so that
+// fractional quotas are used fully.
+func (t Tuning) MaxProcs() int {
+	if n := int(math.Ceil(t.CPUs)); n > 1 {
+		return n
+	}
+	return 1
+}
+
+// SetMaxProcs sets GOMAXPROCS to MaxProcs, unless it is set by the
+// GOMAXPROCS env var. Go only sizes it after the CPUs of the node, which
+// throttles containers with a lower CPU quota. It returns the GOMAXPROCS in
+// effect.
+func (t Tuning) SetMaxProcs() int {
+	if _, ok := os.LookupEnv("GOMAXPROCS"); !ok {
+		runtime.GOMAXPROCS(t.MaxProcs())
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// SetMemoryLimit sets the soft memory limit of the Go runtime below the
+// memory limit of the container, unless it is set by the GOMEMLIMIT env var,
+// so that the garbage collector runs harder instead of the container being
+// OOMKilled. It returns the soft limit in effect, math.MaxInt64 if none.
+// Binaries built with Go releases older than 1.19, which have no soft memory
+// limit, ignore it.
+func (t Tuning) SetMemoryLimit() int64 {
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); !ok && t.MemoryLimit > 0 {
+		setMemoryLimit(int64(float64(t.MemoryLimit) * softMemoryRatio))
+	}
+	return setMemoryLimit(-1)
+}
+
+// OutstandingBytesBudget returns the bytes of the Pub/Sub messages that may
+// be outstanding in all the subscriptions of the container together, a share
+// of its memory limit, or zero if it has none. Each subscription's flow
+// control limit is its part of the budget, so that the memory they take
+// doesn't grow with their number.
+func (t Tuning) OutstandingBytesBudget() int {
+	if t.MemoryLimit <= 0 {
+		return 0
+	}
+	return int(float64(t.MemoryLimit) * outstandingMemoryRatio)
+}
+
+// BatchSize returns the default number of events processed concurrently,
+// given the number per amd64 CPU. It scales with the CPU quota and the
+// throughput of the CPUs of the architecture, and is at least perCPU.
+func (t Tuning) BatchSize(perCPU int) int {
+	weight, ok := cpuWeights[t.Arch]
+	if !ok {
+		weight = 1
+	}
+	if n := int(float64(perCPU) * t.CPUs * weight); n > perCPU {
+		return n
+	}
+	return perCPU
+}
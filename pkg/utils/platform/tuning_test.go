@@ -0,0 +1,189 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package platform
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatalf("TempDir() = %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("MkdirAll() = %v", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("WriteFile() = %v", err)
+		}
+	}
+	return root
+}
+
+func TestDetect(t *testing.T) {
+	testCases := map[string]struct {
+		files    map[string]string
+		wantCPUs float64
+	}{
+		"no cgroup": {
+			wantCPUs: 8,
+		},
+		"cgroup v2": {
+			files:    map[string]string{"cpu.max": "150000 100000\n"},
+			wantCPUs: 1.5,
+		},
+		"cgroup v2 unlimited": {
+			files:    map[string]string{"cpu.max": "max 100000\n"},
+			wantCPUs: 8,
+		},
+		"cgroup v1": {
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "200000\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+			wantCPUs: 2,
+		},
+		"cgroup v1 unlimited": {
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":  "-1\n",
+				"cpu/cpu.cfs_period_us": "100000\n",
+			},
+			wantCPUs: 8,
+		},
+		"quota above the node CPUs": {
+			files:    map[string]string{"cpu.max": "1600000 100000\n"},
+			wantCPUs: 8,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := detect("amd64", writeFiles(t, tc.files), 8)
+			if got.CPUs != tc.wantCPUs {
+				t.Errorf("CPUs = %v, want %v", got.CPUs, tc.wantCPUs)
+			}
+		})
+	}
+}
+
+func TestDetectMemoryLimit(t *testing.T) {
+	testCases := map[string]struct {
+		files map[string]string
+		want  int64
+	}{
+		"no cgroup": {},
+		"cgroup v2": {
+			files: map[string]string{"memory.max": "536870912\n"},
+			want:  512 << 20,
+		},
+		"cgroup v2 unlimited": {
+			files: map[string]string{"memory.max": "max\n"},
+		},
+		"cgroup v1": {
+			files: map[string]string{"memory/memory.limit_in_bytes": "1073741824\n"},
+			want:  1 << 30,
+		},
+		"cgroup v1 unlimited": {
+			files: map[string]string{"memory/memory.limit_in_bytes": "9223372036854771712\n"},
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := detect("amd64", writeFiles(t, tc.files), 1).MemoryLimit; got != tc.want {
+				t.Errorf("MemoryLimit = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutstandingBytesBudget(t *testing.T) {
+	testCases := map[string]struct {
+		limit int64
+		want  int
+	}{
+		"no limit": {
+			want: 0,
+		},
+		"limit": {
+			limit: 2 << 30,
+			want:  512 << 20,
+		},
+		"large limit": {
+			limit: 64 << 30,
+			want:  16 << 30,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := (Tuning{MemoryLimit: tc.limit}).OutstandingBytesBudget(); got != tc.want {
+				t.Errorf("OutstandingBytesBudget() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMaxProcs(t *testing.T) {
+	testCases := map[float64]int{
+		0.25: 1,
+		1:    1,
+		1.5:  2,
+		4:    4,
+	}
+	for cpus, want := range testCases {
+		if got := (Tuning{CPUs: cpus}).MaxProcs(); got != want {
+			t.Errorf("MaxProcs() with %v CPUs = %d, want %d", cpus, got, want)
+		}
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	testCases := map[string]struct {
+		tuning Tuning
+		want   int
+	}{
+		"amd64": {
+			tuning: Tuning{Arch: "amd64", CPUs: 2},
+			want:   200,
+		},
+		"arm64": {
+			tuning: Tuning{Arch: "arm64", CPUs: 2},
+			want:   300,
+		},
+		"unknown arch": {
+			tuning: Tuning{Arch: "s390x", CPUs: 2},
+			want:   200,
+		},
+		"fractional quota": {
+			tuning: Tuning{Arch: "amd64", CPUs: 0.5},
+			want:   100,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			if got := tc.tuning.BatchSize(100); got != tc.want {
+				t.Errorf("BatchSize() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
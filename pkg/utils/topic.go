@@ -0,0 +1,50 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseTopic splits a Pub/Sub topic reference into the project and the ID of
+// the topic. The reference is either a topic ID, for a topic in the project of
+// the resource, or a fully qualified "projects/<project>/topics/<topic>" name,
+// for a topic in another project. The project is empty for a topic ID.
+func ParseTopic(topic string) (project, id string, err error) {
+	if !strings.Contains(topic, "/") {
+		return "", topic, nil
+	}
+	parts := strings.Split(topic, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[1] == "" || parts[2] != "topics" || parts[3] == "" {
+		return "", "", fmt.Errorf("topic %q is neither a topic ID nor of the form projects/<project>/topics/<topic>", topic)
+	}
+	return parts[1], parts[3], nil
+}
+
+// TopicName returns the fully qualified "projects/<project>/topics/<topic>"
+// name of a Pub/Sub topic.
+func TopicName(project, id string) string {
+	return fmt.Sprintf("projects/%s/topics/%s", project, id)
+}
+
+// SubscriptionName returns the fully qualified
+// "projects/<project>/subscriptions/<subscription>" name of a Pub/Sub
+// subscription.
+func SubscriptionName(project, id string) string {
+	return fmt.Sprintf("projects/%s/subscriptions/%s", project, id)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseTopic(t *testing.T) {
+	testCases := []struct {
+		topic       string
+		wantProject string
+		wantID      string
+		wantErr     bool
+	}{{
+		topic:  "my-topic",
+		wantID: "my-topic",
+	}, {
+		topic:       "projects/other-project/topics/my-topic",
+		wantProject: "other-project",
+		wantID:      "my-topic",
+	}, {
+		topic:   "projects/other-project/subscriptions/my-topic",
+		wantErr: true,
+	}, {
+		topic:   "projects//topics/my-topic",
+		wantErr: true,
+	}, {
+		topic:   "projects/other-project/topics/",
+		wantErr: true,
+	}, {
+		topic:   "other-project/my-topic",
+		wantErr: true,
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.topic, func(t *testing.T) {
+			project, id, err := ParseTopic(tc.topic)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("ParseTopic() got error %v, want error %v", err, tc.wantErr)
+			}
+			if project != tc.wantProject || id != tc.wantID {
+				t.Errorf("ParseTopic() got=(%q, %q), want=(%q, %q)", project, id, tc.wantProject, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestTopicName(t *testing.T) {
+	want := "projects/my-project/topics/my-topic"
+	got := TopicName("my-project", "my-topic")
+	if got != want {
+		t.Errorf("TopicName() got=%q, want=%q", got, want)
+	}
+	if project, id, err := ParseTopic(got); err != nil || project != "my-project" || id != "my-topic" {
+		t.Errorf("ParseTopic(%q) got=(%q, %q, %v), want=(%q, %q, nil)", got, project, id, err, "my-project", "my-topic")
+	}
+}
+
+func TestSubscriptionName(t *testing.T) {
+	want := "projects/my-project/subscriptions/my-sub"
+	if got := SubscriptionName("my-project", "my-sub"); got != want {
+		t.Errorf("SubscriptionName() got=%q, want=%q", got, want)
+	}
+}
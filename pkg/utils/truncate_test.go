@@ -0,0 +1,63 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncate(t *testing.T) {
+	testCases := map[string]struct {
+		s    string
+		max  int
+		want string
+	}{
+		"short": {
+			s:    "abc",
+			max:  3,
+			want: "abc",
+		},
+		"long": {
+			s:    "abcdef",
+			max:  3,
+			want: "abc...",
+		},
+		"cut inside a rune": {
+			// "é" is 2 bytes long, so the 4th byte is in the middle of it.
+			s:    "abcé",
+			max:  4,
+			want: "abc...",
+		},
+		"cut after a rune": {
+			s:    "abéd",
+			max:  4,
+			want: "abé...",
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got := Truncate(tc.s, tc.max)
+			if got != tc.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tc.s, tc.max, got, tc.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("Truncate(%q, %d) = %q is not valid UTF-8", tc.s, tc.max, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certificates reconciles the serving certificate of the webhook. It
+// replaces the knative.dev/pkg/webhook/certificates controller with one that
+// rotates certificates without failing admission requests, and that can run
+// on every webhook replica.
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+const (
+	// NextServerKey is the key of the secret holding the private key of the
+	// certificate the webhook switches to once its CA is trusted.
+	NextServerKey = "next-server-key.pem"
+	// NextServerCert is the key of the secret holding the certificate the
+	// webhook switches to once its CA is trusted.
+	NextServerCert = "next-server-cert.pem"
+
+	// rotateBefore is how long before the serving certificate expires it is
+	// rotated.
+	rotateBefore = 7 * 24 * time.Hour
+	// validity is how long new certificates are valid.
+	validity = 365 * 24 * time.Hour
+)
+
+var (
+	// propagationDelay is how long the CA of the next certificate is trusted
+	// before the webhook serves it, so that the CA bundles of the webhook
+	// configurations and of the API servers catch up.
+	propagationDelay = 10 * time.Minute
+
+	// createCerts creates a private key, a certificate and the certificate
+	// of its CA. It is a variable for testing.
+	createCerts = certresources.CreateCerts
+)
+
+type reconciler struct {
+	client       kubernetes.Interface
+	secretLister corelisters.SecretLister
+	secret       types.NamespacedName
+	serviceName  string
+
+	// leader is true if the replica may write the secret. Replicas that
+	// don't run in leader-elected mode are always leaders, and rely on
+	// optimistic concurrency to not overwrite each other.
+	leader atomic.Value
+	// enqueueAfter reconciles the secret again after the delay.
+	enqueueAfter func(key types.NamespacedName, delay time.Duration)
+}
+
+var _ controller.Reconciler = (*reconciler)(nil)
+
+// Reconcile implements controller.Reconciler. Certificates are rotated in two
+// steps: the CA of the next certificate is added to the CA bundle first, and
+// the webhook only serves the next certificate once the CA has propagated.
+// The CAs of previous certificates stay in the bundle until they expire.
+func (r *reconciler) Reconcile(ctx context.Context, _ string) error {
+	if leader, _ := r.leader.Load().(bool); !leader {
+		return nil
+	}
+	logger := logging.FromContext(ctx)
+
+	existing, err := r.secretLister.Secrets(r.secret.Namespace).Get(r.secret.Name)
+	if apierrors.IsNotFound(err) {
+		// The secret should be created explicitly by a higher-level system
+		// that's responsible for install/updates. We simply populate the
+		// secret information.
+		return nil
+	} else if err != nil {
+		logger.Errorf("Error accessing certificate secret %q: %v", r.secret.Name, err)
+		return err
+	}
+	// Updates carry the resource version of the secret, so the replicas
+	// racing to write it fail with a conflict and see the winner's secret
+	// when retrying.
+	secret := existing.DeepCopy()
+	now := time.Now()
+
+	current, err := parseCert(secret.Data[certresources.ServerCert], secret.Data[certresources.ServerKey])
+	if err != nil || len(secret.Data[certresources.CACert]) == 0 {
+		// Nothing is served yet, so there is no need to rotate gracefully.
+		logger.Infof("Certificate secret %q has no valid certificate, creating one: %v", r.secret.Name, err)
+		key, cert, ca, err := createCerts(ctx, r.serviceName, r.secret.Namespace, now.Add(validity))
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			certresources.ServerKey:  key,
+			certresources.ServerCert: cert,
+			certresources.CACert:     ca,
+		}
+		_, err = r.client.CoreV1().Secrets(secret.Namespace).Update(secret)
+		return err
+	}
+
+	bundle := pruneBundle(secret.Data[certresources.CACert], now)
+	next, err := parseCert(secret.Data[NextServerCert], secret.Data[NextServerKey])
+	switch {
+	case err == nil && now.Before(next.NotBefore.Add(propagationDelay)):
+		// Wait for the CA of the next certificate to propagate.
+		r.enqueueAfter(r.secret, next.NotBefore.Add(propagationDelay).Sub(now))
+		return nil
+	case err == nil:
+		logger.Infof("Serving the next certificate of secret %q", r.secret.Name)
+		secret.Data[certresources.ServerKey] = secret.Data[NextServerKey]
+		secret.Data[certresources.ServerCert] = secret.Data[NextServerCert]
+		delete(secret.Data, NextServerKey)
+		delete(secret.Data, NextServerCert)
+	case now.Add(rotateBefore).After(current.NotAfter):
+		logger.Infof("Rotating the certificate of secret %q, which expires at %v", r.secret.Name, current.NotAfter)
+		key, cert, ca, err := createCerts(ctx, r.serviceName, r.secret.Namespace, now.Add(validity))
+		if err != nil {
+			return err
+		}
+		secret.Data[NextServerKey] = key
+		secret.Data[NextServerCert] = cert
+		bundle = append(bundle, ca...)
+		r.enqueueAfter(r.secret, propagationDelay)
+	default:
+		// Drop a broken next certificate, and the expired CAs.
+		delete(secret.Data, NextServerKey)
+		delete(secret.Data, NextServerCert)
+	}
+	secret.Data[certresources.CACert] = bundle
+
+	if equalData(secret.Data, existing.Data) {
+		return nil
+	}
+	_, err = r.client.CoreV1().Secrets(secret.Namespace).Update(secret)
+	return err
+}
+
+// parseCert returns the certificate of a key pair, or an error if the pair is
+// missing or invalid.
+func parseCert(certPEM, keyPEM []byte) (*x509.Certificate, error) {
+	if len(certPEM) == 0 || len(keyPEM) == 0 {
+		return nil, fmt.Errorf("missing certificate or key")
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
+// pruneBundle returns the certificates of the PEM bundle that haven't expired.
+func pruneBundle(bundle []byte, now time.Time) []byte {
+	var pruned []byte
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return pruned
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil || now.After(cert.NotAfter) {
+			continue
+		}
+		pruned = append(pruned, pem.EncodeToMemory(block)...)
+	}
+}
+
+// equalData returns true if the secrets hold the same data.
+func equalData(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !bytes.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
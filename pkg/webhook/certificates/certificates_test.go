@@ -0,0 +1,225 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	logtesting "knative.dev/pkg/logging/testing"
+	certresources "knative.dev/pkg/webhook/certificates/resources"
+)
+
+const (
+	testNS      = "events-system"
+	secretName  = "webhook-certs"
+	serviceName = "webhook"
+)
+
+type keyPair struct {
+	key, cert, ca []byte
+}
+
+func newKeyPair(t *testing.T, notAfter time.Time) keyPair {
+	t.Helper()
+	key, cert, ca, err := certresources.CreateCerts(context.Background(), serviceName, testNS, notAfter)
+	if err != nil {
+		t.Fatalf("CreateCerts() = %v", err)
+	}
+	return keyPair{key: key, cert: cert, ca: ca}
+}
+
+func countCerts(bundle []byte) int {
+	n := 0
+	for rest := bundle; ; n++ {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			return n
+		}
+	}
+}
+
+type testReconciler struct {
+	*reconciler
+	client   *fake.Clientset
+	requeued time.Duration
+}
+
+func newReconciler(t *testing.T, leader bool, data map[string][]byte) *testReconciler {
+	t.Helper()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNS, Name: secretName},
+		Data:       data,
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(secret); err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+	client := fake.NewSimpleClientset(secret)
+	tr := &testReconciler{client: client}
+	tr.reconciler = &reconciler{
+		client:       client,
+		secretLister: corelisters.NewSecretLister(indexer),
+		secret:       types.NamespacedName{Namespace: testNS, Name: secretName},
+		serviceName:  serviceName,
+		enqueueAfter: func(_ types.NamespacedName, delay time.Duration) {
+			tr.requeued = delay
+		},
+	}
+	tr.leader.Store(leader)
+	return tr
+}
+
+// updated returns the data of the updated secret, nil if it wasn't updated.
+func (tr *testReconciler) updated(t *testing.T) map[string][]byte {
+	t.Helper()
+	actions := tr.client.Actions()
+	if len(actions) == 0 {
+		return nil
+	}
+	if len(actions) != 1 || actions[0].GetVerb() != "update" {
+		t.Fatalf("Actions() = %v, want a single update", actions)
+	}
+	s, err := tr.client.CoreV1().Secrets(testNS).Get(secretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	return s.Data
+}
+
+func TestReconcileCreatesCertificates(t *testing.T) {
+	tr := newReconciler(t, true, nil)
+	if err := tr.Reconcile(logtesting.TestContextWithLogger(t), ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	data := tr.updated(t)
+	if _, err := parseCert(data[certresources.ServerCert], data[certresources.ServerKey]); err != nil {
+		t.Errorf("Server certificate is invalid: %v", err)
+	}
+	if got := countCerts(data[certresources.CACert]); got != 1 {
+		t.Errorf("CA bundle has %d certificates, want 1", got)
+	}
+}
+
+func TestReconcileValidCertificate(t *testing.T) {
+	current := newKeyPair(t, time.Now().Add(30*24*time.Hour))
+	tr := newReconciler(t, true, map[string][]byte{
+		certresources.ServerKey:  current.key,
+		certresources.ServerCert: current.cert,
+		certresources.CACert:     current.ca,
+	})
+	if err := tr.Reconcile(logtesting.TestContextWithLogger(t), ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	if data := tr.updated(t); data != nil {
+		t.Errorf("Secret updated to %v, want no update", data)
+	}
+}
+
+func TestReconcileNotLeader(t *testing.T) {
+	tr := newReconciler(t, false, nil)
+	if err := tr.Reconcile(logtesting.TestContextWithLogger(t), ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	if data := tr.updated(t); data != nil {
+		t.Errorf("Secret updated to %v, want no update by a replica that isn't the leader", data)
+	}
+}
+
+func TestReconcileRotatesGracefully(t *testing.T) {
+	ctx := logtesting.TestContextWithLogger(t)
+	current := newKeyPair(t, time.Now().Add(24*time.Hour))
+	tr := newReconciler(t, true, map[string][]byte{
+		certresources.ServerKey:  current.key,
+		certresources.ServerCert: current.cert,
+		certresources.CACert:     current.ca,
+	})
+
+	// The next certificate is created and its CA trusted, but not served yet.
+	if err := tr.Reconcile(ctx, ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	data := tr.updated(t)
+	if !bytes.Equal(data[certresources.ServerCert], current.cert) {
+		t.Error("Server certificate changed before the next CA propagated")
+	}
+	if _, err := parseCert(data[NextServerCert], data[NextServerKey]); err != nil {
+		t.Errorf("Next certificate is invalid: %v", err)
+	}
+	if got := countCerts(data[certresources.CACert]); got != 2 {
+		t.Errorf("CA bundle has %d certificates, want 2", got)
+	}
+	if tr.requeued != propagationDelay {
+		t.Errorf("Requeued after %v, want %v", tr.requeued, propagationDelay)
+	}
+
+	// The next certificate isn't served until its CA propagated.
+	tr = newReconciler(t, true, data)
+	if err := tr.Reconcile(ctx, ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	if got := tr.updated(t); got != nil {
+		t.Errorf("Secret updated to %v, want no update before the next CA propagated", got)
+	}
+	if tr.requeued <= 0 || tr.requeued > propagationDelay {
+		t.Errorf("Requeued after %v, want at most %v", tr.requeued, propagationDelay)
+	}
+
+	// Once it did, the next certificate is served.
+	defer func(d time.Duration) { propagationDelay = d }(propagationDelay)
+	propagationDelay = 0
+	tr = newReconciler(t, true, data)
+	if err := tr.Reconcile(ctx, ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	promoted := tr.updated(t)
+	if !bytes.Equal(promoted[certresources.ServerCert], data[NextServerCert]) ||
+		!bytes.Equal(promoted[certresources.ServerKey], data[NextServerKey]) {
+		t.Error("Next certificate not served")
+	}
+	if _, ok := promoted[NextServerCert]; ok {
+		t.Error("Next certificate kept after being served")
+	}
+	if got := countCerts(promoted[certresources.CACert]); got != 2 {
+		t.Errorf("CA bundle has %d certificates, want the previous CA kept until it expires", got)
+	}
+}
+
+func TestReconcilePrunesExpiredCAs(t *testing.T) {
+	current := newKeyPair(t, time.Now().Add(30*24*time.Hour))
+	expired := newKeyPair(t, time.Now().Add(-time.Hour))
+	tr := newReconciler(t, true, map[string][]byte{
+		certresources.ServerKey:  current.key,
+		certresources.ServerCert: current.cert,
+		certresources.CACert:     append(append([]byte{}, expired.ca...), current.ca...),
+	})
+	if err := tr.Reconcile(logtesting.TestContextWithLogger(t), ""); err != nil {
+		t.Fatalf("Reconcile() = %v", err)
+	}
+	if got := tr.updated(t)[certresources.CACert]; !bytes.Equal(got, current.ca) {
+		t.Errorf("CA bundle = %s, want only the current CA", got)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certificates
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+	secretinformer "knative.dev/pkg/injection/clients/namespacedkube/informers/core/v1/secret"
+	"knative.dev/pkg/injection/sharedmain"
+	kle "knative.dev/pkg/leaderelection"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+	"knative.dev/pkg/webhook"
+)
+
+// NewController constructs a controller for materializing webhook certificates.
+// In order for it to bootstrap, an empty secret should be created with the
+// expected name (and lifecycle managed accordingly), and thereafter this
+// controller will ensure it has the appropriate shape for the webhook.
+//
+// Every webhook replica serves admission and conversion requests. If the
+// webhook component is enabled in the leader election config, only the leader
+// writes the certificates; otherwise all replicas do, and conflicting updates
+// are retried.
+func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
+	logger := logging.FromContext(ctx)
+	secretInformer := secretinformer.Get(ctx)
+	options := webhook.GetOptions(ctx)
+
+	r := &reconciler{
+		client:       kubeclient.Get(ctx),
+		secretLister: secretInformer.Lister(),
+		secret:       types.NamespacedName{Namespace: system.Namespace(), Name: options.SecretName},
+		serviceName:  options.ServiceName,
+	}
+	impl := controller.NewImpl(r, logger, "WebhookCertificates")
+	r.enqueueAfter = impl.EnqueueKeyAfter
+
+	// Reconcile when the cert bundle changes.
+	secretInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterWithNameAndNamespace(r.secret.Namespace, r.secret.Name),
+		// It doesn't matter what we enqueue because we will always Reconcile
+		// the named secret.
+		Handler: controller.HandleAll(impl.Enqueue),
+	})
+
+	leConfig, err := sharedmain.GetLeaderElectionConfig(ctx)
+	if err != nil {
+		logger.Fatalw("Error loading leader election configuration", zap.Error(err))
+	}
+	componentConfig := leConfig.GetComponentConfig(options.ServiceName)
+	if !componentConfig.LeaderElect {
+		r.leader.Store(true)
+		return impl
+	}
+	r.leader.Store(false)
+	go runLeaderElected(ctx, componentConfig, func(leader bool) {
+		r.leader.Store(leader)
+		if leader {
+			impl.EnqueueKey(r.secret)
+		}
+	})
+	return impl
+}
+
+// runLeaderElected campaigns for the certificates lock until the context is
+// done, calling setLeader when the replica gains or loses it. Unlike the
+// controllers of the control plane, losing the lock doesn't stop the replica,
+// as it keeps serving requests.
+func runLeaderElected(ctx context.Context, config kle.ComponentConfig, setLeader func(bool)) {
+	logger := logging.FromContext(ctx)
+	id, err := kle.UniqueID()
+	if err != nil {
+		logger.Fatalw("Failed to get unique ID for leader election", zap.Error(err))
+	}
+	name := config.Component + "-certificates"
+	logger.Infof("%v will run in leader-elected mode with id %v", name, id)
+
+	client := kubeclient.Get(ctx)
+	rl, err := resourcelock.New(config.ResourceLock,
+		system.Namespace(),
+		name,
+		client.CoreV1(),
+		client.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      id,
+			EventRecorder: controller.GetEventRecorder(ctx),
+		})
+	if err != nil {
+		logger.Fatalw("Error creating lock", zap.Error(err))
+	}
+
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:          rl,
+			LeaseDuration: config.LeaseDuration,
+			RenewDeadline: config.RenewDeadline,
+			RetryPeriod:   config.RetryPeriod,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(context.Context) {
+					setLeader(true)
+				},
+				OnStoppedLeading: func() {
+					logger.Infof("%v lost the leader election", name)
+					setLeader(false)
+				},
+			},
+			ReleaseOnCancel: true,
+			Name:            name,
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// +build e2e
+
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	"knative.dev/pkg/test/logstream"
+)
+
+// All conformance tests go below:
+
+// TestBrokerIngress tests the broker ingress responds with the status codes of the data plane contract.
+func TestBrokerIngress(t *testing.T) {
+	cancel := logstream.Start(t)
+	defer cancel()
+	BrokerIngressTestImpl(t, authConfig)
+}
+
+// TestBrokerDelivery tests the subscriber of a trigger receives events as they were sent to the broker.
+func TestBrokerDelivery(t *testing.T) {
+	cancel := logstream.Start(t)
+	defer cancel()
+	BrokerDeliveryTestImpl(t, authConfig)
+}
+
+// TestBrokerReply tests the reply of a subscriber is delivered to the triggers matching it.
+func TestBrokerReply(t *testing.T) {
+	cancel := logstream.Start(t)
+	defer cancel()
+	BrokerReplyTestImpl(t, authConfig)
+}
+
+// TestBrokerFiltering tests a trigger only delivers the events matching its filter.
+func TestBrokerFiltering(t *testing.T) {
+	cancel := logstream.Start(t)
+	defer cancel()
+	BrokerFilteringTestImpl(t, authConfig)
+}
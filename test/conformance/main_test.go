@@ -0,0 +1,41 @@
+// +build e2e
+
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/knative-gcp/test"
+	"github.com/google/knative-gcp/test/e2e/lib"
+)
+
+var authConfig lib.AuthConfig
+
+func TestMain(m *testing.M) {
+	os.Exit(func() int {
+		test.InitializeFlags()
+		authConfig.WorkloadIdentity = test.Flags.WorkloadIdentity
+		// The format of a Google Cloud Service Account is: service-account-name@project-id.iam.gserviceaccount.com.
+		if authConfig.WorkloadIdentity {
+			authConfig.ServiceAccountName = test.Flags.ServiceAccountName
+		}
+		return m.Run()
+	}())
+}
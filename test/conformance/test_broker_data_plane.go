@@ -0,0 +1,285 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cetest "github.com/cloudevents/sdk-go/v2/test"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	eventingv1beta1 "knative.dev/eventing/pkg/apis/eventing/v1beta1"
+	eventingtestlib "knative.dev/eventing/test/lib"
+	"knative.dev/eventing/test/lib/duck"
+	"knative.dev/eventing/test/lib/recordevents"
+	eventingtestresources "knative.dev/eventing/test/lib/resources"
+	"knative.dev/pkg/test/helpers"
+
+	// The following line to load the gcp plugin (only required to authenticate against GKE clusters).
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+
+	"github.com/google/knative-gcp/pkg/apis/broker/v1beta1"
+	"github.com/google/knative-gcp/test/e2e/lib"
+	"github.com/google/knative-gcp/test/e2e/lib/resources"
+)
+
+const (
+	conformanceEventType      = "e2e-conformance-event-type"
+	conformanceEventSource    = "e2e-conformance-event-source"
+	conformanceReplyEventType = "e2e-conformance-reply-event-type"
+	conformanceReplySource    = "e2e-conformance-reply-event-source"
+	conformanceExtension      = "conformanceext"
+
+	// hopsExtension is the extension the broker counts the remaining hops of
+	// an event with. It is local to the broker and must not reach subscribers.
+	hopsExtension = "kgcphops"
+)
+
+/*
+BrokerIngressTestImpl tests that the broker ingress responds to requests with
+the status codes required by the data plane contract:
+
+(Ingress prober) ---> GCP Broker
+
+The prober sends valid events in both content modes, invalid events and
+requests to unknown brokers, and reports the status code of each response.
+*/
+
+func BrokerIngressTestImpl(t *testing.T, authConfig lib.AuthConfig) {
+	client := lib.Setup(t, true, authConfig.WorkloadIdentity)
+	defer lib.TearDown(client)
+	brokerURL, _ := createGCPBroker(client)
+
+	proberName := helpers.AppendRandomString("ingress-prober")
+	client.CreateJobOrFail(resources.IngressProberJob(proberName, []corev1.EnvVar{{
+		Name:  "BROKER_URL",
+		Value: brokerURL.String(),
+	}}))
+
+	out := new(lib.IngressProberOutput)
+	if err := jobOutput(client, proberName, out); err != nil {
+		t.Fatalf("Failed to get the output of the ingress prober: %v", err)
+	}
+	if len(out.Checks) == 0 {
+		t.Fatal("The ingress prober didn't report any check")
+	}
+	for _, check := range out.Checks {
+		if check.GotStatus != check.WantStatus {
+			t.Errorf("%s: got status code %d, want %d. %s", check.Name, check.GotStatus, check.WantStatus, check.Error)
+		}
+	}
+}
+
+/*
+BrokerDeliveryTestImpl tests that an event is delivered to the subscriber of a
+trigger as it was sent to the broker:
+
+          1                  2                  3
+(Sender) ---> GCP Broker ---> trigger ---> Service(Recorder)
+
+The subscriber must receive the event in binary content mode, with its
+attributes, extensions and data intact, and without the broker local hops
+extension.
+*/
+
+func BrokerDeliveryTestImpl(t *testing.T, authConfig lib.AuthConfig) {
+	client := lib.Setup(t, true, authConfig.WorkloadIdentity)
+	defer lib.TearDown(client)
+	brokerURL, brokerName := createGCPBroker(client)
+
+	recorderName := helpers.AppendRandomString("recorder")
+	eventTracker, _ := recordevents.StartEventRecordOrFail(client.Core, recorderName)
+	defer eventTracker.Cleanup()
+	createTrigger(client, brokerName, recorderName, eventingv1beta1.TriggerAnyFilter, nil)
+	waitForTriggersReady(client)
+
+	event := conformanceEvent(conformanceEventType, "delivery")
+	sendEvent(client, brokerURL, event)
+
+	eventTracker.AssertExact(1, recordevents.AllOf(
+		recordevents.MatchEvent(
+			cetest.HasId(event.ID()),
+			cetest.HasSpecVersion(event.SpecVersion()),
+			cetest.HasType(event.Type()),
+			cetest.HasSource(event.Source()),
+			cetest.HasSubject(event.Subject()),
+			cetest.HasDataContentType(event.DataContentType()),
+			cetest.HasData(event.Data()),
+			cetest.HasExtension(conformanceExtension, "delivery"),
+			hasNoExtension(hopsExtension),
+		),
+		// Events in binary content mode carry their attributes in ce- headers.
+		recordevents.HasAdditionalHeader("Ce-Id", event.ID()),
+		recordevents.HasAdditionalHeader("Content-Type", event.DataContentType()),
+	))
+}
+
+/*
+BrokerReplyTestImpl tests that the reply of a subscriber is sent back to the
+broker and delivered to the triggers matching it:
+
+          1                  2                  3
+(Sender) ---> GCP Broker ---> trigger ---> Service(Transformer)
+                  |                               |
+                  |<------------------------------|
+                  |               4 (reply)
+                  |      5                  6
+                  |---> replyTrigger ---> Service(Recorder)
+*/
+
+func BrokerReplyTestImpl(t *testing.T, authConfig lib.AuthConfig) {
+	client := lib.Setup(t, true, authConfig.WorkloadIdentity)
+	defer lib.TearDown(client)
+	brokerURL, brokerName := createGCPBroker(client)
+
+	transformerName := helpers.AppendRandomString("transformer")
+	replyData := []byte(`{"msg":"reply"}`)
+	transformerPod := eventingtestresources.EventTransformationPod(transformerName, conformanceReplyEventType, conformanceReplySource, replyData)
+	client.Core.CreatePodOrFail(transformerPod, eventingtestlib.WithService(transformerName))
+	createTrigger(client, brokerName, transformerName, conformanceEventType, nil)
+
+	recorderName := helpers.AppendRandomString("recorder")
+	eventTracker, _ := recordevents.StartEventRecordOrFail(client.Core, recorderName)
+	defer eventTracker.Cleanup()
+	createTrigger(client, brokerName, recorderName, conformanceReplyEventType, nil)
+	waitForTriggersReady(client)
+
+	event := conformanceEvent(conformanceEventType, "reply")
+	sendEvent(client, brokerURL, event)
+
+	eventTracker.AssertExact(1, recordevents.MatchEvent(
+		cetest.HasId(event.ID()),
+		cetest.HasType(conformanceReplyEventType),
+		cetest.HasSource(conformanceReplySource),
+		cetest.HasData(replyData),
+		hasNoExtension(hopsExtension),
+	))
+	eventTracker.AssertNot(recordevents.MatchEvent(cetest.HasType(conformanceEventType)))
+}
+
+/*
+BrokerFilteringTestImpl tests that a trigger only delivers the events matching
+all the attributes of its filter:
+
+          1                  2                  3
+(Sender) ---> GCP Broker ---> trigger ---> Service(Recorder)
+
+Three events are sent: one matching the filter, one with another type, and one
+with another value of the filtered extension. Only the first one must be
+delivered.
+*/
+
+func BrokerFilteringTestImpl(t *testing.T, authConfig lib.AuthConfig) {
+	client := lib.Setup(t, true, authConfig.WorkloadIdentity)
+	defer lib.TearDown(client)
+	brokerURL, brokerName := createGCPBroker(client)
+
+	recorderName := helpers.AppendRandomString("recorder")
+	eventTracker, _ := recordevents.StartEventRecordOrFail(client.Core, recorderName)
+	defer eventTracker.Cleanup()
+	createTrigger(client, brokerName, recorderName, conformanceEventType, map[string]interface{}{
+		conformanceExtension: "match",
+	})
+	waitForTriggersReady(client)
+
+	matching := conformanceEvent(conformanceEventType, "match")
+	otherType := conformanceEvent(conformanceReplyEventType, "match")
+	otherExtension := conformanceEvent(conformanceEventType, "mismatch")
+	for _, event := range []cloudevents.Event{matching, otherType, otherExtension} {
+		sendEvent(client, brokerURL, event)
+	}
+
+	eventTracker.AssertExact(1, recordevents.MatchEvent(cetest.HasId(matching.ID())))
+	eventTracker.AssertNot(recordevents.MatchEvent(cetest.HasId(otherType.ID())))
+	eventTracker.AssertNot(recordevents.MatchEvent(cetest.HasId(otherExtension.ID())))
+}
+
+func createGCPBroker(client *lib.Client) (url.URL, string) {
+	brokerName := helpers.AppendRandomString("gcp")
+	// Create a new GCP Broker.
+	client.CreateGCPBrokerV1Beta1OrFail(brokerName, resources.WithBrokerClassForBrokerV1Beta1(v1beta1.BrokerClass))
+
+	// Wait for broker ready.
+	client.Core.WaitForResourceReadyOrFail(brokerName, eventingtestlib.BrokerTypeMeta)
+
+	// Get broker URL.
+	metaAddressable := eventingtestresources.NewMetaResource(brokerName, client.Namespace, eventingtestlib.BrokerTypeMeta)
+	u, err := duck.GetAddressableURI(client.Core.Dynamic, metaAddressable)
+	if err != nil {
+		client.T.Fatal(err.Error())
+	}
+	return u, brokerName
+}
+
+func createTrigger(client *lib.Client, brokerName, subscriberName, eventType string, extensions map[string]interface{}) {
+	client.Core.CreateTriggerOrFailV1Beta1(
+		helpers.AppendRandomString("trigger"),
+		eventingtestresources.WithBrokerV1Beta1(brokerName),
+		eventingtestresources.WithAttributesTriggerFilterV1Beta1(eventingv1beta1.TriggerAnyFilter, eventType, extensions),
+		eventingtestresources.WithSubscriberServiceRefForTriggerV1Beta1(subscriberName),
+	)
+}
+
+func waitForTriggersReady(client *lib.Client) {
+	client.Core.WaitForResourcesReadyOrFail(eventingtestlib.TriggerTypeMeta)
+	// Just to make sure all resources are ready.
+	time.Sleep(5 * time.Second)
+}
+
+func conformanceEvent(eventType, extension string) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(string(uuid.NewUUID()))
+	event.SetType(eventType)
+	event.SetSource(conformanceEventSource)
+	event.SetSubject("conformance")
+	event.SetExtension(conformanceExtension, extension)
+	_ = event.SetData(cloudevents.ApplicationJSON, map[string]string{"msg": "conformance"})
+	return event
+}
+
+func sendEvent(client *lib.Client, brokerURL url.URL, event cloudevents.Event) {
+	senderName := helpers.AppendRandomString("sender")
+	if err := client.Core.SendEvent(senderName, brokerURL.String(), event); err != nil {
+		client.T.Fatalf("Failed to send event %s to the broker: %v", event.ID(), err)
+	}
+}
+
+func jobOutput(client *lib.Client, jobName string, out lib.Output) error {
+	msg, err := client.WaitUntilJobDone(client.Namespace, jobName)
+	if err != nil {
+		return err
+	}
+	if msg == "" {
+		return errors.New("no terminating message from the pod")
+	}
+	return json.Unmarshal([]byte(msg), out)
+}
+
+func hasNoExtension(name string) cetest.EventMatcher {
+	return func(have cloudevents.Event) error {
+		if _, ok := have.Extensions()[name]; ok {
+			return fmt.Errorf("unexpected extension %q", name)
+		}
+		return nil
+	}
+}
@@ -22,4 +22,6 @@ go_test_e2e -timeout=30m -parallel=12 ./test/e2e \
   -channels='messaging.cloud.google.com/v1alpha1:Channel,messaging.cloud.google.com/v1beta1:Channel' \
   || fail_test
 
+go_test_e2e -timeout=30m -parallel=12 ./test/conformance || fail_test
+
 success
\ No newline at end of file
@@ -62,4 +62,9 @@ const (
 	E2EDummyEventSource = "e2e-dummy-event-source"
 	// E2EDummyRespEventSource is the source of the resp event sent by image `receiver`
 	E2EDummyRespEventSource = "e2e-dummy-resp-event-source"
+
+	// E2EIngressProbeEventType is the type of the events sent by image `ingress_prober`
+	E2EIngressProbeEventType = "e2e-ingress-probe-event-type"
+	// E2EIngressProbeEventSource is the source of the events sent by image `ingress_prober`
+	E2EIngressProbeEventSource = "e2e-ingress-probe-event-source"
 )
@@ -45,6 +45,10 @@ func TargetJob(name string, envVars []corev1.EnvVar) *batchv1.Job {
 	return baseJob(name, "target", envVars)
 }
 
+func IngressProberJob(name string, envVars []corev1.EnvVar) *batchv1.Job {
+	return baseJob(name, "ingress_prober", envVars)
+}
+
 func SenderJob(name string, envVars []corev1.EnvVar) *batchv1.Job {
 	return baseJob(name, "sender", envVars)
 }
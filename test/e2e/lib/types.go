@@ -33,6 +33,20 @@ type SenderOutput struct {
 	TraceID string `json:"traceid"`
 }
 
+// IngressProberOutput is the output of image `ingress_prober`.
+type IngressProberOutput struct {
+	outputSuccess
+	Checks []IngressCheck `json:"checks"`
+}
+
+// IngressCheck is the result of a request sent to a broker ingress.
+type IngressCheck struct {
+	Name       string `json:"name"`
+	WantStatus int    `json:"wantStatus"`
+	GotStatus  int    `json:"gotStatus"`
+	Error      string `json:"error,omitempty"`
+}
+
 type Output interface {
 	Successful() bool
 }
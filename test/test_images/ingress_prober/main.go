@@ -0,0 +1,185 @@
+/*
+Copyright 2020 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+
+	"github.com/google/knative-gcp/test/e2e/lib"
+)
+
+const (
+	brokerURLEnvVar = "BROKER_URL"
+)
+
+// probe is a request sent to the broker ingress, along with the status code
+// the data plane contract requires in response.
+type probe struct {
+	name       string
+	wantStatus int
+	request    func(brokerURL *url.URL) (*http.Request, error)
+}
+
+var probes = []probe{{
+	name:       "binary content mode event is accepted",
+	wantStatus: http.StatusAccepted,
+	request: func(u *url.URL) (*http.Request, error) {
+		return binaryRequest(u.String(), string(uuid.NewUUID()))
+	},
+}, {
+	name:       "structured content mode event is accepted",
+	wantStatus: http.StatusAccepted,
+	request: func(u *url.URL) (*http.Request, error) {
+		body, err := json.Marshal(map[string]interface{}{
+			"specversion":     "1.0",
+			"id":              string(uuid.NewUUID()),
+			"type":            lib.E2EIngressProbeEventType,
+			"source":          lib.E2EIngressProbeEventSource,
+			"datacontenttype": "application/json",
+			"data":            map[string]string{"probe": "structured"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+		return req, nil
+	},
+}, {
+	name:       "event without id is rejected",
+	wantStatus: http.StatusBadRequest,
+	request: func(u *url.URL) (*http.Request, error) {
+		req, err := binaryRequest(u.String(), "")
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Del("Ce-Id")
+		return req, nil
+	},
+}, {
+	name:       "request that is not an event is rejected",
+	wantStatus: http.StatusBadRequest,
+	request: func(u *url.URL) (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewBufferString(`{"probe": "not an event"}`))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	},
+}, {
+	name:       "GET request is rejected",
+	wantStatus: http.StatusMethodNotAllowed,
+	request: func(u *url.URL) (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, u.String(), nil)
+	},
+}, {
+	name:       "event sent to a malformed path is rejected",
+	wantStatus: http.StatusNotFound,
+	request: func(u *url.URL) (*http.Request, error) {
+		malformed := *u
+		malformed.Path = path.Join(u.Path, "malformed")
+		return binaryRequest(malformed.String(), string(uuid.NewUUID()))
+	},
+}, {
+	name:       "event sent to a broker that doesn't exist is rejected",
+	wantStatus: http.StatusNotFound,
+	request: func(u *url.URL) (*http.Request, error) {
+		missing := *u
+		missing.Path = path.Join(path.Dir(u.Path), "missing-"+string(uuid.NewUUID()))
+		return binaryRequest(missing.String(), string(uuid.NewUUID()))
+	},
+}}
+
+func main() {
+	brokerURL, err := url.Parse(os.Getenv(brokerURLEnvVar))
+	if err != nil {
+		fmt.Printf("Invalid broker URL: %s\n", err)
+		os.Exit(1)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	output := lib.IngressProberOutput{}
+	output.Success = true
+	for _, p := range probes {
+		check := lib.IngressCheck{Name: p.name, WantStatus: p.wantStatus}
+		if err := send(client, brokerURL, p, &check); err != nil {
+			check.Error = err.Error()
+		}
+		if check.GotStatus != check.WantStatus {
+			output.Success = false
+		}
+		fmt.Printf("%s: want status %d, got %d %s\n", check.Name, check.WantStatus, check.GotStatus, check.Error)
+		output.Checks = append(output.Checks, check)
+	}
+
+	if err := writeTerminationMessage(output); err != nil {
+		fmt.Printf("failed to write termination message, %s.\n", err)
+	}
+}
+
+func send(client *http.Client, brokerURL *url.URL, p probe, check *lib.IngressCheck) error {
+	req, err := p.request(brokerURL)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	check.GotStatus = resp.StatusCode
+	if resp.StatusCode != p.wantStatus {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("response body: %s", body)
+	}
+	return nil
+}
+
+func binaryRequest(target, id string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewBufferString(`{"probe": "binary"}`))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", id)
+	req.Header.Set("Ce-Type", lib.E2EIngressProbeEventType)
+	req.Header.Set("Ce-Source", lib.E2EIngressProbeEventSource)
+	return req, nil
+}
+
+func writeTerminationMessage(result interface{}) error {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile("/dev/termination-log", b, 0644)
+}